@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,21 +13,49 @@ import (
 	"time"
 
 	"thamaniyah/internal/config"
+	"thamaniyah/internal/domain"
 	"thamaniyah/internal/handler"
 	"thamaniyah/internal/middleware"
 	"thamaniyah/internal/repository"
 	"thamaniyah/internal/service"
+	"thamaniyah/internal/service/transcode"
+	"thamaniyah/pkg/chromaprint"
 	"thamaniyah/pkg/database"
+	"thamaniyah/pkg/entitlement"
+	"thamaniyah/pkg/errortracker"
+	"thamaniyah/pkg/ffmpeg"
+	"thamaniyah/pkg/geoip"
+	"thamaniyah/pkg/media"
+	"thamaniyah/pkg/messagequeue"
+	"thamaniyah/pkg/notification"
+	"thamaniyah/pkg/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Load the settings that can be reloaded at runtime
+	tunables, err := config.NewTunablesStore(cfg.Server.TunablesFile)
+	if err != nil {
+		log.Fatalf("Failed to load tunables: %v", err)
+	}
+
+	// Report panics and server errors to the configured error tracker
+	tracker, err := errortracker.NewFromConfig(cfg, "cms-service")
+	if err != nil {
+		log.Fatalf("Failed to initialize error tracking: %v", err)
+	}
+	var errorReporter middleware.ErrorReporter
+	if tracker != nil {
+		errorReporter = tracker
+	}
+
 	// Connect to database
-	conn, err := database.NewPostgresConnection(cfg)
+	conn, err := database.NewConnection(cfg)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -41,15 +71,391 @@ func main() {
 
 	// Initialize repositories
 	mediaRepo := repository.NewPostgresMediaRepository(conn)
+	if conn.IsSQLite() {
+		mediaRepo = repository.NewSQLiteMediaRepository(conn)
+	}
+	if cfg.Database.FallbackCacheSize > 0 {
+		mediaRepo = repository.NewFallbackMediaRepository(mediaRepo, time.Duration(cfg.Database.FallbackCacheMaxAgeSeconds)*time.Second, cfg.Database.FallbackCacheSize)
+	}
+	notificationPrefRepo := repository.NewPostgresNotificationPreferenceRepository(conn)
+	outboxRepo := repository.NewPostgresOutboxRepository(conn)
+	shareLinkRepo := repository.NewPostgresShareLinkRepository(conn)
+	transcodePresetRepo := repository.NewPostgresTranscodePresetRepository(conn)
+	renditionRepo := repository.NewPostgresRenditionRepository(conn)
+	showTemplateRepo := repository.NewPostgresShowTemplateRepository(conn)
+	bulkJobRepo := repository.NewPostgresBulkJobRepository(conn)
+	contentKeyRepo := repository.NewPostgresContentKeyRepository(conn)
+	progressRepo := repository.NewPostgresProgressRepository(conn)
+	historyRepo := repository.NewPostgresHistoryRepository(conn)
+	downloadRepo := repository.NewPostgresDownloadRepository(conn)
 
 	// Initialize services
-	mediaService := service.NewMediaService(mediaRepo)
+	notificationService := service.NewNotificationService(notificationPrefRepo, mediaRepo, notification.NewNotifiers(cfg))
+	// Notifications are sent after the change that triggered them returned
+	notificationPublisher := service.NewAsyncEventPublisher(notificationService, cfg.Notification.QueueSize)
+	// Events go to the configured broker, or stay in the process without one
+	eventQueue, err := messagequeue.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to message queue: %v", err)
+	}
+	defer eventQueue.Close()
+	brokered := cfg.Queue.Driver != "" && cfg.Queue.Driver != "memory"
+	publishers := []service.EventPublisher{
+		service.NewLogEventPublisher(),
+		notificationPublisher,
+	}
+	// The outbox relay publishes the events to the broker when the outbox is enabled
+	if !brokered || !cfg.Outbox.Enabled {
+		publishers = append(publishers, service.NewQueueEventPublisher(eventQueue))
+	}
+	streamQueue := eventQueue
+	if brokered {
+		// Discovery keeps its index fresh from media index events on the broker
+		publishers = append(publishers, service.NewMediaIndexEventPublisher(eventQueue, messagequeue.NewDefaultRegistry()))
+
+		// Every replica streams every event to its clients, so the stream does
+		// not share the queues of the consumer group
+		streamConfig := *cfg
+		streamConfig.Queue.ConsumerGroup = ""
+		streamQueue, err = messagequeue.NewFromConfig(&streamConfig)
+		if err != nil {
+			log.Fatalf("Failed to connect to message queue: %v", err)
+		}
+		defer streamQueue.Close()
+	}
+	eventStreamService := service.NewEventStreamService(streamQueue)
+	// The media service appends its events to the outbox in the transaction of
+	// the change they announce, the other services right after their change
+	mediaEventPublisher := service.NewTracingEventPublisher(service.NewFanoutEventPublisher(publishers...))
+	eventPublisher := mediaEventPublisher
+	var outboxPublisher service.EventPublisher
+	if cfg.Outbox.Enabled {
+		outboxPublisher = service.NewTracingEventPublisher(service.NewOutboxEventPublisher(outboxRepo))
+		eventPublisher = service.NewFanoutEventPublisher(mediaEventPublisher, outboxPublisher)
+	}
+	processingQueue := service.NewPriorityProcessingQueue(cfg.Processing.QueueCapacity, cfg.Processing.StarvationLimit)
+	taskLimiter := service.NewTaskLimiter(service.TaskLimits{
+		Concurrency: map[service.TaskType]int{
+			service.TaskMetadata:  cfg.Processing.MetadataConcurrency,
+			service.TaskThumbnail: cfg.Processing.ThumbnailConcurrency,
+			service.TaskTranscode: cfg.Processing.TranscodeConcurrency,
+		},
+		FFmpegProcesses: cfg.Processing.FFmpegMaxProcesses,
+	})
+	mediaStorage, err := storage.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	tagService := service.NewTagService(mediaRepo, mediaStorage, cfg.Processing.ID3WriteBack)
+	uploadLimits, err := service.NewUploadLimitPolicy(service.UploadLimitOptions{
+		URLTTL:             time.Duration(cfg.Upload.URLTTLSeconds) * time.Second,
+		MaxVideoFileSize:   int64(cfg.Upload.MaxVideoFileSizeMB) * 1024 * 1024,
+		MaxPodcastFileSize: int64(cfg.Upload.MaxPodcastFileSizeMB) * 1024 * 1024,
+		StorageQuota:       int64(cfg.Upload.StorageQuotaMB) * 1024 * 1024,
+		TenantOverrides:    cfg.Upload.TenantLimits,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize upload limits: %v", err)
+	}
+	var duplicateDetector service.DuplicateDetector
+	if cfg.Processing.FingerprintEnabled {
+		duplicateDetector = service.NewFingerprintService(
+			repository.NewPostgresFingerprintRepository(conn),
+			mediaStorage,
+			chromaprint.NewRunner(cfg.Processing.FpcalcPath, cfg.Processing.FingerprintLength),
+			eventPublisher,
+			cfg.Processing.FingerprintThreshold,
+		)
+	}
+	chapterService := service.NewChapterService(mediaRepo, mediaStorage, ffmpeg.NewRunner(cfg.Processing.FFmpegPath), taskLimiter, service.ChapterOptions{
+		NoiseDB:           cfg.Processing.SilenceNoiseDB,
+		MinSilenceSeconds: cfg.Processing.MinSilenceSeconds,
+		MinChapterSeconds: cfg.Processing.MinChapterSeconds,
+	})
+	var chapterSuggester service.ChapterSuggester
+	if cfg.Processing.ChapterSuggestions {
+		chapterSuggester = chapterService
+	}
+	suggestionCategories, err := domain.ParseCategoryKeywords(cfg.Processing.SuggestionCategories)
+	if err != nil {
+		log.Fatalf("Failed to initialize transcript suggestions: %v", err)
+	}
+	var explicitTerms map[string]bool
+	if cfg.Processing.ExplicitLanguageDetection {
+		explicitTerms, err = domain.ParseExplicitTerms(cfg.Processing.ExplicitLanguageTerms)
+		if err != nil {
+			log.Fatalf("Failed to initialize explicit language detection: %v", err)
+		}
+	}
+	transcriptService := service.NewTranscriptService(
+		repository.NewPostgresTranscriptRepository(conn),
+		mediaRepo,
+		eventPublisher,
+		service.NewKeywordClassifier(suggestionCategories),
+		cfg.Processing.SuggestionMinConfidence,
+		explicitTerms,
+	)
+	var metadataExtractor media.MetadataExtractor
+	if cfg.Processing.MetadataProbe {
+		metadataExtractor = media.NewFFprobe(cfg.Processing.FFprobePath)
+	}
+	mediaService := service.NewMediaService(mediaRepo, service.MediaServiceDeps{
+		Publisher:       mediaEventPublisher,
+		Outbox:          outboxPublisher,
+		Transactor:      conn,
+		ProcessingQueue: processingQueue,
+		TaskLimiter:     taskLimiter,
+		Presets:         transcodePresetRepo,
+		TagExtractor:    tagService,
+		UploadLimits:    uploadLimits,
+		ShowTemplates:   showTemplateRepo,
+		UploadStorage:   mediaStorage,
+		Duplicates:      duplicateDetector,
+		Chapters:        chapterSuggester,
+		Metadata:        metadataExtractor,
+		// The processing worker takes confirmed uploads from their events
+		UploadEvents: true,
+	})
+
+	shareLinkService := service.NewShareLinkService(shareLinkRepo, mediaRepo)
+	apiKeyService := service.NewAPIKeyService(repository.NewPostgresAPIKeyRepository(conn))
+	watermarkPolicy, err := service.NewWatermarkPolicy(service.WatermarkOptions{
+		Enabled: cfg.Watermark.Enabled,
+		Tenants: cfg.Watermark.Tenants,
+		Shows:   cfg.Watermark.Shows,
+		Watermark: domain.Watermark{
+			LogoPath: cfg.Watermark.LogoPath,
+			Position: domain.WatermarkPosition(cfg.Watermark.Position),
+			Opacity:  cfg.Watermark.Opacity,
+			Margin:   cfg.Watermark.Margin,
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize watermarking: %v", err)
+	}
+	transcodePresetService := service.NewTranscodePresetService(transcodePresetRepo, mediaRepo, watermarkPolicy)
+	audioService := service.NewAudioService(mediaRepo, mediaStorage, ffmpeg.NewRunner(cfg.Processing.FFmpegPath), taskLimiter)
+	audioTrackService := service.NewAudioTrackService(mediaRepo, mediaStorage, eventPublisher)
+	liveService := service.NewLiveService(repository.NewPostgresLiveStreamRepository(conn), mediaRepo, mediaService, mediaStorage, eventPublisher, cfg.Live.IngestURL)
+	keyEncryptionKey, err := base64.StdEncoding.DecodeString(cfg.DRM.KeyEncryptionKey)
+	if err != nil {
+		log.Fatalf("Invalid DRM key encryption key: %v", err)
+	}
+	drmService, err := service.NewDRMService(contentKeyRepo, service.DRMOptions{
+		Enabled:                cfg.DRM.Enabled,
+		WidevineLicenseURL:     cfg.DRM.WidevineLicenseURL,
+		FairPlayLicenseURL:     cfg.DRM.FairPlayLicenseURL,
+		FairPlayCertificateURL: cfg.DRM.FairPlayCertificateURL,
+		KeyEncryptionKey:       keyEncryptionKey,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize DRM: %v", err)
+	}
+	transcodeService := transcode.NewService(mediaRepo, renditionRepo, transcodePresetService, watermarkPolicy, drmService, mediaStorage, ffmpeg.NewRunner(cfg.Processing.FFmpegPath), taskLimiter, transcode.Options{
+		WorkDir:        cfg.Processing.TranscodeWorkDir,
+		SegmentSeconds: cfg.Processing.HLSSegmentSeconds,
+	})
+	thumbnailService := service.NewThumbnailService(mediaRepo, mediaStorage, ffmpeg.NewRunner(cfg.Processing.FFmpegPath), taskLimiter)
+	storageCollector := service.NewStorageCollector(mediaRepo, mediaStorage, service.StorageCollectorOptions{
+		GracePeriod: time.Duration(cfg.StorageGC.GraceHours) * time.Hour,
+		Interval:    time.Duration(cfg.StorageGC.IntervalHours) * time.Hour,
+		BatchSize:   cfg.StorageGC.BatchSize,
+		DryRun:      cfg.StorageGC.DryRun,
+	})
+	playbackService := service.NewPlaybackService(mediaRepo, drmService, mediaStorage, cfg.Embed.PublicBaseURL, time.Duration(cfg.Stream.URLTTLSeconds)*time.Second)
+	streamService := service.NewStreamService(mediaRepo, renditionRepo, mediaStorage, drmService, service.StreamOptions{
+		URLTTL:         time.Duration(cfg.Stream.URLTTLSeconds) * time.Second,
+		DownloadURLTTL: time.Duration(cfg.Stream.DownloadURLTTLSeconds) * time.Second,
+	})
+	downloadStatsService := service.NewDownloadStatsService(downloadRepo, mediaRepo)
+	// API usage is counted in Redis, shared with the discovery service, and rolled up daily
+	usageCounters := repository.NewInMemoryUsageCounterRepository()
+	if cfg.Usage.Enabled {
+		redisClient := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr(), Password: cfg.Redis.Password, DB: cfg.Redis.DB})
+		defer redisClient.Close()
+		usageCounters = repository.NewRedisUsageCounterRepository(redisClient, time.Duration(cfg.Usage.CounterRetentionDays)*24*time.Hour)
+	}
+	usageService := service.NewUsageService(usageCounters, repository.NewPostgresUsageRepository(conn), mediaRepo)
+	var usageRecorder middleware.UsageRecorder
+	if cfg.Usage.Enabled {
+		usageRecorder = usageService
+	}
+	embedService := service.NewEmbedService(mediaRepo, service.EmbedOptions{
+		BaseURL:        cfg.Embed.PublicBaseURL,
+		AllowedOrigins: cfg.Embed.AllowedOrigins,
+		DefaultTheme: domain.EmbedTheme{
+			Mode:        cfg.Embed.Theme,
+			AccentColor: cfg.Embed.AccentColor,
+		},
+	})
+
+	var countryLocator middleware.CountryLocator
+	if cfg.GeoIP.DatabasePath != "" {
+		geoDB, err := geoip.Open(cfg.GeoIP.DatabasePath)
+		if err != nil {
+			log.Fatalf("Failed to load GeoIP database: %v", err)
+		}
+		log.Printf("Loaded %d GeoIP ranges", geoDB.Len())
+		countryLocator = geoDB
+	}
+
+	entitlementProvider, err := entitlement.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize entitlements: %v", err)
+	}
+
+	// Start background processing
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	maintenance := middleware.NewMaintenanceMode(repository.NewPostgresMaintenanceRepository(conn))
+	if cfg.Server.ReadOnly {
+		_, err = maintenance.SetReadOnly(workerCtx, true, "")
+	} else {
+		err = maintenance.Refresh(workerCtx)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load maintenance mode: %v", err)
+	}
+	go maintenance.Run(workerCtx, time.Duration(cfg.Server.ReadOnlyRefreshSeconds)*time.Second)
+	workerOptions := service.ProcessingWorkerOptions{
+		Concurrency:   cfg.Processing.Workers,
+		MaxAttempts:   cfg.Processing.MaxAttempts,
+		RetryDelay:    time.Duration(cfg.Processing.RetryDelaySeconds) * time.Second,
+		MaxRetryDelay: time.Duration(cfg.Processing.MaxRetryDelaySeconds) * time.Second,
+		Events:        eventQueue,
+		Maintenance:   maintenance,
+	}
+	if cfg.Processing.WarmThumbnails {
+		workerOptions.Thumbnails = thumbnailService
+	}
+	if cfg.Processing.TranscodeRenditions {
+		workerOptions.Transcoder = transcodeService
+	}
+	processingWorker := service.NewProcessingWorker(processingQueue, mediaService, workerOptions)
+	go processingWorker.Run(workerCtx)
+	go notificationPublisher.Run(workerCtx, cfg.Notification.Workers)
+	go tunables.ReloadOnSignal(workerCtx, syscall.SIGHUP)
+
+	// Start singleton jobs, on one replica only when leader election is enabled
+	var leaderLock service.LeaderLock
+	// Advisory locks are a PostgreSQL feature; SQLite serves a single replica
+	if cfg.Scheduler.LeaderElection && !conn.IsSQLite() {
+		lock, err := database.NewAdvisoryLock(conn, "cms-service-scheduler")
+		if err != nil {
+			log.Fatalf("Failed to initialize leader election: %v", err)
+		}
+		leaderLock = lock
+	}
+	scheduler := service.NewScheduler(leaderLock, time.Duration(cfg.Scheduler.LeaderCheckIntervalS)*time.Second)
+	scheduler.PauseDuringMaintenance(maintenance)
+	// Media left in processing by a restart is queued again by the leader only
+	if cfg.Processing.ResumeOnStart {
+		scheduler.Add("processing-resume", processingWorker.Resume)
+	}
+	scheduler.Add("license-enforcer", service.NewLicenseEnforcer(mediaRepo, eventPublisher, service.LicenseEnforcerOptions{
+		NoticePeriod: time.Duration(cfg.License.ExpiryNoticeDays) * 24 * time.Hour,
+		Interval:     time.Duration(cfg.License.CheckIntervalMinutes) * time.Minute,
+		BatchSize:    cfg.License.BatchSize,
+	}).Run)
+	scheduler.Add("premiere-announcer", service.NewPremiereAnnouncer(mediaRepo, eventPublisher, service.PremiereAnnouncerOptions{
+		Interval:  time.Duration(cfg.Premiere.CheckIntervalSeconds) * time.Second,
+		BatchSize: cfg.Premiere.BatchSize,
+	}).Run)
+	scheduler.Add("unpublish-scheduler", service.NewUnpublishScheduler(mediaRepo, shareLinkRepo, eventPublisher, service.UnpublishSchedulerOptions{
+		Interval:  time.Duration(cfg.Unpublish.CheckIntervalSeconds) * time.Second,
+		BatchSize: cfg.Unpublish.BatchSize,
+	}).Run)
+	// Deleted media and its files are purged once the trash retention has passed
+	if cfg.Trash.RetentionDays > 0 {
+		scheduler.Add("trash-purger", service.NewTrashPurger(mediaRepo, mediaStorage, eventPublisher, service.TrashPurgerOptions{
+			Retention: time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour,
+			Interval:  time.Duration(cfg.Trash.CheckIntervalSeconds) * time.Second,
+			BatchSize: cfg.Trash.BatchSize,
+		}).Run)
+	}
+	// Stored objects no media record references are collected, or only reported in dry runs
+	if cfg.StorageGC.IntervalHours > 0 {
+		scheduler.Add("storage-collector", storageCollector.Run)
+	}
+	if cfg.Usage.Enabled {
+		scheduler.Add("usage-rollup", service.NewUsageRollup(usageService, time.Duration(cfg.Usage.RollupIntervalMinutes)*time.Minute).Run)
+	}
+	if cfg.Digest.Enabled {
+		digestWeekday, err := domain.ParseWeekday(cfg.Digest.Weekday)
+		if err != nil {
+			log.Fatalf("Invalid DIGEST_WEEKDAY: %v", err)
+		}
+		digestTemplates, err := service.NewDigestTemplates(cfg.Digest.TemplatePath)
+		if err != nil {
+			log.Fatalf("Failed to load digest template: %v", err)
+		}
+		digestService := service.NewDigestService(repository.NewPostgresDigestRepository(conn), repository.NewPostgresSearchQueryRepository(conn),
+			notificationService, digestTemplates, cfg.Digest.TopItems)
+		scheduler.Add("weekly-digest", service.NewDigestScheduler(digestService, digestWeekday, cfg.Digest.Hour).Run)
+	}
+	// Suggestions are precomputed from the PostgreSQL search index
+	if !conn.IsSQLite() {
+		scheduler.Add("suggestion-refresher", service.NewSuggestionRefresher(
+			repository.NewSuggestionRepository(conn),
+			time.Duration(cfg.Search.SuggestionRefreshMinutes)*time.Minute,
+		).Run)
+	}
+	schedulerDone := make(chan struct{})
+	go func() {
+		scheduler.Run(workerCtx)
+		close(schedulerDone)
+	}()
 
 	// Initialize handlers
-	mediaHandler := handler.NewMediaHandler(mediaService)
+	handlers := routeHandlers{
+		media:           handler.NewMediaHandler(mediaService, domain.CountMode(cfg.Listing.MediaCountMode)),
+		notification:    handler.NewNotificationHandler(notificationService),
+		admin:           handler.NewAdminHandler(eventStreamService),
+		config:          handler.NewConfigHandler(tunables),
+		shareLink:       handler.NewShareLinkHandler(shareLinkService),
+		embed:           handler.NewEmbedHandler(embedService),
+		transcodePreset: handler.NewTranscodePresetHandler(transcodePresetService),
+		rendition:       handler.NewRenditionHandler(transcodeService),
+		audio:           handler.NewAudioHandler(audioService),
+		audioTrack:      handler.NewAudioTrackHandler(audioTrackService),
+		live:            handler.NewLiveHandler(liveService),
+		premiere:        handler.NewPremiereHandler(service.NewPremiereService(mediaRepo)),
+		thumbnail:       handler.NewThumbnailHandler(thumbnailService),
+		tag:             handler.NewTagHandler(tagService),
+		playback:        handler.NewPlaybackHandler(playbackService),
+		stream:          handler.NewStreamHandler(streamService),
+		showTemplate:    handler.NewShowTemplateHandler(service.NewShowTemplateService(showTemplateRepo)),
+		bulk:            handler.NewBulkHandler(service.NewBulkLabelService(mediaRepo, bulkJobRepo)),
+		calendar:        handler.NewCalendarHandler(service.NewCalendarService(mediaRepo)),
+		progress:        handler.NewProgressHandler(service.NewProgressService(progressRepo, mediaRepo)),
+		history:         handler.NewHistoryHandler(service.NewHistoryService(historyRepo, progressRepo, mediaRepo)),
+		downloadStats:   handler.NewDownloadStatsHandler(downloadStatsService),
+		adMarker:        handler.NewAdMarkerHandler(service.NewAdMarkerService(mediaRepo)),
+		chapter:         handler.NewChapterHandler(chapterService),
+		transcript:      handler.NewTranscriptHandler(transcriptService),
+		series:          handler.NewSeriesHandler(service.NewSeriesService(mediaRepo)),
+		people:          handler.NewPeopleHandler(service.NewPeopleService(repository.NewPostgresPersonRepository(conn), mediaRepo, eventPublisher)),
+		storage:         handler.NewStorageHandler(storageCollector),
+		maintenance:     handler.NewMaintenanceHandler(maintenance),
+		usage:           handler.NewUsageHandler(usageService),
+		abuseReport:     handler.NewAbuseReportHandler(service.NewAbuseReportService(repository.NewPostgresAbuseReportRepository(conn), mediaRepo, eventPublisher, cfg.Moderation.ReportsPerHour)),
+		apiKey:          handler.NewAPIKeyHandler(apiKeyService),
+	}
 
 	// Setup router
-	router := setupRouter(mediaHandler)
+	router, err := setupRouter(cfg, tunables, handlers, routeMiddleware{
+		maintenance:  maintenance,
+		apiKeys:      apiKeyService,
+		shareTokens:  shareLinkService,
+		countries:    countryLocator,
+		entitlements: entitlementProvider,
+		downloads:    downloadStatsService,
+		usage:        usageRecorder,
+		errors:       errorReporter,
+	})
+	if err != nil {
+		log.Fatalf("Failed to set up router: %v", err)
+	}
 
 	// Start server
 	server := &http.Server{
@@ -70,6 +476,8 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopWorkers()
+	<-schedulerDone // hand leadership over before the database connection closes
 
 	// Graceful shutdown with 10 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -78,21 +486,127 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if tracker != nil {
+		if err := tracker.Close(ctx); err != nil {
+			log.Printf("Failed to flush error events: %v", err)
+		}
+	}
 
 	log.Println("CMS Service shutdown complete")
 }
 
+// routeHandlers groups the HTTP handlers served by the CMS service
+type routeHandlers struct {
+	media           *handler.MediaHandler
+	notification    *handler.NotificationHandler
+	admin           *handler.AdminHandler
+	config          *handler.ConfigHandler
+	shareLink       *handler.ShareLinkHandler
+	embed           *handler.EmbedHandler
+	transcodePreset *handler.TranscodePresetHandler
+	rendition       *handler.RenditionHandler
+	audio           *handler.AudioHandler
+	audioTrack      *handler.AudioTrackHandler
+	live            *handler.LiveHandler
+	premiere        *handler.PremiereHandler
+	thumbnail       *handler.ThumbnailHandler
+	tag             *handler.TagHandler
+	playback        *handler.PlaybackHandler
+	stream          *handler.StreamHandler
+	showTemplate    *handler.ShowTemplateHandler
+	bulk            *handler.BulkHandler
+	calendar        *handler.CalendarHandler
+	progress        *handler.ProgressHandler
+	history         *handler.HistoryHandler
+	downloadStats   *handler.DownloadStatsHandler
+	adMarker        *handler.AdMarkerHandler
+	chapter         *handler.ChapterHandler
+	transcript      *handler.TranscriptHandler
+	series          *handler.SeriesHandler
+	people          *handler.PeopleHandler
+	storage         *handler.StorageHandler
+	maintenance     *handler.MaintenanceHandler
+	usage           *handler.UsageHandler
+	abuseReport     *handler.AbuseReportHandler
+	apiKey          *handler.APIKeyHandler
+}
+
+// routeMiddleware groups the collaborators of the middleware of the CMS service
+type routeMiddleware struct {
+	maintenance  *middleware.MaintenanceMode
+	apiKeys      middleware.APIKeyResolver
+	shareTokens  middleware.ShareTokenResolver
+	countries    middleware.CountryLocator
+	entitlements middleware.EntitlementProvider
+	downloads    middleware.DownloadRecorder
+	usage        middleware.UsageRecorder // nil does not meter usage
+	errors       middleware.ErrorReporter
+}
+
+// newEngine creates a gin engine taking X-Forwarded-For only from trustedProxies,
+// so clients cannot choose the IP geo restrictions and rate limits see
+func newEngine(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	return router, nil
+}
+
 // setupRouter configures the HTTP router with routes and middleware
-func setupRouter(mediaHandler *handler.MediaHandler) *gin.Engine {
+func setupRouter(cfg *config.Config, tunables *config.TunablesStore, h routeHandlers, m routeMiddleware) (*gin.Engine, error) {
 	// Set Gin mode based on environment
 	gin.SetMode(gin.ReleaseMode)
 
-	router := gin.New()
+	router, err := newEngine(cfg.Server.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
 
 	// Add middleware
-	router.Use(middleware.Logger())
-	router.Use(middleware.Recovery())
-	router.Use(middleware.CORS())
+	router.Use(middleware.Trace())
+	router.Use(middleware.Logger(func() middleware.LogSettings {
+		current := tunables.Get()
+		return middleware.LogSettings{
+			Level:                current.LogLevel,
+			SampleAfterPerSecond: current.LogSampleAfterPerSecond,
+			SampleRate:           current.LogSampleRate,
+		}
+	}))
+	router.Use(middleware.Recovery(m.errors))
+	router.Use(middleware.SlowRequests(time.Duration(cfg.SlowLog.RequestMs) * time.Millisecond))
+	router.Use(middleware.Degradation())
+	longTimeout := time.Duration(cfg.Server.LongTimeoutSeconds) * time.Second
+	router.Use(middleware.Timeout(middleware.RouteTimeouts{
+		Read:  time.Duration(cfg.Server.ReadTimeoutSeconds) * time.Second,
+		Write: time.Duration(cfg.Server.WriteTimeoutSeconds) * time.Second,
+		Routes: map[string]time.Duration{
+			// Files are streamed for as long as the client reads them
+			middleware.RouteKey(http.MethodPut, "/upload/:key"):                             0,
+			middleware.RouteKey(http.MethodGet, "/files/*key"):                              0,
+			middleware.RouteKey(http.MethodGet, "/api/v1/media/:id/audio"):                  0,
+			middleware.RouteKey(http.MethodGet, "/api/v1/media/:id/audio-tracks/:language"): 0,
+			middleware.RouteKey(http.MethodPut, "/api/v1/media/:id/audio-tracks/:language"): 0,
+			middleware.RouteKey(http.MethodGet, "/api/v1/media/:id/download"):               0,
+			middleware.RouteKey(http.MethodGet, "/api/v1/admin/events/stream"):              0,
+			middleware.RouteKey(http.MethodPost, "/api/v1/media/bulk/tags"):                 longTimeout,
+			middleware.RouteKey(http.MethodPost, "/api/v1/admin/storage/gc"):                longTimeout,
+		},
+	}))
+	router.Use(middleware.CORS(func() []string { return tunables.Get().CORSAllowedOrigins }))
+	router.Use(middleware.Authenticate(cfg.Auth.AdminAPIKey, m.apiKeys))
+	router.Use(middleware.IdentifyUser(cfg.Auth.UserHeader, cfg.Auth.RoleHeader, cfg.Auth.GatewaySecret))
+	// Rate limited requests are counted too, they show abuse
+	if m.usage != nil {
+		router.Use(middleware.MeterUsage(m.usage, cfg.Usage.TenantHeader))
+	}
+	router.Use(middleware.RateLimit(func() int { return tunables.Get().RateLimitPerMinute }))
+	// Writes are rejected in read-only mode, except validating uploads and leaving the mode
+	router.Use(middleware.ReadOnly(m.maintenance, time.Duration(cfg.Server.ReadOnlyRetryAfterSeconds)*time.Second,
+		"/api/v1/media/validate-upload",
+		"/api/v1/admin/maintenance",
+		"/api/v1/admin/config/reload",
+	))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -103,19 +617,158 @@ func setupRouter(mediaHandler *handler.MediaHandler) *gin.Engine {
 		})
 	})
 
+	// Files are uploaded and downloaded here with the signed URLs of local storage
+	router.PUT("/upload/:key", h.media.UploadFile)
+	router.GET("/files/*key", h.stream.ServeFile)
+
+	// Premium media is only played and downloaded by subscribers
+	entitled := middleware.ResolveEntitlements(m.entitlements)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
-		media := v1.Group("/media")
+		media := v1.Group("/media",
+			middleware.ShareToken(m.shareTokens),
+			middleware.GeoLocate(m.countries, cfg.GeoIP.CountryHeader),
+		)
+		{
+			media.POST("/upload-url", h.media.CreateUploadURL)
+			media.POST("/validate-upload", h.media.ValidateUpload)
+			media.POST("/:id/confirm", h.media.ConfirmUpload)
+			media.POST("/:id/process", middleware.RequireAdmin(), h.media.ReprocessMedia)
+			media.GET("", h.media.GetAllMedia)
+			media.GET("/batch", h.media.GetMediaBatch)
+			media.POST("/playback-urls", entitled, h.playback.GetPlaybackURLs)
+			media.POST("/bulk/tags", middleware.RequireAdmin(), h.bulk.LabelMedia)
+			media.GET("/bulk/jobs/:id", middleware.RequireAdmin(), h.bulk.GetJob)
+			media.GET("/:id", h.media.GetMedia)
+			media.GET("/:id/playback", entitled, h.playback.GetPlayback)
+			media.GET("/:id/stream", entitled, h.stream.GetStream)
+			media.GET("/:id/download-url", entitled, h.stream.GetDownloadURL)
+			media.GET("/:id/embed", entitled, h.embed.GetEmbedConfig)
+			media.GET("/:id/audio", entitled, middleware.CountDownload(m.downloads), h.audio.GetAudio)
+			media.GET("/:id/audio-tracks/:language", entitled, middleware.CountDownload(m.downloads), h.audioTrack.GetAudioTrack)
+			media.GET("/:id/download", entitled, middleware.CountDownload(m.downloads), h.tag.Download)
+			media.GET("/:id/artwork", h.tag.GetArtwork)
+			media.GET("/:id/thumbnail", h.thumbnail.GetThumbnail)
+			media.GET("/:id/series", h.series.GetSeries)
+			media.GET("/:id/premiere", h.premiere.GetCountdown)
+			media.GET("/:id/people", h.people.GetMediaPeople)
+			media.GET("/:id/transcode-plan", middleware.RequireAdmin(), h.transcodePreset.PlanTranscode)
+			media.GET("/:id/renditions", h.rendition.GetRenditions)
+			media.PUT("/:id", h.media.UpdateMedia)
+			media.PUT("/:id/geo-restriction", middleware.RequireAdmin(), h.media.SetGeoRestriction)
+			media.PUT("/:id/cue-points", middleware.RequireAdmin(), h.adMarker.SetCuePoints)
+			media.GET("/:id/chapters", middleware.RequireRole(domain.RoleEditor), h.chapter.GetChapters)
+			media.PUT("/:id/chapters", middleware.RequireRole(domain.RoleEditor), h.chapter.SetChapters)
+			media.POST("/:id/chapters/accept", middleware.RequireRole(domain.RoleEditor), h.chapter.AcceptSuggestions)
+			media.GET("/:id/transcript", middleware.RequireRole(domain.RoleEditor), h.transcript.GetTranscript)
+			media.PUT("/:id/transcript", middleware.RequireRole(domain.RoleEditor), h.transcript.SetTranscript)
+			media.GET("/:id/suggestions", middleware.RequireRole(domain.RoleEditor), h.transcript.GetSuggestions)
+			media.GET("/:id/explicit-language", middleware.RequireRole(domain.RoleEditor), h.transcript.GetExplicitLanguage)
+			media.PUT("/:id/audio-tracks/:language", middleware.RequireRole(domain.RoleEditor), h.audioTrack.SetAudioTrack)
+			media.DELETE("/:id/audio-tracks/:language", middleware.RequireRole(domain.RoleEditor), h.audioTrack.RemoveAudioTrack)
+			media.PUT("/:id/premiere", middleware.RequireRole(domain.RoleEditor), h.premiere.SchedulePremiere)
+			media.DELETE("/:id/premiere", middleware.RequireRole(domain.RoleEditor), h.premiere.CancelPremiere)
+			media.POST("/:id/thumbnail", middleware.RequireRole(domain.RoleEditor), h.thumbnail.SetPoster)
+			media.PUT("/:id/series", middleware.RequireAdmin(), h.series.SetSeries)
+			media.DELETE("/:id/series", middleware.RequireAdmin(), h.series.UnlinkSeries)
+			media.POST("/:id/series/detect", middleware.RequireAdmin(), h.series.DetectSeries)
+			media.PUT("/:id/people", middleware.RequireAdmin(), h.people.SetMediaPeople)
+			media.DELETE("/:id", middleware.RequireAdmin(), h.media.DeleteMedia)
+			media.POST("/:id/share-links", middleware.RequireAdmin(), h.shareLink.CreateShareLink)
+			media.GET("/:id/share-links", middleware.RequireAdmin(), h.shareLink.ListShareLinks)
+			media.DELETE("/:id/share-links/:linkId", middleware.RequireAdmin(), h.shareLink.RevokeShareLink)
+			media.POST("/:id/report", h.abuseReport.ReportMedia)
+		}
+
+		v1.GET("/limits", h.media.GetUploadLimits)
+
+		// The ingest server calls back with the secret stream key of the encoder
+		live := v1.Group("/live")
+		{
+			live.POST("", middleware.RequireRole(domain.RoleEditor), h.live.RegisterLiveStream)
+			live.GET("/:id", h.live.GetLiveStream)
+			live.POST("/ingest/start", h.live.StartIngest)
+			live.POST("/ingest/end", h.live.EndIngest)
+		}
+
+		people := v1.Group("/people")
+		{
+			people.GET("", h.people.ListPeople)
+			people.POST("", middleware.RequireAdmin(), h.people.CreatePerson)
+			people.GET("/:id", h.people.GetPerson)
+			people.GET("/:id/appearances", h.people.GetAppearances)
+			people.PUT("/:id", middleware.RequireAdmin(), h.people.UpdatePerson)
+			people.DELETE("/:id", middleware.RequireAdmin(), h.people.DeletePerson)
+		}
+
+		me := v1.Group("/users/me", middleware.RequireUser(), middleware.ShareToken(m.shareTokens))
+		{
+			me.PUT("/progress/:mediaId", h.progress.RecordProgress)
+			me.GET("/continue", h.progress.GetContinueListening)
+			me.POST("/history", h.history.RecordPlay)
+			me.GET("/history", h.history.GetHistory)
+			me.DELETE("/history", h.history.ClearHistory)
+		}
+
+		notifications := v1.Group("/notifications", middleware.RequireAdmin())
+		{
+			notifications.GET("/preferences", h.notification.GetPreferences)
+			notifications.POST("/preferences", h.notification.CreatePreference)
+			notifications.DELETE("/preferences/:id", h.notification.DeletePreference)
+		}
+
+		transcodePresets := v1.Group("/transcode-presets", middleware.RequireAdmin())
+		{
+			transcodePresets.GET("", h.transcodePreset.ListPresets)
+			transcodePresets.POST("", h.transcodePreset.CreatePreset)
+			transcodePresets.GET("/:id", h.transcodePreset.GetPreset)
+			transcodePresets.PUT("/:id", h.transcodePreset.UpdatePreset)
+			transcodePresets.DELETE("/:id", h.transcodePreset.DeletePreset)
+		}
+
+		shows := v1.Group("/shows", middleware.RequireAdmin())
+		{
+			shows.GET("/:id/template", h.showTemplate.GetTemplate)
+			shows.PUT("/:id/template", h.showTemplate.SetTemplate)
+			shows.DELETE("/:id/template", h.showTemplate.DeleteTemplate)
+			shows.GET("/:id/people", h.people.GetShowPeople)
+			shows.PUT("/:id/people", h.people.SetShowPeople)
+		}
+
+		ads := v1.Group("/ads", middleware.RequireAdmin())
+		{
+			ads.GET("/media/:id/markers", h.adMarker.ResolveMarkers)
+		}
+
+		admin := v1.Group("/admin", middleware.RequireAdmin())
 		{
-			media.POST("/upload-url", mediaHandler.CreateUploadURL)
-			media.POST("/:id/confirm", mediaHandler.ConfirmUpload)
-			media.GET("", mediaHandler.GetAllMedia)
-			media.GET("/:id", mediaHandler.GetMedia)
-			media.PUT("/:id", mediaHandler.UpdateMedia)
-			media.DELETE("/:id", mediaHandler.DeleteMedia)
+			admin.GET("/events/stream", h.admin.StreamEvents)
+			admin.GET("/config", h.config.GetTunables)
+			admin.POST("/config/reload", h.config.ReloadTunables)
+			admin.GET("/calendar", h.calendar.GetCalendar)
+			admin.GET("/stats/downloads/media/:id", h.downloadStats.GetEpisodeStats)
+			admin.GET("/stats/downloads/shows/:id", h.downloadStats.GetShowStats)
+			admin.POST("/storage/gc", h.storage.CollectGarbage)
+			admin.GET("/maintenance", h.maintenance.GetMaintenance)
+			admin.GET("/metrics", gin.WrapH(expvar.Handler()))
+			admin.PUT("/maintenance", h.maintenance.SetMaintenance)
+			admin.GET("/usage", h.usage.GetUsage)
+			admin.GET("/usage/top", h.usage.GetTopUsage)
+			admin.POST("/usage/rollup", h.usage.RollUpUsage)
+			admin.GET("/moderation/reports", h.abuseReport.GetModerationQueue)
+			admin.POST("/moderation/reports/:id/resolve", h.abuseReport.ResolveReports)
+			admin.GET("/suggestions", h.transcript.ListPendingSuggestions)
+			admin.POST("/suggestions/:id/accept", h.transcript.AcceptSuggestion)
+			admin.POST("/suggestions/:id/dismiss", h.transcript.DismissSuggestion)
+			admin.POST("/api-keys", h.apiKey.CreateAPIKey)
+			admin.GET("/api-keys", h.apiKey.ListAPIKeys)
+			admin.POST("/api-keys/:id/rotate", h.apiKey.RotateAPIKey)
+			admin.DELETE("/api-keys/:id", h.apiKey.RevokeAPIKey)
+			admin.GET("/webhooks", h.notification.ListWebhooks)
 		}
 	}
 
-	return router
+	return router, nil
 }
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestMainFunction is a placeholder test for the main function
@@ -28,3 +32,42 @@ func BenchmarkApplication(b *testing.B) {
 		_ = i * 2
 	}
 }
+
+// TestNewEngine_ForwardedFor checks X-Forwarded-For is only believed from trusted proxies
+func TestNewEngine_ForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		expectedIP     string
+	}{
+		{name: "forged header ignored by default", expectedIP: "203.0.113.7"},
+		{name: "header of a trusted proxy", trustedProxies: []string{"203.0.113.0/24"}, expectedIP: "198.51.100.1"},
+		{name: "header of an untrusted proxy", trustedProxies: []string{"10.0.0.0/8"}, expectedIP: "203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, err := newEngine(tt.trustedProxies)
+			require.NoError(t, err)
+			router.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = "203.0.113.7:41234"
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedIP, w.Body.String())
+		})
+	}
+}
+
+// TestNewEngine_InvalidTrustedProxy checks a malformed proxy address is refused
+func TestNewEngine_InvalidTrustedProxy(t *testing.T) {
+	_, err := newEngine([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,43 +18,232 @@ import (
 	"thamaniyah/internal/service"
 	"thamaniyah/pkg/database"
 	"thamaniyah/pkg/elasticsearch"
+	"thamaniyah/pkg/errortracker"
 	"thamaniyah/pkg/httpclient"
+	"thamaniyah/pkg/messagequeue"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Load the settings that can be reloaded at runtime
+	tunables, err := config.NewTunablesStore(cfg.Server.TunablesFile)
+	if err != nil {
+		log.Fatalf("Failed to load tunables: %v", err)
+	}
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go tunables.ReloadOnSignal(reloadCtx, syscall.SIGHUP)
+
+	// Report panics and server errors to the configured error tracker
+	tracker, err := errortracker.NewFromConfig(cfg, "discovery-service")
+	if err != nil {
+		log.Fatalf("Failed to initialize error tracking: %v", err)
+	}
+	var errorReporter middleware.ErrorReporter
+	if tracker != nil {
+		errorReporter = tracker
+	}
+
 	// Connect to database (same database, different service)
-	conn, err := database.NewPostgresConnection(cfg)
+	conn, err := database.NewConnection(cfg)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer conn.Close()
 
-	// Connect to Elasticsearch
-	esClient, err := elasticsearch.NewClient(cfg)
-	if err != nil {
-		log.Fatalf("Failed to connect to Elasticsearch: %v", err)
+	// Initialize repositories, searching with SQLite FTS5 when SQLite is the database
+	searchBoosts := func() repository.SearchBoosts {
+		current := tunables.Get()
+		return repository.SearchBoosts{
+			Title:       current.SearchTitleBoost,
+			Description: current.SearchDescriptionBoost,
+			Show:        current.SearchShowBoost,
+			Category:    current.SearchCategoryBoost,
+			Host:        current.SearchHostBoost,
+			Guest:       current.SearchGuestBoost,
+		}
+	}
+	var searchRepo repository.SearchRepository
+	if conn.IsSQLite() {
+		searchRepo, err = repository.NewSQLiteSearchRepository(conn, searchBoosts)
+		if err != nil {
+			log.Fatalf("Failed to initialize search: %v", err)
+		}
+	} else {
+		var closeSearch func() error
+		searchRepo, closeSearch, err = openSearchBackend(cfg, conn, cfg.Search.Backend, cfg.Elasticsearch, searchBoosts)
+		if err != nil {
+			log.Fatalf("Failed to initialize search: %v", err)
+		}
+		defer closeSearch()
+		if cfg.Search.Backend == config.SearchBackendElasticsearch && cfg.Search.PostgresFallback {
+			fallbackRepo := repository.NewPostgresSearchRepository(conn, cfg.Search.ReindexBatchSize)
+			searchRepo = repository.NewFallbackSearchRepository(searchRepo, fallbackRepo, time.Duration(cfg.Search.FallbackRetrySeconds)*time.Second)
+		}
+
+		// While migrating search backends, the next one is written and compared alongside
+		if cfg.Search.ShadowBackend != "" {
+			shadowES := cfg.Elasticsearch
+			shadowES.URL = cfg.Search.ShadowElasticsearchURL
+			shadowES.Index = cfg.Search.ShadowElasticsearchIndex
+			if cfg.Search.ShadowBackend == cfg.Search.Backend && (cfg.Search.Backend == config.SearchBackendPostgres || shadowES == cfg.Elasticsearch) {
+				log.Fatalf("Shadow search backend %s is the search backend itself", cfg.Search.ShadowBackend)
+			}
+			shadowRepo, closeShadow, err := openSearchBackend(cfg, conn, cfg.Search.ShadowBackend, shadowES, searchBoosts)
+			if err != nil {
+				log.Fatalf("Failed to initialize shadow search: %v", err)
+			}
+			defer closeShadow()
+			shadowName := cfg.Search.ShadowBackend
+			if shadowName == config.SearchBackendElasticsearch {
+				shadowName += " " + shadowES.URL + "/" + shadowES.Index
+			}
+			shadowSearch := repository.NewShadowSearchRepository(searchRepo, shadowRepo, shadowName, repository.ShadowSearchOptions{
+				SampleRate: cfg.Search.ShadowSampleRate,
+				Timeout:    time.Duration(cfg.Search.ShadowTimeoutMs) * time.Millisecond,
+			})
+			expvar.Publish("search_shadow", expvar.Func(func() any {
+				return shadowSearch.Stats()
+			}))
+			log.Printf("Search writes go to the shadow backend %s too, and searches are compared with it", shadowName)
+			searchRepo = shadowSearch
+		}
 	}
-	defer esClient.Close()
 
 	// Initialize HTTP client for CMS service communication
 	cmsClient := httpclient.NewClient(fmt.Sprintf("http://localhost:%d", cfg.Server.Port))
 
-	// Initialize repositories
-	searchRepo := repository.NewElasticsearchSearchRepository(esClient)
-
 	// Initialize services
-	searchService := service.NewSearchService(searchRepo, cmsClient)
+	// Search hits are hydrated with the current metadata from CMS
+	catalog := service.NewCMSMediaCatalog(cmsClient)
+	if cfg.Search.HydrationCacheTTLSeconds > 0 {
+		catalog = service.NewCachedMediaCatalog(catalog, time.Duration(cfg.Search.HydrationCacheTTLSeconds)*time.Second)
+	}
+	// Reindexes are audited and limited to one per cooldown window
+	auditRepo := repository.NewPostgresAuditRepository(conn)
+	reindexCooldown := time.Duration(cfg.Search.ReindexCooldownSeconds) * time.Second
+	// Episodes are indexed with the title and category of their show and the people credited on them
+	searchContexts := service.NewSearchContextResolver(repository.NewPostgresShowTemplateRepository(conn), repository.NewPostgresPersonRepository(conn))
+	// Queries support the advanced syntax unless configured to be taken literally
+	queryParser := service.AdvancedSearchQueryParser
+	if cfg.Search.QuerySyntax == "plain" {
+		queryParser = service.PlainSearchQueryParser
+	}
+	searchService := service.NewSearchService(searchRepo, cmsClient, service.SearchServiceDeps{
+		Catalog:         catalog,
+		Contexts:        searchContexts,
+		AuditRepo:       auditRepo,
+		ReindexCooldown: reindexCooldown,
+		Parser:          queryParser,
+	})
+	// Editor's picks show the current metadata of their media, like search hits
+	mediaRepo := repository.NewPostgresMediaRepository(conn)
+	if conn.IsSQLite() {
+		mediaRepo = repository.NewSQLiteMediaRepository(conn)
+	}
+	if cfg.Database.FallbackCacheSize > 0 {
+		mediaRepo = repository.NewFallbackMediaRepository(mediaRepo, time.Duration(cfg.Database.FallbackCacheMaxAgeSeconds)*time.Second, cfg.Database.FallbackCacheSize)
+	}
+	discoverService := service.NewDiscoverService(mediaRepo, repository.NewPostgresFeaturedItemRepository(conn), catalog)
+	// Drift between the database and the search index is reported to admins, and repaired on request
+	consistencyChecker := service.NewSearchConsistencyChecker(mediaRepo, searchRepo, searchContexts, service.SearchConsistencyOptions{
+		Interval:  time.Duration(cfg.Search.ConsistencyCheckMinutes) * time.Minute,
+		AutoHeal:  cfg.Search.ConsistencyAutoHeal,
+		BatchSize: cfg.Search.ReindexBatchSize,
+	})
+
+	// API usage is counted in Redis and rolled up by the CMS service
+	var usageRecorder middleware.UsageRecorder
+	if cfg.Usage.Enabled {
+		redisClient := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr(), Password: cfg.Redis.Password, DB: cfg.Redis.DB})
+		defer redisClient.Close()
+		usageCounters := repository.NewRedisUsageCounterRepository(redisClient, time.Duration(cfg.Usage.CounterRetentionDays)*24*time.Hour)
+		usageRecorder = service.NewUsageService(usageCounters, repository.NewPostgresUsageRepository(conn), mediaRepo)
+	}
+
+	// Searches are counted for the weekly digests sent by the CMS service
+	var searchRecorder middleware.SearchRecorder
+	if cfg.Digest.Enabled {
+		searchRecorder = service.NewSearchQueryCounter(repository.NewPostgresSearchQueryRepository(conn))
+	}
+
+	// Check the index periodically, on one replica only when leader election is enabled
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobsDone := make(chan struct{})
+	if cfg.Search.ConsistencyCheckMinutes > 0 {
+		var leaderLock service.LeaderLock
+		// Advisory locks are a PostgreSQL feature; SQLite serves a single replica
+		if cfg.Scheduler.LeaderElection && !conn.IsSQLite() {
+			lock, err := database.NewAdvisoryLock(conn, "discovery-service-scheduler")
+			if err != nil {
+				log.Fatalf("Failed to initialize leader election: %v", err)
+			}
+			leaderLock = lock
+		}
+		scheduler := service.NewScheduler(leaderLock, time.Duration(cfg.Scheduler.LeaderCheckIntervalS)*time.Second)
+		scheduler.Add("search-consistency-checker", consistencyChecker.Run)
+		go func() {
+			scheduler.Run(jobsCtx)
+			close(jobsDone)
+		}()
+	} else {
+		close(jobsDone)
+	}
+
+	// Keep the index fresh from the media index events the CMS publishes on the broker
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	defer stopConsumer()
+	consumerDone := make(chan struct{})
+	if cfg.Queue.Driver != "" && cfg.Queue.Driver != "memory" {
+		queue, err := messagequeue.NewFromConfig(cfg)
+		if err != nil {
+			log.Fatalf("Failed to connect to message queue: %v", err)
+		}
+		defer queue.Close()
+
+		consumer := service.NewMediaIndexConsumer(queue, messagequeue.NewDefaultRegistry(),
+			service.NewMediaEventHandler(searchRepo, catalog, searchContexts),
+			service.MediaIndexConsumerOptions{
+				MaxAttempts:  cfg.Search.IndexEventMaxAttempts,
+				RetryBackoff: time.Duration(cfg.Search.IndexEventRetryBackoffMs) * time.Millisecond,
+			})
+		expvar.Publish("media_index_consumer", expvar.Func(func() any {
+			return consumer.Stats()
+		}))
+		go func() {
+			if err := consumer.Run(consumerCtx); err != nil {
+				log.Printf("Media index consumer stopped: %v", err)
+			}
+			close(consumerDone)
+		}()
+	} else {
+		close(consumerDone)
+	}
 
 	// Initialize handlers
-	searchHandler := handler.NewSearchHandler(searchService)
+	handlers := routeHandlers{
+		search:      handler.NewSearchHandler(searchService),
+		config:      handler.NewConfigHandler(tunables),
+		discover:    handler.NewDiscoverHandler(discoverService),
+		consistency: handler.NewSearchConsistencyHandler(consistencyChecker),
+	}
 
 	// Setup router
-	router := setupRouter(searchHandler)
+	router, err := setupRouter(cfg, tunables, handlers, routeMiddleware{
+		apiKeys:  service.NewAPIKeyService(repository.NewPostgresAPIKeyRepository(conn)),
+		usage:    usageRecorder,
+		searches: searchRecorder,
+		errors:   errorReporter,
+	})
+	if err != nil {
+		log.Fatalf("Failed to set up router: %v", err)
+	}
 
 	// Start server on different port (8081)
 	discoveryPort := cfg.Server.Port + 1
@@ -62,6 +252,17 @@ func main() {
 		Handler: router,
 	}
 
+	// Load the caches with representative queries before serving the first users
+	if len(cfg.Search.WarmupQueries) > 0 {
+		warmer := service.NewSearchWarmer(searchService, service.SearchWarmupOptions{
+			Queries: cfg.Search.WarmupQueries,
+			Timeout: time.Duration(cfg.Search.WarmupTimeoutSeconds) * time.Second,
+		})
+		report := warmer.Warm(context.Background())
+		log.Printf("Search warm-up ran %d searches and %d suggestions in %s, %d failed",
+			report.Searches, report.Suggestions, report.Duration.Round(time.Millisecond), report.Failed)
+	}
+
 	// Start server in a goroutine
 	go func() {
 		log.Printf("Discovery Service starting on port %d", discoveryPort)
@@ -83,21 +284,96 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	stopJobs()
+	<-jobsDone // hand leadership over before the database connection closes
+	stopConsumer()
+	<-consumerDone // finish the events being indexed before the queue closes
+	if tracker != nil {
+		if err := tracker.Close(ctx); err != nil {
+			log.Printf("Failed to flush error events: %v", err)
+		}
+	}
 
 	log.Println("Discovery Service shutdown complete")
 }
 
+// openSearchBackend returns the search index of a backend: Postgres, or
+// Elasticsearch with the given settings, and the function closing its client
+func openSearchBackend(cfg *config.Config, conn *database.Connection, backend string, es config.ElasticsearchConfig, boosts func() repository.SearchBoosts) (repository.SearchRepository, func() error, error) {
+	switch backend {
+	case config.SearchBackendPostgres:
+		// The database connection is closed on its own
+		return repository.NewPostgresSearchRepository(conn, cfg.Search.ReindexBatchSize), func() error { return nil }, nil
+	case config.SearchBackendElasticsearch:
+		esCfg := *cfg
+		esCfg.Elasticsearch = es
+		client, err := elasticsearch.NewClient(&esCfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to Elasticsearch at %s: %w", es.URL, err)
+		}
+		return repository.NewElasticsearchSearchRepository(client, boosts), client.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown search backend %q, expected %s or %s", backend, config.SearchBackendElasticsearch, config.SearchBackendPostgres)
+	}
+}
+
+// routeHandlers groups the HTTP handlers served by the discovery service
+type routeHandlers struct {
+	search      *handler.SearchHandler
+	config      *handler.ConfigHandler
+	discover    *handler.DiscoverHandler
+	consistency *handler.SearchConsistencyHandler
+}
+
+// routeMiddleware groups the collaborators of the middleware of the discovery service
+type routeMiddleware struct {
+	apiKeys  middleware.APIKeyResolver
+	usage    middleware.UsageRecorder  // nil does not meter usage
+	searches middleware.SearchRecorder // nil does not count searches
+	errors   middleware.ErrorReporter
+}
+
 // setupRouter configures the HTTP router with routes and middleware
-func setupRouter(searchHandler *handler.SearchHandler) *gin.Engine {
+func setupRouter(cfg *config.Config, tunables *config.TunablesStore, h routeHandlers, m routeMiddleware) (*gin.Engine, error) {
 	// Set Gin mode based on environment
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
+	// X-Forwarded-For of anyone else would let clients choose their rate limit bucket
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 
 	// Add middleware
-	router.Use(middleware.Logger())
-	router.Use(middleware.Recovery())
-	router.Use(middleware.CORS())
+	router.Use(middleware.Trace())
+	router.Use(middleware.Logger(func() middleware.LogSettings {
+		current := tunables.Get()
+		return middleware.LogSettings{
+			Level:                current.LogLevel,
+			SampleAfterPerSecond: current.LogSampleAfterPerSecond,
+			SampleRate:           current.LogSampleRate,
+		}
+	}))
+	router.Use(middleware.Recovery(m.errors))
+	router.Use(middleware.SlowRequests(time.Duration(cfg.SlowLog.RequestMs) * time.Millisecond))
+	router.Use(middleware.Degradation())
+	longTimeout := time.Duration(cfg.Server.LongTimeoutSeconds) * time.Second
+	router.Use(middleware.Timeout(middleware.RouteTimeouts{
+		Read:  time.Duration(cfg.Server.ReadTimeoutSeconds) * time.Second,
+		Write: time.Duration(cfg.Server.WriteTimeoutSeconds) * time.Second,
+		Routes: map[string]time.Duration{
+			middleware.RouteKey(http.MethodPost, "/api/v1/search/reindex"):          longTimeout,
+			middleware.RouteKey(http.MethodGet, "/api/v1/search/consistency"):       longTimeout,
+			middleware.RouteKey(http.MethodPost, "/api/v1/search/consistency/heal"): longTimeout,
+		},
+	}))
+	router.Use(middleware.CORS(func() []string { return tunables.Get().CORSAllowedOrigins }))
+	router.Use(middleware.Authenticate(cfg.Auth.AdminAPIKey, m.apiKeys))
+	// Rate limited requests are counted too, they show abuse
+	if m.usage != nil {
+		router.Use(middleware.MeterUsage(m.usage, cfg.Usage.TenantHeader))
+	}
+	router.Use(middleware.RateLimit(func() int { return tunables.Get().RateLimitPerMinute }))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -113,11 +389,34 @@ func setupRouter(searchHandler *handler.SearchHandler) *gin.Engine {
 	{
 		search := v1.Group("/search")
 		{
-			search.GET("", searchHandler.Search)
-			search.GET("/suggest", searchHandler.Suggest)
-			search.POST("/reindex", searchHandler.Reindex)
+			if m.searches != nil {
+				search.GET("", middleware.CountSearches(m.searches, cfg.Usage.TenantHeader), h.search.Search)
+			} else {
+				search.GET("", h.search.Search)
+			}
+			search.GET("/suggest", h.search.Suggest)
+			search.POST("/reindex", middleware.RequireAdmin(), h.search.Reindex)
+			search.GET("/consistency", middleware.RequireAdmin(), h.consistency.CheckConsistency)
+			search.POST("/consistency/heal", middleware.RequireAdmin(), h.consistency.HealConsistency)
+		}
+
+		discover := v1.Group("/discover")
+		{
+			discover.GET("/new", h.discover.GetNew)
+			discover.GET("/featured", h.discover.GetFeatured)
+		}
+
+		admin := v1.Group("/admin", middleware.RequireAdmin())
+		{
+			admin.GET("/config", h.config.GetTunables)
+			admin.POST("/config/reload", h.config.ReloadTunables)
+			admin.GET("/metrics", gin.WrapH(expvar.Handler()))
+			admin.GET("/featured", h.discover.ListFeaturedItems)
+			admin.POST("/featured", h.discover.CreateFeaturedItem)
+			admin.PUT("/featured/:id", h.discover.UpdateFeaturedItem)
+			admin.DELETE("/featured/:id", h.discover.DeleteFeaturedItem)
 		}
 	}
 
-	return router
+	return router, nil
 }
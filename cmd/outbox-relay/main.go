@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/internal/repository"
+	"thamaniyah/internal/service"
+	"thamaniyah/pkg/database"
+	"thamaniyah/pkg/messagequeue"
+)
+
+func main() {
+	// Load configuration
+	cfg := config.Load()
+
+	// Connect to database
+	conn, err := database.NewConnection(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer conn.Close()
+
+	// Connect to message queue
+	queue, err := messagequeue.NewFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to message queue: %v", err)
+	}
+	defer queue.Close()
+
+	// Initialize relay
+	outboxRepo := repository.NewPostgresOutboxRepository(conn)
+	relay := service.NewOutboxRelay(outboxRepo, queue, service.OutboxRelayOptions{
+		Name:         cfg.Outbox.RelayName,
+		BatchSize:    cfg.Outbox.BatchSize,
+		PollInterval: time.Duration(cfg.Outbox.PollIntervalMs) * time.Millisecond,
+		Retention:    time.Duration(cfg.Outbox.RetentionHours) * time.Hour,
+	})
+
+	// Expose metrics
+	expvar.Publish("outbox_relay", expvar.Func(func() any {
+		return relay.Stats()
+	}))
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Outbox.MetricsPort),
+		Handler: setupMux(relay),
+	}
+
+	go func() {
+		log.Printf("Outbox relay metrics listening on port %d", cfg.Outbox.MetricsPort)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start metrics server: %v", err)
+		}
+	}()
+
+	// Relay until interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.Printf("Outbox relay %s started", cfg.Outbox.RelayName)
+		if err := relay.Run(ctx); err != nil {
+			log.Printf("Outbox relay stopped: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the relay
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down outbox relay...")
+
+	cancel()
+	<-done
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Metrics server forced to shutdown: %v", err)
+	}
+
+	log.Println("Outbox relay shutdown complete")
+}
+
+// setupMux configures the health and metrics endpoints
+func setupMux(relay service.OutboxRelay) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":    "ok",
+			"service":   "outbox-relay",
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(relay.Stats())
+	})
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
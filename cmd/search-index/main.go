@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/pkg/elasticsearch"
+)
+
+const usage = `Usage: search-index <command> [snapshot]
+
+Commands:
+  snapshot [name]  snapshot the media index, named <index>-<UTC time> by default
+  list             list the snapshots of the media index
+  restore <name>   restore a snapshot and move the index alias onto it
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 || len(args) > 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg := config.Load()
+	repository := cfg.Elasticsearch.SnapshotRepository
+
+	// Stop waiting for the cluster when interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Connect to Elasticsearch
+	client, err := elasticsearch.NewClient(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to Elasticsearch: %v", err)
+	}
+	defer client.Close()
+
+	if cfg.Elasticsearch.SnapshotLocation != "" {
+		if err := client.RegisterSnapshotRepository(ctx, repository, cfg.Elasticsearch.SnapshotLocation); err != nil {
+			log.Fatalf("Failed to register snapshot repository %s: %v", repository, err)
+		}
+	}
+
+	switch command := args[0]; {
+	case command == "snapshot":
+		name := client.SnapshotName(time.Now())
+		if len(args) == 2 {
+			name = strings.ToLower(args[1])
+		}
+		fmt.Printf("Snapshotting %s to %s/%s...\n", client.Index(), repository, name)
+		snapshot, err := client.CreateSnapshot(ctx, repository, name)
+		if err != nil {
+			log.Fatalf("Snapshot failed: %v", err)
+		}
+		fmt.Printf("Snapshot %s of %s completed in %s\n", snapshot.Name, strings.Join(snapshot.Indices, ", "), snapshot.EndTime.Sub(snapshot.StartTime).Round(time.Millisecond))
+
+	case command == "list" && len(args) == 1:
+		snapshots, err := client.ListSnapshots(ctx, repository)
+		if err != nil {
+			log.Fatalf("Failed to list snapshots: %v", err)
+		}
+		if len(snapshots) == 0 {
+			fmt.Printf("No snapshots of %s in %s\n", client.Index(), repository)
+			return
+		}
+		for _, snapshot := range snapshots {
+			fmt.Printf("%s\t%s\t%s\t%s\n", snapshot.Name, snapshot.State, snapshot.StartTime.UTC().Format(time.RFC3339), strings.Join(snapshot.Indices, ","))
+		}
+
+	case command == "restore" && len(args) == 2:
+		fmt.Printf("Restoring %s from %s/%s...\n", client.Index(), repository, args[1])
+		result, err := client.RestoreSnapshot(ctx, repository, args[1])
+		if err != nil {
+			log.Fatalf("Restore failed: %v", err)
+		}
+		fmt.Printf("Alias %s now points to %s\n", client.Index(), result.Index)
+		if len(result.Previous) > 0 {
+			fmt.Printf("Previous indices kept, delete them once the restore is verified: %s\n", strings.Join(result.Previous, ", "))
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
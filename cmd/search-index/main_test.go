@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMainFunction is a placeholder test for the main function
+func TestMainFunction(t *testing.T) {
+	// This test ensures the main package compiles correctly
+	assert.True(t, true, "Main package should compile successfully")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -13,25 +14,99 @@ type Config struct {
 	Redis         RedisConfig
 	Queue         QueueConfig
 	Storage       StorageConfig
+	Upload        UploadConfig
+	Auth          AuthConfig
+	Notification  NotificationConfig
+	Outbox        OutboxConfig
+	Processing    ProcessingConfig
+	Embed         EmbedConfig
+	Watermark     WatermarkConfig
+	DRM           DRMConfig
+	GeoIP         GeoIPConfig
+	License       LicenseConfig
+	Unpublish     UnpublishConfig
+	Premiere      PremiereConfig
+	Stream        StreamConfig
+	Trash         TrashConfig
+	StorageGC     StorageGCConfig
+	Entitlement   EntitlementConfig
+	ErrorTracking ErrorTrackingConfig
+	Scheduler     SchedulerConfig
+	Search        SearchConfig
+	Listing       ListingConfig
+	SlowLog       SlowLogConfig
+	Usage         UsageConfig
+	Moderation    ModerationConfig
+	Digest        DigestConfig
+	Live          LiveConfig
 }
 
 type ServerConfig struct {
-	Host string
-	Port int
+	Host         string
+	Port         int
+	TunablesFile string // KEY=VALUE overrides of the tunables, read again on SIGHUP
+
+	// Proxies and load balancers, as IPs or CIDRs, whose X-Forwarded-For is
+	// believed; empty takes the client IP from the connection
+	TrustedProxies []string
+
+	// Request deadlines, 0 disables them
+	ReadTimeoutSeconds  int // GET requests
+	WriteTimeoutSeconds int // other requests
+	LongTimeoutSeconds  int // reindexes, consistency checks, bulk jobs and storage collection
+
+	// Read-only maintenance mode, switched at runtime from the admin API
+	ReadOnly                  bool // start rejecting writes
+	ReadOnlyRetryAfterSeconds int  // Retry-After of rejected writes
+	ReadOnlyRefreshSeconds    int  // how often replicas pick up a switch made on another one
 }
 
+// Database drivers
+const (
+	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverSQLite   = "sqlite" // local development, search uses SQLite FTS5 instead of Elasticsearch
+)
+
+// Search backends of the Postgres driver
+const (
+	SearchBackendElasticsearch = "elasticsearch"
+	SearchBackendPostgres      = "postgres"
+)
+
 type DatabaseConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Driver     string // postgres or sqlite
+	SQLitePath string // database file of the sqlite driver
+	Host       string
+	Port       int
+	User       string
+	Password   string
+	DBName     string
+	SSLMode    string
+
+	// Query tuning
+	PrepareStmt            bool // cache prepared statements per connection
+	SkipDefaultTransaction bool // single writes run without a wrapping transaction
+	QueryTimeoutMs         int  // default deadline of queries without one, 0 disables
+
+	// Transient failures, like a lost connection
+	RetryAttempts  int // attempts of a read outside transactions, 1 disables retries
+	RetryBackoffMs int // wait before the first retry, doubled for every next one
+
+	// Media reads served from memory while the database is unavailable
+	FallbackCacheSize          int // results kept per cache, 0 disables the fallback
+	FallbackCacheMaxAgeSeconds int // oldest result served
 }
 
 type ElasticsearchConfig struct {
-	URL   string
-	Index string
+	URL string
+	// Index name template, {env} and {tenant} are replaced by Environment and Tenant
+	Index       string
+	Environment string
+	Tenant      string
+
+	// Snapshots of the index, taken and restored by cmd/search-index
+	SnapshotRepository string // name of the snapshot repository
+	SnapshotLocation   string // registers the repository as a shared file system one there, empty uses it as registered
 }
 
 type RedisConfig struct {
@@ -42,17 +117,264 @@ type RedisConfig struct {
 }
 
 type QueueConfig struct {
+	Driver   string // "memory" or "rabbitmq"
 	Host     string
 	Port     int
 	User     string
 	Password string
+	// RabbitMQ
+	Exchange        string
+	ConsumerGroup   string // services sharing a group share their queues, empty for temporary queues
+	ChannelPoolSize int
+	Prefetch        int
 }
 
 type StorageConfig struct {
-	Type      string // "local" or "s3"
-	LocalPath string
-	S3Bucket  string
-	S3Region  string
+	Type             string // "local" or "s3"
+	LocalPath        string
+	UploadSigningKey string // signs local upload URLs, random per process when empty
+	S3Bucket         string
+	S3Region         string
+
+	S3Endpoint        string // S3-compatible endpoint like MinIO, AWS when empty
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3SessionToken    string
+}
+
+type UploadConfig struct {
+	URLTTLSeconds        int
+	MaxVideoFileSizeMB   int
+	MaxPodcastFileSizeMB int
+	StorageQuotaMB       int      // total size of a tenant's media, 0 is unlimited
+	TenantLimits         []string // "tenant:key=value" overrides, keys video_max_mb, podcast_max_mb, url_ttl_seconds and quota_mb
+}
+
+type OutboxConfig struct {
+	Enabled        bool
+	RelayName      string
+	BatchSize      int
+	PollIntervalMs int
+	RetentionHours int
+	MetricsPort    int
+}
+
+type ProcessingConfig struct {
+	QueueCapacity   int
+	StarvationLimit int // high priority jobs served in a row before a normal one
+	Workers         int
+	// Retries of processing failing for transient reasons, e.g. storage outages
+	MaxAttempts          int
+	RetryDelaySeconds    int // doubled for every next retry
+	MaxRetryDelaySeconds int
+	ResumeOnStart        bool // queue media left in processing state on startup
+	WarmThumbnails       bool // render the default thumbnail once media is processed
+	// Per task type concurrency, 0 means unlimited
+	MetadataConcurrency  int
+	ThumbnailConcurrency int
+	TranscodeConcurrency int
+	FFmpegMaxProcesses   int
+	FFmpegPath           string
+	// Renditions transcoded from processed videos and packaged as HLS
+	TranscodeRenditions bool
+	TranscodeWorkDir    string // local scratch space, the system temp dir when empty
+	HLSSegmentSeconds   int
+	MetadataProbe       bool // read duration, resolution and codecs with ffprobe, simulated when false
+	FFprobePath         string
+	ID3WriteBack        bool // write corrected ID3 tags into downloaded podcast files
+	// Audio fingerprinting linking re-encoded copies to the original upload
+	FingerprintEnabled   bool
+	FpcalcPath           string
+	FingerprintLength    int     // seconds of audio fingerprinted, 0 uses the fpcalc default
+	FingerprintThreshold float64 // similarity from which media is linked as a near-duplicate
+	// Chapter boundaries suggested from long silences and music stingers
+	ChapterSuggestions bool
+	SilenceNoiseDB     float64 // audio quieter than this is silent
+	MinSilenceSeconds  float64
+	MinChapterSeconds  int
+	// Labels and categories suggested from transcripts
+	SuggestionCategories    []string // "category:word|word" scored by the mentions of their words, built-in categories when empty
+	SuggestionMinConfidence float64  // suggestions below this confidence are not stored
+	// Explicit language in transcripts flags media as explicit
+	ExplicitLanguageDetection bool
+	ExplicitLanguageTerms     []string // single words, built-in English and Arabic ones when empty
+}
+
+type EmbedConfig struct {
+	PublicBaseURL  string
+	AllowedOrigins []string
+	Theme          string
+	AccentColor    string
+}
+
+type WatermarkConfig struct {
+	Enabled  bool     // watermark all videos
+	Tenants  []string // tenants whose videos are watermarked
+	Shows    []string // shows whose videos are watermarked
+	LogoPath string   // "{tenant}" is replaced with the tenant of the media
+	Position string
+	Opacity  float64
+	Margin   int // in pixels
+}
+
+type DRMConfig struct {
+	Enabled                bool
+	WidevineLicenseURL     string
+	FairPlayLicenseURL     string
+	FairPlayCertificateURL string
+	KeyEncryptionKey       string // base64 encoded 32-byte key encrypting stored content keys
+}
+
+type GeoIPConfig struct {
+	DatabasePath  string // CSV range database of start_ip,end_ip,country; lookups are disabled when empty
+	CountryHeader string // header carrying the client country set by a trusted CDN, e.g. CF-IPCountry
+}
+
+type LicenseConfig struct {
+	CheckIntervalMinutes int
+	ExpiryNoticeDays     int // 0 disables expiry notices
+	BatchSize            int
+}
+
+type UnpublishConfig struct {
+	CheckIntervalSeconds int
+	BatchSize            int
+}
+
+type PremiereConfig struct {
+	CheckIntervalSeconds int // how late premieres may be announced
+	BatchSize            int
+}
+
+type StreamConfig struct {
+	URLTTLSeconds         int // validity of signed manifest and segment URLs
+	DownloadURLTTLSeconds int // validity of signed download URLs of uploaded files, at most 7 days on S3
+}
+
+type TrashConfig struct {
+	RetentionDays        int // deleted media is purged this long after deletion, 0 keeps it forever
+	CheckIntervalSeconds int
+	BatchSize            int
+}
+
+// SlowLogConfig are the latencies beyond which operations are logged and counted as slow, 0 disables them
+type SlowLogConfig struct {
+	QueryMs   int // SQL statements
+	SearchMs  int // Elasticsearch requests
+	RequestMs int // HTTP requests
+}
+
+type StorageGCConfig struct {
+	IntervalHours int  // how often unreferenced stored objects are collected, 0 disables the periodic collection
+	GraceHours    int  // objects written more recently are never collected
+	DryRun        bool // periodic collections only report what they would delete
+	BatchSize     int
+}
+
+type EntitlementConfig struct {
+	Provider     string // none or http
+	ServiceURL   string // subscription service answering GET /users/{id}/entitlement
+	CacheSeconds int    // how long resolved tiers are reused
+}
+
+type ErrorTrackingConfig struct {
+	Sink        string // none, log or sentry
+	SentryDSN   string
+	Environment string
+	BufferSize  int
+}
+
+type SchedulerConfig struct {
+	LeaderElection       bool // run singleton jobs only on the replica holding the leader lock
+	LeaderCheckIntervalS int  // seconds between leadership checks
+}
+
+type ListingConfig struct {
+	MediaCountMode string // exact or estimated total of unfiltered media listings
+}
+
+type SearchConfig struct {
+	ReindexBatchSize         int // documents written per statement or bulk request when rebuilding the index
+	SuggestionRefreshMinutes int // how often the precomputed Postgres suggestions are recomputed
+	HydrationCacheTTLSeconds int // how long hit metadata fetched from CMS is reused, 0 fetches it for every search
+	ReindexCooldownSeconds   int // minimum time between the starts of two reindexes, 0 disables the cooldown
+
+	// advanced parses "phrases", tag: and type: filters, -exclusions and OR in
+	// queries, plain matches their words literally
+	QuerySyntax string
+
+	ConsistencyCheckMinutes int  // how often the index is compared with the database, 0 disables the periodic check
+	ConsistencyAutoHeal     bool // repair the drift found by periodic checks
+
+	// While Elasticsearch is unavailable, searches are served from the Postgres
+	// search index, which is kept up to date alongside it
+	PostgresFallback     bool
+	FallbackRetrySeconds int // how long searches skip Elasticsearch after it failed
+
+	// Backend serves searches outside of SQLite. While migrating to another
+	// one, ShadowBackend is written alongside it and a sample of the searches
+	// is compared against it, discrepancies are logged.
+	Backend                  string  // SearchBackendElasticsearch or SearchBackendPostgres
+	ShadowBackend            string  // empty when not migrating
+	ShadowElasticsearchURL   string  // cluster of an Elasticsearch shadow backend
+	ShadowElasticsearchIndex string  // index name template of an Elasticsearch shadow backend
+	ShadowSampleRate         float64 // share of searches and suggestions compared, from 0 to 1
+	ShadowTimeoutMs          int     // how long a compared shadow search may take
+
+	// Representative queries searched, and suggested for as they are typed,
+	// before the discovery service starts serving, to load its caches
+	WarmupQueries        []string
+	WarmupTimeoutSeconds int // how long the warm-up may delay startup
+
+	// Media index events consumed from the queue are retried with exponential
+	// backoff before they are dead-lettered
+	IndexEventMaxAttempts    int
+	IndexEventRetryBackoffMs int // wait before the first retry
+}
+
+// UsageConfig configures API usage metering, counted in Redis
+type UsageConfig struct {
+	Enabled               bool
+	TenantHeader          string // header the API gateway passes the tenant of the request in
+	RollupIntervalMinutes int
+	CounterRetentionDays  int // live counters are kept this long after their last request
+}
+
+// ModerationConfig configures the abuse reports of end users
+type ModerationConfig struct {
+	ReportsPerHour int // abuse reports a user or client may file per hour
+}
+
+// DigestConfig configures the weekly digest of catalog activity
+type DigestConfig struct {
+	Enabled      bool   // also counts the searches of tenants in the discovery service
+	Weekday      string // day the digests are sent on
+	Hour         int    // UTC hour the digests are sent at
+	TopItems     int    // failures, searches and played items listed
+	TemplatePath string // text/template of the digest body, the built-in one when empty
+}
+
+type LiveConfig struct {
+	IngestURL string // RTMP server encoders publish to with the stream key
+}
+
+type AuthConfig struct {
+	AdminAPIKey string
+	UserHeader  string // header carrying the ID of the user signed in at the API gateway; users are anonymous when empty
+	RoleHeader  string // header carrying the role of the signed in user; users are viewers when empty
+	// GatewaySecret is sent by the API gateway in X-Gateway-Secret; the user and
+	// role headers are ignored on requests without it, and always when empty
+	GatewaySecret string
+}
+
+type NotificationConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUser     string
+	SMTPPassword string
+	EmailFrom    string
+	QueueSize    int
+	Workers      int
 }
 
 func Load() *Config {
@@ -60,18 +382,46 @@ func Load() *Config {
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "localhost"),
 			Port: getEnvAsInt("SERVER_PORT", 8080),
+
+			TunablesFile: getEnv("TUNABLES_FILE", ""),
+
+			TrustedProxies: getEnvAsSlice("TRUSTED_PROXIES", nil),
+
+			ReadTimeoutSeconds:  getEnvAsInt("ROUTE_READ_TIMEOUT_SECONDS", 10),
+			WriteTimeoutSeconds: getEnvAsInt("ROUTE_WRITE_TIMEOUT_SECONDS", 30),
+			LongTimeoutSeconds:  getEnvAsInt("ROUTE_LONG_TIMEOUT_SECONDS", 600),
+
+			ReadOnly:                  getEnvAsBool("READ_ONLY_MODE", false),
+			ReadOnlyRetryAfterSeconds: getEnvAsInt("READ_ONLY_RETRY_AFTER_SECONDS", 60),
+			ReadOnlyRefreshSeconds:    getEnvAsInt("READ_ONLY_REFRESH_SECONDS", 5),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "thamaniyah"),
-			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+			Driver:     getEnv("DB_DRIVER", DatabaseDriverPostgres),
+			SQLitePath: getEnv("DB_SQLITE_PATH", "data/thamaniyah.db"),
+			Host:       getEnv("DB_HOST", "localhost"),
+			Port:       getEnvAsInt("DB_PORT", 5432),
+			User:       getEnv("DB_USER", "postgres"),
+			Password:   getEnv("DB_PASSWORD", "postgres"),
+			DBName:     getEnv("DB_NAME", "thamaniyah"),
+			SSLMode:    getEnv("DB_SSL_MODE", "disable"),
+
+			PrepareStmt:                getEnvAsBool("DB_PREPARE_STMT", true),
+			SkipDefaultTransaction:     getEnvAsBool("DB_SKIP_DEFAULT_TRANSACTION", true),
+			QueryTimeoutMs:             getEnvAsInt("DB_QUERY_TIMEOUT_MS", 5000),
+			RetryAttempts:              getEnvAsInt("DB_RETRY_ATTEMPTS", 3),
+			RetryBackoffMs:             getEnvAsInt("DB_RETRY_BACKOFF_MS", 100),
+			FallbackCacheSize:          getEnvAsInt("DB_FALLBACK_CACHE_SIZE", 10000),
+			FallbackCacheMaxAgeSeconds: getEnvAsInt("DB_FALLBACK_CACHE_MAX_AGE_SECONDS", 900),
 		},
 		Elasticsearch: ElasticsearchConfig{
 			URL:   getEnv("ELASTICSEARCH_URL", "http://localhost:9200"),
 			Index: getEnv("ELASTICSEARCH_INDEX", "media"),
+
+			Environment: getEnv("APP_ENV", "development"),
+			Tenant:      getEnv("ELASTICSEARCH_TENANT", "default"),
+
+			SnapshotRepository: getEnv("ELASTICSEARCH_SNAPSHOT_REPOSITORY", "media_backups"),
+			SnapshotLocation:   getEnv("ELASTICSEARCH_SNAPSHOT_LOCATION", ""),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -80,16 +430,208 @@ func Load() *Config {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 		Queue: QueueConfig{
+			Driver:   getEnv("QUEUE_DRIVER", "memory"),
 			Host:     getEnv("RABBITMQ_HOST", "localhost"),
 			Port:     getEnvAsInt("RABBITMQ_PORT", 5672),
 			User:     getEnv("RABBITMQ_USER", "admin"),
 			Password: getEnv("RABBITMQ_PASSWORD", "admin"),
+
+			Exchange:        getEnv("RABBITMQ_EXCHANGE", "thamaniyah.events"),
+			ConsumerGroup:   getEnv("RABBITMQ_CONSUMER_GROUP", ""),
+			ChannelPoolSize: getEnvAsInt("RABBITMQ_CHANNEL_POOL_SIZE", 8),
+			Prefetch:        getEnvAsInt("RABBITMQ_PREFETCH", 20),
 		},
 		Storage: StorageConfig{
-			Type:      getEnv("STORAGE_TYPE", "local"),
-			LocalPath: getEnv("STORAGE_LOCAL_PATH", "./uploads"),
-			S3Bucket:  getEnv("STORAGE_S3_BUCKET", ""),
-			S3Region:  getEnv("STORAGE_S3_REGION", "us-east-1"),
+			Type:             getEnv("STORAGE_TYPE", "local"),
+			LocalPath:        getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+			UploadSigningKey: getEnv("STORAGE_UPLOAD_SIGNING_KEY", ""),
+			S3Bucket:         getEnv("STORAGE_S3_BUCKET", ""),
+			S3Region:         getEnv("STORAGE_S3_REGION", "us-east-1"),
+
+			S3Endpoint:        getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			S3SessionToken:    getEnv("AWS_SESSION_TOKEN", ""),
+		},
+		Upload: UploadConfig{
+			URLTTLSeconds:        getEnvAsInt("UPLOAD_URL_TTL_SECONDS", 3600),
+			MaxVideoFileSizeMB:   getEnvAsInt("UPLOAD_MAX_VIDEO_SIZE_MB", 5120),
+			MaxPodcastFileSizeMB: getEnvAsInt("UPLOAD_MAX_PODCAST_SIZE_MB", 1024),
+			StorageQuotaMB:       getEnvAsInt("UPLOAD_STORAGE_QUOTA_MB", 0),
+			TenantLimits:         getEnvAsSlice("UPLOAD_TENANT_LIMITS", nil),
+		},
+		Embed: EmbedConfig{
+			PublicBaseURL:  getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+			AllowedOrigins: getEnvAsSlice("EMBED_ALLOWED_ORIGINS", nil),
+			Theme:          getEnv("EMBED_THEME", "dark"),
+			AccentColor:    getEnv("EMBED_ACCENT_COLOR", "#1DB954"),
+		},
+		Watermark: WatermarkConfig{
+			Enabled:  getEnvAsBool("WATERMARK_ENABLED", false),
+			Tenants:  getEnvAsSlice("WATERMARK_TENANTS", nil),
+			Shows:    getEnvAsSlice("WATERMARK_SHOWS", nil),
+			LogoPath: getEnv("WATERMARK_LOGO_PATH", "./assets/logos/{tenant}.png"),
+			Position: getEnv("WATERMARK_POSITION", "bottom-right"),
+			Opacity:  getEnvAsFloat("WATERMARK_OPACITY", 0.6),
+			Margin:   getEnvAsInt("WATERMARK_MARGIN", 24),
+		},
+		DRM: DRMConfig{
+			Enabled:                getEnvAsBool("DRM_ENABLED", false),
+			WidevineLicenseURL:     getEnv("DRM_WIDEVINE_LICENSE_URL", ""),
+			FairPlayLicenseURL:     getEnv("DRM_FAIRPLAY_LICENSE_URL", ""),
+			FairPlayCertificateURL: getEnv("DRM_FAIRPLAY_CERTIFICATE_URL", ""),
+			KeyEncryptionKey:       getEnv("DRM_KEY_ENCRYPTION_KEY", ""),
+		},
+		GeoIP: GeoIPConfig{
+			DatabasePath:  getEnv("GEOIP_DATABASE_PATH", ""),
+			CountryHeader: getEnv("GEOIP_COUNTRY_HEADER", ""),
+		},
+		License: LicenseConfig{
+			CheckIntervalMinutes: getEnvAsInt("LICENSE_CHECK_INTERVAL_MINUTES", 60),
+			ExpiryNoticeDays:     getEnvAsInt("LICENSE_EXPIRY_NOTICE_DAYS", 7),
+			BatchSize:            getEnvAsInt("LICENSE_BATCH_SIZE", 100),
+		},
+		Unpublish: UnpublishConfig{
+			CheckIntervalSeconds: getEnvAsInt("UNPUBLISH_CHECK_INTERVAL_SECONDS", 60),
+			BatchSize:            getEnvAsInt("UNPUBLISH_BATCH_SIZE", 100),
+		},
+		Premiere: PremiereConfig{
+			CheckIntervalSeconds: getEnvAsInt("PREMIERE_CHECK_INTERVAL_SECONDS", 15),
+			BatchSize:            getEnvAsInt("PREMIERE_BATCH_SIZE", 100),
+		},
+		Stream: StreamConfig{
+			URLTTLSeconds:         getEnvAsInt("STREAM_URL_TTL_SECONDS", 300),
+			DownloadURLTTLSeconds: getEnvAsInt("DOWNLOAD_URL_TTL_SECONDS", 900),
+		},
+		Trash: TrashConfig{
+			RetentionDays:        getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+			CheckIntervalSeconds: getEnvAsInt("TRASH_CHECK_INTERVAL_SECONDS", 3600),
+			BatchSize:            getEnvAsInt("TRASH_BATCH_SIZE", 100),
+		},
+		StorageGC: StorageGCConfig{
+			IntervalHours: getEnvAsInt("STORAGE_GC_INTERVAL_HOURS", 0),
+			GraceHours:    getEnvAsInt("STORAGE_GC_GRACE_HOURS", 24),
+			DryRun:        getEnvAsBool("STORAGE_GC_DRY_RUN", true),
+			BatchSize:     getEnvAsInt("STORAGE_GC_BATCH_SIZE", 500),
+		},
+		Entitlement: EntitlementConfig{
+			Provider:     getEnv("ENTITLEMENT_PROVIDER", "none"),
+			ServiceURL:   getEnv("ENTITLEMENT_SERVICE_URL", ""),
+			CacheSeconds: getEnvAsInt("ENTITLEMENT_CACHE_SECONDS", 60),
+		},
+		ErrorTracking: ErrorTrackingConfig{
+			Sink:        getEnv("ERROR_TRACKER", "none"),
+			SentryDSN:   getEnv("SENTRY_DSN", ""),
+			Environment: getEnv("APP_ENV", "development"),
+			BufferSize:  getEnvAsInt("ERROR_TRACKER_BUFFER_SIZE", 100),
+		},
+		Listing: ListingConfig{
+			MediaCountMode: getEnv("MEDIA_LIST_COUNT_MODE", "exact"),
+		},
+		SlowLog: SlowLogConfig{
+			QueryMs:   getEnvAsInt("SLOW_QUERY_MS", 200),
+			SearchMs:  getEnvAsInt("SLOW_SEARCH_MS", 500),
+			RequestMs: getEnvAsInt("SLOW_REQUEST_MS", 1000),
+		},
+		Search: SearchConfig{
+			ReindexBatchSize:         getEnvAsInt("SEARCH_REINDEX_BATCH_SIZE", 500),
+			SuggestionRefreshMinutes: getEnvAsInt("SEARCH_SUGGESTION_REFRESH_MINUTES", 10),
+			HydrationCacheTTLSeconds: getEnvAsInt("SEARCH_HYDRATION_CACHE_TTL_SECONDS", 30),
+			ReindexCooldownSeconds:   getEnvAsInt("SEARCH_REINDEX_COOLDOWN_SECONDS", 300),
+			QuerySyntax:              getEnv("SEARCH_QUERY_SYNTAX", "advanced"),
+			ConsistencyCheckMinutes:  getEnvAsInt("SEARCH_CONSISTENCY_CHECK_MINUTES", 0),
+			ConsistencyAutoHeal:      getEnvAsBool("SEARCH_CONSISTENCY_AUTO_HEAL", false),
+			PostgresFallback:         getEnvAsBool("SEARCH_POSTGRES_FALLBACK", true),
+			FallbackRetrySeconds:     getEnvAsInt("SEARCH_FALLBACK_RETRY_SECONDS", 30),
+			Backend:                  getEnv("SEARCH_BACKEND", SearchBackendElasticsearch),
+			ShadowBackend:            getEnv("SEARCH_SHADOW_BACKEND", ""),
+			ShadowElasticsearchURL:   getEnv("SEARCH_SHADOW_ELASTICSEARCH_URL", getEnv("ELASTICSEARCH_URL", "http://localhost:9200")),
+			ShadowElasticsearchIndex: getEnv("SEARCH_SHADOW_ELASTICSEARCH_INDEX", getEnv("ELASTICSEARCH_INDEX", "media")),
+			ShadowSampleRate:         getEnvAsFloat("SEARCH_SHADOW_SAMPLE_RATE", 1),
+			ShadowTimeoutMs:          getEnvAsInt("SEARCH_SHADOW_TIMEOUT_MS", 2000),
+			WarmupQueries:            getEnvAsSlice("SEARCH_WARMUP_QUERIES", nil),
+			WarmupTimeoutSeconds:     getEnvAsInt("SEARCH_WARMUP_TIMEOUT_SECONDS", 30),
+			IndexEventMaxAttempts:    getEnvAsInt("SEARCH_INDEX_EVENT_MAX_ATTEMPTS", 3),
+			IndexEventRetryBackoffMs: getEnvAsInt("SEARCH_INDEX_EVENT_RETRY_BACKOFF_MS", 500),
+		},
+		Scheduler: SchedulerConfig{
+			LeaderElection:       getEnvAsBool("SCHEDULER_LEADER_ELECTION", true),
+			LeaderCheckIntervalS: getEnvAsInt("SCHEDULER_LEADER_CHECK_INTERVAL_SECONDS", 15),
+		},
+		Auth: AuthConfig{
+			AdminAPIKey:   getEnv("ADMIN_API_KEY", ""),
+			UserHeader:    getEnv("AUTH_USER_HEADER", ""),
+			RoleHeader:    getEnv("AUTH_ROLE_HEADER", ""),
+			GatewaySecret: getEnv("AUTH_GATEWAY_SECRET", ""),
+		},
+		Notification: NotificationConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+			SMTPUser:     getEnv("SMTP_USER", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			EmailFrom:    getEnv("NOTIFICATION_EMAIL_FROM", "no-reply@thamaniyah.local"),
+			QueueSize:    getEnvAsInt("NOTIFICATION_QUEUE_SIZE", 1000),
+			Workers:      getEnvAsInt("NOTIFICATION_WORKERS", 2),
+		},
+		Outbox: OutboxConfig{
+			Enabled:        getEnvAsBool("OUTBOX_ENABLED", false),
+			RelayName:      getEnv("OUTBOX_RELAY_NAME", "outbox-relay"),
+			BatchSize:      getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			PollIntervalMs: getEnvAsInt("OUTBOX_POLL_INTERVAL_MS", 1000),
+			RetentionHours: getEnvAsInt("OUTBOX_RETENTION_HOURS", 168),
+			MetricsPort:    getEnvAsInt("OUTBOX_METRICS_PORT", 9091),
+		},
+		Processing: ProcessingConfig{
+			QueueCapacity:   getEnvAsInt("PROCESSING_QUEUE_CAPACITY", 1000),
+			StarvationLimit: getEnvAsInt("PROCESSING_STARVATION_LIMIT", 5),
+			Workers:         getEnvAsInt("PROCESSING_WORKERS", 4),
+
+			MaxAttempts:          getEnvAsInt("PROCESSING_MAX_ATTEMPTS", 3),
+			RetryDelaySeconds:    getEnvAsInt("PROCESSING_RETRY_DELAY_SECONDS", 10),
+			MaxRetryDelaySeconds: getEnvAsInt("PROCESSING_MAX_RETRY_DELAY_SECONDS", 300),
+			ResumeOnStart:        getEnvAsBool("PROCESSING_RESUME_ON_START", true),
+			WarmThumbnails:       getEnvAsBool("PROCESSING_WARM_THUMBNAILS", true),
+
+			MetadataConcurrency:       getEnvAsInt("PROCESSING_METADATA_CONCURRENCY", 4),
+			ThumbnailConcurrency:      getEnvAsInt("PROCESSING_THUMBNAIL_CONCURRENCY", 8),
+			TranscodeConcurrency:      getEnvAsInt("PROCESSING_TRANSCODE_CONCURRENCY", 1),
+			FFmpegMaxProcesses:        getEnvAsInt("FFMPEG_MAX_PROCESSES", 2),
+			FFmpegPath:                getEnv("FFMPEG_PATH", "ffmpeg"),
+			TranscodeRenditions:       getEnvAsBool("TRANSCODE_RENDITIONS_ENABLED", false),
+			TranscodeWorkDir:          getEnv("TRANSCODE_WORK_DIR", ""),
+			HLSSegmentSeconds:         getEnvAsInt("HLS_SEGMENT_SECONDS", 6),
+			ID3WriteBack:              getEnvAsBool("ID3_WRITE_BACK", true),
+			FingerprintEnabled:        getEnvAsBool("FINGERPRINT_ENABLED", false),
+			FpcalcPath:                getEnv("FPCALC_PATH", "fpcalc"),
+			FingerprintLength:         getEnvAsInt("FINGERPRINT_LENGTH_SECONDS", 120),
+			FingerprintThreshold:      getEnvAsFloat("FINGERPRINT_THRESHOLD", 0.85),
+			ChapterSuggestions:        getEnvAsBool("CHAPTER_SUGGESTIONS_ENABLED", false),
+			SilenceNoiseDB:            getEnvAsFloat("CHAPTER_SILENCE_NOISE_DB", -35),
+			MinSilenceSeconds:         getEnvAsFloat("CHAPTER_MIN_SILENCE_SECONDS", 2),
+			MinChapterSeconds:         getEnvAsInt("CHAPTER_MIN_SECONDS", 60),
+			SuggestionCategories:      getEnvAsSlice("SUGGESTION_CATEGORIES", nil),
+			SuggestionMinConfidence:   getEnvAsFloat("SUGGESTION_MIN_CONFIDENCE", 0.4),
+			ExplicitLanguageDetection: getEnvAsBool("EXPLICIT_LANGUAGE_DETECTION", false),
+			ExplicitLanguageTerms:     getEnvAsSlice("EXPLICIT_LANGUAGE_TERMS", nil),
+		},
+		Usage: UsageConfig{
+			Enabled:               getEnvAsBool("USAGE_METERING_ENABLED", false),
+			TenantHeader:          getEnv("USAGE_TENANT_HEADER", "X-Tenant-ID"),
+			RollupIntervalMinutes: getEnvAsInt("USAGE_ROLLUP_INTERVAL_MINUTES", 15),
+			CounterRetentionDays:  getEnvAsInt("USAGE_COUNTER_RETENTION_DAYS", 7),
+		},
+		Moderation: ModerationConfig{
+			ReportsPerHour: getEnvAsInt("ABUSE_REPORTS_PER_HOUR", 10),
+		},
+		Digest: DigestConfig{
+			Enabled:      getEnvAsBool("DIGEST_ENABLED", false),
+			Weekday:      getEnv("DIGEST_WEEKDAY", "monday"),
+			Hour:         getEnvAsInt("DIGEST_HOUR", 8),
+			TopItems:     getEnvAsInt("DIGEST_TOP_ITEMS", 5),
+			TemplatePath: getEnv("DIGEST_TEMPLATE_PATH", ""),
+		},
+		Live: LiveConfig{
+			IngestURL: getEnv("LIVE_INGEST_URL", "rtmp://localhost:1935/live"),
 		},
 	}
 }
@@ -105,6 +647,10 @@ func (c *Config) DatabaseURL() string {
 	)
 }
 
+func (c *Config) RedisAddr() string {
+	return fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port)
+}
+
 func (c *Config) QueueURL() string {
 	return fmt.Sprintf("amqp://%s:%s@%s:%d/",
 		c.Queue.User,
@@ -128,3 +674,34 @@ func getEnvAsInt(name string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(name string, defaultValue float64) float64 {
+	valueStr := getEnv(name, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(name string, defaultValue bool) bool {
+	valueStr := getEnv(name, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsSlice(name string, defaultValue []string) []string {
+	valueStr := getEnv(name, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, value := range strings.Split(valueStr, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Log levels of the request log
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"  // only 4xx and 5xx requests
+	LogLevelError = "error" // only 5xx requests
+)
+
+// Tunables are the settings that can change while the services run
+type Tunables struct {
+	LogLevel                string   `json:"log_level"`
+	LogSampleAfterPerSecond int      `json:"log_sample_after_per_second"` // successful requests per second logged in full, 0 disables sampling
+	LogSampleRate           float64  `json:"log_sample_rate"`             // share of the successful requests beyond it that are logged
+	RateLimitPerMinute      int      `json:"rate_limit_per_minute"`       // requests per client IP, 0 disables the limit
+	SearchTitleBoost        float64  `json:"search_title_boost"`
+	SearchDescriptionBoost  float64  `json:"search_description_boost"`
+	SearchShowBoost         float64  `json:"search_show_boost"`     // show title indexed with episodes
+	SearchCategoryBoost     float64  `json:"search_category_boost"` // category names indexed with episodes
+	SearchHostBoost         float64  `json:"search_host_boost"`     // host names indexed with episodes
+	SearchGuestBoost        float64  `json:"search_guest_boost"`    // guest names indexed with episodes
+	CORSAllowedOrigins      []string `json:"cors_allowed_origins"`
+}
+
+// Validate checks that the tunables can be applied
+func (t *Tunables) Validate() error {
+	switch t.LogLevel {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		return fmt.Errorf("unknown log level %q", t.LogLevel)
+	}
+	if t.LogSampleAfterPerSecond < 0 {
+		return fmt.Errorf("log sampling threshold must not be negative")
+	}
+	if t.LogSampleRate < 0 || t.LogSampleRate > 1 {
+		return fmt.Errorf("log sample rate must be between 0 and 1")
+	}
+	if t.RateLimitPerMinute < 0 {
+		return fmt.Errorf("rate limit must not be negative")
+	}
+	if t.SearchTitleBoost <= 0 || t.SearchDescriptionBoost <= 0 ||
+		t.SearchShowBoost <= 0 || t.SearchCategoryBoost <= 0 || t.SearchHostBoost <= 0 ||
+		t.SearchGuestBoost <= 0 {
+		return fmt.Errorf("search boosts must be positive")
+	}
+	return nil
+}
+
+// TunablesStore holds the current tunables. They are read from the environment,
+// overridden by the KEY=VALUE lines of an optional tunables file, which is read
+// again on every reload.
+type TunablesStore struct {
+	path    string
+	current atomic.Pointer[Tunables]
+	mu      sync.Mutex // serializes reloads
+}
+
+// NewTunablesStore loads the tunables, reading overrides from path when it is set
+func NewTunablesStore(path string) (*TunablesStore, error) {
+	store := &TunablesStore{path: path}
+	if _, err := store.Reload(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Get returns the current tunables, which must not be modified
+func (s *TunablesStore) Get() *Tunables {
+	return s.current.Load()
+}
+
+// Reload reads the tunables again. Invalid tunables are rejected and the
+// current ones are kept.
+func (s *TunablesStore) Reload() (*Tunables, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides, err := readTunablesFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	lookup := func(key string) string {
+		if value, ok := overrides[key]; ok {
+			return value
+		}
+		return os.Getenv(key)
+	}
+
+	tunables := &Tunables{
+		LogLevel:                strings.ToLower(lookupString(lookup, "LOG_LEVEL", LogLevelInfo)),
+		LogSampleAfterPerSecond: lookupInt(lookup, "LOG_SAMPLE_AFTER_PER_SECOND", 0),
+		LogSampleRate:           lookupFloat(lookup, "LOG_SAMPLE_RATE", 0.1),
+		RateLimitPerMinute:      lookupInt(lookup, "RATE_LIMIT_PER_MINUTE", 0),
+		SearchTitleBoost:        lookupFloat(lookup, "SEARCH_TITLE_BOOST", 2),
+		SearchDescriptionBoost:  lookupFloat(lookup, "SEARCH_DESCRIPTION_BOOST", 1),
+		SearchShowBoost:         lookupFloat(lookup, "SEARCH_SHOW_BOOST", 1.5),
+		SearchCategoryBoost:     lookupFloat(lookup, "SEARCH_CATEGORY_BOOST", 1),
+		SearchHostBoost:         lookupFloat(lookup, "SEARCH_HOST_BOOST", 1.5),
+		SearchGuestBoost:        lookupFloat(lookup, "SEARCH_GUEST_BOOST", 1),
+		CORSAllowedOrigins:      lookupSlice(lookup, "CORS_ALLOWED_ORIGINS", []string{"*"}),
+	}
+	if err := tunables.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tunables: %w", err)
+	}
+
+	s.current.Store(tunables)
+	return tunables, nil
+}
+
+// ReloadOnSignal reloads the tunables whenever one of signals is received, until ctx is cancelled
+func (s *TunablesStore) ReloadOnSignal(ctx context.Context, signals ...os.Signal) {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	defer signal.Stop(received)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-received:
+			if _, err := s.Reload(); err != nil {
+				log.Printf("Failed to reload tunables on %s: %v", sig, err)
+				continue
+			}
+			log.Printf("Reloaded tunables on %s", sig)
+		}
+	}
+}
+
+// readTunablesFile parses KEY=VALUE lines, ignoring blank lines and # comments
+func readTunablesFile(path string) (map[string]string, error) {
+	values := make(map[string]string)
+	if path == "" {
+		return values, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tunables file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tunables line %q", line)
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tunables file: %w", err)
+	}
+
+	return values, nil
+}
+
+func lookupString(lookup func(string) string, key, defaultValue string) string {
+	if value := lookup(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func lookupInt(lookup func(string) string, key string, defaultValue int) int {
+	if value, err := strconv.Atoi(lookup(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func lookupFloat(lookup func(string) string, key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(lookup(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func lookupSlice(lookup func(string) string, key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(lookup(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTunablesFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestTunables_Validate(t *testing.T) {
+	valid := func() Tunables {
+		return Tunables{
+			LogLevel:               LogLevelInfo,
+			LogSampleRate:          0.1,
+			SearchTitleBoost:       2,
+			SearchDescriptionBoost: 1,
+			SearchShowBoost:        1.5,
+			SearchCategoryBoost:    1,
+			SearchHostBoost:        1.5,
+			SearchGuestBoost:       1,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Tunables)
+		wantErr bool
+	}{
+		{"valid", func(*Tunables) {}, false},
+		{"unknown log level", func(t *Tunables) { t.LogLevel = "verbose" }, true},
+		{"negative sampling threshold", func(t *Tunables) { t.LogSampleAfterPerSecond = -1 }, true},
+		{"sample rate above one", func(t *Tunables) { t.LogSampleRate = 1.5 }, true},
+		{"negative sample rate", func(t *Tunables) { t.LogSampleRate = -0.1 }, true},
+		{"negative rate limit", func(t *Tunables) { t.RateLimitPerMinute = -1 }, true},
+		{"zero boost", func(t *Tunables) { t.SearchGuestBoost = 0 }, true},
+		{"negative boost", func(t *Tunables) { t.SearchTitleBoost = -2 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tunables := valid()
+			tt.modify(&tunables)
+
+			err := tunables.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTunablesStore_FileOverridesEnvironment(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "60")
+
+	path := filepath.Join(t.TempDir(), "tunables.env")
+	writeTunablesFile(t, path, `
+# overrides
+LOG_LEVEL = "WARN"
+CORS_ALLOWED_ORIGINS=https://a.example, https://b.example
+`)
+
+	store, err := NewTunablesStore(path)
+	require.NoError(t, err)
+
+	tunables := store.Get()
+	assert.Equal(t, LogLevelWarn, tunables.LogLevel)
+	assert.Equal(t, 60, tunables.RateLimitPerMinute)
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, tunables.CORSAllowedOrigins)
+}
+
+func TestTunablesStore_Reload(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+
+	path := filepath.Join(t.TempDir(), "tunables.env")
+	writeTunablesFile(t, path, "SEARCH_TITLE_BOOST=3\n")
+
+	store, err := NewTunablesStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, store.Get().SearchTitleBoost)
+	assert.Equal(t, LogLevelInfo, store.Get().LogLevel)
+
+	t.Run("picks up changes", func(t *testing.T) {
+		writeTunablesFile(t, path, "SEARCH_TITLE_BOOST=4\nLOG_LEVEL=error\n")
+
+		tunables, err := store.Reload()
+		require.NoError(t, err)
+		assert.Equal(t, 4.0, tunables.SearchTitleBoost)
+		assert.Equal(t, LogLevelError, tunables.LogLevel)
+		assert.Same(t, tunables, store.Get())
+	})
+
+	t.Run("keeps current tunables when invalid", func(t *testing.T) {
+		current := store.Get()
+		writeTunablesFile(t, path, "SEARCH_TITLE_BOOST=0\n")
+
+		_, err := store.Reload()
+		assert.Error(t, err)
+		assert.Same(t, current, store.Get())
+	})
+
+	t.Run("keeps current tunables when the file is malformed", func(t *testing.T) {
+		current := store.Get()
+		writeTunablesFile(t, path, "LOG_LEVEL\n")
+
+		_, err := store.Reload()
+		assert.Error(t, err)
+		assert.Same(t, current, store.Get())
+	})
+
+	t.Run("keeps current tunables when the file is missing", func(t *testing.T) {
+		current := store.Get()
+		require.NoError(t, os.Remove(path))
+
+		_, err := store.Reload()
+		assert.Error(t, err)
+		assert.Same(t, current, store.Get())
+	})
+}
+
+func TestNewTunablesStore_Invalid(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	store, err := NewTunablesStore("")
+	assert.Error(t, err)
+	assert.Nil(t, store)
+}
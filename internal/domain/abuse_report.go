@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Abuse reporting
+const (
+	AbuseReportWindow            = time.Hour // reports of a reporter are rate limited per window
+	DefaultAbuseReportsPerWindow = 10
+	MaxAbuseReportDetailsLength  = 1000 // characters
+)
+
+// Moderation queue listings
+const (
+	DefaultModerationQueueLimit = 20
+	MaxModerationQueueLimit     = 100
+)
+
+// AbuseReason is the reason code of an abuse report
+type AbuseReason string
+
+const (
+	AbuseReasonSpam           AbuseReason = "spam"
+	AbuseReasonHarassment     AbuseReason = "harassment"
+	AbuseReasonHateSpeech     AbuseReason = "hate_speech"
+	AbuseReasonViolence       AbuseReason = "violence"
+	AbuseReasonSexualContent  AbuseReason = "sexual_content"
+	AbuseReasonCopyright      AbuseReason = "copyright"
+	AbuseReasonMisinformation AbuseReason = "misinformation"
+	AbuseReasonOther          AbuseReason = "other"
+)
+
+// IsValid checks if the abuse reason is supported
+func (r AbuseReason) IsValid() bool {
+	switch r {
+	case AbuseReasonSpam, AbuseReasonHarassment, AbuseReasonHateSpeech, AbuseReasonViolence,
+		AbuseReasonSexualContent, AbuseReasonCopyright, AbuseReasonMisinformation, AbuseReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// AbuseReportStatus is the moderation state of an abuse report
+type AbuseReportStatus string
+
+const (
+	AbuseReportOpen      AbuseReportStatus = "open"      // waiting in the moderation queue
+	AbuseReportDismissed AbuseReportStatus = "dismissed" // reviewed, the media was left as is
+	AbuseReportActioned  AbuseReportStatus = "actioned"  // reviewed, the media was acted on
+)
+
+// IsDecision reports whether the status is a moderation decision closing reports
+func (s AbuseReportStatus) IsDecision() bool {
+	return s == AbuseReportDismissed || s == AbuseReportActioned
+}
+
+// AbuseReport is the report of a media item by an end user. A reporter has at
+// most one open report per media item, repeated reports are folded into it.
+type AbuseReport struct {
+	ID           string            `json:"id" gorm:"primaryKey"`
+	MediaID      string            `json:"media_id" gorm:"not null;index:idx_abuse_reports_media_reporter,priority:1"`
+	ReporterHash string            `json:"-" gorm:"type:varchar(64);not null;index:idx_abuse_reports_media_reporter,priority:2;index"`
+	Reason       AbuseReason       `json:"reason" gorm:"type:varchar(30);not null"`
+	Details      string            `json:"details,omitempty" gorm:"type:text"`
+	Status       AbuseReportStatus `json:"status" gorm:"type:varchar(20);not null;default:'open';index"`
+	Repeats      int               `json:"repeats"` // times the reporter reported the media again while open
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"` // last reported
+	ResolvedAt   *time.Time        `json:"resolved_at,omitempty"`
+}
+
+// TableName specifies the table name for AbuseReport
+func (AbuseReport) TableName() string {
+	return "abuse_reports"
+}
+
+// AbuseReporter describes who files an abuse report
+type AbuseReporter struct {
+	UserID    string // signed in user, empty for anonymous reporters
+	IP        string
+	UserAgent string
+}
+
+// Hash identifies the reporter without storing who they are: signed in users
+// by their ID, anonymous reporters by IP address and user agent
+func (r AbuseReporter) Hash() string {
+	key := "client:" + r.IP + "\n" + r.UserAgent
+	if r.UserID != "" {
+		key = "user:" + r.UserID
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AbuseReportRequest represents an end user's report of a media item
+type AbuseReportRequest struct {
+	Reason  AbuseReason `json:"reason" binding:"required"`
+	Details string      `json:"details"` // free text, required when the reason is other
+}
+
+// Normalize trims the details of the report
+func (r *AbuseReportRequest) Normalize() {
+	r.Details = strings.TrimSpace(r.Details)
+}
+
+// Validate validates the abuse report request
+func (r *AbuseReportRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if !r.Reason.IsValid() {
+		errs.Add("reason", "must be one of: spam, harassment, hate_speech, violence, sexual_content, copyright, misinformation, other")
+	}
+	if r.Reason == AbuseReasonOther && r.Details == "" {
+		errs.Add("details", "is required when the reason is other")
+	}
+	if utf8.RuneCountInString(r.Details) > MaxAbuseReportDetailsLength {
+		errs.Add("details", "must be at most 1000 characters")
+	}
+
+	return errs
+}
+
+// ResolveAbuseReportsRequest represents a moderator's decision on the open reports of a media item
+type ResolveAbuseReportsRequest struct {
+	Decision AbuseReportStatus `json:"decision" binding:"required"` // dismissed or actioned
+}
+
+// Validate validates the resolve request
+func (r *ResolveAbuseReportsRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if !r.Decision.IsDecision() {
+		errs.Add("decision", "must be one of: dismissed, actioned")
+	}
+
+	return errs
+}
+
+// ModerationQueueItem folds the open reports of a media item for moderators
+type ModerationQueueItem struct {
+	MediaID         string              `json:"media_id"`
+	ReportCount     int                 `json:"report_count"` // distinct reporters
+	Reasons         map[AbuseReason]int `json:"reasons"`      // reporters per reason
+	FirstReportedAt time.Time           `json:"first_reported_at"`
+	LastReportedAt  time.Time           `json:"last_reported_at"`
+	Reports         []*AbuseReport      `json:"reports"` // newest first
+}
+
+// NewModerationQueue folds open reports into one item per media item, in the
+// order of mediaIDs. Media items without reports are left out.
+func NewModerationQueue(mediaIDs []string, reports []*AbuseReport) []ModerationQueueItem {
+	byMedia := make(map[string][]*AbuseReport, len(mediaIDs))
+	for _, report := range reports {
+		byMedia[report.MediaID] = append(byMedia[report.MediaID], report)
+	}
+
+	queue := make([]ModerationQueueItem, 0, len(mediaIDs))
+	for _, mediaID := range mediaIDs {
+		mediaReports := byMedia[mediaID]
+		if len(mediaReports) == 0 {
+			continue
+		}
+		sort.SliceStable(mediaReports, func(i, j int) bool {
+			return mediaReports[i].UpdatedAt.After(mediaReports[j].UpdatedAt)
+		})
+
+		item := ModerationQueueItem{
+			MediaID:         mediaID,
+			ReportCount:     len(mediaReports),
+			Reasons:         make(map[AbuseReason]int),
+			FirstReportedAt: mediaReports[0].CreatedAt,
+			LastReportedAt:  mediaReports[0].UpdatedAt,
+			Reports:         mediaReports,
+		}
+		for _, report := range mediaReports {
+			item.Reasons[report.Reason]++
+			if report.CreatedAt.Before(item.FirstReportedAt) {
+				item.FirstReportedAt = report.CreatedAt
+			}
+		}
+		queue = append(queue, item)
+	}
+	return queue
+}
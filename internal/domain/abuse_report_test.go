@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbuseReportRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         AbuseReportRequest
+		errorFields []string
+	}{
+		{"reason only", AbuseReportRequest{Reason: AbuseReasonSpam}, nil},
+		{"reason with details", AbuseReportRequest{Reason: AbuseReasonCopyright, Details: "This is my show"}, nil},
+		{"unknown reason", AbuseReportRequest{Reason: "boring"}, []string{"reason"}},
+		{"other without details", AbuseReportRequest{Reason: AbuseReasonOther}, []string{"details"}},
+		{"details too long", AbuseReportRequest{Reason: AbuseReasonSpam, Details: strings.Repeat("ب", MaxAbuseReportDetailsLength+1)}, []string{"details"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.errorFields, fields)
+		})
+	}
+}
+
+func TestAbuseReporter_Hash(t *testing.T) {
+	anonymous := AbuseReporter{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+
+	assert.Equal(t, AbuseReporter{UserID: "user-1", IP: "203.0.113.7"}.Hash(), AbuseReporter{UserID: "user-1", IP: "198.51.100.1"}.Hash())
+	assert.NotEqual(t, anonymous.Hash(), AbuseReporter{IP: "203.0.113.8", UserAgent: "Mozilla/5.0"}.Hash())
+	assert.NotEqual(t, anonymous.Hash(), AbuseReporter{UserID: "user-1", IP: "203.0.113.7", UserAgent: "Mozilla/5.0"}.Hash())
+}
+
+func TestNewModerationQueue(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	reports := []*AbuseReport{
+		{ID: "r1", MediaID: "m1", Reason: AbuseReasonSpam, CreatedAt: base, UpdatedAt: base},
+		{ID: "r2", MediaID: "m2", Reason: AbuseReasonViolence, CreatedAt: base, UpdatedAt: base},
+		{ID: "r3", MediaID: "m1", Reason: AbuseReasonSpam, CreatedAt: base.Add(time.Hour), UpdatedAt: base.Add(3 * time.Hour)},
+		{ID: "r4", MediaID: "m1", Reason: AbuseReasonCopyright, CreatedAt: base.Add(-time.Hour), UpdatedAt: base.Add(time.Hour)},
+	}
+
+	queue := NewModerationQueue([]string{"m1", "m2", "m3"}, reports)
+
+	require.Len(t, queue, 2)
+	assert.Equal(t, "m1", queue[0].MediaID)
+	assert.Equal(t, 3, queue[0].ReportCount)
+	assert.Equal(t, map[AbuseReason]int{AbuseReasonSpam: 2, AbuseReasonCopyright: 1}, queue[0].Reasons)
+	assert.Equal(t, base.Add(-time.Hour), queue[0].FirstReportedAt)
+	assert.Equal(t, base.Add(3*time.Hour), queue[0].LastReportedAt)
+	assert.Equal(t, "r3", queue[0].Reports[0].ID)
+	assert.Equal(t, "m2", queue[1].MediaID)
+	assert.Equal(t, 1, queue[1].ReportCount)
+}
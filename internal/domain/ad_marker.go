@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxCuePoints bounds the ad breaks of a media item
+const MaxCuePoints = 50
+
+// CuePointPosition places an ad break relative to the content
+type CuePointPosition string
+
+const (
+	CuePointPreroll  CuePointPosition = "preroll"  // before the content
+	CuePointMidroll  CuePointPosition = "midroll"  // at an offset within the content
+	CuePointPostroll CuePointPosition = "postroll" // after the content
+)
+
+// IsValid checks if the cue point position is known
+func (p CuePointPosition) IsValid() bool {
+	switch p {
+	case CuePointPreroll, CuePointMidroll, CuePointPostroll:
+		return true
+	}
+	return false
+}
+
+// order sorts pre-rolls first and post-rolls last
+func (p CuePointPosition) order() int {
+	switch p {
+	case CuePointPreroll:
+		return 0
+	case CuePointMidroll:
+		return 1
+	}
+	return 2
+}
+
+// CuePoint marks an ad break an ad server fills at playback time
+type CuePoint struct {
+	Position           CuePointPosition `json:"position"`
+	OffsetSeconds      int              `json:"offset_seconds"`                 // start of the break in the content, 0 for pre-rolls and the duration for post-rolls
+	MaxDurationSeconds int              `json:"max_duration_seconds,omitempty"` // longest break, 0 leaves it to the ad server
+}
+
+// CuePointsRequest represents a request to replace the ad breaks of a media item
+type CuePointsRequest struct {
+	CuePoints []CuePoint `json:"cue_points"` // empty removes every break
+}
+
+// Validate validates the cue points request against the media duration in seconds,
+// which is 0 when unknown
+func (r *CuePointsRequest) Validate(duration int) ValidationErrors {
+	var errs ValidationErrors
+
+	if len(r.CuePoints) > MaxCuePoints {
+		errs.Add("cue_points", fmt.Sprintf("must have at most %d entries", MaxCuePoints))
+		return errs
+	}
+
+	counts := make(map[CuePointPosition]int)
+	offsets := make(map[int]bool)
+	for i, cue := range r.CuePoints {
+		field := fmt.Sprintf("cue_points[%d]", i)
+		if !cue.Position.IsValid() {
+			errs.Add(field+".position", "must be preroll, midroll or postroll")
+			continue
+		}
+		counts[cue.Position]++
+		if cue.MaxDurationSeconds < 0 {
+			errs.Add(field+".max_duration_seconds", "must not be negative")
+		}
+		if cue.Position != CuePointMidroll {
+			continue
+		}
+
+		switch {
+		case cue.OffsetSeconds <= 0:
+			errs.Add(field+".offset_seconds", "must be positive for mid-rolls")
+		case duration > 0 && cue.OffsetSeconds >= duration:
+			errs.Add(field+".offset_seconds", "must be before the end of the media")
+		case offsets[cue.OffsetSeconds]:
+			errs.Add(field+".offset_seconds", "is already used by another mid-roll")
+		}
+		offsets[cue.OffsetSeconds] = true
+	}
+	if counts[CuePointPreroll] > 1 {
+		errs.Add("cue_points", "must have at most one preroll")
+	}
+	if counts[CuePointPostroll] > 1 {
+		errs.Add("cue_points", "must have at most one postroll")
+	}
+
+	return errs
+}
+
+// Normalize returns the cue points in playback order, with pre-rolls at 0 and
+// post-rolls at the end of the media
+func (r *CuePointsRequest) Normalize(duration int) []CuePoint {
+	cuePoints := make([]CuePoint, len(r.CuePoints))
+	for i, cue := range r.CuePoints {
+		switch cue.Position {
+		case CuePointPreroll:
+			cue.OffsetSeconds = 0
+		case CuePointPostroll:
+			cue.OffsetSeconds = duration
+		}
+		cuePoints[i] = cue
+	}
+
+	sort.SliceStable(cuePoints, func(i, j int) bool {
+		if cuePoints[i].Position != cuePoints[j].Position {
+			return cuePoints[i].Position.order() < cuePoints[j].Position.order()
+		}
+		return cuePoints[i].OffsetSeconds < cuePoints[j].OffsetSeconds
+	})
+	return cuePoints
+}
+
+// AdMarkers describes the ad breaks of a media item and the context an ad server
+// targets them with
+type AdMarkers struct {
+	MediaID         string     `json:"media_id"`
+	Type            MediaType  `json:"type"`
+	DurationSeconds int        `json:"duration_seconds"`
+	ShowID          string     `json:"show_id,omitempty"`
+	Category        string     `json:"category,omitempty"`
+	Labels          []string   `json:"labels,omitempty"`
+	AgeRating       AgeRating  `json:"age_rating,omitempty"`
+	Explicit        bool       `json:"explicit"`
+	CuePoints       []CuePoint `json:"cue_points"`
+}
+
+// NewAdMarkers builds the ad markers of media
+func NewAdMarkers(media *Media) *AdMarkers {
+	cuePoints := media.CuePoints
+	if cuePoints == nil {
+		cuePoints = []CuePoint{}
+	}
+
+	return &AdMarkers{
+		MediaID:         media.ID,
+		Type:            media.Type,
+		DurationSeconds: media.Duration,
+		ShowID:          media.ShowID,
+		Category:        media.Category,
+		Labels:          media.Labels,
+		AgeRating:       media.ContentRating.AgeRating,
+		Explicit:        media.ContentRating.IsExplicit(),
+		CuePoints:       cuePoints,
+	}
+}
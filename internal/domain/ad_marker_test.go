@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCuePointsRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cuePoints []CuePoint
+		duration  int
+		fields    []string
+	}{
+		{"empty", nil, 1800, nil},
+		{"pre, mid and post", []CuePoint{{Position: CuePointPreroll}, {Position: CuePointMidroll, OffsetSeconds: 900, MaxDurationSeconds: 60}, {Position: CuePointPostroll}}, 1800, nil},
+		{"mid-roll of unknown duration", []CuePoint{{Position: CuePointMidroll, OffsetSeconds: 900}}, 0, nil},
+		{"unknown position", []CuePoint{{Position: "interstitial"}}, 1800, []string{"cue_points[0].position"}},
+		{"mid-roll at start", []CuePoint{{Position: CuePointMidroll}}, 1800, []string{"cue_points[0].offset_seconds"}},
+		{"mid-roll past end", []CuePoint{{Position: CuePointMidroll, OffsetSeconds: 1800}}, 1800, []string{"cue_points[0].offset_seconds"}},
+		{"same mid-roll offset", []CuePoint{{Position: CuePointMidroll, OffsetSeconds: 60}, {Position: CuePointMidroll, OffsetSeconds: 60}}, 1800, []string{"cue_points[1].offset_seconds"}},
+		{"negative break", []CuePoint{{Position: CuePointPreroll, MaxDurationSeconds: -1}}, 1800, []string{"cue_points[0].max_duration_seconds"}},
+		{"two pre-rolls", []CuePoint{{Position: CuePointPreroll}, {Position: CuePointPreroll}}, 1800, []string{"cue_points"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := CuePointsRequest{CuePoints: tt.cuePoints}
+			errs := req.Validate(tt.duration)
+
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+
+	tooMany := CuePointsRequest{CuePoints: make([]CuePoint, MaxCuePoints+1)}
+	assert.True(t, tooMany.Validate(0).HasErrors())
+}
+
+func TestCuePointsRequest_Normalize(t *testing.T) {
+	req := CuePointsRequest{CuePoints: []CuePoint{
+		{Position: CuePointPostroll, OffsetSeconds: 5},
+		{Position: CuePointMidroll, OffsetSeconds: 1200},
+		{Position: CuePointPreroll, OffsetSeconds: 30},
+		{Position: CuePointMidroll, OffsetSeconds: 600},
+	}}
+
+	assert.Equal(t, []CuePoint{
+		{Position: CuePointPreroll, OffsetSeconds: 0},
+		{Position: CuePointMidroll, OffsetSeconds: 600},
+		{Position: CuePointMidroll, OffsetSeconds: 1200},
+		{Position: CuePointPostroll, OffsetSeconds: 1800},
+	}, req.Normalize(1800))
+}
+
+func TestNewAdMarkers(t *testing.T) {
+	media := &Media{
+		ID: "m1", Type: TypePodcast, Duration: 1800, ShowID: "show", Category: "news", Labels: []string{"politics"},
+		ContentRating: ContentRating{AgeRating: AgeRating18},
+	}
+
+	markers := NewAdMarkers(media)
+
+	assert.Equal(t, "m1", markers.MediaID)
+	assert.Equal(t, 1800, markers.DurationSeconds)
+	assert.Equal(t, []string{"politics"}, markers.Labels)
+	assert.True(t, markers.Explicit)
+	assert.NotNil(t, markers.CuePoints)
+}
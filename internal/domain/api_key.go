@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefixLength is the number of leading characters of a key kept to tell keys apart
+const APIKeyPrefixLength = 8
+
+// APIKey is a managed API key. Only the SHA-256 hash of the key is stored, the
+// key itself is returned once when it is created or rotated.
+type APIKey struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	Name      string     `json:"name" gorm:"not null"`
+	TenantID  string     `json:"tenant_id" gorm:"type:varchar(100);not null;index"`
+	Role      Role       `json:"role" gorm:"type:varchar(20);not null;default:'viewer'"`
+	Prefix    string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	KeyHash   string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive returns true if the key has not been revoked
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil
+}
+
+// HashAPIKey returns the stored representation of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyPrefix returns the leading characters of a key shown to tell keys apart
+func APIKeyPrefix(key string) string {
+	if len(key) <= APIKeyPrefixLength {
+		return key
+	}
+	return key[:APIKeyPrefixLength]
+}
+
+// APIKeyRequest represents a request to create an API key
+type APIKeyRequest struct {
+	Name     string `json:"name" binding:"required"`
+	TenantID string `json:"tenant_id"` // defaults to the default tenant
+	Role     Role   `json:"role"`      // defaults to viewer
+}
+
+// Normalize trims the request and applies defaults
+func (r *APIKeyRequest) Normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.TenantID = strings.TrimSpace(r.TenantID)
+	if r.TenantID == "" {
+		r.TenantID = DefaultTenantID
+	}
+	if r.Role == "" {
+		r.Role = RoleViewer
+	}
+}
+
+// Validate validates the API key request
+func (r *APIKeyRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if r.Name == "" {
+		errs.Add("name", "is required")
+	}
+	if len(r.Name) > 100 {
+		errs.Add("name", "must be at most 100 characters")
+	}
+	if len(r.TenantID) > 100 {
+		errs.Add("tenant_id", "must be at most 100 characters")
+	}
+	if !r.Role.IsValid() {
+		errs.Add("role", "must be one of: viewer, editor, admin")
+	}
+
+	return errs
+}
+
+// CreatedAPIKey is returned once on creation and rotation and is the only place the key appears
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  APIKeyRequest
+		hasError bool
+	}{
+		{
+			name:     "valid request",
+			request:  APIKeyRequest{Name: "ingest"},
+			hasError: false,
+		},
+		{
+			name:     "blank name",
+			request:  APIKeyRequest{Name: "   "},
+			hasError: true,
+		},
+		{
+			name:     "unknown role",
+			request:  APIKeyRequest{Name: "ingest", Role: "owner"},
+			hasError: true,
+		},
+		{
+			name:     "name too long",
+			request:  APIKeyRequest{Name: strings.Repeat("a", 101)},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.Normalize()
+			assert.Equal(t, tt.hasError, tt.request.Validate().HasErrors())
+			assert.Equal(t, DefaultTenantID, tt.request.TenantID)
+		})
+	}
+}
+
+func TestAPIKeyPrefix(t *testing.T) {
+	assert.Equal(t, "abcdefgh", APIKeyPrefix("abcdefghijkl"))
+	assert.Equal(t, "abc", APIKeyPrefix("abc"))
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+)
+
+// AudioFormat represents a derived audio format served on demand
+type AudioFormat string
+
+const (
+	AudioFormatAAC  AudioFormat = "aac"
+	AudioFormatOpus AudioFormat = "opus"
+)
+
+// IsValid checks if the audio format is supported
+func (f AudioFormat) IsValid() bool {
+	switch f {
+	case AudioFormatAAC, AudioFormatOpus:
+		return true
+	default:
+		return false
+	}
+}
+
+// Extension returns the file extension of the format's container
+func (f AudioFormat) Extension() string {
+	switch f {
+	case AudioFormatOpus:
+		return ".ogg"
+	default:
+		return ".aac"
+	}
+}
+
+// ContentType returns the MIME type the format is served with
+func (f AudioFormat) ContentType() string {
+	switch f {
+	case AudioFormatOpus:
+		return "audio/ogg"
+	default:
+		return "audio/aac"
+	}
+}
+
+// DerivedAudioKey returns the storage key of the derived audio asset of a media item
+func DerivedAudioKey(mediaID string, format AudioFormat) string {
+	return fmt.Sprintf("%s%s/audio-%s%s", DerivedKeyPrefix, mediaID, format, format.Extension())
+}
+
+// AudioAsset is a derived audio file ready to be streamed to the client.
+// The caller must close Body.
+type AudioAsset struct {
+	Format      AudioFormat
+	ContentType string
+	Size        int64
+	Cached      bool // served from a previously derived asset
+	Body        io.ReadCloser
+}
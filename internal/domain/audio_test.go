@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioFormat_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   AudioFormat
+		expected bool
+	}{
+		{name: "aac", format: AudioFormatAAC, expected: true},
+		{name: "opus", format: AudioFormatOpus, expected: true},
+		{name: "mp3 is not derived", format: AudioFormat("mp3"), expected: false},
+		{name: "empty", format: AudioFormat(""), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := tt.format.IsValid()
+
+			// Then
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestDerivedAudioKey(t *testing.T) {
+	// When / Then
+	assert.Equal(t, "derived/media-123/audio-aac.aac", DerivedAudioKey("media-123", AudioFormatAAC))
+	assert.Equal(t, "derived/media-123/audio-opus.ogg", DerivedAudioKey("media-123", AudioFormatOpus))
+	assert.Equal(t, "audio/ogg", AudioFormatOpus.ContentType())
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxAudioTracks bounds the dubbed audio tracks of a media item
+const MaxAudioTracks = 10
+
+// MaxAudioTrackLabelLength bounds the label of an audio track
+const MaxAudioTrackLabelLength = 100
+
+// audioLanguage matches BCP 47 language tags like "ar", "en" or "es-419", lower-cased
+var audioLanguage = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeAudioLanguage trims and lower-cases a language tag
+func NormalizeAudioLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
+
+// IsValidAudioLanguage checks that a normalized language tag is well formed
+func IsValidAudioLanguage(language string) bool {
+	return len(language) <= MaxTranscriptLanguageLength && audioLanguage.MatchString(language)
+}
+
+// AudioTrack is a dubbed audio track of a media item, stored as a separate
+// asset next to the original upload
+type AudioTrack struct {
+	Language  string    `json:"language"`
+	Label     string    `json:"label,omitempty"` // shown in the player, like "العربية"
+	Format    string    `json:"format"`
+	Key       string    `json:"key"` // storage key of the track
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AudioTrackKey returns the storage key of the dubbed audio track of a media item
+func AudioTrackKey(mediaID, language, format string) string {
+	return fmt.Sprintf("%s%s/audio-tracks/%s.%s", DerivedKeyPrefix, mediaID, language, format)
+}
+
+// AudioTrack returns the dubbed track of a language, or nil
+func (m *Media) AudioTrack(language string) *AudioTrack {
+	for i := range m.AudioTracks {
+		if m.AudioTracks[i].Language == language {
+			return &m.AudioTracks[i]
+		}
+	}
+	return nil
+}
+
+// AudioLanguages returns the languages the media can be listened to in, the
+// language of the original audio first
+func (m *Media) AudioLanguages() []string {
+	var languages []string
+	seen := make(map[string]bool)
+	add := func(language string) {
+		if language != "" && !seen[language] {
+			seen[language] = true
+			languages = append(languages, language)
+		}
+	}
+	add(m.AudioLanguage)
+	for _, track := range m.AudioTracks {
+		add(track.Language)
+	}
+	return languages
+}
+
+// AudioTrackUpload is the file of a dubbed audio track sent in a request body
+type AudioTrackUpload struct {
+	Language string
+	Label    string
+	Format   string // file format, one of the podcast formats
+	Body     io.Reader
+	Size     int64 // declared content length, -1 when unknown
+}
+
+// Normalize trims the upload fields and lower-cases the language and format
+func (u *AudioTrackUpload) Normalize() {
+	u.Language = NormalizeAudioLanguage(u.Language)
+	u.Label = strings.TrimSpace(u.Label)
+	u.Format = strings.ToLower(strings.TrimSpace(u.Format))
+}
+
+// Validate validates the audio track upload
+func (u *AudioTrackUpload) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if !IsValidAudioLanguage(u.Language) {
+		errs.Add("language", "must be a language tag like ar or en-US")
+	}
+	if utf8.RuneCountInString(u.Label) > MaxAudioTrackLabelLength {
+		errs.Add("label", fmt.Sprintf("must be at most %d characters", MaxAudioTrackLabelLength))
+	}
+	if !IsValidAudioFormat(u.Format) {
+		errs.Add("format", "must be one of "+strings.Join(AudioFormats, ", "))
+	}
+	if u.Size > MaxPodcastFileSize {
+		errs.Add("size", fmt.Sprintf("must be at most %d bytes", MaxPodcastFileSize))
+	}
+
+	return errs
+}
+
+// AudioTrackAsset is a dubbed audio track ready to be streamed to the client.
+// The caller must close Body.
+type AudioTrackAsset struct {
+	Language    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
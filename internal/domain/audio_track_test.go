@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedia_AudioLanguages(t *testing.T) {
+	media := &Media{
+		AudioLanguage: "ar",
+		AudioTracks:   []AudioTrack{{Language: "en"}, {Language: "ar"}, {Language: "es-419"}},
+	}
+	assert.Equal(t, []string{"ar", "en", "es-419"}, media.AudioLanguages())
+	assert.Equal(t, "es-419", media.AudioTrack("es-419").Language)
+	assert.Nil(t, media.AudioTrack("fr"))
+
+	assert.Empty(t, (&Media{}).AudioLanguages())
+}
+
+func TestAudioTrackUpload_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		upload   AudioTrackUpload
+		hasError bool
+	}{
+		{name: "valid", upload: AudioTrackUpload{Language: " EN-us ", Format: "MP3"}},
+		{name: "regional language", upload: AudioTrackUpload{Language: "es-419", Format: "ogg"}},
+		{name: "language name", upload: AudioTrackUpload{Language: "english", Format: "mp3"}, hasError: true},
+		{name: "missing language", upload: AudioTrackUpload{Format: "mp3"}, hasError: true},
+		{name: "video format", upload: AudioTrackUpload{Language: "en", Format: "mp4"}, hasError: true},
+		{name: "too large", upload: AudioTrackUpload{Language: "en", Format: "mp3", Size: MaxPodcastFileSize + 1}, hasError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.upload.Normalize()
+			assert.Equal(t, tt.hasError, tt.upload.Validate().HasErrors())
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Audited actions
+const (
+	AuditActionSearchReindex = "search.reindex"
+)
+
+// Audit outcomes
+const (
+	AuditOutcomeStarted   = "started"
+	AuditOutcomeSucceeded = "succeeded"
+	AuditOutcomeFailed    = "failed"
+	AuditOutcomeRejected  = "rejected"
+)
+
+// AuditActor identifies who invoked an audited action
+type AuditActor struct {
+	Name     string `json:"name"` // admin or anonymous, the admin API key carries no identity
+	ClientIP string `json:"client_ip"`
+}
+
+// AuditEntry records an invocation of a sensitive operation
+type AuditEntry struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Action    string    `json:"action" gorm:"type:varchar(50);not null;index:idx_audit_action_created,priority:1"`
+	Outcome   string    `json:"outcome" gorm:"type:varchar(20);not null"`
+	Actor     string    `json:"actor" gorm:"type:varchar(50);not null"`
+	ClientIP  string    `json:"client_ip" gorm:"type:varchar(45)"`
+	RequestID string    `json:"request_id,omitempty" gorm:"type:varchar(64)"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;index:idx_audit_action_created,priority:2"`
+}
+
+// TableName specifies the table name for AuditEntry
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bulk operation limits
+const (
+	MaxBulkSelection   = 10000 // media a single bulk operation can change
+	BulkSyncLimit      = 100   // larger selections run asynchronously as a job
+	MaxBulkJobFailures = 100   // failures kept in a job report
+)
+
+// BulkJobStatus represents the state of a bulk job
+type BulkJobStatus string
+
+const (
+	BulkJobRunning   BulkJobStatus = "running"
+	BulkJobCompleted BulkJobStatus = "completed"
+)
+
+// Bulk job operations
+const (
+	BulkOperationLabels = "labels"
+)
+
+// BulkJobFailure reports a media item a bulk job could not change
+type BulkJobFailure struct {
+	MediaID string `json:"media_id"`
+	Error   string `json:"error"`
+}
+
+// BulkJob reports the progress and outcome of a bulk operation
+type BulkJob struct {
+	ID          string           `json:"id" gorm:"primaryKey"`
+	Operation   string           `json:"operation" gorm:"type:varchar(50);not null"`
+	Status      BulkJobStatus    `json:"status" gorm:"type:varchar(20);not null;index"`
+	Total       int              `json:"total"`
+	Processed   int              `json:"processed"`
+	Succeeded   int              `json:"succeeded"`
+	Failed      int              `json:"failed"`
+	Failures    []BulkJobFailure `json:"failures,omitempty" gorm:"serializer:json;type:jsonb"` // the first MaxBulkJobFailures
+	CreatedAt   time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// TableName specifies the table name for BulkJob
+func (BulkJob) TableName() string {
+	return "bulk_jobs"
+}
+
+// RecordSuccess counts a changed or already up to date media item
+func (j *BulkJob) RecordSuccess() {
+	j.Processed++
+	j.Succeeded++
+}
+
+// RecordFailure counts a media item that could not be changed
+func (j *BulkJob) RecordFailure(mediaID string, err error) {
+	j.Processed++
+	j.Failed++
+	if len(j.Failures) < MaxBulkJobFailures {
+		j.Failures = append(j.Failures, BulkJobFailure{MediaID: mediaID, Error: err.Error()})
+	}
+}
+
+// Complete marks the job as completed at now
+func (j *BulkJob) Complete(now time.Time) {
+	j.Status = BulkJobCompleted
+	j.CompletedAt = &now
+}
+
+// BulkMediaQuery selects media by their properties
+type BulkMediaQuery struct {
+	Status     MediaStatus     `json:"status,omitempty"`
+	Visibility MediaVisibility `json:"visibility,omitempty"`
+	ShowID     string          `json:"show_id,omitempty"`
+	Category   string          `json:"category,omitempty"`
+}
+
+// Filter returns the media filter of the query
+func (q BulkMediaQuery) Filter() *MediaFilter {
+	return &MediaFilter{
+		Status:     q.Status,
+		Visibility: q.Visibility,
+		ShowID:     q.ShowID,
+		Category:   q.Category,
+	}
+}
+
+// BulkLabelRequest represents a request to label or categorize many media at once,
+// selected either by ID or by query
+type BulkLabelRequest struct {
+	IDs   []string        `json:"ids,omitempty"`
+	Query *BulkMediaQuery `json:"query,omitempty"`
+
+	AddLabels    []string `json:"add_labels,omitempty"`
+	RemoveLabels []string `json:"remove_labels,omitempty"`
+	Category     *string  `json:"category,omitempty"` // an empty category clears it
+}
+
+// Validate validates the bulk label request
+func (r *BulkLabelRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	switch {
+	case len(r.IDs) > 0 && r.Query != nil:
+		errs.Add("ids", "must not be combined with query")
+	case len(r.IDs) == 0 && r.Query == nil:
+		errs.Add("ids", "ids or query is required")
+	case len(r.IDs) > MaxBulkSelection:
+		errs.Add("ids", fmt.Sprintf("must have at most %d ids", MaxBulkSelection))
+	case r.Query != nil && *r.Query == (BulkMediaQuery{}):
+		errs.Add("query", "must have at least one condition")
+	}
+	if r.Query != nil && r.Query.Status != "" {
+		if _, ok := ParseMediaStatus(string(r.Query.Status)); !ok {
+			errs.Add("query.status", "is not a media status")
+		}
+	}
+	if r.Query != nil && r.Query.Visibility != "" && !r.Query.Visibility.IsValid() {
+		errs.Add("query.visibility", "must be public, unlisted or private")
+	}
+
+	if len(r.AddLabels) == 0 && len(r.RemoveLabels) == 0 && r.Category == nil {
+		errs.Add("add_labels", "add_labels, remove_labels or category is required")
+	}
+	errs = append(errs, ValidateLabels("add_labels", NormalizeLabels(r.AddLabels))...)
+	if r.Category != nil {
+		errs = append(errs, ValidateCategory("category", strings.TrimSpace(*r.Category))...)
+	}
+
+	return errs
+}
+
+// ApplyTo changes the labels and category of media, reporting whether anything changed.
+// Labels are removed after being added, and media keeps at most MaxLabels labels.
+func (r *BulkLabelRequest) ApplyTo(media *Media) (bool, error) {
+	labels := NormalizeLabels(append(append([]string{}, media.Labels...), r.AddLabels...))
+	removed := make(map[string]bool)
+	for _, label := range NormalizeLabels(r.RemoveLabels) {
+		removed[label] = true
+	}
+	kept := labels[:0]
+	for _, label := range labels {
+		if !removed[label] {
+			kept = append(kept, label)
+		}
+	}
+	if len(kept) > MaxLabels {
+		return false, fmt.Errorf("media would have more than %d labels", MaxLabels)
+	}
+
+	category := media.Category
+	if r.Category != nil {
+		category = strings.TrimSpace(*r.Category)
+	}
+
+	changed := category != media.Category || !equalLabels(kept, media.Labels)
+	media.Labels = kept
+	media.Category = category
+	return changed, nil
+}
+
+// equalLabels reports whether two label lists hold the same labels in the same order
+func equalLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
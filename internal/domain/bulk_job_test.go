@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkLabelRequest_Validate(t *testing.T) {
+	category := "News"
+
+	tests := []struct {
+		name    string
+		request BulkLabelRequest
+		fields  []string
+	}{
+		{"by ids", BulkLabelRequest{IDs: []string{"m1"}, AddLabels: []string{"tech"}}, nil},
+		{"by query", BulkLabelRequest{Query: &BulkMediaQuery{ShowID: "show-1"}, Category: &category}, nil},
+		{"no selection", BulkLabelRequest{AddLabels: []string{"tech"}}, []string{"ids"}},
+		{"ids and query", BulkLabelRequest{IDs: []string{"m1"}, Query: &BulkMediaQuery{ShowID: "show-1"}, AddLabels: []string{"tech"}}, []string{"ids"}},
+		{"empty query", BulkLabelRequest{Query: &BulkMediaQuery{}, AddLabels: []string{"tech"}}, []string{"query"}},
+		{"unknown status", BulkLabelRequest{Query: &BulkMediaQuery{Status: "archived"}, AddLabels: []string{"tech"}}, []string{"query.status"}},
+		{"no operation", BulkLabelRequest{IDs: []string{"m1"}}, []string{"add_labels"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, err := range tt.request.Validate() {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestBulkLabelRequest_ApplyTo(t *testing.T) {
+	// Given
+	empty := ""
+	request := &BulkLabelRequest{AddLabels: []string{"Tech", "news"}, RemoveLabels: []string{"old"}, Category: &empty}
+	media := &Media{Labels: []string{"old", "tech"}, Category: "Technology"}
+
+	// When
+	changed, err := request.ApplyTo(media)
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"tech", "news"}, media.Labels)
+	assert.Empty(t, media.Category)
+
+	// And applying it again changes nothing
+	changed, err = request.ApplyTo(media)
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestBulkJob_RecordFailure_KeepsFirstFailures(t *testing.T) {
+	job := &BulkJob{}
+	for i := 0; i < MaxBulkJobFailures+5; i++ {
+		job.RecordFailure("m", ErrMediaNotFound)
+	}
+	job.RecordSuccess()
+
+	assert.Equal(t, MaxBulkJobFailures+6, job.Processed)
+	assert.Equal(t, MaxBulkJobFailures+5, job.Failed)
+	assert.Equal(t, 1, job.Succeeded)
+	assert.Len(t, job.Failures, MaxBulkJobFailures)
+}
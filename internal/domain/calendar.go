@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Calendar range limits
+const (
+	CalendarDateLayout       = "2006-01-02"
+	DefaultCalendarRangeDays = 30 // days shown when no end date is given
+	MaxCalendarRangeDays     = 92
+)
+
+// CalendarEntryKind identifies the dated event a calendar entry stands for
+type CalendarEntryKind string
+
+const (
+	CalendarLicenseStart  CalendarEntryKind = "license_start"  // the rights window opens
+	CalendarLicenseExpiry CalendarEntryKind = "license_expiry" // the rights window closes, the media is unpublished
+	CalendarUnpublish     CalendarEntryKind = "unpublish"      // the publishing window closes
+	CalendarPremiere      CalendarEntryKind = "premiere"       // playback of the media opens
+)
+
+// CalendarEntry is a dated event of a media item
+type CalendarEntry struct {
+	Kind       CalendarEntryKind `json:"kind"`
+	At         time.Time         `json:"at"`
+	MediaID    string            `json:"media_id"`
+	Title      string            `json:"title"`
+	Type       MediaType         `json:"type"`
+	Status     MediaStatus       `json:"status"`
+	Visibility MediaVisibility   `json:"visibility"`
+	ShowID     string            `json:"show_id,omitempty"`
+}
+
+// CalendarDay holds the entries of one UTC day, in time order
+type CalendarDay struct {
+	Date    string          `json:"date"`
+	Entries []CalendarEntry `json:"entries"`
+}
+
+// Calendar lists the days of a date range that have entries
+type Calendar struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Total int           `json:"total"`
+	Days  []CalendarDay `json:"days"`
+}
+
+// ParseCalendarRange reads the inclusive from and to dates of a calendar request.
+// From defaults to today and to to the following DefaultCalendarRangeDays days.
+// The returned end is exclusive, at the start of the day after to.
+func ParseCalendarRange(fromValue, toValue string, now time.Time) (time.Time, time.Time, ValidationErrors) {
+	var errs ValidationErrors
+
+	from := now.UTC().Truncate(24 * time.Hour)
+	if fromValue != "" {
+		parsed, err := time.Parse(CalendarDateLayout, fromValue)
+		if err != nil {
+			errs.Add("from", "must be a date like 2025-06-01")
+		}
+		from = parsed
+	}
+
+	end := from.AddDate(0, 0, DefaultCalendarRangeDays)
+	if toValue != "" {
+		parsed, err := time.Parse(CalendarDateLayout, toValue)
+		if err != nil {
+			errs.Add("to", "must be a date like 2025-06-30")
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+
+	if errs.HasErrors() {
+		return time.Time{}, time.Time{}, errs
+	}
+	if !end.After(from) {
+		errs.Add("to", "must not be before from")
+	} else if end.Sub(from) > MaxCalendarRangeDays*24*time.Hour {
+		errs.Add("to", fmt.Sprintf("must be at most %d days after from", MaxCalendarRangeDays))
+	}
+
+	return from, end, errs
+}
+
+// CalendarEntriesFor returns the events of media within [from, end)
+func CalendarEntriesFor(media *Media, from, end time.Time) []CalendarEntry {
+	var entries []CalendarEntry
+
+	add := func(kind CalendarEntryKind, at *time.Time) {
+		if at == nil || at.Before(from) || !at.Before(end) {
+			return
+		}
+		entries = append(entries, CalendarEntry{
+			Kind:       kind,
+			At:         at.UTC(),
+			MediaID:    media.ID,
+			Title:      media.Title,
+			Type:       media.Type,
+			Status:     media.Status,
+			Visibility: media.Visibility,
+			ShowID:     media.ShowID,
+		})
+	}
+	add(CalendarLicenseStart, media.License.StartsAt)
+	add(CalendarLicenseExpiry, media.License.EndsAt)
+	add(CalendarUnpublish, media.UnpublishAt)
+	add(CalendarPremiere, media.Premiere.StartsAt)
+
+	return entries
+}
+
+// NewCalendar buckets entries by UTC day, for the inclusive dates from and end minus a day
+func NewCalendar(from, end time.Time, entries []CalendarEntry) *Calendar {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+
+	calendar := &Calendar{
+		From:  from.Format(CalendarDateLayout),
+		To:    end.AddDate(0, 0, -1).Format(CalendarDateLayout),
+		Total: len(entries),
+		Days:  []CalendarDay{},
+	}
+	for _, entry := range entries {
+		date := entry.At.Format(CalendarDateLayout)
+		if last := len(calendar.Days) - 1; last >= 0 && calendar.Days[last].Date == date {
+			calendar.Days[last].Entries = append(calendar.Days[last].Entries, entry)
+			continue
+		}
+		calendar.Days = append(calendar.Days, CalendarDay{Date: date, Entries: []CalendarEntry{entry}})
+	}
+
+	return calendar
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCalendarRange(t *testing.T) {
+	now := time.Date(2025, 6, 1, 15, 30, 0, 0, time.UTC)
+	day := func(value string) time.Time {
+		parsed, err := time.Parse(CalendarDateLayout, value)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		start  time.Time
+		end    time.Time
+		fields []string
+	}{
+		{"defaults to the next 30 days", "", "", day("2025-06-01"), day("2025-07-01"), nil},
+		{"includes the last day", "2025-06-10", "2025-06-20", day("2025-06-10"), day("2025-06-21"), nil},
+		{"single day", "2025-06-10", "2025-06-10", day("2025-06-10"), day("2025-06-11"), nil},
+		{"invalid dates", "June", "2025-13-01", time.Time{}, time.Time{}, []string{"from", "to"}},
+		{"to before from", "2025-06-10", "2025-06-09", day("2025-06-10"), day("2025-06-10"), []string{"to"}},
+		{"range too long", "2025-01-01", "2025-06-01", day("2025-01-01"), day("2025-06-02"), []string{"to"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, errs := ParseCalendarRange(tt.from, tt.to, now)
+
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+}
+
+func TestNewCalendar_BucketsEntriesByDay(t *testing.T) {
+	// Given media with events inside and outside of the range
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := from.AddDate(0, 0, 7)
+	startsAt := from.Add(30 * time.Hour)
+	endsAt := from.Add(10 * time.Hour)
+	unpublishAt := from.Add(26 * time.Hour)
+	outside := end.Add(time.Hour)
+
+	media := []*Media{
+		{ID: "m1", Title: "Episode 1", License: License{StartsAt: &startsAt, EndsAt: &outside}},
+		{ID: "m2", Title: "Episode 2", License: License{EndsAt: &endsAt}, UnpublishAt: &unpublishAt},
+	}
+
+	// When
+	var entries []CalendarEntry
+	for _, m := range media {
+		entries = append(entries, CalendarEntriesFor(m, from, end)...)
+	}
+	calendar := NewCalendar(from, end, entries)
+
+	// Then entries are grouped by day in time order
+	assert.Equal(t, "2025-06-01", calendar.From)
+	assert.Equal(t, "2025-06-07", calendar.To)
+	assert.Equal(t, 3, calendar.Total)
+	require.Len(t, calendar.Days, 2)
+	assert.Equal(t, "2025-06-01", calendar.Days[0].Date)
+	assert.Equal(t, CalendarLicenseExpiry, calendar.Days[0].Entries[0].Kind)
+	assert.Equal(t, "2025-06-02", calendar.Days[1].Date)
+	require.Len(t, calendar.Days[1].Entries, 2)
+	assert.Equal(t, CalendarUnpublish, calendar.Days[1].Entries[0].Kind)
+	assert.Equal(t, "m2", calendar.Days[1].Entries[0].MediaID)
+	assert.Equal(t, CalendarLicenseStart, calendar.Days[1].Entries[1].Kind)
+	assert.Equal(t, "m1", calendar.Days[1].Entries[1].MediaID)
+}
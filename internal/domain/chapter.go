@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MaxChapters bounds the chapters of a media item
+const MaxChapters = 100
+
+// MaxChapterTitleLength bounds the title of a chapter
+const MaxChapterTitleLength = 200
+
+// DefaultMinChapterSeconds is the shortest chapter a boundary is suggested for
+const DefaultMinChapterSeconds = 60
+
+// StingerMaxSeconds is the longest sound between two silences taken for a music
+// stinger separating segments rather than content
+const StingerMaxSeconds = 20
+
+// Chapter is a titled section of a media item
+type Chapter struct {
+	Title     string `json:"title"`
+	StartTime int    `json:"start_time"` // in seconds
+}
+
+// ChapterBoundaryKind tells what a suggested chapter boundary was detected from
+type ChapterBoundaryKind string
+
+const (
+	ChapterBoundarySilence ChapterBoundaryKind = "silence" // a long silence
+	ChapterBoundaryStinger ChapterBoundaryKind = "stinger" // a short sound between two silences, such as a jingle
+)
+
+// ChapterSuggestion is a chapter boundary detected during processing that editors may accept
+type ChapterSuggestion struct {
+	StartTime int                 `json:"start_time"` // in seconds
+	Kind      ChapterBoundaryKind `json:"kind"`
+}
+
+// SilenceInterval is a stretch of silence in the audio of a media item, in seconds
+type SilenceInterval struct {
+	Start float64
+	End   float64
+}
+
+// SuggestChapters suggests a chapter boundary where the audio resumes after each
+// silence, or after the trailing silence of a stinger. Boundaries closer than
+// minChapterSeconds to the previous boundary or to either end of the media are dropped.
+func SuggestChapters(silences []SilenceInterval, duration, minChapterSeconds int) []ChapterSuggestion {
+	suggestions := []ChapterSuggestion{}
+	previous := 0
+	for i := 0; i < len(silences); i++ {
+		kind := ChapterBoundarySilence
+		end := silences[i].End
+		if i+1 < len(silences) && silences[i+1].Start-end <= StingerMaxSeconds {
+			kind = ChapterBoundaryStinger
+			end = silences[i+1].End
+			i++
+		}
+
+		start := int(math.Round(end))
+		if start-previous < minChapterSeconds || (duration > 0 && duration-start < minChapterSeconds) {
+			continue
+		}
+		suggestions = append(suggestions, ChapterSuggestion{StartTime: start, Kind: kind})
+		previous = start
+	}
+	return suggestions
+}
+
+// ChaptersRequest represents a request to replace the chapters of a media item
+type ChaptersRequest struct {
+	Chapters []Chapter `json:"chapters"` // empty removes every chapter
+}
+
+// Normalize trims the chapter titles and sorts the chapters by start time
+func (r *ChaptersRequest) Normalize() {
+	for i := range r.Chapters {
+		r.Chapters[i].Title = strings.TrimSpace(r.Chapters[i].Title)
+	}
+	sortChapters(r.Chapters)
+}
+
+// Validate validates the chapters request against the media duration in seconds,
+// which is 0 when unknown
+func (r *ChaptersRequest) Validate(duration int) ValidationErrors {
+	var errs ValidationErrors
+
+	if len(r.Chapters) > MaxChapters {
+		errs.Add("chapters", fmt.Sprintf("must have at most %d entries", MaxChapters))
+		return errs
+	}
+
+	starts := make(map[int]bool)
+	for i, chapter := range r.Chapters {
+		field := fmt.Sprintf("chapters[%d]", i)
+		if chapter.Title == "" {
+			errs.Add(field+".title", "is required")
+		}
+		if len(chapter.Title) > MaxChapterTitleLength {
+			errs.Add(field+".title", fmt.Sprintf("must be at most %d characters", MaxChapterTitleLength))
+		}
+
+		switch {
+		case chapter.StartTime < 0:
+			errs.Add(field+".start_time", "must not be negative")
+		case duration > 0 && chapter.StartTime >= duration:
+			errs.Add(field+".start_time", "must be before the end of the media")
+		case starts[chapter.StartTime]:
+			errs.Add(field+".start_time", "is already used by another chapter")
+		}
+		starts[chapter.StartTime] = true
+	}
+
+	return errs
+}
+
+// AcceptChaptersRequest represents a request to turn chapter suggestions into chapters
+type AcceptChaptersRequest struct {
+	// Chapters name the suggested boundaries to accept by start time. Chapters without
+	// a title are named after their start time.
+	Chapters []Chapter `json:"chapters" binding:"required"`
+}
+
+// Apply accepts the requested suggestions of media into its chapters, replacing
+// chapters at the same start time, and drops them from the suggestions
+func (r *AcceptChaptersRequest) Apply(media *Media) ValidationErrors {
+	var errs ValidationErrors
+
+	suggested := make(map[int]bool, len(media.ChapterSuggestions))
+	for _, suggestion := range media.ChapterSuggestions {
+		suggested[suggestion.StartTime] = true
+	}
+	accepted := make(map[int]Chapter, len(r.Chapters))
+	for i, chapter := range r.Chapters {
+		field := fmt.Sprintf("chapters[%d]", i)
+		chapter.Title = strings.TrimSpace(chapter.Title)
+		if chapter.Title == "" {
+			chapter.Title = "Chapter at " + FormatChapterTime(chapter.StartTime)
+		}
+
+		switch {
+		case !suggested[chapter.StartTime]:
+			errs.Add(field+".start_time", "is not a suggested chapter boundary")
+		case len(chapter.Title) > MaxChapterTitleLength:
+			errs.Add(field+".title", fmt.Sprintf("must be at most %d characters", MaxChapterTitleLength))
+		}
+		accepted[chapter.StartTime] = chapter
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+
+	chapters := make([]Chapter, 0, len(media.Chapters)+len(accepted))
+	for _, chapter := range media.Chapters {
+		if _, replaced := accepted[chapter.StartTime]; !replaced {
+			chapters = append(chapters, chapter)
+		}
+	}
+	for _, chapter := range accepted {
+		chapters = append(chapters, chapter)
+	}
+	if len(chapters) > MaxChapters {
+		errs.Add("chapters", fmt.Sprintf("media may have at most %d chapters", MaxChapters))
+		return errs
+	}
+	sortChapters(chapters)
+
+	suggestions := make([]ChapterSuggestion, 0, len(media.ChapterSuggestions))
+	for _, suggestion := range media.ChapterSuggestions {
+		if _, ok := accepted[suggestion.StartTime]; !ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	media.Chapters = chapters
+	media.ChapterSuggestions = suggestions
+	return nil
+}
+
+// MediaChapters lists the chapters of a media item and the boundaries suggested for it
+type MediaChapters struct {
+	MediaID     string              `json:"media_id"`
+	Duration    int                 `json:"duration"` // in seconds
+	Chapters    []Chapter           `json:"chapters"`
+	Suggestions []ChapterSuggestion `json:"suggestions"`
+}
+
+// NewMediaChapters builds the chapters of media
+func NewMediaChapters(media *Media) *MediaChapters {
+	chapters := media.Chapters
+	if chapters == nil {
+		chapters = []Chapter{}
+	}
+	suggestions := media.ChapterSuggestions
+	if suggestions == nil {
+		suggestions = []ChapterSuggestion{}
+	}
+
+	return &MediaChapters{
+		MediaID:     media.ID,
+		Duration:    media.Duration,
+		Chapters:    chapters,
+		Suggestions: suggestions,
+	}
+}
+
+// FormatChapterTime formats seconds as M:SS, or H:MM:SS from an hour
+func FormatChapterTime(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// sortChapters sorts chapters by start time
+func sortChapters(chapters []Chapter) {
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].StartTime < chapters[j].StartTime
+	})
+}
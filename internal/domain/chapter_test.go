@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestChapters(t *testing.T) {
+	tests := []struct {
+		name     string
+		silences []SilenceInterval
+		duration int
+		expected []ChapterSuggestion
+	}{
+		{
+			name:     "starts chapters where the audio resumes",
+			silences: []SilenceInterval{{Start: 598, End: 602.4}, {Start: 1200, End: 1203.6}},
+			duration: 1800,
+			expected: []ChapterSuggestion{
+				{StartTime: 602, Kind: ChapterBoundarySilence},
+				{StartTime: 1204, Kind: ChapterBoundarySilence},
+			},
+		},
+		{
+			name:     "folds a jingle between silences into a stinger",
+			silences: []SilenceInterval{{Start: 600, End: 602}, {Start: 612, End: 614}},
+			duration: 1800,
+			expected: []ChapterSuggestion{{StartTime: 614, Kind: ChapterBoundaryStinger}},
+		},
+		{
+			name:     "drops boundaries too close to each other or the ends",
+			silences: []SilenceInterval{{Start: 0, End: 3}, {Start: 300, End: 303}, {Start: 330, End: 333}, {Start: 1770, End: 1775}},
+			duration: 1800,
+			expected: []ChapterSuggestion{{StartTime: 303, Kind: ChapterBoundarySilence}},
+		},
+		{
+			name:     "no silences",
+			duration: 1800,
+			expected: []ChapterSuggestion{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SuggestChapters(tt.silences, tt.duration, DefaultMinChapterSeconds))
+		})
+	}
+}
+
+func TestChaptersRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		chapters []Chapter
+		hasError bool
+	}{
+		{
+			name:     "valid chapters",
+			chapters: []Chapter{{Title: "Intro", StartTime: 0}, {Title: "Interview", StartTime: 600}},
+		},
+		{
+			name:     "no chapters",
+			chapters: []Chapter{},
+		},
+		{
+			name:     "blank title",
+			chapters: []Chapter{{Title: "  ", StartTime: 0}},
+			hasError: true,
+		},
+		{
+			name:     "title too long",
+			chapters: []Chapter{{Title: strings.Repeat("a", MaxChapterTitleLength+1), StartTime: 0}},
+			hasError: true,
+		},
+		{
+			name:     "past the end",
+			chapters: []Chapter{{Title: "Outro", StartTime: 1800}},
+			hasError: true,
+		},
+		{
+			name:     "same start time",
+			chapters: []Chapter{{Title: "One", StartTime: 60}, {Title: "Two", StartTime: 60}},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := ChaptersRequest{Chapters: tt.chapters}
+			request.Normalize()
+			assert.Equal(t, tt.hasError, request.Validate(1800).HasErrors())
+		})
+	}
+}
+
+func TestAcceptChaptersRequest_Apply(t *testing.T) {
+	media := &Media{
+		Chapters: []Chapter{{Title: "Intro", StartTime: 0}, {Title: "Old segment", StartTime: 602}},
+		ChapterSuggestions: []ChapterSuggestion{
+			{StartTime: 602, Kind: ChapterBoundarySilence},
+			{StartTime: 1204, Kind: ChapterBoundaryStinger},
+			{StartTime: 3725, Kind: ChapterBoundarySilence},
+		},
+	}
+
+	t.Run("rejects boundaries that were not suggested", func(t *testing.T) {
+		request := AcceptChaptersRequest{Chapters: []Chapter{{StartTime: 900}}}
+		assert.True(t, request.Apply(media).HasErrors())
+		assert.Len(t, media.ChapterSuggestions, 3)
+	})
+
+	t.Run("accepts suggestions into the chapters", func(t *testing.T) {
+		request := AcceptChaptersRequest{Chapters: []Chapter{{StartTime: 3725}, {Title: " Interview ", StartTime: 602}}}
+		require.False(t, request.Apply(media).HasErrors())
+
+		assert.Equal(t, []Chapter{
+			{Title: "Intro", StartTime: 0},
+			{Title: "Interview", StartTime: 602},
+			{Title: "Chapter at 1:02:05", StartTime: 3725},
+		}, media.Chapters)
+		assert.Equal(t, []ChapterSuggestion{{StartTime: 1204, Kind: ChapterBoundaryStinger}}, media.ChapterSuggestions)
+	})
+}
@@ -1,27 +1,55 @@
 package domain
 
 import (
+	"path/filepath"
+	"strings"
 	"time"
 )
 
 // Constants for business logic
 const (
-	// File size limits
+	// Default file size limits, configurable per media type and tenant
 	MaxVideoFileSize   = 5 * 1024 * 1024 * 1024 // 5GB
 	MaxPodcastFileSize = 1 * 1024 * 1024 * 1024 // 1GB
 
-	// Upload URL expiration
+	// Default upload URL expiration, configurable per tenant
 	UploadURLTTL = 1 * time.Hour
 
+	// Uploaded file paths are this prefix followed by their storage key
+	UploadPathPrefix = "/uploads/"
+
+	// Assets derived from a media item are stored under this prefix followed by its ID
+	DerivedKeyPrefix = "derived/"
+
 	// Search limits
-	MaxSearchLimit     = 100
-	DefaultSearchLimit = 20
+	MaxSearchLimit      = 100
+	DefaultSearchLimit  = 20
+	MaxSearchWindow     = 10000 // deepest result reachable by paging, Elasticsearch's default max_result_window
+	CollapseInnerHits   = 3     // other episodes returned with the top result of a show collapsed by search
+	MaxSuggestLimit     = 50
+	DefaultSuggestLimit = 10
 
 	// Pagination
 	DefaultPageSize = 20
 	MaxPageSize     = 100
+
+	// Media whose playback can be resolved in one request, like a playlist
+	MaxPlaybackBatchSize = 50
 )
 
+// CountMode selects how listing totals are computed
+type CountMode string
+
+const (
+	CountExact     CountMode = "exact"     // COUNT(*) on every request
+	CountEstimated CountMode = "estimated" // planner statistics, constant time but approximate
+)
+
+// IsValid checks if the count mode is known
+func (m CountMode) IsValid() bool {
+	return m == CountExact || m == CountEstimated
+}
+
 // Supported file formats
 var (
 	VideoFormats = []string{"mp4", "mov", "avi", "mkv", "webm"}
@@ -60,6 +88,24 @@ func IsValidFormat(mediaType MediaType, format string) bool {
 	}
 }
 
+// FormatFromFilename infers the file format from the extension of a filename,
+// lower-cased and without the dot; empty when the filename has no extension
+func FormatFromFilename(filename string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+// FormatsFor returns the file formats accepted for a media type
+func FormatsFor(mediaType MediaType) []string {
+	switch mediaType {
+	case TypeVideo:
+		return VideoFormats
+	case TypePodcast:
+		return AudioFormats
+	default:
+		return nil
+	}
+}
+
 // GetMaxFileSize returns the maximum allowed file size for a media type
 func GetMaxFileSize(mediaType MediaType) int64 {
 	switch mediaType {
@@ -78,6 +124,7 @@ type Event struct {
 	Type      string                 `json:"type"`
 	Data      map[string]interface{} `json:"data"`
 	Timestamp time.Time              `json:"timestamp"`
+	Trace     *TraceContext          `json:"trace,omitempty"` // request that caused the event
 }
 
 // Event types
@@ -86,6 +133,19 @@ const (
 	EventMediaProcessed = "media.processed"
 	EventMediaDeleted   = "media.deleted"
 	EventMediaUpdated   = "media.updated"
+	EventMediaPurged    = "media.purged" // deleted media removed for good once its retention passed
+
+	EventMediaStatusChanged = "media.status_changed"
+	EventMediaDuplicate     = "media.duplicate_detected"
+	EventModerationDecided  = "moderation.decided"
+
+	EventLicenseExpiring  = "media.license_expiring"
+	EventMediaUnpublished = "media.unpublished"
+
+	EventLiveStarted = "live.started" // the encoder of a live stream started publishing
+	EventLiveEnded   = "live.ended"
+
+	EventPremiereStarted = "premiere.started" // playback of premiering media opened
 )
 
 // NewEvent creates a new domain event
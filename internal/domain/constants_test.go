@@ -145,6 +145,7 @@ func TestEventTypes(t *testing.T) {
 	assert.Equal(t, "media.processed", EventMediaProcessed)
 	assert.Equal(t, "media.deleted", EventMediaDeleted)
 	assert.Equal(t, "media.updated", EventMediaUpdated)
+	assert.Equal(t, "media.purged", EventMediaPurged)
 }
 
 func TestGenerateID(t *testing.T) {
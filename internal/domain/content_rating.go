@@ -0,0 +1,46 @@
+package domain
+
+// AgeRating is the minimum viewer age media is suitable for
+type AgeRating string
+
+const (
+	AgeRatingAll AgeRating = "all"
+	AgeRating7   AgeRating = "7+"
+	AgeRating13  AgeRating = "13+"
+	AgeRating16  AgeRating = "16+"
+	AgeRating18  AgeRating = "18+" // adult content, always treated as explicit
+)
+
+// IsValid returns true for known age ratings
+func (r AgeRating) IsValid() bool {
+	switch r {
+	case AgeRatingAll, AgeRating7, AgeRating13, AgeRating16, AgeRating18:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContentRating holds the advisory information of media
+type ContentRating struct {
+	AgeRating AgeRating `json:"age_rating" gorm:"type:varchar(10);not null;default:'all'"`
+	Explicit  bool      `json:"explicit" gorm:"not null;default:false;index"` // explicit language or themes
+}
+
+// IsValid returns true if the age rating is known or left empty for the default
+func (r ContentRating) IsValid() bool {
+	return r.AgeRating == "" || r.AgeRating.IsValid()
+}
+
+// IsExplicit returns true if the content is excluded by safe filtering
+func (r ContentRating) IsExplicit() bool {
+	return r.Explicit || r.AgeRating == AgeRating18
+}
+
+// withDefaults returns the rating with an empty age rating set to all ages
+func (r ContentRating) withDefaults() ContentRating {
+	if r.AgeRating == "" {
+		r.AgeRating = AgeRatingAll
+	}
+	return r
+}
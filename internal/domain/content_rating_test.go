@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentRating_IsExplicit(t *testing.T) {
+	tests := []struct {
+		name     string
+		rating   ContentRating
+		valid    bool
+		explicit bool
+	}{
+		{name: "default rating", rating: ContentRating{}, valid: true},
+		{name: "all ages", rating: ContentRating{AgeRating: AgeRatingAll}, valid: true},
+		{name: "teen rating", rating: ContentRating{AgeRating: AgeRating13}, valid: true},
+		{name: "explicit flag", rating: ContentRating{AgeRating: AgeRating16, Explicit: true}, valid: true, explicit: true},
+		{name: "adult rating is explicit", rating: ContentRating{AgeRating: AgeRating18}, valid: true, explicit: true},
+		{name: "unknown rating", rating: ContentRating{AgeRating: "PG-13"}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.rating.IsValid())
+			assert.Equal(t, tt.explicit, tt.rating.IsExplicit())
+		})
+	}
+}
+
+func TestUpdateMediaRequest_ContentRating(t *testing.T) {
+	// Given
+	media := &Media{ID: "123", ContentRating: ContentRating{AgeRating: AgeRating18, Explicit: true}}
+
+	// When
+	request := &UpdateMediaRequest{ContentRating: &ContentRating{}}
+	request.ApplyTo(media)
+
+	// Then
+	assert.True(t, request.IsValid())
+	assert.Equal(t, ContentRating{AgeRating: AgeRatingAll}, media.ContentRating)
+	assert.False(t, (&UpdateMediaRequest{ContentRating: &ContentRating{AgeRating: "R"}}).IsValid())
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Degradation records whether a request was answered with data served from a
+// cache because the database was unavailable
+type Degradation struct {
+	degraded atomic.Bool
+}
+
+// Degraded returns true if some of the data of the request came from a cache
+func (d *Degradation) Degraded() bool {
+	return d.degraded.Load()
+}
+
+type degradationContextKey struct{}
+
+// ContextWithDegradation returns a context recording degraded reads into a new Degradation
+func ContextWithDegradation(ctx context.Context) (context.Context, *Degradation) {
+	degradation := &Degradation{}
+	return context.WithValue(ctx, degradationContextKey{}, degradation), degradation
+}
+
+// MarkDegraded records that data served for ctx came from a cache. It returns
+// false for contexts without a Degradation, like those of background jobs,
+// which must not be served cached data.
+func MarkDegraded(ctx context.Context) bool {
+	degradation, ok := ctx.Value(degradationContextKey{}).(*Degradation)
+	if ok {
+		degradation.degraded.Store(true)
+	}
+	return ok
+}
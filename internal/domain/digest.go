@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DigestPeriod is the activity a weekly digest summarizes
+const DigestPeriod = 7 * 24 * time.Hour
+
+// DefaultDigestTopItems is the number of failures, searches and played items a digest lists
+const DefaultDigestTopItems = 5
+
+// MaxSearchTermLength bounds the search terms counted for digests
+const MaxSearchTermLength = 200
+
+// SearchQueryCount counts the searches of a term by a tenant on a UTC day
+type SearchQueryCount struct {
+	Day      string `json:"day" gorm:"primaryKey;type:varchar(10)"` // YYYY-MM-DD
+	TenantID string `json:"tenant_id" gorm:"primaryKey;type:varchar(100)"`
+	Term     string `json:"term" gorm:"primaryKey;type:varchar(200)"`
+	Count    int64  `json:"count" gorm:"not null;default:0"`
+}
+
+// TableName specifies the table name for SearchQueryCount
+func (SearchQueryCount) TableName() string {
+	return "search_query_counts"
+}
+
+// NormalizeSearchTerm folds the queries users type into the term they are counted as,
+// lower-cased with collapsed whitespace
+func NormalizeSearchTerm(query string) string {
+	term := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if len(term) > MaxSearchTermLength {
+		term = strings.ToValidUTF8(term[:MaxSearchTermLength], "")
+	}
+	return term
+}
+
+// DigestDelivery records the last digest period sent to a tenant, so a digest
+// is sent once per period even when the send is retried or the job restarts
+type DigestDelivery struct {
+	TenantID  string    `json:"tenant_id" gorm:"primaryKey;type:varchar(100)"`
+	PeriodEnd time.Time `json:"period_end" gorm:"not null"` // To of the last digest sent
+	SentAt    time.Time `json:"sent_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for DigestDelivery
+func (DigestDelivery) TableName() string {
+	return "digest_deliveries"
+}
+
+// DigestSearch is a search term and how often it was searched
+type DigestSearch struct {
+	Term  string `json:"term"`
+	Count int64  `json:"count"`
+}
+
+// DigestMedia is a media item listed in a digest
+type DigestMedia struct {
+	MediaID        string `json:"media_id"`
+	Title          string `json:"title"`
+	FailureMessage string `json:"failure_message,omitempty"`
+	Plays          int64  `json:"plays,omitempty"`
+}
+
+// WeeklyDigest summarizes the catalog activity of a tenant over the whole UTC days within [From, To)
+type WeeklyDigest struct {
+	TenantID    string         `json:"tenant_id"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	Uploads     int64          `json:"uploads"`
+	Failures    int64          `json:"failures"`
+	Failed      []DigestMedia  `json:"failed"` // the most recent failures
+	TopSearches []DigestSearch `json:"top_searches"`
+	TopPlayed   []DigestMedia  `json:"top_played"`
+}
+
+// LastDay returns the last day the digest covers, To being exclusive
+func (d *WeeklyDigest) LastDay() time.Time {
+	return d.To.AddDate(0, 0, -1)
+}
+
+// IsEmpty returns true if nothing happened in the catalog during the period
+func (d *WeeklyDigest) IsEmpty() bool {
+	return d.Uploads == 0 && d.Failures == 0 && len(d.TopSearches) == 0 && len(d.TopPlayed) == 0
+}
+
+// ParseWeekday parses an English weekday name, such as monday
+func ParseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), strings.TrimSpace(name)) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday %q", name)
+}
+
+// LastDigestAt returns the last time up to now falling on weekday at hour, in UTC
+func LastDigestAt(now time.Time, weekday time.Weekday, hour int) time.Time {
+	return NextDigestAt(now, weekday, hour).AddDate(0, 0, -7)
+}
+
+// NextDigestAt returns the first time after now falling on weekday at hour, in UTC
+func NextDigestAt(now time.Time, weekday time.Weekday, hour int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	next = next.AddDate(0, 0, (int(weekday)-int(next.Weekday())+7)%7)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSearchTerm(t *testing.T) {
+	assert.Equal(t, "arabic podcasts", NormalizeSearchTerm("  Arabic \t PODCASTS "))
+	assert.Len(t, NormalizeSearchTerm(strings.Repeat("a", 300)), MaxSearchTermLength)
+	assert.Equal(t, "", NormalizeSearchTerm("   "))
+}
+
+func TestParseWeekday(t *testing.T) {
+	day, err := ParseWeekday(" Monday")
+	require.NoError(t, err)
+	assert.Equal(t, time.Monday, day)
+
+	_, err = ParseWeekday("someday")
+	assert.Error(t, err)
+}
+
+func TestNextDigestAt(t *testing.T) {
+	// Wednesday 2025-06-04
+	wednesday := time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		weekday  time.Weekday
+		hour     int
+		expected time.Time
+	}{
+		{
+			name:     "later this week",
+			now:      wednesday,
+			weekday:  time.Friday,
+			hour:     8,
+			expected: time.Date(2025, 6, 6, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "later today",
+			now:      wednesday,
+			weekday:  time.Wednesday,
+			hour:     12,
+			expected: time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "passed today",
+			now:      wednesday,
+			weekday:  time.Wednesday,
+			hour:     10,
+			expected: time.Date(2025, 6, 11, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "next week",
+			now:      wednesday,
+			weekday:  time.Monday,
+			hour:     8,
+			expected: time.Date(2025, 6, 9, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NextDigestAt(tt.now, tt.weekday, tt.hour))
+			assert.Equal(t, tt.expected.AddDate(0, 0, -7), LastDigestAt(tt.now, tt.weekday, tt.hour))
+		})
+	}
+}
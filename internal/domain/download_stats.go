@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Download counting follows the IAB Podcast Measurement Technical Guidelines
+const (
+	DownloadDedupWindow   = 24 * time.Hour // repeated requests of a client within it count once
+	DefaultStatsRangeDays = 30             // days reported when no start date is given
+	MaxStatsRangeDays     = 366
+)
+
+// botUserAgentMarkers identify the user agents of crawlers and scripts, whose requests are not downloads
+var botUserAgentMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests", "go-http-client",
+	"okhttp", "headlesschrome", "facebookexternalhit", "feedfetcher", "monitor", "uptime",
+}
+
+// Download is a counted podcast episode download
+type Download struct {
+	ID           string    `json:"id" gorm:"primaryKey"`
+	MediaID      string    `json:"media_id" gorm:"not null;index:idx_downloads_media_client,priority:1"`
+	ShowID       string    `json:"show_id,omitempty" gorm:"index"` // show of the episode when downloaded
+	ClientHash   string    `json:"-" gorm:"not null;index:idx_downloads_media_client,priority:2"`
+	Day          string    `json:"day" gorm:"type:varchar(10);not null;index"` // UTC day, like 2025-06-01
+	DownloadedAt time.Time `json:"downloaded_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for Download
+func (Download) TableName() string {
+	return "downloads"
+}
+
+// DownloadClient describes the client requesting a media file
+type DownloadClient struct {
+	IP        string
+	UserAgent string
+	Range     string // Range header of the request, empty for the whole file
+}
+
+// Hash identifies the client by IP address and user agent without storing either
+func (c DownloadClient) Hash() string {
+	sum := sha256.Sum256([]byte(c.IP + "\n" + c.UserAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsCountable returns false for requests that are not downloads: requests of bots and
+// of clients without a user agent, and the byte range probes players send before playback
+func (c DownloadClient) IsCountable() bool {
+	userAgent := strings.ToLower(strings.TrimSpace(c.UserAgent))
+	if userAgent == "" {
+		return false
+	}
+	for _, marker := range botUserAgentMarkers {
+		if strings.Contains(userAgent, marker) {
+			return false
+		}
+	}
+	return !isRangeProbe(c.Range)
+}
+
+// isRangeProbe reports whether a Range header asks for at most 2 bytes, like bytes=0-1
+func isRangeProbe(rangeHeader string) bool {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(rangeHeader), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return false
+	}
+	startValue, endValue, ok := strings.Cut(spec, "-")
+	if !ok {
+		return false
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(startValue), 10, 64)
+	if err != nil {
+		return false
+	}
+	end, err := strconv.ParseInt(strings.TrimSpace(endValue), 10, 64)
+	if err != nil {
+		return false
+	}
+	return end >= start && end-start < 2
+}
+
+// DownloadStatsDay holds the downloads of one UTC day
+type DownloadStatsDay struct {
+	Day       string `json:"day"`
+	Downloads int64  `json:"downloads"`
+}
+
+// DownloadStats reports the downloads of an episode or a show by day
+type DownloadStats struct {
+	MediaID string             `json:"media_id,omitempty"`
+	ShowID  string             `json:"show_id,omitempty"`
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Total   int64              `json:"total"`
+	Days    []DownloadStatsDay `json:"days"` // days without downloads are left out
+}
+
+// ParseStatsRange reads the inclusive from and to days of a statistics request.
+// To defaults to today and from to the DefaultStatsRangeDays days up to to.
+// The returned end is exclusive, at the start of the day after to.
+func ParseStatsRange(fromValue, toValue string, now time.Time) (time.Time, time.Time, ValidationErrors) {
+	var errs ValidationErrors
+
+	end := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	if toValue != "" {
+		parsed, err := time.Parse(CalendarDateLayout, toValue)
+		if err != nil {
+			errs.Add("to", "must be a date like 2025-06-30")
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+
+	from := end.AddDate(0, 0, -DefaultStatsRangeDays)
+	if fromValue != "" {
+		parsed, err := time.Parse(CalendarDateLayout, fromValue)
+		if err != nil {
+			errs.Add("from", "must be a date like 2025-06-01")
+		}
+		from = parsed
+	}
+
+	if errs.HasErrors() {
+		return time.Time{}, time.Time{}, errs
+	}
+	if !end.After(from) {
+		errs.Add("to", "must not be before from")
+	} else if end.Sub(from) > MaxStatsRangeDays*24*time.Hour {
+		errs.Add("to", fmt.Sprintf("must be at most %d days after from", MaxStatsRangeDays))
+	}
+
+	return from, end, errs
+}
+
+// NewDownloadStats sums the daily downloads of the range [from, end)
+func NewDownloadStats(days []DownloadStatsDay, from, end time.Time) *DownloadStats {
+	stats := &DownloadStats{
+		From: from.Format(CalendarDateLayout),
+		To:   end.AddDate(0, 0, -1).Format(CalendarDateLayout),
+		Days: days,
+	}
+	if stats.Days == nil {
+		stats.Days = []DownloadStatsDay{}
+	}
+	for _, day := range days {
+		stats.Total += day.Downloads
+	}
+	return stats
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadClient_IsCountable(t *testing.T) {
+	const player = "AppleCoreMedia/1.0.0.21E236 (iPhone; U; CPU OS 17_4 like Mac OS X; en_us)"
+
+	tests := []struct {
+		name      string
+		client    DownloadClient
+		countable bool
+	}{
+		{"player", DownloadClient{UserAgent: player}, true},
+		{"player range", DownloadClient{UserAgent: player, Range: "bytes=0-"}, true},
+		{"player partial range", DownloadClient{UserAgent: player, Range: "bytes=1000-50000"}, true},
+		{"range probe", DownloadClient{UserAgent: player, Range: "bytes=0-1"}, false},
+		{"single byte probe", DownloadClient{UserAgent: player, Range: "bytes=0-0"}, false},
+		{"no user agent", DownloadClient{}, false},
+		{"crawler", DownloadClient{UserAgent: "Mozilla/5.0 (compatible; Googlebot/2.1)"}, false},
+		{"script", DownloadClient{UserAgent: "curl/8.4.0"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.countable, tt.client.IsCountable())
+		})
+	}
+}
+
+func TestDownloadClient_Hash(t *testing.T) {
+	client := DownloadClient{IP: "203.0.113.7", UserAgent: "Overcast/3.0"}
+
+	assert.Equal(t, client.Hash(), DownloadClient{IP: "203.0.113.7", UserAgent: "Overcast/3.0", Range: "bytes=0-"}.Hash())
+	assert.NotEqual(t, client.Hash(), DownloadClient{IP: "203.0.113.8", UserAgent: "Overcast/3.0"}.Hash())
+	assert.NotContains(t, client.Hash(), "203.0.113.7")
+}
+
+func TestParseStatsRange(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 30, 0, 0, time.UTC)
+
+	// Defaults to the 30 days up to today
+	from, end, errs := ParseStatsRange("", "", now)
+	require.False(t, errs.HasErrors())
+	assert.Equal(t, time.Date(2025, 5, 17, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), end)
+
+	from, end, errs = ParseStatsRange("2025-01-01", "2025-01-31", now)
+	require.False(t, errs.HasErrors())
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, errs = ParseStatsRange("2025-02-01", "2025-01-01", now)
+	assert.True(t, errs.HasErrors())
+	_, _, errs = ParseStatsRange("2023-01-01", "2025-01-01", now)
+	assert.True(t, errs.HasErrors())
+	_, _, errs = ParseStatsRange("yesterday", "", now)
+	assert.True(t, errs.HasErrors())
+}
+
+func TestNewDownloadStats(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := from.AddDate(0, 0, 7)
+
+	stats := NewDownloadStats([]DownloadStatsDay{{Day: "2025-06-01", Downloads: 3}, {Day: "2025-06-03", Downloads: 4}}, from, end)
+	assert.Equal(t, "2025-06-01", stats.From)
+	assert.Equal(t, "2025-06-07", stats.To)
+	assert.Equal(t, int64(7), stats.Total)
+
+	assert.NotNil(t, NewDownloadStats(nil, from, end).Days)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// DRMSystem identifies a DRM system players acquire licenses from
+type DRMSystem string
+
+const (
+	DRMWidevine DRMSystem = "widevine"
+	DRMFairPlay DRMSystem = "fairplay"
+)
+
+// WidevineSystemID is the DRM system ID of Widevine, announced in DASH manifests
+const WidevineSystemID = "edef8ba9-79d6-4ace-a3c8-27dcd51d21ed"
+
+// ContentKey is the key the renditions of a media item are encrypted with.
+// The key itself is stored encrypted with the key encryption key.
+type ContentKey struct {
+	MediaID      string    `json:"media_id" gorm:"primaryKey"`
+	KeyID        string    `json:"key_id" gorm:"type:varchar(32);not null;uniqueIndex"` // hex encoded, 16 bytes
+	EncryptedKey []byte    `json:"-" gorm:"type:bytea;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for ContentKey
+func (ContentKey) TableName() string {
+	return "content_keys"
+}
+
+// DRMLicense tells the player where to acquire a license for a DRM system
+type DRMLicense struct {
+	System         DRMSystem `json:"system"`
+	LicenseURL     string    `json:"license_url"`
+	CertificateURL string    `json:"certificate_url,omitempty"` // FairPlay application certificate
+}
+
+// PlaybackDRM is the license acquisition info of protected media
+type PlaybackDRM struct {
+	KeyID    string       `json:"key_id"`
+	Licenses []DRMLicense `json:"licenses"`
+}
+
+// PlaybackInfo is everything a player needs to start playback of a media item
+type PlaybackInfo struct {
+	MediaID   string       `json:"media_id"`
+	Type      MediaType    `json:"type"`
+	StreamURL string       `json:"stream_url"`
+	DRM       *PlaybackDRM `json:"drm,omitempty"`
+	CuePoints []CuePoint   `json:"cue_points,omitempty"` // ad breaks to request from the ad server
+
+	// Audio languages the player may switch between, listed only for dubbed media
+	AudioTracks []PlaybackAudioTrack `json:"audio_tracks,omitempty"`
+}
+
+// PlaybackAudioTrack is an audio language of a media item selectable in the player
+type PlaybackAudioTrack struct {
+	Language string `json:"language,omitempty"` // empty when the original language is unknown
+	Label    string `json:"label,omitempty"`
+	URL      string `json:"url"`
+	Default  bool   `json:"default,omitempty"` // the original audio, part of the stream
+}
+
+// PlaybackResult is the playback info of one media item of a batch, or why it cannot be played
+type PlaybackResult struct {
+	MediaID  string        `json:"media_id"`
+	Playback *PlaybackInfo `json:"playback,omitempty"`
+	Error    string        `json:"error,omitempty"` // MEDIA_NOT_FOUND, GEO_RESTRICTED, SUBSCRIPTION_REQUIRED or MEDIA_NOT_READY
+	Message  string        `json:"message,omitempty"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedia_RequiresDRM(t *testing.T) {
+	tests := []struct {
+		name     string
+		media    Media
+		expected bool
+	}{
+		{name: "premium video", media: Media{Type: TypeVideo, AccessTier: AccessTierPremium}, expected: true},
+		{name: "free video", media: Media{Type: TypeVideo, AccessTier: AccessTierFree}, expected: false},
+		{name: "premium podcast", media: Media{Type: TypePodcast, AccessTier: AccessTierPremium}, expected: false},
+		{name: "tier not set", media: Media{Type: TypeVideo}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := tt.media.RequiresDRM()
+
+			// Then
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Player themes
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// hexColorPattern matches #RGB and #RRGGBB colors
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// EmbedTheme configures the look of the embedded player
+type EmbedTheme struct {
+	Mode        string `json:"mode"` // dark or light
+	AccentColor string `json:"accent_color"`
+}
+
+// Validate validates the theme
+func (t EmbedTheme) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if t.Mode != ThemeDark && t.Mode != ThemeLight {
+		errs.Add("theme", "must be one of: dark, light")
+	}
+	if !hexColorPattern.MatchString(t.AccentColor) {
+		errs.Add("accent_color", "must be a hex color like #1DB954")
+	}
+
+	return errs
+}
+
+// EmbedCaption is a caption track of the embedded player
+type EmbedCaption struct {
+	Language string `json:"language"`
+	Label    string `json:"label"`
+	URL      string `json:"url"`
+}
+
+// EmbedChapter is a chapter marker of the embedded player
+type EmbedChapter struct {
+	Title     string `json:"title"`
+	StartTime int    `json:"start_time"` // in seconds
+}
+
+// EmbedConfig is everything the web player needs to play a media item
+type EmbedConfig struct {
+	MediaID   string         `json:"media_id"`
+	Title     string         `json:"title"`
+	Type      MediaType      `json:"type"`
+	Duration  int            `json:"duration"` // in seconds
+	StreamURL string         `json:"stream_url"`
+	PosterURL string         `json:"poster_url,omitempty"`
+	Captions  []EmbedCaption `json:"captions"`
+	Chapters  []EmbedChapter `json:"chapters"`
+	Theme     EmbedTheme     `json:"theme"`
+}
+
+// NewEmbedConfig builds the player configuration of a media item
+func NewEmbedConfig(media *Media, baseURL string, theme EmbedTheme) *EmbedConfig {
+	chapters := make([]EmbedChapter, len(media.Chapters))
+	for i, chapter := range media.Chapters {
+		chapters[i] = EmbedChapter{Title: chapter.Title, StartTime: chapter.StartTime}
+	}
+
+	return &EmbedConfig{
+		MediaID:   media.ID,
+		Title:     media.Title,
+		Type:      media.Type,
+		Duration:  media.Duration,
+		StreamURL: strings.TrimSuffix(baseURL, "/") + media.FilePath,
+		Captions:  []EmbedCaption{},
+		Chapters:  chapters,
+		Theme:     theme,
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedTheme_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		theme       EmbedTheme
+		errorFields []string
+	}{
+		{
+			name:  "valid theme",
+			theme: EmbedTheme{Mode: ThemeDark, AccentColor: "#1DB954"},
+		},
+		{
+			name:  "short hex color",
+			theme: EmbedTheme{Mode: ThemeLight, AccentColor: "#fff"},
+		},
+		{
+			name:        "unknown mode and invalid color",
+			theme:       EmbedTheme{Mode: "neon", AccentColor: "red"},
+			errorFields: []string{"theme", "accent_color"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.theme.Validate()
+
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.errorFields, fields)
+		})
+	}
+}
+
+func TestNewEmbedConfig(t *testing.T) {
+	// Given
+	media := &Media{
+		ID:       "media-123",
+		Title:    "Episode 1",
+		Type:     TypePodcast,
+		Duration: 1800,
+		FilePath: "/uploads/media-123.mp3",
+	}
+	theme := EmbedTheme{Mode: ThemeDark, AccentColor: "#1DB954"}
+
+	// When
+	config := NewEmbedConfig(media, "https://cdn.example.com/", theme)
+
+	// Then
+	assert.Equal(t, "media-123", config.MediaID)
+	assert.Equal(t, "https://cdn.example.com/uploads/media-123.mp3", config.StreamURL)
+	assert.Equal(t, 1800, config.Duration)
+	assert.Equal(t, theme, config.Theme)
+	assert.NotNil(t, config.Captions)
+	assert.NotNil(t, config.Chapters)
+
+	// And chapters follow the media
+	media.Chapters = []Chapter{{Title: "Intro", StartTime: 0}, {Title: "Interview", StartTime: 600}}
+	config = NewEmbedConfig(media, "https://cdn.example.com/", theme)
+	assert.Equal(t, []EmbedChapter{{Title: "Intro", StartTime: 0}, {Title: "Interview", StartTime: 600}}, config.Chapters)
+}
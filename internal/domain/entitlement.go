@@ -0,0 +1,20 @@
+package domain
+
+// SubscriptionTier is the plan a user is subscribed to
+type SubscriptionTier string
+
+const (
+	SubscriptionFree    SubscriptionTier = "free"
+	SubscriptionPremium SubscriptionTier = "premium"
+)
+
+// IsValid checks if the subscription tier is known
+func (t SubscriptionTier) IsValid() bool {
+	return t == SubscriptionFree || t == SubscriptionPremium
+}
+
+// Includes returns true if the tier grants access to media of accessTier.
+// Free media is open to everyone, including anonymous viewers without a tier.
+func (t SubscriptionTier) Includes(accessTier MediaAccessTier) bool {
+	return accessTier != AccessTierPremium || t == SubscriptionPremium
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionTier_Includes(t *testing.T) {
+	tests := []struct {
+		name     string
+		tier     SubscriptionTier
+		access   MediaAccessTier
+		includes bool
+	}{
+		{"anonymous free media", "", AccessTierFree, true},
+		{"anonymous premium media", "", AccessTierPremium, false},
+		{"free premium media", SubscriptionFree, AccessTierPremium, false},
+		{"premium premium media", SubscriptionPremium, AccessTierPremium, true},
+		{"premium free media", SubscriptionPremium, AccessTierFree, true},
+		{"tier not set on media", SubscriptionFree, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.includes, tt.tier.Includes(tt.access))
+		})
+	}
+}
+
+func TestMedia_CheckPlaybackEntitlement(t *testing.T) {
+	media := &Media{ID: "m1", AccessTier: AccessTierPremium}
+
+	err := media.CheckPlayback(Viewer{UserID: "u1", Tier: SubscriptionFree})
+	require.True(t, errors.Is(err, ErrNotEntitled))
+	var entitlementErr *EntitlementError
+	require.ErrorAs(t, err, &entitlementErr)
+	assert.Equal(t, "m1", entitlementErr.MediaID)
+	assert.Equal(t, AccessTierPremium, entitlementErr.RequiredTier)
+
+	assert.NoError(t, media.CheckPlayback(Viewer{UserID: "u1", Tier: SubscriptionPremium}))
+	assert.NoError(t, media.CheckPlayback(Viewer{IsAdmin: true}))
+
+	// Geo-restrictions are reported before the missing subscription
+	media.GeoRestriction = GeoRestriction{AllowedCountries: []string{"SA"}}
+	assert.ErrorIs(t, media.CheckPlayback(Viewer{Country: "US"}), ErrGeoRestricted)
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Common domain errors
@@ -14,6 +15,36 @@ var (
 	ErrForbidden          = errors.New("forbidden")
 	ErrInternalError      = errors.New("internal server error")
 	ErrServiceUnavailable = errors.New("service unavailable")
+
+	ErrNotificationPreferenceNotFound = errors.New("notification preference not found")
+	ErrProcessingQueueFull            = errors.New("processing queue is full")
+	ErrShareLinkNotFound              = errors.New("share link not found")
+	ErrTranscodePresetNotFound        = errors.New("transcode preset not found")
+	ErrArtworkNotFound                = errors.New("artwork not found")
+	ErrContentKeyNotFound             = errors.New("content key not found")
+	ErrGeoRestricted                  = errors.New("media is not available in this country")
+	ErrAuditEntryNotFound             = errors.New("audit entry not found")
+	ErrCooldown                       = errors.New("operation is cooling down")
+	ErrShowTemplateNotFound           = errors.New("show template not found")
+	ErrBulkJobNotFound                = errors.New("bulk job not found")
+	ErrFeaturedItemNotFound           = errors.New("featured item not found")
+	ErrPersonNotFound                 = errors.New("person not found")
+	ErrNotEntitled                    = errors.New("subscription does not include this media")
+	ErrAbuseReportNotFound            = errors.New("abuse report not found")
+	ErrAPIKeyNotFound                 = errors.New("API key not found")
+	ErrTranscriptNotFound             = errors.New("transcript not found")
+	ErrMetadataSuggestionNotFound     = errors.New("metadata suggestion not found")
+	ErrMetadataSuggestionResolved     = errors.New("metadata suggestion was already accepted or dismissed")
+	ErrInvalidUploadURL               = errors.New("upload URL is invalid or expired")
+	ErrAudioTrackNotFound             = errors.New("audio track not found")
+	ErrLiveStreamNotFound             = errors.New("live stream not found")
+	ErrLiveStreamEnded                = errors.New("live stream has ended")
+	ErrPremiereNotFound               = errors.New("media does not premiere")
+	ErrPremiereNotStarted             = errors.New("premiere has not started")
+	ErrRenditionNotFound              = errors.New("rendition not found")
+	ErrStreamNotFound                 = errors.New("media has no renditions to stream")
+	ErrInvalidDownloadURL             = errors.New("download URL is invalid or expired")
+	ErrFileNotFound                   = errors.New("file not found")
 )
 
 // ValidationError represents a validation error with details
@@ -54,6 +85,84 @@ func (e *ValidationErrors) Add(field, message string) {
 	*e = append(*e, ValidationError{Field: field, Message: message})
 }
 
+// StatusTransitionError represents an illegal media status transition
+type StatusTransitionError struct {
+	From MediaStatus `json:"from"`
+	To   MediaStatus `json:"to"`
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition from '%s' to '%s'", e.From, e.To)
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidMediaStatus) checks
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrInvalidMediaStatus
+}
+
+// GeoRestrictionError reports playback blocked by the geo-restriction rules of media
+type GeoRestrictionError struct {
+	MediaID string `json:"media_id"`
+	Country string `json:"country"` // empty when the country could not be determined
+}
+
+func (e *GeoRestrictionError) Error() string {
+	if e.Country == "" {
+		return fmt.Sprintf("media %s is not available in an unknown country", e.MediaID)
+	}
+	return fmt.Sprintf("media %s is not available in %s", e.MediaID, e.Country)
+}
+
+// Unwrap allows errors.Is(err, ErrGeoRestricted) checks
+func (e *GeoRestrictionError) Unwrap() error {
+	return ErrGeoRestricted
+}
+
+// EntitlementError reports playback blocked because the viewer's subscription does not include the media
+type EntitlementError struct {
+	MediaID      string          `json:"media_id"`
+	RequiredTier MediaAccessTier `json:"required_tier"`
+}
+
+func (e *EntitlementError) Error() string {
+	return fmt.Sprintf("media %s requires a %s subscription", e.MediaID, e.RequiredTier)
+}
+
+// Unwrap allows errors.Is(err, ErrNotEntitled) checks
+func (e *EntitlementError) Unwrap() error {
+	return ErrNotEntitled
+}
+
+// PremiereNotStartedError reports playback refused before the premiere of media starts
+type PremiereNotStartedError struct {
+	MediaID  string    `json:"media_id"`
+	StartsAt time.Time `json:"starts_at"`
+}
+
+func (e *PremiereNotStartedError) Error() string {
+	return fmt.Sprintf("media %s premieres at %s", e.MediaID, e.StartsAt.Format(time.RFC3339))
+}
+
+// Unwrap allows errors.Is(err, ErrPremiereNotStarted) checks
+func (e *PremiereNotStartedError) Unwrap() error {
+	return ErrPremiereNotStarted
+}
+
+// CooldownError reports an operation invoked again before its cooldown window passed
+type CooldownError struct {
+	Action     string        `json:"action"`
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("%s was invoked recently, retry in %s", e.Action, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap allows errors.Is(err, ErrCooldown) checks
+func (e *CooldownError) Unwrap() error {
+	return ErrCooldown
+}
+
 // BusinessError represents a business logic error
 type BusinessError struct {
 	Code    string `json:"code"`
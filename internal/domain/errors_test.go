@@ -218,3 +218,12 @@ func TestErrorIsComparable(t *testing.T) {
 	assert.True(t, errors.Is(err1, err2))
 	assert.False(t, errors.Is(err1, ErrInvalidRequest))
 }
+
+func TestStatusTransitionError(t *testing.T) {
+	// Given
+	err := &StatusTransitionError{From: StatusReady, To: StatusUploading}
+
+	// Then
+	assert.Equal(t, "invalid status transition from 'ready' to 'uploading'", err.Error())
+	assert.True(t, errors.Is(err, ErrInvalidMediaStatus))
+}
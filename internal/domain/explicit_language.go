@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxExplicitLanguageMatches bounds the matches stored per media item
+const MaxExplicitLanguageMatches = 500
+
+// explicitExcerptRunes is the context kept around a match on either side
+const explicitExcerptRunes = 40
+
+// DefaultExplicitTerms are the words flagged as explicit language when no terms
+// are configured, in English and Arabic
+var DefaultExplicitTerms = []string{
+	"fuck", "fucking", "fucked", "motherfucker", "shit", "bullshit", "bitch",
+	"bastard", "asshole", "cunt", "whore", "slut",
+	"كس", "زب", "شرموط", "شرموطة", "منيوك", "منيك", "عرص", "خول", "قحبة",
+}
+
+// ExplicitLanguageMatch is an explicit word found in the transcript of a media
+// item, kept for editors to review the explicit flag it raised
+type ExplicitLanguageMatch struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	MediaID   string    `json:"media_id" gorm:"not null;index"`
+	Term      string    `json:"term" gorm:"type:varchar(100);not null"`
+	Excerpt   string    `json:"excerpt" gorm:"type:text"`
+	StartTime *float64  `json:"start_time,omitempty"`        // seconds into the media, nil for plain text transcripts
+	Position  int       `json:"-" gorm:"not null;default:0"` // order of the match in the transcript
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for ExplicitLanguageMatch
+func (ExplicitLanguageMatch) TableName() string {
+	return "explicit_language_matches"
+}
+
+// ExplicitLanguageReport is the explicit language found in the transcript of a
+// media item and whether the media is flagged as explicit
+type ExplicitLanguageReport struct {
+	MediaID  string                   `json:"media_id"`
+	Explicit bool                     `json:"explicit"`
+	Matches  []*ExplicitLanguageMatch `json:"matches"`
+}
+
+// TranscriptCue is a caption cue of a transcript, or a line of a plain text one
+type TranscriptCue struct {
+	Start *float64 // seconds, nil without cue timings
+	Text  string
+}
+
+// cueTimestamp matches the start time of a WebVTT or SRT cue timing line
+var cueTimestamp = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{2})[.,](\d{1,3})\s*-->`)
+
+// TranscriptCues splits a transcript into its caption cues with their start
+// times, or into lines when it is plain text
+func TranscriptCues(raw string) []TranscriptCue {
+	raw = strings.TrimSpace(strings.ReplaceAll(raw, "\r\n", "\n"))
+	captions := strings.Contains(raw, "-->")
+
+	var cues []TranscriptCue
+	var start *float64
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if captions {
+			switch {
+			case line == "", strings.HasPrefix(line, "WEBVTT"), isCueNumber(line):
+				continue
+			case strings.Contains(line, "-->"):
+				start = parseCueStart(line)
+				continue
+			}
+			line = strings.TrimSpace(captionMarkup.ReplaceAllString(line, ""))
+		}
+		if line != "" {
+			cues = append(cues, TranscriptCue{Start: start, Text: line})
+		}
+	}
+	return cues
+}
+
+// parseCueStart returns the start of a cue timing line in seconds, nil when it is malformed
+func parseCueStart(line string) *float64 {
+	parts := cueTimestamp.FindStringSubmatch(line)
+	if parts == nil {
+		return nil
+	}
+
+	hours, _ := strconv.Atoi(parts[1])
+	minutes, _ := strconv.Atoi(parts[2])
+	seconds, _ := strconv.Atoi(parts[3])
+	millis, _ := strconv.Atoi((parts[4] + "00")[:3])
+	start := float64(hours*3600+minutes*60+seconds) + float64(millis)/1000
+	return &start
+}
+
+// ParseExplicitTerms normalizes configured explicit terms, DefaultExplicitTerms
+// when none are configured
+func ParseExplicitTerms(terms []string) (map[string]bool, error) {
+	if len(terms) == 0 {
+		terms = DefaultExplicitTerms
+	}
+
+	parsed := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		normalized := normalizeTerm(term)
+		if normalized == "" || strings.IndexFunc(normalized, isWordSeparator) >= 0 {
+			return nil, fmt.Errorf("invalid explicit term %q, expected a single word", term)
+		}
+		parsed[normalized] = true
+	}
+	return parsed, nil
+}
+
+// FindExplicitLanguage returns the words of the cues that are explicit terms, in
+// order, with an excerpt of the cue around each, at most MaxExplicitLanguageMatches
+func FindExplicitLanguage(cues []TranscriptCue, terms map[string]bool) []ExplicitLanguageMatch {
+	var matches []ExplicitLanguageMatch
+	for _, cue := range cues {
+		text := []rune(cue.Text)
+		for start := 0; start < len(text); {
+			if isWordSeparator(text[start]) {
+				start++
+				continue
+			}
+			end := start
+			for end < len(text) && !isWordSeparator(text[end]) {
+				end++
+			}
+
+			if term := normalizeTerm(string(text[start:end])); terms[term] {
+				if len(matches) == MaxExplicitLanguageMatches {
+					return matches
+				}
+				matches = append(matches, ExplicitLanguageMatch{
+					Term:      term,
+					Excerpt:   excerpt(text, start, end),
+					StartTime: cue.Start,
+					Position:  len(matches),
+				})
+			}
+			start = end
+		}
+	}
+	return matches
+}
+
+// isWordSeparator reports whether a rune separates the words of a transcript
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+}
+
+// excerpt returns the text around the word at text[start:end], marked with an
+// ellipsis where it was cut
+func excerpt(text []rune, start, end int) string {
+	from := max(start-explicitExcerptRunes, 0)
+	to := min(end+explicitExcerptRunes, len(text))
+
+	result := strings.TrimSpace(string(text[from:to]))
+	if from > 0 {
+		result = "…" + result
+	}
+	if to < len(text) {
+		result += "…"
+	}
+	return result
+}
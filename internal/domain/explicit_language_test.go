@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscriptCues(t *testing.T) {
+	at := func(seconds float64) *float64 { return &seconds }
+
+	t.Run("captions keep the start of their cue", func(t *testing.T) {
+		cues := TranscriptCues("1\r\n00:00:01,000 --> 00:00:04,000\r\n<i>Welcome</i> back.\r\n\r\n2\r\n01:02:03,250 --> 01:02:05,000\r\nLast words.\r\nReally.\r\n")
+		assert.Equal(t, []TranscriptCue{
+			{Start: at(1), Text: "Welcome back."},
+			{Start: at(3723.25), Text: "Last words."},
+			{Start: at(3723.25), Text: "Really."},
+		}, cues)
+	})
+
+	t.Run("WebVTT timings without hours", func(t *testing.T) {
+		cues := TranscriptCues("WEBVTT\n\n01:30.5 --> 01:33.000\nHello")
+		assert.Equal(t, []TranscriptCue{{Start: at(90.5), Text: "Hello"}}, cues)
+	})
+
+	t.Run("plain text lines have no start", func(t *testing.T) {
+		cues := TranscriptCues("Welcome back.\n\nLast words.")
+		assert.Equal(t, []TranscriptCue{{Text: "Welcome back."}, {Text: "Last words."}}, cues)
+	})
+}
+
+func TestFindExplicitLanguage(t *testing.T) {
+	terms, err := ParseExplicitTerms([]string{"shit", "شرموط"})
+	require.NoError(t, err)
+
+	matches := FindExplicitLanguage([]TranscriptCue{
+		{Text: "Shitake mushrooms are not a match."},
+		{Text: "Oh SHIT, the whole segment about the tax reform of last year went missing again!"},
+		{Text: "يا الشرموط"},
+	}, terms)
+
+	require.Len(t, matches, 2)
+	assert.Equal(t, "shit", matches[0].Term)
+	assert.Equal(t, "Oh SHIT, the whole segment about the tax reform…", matches[0].Excerpt)
+	assert.Equal(t, "شرموط", matches[1].Term)
+	assert.Equal(t, 1, matches[1].Position)
+}
+
+func TestParseExplicitTerms(t *testing.T) {
+	terms, err := ParseExplicitTerms(nil)
+	require.NoError(t, err)
+	assert.True(t, terms["fuck"])
+
+	_, err = ParseExplicitTerms([]string{"two words"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Discovery listing limits
+const (
+	DefaultDiscoverLimit = 20
+	MaxDiscoverLimit     = 100
+	MaxFeaturedItems     = 50 // active editor's picks shown at once
+)
+
+// FeaturedItem is an editor's pick shown in its position while its schedule is active
+type FeaturedItem struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	MediaID   string     `json:"media_id" gorm:"not null;index"`
+	Position  int        `json:"position" gorm:"not null;default:0;index"` // lower comes first
+	StartsAt  *time.Time `json:"starts_at,omitempty"`                      // nil shows it right away
+	EndsAt    *time.Time `json:"ends_at,omitempty"`                        // nil shows it until removed
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for FeaturedItem
+func (FeaturedItem) TableName() string {
+	return "featured_items"
+}
+
+// IsActive returns true if the item is shown at now
+func (f *FeaturedItem) IsActive(now time.Time) bool {
+	if f.StartsAt != nil && now.Before(*f.StartsAt) {
+		return false
+	}
+	return f.EndsAt == nil || now.Before(*f.EndsAt)
+}
+
+// FeaturedItemRequest represents a request to create or replace an editor's pick
+type FeaturedItemRequest struct {
+	MediaID  string     `json:"media_id" binding:"required"`
+	Position int        `json:"position"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+// Validate validates the featured item request
+func (r *FeaturedItemRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if strings.TrimSpace(r.MediaID) == "" {
+		errs.Add("media_id", "is required")
+	}
+	if r.Position < 0 {
+		errs.Add("position", "must not be negative")
+	}
+	if r.StartsAt != nil && r.EndsAt != nil && !r.EndsAt.After(*r.StartsAt) {
+		errs.Add("ends_at", "must be after starts_at")
+	}
+
+	return errs
+}
+
+// ApplyTo replaces the fields of a featured item with the request
+func (r *FeaturedItemRequest) ApplyTo(item *FeaturedItem) {
+	item.MediaID = strings.TrimSpace(r.MediaID)
+	item.Position = r.Position
+	item.StartsAt = r.StartsAt
+	item.EndsAt = r.EndsAt
+}
+
+// DiscoverResponse lists media picked for discovery
+type DiscoverResponse struct {
+	Media      []*Media `json:"media"`
+	NextCursor string   `json:"next_cursor,omitempty"` // pass as cursor to get the next page
+}
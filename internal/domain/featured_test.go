@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeaturedItem_IsActive(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		item   FeaturedItem
+		active bool
+	}{
+		{"unscheduled", FeaturedItem{}, true},
+		{"started", FeaturedItem{StartsAt: &before}, true},
+		{"not started", FeaturedItem{StartsAt: &after}, false},
+		{"ending later", FeaturedItem{StartsAt: &before, EndsAt: &after}, true},
+		{"ended", FeaturedItem{EndsAt: &now}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.active, tt.item.IsActive(now))
+		})
+	}
+}
+
+func TestFeaturedItemRequest_Validate(t *testing.T) {
+	startsAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		request FeaturedItemRequest
+		fields  []string
+	}{
+		{"valid", FeaturedItemRequest{MediaID: "m1", Position: 2, StartsAt: &startsAt, EndsAt: &endsAt}, nil},
+		{"missing media", FeaturedItemRequest{MediaID: " "}, []string{"media_id"}},
+		{"negative position", FeaturedItemRequest{MediaID: "m1", Position: -1}, []string{"position"}},
+		{"ends before start", FeaturedItemRequest{MediaID: "m1", StartsAt: &endsAt, EndsAt: &startsAt}, []string{"ends_at"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, err := range tt.request.Validate() {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
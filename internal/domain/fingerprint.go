@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultFingerprintThreshold is the fingerprint similarity from which media is
+// linked to the media it is a near-duplicate of. Unrelated audio scores about 0.5.
+const DefaultFingerprintThreshold = 0.85
+
+// FingerprintDurationTolerance is the share of its duration a near-duplicate may be
+// longer or shorter by, covering trimmed intros and outros
+const FingerprintDurationTolerance = 0.1
+
+// MediaFingerprint is the acoustic fingerprint of the audio of a media item,
+// computed during processing to detect re-encoded copies
+type MediaFingerprint struct {
+	MediaID     string    `json:"media_id" gorm:"primaryKey"`
+	TenantID    string    `json:"tenant_id" gorm:"type:varchar(100);not null;index"`
+	Duration    int       `json:"duration" gorm:"not null;index"` // seconds of audio fingerprinted
+	Fingerprint []uint32  `json:"-" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for MediaFingerprint
+func (MediaFingerprint) TableName() string {
+	return "media_fingerprints"
+}
+
+// DurationRange returns the durations of audio the fingerprint may be a near-duplicate of
+func (f *MediaFingerprint) DurationRange() (int, int) {
+	slack := int(math.Ceil(float64(f.Duration) * FingerprintDurationTolerance))
+	return max(0, f.Duration-slack), f.Duration + slack
+}
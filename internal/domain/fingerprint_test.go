@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaFingerprint_DurationRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration int
+		min      int
+		max      int
+	}{
+		{name: "episode", duration: 1800, min: 1620, max: 1980},
+		{name: "rounds the slack up", duration: 25, min: 22, max: 28},
+		{name: "empty audio", duration: 0, min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fingerprint := &MediaFingerprint{Duration: tt.duration}
+			minDuration, maxDuration := fingerprint.DurationRange()
+			assert.Equal(t, tt.min, minDuration)
+			assert.Equal(t, tt.max, maxDuration)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// countryCodePattern matches ISO 3166-1 alpha-2 country codes
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// GeoRestriction limits the countries media can be played in.
+// Blocked countries always win; a non-empty allow list blocks every other country.
+type GeoRestriction struct {
+	AllowedCountries []string `json:"allowed_countries,omitempty" gorm:"serializer:json;type:jsonb"`
+	BlockedCountries []string `json:"blocked_countries,omitempty" gorm:"serializer:json;type:jsonb"`
+}
+
+// IsRestricted returns true if any country rule is set
+func (g GeoRestriction) IsRestricted() bool {
+	return len(g.AllowedCountries) > 0 || len(g.BlockedCountries) > 0
+}
+
+// Allows reports whether media can be played from country. An unknown country
+// is only allowed when there is no allow list.
+func (g GeoRestriction) Allows(country string) bool {
+	country = strings.ToUpper(country)
+
+	for _, blocked := range g.BlockedCountries {
+		if blocked == country {
+			return false
+		}
+	}
+	if len(g.AllowedCountries) == 0 {
+		return true
+	}
+	for _, allowed := range g.AllowedCountries {
+		if allowed == country {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize upper-cases and de-duplicates the country codes
+func (g *GeoRestriction) Normalize() {
+	g.AllowedCountries = normalizeCountries(g.AllowedCountries)
+	g.BlockedCountries = normalizeCountries(g.BlockedCountries)
+}
+
+// Validate validates the country codes
+func (g GeoRestriction) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	allowed := make(map[string]bool)
+	for _, country := range g.AllowedCountries {
+		if !countryCodePattern.MatchString(strings.ToUpper(country)) {
+			errs.Add("allowed_countries", "must be ISO 3166-1 alpha-2 country codes, got "+country)
+		}
+		allowed[strings.ToUpper(country)] = true
+	}
+	for _, country := range g.BlockedCountries {
+		if !countryCodePattern.MatchString(strings.ToUpper(country)) {
+			errs.Add("blocked_countries", "must be ISO 3166-1 alpha-2 country codes, got "+country)
+		}
+		if allowed[strings.ToUpper(country)] {
+			errs.Add("blocked_countries", country+" cannot be both allowed and blocked")
+		}
+	}
+
+	return errs
+}
+
+// normalizeCountries upper-cases and de-duplicates country codes, keeping their order
+func normalizeCountries(countries []string) []string {
+	if len(countries) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(countries))
+	for _, country := range countries {
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if country != "" && !seen[country] {
+			seen[country] = true
+			result = append(result, country)
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoRestriction_Allows(t *testing.T) {
+	tests := []struct {
+		name        string
+		restriction GeoRestriction
+		country     string
+		expected    bool
+	}{
+		{name: "no rules", restriction: GeoRestriction{}, country: "SA", expected: true},
+		{name: "no rules, unknown country", restriction: GeoRestriction{}, country: "", expected: true},
+		{name: "allowed country", restriction: GeoRestriction{AllowedCountries: []string{"SA", "AE"}}, country: "AE", expected: true},
+		{name: "country outside allow list", restriction: GeoRestriction{AllowedCountries: []string{"SA"}}, country: "US", expected: false},
+		{name: "unknown country with allow list", restriction: GeoRestriction{AllowedCountries: []string{"SA"}}, country: "", expected: false},
+		{name: "blocked country", restriction: GeoRestriction{BlockedCountries: []string{"US"}}, country: "us", expected: false},
+		{name: "unknown country with block list", restriction: GeoRestriction{BlockedCountries: []string{"US"}}, country: "", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := tt.restriction.Allows(tt.country)
+
+			// Then
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGeoRestriction_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		restriction   GeoRestriction
+		expectedError bool
+	}{
+		{name: "valid codes", restriction: GeoRestriction{AllowedCountries: []string{"sa", "AE"}, BlockedCountries: []string{"US"}}},
+		{name: "invalid code", restriction: GeoRestriction{AllowedCountries: []string{"Saudi"}}, expectedError: true},
+		{name: "allowed and blocked", restriction: GeoRestriction{AllowedCountries: []string{"SA"}, BlockedCountries: []string{"sa"}}, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			errs := tt.restriction.Validate()
+
+			// Then
+			assert.Equal(t, tt.expectedError, errs.HasErrors())
+		})
+	}
+}
+
+func TestMedia_CheckPlayback(t *testing.T) {
+	// Given
+	media := &Media{ID: "media-123", GeoRestriction: GeoRestriction{BlockedCountries: []string{"US"}}}
+
+	// When
+	err := media.CheckPlayback(Viewer{Country: "US"})
+
+	// Then
+	var geoErr *GeoRestrictionError
+	assert.True(t, errors.As(err, &geoErr))
+	assert.Equal(t, "US", geoErr.Country)
+	assert.ErrorIs(t, err, ErrGeoRestricted)
+	assert.NoError(t, media.CheckPlayback(Viewer{Country: "US", IsAdmin: true}))
+	assert.NoError(t, media.CheckPlayback(Viewer{Country: "SA"}))
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// PlayEvent records that a user started playing a media item
+type PlayEvent struct {
+	ID       string    `json:"id" gorm:"primaryKey"`
+	UserID   string    `json:"user_id" gorm:"not null;index:idx_play_events_user_played,priority:1"`
+	MediaID  string    `json:"media_id" gorm:"not null"`
+	Type     MediaType `json:"type" gorm:"type:varchar(20)"` // type of the media when played, for filtering
+	PlayedAt time.Time `json:"played_at" gorm:"not null;index:idx_play_events_user_played,priority:2"`
+}
+
+// TableName specifies the table name for PlayEvent
+func (PlayEvent) TableName() string {
+	return "play_events"
+}
+
+// PlayRequest represents a player reporting the start of playback
+type PlayRequest struct {
+	MediaID string `json:"media_id" binding:"required"`
+}
+
+// HistoryFilter narrows the listening history of a user
+type HistoryFilter struct {
+	Type MediaType
+	From *time.Time // inclusive
+	End  *time.Time // exclusive
+}
+
+// HistoryQuery represents the filters of a history request
+type HistoryQuery struct {
+	Type string `form:"type"` // video, podcast, or empty for all
+	From string `form:"from"` // first day, like 2025-06-01
+	To   string `form:"to"`   // last day, like 2025-06-30
+}
+
+// ToFilter validates the query and converts it to a history filter.
+// Days are UTC days and to includes the whole day.
+func (q *HistoryQuery) ToFilter() (*HistoryFilter, ValidationErrors) {
+	var errs ValidationErrors
+	filter := &HistoryFilter{}
+
+	switch mediaType := MediaType(strings.ToLower(strings.TrimSpace(q.Type))); mediaType {
+	case "":
+	case TypeVideo, TypePodcast:
+		filter.Type = mediaType
+	default:
+		errs.Add("type", "must be video or podcast")
+	}
+
+	if q.From != "" {
+		from, err := time.Parse(CalendarDateLayout, q.From)
+		if err != nil {
+			errs.Add("from", "must be a date like 2025-06-01")
+		} else {
+			filter.From = &from
+		}
+	}
+	if q.To != "" {
+		to, err := time.Parse(CalendarDateLayout, q.To)
+		if err != nil {
+			errs.Add("to", "must be a date like 2025-06-30")
+		} else {
+			end := to.AddDate(0, 0, 1)
+			filter.End = &end
+		}
+	}
+	if filter.From != nil && filter.End != nil && !filter.End.After(*filter.From) {
+		errs.Add("to", "must not be before from")
+	}
+
+	return filter, errs
+}
+
+// HistoryEntry is a play of the user's listening history
+type HistoryEntry struct {
+	ID       string    `json:"id"`
+	MediaID  string    `json:"media_id"`
+	Media    *Media    `json:"media,omitempty"` // omitted once the media is no longer available
+	PlayedAt time.Time `json:"played_at"`
+}
+
+// HistoryResponse lists the plays of a user, most recent first
+type HistoryResponse struct {
+	Items      []*HistoryEntry `json:"items"`
+	Total      int64           `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"` // pass as cursor to get the next page
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryQuery_ToFilter(t *testing.T) {
+	// Given a query for the podcasts played in June
+	query := HistoryQuery{Type: "Podcast", From: "2025-06-01", To: "2025-06-30"}
+
+	// When
+	filter, errs := query.ToFilter()
+
+	// Then the last day is included
+	require.False(t, errs.HasErrors())
+	assert.Equal(t, TypePodcast, filter.Type)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), *filter.End)
+}
+
+func TestHistoryQuery_ToFilterInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		field string
+	}{
+		{"unknown type", HistoryQuery{Type: "movie"}, "type"},
+		{"malformed from", HistoryQuery{From: "June"}, "from"},
+		{"malformed to", HistoryQuery{To: "2025-06-31"}, "to"},
+		{"to before from", HistoryQuery{From: "2025-06-02", To: "2025-06-01"}, "to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := tt.query.ToFilter()
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.field, errs[0].Field)
+		})
+	}
+
+	filter, errs := (&HistoryQuery{}).ToFilter()
+	assert.False(t, errs.HasErrors())
+	assert.Equal(t, &HistoryFilter{}, filter)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Limits of the editorial labels and category of media
+const (
+	MaxLabels         = 20
+	MaxLabelLength    = 50
+	MaxCategoryLength = 100
+)
+
+// NormalizeLabels trims, lower-cases and de-duplicates labels, dropping empty
+// ones. The order of first occurrence is kept; nil stays nil.
+func NormalizeLabels(labels []string) []string {
+	if labels == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	return normalized
+}
+
+// ValidateLabels checks the number and length of labels
+func ValidateLabels(field string, labels []string) ValidationErrors {
+	var errs ValidationErrors
+	if len(labels) > MaxLabels {
+		errs.Add(field, fmt.Sprintf("must have at most %d labels", MaxLabels))
+	}
+	for _, label := range labels {
+		if len(label) > MaxLabelLength {
+			errs.Add(field, fmt.Sprintf("label %q is longer than %d characters", label, MaxLabelLength))
+		}
+	}
+	return errs
+}
+
+// ValidateCategory checks the length of a category
+func ValidateCategory(field, category string) ValidationErrors {
+	var errs ValidationErrors
+	if len(category) > MaxCategoryLength {
+		errs.Add(field, fmt.Sprintf("must be at most %d characters", MaxCategoryLength))
+	}
+	return errs
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// License describes the rights window media may be published in
+type License struct {
+	Source   string     `json:"source,omitempty"`               // licensor or rights holder
+	StartsAt *time.Time `json:"starts_at,omitempty"`            // nil when licensed from upload
+	EndsAt   *time.Time `json:"ends_at,omitempty" gorm:"index"` // nil for perpetual rights
+
+	// ExpiryNotifiedAt records when owners were warned about the end of the window
+	ExpiryNotifiedAt *time.Time `json:"expiry_notified_at,omitempty"`
+}
+
+// IsExpired returns true if the license window has ended at now
+func (l License) IsExpired(now time.Time) bool {
+	return l.EndsAt != nil && !now.Before(*l.EndsAt)
+}
+
+// Validate validates the license window
+func (l License) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if l.StartsAt != nil && l.EndsAt != nil && !l.EndsAt.After(*l.StartsAt) {
+		errs.Add("ends_at", "must be after starts_at")
+	}
+
+	return errs
+}
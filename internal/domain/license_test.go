@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicense_IsExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.False(t, License{}.IsExpired(now))
+	assert.False(t, License{EndsAt: &future}.IsExpired(now))
+	assert.True(t, License{EndsAt: &past}.IsExpired(now))
+	assert.True(t, License{EndsAt: &now}.IsExpired(now))
+}
+
+func TestLicense_Validate(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	assert.False(t, License{}.Validate().HasErrors())
+	assert.False(t, License{Source: "Studio", StartsAt: &start, EndsAt: &end}.Validate().HasErrors())
+	assert.False(t, License{EndsAt: &end}.Validate().HasErrors())
+	assert.True(t, License{StartsAt: &end, EndsAt: &start}.Validate().HasErrors())
+	assert.True(t, License{StartsAt: &start, EndsAt: &start}.Validate().HasErrors())
+}
+
+func TestUpdateMediaRequest_License_ResetsExpiryNotice(t *testing.T) {
+	// Given
+	notified := time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC)
+	oldEnd := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	newEnd := oldEnd.AddDate(1, 0, 0)
+	media := &Media{ID: "123", License: License{Source: "Studio", EndsAt: &oldEnd, ExpiryNotifiedAt: &notified}}
+
+	// When
+	request := &UpdateMediaRequest{License: &License{Source: "Studio", EndsAt: &newEnd}}
+	request.ApplyTo(media)
+
+	// Then
+	assert.True(t, request.IsValid())
+	assert.Equal(t, &newEnd, media.License.EndsAt)
+	assert.Nil(t, media.License.ExpiryNotifiedAt)
+}
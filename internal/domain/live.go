@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// LiveStatus is the state of a live stream
+type LiveStatus string
+
+const (
+	LiveScheduled LiveStatus = "scheduled" // registered, waiting for the encoder
+	LiveOnAir     LiveStatus = "live"      // the encoder is publishing
+	LiveEnded     LiveStatus = "ended"     // the encoder stopped without a recording to archive
+	LiveArchived  LiveStatus = "archived"  // the recording was handed over to processing as VOD media
+)
+
+// LiveStream is a live broadcast of a media item of type live. Encoders publish
+// to the ingest server with the stream key, which calls back when publishing
+// starts and ends. Only the SHA-256 hash of the stream key is stored, the key
+// itself is returned once when the stream is registered.
+type LiveStream struct {
+	MediaID       string     `json:"media_id" gorm:"primaryKey"`
+	Status        LiveStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	StreamKeyHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ArchiveType   MediaType  `json:"archive_type" gorm:"type:varchar(20);not null"` // type of the VOD media the recording becomes
+	ScheduledAt   *time.Time `json:"scheduled_at,omitempty"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	RecordingKey  string     `json:"recording_key,omitempty"` // storage key of the recording handed over by the ingest server
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for LiveStream
+func (LiveStream) TableName() string {
+	return "live_streams"
+}
+
+// HashStreamKey returns the stored representation of a stream key
+func HashStreamKey(key string) string {
+	return HashAPIKey(key)
+}
+
+// LiveStreamRequest represents a request to register an upcoming live stream
+type LiveStreamRequest struct {
+	Title       string          `json:"title" binding:"required"`
+	Description string          `json:"description"`
+	ScheduledAt *time.Time      `json:"scheduled_at,omitempty"`
+	ShowID      string          `json:"show_id,omitempty"`
+	Visibility  MediaVisibility `json:"visibility,omitempty"` // defaults to public
+
+	// ArchiveType is the type of the media the recording becomes, video by default
+	ArchiveType MediaType `json:"archive_type,omitempty"`
+
+	// OwnerID is the signed in user registering the stream, never read from the request body
+	OwnerID string `json:"-"`
+}
+
+// Normalize trims the request and applies defaults
+func (r *LiveStreamRequest) Normalize() {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Description = strings.TrimSpace(r.Description)
+	r.ShowID = strings.TrimSpace(r.ShowID)
+	if r.Visibility == "" {
+		r.Visibility = VisibilityPublic
+	}
+	if r.ArchiveType == "" {
+		r.ArchiveType = TypeVideo
+	}
+}
+
+// Validate validates the live stream request
+func (r *LiveStreamRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if r.Title == "" {
+		errs.Add("title", "is required")
+	}
+	if !r.Visibility.IsValid() {
+		errs.Add("visibility", "must be public, unlisted or private")
+	}
+	if r.ArchiveType != TypeVideo && r.ArchiveType != TypePodcast {
+		errs.Add("archive_type", "must be video or podcast")
+	}
+
+	return errs
+}
+
+// ToMedia creates the live media item of the stream
+func (r *LiveStreamRequest) ToMedia(id string) *Media {
+	now := time.Now()
+	return &Media{
+		ID:          id,
+		Title:       r.Title,
+		Description: r.Description,
+		Type:        TypeLive,
+		Status:      StatusUploading, // until the recording is handed over to processing
+		Priority:    PriorityNormal,
+		Visibility:  r.Visibility,
+		AccessTier:  AccessTierFree,
+		TenantID:    DefaultTenantID,
+		OwnerID:     r.OwnerID,
+		ShowID:      r.ShowID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// RegisteredLiveStream is a newly registered live stream with the ingest
+// settings of the encoder
+type RegisteredLiveStream struct {
+	*LiveStream
+	Media     *Media `json:"media"`
+	IngestURL string `json:"ingest_url"`
+	StreamKey string `json:"stream_key"` // only returned once
+}
+
+// LiveIngestCallback is a call of the ingest server when the encoder of a stream
+// starts or stops publishing, in the form fields of nginx-rtmp notifications or JSON
+type LiveIngestCallback struct {
+	StreamKey string `json:"stream_key" form:"name" binding:"required"`
+
+	// Recording is the storage key of the recording of the stream, when ended
+	Recording string `json:"recording" form:"recording"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// MaintenanceStatus describes the maintenance mode in effect. It is stored in
+// a single row so every replica of the service applies the same mode.
+type MaintenanceStatus struct {
+	ID       uint       `json:"-" gorm:"primaryKey"`
+	ReadOnly bool       `json:"read_only" gorm:"not null;default:false"`
+	Reason   string     `json:"reason,omitempty"`
+	Since    *time.Time `json:"since,omitempty"` // when read-only mode was switched on
+}
+
+// TableName specifies the table name for MaintenanceStatus
+func (MaintenanceStatus) TableName() string {
+	return "maintenance_status"
+}
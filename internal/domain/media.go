@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"path"
+	"strings"
 	"time"
 )
 
@@ -8,20 +10,103 @@ import (
 type MediaStatus string
 
 const (
-	StatusUploading MediaStatus = "uploading"
-	StatusReady     MediaStatus = "ready"
-	StatusFailed    MediaStatus = "failed"
-	StatusDeleted   MediaStatus = "deleted"
+	StatusUploading  MediaStatus = "uploading"
+	StatusProcessing MediaStatus = "processing"
+	StatusReady      MediaStatus = "ready"
+	StatusFailed     MediaStatus = "failed"
+	StatusDeleted    MediaStatus = "deleted"
 )
 
+// statusTransitions lists the statuses reachable from each status. Media can
+// be deleted whatever its status, as before transitions were enforced, so
+// abandoned uploads, media stuck in processing and failed items can be cleared.
+// Deleted is final.
+var statusTransitions = map[MediaStatus][]MediaStatus{
+	StatusUploading:  {StatusProcessing, StatusFailed, StatusDeleted},
+	StatusProcessing: {StatusReady, StatusFailed, StatusDeleted},
+	StatusReady:      {StatusDeleted},
+	StatusFailed:     {StatusProcessing, StatusDeleted},
+}
+
+// CanTransitionTo returns true if moving from this status to next is allowed
+func (s MediaStatus) CanTransitionTo(next MediaStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMediaStatus converts a raw string into a known MediaStatus
+func ParseMediaStatus(value string) (MediaStatus, bool) {
+	switch status := MediaStatus(value); status {
+	case StatusUploading, StatusProcessing, StatusReady, StatusFailed, StatusDeleted:
+		return status, true
+	default:
+		return "", false
+	}
+}
+
 // MediaType represents the type of media content
 type MediaType string
 
 const (
 	TypeVideo   MediaType = "video"
 	TypePodcast MediaType = "podcast"
+	TypeLive    MediaType = "live" // a live stream, converted to video or podcast once its recording is archived
+)
+
+// MediaPriority selects the processing lane of a media file
+type MediaPriority string
+
+const (
+	PriorityNormal MediaPriority = "normal"
+	PriorityHigh   MediaPriority = "high" // e.g. breaking-news episodes
+)
+
+// IsValid returns true for known priorities
+func (p MediaPriority) IsValid() bool {
+	return p == PriorityNormal || p == PriorityHigh
+}
+
+// MediaVisibility controls who can see a media file
+type MediaVisibility string
+
+const (
+	VisibilityPublic   MediaVisibility = "public"   // listed, searchable and viewable by anyone
+	VisibilityUnlisted MediaVisibility = "unlisted" // viewable by anyone with the link, never listed
+	VisibilityPrivate  MediaVisibility = "private"  // viewable by admins only
+)
+
+// IsValid returns true for known visibility levels
+func (v MediaVisibility) IsValid() bool {
+	return v == VisibilityPublic || v == VisibilityUnlisted || v == VisibilityPrivate
+}
+
+// MediaAccessTier separates free media from premium media
+type MediaAccessTier string
+
+const (
+	AccessTierFree    MediaAccessTier = "free"
+	AccessTierPremium MediaAccessTier = "premium" // premium video is DRM protected
 )
 
+// IsValid returns true for known access tiers
+func (t MediaAccessTier) IsValid() bool {
+	return t == AccessTierFree || t == AccessTierPremium
+}
+
+// Viewer describes who is accessing media
+type Viewer struct {
+	IsAdmin       bool
+	Role          Role
+	SharedMediaID string // media granted through a share link token
+	Country       string // ISO 3166-1 alpha-2, empty when unknown
+	UserID        string // signed in user, empty for anonymous viewers
+	Tier          SubscriptionTier
+}
+
 // Media represents a media file entity
 type Media struct {
 	ID          string      `json:"id" gorm:"primaryKey"`
@@ -29,13 +114,115 @@ type Media struct {
 	Description string      `json:"description"`
 	FilePath    string      `json:"file_path"`
 	FileSize    int64       `json:"file_size"`
-	Duration    int         `json:"duration"` // in seconds
-	Format      string      `json:"format"`   // mp4, mp3, etc
+	Duration    int         `json:"duration"`                                             // in seconds
+	Format      string      `json:"format"`                                               // mp4, mp3, etc
+	ContentHash string      `json:"content_hash,omitempty" gorm:"type:varchar(64);index"` // hex SHA-256 declared at upload
 	Type        MediaType   `json:"type" gorm:"type:varchar(20)"`
 	Status      MediaStatus `json:"status" gorm:"type:varchar(20)"`
 	CreatedAt   time.Time   `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt   *time.Time  `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Technical metadata probed from the uploaded file during processing
+	Width         int    `json:"width,omitempty"`
+	Height        int    `json:"height,omitempty"`
+	Bitrate       int64  `json:"bitrate,omitempty"` // bits per second
+	VideoCodec    string `json:"video_codec,omitempty" gorm:"type:varchar(50)"`
+	AudioCodec    string `json:"audio_codec,omitempty" gorm:"type:varchar(50)"`
+	AudioChannels int    `json:"audio_channels,omitempty"`
+
+	// Language of the uploaded audio, and the dubbed tracks listeners may switch to
+	AudioLanguage string       `json:"audio_language,omitempty" gorm:"type:varchar(10)"`
+	AudioTracks   []AudioTrack `json:"audio_tracks,omitempty" gorm:"serializer:json;type:jsonb"`
+
+	// Media this is a re-encoded copy of, detected from the audio fingerprint during processing
+	DuplicateOfID string `json:"duplicate_of_id,omitempty" gorm:"index"`
+
+	// Processing bookkeeping
+	ProcessingAttempts int    `json:"processing_attempts" gorm:"not null;default:0"`
+	FailureCode        string `json:"failure_code,omitempty" gorm:"type:varchar(50);index"`
+	FailureMessage     string `json:"failure_message,omitempty"`
+
+	// Processing lane and transcoding preset (empty uses the default preset of the media type)
+	Priority          MediaPriority `json:"priority" gorm:"type:varchar(10);not null;default:'normal'"`
+	TranscodePresetID string        `json:"transcode_preset_id,omitempty" gorm:"index"`
+
+	// Access control
+	Visibility MediaVisibility `json:"visibility" gorm:"type:varchar(20);not null;default:'public';index"`
+	AccessTier MediaAccessTier `json:"access_tier" gorm:"type:varchar(20);not null;default:'free'"`
+
+	// Countries the media can be played in
+	GeoRestriction GeoRestriction `json:"geo_restriction" gorm:"embedded"`
+
+	// Age rating and explicit content advisory
+	ContentRating ContentRating `json:"content_rating" gorm:"embedded"`
+
+	// Rights window, published media is unpublished once it ends
+	License License `json:"license" gorm:"embedded;embeddedPrefix:license_"`
+
+	// End of the publishing window, published media returns to private once it passes
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty" gorm:"index"`
+
+	// Scheduled first playback, refused to viewers until it starts
+	Premiere Premiere `json:"premiere" gorm:"embedded;embeddedPrefix:premiere_"`
+
+	// Frame of a video picked as its poster
+	Poster Poster `json:"poster" gorm:"embedded;embeddedPrefix:poster_"`
+
+	// Ownership and grouping
+	TenantID string `json:"tenant_id" gorm:"type:varchar(100);not null;default:'default';index"`
+	OwnerID  string `json:"owner_id,omitempty" gorm:"index"` // user who may edit the media without the editor role
+	ShowID   string `json:"show_id,omitempty" gorm:"index"`
+
+	// Series the media is a numbered part of, detected from the title unless locked by an editor
+	SeriesID     string `json:"series_id,omitempty" gorm:"type:varchar(100);index"`
+	SeriesPart   int    `json:"series_part,omitempty"`
+	SeriesLocked bool   `json:"series_locked,omitempty" gorm:"not null;default:false"`
+
+	// Editorial labels and category, defaulted from the show template on upload
+	Labels   []string `json:"labels,omitempty" gorm:"serializer:json;type:jsonb"`
+	Category string   `json:"category,omitempty" gorm:"type:varchar(100);index"`
+
+	// Ad breaks in playback order, filled by an ad server at playback time
+	CuePoints []CuePoint `json:"cue_points,omitempty" gorm:"serializer:json;type:jsonb"`
+
+	// Chapters by start time, and the boundaries suggested for them during processing
+	Chapters           []Chapter           `json:"chapters,omitempty" gorm:"serializer:json;type:jsonb"`
+	ChapterSuggestions []ChapterSuggestion `json:"chapter_suggestions,omitempty" gorm:"serializer:json;type:jsonb"`
+
+	// Tags embedded in the uploaded file, extracted during processing
+	Tags MediaTags `json:"tags" gorm:"embedded;embeddedPrefix:tag_"`
+
+	// Show context resolved when the media is indexed for search, never stored
+	SearchContext *SearchContext `json:"-" gorm:"-"`
+}
+
+// Failure codes recorded on media that ended up in failed state
+const (
+	FailureUploadValidation   = "UPLOAD_VALIDATION_FAILED"
+	FailureMetadataExtraction = "METADATA_EXTRACTION_FAILED"
+	FailureProcessingAttempts = "PROCESSING_ATTEMPTS_EXHAUSTED" // processing kept failing for transient reasons
+)
+
+// MediaFilter narrows media listings
+type MediaFilter struct {
+	Status      MediaStatus
+	FailureCode string
+	Visibility  MediaVisibility
+	Safe        bool // excludes explicit content
+	ShowID      string
+	Category    string
+}
+
+// ValidateUnpublishAt checks that a scheduled unpublish lies after now
+func ValidateUnpublishAt(unpublishAt *time.Time, now time.Time) ValidationErrors {
+	var errs ValidationErrors
+
+	if unpublishAt != nil && !unpublishAt.After(now) {
+		errs.Add("unpublish_at", "must be in the future")
+	}
+
+	return errs
 }
 
 // TableName specifies the table name for Media
@@ -55,7 +242,109 @@ func (m *Media) IsProcessed() bool {
 
 // CanBeSearched returns true if the media can appear in search results
 func (m *Media) CanBeSearched() bool {
-	return m.Status == StatusReady
+	return m.Status == StatusReady && m.IsPublic()
+}
+
+// StorageKey returns the key the uploaded file is stored under
+func (m *Media) StorageKey() string {
+	return strings.TrimPrefix(m.FilePath, UploadPathPrefix)
+}
+
+// StoredKeys returns the keys of the uploaded file and of every asset that may
+// have been derived from it. Derived audio is only stored once requested.
+func (m *Media) StoredKeys() []string {
+	var keys []string
+	if key := m.StorageKey(); key != "" {
+		keys = append(keys, key)
+	}
+	if m.Tags.ArtworkKey != "" {
+		keys = append(keys, m.Tags.ArtworkKey)
+	}
+	if m.Poster.IsSet() {
+		keys = append(keys, m.Poster.Key)
+	}
+	for _, format := range []AudioFormat{AudioFormatAAC, AudioFormatOpus} {
+		keys = append(keys, DerivedAudioKey(m.ID, format))
+	}
+	for _, track := range m.AudioTracks {
+		keys = append(keys, track.Key)
+	}
+	return keys
+}
+
+// StoredKeyPrefixes returns the prefixes of the assets of the media stored as
+// many objects, like the files and HLS segments of its renditions
+func (m *Media) StoredKeyPrefixes() []string {
+	return []string{RenditionKeyPrefix(m.ID)}
+}
+
+// MediaIDOfStorageKey returns the ID of the media a stored object belongs to:
+// uploads are stored as the media ID with the file extension and derived assets
+// under derived/<media ID>/. Keys of any other layout return "".
+func MediaIDOfStorageKey(key string) string {
+	if rest, ok := strings.CutPrefix(key, DerivedKeyPrefix); ok {
+		mediaID, _, _ := strings.Cut(rest, "/")
+		return mediaID
+	}
+	if strings.Contains(key, "/") {
+		return ""
+	}
+	return strings.TrimSuffix(key, path.Ext(key))
+}
+
+// IsPublic returns true if the media is publicly visible.
+// Records created before visibility existed are public.
+func (m *Media) IsPublic() bool {
+	return m.Visibility == "" || m.Visibility == VisibilityPublic
+}
+
+// IsVisibleTo returns true if the viewer may access the media directly:
+// admins, owners and holders of a share link see private media too
+func (m *Media) IsVisibleTo(viewer Viewer) bool {
+	if viewer.IsAdmin || (viewer.SharedMediaID != "" && viewer.SharedMediaID == m.ID) {
+		return true
+	}
+	if m.OwnerID != "" && viewer.UserID == m.OwnerID {
+		return true
+	}
+	return m.Visibility != VisibilityPrivate
+}
+
+// OwnerMe stands for the signed in user in owner filters of media listings
+const OwnerMe = "me"
+
+// IsEditableBy returns true if the viewer may edit the metadata of the media:
+// editors and admins edit any media, other users the media they own
+func (m *Media) IsEditableBy(viewer Viewer) bool {
+	if viewer.Role.Includes(RoleEditor) {
+		return true
+	}
+	return viewer.UserID != "" && viewer.UserID == m.OwnerID
+}
+
+// CheckPlayback returns a GeoRestrictionError when the viewer's country may not play the media,
+// an EntitlementError when the viewer's subscription does not include it and a
+// PremiereNotStartedError before its premiere starts.
+// Admins are neither geo-restricted, asked for a subscription nor kept waiting for premieres.
+func (m *Media) CheckPlayback(viewer Viewer) error {
+	if viewer.IsAdmin {
+		return nil
+	}
+	if !m.GeoRestriction.Allows(viewer.Country) {
+		return &GeoRestrictionError{MediaID: m.ID, Country: viewer.Country}
+	}
+	if !viewer.Tier.Includes(m.AccessTier) {
+		return &EntitlementError{MediaID: m.ID, RequiredTier: m.AccessTier}
+	}
+	if m.Premiere.IsUpcoming(time.Now()) {
+		return &PremiereNotStartedError{MediaID: m.ID, StartsAt: m.Premiere.StartsAt.UTC()}
+	}
+	return nil
+}
+
+// RequiresDRM returns true if the renditions of the media are DRM protected
+func (m *Media) RequiresDRM() bool {
+	return m.Type == TypeVideo && m.AccessTier == AccessTierPremium
 }
 
 // UpdateStatus updates the media status and timestamp
@@ -63,3 +352,23 @@ func (m *Media) UpdateStatus(status MediaStatus) {
 	m.Status = status
 	m.UpdatedAt = time.Now()
 }
+
+// SetFailure records why the media failed
+func (m *Media) SetFailure(code, message string) {
+	m.FailureCode = code
+	m.FailureMessage = message
+}
+
+// ClearFailure removes a previously recorded failure reason
+func (m *Media) ClearFailure() {
+	m.SetFailure("", "")
+}
+
+// TransitionTo moves the media to the next status if the transition is allowed
+func (m *Media) TransitionTo(next MediaStatus) error {
+	if !m.Status.CanTransitionTo(next) {
+		return &StatusTransitionError{From: m.Status, To: next}
+	}
+	m.UpdateStatus(next)
+	return nil
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// recordingDateLayouts are the ISO 8601 precisions allowed in ID3 recording dates
+var recordingDateLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// MediaTags holds the metadata embedded in an uploaded file, such as the ID3 tags of a podcast
+type MediaTags struct {
+	Artist     string     `json:"artist,omitempty"`
+	Album      string     `json:"album,omitempty"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+	ArtworkKey string     `json:"artwork_key,omitempty"` // storage key of the extracted episode art
+}
+
+// HasArtwork returns true if episode art was extracted
+func (t MediaTags) HasArtwork() bool {
+	return t.ArtworkKey != ""
+}
+
+// Merge fills the empty fields with the values from extracted tags,
+// so corrections made by editors survive reprocessing
+func (t *MediaTags) Merge(extracted MediaTags) {
+	if t.Artist == "" {
+		t.Artist = extracted.Artist
+	}
+	if t.Album == "" {
+		t.Album = extracted.Album
+	}
+	if t.RecordedAt == nil {
+		t.RecordedAt = extracted.RecordedAt
+	}
+	if t.ArtworkKey == "" {
+		t.ArtworkKey = extracted.ArtworkKey
+	}
+}
+
+// ParseRecordingDate parses an ID3 recording date, returning nil when it is not a valid date
+func ParseRecordingDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range recordingDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
+// FormatRecordingDate formats a recording date for an ID3 tag
+func FormatRecordingDate(recordedAt *time.Time) string {
+	if recordedAt == nil {
+		return ""
+	}
+	return recordedAt.UTC().Format("2006-01-02")
+}
+
+// DerivedArtworkKey returns the storage key of the episode art extracted from a media item
+func DerivedArtworkKey(mediaID, mimeType string) string {
+	ext := ".jpg"
+	switch strings.ToLower(mimeType) {
+	case "image/png":
+		ext = ".png"
+	case "image/webp":
+		ext = ".webp"
+	case "image/gif":
+		ext = ".gif"
+	}
+	return fmt.Sprintf("%s%s/artwork%s", DerivedKeyPrefix, mediaID, ext)
+}
+
+// FileAsset is a stored file ready to be streamed to the client, such as a download
+// or episode art. The caller must close Body.
+type FileAsset struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecordingDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected *time.Time
+	}{
+		{name: "full date", value: "2025-08-27", expected: timePtr(time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC))},
+		{name: "year only", value: "2024", expected: timePtr(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "date and time", value: "2025-08-27T14:30", expected: timePtr(time.Date(2025, 8, 27, 14, 30, 0, 0, time.UTC))},
+		{name: "invalid date", value: "last tuesday", expected: nil},
+		{name: "empty", value: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := ParseRecordingDate(tt.value)
+
+			// Then
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMediaTags_Merge(t *testing.T) {
+	// Given
+	recordedAt := time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC)
+	tags := MediaTags{Artist: "Corrected Artist"}
+
+	// When
+	tags.Merge(MediaTags{Artist: "Embedded Artist", Album: "Season 1", RecordedAt: &recordedAt})
+
+	// Then
+	assert.Equal(t, "Corrected Artist", tags.Artist)
+	assert.Equal(t, "Season 1", tags.Album)
+	assert.Equal(t, &recordedAt, tags.RecordedAt)
+	assert.Equal(t, "2025-08-27", FormatRecordingDate(tags.RecordedAt))
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
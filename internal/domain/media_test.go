@@ -109,10 +109,29 @@ func TestMedia_IsProcessed(t *testing.T) {
 
 func TestMedia_CanBeSearched(t *testing.T) {
 	tests := []struct {
-		name     string
-		status   MediaStatus
-		expected bool
+		name       string
+		status     MediaStatus
+		visibility MediaVisibility
+		expected   bool
 	}{
+		{
+			name:       "ready public",
+			status:     StatusReady,
+			visibility: VisibilityPublic,
+			expected:   true,
+		},
+		{
+			name:       "ready unlisted",
+			status:     StatusReady,
+			visibility: VisibilityUnlisted,
+			expected:   false,
+		},
+		{
+			name:       "ready private",
+			status:     StatusReady,
+			visibility: VisibilityPrivate,
+			expected:   false,
+		},
 		{
 			name:     "ready status",
 			status:   StatusReady,
@@ -137,13 +156,78 @@ func TestMedia_CanBeSearched(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			media := Media{Status: tt.status}
+			media := Media{Status: tt.status, Visibility: tt.visibility}
 			result := media.CanBeSearched()
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+func TestMedia_IsVisibleTo(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility MediaVisibility
+		viewer     Viewer
+		expected   bool
+	}{
+		{
+			name:       "public media for anonymous viewer",
+			visibility: VisibilityPublic,
+			viewer:     Viewer{},
+			expected:   true,
+		},
+		{
+			name:       "unlisted media for anonymous viewer",
+			visibility: VisibilityUnlisted,
+			viewer:     Viewer{},
+			expected:   true,
+		},
+		{
+			name:       "private media for anonymous viewer",
+			visibility: VisibilityPrivate,
+			viewer:     Viewer{},
+			expected:   false,
+		},
+		{
+			name:       "private media shared through a link",
+			visibility: VisibilityPrivate,
+			viewer:     Viewer{SharedMediaID: "media-123"},
+			expected:   true,
+		},
+		{
+			name:       "private media with a link for other media",
+			visibility: VisibilityPrivate,
+			viewer:     Viewer{SharedMediaID: "media-456"},
+			expected:   false,
+		},
+		{
+			name:       "private media for admin",
+			visibility: VisibilityPrivate,
+			viewer:     Viewer{IsAdmin: true},
+			expected:   true,
+		},
+		{
+			name:       "private media for its owner",
+			visibility: VisibilityPrivate,
+			viewer:     Viewer{UserID: "user-1"},
+			expected:   true,
+		},
+		{
+			name:       "private media for another user",
+			visibility: VisibilityPrivate,
+			viewer:     Viewer{UserID: "user-2"},
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			media := Media{ID: "media-123", Visibility: tt.visibility, OwnerID: "user-1"}
+			assert.Equal(t, tt.expected, media.IsVisibleTo(tt.viewer))
+		})
+	}
+}
+
 func TestMedia_UpdateStatus(t *testing.T) {
 	// Given
 	media := &Media{
@@ -181,7 +265,119 @@ func TestMediaType_Constants(t *testing.T) {
 func TestMediaStatus_Constants(t *testing.T) {
 	// Test that constants are defined correctly
 	assert.Equal(t, MediaStatus("uploading"), StatusUploading)
+	assert.Equal(t, MediaStatus("processing"), StatusProcessing)
 	assert.Equal(t, MediaStatus("ready"), StatusReady)
 	assert.Equal(t, MediaStatus("failed"), StatusFailed)
 	assert.Equal(t, MediaStatus("deleted"), StatusDeleted)
 }
+
+func TestParseMediaStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected MediaStatus
+		ok       bool
+	}{
+		{"uploading", "uploading", StatusUploading, true},
+		{"processing", "processing", StatusProcessing, true},
+		{"ready", "ready", StatusReady, true},
+		{"failed", "failed", StatusFailed, true},
+		{"deleted", "deleted", StatusDeleted, true},
+		{"unknown status", "archived", "", false},
+		{"empty status", "", "", false},
+		{"case sensitive - READY", "READY", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ok := ParseMediaStatus(tt.value)
+			assert.Equal(t, tt.expected, status)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestMediaStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     MediaStatus
+		to       MediaStatus
+		expected bool
+	}{
+		{"uploading to processing", StatusUploading, StatusProcessing, true},
+		{"uploading to failed", StatusUploading, StatusFailed, true},
+		{"uploading to deleted", StatusUploading, StatusDeleted, true},
+		{"processing to ready", StatusProcessing, StatusReady, true},
+		{"processing to failed", StatusProcessing, StatusFailed, true},
+		{"processing to deleted", StatusProcessing, StatusDeleted, true},
+		{"ready to deleted", StatusReady, StatusDeleted, true},
+		{"failed to processing", StatusFailed, StatusProcessing, true},
+		{"failed to deleted", StatusFailed, StatusDeleted, true},
+		{"uploading to ready", StatusUploading, StatusReady, false},
+		{"ready to processing", StatusReady, StatusProcessing, false},
+		{"deleted to ready", StatusDeleted, StatusReady, false},
+		{"deleted to processing", StatusDeleted, StatusProcessing, false},
+		{"unknown status", MediaStatus("archived"), StatusReady, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestMedia_TransitionTo(t *testing.T) {
+	t.Run("allowed transition", func(t *testing.T) {
+		// Given
+		media := &Media{ID: "123", Status: StatusUploading}
+
+		// When
+		err := media.TransitionTo(StatusProcessing)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, StatusProcessing, media.Status)
+	})
+
+	t.Run("illegal transition", func(t *testing.T) {
+		// Given
+		media := &Media{ID: "123", Status: StatusDeleted}
+
+		// When
+		err := media.TransitionTo(StatusReady)
+
+		// Then
+		var transitionErr *StatusTransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.Equal(t, StatusDeleted, transitionErr.From)
+		assert.Equal(t, StatusReady, transitionErr.To)
+		assert.Equal(t, StatusDeleted, media.Status)
+	})
+}
+
+func TestMedia_SetFailure(t *testing.T) {
+	// Given
+	media := &Media{ID: "123", Status: StatusFailed}
+
+	// When
+	media.SetFailure(FailureMetadataExtraction, "unsupported codec")
+
+	// Then
+	assert.Equal(t, FailureMetadataExtraction, media.FailureCode)
+	assert.Equal(t, "unsupported codec", media.FailureMessage)
+
+	// When
+	media.ClearFailure()
+
+	// Then
+	assert.Empty(t, media.FailureCode)
+	assert.Empty(t, media.FailureMessage)
+}
+
+func TestMediaIDOfStorageKey(t *testing.T) {
+	assert.Equal(t, "m1", MediaIDOfStorageKey("m1.mp3"))
+	assert.Equal(t, "m1", MediaIDOfStorageKey(DerivedArtworkKey("m1", "image/png")))
+	assert.Equal(t, "m1", MediaIDOfStorageKey(DerivedAudioKey("m1", AudioFormatOpus)))
+	assert.Equal(t, "", MediaIDOfStorageKey("exports/report.csv"))
+}
@@ -0,0 +1,289 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Transcript suggestions
+const (
+	DefaultSuggestionConfidence = 0.4 // suggestions below this confidence are not stored
+	MaxSuggestedLabels          = 5
+	MaxSuggestedCategories      = 3
+	// KeywordConfidenceScale is the mentions at which a keyword is suggested with
+	// a confidence of 0.5, confidence growing towards 1 with further mentions
+	KeywordConfidenceScale = 5
+	minKeywordMentions     = 3
+	minKeywordLength       = 3 // characters
+)
+
+// Suggestion review listings
+const (
+	DefaultSuggestionQueueLimit = 50
+	MaxSuggestionQueueLimit     = 200
+)
+
+// MetadataSuggestionKind tells which classification of media a suggestion is for
+type MetadataSuggestionKind string
+
+const (
+	SuggestionKindLabel    MetadataSuggestionKind = "label"    // an editorial label added to the media
+	SuggestionKindCategory MetadataSuggestionKind = "category" // the category of the media
+)
+
+// MetadataSuggestionStatus is the review state of a suggestion
+type MetadataSuggestionStatus string
+
+const (
+	SuggestionPending   MetadataSuggestionStatus = "pending"
+	SuggestionAccepted  MetadataSuggestionStatus = "accepted"
+	SuggestionDismissed MetadataSuggestionStatus = "dismissed"
+)
+
+// MetadataSuggestion is a label or category suggested for a media item from its
+// transcript, waiting for an editor to accept or dismiss it
+type MetadataSuggestion struct {
+	ID         string                   `json:"id" gorm:"primaryKey"`
+	MediaID    string                   `json:"media_id" gorm:"not null;index"`
+	Kind       MetadataSuggestionKind   `json:"kind" gorm:"type:varchar(20);not null"`
+	Value      string                   `json:"value" gorm:"type:varchar(100);not null"`
+	Confidence float64                  `json:"confidence"` // between 0 and 1
+	Status     MetadataSuggestionStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	CreatedAt  time.Time                `json:"created_at"`
+	ResolvedAt *time.Time               `json:"resolved_at,omitempty"`
+}
+
+// TableName specifies the table name for MetadataSuggestion
+func (MetadataSuggestion) TableName() string {
+	return "metadata_suggestions"
+}
+
+// ApplyTo adds a suggested label to the labels of media, or makes a suggested
+// category its category
+func (s *MetadataSuggestion) ApplyTo(media *Media) ValidationErrors {
+	switch s.Kind {
+	case SuggestionKindLabel:
+		labels := NormalizeLabels(append(append([]string{}, media.Labels...), s.Value))
+		if errs := ValidateLabels("labels", labels); errs.HasErrors() {
+			return errs
+		}
+		media.Labels = labels
+	case SuggestionKindCategory:
+		if errs := ValidateCategory("category", s.Value); errs.HasErrors() {
+			return errs
+		}
+		media.Category = s.Value
+	default:
+		var errs ValidationErrors
+		errs.Add("kind", fmt.Sprintf("unknown suggestion kind %q", s.Kind))
+		return errs
+	}
+	return nil
+}
+
+// AppliedTo reports whether media already carries the suggested label or category
+func (s *MetadataSuggestion) AppliedTo(media *Media) bool {
+	switch s.Kind {
+	case SuggestionKindLabel:
+		for _, label := range media.Labels {
+			if label == s.Value {
+				return true
+			}
+		}
+	case SuggestionKindCategory:
+		return strings.EqualFold(media.Category, s.Value)
+	}
+	return false
+}
+
+// DefaultCategoryKeywords are the categories suggested from transcripts unless
+// configured otherwise, with the English and Arabic words pointing at them
+var DefaultCategoryKeywords = map[string][]string{
+	"technology": {"technology", "software", "internet", "startup", "computer", "programming", "تقنية", "تكنولوجيا", "برمجة", "إنترنت"},
+	"business":   {"business", "company", "market", "investment", "economy", "money", "اقتصاد", "استثمار", "شركة", "سوق"},
+	"sports":     {"sports", "football", "match", "team", "player", "league", "رياضة", "كرة", "مباراة", "فريق", "لاعب"},
+	"health":     {"health", "medicine", "doctor", "disease", "nutrition", "fitness", "صحة", "طبيب", "مرض", "تغذية"},
+	"science":    {"science", "research", "physics", "biology", "space", "scientists", "علم", "علوم", "بحث", "فيزياء", "فضاء"},
+	"culture":    {"culture", "art", "music", "film", "book", "poetry", "ثقافة", "موسيقى", "فيلم", "كتاب", "شعر"},
+	"history":    {"history", "century", "empire", "war", "ancient", "تاريخ", "قرن", "حضارة", "حرب", "دولة"},
+	"religion":   {"religion", "faith", "prayer", "quran", "islam", "دين", "إيمان", "صلاة", "قرآن", "إسلام"},
+	"education":  {"education", "school", "university", "students", "learning", "تعليم", "مدرسة", "جامعة", "طلاب", "تعلم"},
+	"politics":   {"politics", "government", "election", "policy", "minister", "سياسة", "حكومة", "انتخابات", "وزير"},
+}
+
+// ParseCategoryKeywords parses categories configured as "category:word|word",
+// the category name itself always pointing at the category. Words shorter than
+// three characters are never counted.
+func ParseCategoryKeywords(entries []string) (map[string][]string, error) {
+	categories := make(map[string][]string, len(entries))
+	for _, entry := range entries {
+		name, words, _ := strings.Cut(entry, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return nil, fmt.Errorf("invalid suggestion category %q, expected category:word|word", entry)
+		}
+		if errs := ValidateCategory("category", name); errs.HasErrors() {
+			return nil, fmt.Errorf("invalid suggestion category %q: %w", entry, errs)
+		}
+
+		keywords := []string{name}
+		for _, word := range strings.Split(words, "|") {
+			if word = strings.TrimSpace(word); word != "" {
+				keywords = append(keywords, word)
+			}
+		}
+		categories[name] = append(categories[name], keywords...)
+	}
+	return categories, nil
+}
+
+// SuggestLabels suggests the keywords mentioned most in a transcript as labels,
+// at most limit of them
+func SuggestLabels(text string, limit int) []MetadataSuggestion {
+	counts := countTerms(text)
+
+	suggestions := []MetadataSuggestion{}
+	for term, count := range counts {
+		if count < minKeywordMentions || len(term) > MaxLabelLength {
+			continue
+		}
+		suggestions = append(suggestions, MetadataSuggestion{
+			Kind:       SuggestionKindLabel,
+			Value:      term,
+			Confidence: keywordConfidence(count),
+		})
+	}
+	return topSuggestions(suggestions, limit)
+}
+
+// SuggestCategories scores each category by the mentions of its keywords in a
+// transcript and suggests the best scoring ones, at most limit of them
+func SuggestCategories(text string, categories map[string][]string, limit int) []MetadataSuggestion {
+	counts := countTerms(text)
+
+	suggestions := []MetadataSuggestion{}
+	for category, keywords := range categories {
+		mentions := 0
+		seen := make(map[string]bool, len(keywords))
+		for _, keyword := range keywords {
+			term := normalizeTerm(keyword)
+			if !seen[term] {
+				seen[term] = true
+				mentions += counts[term]
+			}
+		}
+		if mentions < minKeywordMentions {
+			continue
+		}
+		suggestions = append(suggestions, MetadataSuggestion{
+			Kind:       SuggestionKindCategory,
+			Value:      category,
+			Confidence: keywordConfidence(mentions),
+		})
+	}
+	return topSuggestions(suggestions, limit)
+}
+
+// keywordConfidence maps mentions to a confidence below 1
+func keywordConfidence(mentions int) float64 {
+	confidence := float64(mentions) / float64(mentions+KeywordConfidenceScale)
+	return math.Round(confidence*100) / 100
+}
+
+// topSuggestions keeps the limit most confident suggestions, ties by value
+func topSuggestions(suggestions []MetadataSuggestion, limit int) []MetadataSuggestion {
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Value < suggestions[j].Value
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// countTerms counts the normalized words of text, leaving out stopwords, numbers
+// and words too short to carry meaning
+func countTerms(text string) map[string]int {
+	counts := make(map[string]int)
+	words := strings.FieldsFunc(text, isWordSeparator)
+	for _, word := range words {
+		term := normalizeTerm(word)
+		if utf8.RuneCountInString(term) < minKeywordLength || transcriptStopwords[term] || isNumber(term) {
+			continue
+		}
+		counts[term]++
+	}
+	return counts
+}
+
+// normalizeTerm lower-cases a word and folds the Arabic spelling variants that
+// would split the mentions of one word: diacritics, tatweel, hamza forms of alef
+// and the definite article
+func normalizeTerm(word string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(word)) {
+		switch {
+		case r >= '\u064B' && r <= '\u0652', r == '\u0640': // diacritics and tatweel
+			continue
+		case r == '\u0623', r == '\u0625', r == '\u0622': // alef with hamza or madda
+			r = '\u0627'
+		}
+		b.WriteRune(r)
+	}
+	term := b.String()
+	if strings.HasPrefix(term, "ال") && utf8.RuneCountInString(term) > 4 {
+		term = strings.TrimPrefix(term, "ال")
+	}
+	return term
+}
+
+// isNumber reports whether a term has only digits
+func isNumber(term string) bool {
+	for _, r := range term {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// transcriptStopwords are the function words and spoken fillers of English and
+// Arabic transcripts, normalized, that are never suggested
+var transcriptStopwords = makeStopwords(
+	// English
+	"the", "and", "for", "are", "but", "not", "you", "all", "any", "can", "had", "her", "was", "one", "our", "out",
+	"has", "him", "his", "how", "its", "may", "new", "now", "old", "see", "two", "who", "did", "get", "let", "say",
+	"she", "too", "use", "that", "with", "have", "this", "will", "your", "from", "they", "know", "want", "been",
+	"good", "much", "some", "time", "very", "when", "come", "here", "just", "like", "long", "make", "many", "more",
+	"only", "over", "such", "take", "than", "them", "well", "were", "what", "then", "there", "these", "thing",
+	"things", "think", "those", "would", "could", "should", "about", "after", "again", "also", "because", "before",
+	"being", "going", "gonna", "into", "kind", "look", "mean", "other", "really", "right", "said", "same", "still",
+	"their", "through", "where", "which", "while", "yeah", "okay", "actually", "basically", "maybe", "something",
+	"anything", "everything", "people", "lot", "way", "why", "yes", "don", "didn", "doesn", "isn", "wasn", "aren",
+	"got", "does", "each", "even", "every", "first", "most", "need", "never", "ours", "own", "put", "since", "sure",
+	"tell", "today", "went", "year", "years",
+	// Arabic
+	"في", "من", "على", "إلى", "عن", "مع", "هذا", "هذه", "ذلك", "تلك", "التي", "الذي", "الذين", "هو", "هي", "هم",
+	"نحن", "أنا", "أنت", "كان", "كانت", "يكون", "تكون", "لكن", "ولكن", "أو", "ثم", "إذا", "لقد", "قد", "كل", "بعض",
+	"غير", "بين", "عند", "عندما", "حتى", "أيضا", "أيضاً", "فقط", "يعني", "طيب", "شيء", "أشياء", "جدا", "جداً",
+	"هناك", "هنا", "كيف", "ماذا", "لماذا", "متى", "أين", "الآن", "اليوم", "مثل", "بعد", "قبل", "لأن", "وهو", "وهي",
+	"فيه", "فيها", "منه", "منها", "عليه", "عليها", "إنه", "إنها", "أنه", "أنها", "لها", "له", "لنا", "لهم", "كذلك",
+	"نعم", "لا", "ليس", "ليست", "أكثر", "أول", "سنة", "ايش", "شو", "والله", "يا",
+)
+
+// makeStopwords normalizes stopwords into a set
+func makeStopwords(words ...string) map[string]bool {
+	stopwords := make(map[string]bool, len(words))
+	for _, word := range words {
+		stopwords[normalizeTerm(word)] = true
+	}
+	return stopwords
+}
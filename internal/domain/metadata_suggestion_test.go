@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestLabels(t *testing.T) {
+	text := strings.Repeat("The startup raised money from investors. ", 3) +
+		strings.Repeat("Startups need investors, and investors need startups. ", 2) +
+		"Yeah, you know, it was really something."
+
+	suggestions := SuggestLabels(text, 2)
+
+	assert.Equal(t, []MetadataSuggestion{
+		{Kind: SuggestionKindLabel, Value: "investors", Confidence: 0.58},
+		{Kind: SuggestionKindLabel, Value: "startups", Confidence: 0.44},
+	}, suggestions)
+}
+
+func TestSuggestLabels_FoldsArabicSpellings(t *testing.T) {
+	text := "الاقتصاد مهم. الاقتصادُ في المنطقة. اقتصاد الخليج. في في في"
+
+	suggestions := SuggestLabels(text, MaxSuggestedLabels)
+
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "اقتصاد", suggestions[0].Value)
+}
+
+func TestSuggestCategories(t *testing.T) {
+	text := "The match was decided in the last minute. The team and every player " +
+		"celebrated, football at its best. Our economy segment is next week."
+
+	suggestions := SuggestCategories(text, DefaultCategoryKeywords, MaxSuggestedCategories)
+
+	assert.Equal(t, []MetadataSuggestion{{Kind: SuggestionKindCategory, Value: "sports", Confidence: 0.44}}, suggestions)
+}
+
+func TestParseCategoryKeywords(t *testing.T) {
+	categories, err := ParseCategoryKeywords([]string{"Gaming:games|console", "food"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"gaming": {"gaming", "games", "console"},
+		"food":   {"food"},
+	}, categories)
+
+	_, err = ParseCategoryKeywords([]string{":games"})
+	assert.Error(t, err)
+}
+
+func TestMetadataSuggestion_ApplyTo(t *testing.T) {
+	media := &Media{Labels: []string{"interview"}, Category: "culture"}
+
+	label := MetadataSuggestion{Kind: SuggestionKindLabel, Value: "startups"}
+	require.False(t, label.ApplyTo(media).HasErrors())
+	assert.Equal(t, []string{"interview", "startups"}, media.Labels)
+	assert.True(t, label.AppliedTo(media))
+
+	category := MetadataSuggestion{Kind: SuggestionKindCategory, Value: "business"}
+	assert.False(t, category.AppliedTo(media))
+	require.False(t, category.ApplyTo(media).HasErrors())
+	assert.Equal(t, "business", media.Category)
+
+	full := &Media{Labels: make([]string, MaxLabels)}
+	for i := range full.Labels {
+		full.Labels[i] = strings.Repeat("x", i+1)
+	}
+	assert.True(t, label.ApplyTo(full).HasErrors())
+}
+
+func TestTranscriptText(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			name:     "plain text is trimmed",
+			raw:      "  Welcome to the show.\n1999 was a year.  ",
+			expected: "Welcome to the show.\n1999 was a year.",
+		},
+		{
+			name:     "WebVTT",
+			raw:      "WEBVTT\n\n00:00:01.000 --> 00:00:04.000\n<v Host>Welcome to the show.</v>\n\n00:00:04.500 --> 00:00:06.000\nToday we talk startups.\n",
+			expected: "Welcome to the show.\nToday we talk startups.",
+		},
+		{
+			name:     "SRT",
+			raw:      "1\r\n00:00:01,000 --> 00:00:04,000\r\nWelcome to the show.\r\n\r\n2\r\n00:00:04,500 --> 00:00:06,000\r\n<i>Today</i> we talk startups.\r\n",
+			expected: "Welcome to the show.\nToday we talk startups.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TranscriptText(tt.raw))
+		})
+	}
+}
+
+func TestTranscriptRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  TranscriptRequest
+		hasError bool
+	}{
+		{name: "valid", request: TranscriptRequest{Text: "Welcome", Language: "ar"}},
+		{name: "only captions markup", request: TranscriptRequest{Text: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n"}, hasError: true},
+		{name: "too long", request: TranscriptRequest{Text: strings.Repeat("a", MaxTranscriptBytes+1)}, hasError: true},
+		{name: "language too long", request: TranscriptRequest{Text: "Welcome", Language: "not-a-language"}, hasError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.Normalize()
+			assert.Equal(t, tt.hasError, tt.request.Validate().HasErrors())
+		})
+	}
+}
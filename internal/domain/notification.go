@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultTenantID identifies the single tenant of a non multi-tenant deployment
+const DefaultTenantID = "default"
+
+// Notification subject types
+const (
+	SubjectUser   = "user"
+	SubjectTenant = "tenant"
+)
+
+// Notification kinds users can subscribe to
+const (
+	NotificationProcessingCompleted = "processing.completed"
+	NotificationProcessingFailed    = "processing.failed"
+	NotificationModerationDecision  = "moderation.decision"
+	NotificationLicenseExpiring     = "license.expiring"
+	NotificationLicenseExpired      = "license.expired"
+	NotificationWeeklyDigest        = "digest.weekly"
+	NotificationPremiereStarted     = "premiere.started"
+)
+
+// NotificationKinds lists all notification kinds
+var NotificationKinds = []string{
+	NotificationProcessingCompleted,
+	NotificationProcessingFailed,
+	NotificationModerationDecision,
+	NotificationLicenseExpiring,
+	NotificationLicenseExpired,
+	NotificationWeeklyDigest,
+	NotificationPremiereStarted,
+}
+
+// Notification channels
+var NotificationChannels = []string{"email", "slack", "webhook"}
+
+// NotificationPreference stores where and for which events a user or tenant is notified
+type NotificationPreference struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	SubjectType string    `json:"subject_type" gorm:"type:varchar(20);not null;index:idx_notification_subject"`
+	SubjectID   string    `json:"subject_id" gorm:"not null;index:idx_notification_subject"`
+	Channel     string    `json:"channel" gorm:"type:varchar(20);not null"`
+	Target      string    `json:"target" gorm:"not null"` // email address or URL
+	Events      string    `json:"events"`                 // comma-separated kinds, empty for all
+	Enabled     bool      `json:"enabled" gorm:"not null;default:true"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Delivery stats
+	DeliveredCount  int64      `json:"delivered_count" gorm:"not null;default:0"`
+	FailedCount     int64      `json:"failed_count" gorm:"not null;default:0"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at,omitempty"`
+	LastFailedAt    *time.Time `json:"last_failed_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+// TableName specifies the table name for NotificationPreference
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// Wants returns true if the preference is enabled for the notification kind
+func (p *NotificationPreference) Wants(kind string) bool {
+	if !p.Enabled {
+		return false
+	}
+	if p.Events == "" {
+		return true
+	}
+	for _, event := range strings.Split(p.Events, ",") {
+		if strings.TrimSpace(event) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationSubject identifies who a notification is addressed to
+type NotificationSubject struct {
+	Type string
+	ID   string
+}
+
+// NotificationPreferenceRequest represents a request to create a notification preference
+type NotificationPreferenceRequest struct {
+	SubjectType string   `json:"subject_type" binding:"required"`
+	SubjectID   string   `json:"subject_id" binding:"required"`
+	Channel     string   `json:"channel" binding:"required"`
+	Target      string   `json:"target" binding:"required"`
+	Events      []string `json:"events"`
+}
+
+// Validate validates the preference request
+func (r *NotificationPreferenceRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if r.SubjectType != SubjectUser && r.SubjectType != SubjectTenant {
+		errs.Add("subject_type", "must be one of: user, tenant")
+	}
+	if r.SubjectID == "" {
+		errs.Add("subject_id", "is required")
+	}
+	if !containsString(NotificationChannels, r.Channel) {
+		errs.Add("channel", "must be one of: "+strings.Join(NotificationChannels, ", "))
+	}
+	if r.Target == "" {
+		errs.Add("target", "is required")
+	}
+	for _, event := range r.Events {
+		if !containsString(NotificationKinds, event) {
+			errs.Add("events", "unknown notification kind: "+event)
+		}
+	}
+
+	return errs
+}
+
+// ToPreference converts the request to a NotificationPreference entity
+func (r *NotificationPreferenceRequest) ToPreference(id string) *NotificationPreference {
+	return &NotificationPreference{
+		ID:          id,
+		SubjectType: r.SubjectType,
+		SubjectID:   r.SubjectID,
+		Channel:     r.Channel,
+		Target:      r.Target,
+		Events:      strings.Join(r.Events, ","),
+		Enabled:     true,
+	}
+}
+
+// containsString checks if the slice contains the value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
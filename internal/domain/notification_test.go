@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreference_Wants(t *testing.T) {
+	tests := []struct {
+		name     string
+		pref     NotificationPreference
+		kind     string
+		expected bool
+	}{
+		{
+			name:     "all events when none listed",
+			pref:     NotificationPreference{Enabled: true},
+			kind:     NotificationProcessingFailed,
+			expected: true,
+		},
+		{
+			name:     "listed event",
+			pref:     NotificationPreference{Enabled: true, Events: "processing.completed, processing.failed"},
+			kind:     NotificationProcessingFailed,
+			expected: true,
+		},
+		{
+			name:     "unlisted event",
+			pref:     NotificationPreference{Enabled: true, Events: "processing.completed"},
+			kind:     NotificationModerationDecision,
+			expected: false,
+		},
+		{
+			name:     "disabled preference",
+			pref:     NotificationPreference{Enabled: false},
+			kind:     NotificationProcessingCompleted,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.pref.Wants(tt.kind))
+		})
+	}
+}
+
+func TestNotificationPreferenceRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     NotificationPreferenceRequest
+		errorFields []string
+	}{
+		{
+			name: "valid request",
+			request: NotificationPreferenceRequest{
+				SubjectType: SubjectTenant,
+				SubjectID:   DefaultTenantID,
+				Channel:     "slack",
+				Target:      "https://hooks.slack.com/services/T000/B000/XXX",
+				Events:      []string{NotificationProcessingFailed},
+			},
+		},
+		{
+			name: "unknown subject type and channel",
+			request: NotificationPreferenceRequest{
+				SubjectType: "group",
+				SubjectID:   "1",
+				Channel:     "sms",
+				Target:      "+966500000000",
+			},
+			errorFields: []string{"subject_type", "channel"},
+		},
+		{
+			name: "unknown event kind",
+			request: NotificationPreferenceRequest{
+				SubjectType: SubjectUser,
+				SubjectID:   "user-1",
+				Channel:     "email",
+				Target:      "ops@example.com",
+				Events:      []string{"media.played"},
+			},
+			errorFields: []string{"events"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.request.Validate()
+
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.errorFields, fields)
+		})
+	}
+}
+
+func TestNotificationPreferenceRequest_ToPreference(t *testing.T) {
+	// Given
+	req := NotificationPreferenceRequest{
+		SubjectType: SubjectUser,
+		SubjectID:   "user-1",
+		Channel:     "email",
+		Target:      "ops@example.com",
+		Events:      []string{NotificationProcessingCompleted, NotificationProcessingFailed},
+	}
+
+	// When
+	pref := req.ToPreference("pref-1")
+
+	// Then
+	assert.Equal(t, "pref-1", pref.ID)
+	assert.Equal(t, "processing.completed,processing.failed", pref.Events)
+	assert.True(t, pref.Enabled)
+	assert.True(t, pref.Wants(NotificationProcessingFailed))
+}
+
+func TestNotificationPreference_TableName(t *testing.T) {
+	assert.Equal(t, "notification_preferences", NotificationPreference{}.TableName())
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// OutboxMessage is a message waiting to be relayed to the message queue.
+// It is written in the transaction of the change it announces, and marked as
+// published once the queue accepted it.
+type OutboxMessage struct {
+	ID          uint64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Topic       string     `json:"topic" gorm:"type:varchar(100);not null"`
+	Payload     []byte     `json:"payload" gorm:"type:bytea;not null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+	PublishedAt *time.Time `json:"published_at,omitempty" gorm:"index"`
+}
+
+// TableName specifies the table name for OutboxMessage
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+// NewOutboxMessage creates a new outbox message for a topic
+func NewOutboxMessage(topic string, payload []byte) *OutboxMessage {
+	return &OutboxMessage{
+		Topic:   topic,
+		Payload: payload,
+	}
+}
+
+// OutboxCheckpoint records the last outbox message a relay has published.
+// Superseded by OutboxMessage.PublishedAt, the migration marks the messages
+// up to the checkpoints as published.
+type OutboxCheckpoint struct {
+	Name          string    `json:"name" gorm:"primaryKey;type:varchar(100)"`
+	LastMessageID uint64    `json:"last_message_id" gorm:"not null;default:0"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for OutboxCheckpoint
+func (OutboxCheckpoint) TableName() string {
+	return "outbox_checkpoints"
+}
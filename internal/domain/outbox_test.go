@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOutboxMessage(t *testing.T) {
+	// Given
+	payload := []byte(`{"type":"media.status_changed"}`)
+
+	// When
+	msg := NewOutboxMessage(EventMediaStatusChanged, payload)
+
+	// Then
+	assert.Equal(t, EventMediaStatusChanged, msg.Topic)
+	assert.Equal(t, payload, msg.Payload)
+	assert.Zero(t, msg.ID)
+}
+
+func TestOutbox_TableNames(t *testing.T) {
+	assert.Equal(t, "outbox_messages", OutboxMessage{}.TableName())
+	assert.Equal(t, "outbox_checkpoints", OutboxCheckpoint{}.TableName())
+}
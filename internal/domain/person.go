@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Limits of people and their credits
+const (
+	MaxPersonNameLength = 200
+	MaxPersonBioLength  = 5000
+	MaxPersonImageURL   = 500
+	MaxCredits          = 50 // people credited on one media item or show
+)
+
+// PersonRole is the part a person plays in media or a show
+type PersonRole string
+
+const (
+	PersonRoleHost  PersonRole = "host"
+	PersonRoleGuest PersonRole = "guest"
+)
+
+// IsValid returns true for known roles
+func (r PersonRole) IsValid() bool {
+	return r == PersonRoleHost || r == PersonRoleGuest
+}
+
+// CreditTarget is what a person is credited on
+type CreditTarget string
+
+const (
+	CreditTargetMedia CreditTarget = "media" // an episode or video
+	CreditTargetShow  CreditTarget = "show"  // every episode of a show
+)
+
+// Person is a host or guest of media and shows
+type Person struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(200);not null;index"`
+	Bio       string    `json:"bio,omitempty"`
+	ImageURL  string    `json:"image_url,omitempty" gorm:"type:varchar(500)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for Person
+func (Person) TableName() string {
+	return "people"
+}
+
+// PersonCredit links a person to a media item or a show in a role
+type PersonCredit struct {
+	PersonID   string       `json:"person_id" gorm:"primaryKey"`
+	TargetType CreditTarget `json:"target_type" gorm:"primaryKey;type:varchar(10);index:idx_person_credits_target,priority:1"`
+	TargetID   string       `json:"target_id" gorm:"primaryKey;index:idx_person_credits_target,priority:2"`
+	Role       PersonRole   `json:"role" gorm:"type:varchar(10);not null"`
+	Position   int          `json:"-" gorm:"not null;default:0"` // order of the people of a target
+	CreatedAt  time.Time    `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for PersonCredit
+func (PersonCredit) TableName() string {
+	return "person_credits"
+}
+
+// PersonRequest represents a request to create or replace a person
+type PersonRequest struct {
+	Name     string `json:"name"`
+	Bio      string `json:"bio"`
+	ImageURL string `json:"image_url"`
+}
+
+// Validate validates the person request
+func (r *PersonRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		errs.Add("name", "is required")
+	} else if len(name) > MaxPersonNameLength {
+		errs.Add("name", fmt.Sprintf("must be at most %d characters", MaxPersonNameLength))
+	}
+	if len(r.Bio) > MaxPersonBioLength {
+		errs.Add("bio", fmt.Sprintf("must be at most %d characters", MaxPersonBioLength))
+	}
+	if imageURL := strings.TrimSpace(r.ImageURL); imageURL != "" {
+		parsed, err := url.Parse(imageURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs.Add("image_url", "must be an http or https URL")
+		} else if len(imageURL) > MaxPersonImageURL {
+			errs.Add("image_url", fmt.Sprintf("must be at most %d characters", MaxPersonImageURL))
+		}
+	}
+
+	return errs
+}
+
+// ApplyTo applies the person request to a person
+func (r *PersonRequest) ApplyTo(person *Person) {
+	person.Name = strings.Join(strings.Fields(r.Name), " ")
+	person.Bio = strings.TrimSpace(r.Bio)
+	person.ImageURL = strings.TrimSpace(r.ImageURL)
+}
+
+// CreditRequest credits a person in a role
+type CreditRequest struct {
+	PersonID string     `json:"person_id"`
+	Role     PersonRole `json:"role"`
+}
+
+// CreditsRequest represents a request to replace the people of a media item or show, in order
+type CreditsRequest struct {
+	People []CreditRequest `json:"people"` // empty removes every credit
+}
+
+// Validate validates the credits request
+func (r *CreditsRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if len(r.People) > MaxCredits {
+		errs.Add("people", fmt.Sprintf("must have at most %d entries", MaxCredits))
+		return errs
+	}
+
+	seen := make(map[string]bool, len(r.People))
+	for i, credit := range r.People {
+		field := fmt.Sprintf("people[%d]", i)
+		switch {
+		case credit.PersonID == "":
+			errs.Add(field+".person_id", "is required")
+		case seen[credit.PersonID]:
+			errs.Add(field+".person_id", "is already credited")
+		}
+		seen[credit.PersonID] = true
+		if !credit.Role.IsValid() {
+			errs.Add(field+".role", "must be host or guest")
+		}
+	}
+
+	return errs
+}
+
+// ToCredits builds the credits of a target from the request
+func (r *CreditsRequest) ToCredits(targetType CreditTarget, targetID string) []*PersonCredit {
+	credits := make([]*PersonCredit, len(r.People))
+	for i, credit := range r.People {
+		credits[i] = &PersonCredit{
+			PersonID:   credit.PersonID,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Role:       credit.Role,
+			Position:   i,
+		}
+	}
+	return credits
+}
+
+// Credit is a person credited on a media item or show
+type Credit struct {
+	Person *Person    `json:"person"`
+	Role   PersonRole `json:"role"`
+}
+
+// CreditsResponse lists the people of a media item or show
+type CreditsResponse struct {
+	Items []Credit `json:"items"`
+}
+
+// ShowAppearance is a show a person is credited on
+type ShowAppearance struct {
+	ShowID string     `json:"show_id"`
+	Role   PersonRole `json:"role"`
+}
+
+// MediaAppearance is a media item a person is credited on
+type MediaAppearance struct {
+	Media *Media     `json:"media"`
+	Role  PersonRole `json:"role"`
+}
+
+// AppearancesResponse lists the shows and media a person appears in
+type AppearancesResponse struct {
+	Person     *Person           `json:"person"`
+	Shows      []ShowAppearance  `json:"shows"`
+	Items      []MediaAppearance `json:"items"` // most recent first
+	Total      int64             `json:"total"`
+	NextCursor string            `json:"next_cursor,omitempty"` // pass as cursor to get the next page
+}
+
+// PeopleListResponse represents a page of people
+type PeopleListResponse struct {
+	Items      []*Person `json:"items"`
+	Total      int64     `json:"total"`
+	NextCursor string    `json:"next_cursor,omitempty"` // pass as cursor to get the next page
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   PersonRequest
+		wantField string
+	}{
+		{name: "valid", request: PersonRequest{Name: "Sara", ImageURL: "https://cdn.example.com/sara.jpg"}},
+		{name: "missing name", request: PersonRequest{Name: "  "}, wantField: "name"},
+		{name: "long name", request: PersonRequest{Name: strings.Repeat("a", MaxPersonNameLength+1)}, wantField: "name"},
+		{name: "long bio", request: PersonRequest{Name: "Sara", Bio: strings.Repeat("a", MaxPersonBioLength+1)}, wantField: "bio"},
+		{name: "relative image", request: PersonRequest{Name: "Sara", ImageURL: "/sara.jpg"}, wantField: "image_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.request.Validate()
+			if tt.wantField == "" {
+				assert.False(t, errs.HasErrors())
+				return
+			}
+			assert.Contains(t, errs.Error(), tt.wantField)
+		})
+	}
+}
+
+func TestCreditsRequest(t *testing.T) {
+	req := CreditsRequest{People: []CreditRequest{
+		{PersonID: "p1", Role: PersonRoleHost},
+		{PersonID: "p2", Role: PersonRoleGuest},
+	}}
+	assert.False(t, req.Validate().HasErrors())
+
+	credits := req.ToCredits(CreditTargetShow, "show-1")
+	assert.Equal(t, []*PersonCredit{
+		{PersonID: "p1", TargetType: CreditTargetShow, TargetID: "show-1", Role: PersonRoleHost, Position: 0},
+		{PersonID: "p2", TargetType: CreditTargetShow, TargetID: "show-1", Role: PersonRoleGuest, Position: 1},
+	}, credits)
+
+	// Repeated people, missing IDs and unknown roles are rejected
+	req = CreditsRequest{People: []CreditRequest{
+		{PersonID: "p1", Role: PersonRoleHost},
+		{PersonID: "p1", Role: PersonRoleGuest},
+		{Role: "producer"},
+	}}
+	errs := req.Validate()
+	assert.Contains(t, errs.Error(), "people[1].person_id")
+	assert.Contains(t, errs.Error(), "people[2].person_id")
+	assert.Contains(t, errs.Error(), "people[2].role")
+
+	req = CreditsRequest{People: make([]CreditRequest, MaxCredits+1)}
+	assert.Contains(t, req.Validate().Error(), "at most")
+}
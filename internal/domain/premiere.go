@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// Premiere schedules the first playback of media already uploaded at a set time,
+// played to everyone as if it were live. Playback is refused until it starts.
+type Premiere struct {
+	StartsAt *time.Time `json:"starts_at,omitempty" gorm:"index"` // nil when the media does not premiere
+
+	// AnnouncedAt records when the start of the premiere was announced
+	AnnouncedAt *time.Time `json:"announced_at,omitempty"`
+}
+
+// IsScheduled returns true if the media premieres
+func (p Premiere) IsScheduled() bool {
+	return p.StartsAt != nil
+}
+
+// IsUpcoming returns true if the premiere has not started at now
+func (p Premiere) IsUpcoming(now time.Time) bool {
+	return p.StartsAt != nil && now.Before(*p.StartsAt)
+}
+
+// PremiereRequest schedules the premiere of media
+type PremiereRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+}
+
+// Validate checks that the premiere starts after now
+func (r *PremiereRequest) Validate(now time.Time) ValidationErrors {
+	var errs ValidationErrors
+
+	if !r.StartsAt.After(now) {
+		errs.Add("starts_at", "must be in the future")
+	}
+
+	return errs
+}
+
+// PremiereCountdown tells viewers how long until a premiere starts
+type PremiereCountdown struct {
+	MediaID          string    `json:"media_id"`
+	StartsAt         time.Time `json:"starts_at"`
+	ServerTime       time.Time `json:"server_time"`       // for clients to correct their clock
+	SecondsRemaining int64     `json:"seconds_remaining"` // 0 once started
+	Started          bool      `json:"started"`
+}
+
+// NewPremiereCountdown returns the countdown to the premiere of media at now
+func NewPremiereCountdown(media *Media, now time.Time) *PremiereCountdown {
+	countdown := &PremiereCountdown{
+		MediaID:    media.ID,
+		StartsAt:   media.Premiere.StartsAt.UTC(),
+		ServerTime: now.UTC(),
+		Started:    !media.Premiere.IsUpcoming(now),
+	}
+	if !countdown.Started {
+		// Rounded up so clients never count down to zero before playback opens
+		remaining := media.Premiere.StartsAt.Sub(now)
+		countdown.SecondsRemaining = int64((remaining + time.Second - 1) / time.Second)
+	}
+	return countdown
+}
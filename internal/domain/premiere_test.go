@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPremiere_IsUpcoming(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.False(t, Premiere{}.IsUpcoming(now))
+	assert.False(t, Premiere{StartsAt: &past}.IsUpcoming(now))
+	assert.False(t, Premiere{StartsAt: &now}.IsUpcoming(now))
+	assert.True(t, Premiere{StartsAt: &future}.IsUpcoming(now))
+}
+
+func TestNewPremiereCountdown(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	startsAt := now.Add(90*time.Second + 200*time.Millisecond)
+	media := &Media{ID: "123", Premiere: Premiere{StartsAt: &startsAt}}
+
+	countdown := NewPremiereCountdown(media, now)
+	assert.Equal(t, int64(91), countdown.SecondsRemaining, "rounded up")
+	assert.False(t, countdown.Started)
+	assert.Equal(t, now, countdown.ServerTime)
+
+	countdown = NewPremiereCountdown(media, startsAt)
+	assert.Zero(t, countdown.SecondsRemaining)
+	assert.True(t, countdown.Started)
+}
+
+func TestMedia_CheckPlayback_Premiere(t *testing.T) {
+	startsAt := time.Now().Add(time.Hour)
+	media := &Media{ID: "123", Premiere: Premiere{StartsAt: &startsAt}}
+
+	var premiereErr *PremiereNotStartedError
+	err := media.CheckPlayback(Viewer{})
+	require.ErrorAs(t, err, &premiereErr)
+	assert.ErrorIs(t, err, ErrPremiereNotStarted)
+	assert.Equal(t, "123", premiereErr.MediaID)
+	assert.NoError(t, media.CheckPlayback(Viewer{IsAdmin: true}))
+
+	started := time.Now().Add(-time.Minute)
+	media.Premiere.StartsAt = &started
+	assert.NoError(t, media.CheckPlayback(Viewer{}))
+}
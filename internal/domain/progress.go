@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// Continue listening limits
+const (
+	DefaultContinueLimit = 20
+	MaxContinueLimit     = 50
+
+	// ProgressCompletionRatio is the share of a media's duration after which it counts as finished
+	ProgressCompletionRatio = 0.95
+)
+
+// PlaybackProgress is how far a user got in a media item
+type PlaybackProgress struct {
+	UserID          string    `json:"user_id" gorm:"primaryKey"`
+	MediaID         string    `json:"media_id" gorm:"primaryKey;index"`
+	PositionSeconds int       `json:"position_seconds" gorm:"not null;default:0"`
+	Completed       bool      `json:"completed" gorm:"not null;default:false"`
+	LastPlayedAt    time.Time `json:"last_played_at" gorm:"not null;index"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for PlaybackProgress
+func (PlaybackProgress) TableName() string {
+	return "playback_progress"
+}
+
+// ProgressRequest represents a playback position reported by a player
+type ProgressRequest struct {
+	PositionSeconds int  `json:"position_seconds"`
+	Completed       bool `json:"completed"` // players may flag the end early, e.g. when credits start
+}
+
+// Validate validates the progress request
+func (r *ProgressRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if r.PositionSeconds < 0 {
+		errs.Add("position_seconds", "must not be negative")
+	}
+
+	return errs
+}
+
+// ToProgress builds the progress of userID in media played at now.
+// Positions past the end of the media are capped to its duration, and media played
+// beyond ProgressCompletionRatio of its duration is marked completed.
+func (r *ProgressRequest) ToProgress(userID string, media *Media, now time.Time) *PlaybackProgress {
+	position := r.PositionSeconds
+	completed := r.Completed
+
+	if media.Duration > 0 {
+		if position > media.Duration {
+			position = media.Duration
+		}
+		if float64(position) >= float64(media.Duration)*ProgressCompletionRatio {
+			completed = true
+		}
+	}
+
+	return &PlaybackProgress{
+		UserID:          userID,
+		MediaID:         media.ID,
+		PositionSeconds: position,
+		Completed:       completed,
+		LastPlayedAt:    now,
+	}
+}
+
+// ContinueItem is a media item the user started but did not finish
+type ContinueItem struct {
+	Media           *Media    `json:"media"`
+	PositionSeconds int       `json:"position_seconds"`
+	LastPlayedAt    time.Time `json:"last_played_at"`
+}
+
+// ContinueListeningResponse lists the media a user can resume, most recently played first
+type ContinueListeningResponse struct {
+	Items []*ContinueItem `json:"items"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressRequest_Validate(t *testing.T) {
+	assert.False(t, (&ProgressRequest{PositionSeconds: 30}).Validate().HasErrors())
+	assert.True(t, (&ProgressRequest{PositionSeconds: -1}).Validate().HasErrors())
+}
+
+func TestProgressRequest_ToProgress(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	media := &Media{ID: "m1", Duration: 1000}
+
+	tests := []struct {
+		name      string
+		request   ProgressRequest
+		position  int
+		completed bool
+	}{
+		{"started", ProgressRequest{PositionSeconds: 120}, 120, false},
+		{"almost done", ProgressRequest{PositionSeconds: 949}, 949, false},
+		{"past completion ratio", ProgressRequest{PositionSeconds: 950}, 950, true},
+		{"flagged completed", ProgressRequest{PositionSeconds: 10, Completed: true}, 10, true},
+		{"past the end", ProgressRequest{PositionSeconds: 5000}, 1000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := tt.request.ToProgress("u1", media, now)
+			assert.Equal(t, "u1", progress.UserID)
+			assert.Equal(t, "m1", progress.MediaID)
+			assert.Equal(t, tt.position, progress.PositionSeconds)
+			assert.Equal(t, tt.completed, progress.Completed)
+			assert.Equal(t, now, progress.LastPlayedAt)
+		})
+	}
+
+	// Media of unknown duration is only completed when flagged
+	progress := (&ProgressRequest{PositionSeconds: 5000}).ToProgress("u1", &Media{ID: "m2"}, now)
+	assert.Equal(t, 5000, progress.PositionSeconds)
+	assert.False(t, progress.Completed)
+}
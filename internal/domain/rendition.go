@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultHLSSegmentSeconds is the target duration of HLS segments
+const DefaultHLSSegmentSeconds = 6
+
+// DefaultVideoRenditions are produced for videos whose media type has no default transcode preset
+var DefaultVideoRenditions = []Rendition{
+	{Name: "1080p", Width: 1920, Height: 1080, VideoCodec: "h264", VideoBitrateKbps: 5000, AudioCodec: "aac", AudioBitrateKbps: 128, Container: "mp4"},
+	{Name: "720p", Width: 1280, Height: 720, VideoCodec: "h264", VideoBitrateKbps: 2800, AudioCodec: "aac", AudioBitrateKbps: 128, Container: "mp4"},
+	{Name: "480p", Width: 854, Height: 480, VideoCodec: "h264", VideoBitrateKbps: 1400, AudioCodec: "aac", AudioBitrateKbps: 96, Container: "mp4"},
+}
+
+// RenditionStatus is the transcoding state of a rendition
+type RenditionStatus string
+
+const (
+	RenditionPending    RenditionStatus = "pending"
+	RenditionProcessing RenditionStatus = "processing"
+	RenditionReady      RenditionStatus = "ready"
+	RenditionFailed     RenditionStatus = "failed"
+	RenditionSkipped    RenditionStatus = "skipped" // larger than the upload, which is never upscaled
+)
+
+// IsHLSCompatible returns true if the rendition is packaged as HLS segments:
+// H.264 video in MP4, or renditions requesting HLS only
+func (r Rendition) IsHLSCompatible() bool {
+	return !r.IsAudioOnly() && r.VideoCodec == "h264" && (r.Container == "mp4" || r.Container == "hls")
+}
+
+// MediaRendition is a rendition transcoded from the upload of a media item. Its
+// file and HLS segments are stored under the rendition prefix of the media.
+type MediaRendition struct {
+	ID          string          `json:"id" gorm:"primaryKey"`
+	MediaID     string          `json:"media_id" gorm:"not null;uniqueIndex:idx_renditions_media_name"`
+	Name        string          `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_renditions_media_name"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	VideoCodec  string          `json:"video_codec" gorm:"type:varchar(20)"`
+	AudioCodec  string          `json:"audio_codec" gorm:"type:varchar(20)"`
+	BitrateKbps int             `json:"bitrate_kbps"` // video and audio
+	Container   string          `json:"container" gorm:"type:varchar(10)"`
+	Status      RenditionStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	FileKey     string          `json:"file_key,omitempty"`                       // progressive file, empty for renditions only packaged as HLS
+	PlaylistKey string          `json:"playlist_key,omitempty"`                   // HLS media playlist, next to its segments
+	KeyID       string          `json:"key_id,omitempty" gorm:"type:varchar(32)"` // content key the file is encrypted with, empty for clear renditions
+	Size        int64           `json:"size"`                                     // bytes of the file and segments
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for MediaRendition
+func (MediaRendition) TableName() string {
+	return "renditions"
+}
+
+// NewMediaRendition returns the pending rendition of media described by a preset rendition
+func NewMediaRendition(id, mediaID string, rendition Rendition) *MediaRendition {
+	return &MediaRendition{
+		ID:          id,
+		MediaID:     mediaID,
+		Name:        rendition.Name,
+		Width:       rendition.Width,
+		Height:      rendition.Height,
+		VideoCodec:  rendition.VideoCodec,
+		AudioCodec:  rendition.AudioCodec,
+		BitrateKbps: rendition.VideoBitrateKbps + rendition.AudioBitrateKbps,
+		Container:   rendition.Container,
+		Status:      RenditionPending,
+	}
+}
+
+// RenditionKeyPrefix returns the prefix the renditions of media are stored under
+func RenditionKeyPrefix(mediaID string) string {
+	return DerivedKeyPrefix + mediaID + "/renditions/"
+}
+
+// RenditionFileKey returns the key of the progressive file of a rendition
+func RenditionFileKey(mediaID, name, container string) string {
+	return RenditionKeyPrefix(mediaID) + name + "." + container
+}
+
+// RenditionPlaylistKey returns the key of the HLS media playlist of a rendition,
+// whose segments are stored next to it
+func RenditionPlaylistKey(mediaID, name string) string {
+	return RenditionKeyPrefix(mediaID) + name + "/index.m3u8"
+}
+
+// HLSMasterPlaylistKey returns the key of the HLS master playlist of media
+func HLSMasterPlaylistKey(mediaID string) string {
+	return RenditionKeyPrefix(mediaID) + "master.m3u8"
+}
+
+// RelativePlaylistURI returns the URI of the HLS media playlist of the
+// rendition relative to the master playlist stored next to it
+func (r *MediaRendition) RelativePlaylistURI() string {
+	return strings.TrimPrefix(r.PlaylistKey, RenditionKeyPrefix(r.MediaID))
+}
+
+// HLSMasterPlaylist renders the master playlist of the ready HLS renditions,
+// highest bitrate first, referencing the media playlist of each by uriOf. It
+// returns "" when no rendition is packaged as HLS.
+func HLSMasterPlaylist(renditions []*MediaRendition, uriOf func(*MediaRendition) string) string {
+	var variants []*MediaRendition
+	for _, rendition := range renditions {
+		if rendition.Status == RenditionReady && rendition.PlaylistKey != "" {
+			variants = append(variants, rendition)
+		}
+	}
+	if len(variants) == 0 {
+		return ""
+	}
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].BitrateKbps > variants[j].BitrateKbps
+	})
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, variant := range variants {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=\"%s\"\n%s\n",
+			variant.BitrateKbps*1000, variant.Width, variant.Height, variant.Name, uriOf(variant))
+	}
+	return b.String()
+}
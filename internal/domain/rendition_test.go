@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRendition_IsHLSCompatible(t *testing.T) {
+	assert.True(t, Rendition{VideoCodec: "h264", Container: "mp4"}.IsHLSCompatible())
+	assert.True(t, Rendition{VideoCodec: "h264", Container: "hls"}.IsHLSCompatible())
+	assert.False(t, Rendition{VideoCodec: "vp9", Container: "webm"}.IsHLSCompatible())
+	assert.False(t, Rendition{VideoCodec: "h265", Container: "mp4"}.IsHLSCompatible())
+	assert.False(t, Rendition{AudioCodec: "aac", Container: "mp4"}.IsHLSCompatible(), "audio only")
+}
+
+func TestHLSMasterPlaylist(t *testing.T) {
+	renditions := []*MediaRendition{
+		{MediaID: "123", Name: "480p", Width: 854, Height: 480, BitrateKbps: 1496, Status: RenditionReady, PlaylistKey: RenditionPlaylistKey("123", "480p")},
+		{MediaID: "123", Name: "1080p", Width: 1920, Height: 1080, BitrateKbps: 5128, Status: RenditionSkipped},
+		{MediaID: "123", Name: "720p", Width: 1280, Height: 720, BitrateKbps: 2928, Status: RenditionReady, PlaylistKey: RenditionPlaylistKey("123", "720p")},
+		{MediaID: "123", Name: "webm", Width: 1280, Height: 720, BitrateKbps: 2000, Status: RenditionReady, FileKey: RenditionFileKey("123", "webm", "webm")},
+	}
+
+	expected := "#EXTM3U\n#EXT-X-VERSION:3\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=2928000,RESOLUTION=1280x720,NAME=\"720p\"\n720p/index.m3u8\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=1496000,RESOLUTION=854x480,NAME=\"480p\"\n480p/index.m3u8\n"
+	assert.Equal(t, expected, HLSMasterPlaylist(renditions, (*MediaRendition).RelativePlaylistURI))
+	assert.Empty(t, HLSMasterPlaylist(renditions[1:2], (*MediaRendition).RelativePlaylistURI))
+}
@@ -0,0 +1,36 @@
+package domain
+
+// Role grants access to media management, each role including the access of the ones below it
+type Role string
+
+const (
+	RoleViewer Role = "viewer" // reads media, the role of anonymous requests
+	RoleEditor Role = "editor" // also edits the metadata of any media
+	RoleAdmin  Role = "admin"  // also deletes media and runs admin operations
+)
+
+// Roles lists all roles, from the least to the most privileged
+var Roles = []Role{RoleViewer, RoleEditor, RoleAdmin}
+
+// IsValid checks if the role is known
+func (r Role) IsValid() bool {
+	return r == RoleViewer || r == RoleEditor || r == RoleAdmin
+}
+
+// Includes returns true if the role grants the access of required.
+// Unknown roles grant the access of viewers.
+func (r Role) Includes(required Role) bool {
+	return r.rank() >= required.rank()
+}
+
+// rank orders the roles by privilege
+func (r Role) rank() int {
+	switch r {
+	case RoleAdmin:
+		return 2
+	case RoleEditor:
+		return 1
+	default:
+		return 0
+	}
+}
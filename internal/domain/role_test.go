@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Includes(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		expected bool
+	}{
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleEditor, true},
+		{RoleEditor, RoleEditor, true},
+		{RoleEditor, RoleAdmin, false},
+		{RoleViewer, RoleEditor, false},
+		{RoleViewer, RoleViewer, true},
+		{"", RoleViewer, true},
+		{"owner", RoleEditor, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+string(tt.required), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.role.Includes(tt.required))
+		})
+	}
+}
+
+func TestMedia_IsEditableBy(t *testing.T) {
+	media := &Media{ID: "m1", OwnerID: "u1"}
+
+	assert.True(t, media.IsEditableBy(Viewer{Role: RoleEditor}))
+	assert.True(t, media.IsEditableBy(Viewer{Role: RoleAdmin, IsAdmin: true}))
+	assert.True(t, media.IsEditableBy(Viewer{Role: RoleViewer, UserID: "u1"}))
+	assert.False(t, media.IsEditableBy(Viewer{Role: RoleViewer, UserID: "u2"}))
+	assert.False(t, media.IsEditableBy(Viewer{Role: RoleViewer}))
+	assert.False(t, (&Media{ID: "m2"}).IsEditableBy(Viewer{Role: RoleViewer}))
+}
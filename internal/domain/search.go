@@ -1,34 +1,79 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // SearchRequest represents a search request
 type SearchRequest struct {
 	Query  string `json:"query" form:"query" binding:"required"`
-	Type   string `json:"type,omitempty" form:"type"`     // video, podcast, or empty for all
-	Limit  int    `json:"limit,omitempty" form:"limit"`   // default 20
-	Offset int    `json:"offset,omitempty" form:"offset"` // default 0
+	Type   string `json:"type,omitempty" form:"type"`       // video, podcast, or empty for all
+	ShowID string `json:"show_id,omitempty" form:"show_id"` // episodes of one show, or empty for all
+	Limit  int    `json:"limit,omitempty" form:"limit"`     // default 20
+	Offset int    `json:"offset,omitempty" form:"offset"`   // default 0
+	Safe   bool   `json:"safe,omitempty" form:"safe"`       // exclude explicit content
+	Fresh  bool   `json:"fresh,omitempty" form:"fresh"`     // hydrate hits from CMS, bypassing the cache
+
+	// AudioLanguage narrows results to media with original or dubbed audio in
+	// this language, or empty for all
+	AudioLanguage string `json:"audio_language,omitempty" form:"audio_language"`
+
+	// Collapse is SearchCollapseShow to return one result per show, its best
+	// matching episode with the next ones as inner hits, or empty for every hit
+	Collapse string `json:"collapse,omitempty" form:"collapse"`
+
+	// Parsed is Query parsed and normalized by the search service, repositories search it
+	Parsed *SearchQuery `json:"-" form:"-"`
+}
+
+// StructuredQuery returns the parsed query, or Query taken as plain words,
+// normalized, if it was not parsed
+func (r *SearchRequest) StructuredQuery() *SearchQuery {
+	if r.Parsed != nil {
+		return r.Parsed
+	}
+	return PlainSearchQuery(r.Query).Normalized()
 }
 
+// SearchCollapseShow collapses the episodes of a show into one search result
+const SearchCollapseShow = "show"
+
 // SearchResult represents a search result item
 type SearchResult struct {
 	Media *Media  `json:"media"`
 	Score float64 `json:"score"` // relevance score
+
+	// With collapse=show, the next best matching episodes of the show of
+	// Media, at most CollapseInnerHits, and how many other episodes match
+	InnerHits      []*SearchResult `json:"inner_hits,omitempty"`
+	InnerHitsTotal int64           `json:"inner_hits_total,omitempty"`
+}
+
+// ShowGroup returns the key search results are collapsed by: the show of
+// media, or its own ID when it is not part of a show
+func ShowGroup(media *Media) string {
+	if media.ShowID != "" {
+		return media.ShowID
+	}
+	return "media:" + media.ID
 }
 
 // SearchResponse represents the search response
 type SearchResponse struct {
-	Results []*SearchResult `json:"results"`
-	Total   int64           `json:"total"`
-	Query   string          `json:"query"`
-	Limit   int             `json:"limit"`
-	Offset  int             `json:"offset"`
+	Results    []*SearchResult `json:"results"`
+	Total      int64           `json:"total"`
+	Query      string          `json:"query"`
+	Limit      int             `json:"limit"`
+	Offset     int             `json:"offset"`
+	NextCursor string          `json:"next_cursor,omitempty"` // pass as cursor to get the next page
 }
 
 // SuggestRequest represents a suggestion request
 type SuggestRequest struct {
 	Query string `json:"query" form:"query" binding:"required"`
 	Limit int    `json:"limit,omitempty" form:"limit"` // default 10
+	Safe  bool   `json:"safe,omitempty" form:"safe"`   // exclude explicit content
 }
 
 // Suggestion represents a search suggestion
@@ -43,6 +88,67 @@ type SuggestResponse struct {
 	Query       string        `json:"query"`
 }
 
+// SearchContext is the context of its show indexed with an episode, so searching
+// for the show, its category, its hosts or its guests finds the episode
+type SearchContext struct {
+	ShowTitle  string
+	Categories []string
+	Hosts      []string
+	Guests     []string
+}
+
+// NewSearchContext combines the category and artist of media with the template
+// of its show, which is nil when the show has none, and the people credited on
+// the media or its show
+func NewSearchContext(media *Media, show *ShowTemplate, credits []Credit) *SearchContext {
+	searchContext := &SearchContext{}
+	var categories, hosts, guests []string
+	if show != nil {
+		searchContext.ShowTitle = show.Title
+		categories = append(categories, show.Category)
+		hosts = append(hosts, show.Hosts...)
+	}
+	for _, credit := range credits {
+		if credit.Person == nil {
+			continue
+		}
+		if credit.Role == PersonRoleGuest {
+			guests = append(guests, credit.Person.Name)
+		} else {
+			hosts = append(hosts, credit.Person.Name)
+		}
+	}
+	searchContext.Categories = distinctTerms(append([]string{media.Category}, categories...))
+	searchContext.Hosts = distinctTerms(append(hosts, media.Tags.Artist))
+	searchContext.Guests = distinctTerms(guests)
+	return searchContext
+}
+
+// Text joins the terms of the context into searchable text
+func (c *SearchContext) Text() string {
+	if c == nil {
+		return ""
+	}
+	terms := append([]string{c.ShowTitle}, c.Categories...)
+	terms = append(terms, c.Hosts...)
+	return strings.TrimSpace(strings.Join(append(terms, c.Guests...), " "))
+}
+
+// distinctTerms drops empty and repeated terms, ignoring case
+func distinctTerms(terms []string) []string {
+	distinct := make([]string, 0, len(terms))
+	seen := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" || seen[strings.ToLower(term)] {
+			continue
+		}
+		seen[strings.ToLower(term)] = true
+		distinct = append(distinct, term)
+	}
+	return distinct
+}
+
 // SearchIndex represents a search index entry in the database
 type SearchIndex struct {
 	ID          string    `json:"id" gorm:"primaryKey"`
@@ -51,10 +157,68 @@ type SearchIndex struct {
 	Description string    `json:"description"`
 	Content     string    `json:"content"`                      // combined searchable text
 	Type        MediaType `json:"type" gorm:"type:varchar(20)"` // video, podcast
+	AgeRating   AgeRating `json:"age_rating" gorm:"type:varchar(10)"`
+	Explicit    bool      `json:"explicit" gorm:"not null;default:false"` // explicit flag or adult age rating
+	ShowID      string    `json:"show_id,omitempty" gorm:"index"`
+	Tags        []string  `json:"tags,omitempty" gorm:"serializer:json;type:jsonb"` // labels of the media, filtered with tag:
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Original and dubbed audio languages, filtered with audio_language
+	AudioLanguages []string `json:"audio_languages,omitempty" gorm:"serializer:json;type:jsonb"`
+
+	// Update time of the media version the entry was built from, zero for
+	// entries indexed before it was recorded
+	MediaUpdatedAt *time.Time `json:"media_updated_at,omitempty"`
 }
 
 // TableName specifies the table name for SearchIndex
 func (SearchIndex) TableName() string {
 	return "search_index"
 }
+
+// SearchIndexRemoval records the removal of media from the search index, so a
+// rebuild running meanwhile drops it from the rebuilt index too
+type SearchIndexRemoval struct {
+	MediaID   string    `json:"media_id" gorm:"primaryKey"`
+	RemovedAt time.Time `json:"removed_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for SearchIndexRemoval
+func (SearchIndexRemoval) TableName() string {
+	return "search_index_removals"
+}
+
+// IndexedMedia identifies a document of the search index and the media
+// version it was built from
+type IndexedMedia struct {
+	MediaID   string    `json:"media_id"`
+	UpdatedAt time.Time `json:"updated_at"` // zero when the index did not record it
+}
+
+// IsStaleFor returns true if the document was built from another version of media.
+// Times are compared to the millisecond, the precision search engines keep.
+func (i *IndexedMedia) IsStaleFor(media *Media) bool {
+	return !i.UpdatedAt.Truncate(time.Millisecond).Equal(media.UpdatedAt.Truncate(time.Millisecond))
+}
+
+// Suggestion term kinds
+const (
+	SuggestionKindTitle  = "title"
+	SuggestionKindArtist = "artist"
+	SuggestionKindAlbum  = "album"
+)
+
+// SearchSuggestion is a precomputed suggestion term with the number of indexed
+// media it appears in, refreshed periodically from the search index
+type SearchSuggestion struct {
+	Term        string    `json:"term" gorm:"primaryKey"`
+	Kind        string    `json:"kind" gorm:"primaryKey;type:varchar(10)"` // title, artist or album
+	Count       int64     `json:"count" gorm:"not null"`
+	SafeCount   int64     `json:"safe_count" gorm:"not null"` // media without explicit content
+	RefreshedAt time.Time `json:"refreshed_at"`
+}
+
+// TableName specifies the table name for SearchSuggestion
+func (SearchSuggestion) TableName() string {
+	return "search_suggestions"
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Arabic affixes removed by light stemming, longest first, in their folded spelling
+var (
+	arabicPrefixes = []string{"وال", "بال", "كال", "فال", "لل", "ال"}
+	arabicSuffixes = []string{"ها", "ان", "ات", "ون", "ين", "يه", "ه", "ي"}
+)
+
+// NormalizeSearchText folds text to the form queries and indexed content are
+// matched in, so that equivalent spellings match: it is lowercased, stripped
+// of diacritics (Arabic harakat and tatweel, Latin accents), alef, yaa and
+// taa marbuta variants are unified, Arabic-Indic digits become ASCII digits,
+// punctuation separates words, and words are lightly stemmed (English plural,
+// -ing and -ed endings, Arabic articles and common suffixes).
+func NormalizeSearchText(text string) string {
+	words := strings.FieldsFunc(foldSearchText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for i, word := range words {
+		words[i] = stemSearchWord(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// Normalized returns a copy of the query with the text of its terms
+// normalized by NormalizeSearchText. Terms without any word to normalize,
+// like punctuation, are kept as they are.
+func (q *SearchQuery) Normalized() *SearchQuery {
+	normalized := *q
+	normalized.Clauses = make([]SearchClause, len(q.Clauses))
+	for i, clause := range q.Clauses {
+		normalized.Clauses[i] = SearchClause{
+			Terms:    normalizeSearchTerms(clause.Terms),
+			Excluded: normalizeSearchTerms(clause.Excluded),
+		}
+	}
+	return &normalized
+}
+
+// normalizeSearchTerms returns a copy of terms with their text normalized
+func normalizeSearchTerms(terms []SearchTerm) []SearchTerm {
+	if terms == nil {
+		return nil
+	}
+	normalized := make([]SearchTerm, len(terms))
+	for i, term := range terms {
+		normalized[i] = term
+		if text := NormalizeSearchText(term.Text); text != "" {
+			normalized[i].Text = text
+		}
+	}
+	return normalized
+}
+
+// foldSearchText lowercases text and removes the spelling variations of
+// letters and digits
+func foldSearchText(text string) string {
+	var folded strings.Builder
+	// Decomposition splits accents and hamzas from their letters, e.g. أ into ا and a hamza mark
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r), r == 'ـ':
+			continue
+		case r == 'ٱ':
+			r = 'ا'
+		case r == 'ى':
+			r = 'ي'
+		case r == 'ة':
+			r = 'ه'
+		case r >= '٠' && r <= '٩':
+			r = '0' + (r - '٠')
+		case r >= '۰' && r <= '۹':
+			r = '0' + (r - '۰')
+		}
+		folded.WriteRune(r)
+	}
+	return folded.String()
+}
+
+// stemSearchWord removes the common affixes of a folded Arabic or English word
+func stemSearchWord(word string) string {
+	first, _ := utf8.DecodeRuneInString(word)
+	switch {
+	case unicode.Is(unicode.Arabic, first):
+		return stemArabicWord(word)
+	case isASCIILetters(word):
+		return stemEnglishWord(word)
+	}
+	return word
+}
+
+// stemArabicWord removes the definite article with its attached particles,
+// keeping at least two letters, and one suffix, keeping at least three, like
+// the light stemmers used for Arabic retrieval
+func stemArabicWord(word string) string {
+	for _, prefix := range arabicPrefixes {
+		if rest, ok := strings.CutPrefix(word, prefix); ok && len([]rune(rest)) >= 2 {
+			word = rest
+			break
+		}
+	}
+	for _, suffix := range arabicSuffixes {
+		if rest, ok := strings.CutSuffix(word, suffix); ok && len([]rune(rest)) >= 3 {
+			return rest
+		}
+	}
+	return word
+}
+
+// stemEnglishWord removes a plural ending, then an -ing or -ed ending from
+// words long enough to keep a stem with a vowel
+func stemEnglishWord(word string) string {
+	switch n := len(word); {
+	case strings.HasSuffix(word, "ies") && n > 4:
+		word = word[:n-3] + "y"
+	case strings.HasSuffix(word, "es") && n > 4 && strings.ContainsAny(word[n-3:n-2], "sxz"),
+		strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes"):
+		word = word[:n-2]
+	case strings.HasSuffix(word, "s") && n > 3 && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = word[:n-1]
+	}
+
+	for _, suffix := range []string{"ing", "ed"} {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if !ok || len(stem) < 3 || !strings.ContainsAny(stem, "aeiouy") {
+			continue
+		}
+		// running -> run, but not falling -> fal
+		if n := len(stem); stem[n-1] == stem[n-2] && !strings.ContainsAny(stem[n-1:], "aeioulsz") {
+			stem = stem[:n-1]
+		}
+		return stem
+	}
+	return word
+}
+
+// isASCIILetters returns true if word only has ASCII letters
+func isASCIILetters(word string) bool {
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearchText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"lowercases and splits punctuation", "Go: Tips, Tricks & X-Ray", "go tip trick x ray"},
+		{"latin accents", "Café Résumé", "cafe resume"},
+		{"english plurals", "stories classes boxes episodes bus news", "story class box episode bus new"},
+		{"english verb endings", "cooking running falling needed sing", "cook run fall need sing"},
+		{"arabic diacritics and tatweel", "مُحَمَّد الـعـربـيـة", "محمد عرب"},
+		{"alef variants", "أحمد إسلام آمنة ٱلقرآن", "احمد اسلام امن قران"},
+		{"yaa and taa marbuta", "مستشفى مدرسة", "مستشف مدرس"},
+		{"arabic articles and suffixes", "والكتاب بالبيت للطلاب كتابها المعلمون", "كتاب بيت طلاب كتاب معلم"},
+		{"short arabic words kept", "من في هو", "من في هو"},
+		{"arabic-indic digits", "الحلقة ١٢ ۳", "حلق 12 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeSearchText(tt.text))
+		})
+	}
+}
+
+func TestSearchQuery_Normalized(t *testing.T) {
+	query := &SearchQuery{
+		Clauses: []SearchClause{{
+			Terms:    []SearchTerm{{Text: "Cooking Shows", Phrase: true}, {Text: "-"}},
+			Excluded: []SearchTerm{{Text: "الأطفال"}},
+		}},
+		Tags: []string{"news"},
+	}
+
+	normalized := query.Normalized()
+
+	assert.Equal(t, &SearchQuery{
+		Clauses: []SearchClause{{
+			Terms:    []SearchTerm{{Text: "cook show", Phrase: true}, {Text: "-"}},
+			Excluded: []SearchTerm{{Text: "اطفال"}},
+		}},
+		Tags: []string{"news"},
+	}, normalized)
+	assert.Equal(t, "Cooking Shows", query.Clauses[0].Terms[0].Text, "the query is left unchanged")
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// SearchQuery is the structured form of a search query string
+type SearchQuery struct {
+	// Clauses are alternatives joined by OR, hits match at least one of them.
+	// A query of filters only has none and matches everything they allow.
+	Clauses      []SearchClause `json:"clauses,omitempty"`
+	Tags         []string       `json:"tags,omitempty"`          // labels hits carry, all of them
+	ExcludedTags []string       `json:"excluded_tags,omitempty"` // labels hits must not carry
+	Type         string         `json:"type,omitempty"`          // video, podcast, or empty for all
+}
+
+// MatchesTags returns true if labels, normalized, carry every tag of the query and none of its excluded tags
+func (q *SearchQuery) MatchesTags(labels []string) bool {
+	for _, tag := range q.Tags {
+		if !slices.Contains(labels, tag) {
+			return false
+		}
+	}
+	for _, tag := range q.ExcludedTags {
+		if slices.Contains(labels, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchClause matches media containing all of its terms and none of its excluded terms
+type SearchClause struct {
+	Terms    []SearchTerm `json:"terms,omitempty"`
+	Excluded []SearchTerm `json:"excluded,omitempty"`
+}
+
+// SearchTerm is a word, or words that must appear together in order
+type SearchTerm struct {
+	Text   string `json:"text"`
+	Phrase bool   `json:"phrase,omitempty"`
+}
+
+// Search query syntax
+const (
+	searchOperatorOr  = "OR"
+	searchOperatorAnd = "AND" // the default between terms
+	searchOperatorNot = "NOT"
+	searchFieldTag    = "tag"
+	searchFieldType   = "type"
+)
+
+// PlainSearchQuery returns a query matching media containing every word of
+// query, with no syntax
+func PlainSearchQuery(query string) *SearchQuery {
+	var clause SearchClause
+	for _, word := range strings.Fields(query) {
+		clause.Terms = append(clause.Terms, SearchTerm{Text: word})
+	}
+	if len(clause.Terms) == 0 {
+		return &SearchQuery{}
+	}
+	return &SearchQuery{Clauses: []SearchClause{clause}}
+}
+
+// ParseSearchQuery parses the advanced search syntax: words that must all
+// match, "quoted phrases", tag:news and type:podcast filters, -word and NOT
+// exclusions, and OR between alternatives. AND is implied between terms and
+// binds tighter than OR.
+func ParseSearchQuery(query string) (*SearchQuery, error) {
+	parsed := &SearchQuery{}
+	clause := SearchClause{}
+	negate := false
+	endClause := func() {
+		if len(clause.Terms) > 0 || len(clause.Excluded) > 0 {
+			parsed.Clauses = append(parsed.Clauses, clause)
+		}
+		clause = SearchClause{}
+	}
+
+	for _, token := range tokenizeSearchQuery(query) {
+		if !token.quoted {
+			switch token.text {
+			case searchOperatorOr:
+				endClause()
+				negate = false
+				continue
+			case searchOperatorAnd:
+				continue
+			case searchOperatorNot:
+				negate = true
+				continue
+			}
+		}
+		negated := negate != token.negated
+		negate = false
+
+		if token.field != "" {
+			if err := parsed.addFilter(token.field, token.text, negated); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		term := SearchTerm{Text: token.text, Phrase: token.quoted && strings.ContainsFunc(token.text, unicode.IsSpace)}
+		if negated {
+			clause.Excluded = append(clause.Excluded, term)
+		} else {
+			clause.Terms = append(clause.Terms, term)
+		}
+	}
+	endClause()
+
+	if len(parsed.Clauses) == 0 && len(parsed.Tags) == 0 && len(parsed.ExcludedTags) == 0 && parsed.Type == "" {
+		return nil, NewBusinessError("INVALID_SEARCH_QUERY", "Search query has nothing to search for")
+	}
+	if len(parsed.Clauses) > 1 {
+		for _, alternative := range parsed.Clauses {
+			if len(alternative.Terms) == 0 {
+				return nil, NewBusinessErrorWithDetails("INVALID_SEARCH_QUERY", "Every alternative of OR needs a term to match",
+					"exclusions like -word only narrow down the terms next to them")
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// addFilter applies a field:value filter of the query
+func (q *SearchQuery) addFilter(field, value string, negated bool) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch field {
+	case searchFieldTag:
+		if negated {
+			q.ExcludedTags = append(q.ExcludedTags, value)
+		} else {
+			q.Tags = append(q.Tags, value)
+		}
+	case searchFieldType:
+		if negated {
+			return NewBusinessErrorWithDetails("INVALID_SEARCH_QUERY", "Types cannot be excluded", "search one type with type:video or type:podcast")
+		}
+		if value != string(TypeVideo) && value != string(TypePodcast) {
+			return NewBusinessErrorWithDetails("INVALID_SEARCH_QUERY", "Unknown media type", "type must be video or podcast, got "+value)
+		}
+		if q.Type != "" && q.Type != value {
+			return NewBusinessError("INVALID_SEARCH_QUERY", "Search query filters more than one type")
+		}
+		q.Type = value
+	}
+	return nil
+}
+
+// searchToken is a word, quoted text or field:value filter of a query
+type searchToken struct {
+	text    string
+	field   string // tag or type for filters
+	quoted  bool
+	negated bool // prefixed with -
+}
+
+// tokenizeSearchQuery splits a query into tokens. Quotes group words, an
+// unterminated quote runs to the end of the query. Fields other than tag and
+// type are taken as words.
+func tokenizeSearchQuery(query string) []searchToken {
+	var tokens []searchToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		token := searchToken{}
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			token.negated = true
+			i++
+		}
+
+		// A field name is followed by a colon and a value
+		start := i
+		for i < len(runes) && unicode.IsLetter(runes[i]) {
+			i++
+		}
+		if i < len(runes) && runes[i] == ':' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			if field := strings.ToLower(string(runes[start:i])); field == searchFieldTag || field == searchFieldType {
+				token.field = field
+				i++
+				start = i
+			}
+		}
+		i = start
+
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			token.text = strings.TrimSpace(string(runes[i+1 : end]))
+			token.quoted = true
+			i = min(end+1, len(runes))
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			token.text = string(runes[i:end])
+			i = end
+		}
+
+		if token.text != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected *SearchQuery
+	}{
+		{
+			name:  "plain words",
+			query: "golang  concurrency",
+			expected: &SearchQuery{Clauses: []SearchClause{
+				{Terms: []SearchTerm{{Text: "golang"}, {Text: "concurrency"}}},
+			}},
+		},
+		{
+			name:  "phrases and exclusions",
+			query: `"clean code" -java NOT "design patterns" "go"`,
+			expected: &SearchQuery{Clauses: []SearchClause{{
+				Terms:    []SearchTerm{{Text: "clean code", Phrase: true}, {Text: "go"}},
+				Excluded: []SearchTerm{{Text: "java"}, {Text: "design patterns", Phrase: true}},
+			}}},
+		},
+		{
+			name:  "filters",
+			query: `tag:News tag:"Middle East" -tag:sports TYPE:podcast`,
+			expected: &SearchQuery{
+				Tags:         []string{"news", "middle east"},
+				ExcludedTags: []string{"sports"},
+				Type:         "podcast",
+			},
+		},
+		{
+			name:  "boolean operators",
+			query: "golang AND channels OR rust -unsafe or tag:tech",
+			expected: &SearchQuery{
+				Clauses: []SearchClause{
+					{Terms: []SearchTerm{{Text: "golang"}, {Text: "channels"}}},
+					{Terms: []SearchTerm{{Text: "rust"}, {Text: "or"}}, Excluded: []SearchTerm{{Text: "unsafe"}}},
+				},
+				Tags: []string{"tech"},
+			},
+		},
+		{
+			name:  "operators and unknown fields quoted or as words",
+			query: `"OR" title:go - x-ray "unterminated quote`,
+			expected: &SearchQuery{Clauses: []SearchClause{{Terms: []SearchTerm{
+				{Text: "OR"}, {Text: "title:go"}, {Text: "-"}, {Text: "x-ray"}, {Text: "unterminated quote", Phrase: true},
+			}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseSearchQuery(tt.query)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parsed)
+		})
+	}
+}
+
+func TestParseSearchQuery_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"empty", `  "" OR `},
+		{"unknown type", "type:movie"},
+		{"excluded type", "golang -type:video"},
+		{"conflicting types", "type:video type:podcast"},
+		{"alternative without terms", "golang OR -java"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSearchQuery(tt.query)
+
+			var businessErr *BusinessError
+			require.True(t, errors.As(err, &businessErr))
+			assert.Equal(t, "INVALID_SEARCH_QUERY", businessErr.Code)
+		})
+	}
+}
+
+func TestSearchRequest_StructuredQuery(t *testing.T) {
+	req := &SearchRequest{Query: `"Clean code" -java`}
+	assert.Equal(t, &SearchQuery{Clauses: []SearchClause{
+		{Terms: []SearchTerm{{Text: "clean"}, {Text: "code"}, {Text: "java"}}},
+	}}, req.StructuredQuery(), "words are taken literally and normalized")
+
+	req.Parsed = &SearchQuery{Type: "video"}
+	assert.Same(t, req.Parsed, req.StructuredQuery())
+}
@@ -169,3 +169,28 @@ func TestSuggestResponse_EmptySuggestions(t *testing.T) {
 	assert.Empty(t, response.Suggestions)
 	assert.Equal(t, "no suggestions", response.Query)
 }
+
+func TestNewSearchContext(t *testing.T) {
+	media := &Media{Category: "Technology", Tags: MediaTags{Artist: "Guest Host"}}
+	show := &ShowTemplate{Title: "Fnjan", Category: "technology", Hosts: []string{"Main Host"}}
+
+	credits := []Credit{
+		{Person: &Person{Name: "Co Host"}, Role: PersonRoleHost},
+		{Person: &Person{Name: "Visitor"}, Role: PersonRoleGuest},
+	}
+
+	searchContext := NewSearchContext(media, show, credits)
+	assert.Equal(t, "Fnjan", searchContext.ShowTitle)
+	assert.Equal(t, []string{"Technology"}, searchContext.Categories)
+	assert.Equal(t, []string{"Main Host", "Co Host", "Guest Host"}, searchContext.Hosts)
+	assert.Equal(t, []string{"Visitor"}, searchContext.Guests)
+	assert.Equal(t, "Fnjan Technology Main Host Co Host Guest Host Visitor", searchContext.Text())
+
+	// Without a show template only the metadata of the media is used
+	searchContext = NewSearchContext(&Media{}, nil, nil)
+	assert.Empty(t, searchContext.ShowTitle)
+	assert.Empty(t, searchContext.Categories)
+	assert.Empty(t, searchContext.Hosts)
+	assert.Empty(t, searchContext.Guests)
+	assert.Empty(t, (*SearchContext)(nil).Text())
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Series limits
+const (
+	MaxSeriesIDLength = 100
+	MaxSeriesPart     = 10000
+)
+
+// seriesPartPattern finds a numbered part in a title, like "Episode 12", "Pt. 3" or "الحلقة ٤"
+var seriesPartPattern = regexp.MustCompile(
+	`(?i)(?:^|[\s\-–—:|(\[,])(episode|ep\.?|part|pt\.?|chapter|الحلقة|حلقة|الجزء|جزء)\s*#?\s*([0-9٠-٩]+)(?:$|[^\p{L}\p{N}])`)
+
+// DetectSeriesPart finds the numbered part in a title. The key is the normalized title
+// before the number, shared by the parts of a series and empty for titles like "Episode 12".
+func DetectSeriesPart(title string) (string, int, bool) {
+	match := seriesPartPattern.FindStringSubmatchIndex(title)
+	if match == nil {
+		return "", 0, false
+	}
+
+	part, err := strconv.Atoi(westernDigits(title[match[4]:match[5]]))
+	if err != nil || part < 1 || part > MaxSeriesPart {
+		return "", 0, false
+	}
+
+	return normalizeSeriesKey(title[:match[2]]), part, true
+}
+
+// westernDigits replaces Arabic-Indic digits by their western equivalents
+func westernDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '٠' && r <= '٩' {
+			return '0' + (r - '٠')
+		}
+		return r
+	}, value)
+}
+
+// normalizeSeriesKey lowercases a title prefix, collapses its spaces and drops trailing separators
+func normalizeSeriesKey(prefix string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(prefix), " "))
+	return strings.TrimRightFunc(key, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || r == '|'
+	})
+}
+
+// DetectSeries links the media to the series numbered in its title, within its show.
+// Media whose series was set by an editor is left alone.
+func (m *Media) DetectSeries() {
+	if m.SeriesLocked {
+		return
+	}
+	m.SeriesID, m.SeriesPart = "", 0
+
+	key, part, ok := DetectSeriesPart(m.Title)
+	if !ok || (key == "" && m.ShowID == "") {
+		return
+	}
+
+	tenantID := m.TenantID
+	if tenantID == "" {
+		tenantID = DefaultTenantID
+	}
+	sum := sha256.Sum256([]byte(tenantID + "\n" + m.ShowID + "\n" + key))
+	m.SeriesID = hex.EncodeToString(sum[:16])
+	m.SeriesPart = part
+}
+
+// SeriesRequest represents an editor's request to link a media item to a series
+type SeriesRequest struct {
+	SeriesID string `json:"series_id"` // an existing series, or a new one
+	Part     int    `json:"part"`
+}
+
+// Validate validates the series request
+func (r *SeriesRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	r.SeriesID = strings.TrimSpace(r.SeriesID)
+	if r.SeriesID == "" {
+		errs.Add("series_id", "is required")
+	} else if len(r.SeriesID) > MaxSeriesIDLength {
+		errs.Add("series_id", fmt.Sprintf("must be at most %d characters", MaxSeriesIDLength))
+	}
+	if r.Part < 1 || r.Part > MaxSeriesPart {
+		errs.Add("part", fmt.Sprintf("must be between 1 and %d", MaxSeriesPart))
+	}
+
+	return errs
+}
+
+// SeriesEntry is a part of a series
+type SeriesEntry struct {
+	MediaID string `json:"media_id"`
+	Title   string `json:"title"`
+	Part    int    `json:"part"`
+}
+
+// SeriesResponse places a media item within its series
+type SeriesResponse struct {
+	SeriesID string        `json:"series_id,omitempty"` // empty when the media is not part of a series
+	MediaID  string        `json:"media_id"`
+	Part     int           `json:"part,omitempty"`
+	Locked   bool          `json:"locked"` // set by an editor rather than detected from the title
+	Previous *SeriesEntry  `json:"previous,omitempty"`
+	Next     *SeriesEntry  `json:"next,omitempty"`
+	Parts    []SeriesEntry `json:"parts"`
+}
+
+// NewSeriesResponse builds the series of media from its parts, in part order
+func NewSeriesResponse(media *Media, parts []*Media) *SeriesResponse {
+	response := &SeriesResponse{
+		SeriesID: media.SeriesID,
+		MediaID:  media.ID,
+		Part:     media.SeriesPart,
+		Locked:   media.SeriesLocked,
+		Parts:    make([]SeriesEntry, 0, len(parts)),
+	}
+
+	current := -1
+	for _, part := range parts {
+		if part.ID == media.ID {
+			current = len(response.Parts)
+		}
+		response.Parts = append(response.Parts, SeriesEntry{
+			MediaID: part.ID,
+			Title:   part.Title,
+			Part:    part.SeriesPart,
+		})
+	}
+	if current > 0 {
+		response.Previous = &response.Parts[current-1]
+	}
+	if current >= 0 && current < len(response.Parts)-1 {
+		response.Next = &response.Parts[current+1]
+	}
+
+	return response
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectSeriesPart(t *testing.T) {
+	tests := []struct {
+		title string
+		key   string
+		part  int
+		ok    bool
+	}{
+		{"The Founders - Episode 12", "the founders", 12, true},
+		{"The  Founders: Part 3 (Finale)", "the founders", 3, true},
+		{"Deep Dive Ep. 7", "deep dive", 7, true},
+		{"Deep Dive ep7", "deep dive", 7, true},
+		{"History, Pt 2", "history", 2, true},
+		{"Chapter 1: Beginnings", "", 1, true},
+		{"قصة المدينة - الحلقة ٤", "قصة المدينة", 4, true},
+		{"قصة المدينة الجزء 2", "قصة المدينة", 2, true},
+		{"Party 3 recap", "", 0, false},
+		{"Deep 3", "", 0, false},
+		{"Episode 12b", "", 0, false},
+		{"Part 0", "", 0, false},
+		{"Interview with the author", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			key, part, ok := DetectSeriesPart(tt.title)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.key, key)
+			assert.Equal(t, tt.part, part)
+		})
+	}
+}
+
+func TestMedia_DetectSeries(t *testing.T) {
+	first := &Media{Title: "The Founders - Episode 1", ShowID: "show"}
+	second := &Media{Title: "the founders: episode 2", ShowID: "show", TenantID: DefaultTenantID}
+	otherShow := &Media{Title: "The Founders - Episode 3", ShowID: "other"}
+	first.DetectSeries()
+	second.DetectSeries()
+	otherShow.DetectSeries()
+
+	assert.NotEmpty(t, first.SeriesID)
+	assert.Equal(t, 1, first.SeriesPart)
+	assert.Equal(t, first.SeriesID, second.SeriesID)
+	assert.Equal(t, 2, second.SeriesPart)
+	assert.NotEqual(t, first.SeriesID, otherShow.SeriesID)
+
+	// A bare episode number needs a show to belong to
+	loose := &Media{Title: "Episode 4"}
+	loose.DetectSeries()
+	assert.Empty(t, loose.SeriesID)
+
+	// A retitled part leaves its series, unless an editor set it
+	first.Title = "The Founders: a retrospective"
+	first.DetectSeries()
+	assert.Empty(t, first.SeriesID)
+	assert.Zero(t, first.SeriesPart)
+
+	locked := &Media{Title: "Bonus", SeriesID: "founders", SeriesPart: 9, SeriesLocked: true}
+	locked.DetectSeries()
+	assert.Equal(t, "founders", locked.SeriesID)
+	assert.Equal(t, 9, locked.SeriesPart)
+}
+
+func TestSeriesRequest_Validate(t *testing.T) {
+	valid := SeriesRequest{SeriesID: " founders ", Part: 2}
+	assert.False(t, valid.Validate().HasErrors())
+	assert.Equal(t, "founders", valid.SeriesID)
+
+	invalid := SeriesRequest{SeriesID: "  ", Part: 0}
+	errs := invalid.Validate()
+	assert.Len(t, errs, 2)
+}
+
+func TestNewSeriesResponse(t *testing.T) {
+	parts := []*Media{
+		{ID: "a", Title: "Part 1", SeriesID: "s", SeriesPart: 1},
+		{ID: "b", Title: "Part 2", SeriesID: "s", SeriesPart: 2},
+		{ID: "c", Title: "Part 3", SeriesID: "s", SeriesPart: 3},
+	}
+
+	middle := NewSeriesResponse(parts[1], parts)
+	assert.Equal(t, "s", middle.SeriesID)
+	assert.Equal(t, 2, middle.Part)
+	assert.Len(t, middle.Parts, 3)
+	assert.Equal(t, "a", middle.Previous.MediaID)
+	assert.Equal(t, "c", middle.Next.MediaID)
+
+	first := NewSeriesResponse(parts[0], parts)
+	assert.Nil(t, first.Previous)
+	assert.Equal(t, "b", first.Next.MediaID)
+
+	alone := NewSeriesResponse(&Media{ID: "x"}, nil)
+	assert.Empty(t, alone.SeriesID)
+	assert.NotNil(t, alone.Parts)
+	assert.Nil(t, alone.Next)
+}
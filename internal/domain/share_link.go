@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Share link lifetimes
+const (
+	DefaultShareLinkTTL = 7 * 24 * time.Hour
+	MaxShareLinkTTL     = 90 * 24 * time.Hour
+)
+
+// ShareLink grants time-limited access to a single media item through a token.
+// Only the SHA-256 hash of the token is stored.
+type ShareLink struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	MediaID   string     `json:"media_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for ShareLink
+func (ShareLink) TableName() string {
+	return "share_links"
+}
+
+// IsActive returns true if the link is neither revoked nor expired at the given time
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// HashShareToken returns the stored representation of a share token
+func HashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ShareLinkRequest represents a request to create a share link
+type ShareLinkRequest struct {
+	ExpiresIn int64 `json:"expires_in"` // seconds, defaults to 7 days
+}
+
+// TTL returns the requested lifetime of the link
+func (r *ShareLinkRequest) TTL() time.Duration {
+	if r.ExpiresIn == 0 {
+		return DefaultShareLinkTTL
+	}
+	return time.Duration(r.ExpiresIn) * time.Second
+}
+
+// Validate validates the share link request
+func (r *ShareLinkRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if r.ExpiresIn < 0 || r.TTL() > MaxShareLinkTTL {
+		errs.Add("expires_in", "must be between 1 second and 90 days")
+	}
+
+	return errs
+}
+
+// CreatedShareLink is returned once on creation and is the only place the token appears
+type CreatedShareLink struct {
+	*ShareLink
+	Token string `json:"token"`
+}
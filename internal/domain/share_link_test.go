@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareLink_IsActive(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		link     ShareLink
+		expected bool
+	}{
+		{
+			name:     "active link",
+			link:     ShareLink{ExpiresAt: now.Add(time.Hour)},
+			expected: true,
+		},
+		{
+			name:     "expired link",
+			link:     ShareLink{ExpiresAt: now.Add(-time.Hour)},
+			expected: false,
+		},
+		{
+			name:     "revoked link",
+			link:     ShareLink{ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.link.IsActive(now))
+		})
+	}
+}
+
+func TestShareLinkRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     ShareLinkRequest
+		expectError bool
+		expectedTTL time.Duration
+	}{
+		{
+			name:        "default lifetime",
+			request:     ShareLinkRequest{},
+			expectedTTL: DefaultShareLinkTTL,
+		},
+		{
+			name:        "custom lifetime",
+			request:     ShareLinkRequest{ExpiresIn: 3600},
+			expectedTTL: time.Hour,
+		},
+		{
+			name:        "negative lifetime",
+			request:     ShareLinkRequest{ExpiresIn: -1},
+			expectError: true,
+		},
+		{
+			name:        "lifetime over maximum",
+			request:     ShareLinkRequest{ExpiresIn: int64((MaxShareLinkTTL + time.Hour) / time.Second)},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.request.Validate()
+			assert.Equal(t, tt.expectError, errs.HasErrors())
+			if !tt.expectError {
+				assert.Equal(t, tt.expectedTTL, tt.request.TTL())
+			}
+		})
+	}
+}
+
+func TestHashShareToken(t *testing.T) {
+	hash := HashShareToken("token")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashShareToken("token"))
+	assert.NotEqual(t, hash, HashShareToken("other"))
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Limits of the show metadata indexed with episodes
+const (
+	MaxShowTitleLength = 200
+	MaxShowHosts       = 10
+	MaxHostNameLength  = 100
+)
+
+// ShowTemplate holds the default metadata of the episodes of a show, applied
+// when they are uploaded unless the upload request sets them. The title and
+// hosts of the show are not copied to episodes, they are indexed with them.
+type ShowTemplate struct {
+	ShowID     string    `json:"show_id" gorm:"primaryKey"`
+	Title      string    `json:"title,omitempty"`
+	Hosts      []string  `json:"hosts,omitempty" gorm:"serializer:json;type:jsonb"`
+	Labels     []string  `json:"labels" gorm:"serializer:json;type:jsonb"`
+	Category   string    `json:"category,omitempty" gorm:"type:varchar(100)"`
+	ArtworkKey string    `json:"artwork_key,omitempty"` // storage key of the show art
+	Explicit   bool      `json:"explicit" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for ShowTemplate
+func (ShowTemplate) TableName() string {
+	return "show_templates"
+}
+
+// ApplyTo fills the metadata of a new episode that its upload request left unset.
+// A request with a content rating keeps its explicit flag, even when false.
+func (t *ShowTemplate) ApplyTo(media *Media, req *UploadRequest) {
+	if req.Labels == nil {
+		media.Labels = append([]string(nil), t.Labels...)
+	}
+	if req.Category == "" {
+		media.Category = t.Category
+	}
+	if media.Tags.ArtworkKey == "" {
+		media.Tags.ArtworkKey = t.ArtworkKey
+	}
+	if req.ContentRating.AgeRating == "" && !req.ContentRating.Explicit {
+		media.ContentRating.Explicit = t.Explicit
+	}
+}
+
+// ShowTemplateRequest represents a request to set the template of a show
+type ShowTemplateRequest struct {
+	Title      string   `json:"title"`
+	Hosts      []string `json:"hosts"`
+	Labels     []string `json:"labels"`
+	Category   string   `json:"category"`
+	ArtworkKey string   `json:"artwork_key"`
+	Explicit   bool     `json:"explicit"`
+}
+
+// Validate validates the template request
+func (r *ShowTemplateRequest) Validate() ValidationErrors {
+	errs := ValidateLabels("labels", NormalizeLabels(r.Labels))
+	errs = append(errs, ValidateCategory("category", strings.TrimSpace(r.Category))...)
+	if len(strings.TrimSpace(r.Title)) > MaxShowTitleLength {
+		errs.Add("title", fmt.Sprintf("must be at most %d characters", MaxShowTitleLength))
+	}
+	hosts := normalizeHosts(r.Hosts)
+	if len(hosts) > MaxShowHosts {
+		errs.Add("hosts", fmt.Sprintf("must have at most %d hosts", MaxShowHosts))
+	}
+	for _, host := range hosts {
+		if len(host) > MaxHostNameLength {
+			errs.Add("hosts", fmt.Sprintf("host %q is longer than %d characters", host, MaxHostNameLength))
+		}
+	}
+	return errs
+}
+
+// normalizeHosts trims host names and drops empty and repeated ones, ignoring case
+func normalizeHosts(hosts []string) []string {
+	normalized := make([]string, 0, len(hosts))
+	seen := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		host = strings.Join(strings.Fields(host), " ")
+		if host == "" || seen[strings.ToLower(host)] {
+			continue
+		}
+		seen[strings.ToLower(host)] = true
+		normalized = append(normalized, host)
+	}
+	return normalized
+}
+
+// ApplyTo applies the template request to a template
+func (r *ShowTemplateRequest) ApplyTo(template *ShowTemplate) {
+	template.Title = strings.TrimSpace(r.Title)
+	template.Hosts = normalizeHosts(r.Hosts)
+	template.Labels = NormalizeLabels(r.Labels)
+	if template.Labels == nil {
+		template.Labels = []string{}
+	}
+	template.Category = strings.TrimSpace(r.Category)
+	template.ArtworkKey = strings.TrimSpace(r.ArtworkKey)
+	template.Explicit = r.Explicit
+}
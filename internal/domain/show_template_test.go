@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowTemplate_ApplyTo(t *testing.T) {
+	template := &ShowTemplate{
+		ShowID:     "show-1",
+		Labels:     []string{"tech", "interviews"},
+		Category:   "Technology",
+		ArtworkKey: "shows/show-1/artwork.jpg",
+		Explicit:   true,
+	}
+
+	tests := []struct {
+		name         string
+		request      UploadRequest
+		wantLabels   []string
+		wantCategory string
+		wantExplicit bool
+	}{
+		{
+			name:         "defaults from the template",
+			request:      UploadRequest{ShowID: "show-1"},
+			wantLabels:   []string{"tech", "interviews"},
+			wantCategory: "Technology",
+			wantExplicit: true,
+		},
+		{
+			name:         "request overrides",
+			request:      UploadRequest{ShowID: "show-1", Labels: []string{"News"}, Category: "News", ContentRating: ContentRating{AgeRating: AgeRatingAll}},
+			wantLabels:   []string{"news"},
+			wantCategory: "News",
+			wantExplicit: false,
+		},
+		{
+			name:         "empty labels clear the template labels",
+			request:      UploadRequest{ShowID: "show-1", Labels: []string{}},
+			wantLabels:   []string{},
+			wantCategory: "Technology",
+			wantExplicit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			media := tt.request.ToMedia("media-1", "/uploads/media-1.mp3")
+
+			// When
+			template.ApplyTo(media, &tt.request)
+
+			// Then
+			assert.Equal(t, tt.wantLabels, media.Labels)
+			assert.Equal(t, tt.wantCategory, media.Category)
+			assert.Equal(t, tt.wantExplicit, media.ContentRating.Explicit)
+			assert.Equal(t, template.ArtworkKey, media.Tags.ArtworkKey)
+		})
+	}
+}
+
+func TestShowTemplateRequest_Validate(t *testing.T) {
+	tooMany := make([]string, MaxLabels+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+
+	assert.False(t, (&ShowTemplateRequest{Labels: []string{"tech", " Tech "}}).Validate().HasErrors())
+	assert.True(t, (&ShowTemplateRequest{Labels: tooMany}).Validate().HasErrors())
+}
+
+func TestNormalizeLabels(t *testing.T) {
+	assert.Nil(t, NormalizeLabels(nil))
+	assert.Equal(t, []string{"tech", "news"}, NormalizeLabels([]string{" Tech", "news", "", "TECH"}))
+}
+
+func TestShowTemplateRequest_Hosts(t *testing.T) {
+	req := &ShowTemplateRequest{Title: " Fnjan ", Hosts: []string{" Abdulrahman  Abumalih", "abdulrahman abumalih", ""}}
+	assert.False(t, req.Validate().HasErrors())
+
+	template := &ShowTemplate{ShowID: "show-1"}
+	req.ApplyTo(template)
+	assert.Equal(t, "Fnjan", template.Title)
+	assert.Equal(t, []string{"Abdulrahman Abumalih"}, template.Hosts)
+
+	tooMany := make([]string, MaxShowHosts+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+	assert.True(t, (&ShowTemplateRequest{Hosts: tooMany}).Validate().HasErrors())
+}
@@ -0,0 +1,333 @@
+package domain
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultStreamURLTTL is how long the URLs of a stream manifest stay valid
+const DefaultStreamURLTTL = 5 * time.Minute
+
+// DefaultDownloadURLTTL is how long a download URL of a media file stays valid
+const DefaultDownloadURLTTL = 15 * time.Minute
+
+// maxDownloadFilenameLength is the longest filename, in bytes, downloads are saved as
+const maxDownloadFilenameLength = 200
+
+// StreamFormat represents an adaptive streaming format of manifests
+type StreamFormat string
+
+const (
+	StreamFormatHLS  StreamFormat = "hls"
+	StreamFormatDASH StreamFormat = "dash"
+)
+
+// ParseStreamFormat parses the format query parameter of a stream request,
+// HLS when empty
+func ParseStreamFormat(format string) (StreamFormat, error) {
+	switch StreamFormat(strings.ToLower(format)) {
+	case "", StreamFormatHLS:
+		return StreamFormatHLS, nil
+	case StreamFormatDASH:
+		return StreamFormatDASH, nil
+	default:
+		return "", NewBusinessError("INVALID_FORMAT", "Stream format must be one of: hls, dash")
+	}
+}
+
+// ContentType returns the MIME type manifests of the format are served with
+func (f StreamFormat) ContentType() string {
+	if f == StreamFormatDASH {
+		return "application/dash+xml"
+	}
+	return "application/vnd.apple.mpegurl"
+}
+
+// StreamRequest selects the manifest of a media stream. Without a rendition
+// the manifest lists every rendition for the player to switch between.
+type StreamRequest struct {
+	Format    StreamFormat
+	Rendition string
+}
+
+// StreamManifest is a manifest whose URLs are signed. The earliest expires at
+// ExpiresAt; later segments stay valid for as long after as they start into
+// the stream so that playback in real time does not outlive them.
+type StreamManifest struct {
+	Format    StreamFormat
+	Body      string
+	ExpiresAt time.Time
+}
+
+// DownloadURL is a signed URL of the uploaded file of a media item
+type DownloadURL struct {
+	URL       string    `json:"url"`
+	Filename  string    `json:"filename,omitempty"` // name the file is saved as, the browser's choice when empty
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DownloadFilename cleans the filename a download is saved as, adding ext,
+// the extension of the file, when it is missing. Path separators, quotes and
+// control characters are dropped; an empty name stays empty.
+func DownloadFilename(name, ext string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil
+	}
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\"`, r) {
+			return -1
+		}
+		return r
+	}, name))
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return "", NewBusinessError("INVALID_FILENAME", "Filename must contain more than dots, quotes and slashes")
+	}
+	if ext != "" && !strings.EqualFold(path.Ext(name), ext) {
+		name += ext
+	}
+	if !utf8.ValidString(name) || len(name) > maxDownloadFilenameLength {
+		return "", NewBusinessError("INVALID_FILENAME", fmt.Sprintf("Filename must be valid text of at most %d bytes", maxDownloadFilenameLength))
+	}
+	return name, nil
+}
+
+// StreamContentType returns the MIME type a file of a stream is served with
+func StreamContentType(key string) string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".m3u8":
+		return StreamFormatHLS.ContentType()
+	case ".mpd":
+		return StreamFormatDASH.ContentType()
+	case ".ts":
+		return "video/mp2t"
+	case ".mp4":
+		return "video/mp4"
+	case ".webm":
+		return "video/webm"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// SignHLSPlaylist rewrites the segment URIs of an HLS media playlist with the
+// URL sign returns for each, given the offset at which the segment starts
+func SignHLSPlaylist(playlist string, sign func(uri string, offset time.Duration) (string, error)) (string, error) {
+	var b strings.Builder
+	var offset, duration time.Duration
+	scanner := bufio.NewScanner(strings.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			seconds, _, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			if value, err := strconv.ParseFloat(seconds, 64); err == nil {
+				duration = time.Duration(value * float64(time.Second))
+			}
+		case !strings.HasPrefix(line, "#"):
+			signed, err := sign(line, offset)
+			if err != nil {
+				return "", err
+			}
+			line = signed
+			offset += duration
+			duration = 0
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// InsertHLSCuePoints marks the ad breaks of an HLS media playlist with an
+// #EXT-X-CUE-OUT and #EXT-X-CUE-IN pair, the splice point ad servers fill,
+// before the first segment starting at or after each cue point. Breaks past the
+// last segment, like post-rolls, end the playlist. cuePoints are in playback order.
+func InsertHLSCuePoints(playlist string, cuePoints []CuePoint) (string, error) {
+	if len(cuePoints) == 0 {
+		return playlist, nil
+	}
+
+	var b strings.Builder
+	writeCues := func(until time.Duration) {
+		for len(cuePoints) > 0 && time.Duration(cuePoints[0].OffsetSeconds)*time.Second <= until {
+			if cuePoints[0].MaxDurationSeconds > 0 {
+				fmt.Fprintf(&b, "#EXT-X-CUE-OUT:DURATION=%d\n", cuePoints[0].MaxDurationSeconds)
+			} else {
+				b.WriteString("#EXT-X-CUE-OUT\n")
+			}
+			b.WriteString("#EXT-X-CUE-IN\n")
+			cuePoints = cuePoints[1:]
+		}
+	}
+
+	var offset, duration time.Duration
+	scanner := bufio.NewScanner(strings.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			writeCues(offset)
+			seconds, _, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			if value, err := strconv.ParseFloat(seconds, 64); err == nil {
+				duration = time.Duration(value * float64(time.Second))
+			}
+		case line == "#EXT-X-ENDLIST":
+			writeCues(math.MaxInt64)
+		case !strings.HasPrefix(line, "#"):
+			offset += duration
+			duration = 0
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	writeCues(math.MaxInt64)
+	return b.String(), nil
+}
+
+// DASHCuePointScheme identifies the event stream of the ad breaks of DASH
+// manifests; each event carries the position of its break
+const DASHCuePointScheme = "urn:thamaniyah:cue-point:2024"
+
+// dashMPD is a static DASH manifest of progressive renditions
+type dashMPD struct {
+	XMLName                   xml.Name         `xml:"urn:mpeg:dash:schema:mpd:2011 MPD"`
+	Profiles                  string           `xml:"profiles,attr"`
+	Type                      string           `xml:"type,attr"`
+	MediaPresentationDuration string           `xml:"mediaPresentationDuration,attr,omitempty"`
+	MinBufferTime             string           `xml:"minBufferTime,attr"`
+	CENCNamespace             string           `xml:"xmlns:cenc,attr,omitempty"` // declared for the key IDs of protected renditions
+	EventStream               *dashEventStream `xml:"Period>EventStream,omitempty"`
+	AdaptationSets            []dashAdaptSet   `xml:"Period>AdaptationSet"`
+}
+
+type dashEventStream struct {
+	SchemeIDURI string      `xml:"schemeIdUri,attr"`
+	Timescale   int         `xml:"timescale,attr"`
+	Events      []dashEvent `xml:"Event"`
+}
+
+type dashEvent struct {
+	ID               int    `xml:"id,attr"`
+	PresentationTime int    `xml:"presentationTime,attr"`
+	Duration         int    `xml:"duration,attr,omitempty"`
+	Position         string `xml:",chardata"`
+}
+
+type dashAdaptSet struct {
+	MimeType           string                  `xml:"mimeType,attr"`
+	ContentProtections []dashContentProtection `xml:"ContentProtection"`
+	Representations    []dashRepresentation    `xml:"Representation"`
+}
+
+type dashContentProtection struct {
+	SchemeIDURI string `xml:"schemeIdUri,attr"`
+	Value       string `xml:"value,attr,omitempty"`
+	DefaultKID  string `xml:"cenc:default_KID,attr,omitempty"`
+}
+
+type dashRepresentation struct {
+	ID        string `xml:"id,attr"`
+	Bandwidth int    `xml:"bandwidth,attr"`
+	Width     int    `xml:"width,attr,omitempty"`
+	Height    int    `xml:"height,attr,omitempty"`
+	BaseURL   string `xml:"BaseURL"`
+}
+
+// DASHManifest renders a static DASH manifest of the ready renditions with a
+// progressive file, served from the URL urlOf returns for each, and of the ad
+// breaks of cuePoints. Renditions are grouped by container and content key,
+// highest bitrate first; encrypted ones announce their key to Widevine players.
+func DASHManifest(durationSeconds int, cuePoints []CuePoint, renditions []*MediaRendition, urlOf func(*MediaRendition) (string, error)) (string, error) {
+	mpd := dashMPD{
+		Profiles:      "urn:mpeg:dash:profile:isoff-on-demand:2011",
+		Type:          "static",
+		MinBufferTime: "PT2S",
+	}
+	if durationSeconds > 0 {
+		mpd.MediaPresentationDuration = fmt.Sprintf("PT%dS", durationSeconds)
+	}
+	if len(cuePoints) > 0 {
+		mpd.EventStream = &dashEventStream{SchemeIDURI: DASHCuePointScheme, Timescale: 1}
+		for i, cue := range cuePoints {
+			mpd.EventStream.Events = append(mpd.EventStream.Events, dashEvent{
+				ID:               i + 1,
+				PresentationTime: cue.OffsetSeconds,
+				Duration:         cue.MaxDurationSeconds,
+				Position:         string(cue.Position),
+			})
+		}
+	}
+
+	sets := make(map[[2]string]int)
+	for _, rendition := range renditions {
+		if rendition.Status != RenditionReady || rendition.FileKey == "" {
+			continue
+		}
+		url, err := urlOf(rendition)
+		if err != nil {
+			return "", err
+		}
+		mimeType := StreamContentType(rendition.FileKey)
+		i, ok := sets[[2]string{mimeType, rendition.KeyID}]
+		if !ok {
+			i = len(mpd.AdaptationSets)
+			sets[[2]string{mimeType, rendition.KeyID}] = i
+			set := dashAdaptSet{MimeType: mimeType}
+			if rendition.KeyID != "" {
+				mpd.CENCNamespace = "urn:mpeg:cenc:2013"
+				set.ContentProtections = []dashContentProtection{
+					{SchemeIDURI: "urn:mpeg:dash:mp4protection:2011", Value: "cenc", DefaultKID: keyIDUUID(rendition.KeyID)},
+					{SchemeIDURI: "urn:uuid:" + WidevineSystemID, Value: "Widevine"},
+				}
+			}
+			mpd.AdaptationSets = append(mpd.AdaptationSets, set)
+		}
+		mpd.AdaptationSets[i].Representations = append(mpd.AdaptationSets[i].Representations, dashRepresentation{
+			ID:        rendition.Name,
+			Bandwidth: rendition.BitrateKbps * 1000,
+			Width:     rendition.Width,
+			Height:    rendition.Height,
+			BaseURL:   url,
+		})
+	}
+	for _, set := range mpd.AdaptationSets {
+		representations := set.Representations
+		sort.SliceStable(representations, func(i, j int) bool {
+			return representations[i].Bandwidth > representations[j].Bandwidth
+		})
+	}
+
+	body, err := xml.MarshalIndent(mpd, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(body) + "\n", nil
+}
+
+// keyIDUUID formats a hex encoded 16 byte key ID as the UUID DASH manifests expect
+func keyIDUUID(keyID string) string {
+	if len(keyID) != 32 {
+		return keyID
+	}
+	return keyID[:8] + "-" + keyID[8:12] + "-" + keyID[12:16] + "-" + keyID[16:20] + "-" + keyID[20:]
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStreamFormat(t *testing.T) {
+	format, err := ParseStreamFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, StreamFormatHLS, format)
+
+	format, err = ParseStreamFormat("DASH")
+	require.NoError(t, err)
+	assert.Equal(t, StreamFormatDASH, format)
+
+	_, err = ParseStreamFormat("smooth")
+	var businessErr *BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_FORMAT", businessErr.Code)
+}
+
+func TestSignHLSPlaylist(t *testing.T) {
+	playlist := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n" +
+		"#EXTINF:6.000000,\nsegment_0000.ts\n" +
+		"#EXTINF:4.500000,\nsegment_0001.ts\n" +
+		"#EXTINF:2.000000,\nsegment_0002.ts\n#EXT-X-ENDLIST\n"
+
+	signed, err := SignHLSPlaylist(playlist, func(uri string, offset time.Duration) (string, error) {
+		return fmt.Sprintf("https://cdn.test/%s?at=%s", uri, offset), nil
+	})
+	require.NoError(t, err)
+
+	expected := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n" +
+		"#EXTINF:6.000000,\nhttps://cdn.test/segment_0000.ts?at=0s\n" +
+		"#EXTINF:4.500000,\nhttps://cdn.test/segment_0001.ts?at=6s\n" +
+		"#EXTINF:2.000000,\nhttps://cdn.test/segment_0002.ts?at=10.5s\n#EXT-X-ENDLIST\n"
+	assert.Equal(t, expected, signed)
+}
+
+func TestDASHManifest(t *testing.T) {
+	renditions := []*MediaRendition{
+		{Name: "480p", Width: 854, Height: 480, BitrateKbps: 1496, Status: RenditionReady, FileKey: "derived/123/renditions/480p.mp4"},
+		{Name: "720p", Width: 1280, Height: 720, BitrateKbps: 2928, Status: RenditionReady, FileKey: "derived/123/renditions/720p.mp4"},
+		{Name: "webm", Width: 1280, Height: 720, BitrateKbps: 2000, Status: RenditionReady, FileKey: "derived/123/renditions/webm.webm"},
+		{Name: "hls", Width: 640, Height: 360, BitrateKbps: 800, Status: RenditionReady, PlaylistKey: "derived/123/renditions/hls/index.m3u8"},
+		{Name: "1080p", Width: 1920, Height: 1080, BitrateKbps: 5128, Status: RenditionFailed},
+	}
+
+	cuePoints := []CuePoint{{Position: CuePointPreroll}, {Position: CuePointMidroll, OffsetSeconds: 40, MaxDurationSeconds: 30}}
+
+	manifest, err := DASHManifest(95, cuePoints, renditions, func(rendition *MediaRendition) (string, error) {
+		return "https://cdn.test/" + rendition.FileKey + "?a=1&b=2", nil
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, manifest, `mediaPresentationDuration="PT95S"`)
+	assert.Contains(t, manifest, `<AdaptationSet mimeType="video/mp4">`)
+	assert.Contains(t, manifest, `<AdaptationSet mimeType="video/webm">`)
+	assert.Contains(t, manifest, "<BaseURL>https://cdn.test/derived/123/renditions/720p.mp4?a=1&amp;b=2</BaseURL>")
+	assert.Less(t, strings.Index(manifest, `id="720p"`), strings.Index(manifest, `id="480p"`), "highest bitrate first")
+	assert.NotContains(t, manifest, "hls")
+	assert.NotContains(t, manifest, "1080p")
+	assert.Contains(t, manifest, `<EventStream schemeIdUri="`+DASHCuePointScheme+`" timescale="1">`)
+	assert.Contains(t, manifest, `<Event id="1" presentationTime="0">preroll</Event>`)
+	assert.Contains(t, manifest, `<Event id="2" presentationTime="40" duration="30">midroll</Event>`)
+	assert.Less(t, strings.Index(manifest, "<EventStream"), strings.Index(manifest, "<AdaptationSet"), "events before adaptation sets")
+}
+
+func TestInsertHLSCuePoints(t *testing.T) {
+	playlist := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n" +
+		"#EXTINF:6.000000,\nsegment_0000.ts\n" +
+		"#EXTINF:6.000000,\nsegment_0001.ts\n" +
+		"#EXTINF:6.000000,\nsegment_0002.ts\n#EXT-X-ENDLIST\n"
+	cuePoints := []CuePoint{
+		{Position: CuePointPreroll},
+		{Position: CuePointMidroll, OffsetSeconds: 10, MaxDurationSeconds: 30},
+		{Position: CuePointPostroll, OffsetSeconds: 18},
+	}
+
+	marked, err := InsertHLSCuePoints(playlist, cuePoints)
+	require.NoError(t, err)
+
+	// Mid-rolls move to the next segment boundary, post-rolls end the playlist
+	expected := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n" +
+		"#EXT-X-CUE-OUT\n#EXT-X-CUE-IN\n" +
+		"#EXTINF:6.000000,\nsegment_0000.ts\n" +
+		"#EXTINF:6.000000,\nsegment_0001.ts\n" +
+		"#EXT-X-CUE-OUT:DURATION=30\n#EXT-X-CUE-IN\n" +
+		"#EXTINF:6.000000,\nsegment_0002.ts\n" +
+		"#EXT-X-CUE-OUT\n#EXT-X-CUE-IN\n#EXT-X-ENDLIST\n"
+	assert.Equal(t, expected, marked)
+
+	unchanged, err := InsertHLSCuePoints(playlist, nil)
+	require.NoError(t, err)
+	assert.Equal(t, playlist, unchanged)
+}
+
+func TestDownloadFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		expected string
+		invalid  bool
+	}{
+		{name: "empty", filename: "  ", expected: ""},
+		{name: "adds extension", filename: "Episode 12", expected: "Episode 12.mp3"},
+		{name: "keeps extension", filename: "episode.MP3", expected: "episode.MP3"},
+		{name: "arabic", filename: "الحلقة ١٢", expected: "الحلقة ١٢.mp3"},
+		{name: "drops path and quotes", filename: `../"secret"/ep`, expected: "secretep.mp3"},
+		{name: "drops control characters", filename: "ep\r\nX-Header: 1", expected: "epX-Header: 1.mp3"},
+		{name: "only dots", filename: "..", invalid: true},
+		{name: "too long", filename: strings.Repeat("a", 200), invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename, err := DownloadFilename(tt.filename, ".mp3")
+
+			if tt.invalid {
+				var businessErr *BusinessError
+				require.ErrorAs(t, err, &businessErr)
+				assert.Equal(t, "INVALID_FILENAME", businessErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filename)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Thumbnail sizes. Requested sizes are rounded up to the next of
+// ThumbnailSizes, so that the variants rendered and cached per media item stay
+// few whatever clients ask for.
+const (
+	DefaultThumbnailWidth = 320
+	MaxThumbnailDimension = 1920
+)
+
+// ThumbnailSizes are the widths and heights thumbnails are rendered at, ascending
+var ThumbnailSizes = []int{64, 128, 256, DefaultThumbnailWidth, 480, 640, 960, 1280, MaxThumbnailDimension}
+
+// ThumbnailFormat represents an image format thumbnails are rendered in
+type ThumbnailFormat string
+
+const (
+	ThumbnailFormatJPEG ThumbnailFormat = "jpeg"
+	ThumbnailFormatPNG  ThumbnailFormat = "png"
+	ThumbnailFormatWebP ThumbnailFormat = "webp"
+)
+
+// IsValid checks if the thumbnail format is supported
+func (f ThumbnailFormat) IsValid() bool {
+	switch f {
+	case ThumbnailFormatJPEG, ThumbnailFormatPNG, ThumbnailFormatWebP:
+		return true
+	default:
+		return false
+	}
+}
+
+// Extension returns the file extension of the format
+func (f ThumbnailFormat) Extension() string {
+	switch f {
+	case ThumbnailFormatPNG:
+		return ".png"
+	case ThumbnailFormatWebP:
+		return ".webp"
+	default:
+		return ".jpg"
+	}
+}
+
+// ContentType returns the MIME type the format is served with
+func (f ThumbnailFormat) ContentType() string {
+	return "image/" + string(f)
+}
+
+// ThumbnailSpec is the size and format of a thumbnail. A zero width or height
+// follows the aspect ratio of the source; with both set the image fits within
+// them.
+type ThumbnailSpec struct {
+	Width  int
+	Height int
+	Format ThumbnailFormat
+}
+
+// ParseThumbnailSpec parses the w, h and format query parameters of a
+// thumbnail request. Without a size the thumbnail is DefaultThumbnailWidth
+// wide, and without a format it is a JPEG.
+func ParseThumbnailSpec(width, height, format string) (ThumbnailSpec, error) {
+	spec := ThumbnailSpec{Format: ThumbnailFormat(strings.ToLower(format))}
+	if spec.Format == "" || spec.Format == "jpg" {
+		spec.Format = ThumbnailFormatJPEG
+	}
+	if !spec.Format.IsValid() {
+		return ThumbnailSpec{}, NewBusinessError("INVALID_FORMAT", "Thumbnail format must be one of: jpeg, png, webp")
+	}
+
+	var err error
+	if spec.Width, err = parseThumbnailDimension(width); err != nil {
+		return ThumbnailSpec{}, err
+	}
+	if spec.Height, err = parseThumbnailDimension(height); err != nil {
+		return ThumbnailSpec{}, err
+	}
+	if spec.Width == 0 && spec.Height == 0 {
+		spec.Width = DefaultThumbnailWidth
+	}
+	return spec, nil
+}
+
+// parseThumbnailDimension parses a width or height, 0 when empty, rounded up to one of ThumbnailSizes
+func parseThumbnailDimension(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 || size > MaxThumbnailDimension {
+		return 0, NewBusinessError("INVALID_THUMBNAIL_SIZE", fmt.Sprintf("Thumbnail width and height must be between 1 and %d pixels", MaxThumbnailDimension))
+	}
+	for _, bucket := range ThumbnailSizes {
+		if size <= bucket {
+			return bucket, nil
+		}
+	}
+	return MaxThumbnailDimension, nil
+}
+
+// DerivedThumbnailKey returns the storage key of a thumbnail of a media item
+func DerivedThumbnailKey(mediaID string, spec ThumbnailSpec) string {
+	return fmt.Sprintf("%s%dx%d%s", DerivedThumbnailPrefix(mediaID), spec.Width, spec.Height, spec.Format.Extension())
+}
+
+// Poster is the frame of a video picked as its poster. Thumbnails of videos
+// with a poster are rendered from it instead of a frame picked automatically.
+type Poster struct {
+	Key      string `json:"key,omitempty"`                              // storage key of the full size frame
+	Timecode string `json:"timecode,omitempty" gorm:"type:varchar(20)"` // where the frame was taken, HH:MM:SS[.mmm]
+}
+
+// IsSet returns true if a poster was picked
+func (p Poster) IsSet() bool {
+	return p.Key != ""
+}
+
+// DerivedPosterKey returns the storage key of the poster of a media item taken at a timecode
+func DerivedPosterKey(mediaID string, at time.Duration) string {
+	return fmt.Sprintf("%s%s/poster-%d.jpg", DerivedKeyPrefix, mediaID, at.Milliseconds())
+}
+
+// DerivedThumbnailPrefix returns the prefix the thumbnails of a media item are stored under
+func DerivedThumbnailPrefix(mediaID string) string {
+	return DerivedKeyPrefix + mediaID + "/thumbnail-"
+}
+
+// ParseTimecode parses a position in a media item given as HH:MM:SS, MM:SS or
+// seconds, each with optional fractional seconds, e.g. 00:01:23.5
+func ParseTimecode(value string) (time.Duration, error) {
+	invalid := NewBusinessError("INVALID_TIMECODE", "Timecode must be HH:MM:SS, MM:SS or seconds, e.g. 00:01:23")
+
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 3 || parts[0] == "" {
+		return 0, invalid
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil || seconds < 0 || (len(parts) > 1 && seconds >= 60) {
+		return 0, invalid
+	}
+	multiplier := 60.0
+	for i := len(parts) - 2; i >= 0; i-- {
+		unit, err := strconv.Atoi(parts[i])
+		if err != nil || unit < 0 || (i > 0 && unit >= 60) {
+			return 0, invalid
+		}
+		seconds += float64(unit) * multiplier
+		multiplier *= 60
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond), nil
+}
+
+// FormatTimecode formats a position as HH:MM:SS, with milliseconds when not whole seconds
+func FormatTimecode(at time.Duration) string {
+	at = at.Round(time.Millisecond)
+	timecode := fmt.Sprintf("%02d:%02d:%02d", int(at.Hours()), int(at.Minutes())%60, int(at.Seconds())%60)
+	if ms := at.Milliseconds() % 1000; ms != 0 {
+		timecode += fmt.Sprintf(".%03d", ms)
+	}
+	return timecode
+}
+
+// ThumbnailAsset is a rendered thumbnail ready to be sent to the client.
+// The caller must close Body.
+type ThumbnailAsset struct {
+	Format      ThumbnailFormat
+	ContentType string
+	Size        int64
+	Cached      bool // served from a previously rendered thumbnail
+	Body        io.ReadCloser
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThumbnailSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     string
+		height    string
+		format    string
+		expected  ThumbnailSpec
+		errorCode string
+	}{
+		{name: "defaults", expected: ThumbnailSpec{Width: DefaultThumbnailWidth, Format: ThumbnailFormatJPEG}},
+		{name: "sizes rounded up to a bucket", width: "300", height: "169", format: "WEBP", expected: ThumbnailSpec{Width: 320, Height: 256, Format: ThumbnailFormatWebP}},
+		{name: "sizes between buckets share a variant", width: "321", height: "1", expected: ThumbnailSpec{Width: 480, Height: 64, Format: ThumbnailFormatJPEG}},
+		{name: "height only", height: "64", format: "jpg", expected: ThumbnailSpec{Height: 64, Format: ThumbnailFormatJPEG}},
+		{name: "just below the largest size", width: "1281", expected: ThumbnailSpec{Width: 1920, Format: ThumbnailFormatJPEG}},
+		{name: "largest size", width: "1920", format: "png", expected: ThumbnailSpec{Width: 1920, Format: ThumbnailFormatPNG}},
+		{name: "too large", width: "1921", errorCode: "INVALID_THUMBNAIL_SIZE"},
+		{name: "not a number", height: "big", errorCode: "INVALID_THUMBNAIL_SIZE"},
+		{name: "zero", width: "0", errorCode: "INVALID_THUMBNAIL_SIZE"},
+		{name: "unknown format", format: "gif", errorCode: "INVALID_FORMAT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			spec, err := ParseThumbnailSpec(tt.width, tt.height, tt.format)
+
+			// Then
+			if tt.errorCode != "" {
+				var businessErr *BusinessError
+				require.True(t, errors.As(err, &businessErr))
+				assert.Equal(t, tt.errorCode, businessErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, spec)
+		})
+	}
+}
+
+func TestDerivedThumbnailKey(t *testing.T) {
+	// When / Then
+	assert.Equal(t, "derived/media-123/thumbnail-320x0.jpg", DerivedThumbnailKey("media-123", ThumbnailSpec{Width: 320, Format: ThumbnailFormatJPEG}))
+	assert.Equal(t, "derived/media-123/thumbnail-480x256.webp", DerivedThumbnailKey("media-123", ThumbnailSpec{Width: 480, Height: 256, Format: ThumbnailFormatWebP}))
+	assert.Equal(t, "image/webp", ThumbnailFormatWebP.ContentType())
+}
+
+func TestParseTimecode(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		valid    bool
+	}{
+		{value: "00:01:23", expected: 83 * time.Second, valid: true},
+		{value: "1:02:03.5", expected: time.Hour + 2*time.Minute + 3500*time.Millisecond, valid: true},
+		{value: "01:23", expected: 83 * time.Second, valid: true},
+		{value: "83.25", expected: 83250 * time.Millisecond, valid: true},
+		{value: "0", expected: 0, valid: true},
+		{value: "", valid: false},
+		{value: "00:60:00", valid: false},
+		{value: "00:01:60", valid: false},
+		{value: "-5", valid: false},
+		{value: "1:2:3:4", valid: false},
+		{value: "abc", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			at, err := ParseTimecode(tt.value)
+			if !tt.valid {
+				var businessErr *BusinessError
+				require.ErrorAs(t, err, &businessErr)
+				assert.Equal(t, "INVALID_TIMECODE", businessErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, at)
+		})
+	}
+}
+
+func TestFormatTimecode(t *testing.T) {
+	assert.Equal(t, "00:01:23", FormatTimecode(83*time.Second))
+	assert.Equal(t, "01:02:03.500", FormatTimecode(time.Hour+2*time.Minute+3500*time.Millisecond))
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// TraceContext identifies the request that caused some work, so events and
+// background jobs can be correlated with it
+type TraceContext struct {
+	RequestID   string `json:"request_id,omitempty"`
+	TraceParent string `json:"traceparent,omitempty"` // W3C trace context header value
+}
+
+type traceContextKey struct{}
+
+// ContextWithTrace returns a context carrying the trace context
+func ContextWithTrace(ctx context.Context, trace *TraceContext) context.Context {
+	if trace == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, traceContextKey{}, trace)
+}
+
+// TraceFromContext returns the trace context carried by ctx, or nil
+func TraceFromContext(ctx context.Context) *TraceContext {
+	trace, _ := ctx.Value(traceContextKey{}).(*TraceContext)
+	return trace
+}
+
+// ContinueTraceParent returns the traceparent of a span continuing the trace of
+// incoming, or of a new trace when incoming is not a valid traceparent
+func ContinueTraceParent(incoming string) string {
+	traceID, flags := randomHex(16), "01"
+	if parts := strings.Split(strings.ToLower(strings.TrimSpace(incoming)), "-"); len(parts) == 4 &&
+		parts[0] == "00" && isHex(parts[1], 32) && isHex(parts[2], 16) && isHex(parts[3], 2) &&
+		parts[1] != strings.Repeat("0", 32) {
+		traceID, flags = parts[1], parts[3]
+	}
+	return "00-" + traceID + "-" + randomHex(8) + "-" + flags
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContinueTraceParent(t *testing.T) {
+	const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name          string
+		incoming      string
+		keepsTraceID  bool
+		expectedFlags string
+	}{
+		{name: "continues a valid trace", incoming: incoming, keepsTraceID: true, expectedFlags: "01"},
+		{name: "keeps unsampled flag", incoming: strings.TrimSuffix(incoming, "01") + "00", keepsTraceID: true, expectedFlags: "00"},
+		{name: "no incoming trace", incoming: "", expectedFlags: "01"},
+		{name: "malformed trace", incoming: "00-xyz-00f067aa0ba902b7-01", expectedFlags: "01"},
+		{name: "all zero trace ID", incoming: "00-" + strings.Repeat("0", 32) + "-00f067aa0ba902b7-01", expectedFlags: "01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := ContinueTraceParent(tt.incoming)
+
+			// Then
+			parts := strings.Split(result, "-")
+			assert.Len(t, parts, 4)
+			assert.Equal(t, "00", parts[0])
+			assert.Len(t, parts[1], 32)
+			assert.Len(t, parts[2], 16)
+			assert.Equal(t, tt.expectedFlags, parts[3])
+			assert.NotEqual(t, "00f067aa0ba902b7", parts[2], "a new span ID is used")
+			if tt.keepsTraceID {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", parts[1])
+			} else {
+				assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", parts[1])
+			}
+		})
+	}
+}
+
+func TestTraceFromContext(t *testing.T) {
+	trace := &TraceContext{RequestID: "req-1"}
+
+	assert.Nil(t, TraceFromContext(context.Background()))
+	assert.Equal(t, trace, TraceFromContext(ContextWithTrace(context.Background(), trace)))
+	assert.Nil(t, TraceFromContext(ContextWithTrace(context.Background(), nil)))
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported codecs and containers of transcode renditions
+var (
+	VideoCodecs = []string{"h264", "h265", "vp9", "av1"}
+	AudioCodecs = []string{"aac", "opus", "mp3"}
+	Containers  = []string{"mp4", "webm", "hls", "mp3", "m4a", "ogg"}
+)
+
+// Rendition describes one output of the transcoding pipeline.
+// Audio-only renditions leave the video fields empty.
+type Rendition struct {
+	Name             string `json:"name"`
+	Width            int    `json:"width,omitempty"`
+	Height           int    `json:"height,omitempty"`
+	VideoCodec       string `json:"video_codec,omitempty"`
+	VideoBitrateKbps int    `json:"video_bitrate_kbps,omitempty"`
+	AudioCodec       string `json:"audio_codec"`
+	AudioBitrateKbps int    `json:"audio_bitrate_kbps"`
+	Container        string `json:"container"`
+}
+
+// IsAudioOnly returns true if the rendition has no video stream
+func (r Rendition) IsAudioOnly() bool {
+	return r.VideoCodec == ""
+}
+
+// TranscodePreset is a named set of renditions the transcoding pipeline produces
+type TranscodePreset struct {
+	ID          string      `json:"id" gorm:"primaryKey"`
+	Name        string      `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description string      `json:"description"`
+	MediaType   MediaType   `json:"media_type" gorm:"type:varchar(20);not null;index"`
+	IsDefault   bool        `json:"is_default" gorm:"not null;default:false"`
+	Renditions  []Rendition `json:"renditions" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for TranscodePreset
+func (TranscodePreset) TableName() string {
+	return "transcode_presets"
+}
+
+// TranscodePresetRequest represents a request to create or replace a transcode preset
+type TranscodePresetRequest struct {
+	Name        string      `json:"name" binding:"required"`
+	Description string      `json:"description"`
+	MediaType   MediaType   `json:"media_type" binding:"required"`
+	IsDefault   bool        `json:"is_default"`
+	Renditions  []Rendition `json:"renditions" binding:"required"`
+}
+
+// Validate validates the preset request
+func (r *TranscodePresetRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if strings.TrimSpace(r.Name) == "" {
+		errs.Add("name", "is required")
+	}
+	if r.MediaType != TypeVideo && r.MediaType != TypePodcast {
+		errs.Add("media_type", "must be one of: video, podcast")
+	}
+	if len(r.Renditions) == 0 {
+		errs.Add("renditions", "at least one rendition is required")
+	}
+
+	names := make(map[string]bool)
+	for i, rendition := range r.Renditions {
+		field := fmt.Sprintf("renditions[%d]", i)
+
+		if rendition.Name == "" {
+			errs.Add(field+".name", "is required")
+		} else if names[rendition.Name] {
+			errs.Add(field+".name", "must be unique within the preset")
+		}
+		names[rendition.Name] = true
+
+		if !rendition.IsAudioOnly() {
+			if r.MediaType == TypePodcast {
+				errs.Add(field+".video_codec", "podcast presets only support audio renditions")
+			}
+			if !containsString(VideoCodecs, rendition.VideoCodec) {
+				errs.Add(field+".video_codec", "must be one of: "+strings.Join(VideoCodecs, ", "))
+			}
+			if rendition.Width <= 0 || rendition.Height <= 0 {
+				errs.Add(field+".resolution", "width and height must be positive")
+			}
+			if rendition.VideoBitrateKbps <= 0 {
+				errs.Add(field+".video_bitrate_kbps", "must be positive")
+			}
+		}
+		if !containsString(AudioCodecs, rendition.AudioCodec) {
+			errs.Add(field+".audio_codec", "must be one of: "+strings.Join(AudioCodecs, ", "))
+		}
+		if rendition.AudioBitrateKbps <= 0 {
+			errs.Add(field+".audio_bitrate_kbps", "must be positive")
+		}
+		if !containsString(Containers, rendition.Container) {
+			errs.Add(field+".container", "must be one of: "+strings.Join(Containers, ", "))
+		}
+	}
+
+	return errs
+}
+
+// ApplyTo copies the request onto a preset
+func (r *TranscodePresetRequest) ApplyTo(preset *TranscodePreset) {
+	preset.Name = strings.TrimSpace(r.Name)
+	preset.Description = r.Description
+	preset.MediaType = r.MediaType
+	preset.IsDefault = r.IsDefault
+	preset.Renditions = r.Renditions
+}
+
+// TranscodePlan describes how a media item is transcoded
+type TranscodePlan struct {
+	MediaID   string           `json:"media_id"`
+	Preset    *TranscodePreset `json:"preset"`
+	Watermark *Watermark       `json:"watermark,omitempty"`
+}
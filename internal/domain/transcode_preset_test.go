@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscodePresetRequest_Validate(t *testing.T) {
+	hd := Rendition{Name: "720p", Width: 1280, Height: 720, VideoCodec: "h264", VideoBitrateKbps: 3000, AudioCodec: "aac", AudioBitrateKbps: 128, Container: "hls"}
+	audio := Rendition{Name: "audio", AudioCodec: "opus", AudioBitrateKbps: 64, Container: "ogg"}
+
+	tests := []struct {
+		name        string
+		request     TranscodePresetRequest
+		errorFields []string
+	}{
+		{
+			name:    "valid video preset",
+			request: TranscodePresetRequest{Name: "web", MediaType: TypeVideo, Renditions: []Rendition{hd, audio}},
+		},
+		{
+			name:    "valid podcast preset",
+			request: TranscodePresetRequest{Name: "podcast", MediaType: TypePodcast, Renditions: []Rendition{audio}},
+		},
+		{
+			name:        "missing renditions",
+			request:     TranscodePresetRequest{Name: "empty", MediaType: TypeVideo},
+			errorFields: []string{"renditions"},
+		},
+		{
+			name:        "video rendition in podcast preset",
+			request:     TranscodePresetRequest{Name: "podcast", MediaType: TypePodcast, Renditions: []Rendition{hd}},
+			errorFields: []string{"renditions[0].video_codec"},
+		},
+		{
+			name:        "duplicate rendition names",
+			request:     TranscodePresetRequest{Name: "web", MediaType: TypeVideo, Renditions: []Rendition{hd, hd}},
+			errorFields: []string{"renditions[1].name"},
+		},
+		{
+			name: "unknown codecs and container",
+			request: TranscodePresetRequest{Name: "web", MediaType: TypeVideo, Renditions: []Rendition{
+				{Name: "bad", Width: 640, Height: 360, VideoCodec: "mpeg2", VideoBitrateKbps: 800, AudioCodec: "flac", AudioBitrateKbps: 128, Container: "avi"},
+			}},
+			errorFields: []string{"renditions[0].video_codec", "renditions[0].audio_codec", "renditions[0].container"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.request.Validate()
+
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.errorFields, fields)
+		})
+	}
+}
+
+func TestTranscodePresetRequest_ApplyTo(t *testing.T) {
+	// Given
+	preset := &TranscodePreset{ID: "preset-1"}
+	request := TranscodePresetRequest{
+		Name:       "  web  ",
+		MediaType:  TypeVideo,
+		IsDefault:  true,
+		Renditions: []Rendition{{Name: "audio", AudioCodec: "aac", AudioBitrateKbps: 128, Container: "m4a"}},
+	}
+
+	// When
+	request.ApplyTo(preset)
+
+	// Then
+	assert.Equal(t, "preset-1", preset.ID)
+	assert.Equal(t, "web", preset.Name)
+	assert.True(t, preset.IsDefault)
+	assert.Len(t, preset.Renditions, 1)
+}
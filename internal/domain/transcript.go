@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxTranscriptBytes bounds the text of a transcript
+const MaxTranscriptBytes = 1 << 20
+
+// MaxTranscriptLanguageLength bounds the language tag of a transcript, like "ar" or "en-US"
+const MaxTranscriptLanguageLength = 10
+
+// Transcript is the spoken text of a media item, supplied by editors or a
+// transcription service, from which labels and a category are suggested
+type Transcript struct {
+	MediaID   string    `json:"media_id" gorm:"primaryKey"`
+	Language  string    `json:"language,omitempty" gorm:"type:varchar(10)"`
+	Text      string    `json:"text" gorm:"type:text;not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Transcript
+func (Transcript) TableName() string {
+	return "media_transcripts"
+}
+
+// TranscriptRequest represents a request to replace the transcript of a media item
+type TranscriptRequest struct {
+	Text     string `json:"text" binding:"required"` // plain text, WebVTT or SRT
+	Language string `json:"language"`
+}
+
+// captionMarkup matches the voice, style and timestamp tags inside caption cues
+var captionMarkup = regexp.MustCompile(`<[^>]*>`)
+
+// Normalize reduces WebVTT and SRT captions to their spoken text and trims the request
+func (r *TranscriptRequest) Normalize() {
+	r.Text = TranscriptText(r.Text)
+	r.Language = strings.TrimSpace(r.Language)
+}
+
+// Validate validates the transcript request
+func (r *TranscriptRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if r.Text == "" {
+		errs.Add("text", "is required")
+	}
+	if len(r.Text) > MaxTranscriptBytes {
+		errs.Add("text", "must be at most 1 MiB")
+	}
+	if !utf8.ValidString(r.Text) {
+		errs.Add("text", "must be valid UTF-8")
+	}
+	if len(r.Language) > MaxTranscriptLanguageLength {
+		errs.Add("language", "must be at most 10 characters")
+	}
+
+	return errs
+}
+
+// TranscriptText returns the spoken text of a transcript. Captions, recognized by
+// their cue timings, lose their header, cue numbers, timings and markup; plain
+// text is only trimmed.
+func TranscriptText(raw string) string {
+	raw = strings.TrimSpace(strings.ReplaceAll(raw, "\r\n", "\n"))
+	if !strings.Contains(raw, "-->") {
+		return raw
+	}
+
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "", strings.HasPrefix(line, "WEBVTT"), strings.Contains(line, "-->"), isCueNumber(line):
+			continue
+		}
+		if line = strings.TrimSpace(captionMarkup.ReplaceAllString(line, "")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isCueNumber reports whether a caption line is the sequence number of an SRT cue
+func isCueNumber(line string) bool {
+	for _, r := range line {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,11 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
 	"time"
 )
 
@@ -11,6 +16,16 @@ type UploadURL struct {
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
+// DirectUpload is a file uploaded straight to the service, to the upload URL
+// issued for it when uploads are stored locally
+type DirectUpload struct {
+	Key       string // storage key the upload URL was issued for
+	Expires   int64  // unix time the upload URL expires at
+	Signature string // signature of the upload URL
+	Body      io.Reader
+	Size      int64 // declared content length, -1 when unknown
+}
+
 // UploadRequest represents a request to initiate file upload
 type UploadRequest struct {
 	Title       string    `json:"title" binding:"required"`
@@ -18,6 +33,45 @@ type UploadRequest struct {
 	Filename    string    `json:"filename" binding:"required"`
 	FileSize    int64     `json:"file_size" binding:"required"`
 	Type        MediaType `json:"type" binding:"required"`
+
+	// Priority is optional and defaults to normal
+	Priority MediaPriority `json:"priority,omitempty"`
+
+	// Visibility is optional and defaults to public
+	Visibility MediaVisibility `json:"visibility,omitempty"`
+
+	// AccessTier is optional and defaults to free
+	AccessTier MediaAccessTier `json:"access_tier,omitempty"`
+
+	// TranscodePresetID is optional and defaults to the preset of the media type
+	TranscodePresetID string `json:"transcode_preset_id,omitempty"`
+
+	// ShowID optionally groups the media under a show, whose template
+	// defaults the labels, category, artwork and explicit flag
+	ShowID string `json:"show_id,omitempty"`
+
+	// Labels and Category are optional and override the show template;
+	// an empty list of labels clears the labels of the template
+	Labels   []string `json:"labels,omitempty"`
+	Category string   `json:"category,omitempty"`
+
+	// GeoRestriction optionally limits the countries the media can be played in
+	GeoRestriction GeoRestriction `json:"geo_restriction,omitempty"`
+
+	// ContentRating is optional and defaults to all ages, not explicit
+	ContentRating ContentRating `json:"content_rating,omitempty"`
+
+	// License is optional, media without an end date is licensed perpetually
+	License License `json:"license,omitempty"`
+
+	// ContentHash is the optional hex SHA-256 of the file, used to detect duplicate uploads
+	ContentHash string `json:"content_hash,omitempty"`
+
+	// UnpublishAt optionally ends the publishing window, the media then returns to private
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+
+	// OwnerID is the signed in user uploading the media, never read from the request body
+	OwnerID string `json:"-"`
 }
 
 // IsValid validates the upload request
@@ -31,34 +85,201 @@ func (ur *UploadRequest) IsValid() bool {
 		return false
 	}
 
-	// Basic file size validation (max 5GB)
-	maxFileSize := int64(5 * 1024 * 1024 * 1024) // 5GB
-	if ur.FileSize > maxFileSize {
+	if ur.Priority != "" && !ur.Priority.IsValid() {
+		return false
+	}
+
+	if ur.Visibility != "" && !ur.Visibility.IsValid() {
+		return false
+	}
+
+	if ur.AccessTier != "" && !ur.AccessTier.IsValid() {
+		return false
+	}
+
+	if ur.GeoRestriction.Validate().HasErrors() {
 		return false
 	}
 
-	return true
+	if !ur.ContentRating.IsValid() {
+		return false
+	}
+
+	if ur.ContentHash != "" && !IsValidContentHash(ur.ContentHash) {
+		return false
+	}
+
+	if ValidateLabels("labels", NormalizeLabels(ur.Labels)).HasErrors() || ValidateCategory("category", ur.Category).HasErrors() {
+		return false
+	}
+
+	// The maximum file size depends on the tenant, see UploadLimits
+	return !ur.License.Validate().HasErrors()
+}
+
+// Validate checks the fields of the upload request, reporting every violation.
+// Limits depending on the tenant, such as the maximum file size, are not checked.
+func (ur *UploadRequest) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if strings.TrimSpace(ur.Title) == "" {
+		errs.Add("title", "is required")
+	}
+	if ur.FileSize <= 0 {
+		errs.Add("file_size", "must be positive")
+	}
+	if ur.Type != TypeVideo && ur.Type != TypePodcast {
+		errs.Add("type", "must be video or podcast")
+	}
+	if ur.Filename == "" {
+		errs.Add("filename", "is required")
+	} else if (ur.Type == TypeVideo || ur.Type == TypePodcast) && !ur.HasValidFormat() {
+		errs.Add("filename", fmt.Sprintf("format must be one of %s", strings.Join(FormatsFor(ur.Type), ", ")))
+	}
+	if ur.Priority != "" && !ur.Priority.IsValid() {
+		errs.Add("priority", "must be normal or high")
+	}
+	if ur.Visibility != "" && !ur.Visibility.IsValid() {
+		errs.Add("visibility", "must be public, unlisted or private")
+	}
+	if ur.AccessTier != "" && !ur.AccessTier.IsValid() {
+		errs.Add("access_tier", "must be free or premium")
+	}
+	if !ur.ContentRating.IsValid() {
+		errs.Add("content_rating", "age rating is not supported")
+	}
+	if ur.ContentHash != "" && !IsValidContentHash(ur.ContentHash) {
+		errs.Add("content_hash", "must be a hex encoded SHA-256")
+	}
+	errs = append(errs, ValidateLabels("labels", NormalizeLabels(ur.Labels))...)
+	errs = append(errs, ValidateCategory("category", strings.TrimSpace(ur.Category))...)
+	errs = append(errs, ur.GeoRestriction.Validate()...)
+	errs = append(errs, ur.License.Validate()...)
+
+	return errs
+}
+
+// Format returns the file format inferred from the filename
+func (ur *UploadRequest) Format() string {
+	return FormatFromFilename(ur.Filename)
+}
+
+// HasValidFormat checks if the file format is accepted for the media type
+func (ur *UploadRequest) HasValidFormat() bool {
+	return IsValidFormat(ur.Type, ur.Format())
+}
+
+// TenantID returns the tenant the uploaded media belongs to
+func (ur *UploadRequest) TenantID() string {
+	return DefaultTenantID
+}
+
+// IsValidContentHash checks if hash is a hex encoded SHA-256
+func IsValidContentHash(hash string) bool {
+	if len(hash) != 2*sha256.Size {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
 }
 
 // ToMedia converts UploadRequest to Media entity
 func (ur *UploadRequest) ToMedia(id, filePath string) *Media {
+	priority := ur.Priority
+	if priority == "" {
+		priority = PriorityNormal
+	}
+
+	visibility := ur.Visibility
+	if visibility == "" {
+		visibility = VisibilityPublic
+	}
+
+	accessTier := ur.AccessTier
+	if accessTier == "" {
+		accessTier = AccessTierFree
+	}
+
+	geoRestriction := ur.GeoRestriction
+	geoRestriction.Normalize()
+
 	return &Media{
 		ID:          id,
 		Title:       ur.Title,
 		Description: ur.Description,
 		FilePath:    filePath,
 		FileSize:    ur.FileSize,
+		Format:      ur.Format(),
+		ContentHash: strings.ToLower(ur.ContentHash),
 		Type:        ur.Type,
 		Status:      StatusUploading,
+		Priority:    priority,
+		Visibility:  visibility,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+
+		TranscodePresetID: ur.TranscodePresetID,
+		TenantID:          ur.TenantID(),
+		OwnerID:           ur.OwnerID,
+		ShowID:            ur.ShowID,
+		Labels:            NormalizeLabels(ur.Labels),
+		Category:          strings.TrimSpace(ur.Category),
+		AccessTier:        accessTier,
+		GeoRestriction:    geoRestriction,
+		ContentRating:     ur.ContentRating.withDefaults(),
+		License: License{
+			Source:   ur.License.Source,
+			StartsAt: ur.License.StartsAt,
+			EndsAt:   ur.License.EndsAt,
+		},
+		UnpublishAt: ur.UnpublishAt,
 	}
 }
 
 // UpdateMediaRequest represents a request to update media metadata
 type UpdateMediaRequest struct {
-	Title       *string `json:"title,omitempty"`
-	Description *string `json:"description,omitempty"`
+	Title       *string          `json:"title,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	Visibility  *MediaVisibility `json:"visibility,omitempty"`
+	AccessTier  *MediaAccessTier `json:"access_tier,omitempty"`
+
+	// ContentRating replaces the age rating and explicit flag
+	ContentRating *ContentRating `json:"content_rating,omitempty"`
+
+	// License replaces the rights window
+	License *License `json:"license,omitempty"`
+
+	// UnpublishAt schedules the end of the publishing window, ClearUnpublishAt removes it
+	UnpublishAt      *time.Time `json:"unpublish_at,omitempty"`
+	ClearUnpublishAt bool       `json:"clear_unpublish_at,omitempty"`
+
+	// Corrections to the tags embedded in the uploaded file
+	Artist     *string    `json:"artist,omitempty"`
+	Album      *string    `json:"album,omitempty"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+
+	// Language of the uploaded audio, like "ar"
+	AudioLanguage *string `json:"audio_language,omitempty"`
+}
+
+// IsValid validates the update request
+func (umr *UpdateMediaRequest) IsValid() bool {
+	if umr.Visibility != nil && !umr.Visibility.IsValid() {
+		return false
+	}
+	if umr.ContentRating != nil && !umr.ContentRating.IsValid() {
+		return false
+	}
+	if umr.License != nil && umr.License.Validate().HasErrors() {
+		return false
+	}
+	if umr.UnpublishAt != nil && umr.ClearUnpublishAt {
+		return false
+	}
+	if umr.AudioLanguage != nil && !IsValidAudioLanguage(NormalizeAudioLanguage(*umr.AudioLanguage)) {
+		return false
+	}
+	return umr.AccessTier == nil || umr.AccessTier.IsValid()
 }
 
 // ApplyTo applies the update request to a media entity
@@ -69,5 +290,40 @@ func (umr *UpdateMediaRequest) ApplyTo(media *Media) {
 	if umr.Description != nil {
 		media.Description = *umr.Description
 	}
+	if umr.Visibility != nil {
+		media.Visibility = *umr.Visibility
+	}
+	if umr.AccessTier != nil {
+		media.AccessTier = *umr.AccessTier
+	}
+	if umr.ContentRating != nil {
+		media.ContentRating = umr.ContentRating.withDefaults()
+	}
+	if umr.License != nil {
+		// A new window needs a new expiry notice
+		media.License = License{
+			Source:   umr.License.Source,
+			StartsAt: umr.License.StartsAt,
+			EndsAt:   umr.License.EndsAt,
+		}
+	}
+	if umr.UnpublishAt != nil {
+		media.UnpublishAt = umr.UnpublishAt
+	}
+	if umr.ClearUnpublishAt {
+		media.UnpublishAt = nil
+	}
+	if umr.Artist != nil {
+		media.Tags.Artist = *umr.Artist
+	}
+	if umr.Album != nil {
+		media.Tags.Album = *umr.Album
+	}
+	if umr.RecordedAt != nil {
+		media.Tags.RecordedAt = umr.RecordedAt
+	}
+	if umr.AudioLanguage != nil {
+		media.AudioLanguage = NormalizeAudioLanguage(*umr.AudioLanguage)
+	}
 	media.UpdatedAt = time.Now()
 }
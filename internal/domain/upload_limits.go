@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+)
+
+// UploadLimits are the upload constraints in effect for a tenant, surfaced to
+// clients so they can validate files before requesting an upload URL
+type UploadLimits struct {
+	TenantID            string                 `json:"tenant_id"`
+	UploadURLTTLSeconds int                    `json:"upload_url_ttl_seconds"`
+	MaxFileSizes        map[MediaType]int64    `json:"max_file_sizes"` // in bytes
+	StorageQuota        int64                  `json:"storage_quota"`  // total bytes of the tenant's media, 0 is unlimited
+	Formats             map[MediaType][]string `json:"formats"`
+}
+
+// DefaultUploadLimits returns the built-in upload limits of a tenant
+func DefaultUploadLimits(tenantID string) *UploadLimits {
+	return &UploadLimits{
+		TenantID:            tenantID,
+		UploadURLTTLSeconds: int(UploadURLTTL / time.Second),
+		MaxFileSizes: map[MediaType]int64{
+			TypeVideo:   GetMaxFileSize(TypeVideo),
+			TypePodcast: GetMaxFileSize(TypePodcast),
+		},
+		Formats: map[MediaType][]string{
+			TypeVideo:   FormatsFor(TypeVideo),
+			TypePodcast: FormatsFor(TypePodcast),
+		},
+	}
+}
+
+// UploadURLTTL returns how long upload URLs stay valid
+func (l *UploadLimits) UploadURLTTL() time.Duration {
+	return time.Duration(l.UploadURLTTLSeconds) * time.Second
+}
+
+// MaxFileSize returns the maximum allowed file size for a media type, 0 when it cannot be uploaded
+func (l *UploadLimits) MaxFileSize(mediaType MediaType) int64 {
+	return l.MaxFileSizes[mediaType]
+}
+
+// RemainingQuota returns the bytes a tenant using used bytes can still upload, -1 when unlimited
+func (l *UploadLimits) RemainingQuota(used int64) int64 {
+	if l.StorageQuota <= 0 {
+		return -1
+	}
+	if used >= l.StorageQuota {
+		return 0
+	}
+	return l.StorageQuota - used
+}
+
+// AllowsFileSize checks if a file of size bytes can be uploaded as the media type
+func (l *UploadLimits) AllowsFileSize(mediaType MediaType, size int64) bool {
+	return size > 0 && size <= l.MaxFileSize(mediaType)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultUploadLimits(t *testing.T) {
+	// When
+	limits := DefaultUploadLimits(DefaultTenantID)
+
+	// Then
+	assert.Equal(t, DefaultTenantID, limits.TenantID)
+	assert.Equal(t, UploadURLTTL, limits.UploadURLTTL())
+	assert.Equal(t, int64(MaxVideoFileSize), limits.MaxFileSize(TypeVideo))
+	assert.Equal(t, int64(MaxPodcastFileSize), limits.MaxFileSize(TypePodcast))
+	assert.Equal(t, VideoFormats, limits.Formats[TypeVideo])
+	assert.Equal(t, AudioFormats, limits.Formats[TypePodcast])
+}
+
+func TestUploadLimits_AllowsFileSize(t *testing.T) {
+	limits := &UploadLimits{
+		UploadURLTTLSeconds: int(time.Hour / time.Second),
+		MaxFileSizes:        map[MediaType]int64{TypeVideo: 100, TypePodcast: 10},
+	}
+
+	tests := []struct {
+		name      string
+		mediaType MediaType
+		size      int64
+		expected  bool
+	}{
+		{"within video limit", TypeVideo, 100, true},
+		{"over video limit", TypeVideo, 101, false},
+		{"over podcast limit", TypePodcast, 11, false},
+		{"empty file", TypeVideo, 0, false},
+		{"unknown type", MediaType("image"), 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, limits.AllowsFileSize(tt.mediaType, tt.size))
+		})
+	}
+}
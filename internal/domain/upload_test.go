@@ -103,14 +103,47 @@ func TestUploadRequest_IsValid(t *testing.T) {
 			expected: false,
 		},
 		{
-			name: "file too large",
+			name: "high priority request",
+			request: UploadRequest{
+				Title:    "Breaking News",
+				Filename: "news.mp4",
+				FileSize: 1024 * 1024,
+				Type:     TypeVideo,
+				Priority: PriorityHigh,
+			},
+			expected: true,
+		},
+		{
+			name: "unknown priority",
+			request: UploadRequest{
+				Title:    "Test Video",
+				Filename: "test.mp4",
+				FileSize: 1024 * 1024,
+				Type:     TypeVideo,
+				Priority: MediaPriority("urgent"),
+			},
+			expected: false,
+		},
+		{
+			name: "unknown visibility",
+			request: UploadRequest{
+				Title:      "Test Video",
+				Filename:   "test.mp4",
+				FileSize:   1024 * 1024,
+				Type:       TypeVideo,
+				Visibility: MediaVisibility("secret"),
+			},
+			expected: false,
+		},
+		{
+			name: "file over the default limit is checked against the tenant limits",
 			request: UploadRequest{
 				Title:    "Large Video",
 				Filename: "large.mp4",
-				FileSize: 6 * 1024 * 1024 * 1024, // 6GB (over 5GB limit)
+				FileSize: 6 * 1024 * 1024 * 1024, // 6GB (over the 5GB default)
 				Type:     TypeVideo,
 			},
-			expected: false,
+			expected: true,
 		},
 	}
 
@@ -130,6 +163,7 @@ func TestUploadRequest_ToMedia(t *testing.T) {
 		Filename:    "test.mp4",
 		FileSize:    1024 * 1024,
 		Type:        TypeVideo,
+		OwnerID:     "user-1",
 	}
 	id := "media-123"
 	filePath := "/uploads/media-123.mp4"
@@ -144,8 +178,13 @@ func TestUploadRequest_ToMedia(t *testing.T) {
 	assert.Equal(t, request.Description, media.Description)
 	assert.Equal(t, filePath, media.FilePath)
 	assert.Equal(t, request.FileSize, media.FileSize)
+	assert.Equal(t, "mp4", media.Format)
 	assert.Equal(t, request.Type, media.Type)
 	assert.Equal(t, StatusUploading, media.Status)
+	assert.Equal(t, PriorityNormal, media.Priority)
+	assert.Equal(t, VisibilityPublic, media.Visibility)
+	assert.Equal(t, ContentRating{AgeRating: AgeRatingAll}, media.ContentRating)
+	assert.Equal(t, "user-1", media.OwnerID)
 	assert.False(t, media.CreatedAt.IsZero())
 	assert.False(t, media.UpdatedAt.IsZero())
 }
@@ -226,6 +265,44 @@ func TestUpdateMediaRequest_ApplyTo_EmptyUpdate(t *testing.T) {
 	assert.True(t, media.UpdatedAt.After(beforeUpdate)) // UpdatedAt should still be updated
 }
 
+func TestUpdateMediaRequest_Visibility(t *testing.T) {
+	// Given
+	media := &Media{ID: "123", Visibility: VisibilityPublic}
+	private := VisibilityPrivate
+	unknown := MediaVisibility("secret")
+
+	// When
+	request := &UpdateMediaRequest{Visibility: &private}
+	request.ApplyTo(media)
+
+	// Then
+	assert.True(t, request.IsValid())
+	assert.Equal(t, VisibilityPrivate, media.Visibility)
+	assert.False(t, (&UpdateMediaRequest{Visibility: &unknown}).IsValid())
+	assert.True(t, (&UpdateMediaRequest{}).IsValid())
+}
+
+func TestUpdateMediaRequest_UnpublishAt(t *testing.T) {
+	// Given
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	unpublishAt := now.Add(time.Hour)
+	media := &Media{ID: "123"}
+
+	// When scheduled, then cleared
+	schedule := &UpdateMediaRequest{UnpublishAt: &unpublishAt}
+	schedule.ApplyTo(media)
+	scheduled := media.UnpublishAt
+	(&UpdateMediaRequest{ClearUnpublishAt: true}).ApplyTo(media)
+
+	// Then
+	assert.Equal(t, &unpublishAt, scheduled)
+	assert.Nil(t, media.UnpublishAt)
+	assert.False(t, (&UpdateMediaRequest{UnpublishAt: &unpublishAt, ClearUnpublishAt: true}).IsValid())
+	assert.False(t, ValidateUnpublishAt(&unpublishAt, now).HasErrors())
+	assert.True(t, ValidateUnpublishAt(&now, now).HasErrors())
+	assert.False(t, ValidateUnpublishAt(nil, now).HasErrors())
+}
+
 func TestUploadURL_Structure(t *testing.T) {
 	// Test that UploadURL has the expected fields
 	uploadURL := UploadURL{
@@ -238,3 +315,52 @@ func TestUploadURL_Structure(t *testing.T) {
 	assert.Equal(t, "https://example.com/upload", uploadURL.URL)
 	assert.False(t, uploadURL.ExpiresAt.IsZero())
 }
+
+func TestUploadRequest_Format(t *testing.T) {
+	tests := []struct {
+		name        string
+		filename    string
+		mediaType   MediaType
+		format      string
+		validFormat bool
+	}{
+		{"video", "talk.mp4", TypeVideo, "mp4", true},
+		{"upper case extension", "Talk.MOV", TypeVideo, "mov", true},
+		{"podcast", "episode.final.mp3", TypePodcast, "mp3", true},
+		{"audio declared as video", "episode.mp3", TypeVideo, "mp3", false},
+		{"video declared as podcast", "talk.mp4", TypePodcast, "mp4", false},
+		{"no extension", "talk", TypeVideo, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &UploadRequest{Filename: tt.filename, Type: tt.mediaType}
+
+			assert.Equal(t, tt.format, request.Format())
+			assert.Equal(t, tt.validFormat, request.HasValidFormat())
+		})
+	}
+}
+
+func TestUploadRequest_Validate(t *testing.T) {
+	// Given a request breaking several rules
+	request := &UploadRequest{
+		Title:       "  ",
+		Filename:    "episode.mp4",
+		FileSize:    0,
+		Type:        TypePodcast,
+		Visibility:  MediaVisibility("secret"),
+		ContentHash: "not-a-hash",
+	}
+
+	// When
+	errs := request.Validate()
+
+	// Then every violation is reported
+	fields := make([]string, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	assert.Equal(t, []string{"title", "file_size", "filename", "visibility", "content_hash"}, fields)
+	assert.False(t, request.IsValid())
+}
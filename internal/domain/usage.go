@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// AnonymousAPIKeyID identifies requests made without an API key in usage records
+const AnonymousAPIKeyID = "anonymous"
+
+// Top usage listings
+const (
+	DefaultTopUsageLimit = 10
+	MaxTopUsageLimit     = 100
+)
+
+// UsageSort orders the top consumers of a day
+type UsageSort string
+
+const (
+	UsageSortRequests UsageSort = "requests"
+	UsageSortBytesIn  UsageSort = "bytes_in"
+	UsageSortBytesOut UsageSort = "bytes_out"
+)
+
+// IsValid checks if the usage sort is supported
+func (s UsageSort) IsValid() bool {
+	switch s {
+	case UsageSortRequests, UsageSortBytesIn, UsageSortBytesOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIKeyID identifies an API key in usage records without storing the key itself
+func APIKeyID(key string) string {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AnonymousAPIKeyID
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// UsageSample is the usage of a single API request
+type UsageSample struct {
+	TenantID string
+	APIKeyID string
+	BytesIn  int64 // request body
+	BytesOut int64 // response body
+	At       time.Time
+}
+
+// Day returns the UTC day the sample is counted in
+func (s UsageSample) Day() string {
+	return s.At.UTC().Format(CalendarDateLayout)
+}
+
+// UsageCounters are the live counters of a tenant and API key over a day
+type UsageCounters struct {
+	Day      string
+	TenantID string
+	APIKeyID string
+	Requests int64
+	BytesIn  int64
+	BytesOut int64
+}
+
+// Add counts a request of sample
+func (c *UsageCounters) Add(sample UsageSample) {
+	c.Requests++
+	c.BytesIn += max(sample.BytesIn, 0)
+	c.BytesOut += max(sample.BytesOut, 0)
+}
+
+// UsageRecord is the daily rollup of the usage of a tenant and API key
+type UsageRecord struct {
+	Day      string `json:"day" gorm:"type:varchar(10);primaryKey"` // UTC day, like 2025-06-01
+	TenantID string `json:"tenant_id" gorm:"type:varchar(100);primaryKey"`
+	APIKeyID string `json:"api_key_id" gorm:"type:varchar(32);primaryKey"`
+	Requests int64  `json:"requests" gorm:"not null;default:0"`
+	BytesIn  int64  `json:"bytes_in" gorm:"not null;default:0"`
+	BytesOut int64  `json:"bytes_out" gorm:"not null;default:0"`
+	// Bytes of the tenant's media at the rollup, the same for every API key of the tenant
+	StorageBytes int64     `json:"storage_bytes" gorm:"not null;default:0"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for UsageRecord
+func (UsageRecord) TableName() string {
+	return "usage_records"
+}
+
+// NewUsageRecord rolls up the counters of a day with the storage used by their tenant
+func NewUsageRecord(counters UsageCounters, storageBytes int64, now time.Time) UsageRecord {
+	return UsageRecord{
+		Day:          counters.Day,
+		TenantID:     counters.TenantID,
+		APIKeyID:     counters.APIKeyID,
+		Requests:     counters.Requests,
+		BytesIn:      counters.BytesIn,
+		BytesOut:     counters.BytesOut,
+		StorageBytes: storageBytes,
+		UpdatedAt:    now,
+	}
+}
+
+// UsageReport sums the daily usage of a tenant or an API key over a range of days
+type UsageReport struct {
+	TenantID string        `json:"tenant_id,omitempty"`
+	APIKeyID string        `json:"api_key_id,omitempty"`
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Requests int64         `json:"requests"`
+	BytesIn  int64         `json:"bytes_in"`
+	BytesOut int64         `json:"bytes_out"`
+	Storage  int64         `json:"storage_bytes"` // latest storage of each tenant in the range, summed
+	Days     []UsageRecord `json:"days"`          // in day order, days without requests are left out
+}
+
+// NewUsageReport sums the usage records of the range [from, end), given in day order
+func NewUsageReport(records []UsageRecord, from, end time.Time) *UsageReport {
+	report := &UsageReport{
+		From: from.Format(CalendarDateLayout),
+		To:   end.AddDate(0, 0, -1).Format(CalendarDateLayout),
+		Days: records,
+	}
+	if report.Days == nil {
+		report.Days = []UsageRecord{}
+	}
+	storage := make(map[string]int64)
+	for _, record := range records {
+		report.Requests += record.Requests
+		report.BytesIn += record.BytesIn
+		report.BytesOut += record.BytesOut
+		storage[record.TenantID] = record.StorageBytes
+	}
+	for _, bytes := range storage {
+		report.Storage += bytes
+	}
+	return report
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyID(t *testing.T) {
+	assert.Equal(t, AnonymousAPIKeyID, APIKeyID(""))
+	assert.Equal(t, APIKeyID("secret-key"), APIKeyID(" secret-key "))
+	assert.NotEqual(t, APIKeyID("secret-key"), APIKeyID("other-key"))
+	assert.Len(t, APIKeyID("secret-key"), 16)
+	assert.NotContains(t, APIKeyID("secret-key"), "secret")
+}
+
+func TestUsageCounters_Add(t *testing.T) {
+	counters := UsageCounters{}
+	counters.Add(UsageSample{BytesIn: 100, BytesOut: 2000})
+	counters.Add(UsageSample{BytesIn: -1, BytesOut: -1}) // unknown sizes
+
+	assert.Equal(t, UsageCounters{Requests: 2, BytesIn: 100, BytesOut: 2000}, counters)
+}
+
+func TestNewUsageReport(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)
+	records := []UsageRecord{
+		{Day: "2025-06-01", TenantID: "acme", APIKeyID: "k1", Requests: 10, BytesIn: 5, BytesOut: 100, StorageBytes: 1000},
+		{Day: "2025-06-01", TenantID: "globex", APIKeyID: "k2", Requests: 1, BytesOut: 10, StorageBytes: 50},
+		{Day: "2025-06-02", TenantID: "acme", APIKeyID: "k1", Requests: 5, BytesOut: 50, StorageBytes: 1500},
+	}
+
+	report := NewUsageReport(records, from, end)
+
+	assert.Equal(t, "2025-06-01", report.From)
+	assert.Equal(t, "2025-06-07", report.To)
+	assert.Equal(t, int64(16), report.Requests)
+	assert.Equal(t, int64(5), report.BytesIn)
+	assert.Equal(t, int64(160), report.BytesOut)
+	// Latest storage of each tenant
+	assert.Equal(t, int64(1550), report.Storage)
+
+	assert.Equal(t, []UsageRecord{}, NewUsageReport(nil, from, end).Days)
+}
+
+func TestUsageSort_IsValid(t *testing.T) {
+	assert.True(t, UsageSortRequests.IsValid())
+	assert.True(t, UsageSortBytesOut.IsValid())
+	assert.False(t, UsageSort("storage").IsValid())
+}
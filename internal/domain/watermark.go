@@ -0,0 +1,52 @@
+package domain
+
+import "strings"
+
+// WatermarkPosition is the corner (or center) of the video the watermark is anchored to
+type WatermarkPosition string
+
+const (
+	WatermarkTopLeft     WatermarkPosition = "top-left"
+	WatermarkTopRight    WatermarkPosition = "top-right"
+	WatermarkBottomLeft  WatermarkPosition = "bottom-left"
+	WatermarkBottomRight WatermarkPosition = "bottom-right"
+	WatermarkCenter      WatermarkPosition = "center"
+)
+
+// IsValid checks if the watermark position is valid
+func (p WatermarkPosition) IsValid() bool {
+	switch p {
+	case WatermarkTopLeft, WatermarkTopRight, WatermarkBottomLeft, WatermarkBottomRight, WatermarkCenter:
+		return true
+	default:
+		return false
+	}
+}
+
+// Watermark is a logo overlaid on video renditions during transcoding
+type Watermark struct {
+	LogoPath string            `json:"logo_path"`
+	Position WatermarkPosition `json:"position"`
+	Opacity  float64           `json:"opacity"` // 0 (invisible) to 1 (opaque)
+	Margin   int               `json:"margin"`  // distance from the anchored edges, in pixels
+}
+
+// Validate validates the watermark settings
+func (w Watermark) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if strings.TrimSpace(w.LogoPath) == "" {
+		errs.Add("logo_path", "is required")
+	}
+	if !w.Position.IsValid() {
+		errs.Add("position", "must be one of: top-left, top-right, bottom-left, bottom-right, center")
+	}
+	if w.Opacity <= 0 || w.Opacity > 1 {
+		errs.Add("opacity", "must be greater than 0 and at most 1")
+	}
+	if w.Margin < 0 {
+		errs.Add("margin", "must not be negative")
+	}
+
+	return errs
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatermark_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		watermark     Watermark
+		expectedError bool
+	}{
+		{
+			name:      "valid watermark",
+			watermark: Watermark{LogoPath: "logo.png", Position: WatermarkBottomRight, Opacity: 0.6, Margin: 24},
+		},
+		{
+			name:          "unknown position",
+			watermark:     Watermark{LogoPath: "logo.png", Position: "middle", Opacity: 0.6},
+			expectedError: true,
+		},
+		{
+			name:          "opacity out of range",
+			watermark:     Watermark{LogoPath: "logo.png", Position: WatermarkCenter, Opacity: 1.5},
+			expectedError: true,
+		},
+		{
+			name:          "missing logo",
+			watermark:     Watermark{Position: WatermarkTopLeft, Opacity: 0.5},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			errs := tt.watermark.Validate()
+
+			// Then
+			assert.Equal(t, tt.expectedError, errs.HasErrors())
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AbuseReportHandler handles HTTP requests for abuse reports and the moderation queue
+type AbuseReportHandler struct {
+	abuseReportService service.AbuseReportService
+}
+
+// NewAbuseReportHandler creates a new abuse report handler
+func NewAbuseReportHandler(abuseReportService service.AbuseReportService) *AbuseReportHandler {
+	return &AbuseReportHandler{
+		abuseReportService: abuseReportService,
+	}
+}
+
+// ReportMedia godoc
+// @Summary Report media
+// @Description Flag a media item for moderation with a reason code and optional free text. Repeated reports of the same media by the same user or client are folded into their open report. Reporters are limited to a number of reports per hour.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.AbuseReportRequest true "Abuse report"
+// @Success 202 {object} domain.AbuseReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/report [post]
+func (h *AbuseReportHandler) ReportMedia(c *gin.Context) {
+	var req domain.AbuseReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	reporter := domain.AbuseReporter{
+		UserID:    middleware.CurrentUserID(c),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	report, err := h.abuseReportService.ReportMedia(c.Request.Context(), c.Param("id"), reporter, &req)
+	if err != nil {
+		var cooldownErr *domain.CooldownError
+		if errors.As(err, &cooldownErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(cooldownErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, ErrorResponse{
+				Error:   "REPORT_LIMIT_REACHED",
+				Message: "Too many reports, please retry later",
+			})
+			return
+		}
+		h.handleError(c, err, "Failed to report media")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, report)
+}
+
+// GetModerationQueue godoc
+// @Summary Moderation queue
+// @Description List the media with open abuse reports, folded per media item, the most reported first
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Number of media items (default: 20, max: 100)"
+// @Success 200 {object} ModerationQueueResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/moderation/reports [get]
+func (h *AbuseReportHandler) GetModerationQueue(c *gin.Context) {
+	limit, limitErr := parseLimit(c, domain.DefaultModerationQueueLimit, domain.MaxModerationQueueLimit)
+	if limitErr != nil {
+		h.handleError(c, limitErr, "")
+		return
+	}
+
+	queue, err := h.abuseReportService.GetModerationQueue(c.Request.Context(), limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to get moderation queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModerationQueueResponse{Items: queue})
+}
+
+// ResolveReports godoc
+// @Summary Resolve abuse reports
+// @Description Close the open abuse reports of a media item with a moderation decision, which is announced as a moderation.decided event
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.ResolveAbuseReportsRequest true "Moderation decision"
+// @Success 200 {object} ResolveReportsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/moderation/reports/{id}/resolve [post]
+func (h *AbuseReportHandler) ResolveReports(c *gin.Context) {
+	var req domain.ResolveAbuseReportsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resolved, err := h.abuseReportService.ResolveReports(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrAbuseReportNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "REPORTS_NOT_FOUND",
+				Message: "Media has no open abuse reports",
+			})
+			return
+		}
+		h.handleError(c, err, "Failed to resolve abuse reports")
+		return
+	}
+
+	c.JSON(http.StatusOK, ResolveReportsResponse{Resolved: resolved})
+}
+
+// handleError maps abuse report service errors to HTTP responses
+func (h *AbuseReportHandler) handleError(c *gin.Context, err error, message string) {
+	if errors.Is(err, domain.ErrMediaNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// ModerationQueueResponse represents the moderation queue
+type ModerationQueueResponse struct {
+	Items []domain.ModerationQueueItem `json:"items"`
+}
+
+// ResolveReportsResponse represents the result of a moderation decision
+type ResolveReportsResponse struct {
+	Resolved int64 `json:"resolved"` // abuse reports closed
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdMarkerHandler handles HTTP requests for the ad breaks of media
+type AdMarkerHandler struct {
+	adMarkerService service.AdMarkerService
+}
+
+// NewAdMarkerHandler creates a new ad marker handler
+func NewAdMarkerHandler(adMarkerService service.AdMarkerService) *AdMarkerHandler {
+	return &AdMarkerHandler{
+		adMarkerService: adMarkerService,
+	}
+}
+
+// SetCuePoints godoc
+// @Summary Set ad breaks
+// @Description Replace the pre-roll, mid-roll and post-roll ad breaks of a media item. Mid-rolls need an offset within the media; an empty list removes every break.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.CuePointsRequest true "Cue points request"
+// @Success 200 {object} domain.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/cue-points [put]
+func (h *AdMarkerHandler) SetCuePoints(c *gin.Context) {
+	var req domain.CuePointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	media, err := h.adMarkerService.SetCuePoints(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to set cue points")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// ResolveMarkers godoc
+// @Summary Resolve ad markers
+// @Description For ad servers: the ad breaks of a media item at playback time, with the show, category, labels and content rating to target ads by
+// @Tags ads
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.AdMarkers
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/ads/media/{id}/markers [get]
+func (h *AdMarkerHandler) ResolveMarkers(c *gin.Context) {
+	markers, err := h.adMarkerService.ResolveMarkers(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to resolve ad markers")
+		return
+	}
+
+	c.JSON(http.StatusOK, markers)
+}
+
+// handleError maps ad marker service errors to HTTP responses
+func (h *AdMarkerHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrMediaNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
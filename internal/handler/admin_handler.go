@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"io"
+	"time"
+
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventStreamHeartbeat keeps idle event streams alive through proxies
+const eventStreamHeartbeat = 15 * time.Second
+
+// AdminHandler handles HTTP requests for admin tooling
+type AdminHandler struct {
+	eventStreamService service.EventStreamService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(eventStreamService service.EventStreamService) *AdminHandler {
+	return &AdminHandler{
+		eventStreamService: eventStreamService,
+	}
+}
+
+// StreamEvents godoc
+// @Summary Stream domain events
+// @Description Stream domain events in real time as Server-Sent Events
+// @Tags admin
+// @Produce text/event-stream
+// @Param topic query string false "Event type pattern, e.g. media.# (default: all events)"
+// @Success 200 {string} string "Event stream"
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/events/stream [get]
+func (h *AdminHandler) StreamEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	events, err := h.eventStreamService.Subscribe(ctx, c.Query("topic"))
+	if err != nil {
+		respondInternalError(c, "Failed to subscribe to events", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event := <-events:
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"time": time.Now().UTC()})
+			return true
+		}
+	})
+}
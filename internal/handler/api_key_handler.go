@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles HTTP requests for API key management
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Create an API key for a tenant. The key is only returned in this response, store it safely.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body domain.APIKeyRequest true "API key request"
+// @Success 201 {object} domain.CreatedAPIKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req domain.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List all API keys, revoked ones included. Keys are identified by their prefix, the keys themselves are never returned.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} APIKeyListResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to list API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, APIKeyListResponse{Items: keys})
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate API key
+// @Description Replace the key of an active API key. The previous key stops working immediately and the new key is only returned in this response.
+// @Tags admin
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} domain.CreatedAPIKey
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	key, err := h.apiKeyService.RotateAPIKey(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to rotate API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revoke an API key so it no longer authenticates requests
+// @Tags admin
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "API key revoked successfully",
+	})
+}
+
+// handleError maps API key service errors to HTTP responses
+func (h *APIKeyHandler) handleError(c *gin.Context, err error, message string) {
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "API_KEY_NOT_FOUND",
+			Message: "Active API key not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// APIKeyListResponse represents a list of API keys
+type APIKeyListResponse struct {
+	Items []*domain.APIKey `json:"items"`
+}
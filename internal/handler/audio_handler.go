@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AudioHandler handles HTTP requests for derived audio formats
+type AudioHandler struct {
+	audioService service.AudioService
+}
+
+// NewAudioHandler creates a new audio handler
+func NewAudioHandler(audioService service.AudioService) *AudioHandler {
+	return &AudioHandler{
+		audioService: audioService,
+	}
+}
+
+// GetAudio godoc
+// @Summary Get podcast audio in a derived format
+// @Description Stream the audio of a podcast converted to the requested codec. The first request transcodes and caches the asset; later requests are served from the cache.
+// @Tags media
+// @Produce audio/aac,audio/ogg
+// @Param id path string true "Media ID"
+// @Param format query string true "Audio format (aac, opus)"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} EntitlementRequiredResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} GeoRestrictedResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/audio [get]
+func (h *AudioHandler) GetAudio(c *gin.Context) {
+	format := domain.AudioFormat(c.Query("format"))
+
+	asset, err := h.audioService.GetAudio(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c), format)
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		if respondGeoRestricted(c, err) {
+			return
+		}
+		if respondNotEntitled(c, err) {
+			return
+		}
+		if respondPremiereNotStarted(c, err) {
+			return
+		}
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to convert audio", err)
+		return
+	}
+	defer asset.Body.Close()
+
+	cacheStatus := "MISS"
+	if asset.Cached {
+		cacheStatus = "HIT"
+	}
+
+	c.DataFromReader(http.StatusOK, asset.Size, asset.ContentType, asset.Body, map[string]string{
+		"Cache-Control": "private, max-age=3600",
+		"X-Cache":       cacheStatus,
+	})
+}
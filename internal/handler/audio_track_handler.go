@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AudioTrackHandler handles HTTP requests for the dubbed audio tracks of media
+type AudioTrackHandler struct {
+	audioTrackService service.AudioTrackService
+}
+
+// NewAudioTrackHandler creates a new audio track handler
+func NewAudioTrackHandler(audioTrackService service.AudioTrackService) *AudioTrackHandler {
+	return &AudioTrackHandler{
+		audioTrackService: audioTrackService,
+	}
+}
+
+// SetAudioTrack godoc
+// @Summary Upload a dubbed audio track
+// @Description Store the audio of a media item dubbed in a language, replacing the earlier track of that language. The body is the raw audio file, of a podcast format. The track is listed in the playback info and the media is found when searching by its language.
+// @Tags media
+// @Accept octet-stream
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param language path string true "Language tag of the track, like en or es-419"
+// @Param format query string true "Audio file format (mp3, wav, flac, aac, ogg)"
+// @Param label query string false "Name of the track shown in players"
+// @Success 200 {object} domain.AudioTrack
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/audio-tracks/{language} [put]
+func (h *AudioTrackHandler) SetAudioTrack(c *gin.Context) {
+	track, err := h.audioTrackService.SetAudioTrack(c.Request.Context(), c.Param("id"), &domain.AudioTrackUpload{
+		Language: c.Param("language"),
+		Label:    c.Query("label"),
+		Format:   c.Query("format"),
+		Body:     c.Request.Body,
+		Size:     c.Request.ContentLength,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to store audio track")
+		return
+	}
+
+	c.JSON(http.StatusOK, track)
+}
+
+// GetAudioTrack godoc
+// @Summary Get a dubbed audio track
+// @Description Stream the audio of a media item dubbed in a language
+// @Tags media
+// @Produce audio/mpeg,audio/wav,audio/flac,audio/aac,audio/ogg
+// @Param id path string true "Media ID"
+// @Param language path string true "Language tag of the track"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} EntitlementRequiredResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} GeoRestrictedResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/audio-tracks/{language} [get]
+func (h *AudioTrackHandler) GetAudioTrack(c *gin.Context) {
+	asset, err := h.audioTrackService.GetAudioTrack(c.Request.Context(), c.Param("id"), c.Param("language"), middleware.CurrentViewer(c))
+	if err != nil {
+		if respondGeoRestricted(c, err) {
+			return
+		}
+		if respondNotEntitled(c, err) {
+			return
+		}
+		if respondPremiereNotStarted(c, err) {
+			return
+		}
+		h.handleError(c, err, "Failed to get audio track")
+		return
+	}
+	defer asset.Body.Close()
+
+	c.DataFromReader(http.StatusOK, asset.Size, asset.ContentType, asset.Body, map[string]string{
+		"Cache-Control":    "private, max-age=3600",
+		"Content-Language": asset.Language,
+	})
+}
+
+// RemoveAudioTrack godoc
+// @Summary Remove a dubbed audio track
+// @Description Delete the audio of a media item dubbed in a language
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param language path string true "Language tag of the track"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/audio-tracks/{language} [delete]
+func (h *AudioTrackHandler) RemoveAudioTrack(c *gin.Context) {
+	if err := h.audioTrackService.RemoveAudioTrack(c.Request.Context(), c.Param("id"), c.Param("language")); err != nil {
+		h.handleError(c, err, "Failed to remove audio track")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Audio track removed successfully",
+	})
+}
+
+// handleError maps audio track service errors to HTTP responses
+func (h *AudioTrackHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	case errors.Is(err, domain.ErrAudioTrackNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "AUDIO_TRACK_NOT_FOUND",
+			Message: "Media has no audio track in this language",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		status := http.StatusBadRequest
+		if businessErr.Code == "FILE_TOO_LARGE" {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkHandler handles HTTP requests for bulk media operations
+type BulkHandler struct {
+	bulkService service.BulkLabelService
+}
+
+// NewBulkHandler creates a new bulk handler
+func NewBulkHandler(bulkService service.BulkLabelService) *BulkHandler {
+	return &BulkHandler{
+		bulkService: bulkService,
+	}
+}
+
+// LabelMedia godoc
+// @Summary Bulk label media
+// @Description Add or remove labels and set the category of media selected by IDs or query. Selections of up to 100 media complete before responding; larger ones run in the background, follow their job for the report.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param request body domain.BulkLabelRequest true "Bulk label request"
+// @Success 200 {object} domain.BulkJob
+// @Success 202 {object} domain.BulkJob
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/bulk/tags [post]
+func (h *BulkHandler) LabelMedia(c *gin.Context) {
+	var req domain.BulkLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	job, err := h.bulkService.LabelMedia(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to label media")
+		return
+	}
+
+	if job.Status == domain.BulkJobRunning {
+		c.Header("Location", "/api/v1/media/bulk/jobs/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// GetJob godoc
+// @Summary Get bulk job
+// @Description Get the progress and report of a bulk operation
+// @Tags media
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.BulkJob
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/bulk/jobs/{id} [get]
+func (h *BulkHandler) GetJob(c *gin.Context) {
+	job, err := h.bulkService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get bulk job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// handleError maps bulk service errors to HTTP responses
+func (h *BulkHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrBulkJobNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "BULK_JOB_NOT_FOUND",
+			Message: "Bulk job not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler handles HTTP requests for the editorial content calendar
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(calendarService service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetCalendar godoc
+// @Summary Get content calendar
+// @Description List license starts, license expiries and scheduled unpublishes of media by UTC day, for at most 92 days
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day, YYYY-MM-DD (default: today)"
+// @Param to query string false "Last day, YYYY-MM-DD (default: 30 days from from)"
+// @Success 200 {object} domain.Calendar
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/calendar [get]
+func (h *CalendarHandler) GetCalendar(c *gin.Context) {
+	calendar, err := h.calendarService.GetCalendar(c.Request.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to get content calendar", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChapterHandler handles HTTP requests for the chapters of media
+type ChapterHandler struct {
+	chapterService service.ChapterService
+}
+
+// NewChapterHandler creates a new chapter handler
+func NewChapterHandler(chapterService service.ChapterService) *ChapterHandler {
+	return &ChapterHandler{
+		chapterService: chapterService,
+	}
+}
+
+// GetChapters godoc
+// @Summary Get chapters
+// @Description Get the chapters of a media item and the chapter boundaries suggested during processing from long silences and music stingers
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.MediaChapters
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/chapters [get]
+func (h *ChapterHandler) GetChapters(c *gin.Context) {
+	chapters, err := h.chapterService.GetChapters(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get chapters")
+		return
+	}
+
+	c.JSON(http.StatusOK, chapters)
+}
+
+// SetChapters godoc
+// @Summary Set chapters
+// @Description Replace the chapters of a media item; an empty list removes every chapter. Suggestions are kept.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.ChaptersRequest true "Chapters request"
+// @Success 200 {object} domain.MediaChapters
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/chapters [put]
+func (h *ChapterHandler) SetChapters(c *gin.Context) {
+	var req domain.ChaptersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	chapters, err := h.chapterService.SetChapters(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to set chapters")
+		return
+	}
+
+	c.JSON(http.StatusOK, chapters)
+}
+
+// AcceptSuggestions godoc
+// @Summary Accept chapter suggestions
+// @Description Turn suggested chapter boundaries, picked by start time, into chapters. Chapters without a title are named after their start time, and replace chapters starting at the same time.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.AcceptChaptersRequest true "Suggestions to accept"
+// @Success 200 {object} domain.MediaChapters
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/chapters/accept [post]
+func (h *ChapterHandler) AcceptSuggestions(c *gin.Context) {
+	var req domain.AcceptChaptersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	chapters, err := h.chapterService.AcceptSuggestions(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to accept chapter suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, chapters)
+}
+
+// handleError maps chapter service errors to HTTP responses
+func (h *ChapterHandler) handleError(c *gin.Context, err error, message string) {
+	if errors.Is(err, domain.ErrMediaNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
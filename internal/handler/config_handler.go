@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler handles HTTP requests for the runtime tunables
+type ConfigHandler struct {
+	tunables *config.TunablesStore
+}
+
+// NewConfigHandler creates a new config handler
+func NewConfigHandler(tunables *config.TunablesStore) *ConfigHandler {
+	return &ConfigHandler{
+		tunables: tunables,
+	}
+}
+
+// GetTunables godoc
+// @Summary Get runtime settings
+// @Description Get the settings that can be reloaded without restarting the service
+// @Tags admin
+// @Produce json
+// @Success 200 {object} config.Tunables
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/config [get]
+func (h *ConfigHandler) GetTunables(c *gin.Context) {
+	c.JSON(http.StatusOK, h.tunables.Get())
+}
+
+// ReloadTunables godoc
+// @Summary Reload runtime settings
+// @Description Re-read the tunables file and environment and apply the settings, as on SIGHUP
+// @Tags admin
+// @Produce json
+// @Success 200 {object} config.Tunables
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/config/reload [post]
+func (h *ConfigHandler) ReloadTunables(c *gin.Context) {
+	tunables, err := h.tunables.Reload()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_CONFIG",
+			Message: "Failed to reload settings, the current settings are kept",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tunables)
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DiscoverHandler handles HTTP requests for discovery listings and editor's picks
+type DiscoverHandler struct {
+	discoverService service.DiscoverService
+}
+
+// NewDiscoverHandler creates a new discover handler
+func NewDiscoverHandler(discoverService service.DiscoverService) *DiscoverHandler {
+	return &DiscoverHandler{
+		discoverService: discoverService,
+	}
+}
+
+// GetNew godoc
+// @Summary Recently added media
+// @Description List published media, newest first
+// @Tags discover
+// @Produce json
+// @Param limit query int false "Limit results (at most 100)" default(20)
+// @Param offset query int false "Offset results" default(0)
+// @Param cursor query string false "Cursor of the page to get, from next_cursor (instead of offset)"
+// @Param safe query bool false "Exclude explicit content"
+// @Success 200 {object} domain.DiscoverResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/discover/new [get]
+func (h *DiscoverHandler) GetNew(c *gin.Context) {
+	p, pageErr := parsePage(c, pageParams{defaultLimit: domain.DefaultDiscoverLimit, maxLimit: domain.MaxDiscoverLimit})
+	if pageErr != nil {
+		respondInvalidPage(c, pageErr)
+		return
+	}
+	safe, _ := strconv.ParseBool(c.Query("safe"))
+
+	mediaList, err := h.discoverService.GetNew(c.Request.Context(), p.Limit, p.Offset, safe)
+	if err != nil {
+		h.handleError(c, err, "Failed to get new media")
+		return
+	}
+
+	// A full page may be followed by another one
+	response := domain.DiscoverResponse{Media: mediaList}
+	if len(mediaList) == p.Limit {
+		response.NextCursor = encodeCursor(p.Offset + p.Limit)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFeatured godoc
+// @Summary Editor's picks
+// @Description List the published media of the active editor's picks in their curated order
+// @Tags discover
+// @Produce json
+// @Param safe query bool false "Exclude explicit content"
+// @Success 200 {object} domain.DiscoverResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/discover/featured [get]
+func (h *DiscoverHandler) GetFeatured(c *gin.Context) {
+	safe, _ := strconv.ParseBool(c.Query("safe"))
+
+	mediaList, err := h.discoverService.GetFeatured(c.Request.Context(), safe)
+	if err != nil {
+		h.handleError(c, err, "Failed to get featured media")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.DiscoverResponse{Media: mediaList})
+}
+
+// ListFeaturedItems godoc
+// @Summary List editor's picks
+// @Description List every editor's pick, including scheduled and ended ones, in position order
+// @Tags admin
+// @Produce json
+// @Success 200 {object} FeaturedItemListResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/featured [get]
+func (h *DiscoverHandler) ListFeaturedItems(c *gin.Context) {
+	items, err := h.discoverService.ListFeaturedItems(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to list featured items")
+		return
+	}
+
+	c.JSON(http.StatusOK, FeaturedItemListResponse{
+		Items: items,
+	})
+}
+
+// CreateFeaturedItem godoc
+// @Summary Add editor's pick
+// @Description Feature media at a position, optionally only between starts_at and ends_at
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body domain.FeaturedItemRequest true "Featured item request"
+// @Success 201 {object} domain.FeaturedItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/featured [post]
+func (h *DiscoverHandler) CreateFeaturedItem(c *gin.Context) {
+	var req domain.FeaturedItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	item, err := h.discoverService.CreateFeaturedItem(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create featured item")
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// UpdateFeaturedItem godoc
+// @Summary Update editor's pick
+// @Description Replace the media, position and schedule of an editor's pick
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Featured item ID"
+// @Param request body domain.FeaturedItemRequest true "Featured item request"
+// @Success 200 {object} domain.FeaturedItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/featured/{id} [put]
+func (h *DiscoverHandler) UpdateFeaturedItem(c *gin.Context) {
+	var req domain.FeaturedItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	item, err := h.discoverService.UpdateFeaturedItem(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update featured item")
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteFeaturedItem godoc
+// @Summary Remove editor's pick
+// @Description Remove an editor's pick
+// @Tags admin
+// @Produce json
+// @Param id path string true "Featured item ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/featured/{id} [delete]
+func (h *DiscoverHandler) DeleteFeaturedItem(c *gin.Context) {
+	if err := h.discoverService.DeleteFeaturedItem(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to delete featured item")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Featured item deleted successfully",
+	})
+}
+
+// handleError maps discover service errors to HTTP responses
+func (h *DiscoverHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrFeaturedItemNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "FEATURED_ITEM_NOT_FOUND",
+			Message: "Featured item not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// FeaturedItemListResponse represents a list of editor's picks
+type FeaturedItemListResponse struct {
+	Items []*domain.FeaturedItem `json:"items"`
+}
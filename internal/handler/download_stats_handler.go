@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadStatsHandler handles HTTP requests for podcast download statistics
+type DownloadStatsHandler struct {
+	statsService service.DownloadStatsService
+}
+
+// NewDownloadStatsHandler creates a new download statistics handler
+func NewDownloadStatsHandler(statsService service.DownloadStatsService) *DownloadStatsHandler {
+	return &DownloadStatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetEpisodeStats godoc
+// @Summary Episode download statistics
+// @Description Report the downloads of a podcast episode by UTC day, counted per the IAB podcast measurement guidelines: bots and byte range probes are excluded and requests of the same IP address and user agent count once per 24 hours
+// @Tags admin
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param from query string false "First day, YYYY-MM-DD (default: 30 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (default: today)"
+// @Success 200 {object} domain.DownloadStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/stats/downloads/media/{id} [get]
+func (h *DownloadStatsHandler) GetEpisodeStats(c *gin.Context) {
+	stats, err := h.statsService.GetEpisodeStats(c.Request.Context(), c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get episode download statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetShowStats godoc
+// @Summary Show download statistics
+// @Description Report the downloads of the episodes of a show by UTC day, counted like the episode statistics
+// @Tags admin
+// @Produce json
+// @Param id path string true "Show ID"
+// @Param from query string false "First day, YYYY-MM-DD (default: 30 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (default: today)"
+// @Success 200 {object} domain.DownloadStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/stats/downloads/shows/{id} [get]
+func (h *DownloadStatsHandler) GetShowStats(c *gin.Context) {
+	stats, err := h.statsService.GetShowStats(c.Request.Context(), c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get show download statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// handleError maps download statistics service errors to HTTP responses
+func (h *DownloadStatsHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrMediaNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmbedHandler handles HTTP requests for the embeddable player
+type EmbedHandler struct {
+	embedService service.EmbedService
+}
+
+// NewEmbedHandler creates a new embed handler
+func NewEmbedHandler(embedService service.EmbedService) *EmbedHandler {
+	return &EmbedHandler{
+		embedService: embedService,
+	}
+}
+
+// GetEmbedConfig godoc
+// @Summary Get embeddable player configuration
+// @Description Get the player configuration (stream URL, poster, captions, chapters, theme) of a media item. Requests from pages on origins that are not allowed to embed the player are rejected.
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param theme query string false "Player theme (dark, light)"
+// @Param accent_color query string false "Accent color as hex, e.g. #1DB954"
+// @Success 200 {object} EmbedConfigResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} GeoRestrictedResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/embed [get]
+func (h *EmbedHandler) GetEmbedConfig(c *gin.Context) {
+	origin := requestOrigin(c)
+	if origin != "" {
+		if !h.embedService.IsOriginAllowed(origin) {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "ORIGIN_NOT_ALLOWED",
+				Message: "This origin is not allowed to embed the player",
+				Details: origin,
+			})
+			return
+		}
+		c.Header("Access-Control-Allow-Origin", origin)
+		c.Header("Vary", "Origin")
+	}
+
+	theme := domain.EmbedTheme{
+		Mode:        c.Query("theme"),
+		AccentColor: c.Query("accent_color"),
+	}
+
+	config, err := h.embedService.GetEmbedConfig(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c), theme)
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		if respondGeoRestricted(c, err) {
+			return
+		}
+		if respondNotEntitled(c, err) {
+			return
+		}
+		if respondPremiereNotStarted(c, err) {
+			return
+		}
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to get embed configuration", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EmbedConfigResponse{
+		EmbedConfig:    config,
+		AllowedOrigins: h.embedService.AllowedOrigins(),
+	})
+}
+
+// requestOrigin returns the origin of the embedding page from the Origin or Referer header
+func requestOrigin(c *gin.Context) string {
+	if origin := c.GetHeader("Origin"); origin != "" {
+		return origin
+	}
+
+	referer, err := url.Parse(c.GetHeader("Referer"))
+	if err != nil || referer.Host == "" {
+		return ""
+	}
+	return strings.ToLower(referer.Scheme + "://" + referer.Host)
+}
+
+// EmbedConfigResponse represents the embeddable player configuration.
+// The player uses AllowedOrigins to refuse being framed by other sites.
+type EmbedConfigResponse struct {
+	*domain.EmbedConfig
+	AllowedOrigins []string `json:"allowed_origins"`
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryHandler handles HTTP requests for the listening history of the signed in user
+type HistoryHandler struct {
+	historyService service.HistoryService
+}
+
+// NewHistoryHandler creates a new listening history handler
+func NewHistoryHandler(historyService service.HistoryService) *HistoryHandler {
+	return &HistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// RecordPlay godoc
+// @Summary Record a play
+// @Description Add the start of playback of a media item to the history of the signed in user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body domain.PlayRequest true "Play request"
+// @Success 201 {object} domain.PlayEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/history [post]
+func (h *HistoryHandler) RecordPlay(c *gin.Context) {
+	var req domain.PlayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	event, err := h.historyService.RecordPlay(c.Request.Context(), middleware.CurrentUserID(c), &req, middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to record play")
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// GetHistory godoc
+// @Summary Listening history
+// @Description List the plays of the signed in user, most recent first
+// @Tags users
+// @Produce json
+// @Param type query string false "Media type (video, podcast)"
+// @Param from query string false "First UTC day, like 2025-06-01"
+// @Param to query string false "Last UTC day, like 2025-06-30"
+// @Param limit query int false "Limit results (at most 100)" default(20)
+// @Param offset query int false "Offset results" default(0)
+// @Param cursor query string false "Cursor of the page to get, from next_cursor (instead of offset)"
+// @Success 200 {object} domain.HistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/history [get]
+func (h *HistoryHandler) GetHistory(c *gin.Context) {
+	var query domain.HistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid history parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filter, errs := query.ToFilter()
+	if errs.HasErrors() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid history parameters",
+			Details: errs.Error(),
+		})
+		return
+	}
+
+	p, pageErr := parsePage(c, pageParams{defaultLimit: domain.DefaultPageSize, maxLimit: domain.MaxPageSize})
+	if pageErr != nil {
+		respondInvalidPage(c, pageErr)
+		return
+	}
+
+	entries, total, err := h.historyService.GetHistory(c.Request.Context(), middleware.CurrentUserID(c), filter, p.Limit, p.Offset, middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get history")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.HistoryResponse{
+		Items:      entries,
+		Total:      total,
+		NextCursor: nextCursor(p, len(entries), total),
+	})
+}
+
+// ClearHistory godoc
+// @Summary Clear listening history
+// @Description Forget every play of the signed in user, including their continue listening progress
+// @Tags users
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/history [delete]
+func (h *HistoryHandler) ClearHistory(c *gin.Context) {
+	if err := h.historyService.ClearHistory(c.Request.Context(), middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err, "Failed to clear history")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "History cleared",
+	})
+}
+
+// handleError maps history service errors to HTTP responses
+func (h *HistoryHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrMediaNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LiveHandler handles HTTP requests for live streams and the callbacks of the ingest server
+type LiveHandler struct {
+	liveService service.LiveService
+}
+
+// NewLiveHandler creates a new live handler
+func NewLiveHandler(liveService service.LiveService) *LiveHandler {
+	return &LiveHandler{
+		liveService: liveService,
+	}
+}
+
+// RegisterLiveStream godoc
+// @Summary Register a live stream
+// @Description Create a media item of type live for an upcoming stream and issue the ingest URL and stream key its encoder publishes with. The stream key is only returned once. When the stream ends, its recording becomes the file of the media, which is processed as a video or podcast.
+// @Tags live
+// @Accept json
+// @Produce json
+// @Param request body domain.LiveStreamRequest true "Live stream request"
+// @Success 201 {object} domain.RegisteredLiveStream
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/live [post]
+func (h *LiveHandler) RegisterLiveStream(c *gin.Context) {
+	var req domain.LiveStreamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+	req.OwnerID = middleware.CurrentUserID(c)
+
+	registered, err := h.liveService.RegisterLiveStream(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to register live stream")
+		return
+	}
+
+	c.JSON(http.StatusCreated, registered)
+}
+
+// GetLiveStream godoc
+// @Summary Get a live stream
+// @Description Get the status of the live stream of a media item: scheduled, live, ended, or archived once its recording was handed over to processing
+// @Tags live
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.LiveStream
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/live/{id} [get]
+func (h *LiveHandler) GetLiveStream(c *gin.Context) {
+	stream, err := h.liveService.GetLiveStream(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get live stream")
+		return
+	}
+
+	c.JSON(http.StatusOK, stream)
+}
+
+// StartIngest godoc
+// @Summary Live stream publishing started
+// @Description Called by the ingest server when an encoder starts publishing, with the stream key as the name form field (nginx-rtmp on_publish) or stream_key in JSON. Unknown keys and streams that ended are refused so the ingest server drops the encoder.
+// @Tags live
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Param name formData string true "Stream key"
+// @Success 200 {object} domain.LiveStream
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/live/ingest/start [post]
+func (h *LiveHandler) StartIngest(c *gin.Context) {
+	var callback domain.LiveIngestCallback
+	if !h.bindCallback(c, &callback) {
+		return
+	}
+
+	stream, err := h.liveService.StartIngest(c.Request.Context(), callback.StreamKey)
+	if err != nil {
+		h.handleError(c, err, "Failed to start live stream")
+		return
+	}
+
+	c.JSON(http.StatusOK, stream)
+}
+
+// EndIngest godoc
+// @Summary Live stream publishing ended
+// @Description Called by the ingest server when an encoder stops publishing, with the stream key and the storage key of the recording, if any. The recording is moved to the upload of the media, which becomes a video or podcast and is processed like a confirmed upload.
+// @Tags live
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Param name formData string true "Stream key"
+// @Param recording formData string false "Storage key of the recording"
+// @Success 200 {object} domain.LiveStream
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/live/ingest/end [post]
+func (h *LiveHandler) EndIngest(c *gin.Context) {
+	var callback domain.LiveIngestCallback
+	if !h.bindCallback(c, &callback) {
+		return
+	}
+
+	stream, err := h.liveService.EndIngest(c.Request.Context(), &callback)
+	if err != nil {
+		h.handleError(c, err, "Failed to end live stream")
+		return
+	}
+
+	c.JSON(http.StatusOK, stream)
+}
+
+// bindCallback binds an ingest server callback from a form or JSON body
+func (h *LiveHandler) bindCallback(c *gin.Context, callback *domain.LiveIngestCallback) bool {
+	if err := c.ShouldBind(callback); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "The stream key is required",
+			Details: err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// handleError maps live service errors to HTTP responses
+func (h *LiveHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	case errors.Is(err, domain.ErrLiveStreamNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "LIVE_STREAM_NOT_FOUND",
+			Message: "Live stream not found",
+		})
+		return
+	case errors.Is(err, domain.ErrLiveStreamEnded):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "LIVE_STREAM_ENDED",
+			Message: "Live stream has ended",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
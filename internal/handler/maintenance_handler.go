@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceHandler handles HTTP requests switching read-only maintenance mode
+type MaintenanceHandler struct {
+	mode *middleware.MaintenanceMode
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(mode *middleware.MaintenanceMode) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		mode: mode,
+	}
+}
+
+// SetMaintenanceRequest switches read-only maintenance mode
+type SetMaintenanceRequest struct {
+	ReadOnly *bool  `json:"read_only" binding:"required"`
+	Reason   string `json:"reason,omitempty"` // shown to clients whose writes are rejected
+}
+
+// GetMaintenance godoc
+// @Summary Get maintenance mode
+// @Description Get whether the service is in read-only maintenance mode
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.MaintenanceStatus
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, h.mode.Status())
+}
+
+// SetMaintenance godoc
+// @Summary Switch read-only maintenance mode
+// @Description Switch read-only maintenance mode on or off, for migrations and reindexes. While it is on, writes are rejected with 503 and a Retry-After header and reads keep working
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body SetMaintenanceRequest true "Maintenance mode"
+// @Success 200 {object} domain.MaintenanceStatus
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance [put]
+func (h *MaintenanceHandler) SetMaintenance(c *gin.Context) {
+	var req SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	status, err := h.mode.SetReadOnly(c.Request.Context(), *req.ReadOnly, req.Reason)
+	if err != nil {
+		respondInternalError(c, "Failed to switch maintenance mode", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
 	"thamaniyah/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -13,12 +19,19 @@ import (
 // MediaHandler handles HTTP requests for media operations
 type MediaHandler struct {
 	mediaService service.MediaService
+	countMode    domain.CountMode
 }
 
-// NewMediaHandler creates a new media handler
-func NewMediaHandler(mediaService service.MediaService) *MediaHandler {
+// NewMediaHandler creates a new media handler. Unfiltered listings count their
+// total as countMode selects unless the request asks otherwise.
+func NewMediaHandler(mediaService service.MediaService, countMode domain.CountMode) *MediaHandler {
+	if !countMode.IsValid() {
+		countMode = domain.CountExact
+	}
+
 	return &MediaHandler{
 		mediaService: mediaService,
+		countMode:    countMode,
 	}
 }
 
@@ -43,6 +56,11 @@ func (h *MediaHandler) CreateUploadURL(c *gin.Context) {
 		})
 		return
 	}
+	req.OwnerID = middleware.CurrentUserID(c)
+	// Only editors may jump the processing queue
+	if !middleware.CurrentRole(c).Includes(domain.RoleEditor) {
+		req.Priority = ""
+	}
 
 	uploadURL, err := h.mediaService.CreateUploadURL(c.Request.Context(), &req)
 	if err != nil {
@@ -54,15 +72,124 @@ func (h *MediaHandler) CreateUploadURL(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Failed to create upload URL",
+		respondInternalError(c, "Failed to create upload URL", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, uploadURL)
+}
+
+// ValidateUpload godoc
+// @Summary Validate an upload
+// @Description Check an upload request before requesting its URL: fields, format, size and storage quota of the tenant, and duplicates of the declared content hash. All violations are returned at once
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param request body domain.UploadRequest true "Upload request"
+// @Success 200 {object} UploadValidationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/validate-upload [post]
+func (h *MediaHandler) ValidateUpload(c *gin.Context) {
+	// Decoded without binding, missing fields are reported with the other violations
+	var req domain.UploadRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
 			Details: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, uploadURL)
+	violations, err := h.mediaService.ValidateUpload(c.Request.Context(), &req)
+	if err != nil {
+		respondInternalError(c, "Failed to validate upload", err)
+		return
+	}
+
+	if violations == nil {
+		violations = domain.ValidationErrors{}
+	}
+	c.JSON(http.StatusOK, UploadValidationResponse{
+		Valid:      !violations.HasErrors(),
+		Violations: violations,
+	})
+}
+
+// GetUploadLimits godoc
+// @Summary Get upload limits
+// @Description Get the upload URL TTL, maximum file sizes and formats in effect, for client side validation
+// @Tags media
+// @Produce json
+// @Param tenant query string false "Tenant ID, the default tenant when omitted"
+// @Success 200 {object} domain.UploadLimits
+// @Router /api/v1/limits [get]
+func (h *MediaHandler) GetUploadLimits(c *gin.Context) {
+	c.JSON(http.StatusOK, h.mediaService.GetUploadLimits(c.Request.Context(), c.Query("tenant")))
+}
+
+// UploadFile godoc
+// @Summary Upload a file
+// @Description Store the file of media at the upload URL issued for it when uploads are stored locally. The body is the raw file; it must be audio or video of a format accepted for the media type and no larger than the file size declared when the upload URL was requested.
+// @Tags media
+// @Accept octet-stream
+// @Produce json
+// @Param key path string true "Storage key of the upload"
+// @Param expires query int true "Expiry of the upload URL"
+// @Param signature query string true "Signature of the upload URL"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /upload/{key} [put]
+func (h *MediaHandler) UploadFile(c *gin.Context) {
+	// A malformed expiry fails verification like a wrong signature
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	err := h.mediaService.ReceiveUpload(c.Request.Context(), &domain.DirectUpload{
+		Key:       c.Param("key"),
+		Expires:   expires,
+		Signature: c.Query("signature"),
+		Body:      c.Request.Body,
+		Size:      c.Request.ContentLength,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidUploadURL):
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "INVALID_UPLOAD_URL",
+				Message: "Upload URL is invalid or expired",
+			})
+			return
+		case errors.Is(err, domain.ErrMediaNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			status := http.StatusBadRequest
+			if businessErr.Code == "FILE_TOO_LARGE" {
+				status = http.StatusRequestEntityTooLarge
+			}
+			c.JSON(status, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to store upload", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "File uploaded successfully",
+	})
 }
 
 // ConfirmUpload godoc
@@ -74,7 +201,9 @@ func (h *MediaHandler) CreateUploadURL(c *gin.Context) {
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
 // @Router /api/v1/media/{id}/confirm [post]
 func (h *MediaHandler) ConfirmUpload(c *gin.Context) {
 	mediaID := c.Param("id")
@@ -95,6 +224,15 @@ func (h *MediaHandler) ConfirmUpload(c *gin.Context) {
 			})
 			return
 		}
+		var transitionErr *domain.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "INVALID_STATUS_TRANSITION",
+				Message: "Media cannot move to the requested status",
+				Details: transitionErr.Error(),
+			})
+			return
+		}
 		if businessErr, ok := err.(*domain.BusinessError); ok {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:   businessErr.Code,
@@ -103,11 +241,7 @@ func (h *MediaHandler) ConfirmUpload(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Failed to confirm upload",
-			Details: err.Error(),
-		})
+		respondInternalError(c, "Failed to confirm upload", err)
 		return
 	}
 
@@ -136,7 +270,7 @@ func (h *MediaHandler) GetMedia(c *gin.Context) {
 		return
 	}
 
-	media, err := h.mediaService.GetMedia(c.Request.Context(), mediaID)
+	media, err := h.mediaService.GetVisibleMedia(c.Request.Context(), mediaID, middleware.CurrentViewer(c))
 	if err != nil {
 		if err == domain.ErrMediaNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{
@@ -145,65 +279,223 @@ func (h *MediaHandler) GetMedia(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Failed to get media",
-			Details: err.Error(),
-		})
+		respondInternalError(c, "Failed to get media", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, media)
 }
 
+// GetMediaBatch godoc
+// @Summary Get media by IDs
+// @Description Retrieve the media among a list of IDs in one request; missing and invisible media are left out
+// @Tags media
+// @Produce json
+// @Param ids query string true "Comma-separated media IDs (at most 100)"
+// @Success 200 {object} MediaBatchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/batch [get]
+func (h *MediaHandler) GetMediaBatch(c *gin.Context) {
+	var ids []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "At least one media ID is required",
+		})
+		return
+	}
+
+	mediaList, err := h.mediaService.GetMediaByIDs(c.Request.Context(), ids)
+	if err != nil {
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to get media", err)
+		return
+	}
+
+	// Private media is left out so its existence does not leak
+	viewer := middleware.CurrentViewer(c)
+	items := make([]*domain.Media, 0, len(mediaList))
+	for _, media := range mediaList {
+		if media.IsVisibleTo(viewer) {
+			items = append(items, media)
+		}
+	}
+
+	c.JSON(http.StatusOK, MediaBatchResponse{Items: items})
+}
+
 // GetAllMedia godoc
 // @Summary List all media
 // @Description Retrieve all media with pagination
 // @Tags media
 // @Produce json
-// @Param limit query int false "Limit" default(20)
+// @Param limit query int false "Limit (at most 100)" default(20)
 // @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "Cursor of the page to get, from next_cursor (instead of offset)"
+// @Param status query string false "Media status (non-ready statuses require admin)"
+// @Param failure_code query string false "Failure code (admin only)"
+// @Param visibility query string false "Visibility: public, unlisted, private (admin only; others only see public media)"
+// @Param safe query bool false "Exclude explicit content"
+// @Param count query string false "Total of unfiltered listings: exact or estimated (filtered listings are always exact)"
+// @Param owner query string false "List the media a user owns in any status: me for the signed in user, any user ID for admins (other filters are ignored)"
 // @Success 200 {object} MediaListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/media [get]
 func (h *MediaHandler) GetAllMedia(c *gin.Context) {
 	// Parse pagination parameters
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
+	p, pageErr := parsePage(c, pageParams{defaultLimit: domain.DefaultPageSize, maxLimit: domain.MaxPageSize})
+	if pageErr != nil {
+		respondInvalidPage(c, pageErr)
+		return
+	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20
+	countMode := h.countMode
+	if countStr := c.Query("count"); countStr != "" {
+		countMode = domain.CountMode(countStr)
+		if !countMode.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "INVALID_COUNT_MODE",
+				Message: "Count must be exact or estimated",
+				Details: countStr,
+			})
+			return
+		}
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	if owner := c.Query("owner"); owner != "" {
+		h.getOwnedMedia(c, owner, p)
+		return
 	}
 
-	mediaList, total, err := h.mediaService.GetAllMedia(c.Request.Context(), limit, offset)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Failed to get media list",
-			Details: err.Error(),
+	var mediaList []*domain.Media
+	var total int64
+	var err error
+
+	isAdmin := middleware.IsAdmin(c)
+	filter := &domain.MediaFilter{FailureCode: c.Query("failure_code")}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status, ok := domain.ParseMediaStatus(statusStr)
+		if !ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "INVALID_STATUS",
+				Message: "Unknown media status",
+				Details: statusStr,
+			})
+			return
+		}
+		filter.Status = status
+	}
+
+	// Only operators may look at items that are not publicly visible
+	if ((filter.Status != "" && filter.Status != domain.StatusReady) || filter.FailureCode != "") && !isAdmin {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "FORBIDDEN",
+			Message: "Admin access required for this filter",
 		})
 		return
 	}
 
+	// Admins may filter by visibility, everyone else only lists public media
+	if visibilityStr := c.Query("visibility"); visibilityStr != "" && isAdmin {
+		visibility := domain.MediaVisibility(visibilityStr)
+		if !visibility.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "INVALID_VISIBILITY",
+				Message: "Unknown media visibility",
+				Details: visibilityStr,
+			})
+			return
+		}
+		filter.Visibility = visibility
+	} else if !isAdmin {
+		filter.Visibility = domain.VisibilityPublic
+	}
+	// and only media ready to play, so uploads in progress and failures stay private
+	if !isAdmin {
+		filter.Status = domain.StatusReady
+	}
+
+	// Safe listings leave out explicit content
+	filter.Safe, _ = strconv.ParseBool(c.Query("safe"))
+
+	filtered := *filter != (domain.MediaFilter{})
+	if filtered {
+		mediaList, total, err = h.mediaService.GetFilteredMedia(c.Request.Context(), filter, p.Limit, p.Offset)
+	} else {
+		mediaList, total, err = h.mediaService.GetAllMedia(c.Request.Context(), p.Limit, p.Offset, countMode)
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to get media list", err)
+		return
+	}
+
 	response := MediaListResponse{
-		Items:  mediaList,
-		Total:  total,
-		Limit:  limit,
-		Offset: offset,
+		Items:          mediaList,
+		Total:          total,
+		TotalEstimated: !filtered && countMode == domain.CountEstimated,
+		Limit:          p.Limit,
+		Offset:         p.Offset,
+		NextCursor:     nextCursor(p, len(mediaList), total),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// getOwnedMedia lists the media an owner uploaded, whatever its status and visibility.
+// Users list their own media as owner "me", admins the media of any user.
+func (h *MediaHandler) getOwnedMedia(c *gin.Context, owner string, p page) {
+	if owner == domain.OwnerMe {
+		owner = middleware.CurrentUserID(c)
+		if owner == "" {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "UNAUTHORIZED",
+				Message: "Sign in required",
+			})
+			return
+		}
+	} else if owner != middleware.CurrentUserID(c) && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "FORBIDDEN",
+			Message: "Admin access required to list the media of other users",
+		})
+		return
+	}
+
+	mediaList, total, err := h.mediaService.GetMediaByOwner(c.Request.Context(), owner, p.Limit, p.Offset)
+	if err != nil {
+		respondInternalError(c, "Failed to get media list", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MediaListResponse{
+		Items:      mediaList,
+		Total:      total,
+		Limit:      p.Limit,
+		Offset:     p.Offset,
+		NextCursor: nextCursor(p, len(mediaList), total),
+	})
+}
+
 // UpdateMedia godoc
 // @Summary Update media metadata
-// @Description Update media metadata
+// @Description Update media metadata (editors, admins and the owner of the media)
 // @Tags media
 // @Accept json
 // @Produce json
@@ -211,6 +503,7 @@ func (h *MediaHandler) GetAllMedia(c *gin.Context) {
 // @Param request body domain.UpdateMediaRequest true "Update request"
 // @Success 200 {object} domain.Media
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/media/{id} [put]
@@ -223,6 +516,9 @@ func (h *MediaHandler) UpdateMedia(c *gin.Context) {
 		})
 		return
 	}
+	if !h.authorizeEdit(c, mediaID) {
+		return
+	}
 
 	var req domain.UpdateMediaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -243,25 +539,120 @@ func (h *MediaHandler) UpdateMedia(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Failed to update media",
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to update media", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// authorizeEdit responds with 403 unless the current request may edit the metadata of the media
+func (h *MediaHandler) authorizeEdit(c *gin.Context, mediaID string) bool {
+	viewer := middleware.CurrentViewer(c)
+	// Editors edit any media, no need to look it up
+	if viewer.Role.Includes(domain.RoleEditor) {
+		return true
+	}
+
+	media, err := h.mediaService.GetMedia(c.Request.Context(), mediaID)
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return false
+		}
+		respondInternalError(c, "Failed to authorize media update", err)
+		return false
+	}
+
+	if !media.IsEditableBy(viewer) {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "FORBIDDEN",
+			Message: "Editing media requires the editor role or owning the media",
+		})
+		return false
+	}
+	return true
+}
+
+// SetGeoRestriction godoc
+// @Summary Set media geo restriction
+// @Description Replace the allowed and blocked playback countries of a media item (admin only)
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.GeoRestriction true "Country rules"
+// @Success 200 {object} domain.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/geo-restriction [put]
+func (h *MediaHandler) SetGeoRestriction(c *gin.Context) {
+	mediaID := c.Param("id")
+	if mediaID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Media ID is required",
+		})
+		return
+	}
+
+	var req domain.GeoRestriction
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
 			Details: err.Error(),
 		})
 		return
 	}
 
+	media, err := h.mediaService.SetGeoRestriction(c.Request.Context(), mediaID, req)
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to update geo restriction", err)
+		return
+	}
+
 	c.JSON(http.StatusOK, media)
 }
 
 // DeleteMedia godoc
 // @Summary Delete media
-// @Description Soft delete a media record
+// @Description Soft delete a media record (admin only)
 // @Tags media
 // @Produce json
 // @Param id path string true "Media ID"
 // @Success 200 {object} SuccessResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/media/{id} [delete]
 func (h *MediaHandler) DeleteMedia(c *gin.Context) {
@@ -283,11 +674,16 @@ func (h *MediaHandler) DeleteMedia(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Failed to delete media",
-			Details: err.Error(),
-		})
+		var transitionErr *domain.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "INVALID_STATUS_TRANSITION",
+				Message: "Media cannot move to the requested status",
+				Details: transitionErr.Error(),
+			})
+			return
+		}
+		respondInternalError(c, "Failed to delete media", err)
 		return
 	}
 
@@ -296,13 +692,197 @@ func (h *MediaHandler) DeleteMedia(c *gin.Context) {
 	})
 }
 
+// ReprocessMedia godoc
+// @Summary Reprocess failed media
+// @Description Re-run processing for a media item in failed state (admin only)
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/media/{id}/process [post]
+func (h *MediaHandler) ReprocessMedia(c *gin.Context) {
+	mediaID := c.Param("id")
+	if mediaID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Media ID is required",
+		})
+		return
+	}
+
+	media, err := h.mediaService.ReprocessMedia(c.Request.Context(), mediaID)
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		var transitionErr *domain.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "INVALID_STATUS_TRANSITION",
+				Message: "Media cannot move to the requested status",
+				Details: transitionErr.Error(),
+			})
+			return
+		}
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to reprocess media", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
 // Response types
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+// MediaBatchResponse represents the media found among requested IDs
+type MediaBatchResponse struct {
+	Items []*domain.Media `json:"items"`
+}
+
+// UploadValidationResponse represents the outcome of an upload pre-flight check
+type UploadValidationResponse struct {
+	Valid      bool                    `json:"valid"`
+	Violations domain.ValidationErrors `json:"violations"`
+}
+
+// ErrorResponse represents an error response, shared with the middleware
+type ErrorResponse = middleware.ErrorResponse
+
+// GeoRestrictedResponse represents a playback refused in the viewer's country
+type GeoRestrictedResponse struct {
+	ErrorResponse
+	MediaID string `json:"media_id"`
+	Country string `json:"country,omitempty"`
+}
+
+// respondGeoRestricted writes a 451 response if err is a geo restriction.
+// It returns false for any other error so callers can keep mapping it.
+func respondGeoRestricted(c *gin.Context, err error) bool {
+	var geoErr *domain.GeoRestrictionError
+	if !errors.As(err, &geoErr) {
+		return false
+	}
+
+	c.JSON(http.StatusUnavailableForLegalReasons, GeoRestrictedResponse{
+		ErrorResponse: ErrorResponse{
+			Error:   "GEO_RESTRICTED",
+			Message: "Media is not available in your country",
+		},
+		MediaID: geoErr.MediaID,
+		Country: geoErr.Country,
+	})
+	return true
+}
+
+// EntitlementRequiredResponse represents a 403 response for premium media the viewer is not subscribed to
+type EntitlementRequiredResponse struct {
+	ErrorResponse
+	MediaID      string                 `json:"media_id"`
+	RequiredTier domain.MediaAccessTier `json:"required_tier"`
+}
+
+// respondNotEntitled writes a 403 response if err is a missing entitlement.
+// It returns true when the response was written.
+func respondNotEntitled(c *gin.Context, err error) bool {
+	var entitlementErr *domain.EntitlementError
+	if !errors.As(err, &entitlementErr) {
+		return false
+	}
+
+	c.JSON(http.StatusForbidden, EntitlementRequiredResponse{
+		ErrorResponse: ErrorResponse{
+			Error:   "SUBSCRIPTION_REQUIRED",
+			Message: "A premium subscription is required for this media",
+		},
+		MediaID:      entitlementErr.MediaID,
+		RequiredTier: entitlementErr.RequiredTier,
+	})
+	return true
+}
+
+// PremiereNotStartedResponse represents a 403 response for media whose premiere has not started
+type PremiereNotStartedResponse struct {
+	ErrorResponse
+	MediaID  string    `json:"media_id"`
+	StartsAt time.Time `json:"starts_at"`
+}
+
+// respondPremiereNotStarted writes a 403 response if err is a premiere that has not started.
+// It returns true when the response was written.
+func respondPremiereNotStarted(c *gin.Context, err error) bool {
+	var premiereErr *domain.PremiereNotStartedError
+	if !errors.As(err, &premiereErr) {
+		return false
+	}
+
+	c.JSON(http.StatusForbidden, PremiereNotStartedResponse{
+		ErrorResponse: ErrorResponse{
+			Error:   "PREMIERE_NOT_STARTED",
+			Message: "The premiere of this media has not started",
+		},
+		MediaID:  premiereErr.MediaID,
+		StartsAt: premiereErr.StartsAt,
+	})
+	return true
+}
+
+// serviceUnavailableRetryAfter is how many seconds clients are told to wait when
+// the database is unavailable or the processing queue is full
+const serviceUnavailableRetryAfter = "5"
+
+// respondInternalError writes a 503 response asking the client to retry if err
+// comes from an unavailable database or a full processing queue, a 504
+// response if the request ran out of time, and a 500 response otherwise
+func respondInternalError(c *gin.Context, message string, err error) {
+	if errors.Is(err, context.DeadlineExceeded) && c.Request.Context().Err() != nil {
+		c.JSON(http.StatusGatewayTimeout, ErrorResponse{
+			Error:   "TIMEOUT",
+			Message: "The request took too long, please retry later",
+			Details: message,
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrProcessingQueueFull) {
+		c.Header("Retry-After", serviceUnavailableRetryAfter)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "PROCESSING_QUEUE_FULL",
+			Message: "Too many uploads are waiting for processing, please retry later",
+			Details: message,
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrServiceUnavailable) {
+		c.Header("Retry-After", serviceUnavailableRetryAfter)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "SERVICE_UNAVAILABLE",
+			Message: "The service is temporarily unavailable, please retry later",
+			Details: message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "INTERNAL_ERROR",
+		Message: message,
+		Details: err.Error(),
+	})
 }
 
 // SuccessResponse represents a success response
@@ -312,8 +892,10 @@ type SuccessResponse struct {
 
 // MediaListResponse represents a paginated media list response
 type MediaListResponse struct {
-	Items  []*domain.Media `json:"items"`
-	Total  int64           `json:"total"`
-	Limit  int             `json:"limit"`
-	Offset int             `json:"offset"`
+	Items          []*domain.Media `json:"items"`
+	Total          int64           `json:"total"`
+	TotalEstimated bool            `json:"total_estimated,omitempty"` // total is approximate
+	Limit          int             `json:"limit"`
+	Offset         int             `json:"offset"`
+	NextCursor     string          `json:"next_cursor,omitempty"` // pass as cursor to get the next page
 }
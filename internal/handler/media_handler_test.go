@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaHandler_GetAllMedia_PublicListsOnlyReadyMedia(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Given
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	for _, media := range []*domain.Media{
+		{ID: "ready", Title: "Ready", Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+		{ID: "failed", Title: "Failed", Status: domain.StatusFailed, Visibility: domain.VisibilityPublic, FailureMessage: "ffmpeg exited with status 1"},
+		{ID: "uploading", Title: "Uploading", Status: domain.StatusUploading, Visibility: domain.VisibilityPublic},
+		{ID: "private", Title: "Private", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+	} {
+		require.NoError(t, mediaRepo.Create(context.Background(), media))
+	}
+	h := NewMediaHandler(service.NewMediaService(mediaRepo, service.MediaServiceDeps{}), domain.CountExact)
+
+	tests := []struct {
+		name     string
+		role     domain.Role
+		expected []string
+	}{
+		{name: "anonymous", expected: []string{"ready"}},
+		{name: "admin", role: domain.RoleAdmin, expected: []string{"ready", "failed", "uploading", "private"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/media", nil)
+			if tt.role != "" {
+				c.Set("role", tt.role)
+			}
+
+			// When
+			h.GetAllMedia(c)
+
+			// Then
+			require.Equal(t, http.StatusOK, w.Code)
+			var response MediaListResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			ids := make([]string, 0, len(response.Items))
+			for _, media := range response.Items {
+				ids = append(ids, media.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+			assert.EqualValues(t, len(tt.expected), response.Total)
+		})
+	}
+}
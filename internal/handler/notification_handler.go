@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles HTTP requests for notification preferences
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetPreferences godoc
+// @Summary List notification preferences
+// @Description List the notification preferences of a user or tenant
+// @Tags notifications
+// @Produce json
+// @Param subject_type query string true "Subject type (user, tenant)"
+// @Param subject_id query string true "Subject ID"
+// @Success 200 {object} NotificationPreferenceListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	subject := domain.NotificationSubject{
+		Type: c.Query("subject_type"),
+		ID:   c.Query("subject_id"),
+	}
+	if subject.Type == "" || subject.ID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "subject_type and subject_id are required",
+		})
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), subject)
+	if err != nil {
+		respondInternalError(c, "Failed to get notification preferences", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationPreferenceListResponse{
+		Items: prefs,
+	})
+}
+
+// CreatePreference godoc
+// @Summary Create notification preference
+// @Description Subscribe a user or tenant to lifecycle notifications on a channel
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body domain.NotificationPreferenceRequest true "Preference request"
+// @Success 201 {object} domain.NotificationPreference
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/notifications/preferences [post]
+func (h *NotificationHandler) CreatePreference(c *gin.Context) {
+	var req domain.NotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	pref, err := h.notificationService.CreatePreference(c.Request.Context(), &req)
+	if err != nil {
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to create notification preference", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, pref)
+}
+
+// DeletePreference godoc
+// @Summary Delete notification preference
+// @Description Remove a notification preference
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Preference ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/notifications/preferences/{id} [delete]
+func (h *NotificationHandler) DeletePreference(c *gin.Context) {
+	err := h.notificationService.DeletePreference(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == domain.ErrNotificationPreferenceNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "PREFERENCE_NOT_FOUND",
+				Message: "Notification preference not found",
+			})
+			return
+		}
+		respondInternalError(c, "Failed to delete notification preference", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Notification preference deleted successfully",
+	})
+}
+
+// ListWebhooks godoc
+// @Summary List webhook subscriptions
+// @Description List the webhook subscriptions of all users and tenants with their delivery stats
+// @Tags admin
+// @Produce json
+// @Success 200 {object} NotificationPreferenceListResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/webhooks [get]
+func (h *NotificationHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.notificationService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to list webhooks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationPreferenceListResponse{
+		Items: webhooks,
+	})
+}
+
+// NotificationPreferenceListResponse represents a list of notification preferences
+type NotificationPreferenceListResponse struct {
+	Items []*domain.NotificationPreference `json:"items"`
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cursorPrefix marks the position encoded in a pagination cursor
+const cursorPrefix = "offset:"
+
+// pageParams bounds the pagination of a list endpoint
+type pageParams struct {
+	defaultLimit int
+	maxLimit     int // larger limits are clamped
+	window       int // limit+offset may not exceed it, 0 for no bound
+}
+
+// page is the validated position and size of a requested page
+type page struct {
+	Limit  int
+	Offset int
+}
+
+// parseLimit reads the limit query parameter, clamped to maxLimit
+func parseLimit(c *gin.Context, defaultLimit, maxLimit int) (int, *domain.BusinessError) {
+	limitStr := c.Query("limit")
+	if limitStr == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		return 0, domain.NewBusinessErrorWithDetails("INVALID_LIMIT", "Limit must be a positive integer", limitStr)
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return limit, nil
+}
+
+// parsePage reads the limit and either the offset or the cursor query
+// parameters of a list request
+func parsePage(c *gin.Context, params pageParams) (page, *domain.BusinessError) {
+	limit, limitErr := parseLimit(c, params.defaultLimit, params.maxLimit)
+	if limitErr != nil {
+		return page{}, limitErr
+	}
+
+	offsetStr, cursor := c.Query("offset"), c.Query("cursor")
+	var offset int
+	var err error
+	switch {
+	case offsetStr != "" && cursor != "":
+		return page{}, domain.NewBusinessError("INVALID_CURSOR", "Use either offset or cursor")
+	case cursor != "":
+		if offset, err = decodeCursor(cursor); err != nil {
+			return page{}, domain.NewBusinessErrorWithDetails("INVALID_CURSOR", "Malformed pagination cursor", cursor)
+		}
+	case offsetStr != "":
+		if offset, err = strconv.Atoi(offsetStr); err != nil || offset < 0 {
+			return page{}, domain.NewBusinessErrorWithDetails("INVALID_OFFSET", "Offset must be a non-negative integer", offsetStr)
+		}
+	}
+
+	// Compared without adding the offset, which may be large enough to overflow
+	if params.window > 0 && offset > params.window-limit {
+		return page{}, domain.NewBusinessErrorWithDetails("INVALID_OFFSET",
+			fmt.Sprintf("Only the first %d results can be paged through", params.window), strconv.Itoa(offset))
+	}
+
+	return page{Limit: limit, Offset: offset}, nil
+}
+
+// respondInvalidPage writes the validation error of the pagination parameters
+func respondInvalidPage(c *gin.Context, err *domain.BusinessError) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   err.Code,
+		Message: err.Message,
+		Details: err.Details,
+	})
+}
+
+// nextCursor returns the cursor of the page after the returned items, empty on the last page
+func nextCursor(p page, returned int, total int64) string {
+	next := p.Offset + returned
+	if returned == 0 || int64(next) >= total {
+		return ""
+	}
+	return encodeCursor(next)
+}
+
+// encodeCursor builds the opaque cursor pointing at offset
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeCursor returns the offset a cursor points at
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	offsetStr, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, fmt.Errorf("unknown cursor format")
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor offset %q", offsetStr)
+	}
+	return offset, nil
+}
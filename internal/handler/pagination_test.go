@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"math"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePage(t *testing.T) {
+	params := pageParams{defaultLimit: 20, maxLimit: 100, window: 1000}
+
+	tests := []struct {
+		name      string
+		query     string
+		expected  page
+		errorCode string
+	}{
+		{name: "defaults", query: "", expected: page{Limit: 20}},
+		{name: "limit and offset", query: "limit=10&offset=30", expected: page{Limit: 10, Offset: 30}},
+		{name: "limit clamped", query: "limit=500", expected: page{Limit: 100}},
+		{name: "cursor", query: "limit=10&cursor=" + encodeCursor(40), expected: page{Limit: 10, Offset: 40}},
+		{name: "invalid limit", query: "limit=abc", errorCode: "INVALID_LIMIT"},
+		{name: "zero limit", query: "limit=0", errorCode: "INVALID_LIMIT"},
+		{name: "negative offset", query: "offset=-1", errorCode: "INVALID_OFFSET"},
+		{name: "beyond window", query: "limit=50&offset=990", errorCode: "INVALID_OFFSET"},
+		{name: "offset near max int", query: "limit=10&offset=" + strconv.Itoa(math.MaxInt-5), errorCode: "INVALID_OFFSET"},
+		{name: "cursor near max int", query: "limit=10&cursor=" + encodeCursor(math.MaxInt-5), errorCode: "INVALID_OFFSET"},
+		{name: "last page of window", query: "limit=50&offset=950", expected: page{Limit: 50, Offset: 950}},
+		{name: "malformed cursor", query: "cursor=not-a-cursor", errorCode: "INVALID_CURSOR"},
+		{name: "offset and cursor", query: "offset=10&cursor=" + encodeCursor(40), errorCode: "INVALID_CURSOR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/?"+tt.query, nil)
+
+			// When
+			p, err := parsePage(c, params)
+
+			// Then
+			if tt.errorCode != "" {
+				require.NotNil(t, err)
+				assert.Equal(t, tt.errorCode, err.Code)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, tt.expected, p)
+		})
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	p := page{Limit: 10, Offset: 20}
+
+	offset, err := decodeCursor(nextCursor(p, 10, 35))
+	require.NoError(t, err)
+	assert.Equal(t, 30, offset)
+	assert.Empty(t, nextCursor(p, 10, 30), "no cursor after the last page")
+	assert.Empty(t, nextCursor(p, 0, 35), "no cursor past the end")
+}
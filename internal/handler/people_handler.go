@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PeopleHandler handles HTTP requests for hosts, guests and their credits
+type PeopleHandler struct {
+	peopleService service.PeopleService
+}
+
+// NewPeopleHandler creates a new people handler
+func NewPeopleHandler(peopleService service.PeopleService) *PeopleHandler {
+	return &PeopleHandler{
+		peopleService: peopleService,
+	}
+}
+
+// CreatePerson godoc
+// @Summary Create person
+// @Description Add a host or guest that can be credited on media and shows
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param request body domain.PersonRequest true "Person request"
+// @Success 201 {object} domain.Person
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/people [post]
+func (h *PeopleHandler) CreatePerson(c *gin.Context) {
+	var req domain.PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	person, err := h.peopleService.CreatePerson(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create person")
+		return
+	}
+
+	c.JSON(http.StatusCreated, person)
+}
+
+// GetPerson godoc
+// @Summary Get person
+// @Description Get a host or guest by ID
+// @Tags people
+// @Produce json
+// @Param id path string true "Person ID"
+// @Success 200 {object} domain.Person
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/people/{id} [get]
+func (h *PeopleHandler) GetPerson(c *gin.Context) {
+	person, err := h.peopleService.GetPerson(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get person")
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// ListPeople godoc
+// @Summary List people
+// @Description List hosts and guests ordered by name
+// @Tags people
+// @Produce json
+// @Param limit query int false "Number of people to return" default(20)
+// @Param offset query int false "Number of people to skip"
+// @Param cursor query string false "Cursor from the previous page, instead of offset"
+// @Success 200 {object} domain.PeopleListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/people [get]
+func (h *PeopleHandler) ListPeople(c *gin.Context) {
+	p, pageErr := parsePage(c, pageParams{defaultLimit: domain.DefaultPageSize, maxLimit: domain.MaxPageSize})
+	if pageErr != nil {
+		respondInvalidPage(c, pageErr)
+		return
+	}
+
+	people, total, err := h.peopleService.ListPeople(c.Request.Context(), p.Limit, p.Offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to list people")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.PeopleListResponse{
+		Items:      people,
+		Total:      total,
+		NextCursor: nextCursor(p, len(people), total),
+	})
+}
+
+// UpdatePerson godoc
+// @Summary Update person
+// @Description Replace a host or guest. Indexed media picks the new name up when it is next indexed.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Param id path string true "Person ID"
+// @Param request body domain.PersonRequest true "Person request"
+// @Success 200 {object} domain.Person
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/people/{id} [put]
+func (h *PeopleHandler) UpdatePerson(c *gin.Context) {
+	var req domain.PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	person, err := h.peopleService.UpdatePerson(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update person")
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// DeletePerson godoc
+// @Summary Delete person
+// @Description Delete a host or guest with their credits on media and shows
+// @Tags people
+// @Produce json
+// @Param id path string true "Person ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/people/{id} [delete]
+func (h *PeopleHandler) DeletePerson(c *gin.Context) {
+	if err := h.peopleService.DeletePerson(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to delete person")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Person deleted successfully",
+	})
+}
+
+// GetAppearances godoc
+// @Summary Get appearances
+// @Description List the shows a person is credited on and the ready episodes they appear in, directly or through a show, most recent first
+// @Tags people
+// @Produce json
+// @Param id path string true "Person ID"
+// @Param limit query int false "Number of episodes to return" default(20)
+// @Param offset query int false "Number of episodes to skip"
+// @Param cursor query string false "Cursor from the previous page, instead of offset"
+// @Success 200 {object} domain.AppearancesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/people/{id}/appearances [get]
+func (h *PeopleHandler) GetAppearances(c *gin.Context) {
+	p, pageErr := parsePage(c, pageParams{defaultLimit: domain.DefaultPageSize, maxLimit: domain.MaxPageSize})
+	if pageErr != nil {
+		respondInvalidPage(c, pageErr)
+		return
+	}
+
+	appearances, err := h.peopleService.GetAppearances(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c), p.Limit, p.Offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to get appearances")
+		return
+	}
+	appearances.NextCursor = nextCursor(p, len(appearances.Items), appearances.Total)
+
+	c.JSON(http.StatusOK, appearances)
+}
+
+// GetMediaPeople godoc
+// @Summary Get media people
+// @Description List the hosts and guests credited on a media item, in order
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.CreditsResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/people [get]
+func (h *PeopleHandler) GetMediaPeople(c *gin.Context) {
+	credits, err := h.peopleService.GetMediaPeople(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get media people")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.CreditsResponse{
+		Items: credits,
+	})
+}
+
+// SetMediaPeople godoc
+// @Summary Set media people
+// @Description Replace the hosts and guests credited on a media item. An empty list removes every credit.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.CreditsRequest true "Credits request"
+// @Success 200 {object} domain.CreditsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/people [put]
+func (h *PeopleHandler) SetMediaPeople(c *gin.Context) {
+	var req domain.CreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	credits, err := h.peopleService.SetMediaPeople(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to set media people")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.CreditsResponse{
+		Items: credits,
+	})
+}
+
+// GetShowPeople godoc
+// @Summary Get show people
+// @Description List the hosts and guests credited on every episode of a show, in order
+// @Tags shows
+// @Produce json
+// @Param id path string true "Show ID"
+// @Success 200 {object} domain.CreditsResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/shows/{id}/people [get]
+func (h *PeopleHandler) GetShowPeople(c *gin.Context) {
+	credits, err := h.peopleService.GetShowPeople(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get show people")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.CreditsResponse{
+		Items: credits,
+	})
+}
+
+// SetShowPeople godoc
+// @Summary Set show people
+// @Description Replace the hosts and guests credited on every episode of a show. Episodes already in search pick them up when they are next indexed.
+// @Tags shows
+// @Accept json
+// @Produce json
+// @Param id path string true "Show ID"
+// @Param request body domain.CreditsRequest true "Credits request"
+// @Success 200 {object} domain.CreditsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/shows/{id}/people [put]
+func (h *PeopleHandler) SetShowPeople(c *gin.Context) {
+	var req domain.CreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	credits, err := h.peopleService.SetShowPeople(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to set show people")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.CreditsResponse{
+		Items: credits,
+	})
+}
+
+// handleError maps people service errors to HTTP responses
+func (h *PeopleHandler) handleError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrPersonNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "PERSON_NOT_FOUND",
+			Message: "Person not found",
+		})
+		return
+	case domain.ErrMediaNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
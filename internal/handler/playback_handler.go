@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlaybackHandler handles HTTP requests for media playback
+type PlaybackHandler struct {
+	playbackService service.PlaybackService
+}
+
+// NewPlaybackHandler creates a new playback handler
+func NewPlaybackHandler(playbackService service.PlaybackService) *PlaybackHandler {
+	return &PlaybackHandler{
+		playbackService: playbackService,
+	}
+}
+
+// GetPlayback godoc
+// @Summary Get playback URL
+// @Description Get the stream URL of a media item. DRM protected media also returns the key ID and the Widevine/FairPlay license acquisition URLs. Media premiering later is refused with PREMIERE_NOT_STARTED until its premiere starts.
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.PlaybackInfo
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} EntitlementRequiredResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} GeoRestrictedResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/playback [get]
+func (h *PlaybackHandler) GetPlayback(c *gin.Context) {
+	info, err := h.playbackService.GetPlayback(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		if err == domain.ErrFileNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "FILE_NOT_FOUND",
+				Message: "Media has no uploaded file",
+			})
+			return
+		}
+		if respondGeoRestricted(c, err) {
+			return
+		}
+		if respondNotEntitled(c, err) {
+			return
+		}
+		if respondPremiereNotStarted(c, err) {
+			return
+		}
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to get playback info", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// PlaybackURLsRequest represents a request for the playback of several media items
+type PlaybackURLsRequest struct {
+	MediaIDs []string `json:"media_ids" binding:"required"`
+}
+
+// PlaybackURLsResponse represents the playback of several media items
+type PlaybackURLsResponse struct {
+	Items []*domain.PlaybackResult `json:"items"`
+}
+
+// GetPlaybackURLs godoc
+// @Summary Get playback URLs in batch
+// @Description Get the playback info of up to 50 media items in one request, for playlists and queues. Items come back in the order of the request without duplicates; items that cannot be played carry an error code (MEDIA_NOT_FOUND, GEO_RESTRICTED, SUBSCRIPTION_REQUIRED, MEDIA_NOT_READY) instead of their playback info.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param request body PlaybackURLsRequest true "Media IDs"
+// @Success 200 {object} PlaybackURLsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/playback-urls [post]
+func (h *PlaybackHandler) GetPlaybackURLs(c *gin.Context) {
+	var req PlaybackURLsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	results, err := h.playbackService.GetPlaybackBatch(c.Request.Context(), req.MediaIDs, middleware.CurrentViewer(c))
+	if err != nil {
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to get playback info", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PlaybackURLsResponse{Items: results})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PremiereHandler handles HTTP requests for the premieres of media
+type PremiereHandler struct {
+	premiereService service.PremiereService
+}
+
+// NewPremiereHandler creates a new premiere handler
+func NewPremiereHandler(premiereService service.PremiereService) *PremiereHandler {
+	return &PremiereHandler{
+		premiereService: premiereService,
+	}
+}
+
+// SchedulePremiere godoc
+// @Summary Schedule a premiere
+// @Description Schedule or move the premiere of a video or podcast already uploaded. Viewers are refused playback with PREMIERE_NOT_STARTED until it starts, and a premiere.started event is published once it does.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.PremiereRequest true "Premiere request"
+// @Success 200 {object} domain.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/premiere [put]
+func (h *PremiereHandler) SchedulePremiere(c *gin.Context) {
+	var req domain.PremiereRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	media, err := h.premiereService.SchedulePremiere(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to schedule premiere")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// CancelPremiere godoc
+// @Summary Cancel a premiere
+// @Description Remove the premiere of a media item, opening its playback right away
+// @Tags media
+// @Param id path string true "Media ID"
+// @Success 204
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/premiere [delete]
+func (h *PremiereHandler) CancelPremiere(c *gin.Context) {
+	if err := h.premiereService.CancelPremiere(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to cancel premiere")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCountdown godoc
+// @Summary Count down to a premiere
+// @Description Get the start of the premiere of a media item and the seconds remaining until it, with the server time for clients to correct their clock
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.PremiereCountdown
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/premiere [get]
+func (h *PremiereHandler) GetCountdown(c *gin.Context) {
+	countdown, err := h.premiereService.GetCountdown(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get premiere countdown")
+		return
+	}
+
+	c.JSON(http.StatusOK, countdown)
+}
+
+// handleError maps premiere service errors to HTTP responses
+func (h *PremiereHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	case errors.Is(err, domain.ErrPremiereNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "PREMIERE_NOT_FOUND",
+			Message: "Media does not premiere",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProgressHandler handles HTTP requests for the playback progress of the signed in user
+type ProgressHandler struct {
+	progressService service.ProgressService
+}
+
+// NewProgressHandler creates a new playback progress handler
+func NewProgressHandler(progressService service.ProgressService) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+	}
+}
+
+// RecordProgress godoc
+// @Summary Report playback progress
+// @Description Store how far the signed in user got in a media item. Media played past 95% of its duration is marked completed.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param mediaId path string true "Media ID"
+// @Param request body domain.ProgressRequest true "Progress request"
+// @Success 200 {object} domain.PlaybackProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/progress/{mediaId} [put]
+func (h *ProgressHandler) RecordProgress(c *gin.Context) {
+	var req domain.ProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	progress, err := h.progressService.RecordProgress(c.Request.Context(), middleware.CurrentUserID(c), c.Param("mediaId"), &req, middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to record progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetContinueListening godoc
+// @Summary Continue listening
+// @Description List the media the signed in user started but did not finish, most recently played first
+// @Tags users
+// @Produce json
+// @Param limit query int false "Limit results (at most 50)" default(20)
+// @Success 200 {object} domain.ContinueListeningResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/me/continue [get]
+func (h *ProgressHandler) GetContinueListening(c *gin.Context) {
+	limit, limitErr := parseLimit(c, domain.DefaultContinueLimit, domain.MaxContinueLimit)
+	if limitErr != nil {
+		respondInvalidPage(c, limitErr)
+		return
+	}
+
+	items, err := h.progressService.GetContinueListening(c.Request.Context(), middleware.CurrentUserID(c), limit, middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get continue listening")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.ContinueListeningResponse{Items: items})
+}
+
+// handleError maps progress service errors to HTTP responses
+func (h *ProgressHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrMediaNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
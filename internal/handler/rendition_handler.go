@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service/transcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RenditionHandler handles HTTP requests for the transcoded renditions of media
+type RenditionHandler struct {
+	transcodeService transcode.Service
+}
+
+// NewRenditionHandler creates a new rendition handler
+func NewRenditionHandler(transcodeService transcode.Service) *RenditionHandler {
+	return &RenditionHandler{
+		transcodeService: transcodeService,
+	}
+}
+
+// GetRenditions godoc
+// @Summary List renditions
+// @Description List the renditions transcoded from a video, largest first, with the transcoding status of each. Renditions larger than the upload are reported as skipped.
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {array} domain.MediaRendition
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/renditions [get]
+func (h *RenditionHandler) GetRenditions(c *gin.Context) {
+	renditions, err := h.transcodeService.GetRenditions(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrMediaNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		respondInternalError(c, "Failed to get renditions", err)
+		return
+	}
+
+	if renditions == nil {
+		renditions = []*domain.MediaRendition{}
+	}
+	c.JSON(http.StatusOK, renditions)
+}
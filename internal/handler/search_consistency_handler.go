@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchConsistencyHandler handles HTTP requests comparing the search index with the database
+type SearchConsistencyHandler struct {
+	checker service.SearchConsistencyChecker
+}
+
+// NewSearchConsistencyHandler creates a new search consistency handler
+func NewSearchConsistencyHandler(checker service.SearchConsistencyChecker) *SearchConsistencyHandler {
+	return &SearchConsistencyHandler{
+		checker: checker,
+	}
+}
+
+// CheckConsistency godoc
+// @Summary Check search index consistency
+// @Description Report the searchable media missing from the search index, the documents built from an older version of their media, and the documents of media that is gone or no longer searchable. Admin only
+// @Tags search
+// @Produce json
+// @Success 200 {object} service.SearchConsistencyReport
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/search/consistency [get]
+func (h *SearchConsistencyHandler) CheckConsistency(c *gin.Context) {
+	h.check(c, false)
+}
+
+// HealConsistency godoc
+// @Summary Heal search index drift
+// @Description Check the search index like GET /search/consistency, then reindex missing and stale media and delete orphaned documents. Admin only
+// @Tags search
+// @Produce json
+// @Success 200 {object} service.SearchConsistencyReport
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/search/consistency/heal [post]
+func (h *SearchConsistencyHandler) HealConsistency(c *gin.Context) {
+	h.check(c, true)
+}
+
+// check runs a consistency check and responds with its report
+func (h *SearchConsistencyHandler) check(c *gin.Context, heal bool) {
+	report, err := h.checker.Check(c.Request.Context(), heal)
+	if err != nil {
+		respondInternalError(c, "Search consistency check failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
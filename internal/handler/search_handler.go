@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
 
 	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
 	"thamaniyah/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -28,10 +31,16 @@ func NewSearchHandler(searchService service.SearchService) *SearchHandler {
 // @Tags search
 // @Accept json
 // @Produce json
-// @Param query query string true "Search query"
+// @Param query query string true "Search query, with \"phrases\", tag:, type:, -exclusions and OR"
 // @Param type query string false "Media type (video, podcast)"
-// @Param limit query int false "Limit results" default(20)
-// @Param offset query int false "Offset results" default(0)
+// @Param show_id query string false "Only episodes of this show"
+// @Param audio_language query string false "Only media with original or dubbed audio in this language, like ar"
+// @Param limit query int false "Limit results (at most 100)" default(20)
+// @Param offset query int false "Offset results, offset+limit at most 10000" default(0)
+// @Param cursor query string false "Cursor of the page to get, from next_cursor (instead of offset)"
+// @Param safe query bool false "Exclude explicit content"
+// @Param fresh query bool false "Hydrate hits with metadata fetched from CMS now instead of the cached copy"
+// @Param collapse query string false "show to return the best episode of each show, with the next ones as inner_hits"
 // @Success 200 {object} domain.SearchResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -48,18 +57,13 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		return
 	}
 
-	// Parse limit and offset manually since ShouldBindQuery might not handle them correctly
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
-			req.Limit = limit
-		}
-	}
-
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
-			req.Offset = offset
-		}
+	// Parse limit and offset or cursor, the backend only pages through the first MaxSearchWindow hits
+	p, pageErr := parsePage(c, pageParams{defaultLimit: domain.DefaultSearchLimit, maxLimit: domain.MaxSearchLimit, window: domain.MaxSearchWindow})
+	if pageErr != nil {
+		respondInvalidPage(c, pageErr)
+		return
 	}
+	req.Limit, req.Offset = p.Limit, p.Offset
 
 	// Perform search
 	response, err := h.searchService.Search(c.Request.Context(), &req)
@@ -72,14 +76,11 @@ func (h *SearchHandler) Search(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Search failed",
-			Details: err.Error(),
-		})
+		respondInternalError(c, "Search failed", err)
 		return
 	}
 
+	response.NextCursor = nextCursor(p, len(response.Results), response.Total)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -90,7 +91,8 @@ func (h *SearchHandler) Search(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param query query string true "Partial search query"
-// @Param limit query int false "Limit suggestions" default(10)
+// @Param limit query int false "Limit suggestions (at most 50)" default(10)
+// @Param safe query bool false "Exclude explicit content"
 // @Success 200 {object} domain.SuggestResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -107,12 +109,13 @@ func (h *SearchHandler) Suggest(c *gin.Context) {
 		return
 	}
 
-	// Parse limit manually
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
-			req.Limit = limit
-		}
+	// Parse limit
+	limit, limitErr := parseLimit(c, domain.DefaultSuggestLimit, domain.MaxSuggestLimit)
+	if limitErr != nil {
+		respondInvalidPage(c, limitErr)
+		return
 	}
+	req.Limit = limit
 
 	// Get suggestions
 	response, err := h.searchService.Suggest(c.Request.Context(), &req)
@@ -125,11 +128,7 @@ func (h *SearchHandler) Suggest(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Suggestions failed",
-			Details: err.Error(),
-		})
+		respondInternalError(c, "Suggestions failed", err)
 		return
 	}
 
@@ -138,21 +137,30 @@ func (h *SearchHandler) Suggest(c *gin.Context) {
 
 // Reindex godoc
 // @Summary Reindex search data
-// @Description Rebuild the search index with latest data from CMS service
+// @Description Rebuild the search index with latest data from CMS service. Admin only; at most one reindex starts per cooldown window
 // @Tags search
 // @Accept json
 // @Produce json
 // @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/search/reindex [post]
 func (h *SearchHandler) Reindex(c *gin.Context) {
-	err := h.searchService.Reindex(c.Request.Context())
+	err := h.searchService.Reindex(c.Request.Context(), middleware.CurrentActor(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: "Reindex failed",
-			Details: err.Error(),
-		})
+		var cooldownErr *domain.CooldownError
+		if errors.As(err, &cooldownErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(cooldownErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, ErrorResponse{
+				Error:   "REINDEX_COOLDOWN",
+				Message: "A reindex was started recently, please retry later",
+				Details: err.Error(),
+			})
+			return
+		}
+		respondInternalError(c, "Reindex failed", err)
 		return
 	}
 
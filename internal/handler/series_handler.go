@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeriesHandler handles HTTP requests for the series of multi-part media
+type SeriesHandler struct {
+	seriesService service.SeriesService
+}
+
+// NewSeriesHandler creates a new series handler
+func NewSeriesHandler(seriesService service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		seriesService: seriesService,
+	}
+}
+
+// GetSeries godoc
+// @Summary Get series
+// @Description Place a media item within its series: its part, the previous and next parts and every part in order. Parts are detected from numbered titles like "Episode 12" unless set by an editor.
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.SeriesResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/series [get]
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	series, err := h.seriesService.GetSeries(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get series")
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// SetSeries godoc
+// @Summary Set series
+// @Description Link a media item to a part of a series, overriding the part detected from its title
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.SeriesRequest true "Series request"
+// @Success 200 {object} domain.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/series [put]
+func (h *SeriesHandler) SetSeries(c *gin.Context) {
+	var req domain.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	media, err := h.seriesService.SetSeries(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to set series")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// UnlinkSeries godoc
+// @Summary Unlink series
+// @Description Remove a media item from its series. Its title is no longer used to link it.
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.Media
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/series [delete]
+func (h *SeriesHandler) UnlinkSeries(c *gin.Context) {
+	media, err := h.seriesService.UnlinkSeries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to unlink series")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// DetectSeries godoc
+// @Summary Detect series
+// @Description Drop the series set by an editor and link a media item to the series numbered in its title again
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.Media
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/series/detect [post]
+func (h *SeriesHandler) DetectSeries(c *gin.Context) {
+	media, err := h.seriesService.DetectSeries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to detect series")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// handleError maps series service errors to HTTP responses
+func (h *SeriesHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrMediaNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
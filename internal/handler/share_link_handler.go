@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShareLinkHandler handles HTTP requests for media share links
+type ShareLinkHandler struct {
+	shareLinkService service.ShareLinkService
+}
+
+// NewShareLinkHandler creates a new share link handler
+func NewShareLinkHandler(shareLinkService service.ShareLinkService) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		shareLinkService: shareLinkService,
+	}
+}
+
+// CreateShareLink godoc
+// @Summary Create share link
+// @Description Create a revocable, time-limited link granting access to private or unlisted media. The token is only returned once.
+// @Tags share-links
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.ShareLinkRequest false "Share link request"
+// @Success 201 {object} domain.CreatedShareLink
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/share-links [post]
+func (h *ShareLinkHandler) CreateShareLink(c *gin.Context) {
+	var req domain.ShareLinkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "INVALID_REQUEST",
+				Message: "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	link, err := h.shareLinkService.CreateShareLink(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		if businessErr, ok := err.(*domain.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   businessErr.Code,
+				Message: businessErr.Message,
+				Details: businessErr.Details,
+			})
+			return
+		}
+		respondInternalError(c, "Failed to create share link", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// ListShareLinks godoc
+// @Summary List share links
+// @Description List the share links of a media item, including expired and revoked ones
+// @Tags share-links
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} ShareLinkListResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/share-links [get]
+func (h *ShareLinkHandler) ListShareLinks(c *gin.Context) {
+	links, err := h.shareLinkService.ListShareLinks(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		respondInternalError(c, "Failed to list share links", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ShareLinkListResponse{
+		Items: links,
+	})
+}
+
+// RevokeShareLink godoc
+// @Summary Revoke share link
+// @Description Revoke a share link so its token no longer grants access
+// @Tags share-links
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param linkId path string true "Share link ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/share-links/{linkId} [delete]
+func (h *ShareLinkHandler) RevokeShareLink(c *gin.Context) {
+	err := h.shareLinkService.RevokeShareLink(c.Request.Context(), c.Param("id"), c.Param("linkId"))
+	if err != nil {
+		if err == domain.ErrShareLinkNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "SHARE_LINK_NOT_FOUND",
+				Message: "Share link not found",
+			})
+			return
+		}
+		respondInternalError(c, "Failed to revoke share link", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Share link revoked successfully",
+	})
+}
+
+// ShareLinkListResponse represents a list of share links
+type ShareLinkListResponse struct {
+	Items []*domain.ShareLink `json:"items"`
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShowTemplateHandler handles HTTP requests for show metadata templates
+type ShowTemplateHandler struct {
+	templateService service.ShowTemplateService
+}
+
+// NewShowTemplateHandler creates a new show template handler
+func NewShowTemplateHandler(templateService service.ShowTemplateService) *ShowTemplateHandler {
+	return &ShowTemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// GetTemplate godoc
+// @Summary Get show template
+// @Description Get the default metadata applied to new episodes of a show
+// @Tags shows
+// @Produce json
+// @Param id path string true "Show ID"
+// @Success 200 {object} domain.ShowTemplate
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/shows/{id}/template [get]
+func (h *ShowTemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.templateService.GetTemplate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get show template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// SetTemplate godoc
+// @Summary Set show template
+// @Description Create or replace the labels, category, artwork and explicit flag applied to new episodes of a show. Upload requests can override them.
+// @Tags shows
+// @Accept json
+// @Produce json
+// @Param id path string true "Show ID"
+// @Param request body domain.ShowTemplateRequest true "Show template request"
+// @Success 200 {object} domain.ShowTemplate
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/shows/{id}/template [put]
+func (h *ShowTemplateHandler) SetTemplate(c *gin.Context) {
+	var req domain.ShowTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	template, err := h.templateService.SetTemplate(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to set show template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete show template
+// @Description Stop applying default metadata to new episodes of a show; existing episodes are unchanged
+// @Tags shows
+// @Produce json
+// @Param id path string true "Show ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/shows/{id}/template [delete]
+func (h *ShowTemplateHandler) DeleteTemplate(c *gin.Context) {
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to delete show template")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Show template deleted successfully",
+	})
+}
+
+// handleError maps show template service errors to HTTP responses
+func (h *ShowTemplateHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrShowTemplateNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "SHOW_TEMPLATE_NOT_FOUND",
+			Message: "Show template not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
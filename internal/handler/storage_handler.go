@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageHandler handles HTTP requests for storage maintenance
+type StorageHandler struct {
+	collector service.StorageCollector
+}
+
+// NewStorageHandler creates a new storage handler
+func NewStorageHandler(collector service.StorageCollector) *StorageHandler {
+	return &StorageHandler{
+		collector: collector,
+	}
+}
+
+// CollectGarbage godoc
+// @Summary Collect unreferenced stored objects
+// @Description Find the stored objects no media record references, like leftovers of purged media, and delete them. Objects of media in the trash and recently written objects are kept. Runs as a dry run reporting what would be deleted unless dry_run=false
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Only report the unreferenced objects" default(true)
+// @Success 200 {object} service.StorageCollectResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/storage/gc [post]
+func (h *StorageHandler) CollectGarbage(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "dry_run must be true or false",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.collector.Collect(c.Request.Context(), dryRun)
+	if err != nil {
+		respondInternalError(c, "Storage collection failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
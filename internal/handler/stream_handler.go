@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamHandler handles HTTP requests for adaptive streaming of media
+type StreamHandler struct {
+	streamService service.StreamService
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(streamService service.StreamService) *StreamHandler {
+	return &StreamHandler{
+		streamService: streamService,
+	}
+}
+
+// GetStream godoc
+// @Summary Get a streaming manifest
+// @Description Get the HLS or DASH manifest of a ready video, with short-lived signed URLs of its segments. Each segment URL stays valid for as long after the expiry of the manifest as the segment starts into the stream. Without a rendition, the HLS master playlist lists every rendition by a URI of this endpoint relative to it; DASH manifests list the progressive file of every rendition. Protected media only streams as DASH, from renditions encrypted with its content key that play with the license of its playback info; HLS requests are refused with DRM_REQUIRES_DASH.
+// @Tags media
+// @Produce application/vnd.apple.mpegurl
+// @Produce application/dash+xml
+// @Param id path string true "Media ID"
+// @Param format query string false "Manifest format: hls (default) or dash"
+// @Param rendition query string false "Name of a single rendition to stream, e.g. 720p"
+// @Success 200 {string} string "Manifest"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} EntitlementRequiredResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} GeoRestrictedResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/stream [get]
+func (h *StreamHandler) GetStream(c *gin.Context) {
+	format, err := domain.ParseStreamFormat(c.Query("format"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	manifest, err := h.streamService.GetManifest(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c), domain.StreamRequest{
+		Format:    format,
+		Rendition: c.Query("rendition"),
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Signed URLs expire, manifests are not reused
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Expires", manifest.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, manifest.Format.ContentType(), []byte(manifest.Body))
+}
+
+// GetDownloadURL godoc
+// @Summary Get a download URL
+// @Description Get a short-lived signed URL of the uploaded file of a ready media item: an S3 presigned URL, or a URL of the files endpoint signed by the service when files are stored locally. With a filename, the file is served as an attachment saved under that name, with the extension of the file added when missing. Protected media is refused with DRM_REQUIRED.
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param filename query string false "Name to save the file as"
+// @Success 200 {object} domain.DownloadURL
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} EntitlementRequiredResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} GeoRestrictedResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/download-url [get]
+func (h *StreamHandler) GetDownloadURL(c *gin.Context) {
+	download, err := h.streamService.GetDownloadURL(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c), c.Query("filename"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, download)
+}
+
+// ServeFile godoc
+// @Summary Download a file
+// @Description Serve a stored file, such as a stream segment or an uploaded file, at the download URL issued for it when files are stored locally
+// @Tags media
+// @Produce octet-stream
+// @Param key path string true "Storage key of the file"
+// @Param expires query int true "Expiry of the download URL"
+// @Param filename query string false "Name the file is saved as, when the URL was issued with one"
+// @Param signature query string true "Signature of the download URL"
+// @Success 200 {file} file
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /files/{key} [get]
+func (h *StreamHandler) ServeFile(c *gin.Context) {
+	// A malformed expiry fails verification like a wrong signature
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	filename := c.Query("filename")
+	asset, err := h.streamService.OpenFile(c.Request.Context(), strings.TrimPrefix(c.Param("key"), "/"), filename, expires, c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDownloadURL):
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "INVALID_DOWNLOAD_URL",
+				Message: "Download URL is invalid or expired",
+			})
+			return
+		case errors.Is(err, domain.ErrFileNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "FILE_NOT_FOUND",
+				Message: "File not found",
+			})
+			return
+		}
+		respondInternalError(c, "Failed to serve file", err)
+		return
+	}
+	defer asset.Body.Close()
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	if filename != "" {
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	// Local files can be seeked, which range requests of players need
+	if content, ok := asset.Body.(io.ReadSeeker); ok {
+		c.Header("Content-Type", asset.ContentType)
+		http.ServeContent(c.Writer, c.Request, asset.Filename, time.Time{}, content)
+		return
+	}
+	c.DataFromReader(http.StatusOK, asset.Size, asset.ContentType, asset.Body, nil)
+}
+
+// handleError maps stream service errors to HTTP responses
+func (h *StreamHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	case errors.Is(err, domain.ErrRenditionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "RENDITION_NOT_FOUND",
+			Message: "Media has no ready rendition of that name in this format",
+		})
+		return
+	case errors.Is(err, domain.ErrFileNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "FILE_NOT_FOUND",
+			Message: "Media has no uploaded file",
+		})
+		return
+	case errors.Is(err, domain.ErrStreamNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "STREAM_NOT_FOUND",
+			Message: "Media has no renditions to stream in this format",
+		})
+		return
+	}
+	if respondGeoRestricted(c, err) || respondNotEntitled(c, err) || respondPremiereNotStarted(c, err) {
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, "Failed to get stream", err)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler handles HTTP requests for media downloads and embedded tags
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// Download godoc
+// @Summary Download media file
+// @Description Download the uploaded file of a media item. Podcast MP3 files carry ID3 tags rewritten from the (possibly corrected) media metadata.
+// @Tags media
+// @Produce octet-stream
+// @Param id path string true "Media ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} EntitlementRequiredResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} GeoRestrictedResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/download [get]
+func (h *TagHandler) Download(c *gin.Context) {
+	asset, err := h.tagService.Download(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to download media")
+		return
+	}
+	defer asset.Body.Close()
+
+	c.DataFromReader(http.StatusOK, asset.Size, asset.ContentType, asset.Body, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, asset.Filename),
+	})
+}
+
+// GetArtwork godoc
+// @Summary Get episode art
+// @Description Get the artwork extracted from the tags of a media file
+// @Tags media
+// @Produce image/jpeg,image/png
+// @Param id path string true "Media ID"
+// @Success 200 {file} binary
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/artwork [get]
+func (h *TagHandler) GetArtwork(c *gin.Context) {
+	asset, err := h.tagService.GetArtwork(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to get artwork")
+		return
+	}
+	defer asset.Body.Close()
+
+	c.DataFromReader(http.StatusOK, asset.Size, asset.ContentType, asset.Body, map[string]string{
+		"Cache-Control": "private, max-age=3600",
+	})
+}
+
+// handleError maps tag service errors to HTTP responses
+func (h *TagHandler) handleError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrMediaNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	case domain.ErrArtworkNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "ARTWORK_NOT_FOUND",
+			Message: "Media has no artwork",
+		})
+		return
+	}
+	if respondGeoRestricted(c, err) {
+		return
+	}
+	if respondNotEntitled(c, err) {
+		return
+	}
+	if respondPremiereNotStarted(c, err) {
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
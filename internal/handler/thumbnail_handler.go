@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/middleware"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ThumbnailHandler handles HTTP requests for media thumbnails
+type ThumbnailHandler struct {
+	thumbnailService service.ThumbnailService
+}
+
+// NewThumbnailHandler creates a new thumbnail handler
+func NewThumbnailHandler(thumbnailService service.ThumbnailService) *ThumbnailHandler {
+	return &ThumbnailHandler{
+		thumbnailService: thumbnailService,
+	}
+}
+
+// GetThumbnail godoc
+// @Summary Get a media thumbnail
+// @Description Get a thumbnail of a media item resized for the client: a representative frame of videos, the episode art of podcasts. Sizes are rounded up to a multiple of 16 pixels; with both w and h the image fits within them, with one the other follows the aspect ratio. The first request for a size renders and caches it; later requests are served from the cache.
+// @Tags media
+// @Produce image/jpeg,image/png,image/webp
+// @Param id path string true "Media ID"
+// @Param w query int false "Width in pixels (at most 1920)"
+// @Param h query int false "Height in pixels (at most 1920)"
+// @Param format query string false "Image format (jpeg, png, webp)" default(jpeg)
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/thumbnail [get]
+func (h *ThumbnailHandler) GetThumbnail(c *gin.Context) {
+	spec, err := domain.ParseThumbnailSpec(c.Query("w"), c.Query("h"), c.Query("format"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	asset, err := h.thumbnailService.GetThumbnail(c.Request.Context(), c.Param("id"), middleware.CurrentViewer(c), spec)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	defer asset.Body.Close()
+
+	cacheStatus := "MISS"
+	if asset.Cached {
+		cacheStatus = "HIT"
+	}
+
+	c.DataFromReader(http.StatusOK, asset.Size, asset.ContentType, asset.Body, map[string]string{
+		"Cache-Control": "private, max-age=3600",
+		"X-Cache":       cacheStatus,
+	})
+}
+
+// SetPoster godoc
+// @Summary Set the poster frame
+// @Description Extract the frame of a processed video at a timecode and set it as the poster, replacing the frame picked automatically: thumbnails of every size are rendered from it from then on
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param at query string true "Timecode of the frame, HH:MM:SS, MM:SS or seconds, e.g. 00:01:23"
+// @Success 200 {object} domain.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/thumbnail [post]
+func (h *ThumbnailHandler) SetPoster(c *gin.Context) {
+	at, err := domain.ParseTimecode(c.Query("at"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	media, err := h.thumbnailService.SetPoster(c.Request.Context(), c.Param("id"), at)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// handleError maps thumbnail service errors to HTTP responses
+func (h *ThumbnailHandler) handleError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrMediaNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	case domain.ErrArtworkNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "ARTWORK_NOT_FOUND",
+			Message: "Media has no artwork",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, "Failed to get thumbnail", err)
+}
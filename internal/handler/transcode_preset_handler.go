@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TranscodePresetHandler handles HTTP requests for transcode presets
+type TranscodePresetHandler struct {
+	presetService service.TranscodePresetService
+}
+
+// NewTranscodePresetHandler creates a new transcode preset handler
+func NewTranscodePresetHandler(presetService service.TranscodePresetService) *TranscodePresetHandler {
+	return &TranscodePresetHandler{
+		presetService: presetService,
+	}
+}
+
+// CreatePreset godoc
+// @Summary Create transcode preset
+// @Description Create a rendition preset. Marking it as default replaces the previous default of its media type.
+// @Tags transcode-presets
+// @Accept json
+// @Produce json
+// @Param request body domain.TranscodePresetRequest true "Transcode preset request"
+// @Success 201 {object} domain.TranscodePreset
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transcode-presets [post]
+func (h *TranscodePresetHandler) CreatePreset(c *gin.Context) {
+	var req domain.TranscodePresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	preset, err := h.presetService.CreatePreset(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create transcode preset")
+		return
+	}
+
+	c.JSON(http.StatusCreated, preset)
+}
+
+// GetPreset godoc
+// @Summary Get transcode preset
+// @Description Get a transcode preset by ID
+// @Tags transcode-presets
+// @Produce json
+// @Param id path string true "Preset ID"
+// @Success 200 {object} domain.TranscodePreset
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transcode-presets/{id} [get]
+func (h *TranscodePresetHandler) GetPreset(c *gin.Context) {
+	preset, err := h.presetService.GetPreset(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get transcode preset")
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// ListPresets godoc
+// @Summary List transcode presets
+// @Description List all transcode presets
+// @Tags transcode-presets
+// @Produce json
+// @Success 200 {object} TranscodePresetListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transcode-presets [get]
+func (h *TranscodePresetHandler) ListPresets(c *gin.Context) {
+	presets, err := h.presetService.ListPresets(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to list transcode presets")
+		return
+	}
+
+	c.JSON(http.StatusOK, TranscodePresetListResponse{
+		Items: presets,
+	})
+}
+
+// UpdatePreset godoc
+// @Summary Update transcode preset
+// @Description Replace a transcode preset. Media already transcoded keeps its renditions.
+// @Tags transcode-presets
+// @Accept json
+// @Produce json
+// @Param id path string true "Preset ID"
+// @Param request body domain.TranscodePresetRequest true "Transcode preset request"
+// @Success 200 {object} domain.TranscodePreset
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transcode-presets/{id} [put]
+func (h *TranscodePresetHandler) UpdatePreset(c *gin.Context) {
+	var req domain.TranscodePresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	preset, err := h.presetService.UpdatePreset(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update transcode preset")
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// DeletePreset godoc
+// @Summary Delete transcode preset
+// @Description Delete a transcode preset. Media referencing it falls back to the default preset of its type.
+// @Tags transcode-presets
+// @Produce json
+// @Param id path string true "Preset ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transcode-presets/{id} [delete]
+func (h *TranscodePresetHandler) DeletePreset(c *gin.Context) {
+	if err := h.presetService.DeletePreset(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to delete transcode preset")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Transcode preset deleted successfully",
+	})
+}
+
+// PlanTranscode godoc
+// @Summary Get transcode plan
+// @Description Get the preset and watermark a media item is transcoded with
+// @Tags transcode-presets
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.TranscodePlan
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/transcode-plan [get]
+func (h *TranscodePresetHandler) PlanTranscode(c *gin.Context) {
+	plan, err := h.presetService.PlanTranscode(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == domain.ErrMediaNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "MEDIA_NOT_FOUND",
+				Message: "Media not found",
+			})
+			return
+		}
+		h.handleError(c, err, "Failed to plan transcode")
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// handleError maps transcode preset service errors to HTTP responses
+func (h *TranscodePresetHandler) handleError(c *gin.Context, err error, message string) {
+	if err == domain.ErrTranscodePresetNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "TRANSCODE_PRESET_NOT_FOUND",
+			Message: "Transcode preset not found",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// TranscodePresetListResponse represents a list of transcode presets
+type TranscodePresetListResponse struct {
+	Items []*domain.TranscodePreset `json:"items"`
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TranscriptHandler handles HTTP requests for the transcripts of media and the
+// labels and categories suggested from them
+type TranscriptHandler struct {
+	transcriptService service.TranscriptService
+}
+
+// NewTranscriptHandler creates a new transcript handler
+func NewTranscriptHandler(transcriptService service.TranscriptService) *TranscriptHandler {
+	return &TranscriptHandler{
+		transcriptService: transcriptService,
+	}
+}
+
+// SetTranscript godoc
+// @Summary Set transcript
+// @Description Replace the transcript of a media item, as plain text, WebVTT or SRT. Labels and categories are suggested from it with confidence scores, replacing the earlier pending suggestions; values dismissed or accepted before are not suggested again. When explicit language detection is enabled, media whose transcript has explicit words is flagged as explicit.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body domain.TranscriptRequest true "Transcript request"
+// @Success 200 {object} domain.Transcript
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/transcript [put]
+func (h *TranscriptHandler) SetTranscript(c *gin.Context) {
+	var req domain.TranscriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	transcript, err := h.transcriptService.SetTranscript(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to set transcript")
+		return
+	}
+
+	c.JSON(http.StatusOK, transcript)
+}
+
+// GetTranscript godoc
+// @Summary Get transcript
+// @Description Get the transcript of a media item
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.Transcript
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/transcript [get]
+func (h *TranscriptHandler) GetTranscript(c *gin.Context) {
+	transcript, err := h.transcriptService.GetTranscript(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get transcript")
+		return
+	}
+
+	c.JSON(http.StatusOK, transcript)
+}
+
+// GetSuggestions godoc
+// @Summary Get metadata suggestions
+// @Description List the labels and categories suggested for a media item from its transcript, pending or reviewed, the most confident first
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} MetadataSuggestionsResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/suggestions [get]
+func (h *TranscriptHandler) GetSuggestions(c *gin.Context) {
+	suggestions, err := h.transcriptService.GetSuggestions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, MetadataSuggestionsResponse{Items: suggestions})
+}
+
+// GetExplicitLanguage godoc
+// @Summary Get explicit language
+// @Description List the explicit words found in the transcript of a media item, in transcript order with an excerpt and, for captions, their start time, and whether the media is flagged as explicit
+// @Tags media
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} domain.ExplicitLanguageReport
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/media/{id}/explicit-language [get]
+func (h *TranscriptHandler) GetExplicitLanguage(c *gin.Context) {
+	report, err := h.transcriptService.GetExplicitLanguage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get explicit language")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListPendingSuggestions godoc
+// @Summary Suggestion review queue
+// @Description List the label and category suggestions of all media waiting for review, the most confident first
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Number of suggestions (default: 50, max: 200)"
+// @Success 200 {object} MetadataSuggestionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/suggestions [get]
+func (h *TranscriptHandler) ListPendingSuggestions(c *gin.Context) {
+	limit, limitErr := parseLimit(c, domain.DefaultSuggestionQueueLimit, domain.MaxSuggestionQueueLimit)
+	if limitErr != nil {
+		h.handleError(c, limitErr, "")
+		return
+	}
+
+	suggestions, err := h.transcriptService.ListPendingSuggestions(c.Request.Context(), limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to list suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, MetadataSuggestionsResponse{Items: suggestions})
+}
+
+// AcceptSuggestion godoc
+// @Summary Accept a metadata suggestion
+// @Description Add a suggested label to the labels of its media, or make a suggested category its category
+// @Tags admin
+// @Produce json
+// @Param id path string true "Suggestion ID"
+// @Success 200 {object} domain.MetadataSuggestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/suggestions/{id}/accept [post]
+func (h *TranscriptHandler) AcceptSuggestion(c *gin.Context) {
+	suggestion, err := h.transcriptService.AcceptSuggestion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to accept suggestion")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// DismissSuggestion godoc
+// @Summary Dismiss a metadata suggestion
+// @Description Close a suggestion without applying it; the value is not suggested for the media again
+// @Tags admin
+// @Produce json
+// @Param id path string true "Suggestion ID"
+// @Success 200 {object} domain.MetadataSuggestion
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/suggestions/{id}/dismiss [post]
+func (h *TranscriptHandler) DismissSuggestion(c *gin.Context) {
+	suggestion, err := h.transcriptService.DismissSuggestion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to dismiss suggestion")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// handleError maps transcript service errors to HTTP responses
+func (h *TranscriptHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "MEDIA_NOT_FOUND",
+			Message: "Media not found",
+		})
+		return
+	case errors.Is(err, domain.ErrTranscriptNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "TRANSCRIPT_NOT_FOUND",
+			Message: "Media has no transcript",
+		})
+		return
+	case errors.Is(err, domain.ErrMetadataSuggestionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "SUGGESTION_NOT_FOUND",
+			Message: "Suggestion not found",
+		})
+		return
+	case errors.Is(err, domain.ErrMetadataSuggestionResolved):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "SUGGESTION_RESOLVED",
+			Message: "Suggestion was already accepted or dismissed",
+		})
+		return
+	}
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// MetadataSuggestionsResponse represents a list of metadata suggestions
+type MetadataSuggestionsResponse struct {
+	Items []*domain.MetadataSuggestion `json:"items"`
+}
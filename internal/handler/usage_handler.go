@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageHandler handles HTTP requests for API usage metering
+type UsageHandler struct {
+	usageService service.UsageService
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(usageService service.UsageService) *UsageHandler {
+	return &UsageHandler{
+		usageService: usageService,
+	}
+}
+
+// GetUsage godoc
+// @Summary API usage report
+// @Description Report the requests, bytes received and sent and storage of a tenant or an API key by UTC day, for billing. Usage is rolled up from the live counters periodically, so the current day lags behind by up to the rollup interval
+// @Tags admin
+// @Produce json
+// @Param tenant query string false "Tenant ID, every tenant when omitted"
+// @Param api_key_id query string false "API key ID as listed in usage records, every key when omitted"
+// @Param from query string false "First day, YYYY-MM-DD (default: 30 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (default: today)"
+// @Success 200 {object} domain.UsageReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	report, err := h.usageService.GetUsage(c.Request.Context(), c.Query("tenant"), c.Query("api_key_id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get usage report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetTopUsage godoc
+// @Summary Top API consumers
+// @Description List the tenants and API keys with the most usage on a UTC day, for abuse detection
+// @Tags admin
+// @Produce json
+// @Param day query string false "Day, YYYY-MM-DD (default: today)"
+// @Param sort query string false "Counter to rank by: requests, bytes_in or bytes_out (default: requests)"
+// @Param limit query int false "Number of consumers (default: 10, max: 100)"
+// @Success 200 {object} UsageRecordListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/usage/top [get]
+func (h *UsageHandler) GetTopUsage(c *gin.Context) {
+	limit, limitErr := parseLimit(c, domain.DefaultTopUsageLimit, domain.MaxTopUsageLimit)
+	if limitErr != nil {
+		h.handleError(c, limitErr, "")
+		return
+	}
+
+	records, err := h.usageService.GetTopUsage(c.Request.Context(), c.Query("day"), domain.UsageSort(c.Query("sort")), limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to get top usage")
+		return
+	}
+
+	c.JSON(http.StatusOK, UsageRecordListResponse{Items: records})
+}
+
+// RollUpUsage godoc
+// @Summary Roll up API usage
+// @Description Save the live usage counters of a UTC day into the usage records now, instead of waiting for the periodic rollup
+// @Tags admin
+// @Produce json
+// @Param day query string false "Day, YYYY-MM-DD (default: today)"
+// @Success 200 {object} UsageRollupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/usage/rollup [post]
+func (h *UsageHandler) RollUpUsage(c *gin.Context) {
+	saved, err := h.usageService.RollUp(c.Request.Context(), c.Query("day"))
+	if err != nil {
+		h.handleError(c, err, "Failed to roll up usage")
+		return
+	}
+
+	c.JSON(http.StatusOK, UsageRollupResponse{Records: saved})
+}
+
+// handleError maps usage service errors to HTTP responses
+func (h *UsageHandler) handleError(c *gin.Context, err error, message string) {
+	if businessErr, ok := err.(*domain.BusinessError); ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   businessErr.Code,
+			Message: businessErr.Message,
+			Details: businessErr.Details,
+		})
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// UsageRecordListResponse represents a list of usage records
+type UsageRecordListResponse struct {
+	Items []domain.UsageRecord `json:"items"`
+}
+
+// UsageRollupResponse represents the result of a usage rollup
+type UsageRollupResponse struct {
+	Records int `json:"records"` // usage records saved
+}
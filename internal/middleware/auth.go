@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// roleContextKey is the gin context key holding the role of the request
+const roleContextKey = "role"
+
+// APIKeyResolver resolves managed API keys
+type APIKeyResolver interface {
+	ResolveAPIKey(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+// Authenticate returns a gin middleware granting the admin role to requests carrying
+// the admin API key, or the role of an active managed API key when resolver is not nil.
+// Other requests get the viewer role.
+func Authenticate(adminAPIKey string, resolver APIKeyResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := domain.RoleViewer
+		key := requestAPIKey(c)
+		if adminAPIKey != "" && key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(adminAPIKey)) == 1 {
+			role = domain.RoleAdmin
+		} else if key != "" && resolver != nil {
+			if apiKey, err := resolver.ResolveAPIKey(c.Request.Context(), key); err == nil {
+				role = apiKey.Role
+			}
+		}
+		c.Set(roleContextKey, role)
+
+		c.Next()
+	}
+}
+
+// requestAPIKey returns the API key of the request, from the X-API-Key header or a bearer token
+func requestAPIKey(c *gin.Context) string {
+	key := c.GetHeader("X-API-Key")
+	if key == "" {
+		key = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	return key
+}
+
+// RequireRole returns a gin middleware rejecting requests without the access of role
+func RequireRole(role domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !CurrentRole(c).Includes(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Error:   "FORBIDDEN",
+				Message: fmt.Sprintf("The %s role is required", role),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAdmin returns a gin middleware rejecting requests without admin access
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Error:   "FORBIDDEN",
+				Message: "Admin access required",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentRole returns the role of the current request
+func CurrentRole(c *gin.Context) domain.Role {
+	if role, ok := c.Get(roleContextKey); ok {
+		return role.(domain.Role)
+	}
+	return domain.RoleViewer
+}
+
+// IsAdmin reports whether the current request was authenticated as admin
+func IsAdmin(c *gin.Context) bool {
+	return CurrentRole(c) == domain.RoleAdmin
+}
+
+// CurrentViewer describes who is making the request
+func CurrentViewer(c *gin.Context) domain.Viewer {
+	return domain.Viewer{
+		IsAdmin:       IsAdmin(c),
+		Role:          CurrentRole(c),
+		SharedMediaID: c.GetString(sharedMediaContextKey),
+		Country:       CurrentCountry(c),
+		UserID:        CurrentUserID(c),
+		Tier:          CurrentTier(c),
+	}
+}
+
+// CurrentActor describes who is making the request for the audit trail
+func CurrentActor(c *gin.Context) domain.AuditActor {
+	name := "anonymous"
+	if IsAdmin(c) {
+		name = "admin"
+	}
+	return domain.AuditActor{Name: name, ClientIP: c.ClientIP()}
+}
+
+// sharedMediaContextKey is the gin context key holding the media granted by a share token
+const sharedMediaContextKey = "shared_media_id"
+
+// ShareTokenResolver resolves share tokens to the media they grant access to
+type ShareTokenResolver interface {
+	ResolveShareToken(ctx context.Context, token string) (*domain.ShareLink, error)
+}
+
+// ShareToken returns a gin middleware granting access to the media of a valid share token,
+// passed as the share_token query parameter or the X-Share-Token header.
+// Invalid tokens are ignored so the request is handled like an anonymous one.
+func ShareToken(resolver ShareTokenResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("share_token")
+		if token == "" {
+			token = c.GetHeader("X-Share-Token")
+		}
+
+		if token != "" {
+			if link, err := resolver.ResolveShareToken(c.Request.Context(), token); err == nil {
+				c.Set(sharedMediaContextKey, link.MediaID)
+			}
+		}
+
+		c.Next()
+	}
+}
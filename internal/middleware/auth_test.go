@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		role            domain.Role
+		guard           gin.HandlerFunc
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{name: "editor passes the editor role", role: domain.RoleEditor, guard: RequireRole(domain.RoleEditor), expectedStatus: http.StatusOK},
+		{name: "admin passes the editor role", role: domain.RoleAdmin, guard: RequireRole(domain.RoleEditor), expectedStatus: http.StatusOK},
+		{name: "viewer is forbidden the editor role", role: domain.RoleViewer, guard: RequireRole(domain.RoleEditor), expectedStatus: http.StatusForbidden, expectedMessage: "The editor role is required"},
+		{name: "admin passes admin access", role: domain.RoleAdmin, guard: RequireAdmin(), expectedStatus: http.StatusOK},
+		{name: "editor is forbidden admin access", role: domain.RoleEditor, guard: RequireAdmin(), expectedStatus: http.StatusForbidden, expectedMessage: "Admin access required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(roleContextKey, tt.role)
+				c.Next()
+			})
+			router.GET("/media", tt.guard, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			// When
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/media", nil))
+
+			// Then
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, ErrorResponse{Error: "FORBIDDEN", Message: tt.expectedMessage}, response)
+			}
+		})
+	}
+}
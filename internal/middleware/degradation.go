@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DegradedHeader tells clients the response was served from a cache while the database is unavailable
+const DegradedHeader = "X-Degraded"
+
+// Degradation returns a gin middleware recording the reads of a request served
+// from a cache because the database was unavailable, and flagging the response
+// with the X-Degraded header when there were any.
+func Degradation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, degradation := domain.ContextWithDegradation(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &degradedResponseWriter{ResponseWriter: c.Writer, degradation: degradation}
+
+		c.Next()
+	}
+}
+
+// degradedResponseWriter adds the X-Degraded header before the response is written
+type degradedResponseWriter struct {
+	gin.ResponseWriter
+	degradation *domain.Degradation
+}
+
+func (w *degradedResponseWriter) flagDegraded() {
+	if w.degradation.Degraded() && !w.Written() {
+		w.Header().Set(DegradedHeader, "cache")
+	}
+}
+
+func (w *degradedResponseWriter) WriteHeader(code int) {
+	w.flagDegraded()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *degradedResponseWriter) WriteHeaderNow() {
+	w.flagDegraded()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *degradedResponseWriter) Write(data []byte) (int, error) {
+	w.flagDegraded()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *degradedResponseWriter) WriteString(s string) (int, error) {
+	w.flagDegraded()
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadRecorder counts the downloads of media files
+type DownloadRecorder interface {
+	RecordDownload(ctx context.Context, mediaID string, client domain.DownloadClient) (bool, error)
+}
+
+// CountDownload returns a gin middleware counting the successful downloads of the
+// media in the id path parameter. Counting failures are logged, the file is served anyway.
+func CountDownload(recorder DownloadRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		if status != http.StatusOK && status != http.StatusPartialContent {
+			return
+		}
+
+		client := domain.DownloadClient{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			Range:     c.GetHeader("Range"),
+		}
+		if _, err := recorder.RecordDownload(c.Request.Context(), c.Param("id"), client); err != nil {
+			log.Printf("Failed to count download of media %s: %v", c.Param("id"), err)
+		}
+	}
+}
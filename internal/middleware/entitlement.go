@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tierContextKey is the gin context key holding the subscription tier of the signed in user
+const tierContextKey = "subscription_tier"
+
+// EntitlementProvider resolves the subscription tier of users
+type EntitlementProvider interface {
+	SubscriptionTier(ctx context.Context, userID string) (domain.SubscriptionTier, error)
+}
+
+// ResolveEntitlements returns a gin middleware recording the subscription tier of the
+// signed in user. Anonymous users have no tier, and users whose tier cannot be resolved
+// are treated as free so premium media is never served by mistake.
+func ResolveEntitlements(provider EntitlementProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID := CurrentUserID(c); userID != "" {
+			tier, err := provider.SubscriptionTier(c.Request.Context(), userID)
+			if err != nil {
+				log.Printf("Failed to resolve subscription of user %s: %v", userID, err)
+				tier = domain.SubscriptionFree
+			}
+			c.Set(tierContextKey, tier)
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentTier returns the subscription tier of the signed in user, empty when unknown
+func CurrentTier(c *gin.Context) domain.SubscriptionTier {
+	tier, _ := c.Get(tierContextKey)
+	value, _ := tier.(domain.SubscriptionTier)
+	return value
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// countryContextKey is the gin context key holding the viewer's country
+const countryContextKey = "country"
+
+// CountryLocator resolves client IP addresses to ISO 3166-1 alpha-2 country codes
+type CountryLocator interface {
+	Country(ip netip.Addr) string
+}
+
+// GeoLocate returns a gin middleware recording the country of the client.
+// A country set by a trusted CDN in countryHeader wins over the locator lookup;
+// either may be disabled by passing an empty header or a nil locator.
+func GeoLocate(locator CountryLocator, countryHeader string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var country string
+		if countryHeader != "" {
+			country = strings.ToUpper(strings.TrimSpace(c.GetHeader(countryHeader)))
+		}
+		if country == "" && locator != nil {
+			if ip, err := netip.ParseAddr(c.ClientIP()); err == nil {
+				country = locator.Country(ip)
+			}
+		}
+
+		if isCountryCode(country) {
+			c.Set(countryContextKey, country)
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentCountry returns the country of the client, or an empty string when unknown
+func CurrentCountry(c *gin.Context) string {
+	return c.GetString(countryContextKey)
+}
+
+// isCountryCode filters the placeholders CDNs send for unknown or anonymous
+// origins, like XX and T1, out of the country codes
+func isCountryCode(country string) bool {
+	if len(country) != 2 || country == "XX" {
+		return false
+	}
+	for _, r := range country {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
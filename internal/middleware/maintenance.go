@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+}
+
+// MaintenanceStore persists the maintenance mode shared by the replicas
+type MaintenanceStore interface {
+	Get(ctx context.Context) (*domain.MaintenanceStatus, error)
+	Save(ctx context.Context, status *domain.MaintenanceStatus) error
+}
+
+// MaintenanceMode is the switch of read-only maintenance mode. The mode is
+// kept in the store and cached; Run refreshes the cache so a switch made on
+// one replica reaches the others.
+type MaintenanceMode struct {
+	store MaintenanceStore
+
+	mu     sync.RWMutex
+	status domain.MaintenanceStatus
+}
+
+// NewMaintenanceMode creates a maintenance mode switch kept in store
+func NewMaintenanceMode(store MaintenanceStore) *MaintenanceMode {
+	return &MaintenanceMode{
+		store: store,
+	}
+}
+
+// Status returns the maintenance mode in effect
+func (m *MaintenanceMode) Status() domain.MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// ReadOnly reports whether writes are suspended
+func (m *MaintenanceMode) ReadOnly() bool {
+	return m.Status().ReadOnly
+}
+
+// SetReadOnly switches read-only mode on or off for every replica and returns
+// the new status. Switching it on again only updates the reason.
+func (m *MaintenanceMode) SetReadOnly(ctx context.Context, readOnly bool, reason string) (domain.MaintenanceStatus, error) {
+	current, err := m.store.Get(ctx)
+	if err != nil {
+		return domain.MaintenanceStatus{}, err
+	}
+
+	status := domain.MaintenanceStatus{}
+	if readOnly {
+		status = domain.MaintenanceStatus{ReadOnly: true, Reason: reason, Since: current.Since}
+		if !current.ReadOnly || status.Since == nil {
+			now := time.Now().UTC()
+			status.Since = &now
+		}
+	}
+	if err := m.store.Save(ctx, &status); err != nil {
+		return domain.MaintenanceStatus{}, err
+	}
+
+	m.set(status)
+	return status, nil
+}
+
+// Refresh reloads the maintenance mode from the store
+func (m *MaintenanceMode) Refresh(ctx context.Context) error {
+	status, err := m.store.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.set(*status)
+	return nil
+}
+
+// Run refreshes the maintenance mode every interval until ctx is cancelled
+func (m *MaintenanceMode) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Refresh(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to refresh maintenance mode: %v", err)
+			}
+		}
+	}
+}
+
+// set caches the status
+func (m *MaintenanceMode) set(status domain.MaintenanceStatus) {
+	status.ID = 0
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = status
+}
+
+// ReadOnly returns a gin middleware rejecting writes with a 503 and a
+// Retry-After header while mode is read-only. GET, HEAD and OPTIONS requests
+// are reads; so are the routes in allowed, like the one switching the mode off.
+func ReadOnly(mode *MaintenanceMode, retryAfter time.Duration, allowed ...string) gin.HandlerFunc {
+	allowedRoutes := make(map[string]bool, len(allowed))
+	for _, route := range allowed {
+		allowedRoutes[route] = true
+	}
+	retryAfterSeconds := strconv.Itoa(int(retryAfter.Seconds()))
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		status := mode.Status()
+		if !status.ReadOnly || allowedRoutes[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", retryAfterSeconds)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "READ_ONLY_MODE",
+			Message: "The service is in read-only maintenance mode, please retry later",
+			Details: status.Reason,
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryMaintenanceStore keeps the maintenance status in memory, shared like the database
+type memoryMaintenanceStore struct {
+	mu     sync.Mutex
+	status domain.MaintenanceStatus
+}
+
+func (s *memoryMaintenanceStore) Get(ctx context.Context) (*domain.MaintenanceStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	return &status, nil
+}
+
+func (s *memoryMaintenanceStore) Save(ctx context.Context, status *domain.MaintenanceStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = *status
+	return nil
+}
+
+func TestReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		readOnly       bool
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "writes pass outside maintenance", method: http.MethodPost, path: "/media", expectedStatus: http.StatusOK},
+		{name: "reads pass during maintenance", readOnly: true, method: http.MethodGet, path: "/media", expectedStatus: http.StatusOK},
+		{name: "writes are rejected during maintenance", readOnly: true, method: http.MethodPost, path: "/media", expectedStatus: http.StatusServiceUnavailable},
+		{name: "allowed routes pass during maintenance", readOnly: true, method: http.MethodPut, path: "/maintenance", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mode := NewMaintenanceMode(&memoryMaintenanceStore{})
+			_, err := mode.SetReadOnly(context.Background(), tt.readOnly, "database migration")
+			require.NoError(t, err)
+			router := gin.New()
+			router.Use(ReadOnly(mode, time.Minute, "/maintenance"))
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.GET("/media", ok)
+			router.POST("/media", ok)
+			router.PUT("/maintenance", ok)
+
+			// When
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			// Then
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, "60", w.Header().Get("Retry-After"))
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "READ_ONLY_MODE", response.Error)
+				assert.Equal(t, "database migration", response.Details)
+			}
+		})
+	}
+}
+
+func TestMaintenanceMode_SharedBetweenReplicas(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryMaintenanceStore{}
+	switched := NewMaintenanceMode(store)
+	replica := NewMaintenanceMode(store)
+
+	// Given read-only mode switched on one replica
+	status, err := switched.SetReadOnly(ctx, true, "reindex")
+	require.NoError(t, err)
+	require.NotNil(t, status.Since)
+
+	// When another replica refreshes
+	assert.False(t, replica.ReadOnly())
+	require.NoError(t, replica.Refresh(ctx))
+
+	// Then it is read-only too
+	assert.Equal(t, status, replica.Status())
+
+	// And switching it on again keeps the time it started
+	again, err := replica.SetReadOnly(ctx, true, "longer reindex")
+	require.NoError(t, err)
+	assert.Equal(t, status.Since, again.Since)
+	assert.Equal(t, "longer reindex", again.Reason)
+
+	off, err := replica.SetReadOnly(ctx, false, "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.MaintenanceStatus{}, off)
+}
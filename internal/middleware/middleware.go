@@ -1,40 +1,269 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"thamaniyah/pkg/errortracker"
+
 	"github.com/gin-gonic/gin"
 )
 
-// Logger returns a gin middleware for logging requests
-func Logger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	})
-}
-
-// Recovery returns a gin middleware for recovering from panics
-func Recovery() gin.HandlerFunc {
-	return gin.Recovery()
-}
-
-// CORS returns a gin middleware for handling CORS
-func CORS() gin.HandlerFunc {
+// LogSettings select the requests that are logged
+type LogSettings struct {
+	Level string // "warn" logs only 4xx and 5xx responses and "error" only 5xx responses
+	// Successful requests beyond SampleAfterPerSecond in a second are logged
+	// with probability SampleRate; 0 logs every request
+	SampleAfterPerSecond int
+	SampleRate           float64
+}
+
+// Logger returns a gin middleware writing a JSON entry per request to gin's
+// default writer. settings is consulted per request. Errors are always logged
+// and, under load, only a sample of the successful requests. Credentials in
+// headers and share tokens and signatures in URLs are redacted.
+func Logger(settings func() LogSettings) gin.HandlerFunc {
+	logger := slog.New(slog.NewJSONHandler(gin.DefaultWriter, nil))
+	sampler := &logSampler{}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		current := settings()
+		status := c.Writer.Status()
+		switch {
+		case current.Level == "warn" && status < http.StatusBadRequest,
+			current.Level == "error" && status < http.StatusInternalServerError:
+			return
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", redactedURL(c.Request.URL).String()),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+			slog.String("request_id", RequestID(c)),
+		}
+		if userID := CurrentUserID(c); userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		if IsAdmin(c) {
+			attrs = append(attrs, slog.Bool("admin", true))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		if level == slog.LevelInfo && current.SampleAfterPerSecond > 0 {
+			sampled, rate := sampler.sample(time.Now(), current.SampleAfterPerSecond, current.SampleRate)
+			if !sampled {
+				return
+			}
+			if rate < 1 {
+				attrs = append(attrs, slog.Float64("sample_rate", rate))
+			}
+		}
+		if current.Level == "debug" {
+			attrs = append(attrs, slog.Any("headers", redactedHeaders(c.Request.Header)))
+		}
+
+		logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// logSampler counts the successful requests of the current second
+type logSampler struct {
+	mu     sync.Mutex
+	second int64
+	count  int
+}
+
+// sample returns whether to log a successful request at now, and the share of
+// the requests like it that are logged
+func (s *logSampler) sample(now time.Time, afterPerSecond int, rate float64) (bool, float64) {
+	s.mu.Lock()
+	if second := now.Unix(); second != s.second {
+		s.second, s.count = second, 0
+	}
+	s.count++
+	count := s.count
+	s.mu.Unlock()
+
+	if count <= afterPerSecond {
+		return true, 1
+	}
+	return rand.Float64() < rate, rate
+}
+
+// ErrorReporter receives the panics and server errors of requests
+type ErrorReporter interface {
+	Report(event *errortracker.Event)
+}
+
+// Recovery returns a gin middleware for recovering from panics. With a reporter,
+// panics and 5xx responses are reported along with the request they happened in.
+func Recovery(reporter ErrorReporter) gin.HandlerFunc {
+	if reporter == nil {
+		return gin.Recovery()
+	}
+
+	return func(c *gin.Context) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				// A client hanging up mid-response is not a server error
+				if isBrokenPipe(rec) {
+					c.Abort()
+					return
+				}
+
+				log.Printf("Panic recovered: %v\n%s", rec, debug.Stack())
+				reporter.Report(&errortracker.Event{
+					Level:   errortracker.LevelFatal,
+					Type:    "panic",
+					Message: fmt.Sprint(rec),
+					Frames:  errortracker.Stack(1),
+					Request: requestInfo(c),
+					Tags:    map[string]string{"request_id": RequestID(c)},
+				})
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+
+		c.Next()
+
+		if status := c.Writer.Status(); status >= http.StatusInternalServerError {
+			message := fmt.Sprintf("%s %s responded %d", c.Request.Method, c.FullPath(), status)
+			if len(c.Errors) > 0 {
+				message += ": " + c.Errors.String()
+			}
+			reporter.Report(&errortracker.Event{
+				Level:   errortracker.LevelError,
+				Type:    "http_5xx",
+				Message: message,
+				Request: requestInfo(c),
+				Tags:    map[string]string{"status": strconv.Itoa(status), "request_id": RequestID(c)},
+			})
+		}
+	}
+}
+
+// sensitiveHeaders are left out of reported requests and redacted in request logs
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+	"X-Share-Token":       true,
+	GatewaySecretHeader:   true,
+}
+
+// requestInfo describes the request for error reports, without credentials
+func requestInfo(c *gin.Context) *errortracker.Request {
+	headers := make(map[string]string)
+	for name, values := range c.Request.Header {
+		if !sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+
+	return &errortracker.Request{
+		Method:   c.Request.Method,
+		URL:      redactedURL(c.Request.URL).String(),
+		Route:    c.FullPath(),
+		Headers:  headers,
+		ClientIP: c.ClientIP(),
+	}
+}
+
+// redactedHeaders returns the request headers with the values of credentials replaced
+func redactedHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = "[redacted]"
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// sensitiveQueryParams grant access on their own and are redacted from logged URLs
+var sensitiveQueryParams = []string{
+	"share_token", // share links
+	"signature",   // signed upload and download URLs
+}
+
+// redactedURL returns a copy of u without its share token or URL signature
+func redactedURL(u *url.URL) *url.URL {
+	redacted := *u
+	query := redacted.Query()
+	changed := false
+	for _, param := range sensitiveQueryParams {
+		if query.Has(param) {
+			query.Set(param, "[redacted]")
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	return &redacted
+}
+
+// isBrokenPipe reports whether a recovered panic comes from a closed client connection
+func isBrokenPipe(rec interface{}) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+	message := strings.ToLower(syscallErr.Error())
+	return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+}
+
+// CORS returns a gin middleware for handling CORS. origins is consulted per
+// request; "*" allows any origin, otherwise only listed origins are echoed back.
+func CORS(origins func() []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		if origin := allowedOrigin(origins(), c.GetHeader("Origin")); origin != "" {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		c.Writer.Header().Add("Vary", "Origin")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, traceparent")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", RequestIDHeader)
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -45,3 +274,17 @@ func CORS() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// allowedOrigin returns the Access-Control-Allow-Origin value for origin, or
+// an empty string when it is not allowed
+func allowedOrigin(allowed []string, origin string) string {
+	for _, candidate := range allowed {
+		if candidate == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(candidate, origin) {
+			return origin
+		}
+	}
+	return ""
+}
@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"thamaniyah/pkg/errortracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter keeps the reported events
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []*errortracker.Event
+}
+
+func (r *recordingReporter) Report(event *errortracker.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		handler        gin.HandlerFunc
+		expectedStatus int
+		expectedType   string
+		expectedLevel  errortracker.Level
+	}{
+		{
+			name:           "panic is reported and answered with 500",
+			handler:        func(c *gin.Context) { panic("nil map") },
+			expectedStatus: http.StatusInternalServerError,
+			expectedType:   "panic",
+			expectedLevel:  errortracker.LevelFatal,
+		},
+		{
+			name:           "server error is reported",
+			handler:        func(c *gin.Context) { c.Status(http.StatusBadGateway) },
+			expectedStatus: http.StatusBadGateway,
+			expectedType:   "http_5xx",
+			expectedLevel:  errortracker.LevelError,
+		},
+		{
+			name:           "client error is not reported",
+			handler:        func(c *gin.Context) { c.Status(http.StatusNotFound) },
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			reporter := &recordingReporter{}
+			router := gin.New()
+			router.Use(Recovery(reporter))
+			router.GET("/media/:id", tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/media/1?share_token=secret&lang=ar", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			req.Header.Set("X-API-Key", "secret")
+			req.Header.Set("Accept", "application/json")
+
+			// When
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedType == "" {
+				assert.Empty(t, reporter.events)
+				return
+			}
+			require.Len(t, reporter.events, 1)
+			event := reporter.events[0]
+			assert.Equal(t, tt.expectedType, event.Type)
+			assert.Equal(t, tt.expectedLevel, event.Level)
+			assert.Equal(t, "/media/:id", event.Request.Route)
+			assert.NotContains(t, event.Request.URL, "secret")
+			assert.Equal(t, map[string]string{"Accept": "application/json"}, event.Request.Headers)
+			if tt.expectedType == "panic" {
+				assert.Equal(t, "nil map", event.Message)
+				assert.NotEmpty(t, event.Frames)
+			}
+		})
+	}
+}
+
+func TestRecovery_WithoutReporter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recovery(nil))
+	router.GET("/", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+// logEntries runs requests through Logger and returns the JSON entries it wrote
+func logEntries(t *testing.T, settings LogSettings, status int, requests ...*http.Request) []map[string]interface{} {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &buf
+	t.Cleanup(func() { gin.DefaultWriter = defaultWriter })
+
+	router := gin.New()
+	router.Use(Logger(func() LogSettings { return settings }))
+	router.GET("/media/:id", func(c *gin.Context) { c.Status(status) })
+
+	for _, req := range requests {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger_Levels(t *testing.T) {
+	tests := []struct {
+		level    string
+		status   int
+		expected bool
+	}{
+		{"info", http.StatusOK, true},
+		{"warn", http.StatusOK, false},
+		{"warn", http.StatusNotFound, true},
+		{"error", http.StatusNotFound, false},
+		{"error", http.StatusServiceUnavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level+"/"+http.StatusText(tt.status), func(t *testing.T) {
+			entries := logEntries(t, LogSettings{Level: tt.level}, tt.status,
+				httptest.NewRequest(http.MethodGet, "/media/1", nil))
+
+			if !tt.expected {
+				assert.Empty(t, entries)
+				return
+			}
+			require.Len(t, entries, 1)
+			assert.Equal(t, "/media/:id", entries[0]["route"])
+			assert.Equal(t, float64(tt.status), entries[0]["status"])
+		})
+	}
+}
+
+func TestLogger_Redaction(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/media/1?share_token=secret&signature=secret&lang=ar", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("Accept", "application/json")
+
+	entries := logEntries(t, LogSettings{Level: "debug"}, http.StatusOK, req)
+
+	require.Len(t, entries, 1)
+	path, _ := entries[0]["path"].(string)
+	assert.NotContains(t, path, "secret")
+	assert.Contains(t, path, "lang=ar")
+	assert.Equal(t, map[string]interface{}{
+		"Authorization": "[redacted]",
+		"Cookie":        "[redacted]",
+		"Accept":        "application/json",
+	}, entries[0]["headers"])
+}
+
+func TestLogger_Sampling(t *testing.T) {
+	requests := make([]*http.Request, 20)
+	for i := range requests {
+		requests[i] = httptest.NewRequest(http.MethodGet, "/media/1", nil)
+	}
+
+	t.Run("successful requests beyond the threshold are dropped", func(t *testing.T) {
+		entries := logEntries(t, LogSettings{Level: "info", SampleAfterPerSecond: 5, SampleRate: 0}, http.StatusOK, requests...)
+
+		// A second boundary during the run lets a few more through
+		assert.GreaterOrEqual(t, len(entries), 5)
+		assert.Less(t, len(entries), len(requests))
+		for _, entry := range entries {
+			assert.NotContains(t, entry, "sample_rate")
+		}
+	})
+
+	t.Run("errors are never sampled", func(t *testing.T) {
+		entries := logEntries(t, LogSettings{Level: "info", SampleAfterPerSecond: 5, SampleRate: 0}, http.StatusInternalServerError, requests...)
+
+		assert.Len(t, entries, len(requests))
+	})
+}
+
+func TestLogSampler(t *testing.T) {
+	sampler := &logSampler{}
+	now := time.Unix(1700000000, 0)
+
+	for i := 0; i < 3; i++ {
+		sampled, rate := sampler.sample(now, 3, 0)
+		assert.True(t, sampled)
+		assert.Equal(t, 1.0, rate)
+	}
+
+	sampled, rate := sampler.sample(now, 3, 0)
+	assert.False(t, sampled)
+	assert.Equal(t, 0.0, rate)
+
+	sampled, rate = sampler.sample(now.Add(500*time.Millisecond), 3, 1)
+	assert.True(t, sampled)
+	assert.Equal(t, 1.0, rate)
+
+	// A new second starts counting again
+	sampled, rate = sampler.sample(now.Add(time.Second), 3, 0)
+	assert.True(t, sampled)
+	assert.Equal(t, 1.0, rate)
+}
+
+func TestRedactedURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"/media/1", "/media/1"},
+		{"/media/1?lang=ar", "/media/1?lang=ar"},
+		{"/media/1?share_token=secret", "/media/1?share_token=%5Bredacted%5D"},
+		{"/files/a.mp3?expires=1700000000&signature=secret", "/files/a.mp3?expires=1700000000&signature=%5Bredacted%5D"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			u, err := url.Parse(tt.raw)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, redactedURL(u).String())
+			assert.Equal(t, tt.raw, u.String()) // the request URL is left alone
+		})
+	}
+}
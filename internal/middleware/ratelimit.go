@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitWindow is the fixed window requests are counted in
+const rateLimitWindow = time.Minute
+
+// RateLimit returns a gin middleware that limits the requests of each client IP
+// per minute. limit is consulted per request so it can change at runtime; a
+// limit of 0 disables rate limiting. Admin requests are never limited.
+func RateLimit(limit func() int) gin.HandlerFunc {
+	limiter := &rateLimiter{counts: make(map[string]int)}
+
+	return func(c *gin.Context) {
+		perMinute := limit()
+		if perMinute <= 0 || IsAdmin(c) {
+			c.Next()
+			return
+		}
+
+		allowed, retryAfter := limiter.allow(c.ClientIP(), perMinute, time.Now())
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "RATE_LIMITED",
+				"message": "Too many requests, please retry later",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimiter counts requests per key in fixed windows
+type rateLimiter struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+// allow records a request for key and reports whether it is within perMinute,
+// along with the time until the window resets
+func (l *rateLimiter) allow(key string, perMinute int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.windowStart) >= rateLimitWindow {
+		l.windowStart = now.Truncate(rateLimitWindow)
+		l.counts = make(map[string]int)
+	}
+
+	l.counts[key]++
+	return l.counts[key] <= perMinute, l.windowStart.Add(rateLimitWindow).Sub(now)
+}
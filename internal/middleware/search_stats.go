@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchRecorder counts the searches of tenants
+type SearchRecorder interface {
+	RecordSearch(ctx context.Context, tenantID, query string) error
+}
+
+// CountSearches returns a gin middleware counting the query parameter of
+// successful searches for the tenant in tenantHeader. Counting failures are
+// logged, the search is served anyway.
+func CountSearches(recorder SearchRecorder, tenantHeader string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		query := c.Query("query")
+		if c.Writer.Status() != http.StatusOK || query == "" {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), usageRecordTimeout)
+		defer cancel()
+		tenantID := requestTenantID(c, tenantHeader)
+		if err := recorder.RecordSearch(ctx, tenantID, query); err != nil {
+			log.Printf("Failed to count search of tenant %s: %v", tenantID, err)
+		}
+	}
+}
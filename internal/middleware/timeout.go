@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"thamaniyah/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteTimeouts are the deadlines of requests
+type RouteTimeouts struct {
+	Read  time.Duration // GET and HEAD requests
+	Write time.Duration // every other request
+	// Routes overrides the deadline of routes keyed by RouteKey, 0 disabling it,
+	// like for downloads and event streams
+	Routes map[string]time.Duration
+}
+
+// RouteKey returns the key of a route in RouteTimeouts.Routes, like "POST /api/v1/search/reindex"
+func RouteKey(method, path string) string {
+	return method + " " + path
+}
+
+// Timeout returns a gin middleware giving every request a deadline. Database
+// and search calls of the request are cancelled once it passes, and a 504 is
+// returned if the handler has not responded yet.
+func Timeout(timeouts RouteTimeouts) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := timeouts.Routes[RouteKey(c.Request.Method, c.FullPath())]
+		if !ok {
+			timeout = timeouts.Write
+			if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+				timeout = timeouts.Read
+			}
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, ErrorResponse{
+				Error:   "TIMEOUT",
+				Message: "The request took too long, please retry later",
+			})
+		}
+	}
+}
+
+// SlowRequests returns a gin middleware logging and counting the requests
+// taking longer than threshold, by route. A threshold of 0 disables it.
+func SlowRequests(threshold time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveSlow(c.Request.Context(), metrics.KindHTTP, RouteKey(c.Request.Method, route), time.Since(start), threshold)
+	}
+}
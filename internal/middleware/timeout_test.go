@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"thamaniyah/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// waitForDeadline blocks like a slow database call until the request deadline passes
+	waitForDeadline := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	}
+	respond := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	timeouts := RouteTimeouts{
+		Read:  20 * time.Millisecond,
+		Write: time.Second,
+		Routes: map[string]time.Duration{
+			RouteKey(http.MethodGet, "/download"): 0,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		handler        gin.HandlerFunc
+		expectedStatus int
+	}{
+		{"slow read times out", http.MethodGet, "/media", waitForDeadline, http.StatusGatewayTimeout},
+		{"fast read responds", http.MethodGet, "/media", respond, http.StatusOK},
+		{"write uses the write deadline", http.MethodPost, "/media", func(c *gin.Context) {
+			deadline, ok := c.Request.Context().Deadline()
+			require.True(t, ok)
+			assert.Greater(t, time.Until(deadline), 500*time.Millisecond)
+			c.Status(http.StatusCreated)
+		}, http.StatusCreated},
+		{"route override disables the deadline", http.MethodGet, "/download", func(c *gin.Context) {
+			_, ok := c.Request.Context().Deadline()
+			assert.False(t, ok)
+			c.Status(http.StatusOK)
+		}, http.StatusOK},
+		{"response written before the deadline is kept", http.MethodGet, "/media", func(c *gin.Context) {
+			c.Status(http.StatusAccepted)
+			c.Writer.WriteHeaderNow()
+			<-c.Request.Context().Done()
+		}, http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(Timeout(timeouts))
+			router.Handle(tt.method, tt.path, tt.handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusGatewayTimeout {
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "TIMEOUT", response.Error)
+			}
+		})
+	}
+}
+
+func TestSlowRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	slowFingerprints := expvar.Get("slow_fingerprints").(*expvar.Map)
+	count := func(route string) int64 {
+		if value, ok := slowFingerprints.Get(metrics.KindHTTP + " " + route).(*expvar.Int); ok {
+			return value.Value()
+		}
+		return 0
+	}
+
+	router := gin.New()
+	router.Use(SlowRequests(10 * time.Millisecond))
+	router.GET("/slow/:id", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	slowBefore, fastBefore := count("GET /slow/:id"), count("GET /fast/:id")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow/2", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast/1", nil))
+
+	// Slow requests are counted by route, not by URL
+	assert.Equal(t, slowBefore+2, count("GET /slow/:id"))
+	assert.Equal(t, fastBefore, count("GET /fast/:id"))
+}
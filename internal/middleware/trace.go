@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"unicode"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the request ID, set by the caller or generated
+	RequestIDHeader = "X-Request-ID"
+	// traceParentHeader carries the W3C trace context
+	traceParentHeader = "traceparent"
+
+	requestIDContextKey = "request_id"
+	maxRequestIDLength  = 128
+)
+
+// Trace returns a gin middleware giving every request a request ID and trace
+// context. A well-formed X-Request-ID or traceparent from the caller is kept.
+// The trace context is added to the request context so the events and jobs it
+// causes can be correlated with the request.
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		trace := &domain.TraceContext{
+			RequestID:   requestID,
+			TraceParent: domain.ContinueTraceParent(c.GetHeader(traceParentHeader)),
+		}
+		c.Set(requestIDContextKey, requestID)
+		c.Request = c.Request.WithContext(domain.ContextWithTrace(c.Request.Context(), trace))
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// RequestID returns the ID of the request, or an empty string outside of Trace
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDContextKey)
+}
+
+// isValidRequestID accepts short printable IDs, keeping caller input out of logs otherwise
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) || r == ' ' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// usageRecordTimeout bounds counting a request, which happens after the response
+const usageRecordTimeout = 2 * time.Second
+
+// UsageRecorder counts API usage per tenant and API key
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, sample domain.UsageSample) error
+}
+
+// MeterUsage returns a gin middleware counting every request, with the bytes
+// it sent and received, for the tenant in tenantHeader and the API key of the
+// request. Requests without a tenant count for the default tenant. Counting
+// failures are logged, the request is served anyway.
+func MeterUsage(recorder UsageRecorder, tenantHeader string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		sample := domain.UsageSample{
+			TenantID: requestTenantID(c, tenantHeader),
+			APIKeyID: domain.APIKeyID(requestAPIKey(c)),
+			BytesIn:  c.Request.ContentLength,
+			BytesOut: int64(c.Writer.Size()),
+			At:       time.Now(),
+		}
+
+		// Streams end when the client goes away, their usage still counts
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), usageRecordTimeout)
+		defer cancel()
+		if err := recorder.RecordUsage(ctx, sample); err != nil {
+			log.Printf("Failed to meter usage of tenant %s: %v", sample.TenantID, err)
+		}
+	}
+}
+
+// requestTenantID returns the tenant of the request from tenantHeader, the default tenant when missing
+func requestTenantID(c *gin.Context, tenantHeader string) string {
+	if tenantHeader != "" {
+		if tenantID := strings.TrimSpace(c.GetHeader(tenantHeader)); tenantID != "" {
+			return tenantID
+		}
+	}
+	return domain.DefaultTenantID
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userContextKey is the gin context key holding the ID of the signed in user
+const userContextKey = "user_id"
+
+// GatewaySecretHeader carries the secret shared with the API gateway, proving a
+// request came through it
+const GatewaySecretHeader = "X-Gateway-Secret"
+
+// IdentifyUser returns a gin middleware recording the user signed in at the API gateway,
+// which passes the ID of the user it authenticated in userHeader and their role in
+// roleHeader. These headers are only trusted on requests carrying gatewaySecret in
+// GatewaySecretHeader, as clients reaching the service directly can set them too, and
+// users are not identified when userHeader or gatewaySecret is empty. The role of the
+// user applies when it grants more access than the API key of the request, up to
+// editor: only API keys grant admin.
+func IdentifyUser(userHeader, roleHeader, gatewaySecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userHeader != "" && gatewaySecret != "" && fromGateway(c, gatewaySecret) {
+			if userID := strings.TrimSpace(c.GetHeader(userHeader)); userID != "" {
+				c.Set(userContextKey, userID)
+
+				if roleHeader != "" {
+					role := domain.Role(strings.ToLower(strings.TrimSpace(c.GetHeader(roleHeader))))
+					if role.IsValid() && role != domain.RoleAdmin && !CurrentRole(c).Includes(role) {
+						c.Set(roleContextKey, role)
+					}
+				}
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// fromGateway reports whether the request carries the secret shared with the API gateway
+func fromGateway(c *gin.Context, secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(c.GetHeader(GatewaySecretHeader)), []byte(secret)) == 1
+}
+
+// RequireUser returns a gin middleware rejecting requests without a signed in user
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if CurrentUserID(c) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "UNAUTHORIZED",
+				"message": "Sign in required",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentUserID returns the ID of the signed in user, or an empty string for anonymous requests
+func CurrentUserID(c *gin.Context) string {
+	return c.GetString(userContextKey)
+}
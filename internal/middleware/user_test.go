@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifyUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		gatewaySecret string
+		headers       map[string]string
+		expectedUser  string
+		expectedRole  domain.Role
+	}{
+		{
+			name:          "gateway request",
+			gatewaySecret: "s3cret",
+			headers:       map[string]string{GatewaySecretHeader: "s3cret", "X-User-ID": "user-1", "X-User-Role": "editor"},
+			expectedUser:  "user-1",
+			expectedRole:  domain.RoleEditor,
+		},
+		{
+			name:          "gateway headers never grant admin",
+			gatewaySecret: "s3cret",
+			headers:       map[string]string{GatewaySecretHeader: "s3cret", "X-User-ID": "user-1", "X-User-Role": "admin"},
+			expectedUser:  "user-1",
+			expectedRole:  domain.RoleViewer,
+		},
+		{
+			name:          "request bypassing the gateway",
+			gatewaySecret: "s3cret",
+			headers:       map[string]string{"X-User-ID": "user-1", "X-User-Role": "editor"},
+			expectedRole:  domain.RoleViewer,
+		},
+		{
+			name:          "wrong secret",
+			gatewaySecret: "s3cret",
+			headers:       map[string]string{GatewaySecretHeader: "guess", "X-User-ID": "user-1", "X-User-Role": "editor"},
+			expectedRole:  domain.RoleViewer,
+		},
+		{
+			name:         "no gateway configured",
+			headers:      map[string]string{GatewaySecretHeader: "", "X-User-ID": "user-1", "X-User-Role": "editor"},
+			expectedRole: domain.RoleViewer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			var user string
+			var role domain.Role
+			router := gin.New()
+			router.Use(Authenticate("", nil), IdentifyUser("X-User-ID", "X-User-Role", tt.gatewaySecret))
+			router.GET("/", func(c *gin.Context) {
+				user, role = CurrentUserID(c), CurrentRole(c)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			// When
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Then
+			assert.Equal(t, tt.expectedUser, user)
+			assert.Equal(t, tt.expectedRole, role)
+		})
+	}
+}
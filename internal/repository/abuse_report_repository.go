@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// AbuseReportRepository defines the contract for abuse report data access
+type AbuseReportRepository interface {
+	// Create creates a new abuse report
+	Create(ctx context.Context, report *domain.AbuseReport) error
+
+	// GetOpen retrieves the open report of a media item by a reporter
+	GetOpen(ctx context.Context, mediaID, reporterHash string) (*domain.AbuseReport, error)
+
+	// Update saves the reason, details and repeats of a report
+	Update(ctx context.Context, report *domain.AbuseReport) error
+
+	// CountSince counts the reports of a reporter filed or repeated since a time
+	CountSince(ctx context.Context, reporterHash string, since time.Time) (int64, error)
+
+	// Queue retrieves the open reports folded per media item, the most reported first
+	Queue(ctx context.Context, limit int) ([]domain.ModerationQueueItem, error)
+
+	// Resolve closes the open reports of a media item with a decision, returning how many were
+	Resolve(ctx context.Context, mediaID string, decision domain.AbuseReportStatus, resolvedAt time.Time) (int64, error)
+}
+
+// postgresAbuseReportRepository implements AbuseReportRepository using PostgreSQL
+type postgresAbuseReportRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresAbuseReportRepository creates a new PostgreSQL abuse report repository
+func NewPostgresAbuseReportRepository(conn *database.Connection) AbuseReportRepository {
+	return &postgresAbuseReportRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new abuse report
+func (r *postgresAbuseReportRepository) Create(ctx context.Context, report *domain.AbuseReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+// GetOpen retrieves the open report of a media item by a reporter
+func (r *postgresAbuseReportRepository) GetOpen(ctx context.Context, mediaID, reporterHash string) (*domain.AbuseReport, error) {
+	var report domain.AbuseReport
+	err := r.db.WithContext(ctx).
+		Where("media_id = ? AND reporter_hash = ? AND status = ?", mediaID, reporterHash, domain.AbuseReportOpen).
+		First(&report).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAbuseReportNotFound
+		}
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// Update saves the reason, details and repeats of a report
+func (r *postgresAbuseReportRepository) Update(ctx context.Context, report *domain.AbuseReport) error {
+	return r.db.WithContext(ctx).
+		Model(report).
+		Select("reason", "details", "repeats", "updated_at").
+		Updates(report).Error
+}
+
+// CountSince counts the reports of a reporter filed or repeated since a time
+func (r *postgresAbuseReportRepository) CountSince(ctx context.Context, reporterHash string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.AbuseReport{}).
+		Where("reporter_hash = ? AND updated_at >= ?", reporterHash, since).
+		Count(&count).Error
+	return count, err
+}
+
+// Queue retrieves the open reports folded per media item, the most reported first
+func (r *postgresAbuseReportRepository) Queue(ctx context.Context, limit int) ([]domain.ModerationQueueItem, error) {
+	var mediaIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.AbuseReport{}).
+		Where("status = ?", domain.AbuseReportOpen).
+		Group("media_id").
+		Order("COUNT(*) DESC, MAX(updated_at) DESC, media_id ASC").
+		Limit(limit).
+		Pluck("media_id", &mediaIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(mediaIDs) == 0 {
+		return []domain.ModerationQueueItem{}, nil
+	}
+
+	var reports []*domain.AbuseReport
+	err = r.db.WithContext(ctx).
+		Where("media_id IN ? AND status = ?", mediaIDs, domain.AbuseReportOpen).
+		Find(&reports).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewModerationQueue(mediaIDs, reports), nil
+}
+
+// Resolve closes the open reports of a media item with a decision
+func (r *postgresAbuseReportRepository) Resolve(ctx context.Context, mediaID string, decision domain.AbuseReportStatus, resolvedAt time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.AbuseReport{}).
+		Where("media_id = ? AND status = ?", mediaID, domain.AbuseReportOpen).
+		Updates(map[string]interface{}{
+			"status":      decision,
+			"resolved_at": resolvedAt,
+		})
+
+	return result.RowsAffected, result.Error
+}
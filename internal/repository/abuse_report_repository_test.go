@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAbuseReportRepositoryInterface ensures the mock satisfies the AbuseReportRepository interface
+func TestAbuseReportRepositoryInterface(t *testing.T) {
+	var _ AbuseReportRepository = (*MockAbuseReportRepository)(nil)
+}
+
+// MockAbuseReportRepository can be used in tests
+type MockAbuseReportRepository struct{}
+
+func (m *MockAbuseReportRepository) Create(ctx context.Context, report *domain.AbuseReport) error {
+	return nil
+}
+
+func (m *MockAbuseReportRepository) GetOpen(ctx context.Context, mediaID, reporterHash string) (*domain.AbuseReport, error) {
+	return nil, domain.ErrAbuseReportNotFound
+}
+
+func (m *MockAbuseReportRepository) Update(ctx context.Context, report *domain.AbuseReport) error {
+	return nil
+}
+
+func (m *MockAbuseReportRepository) CountSince(ctx context.Context, reporterHash string, since time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockAbuseReportRepository) Queue(ctx context.Context, limit int) ([]domain.ModerationQueueItem, error) {
+	return nil, nil
+}
+
+func (m *MockAbuseReportRepository) Resolve(ctx context.Context, mediaID string, decision domain.AbuseReportStatus, resolvedAt time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestAbuseReportRepository_FoldAndResolve(t *testing.T) {
+	// Given reports of two media items by three reporters
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresAbuseReportRepository(conn)
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, report := range []*domain.AbuseReport{
+		{ID: "r1", MediaID: "m1", ReporterHash: "alice", Reason: domain.AbuseReasonSpam, Status: domain.AbuseReportOpen, CreatedAt: base, UpdatedAt: base},
+		{ID: "r2", MediaID: "m1", ReporterHash: "bob", Reason: domain.AbuseReasonViolence, Status: domain.AbuseReportOpen, CreatedAt: base, UpdatedAt: base},
+		{ID: "r3", MediaID: "m2", ReporterHash: "alice", Reason: domain.AbuseReasonCopyright, Status: domain.AbuseReportOpen, CreatedAt: base, UpdatedAt: base},
+		{ID: "r4", MediaID: "m2", ReporterHash: "carol", Reason: domain.AbuseReasonCopyright, Status: domain.AbuseReportDismissed, CreatedAt: base, UpdatedAt: base},
+	} {
+		require.NoError(t, repo.Create(ctx, report))
+	}
+
+	// When alice reports m1 again, Then her open report is folded
+	report, err := repo.GetOpen(ctx, "m1", "alice")
+	require.NoError(t, err)
+	report.Reason = domain.AbuseReasonHarassment
+	report.Repeats++
+	report.UpdatedAt = base.Add(time.Hour)
+	require.NoError(t, repo.Update(ctx, report))
+
+	_, err = repo.GetOpen(ctx, "m2", "carol")
+	assert.ErrorIs(t, err, domain.ErrAbuseReportNotFound)
+
+	count, err := repo.CountSince(ctx, "alice", base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// And the queue lists the most reported media first, without closed reports
+	queue, err := repo.Queue(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.Equal(t, "m1", queue[0].MediaID)
+	assert.Equal(t, 2, queue[0].ReportCount)
+	assert.Equal(t, map[domain.AbuseReason]int{domain.AbuseReasonHarassment: 1, domain.AbuseReasonViolence: 1}, queue[0].Reasons)
+	assert.Equal(t, 1, queue[0].Reports[0].Repeats)
+	assert.Equal(t, "m2", queue[1].MediaID)
+	assert.Equal(t, 1, queue[1].ReportCount)
+
+	// When m1 is resolved, Then it leaves the queue
+	resolved, err := repo.Resolve(ctx, "m1", domain.AbuseReportActioned, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resolved)
+
+	queue, err = repo.Queue(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, "m2", queue[0].MediaID)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository defines the contract for API key data access
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, key *domain.APIKey) error
+
+	// GetByID retrieves an API key by ID
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+
+	// GetByKeyHash retrieves an API key by the hash of the key
+	GetByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+
+	// List retrieves all API keys, newest first
+	List(ctx context.Context) ([]*domain.APIKey, error)
+
+	// Rotate replaces the key of an active API key
+	Rotate(ctx context.Context, id, keyHash, prefix string, rotatedAt time.Time) error
+
+	// Revoke marks an active API key as revoked
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+}
+
+// postgresAPIKeyRepository implements APIKeyRepository using PostgreSQL
+type postgresAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresAPIKeyRepository creates a new PostgreSQL API key repository
+func NewPostgresAPIKeyRepository(conn *database.Connection) APIKeyRepository {
+	return &postgresAPIKeyRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new API key
+func (r *postgresAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetByID retrieves an API key by ID
+func (r *postgresAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+// GetByKeyHash retrieves an API key by the hash of the key
+func (r *postgresAPIKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	return r.first(ctx, "key_hash = ?", keyHash)
+}
+
+// List retrieves all API keys, newest first
+func (r *postgresAPIKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Rotate replaces the key of an active API key
+func (r *postgresAPIKeyRepository) Rotate(ctx context.Context, id, keyHash, prefix string, rotatedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"key_hash":   keyHash,
+			"prefix":     prefix,
+			"rotated_at": rotatedAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Revoke marks an active API key as revoked
+func (r *postgresAPIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// first retrieves the first API key matching the condition
+func (r *postgresAPIKeyRepository) first(ctx context.Context, query string, args ...interface{}) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.db.WithContext(ctx).Where(query, args...).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIKeyRepositoryInterface ensures the mock satisfies the APIKeyRepository interface
+func TestAPIKeyRepositoryInterface(t *testing.T) {
+	var _ APIKeyRepository = (*MockAPIKeyRepository)(nil)
+}
+
+// MockAPIKeyRepository can be used in tests
+type MockAPIKeyRepository struct{}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	return nil
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (m *MockAPIKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	return nil, nil
+}
+
+func (m *MockAPIKeyRepository) Rotate(ctx context.Context, id, keyHash, prefix string, rotatedAt time.Time) error {
+	return nil
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	return nil
+}
+
+func TestAPIKeyRepository_RotateAndRevoke(t *testing.T) {
+	// Given an API key
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresAPIKeyRepository(conn)
+
+	require.NoError(t, repo.Create(ctx, &domain.APIKey{
+		ID:       "k1",
+		Name:     "ingest",
+		TenantID: domain.DefaultTenantID,
+		Prefix:   "old",
+		KeyHash:  domain.HashAPIKey("old-key"),
+	}))
+
+	// When it is rotated, Then only the new key resolves
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Rotate(ctx, "k1", domain.HashAPIKey("new-key"), "new", at))
+
+	_, err := repo.GetByKeyHash(ctx, domain.HashAPIKey("old-key"))
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	key, err := repo.GetByKeyHash(ctx, domain.HashAPIKey("new-key"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", key.Prefix)
+	require.NotNil(t, key.RotatedAt)
+
+	// When it is revoked, Then it can be neither revoked again nor rotated
+	require.NoError(t, repo.Revoke(ctx, "k1", at.Add(time.Hour)))
+	assert.ErrorIs(t, repo.Revoke(ctx, "k1", at.Add(2*time.Hour)), domain.ErrAPIKeyNotFound)
+	assert.ErrorIs(t, repo.Rotate(ctx, "k1", domain.HashAPIKey("newer-key"), "newer", at.Add(2*time.Hour)), domain.ErrAPIKeyNotFound)
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.False(t, keys[0].IsActive())
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository defines the contract for audit trail data access
+type AuditRepository interface {
+	// Record appends an entry to the audit trail
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+
+	// GetLatest retrieves the most recent entry of an action with the given outcome
+	GetLatest(ctx context.Context, action, outcome string) (*domain.AuditEntry, error)
+
+	// RecordUnlessSince appends an entry unless one of the same action and
+	// outcome was recorded after since, in which case that entry is returned
+	// and nothing is recorded. Concurrent calls for one action are serialized.
+	RecordUnlessSince(ctx context.Context, entry *domain.AuditEntry, since time.Time) (*domain.AuditEntry, error)
+}
+
+// postgresAuditRepository implements AuditRepository using PostgreSQL
+type postgresAuditRepository struct {
+	db     *gorm.DB
+	sqlite bool // SQLite has a single writer and no advisory locks
+}
+
+// NewPostgresAuditRepository creates a new PostgreSQL audit repository
+func NewPostgresAuditRepository(conn *database.Connection) AuditRepository {
+	return &postgresAuditRepository{
+		db:     conn.DB,
+		sqlite: conn.IsSQLite(),
+	}
+}
+
+// Record appends an entry to the audit trail
+func (r *postgresAuditRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// GetLatest retrieves the most recent entry of an action with the given outcome
+func (r *postgresAuditRepository) GetLatest(ctx context.Context, action, outcome string) (*domain.AuditEntry, error) {
+	var entry domain.AuditEntry
+	err := r.db.WithContext(ctx).
+		Where("action = ? AND outcome = ?", action, outcome).
+		Order("created_at DESC").
+		First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAuditEntryNotFound
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// RecordUnlessSince appends an entry unless one of the same action and outcome
+// was recorded after since. The check and insert run under a transaction-level
+// advisory lock on the action, so replicas sharing the database cannot both record.
+func (r *postgresAuditRepository) RecordUnlessSince(ctx context.Context, entry *domain.AuditEntry, since time.Time) (*domain.AuditEntry, error) {
+	var recent *domain.AuditEntry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !r.sqlite {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "audit:"+entry.Action).Error; err != nil {
+				return err
+			}
+		}
+
+		var last domain.AuditEntry
+		err := tx.Where("action = ? AND outcome = ? AND created_at > ?", entry.Action, entry.Outcome, since).
+			Order("created_at DESC").
+			First(&last).Error
+		if err == nil {
+			recent = &last
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recent, nil
+}
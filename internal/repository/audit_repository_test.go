@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuditRepositoryInterface ensures the mock satisfies the AuditRepository interface
+func TestAuditRepositoryInterface(t *testing.T) {
+	var _ AuditRepository = (*MockAuditRepository)(nil)
+}
+
+// MockAuditRepository can be used in tests
+type MockAuditRepository struct{}
+
+func (m *MockAuditRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	return nil
+}
+
+func (m *MockAuditRepository) GetLatest(ctx context.Context, action, outcome string) (*domain.AuditEntry, error) {
+	return nil, domain.ErrAuditEntryNotFound
+}
+
+func (m *MockAuditRepository) RecordUnlessSince(ctx context.Context, entry *domain.AuditEntry, since time.Time) (*domain.AuditEntry, error) {
+	return nil, nil
+}
+
+func TestAuditRepository_RecordUnlessSince(t *testing.T) {
+	// Given a reindex started at noon
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresAuditRepository(conn)
+
+	noon := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	started := func(id string, at time.Time) *domain.AuditEntry {
+		return &domain.AuditEntry{ID: id, Action: domain.AuditActionSearchReindex, Outcome: domain.AuditOutcomeStarted, CreatedAt: at}
+	}
+	recent, err := repo.RecordUnlessSince(ctx, started("a1", noon), noon.Add(-5*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, recent)
+
+	// When another start is recorded within five minutes
+	recent, err = repo.RecordUnlessSince(ctx, started("a2", noon.Add(time.Minute)), noon.Add(-4*time.Minute))
+
+	// Then the first start is returned and nothing is recorded
+	require.NoError(t, err)
+	require.NotNil(t, recent)
+	assert.Equal(t, "a1", recent.ID)
+	var count int64
+	require.NoError(t, conn.DB.Model(&domain.AuditEntry{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// And a start after the window is recorded
+	recent, err = repo.RecordUnlessSince(ctx, started("a3", noon.Add(6*time.Minute)), noon.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, recent)
+	latest, err := repo.GetLatest(ctx, domain.AuditActionSearchReindex, domain.AuditOutcomeStarted)
+	require.NoError(t, err)
+	assert.Equal(t, "a3", latest.ID)
+}
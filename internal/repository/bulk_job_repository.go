@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// BulkJobRepository defines the contract for bulk job data access
+type BulkJobRepository interface {
+	// Create creates a new job
+	Create(ctx context.Context, job *domain.BulkJob) error
+
+	// GetByID retrieves a job by ID
+	GetByID(ctx context.Context, id string) (*domain.BulkJob, error)
+
+	// Update saves the progress of a job
+	Update(ctx context.Context, job *domain.BulkJob) error
+}
+
+// postgresBulkJobRepository implements BulkJobRepository using PostgreSQL
+type postgresBulkJobRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresBulkJobRepository creates a new PostgreSQL bulk job repository
+func NewPostgresBulkJobRepository(conn *database.Connection) BulkJobRepository {
+	return &postgresBulkJobRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new job
+func (r *postgresBulkJobRepository) Create(ctx context.Context, job *domain.BulkJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetByID retrieves a job by ID
+func (r *postgresBulkJobRepository) GetByID(ctx context.Context, id string) (*domain.BulkJob, error) {
+	var job domain.BulkJob
+	err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrBulkJobNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Update saves the progress of a job
+func (r *postgresBulkJobRepository) Update(ctx context.Context, job *domain.BulkJob) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.BulkJob{}).
+		Where("id = ?", job.ID).
+		Select("status", "processed", "succeeded", "failed", "failures", "completed_at", "updated_at").
+		Updates(job)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrBulkJobNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBulkJobRepositoryInterface ensures the mock satisfies the BulkJobRepository interface
+func TestBulkJobRepositoryInterface(t *testing.T) {
+	var _ BulkJobRepository = (*MockBulkJobRepository)(nil)
+}
+
+// MockBulkJobRepository can be used in tests
+type MockBulkJobRepository struct{}
+
+func (m *MockBulkJobRepository) Create(ctx context.Context, job *domain.BulkJob) error {
+	return nil
+}
+
+func (m *MockBulkJobRepository) GetByID(ctx context.Context, id string) (*domain.BulkJob, error) {
+	return nil, domain.ErrBulkJobNotFound
+}
+
+func (m *MockBulkJobRepository) Update(ctx context.Context, job *domain.BulkJob) error {
+	return nil
+}
+
+func TestBulkJobRepository_TracksProgress(t *testing.T) {
+	// Given a running job
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresBulkJobRepository(conn)
+	job := &domain.BulkJob{ID: "job-1", Operation: domain.BulkOperationLabels, Status: domain.BulkJobRunning, Total: 2}
+	require.NoError(t, repo.Create(ctx, job))
+
+	// When it records its results and completes
+	job.RecordSuccess()
+	job.RecordFailure("missing", errors.New("media not found"))
+	job.Complete(time.Now())
+	require.NoError(t, repo.Update(ctx, job))
+
+	// Then the report is stored
+	stored, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BulkJobCompleted, stored.Status)
+	assert.Equal(t, 2, stored.Processed)
+	assert.Equal(t, 1, stored.Succeeded)
+	assert.Equal(t, 1, stored.Failed)
+	assert.Equal(t, []domain.BulkJobFailure{{MediaID: "missing", Error: "media not found"}}, stored.Failures)
+	assert.NotNil(t, stored.CompletedAt)
+
+	_, err = repo.GetByID(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrBulkJobNotFound)
+}
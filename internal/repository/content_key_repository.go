@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContentKeyRepository defines the contract for DRM content key storage
+type ContentKeyRepository interface {
+	// Create stores the content key of a media item unless it already has one.
+	// It returns false when a key already existed.
+	Create(ctx context.Context, key *domain.ContentKey) (bool, error)
+
+	// GetByMediaID retrieves the content key of a media item
+	GetByMediaID(ctx context.Context, mediaID string) (*domain.ContentKey, error)
+}
+
+// postgresContentKeyRepository implements ContentKeyRepository using PostgreSQL
+type postgresContentKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresContentKeyRepository creates a new PostgreSQL content key repository
+func NewPostgresContentKeyRepository(conn *database.Connection) ContentKeyRepository {
+	return &postgresContentKeyRepository{
+		db: conn.DB,
+	}
+}
+
+// Create stores the content key of a media item unless it already has one
+func (r *postgresContentKeyRepository) Create(ctx context.Context, key *domain.ContentKey) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetByMediaID retrieves the content key of a media item
+func (r *postgresContentKeyRepository) GetByMediaID(ctx context.Context, mediaID string) (*domain.ContentKey, error) {
+	var key domain.ContentKey
+	err := r.db.WithContext(ctx).First(&key, "media_id = ?", mediaID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrContentKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
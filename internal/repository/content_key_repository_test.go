@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+)
+
+// TestContentKeyRepositoryInterface ensures the mock satisfies the ContentKeyRepository interface
+func TestContentKeyRepositoryInterface(t *testing.T) {
+	var _ ContentKeyRepository = (*MockContentKeyRepository)(nil)
+}
+
+// MockContentKeyRepository can be used in tests
+type MockContentKeyRepository struct{}
+
+func (m *MockContentKeyRepository) Create(ctx context.Context, key *domain.ContentKey) (bool, error) {
+	return true, nil
+}
+
+func (m *MockContentKeyRepository) GetByMediaID(ctx context.Context, mediaID string) (*domain.ContentKey, error) {
+	return nil, domain.ErrContentKeyNotFound
+}
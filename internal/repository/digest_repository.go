@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DigestRepository defines the contract for the catalog activity summarized in digests
+type DigestRepository interface {
+	// Tenants retrieves the tenants owning media
+	Tenants(ctx context.Context) ([]string, error)
+
+	// CountUploads counts the media a tenant uploaded within [from, end)
+	CountUploads(ctx context.Context, tenantID string, from, end time.Time) (int64, error)
+
+	// Failures counts the media of a tenant that failed within [from, end) and retrieves
+	// the most recent ones
+	Failures(ctx context.Context, tenantID string, from, end time.Time, limit int) (int64, []domain.DigestMedia, error)
+
+	// TopPlayed retrieves the media of a tenant played the most within [from, end)
+	TopPlayed(ctx context.Context, tenantID string, from, end time.Time, limit int) ([]domain.DigestMedia, error)
+
+	// LastSent retrieves the end of the last digest period sent to a tenant, zero when none was
+	LastSent(ctx context.Context, tenantID string) (time.Time, error)
+
+	// MarkSent records that the digest of the period ending at periodEnd was sent to a tenant
+	MarkSent(ctx context.Context, tenantID string, periodEnd, sentAt time.Time) error
+}
+
+// postgresDigestRepository implements DigestRepository using PostgreSQL
+type postgresDigestRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresDigestRepository creates a new PostgreSQL digest repository
+func NewPostgresDigestRepository(conn *database.Connection) DigestRepository {
+	return &postgresDigestRepository{
+		db: conn.DB,
+	}
+}
+
+// Tenants retrieves the tenants owning media
+func (r *postgresDigestRepository) Tenants(ctx context.Context) ([]string, error) {
+	var tenants []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.Media{}).
+		Where("deleted_at IS NULL").
+		Distinct("tenant_id").
+		Order("tenant_id ASC").
+		Pluck("tenant_id", &tenants).Error
+	return tenants, err
+}
+
+// CountUploads counts the media a tenant uploaded within [from, end)
+func (r *postgresDigestRepository) CountUploads(ctx context.Context, tenantID string, from, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.Media{}).
+		Where("tenant_id = ? AND created_at >= ? AND created_at < ? AND deleted_at IS NULL", tenantID, from, end).
+		Count(&count).Error
+	return count, err
+}
+
+// Failures counts the media of a tenant that failed within [from, end) and retrieves the most recent ones
+func (r *postgresDigestRepository) Failures(ctx context.Context, tenantID string, from, end time.Time, limit int) (int64, []domain.DigestMedia, error) {
+	failed := r.db.WithContext(ctx).
+		Model(&domain.Media{}).
+		Where("tenant_id = ? AND status = ? AND updated_at >= ? AND updated_at < ? AND deleted_at IS NULL",
+			tenantID, domain.StatusFailed, from, end)
+
+	var count int64
+	if err := failed.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return 0, nil, err
+	}
+	if count == 0 {
+		return 0, nil, nil
+	}
+
+	var media []domain.DigestMedia
+	err := failed.
+		Select("id AS media_id, title, failure_message").
+		Order("updated_at DESC").
+		Limit(limit).
+		Scan(&media).Error
+	return count, media, err
+}
+
+// TopPlayed retrieves the media of a tenant played the most within [from, end)
+func (r *postgresDigestRepository) TopPlayed(ctx context.Context, tenantID string, from, end time.Time, limit int) ([]domain.DigestMedia, error) {
+	var media []domain.DigestMedia
+	err := r.db.WithContext(ctx).
+		Table("play_events AS p").
+		Select("p.media_id, m.title, COUNT(*) AS plays").
+		Joins("JOIN media_files m ON m.id = p.media_id").
+		Where("m.tenant_id = ? AND p.played_at >= ? AND p.played_at < ? AND m.deleted_at IS NULL", tenantID, from, end).
+		Group("p.media_id, m.title").
+		Order("plays DESC, p.media_id ASC").
+		Limit(limit).
+		Scan(&media).Error
+	return media, err
+}
+
+// LastSent retrieves the end of the last digest period sent to a tenant, zero when none was
+func (r *postgresDigestRepository) LastSent(ctx context.Context, tenantID string) (time.Time, error) {
+	var deliveries []domain.DigestDelivery
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Limit(1).
+		Find(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return time.Time{}, err
+	}
+	return deliveries[0].PeriodEnd.UTC(), nil
+}
+
+// MarkSent records that the digest of the period ending at periodEnd was sent to a tenant
+func (r *postgresDigestRepository) MarkSent(ctx context.Context, tenantID string, periodEnd, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"period_end", "sent_at"}),
+	}).Create(&domain.DigestDelivery{TenantID: tenantID, PeriodEnd: periodEnd, SentAt: sentAt}).Error
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDigestRepositoryInterface ensures the mock satisfies the DigestRepository interface
+func TestDigestRepositoryInterface(t *testing.T) {
+	var _ DigestRepository = (*MockDigestRepository)(nil)
+}
+
+// MockDigestRepository can be used in tests
+type MockDigestRepository struct{}
+
+func (m *MockDigestRepository) Tenants(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockDigestRepository) CountUploads(ctx context.Context, tenantID string, from, end time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockDigestRepository) Failures(ctx context.Context, tenantID string, from, end time.Time, limit int) (int64, []domain.DigestMedia, error) {
+	return 0, nil, nil
+}
+
+func (m *MockDigestRepository) TopPlayed(ctx context.Context, tenantID string, from, end time.Time, limit int) ([]domain.DigestMedia, error) {
+	return nil, nil
+}
+
+func (m *MockDigestRepository) LastSent(ctx context.Context, tenantID string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (m *MockDigestRepository) MarkSent(ctx context.Context, tenantID string, periodEnd, sentAt time.Time) error {
+	return nil
+}
+
+func TestDigestRepository_Activity(t *testing.T) {
+	// Given media of two tenants, one deleted, uploaded before and during the week
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresDigestRepository(conn)
+
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	end := from.Add(domain.DigestPeriod)
+	deletedAt := from.Add(time.Hour)
+	for _, media := range []*domain.Media{
+		{ID: "m1", Title: "Old episode", TenantID: "acme", Status: domain.StatusReady, CreatedAt: from.Add(-time.Hour), UpdatedAt: from.Add(-time.Hour)},
+		{ID: "m2", Title: "New episode", TenantID: "acme", Status: domain.StatusReady, CreatedAt: from.Add(time.Hour), UpdatedAt: from.Add(time.Hour)},
+		{ID: "m3", Title: "Broken episode", TenantID: "acme", Status: domain.StatusFailed, FailureMessage: "corrupt file", CreatedAt: from.Add(2 * time.Hour), UpdatedAt: from.Add(3 * time.Hour)},
+		{ID: "m4", Title: "Deleted episode", TenantID: "acme", Status: domain.StatusFailed, CreatedAt: from.Add(time.Hour), UpdatedAt: from.Add(time.Hour), DeletedAt: &deletedAt},
+		{ID: "m5", Title: "Other tenant", TenantID: "globex", Status: domain.StatusReady, CreatedAt: from.Add(time.Hour), UpdatedAt: from.Add(time.Hour)},
+	} {
+		require.NoError(t, conn.DB.Create(media).Error)
+	}
+	for i, play := range []struct {
+		mediaID  string
+		playedAt time.Time
+	}{
+		{"m1", from.Add(time.Hour)},
+		{"m1", from.Add(2 * time.Hour)},
+		{"m2", from.Add(time.Hour)},
+		{"m2", from.Add(-time.Hour)},
+		{"m5", from.Add(time.Hour)},
+	} {
+		require.NoError(t, conn.DB.Create(&domain.PlayEvent{
+			ID: string(rune('a' + i)), UserID: "u1", MediaID: play.mediaID, PlayedAt: play.playedAt,
+		}).Error)
+	}
+
+	// Then
+	tenants, err := repo.Tenants(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "globex"}, tenants)
+
+	uploads, err := repo.CountUploads(ctx, "acme", from, end)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), uploads)
+
+	failures, failed, err := repo.Failures(ctx, "acme", from, end, 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), failures)
+	assert.Equal(t, []domain.DigestMedia{{MediaID: "m3", Title: "Broken episode", FailureMessage: "corrupt file"}}, failed)
+
+	played, err := repo.TopPlayed(ctx, "acme", from, end, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DigestMedia{
+		{MediaID: "m1", Title: "Old episode", Plays: 2},
+		{MediaID: "m2", Title: "New episode", Plays: 1},
+	}, played)
+}
+
+func TestDigestRepository_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresDigestRepository(conn)
+
+	// Nothing was sent yet
+	last, err := repo.LastSent(ctx, "acme")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	// Each period sent replaces the previous one
+	first := time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)
+	second := first.Add(domain.DigestPeriod)
+	require.NoError(t, repo.MarkSent(ctx, "acme", first, first.Add(8*time.Hour)))
+	require.NoError(t, repo.MarkSent(ctx, "acme", second, second.Add(8*time.Hour)))
+
+	last, err = repo.LastSent(ctx, "acme")
+	require.NoError(t, err)
+	assert.True(t, second.Equal(last))
+
+	last, err = repo.LastSent(ctx, "globex")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// DownloadRepository defines the contract for podcast download data access
+type DownloadRepository interface {
+	// Create records a counted download
+	Create(ctx context.Context, download *domain.Download) error
+
+	// CreateUnlessSince records a download unless one of the media by the same client
+	// was counted at or after since, and reports whether it was recorded
+	CreateUnlessSince(ctx context.Context, download *domain.Download, since time.Time) (bool, error)
+
+	// CountByMedia counts the downloads of an episode by UTC day within [from, end)
+	CountByMedia(ctx context.Context, mediaID string, from, end time.Time) ([]domain.DownloadStatsDay, error)
+
+	// CountByShow counts the downloads of the episodes of a show by UTC day within [from, end)
+	CountByShow(ctx context.Context, showID string, from, end time.Time) ([]domain.DownloadStatsDay, error)
+}
+
+// postgresDownloadRepository implements DownloadRepository using PostgreSQL
+type postgresDownloadRepository struct {
+	db     *gorm.DB
+	sqlite bool // SQLite has a single writer and no advisory locks
+}
+
+// NewPostgresDownloadRepository creates a new PostgreSQL download repository
+func NewPostgresDownloadRepository(conn *database.Connection) DownloadRepository {
+	return &postgresDownloadRepository{
+		db:     conn.DB,
+		sqlite: conn.IsSQLite(),
+	}
+}
+
+// Create records a counted download
+func (r *postgresDownloadRepository) Create(ctx context.Context, download *domain.Download) error {
+	return r.db.WithContext(ctx).Create(download).Error
+}
+
+// CreateUnlessSince records a download unless one of the media by the same client
+// was counted at or after since. The check and the insert are a single statement,
+// run under an advisory lock on the client so concurrent requests cannot both count.
+func (r *postgresDownloadRepository) CreateUnlessSince(ctx context.Context, download *domain.Download, since time.Time) (bool, error) {
+	// Postgres reads untyped parameters of INSERT ... SELECT as text
+	downloadedAt := "CAST(? AS TIMESTAMPTZ)"
+	if r.sqlite {
+		downloadedAt = "?"
+	}
+
+	var created bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !r.sqlite {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "download:"+download.MediaID+":"+download.ClientHash).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec(
+			"INSERT INTO downloads (id, media_id, show_id, client_hash, day, downloaded_at) "+
+				"SELECT ?, ?, ?, ?, ?, "+downloadedAt+" "+
+				"WHERE NOT EXISTS (SELECT 1 FROM downloads WHERE media_id = ? AND client_hash = ? AND downloaded_at >= ?)",
+			download.ID, download.MediaID, download.ShowID, download.ClientHash, download.Day, download.DownloadedAt,
+			download.MediaID, download.ClientHash, since,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = result.RowsAffected > 0
+		return nil
+	})
+	return created, err
+}
+
+// CountByMedia counts the downloads of an episode by UTC day within [from, end)
+func (r *postgresDownloadRepository) CountByMedia(ctx context.Context, mediaID string, from, end time.Time) ([]domain.DownloadStatsDay, error) {
+	return r.countByDay(r.db.WithContext(ctx).Where("media_id = ?", mediaID), from, end)
+}
+
+// CountByShow counts the downloads of the episodes of a show by UTC day within [from, end)
+func (r *postgresDownloadRepository) CountByShow(ctx context.Context, showID string, from, end time.Time) ([]domain.DownloadStatsDay, error) {
+	return r.countByDay(r.db.WithContext(ctx).Where("show_id = ?", showID), from, end)
+}
+
+// countByDay groups the downloads of a query by day, in day order.
+// Days are stored as text so the grouping works the same on every database.
+func (r *postgresDownloadRepository) countByDay(query *gorm.DB, from, end time.Time) ([]domain.DownloadStatsDay, error) {
+	var days []domain.DownloadStatsDay
+	err := query.
+		Model(&domain.Download{}).
+		Select("day, COUNT(*) AS downloads").
+		Where("day >= ? AND day < ?", from.UTC().Format(domain.CalendarDateLayout), end.UTC().Format(domain.CalendarDateLayout)).
+		Group("day").
+		Order("day ASC").
+		Scan(&days).Error
+	return days, err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDownloadRepositoryInterface ensures the mock satisfies the DownloadRepository interface
+func TestDownloadRepositoryInterface(t *testing.T) {
+	var _ DownloadRepository = (*MockDownloadRepository)(nil)
+}
+
+// MockDownloadRepository can be used in tests
+type MockDownloadRepository struct{}
+
+func (m *MockDownloadRepository) Create(ctx context.Context, download *domain.Download) error {
+	return nil
+}
+
+func (m *MockDownloadRepository) CreateUnlessSince(ctx context.Context, download *domain.Download, since time.Time) (bool, error) {
+	return true, nil
+}
+
+func (m *MockDownloadRepository) CountByMedia(ctx context.Context, mediaID string, from, end time.Time) ([]domain.DownloadStatsDay, error) {
+	return nil, nil
+}
+
+func (m *MockDownloadRepository) CountByShow(ctx context.Context, showID string, from, end time.Time) ([]domain.DownloadStatsDay, error) {
+	return nil, nil
+}
+
+func TestDownloadRepository_CountByDay(t *testing.T) {
+	// Given downloads of two episodes of a show over two days
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresDownloadRepository(conn)
+
+	day := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	for i, d := range []struct {
+		mediaID string
+		client  string
+		at      time.Time
+	}{
+		{"e1", "c1", day},
+		{"e1", "c2", day.Add(time.Hour)},
+		{"e2", "c1", day.Add(time.Hour)},
+		{"e1", "c1", day.Add(25 * time.Hour)},
+		{"e1", "c3", day.AddDate(0, 0, 10)},
+	} {
+		require.NoError(t, repo.Create(ctx, &domain.Download{
+			ID:           string(rune('a' + i)),
+			MediaID:      d.mediaID,
+			ShowID:       "show",
+			ClientHash:   d.client,
+			Day:          d.at.Format(domain.CalendarDateLayout),
+			DownloadedAt: d.at,
+		}))
+	}
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := from.AddDate(0, 0, 7)
+
+	// When
+	episode, err := repo.CountByMedia(ctx, "e1", from, end)
+	require.NoError(t, err)
+	show, err := repo.CountByShow(ctx, "show", from, end)
+	require.NoError(t, err)
+
+	// Then downloads within the range are counted by day
+	assert.Equal(t, []domain.DownloadStatsDay{{Day: "2025-06-01", Downloads: 2}, {Day: "2025-06-02", Downloads: 1}}, episode)
+	assert.Equal(t, []domain.DownloadStatsDay{{Day: "2025-06-01", Downloads: 3}, {Day: "2025-06-02", Downloads: 1}}, show)
+
+	// And a repeated download of a client within the window is not recorded
+	repeat := func(id, mediaID, client string) *domain.Download {
+		at := day.Add(3 * time.Hour)
+		return &domain.Download{ID: id, MediaID: mediaID, ShowID: "show", ClientHash: client, Day: at.Format(domain.CalendarDateLayout), DownloadedAt: at}
+	}
+	created, err := repo.CreateUnlessSince(ctx, repeat("r1", "e1", "c2"), day)
+	require.NoError(t, err)
+	assert.False(t, created)
+	created, err = repo.CreateUnlessSince(ctx, repeat("r2", "e2", "c1"), day.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	episode, err = repo.CountByMedia(ctx, "e1", from, end)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DownloadStatsDay{{Day: "2025-06-01", Downloads: 2}, {Day: "2025-06-02", Downloads: 1}}, episode)
+	episode, err = repo.CountByMedia(ctx, "e2", from, end)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DownloadStatsDay{{Day: "2025-06-01", Downloads: 2}}, episode)
+}
@@ -4,20 +4,43 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"thamaniyah/internal/domain"
 	"thamaniyah/pkg/elasticsearch"
 )
 
+// SearchBoosts weights the fields of full-text matches
+type SearchBoosts struct {
+	Title       float64
+	Description float64
+	Show        float64 // title of the show of an episode
+	Category    float64 // category names of an episode and its show
+	Host        float64 // host names of an episode and its show
+	Guest       float64 // guest names of an episode and its show
+}
+
+// DefaultSearchBoosts ranks title matches above show and host matches, and
+// those above description, category, guest and content matches
+var DefaultSearchBoosts = SearchBoosts{Title: 2, Description: 1, Show: 1.5, Category: 1, Host: 1.5, Guest: 1}
+
 // ElasticsearchSearchRepository implements SearchRepository using Elasticsearch
 type ElasticsearchSearchRepository struct {
 	client *elasticsearch.Client
+	boosts func() SearchBoosts
 }
 
-// NewElasticsearchSearchRepository creates a new Elasticsearch search repository
-func NewElasticsearchSearchRepository(client *elasticsearch.Client) SearchRepository {
+// NewElasticsearchSearchRepository creates a new Elasticsearch search repository.
+// boosts is consulted on every search so the weights can change at runtime; nil
+// uses DefaultSearchBoosts.
+func NewElasticsearchSearchRepository(client *elasticsearch.Client, boosts func() SearchBoosts) SearchRepository {
+	if boosts == nil {
+		boosts = func() SearchBoosts { return DefaultSearchBoosts }
+	}
 	return &ElasticsearchSearchRepository{
 		client: client,
+		boosts: boosts,
 	}
 }
 
@@ -40,21 +63,54 @@ func (r *ElasticsearchSearchRepository) Search(ctx context.Context, req *domain.
 			Media: media,
 			Score: hit.Score,
 		}
+		if episodes, ok := hit.InnerHits[esCollapsedEpisodes]; ok {
+			result.InnerHits, result.InnerHitsTotal = r.innerHits(hit.ID, episodes.Hits)
+		}
 		results = append(results, result)
 	}
 
+	// The total of a collapsed search is the number of shows, not of hits
+	if req.Collapse == domain.SearchCollapseShow {
+		return results, int64(searchResp.Aggregations[esCollapsedShows].Value), nil
+	}
 	return results, searchResp.Hits.Total.Value, nil
 }
 
+// innerHits converts the hits of a collapsed show other than its top hit
+// topID, and returns how many there are
+func (r *ElasticsearchSearchRepository) innerHits(topID string, hits elasticsearch.SearchHits) ([]*domain.SearchResult, int64) {
+	results := make([]*domain.SearchResult, 0, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		if len(results) == domain.CollapseInnerHits {
+			break
+		}
+		if hit.ID == topID {
+			continue
+		}
+		results = append(results, &domain.SearchResult{Media: r.hitToMedia(hit.Source), Score: hit.Score})
+	}
+	return results, max(hits.Total.Value-1, 0)
+}
+
 // Suggest provides search suggestions using Elasticsearch
 func (r *ElasticsearchSearchRepository) Suggest(ctx context.Context, req *domain.SuggestRequest) ([]*domain.Suggestion, error) {
 	// Build suggestion query using match_phrase_prefix
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"match_phrase_prefix": map[string]interface{}{
-				"title": req.Query,
-			},
+	var matchQuery interface{} = map[string]interface{}{
+		"match_phrase_prefix": map[string]interface{}{
+			"title": req.Query,
 		},
+	}
+	if req.Safe {
+		matchQuery = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":     matchQuery,
+				"must_not": explicitContentQuery(),
+			},
+		}
+	}
+
+	query := map[string]interface{}{
+		"query":   matchQuery,
 		"size":    req.Limit,
 		"_source": []string{"title"},
 		"aggs": map[string]interface{}{
@@ -150,6 +206,57 @@ func (r *ElasticsearchSearchRepository) ReindexAll(ctx context.Context, mediaLis
 	return nil
 }
 
+// esListPageSize is the number of documents fetched per request when listing the index
+const esListPageSize = 1000
+
+// Names of the inner hits and show count of searches collapsed by show
+const (
+	esCollapsedEpisodes = "episodes"
+	esCollapsedShows    = "shows"
+)
+
+// esCardinalityPrecision is the number of shows below which collapsed totals
+// are exact, the maximum Elasticsearch supports
+const esCardinalityPrecision = 40000
+
+// ListIndexed lists every document of the index with the update time of the
+// media it was built from, paging through the index sorted by ID
+func (r *ElasticsearchSearchRepository) ListIndexed(ctx context.Context) ([]*domain.IndexedMedia, error) {
+	var indexed []*domain.IndexedMedia
+	var searchAfter []interface{}
+	for {
+		query := map[string]interface{}{
+			"size":    esListPageSize,
+			"_source": []string{"id", "updated_at"},
+			"query":   map[string]interface{}{"match_all": map[string]interface{}{}},
+			"sort":    []interface{}{map[string]interface{}{"id": "asc"}},
+		}
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		searchResp, err := r.client.Search(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list indexed media: %w", err)
+		}
+		for _, hit := range searchResp.Hits.Hits {
+			entry := &domain.IndexedMedia{MediaID: hit.ID}
+			if value, ok := hit.Source["updated_at"].(string); ok {
+				if updatedAt, err := time.Parse(time.RFC3339Nano, value); err == nil {
+					entry.UpdatedAt = updatedAt
+				}
+			}
+			indexed = append(indexed, entry)
+		}
+
+		hits := searchResp.Hits.Hits
+		if len(hits) < esListPageSize {
+			return indexed, nil
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+}
+
 // Helper methods
 
 // buildSearchQuery constructs Elasticsearch query from SearchRequest
@@ -165,15 +272,9 @@ func (r *ElasticsearchSearchRepository) buildSearchQuery(req *domain.SearchReque
 	}
 
 	// Add text search if query provided
-	if req.Query != "" {
-		textQuery := map[string]interface{}{
-			"multi_match": map[string]interface{}{
-				"query":  req.Query,
-				"fields": []string{"title^2", "description", "content"}, // Boost title matches
-				"type":   "best_fields",
-			},
-		}
-		boolQuery["must"] = append(boolQuery["must"].([]interface{}), textQuery)
+	parsed := req.StructuredQuery()
+	if len(parsed.Clauses) > 0 {
+		boolQuery["must"] = append(boolQuery["must"].([]interface{}), r.clausesQuery(parsed.Clauses))
 	} else {
 		// If no query, match all
 		boolQuery["must"] = append(boolQuery["must"].([]interface{}), map[string]interface{}{
@@ -194,6 +295,56 @@ func (r *ElasticsearchSearchRepository) buildSearchQuery(req *domain.SearchReque
 		boolQuery["filter"] = append(boolQuery["filter"].([]interface{}), typeFilter)
 	}
 
+	// Add show filter if specified
+	if req.ShowID != "" {
+		showFilter := map[string]interface{}{
+			"term": map[string]interface{}{
+				"show_id": req.ShowID,
+			},
+		}
+		if boolQuery["filter"] == nil {
+			boolQuery["filter"] = []interface{}{}
+		}
+		boolQuery["filter"] = append(boolQuery["filter"].([]interface{}), showFilter)
+	}
+
+	// Add audio language filter if specified
+	if req.AudioLanguage != "" {
+		audioLanguageFilter := map[string]interface{}{
+			"term": map[string]interface{}{
+				"audio_languages": req.AudioLanguage,
+			},
+		}
+		if boolQuery["filter"] == nil {
+			boolQuery["filter"] = []interface{}{}
+		}
+		boolQuery["filter"] = append(boolQuery["filter"].([]interface{}), audioLanguageFilter)
+	}
+
+	// Filter by tags
+	for _, tag := range parsed.Tags {
+		if boolQuery["filter"] == nil {
+			boolQuery["filter"] = []interface{}{}
+		}
+		boolQuery["filter"] = append(boolQuery["filter"].([]interface{}), map[string]interface{}{
+			"term": map[string]interface{}{"tags": tag},
+		})
+	}
+	mustNot := []interface{}{}
+	for _, tag := range parsed.ExcludedTags {
+		mustNot = append(mustNot, map[string]interface{}{
+			"term": map[string]interface{}{"tags": tag},
+		})
+	}
+
+	// Exclude explicit content for safe search
+	if req.Safe {
+		mustNot = append(mustNot, explicitContentQuery())
+	}
+	if len(mustNot) > 0 {
+		boolQuery["must_not"] = mustNot
+	}
+
 	query["query"] = map[string]interface{}{
 		"bool": boolQuery,
 	}
@@ -204,13 +355,104 @@ func (r *ElasticsearchSearchRepository) buildSearchQuery(req *domain.SearchReque
 		{"created_at": map[string]string{"order": "desc"}},
 	}
 
+	// Collapse the episodes of a show into its best hit, counting the shows
+	if req.Collapse == domain.SearchCollapseShow {
+		query["collapse"] = map[string]interface{}{
+			"field": "show_group",
+			"inner_hits": map[string]interface{}{
+				"name": esCollapsedEpisodes,
+				"size": domain.CollapseInnerHits + 1, // the top hit comes back among them
+				"sort": query["sort"],
+			},
+		}
+		query["aggs"] = map[string]interface{}{
+			esCollapsedShows: map[string]interface{}{
+				"cardinality": map[string]interface{}{
+					"field":               "show_group",
+					"precision_threshold": esCardinalityPrecision,
+				},
+			},
+		}
+	}
+
 	return query
 }
 
-// mediaToDocument converts Media to Elasticsearch document
+// clausesQuery matches documents matching any of the clauses. The words of a
+// clause are ranked together by relevance, while its phrases must match and
+// its excluded terms must not.
+func (r *ElasticsearchSearchRepository) clausesQuery(clauses []domain.SearchClause) map[string]interface{} {
+	alternatives := make([]interface{}, 0, len(clauses))
+	for _, clause := range clauses {
+		var words []string
+		must, mustNot := []interface{}{}, []interface{}{}
+		for _, term := range clause.Terms {
+			if term.Phrase {
+				must = append(must, r.termQuery(term))
+			} else {
+				words = append(words, term.Text)
+			}
+		}
+		if len(words) > 0 {
+			must = append(must, r.termQuery(domain.SearchTerm{Text: strings.Join(words, " ")}))
+		}
+		for _, term := range clause.Excluded {
+			mustNot = append(mustNot, r.termQuery(term))
+		}
+		if len(must) == 0 {
+			must = append(must, map[string]interface{}{"match_all": map[string]interface{}{}})
+		}
+		alternatives = append(alternatives, map[string]interface{}{
+			"bool": map[string]interface{}{"must": must, "must_not": mustNot},
+		})
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0].(map[string]interface{})
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should":               alternatives,
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// termQuery matches text in the search fields, as a phrase for phrases
+func (r *ElasticsearchSearchRepository) termQuery(term domain.SearchTerm) map[string]interface{} {
+	matchType := "best_fields"
+	if term.Phrase {
+		matchType = "phrase"
+	}
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":  term.Text,
+			"fields": r.searchFields(),
+			"type":   matchType,
+		},
+	}
+}
+
+// explicitContentQuery matches documents excluded by safe search
+func explicitContentQuery() map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{
+			"explicit": true,
+		},
+	}
+}
+
+// mediaToDocument converts Media to Elasticsearch document, with the show
+// context of its search context. The title and description are kept as they
+// are for hits; the other searched fields are normalized like queries.
 func (r *ElasticsearchSearchRepository) mediaToDocument(media *domain.Media) map[string]interface{} {
 	// Create searchable content
-	content := media.Title + " " + media.Description
+	content := domain.NormalizeSearchText(media.Title + " " + media.Description)
+
+	searchContext := media.SearchContext
+	if searchContext == nil {
+		searchContext = &domain.SearchContext{}
+	}
 
 	return map[string]interface{}{
 		"id":          media.ID,
@@ -223,9 +465,29 @@ func (r *ElasticsearchSearchRepository) mediaToDocument(media *domain.Media) map
 		"file_size":   media.FileSize,
 		"duration":    media.Duration,
 		"format":      media.Format,
+		"age_rating":  media.ContentRating.AgeRating,
+		"explicit":    media.ContentRating.IsExplicit(),
+		"show_id":     media.ShowID,
+		"show_group":  domain.ShowGroup(media),
+		"show_title":  domain.NormalizeSearchText(searchContext.ShowTitle),
+		"categories":  normalizeSearchTexts(searchContext.Categories),
+		"hosts":       normalizeSearchTexts(searchContext.Hosts),
+		"guests":      normalizeSearchTexts(searchContext.Guests),
+		"tags":        domain.NormalizeLabels(media.Labels),
 		"created_at":  media.CreatedAt,
 		"updated_at":  media.UpdatedAt,
+
+		"audio_languages": media.AudioLanguages(),
+	}
+}
+
+// normalizeSearchTexts normalizes each of texts like queries
+func normalizeSearchTexts(texts []string) []string {
+	normalized := make([]string, len(texts))
+	for i, text := range texts {
+		normalized[i] = domain.NormalizeSearchText(text)
 	}
+	return normalized
 }
 
 // hitToMedia converts Elasticsearch hit to Media domain model
@@ -259,6 +521,22 @@ func (r *ElasticsearchSearchRepository) hitToMedia(source map[string]interface{}
 	if format, ok := source["format"].(string); ok {
 		media.Format = format
 	}
+	if ageRating, ok := source["age_rating"].(string); ok {
+		media.ContentRating.AgeRating = domain.AgeRating(ageRating)
+	}
+	if explicit, ok := source["explicit"].(bool); ok {
+		media.ContentRating.Explicit = explicit
+	}
+	if showID, ok := source["show_id"].(string); ok {
+		media.ShowID = showID
+	}
+	if tags, ok := source["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			if label, ok := tag.(string); ok {
+				media.Labels = append(media.Labels, label)
+			}
+		}
+	}
 
 	// For search results, we set status as ready since we only index ready content
 	if media.Status == "" {
@@ -267,3 +545,17 @@ func (r *ElasticsearchSearchRepository) hitToMedia(source map[string]interface{}
 
 	return media
 }
+
+// searchFields returns the full-text fields weighted by the current boosts
+func (r *ElasticsearchSearchRepository) searchFields() []string {
+	boosts := r.boosts()
+	return []string{
+		fmt.Sprintf("title^%g", boosts.Title),
+		fmt.Sprintf("description^%g", boosts.Description),
+		fmt.Sprintf("show_title^%g", boosts.Show),
+		fmt.Sprintf("categories^%g", boosts.Category),
+		fmt.Sprintf("hosts^%g", boosts.Host),
+		fmt.Sprintf("guests^%g", boosts.Guest),
+		"content",
+	}
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticsearchSearchRepository_Integration(t *testing.T) {
+	ctx := context.Background()
+	repo := NewElasticsearchSearchRepository(testsupport.StartElasticsearch(t), nil)
+
+	fixtures := testsupport.MediaFixtures()
+	explicit := &domain.Media{ID: "fixture-explicit", Title: "Golang After Dark", Type: domain.TypePodcast, Status: domain.StatusReady,
+		ShowID: "show-after-dark", ContentRating: domain.ContentRating{AgeRating: domain.AgeRating18}}
+	require.NoError(t, repo.ReindexAll(ctx, []*domain.Media{fixtures[0], fixtures[1], explicit}))
+
+	t.Run("ranks title matches first", func(t *testing.T) {
+		results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.NotEmpty(t, results)
+		assert.NotEqual(t, "fixture-podcast", results[0].Media.ID)
+	})
+
+	t.Run("excludes explicit media from safe search", func(t *testing.T) {
+		_, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang", Safe: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("filters by show", func(t *testing.T) {
+		results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang", ShowID: "show-after-dark"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, results, 1)
+		assert.Equal(t, "fixture-explicit", results[0].Media.ID)
+	})
+
+	t.Run("lists indexed media", func(t *testing.T) {
+		indexed, err := repo.ListIndexed(ctx)
+		require.NoError(t, err)
+		require.Len(t, indexed, 3)
+		assert.Equal(t, "fixture-explicit", indexed[0].MediaID)
+		assert.False(t, indexed[0].IsStaleFor(explicit))
+	})
+
+	t.Run("removes media", func(t *testing.T) {
+		require.NoError(t, repo.RemoveFromIndex(ctx, "fixture-video"))
+
+		results, _, err := repo.Search(ctx, &domain.SearchRequest{Query: "concurrency"})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
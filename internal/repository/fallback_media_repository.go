@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// fallbackEntry is the last result of a read, kept for when the database is unavailable
+type fallbackEntry struct {
+	value    interface{}
+	storedAt time.Time
+}
+
+// fallbackMediaRepository keeps the last results of the reads behind the media
+// pages and listings, and serves them while the database is unavailable
+type fallbackMediaRepository struct {
+	MediaRepository
+	maxAge     time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu       sync.Mutex
+	media    map[string]fallbackEntry // *domain.Media by ID, nil for missing media
+	listings map[string]fallbackEntry // pages and totals by query
+}
+
+// NewFallbackMediaRepository wraps next so that media, listing pages and totals
+// read in the last maxAge are served from memory when reading them fails with
+// domain.ErrServiceUnavailable. Copies are only served to contexts accepting
+// degraded reads, which they are marked with. Each cache holds up to
+// maxEntries results.
+func NewFallbackMediaRepository(next MediaRepository, maxAge time.Duration, maxEntries int) MediaRepository {
+	return &fallbackMediaRepository{
+		MediaRepository: next,
+		maxAge:          maxAge,
+		maxEntries:      maxEntries,
+		now:             time.Now,
+		media:           make(map[string]fallbackEntry),
+		listings:        make(map[string]fallbackEntry),
+	}
+}
+
+// GetByID retrieves a media record by ID
+func (r *fallbackMediaRepository) GetByID(ctx context.Context, id string) (*domain.Media, error) {
+	media, err := r.MediaRepository.GetByID(ctx, id)
+	switch {
+	case err == nil:
+		r.storeMedia(map[string]*domain.Media{id: media})
+		return media, nil
+	case errors.Is(err, domain.ErrMediaNotFound):
+		r.storeMedia(map[string]*domain.Media{id: nil})
+		return nil, err
+	case !errors.Is(err, domain.ErrServiceUnavailable):
+		return nil, err
+	}
+
+	cached, ok := r.loadMedia([]string{id})
+	if !ok || !domain.MarkDegraded(ctx) {
+		return nil, err
+	}
+	if len(cached) == 0 {
+		return nil, domain.ErrMediaNotFound
+	}
+	return cached[0], nil
+}
+
+// GetByIDs retrieves the existing media records among ids
+func (r *fallbackMediaRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	mediaList, err := r.MediaRepository.GetByIDs(ctx, ids)
+	if err == nil {
+		found := make(map[string]*domain.Media, len(ids))
+		for _, id := range ids {
+			found[id] = nil
+		}
+		for _, media := range mediaList {
+			found[media.ID] = media
+		}
+		r.storeMedia(found)
+		return mediaList, nil
+	}
+	if !errors.Is(err, domain.ErrServiceUnavailable) {
+		return nil, err
+	}
+
+	cached, ok := r.loadMedia(ids)
+	if !ok || !domain.MarkDegraded(ctx) {
+		return nil, err
+	}
+	return cached, nil
+}
+
+// GetAllWithTotal retrieves a page of media records together with the total count in one query
+func (r *fallbackMediaRepository) GetAllWithTotal(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error) {
+	key := fmt.Sprintf("all:%d:%d", limit, offset)
+	mediaList, total, err := r.MediaRepository.GetAllWithTotal(ctx, limit, offset)
+	if err == nil {
+		r.storeListing(key, mediaPage{media: mediaList, total: total})
+		return mediaList, total, nil
+	}
+
+	cached, ok := r.fallbackListing(ctx, key, err)
+	if !ok {
+		return nil, 0, err
+	}
+	page := cached.(mediaPage)
+	return cloneMediaList(page.media), page.total, nil
+}
+
+// GetByFilter retrieves media records matching the filter
+func (r *fallbackMediaRepository) GetByFilter(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, error) {
+	key := fmt.Sprintf("filter:%+v:%d:%d", filterKey(filter), limit, offset)
+	mediaList, err := r.MediaRepository.GetByFilter(ctx, filter, limit, offset)
+	if err == nil {
+		r.storeListing(key, mediaPage{media: mediaList})
+		return mediaList, nil
+	}
+
+	cached, ok := r.fallbackListing(ctx, key, err)
+	if !ok {
+		return nil, err
+	}
+	return cloneMediaList(cached.(mediaPage).media), nil
+}
+
+// GetTotalByFilter returns the number of media records matching the filter
+func (r *fallbackMediaRepository) GetTotalByFilter(ctx context.Context, filter *domain.MediaFilter) (int64, error) {
+	key := fmt.Sprintf("total:%+v", filterKey(filter))
+	total, err := r.MediaRepository.GetTotalByFilter(ctx, filter)
+	if err == nil {
+		r.storeListing(key, total)
+		return total, nil
+	}
+
+	cached, ok := r.fallbackListing(ctx, key, err)
+	if !ok {
+		return 0, err
+	}
+	return cached.(int64), nil
+}
+
+// mediaPage is a cached listing page
+type mediaPage struct {
+	media []*domain.Media
+	total int64
+}
+
+// filterKey returns the filter a listing is cached under, nil being no filter
+func filterKey(filter *domain.MediaFilter) domain.MediaFilter {
+	if filter == nil {
+		return domain.MediaFilter{}
+	}
+	return *filter
+}
+
+// fallbackListing returns the cached result of a listing when err is an unavailable database
+func (r *fallbackMediaRepository) fallbackListing(ctx context.Context, key string, err error) (interface{}, bool) {
+	if !errors.Is(err, domain.ErrServiceUnavailable) {
+		return nil, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.listings[key]
+	if !ok || r.expired(entry) || !domain.MarkDegraded(ctx) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// storeListing keeps the result of a listing
+func (r *fallbackMediaRepository) storeListing(key string, value interface{}) {
+	if page, ok := value.(mediaPage); ok {
+		page.media = cloneMediaList(page.media)
+		value = page
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(r.listings, key, value)
+}
+
+// storeMedia keeps media by ID, nil recording media that does not exist
+func (r *fallbackMediaRepository) storeMedia(found map[string]*domain.Media) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, media := range found {
+		if media != nil {
+			media = cloneMedia(media)
+		}
+		r.store(r.media, id, media)
+	}
+}
+
+// loadMedia returns the cached media among ids, and false unless every ID is cached
+func (r *fallbackMediaRepository) loadMedia(ids []string) ([]*domain.Media, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mediaList := make([]*domain.Media, 0, len(ids))
+	for _, id := range ids {
+		entry, ok := r.media[id]
+		if !ok || r.expired(entry) {
+			return nil, false
+		}
+		if media, _ := entry.value.(*domain.Media); media != nil {
+			mediaList = append(mediaList, cloneMedia(media))
+		}
+	}
+	return mediaList, true
+}
+
+// store keeps value under key. A full cache first drops its expired entries,
+// and keeps nothing new when none expired.
+func (r *fallbackMediaRepository) store(entries map[string]fallbackEntry, key string, value interface{}) {
+	if _, ok := entries[key]; !ok && len(entries) >= r.maxEntries {
+		for k, entry := range entries {
+			if r.expired(entry) {
+				delete(entries, k)
+			}
+		}
+		if len(entries) >= r.maxEntries {
+			return
+		}
+	}
+	entries[key] = fallbackEntry{value: value, storedAt: r.now()}
+}
+
+// expired returns true if the entry is too old to be served
+func (r *fallbackMediaRepository) expired(entry fallbackEntry) bool {
+	return r.now().Sub(entry.storedAt) > r.maxAge
+}
+
+// cloneMediaList copies media so cached copies are never shared with callers
+func cloneMediaList(mediaList []*domain.Media) []*domain.Media {
+	clones := make([]*domain.Media, len(mediaList))
+	for i, media := range mediaList {
+		clones[i] = cloneMedia(media)
+	}
+	return clones
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableMediaRepository fails every read while down, like a database that went away
+type unavailableMediaRepository struct {
+	MediaRepository
+	down bool
+}
+
+func (r *unavailableMediaRepository) err() error {
+	return fmt.Errorf("%w: connection refused", domain.ErrServiceUnavailable)
+}
+
+func (r *unavailableMediaRepository) GetByID(ctx context.Context, id string) (*domain.Media, error) {
+	if r.down {
+		return nil, r.err()
+	}
+	return r.MediaRepository.GetByID(ctx, id)
+}
+
+func (r *unavailableMediaRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	if r.down {
+		return nil, r.err()
+	}
+	return r.MediaRepository.GetByIDs(ctx, ids)
+}
+
+func (r *unavailableMediaRepository) GetByFilter(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, error) {
+	if r.down {
+		return nil, r.err()
+	}
+	return r.MediaRepository.GetByFilter(ctx, filter, limit, offset)
+}
+
+func TestFallbackMediaRepository(t *testing.T) {
+	// Given media read while the database was up
+	background := context.Background()
+	ctx, degradation := domain.ContextWithDegradation(background)
+	source := &unavailableMediaRepository{MediaRepository: NewInMemoryMediaRepository()}
+	require.NoError(t, source.Create(ctx, &domain.Media{ID: "m1", Title: "Episode 1", Status: domain.StatusReady}))
+	require.NoError(t, source.Create(ctx, &domain.Media{ID: "m2", Title: "Episode 2", Status: domain.StatusReady}))
+	repo := NewFallbackMediaRepository(source, time.Minute, 10).(*fallbackMediaRepository)
+
+	_, err := repo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	filter := &domain.MediaFilter{Status: domain.StatusReady}
+	_, err = repo.GetByFilter(ctx, filter, 10, 0)
+	require.NoError(t, err)
+	assert.False(t, degradation.Degraded())
+
+	// When the database goes down
+	source.down = true
+
+	// Then the cached reads are served and the request is marked degraded
+	media, err := repo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, "Episode 1", media.Title)
+	assert.True(t, degradation.Degraded())
+	_, err = repo.GetByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	list, err := repo.GetByFilter(ctx, &domain.MediaFilter{Status: domain.StatusReady}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	// Reads never cached, or only partly, still fail
+	_, err = repo.GetByID(ctx, "m2")
+	assert.ErrorIs(t, err, domain.ErrServiceUnavailable)
+	_, err = repo.GetByIDs(ctx, []string{"m1", "m2"})
+	assert.ErrorIs(t, err, domain.ErrServiceUnavailable)
+	_, err = repo.GetByFilter(ctx, filter, 10, 10)
+	assert.ErrorIs(t, err, domain.ErrServiceUnavailable)
+
+	// Background jobs are never served cached copies
+	_, err = repo.GetByID(background, "m1")
+	assert.ErrorIs(t, err, domain.ErrServiceUnavailable)
+
+	// Nor are copies older than the maximum age
+	repo.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = repo.GetByID(ctx, "m1")
+	assert.ErrorIs(t, err, domain.ErrServiceUnavailable)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// fallbackSearchRepository searches a fallback index while the primary one is unavailable
+type fallbackSearchRepository struct {
+	SearchRepository
+	fallback   SearchRepository
+	retryAfter time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	downUntil time.Time // the primary index is not tried before
+}
+
+// NewFallbackSearchRepository wraps primary so that searches and suggestions
+// are served by fallback, marking the request context degraded, when primary
+// fails with domain.ErrServiceUnavailable. primary is tried again retryAfter
+// later. Both indexes are updated so the fallback stays current; listing the
+// index reads primary only.
+func NewFallbackSearchRepository(primary, fallback SearchRepository, retryAfter time.Duration) SearchRepository {
+	return &fallbackSearchRepository{
+		SearchRepository: primary,
+		fallback:         fallback,
+		retryAfter:       retryAfter,
+		now:              time.Now,
+	}
+}
+
+// Search performs full-text search on indexed media
+func (r *fallbackSearchRepository) Search(ctx context.Context, req *domain.SearchRequest) ([]*domain.SearchResult, int64, error) {
+	if r.primaryAvailable() {
+		results, total, err := r.SearchRepository.Search(ctx, req)
+		if !r.failedOver(err) {
+			return results, total, err
+		}
+	}
+
+	domain.MarkDegraded(ctx)
+	return r.fallback.Search(ctx, req)
+}
+
+// Suggest provides search suggestions based on query
+func (r *fallbackSearchRepository) Suggest(ctx context.Context, req *domain.SuggestRequest) ([]*domain.Suggestion, error) {
+	if r.primaryAvailable() {
+		suggestions, err := r.SearchRepository.Suggest(ctx, req)
+		if !r.failedOver(err) {
+			return suggestions, err
+		}
+	}
+
+	domain.MarkDegraded(ctx)
+	return r.fallback.Suggest(ctx, req)
+}
+
+// IndexMedia adds or updates media in both indexes
+func (r *fallbackSearchRepository) IndexMedia(ctx context.Context, media *domain.Media) error {
+	if err := r.fallback.IndexMedia(ctx, media); err != nil {
+		log.Printf("Failed to index media %s in the fallback search index: %v", media.ID, err)
+	}
+	return r.SearchRepository.IndexMedia(ctx, media)
+}
+
+// RemoveFromIndex removes media from both indexes
+func (r *fallbackSearchRepository) RemoveFromIndex(ctx context.Context, mediaID string) error {
+	if err := r.fallback.RemoveFromIndex(ctx, mediaID); err != nil {
+		log.Printf("Failed to remove media %s from the fallback search index: %v", mediaID, err)
+	}
+	return r.SearchRepository.RemoveFromIndex(ctx, mediaID)
+}
+
+// ReindexAll rebuilds both indexes
+func (r *fallbackSearchRepository) ReindexAll(ctx context.Context, mediaList []*domain.Media) error {
+	if err := r.fallback.ReindexAll(ctx, mediaList); err != nil {
+		log.Printf("Failed to rebuild the fallback search index: %v", err)
+	}
+	return r.SearchRepository.ReindexAll(ctx, mediaList)
+}
+
+// primaryAvailable returns false while the primary index is skipped after a failure
+func (r *fallbackSearchRepository) primaryAvailable() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.now().Before(r.downUntil)
+}
+
+// failedOver returns true if err is an unavailable primary index, which is then skipped for retryAfter
+func (r *fallbackSearchRepository) failedOver(err error) bool {
+	if !errors.Is(err, domain.ErrServiceUnavailable) {
+		return false
+	}
+
+	log.Printf("Search index unavailable, searching the fallback index for %s: %v", r.retryAfter, err)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.downUntil = r.now().Add(r.retryAfter)
+	return true
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableSearchRepository fails every search while down, like an unreachable cluster
+type unavailableSearchRepository struct {
+	SearchRepository
+	down     bool
+	searches int
+}
+
+func (r *unavailableSearchRepository) Search(ctx context.Context, req *domain.SearchRequest) ([]*domain.SearchResult, int64, error) {
+	r.searches++
+	if r.down {
+		return nil, 0, fmt.Errorf("%w: 503 Service Unavailable", domain.ErrServiceUnavailable)
+	}
+	return r.SearchRepository.Search(ctx, req)
+}
+
+func TestFallbackSearchRepository(t *testing.T) {
+	// Given media indexed through the fallback repository
+	ctx, degradation := domain.ContextWithDegradation(context.Background())
+	primary := &unavailableSearchRepository{SearchRepository: NewInMemorySearchRepository()}
+	fallback := NewInMemorySearchRepository()
+	repo := NewFallbackSearchRepository(primary, fallback, time.Minute).(*fallbackSearchRepository)
+	require.NoError(t, repo.ReindexAll(ctx, []*domain.Media{{ID: "m1", Title: "Golang Concurrency"}}))
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m2", Title: "Golang Generics"}))
+	require.NoError(t, repo.RemoveFromIndex(ctx, "m1"))
+
+	// When the primary index is up
+	results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+
+	// Then it serves the search
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "m2", results[0].Media.ID)
+	assert.False(t, degradation.Degraded())
+
+	// When the primary index goes down
+	primary.down = true
+	results, total, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+
+	// Then the fallback index, kept up to date, serves the search degraded
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "m2", results[0].Media.ID)
+	assert.True(t, degradation.Degraded())
+
+	// And the primary index is skipped until it is retried
+	_, _, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, primary.searches)
+
+	primary.down = false
+	repo.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, _, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, primary.searches)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// FeaturedItemRepository defines the contract for editor's pick data access
+type FeaturedItemRepository interface {
+	// Create creates a new featured item
+	Create(ctx context.Context, item *domain.FeaturedItem) error
+
+	// GetByID retrieves a featured item by ID
+	GetByID(ctx context.Context, id string) (*domain.FeaturedItem, error)
+
+	// GetAll retrieves every featured item, including scheduled and ended ones, in position order
+	GetAll(ctx context.Context) ([]*domain.FeaturedItem, error)
+
+	// GetActive retrieves the items shown at now in position order
+	GetActive(ctx context.Context, now time.Time, limit int) ([]*domain.FeaturedItem, error)
+
+	// Update replaces a featured item
+	Update(ctx context.Context, item *domain.FeaturedItem) error
+
+	// Delete removes a featured item by ID
+	Delete(ctx context.Context, id string) error
+}
+
+// postgresFeaturedItemRepository implements FeaturedItemRepository using PostgreSQL
+type postgresFeaturedItemRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresFeaturedItemRepository creates a new PostgreSQL featured item repository
+func NewPostgresFeaturedItemRepository(conn *database.Connection) FeaturedItemRepository {
+	return &postgresFeaturedItemRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new featured item
+func (r *postgresFeaturedItemRepository) Create(ctx context.Context, item *domain.FeaturedItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// GetByID retrieves a featured item by ID
+func (r *postgresFeaturedItemRepository) GetByID(ctx context.Context, id string) (*domain.FeaturedItem, error) {
+	var item domain.FeaturedItem
+	err := r.db.WithContext(ctx).First(&item, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrFeaturedItemNotFound
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// GetAll retrieves every featured item, including scheduled and ended ones, in position order
+func (r *postgresFeaturedItemRepository) GetAll(ctx context.Context) ([]*domain.FeaturedItem, error) {
+	return r.find(r.db.WithContext(ctx))
+}
+
+// GetActive retrieves the items shown at now in position order
+func (r *postgresFeaturedItemRepository) GetActive(ctx context.Context, now time.Time, limit int) ([]*domain.FeaturedItem, error) {
+	return r.find(r.db.WithContext(ctx).
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Limit(limit))
+}
+
+// Update replaces a featured item
+func (r *postgresFeaturedItemRepository) Update(ctx context.Context, item *domain.FeaturedItem) error {
+	// Select forces cleared schedules to be written
+	result := r.db.WithContext(ctx).
+		Model(&domain.FeaturedItem{}).
+		Where("id = ?", item.ID).
+		Select("media_id", "position", "starts_at", "ends_at", "updated_at").
+		Updates(item)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrFeaturedItemNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a featured item by ID
+func (r *postgresFeaturedItemRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&domain.FeaturedItem{}, "id = ?", id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrFeaturedItemNotFound
+	}
+
+	return nil
+}
+
+// find runs a featured item query in position order, oldest first among equal positions
+func (r *postgresFeaturedItemRepository) find(query *gorm.DB) ([]*domain.FeaturedItem, error) {
+	var items []domain.FeaturedItem
+	if err := query.Order("position ASC").Order("created_at ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.FeaturedItem, len(items))
+	for i := range items {
+		result[i] = &items[i]
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeaturedItemRepositoryInterface ensures the mock satisfies the FeaturedItemRepository interface
+func TestFeaturedItemRepositoryInterface(t *testing.T) {
+	var _ FeaturedItemRepository = (*MockFeaturedItemRepository)(nil)
+}
+
+// MockFeaturedItemRepository can be used in tests
+type MockFeaturedItemRepository struct{}
+
+func (m *MockFeaturedItemRepository) Create(ctx context.Context, item *domain.FeaturedItem) error {
+	return nil
+}
+
+func (m *MockFeaturedItemRepository) GetByID(ctx context.Context, id string) (*domain.FeaturedItem, error) {
+	return nil, domain.ErrFeaturedItemNotFound
+}
+
+func (m *MockFeaturedItemRepository) GetAll(ctx context.Context) ([]*domain.FeaturedItem, error) {
+	return nil, nil
+}
+
+func (m *MockFeaturedItemRepository) GetActive(ctx context.Context, now time.Time, limit int) ([]*domain.FeaturedItem, error) {
+	return nil, nil
+}
+
+func (m *MockFeaturedItemRepository) Update(ctx context.Context, item *domain.FeaturedItem) error {
+	return nil
+}
+
+func (m *MockFeaturedItemRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestFeaturedItemRepository_Schedules(t *testing.T) {
+	// Given picks that are active, scheduled and ended
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresFeaturedItemRepository(conn)
+
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	require.NoError(t, repo.Create(ctx, &domain.FeaturedItem{ID: "second", MediaID: "m2", Position: 2}))
+	require.NoError(t, repo.Create(ctx, &domain.FeaturedItem{ID: "first", MediaID: "m1", Position: 1, StartsAt: &past, EndsAt: &future}))
+	require.NoError(t, repo.Create(ctx, &domain.FeaturedItem{ID: "scheduled", MediaID: "m3", StartsAt: &future}))
+	require.NoError(t, repo.Create(ctx, &domain.FeaturedItem{ID: "ended", MediaID: "m4", EndsAt: &past}))
+
+	// When
+	active, err := repo.GetActive(ctx, now, 10)
+	require.NoError(t, err)
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+
+	// Then only the active picks are listed, in position order
+	require.Len(t, active, 2)
+	assert.Equal(t, "first", active[0].ID)
+	assert.Equal(t, "second", active[1].ID)
+	assert.Len(t, all, 4)
+
+	// And clearing the schedule of a pick shows it right away
+	scheduled, err := repo.GetByID(ctx, "scheduled")
+	require.NoError(t, err)
+	scheduled.StartsAt = nil
+	require.NoError(t, repo.Update(ctx, scheduled))
+	active, err = repo.GetActive(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Len(t, active, 3)
+
+	require.NoError(t, repo.Delete(ctx, "scheduled"))
+	assert.ErrorIs(t, repo.Delete(ctx, "scheduled"), domain.ErrFeaturedItemNotFound)
+	_, err = repo.GetByID(ctx, "scheduled")
+	assert.ErrorIs(t, err, domain.ErrFeaturedItemNotFound)
+}
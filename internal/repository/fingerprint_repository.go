@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FingerprintRepository defines the contract for media fingerprint data access
+type FingerprintRepository interface {
+	// Save creates or replaces the fingerprint of a media item
+	Save(ctx context.Context, fingerprint *domain.MediaFingerprint) error
+
+	// FindCandidates retrieves the fingerprints of a tenant's media that is not deleted,
+	// other than mediaID, whose duration is within [minDuration, maxDuration], oldest first
+	FindCandidates(ctx context.Context, tenantID, mediaID string, minDuration, maxDuration, limit int) ([]*domain.MediaFingerprint, error)
+}
+
+// postgresFingerprintRepository implements FingerprintRepository using PostgreSQL
+type postgresFingerprintRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresFingerprintRepository creates a new PostgreSQL fingerprint repository
+func NewPostgresFingerprintRepository(conn *database.Connection) FingerprintRepository {
+	return &postgresFingerprintRepository{
+		db: conn.DB,
+	}
+}
+
+// Save creates or replaces the fingerprint of a media item
+func (r *postgresFingerprintRepository) Save(ctx context.Context, fingerprint *domain.MediaFingerprint) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tenant_id", "duration", "fingerprint"}),
+	}).Create(fingerprint).Error
+}
+
+// FindCandidates retrieves the fingerprints the fingerprint of mediaID may be a near-duplicate of
+func (r *postgresFingerprintRepository) FindCandidates(ctx context.Context, tenantID, mediaID string, minDuration, maxDuration, limit int) ([]*domain.MediaFingerprint, error) {
+	var fingerprints []*domain.MediaFingerprint
+	err := r.db.WithContext(ctx).
+		Joins("JOIN media_files ON media_files.id = media_fingerprints.media_id").
+		Where("media_fingerprints.tenant_id = ? AND media_fingerprints.media_id <> ?", tenantID, mediaID).
+		Where("media_fingerprints.duration BETWEEN ? AND ?", minDuration, maxDuration).
+		Where("media_files.status <> ?", string(domain.StatusDeleted)).
+		Order("media_files.created_at ASC, media_fingerprints.media_id ASC").
+		Limit(limit).
+		Find(&fingerprints).Error
+	return fingerprints, err
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFingerprintRepositoryInterface ensures the mock satisfies the FingerprintRepository interface
+func TestFingerprintRepositoryInterface(t *testing.T) {
+	var _ FingerprintRepository = (*MockFingerprintRepository)(nil)
+}
+
+// MockFingerprintRepository can be used in tests
+type MockFingerprintRepository struct{}
+
+func (m *MockFingerprintRepository) Save(ctx context.Context, fingerprint *domain.MediaFingerprint) error {
+	return nil
+}
+
+func (m *MockFingerprintRepository) FindCandidates(ctx context.Context, tenantID, mediaID string, minDuration, maxDuration, limit int) ([]*domain.MediaFingerprint, error) {
+	return nil, nil
+}
+
+func TestFingerprintRepository_SaveAndFindCandidates(t *testing.T) {
+	// Given fingerprinted media of two tenants, one deleted and one much longer
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresFingerprintRepository(conn)
+
+	created := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	for i, media := range []*domain.Media{
+		{ID: "m1", Title: "Episode", TenantID: "acme", Status: domain.StatusReady},
+		{ID: "m2", Title: "Re-encoded episode", TenantID: "acme", Status: domain.StatusProcessing},
+		{ID: "m3", Title: "Deleted episode", TenantID: "acme", Status: domain.StatusDeleted},
+		{ID: "m4", Title: "Long episode", TenantID: "acme", Status: domain.StatusReady},
+		{ID: "m5", Title: "Other tenant", TenantID: "globex", Status: domain.StatusReady},
+		{ID: "m6", Title: "Earlier episode", TenantID: "acme", Status: domain.StatusReady},
+	} {
+		media.CreatedAt = created.Add(time.Duration(i) * time.Hour)
+		if media.ID == "m6" {
+			media.CreatedAt = created.Add(-time.Hour)
+		}
+		require.NoError(t, conn.DB.Create(media).Error)
+	}
+	for _, fingerprint := range []*domain.MediaFingerprint{
+		{MediaID: "m1", TenantID: "acme", Duration: 1800, Fingerprint: []uint32{1, 2, 3}},
+		{MediaID: "m2", TenantID: "acme", Duration: 1790, Fingerprint: []uint32{1, 2, 4}},
+		{MediaID: "m3", TenantID: "acme", Duration: 1800, Fingerprint: []uint32{1, 2, 3}},
+		{MediaID: "m4", TenantID: "acme", Duration: 3600, Fingerprint: []uint32{1, 2, 3}},
+		{MediaID: "m5", TenantID: "globex", Duration: 1800, Fingerprint: []uint32{1, 2, 3}},
+		{MediaID: "m6", TenantID: "acme", Duration: 1700, Fingerprint: []uint32{9}},
+	} {
+		require.NoError(t, repo.Save(ctx, fingerprint))
+	}
+
+	// When the fingerprint of m1 is replaced
+	require.NoError(t, repo.Save(ctx, &domain.MediaFingerprint{MediaID: "m1", TenantID: "acme", Duration: 1800, Fingerprint: []uint32{7, 8}}))
+	candidates, err := repo.FindCandidates(ctx, "acme", "m2", 1611, 1969, 10)
+
+	// Then only the live media of the tenant within the durations is found, oldest first
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "m6", candidates[0].MediaID)
+	assert.Equal(t, "m1", candidates[1].MediaID)
+	assert.Equal(t, []uint32{7, 8}, candidates[1].Fingerprint)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// HistoryRepository defines the contract for listening history data access
+type HistoryRepository interface {
+	// Create records a play event
+	Create(ctx context.Context, event *domain.PlayEvent) error
+
+	// GetByUser retrieves the play events of a user matching filter, most recent first
+	GetByUser(ctx context.Context, userID string, filter *domain.HistoryFilter, limit, offset int) ([]*domain.PlayEvent, error)
+
+	// CountByUser counts the play events of a user matching filter
+	CountByUser(ctx context.Context, userID string, filter *domain.HistoryFilter) (int64, error)
+
+	// DeleteByUser removes every play event of a user
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// postgresHistoryRepository implements HistoryRepository using PostgreSQL
+type postgresHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresHistoryRepository creates a new PostgreSQL listening history repository
+func NewPostgresHistoryRepository(conn *database.Connection) HistoryRepository {
+	return &postgresHistoryRepository{
+		db: conn.DB,
+	}
+}
+
+// Create records a play event
+func (r *postgresHistoryRepository) Create(ctx context.Context, event *domain.PlayEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// GetByUser retrieves the play events of a user matching filter, most recent first
+func (r *postgresHistoryRepository) GetByUser(ctx context.Context, userID string, filter *domain.HistoryFilter, limit, offset int) ([]*domain.PlayEvent, error) {
+	var events []domain.PlayEvent
+	err := r.filtered(ctx, userID, filter).
+		Order("played_at DESC").
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.PlayEvent, len(events))
+	for i := range events {
+		result[i] = &events[i]
+	}
+
+	return result, nil
+}
+
+// CountByUser counts the play events of a user matching filter
+func (r *postgresHistoryRepository) CountByUser(ctx context.Context, userID string, filter *domain.HistoryFilter) (int64, error) {
+	var count int64
+	err := r.filtered(ctx, userID, filter).Count(&count).Error
+	return count, err
+}
+
+// DeleteByUser removes every play event of a user
+func (r *postgresHistoryRepository) DeleteByUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Delete(&domain.PlayEvent{}, "user_id = ?", userID).Error
+}
+
+// filtered scopes a play event query to the events of a user matching filter
+func (r *postgresHistoryRepository) filtered(ctx context.Context, userID string, filter *domain.HistoryFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.PlayEvent{}).Where("user_id = ?", userID)
+
+	if filter != nil {
+		if filter.Type != "" {
+			query = query.Where("type = ?", filter.Type)
+		}
+		if filter.From != nil {
+			query = query.Where("played_at >= ?", *filter.From)
+		}
+		if filter.End != nil {
+			query = query.Where("played_at < ?", *filter.End)
+		}
+	}
+
+	return query
+}
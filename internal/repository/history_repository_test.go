@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHistoryRepositoryInterface ensures the mock satisfies the HistoryRepository interface
+func TestHistoryRepositoryInterface(t *testing.T) {
+	var _ HistoryRepository = (*MockHistoryRepository)(nil)
+}
+
+// MockHistoryRepository can be used in tests
+type MockHistoryRepository struct{}
+
+func (m *MockHistoryRepository) Create(ctx context.Context, event *domain.PlayEvent) error {
+	return nil
+}
+
+func (m *MockHistoryRepository) GetByUser(ctx context.Context, userID string, filter *domain.HistoryFilter, limit, offset int) ([]*domain.PlayEvent, error) {
+	return nil, nil
+}
+
+func (m *MockHistoryRepository) CountByUser(ctx context.Context, userID string, filter *domain.HistoryFilter) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockHistoryRepository) DeleteByUser(ctx context.Context, userID string) error {
+	return nil
+}
+
+func TestHistoryRepository_Filters(t *testing.T) {
+	// Given plays of two users over two days
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresHistoryRepository(conn)
+
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, event := range []*domain.PlayEvent{
+		{ID: "e1", UserID: "u1", MediaID: "m1", Type: domain.TypePodcast, PlayedAt: day.Add(time.Hour)},
+		{ID: "e2", UserID: "u1", MediaID: "m2", Type: domain.TypeVideo, PlayedAt: day.Add(2 * time.Hour)},
+		{ID: "e3", UserID: "u1", MediaID: "m1", Type: domain.TypePodcast, PlayedAt: day.Add(25 * time.Hour)},
+		{ID: "e4", UserID: "u2", MediaID: "m1", Type: domain.TypePodcast, PlayedAt: day.Add(time.Hour)},
+	} {
+		require.NoError(t, repo.Create(ctx, event))
+	}
+
+	// When
+	all, err := repo.GetByUser(ctx, "u1", &domain.HistoryFilter{}, 10, 0)
+	require.NoError(t, err)
+	end := day.Add(24 * time.Hour)
+	filter := &domain.HistoryFilter{Type: domain.TypePodcast, From: &day, End: &end}
+	podcasts, err := repo.GetByUser(ctx, "u1", filter, 10, 0)
+	require.NoError(t, err)
+	count, err := repo.CountByUser(ctx, "u1", filter)
+	require.NoError(t, err)
+
+	// Then the plays of the user are listed most recent first
+	require.Len(t, all, 3)
+	assert.Equal(t, []string{"e3", "e2", "e1"}, []string{all[0].ID, all[1].ID, all[2].ID})
+	require.Len(t, podcasts, 1)
+	assert.Equal(t, "e1", podcasts[0].ID)
+	assert.Equal(t, int64(1), count)
+
+	// And clearing the history of a user leaves the others alone
+	require.NoError(t, repo.DeleteByUser(ctx, "u1"))
+	count, err = repo.CountByUser(ctx, "u1", nil)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = repo.CountByUser(ctx, "u2", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
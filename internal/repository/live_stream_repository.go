@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// LiveStreamRepository defines the contract for live stream data access
+type LiveStreamRepository interface {
+	// Create creates a new live stream
+	Create(ctx context.Context, stream *domain.LiveStream) error
+
+	// GetByMediaID retrieves the live stream of a media item
+	GetByMediaID(ctx context.Context, mediaID string) (*domain.LiveStream, error)
+
+	// GetByStreamKeyHash retrieves a live stream by the hash of its stream key
+	GetByStreamKeyHash(ctx context.Context, keyHash string) (*domain.LiveStream, error)
+
+	// Transition writes the status, times and recording of a live stream if it
+	// is still in one of the from statuses, reporting whether it was
+	Transition(ctx context.Context, stream *domain.LiveStream, from ...domain.LiveStatus) (bool, error)
+}
+
+// postgresLiveStreamRepository implements LiveStreamRepository using PostgreSQL
+type postgresLiveStreamRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresLiveStreamRepository creates a new PostgreSQL live stream repository
+func NewPostgresLiveStreamRepository(conn *database.Connection) LiveStreamRepository {
+	return &postgresLiveStreamRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new live stream
+func (r *postgresLiveStreamRepository) Create(ctx context.Context, stream *domain.LiveStream) error {
+	return r.db.WithContext(ctx).Create(stream).Error
+}
+
+// GetByMediaID retrieves the live stream of a media item
+func (r *postgresLiveStreamRepository) GetByMediaID(ctx context.Context, mediaID string) (*domain.LiveStream, error) {
+	return r.first(ctx, "media_id = ?", mediaID)
+}
+
+// GetByStreamKeyHash retrieves a live stream by the hash of its stream key
+func (r *postgresLiveStreamRepository) GetByStreamKeyHash(ctx context.Context, keyHash string) (*domain.LiveStream, error) {
+	return r.first(ctx, "stream_key_hash = ?", keyHash)
+}
+
+// Transition writes the state of a live stream still in one of the from statuses
+func (r *postgresLiveStreamRepository) Transition(ctx context.Context, stream *domain.LiveStream, from ...domain.LiveStatus) (bool, error) {
+	// Select writes the times and recording even when they are empty
+	result := r.db.WithContext(ctx).
+		Model(&domain.LiveStream{}).
+		Where("media_id = ? AND status IN ?", stream.MediaID, from).
+		Select("status", "started_at", "ended_at", "recording_key", "updated_at").
+		Updates(stream)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// first retrieves the first live stream matching the condition
+func (r *postgresLiveStreamRepository) first(ctx context.Context, query string, args ...interface{}) (*domain.LiveStream, error) {
+	var stream domain.LiveStream
+	err := r.db.WithContext(ctx).Where(query, args...).First(&stream).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrLiveStreamNotFound
+		}
+		return nil, err
+	}
+
+	return &stream, nil
+}
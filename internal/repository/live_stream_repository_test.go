@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLiveStreamRepositoryInterface ensures the mock satisfies the LiveStreamRepository interface
+func TestLiveStreamRepositoryInterface(t *testing.T) {
+	var _ LiveStreamRepository = (*MockLiveStreamRepository)(nil)
+}
+
+// MockLiveStreamRepository can be used in tests
+type MockLiveStreamRepository struct{}
+
+func (m *MockLiveStreamRepository) Create(ctx context.Context, stream *domain.LiveStream) error {
+	return nil
+}
+
+func (m *MockLiveStreamRepository) GetByMediaID(ctx context.Context, mediaID string) (*domain.LiveStream, error) {
+	return nil, domain.ErrLiveStreamNotFound
+}
+
+func (m *MockLiveStreamRepository) GetByStreamKeyHash(ctx context.Context, keyHash string) (*domain.LiveStream, error) {
+	return nil, domain.ErrLiveStreamNotFound
+}
+
+func (m *MockLiveStreamRepository) Transition(ctx context.Context, stream *domain.LiveStream, from ...domain.LiveStatus) (bool, error) {
+	return false, nil
+}
+
+func TestLiveStreamRepository_Transition(t *testing.T) {
+	// Given a scheduled live stream
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresLiveStreamRepository(conn)
+
+	require.NoError(t, repo.Create(ctx, &domain.LiveStream{
+		MediaID:       "live-1",
+		Status:        domain.LiveScheduled,
+		StreamKeyHash: domain.HashStreamKey("secret"),
+		ArchiveType:   domain.TypeVideo,
+	}))
+
+	// When the encoder starts publishing
+	stream, err := repo.GetByStreamKeyHash(ctx, domain.HashStreamKey("secret"))
+	require.NoError(t, err)
+	startedAt := time.Now().UTC()
+	stream.Status = domain.LiveOnAir
+	stream.StartedAt = &startedAt
+	ok, err := repo.Transition(ctx, stream, domain.LiveScheduled)
+
+	// Then the stream is live
+	require.NoError(t, err)
+	assert.True(t, ok)
+	stored, err := repo.GetByMediaID(ctx, "live-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveOnAir, stored.Status)
+	require.NotNil(t, stored.StartedAt)
+
+	// And a stream no longer scheduled is left as is
+	stream.Status = domain.LiveEnded
+	ok, err = repo.Transition(ctx, stream, domain.LiveScheduled)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = repo.GetByStreamKeyHash(ctx, domain.HashStreamKey("other"))
+	assert.ErrorIs(t, err, domain.ErrLiveStreamNotFound)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// maintenanceStatusID is the ID of the row holding the maintenance status
+const maintenanceStatusID = 1
+
+// MaintenanceRepository defines the contract for the stored maintenance mode
+type MaintenanceRepository interface {
+	// Get retrieves the maintenance status, the zero status when it was never set
+	Get(ctx context.Context) (*domain.MaintenanceStatus, error)
+
+	// Save stores the maintenance status
+	Save(ctx context.Context, status *domain.MaintenanceStatus) error
+}
+
+// postgresMaintenanceRepository implements MaintenanceRepository using PostgreSQL
+type postgresMaintenanceRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresMaintenanceRepository creates a new PostgreSQL maintenance repository
+func NewPostgresMaintenanceRepository(conn *database.Connection) MaintenanceRepository {
+	return &postgresMaintenanceRepository{
+		db: conn.DB,
+	}
+}
+
+// Get retrieves the maintenance status, the zero status when it was never set
+func (r *postgresMaintenanceRepository) Get(ctx context.Context) (*domain.MaintenanceStatus, error) {
+	var status domain.MaintenanceStatus
+	err := r.db.WithContext(ctx).First(&status, maintenanceStatusID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &domain.MaintenanceStatus{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance status: %w", err)
+	}
+	return &status, nil
+}
+
+// Save stores the maintenance status
+func (r *postgresMaintenanceRepository) Save(ctx context.Context, status *domain.MaintenanceStatus) error {
+	stored := *status
+	stored.ID = maintenanceStatusID
+	if err := r.db.WithContext(ctx).Save(&stored).Error; err != nil {
+		return fmt.Errorf("failed to save maintenance status: %w", err)
+	}
+	return nil
+}
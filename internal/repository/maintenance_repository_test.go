@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceRepository(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, conn.DB.AutoMigrate(&domain.MaintenanceStatus{}))
+	repo := NewPostgresMaintenanceRepository(conn)
+
+	// Never switched
+	status, err := repo.Get(ctx)
+	require.NoError(t, err)
+	assert.False(t, status.ReadOnly)
+
+	// Switched on, then off
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Save(ctx, &domain.MaintenanceStatus{ReadOnly: true, Reason: "migration", Since: &since}))
+	status, err = repo.Get(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.ReadOnly)
+	assert.Equal(t, "migration", status.Reason)
+	assert.True(t, since.Equal(*status.Since))
+
+	require.NoError(t, repo.Save(ctx, &domain.MaintenanceStatus{}))
+	status, err = repo.Get(ctx)
+	require.NoError(t, err)
+	assert.False(t, status.ReadOnly)
+	assert.Nil(t, status.Since)
+
+	var rows int64
+	require.NoError(t, conn.DB.Model(&domain.MaintenanceStatus{}).Count(&rows).Error)
+	assert.Equal(t, int64(1), rows)
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"thamaniyah/internal/domain"
 )
 
@@ -13,21 +15,120 @@ type MediaRepository interface {
 	// GetByID retrieves a media record by ID
 	GetByID(ctx context.Context, id string) (*domain.Media, error)
 
+	// GetByIDs retrieves the existing media records among ids
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Media, error)
+
 	// GetAll retrieves all media records with pagination
 	GetAll(ctx context.Context, limit, offset int) ([]*domain.Media, error)
 
+	// GetAllWithTotal retrieves a page of media records together with the total count in one query
+	GetAllWithTotal(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error)
+
 	// Update updates an existing media record
 	Update(ctx context.Context, media *domain.Media) error
 
-	// Delete soft deletes a media record by ID
+	// Delete soft deletes a media record by ID, moving it to the trash.
+	// Media in the trash is only returned by GetTrashed.
 	Delete(ctx context.Context, id string) error
 
+	// GetTrashed retrieves media deleted at or before deletedBefore, longest deleted first
+	GetTrashed(ctx context.Context, deletedBefore time.Time, limit int) ([]*domain.Media, error)
+
+	// Purge permanently removes a media record from the trash
+	Purge(ctx context.Context, id string) error
+
+	// GetByIDsWithTrashed retrieves the media records among ids, those in the trash included
+	GetByIDsWithTrashed(ctx context.Context, ids []string) ([]*domain.Media, error)
+
+	// GetByOwner retrieves a page of the media records a user owns, newest first,
+	// together with their total count
+	GetByOwner(ctx context.Context, ownerID string, limit, offset int) ([]*domain.Media, int64, error)
+
 	// GetByStatus retrieves media records by status
 	GetByStatus(ctx context.Context, status domain.MediaStatus, limit, offset int) ([]*domain.Media, error)
 
+	// GetByFilter retrieves media records matching the filter
+	GetByFilter(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, error)
+
 	// UpdateStatus updates only the status of a media record
 	UpdateStatus(ctx context.Context, id string, status domain.MediaStatus) error
 
+	// UpdateFailure sets or clears the failure reason of a media record
+	UpdateFailure(ctx context.Context, id, code, message string) error
+
+	// UpdateGeoRestriction replaces the country rules of a media record
+	UpdateGeoRestriction(ctx context.Context, id string, restriction domain.GeoRestriction) error
+
+	// UpdateContentRating replaces the age rating and explicit flag of a media record
+	UpdateContentRating(ctx context.Context, id string, rating domain.ContentRating) error
+
+	// UpdateLicense replaces the rights window of a media record
+	UpdateLicense(ctx context.Context, id string, license domain.License) error
+
+	// UpdateClassification replaces the labels and category of a media record
+	UpdateClassification(ctx context.Context, id string, labels []string, category string) error
+
+	// UpdateCuePoints replaces the ad breaks of a media record
+	UpdateCuePoints(ctx context.Context, id string, cuePoints []domain.CuePoint) error
+
+	// UpdateChapters replaces the chapters and chapter suggestions of a media record
+	UpdateChapters(ctx context.Context, id string, chapters []domain.Chapter, suggestions []domain.ChapterSuggestion) error
+
+	// UpdateAudioTracks replaces the dubbed audio tracks of a media record
+	UpdateAudioTracks(ctx context.Context, id string, tracks []domain.AudioTrack) error
+
+	// UpdateSeries links a media record to a series part, or unlinks it given an empty series ID.
+	// Locked records are left alone by series detection.
+	UpdateSeries(ctx context.Context, id, seriesID string, part int, locked bool) error
+
+	// GetBySeries retrieves the media of a series that is not deleted, in part order
+	GetBySeries(ctx context.Context, seriesID string) ([]*domain.Media, error)
+
+	// UpdateVisibility changes only the visibility of a media record
+	UpdateVisibility(ctx context.Context, id string, visibility domain.MediaVisibility) error
+
+	// UpdateUnpublishAt schedules or, given nil, cancels the unpublishing of a media record
+	UpdateUnpublishAt(ctx context.Context, id string, unpublishAt *time.Time) error
+
+	// GetDueUnpublishes retrieves published media scheduled to be unpublished at or before now
+	GetDueUnpublishes(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error)
+
+	// UpdatePremiere replaces the premiere of a media record
+	UpdatePremiere(ctx context.Context, id string, premiere domain.Premiere) error
+
+	// UpdatePoster replaces the poster of a media record
+	UpdatePoster(ctx context.Context, id string, poster domain.Poster) error
+
+	// GetDuePremieres retrieves published media whose premiere started at or before now
+	// and was not announced yet
+	GetDuePremieres(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error)
+
+	// GetScheduledBetween retrieves media that is not deleted and whose license starts or
+	// ends, or which is unpublished or premieres, within [from, end)
+	GetScheduledBetween(ctx context.Context, from, end time.Time) ([]*domain.Media, error)
+
+	// GetExpiredLicenses retrieves published media whose license ended at or before now
+	GetExpiredLicenses(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error)
+
+	// GetLicensesExpiringBefore retrieves published media whose license ends after now and at
+	// or before the deadline, and whose owners were not warned yet
+	GetLicensesExpiringBefore(ctx context.Context, now, deadline time.Time, limit int) ([]*domain.Media, error)
+
+	// IncrementProcessingAttempts atomically bumps the processing attempt counter
+	IncrementProcessingAttempts(ctx context.Context, id string) error
+
 	// GetTotal returns the total count of media records
 	GetTotal(ctx context.Context) (int64, error)
+
+	// GetEstimatedTotal returns an approximate count of media records without scanning them
+	GetEstimatedTotal(ctx context.Context) (int64, error)
+
+	// GetTotalByFilter returns the count of media records matching the filter
+	GetTotalByFilter(ctx context.Context, filter *domain.MediaFilter) (int64, error)
+
+	// GetStorageUsage returns the total file size of the media of a tenant that is not deleted
+	GetStorageUsage(ctx context.Context, tenantID string) (int64, error)
+
+	// GetByContentHash retrieves a media record of a tenant that is not deleted by its content hash
+	GetByContentHash(ctx context.Context, tenantID, contentHash string) (*domain.Media, error)
 }
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+)
+
+// benchmarkSettings compares the default GORM settings with the tuned ones
+var benchmarkSettings = []struct {
+	name  string
+	tuned bool
+}{
+	{name: "default", tuned: false},
+	{name: "tuned", tuned: true},
+}
+
+// newBenchmarkMediaRepository opens a migrated SQLite database holding count media
+func newBenchmarkMediaRepository(b *testing.B, tuned bool, count int) MediaRepository {
+	b.Helper()
+
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		SQLitePath:             filepath.Join(b.TempDir(), "bench.db"),
+		PrepareStmt:            tuned,
+		SkipDefaultTransaction: tuned,
+	}}
+	conn, err := database.NewSQLiteConnection(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { conn.Close() })
+	if err := database.SimpleAutoMigrate(conn.DB); err != nil {
+		b.Fatal(err)
+	}
+	if err := database.CreateIndexes(conn.DB); err != nil {
+		b.Fatal(err)
+	}
+
+	repo := NewSQLiteMediaRepository(conn)
+	created := time.Now()
+	for i := 0; i < count; i++ {
+		media := &domain.Media{
+			ID:        fmt.Sprintf("media-%d", i),
+			Title:     fmt.Sprintf("Episode %d", i),
+			Type:      domain.TypePodcast,
+			Status:    domain.StatusReady,
+			CreatedAt: created.Add(time.Duration(i) * time.Second),
+		}
+		if err := repo.Create(context.Background(), media); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func BenchmarkMediaRepository_GetByID(b *testing.B) {
+	for _, settings := range benchmarkSettings {
+		b.Run(settings.name, func(b *testing.B) {
+			repo := newBenchmarkMediaRepository(b, settings.tuned, 1000)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetByID(ctx, fmt.Sprintf("media-%d", i%1000)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMediaRepository_GetAll(b *testing.B) {
+	for _, settings := range benchmarkSettings {
+		b.Run(settings.name, func(b *testing.B) {
+			repo := newBenchmarkMediaRepository(b, settings.tuned, 1000)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetAll(ctx, 20, (i%50)*20); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMediaRepository_UpdateStatus(b *testing.B) {
+	for _, settings := range benchmarkSettings {
+		b.Run(settings.name, func(b *testing.B) {
+			repo := newBenchmarkMediaRepository(b, settings.tuned, 1000)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := repo.UpdateStatus(ctx, fmt.Sprintf("media-%d", i%1000), domain.StatusReady); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"thamaniyah/internal/domain"
 )
@@ -37,14 +38,138 @@ func (m *MockMediaRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *MockMediaRepository) GetTrashed(ctx context.Context, deletedBefore time.Time, limit int) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) Purge(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *MockMediaRepository) GetByIDsWithTrashed(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	return nil, nil
+}
+
 func (m *MockMediaRepository) GetByStatus(ctx context.Context, status domain.MediaStatus, limit, offset int) ([]*domain.Media, error) {
 	return nil, nil
 }
 
+func (m *MockMediaRepository) GetByFilter(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, error) {
+	return nil, nil
+}
+
 func (m *MockMediaRepository) UpdateStatus(ctx context.Context, id string, status domain.MediaStatus) error {
 	return nil
 }
 
+func (m *MockMediaRepository) UpdateFailure(ctx context.Context, id, code, message string) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateGeoRestriction(ctx context.Context, id string, restriction domain.GeoRestriction) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateContentRating(ctx context.Context, id string, rating domain.ContentRating) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateClassification(ctx context.Context, id string, labels []string, category string) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateLicense(ctx context.Context, id string, license domain.License) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateCuePoints(ctx context.Context, id string, cuePoints []domain.CuePoint) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateChapters(ctx context.Context, id string, chapters []domain.Chapter, suggestions []domain.ChapterSuggestion) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateAudioTracks(ctx context.Context, id string, tracks []domain.AudioTrack) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateSeries(ctx context.Context, id, seriesID string, part int, locked bool) error {
+	return nil
+}
+
+func (m *MockMediaRepository) GetBySeries(ctx context.Context, seriesID string) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.MediaVisibility) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdateUnpublishAt(ctx context.Context, id string, unpublishAt *time.Time) error {
+	return nil
+}
+
+func (m *MockMediaRepository) GetDueUnpublishes(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) UpdatePremiere(ctx context.Context, id string, premiere domain.Premiere) error {
+	return nil
+}
+
+func (m *MockMediaRepository) UpdatePoster(ctx context.Context, id string, poster domain.Poster) error {
+	return nil
+}
+
+func (m *MockMediaRepository) GetDuePremieres(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) GetScheduledBetween(ctx context.Context, from, end time.Time) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) GetExpiredLicenses(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) GetLicensesExpiringBefore(ctx context.Context, now, deadline time.Time, limit int) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) IncrementProcessingAttempts(ctx context.Context, id string) error {
+	return nil
+}
+
 func (m *MockMediaRepository) GetTotal(ctx context.Context) (int64, error) {
 	return 0, nil
 }
+
+func (m *MockMediaRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	return nil, nil
+}
+
+func (m *MockMediaRepository) GetAllWithTotal(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockMediaRepository) GetEstimatedTotal(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockMediaRepository) GetTotalByFilter(ctx context.Context, filter *domain.MediaFilter) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockMediaRepository) GetStorageUsage(ctx context.Context, tenantID string) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockMediaRepository) GetByOwner(ctx context.Context, ownerID string, limit, offset int) ([]*domain.Media, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockMediaRepository) GetByContentHash(ctx context.Context, tenantID, contentHash string) (*domain.Media, error) {
+	return nil, domain.ErrMediaNotFound
+}
@@ -0,0 +1,607 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// inMemoryMediaRepository implements MediaRepository in memory, for tests.
+// It follows the semantics of the PostgreSQL repository: column defaults are
+// applied on create and Update only writes non-zero fields.
+type inMemoryMediaRepository struct {
+	mu    sync.RWMutex
+	media map[string]*domain.Media
+	now   func() time.Time
+}
+
+// NewInMemoryMediaRepository creates an empty in-memory media repository, safe
+// for concurrent use. Records are copied in and out, so callers cannot change
+// stored media without going through the repository.
+func NewInMemoryMediaRepository() MediaRepository {
+	return &inMemoryMediaRepository{
+		media: make(map[string]*domain.Media),
+		now:   time.Now,
+	}
+}
+
+// Create creates a new media record
+func (r *inMemoryMediaRepository) Create(ctx context.Context, media *domain.Media) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.media[media.ID]; exists {
+		return gorm.ErrDuplicatedKey
+	}
+
+	// Same defaults as the columns, written back like GORM does
+	if media.Priority == "" {
+		media.Priority = domain.PriorityNormal
+	}
+	if media.Visibility == "" {
+		media.Visibility = domain.VisibilityPublic
+	}
+	if media.AccessTier == "" {
+		media.AccessTier = domain.AccessTierFree
+	}
+	if media.ContentRating.AgeRating == "" {
+		media.ContentRating.AgeRating = domain.AgeRatingAll
+	}
+	if media.TenantID == "" {
+		media.TenantID = domain.DefaultTenantID
+	}
+	now := r.now()
+	if media.CreatedAt.IsZero() {
+		media.CreatedAt = now
+	}
+	if media.UpdatedAt.IsZero() {
+		media.UpdatedAt = now
+	}
+
+	r.media[media.ID] = cloneMedia(media)
+	return nil
+}
+
+// GetByID retrieves a media record by ID
+func (r *inMemoryMediaRepository) GetByID(ctx context.Context, id string) (*domain.Media, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	media, ok := r.media[id]
+	if !ok || media.DeletedAt != nil {
+		return nil, domain.ErrMediaNotFound
+	}
+	return cloneMedia(media), nil
+}
+
+// GetByIDs retrieves the existing media records among ids
+func (r *inMemoryMediaRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.Media, 0, len(ids))
+	for _, id := range ids {
+		if media, ok := r.media[id]; ok && media.DeletedAt == nil {
+			result = append(result, cloneMedia(media))
+		}
+	}
+	return result, nil
+}
+
+// GetAll retrieves all media records with pagination
+func (r *inMemoryMediaRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Media, error) {
+	return r.find(nil, limit, offset), nil
+}
+
+// Update updates an existing media record, skipping zero fields
+func (r *inMemoryMediaRepository) Update(ctx context.Context, media *domain.Media) error {
+	return r.update(media.ID, func(stored *domain.Media) {
+		mergeNonZero(reflect.ValueOf(stored).Elem(), reflect.ValueOf(cloneMedia(media)).Elem())
+	})
+}
+
+// Delete soft deletes a media record by ID, moving it to the trash
+func (r *inMemoryMediaRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	media, ok := r.media[id]
+	if !ok || media.DeletedAt != nil {
+		return domain.ErrMediaNotFound
+	}
+	now := r.now()
+	media.DeletedAt = &now
+	media.UpdatedAt = now
+	return nil
+}
+
+// GetTrashed retrieves media deleted at or before deletedBefore, longest deleted first
+func (r *inMemoryMediaRepository) GetTrashed(ctx context.Context, deletedBefore time.Time, limit int) ([]*domain.Media, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Media
+	for _, media := range r.media {
+		if media.DeletedAt != nil && !media.DeletedAt.After(deletedBefore) {
+			result = append(result, cloneMedia(media))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DeletedAt.Equal(*result[j].DeletedAt) {
+			return result[i].DeletedAt.Before(*result[j].DeletedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return paginate(result, limit, 0), nil
+}
+
+// Purge permanently removes a media record from the trash
+func (r *inMemoryMediaRepository) Purge(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	media, ok := r.media[id]
+	if !ok || media.DeletedAt == nil {
+		return domain.ErrMediaNotFound
+	}
+	delete(r.media, id)
+	return nil
+}
+
+// GetByIDsWithTrashed retrieves the media records among ids, those in the trash included
+func (r *inMemoryMediaRepository) GetByIDsWithTrashed(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.Media, 0, len(ids))
+	for _, id := range ids {
+		if media, ok := r.media[id]; ok {
+			result = append(result, cloneMedia(media))
+		}
+	}
+	return result, nil
+}
+
+// GetByStatus retrieves media records by status
+func (r *inMemoryMediaRepository) GetByStatus(ctx context.Context, status domain.MediaStatus, limit, offset int) ([]*domain.Media, error) {
+	return r.find(func(media *domain.Media) bool {
+		return media.Status == status
+	}, limit, offset), nil
+}
+
+// GetByFilter retrieves media records matching the filter
+func (r *inMemoryMediaRepository) GetByFilter(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, error) {
+	return r.find(filterMatcher(filter), limit, offset), nil
+}
+
+// UpdateStatus updates only the status of a media record
+func (r *inMemoryMediaRepository) UpdateStatus(ctx context.Context, id string, status domain.MediaStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Deleted media stays deleted, even when processing it ends afterwards
+	media, ok := r.media[id]
+	if !ok || media.Status == domain.StatusDeleted {
+		return domain.ErrMediaNotFound
+	}
+	media.Status = status
+	media.UpdatedAt = r.now()
+	return nil
+}
+
+// UpdateFailure sets or clears the failure reason of a media record
+func (r *inMemoryMediaRepository) UpdateFailure(ctx context.Context, id, code, message string) error {
+	return r.update(id, func(media *domain.Media) {
+		media.FailureCode = code
+		media.FailureMessage = message
+	})
+}
+
+// UpdateGeoRestriction replaces the country rules of a media record
+func (r *inMemoryMediaRepository) UpdateGeoRestriction(ctx context.Context, id string, restriction domain.GeoRestriction) error {
+	return r.update(id, func(media *domain.Media) {
+		media.GeoRestriction = domain.GeoRestriction{
+			AllowedCountries: append([]string(nil), restriction.AllowedCountries...),
+			BlockedCountries: append([]string(nil), restriction.BlockedCountries...),
+		}
+	})
+}
+
+// UpdateContentRating replaces the age rating and explicit flag of a media record
+func (r *inMemoryMediaRepository) UpdateContentRating(ctx context.Context, id string, rating domain.ContentRating) error {
+	return r.update(id, func(media *domain.Media) {
+		media.ContentRating = rating
+	})
+}
+
+// UpdateClassification replaces the labels and category of a media record
+func (r *inMemoryMediaRepository) UpdateClassification(ctx context.Context, id string, labels []string, category string) error {
+	return r.update(id, func(media *domain.Media) {
+		media.Labels = append([]string{}, labels...)
+		media.Category = category
+	})
+}
+
+// UpdateLicense replaces the rights window of a media record
+func (r *inMemoryMediaRepository) UpdateLicense(ctx context.Context, id string, license domain.License) error {
+	return r.update(id, func(media *domain.Media) {
+		media.License = cloneLicense(license)
+	})
+}
+
+// UpdateCuePoints replaces the ad breaks of a media record
+func (r *inMemoryMediaRepository) UpdateCuePoints(ctx context.Context, id string, cuePoints []domain.CuePoint) error {
+	return r.update(id, func(media *domain.Media) {
+		media.CuePoints = append([]domain.CuePoint{}, cuePoints...)
+	})
+}
+
+// UpdateChapters replaces the chapters and chapter suggestions of a media record
+func (r *inMemoryMediaRepository) UpdateChapters(ctx context.Context, id string, chapters []domain.Chapter, suggestions []domain.ChapterSuggestion) error {
+	return r.update(id, func(media *domain.Media) {
+		media.Chapters = append([]domain.Chapter{}, chapters...)
+		media.ChapterSuggestions = append([]domain.ChapterSuggestion{}, suggestions...)
+	})
+}
+
+// UpdateAudioTracks replaces the dubbed audio tracks of a media record
+func (r *inMemoryMediaRepository) UpdateAudioTracks(ctx context.Context, id string, tracks []domain.AudioTrack) error {
+	return r.update(id, func(media *domain.Media) {
+		media.AudioTracks = append([]domain.AudioTrack{}, tracks...)
+	})
+}
+
+// UpdateSeries links a media record to a series part, or unlinks it given an empty series ID
+func (r *inMemoryMediaRepository) UpdateSeries(ctx context.Context, id, seriesID string, part int, locked bool) error {
+	return r.update(id, func(media *domain.Media) {
+		media.SeriesID = seriesID
+		media.SeriesPart = part
+		media.SeriesLocked = locked
+	})
+}
+
+// GetBySeries retrieves the media of a series that is not deleted, in part order
+func (r *inMemoryMediaRepository) GetBySeries(ctx context.Context, seriesID string) ([]*domain.Media, error) {
+	result := r.find(func(media *domain.Media) bool {
+		return media.SeriesID == seriesID && media.Status != domain.StatusDeleted
+	}, 0, 0)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].SeriesPart != result[j].SeriesPart {
+			return result[i].SeriesPart < result[j].SeriesPart
+		}
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// UpdateVisibility changes only the visibility of a media record
+func (r *inMemoryMediaRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.MediaVisibility) error {
+	return r.update(id, func(media *domain.Media) {
+		media.Visibility = visibility
+	})
+}
+
+// UpdateUnpublishAt schedules or, given nil, cancels the unpublishing of a media record
+func (r *inMemoryMediaRepository) UpdateUnpublishAt(ctx context.Context, id string, unpublishAt *time.Time) error {
+	return r.update(id, func(media *domain.Media) {
+		media.UnpublishAt = cloneTime(unpublishAt)
+	})
+}
+
+// GetDueUnpublishes retrieves published media scheduled to be unpublished at or before now
+func (r *inMemoryMediaRepository) GetDueUnpublishes(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	result := r.find(func(media *domain.Media) bool {
+		return media.Status == domain.StatusReady &&
+			media.Visibility != domain.VisibilityPrivate &&
+			media.UnpublishAt != nil &&
+			!media.UnpublishAt.After(now)
+	}, 0, 0)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].UnpublishAt.Before(*result[j].UnpublishAt)
+	})
+	return paginate(result, limit, 0), nil
+}
+
+// UpdatePremiere replaces the premiere of a media record
+func (r *inMemoryMediaRepository) UpdatePremiere(ctx context.Context, id string, premiere domain.Premiere) error {
+	return r.update(id, func(media *domain.Media) {
+		media.Premiere = clonePremiere(premiere)
+	})
+}
+
+// UpdatePoster replaces the poster of a media record
+func (r *inMemoryMediaRepository) UpdatePoster(ctx context.Context, id string, poster domain.Poster) error {
+	return r.update(id, func(media *domain.Media) {
+		media.Poster = poster
+	})
+}
+
+// GetDuePremieres retrieves published media whose premiere started at or before now
+// and was not announced yet
+func (r *inMemoryMediaRepository) GetDuePremieres(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	result := r.find(func(media *domain.Media) bool {
+		return media.Status == domain.StatusReady &&
+			media.Visibility != domain.VisibilityPrivate &&
+			media.Premiere.StartsAt != nil &&
+			!media.Premiere.StartsAt.After(now) &&
+			media.Premiere.AnnouncedAt == nil
+	}, 0, 0)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Premiere.StartsAt.Before(*result[j].Premiere.StartsAt)
+	})
+	return paginate(result, limit, 0), nil
+}
+
+// GetScheduledBetween retrieves media that is not deleted and whose license starts or
+// ends, or which is unpublished or premieres, within [from, end)
+func (r *inMemoryMediaRepository) GetScheduledBetween(ctx context.Context, from, end time.Time) ([]*domain.Media, error) {
+	within := func(at *time.Time) bool {
+		return at != nil && !at.Before(from) && at.Before(end)
+	}
+	return r.find(func(media *domain.Media) bool {
+		return media.Status != domain.StatusDeleted &&
+			(within(media.License.StartsAt) || within(media.License.EndsAt) || within(media.UnpublishAt) || within(media.Premiere.StartsAt))
+	}, 0, 0), nil
+}
+
+// GetExpiredLicenses retrieves published media whose license ended at or before now
+func (r *inMemoryMediaRepository) GetExpiredLicenses(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	return r.findByLicenseEnd(func(media *domain.Media) bool {
+		return !media.License.EndsAt.After(now)
+	}, limit), nil
+}
+
+// GetLicensesExpiringBefore retrieves published media whose license ends after now and at
+// or before the deadline, and whose owners were not warned yet
+func (r *inMemoryMediaRepository) GetLicensesExpiringBefore(ctx context.Context, now, deadline time.Time, limit int) ([]*domain.Media, error) {
+	return r.findByLicenseEnd(func(media *domain.Media) bool {
+		endsAt := *media.License.EndsAt
+		return endsAt.After(now) && !endsAt.After(deadline) && media.License.ExpiryNotifiedAt == nil
+	}, limit), nil
+}
+
+// IncrementProcessingAttempts atomically bumps the processing attempt counter
+func (r *inMemoryMediaRepository) IncrementProcessingAttempts(ctx context.Context, id string) error {
+	return r.update(id, func(media *domain.Media) {
+		media.ProcessingAttempts++
+	})
+}
+
+// GetTotal returns the total count of media records
+func (r *inMemoryMediaRepository) GetTotal(ctx context.Context) (int64, error) {
+	return int64(len(r.find(nil, 0, 0))), nil
+}
+
+// GetAllWithTotal retrieves a page of media records together with the total count
+func (r *inMemoryMediaRepository) GetAllWithTotal(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error) {
+	all := r.find(nil, 0, 0)
+	return paginate(all, limit, offset), int64(len(all)), nil
+}
+
+// GetByOwner retrieves a page of the media records a user owns together with their total count
+func (r *inMemoryMediaRepository) GetByOwner(ctx context.Context, ownerID string, limit, offset int) ([]*domain.Media, int64, error) {
+	owned := r.find(func(media *domain.Media) bool {
+		return media.OwnerID == ownerID
+	}, 0, 0)
+	return paginate(owned, limit, offset), int64(len(owned)), nil
+}
+
+// GetEstimatedTotal returns the exact count of media records
+func (r *inMemoryMediaRepository) GetEstimatedTotal(ctx context.Context) (int64, error) {
+	return r.GetTotal(ctx)
+}
+
+// GetTotalByFilter returns the count of media records matching the filter
+func (r *inMemoryMediaRepository) GetTotalByFilter(ctx context.Context, filter *domain.MediaFilter) (int64, error) {
+	return int64(len(r.find(filterMatcher(filter), 0, 0))), nil
+}
+
+// GetStorageUsage returns the total file size of the media of a tenant that is not deleted
+func (r *inMemoryMediaRepository) GetStorageUsage(ctx context.Context, tenantID string) (int64, error) {
+	var usage int64
+	for _, media := range r.find(tenantMatcher(tenantID), 0, 0) {
+		usage += media.FileSize
+	}
+	return usage, nil
+}
+
+// GetByContentHash retrieves a media record of a tenant that is not deleted by its content hash
+func (r *inMemoryMediaRepository) GetByContentHash(ctx context.Context, tenantID, contentHash string) (*domain.Media, error) {
+	contentHash = strings.ToLower(contentHash)
+	matches := r.find(func(media *domain.Media) bool {
+		return tenantMatcher(tenantID)(media) && media.ContentHash == contentHash
+	}, 0, 0)
+	if len(matches) == 0 {
+		return nil, domain.ErrMediaNotFound
+	}
+	// Oldest first, like the PostgreSQL repository
+	return matches[len(matches)-1], nil
+}
+
+// tenantMatcher matches the media of a tenant that is not deleted
+func tenantMatcher(tenantID string) func(*domain.Media) bool {
+	return func(media *domain.Media) bool {
+		return media.TenantID == tenantID && media.Status != domain.StatusDeleted
+	}
+}
+
+// update applies change to a stored record and bumps its update time
+func (r *inMemoryMediaRepository) update(id string, change func(*domain.Media)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	media, ok := r.media[id]
+	if !ok {
+		return domain.ErrMediaNotFound
+	}
+	change(media)
+	media.UpdatedAt = r.now()
+	return nil
+}
+
+// find returns copies of the matching records outside the trash, newest first.
+// A limit of 0 returns all of them.
+func (r *inMemoryMediaRepository) find(match func(*domain.Media) bool, limit, offset int) []*domain.Media {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.Media, 0, len(r.media))
+	for _, media := range r.media {
+		if media.DeletedAt == nil && (match == nil || match(media)) {
+			result = append(result, cloneMedia(media))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return paginate(result, limit, offset)
+}
+
+// findByLicenseEnd returns published media with a license end matching, soonest first
+func (r *inMemoryMediaRepository) findByLicenseEnd(match func(*domain.Media) bool, limit int) []*domain.Media {
+	result := r.find(func(media *domain.Media) bool {
+		return media.Status == domain.StatusReady &&
+			media.Visibility != domain.VisibilityPrivate &&
+			media.License.EndsAt != nil &&
+			match(media)
+	}, 0, 0)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].License.EndsAt.Before(*result[j].License.EndsAt)
+	})
+	return paginate(result, limit, 0)
+}
+
+// filterMatcher returns the condition of a media filter
+func filterMatcher(filter *domain.MediaFilter) func(*domain.Media) bool {
+	if filter == nil {
+		return nil
+	}
+	return func(media *domain.Media) bool {
+		if filter.Status != "" && media.Status != filter.Status {
+			return false
+		}
+		if filter.FailureCode != "" && media.FailureCode != filter.FailureCode {
+			return false
+		}
+		if filter.Visibility != "" && media.Visibility != filter.Visibility {
+			return false
+		}
+		if filter.Safe && (media.ContentRating.Explicit || media.ContentRating.AgeRating == domain.AgeRating18) {
+			return false
+		}
+		if filter.ShowID != "" && media.ShowID != filter.ShowID {
+			return false
+		}
+		if filter.Category != "" && media.Category != filter.Category {
+			return false
+		}
+		return true
+	}
+}
+
+// paginate applies limit and offset to a result, a limit of 0 returns everything after offset
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return items[:0]
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// cloneMedia deep copies media so stored records do not share memory with callers
+func cloneMedia(media *domain.Media) *domain.Media {
+	clone := *media
+	if media.DeletedAt != nil {
+		deletedAt := *media.DeletedAt
+		clone.DeletedAt = &deletedAt
+	}
+	clone.GeoRestriction.AllowedCountries = append([]string(nil), media.GeoRestriction.AllowedCountries...)
+	clone.GeoRestriction.BlockedCountries = append([]string(nil), media.GeoRestriction.BlockedCountries...)
+	clone.License = cloneLicense(media.License)
+	clone.UnpublishAt = cloneTime(media.UnpublishAt)
+	clone.Premiere = clonePremiere(media.Premiere)
+	if media.Labels != nil {
+		clone.Labels = append([]string{}, media.Labels...)
+	}
+	if media.CuePoints != nil {
+		clone.CuePoints = append([]domain.CuePoint{}, media.CuePoints...)
+	}
+	if media.Chapters != nil {
+		clone.Chapters = append([]domain.Chapter{}, media.Chapters...)
+	}
+	if media.ChapterSuggestions != nil {
+		clone.ChapterSuggestions = append([]domain.ChapterSuggestion{}, media.ChapterSuggestions...)
+	}
+	if media.Tags.RecordedAt != nil {
+		recordedAt := *media.Tags.RecordedAt
+		clone.Tags.RecordedAt = &recordedAt
+	}
+	if media.SearchContext != nil {
+		clone.SearchContext = &domain.SearchContext{
+			ShowTitle:  media.SearchContext.ShowTitle,
+			Categories: append([]string(nil), media.SearchContext.Categories...),
+			Hosts:      append([]string(nil), media.SearchContext.Hosts...),
+			Guests:     append([]string(nil), media.SearchContext.Guests...),
+		}
+	}
+	return &clone
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := *t
+	return &value
+}
+
+func cloneLicense(license domain.License) domain.License {
+	for _, field := range []**time.Time{&license.StartsAt, &license.EndsAt, &license.ExpiryNotifiedAt} {
+		if *field != nil {
+			value := **field
+			*field = &value
+		}
+	}
+	return license
+}
+
+// clonePremiere copies the times of a premiere
+func clonePremiere(premiere domain.Premiere) domain.Premiere {
+	premiere.StartsAt = cloneTime(premiere.StartsAt)
+	premiere.AnnouncedAt = cloneTime(premiere.AnnouncedAt)
+	return premiere
+}
+
+// mergeNonZero copies the non-zero fields of src into dst like GORM's Updates
+// with a struct: embedded structs are merged field by field, like their columns.
+func mergeNonZero(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Field(i)
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Time{}) {
+			mergeNonZero(dst.Field(i), field)
+			continue
+		}
+		if !field.IsZero() {
+			dst.Field(i).Set(field)
+		}
+	}
+}
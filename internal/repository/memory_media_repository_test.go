@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestInMemoryMediaRepository_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryMediaRepository()
+
+	media := &domain.Media{ID: "m1", Title: "Episode 1", GeoRestriction: domain.GeoRestriction{AllowedCountries: []string{"SA"}}}
+	require.NoError(t, repo.Create(ctx, media))
+	assert.ErrorIs(t, repo.Create(ctx, &domain.Media{ID: "m1"}), gorm.ErrDuplicatedKey)
+
+	// Column defaults are written back
+	assert.Equal(t, domain.VisibilityPublic, media.Visibility)
+	assert.Equal(t, domain.AgeRatingAll, media.ContentRating.AgeRating)
+	assert.False(t, media.CreatedAt.IsZero())
+
+	// Stored records are isolated from callers
+	media.GeoRestriction.AllowedCountries[0] = "US"
+	stored, err := repo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SA"}, stored.GeoRestriction.AllowedCountries)
+
+	_, err = repo.GetByID(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+}
+
+func TestInMemoryMediaRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryMediaRepository()
+	require.NoError(t, repo.Create(ctx, &domain.Media{
+		ID: "m1", Title: "Episode 1", Description: "First", ContentRating: domain.ContentRating{Explicit: true},
+	}))
+
+	// Zero fields are skipped, like GORM's Updates
+	require.NoError(t, repo.Update(ctx, &domain.Media{ID: "m1", Title: "Renamed"}))
+	media, err := repo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", media.Title)
+	assert.Equal(t, "First", media.Description)
+	assert.True(t, media.ContentRating.Explicit)
+
+	// Dedicated updates clear values
+	require.NoError(t, repo.UpdateContentRating(ctx, "m1", domain.ContentRating{AgeRating: domain.AgeRatingAll}))
+	media, err = repo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	assert.False(t, media.ContentRating.Explicit)
+
+	assert.ErrorIs(t, repo.Update(ctx, &domain.Media{ID: "missing"}), domain.ErrMediaNotFound)
+	assert.ErrorIs(t, repo.UpdateStatus(ctx, "missing", domain.StatusReady), domain.ErrMediaNotFound)
+
+	// Deleted media stays deleted
+	require.NoError(t, repo.UpdateStatus(ctx, "m1", domain.StatusDeleted))
+	assert.ErrorIs(t, repo.UpdateStatus(ctx, "m1", domain.StatusReady), domain.ErrMediaNotFound)
+}
+
+func TestInMemoryMediaRepository_Queries(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryMediaRepository()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	endedAt, endsSoon := now.Add(-time.Hour), now.Add(time.Hour)
+
+	for i, media := range []*domain.Media{
+		{ID: "expired", Status: domain.StatusReady, License: domain.License{EndsAt: &endedAt}},
+		{ID: "expiring", Status: domain.StatusReady, License: domain.License{EndsAt: &endsSoon}},
+		{ID: "private", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate, License: domain.License{EndsAt: &endedAt}},
+		{ID: "failed", Status: domain.StatusFailed, FailureCode: domain.FailureUploadValidation},
+		{ID: "explicit", Status: domain.StatusReady, ContentRating: domain.ContentRating{AgeRating: domain.AgeRating18}},
+	} {
+		media.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(ctx, media))
+	}
+
+	all, err := repo.GetAll(ctx, 2, 1)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "failed", all[0].ID, "newest first")
+
+	ready, err := repo.GetByFilter(ctx, &domain.MediaFilter{Status: domain.StatusReady, Safe: true}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, ready, 3)
+
+	total, err := repo.GetTotalByFilter(ctx, &domain.MediaFilter{FailureCode: domain.FailureUploadValidation})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	expired, err := repo.GetExpiredLicenses(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "expired", expired[0].ID)
+
+	expiring, err := repo.GetLicensesExpiringBefore(ctx, now, now.Add(24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, expiring, 1)
+	assert.Equal(t, "expiring", expiring[0].ID)
+}
+
+func TestInMemoryMediaRepository_ConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryMediaRepository()
+	require.NoError(t, repo.Create(ctx, &domain.Media{ID: "m1"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.IncrementProcessingAttempts(ctx, "m1"))
+		}()
+	}
+	wg.Wait()
+
+	media, err := repo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, 50, media.ProcessingAttempts)
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"thamaniyah/internal/domain"
+)
+
+// inMemorySearchRepository implements SearchRepository in memory, for tests.
+// Media matches a clause of the query when each of its words and phrases
+// occurs in its title, description or search context, normalized like
+// queries, and none of the excluded ones do. Title matches weigh more.
+type inMemorySearchRepository struct {
+	mu    sync.RWMutex
+	index map[string]*domain.Media
+}
+
+// NewInMemorySearchRepository creates an empty in-memory search repository, safe for concurrent use
+func NewInMemorySearchRepository() SearchRepository {
+	return &inMemorySearchRepository{
+		index: make(map[string]*domain.Media),
+	}
+}
+
+// Search performs full-text search on indexed media
+func (r *inMemorySearchRepository) Search(ctx context.Context, req *domain.SearchRequest) ([]*domain.SearchResult, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := req.StructuredQuery()
+	var results []*domain.SearchResult
+	for _, media := range r.index {
+		if req.Type != "" && string(media.Type) != req.Type {
+			continue
+		}
+		if req.ShowID != "" && media.ShowID != req.ShowID {
+			continue
+		}
+		if req.AudioLanguage != "" && !slices.Contains(media.AudioLanguages(), req.AudioLanguage) {
+			continue
+		}
+		if req.Safe && media.ContentRating.IsExplicit() {
+			continue
+		}
+		if !query.MatchesTags(media.Labels) {
+			continue
+		}
+		score, ok := queryScore(media, query.Clauses)
+		if !ok {
+			continue
+		}
+		results = append(results, &domain.SearchResult{Media: cloneMedia(media), Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Media.ID < results[j].Media.ID
+	})
+	if req.Collapse == domain.SearchCollapseShow {
+		results = collapseByShow(results)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	return paginate(results, limit, req.Offset), int64(len(results)), nil
+}
+
+// Suggest provides titles containing the query, most frequent first
+func (r *inMemorySearchRepository) Suggest(ctx context.Context, req *domain.SuggestRequest) ([]*domain.Suggestion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := strings.ToLower(req.Query)
+	counts := make(map[string]int)
+	for _, media := range r.index {
+		if req.Safe && media.ContentRating.IsExplicit() {
+			continue
+		}
+		if strings.Contains(strings.ToLower(media.Title), query) {
+			counts[media.Title]++
+		}
+	}
+
+	suggestions := make([]*domain.Suggestion, 0, len(counts))
+	for text, count := range counts {
+		suggestions = append(suggestions, &domain.Suggestion{Text: text, Count: count})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	return paginate(suggestions, limit, 0), nil
+}
+
+// IndexMedia adds or updates media in search index
+func (r *inMemorySearchRepository) IndexMedia(ctx context.Context, media *domain.Media) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.index[media.ID] = cloneMedia(media)
+	return nil
+}
+
+// RemoveFromIndex removes media from search index
+func (r *inMemorySearchRepository) RemoveFromIndex(ctx context.Context, mediaID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.index, mediaID)
+	return nil
+}
+
+// ReindexAll rebuilds the entire search index
+func (r *inMemorySearchRepository) ReindexAll(ctx context.Context, mediaList []*domain.Media) error {
+	index := make(map[string]*domain.Media, len(mediaList))
+	for _, media := range mediaList {
+		index[media.ID] = cloneMedia(media)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.index = index
+	return nil
+}
+
+// ListIndexed lists every document of the search index with the update
+// time of the media it was built from
+func (r *inMemorySearchRepository) ListIndexed(ctx context.Context) ([]*domain.IndexedMedia, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	indexed := make([]*domain.IndexedMedia, 0, len(r.index))
+	for _, media := range r.index {
+		indexed = append(indexed, &domain.IndexedMedia{MediaID: media.ID, UpdatedAt: media.UpdatedAt})
+	}
+	sort.Slice(indexed, func(i, j int) bool { return indexed[i].MediaID < indexed[j].MediaID })
+	return indexed, nil
+}
+
+// collapseByShow keeps the first result of each show, with the next ones as its inner hits
+func collapseByShow(results []*domain.SearchResult) []*domain.SearchResult {
+	collapsed := results[:0]
+	tops := make(map[string]*domain.SearchResult)
+	for _, result := range results {
+		group := domain.ShowGroup(result.Media)
+		top, ok := tops[group]
+		if !ok {
+			tops[group] = result
+			collapsed = append(collapsed, result)
+			continue
+		}
+		if len(top.InnerHits) < domain.CollapseInnerHits {
+			top.InnerHits = append(top.InnerHits, result)
+		}
+		top.InnerHitsTotal++
+	}
+	return collapsed
+}
+
+// queryScore scores media against the best matching clause of the query.
+// Media matches a query without clauses with a score of 0.
+func queryScore(media *domain.Media, clauses []domain.SearchClause) (float64, bool) {
+	if len(clauses) == 0 {
+		return 0, true
+	}
+
+	best, matched := 0.0, false
+	for _, clause := range clauses {
+		score, ok := matchScore(media, clause)
+		if ok && (!matched || score > best) {
+			best, matched = score, true
+		}
+	}
+	return best, matched
+}
+
+// matchScore scores media against the words and phrases of a clause,
+// weighting the fields like DefaultSearchBoosts
+func matchScore(media *domain.Media, clause domain.SearchClause) (float64, bool) {
+	searchContext := media.SearchContext
+	if searchContext == nil {
+		searchContext = &domain.SearchContext{}
+	}
+	fields := []struct {
+		text  string
+		boost float64
+	}{
+		{media.Title, DefaultSearchBoosts.Title},
+		{media.Description, DefaultSearchBoosts.Description},
+		{searchContext.ShowTitle, DefaultSearchBoosts.Show},
+		{strings.Join(searchContext.Categories, " "), DefaultSearchBoosts.Category},
+		{strings.Join(searchContext.Hosts, " "), DefaultSearchBoosts.Host},
+		{strings.Join(searchContext.Guests, " "), DefaultSearchBoosts.Guest},
+	}
+
+	for i := range fields {
+		fields[i].text = domain.NormalizeSearchText(fields[i].text)
+	}
+
+	for _, term := range clause.Excluded {
+		for _, field := range fields {
+			if strings.Contains(field.text, strings.ToLower(term.Text)) {
+				return 0, false
+			}
+		}
+	}
+
+	var score float64
+	for _, term := range clause.Terms {
+		matched := false
+		for _, field := range fields {
+			if strings.Contains(field.text, strings.ToLower(term.Text)) {
+				score += field.boost
+				matched = true
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+	return score, true
+}
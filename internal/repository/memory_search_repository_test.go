@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemorySearchRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemorySearchRepository()
+
+	require.NoError(t, repo.ReindexAll(ctx, []*domain.Media{
+		{ID: "m1", Title: "Golang Concurrency", Description: "Channels", Type: domain.TypeVideo},
+		{ID: "m2", Title: "Cooking Show", Description: "Cooking with golang", Type: domain.TypePodcast},
+		{ID: "m3", Title: "Golang After Dark", Type: domain.TypeVideo, ContentRating: domain.ContentRating{Explicit: true}, ShowID: "show-1"},
+	}))
+
+	results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, results, 3)
+	assert.Equal(t, "m2", results[2].Media.ID, "description matches rank last")
+
+	results, total, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang", Type: string(domain.TypeVideo), Safe: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "m1", results[0].Media.ID)
+
+	results, total, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang", ShowID: "show-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "m3", results[0].Media.ID)
+
+	suggestions, err := repo.Suggest(ctx, &domain.SuggestRequest{Query: "golang", Safe: true})
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "Golang Concurrency", suggestions[0].Text)
+
+	query := `"golang after" OR cooking -tag:food`
+	parsed, err := domain.ParseSearchQuery(query)
+	require.NoError(t, err)
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m4", Title: "Cooking Rice", Labels: []string{"food"}}))
+	results, total, err = repo.Search(ctx, &domain.SearchRequest{Query: query, Parsed: parsed.Normalized()})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "m2", results[0].Media.ID, "phrases and alternatives match, excluded tags do not")
+	assert.Equal(t, "m3", results[1].Media.ID)
+
+	require.NoError(t, repo.RemoveFromIndex(ctx, "m1"))
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m2", Title: "Cooking Show"}))
+	_, total, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
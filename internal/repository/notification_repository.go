@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// NotificationPreferenceRepository defines the contract for notification preference data access
+type NotificationPreferenceRepository interface {
+	// Create creates a new notification preference
+	Create(ctx context.Context, pref *domain.NotificationPreference) error
+
+	// GetBySubjects retrieves the preferences of all given subjects
+	GetBySubjects(ctx context.Context, subjects []domain.NotificationSubject) ([]*domain.NotificationPreference, error)
+
+	// Delete removes a notification preference by ID
+	Delete(ctx context.Context, id string) error
+
+	// ListByChannel retrieves the preferences of a channel across all subjects
+	ListByChannel(ctx context.Context, channel string) ([]*domain.NotificationPreference, error)
+
+	// RecordDelivery updates the delivery stats of a preference, a nil deliveryErr counting as delivered
+	RecordDelivery(ctx context.Context, id string, at time.Time, deliveryErr error) error
+}
+
+// postgresNotificationPreferenceRepository implements NotificationPreferenceRepository using PostgreSQL
+type postgresNotificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresNotificationPreferenceRepository creates a new PostgreSQL notification preference repository
+func NewPostgresNotificationPreferenceRepository(conn *database.Connection) NotificationPreferenceRepository {
+	return &postgresNotificationPreferenceRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new notification preference
+func (r *postgresNotificationPreferenceRepository) Create(ctx context.Context, pref *domain.NotificationPreference) error {
+	return r.db.WithContext(ctx).Create(pref).Error
+}
+
+// GetBySubjects retrieves the preferences of all given subjects
+func (r *postgresNotificationPreferenceRepository) GetBySubjects(ctx context.Context, subjects []domain.NotificationSubject) ([]*domain.NotificationPreference, error) {
+	if len(subjects) == 0 {
+		return nil, nil
+	}
+
+	query := r.db.WithContext(ctx)
+	conditions := r.db.Where("subject_type = ? AND subject_id = ?", subjects[0].Type, subjects[0].ID)
+	for _, subject := range subjects[1:] {
+		conditions = conditions.Or("subject_type = ? AND subject_id = ?", subject.Type, subject.ID)
+	}
+
+	var prefs []domain.NotificationPreference
+	if err := query.Where(conditions).Order("created_at ASC").Find(&prefs).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.NotificationPreference, len(prefs))
+	for i := range prefs {
+		result[i] = &prefs[i]
+	}
+
+	return result, nil
+}
+
+// Delete removes a notification preference by ID
+func (r *postgresNotificationPreferenceRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&domain.NotificationPreference{}, "id = ?", id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrNotificationPreferenceNotFound
+	}
+
+	return nil
+}
+
+// ListByChannel retrieves the preferences of a channel across all subjects
+func (r *postgresNotificationPreferenceRepository) ListByChannel(ctx context.Context, channel string) ([]*domain.NotificationPreference, error) {
+	var prefs []*domain.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("channel = ?", channel).
+		Order("created_at ASC").
+		Find(&prefs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// RecordDelivery updates the delivery stats of a preference
+func (r *postgresNotificationPreferenceRepository) RecordDelivery(ctx context.Context, id string, at time.Time, deliveryErr error) error {
+	updates := map[string]interface{}{
+		"delivered_count":   gorm.Expr("delivered_count + 1"),
+		"last_delivered_at": at,
+	}
+	if deliveryErr != nil {
+		updates = map[string]interface{}{
+			"failed_count":   gorm.Expr("failed_count + 1"),
+			"last_failed_at": at,
+			"last_error":     deliveryErr.Error(),
+		}
+	}
+
+	// Stats must not bump updated_at, which tracks changes to the preference itself
+	return r.db.WithContext(ctx).
+		Model(&domain.NotificationPreference{}).
+		Where("id = ?", id).
+		UpdateColumns(updates).Error
+}
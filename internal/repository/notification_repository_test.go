@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNotificationPreferenceRepositoryInterface ensures the mock satisfies
+// the NotificationPreferenceRepository interface
+func TestNotificationPreferenceRepositoryInterface(t *testing.T) {
+	var _ NotificationPreferenceRepository = (*MockNotificationPreferenceRepository)(nil)
+}
+
+// MockNotificationPreferenceRepository can be used in tests
+type MockNotificationPreferenceRepository struct{}
+
+func (m *MockNotificationPreferenceRepository) Create(ctx context.Context, pref *domain.NotificationPreference) error {
+	return nil
+}
+
+func (m *MockNotificationPreferenceRepository) GetBySubjects(ctx context.Context, subjects []domain.NotificationSubject) ([]*domain.NotificationPreference, error) {
+	return nil, nil
+}
+
+func (m *MockNotificationPreferenceRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *MockNotificationPreferenceRepository) ListByChannel(ctx context.Context, channel string) ([]*domain.NotificationPreference, error) {
+	return nil, nil
+}
+
+func (m *MockNotificationPreferenceRepository) RecordDelivery(ctx context.Context, id string, at time.Time, deliveryErr error) error {
+	return nil
+}
+
+func TestNotificationPreferenceRepository_RecordDelivery(t *testing.T) {
+	// Given a webhook and an email preference
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresNotificationPreferenceRepository(conn)
+
+	for _, pref := range []*domain.NotificationPreference{
+		{ID: "p1", SubjectType: domain.SubjectTenant, SubjectID: domain.DefaultTenantID, Channel: "webhook", Target: "https://example.com/hooks", Enabled: true},
+		{ID: "p2", SubjectType: domain.SubjectTenant, SubjectID: domain.DefaultTenantID, Channel: "email", Target: "ops@example.com", Enabled: true},
+	} {
+		require.NoError(t, repo.Create(ctx, pref))
+	}
+
+	// When two deliveries succeed and one fails
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.RecordDelivery(ctx, "p1", at, nil))
+	require.NoError(t, repo.RecordDelivery(ctx, "p1", at.Add(time.Minute), nil))
+	require.NoError(t, repo.RecordDelivery(ctx, "p1", at.Add(2*time.Minute), errors.New("status 502")))
+
+	// Then the webhook carries its delivery stats
+	webhooks, err := repo.ListByChannel(ctx, "webhook")
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	webhook := webhooks[0]
+	assert.Equal(t, "p1", webhook.ID)
+	assert.Equal(t, int64(2), webhook.DeliveredCount)
+	assert.Equal(t, int64(1), webhook.FailedCount)
+	require.NotNil(t, webhook.LastDeliveredAt)
+	assert.True(t, webhook.LastDeliveredAt.Equal(at.Add(time.Minute)))
+	require.NotNil(t, webhook.LastFailedAt)
+	assert.True(t, webhook.LastFailedAt.Equal(at.Add(2*time.Minute)))
+	assert.Equal(t, "status 502", webhook.LastError)
+}
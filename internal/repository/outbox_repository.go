@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository defines the contract for outbox data access
+type OutboxRepository interface {
+	// Append stores a new message in the outbox, in the transaction of ctx if any
+	Append(ctx context.Context, msg *domain.OutboxMessage) error
+
+	// RelayUnpublished locks up to limit unpublished messages, oldest first,
+	// skipping those another relay holds, and calls publish on each until it
+	// fails. The messages published are marked as such in the same transaction,
+	// so they are relayed again if the mark is not committed. It returns how
+	// many were published, and the error of publish if any.
+	RelayUnpublished(ctx context.Context, limit int, publish func(msg *domain.OutboxMessage) error) (int, error)
+
+	// CountUnpublished returns how many messages wait to be published
+	CountUnpublished(ctx context.Context) (int64, error)
+
+	// DeletePublished removes messages published before the given time
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// postgresOutboxRepository implements OutboxRepository using PostgreSQL
+type postgresOutboxRepository struct {
+	db     *gorm.DB
+	sqlite bool // SQLite has a single writer, it cannot skip locked rows
+}
+
+// NewPostgresOutboxRepository creates a new PostgreSQL outbox repository
+func NewPostgresOutboxRepository(conn *database.Connection) OutboxRepository {
+	return &postgresOutboxRepository{
+		db:     conn.DB,
+		sqlite: conn.IsSQLite(),
+	}
+}
+
+// Append stores a new message in the outbox, in the transaction of ctx if any
+func (r *postgresOutboxRepository) Append(ctx context.Context, msg *domain.OutboxMessage) error {
+	return database.DB(ctx, r.db).Create(msg).Error
+}
+
+// RelayUnpublished publishes a batch of unpublished messages and marks them as published
+func (r *postgresOutboxRepository) RelayUnpublished(ctx context.Context, limit int, publish func(msg *domain.OutboxMessage) error) (int, error) {
+	var published []uint64
+	var publishErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("published_at IS NULL").Order("id ASC").Limit(limit)
+		if !r.sqlite {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		var messages []domain.OutboxMessage
+		if err := query.Find(&messages).Error; err != nil {
+			return err
+		}
+
+		for i := range messages {
+			if err := publish(&messages[i]); err != nil {
+				publishErr = err
+				break
+			}
+			published = append(published, messages[i].ID)
+		}
+		if len(published) == 0 {
+			return nil
+		}
+
+		return tx.Model(&domain.OutboxMessage{}).
+			Where("id IN ?", published).
+			Update("published_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(published), publishErr
+}
+
+// CountUnpublished returns how many messages wait to be published
+func (r *postgresOutboxRepository) CountUnpublished(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.OutboxMessage{}).
+		Where("published_at IS NULL").
+		Count(&count).Error
+	return count, err
+}
+
+// DeletePublished removes messages published before the given time
+func (r *postgresOutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at < ?", before).
+		Delete(&domain.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
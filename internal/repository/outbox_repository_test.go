@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOutboxRepositoryInterface ensures the mock satisfies the OutboxRepository interface
+func TestOutboxRepositoryInterface(t *testing.T) {
+	var _ OutboxRepository = (*MockOutboxRepository)(nil)
+}
+
+// MockOutboxRepository can be used in tests
+type MockOutboxRepository struct{}
+
+func (m *MockOutboxRepository) Append(ctx context.Context, msg *domain.OutboxMessage) error {
+	return nil
+}
+
+func (m *MockOutboxRepository) RelayUnpublished(ctx context.Context, limit int, publish func(msg *domain.OutboxMessage) error) (int, error) {
+	return 0, nil
+}
+
+func (m *MockOutboxRepository) CountUnpublished(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockOutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestOutboxRepository_RelayUnpublished(t *testing.T) {
+	// Given an outbox with two messages, and one appended in a rolled back transaction
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, conn.DB.AutoMigrate(&domain.OutboxMessage{}))
+	repo := NewPostgresOutboxRepository(conn)
+	require.NoError(t, repo.Append(ctx, domain.NewOutboxMessage("a", []byte("1"))))
+	require.NoError(t, repo.Append(ctx, domain.NewOutboxMessage("b", []byte("2"))))
+	err := conn.InTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, repo.Append(ctx, domain.NewOutboxMessage("c", []byte("3"))))
+		return errors.New("media update failed")
+	})
+	require.Error(t, err)
+
+	// When the queue rejects the second message
+	var topics []string
+	published, err := repo.RelayUnpublished(ctx, 10, func(msg *domain.OutboxMessage) error {
+		if msg.Topic == "b" {
+			return errors.New("broker unavailable")
+		}
+		topics = append(topics, msg.Topic)
+		return nil
+	})
+
+	// Then only the first one is marked as published
+	assert.EqualError(t, err, "broker unavailable")
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{"a"}, topics)
+	count, err := repo.CountUnpublished(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// And the next batch relays the rejected one
+	topics = nil
+	published, err = repo.RelayUnpublished(ctx, 10, func(msg *domain.OutboxMessage) error {
+		topics = append(topics, msg.Topic)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{"b"}, topics)
+
+	// And published messages are pruned once retained long enough
+	deleted, err := repo.DeletePublished(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
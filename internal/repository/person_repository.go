@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// PersonRepository defines the contract for people and their credits
+type PersonRepository interface {
+	// Create creates a new person
+	Create(ctx context.Context, person *domain.Person) error
+
+	// GetByID retrieves a person by ID
+	GetByID(ctx context.Context, id string) (*domain.Person, error)
+
+	// GetByIDs retrieves the existing people among ids
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Person, error)
+
+	// GetAll retrieves a page of people ordered by name, with the total
+	GetAll(ctx context.Context, limit, offset int) ([]*domain.Person, int64, error)
+
+	// Update updates an existing person
+	Update(ctx context.Context, person *domain.Person) error
+
+	// Delete removes a person and their credits
+	Delete(ctx context.Context, id string) error
+
+	// SetCredits replaces the people credited on a target
+	SetCredits(ctx context.Context, targetType domain.CreditTarget, targetID string, credits []*domain.PersonCredit) error
+
+	// GetCredits retrieves the credits of targets in their order
+	GetCredits(ctx context.Context, targetType domain.CreditTarget, targetIDs []string) ([]*domain.PersonCredit, error)
+
+	// GetCreditsByPerson retrieves every credit of a person
+	GetCreditsByPerson(ctx context.Context, personID string) ([]*domain.PersonCredit, error)
+
+	// GetAppearances retrieves a page of the ready media a person is credited on, directly or
+	// through its show, most recent first, with the total. Credits carry the media ID as target
+	// and the direct role over the show role. publicOnly leaves out unlisted and private media.
+	GetAppearances(ctx context.Context, personID string, publicOnly bool, limit, offset int) ([]*domain.PersonCredit, int64, error)
+}
+
+// postgresPersonRepository implements PersonRepository using PostgreSQL
+type postgresPersonRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresPersonRepository creates a new PostgreSQL person repository
+func NewPostgresPersonRepository(conn *database.Connection) PersonRepository {
+	return &postgresPersonRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new person
+func (r *postgresPersonRepository) Create(ctx context.Context, person *domain.Person) error {
+	return r.db.WithContext(ctx).Create(person).Error
+}
+
+// GetByID retrieves a person by ID
+func (r *postgresPersonRepository) GetByID(ctx context.Context, id string) (*domain.Person, error) {
+	var person domain.Person
+	err := r.db.WithContext(ctx).First(&person, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPersonNotFound
+		}
+		return nil, err
+	}
+
+	return &person, nil
+}
+
+// GetByIDs retrieves the existing people among ids
+func (r *postgresPersonRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Person, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var people []domain.Person
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&people).Error; err != nil {
+		return nil, err
+	}
+
+	return personPointers(people), nil
+}
+
+// GetAll retrieves a page of people ordered by name, with the total
+func (r *postgresPersonRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Person, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&domain.Person{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var people []domain.Person
+	err := r.db.WithContext(ctx).
+		Order("name ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&people).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return personPointers(people), total, nil
+}
+
+// Update updates an existing person
+func (r *postgresPersonRepository) Update(ctx context.Context, person *domain.Person) error {
+	return r.db.WithContext(ctx).Save(person).Error
+}
+
+// Delete removes a person and their credits
+func (r *postgresPersonRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&domain.Person{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrPersonNotFound
+		}
+
+		return tx.Delete(&domain.PersonCredit{}, "person_id = ?", id).Error
+	})
+}
+
+// SetCredits replaces the people credited on a target
+func (r *postgresPersonRepository) SetCredits(ctx context.Context, targetType domain.CreditTarget, targetID string, credits []*domain.PersonCredit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Delete(&domain.PersonCredit{}, "target_type = ? AND target_id = ?", string(targetType), targetID).Error
+		if err != nil {
+			return err
+		}
+		if len(credits) == 0 {
+			return nil
+		}
+
+		return tx.Create(credits).Error
+	})
+}
+
+// GetCredits retrieves the credits of targets in their order
+func (r *postgresPersonRepository) GetCredits(ctx context.Context, targetType domain.CreditTarget, targetIDs []string) ([]*domain.PersonCredit, error) {
+	if len(targetIDs) == 0 {
+		return nil, nil
+	}
+
+	var credits []domain.PersonCredit
+	err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id IN ?", string(targetType), targetIDs).
+		Order("target_id ASC, position ASC").
+		Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return creditPointers(credits), nil
+}
+
+// GetCreditsByPerson retrieves every credit of a person
+func (r *postgresPersonRepository) GetCreditsByPerson(ctx context.Context, personID string) ([]*domain.PersonCredit, error) {
+	var credits []domain.PersonCredit
+	err := r.db.WithContext(ctx).
+		Where("person_id = ?", personID).
+		Order("target_type ASC, created_at ASC, target_id ASC").
+		Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return creditPointers(credits), nil
+}
+
+// GetAppearances retrieves a page of the ready media a person is credited on, directly or
+// through its show, most recent first, with the total
+func (r *postgresPersonRepository) GetAppearances(ctx context.Context, personID string, publicOnly bool, limit, offset int) ([]*domain.PersonCredit, int64, error) {
+	appearances := func() *gorm.DB {
+		query := r.db.WithContext(ctx).
+			Table("media_files").
+			Joins("LEFT JOIN person_credits mc ON mc.target_type = ? AND mc.target_id = media_files.id AND mc.person_id = ?",
+				string(domain.CreditTargetMedia), personID).
+			Joins("LEFT JOIN person_credits sc ON sc.target_type = ? AND sc.target_id = media_files.show_id AND sc.person_id = ?",
+				string(domain.CreditTargetShow), personID).
+			Where("(mc.person_id IS NOT NULL OR sc.person_id IS NOT NULL)").
+			Where("media_files.status = ? AND media_files.deleted_at IS NULL", string(domain.StatusReady))
+		if publicOnly {
+			// Records created before visibility existed are public
+			query = query.Where("(media_files.visibility = ? OR media_files.visibility = '')", string(domain.VisibilityPublic))
+		}
+		return query
+	}
+
+	var total int64
+	if err := appearances().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		MediaID string
+		Role    string
+	}
+	err := appearances().
+		Select("media_files.id AS media_id, COALESCE(mc.role, sc.role) AS role").
+		Order("media_files.created_at DESC, media_files.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	credits := make([]*domain.PersonCredit, len(rows))
+	for i, row := range rows {
+		credits[i] = &domain.PersonCredit{
+			PersonID:   personID,
+			TargetType: domain.CreditTargetMedia,
+			TargetID:   row.MediaID,
+			Role:       domain.PersonRole(row.Role),
+		}
+	}
+
+	return credits, total, nil
+}
+
+// personPointers converts loaded people into the pointers the interface returns
+func personPointers(people []domain.Person) []*domain.Person {
+	result := make([]*domain.Person, len(people))
+	for i := range people {
+		result[i] = &people[i]
+	}
+	return result
+}
+
+// creditPointers converts loaded credits into the pointers the interface returns
+func creditPointers(credits []domain.PersonCredit) []*domain.PersonCredit {
+	result := make([]*domain.PersonCredit, len(credits))
+	for i := range credits {
+		result[i] = &credits[i]
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPersonRepositoryInterface ensures the mock satisfies the PersonRepository interface
+func TestPersonRepositoryInterface(t *testing.T) {
+	var _ PersonRepository = (*MockPersonRepository)(nil)
+}
+
+// MockPersonRepository can be used in tests
+type MockPersonRepository struct{}
+
+func (m *MockPersonRepository) Create(ctx context.Context, person *domain.Person) error {
+	return nil
+}
+
+func (m *MockPersonRepository) GetByID(ctx context.Context, id string) (*domain.Person, error) {
+	return nil, domain.ErrPersonNotFound
+}
+
+func (m *MockPersonRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Person, error) {
+	return nil, nil
+}
+
+func (m *MockPersonRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Person, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockPersonRepository) Update(ctx context.Context, person *domain.Person) error {
+	return nil
+}
+
+func (m *MockPersonRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *MockPersonRepository) SetCredits(ctx context.Context, targetType domain.CreditTarget, targetID string, credits []*domain.PersonCredit) error {
+	return nil
+}
+
+func (m *MockPersonRepository) GetCredits(ctx context.Context, targetType domain.CreditTarget, targetIDs []string) ([]*domain.PersonCredit, error) {
+	return nil, nil
+}
+
+func (m *MockPersonRepository) GetCreditsByPerson(ctx context.Context, personID string) ([]*domain.PersonCredit, error) {
+	return nil, nil
+}
+
+func (m *MockPersonRepository) GetAppearances(ctx context.Context, personID string, publicOnly bool, limit, offset int) ([]*domain.PersonCredit, int64, error) {
+	return nil, 0, nil
+}
+
+func TestPersonRepository(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresPersonRepository(conn)
+	mediaRepo := NewSQLiteMediaRepository(conn)
+
+	require.NoError(t, repo.Create(ctx, &domain.Person{ID: "p1", Name: "Sara"}))
+	require.NoError(t, repo.Create(ctx, &domain.Person{ID: "p2", Name: "Adam"}))
+
+	now := time.Now().UTC()
+	for i, media := range []*domain.Media{
+		{ID: "m1", Title: "Interview", ShowID: "show-1", Visibility: domain.VisibilityPublic, Status: domain.StatusReady},
+		{ID: "m2", Title: "Episode 2", ShowID: "show-1", Visibility: domain.VisibilityPublic, Status: domain.StatusReady},
+		{ID: "m3", Title: "Unlisted", ShowID: "show-1", Visibility: domain.VisibilityUnlisted, Status: domain.StatusReady},
+		{ID: "m4", Title: "Processing", ShowID: "show-1", Visibility: domain.VisibilityPublic, Status: domain.StatusProcessing},
+		{ID: "m5", Title: "Other show", ShowID: "show-2", Visibility: domain.VisibilityPublic, Status: domain.StatusReady},
+	} {
+		media.Type = domain.TypePodcast
+		media.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, mediaRepo.Create(ctx, media))
+	}
+
+	t.Run("lists people by name", func(t *testing.T) {
+		people, total, err := repo.GetAll(ctx, 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, people, 1)
+		assert.Equal(t, "Adam", people[0].Name)
+	})
+
+	t.Run("replaces credits in order", func(t *testing.T) {
+		require.NoError(t, repo.SetCredits(ctx, domain.CreditTargetMedia, "m1", []*domain.PersonCredit{
+			{PersonID: "p1", TargetType: domain.CreditTargetMedia, TargetID: "m1", Role: domain.PersonRoleGuest},
+		}))
+		require.NoError(t, repo.SetCredits(ctx, domain.CreditTargetMedia, "m1", []*domain.PersonCredit{
+			{PersonID: "p2", TargetType: domain.CreditTargetMedia, TargetID: "m1", Role: domain.PersonRoleHost, Position: 0},
+			{PersonID: "p1", TargetType: domain.CreditTargetMedia, TargetID: "m1", Role: domain.PersonRoleGuest, Position: 1},
+		}))
+
+		credits, err := repo.GetCredits(ctx, domain.CreditTargetMedia, []string{"m1"})
+		require.NoError(t, err)
+		require.Len(t, credits, 2)
+		assert.Equal(t, "p2", credits[0].PersonID)
+		assert.Equal(t, "p1", credits[1].PersonID)
+	})
+
+	t.Run("lists appearances through shows", func(t *testing.T) {
+		require.NoError(t, repo.SetCredits(ctx, domain.CreditTargetShow, "show-1", []*domain.PersonCredit{
+			{PersonID: "p1", TargetType: domain.CreditTargetShow, TargetID: "show-1", Role: domain.PersonRoleHost},
+		}))
+
+		// The direct guest credit on m1 wins over the show credit, unready media is left out
+		credits, total, err := repo.GetAppearances(ctx, "p1", true, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, credits, 2)
+		assert.Equal(t, "m2", credits[0].TargetID)
+		assert.Equal(t, domain.PersonRoleHost, credits[0].Role)
+		assert.Equal(t, "m1", credits[1].TargetID)
+		assert.Equal(t, domain.PersonRoleGuest, credits[1].Role)
+
+		// Admins also see unlisted media
+		_, total, err = repo.GetAppearances(ctx, "p1", false, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+
+		byPerson, err := repo.GetCreditsByPerson(ctx, "p1")
+		require.NoError(t, err)
+		assert.Len(t, byPerson, 2)
+	})
+
+	t.Run("deletes people with their credits", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, "p1"))
+		assert.ErrorIs(t, repo.Delete(ctx, "p1"), domain.ErrPersonNotFound)
+		_, err := repo.GetByID(ctx, "p1")
+		assert.ErrorIs(t, err, domain.ErrPersonNotFound)
+
+		credits, err := repo.GetCredits(ctx, domain.CreditTargetMedia, []string{"m1"})
+		require.NoError(t, err)
+		require.Len(t, credits, 1)
+		assert.Equal(t, "p2", credits[0].PersonID)
+	})
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"thamaniyah/internal/domain"
 	"thamaniyah/pkg/database"
@@ -24,7 +26,7 @@ func NewPostgresMediaRepository(conn *database.Connection) MediaRepository {
 
 // Create creates a new media record
 func (r *postgresMediaRepository) Create(ctx context.Context, media *domain.Media) error {
-	if err := r.db.WithContext(ctx).Create(media).Error; err != nil {
+	if err := database.DB(ctx, r.db).Create(media).Error; err != nil {
 		return err
 	}
 	return nil
@@ -34,7 +36,7 @@ func (r *postgresMediaRepository) Create(ctx context.Context, media *domain.Medi
 func (r *postgresMediaRepository) GetByID(ctx context.Context, id string) (*domain.Media, error) {
 	var media domain.Media
 
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&media).Error
+	err := r.liveMedia(ctx).Where("id = ?", id).First(&media).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrMediaNotFound
@@ -45,11 +47,34 @@ func (r *postgresMediaRepository) GetByID(ctx context.Context, id string) (*doma
 	return &media, nil
 }
 
+// GetByIDs retrieves the existing media records among ids
+func (r *postgresMediaRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	if len(ids) == 0 {
+		return []*domain.Media{}, nil
+	}
+
+	var mediaList []domain.Media
+
+	err := r.liveMedia(ctx).
+		Where("id IN ?", ids).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
 // GetAll retrieves all media records with pagination
 func (r *postgresMediaRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Media, error) {
 	var mediaList []domain.Media
 
-	err := r.db.WithContext(ctx).
+	err := r.liveMedia(ctx).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -66,9 +91,82 @@ func (r *postgresMediaRepository) GetAll(ctx context.Context, limit, offset int)
 	return result, nil
 }
 
+// mediaWithTotal is a media row carrying the window count of its query
+type mediaWithTotal struct {
+	domain.Media `gorm:"embedded"`
+	TotalCount   int64
+}
+
+// GetAllWithTotal retrieves a page of media records together with the total
+// count, computed by a window function in the same query
+func (r *postgresMediaRepository) GetAllWithTotal(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error) {
+	var rows []mediaWithTotal
+
+	err := r.liveMedia(ctx).
+		Model(&domain.Media{}).
+		Select("*, COUNT(*) OVER() AS total_count").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// A page past the end has no row to carry the count
+	if len(rows) == 0 {
+		total, err := r.GetTotal(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*domain.Media{}, total, nil
+	}
+
+	result := make([]*domain.Media, len(rows))
+	for i := range rows {
+		result[i] = &rows[i].Media
+	}
+
+	return result, rows[0].TotalCount, nil
+}
+
+// GetByOwner retrieves a page of the media records a user owns together with
+// their total count, computed by a window function in the same query
+func (r *postgresMediaRepository) GetByOwner(ctx context.Context, ownerID string, limit, offset int) ([]*domain.Media, int64, error) {
+	var rows []mediaWithTotal
+
+	err := r.liveMedia(ctx).
+		Model(&domain.Media{}).
+		Select("*, COUNT(*) OVER() AS total_count").
+		Where("owner_id = ?", ownerID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// A page past the end has no row to carry the count
+	if len(rows) == 0 {
+		var total int64
+		if err := r.liveMedia(ctx).Model(&domain.Media{}).Where("owner_id = ?", ownerID).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		return []*domain.Media{}, total, nil
+	}
+
+	result := make([]*domain.Media, len(rows))
+	for i := range rows {
+		result[i] = &rows[i].Media
+	}
+
+	return result, rows[0].TotalCount, nil
+}
+
 // Update updates an existing media record
 func (r *postgresMediaRepository) Update(ctx context.Context, media *domain.Media) error {
-	result := r.db.WithContext(ctx).
+	result := database.DB(ctx, r.db).
 		Model(&domain.Media{}).
 		Where("id = ?", media.ID).
 		Updates(media)
@@ -84,9 +182,12 @@ func (r *postgresMediaRepository) Update(ctx context.Context, media *domain.Medi
 	return nil
 }
 
-// Delete soft deletes a media record by ID
+// Delete soft deletes a media record by ID, moving it to the trash
 func (r *postgresMediaRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&domain.Media{}, "id = ?", id)
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", time.Now())
 
 	if result.Error != nil {
 		return result.Error
@@ -99,11 +200,70 @@ func (r *postgresMediaRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// GetTrashed retrieves media deleted at or before deletedBefore, longest deleted first
+func (r *postgresMediaRepository) GetTrashed(ctx context.Context, deletedBefore time.Time, limit int) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := database.DB(ctx, r.db).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", deletedBefore).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
+// Purge permanently removes a media record from the trash
+func (r *postgresMediaRepository) Purge(ctx context.Context, id string) error {
+	result := database.DB(ctx, r.db).Delete(&domain.Media{}, "id = ? AND deleted_at IS NOT NULL", id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// GetByIDsWithTrashed retrieves the media records among ids, those in the trash included
+func (r *postgresMediaRepository) GetByIDsWithTrashed(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	if len(ids) == 0 {
+		return []*domain.Media{}, nil
+	}
+
+	var mediaList []domain.Media
+
+	err := database.DB(ctx, r.db).
+		Where("id IN ?", ids).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
 // GetByStatus retrieves media records by status
 func (r *postgresMediaRepository) GetByStatus(ctx context.Context, status domain.MediaStatus, limit, offset int) ([]*domain.Media, error) {
 	var mediaList []domain.Media
 
-	err := r.db.WithContext(ctx).
+	err := r.liveMedia(ctx).
 		Where("status = ?", string(status)).
 		Order("created_at DESC").
 		Limit(limit).
@@ -121,11 +281,33 @@ func (r *postgresMediaRepository) GetByStatus(ctx context.Context, status domain
 	return result, nil
 }
 
+// GetByFilter retrieves media records matching the filter
+func (r *postgresMediaRepository) GetByFilter(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := r.applyFilter(r.liveMedia(ctx), filter).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
 // UpdateStatus updates only the status of a media record
 func (r *postgresMediaRepository) UpdateStatus(ctx context.Context, id string, status domain.MediaStatus) error {
-	result := r.db.WithContext(ctx).
+	// Deleted media stays deleted, even when processing it ends afterwards
+	result := database.DB(ctx, r.db).
 		Model(&domain.Media{}).
-		Where("id = ?", id).
+		Where("id = ? AND status <> ?", id, string(domain.StatusDeleted)).
 		Update("status", string(status))
 
 	if result.Error != nil {
@@ -139,11 +321,446 @@ func (r *postgresMediaRepository) UpdateStatus(ctx context.Context, id string, s
 	return nil
 }
 
+// UpdateFailure sets or clears the failure reason of a media record
+func (r *postgresMediaRepository) UpdateFailure(ctx context.Context, id, code, message string) error {
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"failure_code":    code,
+			"failure_message": message,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateGeoRestriction replaces the country rules of a media record
+func (r *postgresMediaRepository) UpdateGeoRestriction(ctx context.Context, id string, restriction domain.GeoRestriction) error {
+	// Select forces empty lists to be written so rules can be cleared
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("allowed_countries", "blocked_countries").
+		Updates(&domain.Media{GeoRestriction: restriction})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateClassification replaces the labels and category of a media record
+func (r *postgresMediaRepository) UpdateClassification(ctx context.Context, id string, labels []string, category string) error {
+	if labels == nil {
+		labels = []string{}
+	}
+
+	// Select forces the labels and category to be written when they are cleared
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("labels", "category").
+		Updates(&domain.Media{Labels: labels, Category: category})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateContentRating replaces the age rating and explicit flag of a media record
+func (r *postgresMediaRepository) UpdateContentRating(ctx context.Context, id string, rating domain.ContentRating) error {
+	// Select forces the explicit flag to be written when it is cleared
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("age_rating", "explicit").
+		Updates(&domain.Media{ContentRating: rating})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateLicense replaces the rights window of a media record
+func (r *postgresMediaRepository) UpdateLicense(ctx context.Context, id string, license domain.License) error {
+	// Select forces cleared dates to be written
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("license_source", "license_starts_at", "license_ends_at", "license_expiry_notified_at").
+		Updates(&domain.Media{License: license})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateSeries links a media record to a series part, or unlinks it given an empty series ID
+func (r *postgresMediaRepository) UpdateSeries(ctx context.Context, id, seriesID string, part int, locked bool) error {
+	// Select forces the series to be written when it is cleared or unlocked
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("series_id", "series_part", "series_locked").
+		Updates(&domain.Media{SeriesID: seriesID, SeriesPart: part, SeriesLocked: locked})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// GetBySeries retrieves the media of a series that is not deleted, in part order
+func (r *postgresMediaRepository) GetBySeries(ctx context.Context, seriesID string) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := database.DB(ctx, r.db).
+		Where("series_id = ? AND status <> ?", seriesID, string(domain.StatusDeleted)).
+		Order("series_part ASC, created_at ASC, id ASC").
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
+// UpdateVisibility changes only the visibility of a media record
+func (r *postgresMediaRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.MediaVisibility) error {
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Update("visibility", string(visibility))
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateCuePoints replaces the ad breaks of a media record
+func (r *postgresMediaRepository) UpdateCuePoints(ctx context.Context, id string, cuePoints []domain.CuePoint) error {
+	if cuePoints == nil {
+		cuePoints = []domain.CuePoint{}
+	}
+
+	// Select forces the cue points to be written when they are cleared
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("cue_points").
+		Updates(&domain.Media{CuePoints: cuePoints})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateChapters replaces the chapters and chapter suggestions of a media record
+func (r *postgresMediaRepository) UpdateChapters(ctx context.Context, id string, chapters []domain.Chapter, suggestions []domain.ChapterSuggestion) error {
+	if chapters == nil {
+		chapters = []domain.Chapter{}
+	}
+	if suggestions == nil {
+		suggestions = []domain.ChapterSuggestion{}
+	}
+
+	// Select forces both lists to be written when they are cleared
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("chapters", "chapter_suggestions").
+		Updates(&domain.Media{Chapters: chapters, ChapterSuggestions: suggestions})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateAudioTracks replaces the dubbed audio tracks of a media record
+func (r *postgresMediaRepository) UpdateAudioTracks(ctx context.Context, id string, tracks []domain.AudioTrack) error {
+	if tracks == nil {
+		tracks = []domain.AudioTrack{}
+	}
+
+	// Select forces the list to be written when it is cleared
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("audio_tracks").
+		Updates(&domain.Media{AudioTracks: tracks})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdateUnpublishAt schedules or, given nil, cancels the unpublishing of a media record
+func (r *postgresMediaRepository) UpdateUnpublishAt(ctx context.Context, id string, unpublishAt *time.Time) error {
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Update("unpublish_at", unpublishAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// GetDueUnpublishes retrieves published media scheduled to be unpublished at or before now
+func (r *postgresMediaRepository) GetDueUnpublishes(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := r.publishedMedia(ctx).
+		Where("unpublish_at <= ?", now).
+		Order("unpublish_at ASC").
+		Limit(limit).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
+// UpdatePremiere replaces the premiere of a media record
+func (r *postgresMediaRepository) UpdatePremiere(ctx context.Context, id string, premiere domain.Premiere) error {
+	// Select forces a cancelled premiere to be written
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("premiere_starts_at", "premiere_announced_at").
+		Updates(&domain.Media{Premiere: premiere})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// UpdatePoster replaces the poster of a media record
+func (r *postgresMediaRepository) UpdatePoster(ctx context.Context, id string, poster domain.Poster) error {
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Select("poster_key", "poster_timecode").
+		Updates(&domain.Media{Poster: poster})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// GetDuePremieres retrieves published media whose premiere started at or before now
+// and was not announced yet
+func (r *postgresMediaRepository) GetDuePremieres(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := r.publishedMedia(ctx).
+		Where("premiere_starts_at <= ?", now).
+		Where("premiere_announced_at IS NULL").
+		Order("premiere_starts_at ASC").
+		Limit(limit).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
+// GetScheduledBetween retrieves media that is not deleted and whose license starts or
+// ends, or which is unpublished or premieres, within [from, end)
+func (r *postgresMediaRepository) GetScheduledBetween(ctx context.Context, from, end time.Time) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := database.DB(ctx, r.db).
+		Where("status <> ?", string(domain.StatusDeleted)).
+		Where("(license_starts_at >= ? AND license_starts_at < ?) OR (license_ends_at >= ? AND license_ends_at < ?) OR (unpublish_at >= ? AND unpublish_at < ?) OR (premiere_starts_at >= ? AND premiere_starts_at < ?)",
+			from, end, from, end, from, end, from, end).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
+// GetExpiredLicenses retrieves published media whose license ended at or before now
+func (r *postgresMediaRepository) GetExpiredLicenses(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := r.publishedMedia(ctx).
+		Where("license_ends_at <= ?", now).
+		Order("license_ends_at ASC").
+		Limit(limit).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
+// GetLicensesExpiringBefore retrieves published media whose license ends after now and at
+// or before the deadline, and whose owners were not warned yet
+func (r *postgresMediaRepository) GetLicensesExpiringBefore(ctx context.Context, now, deadline time.Time, limit int) ([]*domain.Media, error) {
+	var mediaList []domain.Media
+
+	err := r.publishedMedia(ctx).
+		Where("license_ends_at > ? AND license_ends_at <= ?", now, deadline).
+		Where("license_expiry_notified_at IS NULL").
+		Order("license_ends_at ASC").
+		Limit(limit).
+		Find(&mediaList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Media, len(mediaList))
+	for i := range mediaList {
+		result[i] = &mediaList[i]
+	}
+
+	return result, nil
+}
+
+// liveMedia starts a query over media that is not in the trash
+func (r *postgresMediaRepository) liveMedia(ctx context.Context) *gorm.DB {
+	return database.DB(ctx, r.db).Where("deleted_at IS NULL")
+}
+
+// publishedMedia starts a query over ready media that is not private
+func (r *postgresMediaRepository) publishedMedia(ctx context.Context) *gorm.DB {
+	return database.DB(ctx, r.db).
+		Where("status = ?", string(domain.StatusReady)).
+		Where("visibility <> ?", string(domain.VisibilityPrivate))
+}
+
+// IncrementProcessingAttempts atomically bumps the processing attempt counter
+func (r *postgresMediaRepository) IncrementProcessingAttempts(ctx context.Context, id string) error {
+	result := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Where("id = ?", id).
+		Update("processing_attempts", gorm.Expr("processing_attempts + 1"))
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMediaNotFound
+	}
+
+	return nil
+}
+
 // GetTotal returns the total count of media records
 func (r *postgresMediaRepository) GetTotal(ctx context.Context) (int64, error) {
 	var count int64
 
-	err := r.db.WithContext(ctx).
+	err := r.liveMedia(ctx).
 		Model(&domain.Media{}).
 		Count(&count).Error
 	if err != nil {
@@ -152,3 +769,96 @@ func (r *postgresMediaRepository) GetTotal(ctx context.Context) (int64, error) {
 
 	return count, nil
 }
+
+// GetEstimatedTotal returns the row count estimate the planner keeps for the
+// media table, falling back to an exact count until the table was analyzed
+func (r *postgresMediaRepository) GetEstimatedTotal(ctx context.Context) (int64, error) {
+	var estimate float64
+
+	err := database.DB(ctx, r.db).
+		Raw("SELECT reltuples FROM pg_class WHERE oid = ?::regclass", domain.Media{}.TableName()).
+		Scan(&estimate).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// reltuples is -1 for tables that were never vacuumed or analyzed
+	if estimate < 0 {
+		return r.GetTotal(ctx)
+	}
+
+	return int64(estimate), nil
+}
+
+// GetTotalByFilter returns the count of media records matching the filter
+func (r *postgresMediaRepository) GetTotalByFilter(ctx context.Context, filter *domain.MediaFilter) (int64, error) {
+	var count int64
+
+	err := r.applyFilter(r.liveMedia(ctx).Model(&domain.Media{}), filter).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetStorageUsage returns the total file size of the media of a tenant that is not deleted
+func (r *postgresMediaRepository) GetStorageUsage(ctx context.Context, tenantID string) (int64, error) {
+	var usage int64
+
+	err := database.DB(ctx, r.db).
+		Model(&domain.Media{}).
+		Select("COALESCE(SUM(file_size), 0)").
+		Where("tenant_id = ? AND status <> ?", tenantID, string(domain.StatusDeleted)).
+		Scan(&usage).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return usage, nil
+}
+
+// GetByContentHash retrieves a media record of a tenant that is not deleted by its content hash
+func (r *postgresMediaRepository) GetByContentHash(ctx context.Context, tenantID, contentHash string) (*domain.Media, error) {
+	var media domain.Media
+
+	err := database.DB(ctx, r.db).
+		Where("tenant_id = ? AND content_hash = ? AND status <> ?", tenantID, strings.ToLower(contentHash), string(domain.StatusDeleted)).
+		Order("created_at").
+		First(&media).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return &media, nil
+}
+
+// applyFilter adds the filter conditions to a media query
+func (r *postgresMediaRepository) applyFilter(query *gorm.DB, filter *domain.MediaFilter) *gorm.DB {
+	if filter == nil {
+		return query
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.FailureCode != "" {
+		query = query.Where("failure_code = ?", filter.FailureCode)
+	}
+	if filter.Visibility != "" {
+		query = query.Where("visibility = ?", string(filter.Visibility))
+	}
+	if filter.Safe {
+		query = query.Where("explicit = ? AND age_rating <> ?", false, string(domain.AgeRating18))
+	}
+	if filter.ShowID != "" {
+		query = query.Where("show_id = ?", filter.ShowID)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	return query
+}
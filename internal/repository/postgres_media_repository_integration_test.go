@@ -0,0 +1,71 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresMediaRepository_Integration(t *testing.T) {
+	ctx := context.Background()
+	pg := testsupport.StartPostgres(t)
+	testsupport.SeedMedia(t, pg.Conn, testsupport.MediaFixtures()...)
+	repo := NewPostgresMediaRepository(pg.Conn)
+
+	t.Run("lists newest first", func(t *testing.T) {
+		list, err := repo.GetAll(ctx, 2, 0)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "fixture-private", list[0].ID)
+	})
+
+	t.Run("estimates the total from planner statistics", func(t *testing.T) {
+		require.NoError(t, pg.Conn.DB.Exec("ANALYZE media_files").Error)
+
+		total, err := repo.GetEstimatedTotal(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(testsupport.MediaFixtures())), total)
+	})
+
+	t.Run("filters by status and visibility", func(t *testing.T) {
+		total, err := repo.GetTotalByFilter(ctx, &domain.MediaFilter{Status: domain.StatusReady, Visibility: domain.VisibilityPublic})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("clears geo restriction", func(t *testing.T) {
+		require.NoError(t, repo.UpdateGeoRestriction(ctx, "fixture-video", domain.GeoRestriction{BlockedCountries: []string{"US"}}))
+		require.NoError(t, repo.UpdateGeoRestriction(ctx, "fixture-video", domain.GeoRestriction{}))
+
+		media, err := repo.GetByID(ctx, "fixture-video")
+		require.NoError(t, err)
+		assert.Empty(t, media.GeoRestriction.BlockedCountries)
+	})
+
+	t.Run("finds expired licenses", func(t *testing.T) {
+		endedAt := time.Now().Add(-time.Hour)
+		require.NoError(t, repo.UpdateLicense(ctx, "fixture-podcast", domain.License{EndsAt: &endedAt}))
+
+		expired, err := repo.GetExpiredLicenses(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		assert.Equal(t, "fixture-podcast", expired[0].ID)
+	})
+
+	t.Run("increments attempts atomically", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repo.IncrementProcessingAttempts(ctx, "fixture-processing"))
+		}
+		media, err := repo.GetByID(ctx, "fixture-processing")
+		require.NoError(t, err)
+		assert.Equal(t, 3, media.ProcessingAttempts)
+	})
+}
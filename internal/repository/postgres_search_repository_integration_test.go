@@ -0,0 +1,103 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresSearchRepository_ReindexAll_Integration(t *testing.T) {
+	ctx := context.Background()
+	pg := testsupport.StartPostgres(t)
+	repo := NewPostgresSearchRepository(pg.Conn, 2)
+
+	indexesBefore, err := tableIndexes(pg.Conn.DB, "search_index")
+	require.NoError(t, err)
+
+	// Given a stale entry in the current index
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "stale", Title: "Stale Golang Entry", Type: domain.TypeVideo}))
+
+	// When the index is rebuilt twice from the fixtures
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.ReindexAll(ctx, testsupport.MediaFixtures()))
+	}
+
+	// Then the swapped-in index holds only the fixtures
+	var ids []string
+	require.NoError(t, pg.Conn.DB.Model(&domain.SearchIndex{}).Order("id").Pluck("media_id", &ids).Error)
+	assert.Equal(t, []string{"fixture-podcast", "fixture-private", "fixture-processing", "fixture-video"}, ids)
+
+	// And keeps the index names the migrations created
+	indexesAfter, err := tableIndexes(pg.Conn.DB, "search_index")
+	require.NoError(t, err)
+	assert.Equal(t, indexesBefore, indexesAfter)
+	assert.False(t, pg.Conn.DB.Migrator().HasTable(searchIndexShadowTable))
+}
+
+func TestPostgresSearchRepository_ReindexAll_ReplaysWrites_Integration(t *testing.T) {
+	ctx := context.Background()
+	pg := testsupport.StartPostgres(t)
+	repo := NewPostgresSearchRepository(pg.Conn, 0).(*PostgresSearchRepository)
+	fixtures := testsupport.MediaFixtures()
+
+	// Given a rebuild started before an update, an upload and a removal
+	started := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+	repo.now = func() time.Time { return started }
+	renamed := *fixtures[0]
+	renamed.Title = "Renamed Golang Talk"
+	renamed.UpdatedAt = time.Now()
+	require.NoError(t, repo.IndexMedia(ctx, &renamed))
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "uploaded", Title: "Uploaded Meanwhile", Type: domain.TypeVideo, UpdatedAt: time.Now()}))
+	require.NoError(t, repo.RemoveFromIndex(ctx, fixtures[1].ID))
+
+	// When the rebuild swaps in the index built from the earlier media list
+	require.NoError(t, repo.ReindexAll(ctx, fixtures))
+
+	// Then the writes made meanwhile are kept
+	var entries []*domain.SearchIndex
+	require.NoError(t, pg.Conn.DB.Order("media_id").Find(&entries).Error)
+	titles := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		titles[entry.MediaID] = entry.Title
+	}
+	assert.Equal(t, map[string]string{
+		"fixture-private":    "Private Golang Draft",
+		"fixture-processing": "Unfinished Upload",
+		"fixture-video":      "Renamed Golang Talk",
+		"uploaded":           "Uploaded Meanwhile",
+	}, titles)
+}
+
+func TestPostgresSearchRepository_Suggest_Integration(t *testing.T) {
+	ctx := context.Background()
+	pg := testsupport.StartPostgres(t)
+	repo := NewPostgresSearchRepository(pg.Conn, 0)
+	require.NoError(t, repo.ReindexAll(ctx, testsupport.MediaFixtures()))
+	require.NoError(t, NewSuggestionRepository(pg.Conn).RefreshSuggestions(ctx))
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{name: "substring", query: "concurr", expected: "Golang Concurrency Patterns"},
+		{name: "typo", query: "podcst", expected: "Arabic Tech Podcast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, err := repo.Suggest(ctx, &domain.SuggestRequest{Query: tt.query, Limit: 5})
+			require.NoError(t, err)
+			require.NotEmpty(t, suggestions)
+			assert.Equal(t, tt.expected, suggestions[0].Text)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProgressRepository defines the contract for playback progress data access
+type ProgressRepository interface {
+	// Save creates or replaces the progress of a user in a media item
+	Save(ctx context.Context, progress *domain.PlaybackProgress) error
+
+	// GetInProgress retrieves the unfinished media of a user, most recently played first
+	GetInProgress(ctx context.Context, userID string, limit int) ([]*domain.PlaybackProgress, error)
+
+	// DeleteByUser removes the progress of a user in every media item
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// postgresProgressRepository implements ProgressRepository using PostgreSQL
+type postgresProgressRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresProgressRepository creates a new PostgreSQL playback progress repository
+func NewPostgresProgressRepository(conn *database.Connection) ProgressRepository {
+	return &postgresProgressRepository{
+		db: conn.DB,
+	}
+}
+
+// Save creates or replaces the progress of a user in a media item
+func (r *postgresProgressRepository) Save(ctx context.Context, progress *domain.PlaybackProgress) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position_seconds", "completed", "last_played_at", "updated_at"}),
+	}).Create(progress).Error
+}
+
+// GetInProgress retrieves the unfinished media of a user, most recently played first
+func (r *postgresProgressRepository) GetInProgress(ctx context.Context, userID string, limit int) ([]*domain.PlaybackProgress, error) {
+	var progress []domain.PlaybackProgress
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND completed = ?", userID, false).
+		Order("last_played_at DESC").
+		Limit(limit).
+		Find(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.PlaybackProgress, len(progress))
+	for i := range progress {
+		result[i] = &progress[i]
+	}
+
+	return result, nil
+}
+
+// DeleteByUser removes the progress of a user in every media item
+func (r *postgresProgressRepository) DeleteByUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Delete(&domain.PlaybackProgress{}, "user_id = ?", userID).Error
+}
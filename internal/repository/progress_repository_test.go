@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProgressRepositoryInterface ensures the mock satisfies the ProgressRepository interface
+func TestProgressRepositoryInterface(t *testing.T) {
+	var _ ProgressRepository = (*MockProgressRepository)(nil)
+}
+
+// MockProgressRepository can be used in tests
+type MockProgressRepository struct{}
+
+func (m *MockProgressRepository) Save(ctx context.Context, progress *domain.PlaybackProgress) error {
+	return nil
+}
+
+func (m *MockProgressRepository) GetInProgress(ctx context.Context, userID string, limit int) ([]*domain.PlaybackProgress, error) {
+	return nil, nil
+}
+
+func (m *MockProgressRepository) DeleteByUser(ctx context.Context, userID string) error {
+	return nil
+}
+
+func TestProgressRepository_GetInProgress(t *testing.T) {
+	// Given progress of two users, one item finished
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresProgressRepository(conn)
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Save(ctx, &domain.PlaybackProgress{UserID: "u1", MediaID: "m1", PositionSeconds: 10, LastPlayedAt: base}))
+	require.NoError(t, repo.Save(ctx, &domain.PlaybackProgress{UserID: "u1", MediaID: "m2", PositionSeconds: 20, LastPlayedAt: base.Add(time.Minute)}))
+	require.NoError(t, repo.Save(ctx, &domain.PlaybackProgress{UserID: "u1", MediaID: "m3", PositionSeconds: 30, Completed: true, LastPlayedAt: base.Add(2 * time.Minute)}))
+	require.NoError(t, repo.Save(ctx, &domain.PlaybackProgress{UserID: "u2", MediaID: "m1", PositionSeconds: 40, LastPlayedAt: base}))
+
+	// When m1 is played again
+	require.NoError(t, repo.Save(ctx, &domain.PlaybackProgress{UserID: "u1", MediaID: "m1", PositionSeconds: 90, LastPlayedAt: base.Add(3 * time.Minute)}))
+	progress, err := repo.GetInProgress(ctx, "u1", 10)
+	require.NoError(t, err)
+
+	// Then the unfinished items of the user are listed, most recently played first
+	require.Len(t, progress, 2)
+	assert.Equal(t, "m1", progress[0].MediaID)
+	assert.Equal(t, 90, progress[0].PositionSeconds)
+	assert.Equal(t, "m2", progress[1].MediaID)
+
+	limited, err := repo.GetInProgress(ctx, "u1", 1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// RenditionRepository defines the contract for rendition data access
+type RenditionRepository interface {
+	// Replace replaces the renditions of a media item, as it is transcoded again
+	Replace(ctx context.Context, mediaID string, renditions []*domain.MediaRendition) error
+
+	// Update writes the status, keys, size and error of a rendition
+	Update(ctx context.Context, rendition *domain.MediaRendition) error
+
+	// ListByMediaID retrieves the renditions of a media item, largest first
+	ListByMediaID(ctx context.Context, mediaID string) ([]*domain.MediaRendition, error)
+}
+
+// postgresRenditionRepository implements RenditionRepository using PostgreSQL
+type postgresRenditionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRenditionRepository creates a new PostgreSQL rendition repository
+func NewPostgresRenditionRepository(conn *database.Connection) RenditionRepository {
+	return &postgresRenditionRepository{
+		db: conn.DB,
+	}
+}
+
+// Replace replaces the renditions of a media item
+func (r *postgresRenditionRepository) Replace(ctx context.Context, mediaID string, renditions []*domain.MediaRendition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", mediaID).Delete(&domain.MediaRendition{}).Error; err != nil {
+			return err
+		}
+		if len(renditions) == 0 {
+			return nil
+		}
+		return tx.Create(renditions).Error
+	})
+}
+
+// Update writes the status, keys, size and error of a rendition
+func (r *postgresRenditionRepository) Update(ctx context.Context, rendition *domain.MediaRendition) error {
+	// Select writes a cleared error too
+	result := r.db.WithContext(ctx).
+		Model(&domain.MediaRendition{}).
+		Where("id = ?", rendition.ID).
+		Select("status", "file_key", "playlist_key", "size", "error", "updated_at").
+		Updates(rendition)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrRenditionNotFound
+	}
+
+	return nil
+}
+
+// ListByMediaID retrieves the renditions of a media item, largest first
+func (r *postgresRenditionRepository) ListByMediaID(ctx context.Context, mediaID string) ([]*domain.MediaRendition, error) {
+	var renditions []*domain.MediaRendition
+	err := r.db.WithContext(ctx).
+		Where("media_id = ?", mediaID).
+		Order("height DESC, bitrate_kbps DESC, name ASC").
+		Find(&renditions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return renditions, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRenditionRepositoryInterface ensures the mock satisfies the RenditionRepository interface
+func TestRenditionRepositoryInterface(t *testing.T) {
+	var _ RenditionRepository = (*MockRenditionRepository)(nil)
+}
+
+// MockRenditionRepository can be used in tests
+type MockRenditionRepository struct{}
+
+func (m *MockRenditionRepository) Replace(ctx context.Context, mediaID string, renditions []*domain.MediaRendition) error {
+	return nil
+}
+
+func (m *MockRenditionRepository) Update(ctx context.Context, rendition *domain.MediaRendition) error {
+	return nil
+}
+
+func (m *MockRenditionRepository) ListByMediaID(ctx context.Context, mediaID string) ([]*domain.MediaRendition, error) {
+	return nil, nil
+}
+
+func TestRenditionRepository(t *testing.T) {
+	// Given the renditions of a transcoded video
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresRenditionRepository(conn)
+
+	var renditions []*domain.MediaRendition
+	for i, rendition := range domain.DefaultVideoRenditions {
+		renditions = append(renditions, domain.NewMediaRendition(string(rune('a'+i)), "m1", rendition))
+	}
+	require.NoError(t, repo.Replace(ctx, "m1", renditions))
+
+	// When one of them is transcoded
+	renditions[1].Status = domain.RenditionReady
+	renditions[1].FileKey = domain.RenditionFileKey("m1", "720p", "mp4")
+	renditions[1].Size = 1024
+	require.NoError(t, repo.Update(ctx, renditions[1]))
+
+	// Then the renditions are listed largest first with its state
+	list, err := repo.ListByMediaID(ctx, "m1")
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, []string{"1080p", "720p", "480p"}, []string{list[0].Name, list[1].Name, list[2].Name})
+	assert.Equal(t, domain.RenditionReady, list[1].Status)
+	assert.Equal(t, int64(1024), list[1].Size)
+	assert.Equal(t, domain.RenditionPending, list[0].Status)
+
+	// And transcoding again replaces them
+	require.NoError(t, repo.Replace(ctx, "m1", renditions[:1]))
+	list, err = repo.ListByMediaID(ctx, "m1")
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.ErrorIs(t, repo.Update(ctx, &domain.MediaRendition{ID: "missing"}), domain.ErrRenditionNotFound)
+}
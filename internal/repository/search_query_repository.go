@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchQueryRepository defines the contract for the daily search term counts
+type SearchQueryRepository interface {
+	// Increment counts a search of the term by the tenant on the day
+	Increment(ctx context.Context, day, tenantID, term string) error
+
+	// Top retrieves the terms a tenant searched the most within the days [fromDay, endDay)
+	Top(ctx context.Context, tenantID, fromDay, endDay string, limit int) ([]domain.DigestSearch, error)
+}
+
+// postgresSearchQueryRepository implements SearchQueryRepository using PostgreSQL
+type postgresSearchQueryRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresSearchQueryRepository creates a new PostgreSQL search query repository
+func NewPostgresSearchQueryRepository(conn *database.Connection) SearchQueryRepository {
+	return &postgresSearchQueryRepository{
+		db: conn.DB,
+	}
+}
+
+// Increment counts a search of the term by the tenant on the day
+func (r *postgresSearchQueryRepository) Increment(ctx context.Context, day, tenantID, term string) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "tenant_id"}, {Name: "term"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count": gorm.Expr("search_query_counts.count + 1"),
+		}),
+	}).Create(&domain.SearchQueryCount{Day: day, TenantID: tenantID, Term: term, Count: 1}).Error
+}
+
+// Top retrieves the terms a tenant searched the most within the days [fromDay, endDay)
+func (r *postgresSearchQueryRepository) Top(ctx context.Context, tenantID, fromDay, endDay string, limit int) ([]domain.DigestSearch, error) {
+	var searches []domain.DigestSearch
+	err := r.db.WithContext(ctx).
+		Model(&domain.SearchQueryCount{}).
+		Select("term, SUM(count) AS count").
+		Where("tenant_id = ? AND day >= ? AND day < ?", tenantID, fromDay, endDay).
+		Group("term").
+		Order("SUM(count) DESC, term ASC").
+		Limit(limit).
+		Scan(&searches).Error
+	return searches, err
+}
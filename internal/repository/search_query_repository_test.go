@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSearchQueryRepositoryInterface ensures the mock satisfies the SearchQueryRepository interface
+func TestSearchQueryRepositoryInterface(t *testing.T) {
+	var _ SearchQueryRepository = (*MockSearchQueryRepository)(nil)
+}
+
+// MockSearchQueryRepository can be used in tests
+type MockSearchQueryRepository struct{}
+
+func (m *MockSearchQueryRepository) Increment(ctx context.Context, day, tenantID, term string) error {
+	return nil
+}
+
+func (m *MockSearchQueryRepository) Top(ctx context.Context, tenantID, fromDay, endDay string, limit int) ([]domain.DigestSearch, error) {
+	return nil, nil
+}
+
+func TestSearchQueryRepository_IncrementAndTop(t *testing.T) {
+	// Given searches of two tenants over three days
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresSearchQueryRepository(conn)
+
+	for _, search := range []struct{ day, tenant, term string }{
+		{"2025-06-01", "acme", "jazz"},
+		{"2025-06-01", "acme", "jazz"},
+		{"2025-06-02", "acme", "jazz"},
+		{"2025-06-02", "acme", "news"},
+		{"2025-06-02", "acme", "interviews"},
+		{"2025-06-02", "acme", "interviews"},
+		{"2025-06-02", "globex", "news"},
+		{"2025-06-08", "acme", "news"},
+	} {
+		require.NoError(t, repo.Increment(ctx, search.day, search.tenant, search.term))
+	}
+
+	// When
+	top, err := repo.Top(ctx, "acme", "2025-06-01", "2025-06-08", 2)
+
+	// Then the counts of the days are summed, without the other tenant and days
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DigestSearch{
+		{Term: "jazz", Count: 3},
+		{Term: "interviews", Count: 2},
+	}, top)
+}
@@ -2,10 +2,17 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"thamaniyah/internal/domain"
 	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SearchRepository defines search operations
@@ -24,17 +31,37 @@ type SearchRepository interface {
 
 	// ReindexAll rebuilds the entire search index
 	ReindexAll(ctx context.Context, mediaList []*domain.Media) error
+
+	// ListIndexed lists every document of the search index with the update
+	// time of the media it was built from
+	ListIndexed(ctx context.Context) ([]*domain.IndexedMedia, error)
 }
 
+// defaultReindexBatchSize is the number of rows per insert statement when rebuilding the index
+const defaultReindexBatchSize = 500
+
+// searchRemovalRetention is how long removals are recorded for the rebuilds
+// running meanwhile, including rebuilds waiting for the one before them
+const searchRemovalRetention = 24 * time.Hour
+
 // PostgresSearchRepository implements SearchRepository using PostgreSQL
 type PostgresSearchRepository struct {
-	conn *database.Connection
+	conn             *database.Connection
+	reindexBatchSize int
+	now              func() time.Time
 }
 
-// NewPostgresSearchRepository creates a new PostgreSQL search repository
-func NewPostgresSearchRepository(conn *database.Connection) SearchRepository {
+// NewPostgresSearchRepository creates a new PostgreSQL search repository. Full
+// reindexes insert reindexBatchSize rows per statement, 0 uses the default.
+func NewPostgresSearchRepository(conn *database.Connection, reindexBatchSize int) SearchRepository {
+	if reindexBatchSize <= 0 {
+		reindexBatchSize = defaultReindexBatchSize
+	}
+
 	return &PostgresSearchRepository{
-		conn: conn,
+		conn:             conn,
+		reindexBatchSize: reindexBatchSize,
+		now:              time.Now,
 	}
 }
 
@@ -47,10 +74,19 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, req *domain.Searc
 	query := r.conn.DB.WithContext(ctx).Model(&domain.SearchIndex{})
 
 	// Full-text search on content field
-	if req.Query != "" {
-		query = query.Where("to_tsvector('english', content) @@ plainto_tsquery('english', ?)", req.Query).
-			Select("*, ts_rank(to_tsvector('english', content), plainto_tsquery('english', ?)) as rank", req.Query).
-			Order("rank DESC")
+	parsed := req.StructuredQuery()
+	rank, rankArgs := "0", []interface{}(nil)
+	if tsQuery, tsArgs := postgresTSQuery(parsed.Clauses); tsQuery != "" {
+		query = query.Where("to_tsvector('english', content) @@ ("+tsQuery+")", tsArgs...)
+		rank, rankArgs = "ts_rank(to_tsvector('english', content), "+tsQuery+")", tsArgs
+	}
+
+	// Filter by tags
+	for _, tag := range parsed.Tags {
+		query = query.Where("COALESCE(tags, '[]'::jsonb) @> ?::jsonb", jsonArray(tag))
+	}
+	for _, tag := range parsed.ExcludedTags {
+		query = query.Where("NOT COALESCE(tags, '[]'::jsonb) @> ?::jsonb", jsonArray(tag))
 	}
 
 	// Filter by type
@@ -58,9 +94,19 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, req *domain.Searc
 		query = query.Where("type = ?", req.Type)
 	}
 
-	// Count total results
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	// Filter by show
+	if req.ShowID != "" {
+		query = query.Where("show_id = ?", req.ShowID)
+	}
+
+	// Filter by available audio language
+	if req.AudioLanguage != "" {
+		query = query.Where("COALESCE(audio_languages, '[]'::jsonb) @> ?::jsonb", jsonArray(req.AudioLanguage))
+	}
+
+	// Exclude explicit content for safe search
+	if req.Safe {
+		query = query.Where("explicit = ?", false)
 	}
 
 	// Apply pagination
@@ -73,6 +119,18 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, req *domain.Searc
 		offset = 0
 	}
 
+	if req.Collapse == domain.SearchCollapseShow {
+		return r.searchCollapsed(ctx, query, rank, rankArgs, limit, offset)
+	}
+	if rankArgs != nil {
+		query = query.Select("*, "+rank+" as rank", rankArgs...).Order("rank DESC")
+	}
+
+	// Count total results
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
 	var searchIndexes []domain.SearchIndex
 	if err := query.Limit(limit).Offset(offset).Find(&searchIndexes).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to search: %w", err)
@@ -91,6 +149,62 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, req *domain.Searc
 	return results, total, nil
 }
 
+// postgresCollapsedRow is an entry of the search index ranked within its show
+type postgresCollapsedRow struct {
+	domain.SearchIndex
+	ShowGroup string
+	ShowHits  int64
+}
+
+// searchCollapsed returns a page of the best entry of each show among the
+// entries matched by hits, ranked by rank, with the next entries of the show
+// as inner hits. Shows are ranked by their best entry, and the total counts
+// shows.
+func (r *PostgresSearchRepository) searchCollapsed(ctx context.Context, hits *gorm.DB, rank string, rankArgs []interface{}, limit, offset int) ([]*domain.SearchResult, int64, error) {
+	// Media outside shows is a group of its own, like domain.ShowGroup
+	hits = hits.Select("*, "+rank+" AS rank, CASE WHEN show_id <> '' THEN show_id ELSE 'media:' || media_id END AS show_group", rankArgs...)
+	ranked := r.conn.DB.Table("(?) AS hits", hits).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY show_group ORDER BY rank DESC, updated_at DESC) AS show_rank, " +
+			"COUNT(*) OVER (PARTITION BY show_group) AS show_hits")
+
+	var total int64
+	if err := r.conn.DB.WithContext(ctx).Table("(?) AS hits", hits).Select("COUNT(DISTINCT show_group)").Scan(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	var tops []postgresCollapsedRow
+	if err := r.conn.DB.WithContext(ctx).Table("(?) AS ranked", ranked).Where("show_rank = 1").
+		Order("rank DESC, updated_at DESC").Limit(limit).Offset(offset).Find(&tops).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search: %w", err)
+	}
+	if len(tops) == 0 {
+		return nil, total, nil
+	}
+
+	results := make([]*domain.SearchResult, 0, len(tops))
+	byGroup := make(map[string]*domain.SearchResult, len(tops))
+	groups := make([]string, 0, len(tops))
+	for _, row := range tops {
+		result := &domain.SearchResult{Media: r.searchIndexToMedia(&row.SearchIndex), Score: 1.0, InnerHitsTotal: row.ShowHits - 1}
+		results = append(results, result)
+		byGroup[row.ShowGroup] = result
+		groups = append(groups, row.ShowGroup)
+	}
+
+	var inner []postgresCollapsedRow
+	if err := r.conn.DB.WithContext(ctx).Table("(?) AS ranked", ranked).
+		Where("show_rank BETWEEN 2 AND ? AND show_group IN ?", domain.CollapseInnerHits+1, groups).
+		Order("show_rank").Find(&inner).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get collapsed hits: %w", err)
+	}
+	for _, row := range inner {
+		top := byGroup[row.ShowGroup]
+		top.InnerHits = append(top.InnerHits, &domain.SearchResult{Media: r.searchIndexToMedia(&row.SearchIndex), Score: 1.0})
+	}
+
+	return results, total, nil
+}
+
 // Suggest provides search suggestions
 func (r *PostgresSearchRepository) Suggest(ctx context.Context, req *domain.SuggestRequest) ([]*domain.Suggestion, error) {
 	var suggestions []*domain.Suggestion
@@ -100,17 +214,20 @@ func (r *PostgresSearchRepository) Suggest(ctx context.Context, req *domain.Sugg
 		limit = 10
 	}
 
-	// Get suggestions from titles
+	// Get suggestions from the precomputed titles and tags containing the query
+	// or a word similar to it (typos), closest first; both matches use the
+	// pg_trgm index on term
 	query := `
-		SELECT title as suggestion, COUNT(*) as count FROM search_index 
-		WHERE title ILIKE ? 
-		GROUP BY title 
-		ORDER BY count DESC 
+		SELECT term as suggestion, SUM(CASE WHEN ? THEN safe_count ELSE count END) as count FROM search_suggestions
+		WHERE term ILIKE ? OR ? <% term
+		GROUP BY term
+		HAVING SUM(CASE WHEN ? THEN safe_count ELSE count END) > 0
+		ORDER BY MAX(word_similarity(?, term)) DESC, count DESC
 		LIMIT ?`
 
 	likePattern := "%" + req.Query + "%"
 
-	rows, err := r.conn.DB.WithContext(ctx).Raw(query, likePattern, limit).Rows()
+	rows, err := r.conn.DB.WithContext(ctx).Raw(query, req.Safe, likePattern, req.Query, req.Safe, req.Query, limit).Rows()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get suggestions: %w", err)
 	}
@@ -129,20 +246,14 @@ func (r *PostgresSearchRepository) Suggest(ctx context.Context, req *domain.Sugg
 
 // IndexMedia adds or updates media in search index
 func (r *PostgresSearchRepository) IndexMedia(ctx context.Context, media *domain.Media) error {
-	// Create searchable content by combining title and description
-	content := media.Title + " " + media.Description
+	searchIndex := toSearchIndex(media)
 
-	searchIndex := &domain.SearchIndex{
-		ID:          media.ID,
-		MediaID:     media.ID,
-		Title:       media.Title,
-		Description: media.Description,
-		Content:     content,
-		Type:        media.Type,
+	// Upsert on media_id so repeated index events update the existing entry
+	upsert := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "description", "content", "type", "age_rating", "explicit", "show_id", "tags", "audio_languages", "updated_at", "media_updated_at"}),
 	}
-
-	// Use ON CONFLICT to handle updates
-	if err := r.conn.DB.WithContext(ctx).Save(searchIndex).Error; err != nil {
+	if err := r.conn.DB.WithContext(ctx).Clauses(upsert).Create(searchIndex).Error; err != nil {
 		return fmt.Errorf("failed to index media: %w", err)
 	}
 
@@ -151,48 +262,252 @@ func (r *PostgresSearchRepository) IndexMedia(ctx context.Context, media *domain
 
 // RemoveFromIndex removes media from search index
 func (r *PostgresSearchRepository) RemoveFromIndex(ctx context.Context, mediaID string) error {
-	if err := r.conn.DB.WithContext(ctx).Delete(&domain.SearchIndex{}, "media_id = ?", mediaID).Error; err != nil {
-		return fmt.Errorf("failed to remove from index: %w", err)
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.SearchIndex{}, "media_id = ?", mediaID).Error; err != nil {
+			return fmt.Errorf("failed to remove from index: %w", err)
+		}
+
+		// Recorded for the rebuild that may be running, see ReindexAll
+		removal := &domain.SearchIndexRemoval{MediaID: mediaID, RemovedAt: r.now()}
+		upsert := clause.OnConflict{
+			Columns:   []clause.Column{{Name: "media_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"removed_at"}),
+		}
+		if err := tx.Clauses(upsert).Create(removal).Error; err != nil {
+			return fmt.Errorf("failed to record removal from index: %w", err)
+		}
+		return nil
+	})
+}
+
+// ReindexAll rebuilds the entire search index. The new index is built in a
+// shadow table and swapped in at commit, so searches keep reading the current
+// index during the rebuild and only wait for the swap itself. Index writes
+// made while the rebuild runs are replayed onto the shadow table before the
+// swap, with writes held back from then until the swap is committed.
+func (r *PostgresSearchRepository) ReindexAll(ctx context.Context, mediaList []*domain.Media) error {
+	started := r.now()
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize rebuilds, they share the shadow table
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", searchIndexShadowTable).Error; err != nil {
+			return fmt.Errorf("failed to lock search index rebuild: %w", err)
+		}
+
+		if err := tx.Exec("DROP TABLE IF EXISTS " + searchIndexShadowTable).Error; err != nil {
+			return fmt.Errorf("failed to drop shadow search index: %w", err)
+		}
+		if err := tx.Exec("CREATE TABLE " + searchIndexShadowTable + " (LIKE search_index INCLUDING ALL)").Error; err != nil {
+			return fmt.Errorf("failed to create shadow search index: %w", err)
+		}
+
+		// Index all media with multi-row inserts
+		if len(mediaList) > 0 {
+			searchIndexes := make([]*domain.SearchIndex, len(mediaList))
+			for i, media := range mediaList {
+				searchIndexes[i] = toSearchIndex(media)
+			}
+			if err := tx.Table(searchIndexShadowTable).CreateInBatches(searchIndexes, r.reindexBatchSize).Error; err != nil {
+				return fmt.Errorf("failed to index media: %w", err)
+			}
+		}
+
+		// Searches go on, index writes wait for the swap and then go to the new index
+		if err := tx.Exec("LOCK TABLE search_index IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return fmt.Errorf("failed to lock search index: %w", err)
+		}
+		if err := replayIndexWrites(tx, started); err != nil {
+			return err
+		}
+
+		return swapSearchIndex(tx)
+	})
+}
+
+// replayIndexWrites carries the index writes made since the rebuild started
+// over to the shadow table: entries of a newer media version replace the
+// rebuilt ones, and media removed since is dropped unless indexed again.
+func replayIndexWrites(tx *gorm.DB, since time.Time) error {
+	columns := []string{"title", "description", "content", "type", "age_rating", "explicit", "show_id", "tags", "audio_languages", "updated_at", "media_updated_at"}
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = excluded." + column
+	}
+
+	upsert := "INSERT INTO " + searchIndexShadowTable + " SELECT * FROM search_index WHERE updated_at >= ?" +
+		" ON CONFLICT (media_id) DO UPDATE SET " + strings.Join(assignments, ", ") +
+		" WHERE " + searchIndexShadowTable + ".media_updated_at IS NULL" +
+		" OR excluded.media_updated_at >= " + searchIndexShadowTable + ".media_updated_at"
+	if err := tx.Exec(upsert, since).Error; err != nil {
+		return fmt.Errorf("failed to replay index writes: %w", err)
+	}
+
+	removals := "DELETE FROM " + searchIndexShadowTable +
+		" WHERE media_id IN (SELECT media_id FROM search_index_removals WHERE removed_at >= ?)" +
+		" AND media_id NOT IN (SELECT media_id FROM search_index)"
+	if err := tx.Exec(removals, since).Error; err != nil {
+		return fmt.Errorf("failed to replay index removals: %w", err)
+	}
+	if err := tx.Where("removed_at < ?", since.Add(-searchRemovalRetention)).Delete(&domain.SearchIndexRemoval{}).Error; err != nil {
+		return fmt.Errorf("failed to prune index removals: %w", err)
 	}
+
 	return nil
 }
 
-// ReindexAll rebuilds the entire search index
-func (r *PostgresSearchRepository) ReindexAll(ctx context.Context, mediaList []*domain.Media) error {
-	// Start transaction
-	tx := r.conn.DB.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// ListIndexed lists every document of the search index with the update
+// time of the media it was built from
+func (r *PostgresSearchRepository) ListIndexed(ctx context.Context) ([]*domain.IndexedMedia, error) {
+	var rows []struct {
+		MediaID        string
+		MediaUpdatedAt *time.Time
+	}
+	if err := r.conn.DB.WithContext(ctx).Model(&domain.SearchIndex{}).
+		Select("media_id, media_updated_at").
+		Order("media_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list indexed media: %w", err)
+	}
+
+	indexed := make([]*domain.IndexedMedia, 0, len(rows))
+	for _, row := range rows {
+		entry := &domain.IndexedMedia{MediaID: row.MediaID}
+		if row.MediaUpdatedAt != nil {
+			entry.UpdatedAt = *row.MediaUpdatedAt
 		}
-	}()
+		indexed = append(indexed, entry)
+	}
+	return indexed, nil
+}
+
+// searchIndexShadowTable is the table ReindexAll builds the new index in
+const searchIndexShadowTable = "search_index_shadow"
 
-	// Clear existing index
-	if err := tx.Exec("TRUNCATE search_index").Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to clear search index: %w", err)
+// indexNamePattern matches the index name in a pg_indexes definition
+var indexNamePattern = regexp.MustCompile(`INDEX \S+ ON `)
+
+// swapSearchIndex replaces search_index with the shadow table. The indexes
+// copied onto the shadow table get generated names, they are renamed to the
+// ones of the replaced table so the migrations keep recognizing them.
+func swapSearchIndex(tx *gorm.DB) error {
+	oldIndexes, err := tableIndexes(tx, "search_index")
+	if err != nil {
+		return err
 	}
 
-	// Index all media
-	for _, media := range mediaList {
-		content := media.Title + " " + media.Description
+	if err := tx.Exec("DROP TABLE search_index").Error; err != nil {
+		return fmt.Errorf("failed to drop search index: %w", err)
+	}
+	if err := tx.Exec("ALTER TABLE " + searchIndexShadowTable + " RENAME TO search_index").Error; err != nil {
+		return fmt.Errorf("failed to swap search index: %w", err)
+	}
 
-		searchIndex := &domain.SearchIndex{
-			ID:          media.ID,
-			MediaID:     media.ID,
-			Title:       media.Title,
-			Description: media.Description,
-			Content:     content,
-			Type:        media.Type,
+	newIndexes, err := tableIndexes(tx, "search_index")
+	if err != nil {
+		return err
+	}
+	for definition, names := range newIndexes {
+		for i, name := range names {
+			if i >= len(oldIndexes[definition]) || name == oldIndexes[definition][i] {
+				continue
+			}
+			rename := fmt.Sprintf("ALTER INDEX %s RENAME TO %s", quoteIdentifier(name), quoteIdentifier(oldIndexes[definition][i]))
+			if err := tx.Exec(rename).Error; err != nil {
+				return fmt.Errorf("failed to rename index %s: %w", name, err)
+			}
 		}
+	}
 
-		if err := tx.Create(searchIndex).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to index media %s: %w", media.ID, err)
+	return nil
+}
+
+// tableIndexes returns the index names of table keyed by their definition
+// without the name
+func tableIndexes(tx *gorm.DB, table string) (map[string][]string, error) {
+	var rows []struct {
+		IndexName string
+		IndexDef  string
+	}
+	if err := tx.Raw(`SELECT indexname AS index_name, indexdef AS index_def FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = ? ORDER BY indexname`, table).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list indexes of %s: %w", table, err)
+	}
+
+	indexes := make(map[string][]string, len(rows))
+	for _, row := range rows {
+		definition := indexNamePattern.ReplaceAllString(row.IndexDef, "INDEX ON ")
+		indexes[definition] = append(indexes[definition], row.IndexName)
+	}
+	return indexes, nil
+}
+
+// quoteIdentifier quotes a Postgres identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// toSearchIndex builds the search index row of media
+func toSearchIndex(media *domain.Media) *domain.SearchIndex {
+	updatedAt := media.UpdatedAt
+	return &domain.SearchIndex{
+		ID:          media.ID,
+		MediaID:     media.ID,
+		Title:       media.Title,
+		Description: media.Description,
+		Content:     searchContent(media),
+		Type:        media.Type,
+		AgeRating:   media.ContentRating.AgeRating,
+		Explicit:    media.ContentRating.IsExplicit(),
+		ShowID:      media.ShowID,
+		Tags:        domain.NormalizeLabels(media.Labels),
+
+		AudioLanguages: media.AudioLanguages(),
+		MediaUpdatedAt: &updatedAt,
+	}
+}
+
+// postgresTSQuery builds the text search query matching any of the clauses,
+// and its arguments. Words match like plainto_tsquery, phrases like
+// phraseto_tsquery.
+func postgresTSQuery(clauses []domain.SearchClause) (string, []interface{}) {
+	alternatives := make([]string, 0, len(clauses))
+	var args []interface{}
+	for _, clause := range clauses {
+		parts := make([]string, 0, len(clause.Terms)+len(clause.Excluded))
+		for _, term := range clause.Terms {
+			parts = append(parts, postgresTSTerm(term))
+			args = append(args, term.Text)
+		}
+		for _, term := range clause.Excluded {
+			parts = append(parts, "!!"+postgresTSTerm(term))
+			args = append(args, term.Text)
 		}
+		alternatives = append(alternatives, "("+strings.Join(parts, " && ")+")")
+	}
+	return strings.Join(alternatives, " || "), args
+}
+
+// postgresTSTerm returns the text search query function matching a term
+func postgresTSTerm(term domain.SearchTerm) string {
+	if term.Phrase {
+		return "phraseto_tsquery('english', ?)"
 	}
+	return "plainto_tsquery('english', ?)"
+}
 
-	return tx.Commit().Error
+// jsonArray encodes values as a JSON array, for jsonb containment
+func jsonArray(values ...string) string {
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+// searchContent combines the title, description and show context of media
+// into searchable content, normalized like queries
+func searchContent(media *domain.Media) string {
+	content := media.Title + " " + media.Description
+	if text := media.SearchContext.Text(); text != "" {
+		content += " " + text
+	}
+	return domain.NormalizeSearchText(content)
 }
 
 // searchIndexToMedia converts SearchIndex back to Media
@@ -202,6 +517,12 @@ func (r *PostgresSearchRepository) searchIndexToMedia(index *domain.SearchIndex)
 		Title:       index.Title,
 		Description: index.Description,
 		Type:        index.Type,
+		ShowID:      index.ShowID,
+		Labels:      index.Tags,
 		Status:      domain.StatusReady, // Search results are ready
+		ContentRating: domain.ContentRating{
+			AgeRating: index.AgeRating,
+			Explicit:  index.Explicit,
+		},
 	}
 }
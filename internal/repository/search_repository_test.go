@@ -3,8 +3,13 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestSearchRepositoryInterface is an interface test to ensure all implementations
@@ -36,3 +41,89 @@ func (m *MockSearchRepository) RemoveFromIndex(ctx context.Context, mediaID stri
 func (m *MockSearchRepository) ReindexAll(ctx context.Context, mediaList []*domain.Media) error {
 	return nil
 }
+
+func (m *MockSearchRepository) ListIndexed(ctx context.Context) ([]*domain.IndexedMedia, error) {
+	return nil, nil
+}
+
+func TestPostgresSearchRepository_IndexMedia_Upsert(t *testing.T) {
+	// Given the search index schema (the upsert SQL is shared with SQLite)
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	require.NoError(t, database.CreateIndexes(conn.DB))
+	repo := NewPostgresSearchRepository(conn, 0)
+
+	// When the same media is indexed repeatedly
+	media := &domain.Media{ID: "m1", Title: "First Title", Type: domain.TypeVideo}
+	require.NoError(t, repo.IndexMedia(ctx, media))
+	require.NoError(t, repo.IndexMedia(ctx, media))
+	media.Title = "Second Title"
+	media.ContentRating = domain.ContentRating{AgeRating: domain.AgeRating18}
+	require.NoError(t, repo.IndexMedia(ctx, media))
+
+	// Then a single, updated entry exists
+	var entries []domain.SearchIndex
+	require.NoError(t, conn.DB.Find(&entries).Error)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Second Title", entries[0].Title)
+	assert.Equal(t, "second title", entries[0].Content, "content is normalized like queries")
+	assert.True(t, entries[0].Explicit)
+
+	// And the indexed version of the media is listed
+	media.UpdatedAt = time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	require.NoError(t, repo.IndexMedia(ctx, media))
+	indexed, err := repo.ListIndexed(ctx)
+	require.NoError(t, err)
+	require.Len(t, indexed, 1)
+	assert.Equal(t, "m1", indexed[0].MediaID)
+	assert.False(t, indexed[0].IsStaleFor(media))
+}
+
+func TestPostgresSearchRepository_Search_CollapseByShow(t *testing.T) {
+	// Given episodes of two shows and media outside shows (listed without a
+	// query, the collapsing SQL is shared with SQLite)
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	require.NoError(t, database.CreateIndexes(conn.DB))
+	repo := NewPostgresSearchRepository(conn, 0)
+	for _, media := range []*domain.Media{
+		{ID: "a1", Title: "A1", ShowID: "show-a"},
+		{ID: "a2", Title: "A2", ShowID: "show-a"},
+		{ID: "a3", Title: "A3", ShowID: "show-a"},
+		{ID: "a4", Title: "A4", ShowID: "show-a"},
+		{ID: "a5", Title: "A5", ShowID: "show-a"},
+		{ID: "b1", Title: "B1", ShowID: "show-b"},
+		{ID: "m1", Title: "M1"},
+		{ID: "m2", Title: "M2"},
+	} {
+		require.NoError(t, repo.IndexMedia(ctx, media))
+	}
+
+	// When searching collapsed by show
+	results, total, err := repo.Search(ctx, &domain.SearchRequest{Collapse: domain.SearchCollapseShow, Limit: 10})
+
+	// Then each show and media outside shows is one result
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	require.Len(t, results, 4)
+	innerHits := make(map[string]int)
+	for _, result := range results {
+		innerHits[domain.ShowGroup(result.Media)] = len(result.InnerHits)
+		if result.Media.ShowID == "show-a" {
+			assert.Equal(t, int64(4), result.InnerHitsTotal)
+			for _, inner := range result.InnerHits {
+				assert.Equal(t, "show-a", inner.Media.ShowID)
+				assert.NotEqual(t, result.Media.ID, inner.Media.ID)
+			}
+		}
+	}
+	assert.Equal(t, map[string]int{"show-a": domain.CollapseInnerHits, "show-b": 0, "media:m1": 0, "media:m2": 0}, innerHits)
+
+	// And pages go through the collapsed results
+	results, total, err = repo.Search(ctx, &domain.SearchRequest{Collapse: domain.SearchCollapseShow, Limit: 3, Offset: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Len(t, results, 1)
+}
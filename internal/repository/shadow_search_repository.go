@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+const (
+	defaultShadowTimeout       = 2 * time.Second
+	defaultShadowMaxConcurrent = 8
+	maxShadowDiffIDs           = 10 // IDs listed per difference in a log line
+)
+
+// ShadowSearchOptions configures the shadow reads of a search migration;
+// zero values use the defaults
+type ShadowSearchOptions struct {
+	SampleRate    float64       // share of searches and suggestions compared, every one when not in (0, 1)
+	Timeout       time.Duration // how long a shadow read, or media update, may take
+	MaxConcurrent int           // shadow reads in flight, searches beyond are not compared
+}
+
+// ShadowSearchStats counts the shadow reads and writes of a search migration
+type ShadowSearchStats struct {
+	Compared      int64  `json:"compared"`       // searches and suggestions served by both indexes
+	Mismatched    int64  `json:"mismatched"`     // compared reads whose results differ
+	Skipped       int64  `json:"skipped"`        // sampled reads not compared, too many were in flight
+	ReadFailures  int64  `json:"read_failures"`  // shadow reads that failed or timed out
+	WriteFailures int64  `json:"write_failures"` // shadow index updates that failed
+	ShadowBackend string `json:"shadow_backend"` // name of the shadow index
+}
+
+// ShadowSearchRepository is a SearchRepository migrating to another index
+type ShadowSearchRepository interface {
+	SearchRepository
+
+	// Stats returns the counters of the migration
+	Stats() ShadowSearchStats
+}
+
+// shadowSearchRepository writes to a shadow index alongside the primary one
+// and compares the reads of both
+type shadowSearchRepository struct {
+	SearchRepository
+	shadow  SearchRepository
+	name    string
+	options ShadowSearchOptions
+	sample  func() float64
+	slots   chan struct{}
+	pending sync.WaitGroup
+
+	compared      atomic.Int64
+	mismatched    atomic.Int64
+	skipped       atomic.Int64
+	readFailures  atomic.Int64
+	writeFailures atomic.Int64
+}
+
+// NewShadowSearchRepository wraps primary, which keeps serving every request,
+// so that the index being migrated to, shadow, is updated alongside it and a
+// sample of the searches and suggestions is run on it in the background. Their
+// results are compared with those of primary and discrepancies are logged, so
+// the shadow index can be trusted before the cutover. The shadow index is
+// updated once primary is, and failures of the shadow index are logged and
+// counted, never returned. Listing the index reads primary only.
+func NewShadowSearchRepository(primary, shadow SearchRepository, name string, options ShadowSearchOptions) ShadowSearchRepository {
+	if options.SampleRate <= 0 || options.SampleRate > 1 {
+		options.SampleRate = 1
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultShadowTimeout
+	}
+	if options.MaxConcurrent <= 0 {
+		options.MaxConcurrent = defaultShadowMaxConcurrent
+	}
+
+	return &shadowSearchRepository{
+		SearchRepository: primary,
+		shadow:           shadow,
+		name:             name,
+		options:          options,
+		sample:           rand.Float64,
+		slots:            make(chan struct{}, options.MaxConcurrent),
+	}
+}
+
+// Search performs full-text search on the primary index, and compares its
+// results with the shadow index
+func (r *shadowSearchRepository) Search(ctx context.Context, req *domain.SearchRequest) ([]*domain.SearchResult, int64, error) {
+	// The primary index may normalize the request it is given
+	shadowReq := *req
+	results, total, err := r.SearchRepository.Search(ctx, req)
+	if err != nil {
+		return results, total, err
+	}
+
+	// The caller may change the results once returned, so the comparison
+	// reads their IDs taken now
+	ids := resultIDs(results)
+	r.compare(ctx, func(ctx context.Context) ([]string, error) {
+		shadowResults, shadowTotal, err := r.shadow.Search(ctx, &shadowReq)
+		if err != nil {
+			return nil, err
+		}
+		return diffSearchResults(ids, total, resultIDs(shadowResults), shadowTotal), nil
+	}, fmt.Sprintf("search %q offset %d", shadowReq.Query, shadowReq.Offset))
+
+	return results, total, nil
+}
+
+// Suggest provides search suggestions from the primary index, and compares
+// them with the shadow index
+func (r *shadowSearchRepository) Suggest(ctx context.Context, req *domain.SuggestRequest) ([]*domain.Suggestion, error) {
+	shadowReq := *req
+	suggestions, err := r.SearchRepository.Suggest(ctx, req)
+	if err != nil {
+		return suggestions, err
+	}
+
+	texts := suggestionTexts(suggestions)
+	r.compare(ctx, func(ctx context.Context) ([]string, error) {
+		shadowSuggestions, err := r.shadow.Suggest(ctx, &shadowReq)
+		if err != nil {
+			return nil, err
+		}
+		return diffLists(texts, suggestionTexts(shadowSuggestions), "suggestions"), nil
+	}, fmt.Sprintf("suggest %q", shadowReq.Query))
+
+	return suggestions, nil
+}
+
+// IndexMedia adds or updates media in the primary index, then in the shadow one
+func (r *shadowSearchRepository) IndexMedia(ctx context.Context, media *domain.Media) error {
+	err := r.SearchRepository.IndexMedia(ctx, media)
+
+	shadowCtx, cancel := context.WithTimeout(ctx, r.options.Timeout)
+	defer cancel()
+	if shadowErr := r.shadow.IndexMedia(shadowCtx, media); shadowErr != nil {
+		r.writeFailed(fmt.Sprintf("index media %s", media.ID), shadowErr)
+	}
+	return err
+}
+
+// RemoveFromIndex removes media from the primary index, then from the shadow one
+func (r *shadowSearchRepository) RemoveFromIndex(ctx context.Context, mediaID string) error {
+	err := r.SearchRepository.RemoveFromIndex(ctx, mediaID)
+
+	shadowCtx, cancel := context.WithTimeout(ctx, r.options.Timeout)
+	defer cancel()
+	if shadowErr := r.shadow.RemoveFromIndex(shadowCtx, mediaID); shadowErr != nil {
+		r.writeFailed(fmt.Sprintf("remove media %s", mediaID), shadowErr)
+	}
+	return err
+}
+
+// ReindexAll rebuilds the primary index, then the shadow one
+func (r *shadowSearchRepository) ReindexAll(ctx context.Context, mediaList []*domain.Media) error {
+	err := r.SearchRepository.ReindexAll(ctx, mediaList)
+
+	if shadowErr := r.shadow.ReindexAll(ctx, mediaList); shadowErr != nil {
+		r.writeFailed("rebuild the index", shadowErr)
+	}
+	return err
+}
+
+// Stats returns the counters of the migration
+func (r *shadowSearchRepository) Stats() ShadowSearchStats {
+	return ShadowSearchStats{
+		Compared:      r.compared.Load(),
+		Mismatched:    r.mismatched.Load(),
+		Skipped:       r.skipped.Load(),
+		ReadFailures:  r.readFailures.Load(),
+		WriteFailures: r.writeFailures.Load(),
+		ShadowBackend: r.name,
+	}
+}
+
+// compare runs a sampled shadow read in the background, without delaying the
+// request, and logs the differences it reports
+func (r *shadowSearchRepository) compare(ctx context.Context, read func(ctx context.Context) ([]string, error), description string) {
+	if r.sample() >= r.options.SampleRate {
+		return
+	}
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		r.skipped.Add(1)
+		return
+	}
+
+	requestID := ""
+	if trace := domain.TraceFromContext(ctx); trace != nil {
+		requestID = trace.RequestID
+	}
+	// The shadow read outlives the request it compares
+	shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.options.Timeout)
+	r.pending.Add(1)
+	go func() {
+		defer r.pending.Done()
+		defer func() { <-r.slots }()
+		defer cancel()
+
+		diffs, err := read(shadowCtx)
+		if err != nil {
+			r.readFailures.Add(1)
+			log.Printf("Shadow search index %s failed to %s: %v request_id=%s", r.name, description, err, requestID)
+			return
+		}
+		r.compared.Add(1)
+		if len(diffs) > 0 {
+			r.mismatched.Add(1)
+			log.Printf("Shadow search index %s differs on %s: %s request_id=%s", r.name, description, strings.Join(diffs, "; "), requestID)
+		}
+	}()
+}
+
+// wait waits for the shadow reads in flight
+func (r *shadowSearchRepository) wait() {
+	r.pending.Wait()
+}
+
+// writeFailed logs and counts a failed update of the shadow index
+func (r *shadowSearchRepository) writeFailed(description string, err error) {
+	r.writeFailures.Add(1)
+	log.Printf("Shadow search index %s failed to %s: %v", r.name, description, err)
+}
+
+// diffSearchResults describes how the media IDs of the results of the shadow
+// index differ from those of the primary one: their totals, the media found by
+// only one of them and the order of the media found by both
+func diffSearchResults(primary []string, primaryTotal int64, shadow []string, shadowTotal int64) []string {
+	var diffs []string
+	if primaryTotal != shadowTotal {
+		diffs = append(diffs, fmt.Sprintf("total %d, shadow %d", primaryTotal, shadowTotal))
+	}
+	return append(diffs, diffLists(primary, shadow, "media")...)
+}
+
+// diffLists describes the items of two ranked lists missing from either, or
+// ranked in another order when both have the same items
+func diffLists(primary, shadow []string, kind string) []string {
+	missing := subtract(primary, shadow)
+	extra := subtract(shadow, primary)
+
+	var diffs []string
+	if len(missing) > 0 {
+		diffs = append(diffs, fmt.Sprintf("%s missing from shadow: %s", kind, joinIDs(missing)))
+	}
+	if len(extra) > 0 {
+		diffs = append(diffs, fmt.Sprintf("%s only in shadow: %s", kind, joinIDs(extra)))
+	}
+	if len(diffs) == 0 && strings.Join(primary, "\x00") != strings.Join(shadow, "\x00") {
+		diffs = append(diffs, fmt.Sprintf("%s ranked %s, shadow %s", kind, joinIDs(primary), joinIDs(shadow)))
+	}
+	return diffs
+}
+
+// resultIDs returns the IDs of the media of search results, in order
+func resultIDs(results []*domain.SearchResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Media != nil {
+			ids = append(ids, result.Media.ID)
+		}
+	}
+	return ids
+}
+
+// suggestionTexts returns the texts of suggestions, in order
+func suggestionTexts(suggestions []*domain.Suggestion) []string {
+	texts := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		texts[i] = suggestion.Text
+	}
+	return texts
+}
+
+// subtract returns the items of list missing from other, in order
+func subtract(list, other []string) []string {
+	in := make(map[string]bool, len(other))
+	for _, item := range other {
+		in[item] = true
+	}
+	var missing []string
+	for _, item := range list {
+		if !in[item] {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+// joinIDs joins the first maxShadowDiffIDs items of a list
+func joinIDs(ids []string) string {
+	if len(ids) > maxShadowDiffIDs {
+		return fmt.Sprintf("%s and %d more", strings.Join(ids[:maxShadowDiffIDs], ","), len(ids)-maxShadowDiffIDs)
+	}
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriteSearchRepository fails every index update
+type failingWriteSearchRepository struct {
+	SearchRepository
+}
+
+func (r *failingWriteSearchRepository) IndexMedia(ctx context.Context, media *domain.Media) error {
+	return errors.New("mapping conflict")
+}
+
+func TestShadowSearchRepository(t *testing.T) {
+	// Given media indexed through the shadow repository
+	ctx := context.Background()
+	primary := NewInMemorySearchRepository()
+	shadow := NewInMemorySearchRepository()
+	repo := NewShadowSearchRepository(primary, shadow, "test", ShadowSearchOptions{}).(*shadowSearchRepository)
+	require.NoError(t, repo.ReindexAll(ctx, []*domain.Media{{ID: "m1", Title: "Golang Concurrency"}}))
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m2", Title: "Golang Generics"}))
+
+	// When both indexes agree
+	results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	repo.wait()
+
+	// Then the primary index serves the search and no discrepancy is counted
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, results, 2)
+	assert.Equal(t, ShadowSearchStats{Compared: 1, ShadowBackend: "test"}, repo.Stats())
+
+	// When the shadow index misses an update
+	require.NoError(t, shadow.RemoveFromIndex(ctx, "m2"))
+	results, total, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	repo.wait()
+
+	// Then the primary results are still served and the discrepancy is counted
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, results, 2)
+	assert.Equal(t, int64(2), repo.Stats().Compared)
+	assert.Equal(t, int64(1), repo.Stats().Mismatched)
+
+	// And removals reach both indexes
+	require.NoError(t, repo.RemoveFromIndex(ctx, "m1"))
+	_, shadowTotal, err := shadow.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Zero(t, shadowTotal)
+}
+
+// blockingSearchRepository holds searches until released
+type blockingSearchRepository struct {
+	SearchRepository
+	release chan struct{}
+}
+
+func (r *blockingSearchRepository) Search(ctx context.Context, req *domain.SearchRequest) ([]*domain.SearchResult, int64, error) {
+	<-r.release
+	return r.SearchRepository.Search(ctx, req)
+}
+
+// recordingSearchRepository records the media updates it gets in a log shared with others
+type recordingSearchRepository struct {
+	SearchRepository
+	name string
+	log  *[]string
+}
+
+func (r *recordingSearchRepository) IndexMedia(ctx context.Context, media *domain.Media) error {
+	*r.log = append(*r.log, r.name+" index "+media.ID)
+	return r.SearchRepository.IndexMedia(ctx, media)
+}
+
+func (r *recordingSearchRepository) RemoveFromIndex(ctx context.Context, mediaID string) error {
+	*r.log = append(*r.log, r.name+" remove "+mediaID)
+	return r.SearchRepository.RemoveFromIndex(ctx, mediaID)
+}
+
+func TestShadowSearchRepository_CallerChangesResults(t *testing.T) {
+	// Given both indexes with the same media, and a slow shadow index
+	ctx := context.Background()
+	primary := NewInMemorySearchRepository()
+	shadow := &blockingSearchRepository{SearchRepository: NewInMemorySearchRepository(), release: make(chan struct{})}
+	media := []*domain.Media{{ID: "m1", Title: "Golang Concurrency"}, {ID: "m2", Title: "Golang Generics"}}
+	require.NoError(t, primary.ReindexAll(ctx, media))
+	require.NoError(t, shadow.ReindexAll(ctx, media))
+	repo := NewShadowSearchRepository(primary, shadow, "test", ShadowSearchOptions{}).(*shadowSearchRepository)
+
+	// When the caller drops a result in place before the shadow search ran, as hydration does
+	results, _, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	kept := results[:0]
+	kept = append(kept, results[1])
+	results[1] = nil
+	close(shadow.release)
+	repo.wait()
+
+	// Then the results as returned are compared
+	assert.Len(t, kept, 1)
+	assert.Equal(t, int64(1), repo.Stats().Compared)
+	assert.Zero(t, repo.Stats().Mismatched)
+}
+
+func TestShadowSearchRepository_WritesPrimaryFirst(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	repo := NewShadowSearchRepository(
+		&recordingSearchRepository{SearchRepository: NewInMemorySearchRepository(), name: "primary", log: &log},
+		&recordingSearchRepository{SearchRepository: NewInMemorySearchRepository(), name: "shadow", log: &log},
+		"test", ShadowSearchOptions{})
+
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m1", Title: "Golang"}))
+	require.NoError(t, repo.RemoveFromIndex(ctx, "m1"))
+
+	assert.Equal(t, []string{"primary index m1", "shadow index m1", "primary remove m1", "shadow remove m1"}, log)
+}
+
+func TestShadowSearchRepository_ShadowFailures(t *testing.T) {
+	ctx := context.Background()
+	repo := NewShadowSearchRepository(NewInMemorySearchRepository(), &failingWriteSearchRepository{NewInMemorySearchRepository()}, "test", ShadowSearchOptions{})
+
+	// Failed shadow updates do not fail the primary one
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m1", Title: "Golang"}))
+	assert.Equal(t, int64(1), repo.Stats().WriteFailures)
+
+	results, _, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestShadowSearchRepository_Sampling(t *testing.T) {
+	ctx := context.Background()
+	repo := NewShadowSearchRepository(NewInMemorySearchRepository(), NewInMemorySearchRepository(), "test", ShadowSearchOptions{SampleRate: 0.5}).(*shadowSearchRepository)
+	repo.sample = func() float64 { return 0.7 }
+
+	_, err := repo.Suggest(ctx, &domain.SuggestRequest{Query: "go"})
+	require.NoError(t, err)
+	repo.wait()
+
+	assert.Zero(t, repo.Stats().Compared)
+}
+
+func TestDiffSearchResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		primary  []string
+		shadow   []string
+		total    int64
+		expected []string
+	}{
+		{name: "same", primary: []string{"a", "b"}, shadow: []string{"a", "b"}, total: 2},
+		{name: "reordered", primary: []string{"a", "b"}, shadow: []string{"b", "a"}, total: 2,
+			expected: []string{"media ranked a,b, shadow b,a"}},
+		{name: "missing and extra", primary: []string{"a", "b"}, shadow: []string{"a", "c"}, total: 3,
+			expected: []string{"total 2, shadow 3", "media missing from shadow: b", "media only in shadow: c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, diffSearchResults(tt.primary, 2, tt.shadow, tt.total))
+		})
+	}
+}
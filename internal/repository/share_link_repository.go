@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// ShareLinkRepository defines the contract for share link data access
+type ShareLinkRepository interface {
+	// Create creates a new share link
+	Create(ctx context.Context, link *domain.ShareLink) error
+
+	// GetByTokenHash retrieves a share link by the hash of its token
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.ShareLink, error)
+
+	// GetByMediaID retrieves all share links of a media item, newest first
+	GetByMediaID(ctx context.Context, mediaID string) ([]*domain.ShareLink, error)
+
+	// Revoke marks a share link of a media item as revoked
+	Revoke(ctx context.Context, mediaID, id string, revokedAt time.Time) error
+
+	// RevokeByMediaID marks every active share link of a media item as revoked, returning how many were
+	RevokeByMediaID(ctx context.Context, mediaID string, revokedAt time.Time) (int64, error)
+}
+
+// postgresShareLinkRepository implements ShareLinkRepository using PostgreSQL
+type postgresShareLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresShareLinkRepository creates a new PostgreSQL share link repository
+func NewPostgresShareLinkRepository(conn *database.Connection) ShareLinkRepository {
+	return &postgresShareLinkRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new share link
+func (r *postgresShareLinkRepository) Create(ctx context.Context, link *domain.ShareLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+// GetByTokenHash retrieves a share link by the hash of its token
+func (r *postgresShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+	err := r.db.WithContext(ctx).First(&link, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// GetByMediaID retrieves all share links of a media item, newest first
+func (r *postgresShareLinkRepository) GetByMediaID(ctx context.Context, mediaID string) ([]*domain.ShareLink, error) {
+	var links []domain.ShareLink
+	err := r.db.WithContext(ctx).
+		Where("media_id = ?", mediaID).
+		Order("created_at DESC").
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.ShareLink, len(links))
+	for i := range links {
+		result[i] = &links[i]
+	}
+
+	return result, nil
+}
+
+// Revoke marks a share link of a media item as revoked
+func (r *postgresShareLinkRepository) Revoke(ctx context.Context, mediaID, id string, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.ShareLink{}).
+		Where("id = ? AND media_id = ? AND revoked_at IS NULL", id, mediaID).
+		Update("revoked_at", revokedAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrShareLinkNotFound
+	}
+
+	return nil
+}
+
+// RevokeByMediaID marks every active share link of a media item as revoked, returning how many were
+func (r *postgresShareLinkRepository) RevokeByMediaID(ctx context.Context, mediaID string, revokedAt time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.ShareLink{}).
+		Where("media_id = ? AND revoked_at IS NULL", mediaID).
+		Update("revoked_at", revokedAt)
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// TestShareLinkRepositoryInterface ensures the mock satisfies the ShareLinkRepository interface
+func TestShareLinkRepositoryInterface(t *testing.T) {
+	var _ ShareLinkRepository = (*MockShareLinkRepository)(nil)
+}
+
+// MockShareLinkRepository can be used in tests
+type MockShareLinkRepository struct{}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, link *domain.ShareLink) error {
+	return nil
+}
+
+func (m *MockShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.ShareLink, error) {
+	return nil, domain.ErrShareLinkNotFound
+}
+
+func (m *MockShareLinkRepository) GetByMediaID(ctx context.Context, mediaID string) ([]*domain.ShareLink, error) {
+	return nil, nil
+}
+
+func (m *MockShareLinkRepository) Revoke(ctx context.Context, mediaID, id string, revokedAt time.Time) error {
+	return nil
+}
+
+func (m *MockShareLinkRepository) RevokeByMediaID(ctx context.Context, mediaID string, revokedAt time.Time) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShowTemplateRepository defines the contract for show template data access
+type ShowTemplateRepository interface {
+	// GetByShowID retrieves the template of a show
+	GetByShowID(ctx context.Context, showID string) (*domain.ShowTemplate, error)
+
+	// GetByShowIDs retrieves the existing templates among the shows of showIDs
+	GetByShowIDs(ctx context.Context, showIDs []string) ([]*domain.ShowTemplate, error)
+
+	// Save creates or replaces the template of a show
+	Save(ctx context.Context, template *domain.ShowTemplate) error
+
+	// Delete removes the template of a show
+	Delete(ctx context.Context, showID string) error
+}
+
+// postgresShowTemplateRepository implements ShowTemplateRepository using PostgreSQL
+type postgresShowTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresShowTemplateRepository creates a new PostgreSQL show template repository
+func NewPostgresShowTemplateRepository(conn *database.Connection) ShowTemplateRepository {
+	return &postgresShowTemplateRepository{
+		db: conn.DB,
+	}
+}
+
+// GetByShowID retrieves the template of a show
+func (r *postgresShowTemplateRepository) GetByShowID(ctx context.Context, showID string) (*domain.ShowTemplate, error) {
+	var template domain.ShowTemplate
+	err := r.db.WithContext(ctx).First(&template, "show_id = ?", showID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShowTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// GetByShowIDs retrieves the existing templates among the shows of showIDs
+func (r *postgresShowTemplateRepository) GetByShowIDs(ctx context.Context, showIDs []string) ([]*domain.ShowTemplate, error) {
+	if len(showIDs) == 0 {
+		return []*domain.ShowTemplate{}, nil
+	}
+
+	var templates []*domain.ShowTemplate
+	if err := r.db.WithContext(ctx).Where("show_id IN ?", showIDs).Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Save creates or replaces the template of a show, keeping its creation time
+func (r *postgresShowTemplateRepository) Save(ctx context.Context, template *domain.ShowTemplate) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "show_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "hosts", "labels", "category", "artwork_key", "explicit", "updated_at"}),
+		}).
+		Create(template).Error
+}
+
+// Delete removes the template of a show
+func (r *postgresShowTemplateRepository) Delete(ctx context.Context, showID string) error {
+	result := r.db.WithContext(ctx).Delete(&domain.ShowTemplate{}, "show_id = ?", showID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrShowTemplateNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShowTemplateRepositoryInterface ensures the mock satisfies the ShowTemplateRepository interface
+func TestShowTemplateRepositoryInterface(t *testing.T) {
+	var _ ShowTemplateRepository = (*MockShowTemplateRepository)(nil)
+}
+
+// MockShowTemplateRepository can be used in tests
+type MockShowTemplateRepository struct{}
+
+func (m *MockShowTemplateRepository) GetByShowID(ctx context.Context, showID string) (*domain.ShowTemplate, error) {
+	return nil, domain.ErrShowTemplateNotFound
+}
+
+func (m *MockShowTemplateRepository) GetByShowIDs(ctx context.Context, showIDs []string) ([]*domain.ShowTemplate, error) {
+	return nil, nil
+}
+
+func (m *MockShowTemplateRepository) Save(ctx context.Context, template *domain.ShowTemplate) error {
+	return nil
+}
+
+func (m *MockShowTemplateRepository) Delete(ctx context.Context, showID string) error {
+	return nil
+}
+
+func TestShowTemplateRepository_SaveReplaces(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresShowTemplateRepository(conn)
+
+	// When a template is saved twice
+	require.NoError(t, repo.Save(ctx, &domain.ShowTemplate{ShowID: "show-1", Labels: []string{"tech"}, Category: "Technology"}))
+	first, err := repo.GetByShowID(ctx, "show-1")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, &domain.ShowTemplate{ShowID: "show-1", Labels: []string{"news"}, Explicit: true}))
+
+	// Then the second replaces the first and keeps its creation time
+	template, err := repo.GetByShowID(ctx, "show-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"news"}, template.Labels)
+	assert.Empty(t, template.Category)
+	assert.True(t, template.Explicit)
+	assert.True(t, first.CreatedAt.Equal(template.CreatedAt))
+
+	// And deleting it twice reports the missing template
+	require.NoError(t, repo.Delete(ctx, "show-1"))
+	assert.ErrorIs(t, repo.Delete(ctx, "show-1"), domain.ErrShowTemplateNotFound)
+	_, err = repo.GetByShowID(ctx, "show-1")
+	assert.ErrorIs(t, err, domain.ErrShowTemplateNotFound)
+}
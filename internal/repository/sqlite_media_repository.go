@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"thamaniyah/pkg/database"
+)
+
+// sqliteMediaRepository implements MediaRepository using SQLite. The queries of
+// the PostgreSQL repository only use portable GORM features, so they are shared.
+type sqliteMediaRepository struct {
+	*postgresMediaRepository
+}
+
+// NewSQLiteMediaRepository creates a new SQLite media repository
+func NewSQLiteMediaRepository(conn *database.Connection) MediaRepository {
+	return &sqliteMediaRepository{
+		postgresMediaRepository: &postgresMediaRepository{db: conn.DB},
+	}
+}
+
+// GetEstimatedTotal returns the exact count, SQLite keeps no row estimates
+func (r *sqliteMediaRepository) GetEstimatedTotal(ctx context.Context) (int64, error) {
+	return r.GetTotal(ctx)
+}
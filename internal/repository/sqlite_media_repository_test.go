@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteMediaRepository(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	require.NoError(t, database.CreateIndexes(conn.DB))
+	repo := NewSQLiteMediaRepository(conn)
+
+	now := time.Now().UTC()
+	endedAt := now.Add(-time.Hour)
+	require.NoError(t, repo.Create(ctx, &domain.Media{
+		ID: "m1", Title: "Episode 1", Type: domain.TypePodcast, Status: domain.StatusReady, Visibility: domain.VisibilityPublic,
+		License: domain.License{EndsAt: &endedAt},
+	}))
+	require.NoError(t, repo.Create(ctx, &domain.Media{
+		ID: "m2", Title: "Episode 2", Type: domain.TypePodcast, Status: domain.StatusProcessing, Visibility: domain.VisibilityPublic,
+		OwnerID: "u1",
+	}))
+
+	t.Run("round-trips media", func(t *testing.T) {
+		require.NoError(t, repo.UpdateGeoRestriction(ctx, "m1", domain.GeoRestriction{AllowedCountries: []string{"SA"}}))
+
+		media, err := repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, "Episode 1", media.Title)
+		assert.Equal(t, []string{"SA"}, media.GeoRestriction.AllowedCountries)
+	})
+
+	t.Run("filters and counts", func(t *testing.T) {
+		list, err := repo.GetByFilter(ctx, &domain.MediaFilter{Status: domain.StatusReady}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "m1", list[0].ID)
+
+		total, err := repo.GetTotal(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("pages with the total in one query", func(t *testing.T) {
+		page, total, err := repo.GetAllWithTotal(ctx, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, "m2", page[0].ID)
+		assert.Equal(t, domain.StatusProcessing, page[0].Status)
+		assert.Equal(t, int64(2), total)
+
+		page, total, err = repo.GetAllWithTotal(ctx, 1, 5)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("pages the media of an owner", func(t *testing.T) {
+		page, total, err := repo.GetByOwner(ctx, "u1", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, "m2", page[0].ID)
+		assert.Equal(t, int64(1), total)
+
+		page, total, err = repo.GetByOwner(ctx, "u1", 10, 5)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+		assert.Equal(t, int64(1), total)
+
+		page, total, err = repo.GetByOwner(ctx, "u2", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+		assert.Zero(t, total)
+	})
+
+	t.Run("finds expired licenses", func(t *testing.T) {
+		expired, err := repo.GetExpiredLicenses(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		assert.Equal(t, "m1", expired[0].ID)
+	})
+
+	t.Run("finds scheduled media within a range", func(t *testing.T) {
+		scheduled, err := repo.GetScheduledBetween(ctx, now.Add(-2*time.Hour), now)
+		require.NoError(t, err)
+		require.Len(t, scheduled, 1)
+		assert.Equal(t, "m1", scheduled[0].ID)
+
+		scheduled, err = repo.GetScheduledBetween(ctx, now, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, scheduled)
+	})
+
+	t.Run("finds and cancels scheduled unpublishes", func(t *testing.T) {
+		require.NoError(t, repo.UpdateUnpublishAt(ctx, "m1", &endedAt))
+		require.NoError(t, repo.UpdateUnpublishAt(ctx, "m2", &endedAt))
+
+		due, err := repo.GetDueUnpublishes(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1, "media not ready yet stays scheduled")
+		assert.Equal(t, "m1", due[0].ID)
+
+		require.NoError(t, repo.UpdateUnpublishAt(ctx, "m1", nil))
+		due, err = repo.GetDueUnpublishes(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+		assert.ErrorIs(t, repo.UpdateUnpublishAt(ctx, "missing", nil), domain.ErrMediaNotFound)
+	})
+
+	t.Run("finds premieres due to be announced", func(t *testing.T) {
+		require.NoError(t, repo.UpdatePremiere(ctx, "m1", domain.Premiere{StartsAt: &endedAt}))
+
+		due, err := repo.GetDuePremieres(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, "m1", due[0].ID)
+
+		require.NoError(t, repo.UpdatePremiere(ctx, "m1", domain.Premiere{StartsAt: &endedAt, AnnouncedAt: &now}))
+		due, err = repo.GetDuePremieres(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, due, "announced premieres are not announced again")
+
+		require.NoError(t, repo.UpdatePremiere(ctx, "m1", domain.Premiere{}))
+		media, err := repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.False(t, media.Premiere.IsScheduled())
+	})
+
+	t.Run("replaces the poster", func(t *testing.T) {
+		poster := domain.Poster{Key: domain.DerivedPosterKey("m1", 83*time.Second), Timecode: "00:01:23"}
+		require.NoError(t, repo.UpdatePoster(ctx, "m1", poster))
+
+		media, err := repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, poster, media.Poster)
+		assert.ErrorIs(t, repo.UpdatePoster(ctx, "missing", poster), domain.ErrMediaNotFound)
+	})
+
+	t.Run("replaces and clears cue points", func(t *testing.T) {
+		cuePoints := []domain.CuePoint{
+			{Position: domain.CuePointPreroll},
+			{Position: domain.CuePointMidroll, OffsetSeconds: 600, MaxDurationSeconds: 90},
+		}
+		require.NoError(t, repo.UpdateCuePoints(ctx, "m1", cuePoints))
+		media, err := repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, cuePoints, media.CuePoints)
+
+		require.NoError(t, repo.UpdateCuePoints(ctx, "m1", nil))
+		media, err = repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Empty(t, media.CuePoints)
+		assert.ErrorIs(t, repo.UpdateCuePoints(ctx, "missing", nil), domain.ErrMediaNotFound)
+	})
+
+	t.Run("replaces and clears chapters", func(t *testing.T) {
+		chapters := []domain.Chapter{{Title: "Intro", StartTime: 0}}
+		suggestions := []domain.ChapterSuggestion{{StartTime: 602, Kind: domain.ChapterBoundaryStinger}}
+		require.NoError(t, repo.UpdateChapters(ctx, "m1", chapters, suggestions))
+		media, err := repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, chapters, media.Chapters)
+		assert.Equal(t, suggestions, media.ChapterSuggestions)
+
+		require.NoError(t, repo.UpdateChapters(ctx, "m1", chapters, nil))
+		media, err = repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, chapters, media.Chapters)
+		assert.Empty(t, media.ChapterSuggestions)
+		assert.ErrorIs(t, repo.UpdateChapters(ctx, "missing", nil, nil), domain.ErrMediaNotFound)
+	})
+
+	t.Run("replaces and clears audio tracks", func(t *testing.T) {
+		tracks := []domain.AudioTrack{{Language: "en", Label: "English", Format: "mp3", Key: domain.AudioTrackKey("m1", "en", "mp3"), Size: 2048}}
+		require.NoError(t, repo.UpdateAudioTracks(ctx, "m1", tracks))
+		media, err := repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, tracks, media.AudioTracks)
+
+		require.NoError(t, repo.UpdateAudioTracks(ctx, "m1", nil))
+		media, err = repo.GetByID(ctx, "m1")
+		require.NoError(t, err)
+		assert.Empty(t, media.AudioTracks)
+		assert.ErrorIs(t, repo.UpdateAudioTracks(ctx, "missing", nil), domain.ErrMediaNotFound)
+	})
+
+	t.Run("links and lists series parts", func(t *testing.T) {
+		require.NoError(t, repo.UpdateSeries(ctx, "m2", "series", 2, false))
+		require.NoError(t, repo.UpdateSeries(ctx, "m1", "series", 1, false))
+		parts, err := repo.GetBySeries(ctx, "series")
+		require.NoError(t, err)
+		require.Len(t, parts, 2)
+		assert.Equal(t, "m1", parts[0].ID)
+		assert.Equal(t, "m2", parts[1].ID)
+
+		require.NoError(t, repo.UpdateSeries(ctx, "m2", "", 0, true))
+		media, err := repo.GetByID(ctx, "m2")
+		require.NoError(t, err)
+		assert.Empty(t, media.SeriesID)
+		assert.True(t, media.SeriesLocked)
+		assert.ErrorIs(t, repo.UpdateSeries(ctx, "missing", "", 0, false), domain.ErrMediaNotFound)
+	})
+
+	t.Run("moves deleted media to the trash until purged", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, &domain.Media{ID: "trashed", Title: "Trashed", Type: domain.TypePodcast, Status: domain.StatusDeleted}))
+		require.NoError(t, repo.Delete(ctx, "trashed"))
+		assert.ErrorIs(t, repo.Delete(ctx, "trashed"), domain.ErrMediaNotFound)
+
+		_, err := repo.GetByID(ctx, "trashed")
+		assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+		total, err := repo.GetTotal(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+
+		trashed, err := repo.GetTrashed(ctx, time.Now().Add(-time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, trashed)
+		trashed, err = repo.GetTrashed(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Equal(t, "trashed", trashed[0].ID)
+		withTrashed, err := repo.GetByIDsWithTrashed(ctx, []string{"trashed", "missing"})
+		require.NoError(t, err)
+		require.Len(t, withTrashed, 1)
+		assert.Equal(t, "trashed", withTrashed[0].ID)
+
+		assert.ErrorIs(t, repo.Purge(ctx, "m1"), domain.ErrMediaNotFound)
+		require.NoError(t, repo.Purge(ctx, "trashed"))
+		trashed, err = repo.GetTrashed(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		assert.Empty(t, trashed)
+	})
+
+	t.Run("reports missing media", func(t *testing.T) {
+		assert.ErrorIs(t, repo.UpdateStatus(ctx, "missing", domain.StatusReady), domain.ErrMediaNotFound)
+		// Deleted media stays deleted
+		assert.ErrorIs(t, repo.UpdateStatus(ctx, "m4", domain.StatusReady), domain.ErrMediaNotFound)
+		_, err := repo.GetByID(ctx, "missing")
+		assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	})
+
+	t.Run("sums storage and finds duplicates of live media", func(t *testing.T) {
+		hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+		require.NoError(t, repo.Create(ctx, &domain.Media{
+			ID: "m3", Title: "Episode 3", Type: domain.TypePodcast, Status: domain.StatusReady, FileSize: 300, ContentHash: hash,
+		}))
+		require.NoError(t, repo.Create(ctx, &domain.Media{
+			ID: "m4", Title: "Episode 4", Type: domain.TypePodcast, Status: domain.StatusDeleted, FileSize: 4000, ContentHash: hash,
+		}))
+		require.NoError(t, repo.Create(ctx, &domain.Media{
+			ID: "m5", Title: "Episode 5", Type: domain.TypePodcast, Status: domain.StatusReady, FileSize: 50000, TenantID: "acme",
+		}))
+
+		usage, err := repo.GetStorageUsage(ctx, domain.DefaultTenantID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(300), usage)
+
+		duplicate, err := repo.GetByContentHash(ctx, domain.DefaultTenantID, hash)
+		require.NoError(t, err)
+		assert.Equal(t, "m3", duplicate.ID)
+
+		_, err = repo.GetByContentHash(ctx, "acme", hash)
+		assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	})
+}
@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// sqliteSearchTable is the FTS5 table holding the search index
+const sqliteSearchTable = "search_fts"
+
+// SQLiteSearchRepository implements SearchRepository using SQLite FTS5
+type SQLiteSearchRepository struct {
+	db     *gorm.DB
+	boosts func() SearchBoosts
+}
+
+// NewSQLiteSearchRepository creates a new SQLite search repository and its FTS5
+// table. SQLite must be built with FTS5, e.g. with the sqlite_fts5 build tag.
+// boosts is consulted on every search; nil uses DefaultSearchBoosts.
+func NewSQLiteSearchRepository(conn *database.Connection, boosts func() SearchBoosts) (SearchRepository, error) {
+	if boosts == nil {
+		boosts = func() SearchBoosts { return DefaultSearchBoosts }
+	}
+
+	if err := createSQLiteSearchTable(conn.DB); err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			return nil, fmt.Errorf("SQLite was built without FTS5, build with -tags sqlite_fts5: %w", err)
+		}
+		return nil, fmt.Errorf("failed to create search table: %w", err)
+	}
+
+	return &SQLiteSearchRepository{
+		db:     conn.DB,
+		boosts: boosts,
+	}, nil
+}
+
+// createSQLiteSearchTable creates the FTS5 table. FTS5 tables cannot gain
+// columns, so a table lacking the latest columns is dropped and created again;
+// its content comes back with the next reindex.
+func createSQLiteSearchTable(db *gorm.DB) error {
+	var outdated int64
+	err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE name = ? AND sql NOT LIKE ?", sqliteSearchTable, "%audio_languages%").
+		Scan(&outdated).Error
+	if err != nil {
+		return err
+	}
+	if outdated > 0 {
+		log.Printf("Recreating search table %s with its latest columns, reindex to search existing media", sqliteSearchTable)
+		if err := db.Exec("DROP TABLE " + sqliteSearchTable).Error; err != nil {
+			return err
+		}
+	}
+
+	// Filter columns are unindexed so they do not match search terms
+	return db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS ` + sqliteSearchTable + ` USING fts5(
+		media_id UNINDEXED,
+		title,
+		description,
+		content,
+		type UNINDEXED,
+		age_rating UNINDEXED,
+		explicit UNINDEXED,
+		show_id UNINDEXED,
+		show_title,
+		categories,
+		hosts,
+		guests,
+		media_updated_at UNINDEXED,
+		tags UNINDEXED,
+		audio_languages UNINDEXED,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`).Error
+}
+
+// sqliteSearchRow is a row of the FTS5 table
+type sqliteSearchRow struct {
+	MediaID     string
+	Title       string
+	Description string
+	Type        domain.MediaType
+	AgeRating   domain.AgeRating
+	Explicit    bool
+	ShowID      string
+	Score       float64
+
+	// With collapse=show, the group of the row and its number of hits
+	ShowGroup string
+	ShowHits  int64
+}
+
+// result converts the row to a search result
+func (row sqliteSearchRow) result() *domain.SearchResult {
+	return &domain.SearchResult{
+		Media: &domain.Media{
+			ID:          row.MediaID,
+			Title:       row.Title,
+			Description: row.Description,
+			Type:        row.Type,
+			ShowID:      row.ShowID,
+			Status:      domain.StatusReady, // Search results are ready
+			ContentRating: domain.ContentRating{
+				AgeRating: row.AgeRating,
+				Explicit:  row.Explicit,
+			},
+		},
+		Score: row.Score,
+	}
+}
+
+// Search performs full-text search ranked by BM25
+func (r *SQLiteSearchRepository) Search(ctx context.Context, req *domain.SearchRequest) ([]*domain.SearchResult, int64, error) {
+	where, args := []string{"1 = 1"}, []interface{}{}
+	parsed := req.StructuredQuery()
+	match, excluded := ftsClauseQuery(parsed.Clauses)
+	if match != "" {
+		where = append(where, sqliteSearchTable+" MATCH ?")
+		args = append(args, match)
+	}
+	if excluded != "" {
+		where = append(where, "rowid NOT IN (SELECT rowid FROM "+sqliteSearchTable+" WHERE "+sqliteSearchTable+" MATCH ?)")
+		args = append(args, excluded)
+	}
+	// Tags are stored between bars, like |news|tech|
+	for _, tag := range parsed.Tags {
+		where = append(where, "instr(tags, ?) > 0")
+		args = append(args, "|"+tag+"|")
+	}
+	for _, tag := range parsed.ExcludedTags {
+		where = append(where, "instr(tags, ?) = 0")
+		args = append(args, "|"+tag+"|")
+	}
+	if req.Type != "" {
+		where = append(where, "type = ?")
+		args = append(args, req.Type)
+	}
+	if req.ShowID != "" {
+		where = append(where, "show_id = ?")
+		args = append(args, req.ShowID)
+	}
+	// Audio languages are stored between bars like tags
+	if req.AudioLanguage != "" {
+		where = append(where, "instr(audio_languages, ?) > 0")
+		args = append(args, "|"+req.AudioLanguage+"|")
+	}
+	// Exclude explicit content for safe search
+	if req.Safe {
+		where = append(where, "explicit = 0")
+	}
+	condition := strings.Join(where, " AND ")
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	// bm25 is lower for better matches; its weights follow the column order
+	boosts := r.boosts()
+	score := "0.0"
+	order := "rowid DESC"
+	if match != "" {
+		score = fmt.Sprintf("-bm25(%s, 0, %g, %g, 1.0, 0, 0, 0, 0, %g, %g, %g, %g, 0, 0, 0)",
+			sqliteSearchTable, boosts.Title, boosts.Description, boosts.Show, boosts.Category, boosts.Host, boosts.Guest)
+		order = "score DESC"
+	}
+
+	if req.Collapse == domain.SearchCollapseShow {
+		return r.searchCollapsed(ctx, condition, args, score, limit, offset)
+	}
+
+	var total int64
+	countSQL := "SELECT COUNT(*) FROM " + sqliteSearchTable + " WHERE " + condition
+	if err := r.db.WithContext(ctx).Raw(countSQL, args...).Scan(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	searchSQL := fmt.Sprintf(
+		"SELECT media_id, title, description, type, age_rating, explicit, show_id, %s AS score FROM %s WHERE %s ORDER BY %s LIMIT ? OFFSET ?",
+		score, sqliteSearchTable, condition, order,
+	)
+	var rows []sqliteSearchRow
+	if err := r.db.WithContext(ctx).Raw(searchSQL, append(args, limit, offset)...).Scan(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search: %w", err)
+	}
+
+	results := make([]*domain.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, row.result())
+	}
+
+	return results, total, nil
+}
+
+// searchCollapsed returns a page of the best hit of each show among the rows
+// matching condition, with the next hits of the show as inner hits. Shows are
+// ranked by their best hit, and the total counts shows.
+func (r *SQLiteSearchRepository) searchCollapsed(ctx context.Context, condition string, args []interface{}, score string, limit, offset int) ([]*domain.SearchResult, int64, error) {
+	// Media outside shows is a group of its own, like domain.ShowGroup
+	hitsSQL := fmt.Sprintf(
+		"SELECT media_id, title, description, type, age_rating, explicit, show_id, %s AS score, rowid AS rid, "+
+			"CASE WHEN show_id != '' THEN show_id ELSE 'media:' || media_id END AS show_group FROM %s WHERE %s",
+		score, sqliteSearchTable, condition,
+	)
+	rankedSQL := "SELECT *, ROW_NUMBER() OVER (PARTITION BY show_group ORDER BY score DESC, rid DESC) AS show_rank, " +
+		"COUNT(*) OVER (PARTITION BY show_group) AS show_hits FROM (" + hitsSQL + ")"
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(DISTINCT show_group) FROM ("+hitsSQL+")", args...).Scan(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	var tops []sqliteSearchRow
+	topsSQL := "SELECT * FROM (" + rankedSQL + ") WHERE show_rank = 1 ORDER BY score DESC, rid DESC LIMIT ? OFFSET ?"
+	if err := r.db.WithContext(ctx).Raw(topsSQL, append(args, limit, offset)...).Scan(&tops).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search: %w", err)
+	}
+	if len(tops) == 0 {
+		return []*domain.SearchResult{}, total, nil
+	}
+
+	results := make([]*domain.SearchResult, 0, len(tops))
+	byGroup := make(map[string]*domain.SearchResult, len(tops))
+	groups := make([]string, 0, len(tops))
+	for _, row := range tops {
+		result := row.result()
+		result.InnerHitsTotal = row.ShowHits - 1
+		results = append(results, result)
+		byGroup[row.ShowGroup] = result
+		groups = append(groups, row.ShowGroup)
+	}
+
+	var inner []sqliteSearchRow
+	innerSQL := "SELECT * FROM (" + rankedSQL + ") WHERE show_rank BETWEEN 2 AND ? AND show_group IN ? ORDER BY show_rank"
+	if err := r.db.WithContext(ctx).Raw(innerSQL, append(args, domain.CollapseInnerHits+1, groups)...).Scan(&inner).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get collapsed hits: %w", err)
+	}
+	for _, row := range inner {
+		top := byGroup[row.ShowGroup]
+		top.InnerHits = append(top.InnerHits, row.result())
+	}
+
+	return results, total, nil
+}
+
+// Suggest provides titles starting with the words of the query
+func (r *SQLiteSearchRepository) Suggest(ctx context.Context, req *domain.SuggestRequest) ([]*domain.Suggestion, error) {
+	match := ftsMatchQuery(req.Query, true)
+	if match == "" {
+		return nil, nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	query := `
+		SELECT title AS text, COUNT(*) AS count FROM ` + sqliteSearchTable + `
+		WHERE ` + sqliteSearchTable + ` MATCH ? AND (? = 0 OR explicit = 0)
+		GROUP BY title
+		ORDER BY count DESC, title ASC
+		LIMIT ?`
+
+	var suggestions []*domain.Suggestion
+	if err := r.db.WithContext(ctx).Raw(query, "{title} : ("+match+")", req.Safe, limit).Scan(&suggestions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// IndexMedia adds or updates media in search index
+func (r *SQLiteSearchRepository) IndexMedia(ctx context.Context, media *domain.Media) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM "+sqliteSearchTable+" WHERE media_id = ?", media.ID).Error; err != nil {
+			return err
+		}
+		return insertSearchRow(tx, media)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index media: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromIndex removes media from search index
+func (r *SQLiteSearchRepository) RemoveFromIndex(ctx context.Context, mediaID string) error {
+	if err := r.db.WithContext(ctx).Exec("DELETE FROM "+sqliteSearchTable+" WHERE media_id = ?", mediaID).Error; err != nil {
+		return fmt.Errorf("failed to remove from index: %w", err)
+	}
+	return nil
+}
+
+// ReindexAll rebuilds the entire search index
+func (r *SQLiteSearchRepository) ReindexAll(ctx context.Context, mediaList []*domain.Media) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM " + sqliteSearchTable).Error; err != nil {
+			return fmt.Errorf("failed to clear search index: %w", err)
+		}
+		for _, media := range mediaList {
+			if err := insertSearchRow(tx, media); err != nil {
+				return fmt.Errorf("failed to index media %s: %w", media.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListIndexed lists every document of the search index with the update
+// time of the media it was built from
+func (r *SQLiteSearchRepository) ListIndexed(ctx context.Context) ([]*domain.IndexedMedia, error) {
+	var rows []struct {
+		MediaID        string
+		MediaUpdatedAt string
+	}
+	if err := r.db.WithContext(ctx).Raw("SELECT media_id, media_updated_at FROM " + sqliteSearchTable + " ORDER BY media_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list indexed media: %w", err)
+	}
+
+	indexed := make([]*domain.IndexedMedia, 0, len(rows))
+	for _, row := range rows {
+		entry := &domain.IndexedMedia{MediaID: row.MediaID}
+		// A time that does not parse leaves the document stale, so it gets rebuilt
+		if updatedAt, err := time.Parse(time.RFC3339Nano, row.MediaUpdatedAt); err == nil {
+			entry.UpdatedAt = updatedAt
+		}
+		indexed = append(indexed, entry)
+	}
+	return indexed, nil
+}
+
+// insertSearchRow adds media to the FTS5 table, with the show context of its
+// search context. Only the title and description are stored as they are, to be
+// returned with hits; the other searched columns are normalized like queries.
+func insertSearchRow(tx *gorm.DB, media *domain.Media) error {
+	searchContext := media.SearchContext
+	if searchContext == nil {
+		searchContext = &domain.SearchContext{}
+	}
+
+	return tx.Exec(
+		"INSERT INTO "+sqliteSearchTable+" (media_id, title, description, content, type, age_rating, explicit, show_id, show_title, categories, hosts, guests, media_updated_at, tags, audio_languages) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		media.ID,
+		media.Title,
+		media.Description,
+		domain.NormalizeSearchText(media.Title+" "+media.Description),
+		string(media.Type),
+		string(media.ContentRating.AgeRating),
+		media.ContentRating.IsExplicit(),
+		media.ShowID,
+		domain.NormalizeSearchText(searchContext.ShowTitle),
+		domain.NormalizeSearchText(strings.Join(searchContext.Categories, " ")),
+		domain.NormalizeSearchText(strings.Join(searchContext.Hosts, " ")),
+		domain.NormalizeSearchText(strings.Join(searchContext.Guests, " ")),
+		media.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		"|"+strings.Join(domain.NormalizeLabels(media.Labels), "|")+"|",
+		"|"+strings.Join(media.AudioLanguages(), "|")+"|",
+	).Error
+}
+
+// ftsClauseQuery turns the clauses of a query into an FTS5 query matching any
+// of them. A query of exclusions only cannot be expressed in FTS5, so it is
+// returned as excluded, an FTS5 query of the documents to leave out.
+func ftsClauseQuery(clauses []domain.SearchClause) (match, excluded string) {
+	if len(clauses) == 1 && len(clauses[0].Terms) == 0 {
+		terms := make([]string, 0, len(clauses[0].Excluded))
+		for _, term := range clauses[0].Excluded {
+			terms = append(terms, ftsTerm(term.Text))
+		}
+		return "", strings.Join(terms, " OR ")
+	}
+
+	alternatives := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		terms := make([]string, 0, len(clause.Terms))
+		for _, term := range clause.Terms {
+			terms = append(terms, ftsTerm(term.Text))
+		}
+		alternative := strings.Join(terms, " AND ")
+		for _, term := range clause.Excluded {
+			alternative += " NOT " + ftsTerm(term.Text)
+		}
+		alternatives = append(alternatives, "("+alternative+")")
+	}
+	return strings.Join(alternatives, " OR "), ""
+}
+
+// ftsTerm quotes text as an FTS5 string, matching its words as a phrase
+func ftsTerm(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
+
+// ftsMatchQuery turns user input into an FTS5 query matching all of its words.
+// Words are quoted so FTS5 operators in the input are taken literally; with
+// prefix the last word also matches longer words.
+func ftsMatchQuery(input string, prefix bool) string {
+	words := strings.Fields(input)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, ftsTerm(word))
+	}
+	if prefix && len(terms) > 0 {
+		terms[len(terms)-1] += "*"
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLiteConnection(t *testing.T) *database.Connection {
+	t.Helper()
+	// Same query tuning as the defaults of config.Load
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		SQLitePath:             filepath.Join(t.TempDir(), "test.db"),
+		PrepareStmt:            true,
+		SkipDefaultTransaction: true,
+		QueryTimeoutMs:         5000,
+	}}
+	conn, err := database.NewSQLiteConnection(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newTestSQLiteSearchRepository(t *testing.T) SearchRepository {
+	t.Helper()
+	repo, err := NewSQLiteSearchRepository(newTestSQLiteConnection(t), nil)
+	if err != nil && strings.Contains(err.Error(), "FTS5") {
+		t.Skip("SQLite built without FTS5, run with -tags sqlite_fts5")
+	}
+	require.NoError(t, err)
+	return repo
+}
+
+func TestSQLiteSearchRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLiteSearchRepository(t)
+
+	require.NoError(t, repo.ReindexAll(ctx, []*domain.Media{
+		{ID: "m1", Title: "Golang Concurrency", Description: "Channels and goroutines", Type: domain.TypeVideo, ShowID: "show-1", Labels: []string{"tech"},
+			AudioLanguage: "ar", AudioTracks: []domain.AudioTrack{{Language: "en"}}},
+		{ID: "m2", Title: "Cooking Show", Description: "Learning golang while cooking", Type: domain.TypePodcast, Labels: []string{"Food", "tech"}},
+		{ID: "m3", Title: "Golang After Dark", Description: "Late night talk", Type: domain.TypeVideo,
+			ContentRating: domain.ContentRating{AgeRating: domain.AgeRating18}},
+	}))
+
+	t.Run("ranks title matches first", func(t *testing.T) {
+		results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, results, 3)
+		assert.NotEqual(t, "m2", results[0].Media.ID)
+		assert.Equal(t, "m2", results[2].Media.ID)
+	})
+
+	t.Run("filters by type and safe search", func(t *testing.T) {
+		results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang", Type: string(domain.TypeVideo), Safe: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, results, 1)
+		assert.Equal(t, "m1", results[0].Media.ID)
+	})
+
+	t.Run("filters by show", func(t *testing.T) {
+		results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang", ShowID: "show-1"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, results, 1)
+		assert.Equal(t, "m1", results[0].Media.ID)
+		assert.Equal(t, "show-1", results[0].Media.ShowID)
+	})
+
+	t.Run("filters by audio language", func(t *testing.T) {
+		for _, language := range []string{"ar", "en"} {
+			results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang", AudioLanguage: language})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), total, language)
+			require.Len(t, results, 1)
+			assert.Equal(t, "m1", results[0].Media.ID)
+		}
+
+		_, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang", AudioLanguage: "e"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("takes search operators literally", func(t *testing.T) {
+		_, total, err := repo.Search(ctx, &domain.SearchRequest{Query: `golang" OR "cooking`})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("searches the advanced query syntax", func(t *testing.T) {
+		for query, expected := range map[string][]string{
+			`"golang concurrency"`:           {"m1"},
+			`"concurrency golang"`:           nil,
+			"golang -cooking":                {"m1", "m3"},
+			"-cooking":                       {"m1", "m3"},
+			"cooking OR goroutines":          {"m1", "m2"},
+			"golang tag:food":                {"m2"},
+			"tag:tech -tag:food":             {"m1"},
+			`golang -"after dark" -tag:food`: {"m1"},
+		} {
+			parsed, err := domain.ParseSearchQuery(query)
+			require.NoError(t, err)
+			results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: query, Parsed: parsed.Normalized()})
+			require.NoError(t, err, query)
+
+			var ids []string
+			for _, result := range results {
+				ids = append(ids, result.Media.ID)
+			}
+			sort.Strings(ids)
+			assert.Equal(t, expected, ids, query)
+			assert.Equal(t, int64(len(expected)), total, query)
+		}
+	})
+
+	t.Run("suggests titles by prefix", func(t *testing.T) {
+		suggestions, err := repo.Suggest(ctx, &domain.SuggestRequest{Query: "gol", Safe: true})
+		require.NoError(t, err)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, "Golang Concurrency", suggestions[0].Text)
+	})
+
+	t.Run("finds episodes by show context", func(t *testing.T) {
+		require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m4", Title: "Episode 12", Type: domain.TypePodcast,
+			SearchContext: &domain.SearchContext{ShowTitle: "Fnjan", Categories: []string{"Society"}, Hosts: []string{"Abdulrahman Abumalih"},
+				Guests: []string{"Visiting Guest"}}}))
+
+		for _, query := range []string{"fnjan", "society", "abumalih", "visiting"} {
+			results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: query})
+			require.NoError(t, err)
+			require.Equal(t, int64(1), total, query)
+			assert.Equal(t, "m4", results[0].Media.ID)
+		}
+		require.NoError(t, repo.RemoveFromIndex(ctx, "m4"))
+	})
+
+	t.Run("matches equivalent spellings", func(t *testing.T) {
+		require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m5", Title: "Baking Stories", Description: "مُقدِّمة في الخَبز", Type: domain.TypePodcast}))
+
+		for _, query := range []string{"baked", "story", "مقدمة", "المقدمه", "الخبز"} {
+			results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: query})
+			require.NoError(t, err)
+			require.Equal(t, int64(1), total, query)
+			assert.Equal(t, "m5", results[0].Media.ID)
+		}
+		require.NoError(t, repo.RemoveFromIndex(ctx, "m5"))
+	})
+
+	t.Run("collapses episodes by show", func(t *testing.T) {
+		for _, id := range []string{"e1", "e2", "e3", "e4", "e5"} {
+			require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: id, Title: "Golang Weekly " + id, Type: domain.TypePodcast, ShowID: "show-9"}))
+		}
+
+		results, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang", Collapse: domain.SearchCollapseShow})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total, "show-9, show-1 and two media outside shows")
+		require.Len(t, results, 4)
+		for _, result := range results {
+			if result.Media.ShowID != "show-9" {
+				assert.Empty(t, result.InnerHits)
+				continue
+			}
+			assert.Len(t, result.InnerHits, domain.CollapseInnerHits)
+			assert.Equal(t, int64(4), result.InnerHitsTotal)
+			for _, inner := range result.InnerHits {
+				assert.Equal(t, "show-9", inner.Media.ShowID)
+				assert.NotEqual(t, result.Media.ID, inner.Media.ID)
+				assert.LessOrEqual(t, inner.Score, result.Score)
+			}
+		}
+
+		results, total, err = repo.Search(ctx, &domain.SearchRequest{Query: "golang", Collapse: domain.SearchCollapseShow, Limit: 2, Offset: 3})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Len(t, results, 1)
+
+		for _, id := range []string{"e1", "e2", "e3", "e4", "e5"} {
+			require.NoError(t, repo.RemoveFromIndex(ctx, id))
+		}
+	})
+
+	t.Run("reindexes and removes media", func(t *testing.T) {
+		require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m1", Title: "Rust Concurrency", Type: domain.TypeVideo}))
+		require.NoError(t, repo.RemoveFromIndex(ctx, "m3"))
+
+		_, total, err := repo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+
+		_, total, err = repo.Search(ctx, &domain.SearchRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("lists indexed media with their update time", func(t *testing.T) {
+		updatedAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+		require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m1", Title: "Rust Concurrency", Type: domain.TypeVideo, UpdatedAt: updatedAt}))
+
+		indexed, err := repo.ListIndexed(ctx)
+		require.NoError(t, err)
+		require.Len(t, indexed, 2)
+		assert.Equal(t, "m1", indexed[0].MediaID)
+		assert.True(t, updatedAt.Equal(indexed[0].UpdatedAt))
+		assert.Equal(t, "m2", indexed[1].MediaID)
+	})
+}
+
+func TestSQLiteSearchRepository_RecreatesTableWithoutShowColumn(t *testing.T) {
+	// Given a search table created before the show column existed
+	conn := newTestSQLiteConnection(t)
+	err := conn.DB.Exec("CREATE VIRTUAL TABLE " + sqliteSearchTable + " USING fts5(media_id UNINDEXED, title, description, content, type UNINDEXED, age_rating UNINDEXED, explicit UNINDEXED)").Error
+	if err != nil && strings.Contains(err.Error(), "fts5") {
+		t.Skip("SQLite built without FTS5, run with -tags sqlite_fts5")
+	}
+	require.NoError(t, err)
+
+	// When
+	repo, err := NewSQLiteSearchRepository(conn, nil)
+	require.NoError(t, err)
+
+	// Then media can be indexed and searched by show
+	ctx := context.Background()
+	require.NoError(t, repo.IndexMedia(ctx, &domain.Media{ID: "m1", Title: "Golang", ShowID: "show-1"}))
+	_, total, err := repo.Search(ctx, &domain.SearchRequest{ShowID: "show-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
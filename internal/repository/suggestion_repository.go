@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// SuggestionRepository maintains the precomputed suggestion terms the
+// PostgreSQL search repository serves suggestions from
+type SuggestionRepository interface {
+	// RefreshSuggestions recomputes the suggestion terms from the search index
+	RefreshSuggestions(ctx context.Context) error
+}
+
+// sqlSuggestionRepository implements SuggestionRepository over the search_suggestions table
+type sqlSuggestionRepository struct {
+	conn *database.Connection
+}
+
+// NewSuggestionRepository creates a new suggestion repository
+func NewSuggestionRepository(conn *database.Connection) SuggestionRepository {
+	return &sqlSuggestionRepository{
+		conn: conn,
+	}
+}
+
+// refreshSuggestionsQuery aggregates the indexed titles and the artist and
+// album tags of the indexed media
+const refreshSuggestionsQuery = `
+	INSERT INTO search_suggestions (term, kind, count, safe_count, refreshed_at)
+	SELECT term, kind, COUNT(*), COUNT(*) FILTER (WHERE NOT explicit), ? FROM (
+		SELECT s.title AS term, ? AS kind, s.explicit FROM search_index s
+		UNION ALL
+		SELECT m.tag_artist, ?, s.explicit FROM search_index s JOIN media_files m ON m.id = s.media_id WHERE m.tag_artist <> ''
+		UNION ALL
+		SELECT m.tag_album, ?, s.explicit FROM search_index s JOIN media_files m ON m.id = s.media_id WHERE m.tag_album <> ''
+	) terms
+	GROUP BY term, kind`
+
+// RefreshSuggestions replaces the suggestion terms in one transaction, so
+// suggestions keep being served from the previous terms until it commits
+func (r *sqlSuggestionRepository) RefreshSuggestions(ctx context.Context) error {
+	return r.conn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM search_suggestions").Error; err != nil {
+			return fmt.Errorf("failed to clear suggestions: %w", err)
+		}
+		err := tx.Exec(refreshSuggestionsQuery, time.Now().UTC(),
+			domain.SuggestionKindTitle, domain.SuggestionKindArtist, domain.SuggestionKindAlbum).Error
+		if err != nil {
+			return fmt.Errorf("failed to refresh suggestions: %w", err)
+		}
+		return nil
+	})
+}
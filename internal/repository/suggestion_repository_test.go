@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestionRepository_RefreshSuggestions(t *testing.T) {
+	// Given indexed media sharing a title and an artist tag, one of them explicit
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	require.NoError(t, database.CreateIndexes(conn.DB))
+	mediaRepo := NewSQLiteMediaRepository(conn)
+	searchRepo := NewPostgresSearchRepository(conn, 0)
+	media := []*domain.Media{
+		{ID: "m1", Title: "Daily Brief", Type: domain.TypePodcast, Tags: domain.MediaTags{Artist: "Thmanyah"}},
+		{ID: "m2", Title: "Daily Brief", Type: domain.TypePodcast, Tags: domain.MediaTags{Artist: "Thmanyah"},
+			ContentRating: domain.ContentRating{AgeRating: domain.AgeRating18}},
+	}
+	for _, m := range media {
+		require.NoError(t, mediaRepo.Create(ctx, m))
+		require.NoError(t, searchRepo.IndexMedia(ctx, m))
+	}
+	repo := NewSuggestionRepository(conn)
+
+	// When the suggestions are refreshed twice
+	require.NoError(t, repo.RefreshSuggestions(ctx))
+	require.NoError(t, repo.RefreshSuggestions(ctx))
+
+	// Then each term is counted once per indexed media
+	var suggestions []domain.SearchSuggestion
+	require.NoError(t, conn.DB.Order("kind").Find(&suggestions).Error)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, domain.SuggestionKindArtist, suggestions[0].Kind)
+	assert.Equal(t, "Thmanyah", suggestions[0].Term)
+	assert.Equal(t, domain.SuggestionKindTitle, suggestions[1].Kind)
+	assert.Equal(t, "Daily Brief", suggestions[1].Term)
+	assert.Equal(t, int64(2), suggestions[1].Count)
+	assert.Equal(t, int64(1), suggestions[1].SafeCount)
+}
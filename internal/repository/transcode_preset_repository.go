@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// TranscodePresetRepository defines the contract for transcode preset data access
+type TranscodePresetRepository interface {
+	// Create creates a new preset; a default preset replaces the previous default of its media type
+	Create(ctx context.Context, preset *domain.TranscodePreset) error
+
+	// GetByID retrieves a preset by ID
+	GetByID(ctx context.Context, id string) (*domain.TranscodePreset, error)
+
+	// GetAll retrieves all presets ordered by name
+	GetAll(ctx context.Context) ([]*domain.TranscodePreset, error)
+
+	// GetDefault retrieves the default preset of a media type
+	GetDefault(ctx context.Context, mediaType domain.MediaType) (*domain.TranscodePreset, error)
+
+	// Update updates an existing preset; a default preset replaces the previous default of its media type
+	Update(ctx context.Context, preset *domain.TranscodePreset) error
+
+	// Delete removes a preset by ID
+	Delete(ctx context.Context, id string) error
+}
+
+// postgresTranscodePresetRepository implements TranscodePresetRepository using PostgreSQL
+type postgresTranscodePresetRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresTranscodePresetRepository creates a new PostgreSQL transcode preset repository
+func NewPostgresTranscodePresetRepository(conn *database.Connection) TranscodePresetRepository {
+	return &postgresTranscodePresetRepository{
+		db: conn.DB,
+	}
+}
+
+// Create creates a new preset; a default preset replaces the previous default of its media type
+func (r *postgresTranscodePresetRepository) Create(ctx context.Context, preset *domain.TranscodePreset) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.clearDefault(tx, preset); err != nil {
+			return err
+		}
+		return tx.Create(preset).Error
+	})
+}
+
+// GetByID retrieves a preset by ID
+func (r *postgresTranscodePresetRepository) GetByID(ctx context.Context, id string) (*domain.TranscodePreset, error) {
+	var preset domain.TranscodePreset
+	err := r.db.WithContext(ctx).First(&preset, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTranscodePresetNotFound
+		}
+		return nil, err
+	}
+
+	return &preset, nil
+}
+
+// GetAll retrieves all presets ordered by name
+func (r *postgresTranscodePresetRepository) GetAll(ctx context.Context) ([]*domain.TranscodePreset, error) {
+	var presets []domain.TranscodePreset
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&presets).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.TranscodePreset, len(presets))
+	for i := range presets {
+		result[i] = &presets[i]
+	}
+
+	return result, nil
+}
+
+// GetDefault retrieves the default preset of a media type
+func (r *postgresTranscodePresetRepository) GetDefault(ctx context.Context, mediaType domain.MediaType) (*domain.TranscodePreset, error) {
+	var preset domain.TranscodePreset
+	err := r.db.WithContext(ctx).
+		Where("media_type = ? AND is_default = ?", string(mediaType), true).
+		First(&preset).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTranscodePresetNotFound
+		}
+		return nil, err
+	}
+
+	return &preset, nil
+}
+
+// Update updates a preset; a default preset replaces the previous default of its media type
+func (r *postgresTranscodePresetRepository) Update(ctx context.Context, preset *domain.TranscodePreset) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.clearDefault(tx, preset); err != nil {
+			return err
+		}
+		return tx.Save(preset).Error
+	})
+}
+
+// Delete removes a preset by ID
+func (r *postgresTranscodePresetRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&domain.TranscodePreset{}, "id = ?", id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrTranscodePresetNotFound
+	}
+
+	return nil
+}
+
+// clearDefault unsets the current default of the preset's media type when the preset becomes default
+func (r *postgresTranscodePresetRepository) clearDefault(tx *gorm.DB, preset *domain.TranscodePreset) error {
+	if !preset.IsDefault {
+		return nil
+	}
+
+	return tx.Model(&domain.TranscodePreset{}).
+		Where("media_type = ? AND is_default = ? AND id <> ?", string(preset.MediaType), true, preset.ID).
+		Update("is_default", false).Error
+}
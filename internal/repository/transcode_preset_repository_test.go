@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+)
+
+// TestTranscodePresetRepositoryInterface ensures the mock satisfies the TranscodePresetRepository interface
+func TestTranscodePresetRepositoryInterface(t *testing.T) {
+	var _ TranscodePresetRepository = (*MockTranscodePresetRepository)(nil)
+}
+
+// MockTranscodePresetRepository can be used in tests
+type MockTranscodePresetRepository struct{}
+
+func (m *MockTranscodePresetRepository) Create(ctx context.Context, preset *domain.TranscodePreset) error {
+	return nil
+}
+
+func (m *MockTranscodePresetRepository) GetByID(ctx context.Context, id string) (*domain.TranscodePreset, error) {
+	return nil, domain.ErrTranscodePresetNotFound
+}
+
+func (m *MockTranscodePresetRepository) GetAll(ctx context.Context) ([]*domain.TranscodePreset, error) {
+	return nil, nil
+}
+
+func (m *MockTranscodePresetRepository) GetDefault(ctx context.Context, mediaType domain.MediaType) (*domain.TranscodePreset, error) {
+	return nil, domain.ErrTranscodePresetNotFound
+}
+
+func (m *MockTranscodePresetRepository) Update(ctx context.Context, preset *domain.TranscodePreset) error {
+	return nil
+}
+
+func (m *MockTranscodePresetRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TranscriptRepository defines the contract for transcript and transcript suggestion data access
+type TranscriptRepository interface {
+	// Save creates or replaces the transcript of a media item
+	Save(ctx context.Context, transcript *domain.Transcript) error
+
+	// Get retrieves the transcript of a media item
+	Get(ctx context.Context, mediaID string) (*domain.Transcript, error)
+
+	// ReplacePendingSuggestions replaces the pending suggestions of a media item,
+	// keeping the accepted and dismissed ones
+	ReplacePendingSuggestions(ctx context.Context, mediaID string, suggestions []*domain.MetadataSuggestion) error
+
+	// GetSuggestions retrieves the suggestions of a media item, the most confident first
+	GetSuggestions(ctx context.Context, mediaID string) ([]*domain.MetadataSuggestion, error)
+
+	// GetSuggestion retrieves a suggestion by ID
+	GetSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error)
+
+	// ListPendingSuggestions retrieves the pending suggestions of all media, the most confident first
+	ListPendingSuggestions(ctx context.Context, limit int) ([]*domain.MetadataSuggestion, error)
+
+	// ResolveSuggestion accepts or dismisses a pending suggestion, reporting false
+	// when it was no longer pending
+	ResolveSuggestion(ctx context.Context, id string, status domain.MetadataSuggestionStatus, resolvedAt time.Time) (bool, error)
+
+	// ReplaceExplicitMatches replaces the explicit language matches of a media item
+	ReplaceExplicitMatches(ctx context.Context, mediaID string, matches []*domain.ExplicitLanguageMatch) error
+
+	// GetExplicitMatches retrieves the explicit language matches of a media item in transcript order
+	GetExplicitMatches(ctx context.Context, mediaID string) ([]*domain.ExplicitLanguageMatch, error)
+}
+
+// postgresTranscriptRepository implements TranscriptRepository using PostgreSQL
+type postgresTranscriptRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresTranscriptRepository creates a new PostgreSQL transcript repository
+func NewPostgresTranscriptRepository(conn *database.Connection) TranscriptRepository {
+	return &postgresTranscriptRepository{
+		db: conn.DB,
+	}
+}
+
+// Save creates or replaces the transcript of a media item
+func (r *postgresTranscriptRepository) Save(ctx context.Context, transcript *domain.Transcript) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"language", "text", "updated_at"}),
+	}).Create(transcript).Error
+}
+
+// Get retrieves the transcript of a media item
+func (r *postgresTranscriptRepository) Get(ctx context.Context, mediaID string) (*domain.Transcript, error) {
+	var transcript domain.Transcript
+	err := r.db.WithContext(ctx).Where("media_id = ?", mediaID).First(&transcript).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTranscriptNotFound
+		}
+		return nil, err
+	}
+
+	return &transcript, nil
+}
+
+// ReplacePendingSuggestions replaces the pending suggestions of a media item
+func (r *postgresTranscriptRepository) ReplacePendingSuggestions(ctx context.Context, mediaID string, suggestions []*domain.MetadataSuggestion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("media_id = ? AND status = ?", mediaID, domain.SuggestionPending).
+			Delete(&domain.MetadataSuggestion{}).Error
+		if err != nil || len(suggestions) == 0 {
+			return err
+		}
+		return tx.Create(suggestions).Error
+	})
+}
+
+// GetSuggestions retrieves the suggestions of a media item, the most confident first
+func (r *postgresTranscriptRepository) GetSuggestions(ctx context.Context, mediaID string) ([]*domain.MetadataSuggestion, error) {
+	var suggestions []*domain.MetadataSuggestion
+	err := r.db.WithContext(ctx).
+		Where("media_id = ?", mediaID).
+		Order("confidence DESC, kind ASC, value ASC").
+		Find(&suggestions).Error
+	return suggestions, err
+}
+
+// GetSuggestion retrieves a suggestion by ID
+func (r *postgresTranscriptRepository) GetSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error) {
+	var suggestion domain.MetadataSuggestion
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&suggestion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrMetadataSuggestionNotFound
+		}
+		return nil, err
+	}
+
+	return &suggestion, nil
+}
+
+// ListPendingSuggestions retrieves the pending suggestions of all media, the most confident first
+func (r *postgresTranscriptRepository) ListPendingSuggestions(ctx context.Context, limit int) ([]*domain.MetadataSuggestion, error) {
+	var suggestions []*domain.MetadataSuggestion
+	err := r.db.WithContext(ctx).
+		Where("status = ?", domain.SuggestionPending).
+		Order("confidence DESC, created_at DESC, id ASC").
+		Limit(limit).
+		Find(&suggestions).Error
+	return suggestions, err
+}
+
+// ResolveSuggestion accepts or dismisses a pending suggestion
+func (r *postgresTranscriptRepository) ResolveSuggestion(ctx context.Context, id string, status domain.MetadataSuggestionStatus, resolvedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.MetadataSuggestion{}).
+		Where("id = ? AND status = ?", id, domain.SuggestionPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_at": resolvedAt,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReplaceExplicitMatches replaces the explicit language matches of a media item
+func (r *postgresTranscriptRepository) ReplaceExplicitMatches(ctx context.Context, mediaID string, matches []*domain.ExplicitLanguageMatch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("media_id = ?", mediaID).Delete(&domain.ExplicitLanguageMatch{}).Error
+		if err != nil || len(matches) == 0 {
+			return err
+		}
+		return tx.CreateInBatches(matches, 100).Error
+	})
+}
+
+// GetExplicitMatches retrieves the explicit language matches of a media item in transcript order
+func (r *postgresTranscriptRepository) GetExplicitMatches(ctx context.Context, mediaID string) ([]*domain.ExplicitLanguageMatch, error) {
+	var matches []*domain.ExplicitLanguageMatch
+	err := r.db.WithContext(ctx).
+		Where("media_id = ?", mediaID).
+		Order("position ASC").
+		Find(&matches).Error
+	return matches, err
+}
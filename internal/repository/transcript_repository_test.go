@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTranscriptRepositoryInterface ensures the mock satisfies the TranscriptRepository interface
+func TestTranscriptRepositoryInterface(t *testing.T) {
+	var _ TranscriptRepository = (*MockTranscriptRepository)(nil)
+}
+
+// MockTranscriptRepository can be used in tests
+type MockTranscriptRepository struct{}
+
+func (m *MockTranscriptRepository) Save(ctx context.Context, transcript *domain.Transcript) error {
+	return nil
+}
+
+func (m *MockTranscriptRepository) Get(ctx context.Context, mediaID string) (*domain.Transcript, error) {
+	return nil, domain.ErrTranscriptNotFound
+}
+
+func (m *MockTranscriptRepository) ReplacePendingSuggestions(ctx context.Context, mediaID string, suggestions []*domain.MetadataSuggestion) error {
+	return nil
+}
+
+func (m *MockTranscriptRepository) GetSuggestions(ctx context.Context, mediaID string) ([]*domain.MetadataSuggestion, error) {
+	return nil, nil
+}
+
+func (m *MockTranscriptRepository) GetSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error) {
+	return nil, domain.ErrMetadataSuggestionNotFound
+}
+
+func (m *MockTranscriptRepository) ListPendingSuggestions(ctx context.Context, limit int) ([]*domain.MetadataSuggestion, error) {
+	return nil, nil
+}
+
+func (m *MockTranscriptRepository) ResolveSuggestion(ctx context.Context, id string, status domain.MetadataSuggestionStatus, resolvedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *MockTranscriptRepository) ReplaceExplicitMatches(ctx context.Context, mediaID string, matches []*domain.ExplicitLanguageMatch) error {
+	return nil
+}
+
+func (m *MockTranscriptRepository) GetExplicitMatches(ctx context.Context, mediaID string) ([]*domain.ExplicitLanguageMatch, error) {
+	return nil, nil
+}
+
+func TestTranscriptRepository_SaveAndGet(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresTranscriptRepository(conn)
+
+	_, err := repo.Get(ctx, "m1")
+	assert.ErrorIs(t, err, domain.ErrTranscriptNotFound)
+
+	require.NoError(t, repo.Save(ctx, &domain.Transcript{MediaID: "m1", Language: "en", Text: "first draft", UpdatedAt: time.Now()}))
+	require.NoError(t, repo.Save(ctx, &domain.Transcript{MediaID: "m1", Language: "ar", Text: "final", UpdatedAt: time.Now()}))
+
+	transcript, err := repo.Get(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, "ar", transcript.Language)
+	assert.Equal(t, "final", transcript.Text)
+}
+
+func TestTranscriptRepository_Suggestions(t *testing.T) {
+	// Given suggestions of two media items, one of them already dismissed
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresTranscriptRepository(conn)
+
+	created := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.ReplacePendingSuggestions(ctx, "m1", []*domain.MetadataSuggestion{
+		{ID: "s1", MediaID: "m1", Kind: domain.SuggestionKindLabel, Value: "startups", Confidence: 0.6, Status: domain.SuggestionPending, CreatedAt: created},
+		{ID: "s2", MediaID: "m1", Kind: domain.SuggestionKindCategory, Value: "business", Confidence: 0.8, Status: domain.SuggestionPending, CreatedAt: created},
+	}))
+	require.NoError(t, repo.ReplacePendingSuggestions(ctx, "m2", []*domain.MetadataSuggestion{
+		{ID: "s3", MediaID: "m2", Kind: domain.SuggestionKindLabel, Value: "football", Confidence: 0.7, Status: domain.SuggestionPending, CreatedAt: created},
+	}))
+	resolved, err := repo.ResolveSuggestion(ctx, "s1", domain.SuggestionDismissed, created)
+	require.NoError(t, err)
+	assert.True(t, resolved)
+
+	t.Run("resolves pending suggestions only", func(t *testing.T) {
+		resolved, err := repo.ResolveSuggestion(ctx, "s1", domain.SuggestionAccepted, created)
+		require.NoError(t, err)
+		assert.False(t, resolved)
+
+		suggestion, err := repo.GetSuggestion(ctx, "s1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.SuggestionDismissed, suggestion.Status)
+		require.NotNil(t, suggestion.ResolvedAt)
+
+		_, err = repo.GetSuggestion(ctx, "missing")
+		assert.ErrorIs(t, err, domain.ErrMetadataSuggestionNotFound)
+	})
+
+	t.Run("lists pending suggestions, the most confident first", func(t *testing.T) {
+		pending, err := repo.ListPendingSuggestions(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		assert.Equal(t, "s2", pending[0].ID)
+		assert.Equal(t, "s3", pending[1].ID)
+	})
+
+	t.Run("replacing keeps resolved suggestions", func(t *testing.T) {
+		require.NoError(t, repo.ReplacePendingSuggestions(ctx, "m1", []*domain.MetadataSuggestion{
+			{ID: "s4", MediaID: "m1", Kind: domain.SuggestionKindLabel, Value: "investors", Confidence: 0.5, Status: domain.SuggestionPending, CreatedAt: created},
+		}))
+
+		suggestions, err := repo.GetSuggestions(ctx, "m1")
+		require.NoError(t, err)
+		require.Len(t, suggestions, 2)
+		assert.Equal(t, "s1", suggestions[0].ID)
+		assert.Equal(t, "s4", suggestions[1].ID)
+	})
+}
+
+func TestTranscriptRepository_ExplicitMatches(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresTranscriptRepository(conn)
+
+	start := 12.5
+	created := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.ReplaceExplicitMatches(ctx, "m1", []*domain.ExplicitLanguageMatch{
+		{ID: "e1", MediaID: "m1", Term: "shit", StartTime: &start, Position: 0, CreatedAt: created},
+		{ID: "e2", MediaID: "m1", Term: "bastard", Position: 1, CreatedAt: created},
+	}))
+	require.NoError(t, repo.ReplaceExplicitMatches(ctx, "m2", []*domain.ExplicitLanguageMatch{
+		{ID: "e3", MediaID: "m2", Term: "shit", CreatedAt: created},
+	}))
+
+	matches, err := repo.GetExplicitMatches(ctx, "m1")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "e1", matches[0].ID)
+	require.NotNil(t, matches[0].StartTime)
+	assert.Equal(t, 12.5, *matches[0].StartTime)
+	assert.Nil(t, matches[1].StartTime)
+
+	// A clean transcript clears the matches of its media only
+	require.NoError(t, repo.ReplaceExplicitMatches(ctx, "m1", nil))
+	matches, err = repo.GetExplicitMatches(ctx, "m1")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+	matches, err = repo.GetExplicitMatches(ctx, "m2")
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+}
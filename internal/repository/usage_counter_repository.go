@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// UsageCounterRepository defines the contract for the live usage counters,
+// incremented on every request and rolled up daily
+type UsageCounterRepository interface {
+	// Increment counts a request in the counters of its day, tenant and API key
+	Increment(ctx context.Context, sample domain.UsageSample) error
+
+	// GetDay retrieves the counters of every tenant and API key of a day
+	GetDay(ctx context.Context, day string) ([]domain.UsageCounters, error)
+}
+
+// redisUsageCounterRepository implements UsageCounterRepository using Redis.
+// The counters of a tenant and API key are the fields of a hash per day, and a
+// set per day lists the hashes to roll up.
+type redisUsageCounterRepository struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewRedisUsageCounterRepository creates a new Redis usage counter
+// repository. Counters expire after retention, once rolled up.
+func NewRedisUsageCounterRepository(client *redis.Client, retention time.Duration) UsageCounterRepository {
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+
+	return &redisUsageCounterRepository{
+		client:    client,
+		retention: retention,
+	}
+}
+
+// Increment counts a request in the counters of its day, tenant and API key
+func (r *redisUsageCounterRepository) Increment(ctx context.Context, sample domain.UsageSample) error {
+	day := sample.Day()
+	member := usageCounterMember(sample.TenantID, sample.APIKeyID)
+	key := usageDayKey(day) + ":" + member
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, key, "requests", 1)
+		if sample.BytesIn > 0 {
+			pipe.HIncrBy(ctx, key, "bytes_in", sample.BytesIn)
+		}
+		if sample.BytesOut > 0 {
+			pipe.HIncrBy(ctx, key, "bytes_out", sample.BytesOut)
+		}
+		pipe.Expire(ctx, key, r.retention)
+		pipe.SAdd(ctx, usageDayKey(day), member)
+		pipe.Expire(ctx, usageDayKey(day), r.retention)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to increment usage counters: %w", err)
+	}
+	return nil
+}
+
+// GetDay retrieves the counters of every tenant and API key of a day
+func (r *redisUsageCounterRepository) GetDay(ctx context.Context, day string) ([]domain.UsageCounters, error) {
+	members, err := r.client.SMembers(ctx, usageDayKey(day)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage counters: %w", err)
+	}
+	sort.Strings(members)
+
+	pipe := r.client.Pipeline()
+	fields := make([]*redis.MapStringStringCmd, len(members))
+	for i, member := range members {
+		fields[i] = pipe.HGetAll(ctx, usageDayKey(day)+":"+member)
+	}
+	if len(members) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to read usage counters: %w", err)
+		}
+	}
+
+	counters := make([]domain.UsageCounters, 0, len(members))
+	for i, member := range members {
+		apiKeyID, tenantID, _ := strings.Cut(member, ":")
+		values := fields[i].Val()
+		counters = append(counters, domain.UsageCounters{
+			Day:      day,
+			TenantID: tenantID,
+			APIKeyID: apiKeyID,
+			Requests: parseUsageCounter(values["requests"]),
+			BytesIn:  parseUsageCounter(values["bytes_in"]),
+			BytesOut: parseUsageCounter(values["bytes_out"]),
+		})
+	}
+	return counters, nil
+}
+
+// usageDayKey returns the key of the set listing the counters of a day
+func usageDayKey(day string) string {
+	return "usage:" + day
+}
+
+// usageCounterMember identifies the counters of a tenant and API key within a
+// day. API key IDs never contain a colon, tenant IDs may.
+func usageCounterMember(tenantID, apiKeyID string) string {
+	return apiKeyID + ":" + tenantID
+}
+
+// parseUsageCounter parses a counter field, 0 when missing
+func parseUsageCounter(value string) int64 {
+	counter, _ := strconv.ParseInt(value, 10, 64)
+	return counter
+}
+
+// inMemoryUsageCounterRepository implements UsageCounterRepository in memory, for tests
+type inMemoryUsageCounterRepository struct {
+	mu       sync.Mutex
+	counters map[string]map[string]*domain.UsageCounters // day -> member -> counters
+}
+
+// NewInMemoryUsageCounterRepository creates an empty in-memory usage counter repository, safe for concurrent use
+func NewInMemoryUsageCounterRepository() UsageCounterRepository {
+	return &inMemoryUsageCounterRepository{
+		counters: make(map[string]map[string]*domain.UsageCounters),
+	}
+}
+
+// Increment counts a request in the counters of its day, tenant and API key
+func (r *inMemoryUsageCounterRepository) Increment(ctx context.Context, sample domain.UsageSample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	day := sample.Day()
+	if r.counters[day] == nil {
+		r.counters[day] = make(map[string]*domain.UsageCounters)
+	}
+	member := usageCounterMember(sample.TenantID, sample.APIKeyID)
+	counters, ok := r.counters[day][member]
+	if !ok {
+		counters = &domain.UsageCounters{Day: day, TenantID: sample.TenantID, APIKeyID: sample.APIKeyID}
+		r.counters[day][member] = counters
+	}
+	counters.Add(sample)
+	return nil
+}
+
+// GetDay retrieves the counters of every tenant and API key of a day
+func (r *inMemoryUsageCounterRepository) GetDay(ctx context.Context, day string) ([]domain.UsageCounters, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members := make([]string, 0, len(r.counters[day]))
+	for member := range r.counters[day] {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+
+	counters := make([]domain.UsageCounters, 0, len(members))
+	for _, member := range members {
+		counters = append(counters, *r.counters[day][member])
+	}
+	return counters, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageCounterRepository(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	repos := map[string]UsageCounterRepository{
+		"redis":  NewRedisUsageCounterRepository(client, time.Hour),
+		"memory": NewInMemoryUsageCounterRepository(),
+	}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			day := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+			// Given requests of two API keys, one of a tenant with a colon in its ID
+			for _, sample := range []domain.UsageSample{
+				{TenantID: "acme", APIKeyID: "k1", BytesIn: 10, BytesOut: 100, At: day},
+				{TenantID: "acme", APIKeyID: "k1", BytesOut: 50, At: day.Add(time.Hour)},
+				{TenantID: "org:globex", APIKeyID: domain.AnonymousAPIKeyID, BytesOut: 5, At: day},
+				{TenantID: "acme", APIKeyID: "k1", At: day.AddDate(0, 0, 1)},
+			} {
+				require.NoError(t, repo.Increment(ctx, sample))
+			}
+
+			// When
+			counters, err := repo.GetDay(ctx, "2025-06-01")
+			require.NoError(t, err)
+
+			// Then the requests of the day are counted per tenant and API key
+			assert.Equal(t, []domain.UsageCounters{
+				{Day: "2025-06-01", TenantID: "org:globex", APIKeyID: domain.AnonymousAPIKeyID, Requests: 1, BytesOut: 5},
+				{Day: "2025-06-01", TenantID: "acme", APIKeyID: "k1", Requests: 2, BytesIn: 10, BytesOut: 150},
+			}, counters)
+
+			empty, err := repo.GetDay(ctx, "2025-05-31")
+			require.NoError(t, err)
+			assert.Empty(t, empty)
+		})
+	}
+
+	// Redis counters expire after the retention
+	assert.Equal(t, time.Hour, server.TTL("usage:2025-06-01"))
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UsageRepository defines the contract for the daily usage rollups
+type UsageRepository interface {
+	// Save creates or replaces the rollups of their day, tenant and API key
+	Save(ctx context.Context, records []domain.UsageRecord) error
+
+	// List retrieves the rollups within [from, end) in day order, of a tenant
+	// and an API key when they are not empty
+	List(ctx context.Context, tenantID, apiKeyID string, from, end time.Time) ([]domain.UsageRecord, error)
+
+	// Top retrieves the rollups of a day with the most usage by sort
+	Top(ctx context.Context, day string, sort domain.UsageSort, limit int) ([]domain.UsageRecord, error)
+}
+
+// postgresUsageRepository implements UsageRepository using PostgreSQL
+type postgresUsageRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUsageRepository creates a new PostgreSQL usage repository
+func NewPostgresUsageRepository(conn *database.Connection) UsageRepository {
+	return &postgresUsageRepository{
+		db: conn.DB,
+	}
+}
+
+// Save creates or replaces the rollups of their day, tenant and API key
+func (r *postgresUsageRepository) Save(ctx context.Context, records []domain.UsageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "day"}, {Name: "tenant_id"}, {Name: "api_key_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"requests", "bytes_in", "bytes_out", "storage_bytes", "updated_at"}),
+	}).Create(&records).Error
+}
+
+// List retrieves the rollups within [from, end) in day order
+func (r *postgresUsageRepository) List(ctx context.Context, tenantID, apiKeyID string, from, end time.Time) ([]domain.UsageRecord, error) {
+	query := r.db.WithContext(ctx).
+		Where("day >= ? AND day < ?", from.UTC().Format(domain.CalendarDateLayout), end.UTC().Format(domain.CalendarDateLayout))
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if apiKeyID != "" {
+		query = query.Where("api_key_id = ?", apiKeyID)
+	}
+
+	var records []domain.UsageRecord
+	err := query.Order("day ASC, tenant_id ASC, api_key_id ASC").Find(&records).Error
+	return records, err
+}
+
+// Top retrieves the rollups of a day with the most usage by sort
+func (r *postgresUsageRepository) Top(ctx context.Context, day string, sort domain.UsageSort, limit int) ([]domain.UsageRecord, error) {
+	if !sort.IsValid() {
+		sort = domain.UsageSortRequests
+	}
+
+	var records []domain.UsageRecord
+	err := r.db.WithContext(ctx).
+		Where("day = ?", day).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: string(sort)}, Desc: true}).
+		Order("tenant_id ASC, api_key_id ASC").
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUsageRepositoryInterface ensures the mock satisfies the UsageRepository interface
+func TestUsageRepositoryInterface(t *testing.T) {
+	var _ UsageRepository = (*MockUsageRepository)(nil)
+}
+
+// MockUsageRepository can be used in tests
+type MockUsageRepository struct{}
+
+func (m *MockUsageRepository) Save(ctx context.Context, records []domain.UsageRecord) error {
+	return nil
+}
+
+func (m *MockUsageRepository) List(ctx context.Context, tenantID, apiKeyID string, from, end time.Time) ([]domain.UsageRecord, error) {
+	return nil, nil
+}
+
+func (m *MockUsageRepository) Top(ctx context.Context, day string, sort domain.UsageSort, limit int) ([]domain.UsageRecord, error) {
+	return nil, nil
+}
+
+func TestUsageRepository_SaveAndList(t *testing.T) {
+	// Given rollups of two tenants over two days
+	ctx := context.Background()
+	conn := newTestSQLiteConnection(t)
+	require.NoError(t, database.SimpleAutoMigrate(conn.DB))
+	repo := NewPostgresUsageRepository(conn)
+
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Save(ctx, []domain.UsageRecord{
+		{Day: "2025-06-01", TenantID: "acme", APIKeyID: "k1", Requests: 10, BytesOut: 100, UpdatedAt: now},
+		{Day: "2025-06-01", TenantID: "acme", APIKeyID: "k2", Requests: 3, BytesOut: 900, UpdatedAt: now},
+		{Day: "2025-06-01", TenantID: "globex", APIKeyID: "k3", Requests: 50, BytesOut: 10, UpdatedAt: now},
+		{Day: "2025-06-02", TenantID: "acme", APIKeyID: "k1", Requests: 1, UpdatedAt: now},
+	}))
+
+	// When a rollup is saved again, Then it replaces the previous one
+	require.NoError(t, repo.Save(ctx, []domain.UsageRecord{
+		{Day: "2025-06-02", TenantID: "acme", APIKeyID: "k1", Requests: 7, StorageBytes: 42, UpdatedAt: now},
+	}))
+	require.NoError(t, repo.Save(ctx, nil))
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	records, err := repo.List(ctx, "acme", "k1", from, from.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(10), records[0].Requests)
+	assert.Equal(t, int64(7), records[1].Requests)
+	assert.Equal(t, int64(42), records[1].StorageBytes)
+
+	records, err = repo.List(ctx, "", "", from, from.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	// And the top consumers of a day are ranked by the requested counter
+	top, err := repo.Top(ctx, "2025-06-01", domain.UsageSortRequests, 2)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, "globex", top[0].TenantID)
+	assert.Equal(t, "k1", top[1].APIKeyID)
+
+	top, err = repo.Top(ctx, "2025-06-01", domain.UsageSortBytesOut, 1)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "k2", top[0].APIKeyID)
+}
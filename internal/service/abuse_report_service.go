@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// AbuseReportService collects the abuse reports of end users into a moderation queue
+type AbuseReportService interface {
+	// ReportMedia files a report of the media by reporter. A reporter's repeated
+	// reports of the same media are folded into their open report.
+	ReportMedia(ctx context.Context, mediaID string, reporter domain.AbuseReporter, req *domain.AbuseReportRequest) (*domain.AbuseReport, error)
+
+	// GetModerationQueue lists the media with open reports, the most reported first
+	GetModerationQueue(ctx context.Context, limit int) ([]domain.ModerationQueueItem, error)
+
+	// ResolveReports closes the open reports of the media with a moderator's
+	// decision, returning how many were closed
+	ResolveReports(ctx context.Context, mediaID string, req *domain.ResolveAbuseReportsRequest) (int64, error)
+}
+
+// abuseReportService implements AbuseReportService interface
+type abuseReportService struct {
+	reportRepo       repository.AbuseReportRepository
+	mediaRepo        repository.MediaRepository
+	publisher        EventPublisher
+	reportsPerWindow int
+	now              func() time.Time
+}
+
+// NewAbuseReportService creates a new abuse report service. A reporter may file
+// reportsPerWindow reports per domain.AbuseReportWindow, the default when 0.
+func NewAbuseReportService(reportRepo repository.AbuseReportRepository, mediaRepo repository.MediaRepository, publisher EventPublisher, reportsPerWindow int) AbuseReportService {
+	if publisher == nil {
+		publisher = NewLogEventPublisher()
+	}
+	if reportsPerWindow <= 0 {
+		reportsPerWindow = domain.DefaultAbuseReportsPerWindow
+	}
+
+	return &abuseReportService{
+		reportRepo:       reportRepo,
+		mediaRepo:        mediaRepo,
+		publisher:        publisher,
+		reportsPerWindow: reportsPerWindow,
+		now:              time.Now,
+	}
+}
+
+// ReportMedia files a report of the media by reporter
+func (s *abuseReportService) ReportMedia(ctx context.Context, mediaID string, reporter domain.AbuseReporter, req *domain.AbuseReportRequest) (*domain.AbuseReport, error) {
+	req.Normalize()
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Abuse report validation failed", errs.Error())
+	}
+
+	// Make sure the media exists
+	if _, err := s.mediaRepo.GetByID(ctx, mediaID); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	reporterHash := reporter.Hash()
+	reported, err := s.reportRepo.CountSince(ctx, reporterHash, now.Add(-domain.AbuseReportWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recent abuse reports: %w", err)
+	}
+	if reported >= int64(s.reportsPerWindow) {
+		return nil, &domain.CooldownError{Action: "abuse_report", RetryAfter: domain.AbuseReportWindow}
+	}
+
+	// Fold a repeated report into the reporter's open one
+	report, err := s.reportRepo.GetOpen(ctx, mediaID, reporterHash)
+	switch {
+	case err == nil:
+		report.Reason = req.Reason
+		report.Details = req.Details
+		report.Repeats++
+		report.UpdatedAt = now
+		if err := s.reportRepo.Update(ctx, report); err != nil {
+			return nil, fmt.Errorf("failed to fold abuse report: %w", err)
+		}
+		return report, nil
+	case !errors.Is(err, domain.ErrAbuseReportNotFound):
+		return nil, fmt.Errorf("failed to look up open abuse report: %w", err)
+	}
+
+	report = &domain.AbuseReport{
+		ID:           uuid.New().String(),
+		MediaID:      mediaID,
+		ReporterHash: reporterHash,
+		Reason:       req.Reason,
+		Details:      req.Details,
+		Status:       domain.AbuseReportOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to create abuse report: %w", err)
+	}
+
+	return report, nil
+}
+
+// GetModerationQueue lists the media with open reports, the most reported first
+func (s *abuseReportService) GetModerationQueue(ctx context.Context, limit int) ([]domain.ModerationQueueItem, error) {
+	if limit <= 0 {
+		limit = domain.DefaultModerationQueueLimit
+	}
+	limit = min(limit, domain.MaxModerationQueueLimit)
+
+	queue, err := s.reportRepo.Queue(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list moderation queue: %w", err)
+	}
+	if queue == nil {
+		queue = []domain.ModerationQueueItem{}
+	}
+	return queue, nil
+}
+
+// ResolveReports closes the open reports of the media with a moderator's decision
+func (s *abuseReportService) ResolveReports(ctx context.Context, mediaID string, req *domain.ResolveAbuseReportsRequest) (int64, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return 0, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Moderation decision validation failed", errs.Error())
+	}
+
+	resolved, err := s.reportRepo.Resolve(ctx, mediaID, req.Decision, s.now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve abuse reports: %w", err)
+	}
+	if resolved == 0 {
+		return 0, domain.ErrAbuseReportNotFound
+	}
+
+	// Let the owners of the media know about the decision
+	event := domain.NewEvent(domain.EventModerationDecided, map[string]interface{}{
+		"media_id": mediaID,
+		"decision": string(req.Decision),
+		"reports":  resolved,
+	})
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish moderation decision of media %s: %v", mediaID, err)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAbuseReportRepository keeps abuse reports in memory
+type memoryAbuseReportRepository struct {
+	reports []*domain.AbuseReport
+}
+
+func (r *memoryAbuseReportRepository) Create(ctx context.Context, report *domain.AbuseReport) error {
+	stored := *report
+	r.reports = append(r.reports, &stored)
+	return nil
+}
+
+func (r *memoryAbuseReportRepository) GetOpen(ctx context.Context, mediaID, reporterHash string) (*domain.AbuseReport, error) {
+	for _, report := range r.reports {
+		if report.MediaID == mediaID && report.ReporterHash == reporterHash && report.Status == domain.AbuseReportOpen {
+			found := *report
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrAbuseReportNotFound
+}
+
+func (r *memoryAbuseReportRepository) Update(ctx context.Context, report *domain.AbuseReport) error {
+	for i, stored := range r.reports {
+		if stored.ID == report.ID {
+			updated := *report
+			r.reports[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (r *memoryAbuseReportRepository) CountSince(ctx context.Context, reporterHash string, since time.Time) (int64, error) {
+	var count int64
+	for _, report := range r.reports {
+		if report.ReporterHash == reporterHash && !report.UpdatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryAbuseReportRepository) Queue(ctx context.Context, limit int) ([]domain.ModerationQueueItem, error) {
+	var open []*domain.AbuseReport
+	counts := make(map[string]int)
+	for _, report := range r.reports {
+		if report.Status == domain.AbuseReportOpen {
+			open = append(open, report)
+			counts[report.MediaID]++
+		}
+	}
+	mediaIDs := make([]string, 0, len(counts))
+	for mediaID := range counts {
+		mediaIDs = append(mediaIDs, mediaID)
+	}
+	sort.Slice(mediaIDs, func(i, j int) bool {
+		if counts[mediaIDs[i]] != counts[mediaIDs[j]] {
+			return counts[mediaIDs[i]] > counts[mediaIDs[j]]
+		}
+		return mediaIDs[i] < mediaIDs[j]
+	})
+	if len(mediaIDs) > limit {
+		mediaIDs = mediaIDs[:limit]
+	}
+	return domain.NewModerationQueue(mediaIDs, open), nil
+}
+
+func (r *memoryAbuseReportRepository) Resolve(ctx context.Context, mediaID string, decision domain.AbuseReportStatus, resolvedAt time.Time) (int64, error) {
+	var resolved int64
+	for _, report := range r.reports {
+		if report.MediaID == mediaID && report.Status == domain.AbuseReportOpen {
+			report.Status = decision
+			report.ResolvedAt = &resolvedAt
+			resolved++
+		}
+	}
+	return resolved, nil
+}
+
+func TestAbuseReportService_ReportMedia(t *testing.T) {
+	// Given four media items and a limit of 3 reports per hour
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", Title: "Episode 1"}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m2", Title: "Episode 2"}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m3", Title: "Episode 3"}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m4", Title: "Episode 4"}))
+	s := NewAbuseReportService(&memoryAbuseReportRepository{}, mediaRepo, nil, 3).(*abuseReportService)
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	alice := domain.AbuseReporter{UserID: "alice"}
+	bob := domain.AbuseReporter{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+
+	// When alice reports m1 twice, Then her second report is folded into the first
+	first, err := s.ReportMedia(ctx, "m1", alice, &domain.AbuseReportRequest{Reason: domain.AbuseReasonSpam})
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	second, err := s.ReportMedia(ctx, "m1", alice, &domain.AbuseReportRequest{Reason: domain.AbuseReasonHarassment, Details: "  Insults the guest  "})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, 1, second.Repeats)
+	assert.Equal(t, domain.AbuseReasonHarassment, second.Reason)
+	assert.Equal(t, "Insults the guest", second.Details)
+
+	// And reports of other reporters fold into the same queue item
+	_, err = s.ReportMedia(ctx, "m1", bob, &domain.AbuseReportRequest{Reason: domain.AbuseReasonSpam})
+	require.NoError(t, err)
+	_, err = s.ReportMedia(ctx, "m2", bob, &domain.AbuseReportRequest{Reason: domain.AbuseReasonCopyright})
+	require.NoError(t, err)
+
+	queue, err := s.GetModerationQueue(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.Equal(t, "m1", queue[0].MediaID)
+	assert.Equal(t, 2, queue[0].ReportCount)
+	assert.Equal(t, map[domain.AbuseReason]int{domain.AbuseReasonHarassment: 1, domain.AbuseReasonSpam: 1}, queue[0].Reasons)
+
+	// When alice reaches the limit, Then she is rate limited until the window passes
+	for _, mediaID := range []string{"m2", "m3"} {
+		_, err = s.ReportMedia(ctx, mediaID, alice, &domain.AbuseReportRequest{Reason: domain.AbuseReasonSpam})
+		require.NoError(t, err)
+	}
+	_, err = s.ReportMedia(ctx, "m4", alice, &domain.AbuseReportRequest{Reason: domain.AbuseReasonSpam})
+	var cooldownErr *domain.CooldownError
+	require.ErrorAs(t, err, &cooldownErr)
+
+	now = now.Add(domain.AbuseReportWindow + time.Second)
+	_, err = s.ReportMedia(ctx, "m4", alice, &domain.AbuseReportRequest{Reason: domain.AbuseReasonSpam})
+	assert.NoError(t, err)
+
+	// And invalid reports and unknown media are rejected
+	_, err = s.ReportMedia(ctx, "m1", bob, &domain.AbuseReportRequest{Reason: domain.AbuseReasonOther})
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_REQUEST", businessErr.Code)
+
+	_, err = s.ReportMedia(ctx, "missing", bob, &domain.AbuseReportRequest{Reason: domain.AbuseReasonSpam})
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+}
+
+func TestAbuseReportService_ResolveReports(t *testing.T) {
+	// Given two open reports of a media item
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", Title: "Episode 1"}))
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventModerationDecided && event.Data["media_id"] == "m1" && event.Data["decision"] == "actioned"
+	})).Return(nil).Once()
+	s := NewAbuseReportService(&memoryAbuseReportRepository{}, mediaRepo, publisher, 0)
+	for _, userID := range []string{"alice", "bob"} {
+		_, err := s.ReportMedia(ctx, "m1", domain.AbuseReporter{UserID: userID}, &domain.AbuseReportRequest{Reason: domain.AbuseReasonViolence})
+		require.NoError(t, err)
+	}
+
+	// When
+	resolved, err := s.ResolveReports(ctx, "m1", &domain.ResolveAbuseReportsRequest{Decision: domain.AbuseReportActioned})
+
+	// Then the reports leave the queue and the decision is announced
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resolved)
+	queue, err := s.GetModerationQueue(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+	publisher.AssertExpectations(t)
+
+	// And media without open reports or undecided requests are rejected
+	_, err = s.ResolveReports(ctx, "m1", &domain.ResolveAbuseReportsRequest{Decision: domain.AbuseReportDismissed})
+	assert.ErrorIs(t, err, domain.ErrAbuseReportNotFound)
+
+	_, err = s.ResolveReports(ctx, "m1", &domain.ResolveAbuseReportsRequest{Decision: domain.AbuseReportOpen})
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+}
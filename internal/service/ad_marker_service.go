@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// AdMarkerService manages the ad breaks of media and resolves them for ad servers
+type AdMarkerService interface {
+	// SetCuePoints replaces the ad breaks of a media item
+	SetCuePoints(ctx context.Context, mediaID string, req *domain.CuePointsRequest) (*domain.Media, error)
+
+	// ResolveMarkers returns the ad breaks of a playable media item with its targeting context
+	ResolveMarkers(ctx context.Context, mediaID string) (*domain.AdMarkers, error)
+}
+
+// adMarkerService implements AdMarkerService interface
+type adMarkerService struct {
+	mediaRepo repository.MediaRepository
+}
+
+// NewAdMarkerService creates a new ad marker service
+func NewAdMarkerService(mediaRepo repository.MediaRepository) AdMarkerService {
+	return &adMarkerService{
+		mediaRepo: mediaRepo,
+	}
+}
+
+// SetCuePoints replaces the ad breaks of a media item
+func (s *adMarkerService) SetCuePoints(ctx context.Context, mediaID string, req *domain.CuePointsRequest) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+
+	if errs := req.Validate(media.Duration); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_CUE_POINTS", "Cue point validation failed", errs.Error())
+	}
+
+	media.CuePoints = req.Normalize(media.Duration)
+	if err := s.mediaRepo.UpdateCuePoints(ctx, media.ID, media.CuePoints); err != nil {
+		return nil, fmt.Errorf("failed to update cue points: %w", err)
+	}
+
+	return media, nil
+}
+
+// ResolveMarkers returns the ad breaks of a playable media item with its targeting context
+func (s *adMarkerService) ResolveMarkers(ctx context.Context, mediaID string) (*domain.AdMarkers, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if !media.IsProcessed() {
+		return nil, domain.ErrMediaNotFound
+	}
+
+	return domain.NewAdMarkers(media), nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdMarkerService_SetAndResolve(t *testing.T) {
+	// Given a ready episode and one still processing
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Type: domain.TypePodcast, Status: domain.StatusReady, Duration: 1800, ShowID: "show"}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "processing", Type: domain.TypePodcast, Status: domain.StatusProcessing}))
+	adMarkerService := NewAdMarkerService(mediaRepo)
+
+	// When
+	media, err := adMarkerService.SetCuePoints(ctx, "episode", &domain.CuePointsRequest{CuePoints: []domain.CuePoint{
+		{Position: domain.CuePointPostroll},
+		{Position: domain.CuePointMidroll, OffsetSeconds: 900, MaxDurationSeconds: 60},
+		{Position: domain.CuePointPreroll},
+	}})
+	require.NoError(t, err)
+
+	// Then the breaks are stored in playback order
+	expected := []domain.CuePoint{
+		{Position: domain.CuePointPreroll},
+		{Position: domain.CuePointMidroll, OffsetSeconds: 900, MaxDurationSeconds: 60},
+		{Position: domain.CuePointPostroll, OffsetSeconds: 1800},
+	}
+	assert.Equal(t, expected, media.CuePoints)
+
+	markers, err := adMarkerService.ResolveMarkers(ctx, "episode")
+	require.NoError(t, err)
+	assert.Equal(t, expected, markers.CuePoints)
+	assert.Equal(t, "show", markers.ShowID)
+
+	// And media that cannot be played has no markers
+	_, err = adMarkerService.ResolveMarkers(ctx, "processing")
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	_, err = adMarkerService.ResolveMarkers(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+}
+
+func TestAdMarkerService_SetCuePointsInvalid(t *testing.T) {
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Status: domain.StatusReady, Duration: 600}))
+	adMarkerService := NewAdMarkerService(mediaRepo)
+
+	_, err := adMarkerService.SetCuePoints(ctx, "episode", &domain.CuePointsRequest{CuePoints: []domain.CuePoint{
+		{Position: domain.CuePointMidroll, OffsetSeconds: 900},
+	}})
+
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_CUE_POINTS", businessErr.Code)
+	media, err := mediaRepo.GetByID(ctx, "episode")
+	require.NoError(t, err)
+	assert.Empty(t, media.CuePoints)
+}
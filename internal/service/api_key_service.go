@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyBytes is the amount of randomness in an API key
+const apiKeyBytes = 32
+
+// APIKeyService manages the API keys of tenants
+type APIKeyService interface {
+	// CreateAPIKey creates an API key and returns the key
+	CreateAPIKey(ctx context.Context, req *domain.APIKeyRequest) (*domain.CreatedAPIKey, error)
+
+	// ListAPIKeys lists all API keys, revoked ones included
+	ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error)
+
+	// RotateAPIKey replaces the key of an active API key and returns the new key.
+	// The previous key stops working immediately.
+	RotateAPIKey(ctx context.Context, id string) (*domain.CreatedAPIKey, error)
+
+	// RevokeAPIKey revokes an API key
+	RevokeAPIKey(ctx context.Context, id string) error
+
+	// ResolveAPIKey returns the active API key for a key
+	ResolveAPIKey(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+// apiKeyService implements APIKeyService interface
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	now        func() time.Time
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		now:        time.Now,
+	}
+}
+
+// CreateAPIKey creates an API key and returns the key
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, req *domain.APIKeyRequest) (*domain.CreatedAPIKey, error) {
+	req.Normalize()
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "API key validation failed", errs.Error())
+	}
+
+	secret, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key := &domain.APIKey{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		TenantID:  req.TenantID,
+		Role:      req.Role,
+		Prefix:    domain.APIKeyPrefix(secret),
+		KeyHash:   domain.HashAPIKey(secret),
+		CreatedAt: s.now().UTC(),
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return &domain.CreatedAPIKey{
+		APIKey: key,
+		Key:    secret,
+	}, nil
+}
+
+// ListAPIKeys lists all API keys, revoked ones included
+func (s *apiKeyService) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	keys, err := s.apiKeyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	if keys == nil {
+		keys = []*domain.APIKey{}
+	}
+	return keys, nil
+}
+
+// RotateAPIKey replaces the key of an active API key and returns the new key
+func (s *apiKeyService) RotateAPIKey(ctx context.Context, id string) (*domain.CreatedAPIKey, error) {
+	secret, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	if err := s.apiKeyRepo.Rotate(ctx, id, domain.HashAPIKey(secret), domain.APIKeyPrefix(secret), s.now().UTC()); err != nil {
+		return nil, err
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAPIKey{
+		APIKey: key,
+		Key:    secret,
+	}, nil
+}
+
+// RevokeAPIKey revokes an API key
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	return s.apiKeyRepo.Revoke(ctx, id, s.now().UTC())
+}
+
+// ResolveAPIKey returns the active API key for a key
+func (s *apiKeyService) ResolveAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	if key == "" {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByKeyHash(ctx, domain.HashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoked keys behave as if they never existed
+	if !apiKey.IsActive() {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	return apiKey, nil
+}
+
+// generateAPIKey returns a random URL-safe API key
+func generateAPIKey() (string, error) {
+	buf := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAPIKeyRepository is an in-memory APIKeyRepository
+type memoryAPIKeyRepository struct {
+	keys []*domain.APIKey
+}
+
+func (r *memoryAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	stored := *key
+	r.keys = append(r.keys, &stored)
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.ID == id {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *memoryAPIKeyRepository) Rotate(ctx context.Context, id, keyHash, prefix string, rotatedAt time.Time) error {
+	for _, key := range r.keys {
+		if key.ID == id && key.IsActive() {
+			key.KeyHash = keyHash
+			key.Prefix = prefix
+			key.RotatedAt = &rotatedAt
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	for _, key := range r.keys {
+		if key.ID == id && key.IsActive() {
+			key.RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	// Given
+	repo := &memoryAPIKeyRepository{}
+	service := NewAPIKeyService(repo)
+
+	// When
+	created, err := service.CreateAPIKey(context.Background(), &domain.APIKeyRequest{Name: " ingest ", Role: domain.RoleEditor})
+
+	// Then only the hash of the key is stored
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Key)
+	assert.Equal(t, "ingest", created.Name)
+	assert.Equal(t, domain.DefaultTenantID, created.TenantID)
+	assert.Equal(t, domain.APIKeyPrefix(created.Key), created.Prefix)
+	require.Len(t, repo.keys, 1)
+	assert.Equal(t, domain.HashAPIKey(created.Key), repo.keys[0].KeyHash)
+
+	resolved, err := service.ResolveAPIKey(context.Background(), created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleEditor, resolved.Role)
+}
+
+func TestAPIKeyService_CreateAPIKey_ValidationError(t *testing.T) {
+	service := NewAPIKeyService(&memoryAPIKeyRepository{})
+
+	_, err := service.CreateAPIKey(context.Background(), &domain.APIKeyRequest{Name: "  "})
+
+	require.Error(t, err)
+	businessErr, ok := err.(*domain.BusinessError)
+	require.True(t, ok)
+	assert.Equal(t, "INVALID_REQUEST", businessErr.Code)
+}
+
+func TestAPIKeyService_RotateAndRevoke(t *testing.T) {
+	// Given an API key
+	ctx := context.Background()
+	service := NewAPIKeyService(&memoryAPIKeyRepository{})
+	created, err := service.CreateAPIKey(ctx, &domain.APIKeyRequest{Name: "ingest"})
+	require.NoError(t, err)
+
+	// When it is rotated, Then only the new key resolves
+	rotated, err := service.RotateAPIKey(ctx, created.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, created.Key, rotated.Key)
+	assert.NotNil(t, rotated.RotatedAt)
+
+	_, err = service.ResolveAPIKey(ctx, created.Key)
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	_, err = service.ResolveAPIKey(ctx, rotated.Key)
+	assert.NoError(t, err)
+
+	// When it is revoked, Then it no longer resolves and cannot be rotated
+	require.NoError(t, service.RevokeAPIKey(ctx, created.ID))
+	_, err = service.ResolveAPIKey(ctx, rotated.Key)
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	_, err = service.RotateAPIKey(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	assert.ErrorIs(t, service.RevokeAPIKey(ctx, "missing"), domain.ErrAPIKeyNotFound)
+
+	keys, err := service.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
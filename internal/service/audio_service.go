@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// AudioTranscoder converts media to a derived audio format
+type AudioTranscoder interface {
+	TranscodeAudio(ctx context.Context, src io.Reader, dst io.Writer, format domain.AudioFormat) error
+}
+
+// AudioService serves podcast audio in derived formats
+type AudioService interface {
+	// GetAudio returns the audio of a podcast in the requested format, transcoding
+	// it on the first request and serving the cached asset afterwards
+	GetAudio(ctx context.Context, mediaID string, viewer domain.Viewer, format domain.AudioFormat) (*domain.AudioAsset, error)
+}
+
+// audioService implements AudioService interface
+type audioService struct {
+	mediaRepo   repository.MediaRepository
+	storage     storage.Storage
+	transcoder  AudioTranscoder
+	taskLimiter *TaskLimiter
+
+	mu       sync.Mutex
+	inflight map[string]chan struct{} // derived asset key -> closed when its transcode ends
+}
+
+// NewAudioService creates a new audio service
+func NewAudioService(mediaRepo repository.MediaRepository, store storage.Storage, transcoder AudioTranscoder, taskLimiter *TaskLimiter) AudioService {
+	return &audioService{
+		mediaRepo:   mediaRepo,
+		storage:     store,
+		transcoder:  transcoder,
+		taskLimiter: taskLimiter,
+		inflight:    make(map[string]chan struct{}),
+	}
+}
+
+// GetAudio returns the audio of a podcast in the requested format
+func (s *audioService) GetAudio(ctx context.Context, mediaID string, viewer domain.Viewer, format domain.AudioFormat) (*domain.AudioAsset, error) {
+	if !format.IsValid() {
+		return nil, domain.NewBusinessError("INVALID_FORMAT", "Audio format must be one of: aac, opus")
+	}
+
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := media.CheckPlayback(viewer); err != nil {
+		return nil, err
+	}
+
+	if media.Type != domain.TypePodcast {
+		return nil, domain.NewBusinessError("UNSUPPORTED_MEDIA_TYPE", "Audio conversion is only available for podcasts")
+	}
+	if !media.IsProcessed() {
+		return nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for playback")
+	}
+
+	key := domain.DerivedAudioKey(media.ID, format)
+	cached := true
+
+	object, err := s.storage.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		cached = false
+		if err := s.derive(ctx, media, format, key); err != nil {
+			return nil, err
+		}
+		object, err = s.storage.Get(ctx, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open derived audio: %w", err)
+	}
+
+	return &domain.AudioAsset{
+		Format:      format,
+		ContentType: format.ContentType(),
+		Size:        object.Size,
+		Cached:      cached,
+		Body:        object.Body,
+	}, nil
+}
+
+// derive transcodes the media into the derived asset stored under key.
+// Concurrent requests for the same asset wait for a single transcode.
+func (s *audioService) derive(ctx context.Context, media *domain.Media, format domain.AudioFormat, key string) error {
+	s.mu.Lock()
+	if done, ok := s.inflight[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	done := make(chan struct{})
+	s.inflight[key] = done
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	release, err := s.taskLimiter.Acquire(ctx, TaskTranscode)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	source, err := s.storage.Get(ctx, media.StorageKey())
+	if err != nil {
+		return fmt.Errorf("failed to open source audio: %w", err)
+	}
+	defer source.Body.Close()
+
+	// Transcode to a temporary file so a failed run never leaves a partial asset in storage
+	tmp, err := os.CreateTemp("", "audio-*"+format.Extension())
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := s.transcoder.TranscodeAudio(ctx, source.Body, tmp, format); err != nil {
+		return fmt.Errorf("failed to transcode audio to %s: %w", format, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read transcoded audio: %w", err)
+	}
+
+	if err := s.storage.Put(ctx, key, tmp); err != nil {
+		return fmt.Errorf("failed to cache derived audio: %w", err)
+	}
+	return nil
+}
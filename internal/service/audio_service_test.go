@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAudioTranscoder prefixes the source with the target format
+type fakeAudioTranscoder struct {
+	calls int32
+	err   error
+}
+
+func (f *fakeAudioTranscoder) TranscodeAudio(ctx context.Context, src io.Reader, dst io.Writer, format domain.AudioFormat) error {
+	atomic.AddInt32(&f.calls, 1)
+	if f.err != nil {
+		return f.err
+	}
+	if _, err := io.WriteString(dst, string(format)+":"); err != nil {
+		return err
+	}
+	_, err := io.Copy(dst, src)
+	return err
+}
+
+func readyPodcast() *domain.Media {
+	return &domain.Media{
+		ID:       "media-123",
+		Type:     domain.TypePodcast,
+		Status:   domain.StatusReady,
+		FilePath: domain.UploadPathPrefix + "media-123.mp3",
+	}
+}
+
+func TestAudioService_GetAudio(t *testing.T) {
+	tests := []struct {
+		name          string
+		media         *domain.Media
+		format        domain.AudioFormat
+		transcodeErr  error
+		expectError   bool
+		errorCode     string
+		expectedBody  string
+		expectedCalls int32
+	}{
+		{
+			name:          "transcodes on first request",
+			media:         readyPodcast(),
+			format:        domain.AudioFormatOpus,
+			expectedBody:  "opus:source",
+			expectedCalls: 1,
+		},
+		{
+			name:        "unsupported format",
+			media:       readyPodcast(),
+			format:      domain.AudioFormat("flac"),
+			expectError: true,
+			errorCode:   "INVALID_FORMAT",
+		},
+		{
+			name: "video media",
+			media: &domain.Media{
+				ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusReady,
+				FilePath: domain.UploadPathPrefix + "media-123.mp4",
+			},
+			format:      domain.AudioFormatAAC,
+			expectError: true,
+			errorCode:   "UNSUPPORTED_MEDIA_TYPE",
+		},
+		{
+			name: "media not ready",
+			media: &domain.Media{
+				ID: "media-123", Type: domain.TypePodcast, Status: domain.StatusProcessing,
+				FilePath: domain.UploadPathPrefix + "media-123.mp3",
+			},
+			format:      domain.AudioFormatAAC,
+			expectError: true,
+			errorCode:   "MEDIA_NOT_READY",
+		},
+		{
+			name:          "transcode failure is not cached",
+			media:         readyPodcast(),
+			format:        domain.AudioFormatAAC,
+			transcodeErr:  errors.New("ffmpeg failed"),
+			expectError:   true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			store := storage.NewLocalStorage(t.TempDir())
+			require.NoError(t, store.Put(context.Background(), tt.media.StorageKey(), strings.NewReader("source")))
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(tt.media, nil)
+			transcoder := &fakeAudioTranscoder{err: tt.transcodeErr}
+			service := NewAudioService(mockRepo, store, transcoder, nil)
+
+			// When
+			asset, err := service.GetAudio(context.Background(), "media-123", domain.Viewer{}, tt.format)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, asset)
+				if tt.errorCode != "" {
+					var businessErr *domain.BusinessError
+					if assert.ErrorAs(t, err, &businessErr) {
+						assert.Equal(t, tt.errorCode, businessErr.Code)
+					}
+				}
+				exists, _ := store.Exists(context.Background(), domain.DerivedAudioKey("media-123", tt.format))
+				assert.False(t, exists)
+			} else {
+				require.NoError(t, err)
+				defer asset.Body.Close()
+				body, _ := io.ReadAll(asset.Body)
+				assert.Equal(t, tt.expectedBody, string(body))
+				assert.Equal(t, int64(len(tt.expectedBody)), asset.Size)
+				assert.Equal(t, tt.format.ContentType(), asset.ContentType)
+				assert.False(t, asset.Cached)
+			}
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&transcoder.calls))
+		})
+	}
+}
+
+func TestAudioService_GetAudio_ServesCachedAsset(t *testing.T) {
+	// Given
+	store := storage.NewLocalStorage(t.TempDir())
+	media := readyPodcast()
+	require.NoError(t, store.Put(context.Background(), media.StorageKey(), strings.NewReader("source")))
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	transcoder := &fakeAudioTranscoder{}
+	service := NewAudioService(mockRepo, store, transcoder, nil)
+
+	first, err := service.GetAudio(context.Background(), "media-123", domain.Viewer{}, domain.AudioFormatAAC)
+	require.NoError(t, err)
+	first.Body.Close()
+
+	// When
+	second, err := service.GetAudio(context.Background(), "media-123", domain.Viewer{}, domain.AudioFormatAAC)
+
+	// Then
+	require.NoError(t, err)
+	defer second.Body.Close()
+	body, _ := io.ReadAll(second.Body)
+	assert.Equal(t, "aac:source", string(body))
+	assert.True(t, second.Cached)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&transcoder.calls))
+}
+
+func TestAudioService_GetAudio_HidesPrivateMedia(t *testing.T) {
+	// Given
+	media := readyPodcast()
+	media.Visibility = domain.VisibilityPrivate
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	service := NewAudioService(mockRepo, storage.NewLocalStorage(t.TempDir()), &fakeAudioTranscoder{}, nil)
+
+	// When
+	asset, err := service.GetAudio(context.Background(), "media-123", domain.Viewer{}, domain.AudioFormatAAC)
+
+	// Then
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	assert.Nil(t, asset)
+}
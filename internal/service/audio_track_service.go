@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// AudioTrackService manages the dubbed audio tracks of media
+type AudioTrackService interface {
+	// SetAudioTrack stores the dubbed audio track of a language, replacing the
+	// earlier track of that language
+	SetAudioTrack(ctx context.Context, mediaID string, upload *domain.AudioTrackUpload) (*domain.AudioTrack, error)
+
+	// RemoveAudioTrack deletes the dubbed audio track of a language
+	RemoveAudioTrack(ctx context.Context, mediaID, language string) error
+
+	// GetAudioTrack returns the dubbed audio track of a language for playback
+	GetAudioTrack(ctx context.Context, mediaID, language string, viewer domain.Viewer) (*domain.AudioTrackAsset, error)
+}
+
+// audioTrackService implements AudioTrackService interface
+type audioTrackService struct {
+	mediaRepo repository.MediaRepository
+	storage   storage.Storage
+	publisher EventPublisher
+	now       func() time.Time
+}
+
+// NewAudioTrackService creates a new audio track service. Changes to the tracks
+// are published as media updates so the audio languages of the media are reindexed.
+func NewAudioTrackService(mediaRepo repository.MediaRepository, store storage.Storage, publisher EventPublisher) AudioTrackService {
+	if publisher == nil {
+		publisher = NewLogEventPublisher()
+	}
+
+	return &audioTrackService{
+		mediaRepo: mediaRepo,
+		storage:   store,
+		publisher: publisher,
+		now:       time.Now,
+	}
+}
+
+// SetAudioTrack stores the dubbed audio track of a language
+func (s *audioTrackService) SetAudioTrack(ctx context.Context, mediaID string, upload *domain.AudioTrackUpload) (*domain.AudioTrack, error) {
+	upload.Normalize()
+	if errs := upload.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_AUDIO_TRACK", "Invalid audio track", errs.Error())
+	}
+
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Language == media.AudioLanguage {
+		return nil, domain.NewBusinessError("ORIGINAL_AUDIO_LANGUAGE",
+			fmt.Sprintf("The original audio of the media is already in %s", upload.Language))
+	}
+	previous := media.AudioTrack(upload.Language)
+	if previous == nil && len(media.AudioTracks) >= domain.MaxAudioTracks {
+		return nil, domain.NewBusinessError("TOO_MANY_AUDIO_TRACKS",
+			fmt.Sprintf("Media can have at most %d audio tracks", domain.MaxAudioTracks))
+	}
+
+	body := bufio.NewReader(upload.Body)
+	head, err := body.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read audio track: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, domain.NewBusinessError("EMPTY_UPLOAD", "The uploaded file is empty")
+	}
+	if !isMediaContent(head) {
+		return nil, domain.NewBusinessError("INVALID_FORMAT",
+			fmt.Sprintf("The uploaded file is %s, not audio", http.DetectContentType(head)))
+	}
+
+	track := domain.AudioTrack{
+		Language:  upload.Language,
+		Label:     upload.Label,
+		Format:    upload.Format,
+		Key:       domain.AudioTrackKey(media.ID, upload.Language, upload.Format),
+		CreatedAt: s.now(),
+	}
+	limited := &sizeLimitedReader{r: body, remaining: domain.MaxPodcastFileSize}
+	err = s.storage.Put(ctx, track.Key, limited)
+	if errors.Is(err, errUploadTooLarge) {
+		return nil, domain.NewBusinessError("FILE_TOO_LARGE",
+			fmt.Sprintf("Audio tracks must be at most %d bytes", domain.MaxPodcastFileSize))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store audio track: %w", err)
+	}
+	track.Size = domain.MaxPodcastFileSize - limited.remaining
+
+	tracks := make([]domain.AudioTrack, 0, len(media.AudioTracks)+1)
+	for _, existing := range media.AudioTracks {
+		if existing.Language != track.Language {
+			tracks = append(tracks, existing)
+		}
+	}
+	tracks = append(tracks, track)
+	if err := s.mediaRepo.UpdateAudioTracks(ctx, media.ID, tracks); err != nil {
+		return nil, fmt.Errorf("failed to update audio tracks: %w", err)
+	}
+
+	// A track replaced in another format leaves its old file behind
+	if previous != nil && previous.Key != track.Key {
+		s.deleteObject(ctx, previous.Key)
+	}
+	s.publishMediaUpdated(ctx, media.ID)
+
+	return &track, nil
+}
+
+// RemoveAudioTrack deletes the dubbed audio track of a language
+func (s *audioTrackService) RemoveAudioTrack(ctx context.Context, mediaID, language string) error {
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	track := media.AudioTrack(domain.NormalizeAudioLanguage(language))
+	if track == nil {
+		return domain.ErrAudioTrackNotFound
+	}
+
+	tracks := make([]domain.AudioTrack, 0, len(media.AudioTracks))
+	for _, existing := range media.AudioTracks {
+		if existing.Language != track.Language {
+			tracks = append(tracks, existing)
+		}
+	}
+	if err := s.mediaRepo.UpdateAudioTracks(ctx, media.ID, tracks); err != nil {
+		return fmt.Errorf("failed to update audio tracks: %w", err)
+	}
+
+	s.deleteObject(ctx, track.Key)
+	s.publishMediaUpdated(ctx, media.ID)
+	return nil
+}
+
+// GetAudioTrack returns the dubbed audio track of a language for playback
+func (s *audioTrackService) GetAudioTrack(ctx context.Context, mediaID, language string, viewer domain.Viewer) (*domain.AudioTrackAsset, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := media.CheckPlayback(viewer); err != nil {
+		return nil, err
+	}
+	if !media.IsProcessed() {
+		return nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for playback")
+	}
+
+	track := media.AudioTrack(domain.NormalizeAudioLanguage(language))
+	if track == nil {
+		return nil, domain.ErrAudioTrackNotFound
+	}
+
+	object, err := s.storage.Get(ctx, track.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, domain.ErrAudioTrackNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio track: %w", err)
+	}
+
+	return &domain.AudioTrackAsset{
+		Language:    track.Language,
+		ContentType: contentTypeOf(track.Key),
+		Size:        object.Size,
+		Body:        object.Body,
+	}, nil
+}
+
+// getMedia loads media, reporting deleted media as missing
+func (s *audioTrackService) getMedia(ctx context.Context, mediaID string) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+	return media, nil
+}
+
+// deleteObject removes a track file no media references anymore. Failures are
+// only logged: the storage collector removes what is left behind.
+func (s *audioTrackService) deleteObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Failed to delete audio track %s: %v", key, err)
+	}
+}
+
+// publishMediaUpdated announces a change of the audio languages of media
+func (s *audioTrackService) publishMediaUpdated(ctx context.Context, mediaID string) {
+	event := domain.NewEvent(domain.EventMediaUpdated, map[string]interface{}{
+		"media_id": mediaID,
+	})
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish %s of media %s: %v", domain.EventMediaUpdated, mediaID, err)
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioTrackService_SetGetRemove(t *testing.T) {
+	// Given a ready video with Arabic original audio
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "film", Type: domain.TypeVideo, Status: domain.StatusReady, AudioLanguage: "ar"}))
+	store := storage.NewLocalStorage(t.TempDir())
+	publisher := newMockEventPublisher()
+	service := NewAudioTrackService(mediaRepo, store, publisher)
+
+	// When an English dub is uploaded
+	track, err := service.SetAudioTrack(ctx, "film", &domain.AudioTrackUpload{
+		Language: " EN ", Label: "English", Format: "MP3", Body: strings.NewReader("ID3\x04\x00english dub"), Size: -1,
+	})
+
+	// Then it is stored and the media can be listened to in both languages
+	require.NoError(t, err)
+	assert.Equal(t, domain.AudioTrackKey("film", "en", "mp3"), track.Key)
+	assert.Equal(t, int64(len("ID3\x04\x00english dub")), track.Size)
+	media, err := mediaRepo.GetByID(ctx, "film")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ar", "en"}, media.AudioLanguages())
+	publisher.AssertNumberOfCalls(t, "Publish", 1)
+
+	asset, err := service.GetAudioTrack(ctx, "film", "en", domain.Viewer{})
+	require.NoError(t, err)
+	body, _ := io.ReadAll(asset.Body)
+	asset.Body.Close()
+	assert.Equal(t, "ID3\x04\x00english dub", string(body))
+	assert.Equal(t, "audio/mpeg", asset.ContentType)
+
+	// When it is replaced in another format
+	_, err = service.SetAudioTrack(ctx, "film", &domain.AudioTrackUpload{
+		Language: "en", Format: "ogg", Body: strings.NewReader("OggS\x00english dub"), Size: -1,
+	})
+	require.NoError(t, err)
+
+	// Then the old file is deleted
+	media, err = mediaRepo.GetByID(ctx, "film")
+	require.NoError(t, err)
+	require.Len(t, media.AudioTracks, 1)
+	assert.Equal(t, "ogg", media.AudioTracks[0].Format)
+	exists, _ := store.Exists(ctx, domain.AudioTrackKey("film", "en", "mp3"))
+	assert.False(t, exists)
+
+	// When it is removed
+	require.NoError(t, service.RemoveAudioTrack(ctx, "film", "en"))
+
+	// Then only the original language is left
+	media, err = mediaRepo.GetByID(ctx, "film")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ar"}, media.AudioLanguages())
+	exists, _ = store.Exists(ctx, domain.AudioTrackKey("film", "en", "ogg"))
+	assert.False(t, exists)
+	_, err = service.GetAudioTrack(ctx, "film", "en", domain.Viewer{})
+	assert.ErrorIs(t, err, domain.ErrAudioTrackNotFound)
+	assert.ErrorIs(t, service.RemoveAudioTrack(ctx, "film", "en"), domain.ErrAudioTrackNotFound)
+}
+
+func TestAudioTrackService_SetAudioTrack_Errors(t *testing.T) {
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "film", Type: domain.TypeVideo, Status: domain.StatusReady, AudioLanguage: "ar"}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "deleted", Type: domain.TypeVideo, Status: domain.StatusDeleted}))
+	service := NewAudioTrackService(mediaRepo, storage.NewLocalStorage(t.TempDir()), nil)
+
+	tests := []struct {
+		name      string
+		mediaID   string
+		upload    domain.AudioTrackUpload
+		errorCode string
+	}{
+		{name: "invalid language", mediaID: "film", upload: domain.AudioTrackUpload{Language: "english", Format: "mp3", Body: strings.NewReader("ID3")}, errorCode: "INVALID_AUDIO_TRACK"},
+		{name: "video format", mediaID: "film", upload: domain.AudioTrackUpload{Language: "en", Format: "mp4", Body: strings.NewReader("ID3")}, errorCode: "INVALID_AUDIO_TRACK"},
+		{name: "original language", mediaID: "film", upload: domain.AudioTrackUpload{Language: "ar", Format: "mp3", Body: strings.NewReader("ID3")}, errorCode: "ORIGINAL_AUDIO_LANGUAGE"},
+		{name: "empty file", mediaID: "film", upload: domain.AudioTrackUpload{Language: "en", Format: "mp3", Body: strings.NewReader("")}, errorCode: "EMPTY_UPLOAD"},
+		{name: "not audio", mediaID: "film", upload: domain.AudioTrackUpload{Language: "en", Format: "mp3", Body: strings.NewReader("<html>dub</html>")}, errorCode: "INVALID_FORMAT"},
+		{name: "deleted media", mediaID: "deleted", upload: domain.AudioTrackUpload{Language: "en", Format: "mp3", Body: strings.NewReader("ID3")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SetAudioTrack(ctx, tt.mediaID, &tt.upload)
+
+			if tt.errorCode == "" {
+				assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+				return
+			}
+			var businessErr *domain.BusinessError
+			require.ErrorAs(t, err, &businessErr)
+			assert.Equal(t, tt.errorCode, businessErr.Code)
+		})
+	}
+}
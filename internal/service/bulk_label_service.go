@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// bulkProgressInterval is how many media a running job processes between progress saves
+const bulkProgressInterval = 100
+
+// BulkLabelService labels and categorizes many media at once
+type BulkLabelService interface {
+	// LabelMedia applies a bulk label request. Selections of up to BulkSyncLimit media
+	// are changed before returning the completed job; larger ones continue in the background.
+	LabelMedia(ctx context.Context, req *domain.BulkLabelRequest) (*domain.BulkJob, error)
+
+	// GetJob retrieves the report of a bulk job
+	GetJob(ctx context.Context, id string) (*domain.BulkJob, error)
+}
+
+// bulkLabelService implements BulkLabelService interface
+type bulkLabelService struct {
+	mediaRepo repository.MediaRepository
+	jobRepo   repository.BulkJobRepository
+	now       func() time.Time
+}
+
+// NewBulkLabelService creates a new bulk label service
+func NewBulkLabelService(mediaRepo repository.MediaRepository, jobRepo repository.BulkJobRepository) BulkLabelService {
+	return &bulkLabelService{
+		mediaRepo: mediaRepo,
+		jobRepo:   jobRepo,
+		now:       time.Now,
+	}
+}
+
+// LabelMedia applies a bulk label request
+func (s *bulkLabelService) LabelMedia(ctx context.Context, req *domain.BulkLabelRequest) (*domain.BulkJob, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Bulk label request validation failed", errs.Error())
+	}
+
+	ids, err := s.selectMedia(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.BulkJob{
+		ID:        uuid.New().String(),
+		Operation: domain.BulkOperationLabels,
+		Status:    domain.BulkJobRunning,
+		Total:     len(ids),
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create bulk job: %w", err)
+	}
+
+	if len(ids) <= domain.BulkSyncLimit {
+		s.run(ctx, job, req, ids)
+		return job, nil
+	}
+
+	// The job outlives the request that started it
+	snapshot := *job
+	go s.run(context.WithoutCancel(ctx), job, req, ids)
+	return &snapshot, nil
+}
+
+// GetJob retrieves the report of a bulk job
+func (s *bulkLabelService) GetJob(ctx context.Context, id string) (*domain.BulkJob, error) {
+	return s.jobRepo.GetByID(ctx, id)
+}
+
+// selectMedia resolves the IDs of the selected media. Query selections are resolved
+// up front, so changing the category they select by does not shift the pages.
+func (s *bulkLabelService) selectMedia(ctx context.Context, req *domain.BulkLabelRequest) ([]string, error) {
+	if req.Query == nil {
+		return req.IDs, nil
+	}
+
+	filter := req.Query.Filter()
+	var ids []string
+	for offset := 0; ; offset += domain.MaxPageSize {
+		page, err := s.mediaRepo.GetByFilter(ctx, filter, domain.MaxPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select media: %w", err)
+		}
+		for _, media := range page {
+			ids = append(ids, media.ID)
+		}
+		if len(ids) > domain.MaxBulkSelection {
+			return nil, domain.NewBusinessError("SELECTION_TOO_LARGE",
+				fmt.Sprintf("The query selects more than %d media", domain.MaxBulkSelection))
+		}
+		if len(page) < domain.MaxPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// run changes the selected media one by one, saving the progress of the job as it goes
+func (s *bulkLabelService) run(ctx context.Context, job *domain.BulkJob, req *domain.BulkLabelRequest, ids []string) {
+	for i, id := range ids {
+		if err := s.labelMedia(ctx, req, id); err != nil {
+			job.RecordFailure(id, err)
+		} else {
+			job.RecordSuccess()
+		}
+
+		if (i+1)%bulkProgressInterval == 0 && i+1 < len(ids) {
+			if err := s.jobRepo.Update(ctx, job); err != nil {
+				log.Printf("Failed to save progress of bulk job %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	job.Complete(s.now())
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		log.Printf("Failed to complete bulk job %s: %v", job.ID, err)
+	}
+}
+
+// labelMedia applies the request to one media item
+func (s *bulkLabelService) labelMedia(ctx context.Context, req *domain.BulkLabelRequest, id string) error {
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	changed, err := req.ApplyTo(media)
+	if err != nil || !changed {
+		return err
+	}
+
+	return s.mediaRepo.UpdateClassification(ctx, id, media.Labels, media.Category)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBulkJobRepository keeps copies of bulk jobs in memory
+type memoryBulkJobRepository struct {
+	mu   sync.Mutex
+	jobs map[string]domain.BulkJob
+}
+
+func newMemoryBulkJobRepository() *memoryBulkJobRepository {
+	return &memoryBulkJobRepository{jobs: make(map[string]domain.BulkJob)}
+}
+
+func (r *memoryBulkJobRepository) Create(ctx context.Context, job *domain.BulkJob) error {
+	return r.Update(ctx, job)
+}
+
+func (r *memoryBulkJobRepository) GetByID(ctx context.Context, id string) (*domain.BulkJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, domain.ErrBulkJobNotFound
+	}
+	return &job, nil
+}
+
+func (r *memoryBulkJobRepository) Update(ctx context.Context, job *domain.BulkJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *job
+	stored.Failures = append([]domain.BulkJobFailure(nil), job.Failures...)
+	r.jobs[job.ID] = stored
+	return nil
+}
+
+func TestBulkLabelService_LabelMedia_ByIDs(t *testing.T) {
+	// Given two media, one already labeled
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", Title: "One", Status: domain.StatusReady}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m2", Title: "Two", Status: domain.StatusReady, Labels: []string{"tech"}}))
+	service := NewBulkLabelService(mediaRepo, newMemoryBulkJobRepository())
+
+	// When labeling them and a missing media
+	job, err := service.LabelMedia(ctx, &domain.BulkLabelRequest{IDs: []string{"m1", "m2", "missing"}, AddLabels: []string{"Tech"}})
+
+	// Then the job completes with a report
+	require.NoError(t, err)
+	assert.Equal(t, domain.BulkJobCompleted, job.Status)
+	assert.Equal(t, 3, job.Total)
+	assert.Equal(t, 2, job.Succeeded)
+	assert.Equal(t, 1, job.Failed)
+	require.Len(t, job.Failures, 1)
+	assert.Equal(t, "missing", job.Failures[0].MediaID)
+
+	media, err := mediaRepo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tech"}, media.Labels)
+}
+
+func TestBulkLabelService_LabelMedia_LargeQueryRunsAsync(t *testing.T) {
+	// Given more media of a show than are changed synchronously
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	count := domain.BulkSyncLimit + 50
+	for i := 0; i < count; i++ {
+		require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: fmt.Sprintf("m%d", i), Title: "Episode", ShowID: "show-1", Category: "Tech"}))
+	}
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "other", Title: "Other", ShowID: "show-2", Category: "Tech"}))
+	jobRepo := newMemoryBulkJobRepository()
+	service := NewBulkLabelService(mediaRepo, jobRepo)
+
+	// When recategorizing by the category the query selects
+	category := "Technology"
+	job, err := service.LabelMedia(ctx, &domain.BulkLabelRequest{
+		Query:    &domain.BulkMediaQuery{ShowID: "show-1", Category: "Tech"},
+		Category: &category,
+	})
+
+	// Then a running job is returned and completes in the background
+	require.NoError(t, err)
+	assert.Equal(t, domain.BulkJobRunning, job.Status)
+	assert.Equal(t, count, job.Total)
+	assert.Eventually(t, func() bool {
+		report, err := service.GetJob(ctx, job.ID)
+		return err == nil && report.Status == domain.BulkJobCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	report, err := service.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, count, report.Succeeded)
+	total, err := mediaRepo.GetTotalByFilter(ctx, &domain.MediaFilter{Category: "Technology"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(count), total)
+	other, err := mediaRepo.GetByID(ctx, "other")
+	require.NoError(t, err)
+	assert.Equal(t, "Tech", other.Category)
+}
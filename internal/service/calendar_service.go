@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// CalendarService aggregates the dated events of media for editorial planning
+type CalendarService interface {
+	// GetCalendar lists the license starts, license expiries and scheduled
+	// unpublishes between the inclusive from and to dates, by day
+	GetCalendar(ctx context.Context, from, to string) (*domain.Calendar, error)
+}
+
+// calendarService implements CalendarService interface
+type calendarService struct {
+	mediaRepo repository.MediaRepository
+	now       func() time.Time
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(mediaRepo repository.MediaRepository) CalendarService {
+	return &calendarService{
+		mediaRepo: mediaRepo,
+		now:       time.Now,
+	}
+}
+
+// GetCalendar lists the license starts, license expiries and scheduled
+// unpublishes between the inclusive from and to dates, by day
+func (s *calendarService) GetCalendar(ctx context.Context, from, to string) (*domain.Calendar, error) {
+	start, end, errs := domain.ParseCalendarRange(from, to, s.now())
+	if errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_RANGE", "Calendar range validation failed", errs.Error())
+	}
+
+	mediaList, err := s.mediaRepo.GetScheduledBetween(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scheduled media: %w", err)
+	}
+
+	var entries []domain.CalendarEntry
+	for _, media := range mediaList {
+		entries = append(entries, domain.CalendarEntriesFor(media, start, end)...)
+	}
+
+	return domain.NewCalendar(start, end, entries), nil
+}
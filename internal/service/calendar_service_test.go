@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarService_GetCalendar(t *testing.T) {
+	// Given scheduled media, deleted media and media without dates
+	ctx := context.Background()
+	endsAt := time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)
+	unpublishAt := time.Date(2025, 6, 5, 18, 0, 0, 0, time.UTC)
+
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "expiring", Title: "Expiring", Status: domain.StatusReady, License: domain.License{EndsAt: &endsAt}}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "embargoed", Title: "Embargoed", Status: domain.StatusReady, UnpublishAt: &unpublishAt}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "deleted", Title: "Deleted", Status: domain.StatusDeleted, UnpublishAt: &unpublishAt}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "plain", Title: "Plain", Status: domain.StatusReady}))
+
+	calendarService := NewCalendarService(mediaRepo)
+
+	// When
+	calendar, err := calendarService.GetCalendar(ctx, "2025-06-01", "2025-06-30")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, 2, calendar.Total)
+	require.Len(t, calendar.Days, 2)
+	assert.Equal(t, "2025-06-03", calendar.Days[0].Date)
+	assert.Equal(t, "expiring", calendar.Days[0].Entries[0].MediaID)
+	assert.Equal(t, "2025-06-05", calendar.Days[1].Date)
+	assert.Equal(t, "embargoed", calendar.Days[1].Entries[0].MediaID)
+}
+
+func TestCalendarService_GetCalendar_InvalidRange(t *testing.T) {
+	calendarService := NewCalendarService(repository.NewInMemoryMediaRepository())
+
+	_, err := calendarService.GetCalendar(context.Background(), "2025-06-30", "2025-06-01")
+
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_RANGE", businessErr.Code)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// SilenceDetector finds the silences in the audio of media
+type SilenceDetector interface {
+	DetectSilences(ctx context.Context, src io.Reader, noiseDB, minSeconds float64) ([]domain.SilenceInterval, error)
+}
+
+// ChapterSuggester suggests the chapter boundaries of uploaded media
+type ChapterSuggester interface {
+	// SuggestChapters detects the long silences and music stingers in the audio of
+	// media and stores the chapter boundaries they suggest, replacing earlier suggestions
+	SuggestChapters(ctx context.Context, media *domain.Media) error
+}
+
+// ChapterService manages the chapters of media
+type ChapterService interface {
+	ChapterSuggester
+
+	// GetChapters returns the chapters of a media item and the boundaries suggested for it
+	GetChapters(ctx context.Context, mediaID string) (*domain.MediaChapters, error)
+
+	// SetChapters replaces the chapters of a media item
+	SetChapters(ctx context.Context, mediaID string, req *domain.ChaptersRequest) (*domain.MediaChapters, error)
+
+	// AcceptSuggestions turns suggested boundaries of a media item into chapters
+	AcceptSuggestions(ctx context.Context, mediaID string, req *domain.AcceptChaptersRequest) (*domain.MediaChapters, error)
+}
+
+// ChapterOptions configures chapter suggestions
+type ChapterOptions struct {
+	NoiseDB           float64 // audio quieter than this is silent, -35 when 0
+	MinSilenceSeconds float64 // shortest silence suggesting a boundary, 2 when 0
+	MinChapterSeconds int     // shortest chapter suggested, domain.DefaultMinChapterSeconds when 0
+}
+
+// chapterService implements ChapterService interface
+type chapterService struct {
+	mediaRepo   repository.MediaRepository
+	storage     storage.Storage
+	detector    SilenceDetector
+	taskLimiter *TaskLimiter
+	options     ChapterOptions
+}
+
+// NewChapterService creates a new chapter service. When taskLimiter is nil,
+// silence detection is not limited.
+func NewChapterService(mediaRepo repository.MediaRepository, store storage.Storage, detector SilenceDetector, taskLimiter *TaskLimiter, options ChapterOptions) ChapterService {
+	if options.NoiseDB == 0 {
+		options.NoiseDB = -35
+	}
+	if options.MinSilenceSeconds <= 0 {
+		options.MinSilenceSeconds = 2
+	}
+	if options.MinChapterSeconds <= 0 {
+		options.MinChapterSeconds = domain.DefaultMinChapterSeconds
+	}
+
+	return &chapterService{
+		mediaRepo:   mediaRepo,
+		storage:     store,
+		detector:    detector,
+		taskLimiter: taskLimiter,
+		options:     options,
+	}
+}
+
+// SuggestChapters detects the chapter boundaries of media and stores them as suggestions
+func (s *chapterService) SuggestChapters(ctx context.Context, media *domain.Media) error {
+	release, err := s.taskLimiter.Acquire(ctx, TaskAnalysis)
+	if err != nil {
+		return fmt.Errorf("failed to acquire analysis slot: %w", err)
+	}
+	silences, err := s.detectSilences(ctx, media)
+	release()
+	if err != nil {
+		return fmt.Errorf("failed to detect silences: %w", err)
+	}
+
+	suggestions := domain.SuggestChapters(silences, media.Duration, s.options.MinChapterSeconds)
+	if err := s.mediaRepo.UpdateChapters(ctx, media.ID, media.Chapters, suggestions); err != nil {
+		return fmt.Errorf("failed to save chapter suggestions: %w", err)
+	}
+	media.ChapterSuggestions = suggestions
+
+	return nil
+}
+
+// GetChapters returns the chapters of a media item and the boundaries suggested for it
+func (s *chapterService) GetChapters(ctx context.Context, mediaID string) (*domain.MediaChapters, error) {
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewMediaChapters(media), nil
+}
+
+// SetChapters replaces the chapters of a media item
+func (s *chapterService) SetChapters(ctx context.Context, mediaID string, req *domain.ChaptersRequest) (*domain.MediaChapters, error) {
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Normalize()
+	if errs := req.Validate(media.Duration); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_CHAPTERS", "Chapter validation failed", errs.Error())
+	}
+
+	if err := s.mediaRepo.UpdateChapters(ctx, media.ID, req.Chapters, media.ChapterSuggestions); err != nil {
+		return nil, fmt.Errorf("failed to update chapters: %w", err)
+	}
+	media.Chapters = req.Chapters
+
+	return domain.NewMediaChapters(media), nil
+}
+
+// AcceptSuggestions turns suggested boundaries of a media item into chapters
+func (s *chapterService) AcceptSuggestions(ctx context.Context, mediaID string, req *domain.AcceptChaptersRequest) (*domain.MediaChapters, error) {
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := req.Apply(media); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_CHAPTERS", "Chapter suggestions could not be accepted", errs.Error())
+	}
+
+	if err := s.mediaRepo.UpdateChapters(ctx, media.ID, media.Chapters, media.ChapterSuggestions); err != nil {
+		return nil, fmt.Errorf("failed to update chapters: %w", err)
+	}
+
+	return domain.NewMediaChapters(media), nil
+}
+
+// detectSilences runs silence detection over the uploaded file of media
+func (s *chapterService) detectSilences(ctx context.Context, media *domain.Media) ([]domain.SilenceInterval, error) {
+	object, err := s.storage.Get(ctx, media.StorageKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer object.Body.Close()
+
+	return s.detector.DetectSilences(ctx, object.Body, s.options.NoiseDB, s.options.MinSilenceSeconds)
+}
+
+// getMedia retrieves media that is not deleted
+func (s *chapterService) getMedia(ctx context.Context, mediaID string) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+	return media, nil
+}
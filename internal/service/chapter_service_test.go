@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSilenceDetector returns fixed silences for every file
+type fakeSilenceDetector struct {
+	silences []domain.SilenceInterval
+	err      error
+}
+
+func (f *fakeSilenceDetector) DetectSilences(ctx context.Context, src io.Reader, noiseDB, minSeconds float64) ([]domain.SilenceInterval, error) {
+	if _, err := io.Copy(io.Discard, src); err != nil {
+		return nil, err
+	}
+	return f.silences, f.err
+}
+
+func TestChapterService_SuggestAndAccept(t *testing.T) {
+	// Given an uploaded episode with silences after its intro and around a jingle
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	store := storage.NewLocalStorage(t.TempDir())
+	media := readyPodcast()
+	media.Duration = 1800
+	require.NoError(t, mediaRepo.Create(ctx, media))
+	require.NoError(t, store.Put(ctx, media.StorageKey(), strings.NewReader("audio")))
+	detector := &fakeSilenceDetector{silences: []domain.SilenceInterval{
+		{Start: 88, End: 91},
+		{Start: 900, End: 902},
+		{Start: 910, End: 913},
+	}}
+	chapterService := NewChapterService(mediaRepo, store, detector, nil, ChapterOptions{})
+
+	// When
+	require.NoError(t, chapterService.SuggestChapters(ctx, media))
+
+	// Then the boundaries are suggested
+	chapters, err := chapterService.GetChapters(ctx, media.ID)
+	require.NoError(t, err)
+	assert.Empty(t, chapters.Chapters)
+	assert.Equal(t, []domain.ChapterSuggestion{
+		{StartTime: 91, Kind: domain.ChapterBoundarySilence},
+		{StartTime: 913, Kind: domain.ChapterBoundaryStinger},
+	}, chapters.Suggestions)
+
+	// When an editor accepts one of them
+	chapters, err = chapterService.AcceptSuggestions(ctx, media.ID, &domain.AcceptChaptersRequest{
+		Chapters: []domain.Chapter{{Title: "Interview", StartTime: 913}},
+	})
+
+	// Then it becomes a chapter and the other stays suggested
+	require.NoError(t, err)
+	stored, err := mediaRepo.GetByID(ctx, media.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Chapter{{Title: "Interview", StartTime: 913}}, stored.Chapters)
+	assert.Equal(t, []domain.ChapterSuggestion{{StartTime: 91, Kind: domain.ChapterBoundarySilence}}, stored.ChapterSuggestions)
+	assert.Equal(t, stored.Chapters, chapters.Chapters)
+}
+
+func TestChapterService_SetChapters(t *testing.T) {
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Type: domain.TypePodcast, Status: domain.StatusReady, Duration: 1800}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "deleted", Type: domain.TypePodcast, Status: domain.StatusDeleted}))
+	chapterService := NewChapterService(mediaRepo, nil, nil, nil, ChapterOptions{})
+
+	t.Run("stores the chapters by start time", func(t *testing.T) {
+		chapters, err := chapterService.SetChapters(ctx, "episode", &domain.ChaptersRequest{Chapters: []domain.Chapter{
+			{Title: "Interview", StartTime: 600},
+			{Title: " Intro ", StartTime: 0},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Chapter{{Title: "Intro", StartTime: 0}, {Title: "Interview", StartTime: 600}}, chapters.Chapters)
+	})
+
+	t.Run("rejects invalid chapters", func(t *testing.T) {
+		_, err := chapterService.SetChapters(ctx, "episode", &domain.ChaptersRequest{Chapters: []domain.Chapter{{Title: "Outro", StartTime: 2000}}})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "INVALID_CHAPTERS", businessErr.Code)
+	})
+
+	t.Run("deleted media is not found", func(t *testing.T) {
+		_, err := chapterService.SetChapters(ctx, "deleted", &domain.ChaptersRequest{})
+		assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	})
+}
+
+func TestChapterService_SuggestChapters_DetectionFails(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	store := storage.NewLocalStorage(t.TempDir())
+	media := readyPodcast()
+	require.NoError(t, mediaRepo.Create(ctx, media))
+	require.NoError(t, store.Put(ctx, media.StorageKey(), strings.NewReader("audio")))
+	chapterService := NewChapterService(mediaRepo, store, &fakeSilenceDetector{err: errors.New("no audio stream")}, nil, ChapterOptions{})
+
+	// When
+	err := chapterService.SuggestChapters(ctx, media)
+
+	// Then
+	assert.Error(t, err)
+	assert.Empty(t, media.ChapterSuggestions)
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/notification"
+)
+
+// defaultDigestSubjectTemplate renders the subject of a weekly digest
+const defaultDigestSubjectTemplate = `Weekly digest for {{.TenantID}}: {{.Uploads}} uploads, {{.Failures}} failures`
+
+// defaultDigestBodyTemplate renders the body of a weekly digest
+const defaultDigestBodyTemplate = `Catalog activity of {{.TenantID}} from {{date .From}} to {{date .LastDay}}
+
+Uploads: {{.Uploads}}
+Processing failures: {{.Failures}}
+{{- range .Failed}}
+  - {{.Title}} ({{.MediaID}}): {{.FailureMessage}}
+{{- end}}
+
+Top searches:
+{{- range $i, $search := .TopSearches}}
+  {{inc $i}}. {{$search.Term}} ({{$search.Count}} searches)
+{{- else}}
+  No searches
+{{- end}}
+
+Most played:
+{{- range $i, $media := .TopPlayed}}
+  {{inc $i}}. {{$media.Title}} ({{$media.Plays}} plays)
+{{- else}}
+  Nothing was played
+{{- end}}
+`
+
+// digestTemplateFuncs are available to digest templates
+var digestTemplateFuncs = template.FuncMap{
+	"date": func(t time.Time) string { return t.Format(domain.CalendarDateLayout) },
+	"inc":  func(i int) int { return i + 1 },
+}
+
+// DigestTemplates render weekly digests into notification messages
+type DigestTemplates struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewDigestTemplates parses the digest templates. The body template is read from
+// bodyPath, the built-in one is used when it is empty.
+func NewDigestTemplates(bodyPath string) (*DigestTemplates, error) {
+	bodyText := defaultDigestBodyTemplate
+	if bodyPath != "" {
+		content, err := os.ReadFile(bodyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read digest template: %w", err)
+		}
+		bodyText = string(content)
+	}
+
+	subject, err := template.New("subject").Funcs(digestTemplateFuncs).Parse(defaultDigestSubjectTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse digest subject template: %w", err)
+	}
+	body, err := template.New("body").Funcs(digestTemplateFuncs).Parse(bodyText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse digest template: %w", err)
+	}
+
+	return &DigestTemplates{subject: subject, body: body}, nil
+}
+
+// Render renders a digest into a notification message
+func (t *DigestTemplates) Render(digest *domain.WeeklyDigest) (*notification.Message, error) {
+	var subject, body bytes.Buffer
+	if err := t.subject.Execute(&subject, digest); err != nil {
+		return nil, fmt.Errorf("failed to render digest subject: %w", err)
+	}
+	if err := t.body.Execute(&body, digest); err != nil {
+		return nil, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	return &notification.Message{
+		Event:   domain.NotificationWeeklyDigest,
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    body.String(),
+		Data: map[string]interface{}{
+			"tenant_id": digest.TenantID,
+			"digest":    digest,
+		},
+	}, nil
+}
+
+// DigestNotifier delivers digests through the notification subsystem
+type DigestNotifier interface {
+	Notify(ctx context.Context, subjects []domain.NotificationSubject, msg *notification.Message) error
+}
+
+// DigestService summarizes the catalog activity of tenants into weekly digests
+type DigestService interface {
+	// BuildDigest summarizes the activity of a tenant over the digest period
+	// of whole days ending before the day of end
+	BuildDigest(ctx context.Context, tenantID string, end time.Time) (*domain.WeeklyDigest, error)
+
+	// SendDigests sends the digest of the period ending before the day of end to
+	// every tenant with activity that was not sent it yet, returning how many were sent
+	SendDigests(ctx context.Context, end time.Time) (int, error)
+}
+
+// digestService implements DigestService interface
+type digestService struct {
+	digestRepo      repository.DigestRepository
+	searchQueryRepo repository.SearchQueryRepository
+	notifier        DigestNotifier
+	templates       *DigestTemplates
+	topItems        int
+	now             func() time.Time
+}
+
+// NewDigestService creates a new digest service listing topItems failures,
+// searches and played items, the default when 0
+func NewDigestService(digestRepo repository.DigestRepository, searchQueryRepo repository.SearchQueryRepository, notifier DigestNotifier, templates *DigestTemplates, topItems int) DigestService {
+	if topItems <= 0 {
+		topItems = domain.DefaultDigestTopItems
+	}
+
+	return &digestService{
+		digestRepo:      digestRepo,
+		searchQueryRepo: searchQueryRepo,
+		notifier:        notifier,
+		templates:       templates,
+		topItems:        topItems,
+		now:             time.Now,
+	}
+}
+
+// BuildDigest summarizes the activity of a tenant over the digest period
+func (s *digestService) BuildDigest(ctx context.Context, tenantID string, end time.Time) (*domain.WeeklyDigest, error) {
+	to := end.UTC().Truncate(24 * time.Hour)
+	digest := &domain.WeeklyDigest{
+		TenantID: tenantID,
+		From:     to.Add(-domain.DigestPeriod),
+		To:       to,
+	}
+
+	var err error
+	if digest.Uploads, err = s.digestRepo.CountUploads(ctx, tenantID, digest.From, digest.To); err != nil {
+		return nil, fmt.Errorf("failed to count uploads: %w", err)
+	}
+	if digest.Failures, digest.Failed, err = s.digestRepo.Failures(ctx, tenantID, digest.From, digest.To, s.topItems); err != nil {
+		return nil, fmt.Errorf("failed to list failures: %w", err)
+	}
+	digest.TopSearches, err = s.searchQueryRepo.Top(ctx, tenantID,
+		digest.From.Format(domain.CalendarDateLayout), digest.To.Format(domain.CalendarDateLayout), s.topItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top searches: %w", err)
+	}
+	if digest.TopPlayed, err = s.digestRepo.TopPlayed(ctx, tenantID, digest.From, digest.To, s.topItems); err != nil {
+		return nil, fmt.Errorf("failed to list top played media: %w", err)
+	}
+
+	return digest, nil
+}
+
+// SendDigests sends the digest of the period ending before the day of end to every
+// tenant with activity that was not sent it yet
+func (s *digestService) SendDigests(ctx context.Context, end time.Time) (int, error) {
+	tenants, err := s.digestRepo.Tenants(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	sent := 0
+	for _, tenantID := range tenants {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		// A failing tenant must not prevent the others from getting their digest
+		ok, err := s.sendDigest(ctx, tenantID, end)
+		if err != nil {
+			log.Printf("Failed to send the weekly digest of tenant %s: %v", tenantID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// sendDigest builds, renders and sends the digest of a tenant, unless nothing
+// happened or it was already sent. The period is recorded once handled, so
+// retries and restarts do not send it twice.
+func (s *digestService) sendDigest(ctx context.Context, tenantID string, end time.Time) (bool, error) {
+	periodEnd := end.UTC().Truncate(24 * time.Hour)
+	lastSent, err := s.digestRepo.LastSent(ctx, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the last digest sent: %w", err)
+	}
+	if !lastSent.Before(periodEnd) {
+		return false, nil
+	}
+
+	digest, err := s.BuildDigest(ctx, tenantID, end)
+	if err != nil {
+		return false, err
+	}
+
+	sent := false
+	if !digest.IsEmpty() {
+		msg, err := s.templates.Render(digest)
+		if err != nil {
+			return false, err
+		}
+		if err := s.notifier.Notify(ctx, []domain.NotificationSubject{{Type: domain.SubjectTenant, ID: tenantID}}, msg); err != nil {
+			return false, err
+		}
+		sent = true
+	}
+
+	if err := s.digestRepo.MarkSent(ctx, tenantID, periodEnd, s.now()); err != nil {
+		return sent, fmt.Errorf("failed to record the digest sent: %w", err)
+	}
+	return sent, nil
+}
+
+// DigestScheduler sends the weekly digests
+type DigestScheduler interface {
+	// Run sends the digests missed since the last send time, then every week
+	// until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// digestScheduler implements DigestScheduler interface
+type digestScheduler struct {
+	digestService DigestService
+	weekday       time.Weekday
+	hour          int
+	now           func() time.Time
+}
+
+// NewDigestScheduler creates a job sending the weekly digests on weekday at hour, UTC
+func NewDigestScheduler(digestService DigestService, weekday time.Weekday, hour int) DigestScheduler {
+	if hour < 0 || hour > 23 {
+		hour = 8
+	}
+
+	return &digestScheduler{
+		digestService: digestService,
+		weekday:       weekday,
+		hour:          hour,
+		now:           time.Now,
+	}
+}
+
+// Run sends the digests missed since the last send time, then every week
+// until ctx is cancelled. Catching up covers a restart or a leadership change
+// across the send time; tenants already sent the digest are skipped.
+func (s *digestScheduler) Run(ctx context.Context) {
+	s.send(ctx, domain.LastDigestAt(s.now(), s.weekday, s.hour))
+
+	for {
+		next := domain.NextDigestAt(s.now(), s.weekday, s.hour)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next.Sub(s.now())):
+		}
+
+		s.send(ctx, next)
+	}
+}
+
+// send sends the digests due at
+func (s *digestScheduler) send(ctx context.Context, at time.Time) {
+	sent, err := s.digestService.SendDigests(ctx, at)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Weekly digest failed: %v", err)
+	}
+	if sent > 0 {
+		log.Printf("Sent %d weekly digests", sent)
+	}
+}
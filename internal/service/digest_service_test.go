@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDigestRepository serves fixed activity per tenant
+type stubDigestRepository struct {
+	tenants    []string
+	uploads    map[string]int64
+	failed     map[string][]domain.DigestMedia
+	played     map[string][]domain.DigestMedia
+	failTenant string // tenant whose activity cannot be loaded
+	from, end  time.Time
+	lastSent   map[string]time.Time
+}
+
+func (r *stubDigestRepository) Tenants(ctx context.Context) ([]string, error) {
+	return r.tenants, nil
+}
+
+func (r *stubDigestRepository) CountUploads(ctx context.Context, tenantID string, from, end time.Time) (int64, error) {
+	if tenantID == r.failTenant {
+		return 0, errors.New("db down")
+	}
+	r.from, r.end = from, end
+	return r.uploads[tenantID], nil
+}
+
+func (r *stubDigestRepository) Failures(ctx context.Context, tenantID string, from, end time.Time, limit int) (int64, []domain.DigestMedia, error) {
+	return int64(len(r.failed[tenantID])), r.failed[tenantID], nil
+}
+
+func (r *stubDigestRepository) TopPlayed(ctx context.Context, tenantID string, from, end time.Time, limit int) ([]domain.DigestMedia, error) {
+	return r.played[tenantID], nil
+}
+
+func (r *stubDigestRepository) LastSent(ctx context.Context, tenantID string) (time.Time, error) {
+	return r.lastSent[tenantID], nil
+}
+
+func (r *stubDigestRepository) MarkSent(ctx context.Context, tenantID string, periodEnd, sentAt time.Time) error {
+	if r.lastSent == nil {
+		r.lastSent = make(map[string]time.Time)
+	}
+	r.lastSent[tenantID] = periodEnd
+	return nil
+}
+
+// stubSearchQueryRepository serves fixed top searches per tenant
+type stubSearchQueryRepository struct {
+	top             map[string][]domain.DigestSearch
+	fromDay, endDay string
+}
+
+func (r *stubSearchQueryRepository) Increment(ctx context.Context, day, tenantID, term string) error {
+	return nil
+}
+
+func (r *stubSearchQueryRepository) Top(ctx context.Context, tenantID, fromDay, endDay string, limit int) ([]domain.DigestSearch, error) {
+	r.fromDay, r.endDay = fromDay, endDay
+	return r.top[tenantID], nil
+}
+
+// recordingDigestNotifier records the messages it is asked to send
+type recordingDigestNotifier struct {
+	subjects []domain.NotificationSubject
+	messages []*notification.Message
+}
+
+func (n *recordingDigestNotifier) Notify(ctx context.Context, subjects []domain.NotificationSubject, msg *notification.Message) error {
+	n.subjects = append(n.subjects, subjects...)
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func TestDigestService_SendDigests(t *testing.T) {
+	// Given an active tenant, an idle one and one whose activity cannot be loaded
+	digestRepo := &stubDigestRepository{
+		tenants: []string{"acme", "idle", "broken"},
+		uploads: map[string]int64{"acme": 12},
+		failed: map[string][]domain.DigestMedia{
+			"acme": {{MediaID: "m3", Title: "Broken episode", FailureMessage: "corrupt file"}},
+		},
+		played: map[string][]domain.DigestMedia{
+			"acme": {{MediaID: "m1", Title: "Pilot", Plays: 40}},
+		},
+		failTenant: "broken",
+	}
+	searchQueryRepo := &stubSearchQueryRepository{top: map[string][]domain.DigestSearch{
+		"acme": {{Term: "jazz", Count: 9}},
+	}}
+	notifier := &recordingDigestNotifier{}
+	templates, err := NewDigestTemplates("")
+	require.NoError(t, err)
+	service := NewDigestService(digestRepo, searchQueryRepo, notifier, templates, 0)
+
+	// When the digests are sent on Monday morning
+	sent, err := service.SendDigests(context.Background(), time.Date(2025, 6, 9, 8, 0, 0, 0, time.UTC))
+
+	// Then only the active tenant gets a digest of the previous week
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), digestRepo.from)
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), digestRepo.end)
+	assert.Equal(t, "2025-06-02", searchQueryRepo.fromDay)
+	assert.Equal(t, "2025-06-09", searchQueryRepo.endDay)
+
+	require.Len(t, notifier.messages, 1)
+	assert.Equal(t, []domain.NotificationSubject{{Type: domain.SubjectTenant, ID: "acme"}}, notifier.subjects)
+	msg := notifier.messages[0]
+	assert.Equal(t, domain.NotificationWeeklyDigest, msg.Event)
+	assert.Equal(t, "Weekly digest for acme: 12 uploads, 1 failures", msg.Subject)
+	assert.Contains(t, msg.Body, "from 2025-06-02 to 2025-06-08")
+	assert.Contains(t, msg.Body, "Broken episode (m3): corrupt file")
+	assert.Contains(t, msg.Body, "1. jazz (9 searches)")
+	assert.Contains(t, msg.Body, "1. Pilot (40 plays)")
+	assert.Equal(t, "acme", msg.Data["tenant_id"])
+
+	// The period is recorded for the tenants handled, not the broken one
+	assert.Equal(t, map[string]time.Time{
+		"acme": time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+		"idle": time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+	}, digestRepo.lastSent)
+}
+
+func TestDigestService_SendDigests_OncePerPeriod(t *testing.T) {
+	// Given a tenant with activity that got the digest of the previous period
+	digestRepo := &stubDigestRepository{
+		tenants:  []string{"acme", "globex"},
+		uploads:  map[string]int64{"acme": 3, "globex": 4},
+		lastSent: map[string]time.Time{"acme": time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	notifier := &recordingDigestNotifier{}
+	templates, err := NewDigestTemplates("")
+	require.NoError(t, err)
+	service := NewDigestService(digestRepo, &stubSearchQueryRepository{}, notifier, templates, 0)
+	end := time.Date(2025, 6, 9, 8, 0, 0, 0, time.UTC)
+
+	// When the digests are sent, then sent again later the same day
+	sent, err := service.SendDigests(context.Background(), end)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	sent, err = service.SendDigests(context.Background(), end.Add(3*time.Hour))
+
+	// Then nobody gets the digest twice
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, notifier.messages, 2)
+}
+
+// recordingDigestService records the times digests are sent for
+type recordingDigestService struct {
+	DigestService
+	sends  chan time.Time
+	cancel context.CancelFunc
+}
+
+func (s *recordingDigestService) SendDigests(ctx context.Context, end time.Time) (int, error) {
+	s.sends <- end
+	s.cancel()
+	return 0, nil
+}
+
+func TestDigestScheduler_CatchesUpOnStart(t *testing.T) {
+	// Given a scheduler started on Tuesday, after the Monday send time
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	digestService := &recordingDigestService{sends: make(chan time.Time, 1), cancel: cancel}
+	scheduler := NewDigestScheduler(digestService, time.Monday, 8).(*digestScheduler)
+	scheduler.now = func() time.Time { return time.Date(2025, 6, 10, 14, 0, 0, 0, time.UTC) }
+
+	// When it runs
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+
+	// Then the Monday digests are sent right away
+	select {
+	case end := <-digestService.sends:
+		assert.Equal(t, time.Date(2025, 6, 9, 8, 0, 0, 0, time.UTC), end)
+	case <-time.After(time.Second):
+		t.Fatal("digests were not sent on start")
+	}
+	<-done
+}
+
+func TestNewDigestTemplates_FromFile(t *testing.T) {
+	// Given a custom body template
+	path := filepath.Join(t.TempDir(), "digest.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{.TenantID}} uploaded {{.Uploads}}"), 0o600))
+
+	// When
+	templates, err := NewDigestTemplates(path)
+	require.NoError(t, err)
+	msg, err := templates.Render(&domain.WeeklyDigest{TenantID: "acme", Uploads: 3})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "acme uploaded 3", msg.Body)
+
+	_, err = NewDigestTemplates(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.Error(t, err)
+}
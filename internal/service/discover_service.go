@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DiscoverService provides the recently added and editor's pick listings and
+// manages the editor's picks
+type DiscoverService interface {
+	// GetNew lists published media, newest first
+	GetNew(ctx context.Context, limit, offset int, safe bool) ([]*domain.Media, error)
+
+	// GetFeatured lists the published media of the active editor's picks in position order
+	GetFeatured(ctx context.Context, safe bool) ([]*domain.Media, error)
+
+	// ListFeaturedItems lists every editor's pick, including scheduled and ended ones
+	ListFeaturedItems(ctx context.Context) ([]*domain.FeaturedItem, error)
+
+	// CreateFeaturedItem adds an editor's pick
+	CreateFeaturedItem(ctx context.Context, req *domain.FeaturedItemRequest) (*domain.FeaturedItem, error)
+
+	// UpdateFeaturedItem replaces the media, position and schedule of an editor's pick
+	UpdateFeaturedItem(ctx context.Context, id string, req *domain.FeaturedItemRequest) (*domain.FeaturedItem, error)
+
+	// DeleteFeaturedItem removes an editor's pick
+	DeleteFeaturedItem(ctx context.Context, id string) error
+}
+
+// discoverService implements DiscoverService interface
+type discoverService struct {
+	mediaRepo    repository.MediaRepository
+	featuredRepo repository.FeaturedItemRepository
+	catalog      MediaCatalog
+	now          func() time.Time
+}
+
+// NewDiscoverService creates a new discover service. Editor's picks are
+// looked up in catalog so they show the current metadata.
+func NewDiscoverService(mediaRepo repository.MediaRepository, featuredRepo repository.FeaturedItemRepository, catalog MediaCatalog) DiscoverService {
+	return &discoverService{
+		mediaRepo:    mediaRepo,
+		featuredRepo: featuredRepo,
+		catalog:      catalog,
+		now:          time.Now,
+	}
+}
+
+// GetNew lists published media, newest first
+func (s *discoverService) GetNew(ctx context.Context, limit, offset int, safe bool) ([]*domain.Media, error) {
+	filter := &domain.MediaFilter{
+		Status:     domain.StatusReady,
+		Visibility: domain.VisibilityPublic,
+		Safe:       safe,
+	}
+
+	mediaList, err := s.mediaRepo.GetByFilter(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new media: %w", err)
+	}
+
+	return mediaList, nil
+}
+
+// GetFeatured lists the published media of the active editor's picks in position order.
+// Picks of media that was unpublished or deleted since are skipped.
+func (s *discoverService) GetFeatured(ctx context.Context, safe bool) ([]*domain.Media, error) {
+	items, err := s.featuredRepo.GetActive(ctx, s.now(), domain.MaxFeaturedItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured items: %w", err)
+	}
+	if len(items) == 0 {
+		return []*domain.Media{}, nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.MediaID)
+	}
+	mediaList, err := s.catalog.GetMediaByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up featured media: %w", err)
+	}
+	mediaByID := make(map[string]*domain.Media, len(mediaList))
+	for _, media := range mediaList {
+		mediaByID[media.ID] = media
+	}
+
+	featured := make([]*domain.Media, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		media, ok := mediaByID[item.MediaID]
+		if !ok || seen[media.ID] || !media.CanBeSearched() {
+			continue
+		}
+		if safe && media.ContentRating.IsExplicit() {
+			continue
+		}
+		seen[media.ID] = true
+		featured = append(featured, media)
+	}
+
+	return featured, nil
+}
+
+// ListFeaturedItems lists every editor's pick, including scheduled and ended ones
+func (s *discoverService) ListFeaturedItems(ctx context.Context) ([]*domain.FeaturedItem, error) {
+	return s.featuredRepo.GetAll(ctx)
+}
+
+// CreateFeaturedItem adds an editor's pick
+func (s *discoverService) CreateFeaturedItem(ctx context.Context, req *domain.FeaturedItemRequest) (*domain.FeaturedItem, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	item := &domain.FeaturedItem{ID: uuid.New().String()}
+	req.ApplyTo(item)
+
+	if err := s.featuredRepo.Create(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to create featured item: %w", err)
+	}
+
+	return item, nil
+}
+
+// UpdateFeaturedItem replaces the media, position and schedule of an editor's pick
+func (s *discoverService) UpdateFeaturedItem(ctx context.Context, id string, req *domain.FeaturedItemRequest) (*domain.FeaturedItem, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	item, err := s.featuredRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(item)
+	if err := s.featuredRepo.Update(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update featured item: %w", err)
+	}
+
+	return item, nil
+}
+
+// DeleteFeaturedItem removes an editor's pick
+func (s *discoverService) DeleteFeaturedItem(ctx context.Context, id string) error {
+	return s.featuredRepo.Delete(ctx, id)
+}
+
+// validate checks a featured item request and that its media exists
+func (s *discoverService) validate(ctx context.Context, req *domain.FeaturedItemRequest) error {
+	if errs := req.Validate(); errs.HasErrors() {
+		return domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Featured item validation failed", errs.Error())
+	}
+
+	media, err := s.mediaRepo.GetByID(ctx, req.MediaID)
+	if err != nil && !errors.Is(err, domain.ErrMediaNotFound) {
+		return fmt.Errorf("failed to look up media: %w", err)
+	}
+	if err != nil || media.Status == domain.StatusDeleted {
+		return domain.NewBusinessError("MEDIA_NOT_FOUND", "The media to feature does not exist")
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryFeaturedItemRepository keeps featured items in memory
+type memoryFeaturedItemRepository struct {
+	items map[string]domain.FeaturedItem
+}
+
+func newMemoryFeaturedItemRepository(items ...domain.FeaturedItem) *memoryFeaturedItemRepository {
+	repo := &memoryFeaturedItemRepository{items: make(map[string]domain.FeaturedItem)}
+	for _, item := range items {
+		repo.items[item.ID] = item
+	}
+	return repo
+}
+
+func (r *memoryFeaturedItemRepository) Create(ctx context.Context, item *domain.FeaturedItem) error {
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *memoryFeaturedItemRepository) GetByID(ctx context.Context, id string) (*domain.FeaturedItem, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return nil, domain.ErrFeaturedItemNotFound
+	}
+	return &item, nil
+}
+
+func (r *memoryFeaturedItemRepository) GetAll(ctx context.Context) ([]*domain.FeaturedItem, error) {
+	return r.find(func(*domain.FeaturedItem) bool { return true }), nil
+}
+
+func (r *memoryFeaturedItemRepository) GetActive(ctx context.Context, now time.Time, limit int) ([]*domain.FeaturedItem, error) {
+	return r.find(func(item *domain.FeaturedItem) bool { return item.IsActive(now) }), nil
+}
+
+func (r *memoryFeaturedItemRepository) Update(ctx context.Context, item *domain.FeaturedItem) error {
+	if _, ok := r.items[item.ID]; !ok {
+		return domain.ErrFeaturedItemNotFound
+	}
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *memoryFeaturedItemRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := r.items[id]; !ok {
+		return domain.ErrFeaturedItemNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func (r *memoryFeaturedItemRepository) find(match func(*domain.FeaturedItem) bool) []*domain.FeaturedItem {
+	var result []*domain.FeaturedItem
+	for _, item := range r.items {
+		if match(&item) {
+			result = append(result, &item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Position < result[j].Position })
+	return result
+}
+
+func newTestDiscoverService(t *testing.T, featuredRepo repository.FeaturedItemRepository) (DiscoverService, repository.MediaRepository) {
+	t.Helper()
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, media := range []*domain.Media{
+		{ID: "old", Title: "Old", Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+		{ID: "explicit", Title: "Explicit", Status: domain.StatusReady, Visibility: domain.VisibilityPublic, ContentRating: domain.ContentRating{Explicit: true}},
+		{ID: "private", Title: "Private", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+		{ID: "processing", Title: "Processing", Status: domain.StatusProcessing, Visibility: domain.VisibilityPublic},
+		{ID: "new", Title: "New", Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+	} {
+		media.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, mediaRepo.Create(ctx, media))
+	}
+	return NewDiscoverService(mediaRepo, featuredRepo, NewRepositoryMediaCatalog(mediaRepo)), mediaRepo
+}
+
+func mediaIDs(mediaList []*domain.Media) []string {
+	ids := make([]string, 0, len(mediaList))
+	for _, media := range mediaList {
+		ids = append(ids, media.ID)
+	}
+	return ids
+}
+
+func TestDiscoverService_GetNew(t *testing.T) {
+	// Given
+	discoverService, _ := newTestDiscoverService(t, newMemoryFeaturedItemRepository())
+
+	// When
+	all, err := discoverService.GetNew(context.Background(), 10, 0, false)
+	require.NoError(t, err)
+	safe, err := discoverService.GetNew(context.Background(), 10, 0, true)
+	require.NoError(t, err)
+
+	// Then only published media is listed, newest first
+	assert.Equal(t, []string{"new", "explicit", "old"}, mediaIDs(all))
+	assert.Equal(t, []string{"new", "old"}, mediaIDs(safe))
+}
+
+func TestDiscoverService_GetFeatured(t *testing.T) {
+	// Given picks of published, unpublished and explicit media, and a scheduled pick
+	future := time.Now().Add(time.Hour)
+	featuredRepo := newMemoryFeaturedItemRepository(
+		domain.FeaturedItem{ID: "f1", MediaID: "old", Position: 3},
+		domain.FeaturedItem{ID: "f2", MediaID: "explicit", Position: 2},
+		domain.FeaturedItem{ID: "f3", MediaID: "private", Position: 1},
+		domain.FeaturedItem{ID: "f4", MediaID: "new", Position: 0, StartsAt: &future},
+		domain.FeaturedItem{ID: "f5", MediaID: "missing", Position: 0},
+	)
+	discoverService, _ := newTestDiscoverService(t, featuredRepo)
+
+	// When
+	featured, err := discoverService.GetFeatured(context.Background(), false)
+	require.NoError(t, err)
+	safe, err := discoverService.GetFeatured(context.Background(), true)
+	require.NoError(t, err)
+
+	// Then active picks of published media are listed in position order
+	assert.Equal(t, []string{"explicit", "old"}, mediaIDs(featured))
+	assert.Equal(t, []string{"old"}, mediaIDs(safe))
+}
+
+func TestDiscoverService_ManageFeaturedItems(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	discoverService, mediaRepo := newTestDiscoverService(t, newMemoryFeaturedItemRepository())
+
+	// When a pick is added and moved
+	item, err := discoverService.CreateFeaturedItem(ctx, &domain.FeaturedItemRequest{MediaID: "old", Position: 1})
+	require.NoError(t, err)
+	updated, err := discoverService.UpdateFeaturedItem(ctx, item.ID, &domain.FeaturedItemRequest{MediaID: "new", Position: 5})
+	require.NoError(t, err)
+
+	// Then
+	assert.NotEmpty(t, item.ID)
+	assert.Equal(t, "new", updated.MediaID)
+	assert.Equal(t, 5, updated.Position)
+
+	// And picks of missing or deleted media are rejected
+	require.NoError(t, mediaRepo.UpdateStatus(ctx, "old", domain.StatusDeleted))
+	for _, mediaID := range []string{"unknown", "old"} {
+		_, err = discoverService.CreateFeaturedItem(ctx, &domain.FeaturedItemRequest{MediaID: mediaID})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "MEDIA_NOT_FOUND", businessErr.Code)
+	}
+
+	require.NoError(t, discoverService.DeleteFeaturedItem(ctx, item.ID))
+	assert.ErrorIs(t, discoverService.DeleteFeaturedItem(ctx, item.ID), domain.ErrFeaturedItemNotFound)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DownloadStatsService counts podcast downloads and reports them to advertisers
+type DownloadStatsService interface {
+	// RecordDownload counts a download of a media item by client, returning whether it was counted.
+	// Only podcast downloads are counted, once per client in DownloadDedupWindow.
+	RecordDownload(ctx context.Context, mediaID string, client domain.DownloadClient) (bool, error)
+
+	// GetEpisodeStats reports the daily downloads of an episode within the from and to days
+	GetEpisodeStats(ctx context.Context, mediaID, from, to string) (*domain.DownloadStats, error)
+
+	// GetShowStats reports the daily downloads of the episodes of a show within the from and to days
+	GetShowStats(ctx context.Context, showID, from, to string) (*domain.DownloadStats, error)
+}
+
+// downloadStatsService implements DownloadStatsService interface
+type downloadStatsService struct {
+	downloadRepo repository.DownloadRepository
+	mediaRepo    repository.MediaRepository
+	now          func() time.Time
+}
+
+// NewDownloadStatsService creates a new download statistics service
+func NewDownloadStatsService(downloadRepo repository.DownloadRepository, mediaRepo repository.MediaRepository) DownloadStatsService {
+	return &downloadStatsService{
+		downloadRepo: downloadRepo,
+		mediaRepo:    mediaRepo,
+		now:          time.Now,
+	}
+}
+
+// RecordDownload counts a download of a media item by client, returning whether it was counted
+func (s *downloadStatsService) RecordDownload(ctx context.Context, mediaID string, client domain.DownloadClient) (bool, error) {
+	if !client.IsCountable() {
+		return false, nil
+	}
+
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return false, err
+	}
+	if media.Type != domain.TypePodcast {
+		return false, nil
+	}
+
+	now := s.now().UTC()
+	download := &domain.Download{
+		ID:           uuid.New().String(),
+		MediaID:      media.ID,
+		ShowID:       media.ShowID,
+		ClientHash:   client.Hash(),
+		Day:          now.Format(domain.CalendarDateLayout),
+		DownloadedAt: now,
+	}
+	counted, err := s.downloadRepo.CreateUnlessSince(ctx, download, now.Add(-domain.DownloadDedupWindow))
+	if err != nil {
+		return false, fmt.Errorf("failed to record download: %w", err)
+	}
+
+	return counted, nil
+}
+
+// GetEpisodeStats reports the daily downloads of an episode within the from and to days
+func (s *downloadStatsService) GetEpisodeStats(ctx context.Context, mediaID, from, to string) (*domain.DownloadStats, error) {
+	start, end, err := s.parseRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.mediaRepo.GetByID(ctx, mediaID); err != nil {
+		return nil, err
+	}
+
+	days, err := s.downloadRepo.CountByMedia(ctx, mediaID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count downloads: %w", err)
+	}
+
+	stats := domain.NewDownloadStats(days, start, end)
+	stats.MediaID = mediaID
+	return stats, nil
+}
+
+// GetShowStats reports the daily downloads of the episodes of a show within the from and to days
+func (s *downloadStatsService) GetShowStats(ctx context.Context, showID, from, to string) (*domain.DownloadStats, error) {
+	start, end, err := s.parseRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.downloadRepo.CountByShow(ctx, showID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count downloads: %w", err)
+	}
+
+	stats := domain.NewDownloadStats(days, start, end)
+	stats.ShowID = showID
+	return stats, nil
+}
+
+// parseRange reads the days of a statistics request
+func (s *downloadStatsService) parseRange(from, to string) (time.Time, time.Time, error) {
+	start, end, errs := domain.ParseStatsRange(from, to, s.now())
+	if errs.HasErrors() {
+		return time.Time{}, time.Time{}, domain.NewBusinessErrorWithDetails("INVALID_RANGE", "Invalid statistics range", errs.Error())
+	}
+	return start, end, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryDownloadRepository keeps counted downloads in memory
+type memoryDownloadRepository struct {
+	downloads []domain.Download
+}
+
+func (r *memoryDownloadRepository) Create(ctx context.Context, download *domain.Download) error {
+	r.downloads = append(r.downloads, *download)
+	return nil
+}
+
+func (r *memoryDownloadRepository) CreateUnlessSince(ctx context.Context, download *domain.Download, since time.Time) (bool, error) {
+	for _, d := range r.downloads {
+		if d.MediaID == download.MediaID && d.ClientHash == download.ClientHash && !d.DownloadedAt.Before(since) {
+			return false, nil
+		}
+	}
+	return true, r.Create(ctx, download)
+}
+
+func (r *memoryDownloadRepository) CountByMedia(ctx context.Context, mediaID string, from, end time.Time) ([]domain.DownloadStatsDay, error) {
+	return r.countByDay(func(d domain.Download) bool { return d.MediaID == mediaID }, from, end), nil
+}
+
+func (r *memoryDownloadRepository) CountByShow(ctx context.Context, showID string, from, end time.Time) ([]domain.DownloadStatsDay, error) {
+	return r.countByDay(func(d domain.Download) bool { return d.ShowID == showID }, from, end), nil
+}
+
+func (r *memoryDownloadRepository) countByDay(match func(domain.Download) bool, from, end time.Time) []domain.DownloadStatsDay {
+	counts := make(map[string]int64)
+	for _, d := range r.downloads {
+		if match(d) && !d.DownloadedAt.Before(from) && d.DownloadedAt.Before(end) {
+			counts[d.Day]++
+		}
+	}
+	days := make([]domain.DownloadStatsDay, 0, len(counts))
+	for day, count := range counts {
+		days = append(days, domain.DownloadStatsDay{Day: day, Downloads: count})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+	return days
+}
+
+func TestDownloadStatsService_RecordDownload(t *testing.T) {
+	// Given a podcast episode and a video
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Type: domain.TypePodcast, ShowID: "show"}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "video", Type: domain.TypeVideo}))
+	s := NewDownloadStatsService(&memoryDownloadRepository{}, mediaRepo).(*downloadStatsService)
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	listener := domain.DownloadClient{IP: "203.0.113.7", UserAgent: "Overcast/3.0"}
+	record := func(mediaID string, client domain.DownloadClient) bool {
+		counted, err := s.RecordDownload(ctx, mediaID, client)
+		require.NoError(t, err)
+		return counted
+	}
+
+	// Then a listener counts once per 24 hours
+	assert.True(t, record("episode", listener))
+	now = now.Add(23 * time.Hour)
+	assert.False(t, record("episode", listener))
+	now = now.Add(2 * time.Hour)
+	assert.True(t, record("episode", listener))
+
+	// And other apps on the same IP address count separately
+	assert.True(t, record("episode", domain.DownloadClient{IP: listener.IP, UserAgent: "Pocket Casts"}))
+
+	// And bots, range probes and videos are not counted
+	assert.False(t, record("episode", domain.DownloadClient{IP: "198.51.100.1", UserAgent: "Googlebot/2.1"}))
+	assert.False(t, record("episode", domain.DownloadClient{IP: "198.51.100.2", UserAgent: "AppleCoreMedia", Range: "bytes=0-1"}))
+	assert.False(t, record("video", listener))
+
+	_, err := s.RecordDownload(ctx, "missing", listener)
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+
+	// When
+	episode, err := s.GetEpisodeStats(ctx, "episode", "2025-06-01", "2025-06-30")
+	require.NoError(t, err)
+	show, err := s.GetShowStats(ctx, "show", "2025-06-01", "2025-06-30")
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, "episode", episode.MediaID)
+	assert.Equal(t, int64(3), episode.Total)
+	assert.Equal(t, []domain.DownloadStatsDay{{Day: "2025-06-01", Downloads: 1}, {Day: "2025-06-02", Downloads: 2}}, episode.Days)
+	assert.Equal(t, "show", show.ShowID)
+	assert.Equal(t, int64(3), show.Total)
+}
+
+func TestDownloadStatsService_GetStatsErrors(t *testing.T) {
+	s := NewDownloadStatsService(&memoryDownloadRepository{}, repository.NewInMemoryMediaRepository())
+
+	_, err := s.GetEpisodeStats(context.Background(), "missing", "", "")
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+
+	_, err = s.GetShowStats(context.Background(), "show", "2025-06-30", "2025-06-01")
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_RANGE", businessErr.Code)
+}
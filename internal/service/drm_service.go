@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// contentKeySize is the size of AES-128 content keys and key IDs used by CENC and FairPlay
+const contentKeySize = 16
+
+// DRMOptions configures the DRM provider integration
+type DRMOptions struct {
+	Enabled                bool
+	WidevineLicenseURL     string
+	FairPlayLicenseURL     string
+	FairPlayCertificateURL string
+	KeyEncryptionKey       []byte // AES-256 key encrypting the stored content keys
+}
+
+// DRMService manages DRM content keys and license acquisition info
+type DRMService interface {
+	// Protects reports whether media only plays with a DRM license
+	Protects(media *domain.Media) bool
+
+	// ContentKey returns the content key of a media item, generating it on first use.
+	// The transcoder encrypts the renditions of protected media with it.
+	ContentKey(ctx context.Context, mediaID string) (keyID string, key []byte, err error)
+
+	// PlaybackDRM returns the license acquisition info of media, or nil when it is not protected
+	PlaybackDRM(ctx context.Context, media *domain.Media) (*domain.PlaybackDRM, error)
+}
+
+// drmService implements DRMService interface
+type drmService struct {
+	keyRepo repository.ContentKeyRepository
+	options DRMOptions
+	aead    cipher.AEAD
+}
+
+// NewDRMService creates a new DRM service
+func NewDRMService(keyRepo repository.ContentKeyRepository, options DRMOptions) (DRMService, error) {
+	service := &drmService{
+		keyRepo: keyRepo,
+		options: options,
+	}
+	if !options.Enabled {
+		return service, nil
+	}
+
+	if options.WidevineLicenseURL == "" && options.FairPlayLicenseURL == "" {
+		return nil, fmt.Errorf("DRM is enabled but no license URL is configured")
+	}
+	if options.FairPlayLicenseURL != "" && options.FairPlayCertificateURL == "" {
+		return nil, fmt.Errorf("FairPlay requires an application certificate URL")
+	}
+	if len(options.KeyEncryptionKey) != 32 {
+		return nil, fmt.Errorf("key encryption key must be 32 bytes, got %d", len(options.KeyEncryptionKey))
+	}
+
+	block, err := aes.NewCipher(options.KeyEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encryption key: %w", err)
+	}
+	service.aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encryption key: %w", err)
+	}
+
+	return service, nil
+}
+
+// Protects reports whether media only plays with a DRM license
+func (s *drmService) Protects(media *domain.Media) bool {
+	return s.options.Enabled && media.RequiresDRM()
+}
+
+// ContentKey returns the content key of a media item, generating it on first use
+func (s *drmService) ContentKey(ctx context.Context, mediaID string) (string, []byte, error) {
+	if !s.options.Enabled {
+		return "", nil, fmt.Errorf("DRM is not enabled")
+	}
+
+	stored, err := s.getOrCreateKey(ctx, mediaID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key, err := s.openKey(stored)
+	if err != nil {
+		return "", nil, err
+	}
+	return stored.KeyID, key, nil
+}
+
+// PlaybackDRM returns the license acquisition info of media, or nil when it is not protected
+func (s *drmService) PlaybackDRM(ctx context.Context, media *domain.Media) (*domain.PlaybackDRM, error) {
+	if !s.Protects(media) {
+		return nil, nil
+	}
+
+	stored, err := s.getOrCreateKey(ctx, media.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &domain.PlaybackDRM{KeyID: stored.KeyID, Licenses: []domain.DRMLicense{}}
+	if s.options.WidevineLicenseURL != "" {
+		info.Licenses = append(info.Licenses, domain.DRMLicense{
+			System:     domain.DRMWidevine,
+			LicenseURL: s.options.WidevineLicenseURL,
+		})
+	}
+	if s.options.FairPlayLicenseURL != "" {
+		info.Licenses = append(info.Licenses, domain.DRMLicense{
+			System:         domain.DRMFairPlay,
+			LicenseURL:     s.options.FairPlayLicenseURL,
+			CertificateURL: s.options.FairPlayCertificateURL,
+		})
+	}
+
+	return info, nil
+}
+
+// getOrCreateKey loads the content key of a media item, generating and storing one when missing
+func (s *drmService) getOrCreateKey(ctx context.Context, mediaID string) (*domain.ContentKey, error) {
+	stored, err := s.keyRepo.GetByMediaID(ctx, mediaID)
+	if err == nil {
+		return stored, nil
+	}
+	if !errors.Is(err, domain.ErrContentKeyNotFound) {
+		return nil, fmt.Errorf("failed to load content key: %w", err)
+	}
+
+	keyID := make([]byte, contentKeySize)
+	key := make([]byte, contentKeySize)
+	if _, err := rand.Read(keyID); err != nil {
+		return nil, fmt.Errorf("failed to generate key ID: %w", err)
+	}
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate content key: %w", err)
+	}
+
+	encrypted, err := s.sealKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stored = &domain.ContentKey{
+		MediaID:      mediaID,
+		KeyID:        hex.EncodeToString(keyID),
+		EncryptedKey: encrypted,
+	}
+	created, err := s.keyRepo.Create(ctx, stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store content key: %w", err)
+	}
+	if !created {
+		// Another request generated the key first
+		return s.keyRepo.GetByMediaID(ctx, mediaID)
+	}
+
+	return stored, nil
+}
+
+// sealKey encrypts a content key with the key encryption key, prefixing the nonce
+func (s *drmService) sealKey(key []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return s.aead.Seal(nonce, nonce, key, nil), nil
+}
+
+// openKey decrypts a stored content key
+func (s *drmService) openKey(stored *domain.ContentKey) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	if len(stored.EncryptedKey) < nonceSize {
+		return nil, fmt.Errorf("content key of media %s is corrupted", stored.MediaID)
+	}
+
+	key, err := s.aead.Open(nil, stored.EncryptedKey[:nonceSize], stored.EncryptedKey[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt content key of media %s: %w", stored.MediaID, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockContentKeyRepository is a mock implementation of ContentKeyRepository
+type MockContentKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockContentKeyRepository) Create(ctx context.Context, key *domain.ContentKey) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockContentKeyRepository) GetByMediaID(ctx context.Context, mediaID string) (*domain.ContentKey, error) {
+	args := m.Called(ctx, mediaID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ContentKey), args.Error(1)
+}
+
+func testDRMOptions() DRMOptions {
+	return DRMOptions{
+		Enabled:                true,
+		WidevineLicenseURL:     "https://drm.example.com/widevine",
+		FairPlayLicenseURL:     "https://drm.example.com/fairplay",
+		FairPlayCertificateURL: "https://drm.example.com/fairplay.cer",
+		KeyEncryptionKey:       bytes.Repeat([]byte{7}, 32),
+	}
+}
+
+func TestNewDRMService_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     func() DRMOptions
+		expectError bool
+	}{
+		{name: "valid options", options: testDRMOptions},
+		{name: "disabled", options: func() DRMOptions { return DRMOptions{} }},
+		{
+			name: "short key encryption key",
+			options: func() DRMOptions {
+				options := testDRMOptions()
+				options.KeyEncryptionKey = []byte("short")
+				return options
+			},
+			expectError: true,
+		},
+		{
+			name: "no license URL",
+			options: func() DRMOptions {
+				options := testDRMOptions()
+				options.WidevineLicenseURL, options.FairPlayLicenseURL = "", ""
+				return options
+			},
+			expectError: true,
+		},
+		{
+			name: "FairPlay without certificate",
+			options: func() DRMOptions {
+				options := testDRMOptions()
+				options.FairPlayCertificateURL = ""
+				return options
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			service, err := NewDRMService(new(MockContentKeyRepository), tt.options())
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, service)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, service)
+			}
+		})
+	}
+}
+
+func TestDRMService_ContentKey_GeneratesAndDecryptsKey(t *testing.T) {
+	// Given
+	keyRepo := new(MockContentKeyRepository)
+	var stored *domain.ContentKey
+	keyRepo.On("GetByMediaID", mock.Anything, "media-123").Return(nil, domain.ErrContentKeyNotFound).Once()
+	keyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ContentKey")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.ContentKey) }).
+		Return(true, nil)
+	service, err := NewDRMService(keyRepo, testDRMOptions())
+	require.NoError(t, err)
+
+	// When
+	keyID, key, err := service.ContentKey(context.Background(), "media-123")
+
+	// Then
+	require.NoError(t, err)
+	assert.Len(t, keyID, 32)
+	assert.Len(t, key, 16)
+	assert.NotContains(t, string(stored.EncryptedKey), string(key))
+
+	// The stored key decrypts to the same content key
+	keyRepo.On("GetByMediaID", mock.Anything, "media-123").Return(stored, nil)
+	sameKeyID, sameKey, err := service.ContentKey(context.Background(), "media-123")
+	require.NoError(t, err)
+	assert.Equal(t, keyID, sameKeyID)
+	assert.Equal(t, key, sameKey)
+	keyRepo.AssertExpectations(t)
+}
+
+func TestDRMService_PlaybackDRM(t *testing.T) {
+	tests := []struct {
+		name             string
+		media            *domain.Media
+		expectedLicenses []domain.DRMSystem
+	}{
+		{
+			name:             "premium video",
+			media:            &domain.Media{ID: "media-123", Type: domain.TypeVideo, AccessTier: domain.AccessTierPremium},
+			expectedLicenses: []domain.DRMSystem{domain.DRMWidevine, domain.DRMFairPlay},
+		},
+		{
+			name:  "free video",
+			media: &domain.Media{ID: "media-123", Type: domain.TypeVideo, AccessTier: domain.AccessTierFree},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			keyRepo := new(MockContentKeyRepository)
+			keyRepo.On("GetByMediaID", mock.Anything, "media-123").
+				Return(&domain.ContentKey{MediaID: "media-123", KeyID: "0123456789abcdef0123456789abcdef"}, nil).Maybe()
+			service, err := NewDRMService(keyRepo, testDRMOptions())
+			require.NoError(t, err)
+
+			// When
+			info, err := service.PlaybackDRM(context.Background(), tt.media)
+
+			// Then
+			assert.NoError(t, err)
+			if tt.expectedLicenses == nil {
+				assert.Nil(t, info)
+				return
+			}
+			assert.Equal(t, "0123456789abcdef0123456789abcdef", info.KeyID)
+			systems := make([]domain.DRMSystem, len(info.Licenses))
+			for i, license := range info.Licenses {
+				systems[i] = license.System
+			}
+			assert.Equal(t, tt.expectedLicenses, systems)
+			assert.Equal(t, "https://drm.example.com/fairplay.cer", info.Licenses[1].CertificateURL)
+		})
+	}
+}
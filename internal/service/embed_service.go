@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// EmbedOptions configures the embeddable player
+type EmbedOptions struct {
+	BaseURL        string   // public base URL media files are served from
+	AllowedOrigins []string // origins allowed to embed the player, "*" allows all, "*.example.com" allows subdomains
+	DefaultTheme   domain.EmbedTheme
+}
+
+// EmbedService builds player configurations for embedded players
+type EmbedService interface {
+	// GetEmbedConfig returns the player configuration of a media item.
+	// Empty theme fields fall back to the configured defaults.
+	GetEmbedConfig(ctx context.Context, mediaID string, viewer domain.Viewer, theme domain.EmbedTheme) (*domain.EmbedConfig, error)
+
+	// IsOriginAllowed reports whether a page on origin may embed the player
+	IsOriginAllowed(origin string) bool
+
+	// AllowedOrigins lists the origins allowed to embed the player
+	AllowedOrigins() []string
+}
+
+// embedService implements EmbedService interface
+type embedService struct {
+	mediaRepo repository.MediaRepository
+	options   EmbedOptions
+}
+
+// NewEmbedService creates a new embed service
+func NewEmbedService(mediaRepo repository.MediaRepository, options EmbedOptions) EmbedService {
+	return &embedService{
+		mediaRepo: mediaRepo,
+		options:   options,
+	}
+}
+
+// GetEmbedConfig returns the player configuration of a media item
+func (s *embedService) GetEmbedConfig(ctx context.Context, mediaID string, viewer domain.Viewer, theme domain.EmbedTheme) (*domain.EmbedConfig, error) {
+	if theme.Mode == "" {
+		theme.Mode = s.options.DefaultTheme.Mode
+	}
+	if theme.AccentColor == "" {
+		theme.AccentColor = s.options.DefaultTheme.AccentColor
+	}
+	if errs := theme.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Player theme validation failed", errs.Error())
+	}
+
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := media.CheckPlayback(viewer); err != nil {
+		return nil, err
+	}
+
+	if !media.IsProcessed() {
+		return nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for playback")
+	}
+
+	return domain.NewEmbedConfig(media, s.options.BaseURL, theme), nil
+}
+
+// IsOriginAllowed reports whether a page on origin may embed the player
+func (s *embedService) IsOriginAllowed(origin string) bool {
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	host := parsed.Hostname()
+
+	for _, allowed := range s.options.AllowedOrigins {
+		switch {
+		case allowed == "*":
+			return true
+		case strings.HasPrefix(allowed, "*."):
+			if strings.HasSuffix(host, allowed[1:]) {
+				return true
+			}
+		case strings.EqualFold(strings.TrimSuffix(allowed, "/"), parsed.Scheme+"://"+parsed.Host):
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowedOrigins lists the origins allowed to embed the player
+func (s *embedService) AllowedOrigins() []string {
+	return s.options.AllowedOrigins
+}
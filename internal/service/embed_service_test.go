@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestEmbedService(mediaRepo *MockMediaRepository) EmbedService {
+	return NewEmbedService(mediaRepo, EmbedOptions{
+		BaseURL:        "https://media.example.com",
+		AllowedOrigins: []string{"https://www.example.com", "*.partner.com"},
+		DefaultTheme:   domain.EmbedTheme{Mode: domain.ThemeDark, AccentColor: "#1DB954"},
+	})
+}
+
+func TestEmbedService_IsOriginAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   string
+		expected bool
+	}{
+		{name: "exact origin", origin: "https://www.example.com", expected: true},
+		{name: "exact origin with other scheme", origin: "http://www.example.com", expected: false},
+		{name: "wildcard subdomain", origin: "https://blog.partner.com", expected: true},
+		{name: "lookalike domain", origin: "https://evilpartner.com", expected: false},
+		{name: "unknown origin", origin: "https://attacker.io", expected: false},
+		{name: "malformed origin", origin: "not a url", expected: false},
+	}
+
+	service := newTestEmbedService(new(MockMediaRepository))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, service.IsOriginAllowed(tt.origin))
+		})
+	}
+}
+
+func TestEmbedService_GetEmbedConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		media       *domain.Media
+		viewer      domain.Viewer
+		theme       domain.EmbedTheme
+		expectError bool
+		errorCode   string
+	}{
+		{
+			name:  "ready public media with default theme",
+			media: &domain.Media{ID: "media-123", Status: domain.StatusReady, FilePath: "/uploads/media-123.mp4"},
+		},
+		{
+			name:  "theme override",
+			media: &domain.Media{ID: "media-123", Status: domain.StatusReady, FilePath: "/uploads/media-123.mp4"},
+			theme: domain.EmbedTheme{Mode: domain.ThemeLight},
+		},
+		{
+			name:        "invalid accent color",
+			media:       &domain.Media{ID: "media-123", Status: domain.StatusReady},
+			theme:       domain.EmbedTheme{AccentColor: "green"},
+			expectError: true,
+			errorCode:   "INVALID_REQUEST",
+		},
+		{
+			name:        "media still processing",
+			media:       &domain.Media{ID: "media-123", Status: domain.StatusProcessing},
+			expectError: true,
+			errorCode:   "MEDIA_NOT_READY",
+		},
+		{
+			name:        "private media for anonymous viewer",
+			media:       &domain.Media{ID: "media-123", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+			expectError: true,
+		},
+		{
+			name:   "private media shared through a link",
+			media:  &domain.Media{ID: "media-123", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+			viewer: domain.Viewer{SharedMediaID: "media-123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(tt.media, nil).Maybe()
+			service := newTestEmbedService(mockRepo)
+
+			// When
+			config, err := service.GetEmbedConfig(context.Background(), "media-123", tt.viewer, tt.theme)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, config)
+				var businessErr *domain.BusinessError
+				if tt.errorCode != "" && errors.As(err, &businessErr) {
+					assert.Equal(t, tt.errorCode, businessErr.Code)
+				} else {
+					assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "https://media.example.com"+tt.media.FilePath, config.StreamURL)
+			expectedMode := tt.theme.Mode
+			if expectedMode == "" {
+				expectedMode = domain.ThemeDark
+			}
+			assert.Equal(t, expectedMode, config.Theme.Mode)
+			assert.Equal(t, "#1DB954", config.Theme.AccentColor)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/messagequeue"
+)
+
+// EventPublisher publishes domain events emitted by services
+type EventPublisher interface {
+	// Publish delivers a domain event to interested consumers
+	Publish(ctx context.Context, event *domain.Event) error
+}
+
+// logEventPublisher implements EventPublisher by logging events
+type logEventPublisher struct{}
+
+// NewLogEventPublisher creates an event publisher that only logs events
+func NewLogEventPublisher() EventPublisher {
+	return &logEventPublisher{}
+}
+
+// Publish logs the event
+func (p *logEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	log.Printf("Event %s (%s): %v", event.Type, event.ID, event.Data)
+	return nil
+}
+
+// fanoutEventPublisher implements EventPublisher by forwarding to several publishers
+type fanoutEventPublisher struct {
+	publishers []EventPublisher
+}
+
+// NewFanoutEventPublisher creates an event publisher forwarding events to all publishers
+func NewFanoutEventPublisher(publishers ...EventPublisher) EventPublisher {
+	return &fanoutEventPublisher{
+		publishers: publishers,
+	}
+}
+
+// Publish forwards the event to every publisher and returns the first error
+func (p *fanoutEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	var firstErr error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// tracingEventPublisher implements EventPublisher by stamping events with the
+// trace context of the request that caused them
+type tracingEventPublisher struct {
+	next EventPublisher
+}
+
+// NewTracingEventPublisher creates an event publisher that adds the trace context
+// carried by ctx to events without one, then forwards them to next
+func NewTracingEventPublisher(next EventPublisher) EventPublisher {
+	return &tracingEventPublisher{
+		next: next,
+	}
+}
+
+// Publish stamps the event and forwards it
+func (p *tracingEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	if event.Trace == nil {
+		event.Trace = domain.TraceFromContext(ctx)
+	}
+	return p.next.Publish(ctx, event)
+}
+
+// AsyncEventPublisher is an EventPublisher handing events over to background
+// workers, so slow consumers do not delay the change that emitted them
+type AsyncEventPublisher interface {
+	EventPublisher
+
+	// Run publishes the queued events with workers until ctx is cancelled
+	Run(ctx context.Context, workers int)
+}
+
+// asyncEvent is an event waiting to be published, with the context it was emitted in
+type asyncEvent struct {
+	ctx   context.Context
+	event *domain.Event
+}
+
+// asyncEventPublisher implements AsyncEventPublisher with a bounded queue
+type asyncEventPublisher struct {
+	next   EventPublisher
+	events chan asyncEvent
+}
+
+// NewAsyncEventPublisher creates an event publisher queuing up to queueSize
+// events for Run to forward to next. Events are dropped, and logged, while the
+// queue is full, so Publish never blocks.
+func NewAsyncEventPublisher(next EventPublisher, queueSize int) AsyncEventPublisher {
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	return &asyncEventPublisher{
+		next:   next,
+		events: make(chan asyncEvent, queueSize),
+	}
+}
+
+// Publish queues the event
+func (p *asyncEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	// The event is published after the request that emitted it returned
+	select {
+	case p.events <- asyncEvent{ctx: context.WithoutCancel(ctx), event: event}:
+		return nil
+	default:
+		return fmt.Errorf("event queue full, dropped %s", event.Type)
+	}
+}
+
+// Run publishes the queued events with workers until ctx is cancelled
+func (p *asyncEventPublisher) Run(ctx context.Context, workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case queued := <-p.events:
+					if err := p.next.Publish(queued.ctx, queued.event); err != nil {
+						log.Printf("Failed to publish %s (%s): %v", queued.event.Type, queued.event.ID, err)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if pending := len(p.events); pending > 0 {
+		log.Printf("Event publisher stopped with %d events queued", pending)
+	}
+}
+
+// queueEventPublisher implements EventPublisher on top of a message queue
+type queueEventPublisher struct {
+	queue messagequeue.MessageQueue
+}
+
+// NewQueueEventPublisher creates an event publisher that sends events to the queue,
+// using the event type as topic
+func NewQueueEventPublisher(queue messagequeue.MessageQueue) EventPublisher {
+	return &queueEventPublisher{
+		queue: queue,
+	}
+}
+
+// Publish marshals the event and publishes it on the queue
+func (p *queueEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := p.queue.Publish(ctx, event.Type, payload); err != nil {
+		return fmt.Errorf("failed to publish event %s: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// outboxEventPublisher implements EventPublisher by storing events in the outbox,
+// from where the outbox relay publishes them to the message queue
+type outboxEventPublisher struct {
+	outboxRepo repository.OutboxRepository
+}
+
+// NewOutboxEventPublisher creates an event publisher writing events to the outbox
+func NewOutboxEventPublisher(outboxRepo repository.OutboxRepository) EventPublisher {
+	return &outboxEventPublisher{
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Publish marshals the event and appends it to the outbox
+func (p *outboxEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := p.outboxRepo.Append(ctx, domain.NewOutboxMessage(event.Type, payload)); err != nil {
+		return fmt.Errorf("failed to append event %s to outbox: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// mediaIndexActions maps the media events search consumers act on to their index action
+var mediaIndexActions = map[string]string{
+	domain.EventMediaUploaded:      messagequeue.MediaIndexCreated,
+	domain.EventMediaUpdated:       messagequeue.MediaIndexUpdated,
+	domain.EventMediaStatusChanged: messagequeue.MediaIndexUpdated,
+	domain.EventMediaUnpublished:   messagequeue.MediaIndexUpdated,
+	domain.EventMediaDeleted:       messagequeue.MediaIndexDeleted,
+	domain.EventMediaPurged:        messagequeue.MediaIndexDeleted,
+}
+
+// mediaIndexEventPublisher implements EventPublisher by translating media
+// events into versioned media index events on the queue
+type mediaIndexEventPublisher struct {
+	queue    messagequeue.MessageQueue
+	registry *messagequeue.Registry
+}
+
+// NewMediaIndexEventPublisher creates an event publisher that publishes a
+// MediaIndexEvent for every media uploaded, updated, deleted or purged, or whose
+// status or visibility changed, so discovery can keep its index fresh. Other events are ignored. The registry
+// defaults to the built-in schemas when nil.
+func NewMediaIndexEventPublisher(queue messagequeue.MessageQueue, registry *messagequeue.Registry) EventPublisher {
+	if registry == nil {
+		registry = messagequeue.NewDefaultRegistry()
+	}
+
+	return &mediaIndexEventPublisher{
+		queue:    queue,
+		registry: registry,
+	}
+}
+
+// Publish publishes the media index event of a media event. Consumers look the
+// media up by ID, as it may have changed again by the time they index it.
+func (p *mediaIndexEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	action, ok := mediaIndexActions[event.Type]
+	if !ok {
+		return nil
+	}
+	mediaID, _ := event.Data["media_id"].(string)
+	if mediaID == "" {
+		return nil
+	}
+
+	indexEvent := &messagequeue.MediaIndexEvent{Action: action, MediaID: mediaID}
+	payload, err := p.registry.Marshal(indexEvent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal media index event: %w", err)
+	}
+
+	if err := p.queue.Publish(ctx, indexEvent.EventType(), payload); err != nil {
+		return fmt.Errorf("failed to publish media index event for %s: %w", mediaID, err)
+	}
+
+	return nil
+}
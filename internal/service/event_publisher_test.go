@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/messagequeue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingEventPublisher_Publish(t *testing.T) {
+	requestTrace := &domain.TraceContext{RequestID: "req-1", TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx := domain.ContextWithTrace(context.Background(), requestTrace)
+
+	t.Run("stamps the trace of the request", func(t *testing.T) {
+		next := newMockEventPublisher()
+		event := domain.NewEvent(domain.EventMediaUploaded, nil)
+
+		require.NoError(t, NewTracingEventPublisher(next).Publish(ctx, event))
+
+		assert.Equal(t, requestTrace, event.Trace)
+		next.AssertCalled(t, "Publish", ctx, event)
+	})
+
+	t.Run("keeps the trace of the event", func(t *testing.T) {
+		next := newMockEventPublisher()
+		original := &domain.TraceContext{RequestID: "req-0"}
+		event := domain.NewEvent(domain.EventMediaUploaded, nil)
+		event.Trace = original
+
+		require.NoError(t, NewTracingEventPublisher(next).Publish(ctx, event))
+
+		assert.Equal(t, original, event.Trace)
+	})
+
+	t.Run("events outside a request have no trace", func(t *testing.T) {
+		event := domain.NewEvent(domain.EventMediaUploaded, nil)
+
+		require.NoError(t, NewTracingEventPublisher(newMockEventPublisher()).Publish(context.Background(), event))
+
+		assert.Nil(t, event.Trace)
+	})
+}
+
+func TestMediaIndexEventPublisher_Publish(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventType      string
+		expectedAction string
+	}{
+		{name: "uploaded media is created", eventType: domain.EventMediaUploaded, expectedAction: messagequeue.MediaIndexCreated},
+		{name: "updated media is updated", eventType: domain.EventMediaUpdated, expectedAction: messagequeue.MediaIndexUpdated},
+		{name: "status change is an update", eventType: domain.EventMediaStatusChanged, expectedAction: messagequeue.MediaIndexUpdated},
+		{name: "unpublished media is updated", eventType: domain.EventMediaUnpublished, expectedAction: messagequeue.MediaIndexUpdated},
+		{name: "deleted media is deleted", eventType: domain.EventMediaDeleted, expectedAction: messagequeue.MediaIndexDeleted},
+		{name: "purged media is deleted", eventType: domain.EventMediaPurged, expectedAction: messagequeue.MediaIndexDeleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			queue := messagequeue.NewInMemoryQueue()
+			registry := messagequeue.NewDefaultRegistry()
+			received := make(chan []byte, 1)
+			require.NoError(t, queue.Subscribe(context.Background(), messagequeue.EventTypeMediaIndex, func(ctx context.Context, message []byte) error {
+				received <- message
+				return nil
+			}))
+
+			// When
+			err := NewMediaIndexEventPublisher(queue, registry).Publish(context.Background(), domain.NewEvent(tt.eventType, map[string]interface{}{"media_id": "media-1"}))
+
+			// Then
+			require.NoError(t, err)
+			select {
+			case message := <-received:
+				_, event, err := registry.UnmarshalLatest(message)
+				require.NoError(t, err)
+				indexEvent, ok := event.(*messagequeue.MediaIndexEvent)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedAction, indexEvent.Action)
+				assert.Equal(t, "media-1", indexEvent.MediaID)
+			case <-time.After(time.Second):
+				t.Fatal("expected a media index event")
+			}
+		})
+	}
+
+	t.Run("ignores other events", func(t *testing.T) {
+		queue := messagequeue.NewInMemoryQueue()
+		received := make(chan []byte, 1)
+		require.NoError(t, queue.Subscribe(context.Background(), messagequeue.EventTypeMediaIndex, func(ctx context.Context, message []byte) error {
+			received <- message
+			return nil
+		}))
+
+		publisher := NewMediaIndexEventPublisher(queue, nil)
+		require.NoError(t, publisher.Publish(context.Background(), domain.NewEvent(domain.EventLicenseExpiring, map[string]interface{}{"media_id": "media-1"})))
+		require.NoError(t, publisher.Publish(context.Background(), domain.NewEvent(domain.EventMediaUpdated, nil)))
+
+		assert.Empty(t, received)
+	})
+}
+
+func TestAsyncEventPublisher(t *testing.T) {
+	t.Run("publishes after the request returned", func(t *testing.T) {
+		published := make(chan *domain.Event, 1)
+		next := new(MockEventPublisher)
+		next.On("Publish", mock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Err() == nil
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			published <- args.Get(1).(*domain.Event)
+		}).Return(nil)
+		publisher := NewAsyncEventPublisher(next, 10)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go publisher.Run(ctx, 1)
+
+		requestCtx, endRequest := context.WithCancel(context.Background())
+		event := domain.NewEvent(domain.EventMediaStatusChanged, nil)
+		require.NoError(t, publisher.Publish(requestCtx, event))
+		endRequest()
+
+		select {
+		case got := <-published:
+			assert.Equal(t, event, got)
+		case <-time.After(time.Second):
+			t.Fatal("event was not published")
+		}
+	})
+
+	t.Run("drops events while the queue is full", func(t *testing.T) {
+		publisher := NewAsyncEventPublisher(newMockEventPublisher(), 1)
+
+		require.NoError(t, publisher.Publish(context.Background(), domain.NewEvent(domain.EventMediaUploaded, nil)))
+		assert.Error(t, publisher.Publish(context.Background(), domain.NewEvent(domain.EventMediaUploaded, nil)))
+	})
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/messagequeue"
+)
+
+// eventStreamBuffer is how many events a slow stream consumer may lag behind
+const eventStreamBuffer = 64
+
+// EventStreamService relays domain events from the message queue to live consumers
+type EventStreamService interface {
+	// Subscribe streams events whose type matches the topic pattern until ctx is cancelled
+	Subscribe(ctx context.Context, pattern string) (<-chan *domain.Event, error)
+}
+
+// eventStreamService implements EventStreamService interface
+type eventStreamService struct {
+	queue messagequeue.MessageQueue
+}
+
+// NewEventStreamService creates a new event stream service
+func NewEventStreamService(queue messagequeue.MessageQueue) EventStreamService {
+	return &eventStreamService{
+		queue: queue,
+	}
+}
+
+// Subscribe streams events whose type matches the topic pattern until ctx is cancelled
+func (s *eventStreamService) Subscribe(ctx context.Context, pattern string) (<-chan *domain.Event, error) {
+	if pattern == "" {
+		pattern = "#"
+	}
+
+	events := make(chan *domain.Event, eventStreamBuffer)
+	handler := func(_ context.Context, message []byte) error {
+		var event domain.Event
+		if err := json.Unmarshal(message, &event); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+
+		// Never block publishers on a slow dashboard
+		select {
+		case <-ctx.Done():
+		case events <- &event:
+		default:
+			log.Printf("Event stream consumer is lagging, dropping event %s", event.ID)
+		}
+		return nil
+	}
+
+	if err := s.queue.Subscribe(ctx, pattern, handler); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+
+	return events, nil
+}
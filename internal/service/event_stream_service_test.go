@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/messagequeue"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStreamService_Subscribe(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		publishTypes  []string
+		expectedTypes []string
+	}{
+		{
+			name:          "empty pattern receives all events",
+			pattern:       "",
+			publishTypes:  []string{domain.EventMediaStatusChanged, domain.EventModerationDecided},
+			expectedTypes: []string{domain.EventMediaStatusChanged, domain.EventModerationDecided},
+		},
+		{
+			name:          "prefix pattern filters events",
+			pattern:       "media.#",
+			publishTypes:  []string{domain.EventModerationDecided, domain.EventMediaStatusChanged},
+			expectedTypes: []string{domain.EventMediaStatusChanged},
+		},
+		{
+			name:          "exact pattern filters events",
+			pattern:       domain.EventModerationDecided,
+			publishTypes:  []string{domain.EventMediaStatusChanged, domain.EventModerationDecided},
+			expectedTypes: []string{domain.EventModerationDecided},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			queue := messagequeue.NewInMemoryQueue()
+			streamService := NewEventStreamService(queue)
+			publisher := NewQueueEventPublisher(queue)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events, err := streamService.Subscribe(ctx, tt.pattern)
+			assert.NoError(t, err)
+
+			// When
+			for _, eventType := range tt.publishTypes {
+				err := publisher.Publish(context.Background(), domain.NewEvent(eventType, map[string]interface{}{"media_id": "media-1"}))
+				assert.NoError(t, err)
+			}
+
+			// Then
+			for _, expectedType := range tt.expectedTypes {
+				select {
+				case event := <-events:
+					assert.Equal(t, expectedType, event.Type)
+					assert.Equal(t, "media-1", event.Data["media_id"])
+				case <-time.After(time.Second):
+					t.Fatalf("expected event %s was not received", expectedType)
+				}
+			}
+			assert.Empty(t, events)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/chromaprint"
+	"thamaniyah/pkg/storage"
+)
+
+// maxFingerprintCandidates bounds the fingerprints a new fingerprint is compared with
+const maxFingerprintCandidates = 500
+
+// AudioFingerprinter computes the acoustic fingerprint of media
+type AudioFingerprinter interface {
+	Fingerprint(ctx context.Context, src io.Reader) (*chromaprint.Fingerprint, error)
+}
+
+// DuplicateDetector finds the media an upload is a re-encoded copy of
+type DuplicateDetector interface {
+	// DetectDuplicate fingerprints the uploaded file of media and links media to
+	// the oldest media of its tenant it is a near-duplicate of
+	DetectDuplicate(ctx context.Context, media *domain.Media) error
+}
+
+// fingerprintService implements DuplicateDetector interface
+type fingerprintService struct {
+	fingerprintRepo repository.FingerprintRepository
+	storage         storage.Storage
+	fingerprinter   AudioFingerprinter
+	publisher       EventPublisher
+	threshold       float64
+}
+
+// NewFingerprintService creates a new fingerprint service linking media whose
+// fingerprint similarity reaches threshold, domain.DefaultFingerprintThreshold when 0
+func NewFingerprintService(fingerprintRepo repository.FingerprintRepository, store storage.Storage, fingerprinter AudioFingerprinter, publisher EventPublisher, threshold float64) DuplicateDetector {
+	if publisher == nil {
+		publisher = NewLogEventPublisher()
+	}
+	if threshold <= 0 {
+		threshold = domain.DefaultFingerprintThreshold
+	}
+
+	return &fingerprintService{
+		fingerprintRepo: fingerprintRepo,
+		storage:         store,
+		fingerprinter:   fingerprinter,
+		publisher:       publisher,
+		threshold:       threshold,
+	}
+}
+
+// DetectDuplicate fingerprints the uploaded file of media and links it to the media it is a near-duplicate of
+func (s *fingerprintService) DetectDuplicate(ctx context.Context, media *domain.Media) error {
+	object, err := s.storage.Get(ctx, media.StorageKey())
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer object.Body.Close()
+
+	computed, err := s.fingerprinter.Fingerprint(ctx, object.Body)
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint audio: %w", err)
+	}
+
+	fingerprint := &domain.MediaFingerprint{
+		MediaID:     media.ID,
+		TenantID:    media.TenantID,
+		Duration:    int(math.Round(computed.Duration)),
+		Fingerprint: computed.Items,
+	}
+	if err := s.fingerprintRepo.Save(ctx, fingerprint); err != nil {
+		return fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+
+	// Media already linked keeps its original when it is processed again
+	if media.DuplicateOfID != "" {
+		return nil
+	}
+
+	minDuration, maxDuration := fingerprint.DurationRange()
+	candidates, err := s.fingerprintRepo.FindCandidates(ctx, media.TenantID, media.ID, minDuration, maxDuration, maxFingerprintCandidates)
+	if err != nil {
+		return fmt.Errorf("failed to find fingerprint candidates: %w", err)
+	}
+
+	// Candidates come oldest first, so the first match is the closest to the original upload
+	for _, candidate := range candidates {
+		similarity := chromaprint.Similarity(fingerprint.Fingerprint, candidate.Fingerprint)
+		if similarity < s.threshold {
+			continue
+		}
+
+		media.DuplicateOfID = candidate.MediaID
+		event := domain.NewEvent(domain.EventMediaDuplicate, map[string]interface{}{
+			"media_id":        media.ID,
+			"duplicate_of_id": candidate.MediaID,
+			"similarity":      similarity,
+		})
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			log.Printf("Failed to publish duplicate of media %s: %v", media.ID, err)
+		}
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/chromaprint"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFingerprinter returns a fixed fingerprint for every file
+type fakeFingerprinter struct {
+	fingerprint *chromaprint.Fingerprint
+	err         error
+}
+
+func (f *fakeFingerprinter) Fingerprint(ctx context.Context, src io.Reader) (*chromaprint.Fingerprint, error) {
+	if _, err := io.Copy(io.Discard, src); err != nil {
+		return nil, err
+	}
+	return f.fingerprint, f.err
+}
+
+// memoryFingerprintRepository keeps fingerprints in insertion order, which stands for upload order
+type memoryFingerprintRepository struct {
+	fingerprints []*domain.MediaFingerprint
+}
+
+func (r *memoryFingerprintRepository) Save(ctx context.Context, fingerprint *domain.MediaFingerprint) error {
+	for i, existing := range r.fingerprints {
+		if existing.MediaID == fingerprint.MediaID {
+			r.fingerprints[i] = fingerprint
+			return nil
+		}
+	}
+	r.fingerprints = append(r.fingerprints, fingerprint)
+	return nil
+}
+
+func (r *memoryFingerprintRepository) FindCandidates(ctx context.Context, tenantID, mediaID string, minDuration, maxDuration, limit int) ([]*domain.MediaFingerprint, error) {
+	var candidates []*domain.MediaFingerprint
+	for _, fingerprint := range r.fingerprints {
+		if fingerprint.TenantID == tenantID && fingerprint.MediaID != mediaID &&
+			fingerprint.Duration >= minDuration && fingerprint.Duration <= maxDuration && len(candidates) < limit {
+			candidates = append(candidates, fingerprint)
+		}
+	}
+	return candidates, nil
+}
+
+// audioFingerprint builds a fingerprint of n pseudo-random items
+func audioFingerprint(seed uint32, n int) []uint32 {
+	items := make([]uint32, n)
+	state := seed
+	for i := range items {
+		state = state*1664525 + 1013904223
+		items[i] = state
+	}
+	return items
+}
+
+// reencoded flips one bit in every fourth item, like a lossy re-encode does
+func reencoded(items []uint32) []uint32 {
+	copied := append([]uint32(nil), items...)
+	for i := 0; i < len(copied); i += 4 {
+		copied[i] ^= 1
+	}
+	return copied
+}
+
+func TestFingerprintService_DetectDuplicate(t *testing.T) {
+	original := audioFingerprint(1, 300)
+
+	tests := []struct {
+		name              string
+		existing          []*domain.MediaFingerprint
+		fingerprint       []uint32
+		duration          float64
+		expectedDuplicate string
+	}{
+		{
+			name: "links a re-encoded copy to the oldest match",
+			existing: []*domain.MediaFingerprint{
+				{MediaID: "original", TenantID: "acme", Duration: 1800, Fingerprint: original},
+				{MediaID: "earlier-copy", TenantID: "acme", Duration: 1800, Fingerprint: reencoded(original)},
+			},
+			fingerprint:       reencoded(original),
+			duration:          1795,
+			expectedDuplicate: "original",
+		},
+		{
+			name: "links a copy with a trimmed intro",
+			existing: []*domain.MediaFingerprint{
+				{MediaID: "original", TenantID: "acme", Duration: 1800, Fingerprint: original},
+			},
+			fingerprint:       original[40:],
+			duration:          1795,
+			expectedDuplicate: "original",
+		},
+		{
+			name: "ignores different audio",
+			existing: []*domain.MediaFingerprint{
+				{MediaID: "other", TenantID: "acme", Duration: 1800, Fingerprint: audioFingerprint(2, 300)},
+			},
+			fingerprint: original,
+			duration:    1800,
+		},
+		{
+			name: "ignores other tenants and durations",
+			existing: []*domain.MediaFingerprint{
+				{MediaID: "other-tenant", TenantID: "globex", Duration: 1800, Fingerprint: original},
+				{MediaID: "longer", TenantID: "acme", Duration: 3600, Fingerprint: original},
+			},
+			fingerprint: original,
+			duration:    1800,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			store := storage.NewLocalStorage(t.TempDir())
+			media := readyPodcast()
+			media.TenantID = "acme"
+			require.NoError(t, store.Put(ctx, media.StorageKey(), strings.NewReader("audio")))
+
+			repo := &memoryFingerprintRepository{fingerprints: tt.existing}
+			publisher := new(MockEventPublisher)
+			if tt.expectedDuplicate != "" {
+				publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+					return event.Type == domain.EventMediaDuplicate && event.Data["duplicate_of_id"] == tt.expectedDuplicate
+				})).Return(nil).Once()
+			}
+			fingerprinter := &fakeFingerprinter{fingerprint: &chromaprint.Fingerprint{Duration: tt.duration, Items: tt.fingerprint}}
+			service := NewFingerprintService(repo, store, fingerprinter, publisher, 0)
+
+			// When
+			err := service.DetectDuplicate(ctx, media)
+
+			// Then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDuplicate, media.DuplicateOfID)
+			saved, _ := repo.FindCandidates(ctx, "acme", "", 0, 10000, 10)
+			assert.Contains(t, saved, &domain.MediaFingerprint{MediaID: media.ID, TenantID: "acme", Duration: int(tt.duration), Fingerprint: tt.fingerprint})
+			publisher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFingerprintService_DetectDuplicate_FingerprintFails(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	store := storage.NewLocalStorage(t.TempDir())
+	media := readyPodcast()
+	require.NoError(t, store.Put(ctx, media.StorageKey(), strings.NewReader("audio")))
+	repo := &memoryFingerprintRepository{}
+	service := NewFingerprintService(repo, store, &fakeFingerprinter{err: errors.New("no audio stream")}, newMockEventPublisher(), 0)
+
+	// When
+	err := service.DetectDuplicate(ctx, media)
+
+	// Then nothing is saved
+	require.Error(t, err)
+	assert.Empty(t, repo.fingerprints)
+	assert.Empty(t, media.DuplicateOfID)
+}
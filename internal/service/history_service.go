@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// HistoryService records what users played and serves their listening history
+type HistoryService interface {
+	// RecordPlay adds the start of playback of a media item to the history of a user
+	RecordPlay(ctx context.Context, userID string, req *domain.PlayRequest, viewer domain.Viewer) (*domain.PlayEvent, error)
+
+	// GetHistory lists the plays of a user matching filter, most recent first, with their total
+	GetHistory(ctx context.Context, userID string, filter *domain.HistoryFilter, limit, offset int, viewer domain.Viewer) ([]*domain.HistoryEntry, int64, error)
+
+	// ClearHistory forgets everything a user played, including their playback progress
+	ClearHistory(ctx context.Context, userID string) error
+}
+
+// historyService implements HistoryService interface
+type historyService struct {
+	historyRepo  repository.HistoryRepository
+	progressRepo repository.ProgressRepository
+	mediaRepo    repository.MediaRepository
+	now          func() time.Time
+}
+
+// NewHistoryService creates a new listening history service
+func NewHistoryService(historyRepo repository.HistoryRepository, progressRepo repository.ProgressRepository, mediaRepo repository.MediaRepository) HistoryService {
+	return &historyService{
+		historyRepo:  historyRepo,
+		progressRepo: progressRepo,
+		mediaRepo:    mediaRepo,
+		now:          time.Now,
+	}
+}
+
+// RecordPlay adds the start of playback of a media item to the history of a user
+func (s *historyService) RecordPlay(ctx context.Context, userID string, req *domain.PlayRequest, viewer domain.Viewer) (*domain.PlayEvent, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, req.MediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !media.IsProcessed() {
+		return nil, domain.ErrMediaNotFound
+	}
+
+	event := &domain.PlayEvent{
+		ID:       uuid.New().String(),
+		UserID:   userID,
+		MediaID:  media.ID,
+		Type:     media.Type,
+		PlayedAt: s.now(),
+	}
+	if err := s.historyRepo.Create(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to record play: %w", err)
+	}
+
+	return event, nil
+}
+
+// GetHistory lists the plays of a user matching filter, most recent first, with their total.
+// Plays of media that is no longer available are listed without the media.
+func (s *historyService) GetHistory(ctx context.Context, userID string, filter *domain.HistoryFilter, limit, offset int, viewer domain.Viewer) ([]*domain.HistoryEntry, int64, error) {
+	events, err := s.historyRepo.GetByUser(ctx, userID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get history: %w", err)
+	}
+	total, err := s.historyRepo.CountByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count history: %w", err)
+	}
+
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.MediaID)
+	}
+	mediaByID := make(map[string]*domain.Media, len(ids))
+	if len(ids) > 0 {
+		mediaList, err := s.mediaRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to look up media: %w", err)
+		}
+		for _, media := range mediaList {
+			if media.IsProcessed() && media.IsVisibleTo(viewer) {
+				mediaByID[media.ID] = media
+			}
+		}
+	}
+
+	entries := make([]*domain.HistoryEntry, 0, len(events))
+	for _, event := range events {
+		entries = append(entries, &domain.HistoryEntry{
+			ID:       event.ID,
+			MediaID:  event.MediaID,
+			Media:    mediaByID[event.MediaID],
+			PlayedAt: event.PlayedAt,
+		})
+	}
+
+	return entries, total, nil
+}
+
+// ClearHistory forgets everything a user played, including their playback progress
+func (s *historyService) ClearHistory(ctx context.Context, userID string) error {
+	if err := s.historyRepo.DeleteByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to clear history: %w", err)
+	}
+	if err := s.progressRepo.DeleteByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to clear playback progress: %w", err)
+	}
+
+	return nil
+}
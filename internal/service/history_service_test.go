@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryHistoryRepository keeps play events in memory
+type memoryHistoryRepository struct {
+	events []domain.PlayEvent
+}
+
+func (r *memoryHistoryRepository) Create(ctx context.Context, event *domain.PlayEvent) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *memoryHistoryRepository) GetByUser(ctx context.Context, userID string, filter *domain.HistoryFilter, limit, offset int) ([]*domain.PlayEvent, error) {
+	matching := r.find(userID, filter)
+	if offset >= len(matching) {
+		return []*domain.PlayEvent{}, nil
+	}
+	matching = matching[offset:]
+	if len(matching) > limit {
+		matching = matching[:limit]
+	}
+	return matching, nil
+}
+
+func (r *memoryHistoryRepository) CountByUser(ctx context.Context, userID string, filter *domain.HistoryFilter) (int64, error) {
+	return int64(len(r.find(userID, filter))), nil
+}
+
+func (r *memoryHistoryRepository) DeleteByUser(ctx context.Context, userID string) error {
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.UserID != userID {
+			kept = append(kept, event)
+		}
+	}
+	r.events = kept
+	return nil
+}
+
+func (r *memoryHistoryRepository) find(userID string, filter *domain.HistoryFilter) []*domain.PlayEvent {
+	var result []*domain.PlayEvent
+	for _, event := range r.events {
+		if event.UserID != userID || (filter.Type != "" && event.Type != filter.Type) {
+			continue
+		}
+		if (filter.From != nil && event.PlayedAt.Before(*filter.From)) || (filter.End != nil && !event.PlayedAt.Before(*filter.End)) {
+			continue
+		}
+		result = append(result, &event)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PlayedAt.After(result[j].PlayedAt) })
+	return result
+}
+
+func TestHistoryService_RecordAndList(t *testing.T) {
+	// Given a user who played a podcast, a video and the podcast again
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	for _, media := range []*domain.Media{
+		{ID: "podcast", Type: domain.TypePodcast, Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+		{ID: "video", Type: domain.TypeVideo, Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+		{ID: "private", Type: domain.TypeVideo, Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+	} {
+		require.NoError(t, mediaRepo.Create(ctx, media))
+	}
+	s := NewHistoryService(&memoryHistoryRepository{}, newMemoryProgressRepository(), mediaRepo).(*historyService)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, id := range []string{"podcast", "video", "podcast"} {
+		now = now.Add(time.Minute)
+		s.now = func() time.Time { return now }
+		_, err := s.RecordPlay(ctx, "u1", &domain.PlayRequest{MediaID: id}, domain.Viewer{})
+		require.NoError(t, err)
+	}
+	_, err := s.RecordPlay(ctx, "u1", &domain.PlayRequest{MediaID: "private"}, domain.Viewer{})
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+
+	// When
+	all, total, err := s.GetHistory(ctx, "u1", &domain.HistoryFilter{}, 2, 0, domain.Viewer{})
+	require.NoError(t, err)
+	videos, _, err := s.GetHistory(ctx, "u1", &domain.HistoryFilter{Type: domain.TypeVideo}, 10, 0, domain.Viewer{})
+	require.NoError(t, err)
+
+	// Then every play is listed most recent first
+	assert.Equal(t, int64(3), total)
+	require.Len(t, all, 2)
+	assert.Equal(t, "podcast", all[0].Media.ID)
+	assert.Equal(t, "video", all[1].Media.ID)
+	require.Len(t, videos, 1)
+	assert.Equal(t, "video", videos[0].MediaID)
+
+	// And plays of media unpublished since are listed without the media
+	video, err := mediaRepo.GetByID(ctx, "video")
+	require.NoError(t, err)
+	video.Visibility = domain.VisibilityPrivate
+	require.NoError(t, mediaRepo.Update(ctx, video))
+	videos, _, err = s.GetHistory(ctx, "u1", &domain.HistoryFilter{Type: domain.TypeVideo}, 10, 0, domain.Viewer{})
+	require.NoError(t, err)
+	require.Len(t, videos, 1)
+	assert.Nil(t, videos[0].Media)
+}
+
+func TestHistoryService_ClearHistory(t *testing.T) {
+	// Given a user with history and playback progress
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", Duration: 600, Status: domain.StatusReady, Visibility: domain.VisibilityPublic}))
+	historyRepo, progressRepo := &memoryHistoryRepository{}, newMemoryProgressRepository()
+	historyService := NewHistoryService(historyRepo, progressRepo, mediaRepo)
+	progressService := NewProgressService(progressRepo, mediaRepo)
+	for _, userID := range []string{"u1", "u2"} {
+		_, err := historyService.RecordPlay(ctx, userID, &domain.PlayRequest{MediaID: "m1"}, domain.Viewer{})
+		require.NoError(t, err)
+		_, err = progressService.RecordProgress(ctx, userID, "m1", &domain.ProgressRequest{PositionSeconds: 60}, domain.Viewer{})
+		require.NoError(t, err)
+	}
+
+	// When
+	require.NoError(t, historyService.ClearHistory(ctx, "u1"))
+
+	// Then both the plays and the progress of the user are gone
+	entries, total, err := historyService.GetHistory(ctx, "u1", &domain.HistoryFilter{}, 10, 0, domain.Viewer{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Zero(t, total)
+	items, err := progressService.GetContinueListening(ctx, "u1", 10, domain.Viewer{})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	// And other users keep theirs
+	_, total, err = historyService.GetHistory(ctx, "u2", &domain.HistoryFilter{}, 10, 0, domain.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// LicenseEnforcerOptions configures license window enforcement
+type LicenseEnforcerOptions struct {
+	NoticePeriod time.Duration // how long before a license ends owners are warned, 0 disables notices
+	Interval     time.Duration // wait between sweeps
+	BatchSize    int           // media handled per sweep and kind
+}
+
+// LicenseSweepResult reports what a sweep did
+type LicenseSweepResult struct {
+	Notified    int `json:"notified"`
+	Unpublished int `json:"unpublished"`
+}
+
+// LicenseEnforcer unpublishes media whose license expired and warns owners beforehand
+type LicenseEnforcer interface {
+	// Sweep handles the licenses that ended or are about to end
+	Sweep(ctx context.Context) (*LicenseSweepResult, error)
+
+	// Run sweeps periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// licenseEnforcer implements LicenseEnforcer interface
+type licenseEnforcer struct {
+	mediaRepo repository.MediaRepository
+	publisher EventPublisher
+	options   LicenseEnforcerOptions
+	now       func() time.Time
+}
+
+// NewLicenseEnforcer creates a new license enforcer
+func NewLicenseEnforcer(mediaRepo repository.MediaRepository, publisher EventPublisher, options LicenseEnforcerOptions) LicenseEnforcer {
+	if options.Interval <= 0 {
+		options.Interval = time.Hour
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+
+	return &licenseEnforcer{
+		mediaRepo: mediaRepo,
+		publisher: publisher,
+		options:   options,
+		now:       time.Now,
+	}
+}
+
+// Sweep unpublishes media with an expired license, then warns the owners of
+// media whose license ends within the notice period
+func (e *licenseEnforcer) Sweep(ctx context.Context) (*LicenseSweepResult, error) {
+	now := e.now()
+	result := &LicenseSweepResult{}
+
+	expired, err := e.mediaRepo.GetExpiredLicenses(ctx, now, e.options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load expired licenses: %w", err)
+	}
+	for _, media := range expired {
+		// One failing item must not keep the others published
+		if err := e.unpublish(ctx, media); err != nil {
+			log.Printf("Failed to unpublish media %s with expired license: %v", media.ID, err)
+			continue
+		}
+		result.Unpublished++
+	}
+
+	if e.options.NoticePeriod <= 0 {
+		return result, nil
+	}
+
+	expiring, err := e.mediaRepo.GetLicensesExpiringBefore(ctx, now, now.Add(e.options.NoticePeriod), e.options.BatchSize)
+	if err != nil {
+		return result, fmt.Errorf("failed to load expiring licenses: %w", err)
+	}
+	for _, media := range expiring {
+		if err := e.notifyExpiring(ctx, media, now); err != nil {
+			log.Printf("Failed to send license expiry notice for media %s: %v", media.ID, err)
+			continue
+		}
+		result.Notified++
+	}
+
+	return result, nil
+}
+
+// Run sweeps periodically until ctx is cancelled
+func (e *licenseEnforcer) Run(ctx context.Context) {
+	for {
+		result, err := e.Sweep(ctx)
+		if err != nil {
+			log.Printf("License sweep failed: %v", err)
+		} else if result.Unpublished > 0 || result.Notified > 0 {
+			log.Printf("License sweep unpublished %d and notified %d media", result.Unpublished, result.Notified)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.options.Interval):
+		}
+	}
+}
+
+// unpublish makes media private and announces it so search drops it
+func (e *licenseEnforcer) unpublish(ctx context.Context, media *domain.Media) error {
+	if err := e.mediaRepo.UpdateVisibility(ctx, media.ID, domain.VisibilityPrivate); err != nil {
+		return err
+	}
+	media.Visibility = domain.VisibilityPrivate
+
+	event := domain.NewEvent(domain.EventMediaUnpublished, map[string]interface{}{
+		"media_id":        media.ID,
+		"reason":          "license_expired",
+		"license_ends_at": media.License.EndsAt.Format(time.RFC3339),
+	})
+	if err := e.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish unpublish event for media %s: %v", media.ID, err)
+	}
+
+	return nil
+}
+
+// notifyExpiring announces the end of a license and records the notice so it is sent once
+func (e *licenseEnforcer) notifyExpiring(ctx context.Context, media *domain.Media, now time.Time) error {
+	event := domain.NewEvent(domain.EventLicenseExpiring, map[string]interface{}{
+		"media_id":        media.ID,
+		"license_ends_at": media.License.EndsAt.Format(time.RFC3339),
+	})
+	if err := e.publisher.Publish(ctx, event); err != nil {
+		return err
+	}
+
+	media.License.ExpiryNotifiedAt = &now
+	return e.mediaRepo.UpdateLicense(ctx, media.ID, media.License)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLicenseEnforcer(mediaRepo *MockMediaRepository, publisher *MockEventPublisher, now time.Time, notice time.Duration) LicenseEnforcer {
+	enforcer := NewLicenseEnforcer(mediaRepo, publisher, LicenseEnforcerOptions{NoticePeriod: notice, BatchSize: 10}).(*licenseEnforcer)
+	enforcer.now = func() time.Time { return now }
+	return enforcer
+}
+
+func TestLicenseEnforcer_Sweep(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	endedAt := now.Add(-time.Hour)
+	endsAt := now.Add(48 * time.Hour)
+
+	tests := []struct {
+		name        string
+		notice      time.Duration
+		setupMock   func(*MockMediaRepository, *MockEventPublisher)
+		expected    *LicenseSweepResult
+		expectError bool
+	}{
+		{
+			name:   "unpublishes expired and warns about expiring licenses",
+			notice: 7 * 24 * time.Hour,
+			setupMock: func(mediaRepo *MockMediaRepository, publisher *MockEventPublisher) {
+				mediaRepo.On("GetExpiredLicenses", mock.Anything, now, 10).Return([]*domain.Media{
+					{ID: "expired", Visibility: domain.VisibilityPublic, License: domain.License{EndsAt: &endedAt}},
+				}, nil)
+				mediaRepo.On("UpdateVisibility", mock.Anything, "expired", domain.VisibilityPrivate).Return(nil)
+				publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+					return event.Type == domain.EventMediaUnpublished && event.Data["media_id"] == "expired" &&
+						event.Data["reason"] == "license_expired"
+				})).Return(nil)
+
+				mediaRepo.On("GetLicensesExpiringBefore", mock.Anything, now, now.Add(7*24*time.Hour), 10).Return([]*domain.Media{
+					{ID: "expiring", License: domain.License{Source: "Studio", EndsAt: &endsAt}},
+				}, nil)
+				publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+					return event.Type == domain.EventLicenseExpiring && event.Data["media_id"] == "expiring"
+				})).Return(nil)
+				mediaRepo.On("UpdateLicense", mock.Anything, "expiring", domain.License{
+					Source: "Studio", EndsAt: &endsAt, ExpiryNotifiedAt: &now,
+				}).Return(nil)
+			},
+			expected: &LicenseSweepResult{Unpublished: 1, Notified: 1},
+		},
+		{
+			name:   "notices disabled",
+			notice: 0,
+			setupMock: func(mediaRepo *MockMediaRepository, publisher *MockEventPublisher) {
+				mediaRepo.On("GetExpiredLicenses", mock.Anything, now, 10).Return([]*domain.Media{}, nil)
+			},
+			expected: &LicenseSweepResult{},
+		},
+		{
+			name:   "failed unpublish keeps going",
+			notice: 0,
+			setupMock: func(mediaRepo *MockMediaRepository, publisher *MockEventPublisher) {
+				mediaRepo.On("GetExpiredLicenses", mock.Anything, now, 10).Return([]*domain.Media{
+					{ID: "gone", License: domain.License{EndsAt: &endedAt}},
+					{ID: "expired", License: domain.License{EndsAt: &endedAt}},
+				}, nil)
+				mediaRepo.On("UpdateVisibility", mock.Anything, "gone", domain.VisibilityPrivate).Return(domain.ErrMediaNotFound)
+				mediaRepo.On("UpdateVisibility", mock.Anything, "expired", domain.VisibilityPrivate).Return(nil)
+				publisher.On("Publish", mock.Anything, mock.Anything).Return(nil)
+			},
+			expected: &LicenseSweepResult{Unpublished: 1},
+		},
+		{
+			name:   "repository error",
+			notice: 0,
+			setupMock: func(mediaRepo *MockMediaRepository, publisher *MockEventPublisher) {
+				mediaRepo.On("GetExpiredLicenses", mock.Anything, now, 10).Return(nil, errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mediaRepo := new(MockMediaRepository)
+			publisher := new(MockEventPublisher)
+			tt.setupMock(mediaRepo, publisher)
+			enforcer := newTestLicenseEnforcer(mediaRepo, publisher, now, tt.notice)
+
+			// When
+			result, err := enforcer.Sweep(context.Background())
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+
+			mediaRepo.AssertExpectations(t)
+			publisher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLicenseEnforcer_Sweep_InMemory(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	endedAt := now.Add(-time.Hour)
+	endsAt := now.Add(48 * time.Hour)
+
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "expired", Status: domain.StatusReady, License: domain.License{EndsAt: &endedAt}}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "expiring", Status: domain.StatusReady, License: domain.License{EndsAt: &endsAt}}))
+
+	enforcer := NewLicenseEnforcer(mediaRepo, newMockEventPublisher(), LicenseEnforcerOptions{NoticePeriod: 7 * 24 * time.Hour}).(*licenseEnforcer)
+	enforcer.now = func() time.Time { return now }
+
+	// When
+	first, err := enforcer.Sweep(ctx)
+	require.NoError(t, err)
+	second, err := enforcer.Sweep(ctx)
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, &LicenseSweepResult{Notified: 1, Unpublished: 1}, first)
+	assert.Equal(t, &LicenseSweepResult{}, second, "handled media is not swept again")
+
+	expired, err := mediaRepo.GetByID(ctx, "expired")
+	require.NoError(t, err)
+	assert.Equal(t, domain.VisibilityPrivate, expired.Visibility)
+}
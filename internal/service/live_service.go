@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// LiveService manages live streams, from their registration to the archiving of
+// their recording as VOD media
+type LiveService interface {
+	// RegisterLiveStream creates a live media item and its stream, returning the
+	// ingest URL and stream key the encoder publishes with
+	RegisterLiveStream(ctx context.Context, req *domain.LiveStreamRequest) (*domain.RegisteredLiveStream, error)
+
+	// GetLiveStream returns the live stream of a media item
+	GetLiveStream(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.LiveStream, error)
+
+	// StartIngest marks the stream of a stream key live when its encoder starts
+	// publishing. Streams that ended refuse to go live again.
+	StartIngest(ctx context.Context, streamKey string) (*domain.LiveStream, error)
+
+	// EndIngest ends the stream of a stream key when its encoder stops publishing.
+	// A recording is moved to the upload of the media, which becomes a video or
+	// podcast and is handed over to processing like a confirmed upload.
+	EndIngest(ctx context.Context, callback *domain.LiveIngestCallback) (*domain.LiveStream, error)
+}
+
+// liveService implements LiveService interface
+type liveService struct {
+	liveRepo     repository.LiveStreamRepository
+	mediaRepo    repository.MediaRepository
+	mediaService MediaService
+	storage      storage.Storage
+	publisher    EventPublisher
+	ingestURL    string
+	now          func() time.Time
+}
+
+// NewLiveService creates a new live service whose encoders publish to ingestURL
+func NewLiveService(liveRepo repository.LiveStreamRepository, mediaRepo repository.MediaRepository, mediaService MediaService, store storage.Storage, publisher EventPublisher, ingestURL string) LiveService {
+	if publisher == nil {
+		publisher = NewLogEventPublisher()
+	}
+
+	return &liveService{
+		liveRepo:     liveRepo,
+		mediaRepo:    mediaRepo,
+		mediaService: mediaService,
+		storage:      store,
+		publisher:    publisher,
+		ingestURL:    strings.TrimSuffix(ingestURL, "/"),
+		now:          time.Now,
+	}
+}
+
+// RegisterLiveStream creates a live media item and its stream
+func (s *liveService) RegisterLiveStream(ctx context.Context, req *domain.LiveStreamRequest) (*domain.RegisteredLiveStream, error) {
+	req.Normalize()
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Live stream validation failed", errs.Error())
+	}
+
+	streamKey, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate stream key: %w", err)
+	}
+
+	media := req.ToMedia(uuid.New().String())
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		return nil, fmt.Errorf("failed to create live media: %w", err)
+	}
+
+	stream := &domain.LiveStream{
+		MediaID:       media.ID,
+		Status:        domain.LiveScheduled,
+		StreamKeyHash: domain.HashStreamKey(streamKey),
+		ArchiveType:   req.ArchiveType,
+		ScheduledAt:   req.ScheduledAt,
+		CreatedAt:     s.now().UTC(),
+	}
+	if err := s.liveRepo.Create(ctx, stream); err != nil {
+		return nil, fmt.Errorf("failed to create live stream: %w", err)
+	}
+
+	return &domain.RegisteredLiveStream{
+		LiveStream: stream,
+		Media:      media,
+		IngestURL:  s.ingestURL,
+		StreamKey:  streamKey,
+	}, nil
+}
+
+// GetLiveStream returns the live stream of a media item
+func (s *liveService) GetLiveStream(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.LiveStream, error) {
+	if _, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer); err != nil {
+		return nil, err
+	}
+
+	return s.liveRepo.GetByMediaID(ctx, mediaID)
+}
+
+// StartIngest marks the stream of a stream key live
+func (s *liveService) StartIngest(ctx context.Context, streamKey string) (*domain.LiveStream, error) {
+	stream, err := s.liveRepo.GetByStreamKeyHash(ctx, domain.HashStreamKey(streamKey))
+	if err != nil {
+		return nil, err
+	}
+
+	switch stream.Status {
+	case domain.LiveOnAir:
+		// The encoder reconnected
+		return stream, nil
+	case domain.LiveEnded, domain.LiveArchived:
+		return nil, domain.ErrLiveStreamEnded
+	}
+
+	startedAt := s.now().UTC()
+	stream.Status = domain.LiveOnAir
+	stream.StartedAt = &startedAt
+	ok, err := s.liveRepo.Transition(ctx, stream, domain.LiveScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start live stream: %w", err)
+	}
+	if !ok {
+		// Another callback moved the stream on first
+		return s.currentStream(ctx, stream.MediaID, domain.LiveOnAir)
+	}
+
+	s.publishLiveEvent(ctx, domain.EventLiveStarted, stream, nil)
+	return stream, nil
+}
+
+// EndIngest ends the stream of a stream key and archives its recording
+func (s *liveService) EndIngest(ctx context.Context, callback *domain.LiveIngestCallback) (*domain.LiveStream, error) {
+	stream, err := s.liveRepo.GetByStreamKeyHash(ctx, domain.HashStreamKey(callback.StreamKey))
+	if err != nil {
+		return nil, err
+	}
+	if stream.Status == domain.LiveEnded || stream.Status == domain.LiveArchived {
+		return nil, domain.ErrLiveStreamEnded
+	}
+
+	recording := strings.TrimSpace(callback.Recording)
+	var media *domain.Media
+	if recording != "" {
+		media, err = s.archiveRecording(ctx, stream, recording)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	endedAt := s.now().UTC()
+	stream.EndedAt = &endedAt
+	stream.Status = domain.LiveEnded
+	if media != nil {
+		stream.Status = domain.LiveArchived
+		stream.RecordingKey = recording
+	}
+	ok, err := s.liveRepo.Transition(ctx, stream, domain.LiveScheduled, domain.LiveOnAir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to end live stream: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrLiveStreamEnded
+	}
+	s.publishLiveEvent(ctx, domain.EventLiveEnded, stream, map[string]interface{}{
+		"archived": media != nil,
+	})
+
+	// The recording is processed like a confirmed upload
+	if media != nil {
+		if err := s.mediaService.ConfirmUpload(ctx, media.ID); err != nil {
+			return nil, fmt.Errorf("failed to hand recording over to processing: %w", err)
+		}
+	}
+
+	return stream, nil
+}
+
+// archiveRecording moves the recording of a stream to the upload of its media
+// and turns the media into the archive type of the stream
+func (s *liveService) archiveRecording(ctx context.Context, stream *domain.LiveStream, recording string) (*domain.Media, error) {
+	format := domain.FormatFromFilename(recording)
+	if !domain.IsValidFormat(stream.ArchiveType, format) {
+		return nil, domain.NewBusinessError("INVALID_RECORDING",
+			fmt.Sprintf("Recordings of %s streams must be one of %s", stream.ArchiveType, strings.Join(domain.FormatsFor(stream.ArchiveType), ", ")))
+	}
+
+	media, err := s.mediaRepo.GetByID(ctx, stream.MediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := media.ID + "." + format
+	object, err := s.storage.Get(ctx, recording)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, domain.NewBusinessError("RECORDING_NOT_FOUND", "The recording of the stream is not in storage")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	size := object.Size
+	if recording != key {
+		err = s.storage.Put(ctx, key, object.Body)
+		object.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to store recording: %w", err)
+		}
+		if err := s.storage.Delete(ctx, recording); err != nil {
+			log.Printf("Failed to delete recording %s of live media %s: %v", recording, media.ID, err)
+		}
+	} else {
+		object.Body.Close()
+	}
+
+	media.Type = stream.ArchiveType
+	media.Format = format
+	media.FilePath = domain.UploadPathPrefix + key
+	media.FileSize = size
+	media.UpdatedAt = s.now()
+	if err := s.mediaRepo.Update(ctx, media); err != nil {
+		return nil, fmt.Errorf("failed to archive live media: %w", err)
+	}
+
+	return media, nil
+}
+
+// currentStream reloads a stream, reporting it as ended unless it is in status
+func (s *liveService) currentStream(ctx context.Context, mediaID string, status domain.LiveStatus) (*domain.LiveStream, error) {
+	stream, err := s.liveRepo.GetByMediaID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if stream.Status != status {
+		return nil, domain.ErrLiveStreamEnded
+	}
+	return stream, nil
+}
+
+// publishLiveEvent announces a change of the status of a live stream
+func (s *liveService) publishLiveEvent(ctx context.Context, eventType string, stream *domain.LiveStream, extra map[string]interface{}) {
+	data := map[string]interface{}{
+		"media_id": stream.MediaID,
+		"status":   string(stream.Status),
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+	if err := s.publisher.Publish(ctx, domain.NewEvent(eventType, data)); err != nil {
+		log.Printf("Failed to publish %s of media %s: %v", eventType, stream.MediaID, err)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLiveStreamRepository keeps live streams in memory
+type memoryLiveStreamRepository struct {
+	streams map[string]*domain.LiveStream
+}
+
+func newMemoryLiveStreamRepository() *memoryLiveStreamRepository {
+	return &memoryLiveStreamRepository{streams: make(map[string]*domain.LiveStream)}
+}
+
+func (r *memoryLiveStreamRepository) Create(ctx context.Context, stream *domain.LiveStream) error {
+	stored := *stream
+	r.streams[stream.MediaID] = &stored
+	return nil
+}
+
+func (r *memoryLiveStreamRepository) GetByMediaID(ctx context.Context, mediaID string) (*domain.LiveStream, error) {
+	stream, ok := r.streams[mediaID]
+	if !ok {
+		return nil, domain.ErrLiveStreamNotFound
+	}
+	found := *stream
+	return &found, nil
+}
+
+func (r *memoryLiveStreamRepository) GetByStreamKeyHash(ctx context.Context, keyHash string) (*domain.LiveStream, error) {
+	for _, stream := range r.streams {
+		if stream.StreamKeyHash == keyHash {
+			found := *stream
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrLiveStreamNotFound
+}
+
+func (r *memoryLiveStreamRepository) Transition(ctx context.Context, stream *domain.LiveStream, from ...domain.LiveStatus) (bool, error) {
+	stored, ok := r.streams[stream.MediaID]
+	if !ok {
+		return false, nil
+	}
+	for _, status := range from {
+		if stored.Status == status {
+			updated := *stream
+			r.streams[stream.MediaID] = &updated
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// confirmingMediaService records the uploads confirmed through it
+type confirmingMediaService struct {
+	MediaService
+	confirmed []string
+}
+
+func (s *confirmingMediaService) ConfirmUpload(ctx context.Context, mediaID string) error {
+	s.confirmed = append(s.confirmed, mediaID)
+	return nil
+}
+
+func TestLiveService_StreamIsArchived(t *testing.T) {
+	// Given a registered live stream
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	liveRepo := newMemoryLiveStreamRepository()
+	store := storage.NewLocalStorage(t.TempDir())
+	mediaService := &confirmingMediaService{}
+	publisher := newMockEventPublisher()
+	service := NewLiveService(liveRepo, mediaRepo, mediaService, store, publisher, "rtmp://ingest.example.com/live/")
+
+	registered, err := service.RegisterLiveStream(ctx, &domain.LiveStreamRequest{Title: " Election night "})
+	require.NoError(t, err)
+	assert.Equal(t, "rtmp://ingest.example.com/live", registered.IngestURL)
+	assert.NotEmpty(t, registered.StreamKey)
+	assert.Equal(t, domain.LiveScheduled, registered.Status)
+	assert.Equal(t, domain.TypeLive, registered.Media.Type)
+	assert.Equal(t, "Election night", registered.Media.Title)
+	mediaID := registered.Media.ID
+
+	// When the encoder starts publishing, and reconnects
+	stream, err := service.StartIngest(ctx, registered.StreamKey)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveOnAir, stream.Status)
+	_, err = service.StartIngest(ctx, registered.StreamKey)
+	require.NoError(t, err)
+
+	// Then the stream is live
+	stream, err = service.GetLiveStream(ctx, mediaID, domain.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveOnAir, stream.Status)
+	require.NotNil(t, stream.StartedAt)
+
+	// When it ends with its recording
+	require.NoError(t, store.Put(ctx, "recordings/election.mp4", strings.NewReader("recording")))
+	stream, err = service.EndIngest(ctx, &domain.LiveIngestCallback{StreamKey: registered.StreamKey, Recording: "recordings/election.mp4"})
+
+	// Then the recording becomes the upload of a video handed over to processing
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveArchived, stream.Status)
+	media, err := mediaRepo.GetByID(ctx, mediaID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TypeVideo, media.Type)
+	assert.Equal(t, "mp4", media.Format)
+	assert.Equal(t, int64(len("recording")), media.FileSize)
+	exists, _ := store.Exists(ctx, media.StorageKey())
+	assert.True(t, exists)
+	exists, _ = store.Exists(ctx, "recordings/election.mp4")
+	assert.False(t, exists)
+	assert.Equal(t, []string{mediaID}, mediaService.confirmed)
+	publisher.AssertNumberOfCalls(t, "Publish", 2)
+
+	// And the stream cannot go live again
+	_, err = service.StartIngest(ctx, registered.StreamKey)
+	assert.ErrorIs(t, err, domain.ErrLiveStreamEnded)
+}
+
+func TestLiveService_EndIngest_Errors(t *testing.T) {
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	store := storage.NewLocalStorage(t.TempDir())
+	service := NewLiveService(newMemoryLiveStreamRepository(), mediaRepo, &confirmingMediaService{}, store, nil, "rtmp://ingest.example.com/live")
+	registered, err := service.RegisterLiveStream(ctx, &domain.LiveStreamRequest{Title: "Radio hour", ArchiveType: domain.TypePodcast})
+	require.NoError(t, err)
+	_, err = service.StartIngest(ctx, registered.StreamKey)
+	require.NoError(t, err)
+
+	t.Run("unknown stream key", func(t *testing.T) {
+		_, err := service.StartIngest(ctx, "guessed")
+		assert.ErrorIs(t, err, domain.ErrLiveStreamNotFound)
+	})
+
+	t.Run("recording of another type", func(t *testing.T) {
+		_, err := service.EndIngest(ctx, &domain.LiveIngestCallback{StreamKey: registered.StreamKey, Recording: "radio.mp4"})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "INVALID_RECORDING", businessErr.Code)
+	})
+
+	t.Run("missing recording", func(t *testing.T) {
+		_, err := service.EndIngest(ctx, &domain.LiveIngestCallback{StreamKey: registered.StreamKey, Recording: "radio.mp3"})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "RECORDING_NOT_FOUND", businessErr.Code)
+	})
+
+	t.Run("ends without a recording", func(t *testing.T) {
+		stream, err := service.EndIngest(ctx, &domain.LiveIngestCallback{StreamKey: registered.StreamKey})
+		require.NoError(t, err)
+		assert.Equal(t, domain.LiveEnded, stream.Status)
+
+		media, err := mediaRepo.GetByID(ctx, registered.Media.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TypeLive, media.Type)
+	})
+}
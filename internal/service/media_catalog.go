@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/httpclient"
+)
+
+// MediaCatalog resolves media IDs to their authoritative records
+type MediaCatalog interface {
+	// GetMediaByIDs returns the existing, visible media among ids
+	GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error)
+}
+
+// FreshMediaCatalog is a MediaCatalog that can bypass its cache
+type FreshMediaCatalog interface {
+	MediaCatalog
+
+	// GetFreshMediaByIDs returns the media among ids from the source, refreshing the cache
+	GetFreshMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error)
+}
+
+// cmsMediaCatalog implements MediaCatalog over the CMS batch endpoint
+type cmsMediaCatalog struct {
+	cmsClient *httpclient.Client
+}
+
+// NewCMSMediaCatalog creates a media catalog backed by the CMS service
+func NewCMSMediaCatalog(cmsClient *httpclient.Client) MediaCatalog {
+	return &cmsMediaCatalog{
+		cmsClient: cmsClient,
+	}
+}
+
+// GetMediaByIDs fetches the media among ids from CMS, MaxPageSize at a time
+func (c *cmsMediaCatalog) GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	mediaList := make([]*domain.Media, 0, len(ids))
+
+	for start := 0; start < len(ids); start += domain.MaxPageSize {
+		end := min(start+domain.MaxPageSize, len(ids))
+
+		body, err := c.cmsClient.Get(ctx, "/api/v1/media/batch?ids="+url.QueryEscape(strings.Join(ids[start:end], ",")))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch media from CMS service: %w", err)
+		}
+
+		var batch struct {
+			Items []*domain.Media `json:"items"`
+		}
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil, fmt.Errorf("failed to parse CMS response: %w", err)
+		}
+		mediaList = append(mediaList, batch.Items...)
+	}
+
+	return mediaList, nil
+}
+
+// repositoryMediaCatalog implements MediaCatalog over the media database
+type repositoryMediaCatalog struct {
+	mediaRepo repository.MediaRepository
+}
+
+// NewRepositoryMediaCatalog creates a media catalog reading the media database directly
+func NewRepositoryMediaCatalog(mediaRepo repository.MediaRepository) MediaCatalog {
+	return &repositoryMediaCatalog{
+		mediaRepo: mediaRepo,
+	}
+}
+
+// GetMediaByIDs returns the existing media among ids
+func (c *repositoryMediaCatalog) GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	return c.mediaRepo.GetByIDs(ctx, ids)
+}
+
+// cachedMedia is a cached catalog lookup, media is nil for missing media
+type cachedMedia struct {
+	media     *domain.Media
+	expiresAt time.Time
+}
+
+// CachedMediaCatalog caches the lookups of a MediaCatalog, including misses,
+// for a fixed time
+type CachedMediaCatalog struct {
+	next MediaCatalog
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedMedia
+}
+
+// NewCachedMediaCatalog creates a catalog serving lookups of next from a cache for ttl
+func NewCachedMediaCatalog(next MediaCatalog, ttl time.Duration) *CachedMediaCatalog {
+	return &CachedMediaCatalog{
+		next:    next,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedMedia),
+	}
+}
+
+// GetMediaByIDs returns the cached media among ids, fetching the others
+func (c *CachedMediaCatalog) GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	mediaList := make([]*domain.Media, 0, len(ids))
+	var misses []string
+
+	c.mu.Lock()
+	now := c.now()
+	for _, id := range ids {
+		entry, ok := c.entries[id]
+		switch {
+		case !ok || now.After(entry.expiresAt):
+			misses = append(misses, id)
+		case entry.media != nil:
+			mediaList = append(mediaList, entry.media)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(misses) == 0 {
+		return mediaList, nil
+	}
+	fetched, err := c.GetFreshMediaByIDs(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	return append(mediaList, fetched...), nil
+}
+
+// GetFreshMediaByIDs returns the media among ids from the source, refreshing the cache
+func (c *CachedMediaCatalog) GetFreshMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	mediaList, err := c.next.GetMediaByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	for id, entry := range c.entries {
+		if c.now().After(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	for _, id := range ids {
+		c.entries[id] = cachedMedia{expiresAt: expiresAt}
+	}
+	for _, media := range mediaList {
+		c.entries[media.ID] = cachedMedia{media: media, expiresAt: expiresAt}
+	}
+
+	return mediaList, nil
+}
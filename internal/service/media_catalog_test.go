@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingMediaCatalog serves fixed media and records the IDs it was asked for
+type countingMediaCatalog struct {
+	media     map[string]*domain.Media
+	requested [][]string
+}
+
+func (c *countingMediaCatalog) GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	c.requested = append(c.requested, ids)
+	var result []*domain.Media
+	for _, id := range ids {
+		if media, ok := c.media[id]; ok {
+			result = append(result, media)
+		}
+	}
+	return result, nil
+}
+
+func TestCachedMediaCatalog(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	source := &countingMediaCatalog{media: map[string]*domain.Media{"m1": {ID: "m1"}}}
+	catalog := NewCachedMediaCatalog(source, time.Minute)
+	now := time.Now()
+	catalog.now = func() time.Time { return now }
+
+	// When the same media, one of them missing, is looked up twice
+	first, err := catalog.GetMediaByIDs(ctx, []string{"m1", "gone"})
+	require.NoError(t, err)
+	second, err := catalog.GetMediaByIDs(ctx, []string{"m1", "gone"})
+	require.NoError(t, err)
+
+	// Then the second lookup, misses included, is served from the cache
+	assert.Len(t, first, 1)
+	assert.Len(t, second, 1)
+	assert.Len(t, source.requested, 1)
+
+	// And expired entries and fresh lookups go to the source
+	now = now.Add(2 * time.Minute)
+	_, err = catalog.GetMediaByIDs(ctx, []string{"m1"})
+	require.NoError(t, err)
+	_, err = catalog.GetFreshMediaByIDs(ctx, []string{"m1"})
+	require.NoError(t, err)
+	assert.Len(t, source.requested, 3)
+}
@@ -2,33 +2,53 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"thamaniyah/internal/domain"
 	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/messagequeue"
 )
 
-// MediaEventHandler handles media events for search indexing
+// MediaEventHandler handles media events for search indexing. Whether media
+// belongs in the index is decided by Media.CanBeSearched alone.
 type MediaEventHandler struct {
 	searchRepo repository.SearchRepository
+	catalog    MediaCatalog
+	contexts   *SearchContextResolver
 }
 
-// NewMediaEventHandler creates a new media event handler
-func NewMediaEventHandler(searchRepo repository.SearchRepository) *MediaEventHandler {
+// NewMediaEventHandler creates a new media event handler. Events that only
+// carry a media ID are resolved through catalog. Episodes are indexed with the
+// title, category and people of their show resolved by contexts, which may be nil.
+func NewMediaEventHandler(searchRepo repository.SearchRepository, catalog MediaCatalog, contexts *SearchContextResolver) *MediaEventHandler {
 	return &MediaEventHandler{
 		searchRepo: searchRepo,
+		catalog:    catalog,
+		contexts:   contexts,
 	}
 }
 
 // HandleMediaCreated handles media creation events
 func (h *MediaEventHandler) HandleMediaCreated(ctx context.Context, media *domain.Media) error {
+	if !media.CanBeSearched() {
+		log.Printf("Skipping indexing of unsearchable media: %s", media.ID)
+		return nil
+	}
 	log.Printf("Indexing newly created media: %s", media.ID)
+	h.contexts.Resolve(ctx, []*domain.Media{media})
 	return h.searchRepo.IndexMedia(ctx, media)
 }
 
 // HandleMediaUpdated handles media update events
 func (h *MediaEventHandler) HandleMediaUpdated(ctx context.Context, media *domain.Media) error {
+	// Media that stopped being searchable must disappear from search
+	if !media.CanBeSearched() {
+		log.Printf("Removing unsearchable media from index: %s", media.ID)
+		return h.searchRepo.RemoveFromIndex(ctx, media.ID)
+	}
 	log.Printf("Reindexing updated media: %s", media.ID)
+	h.contexts.Resolve(ctx, []*domain.Media{media})
 	return h.searchRepo.IndexMedia(ctx, media)
 }
 
@@ -37,3 +57,84 @@ func (h *MediaEventHandler) HandleMediaDeleted(ctx context.Context, mediaID stri
 	log.Printf("Removing deleted media from index: %s", mediaID)
 	return h.searchRepo.RemoveFromIndex(ctx, mediaID)
 }
+
+// HandleEvent keeps the index in line with a domain event: deleted and purged
+// media is removed, and media whose status, visibility or metadata changed is
+// looked up and indexed or removed as it is searchable or not
+func (h *MediaEventHandler) HandleEvent(ctx context.Context, event *domain.Event) error {
+	mediaID, _ := event.Data["media_id"].(string)
+	if mediaID == "" {
+		return nil
+	}
+
+	switch event.Type {
+	case domain.EventMediaDeleted, domain.EventMediaPurged:
+		return h.HandleMediaDeleted(ctx, mediaID)
+	case domain.EventMediaStatusChanged, domain.EventMediaUnpublished, domain.EventMediaUpdated:
+		return h.reevaluate(ctx, mediaID)
+	}
+
+	return nil
+}
+
+// HandleIndexEvent keeps the index in line with a media index event from the
+// queue. Created and updated media is looked up, as it may have changed again
+// since the event was published, and removed when it no longer exists.
+func (h *MediaEventHandler) HandleIndexEvent(ctx context.Context, event *messagequeue.MediaIndexEvent) error {
+	if event.MediaID == "" {
+		return nil
+	}
+
+	switch event.Action {
+	case messagequeue.MediaIndexDeleted:
+		return h.HandleMediaDeleted(ctx, event.MediaID)
+	case messagequeue.MediaIndexCreated, messagequeue.MediaIndexUpdated:
+		media, err := h.lookup(ctx, event.MediaID)
+		if err != nil {
+			return err
+		}
+		if media == nil {
+			return h.HandleMediaDeleted(ctx, event.MediaID)
+		}
+		if event.Action == messagequeue.MediaIndexCreated {
+			return h.HandleMediaCreated(ctx, media)
+		}
+		return h.HandleMediaUpdated(ctx, media)
+	}
+
+	log.Printf("Ignoring media index event with unknown action %q: %s", event.Action, event.MediaID)
+	return nil
+}
+
+// reevaluate indexes the current state of media, removing it when it no longer exists
+func (h *MediaEventHandler) reevaluate(ctx context.Context, mediaID string) error {
+	media, err := h.lookup(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+
+	if media == nil {
+		return h.HandleMediaDeleted(ctx, mediaID)
+	}
+	return h.HandleMediaUpdated(ctx, media)
+}
+
+// lookup returns the current state of media, nil when it no longer exists
+func (h *MediaEventHandler) lookup(ctx context.Context, mediaID string) (*domain.Media, error) {
+	var mediaList []*domain.Media
+	var err error
+	// The event may be newer than a cached copy
+	if freshCatalog, ok := h.catalog.(FreshMediaCatalog); ok {
+		mediaList, err = freshCatalog.GetFreshMediaByIDs(ctx, []string{mediaID})
+	} else {
+		mediaList, err = h.catalog.GetMediaByIDs(ctx, []string{mediaID})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up media %s: %w", mediaID, err)
+	}
+
+	if len(mediaList) == 0 {
+		return nil, nil
+	}
+	return mediaList[0], nil
+}
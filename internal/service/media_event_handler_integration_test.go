@@ -0,0 +1,51 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/internal/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMediaEventHandler_Integration follows media from the database into search
+func TestMediaEventHandler_Integration(t *testing.T) {
+	ctx := context.Background()
+	pg := testsupport.StartPostgres(t)
+	testsupport.SeedMedia(t, pg.Conn, testsupport.MediaFixtures()...)
+	mediaRepo := repository.NewPostgresMediaRepository(pg.Conn)
+	searchRepo := repository.NewElasticsearchSearchRepository(testsupport.StartElasticsearch(t), nil)
+	handler := NewMediaEventHandler(searchRepo, NewRepositoryMediaCatalog(mediaRepo), nil)
+
+	// Given ready media indexed as it is created
+	ready, err := mediaRepo.GetByStatus(ctx, domain.StatusReady, 10, 0)
+	require.NoError(t, err)
+	for _, media := range ready {
+		require.NoError(t, handler.HandleMediaCreated(ctx, media))
+	}
+
+	// Then only public media is searchable
+	results, total, err := searchRepo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	for _, result := range results {
+		assert.NotEqual(t, "fixture-private", result.Media.ID)
+	}
+
+	// When the media is made private
+	require.NoError(t, mediaRepo.UpdateVisibility(ctx, "fixture-video", domain.VisibilityPrivate))
+	media, err := mediaRepo.GetByID(ctx, "fixture-video")
+	require.NoError(t, err)
+	require.NoError(t, handler.HandleMediaUpdated(ctx, media))
+
+	// Then it disappears from search
+	_, total, err = searchRepo.Search(ctx, &domain.SearchRequest{Query: "concurrency"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaEventHandler_HandleEvent(t *testing.T) {
+	statusChanged := func(from, to domain.MediaStatus) *domain.Event {
+		return domain.NewEvent(domain.EventMediaStatusChanged, map[string]interface{}{
+			"media_id": "m1", "from": string(from), "to": string(to),
+		})
+	}
+
+	tests := []struct {
+		name        string
+		indexed     bool
+		stored      *domain.Media // nil when the media no longer exists
+		event       *domain.Event
+		wantIndexed bool
+	}{
+		{
+			name:        "deletion removes media",
+			indexed:     true,
+			event:       domain.NewEvent(domain.EventMediaDeleted, map[string]interface{}{"media_id": "m1"}),
+			wantIndexed: false,
+		},
+		{
+			name:        "purge removes media",
+			indexed:     true,
+			event:       domain.NewEvent(domain.EventMediaPurged, map[string]interface{}{"media_id": "m1"}),
+			wantIndexed: false,
+		},
+		{
+			name:        "becoming ready adds media",
+			stored:      &domain.Media{ID: "m1", Title: "Golang Weekly", Status: domain.StatusReady},
+			event:       statusChanged(domain.StatusProcessing, domain.StatusReady),
+			wantIndexed: true,
+		},
+		{
+			name:        "becoming ready keeps private media out",
+			stored:      &domain.Media{ID: "m1", Title: "Golang Weekly", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+			event:       statusChanged(domain.StatusProcessing, domain.StatusReady),
+			wantIndexed: false,
+		},
+		{
+			name:        "failing removes media",
+			indexed:     true,
+			stored:      &domain.Media{ID: "m1", Title: "Golang Weekly", Status: domain.StatusFailed},
+			event:       statusChanged(domain.StatusReady, domain.StatusFailed),
+			wantIndexed: false,
+		},
+		{
+			name:        "transition to deleted removes media",
+			indexed:     true,
+			event:       statusChanged(domain.StatusReady, domain.StatusDeleted),
+			wantIndexed: false,
+		},
+		{
+			name:        "unpublishing removes media",
+			indexed:     true,
+			stored:      &domain.Media{ID: "m1", Title: "Golang Weekly", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+			event:       domain.NewEvent(domain.EventMediaUnpublished, map[string]interface{}{"media_id": "m1"}),
+			wantIndexed: false,
+		},
+		{
+			name:        "other events are ignored",
+			indexed:     true,
+			event:       domain.NewEvent(domain.EventMediaUploaded, map[string]interface{}{"media_id": "m1"}),
+			wantIndexed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			searchRepo := repository.NewInMemorySearchRepository()
+			if tt.indexed {
+				require.NoError(t, searchRepo.IndexMedia(ctx, &domain.Media{ID: "m1", Title: "Golang Weekly", Status: domain.StatusReady}))
+			}
+			mediaRepo := repository.NewInMemoryMediaRepository()
+			if tt.stored != nil {
+				require.NoError(t, mediaRepo.Create(ctx, tt.stored))
+			}
+			handler := NewMediaEventHandler(searchRepo, NewRepositoryMediaCatalog(mediaRepo), nil)
+
+			// When
+			err := handler.HandleEvent(ctx, tt.event)
+
+			// Then
+			require.NoError(t, err)
+			_, total, err := searchRepo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIndexed, total == 1)
+		})
+	}
+}
+
+func TestMediaEventHandler_IndexesShowContext(t *testing.T) {
+	// Given an episode with a guest, of a show with a title and hosts
+	ctx := context.Background()
+	searchRepo := repository.NewInMemorySearchRepository()
+	shows := stubShowTemplateRepository{
+		"show-1": {ShowID: "show-1", Title: "Fnjan", Category: "Society", Hosts: []string{"Abdulrahman Abumalih"}},
+	}
+	people := newStubPersonRepository(&domain.Person{ID: "p1", Name: "Visiting Guest"})
+	people.credits = []*domain.PersonCredit{
+		{PersonID: "p1", TargetType: domain.CreditTargetMedia, TargetID: "m1", Role: domain.PersonRoleGuest},
+	}
+	handler := NewMediaEventHandler(searchRepo, nil, NewSearchContextResolver(shows, people))
+
+	// When
+	require.NoError(t, handler.HandleMediaCreated(ctx, &domain.Media{ID: "m1", Title: "Episode 12", Status: domain.StatusReady, ShowID: "show-1"}))
+
+	// Then searching for the show, its category, its host or the guest finds the episode
+	for _, query := range []string{"fnjan", "society", "abumalih", "visiting"} {
+		results, total, err := searchRepo.Search(ctx, &domain.SearchRequest{Query: query})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total, query)
+		assert.Equal(t, "m1", results[0].Media.ID)
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"thamaniyah/pkg/messagequeue"
+)
+
+// MediaIndexConsumerOptions configures a media index consumer
+type MediaIndexConsumerOptions struct {
+	MaxAttempts  int           // handling attempts of an event before it is dead-lettered
+	RetryBackoff time.Duration // wait before the first retry, doubled on every retry
+}
+
+// MediaIndexConsumerStats exposes consumer metrics
+type MediaIndexConsumerStats struct {
+	Processed    uint64 `json:"processed"`
+	Retried      uint64 `json:"retried"`
+	Failed       uint64 `json:"failed"`
+	DeadLettered uint64 `json:"dead_lettered"`
+}
+
+// MediaIndexConsumer keeps the search index fresh from the media index events
+// published by the CMS service
+type MediaIndexConsumer struct {
+	queue    messagequeue.MessageQueue
+	registry *messagequeue.Registry
+	handler  *MediaEventHandler
+	options  MediaIndexConsumerOptions
+
+	mu       sync.Mutex
+	stopping bool
+	inFlight sync.WaitGroup
+
+	processed    atomic.Uint64
+	retried      atomic.Uint64
+	failed       atomic.Uint64
+	deadLettered atomic.Uint64
+}
+
+// NewMediaIndexConsumer creates a new media index consumer. The registry
+// defaults to the built-in schemas when nil.
+func NewMediaIndexConsumer(queue messagequeue.MessageQueue, registry *messagequeue.Registry, handler *MediaEventHandler, options MediaIndexConsumerOptions) *MediaIndexConsumer {
+	if registry == nil {
+		registry = messagequeue.NewDefaultRegistry()
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 3
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = 500 * time.Millisecond
+	}
+
+	return &MediaIndexConsumer{
+		queue:    queue,
+		registry: registry,
+		handler:  handler,
+		options:  options,
+	}
+}
+
+// Run consumes media index events until ctx is cancelled, then waits for the
+// events being handled to finish
+func (c *MediaIndexConsumer) Run(ctx context.Context) error {
+	if err := c.queue.Subscribe(ctx, messagequeue.EventTypeMediaIndex, c.HandleMessage); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", messagequeue.EventTypeMediaIndex, err)
+	}
+	log.Printf("Consuming %s events", messagequeue.EventTypeMediaIndex)
+
+	<-ctx.Done()
+	c.mu.Lock()
+	c.stopping = true
+	c.mu.Unlock()
+	c.inFlight.Wait()
+	return nil
+}
+
+// HandleMessage indexes the media of a media index event, retrying failures
+// before dead-lettering the event. It only fails when the event could neither
+// be handled nor dead-lettered, or ctx was cancelled, so the queue redelivers it.
+func (c *MediaIndexConsumer) HandleMessage(ctx context.Context, message []byte) error {
+	c.mu.Lock()
+	if c.stopping {
+		c.mu.Unlock()
+		return fmt.Errorf("media index consumer is shutting down")
+	}
+	c.inFlight.Add(1)
+	c.mu.Unlock()
+	defer c.inFlight.Done()
+
+	_, event, err := c.registry.UnmarshalLatest(message)
+	if err != nil {
+		// Retrying cannot fix a malformed event
+		c.failed.Add(1)
+		return c.deadLetter(ctx, message, err, 1)
+	}
+	indexEvent, ok := event.(*messagequeue.MediaIndexEvent)
+	if !ok {
+		c.failed.Add(1)
+		return c.deadLetter(ctx, message, fmt.Errorf("unexpected event %s on %s", event.EventType(), messagequeue.EventTypeMediaIndex), 1)
+	}
+
+	backoff := c.options.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err = c.handler.HandleIndexEvent(ctx, indexEvent)
+		if err == nil {
+			c.processed.Add(1)
+			return nil
+		}
+		if attempt == c.options.MaxAttempts {
+			break
+		}
+
+		c.retried.Add(1)
+		log.Printf("Indexing media %s failed (attempt %d of %d), retrying in %s: %v",
+			indexEvent.MediaID, attempt, c.options.MaxAttempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			// Shutting down: leave the event to the queue
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	c.failed.Add(1)
+	log.Printf("Indexing media %s failed after %d attempts, dead-lettering the event: %v", indexEvent.MediaID, c.options.MaxAttempts, err)
+	return c.deadLetter(ctx, message, err, c.options.MaxAttempts)
+}
+
+// Stats returns a snapshot of the consumer metrics
+func (c *MediaIndexConsumer) Stats() MediaIndexConsumerStats {
+	return MediaIndexConsumerStats{
+		Processed:    c.processed.Load(),
+		Retried:      c.retried.Load(),
+		Failed:       c.failed.Load(),
+		DeadLettered: c.deadLettered.Load(),
+	}
+}
+
+// deadLetter publishes a message the consumer gave up on to the dead letter topic
+func (c *MediaIndexConsumer) deadLetter(ctx context.Context, message []byte, cause error, attempts int) error {
+	payload, err := json.Marshal(&messagequeue.DeadLetter{
+		Topic:    messagequeue.EventTypeMediaIndex,
+		Message:  message,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	if err := c.queue.Publish(ctx, messagequeue.DeadLetterTopic(messagequeue.EventTypeMediaIndex), payload); err != nil {
+		return fmt.Errorf("failed to dead-letter media index event: %w", err)
+	}
+	c.deadLettered.Add(1)
+	return nil
+}
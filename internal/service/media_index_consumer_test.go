@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/messagequeue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyMediaCatalog fails its first lookups, then serves fixed media
+type flakyMediaCatalog struct {
+	failures int
+	media    map[string]*domain.Media
+}
+
+func (c *flakyMediaCatalog) GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	if c.failures > 0 {
+		c.failures--
+		return nil, errors.New("cms unavailable")
+	}
+	var result []*domain.Media
+	for _, id := range ids {
+		if media, ok := c.media[id]; ok {
+			result = append(result, media)
+		}
+	}
+	return result, nil
+}
+
+func TestMediaIndexConsumer_HandleMessage(t *testing.T) {
+	registry := messagequeue.NewDefaultRegistry()
+	indexEvent := func(action, mediaID string) []byte {
+		message, err := registry.Marshal(&messagequeue.MediaIndexEvent{Action: action, MediaID: mediaID})
+		require.NoError(t, err)
+		return message
+	}
+	searchable := &domain.Media{ID: "m1", Title: "Golang Weekly", Status: domain.StatusReady}
+
+	tests := []struct {
+		name             string
+		failures         int
+		message          []byte
+		indexed          bool
+		wantIndexed      bool
+		wantStats        MediaIndexConsumerStats
+		wantDeadLettered bool
+	}{
+		{
+			name:        "created media is indexed",
+			message:     indexEvent(messagequeue.MediaIndexCreated, "m1"),
+			wantIndexed: true,
+			wantStats:   MediaIndexConsumerStats{Processed: 1},
+		},
+		{
+			name:        "deleted media is removed",
+			indexed:     true,
+			message:     indexEvent(messagequeue.MediaIndexDeleted, "m1"),
+			wantIndexed: false,
+			wantStats:   MediaIndexConsumerStats{Processed: 1},
+		},
+		{
+			name:        "failures are retried",
+			failures:    2,
+			message:     indexEvent(messagequeue.MediaIndexUpdated, "m1"),
+			wantIndexed: true,
+			wantStats:   MediaIndexConsumerStats{Processed: 1, Retried: 2},
+		},
+		{
+			name:             "events failing every attempt are dead-lettered",
+			failures:         3,
+			message:          indexEvent(messagequeue.MediaIndexUpdated, "m1"),
+			wantIndexed:      false,
+			wantStats:        MediaIndexConsumerStats{Retried: 2, Failed: 1, DeadLettered: 1},
+			wantDeadLettered: true,
+		},
+		{
+			name:             "malformed events are dead-lettered at once",
+			message:          []byte(`{"type":"media.index","schema_version":9}`),
+			wantStats:        MediaIndexConsumerStats{Failed: 1, DeadLettered: 1},
+			wantDeadLettered: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			searchRepo := repository.NewInMemorySearchRepository()
+			if tt.indexed {
+				require.NoError(t, searchRepo.IndexMedia(ctx, searchable))
+			}
+			catalog := &flakyMediaCatalog{failures: tt.failures, media: map[string]*domain.Media{"m1": searchable}}
+			queue := messagequeue.NewInMemoryQueue()
+			deadLetters := make(chan []byte, 1)
+			require.NoError(t, queue.Subscribe(ctx, messagequeue.DeadLetterTopic(messagequeue.EventTypeMediaIndex), func(ctx context.Context, message []byte) error {
+				deadLetters <- message
+				return nil
+			}))
+			consumer := NewMediaIndexConsumer(queue, registry, NewMediaEventHandler(searchRepo, catalog, nil), MediaIndexConsumerOptions{
+				MaxAttempts:  3,
+				RetryBackoff: time.Millisecond,
+			})
+
+			// When
+			err := consumer.HandleMessage(ctx, tt.message)
+
+			// Then
+			require.NoError(t, err)
+			_, total, err := searchRepo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIndexed, total == 1)
+			assert.Equal(t, tt.wantStats, consumer.Stats())
+
+			if tt.wantDeadLettered {
+				require.Len(t, deadLetters, 1)
+				var deadLetter messagequeue.DeadLetter
+				require.NoError(t, json.Unmarshal(<-deadLetters, &deadLetter))
+				assert.Equal(t, messagequeue.EventTypeMediaIndex, deadLetter.Topic)
+				assert.Equal(t, tt.message, deadLetter.Message)
+				assert.NotEmpty(t, deadLetter.Error)
+			} else {
+				assert.Empty(t, deadLetters)
+			}
+		})
+	}
+}
+
+func TestMediaIndexConsumer_Run(t *testing.T) {
+	// Given a running consumer
+	searchRepo := repository.NewInMemorySearchRepository()
+	catalog := &flakyMediaCatalog{media: map[string]*domain.Media{"m1": {ID: "m1", Title: "Golang Weekly", Status: domain.StatusReady}}}
+	queue := messagequeue.NewInMemoryQueue()
+	consumer := NewMediaIndexConsumer(queue, nil, NewMediaEventHandler(searchRepo, catalog, nil), MediaIndexConsumerOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	// When the CMS publishes a media index event
+	publisher := NewMediaIndexEventPublisher(queue, nil)
+	require.Eventually(t, func() bool {
+		require.NoError(t, publisher.Publish(context.Background(), domain.NewEvent(domain.EventMediaUpdated, map[string]interface{}{"media_id": "m1"})))
+		return consumer.Stats().Processed > 0
+	}, time.Second, 10*time.Millisecond)
+
+	// Then the media is indexed, and the consumer stops with its context
+	_, total, err := searchRepo.Search(context.Background(), &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("consumer did not stop")
+	}
+	assert.Error(t, consumer.HandleMessage(context.Background(), nil))
+}
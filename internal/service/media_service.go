@@ -10,21 +10,55 @@ type MediaService interface {
 	// CreateUploadURL generates a presigned URL for media upload
 	CreateUploadURL(ctx context.Context, req *domain.UploadRequest) (*domain.UploadURL, error)
 
+	// GetUploadLimits returns the upload limits in effect for a tenant, the default tenant when empty
+	GetUploadLimits(ctx context.Context, tenantID string) *domain.UploadLimits
+
+	// ValidateUpload checks an upload request before it is made, returning every violation
+	// of the request, the tenant's limits and quota, and duplicates of the declared content hash
+	ValidateUpload(ctx context.Context, req *domain.UploadRequest) (domain.ValidationErrors, error)
+
+	// ReceiveUpload stores a file uploaded to the service with an upload URL it
+	// issued, checking the format and the declared size of the media
+	ReceiveUpload(ctx context.Context, upload *domain.DirectUpload) error
+
 	// ConfirmUpload confirms that a file has been uploaded successfully
 	ConfirmUpload(ctx context.Context, mediaID string) error
 
 	// GetMedia retrieves a media record by ID
 	GetMedia(ctx context.Context, id string) (*domain.Media, error)
 
-	// GetAllMedia retrieves all media records with pagination
-	GetAllMedia(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error)
+	// GetVisibleMedia retrieves a media record by ID, reporting media the
+	// viewer may not access as missing
+	GetVisibleMedia(ctx context.Context, id string, viewer domain.Viewer) (*domain.Media, error)
+
+	// GetMediaByIDs retrieves the existing media records among ids
+	GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error)
+
+	// GetAllMedia retrieves all media records with pagination, counting them as countMode selects
+	GetAllMedia(ctx context.Context, limit, offset int, countMode domain.CountMode) ([]*domain.Media, int64, error)
+
+	// GetFilteredMedia retrieves media records matching the filter with pagination
+	GetFilteredMedia(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, int64, error)
+
+	// GetMediaByOwner retrieves the media records a user owns with pagination, newest first
+	GetMediaByOwner(ctx context.Context, ownerID string, limit, offset int) ([]*domain.Media, int64, error)
 
 	// UpdateMedia updates media metadata
 	UpdateMedia(ctx context.Context, id string, req *domain.UpdateMediaRequest) (*domain.Media, error)
 
+	// SetGeoRestriction replaces the countries a media record can be played in
+	SetGeoRestriction(ctx context.Context, id string, restriction domain.GeoRestriction) (*domain.Media, error)
+
 	// DeleteMedia soft deletes a media record
 	DeleteMedia(ctx context.Context, id string) error
 
 	// ProcessMedia processes uploaded media (extract metadata, etc.)
 	ProcessMedia(ctx context.Context, mediaID string) error
+
+	// FailProcessing marks media still in processing as failed after its
+	// processing kept failing; media in any other state is left as is
+	FailProcessing(ctx context.Context, mediaID string, cause error) error
+
+	// ReprocessMedia re-runs processing for media that previously failed
+	ReprocessMedia(ctx context.Context, mediaID string) (*domain.Media, error)
 }
@@ -1,26 +1,92 @@
 package service
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"thamaniyah/internal/domain"
 	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/media"
+	"thamaniyah/pkg/storage"
 
 	"github.com/google/uuid"
 )
 
 // mediaService implements MediaService interface
 type mediaService struct {
-	mediaRepo repository.MediaRepository
+	mediaRepo       repository.MediaRepository
+	publisher       EventPublisher
+	outbox          EventPublisher
+	transactor      Transactor
+	processingQueue ProcessingQueue
+	uploadEvents    bool
+	taskLimiter     *TaskLimiter
+	presetRepo      repository.TranscodePresetRepository
+	tagExtractor    TagExtractor
+	uploadLimits    *UploadLimitPolicy
+	showTemplates   repository.ShowTemplateRepository
+	uploadStorage   storage.Storage
+	duplicates      DuplicateDetector
+	chapters        ChapterSuggester
+	metadata        media.MetadataExtractor
+}
+
+// Transactor runs a unit of work in a database transaction carried by the
+// context it is given
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// MediaServiceDeps holds the collaborators of the media service; only
+// Publisher is required
+type MediaServiceDeps struct {
+	Publisher EventPublisher
+	// Outbox gets the events announcing a change of media in the transaction
+	// saving the change, Publisher only once it is committed; nil writes none
+	Outbox          EventPublisher
+	Transactor      Transactor                           // nil saves changes without a transaction
+	ProcessingQueue ProcessingQueue                      // nil processes media inline
+	TaskLimiter     *TaskLimiter                         // nil does not limit processing tasks
+	Presets         repository.TranscodePresetRepository // nil transcodes with the built-in presets
+	TagExtractor    TagExtractor                         // nil does not read embedded tags
+	UploadLimits    *UploadLimitPolicy                   // nil applies the built-in upload limits
+	ShowTemplates   repository.ShowTemplateRepository    // nil gives episodes no show defaults
+	UploadStorage   storage.Storage                      // nil simulates metadata extraction
+	Duplicates      DuplicateDetector                    // nil does not detect near-duplicates
+	Chapters        ChapterSuggester                     // nil suggests no chapter boundaries
+	Metadata        media.MetadataExtractor              // nil simulates metadata extraction
+	// UploadEvents leaves confirmed uploads to the processing worker consuming
+	// their media.uploaded event instead of queuing them
+	UploadEvents bool
 }
 
 // NewMediaService creates a new media service
-func NewMediaService(mediaRepo repository.MediaRepository) MediaService {
+func NewMediaService(mediaRepo repository.MediaRepository, deps MediaServiceDeps) MediaService {
 	return &mediaService{
-		mediaRepo: mediaRepo,
+		mediaRepo:       mediaRepo,
+		publisher:       deps.Publisher,
+		outbox:          deps.Outbox,
+		transactor:      deps.Transactor,
+		processingQueue: deps.ProcessingQueue,
+		uploadEvents:    deps.UploadEvents,
+		taskLimiter:     deps.TaskLimiter,
+		presetRepo:      deps.Presets,
+		tagExtractor:    deps.TagExtractor,
+		uploadLimits:    deps.UploadLimits,
+		showTemplates:   deps.ShowTemplates,
+		uploadStorage:   deps.UploadStorage,
+		duplicates:      deps.Duplicates,
+		chapters:        deps.Chapters,
+		metadata:        deps.Metadata,
 	}
 }
 
@@ -30,6 +96,28 @@ func (s *mediaService) CreateUploadURL(ctx context.Context, req *domain.UploadRe
 	if !req.IsValid() {
 		return nil, domain.NewBusinessError("INVALID_REQUEST", "Upload request validation failed")
 	}
+	if !req.HasValidFormat() {
+		return nil, domain.NewBusinessError("INVALID_FORMAT",
+			fmt.Sprintf("Files of %s media must be one of %s", req.Type, strings.Join(domain.FormatsFor(req.Type), ", ")))
+	}
+	if errs := domain.ValidateUnpublishAt(req.UnpublishAt, time.Now()); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Upload request validation failed", errs.Error())
+	}
+
+	// Uploads may pick a transcode preset made for their media type
+	if req.TranscodePresetID != "" {
+		preset, err := s.presetRepo.GetByID(ctx, req.TranscodePresetID)
+		if err != nil {
+			if errors.Is(err, domain.ErrTranscodePresetNotFound) {
+				return nil, domain.NewBusinessError("INVALID_TRANSCODE_PRESET", "Transcode preset not found")
+			}
+			return nil, fmt.Errorf("failed to load transcode preset: %w", err)
+		}
+		if preset.MediaType != req.Type {
+			return nil, domain.NewBusinessError("INVALID_TRANSCODE_PRESET",
+				fmt.Sprintf("Transcode preset is made for %s, not %s", preset.MediaType, req.Type))
+		}
+	}
 
 	// Generate unique media ID
 	mediaID := uuid.New().String()
@@ -37,22 +125,200 @@ func (s *mediaService) CreateUploadURL(ctx context.Context, req *domain.UploadRe
 	// Generate file path (in production this would be S3 path)
 	filePath := s.generateFilePath(req.Filename, mediaID)
 
-	// Create media record in uploading state
+	// Create media record in uploading state, within the limits of its tenant
 	media := req.ToMedia(mediaID, filePath)
+	limits := s.uploadLimits.LimitsFor(media.TenantID)
+	if !limits.AllowsFileSize(req.Type, req.FileSize) {
+		return nil, domain.NewBusinessError("FILE_TOO_LARGE",
+			fmt.Sprintf("File size exceeds the %d bytes allowed for %s uploads", limits.MaxFileSize(req.Type), req.Type))
+	}
+	remaining, err := s.remainingQuota(ctx, limits)
+	if err != nil {
+		return nil, err
+	}
+	if remaining >= 0 && req.FileSize > remaining {
+		return nil, domain.NewBusinessError("QUOTA_EXCEEDED",
+			fmt.Sprintf("File size exceeds the %d bytes left in the storage quota", remaining))
+	}
+	if err := s.applyShowTemplate(ctx, media, req); err != nil {
+		return nil, err
+	}
+	media.DetectSeries()
 	if err := s.mediaRepo.Create(ctx, media); err != nil {
 		return nil, fmt.Errorf("failed to create media record: %w", err)
 	}
 
-	// Generate upload URL (simulated presigned URL for local development)
-	uploadURL := s.generateUploadURL(filePath)
+	uploadURL, err := s.generateUploadURL(ctx, media, limits.UploadURLTTL())
+	if err != nil {
+		return nil, err
+	}
 
 	return &domain.UploadURL{
 		MediaID:   mediaID,
 		URL:       uploadURL,
-		ExpiresAt: time.Now().Add(domain.UploadURLTTL),
+		ExpiresAt: time.Now().Add(limits.UploadURLTTL()),
 	}, nil
 }
 
+// GetUploadLimits returns the upload limits in effect for a tenant
+func (s *mediaService) GetUploadLimits(ctx context.Context, tenantID string) *domain.UploadLimits {
+	return s.uploadLimits.LimitsFor(tenantID)
+}
+
+// ValidateUpload checks an upload request without creating anything, returning every violation
+func (s *mediaService) ValidateUpload(ctx context.Context, req *domain.UploadRequest) (domain.ValidationErrors, error) {
+	errs := req.Validate()
+	errs = append(errs, domain.ValidateUnpublishAt(req.UnpublishAt, time.Now())...)
+
+	tenantID := req.TenantID()
+	limits := s.uploadLimits.LimitsFor(tenantID)
+	if maxSize := limits.MaxFileSize(req.Type); maxSize > 0 && req.FileSize > maxSize {
+		errs.Add("file_size", fmt.Sprintf("must be at most %d bytes for %s uploads", maxSize, req.Type))
+	}
+
+	remaining, err := s.remainingQuota(ctx, limits)
+	if err != nil {
+		return nil, err
+	}
+	if remaining >= 0 && req.FileSize > remaining {
+		errs.Add("file_size", fmt.Sprintf("exceeds the %d bytes left in the storage quota", remaining))
+	}
+
+	if req.ContentHash != "" && domain.IsValidContentHash(req.ContentHash) {
+		duplicate, err := s.mediaRepo.GetByContentHash(ctx, tenantID, req.ContentHash)
+		if err != nil && !errors.Is(err, domain.ErrMediaNotFound) {
+			return nil, fmt.Errorf("failed to check for duplicate uploads: %w", err)
+		}
+		if duplicate != nil {
+			errs.Add("content_hash", fmt.Sprintf("duplicates media %s", duplicate.ID))
+		}
+	}
+
+	return errs, nil
+}
+
+// applyShowTemplate fills the metadata the upload request left unset from the template of its show
+func (s *mediaService) applyShowTemplate(ctx context.Context, media *domain.Media, req *domain.UploadRequest) error {
+	if s.showTemplates == nil || media.ShowID == "" {
+		return nil
+	}
+
+	template, err := s.showTemplates.GetByShowID(ctx, media.ShowID)
+	if err != nil {
+		if errors.Is(err, domain.ErrShowTemplateNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to load show template: %w", err)
+	}
+
+	template.ApplyTo(media, req)
+	return nil
+}
+
+// remainingQuota returns the bytes the tenant of limits can still upload, -1 when unlimited
+func (s *mediaService) remainingQuota(ctx context.Context, limits *domain.UploadLimits) (int64, error) {
+	if limits.StorageQuota <= 0 {
+		return -1, nil
+	}
+
+	used, err := s.mediaRepo.GetStorageUsage(ctx, limits.TenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get storage usage: %w", err)
+	}
+
+	return limits.RemainingQuota(used), nil
+}
+
+// errUploadTooLarge is returned by uploads longer than their declared size
+var errUploadTooLarge = errors.New("upload is larger than its declared size")
+
+// ReceiveUpload stores a file uploaded to the service with an upload URL it issued
+func (s *mediaService) ReceiveUpload(ctx context.Context, upload *domain.DirectUpload) error {
+	receiver, ok := s.uploadStorage.(storage.UploadReceiver)
+	if !ok || receiver.VerifyPut(upload.Key, upload.Expires, upload.Signature) != nil {
+		return domain.ErrInvalidUploadURL
+	}
+
+	media, err := s.mediaRepo.GetByID(ctx, domain.MediaIDOfStorageKey(upload.Key))
+	if err != nil {
+		return err
+	}
+	if media.StorageKey() != upload.Key {
+		return domain.ErrInvalidUploadURL
+	}
+	if media.Status != domain.StatusUploading {
+		return domain.NewBusinessError("INVALID_STATUS",
+			fmt.Sprintf("Media is in %s state, expected uploading", media.Status))
+	}
+	if !domain.IsValidFormat(media.Type, domain.FormatFromFilename(upload.Key)) {
+		return domain.NewBusinessError("INVALID_FORMAT",
+			fmt.Sprintf("Files of %s media must be one of %s", media.Type, strings.Join(domain.FormatsFor(media.Type), ", ")))
+	}
+	if upload.Size > media.FileSize {
+		return domain.NewBusinessError("FILE_TOO_LARGE",
+			fmt.Sprintf("File size exceeds the %d bytes declared for the upload", media.FileSize))
+	}
+
+	body := bufio.NewReader(upload.Body)
+	head, err := body.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	if len(head) == 0 {
+		return domain.NewBusinessError("EMPTY_UPLOAD", "The uploaded file is empty")
+	}
+	if !isMediaContent(head) {
+		return domain.NewBusinessError("INVALID_FORMAT",
+			fmt.Sprintf("The uploaded file is %s, not %s media", http.DetectContentType(head), media.Type))
+	}
+
+	err = s.uploadStorage.Put(ctx, upload.Key, &sizeLimitedReader{r: body, remaining: media.FileSize})
+	if errors.Is(err, errUploadTooLarge) {
+		return domain.NewBusinessError("FILE_TOO_LARGE",
+			fmt.Sprintf("File size exceeds the %d bytes declared for the upload", media.FileSize))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store upload: %w", err)
+	}
+	return nil
+}
+
+// isMediaContent reports whether the first bytes of a file may be audio or video.
+// Formats the standard sniffer does not know pass; text, images, documents and
+// archives do not.
+func isMediaContent(head []byte) bool {
+	contentType := http.DetectContentType(head)
+	switch {
+	case strings.HasPrefix(contentType, "text/"), strings.HasPrefix(contentType, "image/"), strings.HasPrefix(contentType, "font/"):
+		return false
+	case contentType == "application/pdf", contentType == "application/zip", contentType == "application/x-gzip",
+		contentType == "application/x-rar-compressed", contentType == "application/wasm":
+		return false
+	}
+	return true
+}
+
+// sizeLimitedReader fails with errUploadTooLarge once more than remaining bytes are read
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errUploadTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errUploadTooLarge
+	}
+	return n, err
+}
+
 // ConfirmUpload confirms that a file has been uploaded successfully
 func (s *mediaService) ConfirmUpload(ctx context.Context, mediaID string) error {
 	// Get the media record
@@ -67,20 +333,23 @@ func (s *mediaService) ConfirmUpload(ctx context.Context, mediaID string) error
 			fmt.Sprintf("Media is in %s state, expected uploading", media.Status))
 	}
 
-	// In production, we would verify the file exists in S3
-	// For now, we'll simulate file validation
-	if err := s.validateUploadedFile(media.FilePath); err != nil {
-		// Mark as failed
-		s.mediaRepo.UpdateStatus(ctx, mediaID, domain.StatusFailed)
+	if err := s.validateUploadedFile(ctx, media); err != nil {
+		// Mark invalid uploads as failed, storage errors can be retried
+		var businessErr *domain.BusinessError
+		if errors.As(err, &businessErr) {
+			s.failMedia(ctx, media, domain.FailureUploadValidation, err)
+		}
 		return err
 	}
 
-	// Update status to ready
-	if err := s.mediaRepo.UpdateStatus(ctx, mediaID, domain.StatusReady); err != nil {
-		return fmt.Errorf("failed to update media status: %w", err)
+	// Hand the upload over to processing, the processing worker queuing it
+	// from the uploaded event when there are upload events
+	uploaded := mediaEvent(domain.EventMediaUploaded, mediaID)
+	if s.uploadEvents {
+		return s.transitionStatus(ctx, media, domain.StatusProcessing, uploaded)
 	}
 
-	return nil
+	return s.startProcessing(ctx, media, nil, uploaded)
 }
 
 // GetMedia retrieves a media record by ID
@@ -88,8 +357,22 @@ func (s *mediaService) GetMedia(ctx context.Context, id string) (*domain.Media,
 	return s.mediaRepo.GetByID(ctx, id)
 }
 
-// GetAllMedia retrieves all media records with pagination
-func (s *mediaService) GetAllMedia(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error) {
+// GetMediaByIDs retrieves the existing media records among ids
+func (s *mediaService) GetMediaByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	if len(ids) > domain.MaxPageSize {
+		return nil, domain.NewBusinessError("TOO_MANY_IDS", fmt.Sprintf("At most %d media can be fetched at once", domain.MaxPageSize))
+	}
+
+	mediaList, err := s.mediaRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+
+	return mediaList, nil
+}
+
+// GetAllMedia retrieves all media records with pagination, counting them as countMode selects
+func (s *mediaService) GetAllMedia(ctx context.Context, limit, offset int, countMode domain.CountMode) ([]*domain.Media, int64, error) {
 	// Validate pagination parameters
 	if limit <= 0 || limit > domain.MaxPageSize {
 		limit = domain.DefaultPageSize
@@ -98,6 +381,15 @@ func (s *mediaService) GetAllMedia(ctx context.Context, limit, offset int) ([]*d
 		offset = 0
 	}
 
+	// Exact totals come with the page in a single query
+	if countMode != domain.CountEstimated {
+		mediaList, total, err := s.mediaRepo.GetAllWithTotal(ctx, limit, offset)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get media list: %w", err)
+		}
+		return mediaList, total, nil
+	}
+
 	// Get media records
 	mediaList, err := s.mediaRepo.GetAll(ctx, limit, offset)
 	if err != nil {
@@ -105,7 +397,7 @@ func (s *mediaService) GetAllMedia(ctx context.Context, limit, offset int) ([]*d
 	}
 
 	// Get total count
-	total, err := s.mediaRepo.GetTotal(ctx)
+	total, err := s.mediaRepo.GetEstimatedTotal(ctx)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
 	}
@@ -113,45 +405,217 @@ func (s *mediaService) GetAllMedia(ctx context.Context, limit, offset int) ([]*d
 	return mediaList, total, nil
 }
 
+// GetFilteredMedia retrieves media records matching the filter with pagination
+func (s *mediaService) GetFilteredMedia(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, int64, error) {
+	// Validate pagination parameters
+	if limit <= 0 || limit > domain.MaxPageSize {
+		limit = domain.DefaultPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// Get media records
+	mediaList, err := s.mediaRepo.GetByFilter(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get filtered media list: %w", err)
+	}
+
+	// Get total count
+	total, err := s.mediaRepo.GetTotalByFilter(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get filtered total count: %w", err)
+	}
+
+	return mediaList, total, nil
+}
+
+// GetMediaByOwner retrieves the media records a user owns with pagination
+func (s *mediaService) GetMediaByOwner(ctx context.Context, ownerID string, limit, offset int) ([]*domain.Media, int64, error) {
+	// Validate pagination parameters
+	if limit <= 0 || limit > domain.MaxPageSize {
+		limit = domain.DefaultPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	mediaList, total, err := s.mediaRepo.GetByOwner(ctx, ownerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get owned media list: %w", err)
+	}
+
+	return mediaList, total, nil
+}
+
 // UpdateMedia updates media metadata
 func (s *mediaService) UpdateMedia(ctx context.Context, id string, req *domain.UpdateMediaRequest) (*domain.Media, error) {
+	if !req.IsValid() {
+		return nil, domain.NewBusinessError("INVALID_REQUEST", "Update request validation failed")
+	}
+	if errs := domain.ValidateUnpublishAt(req.UnpublishAt, time.Now()); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Update request validation failed", errs.Error())
+	}
+
 	// Get existing media
 	media, err := s.mediaRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply updates
+	// Apply updates, relinking the media when its title numbers another series part
+	seriesID, seriesPart := media.SeriesID, media.SeriesPart
 	req.ApplyTo(media)
+	if req.Title != nil {
+		media.DetectSeries()
+	}
 
 	// Update in database
-	if err := s.mediaRepo.Update(ctx, media); err != nil {
-		return nil, fmt.Errorf("failed to update media: %w", err)
+	err = s.saveChange(ctx, func(ctx context.Context) error {
+		if err := s.mediaRepo.Update(ctx, media); err != nil {
+			return fmt.Errorf("failed to update media: %w", err)
+		}
+
+		// The explicit flag and license dates can be cleared, which a regular update skips
+		if req.ContentRating != nil {
+			if err := s.mediaRepo.UpdateContentRating(ctx, id, media.ContentRating); err != nil {
+				return fmt.Errorf("failed to update content rating: %w", err)
+			}
+		}
+		if req.License != nil {
+			if err := s.mediaRepo.UpdateLicense(ctx, id, media.License); err != nil {
+				return fmt.Errorf("failed to update license: %w", err)
+			}
+		}
+		if req.ClearUnpublishAt {
+			if err := s.mediaRepo.UpdateUnpublishAt(ctx, id, nil); err != nil {
+				return fmt.Errorf("failed to cancel unpublishing: %w", err)
+			}
+		}
+		if media.SeriesID != seriesID || media.SeriesPart != seriesPart {
+			if err := s.mediaRepo.UpdateSeries(ctx, id, media.SeriesID, media.SeriesPart, media.SeriesLocked); err != nil {
+				return fmt.Errorf("failed to update series: %w", err)
+			}
+		}
+		return nil
+	}, mediaEvent(domain.EventMediaUpdated, id))
+	if err != nil {
+		return nil, err
 	}
 
 	return media, nil
 }
 
+// SetGeoRestriction replaces the countries a media record can be played in
+func (s *mediaService) SetGeoRestriction(ctx context.Context, id string, restriction domain.GeoRestriction) (*domain.Media, error) {
+	if errs := restriction.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Geo restriction validation failed", errs.Error())
+	}
+	restriction.Normalize()
+
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mediaRepo.UpdateGeoRestriction(ctx, id, restriction); err != nil {
+		return nil, fmt.Errorf("failed to update geo restriction: %w", err)
+	}
+	media.GeoRestriction = restriction
+
+	return media, nil
+}
+
 // DeleteMedia soft deletes a media record
 func (s *mediaService) DeleteMedia(ctx context.Context, id string) error {
 	// Check if media exists
-	_, err := s.mediaRepo.GetByID(ctx, id)
+	media, err := s.mediaRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	// Soft delete the record
-	if err := s.mediaRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete media: %w", err)
+	// Move the record to the trash, its files are purged with it once the
+	// retention passes, and announce the deletion so search drops the media
+	deleteMedia := func(ctx context.Context) error {
+		if err := s.mediaRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete media: %w", err)
+		}
+		return nil
+	}
+	if err := s.changeStatus(ctx, media, domain.StatusDeleted, deleteMedia, mediaEvent(domain.EventMediaDeleted, id)); err != nil {
+		return err
 	}
 
-	// In production, we would also delete the file from S3 here
-	// For now, we just log it
 	fmt.Printf("Media %s marked for deletion\n", id)
 
 	return nil
 }
 
+// mediaEvent creates an event announcing a change of media, so search keeps its index fresh
+func mediaEvent(eventType, mediaID string) *domain.Event {
+	return domain.NewEvent(eventType, map[string]interface{}{
+		"media_id": mediaID,
+	})
+}
+
+// saveChange saves a change of media and appends the events announcing it to
+// the outbox in one transaction, so the events are relayed if and only if the
+// change is committed. The other publishers get the events once it is; the
+// change is saved by then, so a failed publish is only logged.
+func (s *mediaService) saveChange(ctx context.Context, change func(ctx context.Context) error, events ...*domain.Event) error {
+	save := func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+		if s.outbox == nil {
+			return nil
+		}
+		for _, event := range events {
+			if err := s.outbox.Publish(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var err error
+	if s.transactor != nil {
+		err = s.transactor.InTransaction(ctx, save)
+	} else {
+		err = save(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			log.Printf("Failed to publish %s of media %v: %v", event.Type, event.Data["media_id"], err)
+		}
+	}
+	return nil
+}
+
+// GetVisibleMedia retrieves a media record by ID, reporting media the viewer may not access as missing
+func (s *mediaService) GetVisibleMedia(ctx context.Context, id string, viewer domain.Viewer) (*domain.Media, error) {
+	return GetVisibleMedia(ctx, s.mediaRepo, id, viewer)
+}
+
+// GetVisibleMedia loads a media item for a viewer. Deleted media and media the
+// viewer may not access directly are reported as missing, so the existence of
+// private media does not leak. Every lookup of a single media item on behalf of
+// a viewer goes through it.
+func GetVisibleMedia(ctx context.Context, mediaRepo repository.MediaRepository, mediaID string, viewer domain.Viewer) (*domain.Media, error) {
+	media, err := mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted || !media.IsVisibleTo(viewer) {
+		return nil, domain.ErrMediaNotFound
+	}
+	return media, nil
+}
+
 // ProcessMedia processes uploaded media (extract metadata, etc.)
 func (s *mediaService) ProcessMedia(ctx context.Context, mediaID string) error {
 	media, err := s.mediaRepo.GetByID(ctx, mediaID)
@@ -159,56 +623,274 @@ func (s *mediaService) ProcessMedia(ctx context.Context, mediaID string) error {
 		return err
 	}
 
+	if media.Status != domain.StatusProcessing {
+		if err := s.transitionStatus(ctx, media, domain.StatusProcessing); err != nil {
+			return err
+		}
+	}
+
+	return s.processMedia(ctx, media)
+}
+
+// FailProcessing marks media still in processing as failed after its processing kept failing
+func (s *mediaService) FailProcessing(ctx context.Context, mediaID string, cause error) error {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	if media.Status != domain.StatusProcessing {
+		return nil
+	}
+
+	s.failMedia(ctx, media, domain.FailureProcessingAttempts, cause)
+	return nil
+}
+
+// ReprocessMedia re-runs processing for media that previously failed
+func (s *mediaService) ReprocessMedia(ctx context.Context, mediaID string) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only failed items are eligible for another attempt
+	if media.Status != domain.StatusFailed {
+		return nil, domain.NewBusinessError("INVALID_STATUS",
+			fmt.Sprintf("Media is in %s state, expected failed", media.Status))
+	}
+
+	// Forget the previous failure reason before the new attempt
+	clearFailure := func(ctx context.Context) error {
+		if err := s.mediaRepo.UpdateFailure(ctx, media.ID, "", ""); err != nil {
+			return fmt.Errorf("failed to clear failure reason: %w", err)
+		}
+		return nil
+	}
+	if err := s.startProcessing(ctx, media, clearFailure); err != nil {
+		return nil, err
+	}
+	media.ClearFailure()
+
+	return media, nil
+}
+
+// processMedia runs processing for media already in processing state
+func (s *mediaService) processMedia(ctx context.Context, media *domain.Media) error {
+	if err := s.mediaRepo.IncrementProcessingAttempts(ctx, media.ID); err != nil {
+		return fmt.Errorf("failed to record processing attempt: %w", err)
+	}
+	media.ProcessingAttempts++
+
 	// Simulate metadata extraction
-	if err := s.extractMetadata(media); err != nil {
-		// Mark as failed
-		s.mediaRepo.UpdateStatus(ctx, mediaID, domain.StatusFailed)
+	release, err := s.taskLimiter.Acquire(ctx, TaskMetadata)
+	if err != nil {
+		return fmt.Errorf("failed to acquire metadata extraction slot: %w", err)
+	}
+	err = s.extractMetadata(ctx, media)
+	if err == nil {
+		s.extractTags(ctx, media)
+		s.detectDuplicate(ctx, media)
+	}
+	release()
+	if err != nil {
+		// Files that cannot be read fail for good, other errors may go away on a retry
+		if isUnreadableMedia(err) {
+			s.failMedia(ctx, media, domain.FailureMetadataExtraction, err)
+		}
 		return fmt.Errorf("failed to extract metadata: %w", err)
 	}
+	s.suggestChapters(ctx, media)
 
 	// Update the media record with extracted metadata
 	if err := s.mediaRepo.Update(ctx, media); err != nil {
 		return fmt.Errorf("failed to update media with metadata: %w", err)
 	}
 
-	return nil
+	return s.transitionStatus(ctx, media, domain.StatusReady)
 }
 
 // Helper methods
 
+// startProcessing moves media to processing, saving also when not nil, and
+// hands it to the processing queue in the same transaction, so that a full
+// queue fails with ErrProcessingQueueFull and leaves the media as it was for
+// the caller to retry. Without a queue, media is processed inline.
+func (s *mediaService) startProcessing(ctx context.Context, media *domain.Media, also func(ctx context.Context) error, events ...*domain.Event) error {
+	if s.processingQueue == nil {
+		if err := s.changeStatus(ctx, media, domain.StatusProcessing, also, events...); err != nil {
+			return err
+		}
+		return s.processMedia(ctx, media)
+	}
+
+	return s.changeStatus(ctx, media, domain.StatusProcessing, func(ctx context.Context) error {
+		if also != nil {
+			if err := also(ctx); err != nil {
+				return err
+			}
+		}
+		return s.processingQueue.Enqueue(ctx, NewProcessingJob(media))
+	}, events...)
+}
+
+// transitionStatus moves media to the next status through the state machine,
+// persists it and emits a status change event, followed by events
+func (s *mediaService) transitionStatus(ctx context.Context, media *domain.Media, next domain.MediaStatus, events ...*domain.Event) error {
+	return s.changeStatus(ctx, media, next, nil, events...)
+}
+
+// changeStatus is transitionStatus saving another change of the media, when
+// not nil, in the same transaction
+func (s *mediaService) changeStatus(ctx context.Context, media *domain.Media, next domain.MediaStatus, also func(ctx context.Context) error, events ...*domain.Event) error {
+	previous := media.Status
+	if err := media.TransitionTo(next); err != nil {
+		return err
+	}
+
+	statusChanged := domain.NewEvent(domain.EventMediaStatusChanged, map[string]interface{}{
+		"media_id": media.ID,
+		"from":     string(previous),
+		"to":       string(next),
+	})
+	err := s.saveChange(ctx, func(ctx context.Context) error {
+		if err := s.mediaRepo.UpdateStatus(ctx, media.ID, next); err != nil {
+			return fmt.Errorf("failed to update media status: %w", err)
+		}
+		if also != nil {
+			return also(ctx)
+		}
+		return nil
+	}, append([]*domain.Event{statusChanged}, events...)...)
+	if err != nil {
+		media.Status = previous
+		return err
+	}
+
+	return nil
+}
+
+// failMedia moves media to failed state and records the failure reason
+func (s *mediaService) failMedia(ctx context.Context, media *domain.Media, code string, cause error) {
+	if !media.Status.CanTransitionTo(domain.StatusFailed) {
+		log.Printf("Media %s cannot be marked as failed from %s state", media.ID, media.Status)
+		return
+	}
+
+	// Record the reason first so status change consumers can read it
+	media.SetFailure(code, cause.Error())
+	if err := s.mediaRepo.UpdateFailure(ctx, media.ID, media.FailureCode, media.FailureMessage); err != nil {
+		log.Printf("Failed to record failure reason for media %s: %v", media.ID, err)
+	}
+
+	if err := s.transitionStatus(ctx, media, domain.StatusFailed); err != nil {
+		log.Printf("Failed to mark media %s as failed: %v", media.ID, err)
+	}
+}
+
 // generateFilePath creates a file path for the uploaded media
 func (s *mediaService) generateFilePath(filename, mediaID string) string {
 	ext := filepath.Ext(filename)
-	return fmt.Sprintf("/uploads/%s%s", mediaID, ext)
+	return domain.UploadPathPrefix + mediaID + ext
 }
 
-// generateUploadURL creates a presigned URL (simulated for local development)
-func (s *mediaService) generateUploadURL(filePath string) string {
-	// In production, this would generate an actual S3 presigned URL
-	return fmt.Sprintf("http://localhost:8080/upload%s", filePath)
+// generateUploadURL creates a presigned URL the client uploads the file of
+// media to, simulated when the storage cannot presign
+func (s *mediaService) generateUploadURL(ctx context.Context, media *domain.Media, ttl time.Duration) (string, error) {
+	presigner, ok := s.uploadStorage.(storage.Presigner)
+	if !ok {
+		return fmt.Sprintf("http://localhost:8080/upload%s", media.FilePath), nil
+	}
+
+	url, err := presigner.PresignPut(ctx, media.StorageKey(), ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign upload URL: %w", err)
+	}
+	return url, nil
 }
 
-// validateUploadedFile validates that the uploaded file exists and is valid
-func (s *mediaService) validateUploadedFile(filePath string) error {
-	// In production, we would check if file exists in S3 and validate its size/format
-	// For now, we'll just simulate validation
-	fmt.Printf("Validating uploaded file: %s\n", filePath)
+// validateUploadedFile checks that the file of media was uploaded to the
+// storage its URL was presigned for. Simulated uploads are not checked.
+func (s *mediaService) validateUploadedFile(ctx context.Context, media *domain.Media) error {
+	if _, ok := s.uploadStorage.(storage.Presigner); !ok {
+		return nil
+	}
+
+	exists, err := s.uploadStorage.Exists(ctx, media.StorageKey())
+	if err != nil {
+		return fmt.Errorf("failed to check uploaded file: %w", err)
+	}
+	if !exists {
+		return domain.NewBusinessError("UPLOAD_NOT_FOUND", "The file was not uploaded to the upload URL")
+	}
 	return nil
 }
 
-// extractMetadata extracts metadata from the uploaded media file
-func (s *mediaService) extractMetadata(media *domain.Media) error {
-	// In production, we would use libraries like FFmpeg to extract video metadata
-	// For now, we'll simulate metadata extraction
+// extractTags reads the tags embedded in the uploaded file. Tags are optional,
+// so a file that cannot be read does not fail processing.
+func (s *mediaService) extractTags(ctx context.Context, media *domain.Media) {
+	if s.tagExtractor == nil {
+		return
+	}
+	if err := s.tagExtractor.ExtractTags(ctx, media); err != nil {
+		log.Printf("Failed to extract tags of media %s: %v", media.ID, err)
+	}
+}
+
+// detectDuplicate links media to the media it is a re-encoded copy of. Detection
+// is best effort, so audio that cannot be fingerprinted does not fail processing.
+func (s *mediaService) detectDuplicate(ctx context.Context, media *domain.Media) {
+	if s.duplicates == nil {
+		return
+	}
+	if err := s.duplicates.DetectDuplicate(ctx, media); err != nil {
+		log.Printf("Failed to detect duplicates of media %s: %v", media.ID, err)
+	}
+}
+
+// suggestChapters suggests the chapter boundaries of media. Suggestions are
+// optional, so audio that cannot be analyzed does not fail processing.
+func (s *mediaService) suggestChapters(ctx context.Context, media *domain.Media) {
+	if s.chapters == nil {
+		return
+	}
+	if err := s.chapters.SuggestChapters(ctx, media); err != nil {
+		log.Printf("Failed to suggest chapters of media %s: %v", media.ID, err)
+	}
+}
+
+// extractMetadata reads the duration, resolution, bitrate and codecs of the uploaded media file
+func (s *mediaService) extractMetadata(ctx context.Context, media *domain.Media) error {
+	if s.metadata == nil || s.uploadStorage == nil {
+		return s.simulateMetadata(media)
+	}
+
+	object, err := s.uploadStorage.Get(ctx, media.StorageKey())
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer object.Body.Close()
+
+	probed, err := s.metadata.Extract(ctx, object.Body)
+	if err != nil {
+		return err
+	}
+	if err := applyMetadata(media, probed); err != nil {
+		return err
+	}
+
+	log.Printf("Extracted metadata for media %s: duration=%ds resolution=%dx%d video=%s audio=%s",
+		media.ID, media.Duration, media.Width, media.Height, media.VideoCodec, media.AudioCodec)
+	return nil
+}
 
+// simulateMetadata fills in a typical duration when no metadata extractor is configured
+func (s *mediaService) simulateMetadata(media *domain.Media) error {
 	switch media.Type {
 	case domain.TypeVideo:
-		// Simulate video metadata extraction
 		if media.Duration == 0 {
 			media.Duration = 300 // 5 minutes default
 		}
 	case domain.TypePodcast:
-		// Simulate audio metadata extraction
 		if media.Duration == 0 {
 			media.Duration = 1800 // 30 minutes default
 		}
@@ -217,3 +899,31 @@ func (s *mediaService) extractMetadata(media *domain.Media) error {
 	fmt.Printf("Extracted metadata for media %s: duration=%d seconds\n", media.ID, media.Duration)
 	return nil
 }
+
+// applyMetadata copies probed metadata onto target, rejecting files without the
+// streams its media type needs. The declared duration is kept when none was probed.
+func applyMetadata(target *domain.Media, probed *media.Metadata) error {
+	switch {
+	case target.Type == domain.TypeVideo && !probed.HasVideo():
+		return fmt.Errorf("%w: video has no video stream", media.ErrInvalidMedia)
+	case target.Type == domain.TypePodcast && !probed.HasAudio():
+		return fmt.Errorf("%w: podcast has no audio stream", media.ErrInvalidMedia)
+	}
+
+	if probed.Duration > 0 {
+		target.Duration = max(int(math.Round(probed.Duration)), 1)
+	}
+	target.Width = probed.Width
+	target.Height = probed.Height
+	target.Bitrate = probed.Bitrate
+	target.VideoCodec = probed.VideoCodec
+	target.AudioCodec = probed.AudioCodec
+	target.AudioChannels = probed.AudioChannels
+	return nil
+}
+
+// isUnreadableMedia reports whether processing failed because the uploaded file
+// is missing or not a media file, rather than for a reason that may go away
+func isUnreadableMedia(err error) bool {
+	return errors.Is(err, media.ErrInvalidMedia) || errors.Is(err, storage.ErrNotFound)
+}
@@ -3,13 +3,22 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/media"
+	"thamaniyah/pkg/storage"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockMediaRepository is a mock implementation of MediaRepository
@@ -48,6 +57,27 @@ func (m *MockMediaRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *MockMediaRepository) GetTrashed(ctx context.Context, deletedBefore time.Time, limit int) ([]*domain.Media, error) {
+	args := m.Called(ctx, deletedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) GetByIDsWithTrashed(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
 func (m *MockMediaRepository) GetByStatus(ctx context.Context, status domain.MediaStatus, limit, offset int) ([]*domain.Media, error) {
 	args := m.Called(ctx, status, limit, offset)
 	if args.Get(0) == nil {
@@ -56,16 +86,206 @@ func (m *MockMediaRepository) GetByStatus(ctx context.Context, status domain.Med
 	return args.Get(0).([]*domain.Media), args.Error(1)
 }
 
+func (m *MockMediaRepository) GetByFilter(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
 func (m *MockMediaRepository) UpdateStatus(ctx context.Context, id string, status domain.MediaStatus) error {
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
 }
 
+func (m *MockMediaRepository) UpdateFailure(ctx context.Context, id, code, message string) error {
+	args := m.Called(ctx, id, code, message)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateGeoRestriction(ctx context.Context, id string, restriction domain.GeoRestriction) error {
+	args := m.Called(ctx, id, restriction)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateContentRating(ctx context.Context, id string, rating domain.ContentRating) error {
+	args := m.Called(ctx, id, rating)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateClassification(ctx context.Context, id string, labels []string, category string) error {
+	args := m.Called(ctx, id, labels, category)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateLicense(ctx context.Context, id string, license domain.License) error {
+	args := m.Called(ctx, id, license)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateCuePoints(ctx context.Context, id string, cuePoints []domain.CuePoint) error {
+	args := m.Called(ctx, id, cuePoints)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateChapters(ctx context.Context, id string, chapters []domain.Chapter, suggestions []domain.ChapterSuggestion) error {
+	args := m.Called(ctx, id, chapters, suggestions)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateAudioTracks(ctx context.Context, id string, tracks []domain.AudioTrack) error {
+	args := m.Called(ctx, id, tracks)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateSeries(ctx context.Context, id, seriesID string, part int, locked bool) error {
+	args := m.Called(ctx, id, seriesID, part, locked)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) GetBySeries(ctx context.Context, seriesID string) ([]*domain.Media, error) {
+	args := m.Called(ctx, seriesID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) UpdateVisibility(ctx context.Context, id string, visibility domain.MediaVisibility) error {
+	args := m.Called(ctx, id, visibility)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdateUnpublishAt(ctx context.Context, id string, unpublishAt *time.Time) error {
+	args := m.Called(ctx, id, unpublishAt)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) GetDueUnpublishes(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) UpdatePremiere(ctx context.Context, id string, premiere domain.Premiere) error {
+	args := m.Called(ctx, id, premiere)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) UpdatePoster(ctx context.Context, id string, poster domain.Poster) error {
+	args := m.Called(ctx, id, poster)
+	return args.Error(0)
+}
+
+func (m *MockMediaRepository) GetDuePremieres(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetScheduledBetween(ctx context.Context, from, end time.Time) ([]*domain.Media, error) {
+	args := m.Called(ctx, from, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetExpiredLicenses(ctx context.Context, now time.Time, limit int) ([]*domain.Media, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetLicensesExpiringBefore(ctx context.Context, now, deadline time.Time, limit int) ([]*domain.Media, error) {
+	args := m.Called(ctx, now, deadline, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) IncrementProcessingAttempts(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockMediaRepository) GetTotal(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockMediaRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Media, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Media), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetAllWithTotal(ctx context.Context, limit, offset int) ([]*domain.Media, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*domain.Media), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockMediaRepository) GetEstimatedTotal(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetTotalByFilter(ctx context.Context, filter *domain.MediaFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetStorageUsage(ctx context.Context, tenantID string) (int64, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMediaRepository) GetByOwner(ctx context.Context, ownerID string, limit, offset int) ([]*domain.Media, int64, error) {
+	args := m.Called(ctx, ownerID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Media), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockMediaRepository) GetByContentHash(ctx context.Context, tenantID, contentHash string) (*domain.Media, error) {
+	args := m.Called(ctx, tenantID, contentHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Media), args.Error(1)
+}
+
+// MockEventPublisher is a mock implementation of EventPublisher
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// newMockEventPublisher returns a publisher accepting any event
+func newMockEventPublisher() *MockEventPublisher {
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return publisher
+}
+
 func TestMediaService_CreateUploadURL(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -101,7 +321,21 @@ func TestMediaService_CreateUploadURL(t *testing.T) {
 			errorType:   "INVALID_REQUEST",
 		},
 		{
-			name: "invalid request - file too large",
+			name: "filename not matching the media type",
+			request: &domain.UploadRequest{
+				Title:    "Episode",
+				Filename: "episode.mp3",
+				FileSize: 1024 * 1024,
+				Type:     domain.TypeVideo,
+			},
+			setupMock: func(mockRepo *MockMediaRepository) {
+				// No expectations as validation should fail before repository call
+			},
+			expectError: true,
+			errorType:   "INVALID_FORMAT",
+		},
+		{
+			name: "file over the default limit",
 			request: &domain.UploadRequest{
 				Title:    "Large Video",
 				Filename: "large.mp4",
@@ -112,7 +346,7 @@ func TestMediaService_CreateUploadURL(t *testing.T) {
 				// No expectations as validation should fail before repository call
 			},
 			expectError: true,
-			errorType:   "INVALID_REQUEST",
+			errorType:   "FILE_TOO_LARGE",
 		},
 		{
 			name: "repository error",
@@ -135,7 +369,7 @@ func TestMediaService_CreateUploadURL(t *testing.T) {
 			// Given
 			mockRepo := new(MockMediaRepository)
 			tt.setupMock(mockRepo)
-			service := NewMediaService(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
 			ctx := context.Background()
 
 			// When
@@ -164,6 +398,72 @@ func TestMediaService_CreateUploadURL(t *testing.T) {
 	}
 }
 
+func TestMediaService_CreateUploadURL_TranscodePreset(t *testing.T) {
+	tests := []struct {
+		name        string
+		preset      *domain.TranscodePreset
+		presetErr   error
+		expectError bool
+	}{
+		{
+			name:   "preset matches media type",
+			preset: &domain.TranscodePreset{ID: "preset-1", MediaType: domain.TypeVideo},
+		},
+		{
+			name:        "preset for another media type",
+			preset:      &domain.TranscodePreset{ID: "preset-1", MediaType: domain.TypePodcast},
+			expectError: true,
+		},
+		{
+			name:        "unknown preset",
+			presetErr:   domain.ErrTranscodePresetNotFound,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mockRepo := new(MockMediaRepository)
+			presetRepo := new(MockTranscodePresetRepository)
+			var preset interface{}
+			if tt.preset != nil {
+				preset = tt.preset
+			}
+			presetRepo.On("GetByID", mock.Anything, "preset-1").Return(preset, tt.presetErr)
+			if !tt.expectError {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(media *domain.Media) bool {
+					return media.TranscodePresetID == "preset-1"
+				})).Return(nil)
+			}
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), Presets: presetRepo})
+
+			// When
+			result, err := service.CreateUploadURL(context.Background(), &domain.UploadRequest{
+				Title:             "Test Video",
+				Filename:          "test.mp4",
+				FileSize:          1024 * 1024,
+				Type:              domain.TypeVideo,
+				TranscodePresetID: "preset-1",
+			})
+
+			// Then
+			if tt.expectError {
+				assert.Nil(t, result)
+				var businessErr *domain.BusinessError
+				if assert.ErrorAs(t, err, &businessErr) {
+					assert.Equal(t, "INVALID_TRANSCODE_PRESET", businessErr.Code)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+			mockRepo.AssertExpectations(t)
+			presetRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestMediaService_ConfirmUpload(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -181,6 +481,9 @@ func TestMediaService_ConfirmUpload(t *testing.T) {
 					Status: domain.StatusUploading,
 				}
 				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).Return(nil)
+				mockRepo.On("IncrementProcessingAttempts", mock.Anything, "media-123").Return(nil)
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).Return(nil)
 				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusReady).Return(nil)
 			},
 			expectError: false,
@@ -216,7 +519,7 @@ func TestMediaService_ConfirmUpload(t *testing.T) {
 					Status: domain.StatusUploading,
 				}
 				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
-				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusReady).
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).
 					Return(errors.New("database error"))
 			},
 			expectError: true,
@@ -228,7 +531,7 @@ func TestMediaService_ConfirmUpload(t *testing.T) {
 			// Given
 			mockRepo := new(MockMediaRepository)
 			tt.setupMock(mockRepo)
-			service := NewMediaService(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
 			ctx := context.Background()
 
 			// When
@@ -252,6 +555,199 @@ func TestMediaService_ConfirmUpload(t *testing.T) {
 	}
 }
 
+func TestMediaService_ConfirmUpload_EnqueuesProcessing(t *testing.T) {
+	// Given
+	mockRepo := new(MockMediaRepository)
+	media := &domain.Media{
+		ID:       "media-123",
+		Status:   domain.StatusUploading,
+		Priority: domain.PriorityHigh,
+	}
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).Return(nil)
+
+	queue := NewPriorityProcessingQueue(10, 5)
+	service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), ProcessingQueue: queue})
+
+	// When
+	err := service.ConfirmUpload(context.Background(), "media-123")
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusProcessing, media.Status)
+
+	job, err := queue.Dequeue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "media-123", job.MediaID)
+	assert.Equal(t, domain.PriorityHigh, job.Priority)
+
+	// Processing is left to the worker
+	mockRepo.AssertNotCalled(t, "IncrementProcessingAttempts", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMediaService_ConfirmUpload_QueueFull(t *testing.T) {
+	// Given a full processing queue
+	mockRepo := new(MockMediaRepository)
+	media := &domain.Media{ID: "media-123", Status: domain.StatusUploading}
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).Return(nil)
+
+	queue := NewPriorityProcessingQueue(1, 5)
+	require.NoError(t, queue.Enqueue(context.Background(), &ProcessingJob{MediaID: "other", Priority: domain.PriorityNormal}))
+	publisher := newMockEventPublisher()
+	transactor := &stubTransactor{}
+	service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: publisher, ProcessingQueue: queue, Transactor: transactor})
+
+	// When
+	err := service.ConfirmUpload(context.Background(), "media-123")
+
+	// Then the caller is told to retry, the status change is rolled back and nothing is processed inline
+	assert.ErrorIs(t, err, domain.ErrProcessingQueueFull)
+	assert.Equal(t, 0, transactor.committed)
+	assert.Equal(t, domain.StatusUploading, media.Status)
+	publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "IncrementProcessingAttempts", mock.Anything, mock.Anything)
+}
+
+func TestMediaService_ConfirmUpload_UploadEvents(t *testing.T) {
+	// Given uploads taken by the worker from their events
+	mockRepo := new(MockMediaRepository)
+	media := &domain.Media{ID: "media-123", Status: domain.StatusUploading}
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).Return(nil)
+
+	queue := NewPriorityProcessingQueue(10, 5)
+	publisher := newMockEventPublisher()
+	service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: publisher, ProcessingQueue: queue, UploadEvents: true})
+
+	// When
+	err := service.ConfirmUpload(context.Background(), "media-123")
+
+	// Then the upload is announced and not queued
+	require.NoError(t, err)
+	publisher.AssertCalled(t, "Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaUploaded
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = queue.Dequeue(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// presigningStorage issues presigned URLs for objects of a local storage
+type presigningStorage struct {
+	*storage.LocalStorage
+}
+
+func (s presigningStorage) PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return fmt.Sprintf("https://bucket.example.com/%s?expires=%d", key, int(ttl.Seconds())), nil
+}
+
+func TestMediaService_PresignedUploads(t *testing.T) {
+	// Given a storage presigning upload URLs
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	store := presigningStorage{storage.NewLocalStorage(t.TempDir())}
+	service := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), ProcessingQueue: NewPriorityProcessingQueue(10, 5), UploadStorage: store})
+
+	// When an upload URL is requested
+	uploadURL, err := service.CreateUploadURL(ctx, &domain.UploadRequest{Title: "Talk", Filename: "talk.mp4", FileSize: 100, Type: domain.TypeVideo})
+
+	// Then it is presigned for the storage key of the media
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("https://bucket.example.com/%s.mp4?expires=3600", uploadURL.MediaID), uploadURL.URL)
+
+	// When the upload is confirmed before the file is stored
+	err = service.ConfirmUpload(ctx, uploadURL.MediaID)
+
+	// Then the upload fails validation
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "UPLOAD_NOT_FOUND", businessErr.Code)
+	media, err := mediaRepo.GetByID(ctx, uploadURL.MediaID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusFailed, media.Status)
+	assert.Equal(t, domain.FailureUploadValidation, media.FailureCode)
+
+	// When another upload is stored before it is confirmed
+	uploadURL, err = service.CreateUploadURL(ctx, &domain.UploadRequest{Title: "Talk", Filename: "talk.mp4", FileSize: 100, Type: domain.TypeVideo})
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, uploadURL.MediaID+".mp4", strings.NewReader("video")))
+	err = service.ConfirmUpload(ctx, uploadURL.MediaID)
+
+	// Then it is handed over to processing
+	require.NoError(t, err)
+	media, err = mediaRepo.GetByID(ctx, uploadURL.MediaID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusProcessing, media.Status)
+}
+
+func TestMediaService_DirectUploads(t *testing.T) {
+	// Given local storage issuing upload URLs to the service
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	store, err := storage.NewPresigningLocalStorage(storage.NewLocalStorage(t.TempDir()), "http://cms.example.com/", []byte("secret"))
+	require.NoError(t, err)
+	service := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), ProcessingQueue: NewPriorityProcessingQueue(10, 5), UploadStorage: store})
+	video := "\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"
+
+	// When an upload URL is requested
+	uploadURL, err := service.CreateUploadURL(ctx, &domain.UploadRequest{Title: "Talk", Filename: "talk.mp4", FileSize: int64(len(video)), Type: domain.TypeVideo})
+	require.NoError(t, err)
+
+	// Then it points at the upload endpoint
+	parsed, err := url.Parse(uploadURL.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "cms.example.com", parsed.Host)
+	assert.Equal(t, "/upload/"+uploadURL.MediaID+".mp4", parsed.Path)
+	expires, err := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	upload := func(signature, content string) error {
+		return service.ReceiveUpload(ctx, &domain.DirectUpload{
+			Key:       uploadURL.MediaID + ".mp4",
+			Expires:   expires,
+			Signature: signature,
+			Body:      strings.NewReader(content),
+			Size:      -1,
+		})
+	}
+
+	t.Run("rejects forged URLs", func(t *testing.T) {
+		assert.ErrorIs(t, upload(strings.Repeat("0", 64), video), domain.ErrInvalidUploadURL)
+	})
+
+	t.Run("rejects files that are not media", func(t *testing.T) {
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, upload(parsed.Query().Get("signature"), "<html>hi</html>"), &businessErr)
+		assert.Equal(t, "INVALID_FORMAT", businessErr.Code)
+	})
+
+	t.Run("rejects files larger than declared", func(t *testing.T) {
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, upload(parsed.Query().Get("signature"), video+"trailing"), &businessErr)
+		assert.Equal(t, "FILE_TOO_LARGE", businessErr.Code)
+		exists, err := store.Exists(ctx, uploadURL.MediaID+".mp4")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("stores the file for the upload to be confirmed", func(t *testing.T) {
+		require.NoError(t, upload(parsed.Query().Get("signature"), video))
+		require.NoError(t, service.ConfirmUpload(ctx, uploadURL.MediaID))
+
+		media, err := mediaRepo.GetByID(ctx, uploadURL.MediaID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusProcessing, media.Status)
+	})
+
+	t.Run("rejects uploads after confirmation", func(t *testing.T) {
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, upload(parsed.Query().Get("signature"), video), &businessErr)
+		assert.Equal(t, "INVALID_STATUS", businessErr.Code)
+	})
+}
+
 func TestMediaService_GetMedia(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -295,7 +791,7 @@ func TestMediaService_GetMedia(t *testing.T) {
 			// Given
 			mockRepo := new(MockMediaRepository)
 			tt.setupMock(mockRepo)
-			service := NewMediaService(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
 			ctx := context.Background()
 
 			// When
@@ -320,6 +816,7 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 		name      string
 		limit     int
 		offset    int
+		countMode domain.CountMode
 		setupMock func(*MockMediaRepository)
 		wantErr   bool
 	}{
@@ -332,8 +829,18 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 					{ID: "media-1", Title: "Video 1"},
 					{ID: "media-2", Title: "Video 2"},
 				}
-				mockRepo.On("GetAll", mock.Anything, 20, 0).Return(expectedMedia, nil)
-				mockRepo.On("GetTotal", mock.Anything).Return(int64(100), nil)
+				mockRepo.On("GetAllWithTotal", mock.Anything, 20, 0).Return(expectedMedia, int64(100), nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "estimated count",
+			limit:     20,
+			offset:    0,
+			countMode: domain.CountEstimated,
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetAll", mock.Anything, 20, 0).Return([]*domain.Media{}, nil)
+				mockRepo.On("GetEstimatedTotal", mock.Anything).Return(int64(98), nil)
 			},
 			wantErr: false,
 		},
@@ -343,8 +850,7 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 			offset: 0,
 			setupMock: func(mockRepo *MockMediaRepository) {
 				expectedMedia := []*domain.Media{}
-				mockRepo.On("GetAll", mock.Anything, domain.DefaultPageSize, 0).Return(expectedMedia, nil)
-				mockRepo.On("GetTotal", mock.Anything).Return(int64(0), nil)
+				mockRepo.On("GetAllWithTotal", mock.Anything, domain.DefaultPageSize, 0).Return(expectedMedia, int64(0), nil)
 			},
 			wantErr: false,
 		},
@@ -354,8 +860,7 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 			offset: 0,
 			setupMock: func(mockRepo *MockMediaRepository) {
 				expectedMedia := []*domain.Media{}
-				mockRepo.On("GetAll", mock.Anything, domain.DefaultPageSize, 0).Return(expectedMedia, nil)
-				mockRepo.On("GetTotal", mock.Anything).Return(int64(0), nil)
+				mockRepo.On("GetAllWithTotal", mock.Anything, domain.DefaultPageSize, 0).Return(expectedMedia, int64(0), nil)
 			},
 			wantErr: false,
 		},
@@ -365,8 +870,7 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 			offset: -10,
 			setupMock: func(mockRepo *MockMediaRepository) {
 				expectedMedia := []*domain.Media{}
-				mockRepo.On("GetAll", mock.Anything, 20, 0).Return(expectedMedia, nil)
-				mockRepo.On("GetTotal", mock.Anything).Return(int64(0), nil)
+				mockRepo.On("GetAllWithTotal", mock.Anything, 20, 0).Return(expectedMedia, int64(0), nil)
 			},
 			wantErr: false,
 		},
@@ -375,8 +879,8 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 			limit:  20,
 			offset: 0,
 			setupMock: func(mockRepo *MockMediaRepository) {
-				mockRepo.On("GetAll", mock.Anything, 20, 0).
-					Return(nil, errors.New("database error"))
+				mockRepo.On("GetAllWithTotal", mock.Anything, 20, 0).
+					Return(nil, int64(0), errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -387,11 +891,11 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 			// Given
 			mockRepo := new(MockMediaRepository)
 			tt.setupMock(mockRepo)
-			service := NewMediaService(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
 			ctx := context.Background()
 
 			// When
-			mediaList, total, err := service.GetAllMedia(ctx, tt.limit, tt.offset)
+			mediaList, total, err := service.GetAllMedia(ctx, tt.limit, tt.offset, tt.countMode)
 
 			// Then
 			if tt.wantErr {
@@ -409,22 +913,146 @@ func TestMediaService_GetAllMedia(t *testing.T) {
 	}
 }
 
-func TestMediaService_UpdateMedia(t *testing.T) {
+func TestMediaService_GetFilteredMedia(t *testing.T) {
+	failedFilter := &domain.MediaFilter{Status: domain.StatusFailed}
+	failureCodeFilter := &domain.MediaFilter{
+		Status:      domain.StatusFailed,
+		FailureCode: domain.FailureMetadataExtraction,
+	}
+
 	tests := []struct {
-		name        string
-		mediaID     string
-		request     *domain.UpdateMediaRequest
-		setupMock   func(*MockMediaRepository)
-		expectError bool
+		name      string
+		filter    *domain.MediaFilter
+		limit     int
+		offset    int
+		setupMock func(*MockMediaRepository)
+		wantErr   bool
 	}{
 		{
-			name:    "successful media update",
-			mediaID: "media-123",
-			request: &domain.UpdateMediaRequest{
-				Title:       stringPtr("Updated Title"),
-				Description: stringPtr("Updated Description"),
-			},
-			setupMock: func(mockRepo *MockMediaRepository) {
+			name:   "successful get failed media",
+			filter: failedFilter,
+			limit:  20,
+			offset: 0,
+			setupMock: func(mockRepo *MockMediaRepository) {
+				expectedMedia := []*domain.Media{
+					{ID: "media-1", Title: "Video 1", Status: domain.StatusFailed},
+				}
+				mockRepo.On("GetByFilter", mock.Anything, failedFilter, 20, 0).Return(expectedMedia, nil)
+				mockRepo.On("GetTotalByFilter", mock.Anything, failedFilter).Return(int64(1), nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "filter by failure code",
+			filter: failureCodeFilter,
+			limit:  20,
+			offset: 0,
+			setupMock: func(mockRepo *MockMediaRepository) {
+				expectedMedia := []*domain.Media{
+					{ID: "media-1", Status: domain.StatusFailed, FailureCode: domain.FailureMetadataExtraction},
+				}
+				mockRepo.On("GetByFilter", mock.Anything, failureCodeFilter, 20, 0).Return(expectedMedia, nil)
+				mockRepo.On("GetTotalByFilter", mock.Anything, failureCodeFilter).Return(int64(1), nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "limit too large - use default",
+			filter: failedFilter,
+			limit:  200,
+			offset: -5,
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetByFilter", mock.Anything, failedFilter, domain.DefaultPageSize, 0).
+					Return([]*domain.Media{}, nil)
+				mockRepo.On("GetTotalByFilter", mock.Anything, failedFilter).Return(int64(0), nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "repository error",
+			filter: failedFilter,
+			limit:  20,
+			offset: 0,
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetByFilter", mock.Anything, failedFilter, 20, 0).
+					Return(nil, errors.New("database error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:   "count error",
+			filter: failedFilter,
+			limit:  20,
+			offset: 0,
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetByFilter", mock.Anything, failedFilter, 20, 0).Return([]*domain.Media{}, nil)
+				mockRepo.On("GetTotalByFilter", mock.Anything, failedFilter).
+					Return(int64(0), errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mockRepo := new(MockMediaRepository)
+			tt.setupMock(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
+			ctx := context.Background()
+
+			// When
+			mediaList, total, err := service.GetFilteredMedia(ctx, tt.filter, tt.limit, tt.offset)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, mediaList)
+				assert.Zero(t, total)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, mediaList)
+				assert.GreaterOrEqual(t, total, int64(0))
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMediaService_GetMediaByOwner(t *testing.T) {
+	// Given
+	owned := []*domain.Media{{ID: "media-2", OwnerID: "user-1"}, {ID: "media-1", OwnerID: "user-1"}}
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByOwner", mock.Anything, "user-1", domain.DefaultPageSize, 0).Return(owned, int64(2), nil)
+	service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
+
+	// When an out of range page is requested
+	mediaList, total, err := service.GetMediaByOwner(context.Background(), "user-1", 0, -1)
+
+	// Then the default page is listed
+	require.NoError(t, err)
+	assert.Equal(t, owned, mediaList)
+	assert.Equal(t, int64(2), total)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMediaService_UpdateMedia(t *testing.T) {
+	tests := []struct {
+		name        string
+		mediaID     string
+		request     *domain.UpdateMediaRequest
+		setupMock   func(*MockMediaRepository)
+		expectError bool
+	}{
+		{
+			name:    "successful media update",
+			mediaID: "media-123",
+			request: &domain.UpdateMediaRequest{
+				Title:       stringPtr("Updated Title"),
+				Description: stringPtr("Updated Description"),
+			},
+			setupMock: func(mockRepo *MockMediaRepository) {
 				originalMedia := &domain.Media{
 					ID:          "media-123",
 					Title:       "Original Title",
@@ -435,6 +1063,52 @@ func TestMediaService_UpdateMedia(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:    "retitling links the series part",
+			mediaID: "media-123",
+			request: &domain.UpdateMediaRequest{
+				Title: stringPtr("Episode 2"),
+			},
+			setupMock: func(mockRepo *MockMediaRepository) {
+				originalMedia := &domain.Media{
+					ID:     "media-123",
+					Title:  "Original Title",
+					ShowID: "show",
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(originalMedia, nil)
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).Return(nil)
+				mockRepo.On("UpdateSeries", mock.Anything, "media-123", mock.AnythingOfType("string"), 2, false).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:    "clearing the explicit flag",
+			mediaID: "media-123",
+			request: &domain.UpdateMediaRequest{
+				ContentRating: &domain.ContentRating{AgeRating: domain.AgeRating13},
+			},
+			setupMock: func(mockRepo *MockMediaRepository) {
+				originalMedia := &domain.Media{
+					ID:            "media-123",
+					Title:         "Original Title",
+					ContentRating: domain.ContentRating{AgeRating: domain.AgeRating16, Explicit: true},
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(originalMedia, nil)
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).Return(nil)
+				mockRepo.On("UpdateContentRating", mock.Anything, "media-123",
+					domain.ContentRating{AgeRating: domain.AgeRating13}).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:    "invalid visibility",
+			mediaID: "media-123",
+			request: &domain.UpdateMediaRequest{
+				Visibility: visibilityPtr("secret"),
+			},
+			setupMock:   func(mockRepo *MockMediaRepository) {},
+			expectError: true,
+		},
 		{
 			name:    "media not found",
 			mediaID: "non-existent",
@@ -471,7 +1145,7 @@ func TestMediaService_UpdateMedia(t *testing.T) {
 			// Given
 			mockRepo := new(MockMediaRepository)
 			tt.setupMock(mockRepo)
-			service := NewMediaService(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
 			ctx := context.Background()
 
 			// When
@@ -497,6 +1171,78 @@ func TestMediaService_UpdateMedia(t *testing.T) {
 	}
 }
 
+func TestMediaService_SetGeoRestriction(t *testing.T) {
+	tests := []struct {
+		name        string
+		restriction domain.GeoRestriction
+		setupMock   func(*MockMediaRepository)
+		expected    domain.GeoRestriction
+		expectError bool
+	}{
+		{
+			name:        "normalizes and stores country rules",
+			restriction: domain.GeoRestriction{AllowedCountries: []string{"sa", "AE", "sa"}, BlockedCountries: []string{"us"}},
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123"}, nil)
+				mockRepo.On("UpdateGeoRestriction", mock.Anything, "media-123", domain.GeoRestriction{
+					AllowedCountries: []string{"SA", "AE"},
+					BlockedCountries: []string{"US"},
+				}).Return(nil)
+			},
+			expected: domain.GeoRestriction{AllowedCountries: []string{"SA", "AE"}, BlockedCountries: []string{"US"}},
+		},
+		{
+			name:        "clears country rules",
+			restriction: domain.GeoRestriction{},
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{
+					ID:             "media-123",
+					GeoRestriction: domain.GeoRestriction{BlockedCountries: []string{"US"}},
+				}, nil)
+				mockRepo.On("UpdateGeoRestriction", mock.Anything, "media-123", domain.GeoRestriction{}).Return(nil)
+			},
+			expected: domain.GeoRestriction{},
+		},
+		{
+			name:        "invalid country code",
+			restriction: domain.GeoRestriction{BlockedCountries: []string{"USA"}},
+			setupMock:   func(mockRepo *MockMediaRepository) {},
+			expectError: true,
+		},
+		{
+			name:        "media not found",
+			restriction: domain.GeoRestriction{BlockedCountries: []string{"US"}},
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(nil, domain.ErrMediaNotFound)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mockRepo := new(MockMediaRepository)
+			tt.setupMock(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
+
+			// When
+			result, err := service.SetGeoRestriction(context.Background(), "media-123", tt.restriction)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result.GeoRestriction)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestMediaService_DeleteMedia(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -509,14 +1255,44 @@ func TestMediaService_DeleteMedia(t *testing.T) {
 			mediaID: "media-123",
 			setupMock: func(mockRepo *MockMediaRepository) {
 				existingMedia := &domain.Media{
-					ID:    "media-123",
-					Title: "Test Video",
+					ID:     "media-123",
+					Title:  "Test Video",
+					Status: domain.StatusReady,
 				}
 				mockRepo.On("GetByID", mock.Anything, "media-123").Return(existingMedia, nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusDeleted).Return(nil)
 				mockRepo.On("Delete", mock.Anything, "media-123").Return(nil)
 			},
 			expectError: false,
 		},
+		{
+			name:    "abandoned upload",
+			mediaID: "media-123",
+			setupMock: func(mockRepo *MockMediaRepository) {
+				existingMedia := &domain.Media{
+					ID:     "media-123",
+					Title:  "Test Video",
+					Status: domain.StatusUploading,
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(existingMedia, nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusDeleted).Return(nil)
+				mockRepo.On("Delete", mock.Anything, "media-123").Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:    "illegal transition - already deleted",
+			mediaID: "media-123",
+			setupMock: func(mockRepo *MockMediaRepository) {
+				existingMedia := &domain.Media{
+					ID:     "media-123",
+					Title:  "Test Video",
+					Status: domain.StatusDeleted,
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(existingMedia, nil)
+			},
+			expectError: true,
+		},
 		{
 			name:    "media not found",
 			mediaID: "non-existent",
@@ -531,10 +1307,12 @@ func TestMediaService_DeleteMedia(t *testing.T) {
 			mediaID: "media-123",
 			setupMock: func(mockRepo *MockMediaRepository) {
 				existingMedia := &domain.Media{
-					ID:    "media-123",
-					Title: "Test Video",
+					ID:     "media-123",
+					Title:  "Test Video",
+					Status: domain.StatusReady,
 				}
 				mockRepo.On("GetByID", mock.Anything, "media-123").Return(existingMedia, nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusDeleted).Return(nil)
 				mockRepo.On("Delete", mock.Anything, "media-123").Return(errors.New("database error"))
 			},
 			expectError: true,
@@ -546,7 +1324,7 @@ func TestMediaService_DeleteMedia(t *testing.T) {
 			// Given
 			mockRepo := new(MockMediaRepository)
 			tt.setupMock(mockRepo)
-			service := NewMediaService(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
 			ctx := context.Background()
 
 			// When
@@ -564,6 +1342,127 @@ func TestMediaService_DeleteMedia(t *testing.T) {
 	}
 }
 
+func TestMediaService_ConfirmUpload_PublishesUpload(t *testing.T) {
+	// Given
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123", Status: domain.StatusUploading}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).Return(nil)
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaStatusChanged
+	})).Return(nil)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaUploaded && event.Data["media_id"] == "media-123"
+	})).Return(nil).Once()
+	service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: publisher, ProcessingQueue: NewPriorityProcessingQueue(10, 5)})
+
+	// When
+	err := service.ConfirmUpload(context.Background(), "media-123")
+
+	// Then
+	assert.NoError(t, err)
+	publisher.AssertExpectations(t)
+}
+
+func TestMediaService_UpdateMedia_PublishesUpdate(t *testing.T) {
+	// Given
+	title := "New title"
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123", Title: "Old title", Status: domain.StatusReady}, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).Return(nil)
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaUpdated && event.Data["media_id"] == "media-123"
+	})).Return(nil).Once()
+	service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: publisher})
+
+	// When
+	_, err := service.UpdateMedia(context.Background(), "media-123", &domain.UpdateMediaRequest{Title: &title})
+
+	// Then
+	assert.NoError(t, err)
+	publisher.AssertExpectations(t)
+}
+
+func TestMediaService_DeleteMedia_PublishesDeletion(t *testing.T) {
+	// Given
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123", Status: domain.StatusReady}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusDeleted).Return(nil)
+	mockRepo.On("Delete", mock.Anything, "media-123").Return(nil)
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaStatusChanged
+	})).Return(nil)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaDeleted && event.Data["media_id"] == "media-123"
+	})).Return(nil).Once()
+	service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: publisher})
+
+	// When
+	err := service.DeleteMedia(context.Background(), "media-123")
+
+	// Then
+	assert.NoError(t, err)
+	publisher.AssertExpectations(t)
+}
+
+// inTransactionKey marks the context of a unit of work run by stubTransactor
+type inTransactionKey struct{}
+
+// stubTransactor runs units of work in a marked context and counts those committed
+type stubTransactor struct {
+	committed int
+}
+
+func (t *stubTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(context.WithValue(ctx, inTransactionKey{}, true)); err != nil {
+		return err
+	}
+	t.committed++
+	return nil
+}
+
+func TestMediaService_DeleteMedia_Outbox(t *testing.T) {
+	inTransaction := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(inTransactionKey{}) != nil })
+
+	t.Run("events are appended in the transaction of the deletion", func(t *testing.T) {
+		mockRepo := new(MockMediaRepository)
+		mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123", Status: domain.StatusReady}, nil)
+		mockRepo.On("UpdateStatus", inTransaction, "media-123", domain.StatusDeleted).Return(nil)
+		mockRepo.On("Delete", inTransaction, "media-123").Return(nil)
+		outbox := new(MockEventPublisher)
+		outbox.On("Publish", inTransaction, mock.Anything).Return(nil).Twice()
+		publisher := newMockEventPublisher()
+		transactor := &stubTransactor{}
+		service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: publisher, Outbox: outbox, Transactor: transactor})
+
+		require.NoError(t, service.DeleteMedia(context.Background(), "media-123"))
+
+		assert.Equal(t, 1, transactor.committed)
+		outbox.AssertExpectations(t)
+		publisher.AssertNumberOfCalls(t, "Publish", 2)
+	})
+
+	t.Run("a failed append fails the deletion", func(t *testing.T) {
+		mockRepo := new(MockMediaRepository)
+		mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123", Status: domain.StatusReady}, nil)
+		mockRepo.On("UpdateStatus", inTransaction, "media-123", domain.StatusDeleted).Return(nil)
+		mockRepo.On("Delete", inTransaction, "media-123").Return(nil)
+		outbox := new(MockEventPublisher)
+		outbox.On("Publish", mock.Anything, mock.Anything).Return(errors.New("outbox unavailable"))
+		publisher := new(MockEventPublisher)
+		transactor := &stubTransactor{}
+		service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: publisher, Outbox: outbox, Transactor: transactor})
+
+		err := service.DeleteMedia(context.Background(), "media-123")
+
+		assert.Error(t, err)
+		assert.Zero(t, transactor.committed)
+		publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
+
 func TestMediaService_ProcessMedia(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -578,13 +1477,46 @@ func TestMediaService_ProcessMedia(t *testing.T) {
 				media := &domain.Media{
 					ID:       "media-123",
 					Type:     domain.TypeVideo,
+					Status:   domain.StatusProcessing,
 					Duration: 0, // Will be set by extractMetadata
 				}
 				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				mockRepo.On("IncrementProcessingAttempts", mock.Anything, "media-123").Return(nil)
 				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).Return(nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusReady).Return(nil)
 			},
 			expectError: false,
 		},
+		{
+			name:    "failed media moves back through processing",
+			mediaID: "media-123",
+			setupMock: func(mockRepo *MockMediaRepository) {
+				media := &domain.Media{
+					ID:     "media-123",
+					Type:   domain.TypePodcast,
+					Status: domain.StatusFailed,
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).Return(nil)
+				mockRepo.On("IncrementProcessingAttempts", mock.Anything, "media-123").Return(nil)
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).Return(nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusReady).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:    "illegal transition - already ready",
+			mediaID: "media-123",
+			setupMock: func(mockRepo *MockMediaRepository) {
+				media := &domain.Media{
+					ID:     "media-123",
+					Type:   domain.TypeVideo,
+					Status: domain.StatusReady,
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+			},
+			expectError: true,
+		},
 		{
 			name:    "media not found",
 			mediaID: "non-existent",
@@ -599,10 +1531,12 @@ func TestMediaService_ProcessMedia(t *testing.T) {
 			mediaID: "media-123",
 			setupMock: func(mockRepo *MockMediaRepository) {
 				media := &domain.Media{
-					ID:   "media-123",
-					Type: domain.TypeVideo,
+					ID:     "media-123",
+					Type:   domain.TypeVideo,
+					Status: domain.StatusProcessing,
 				}
 				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				mockRepo.On("IncrementProcessingAttempts", mock.Anything, "media-123").Return(nil)
 				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).
 					Return(errors.New("database error"))
 			},
@@ -615,7 +1549,7 @@ func TestMediaService_ProcessMedia(t *testing.T) {
 			// Given
 			mockRepo := new(MockMediaRepository)
 			tt.setupMock(mockRepo)
-			service := NewMediaService(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
 			ctx := context.Background()
 
 			// When
@@ -633,7 +1567,431 @@ func TestMediaService_ProcessMedia(t *testing.T) {
 	}
 }
 
+// stubMetadataExtractor returns fixed metadata for every file
+type stubMetadataExtractor struct {
+	metadata *media.Metadata
+	err      error
+}
+
+func (e *stubMetadataExtractor) Extract(ctx context.Context, src io.Reader) (*media.Metadata, error) {
+	if _, err := io.Copy(io.Discard, src); err != nil {
+		return nil, err
+	}
+	return e.metadata, e.err
+}
+
+func TestMediaService_ProcessMedia_ExtractsMetadata(t *testing.T) {
+	video := &media.Metadata{Duration: 61.6, Bitrate: 2_500_000, Width: 1920, Height: 1080, VideoCodec: "h264", AudioCodec: "aac", AudioChannels: 2}
+	tests := []struct {
+		name            string
+		mediaType       domain.MediaType
+		uploaded        bool
+		extractor       *stubMetadataExtractor
+		expectedStatus  domain.MediaStatus
+		expectedFailure string
+	}{
+		{
+			name:           "video metadata is stored",
+			mediaType:      domain.TypeVideo,
+			uploaded:       true,
+			extractor:      &stubMetadataExtractor{metadata: video},
+			expectedStatus: domain.StatusReady,
+		},
+		{
+			name:            "unreadable file fails",
+			mediaType:       domain.TypeVideo,
+			uploaded:        true,
+			extractor:       &stubMetadataExtractor{err: fmt.Errorf("%w: moov atom not found", media.ErrInvalidMedia)},
+			expectedStatus:  domain.StatusFailed,
+			expectedFailure: domain.FailureMetadataExtraction,
+		},
+		{
+			name:            "video without a video stream fails",
+			mediaType:       domain.TypeVideo,
+			uploaded:        true,
+			extractor:       &stubMetadataExtractor{metadata: &media.Metadata{Duration: 60, AudioCodec: "mp3", AudioChannels: 2}},
+			expectedStatus:  domain.StatusFailed,
+			expectedFailure: domain.FailureMetadataExtraction,
+		},
+		{
+			name:            "missing file fails",
+			mediaType:       domain.TypePodcast,
+			extractor:       &stubMetadataExtractor{metadata: video},
+			expectedStatus:  domain.StatusFailed,
+			expectedFailure: domain.FailureMetadataExtraction,
+		},
+		{
+			name:           "transient error leaves the media for a retry",
+			mediaType:      domain.TypePodcast,
+			uploaded:       true,
+			extractor:      &stubMetadataExtractor{err: errors.New("ffprobe: signal: killed")},
+			expectedStatus: domain.StatusProcessing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given an upload waiting for processing
+			ctx := context.Background()
+			mediaRepo := repository.NewInMemoryMediaRepository()
+			item := &domain.Media{ID: "media-123", Type: tt.mediaType, Format: "mp4", FilePath: "uploads/media-123.mp4", Duration: 30, Status: domain.StatusProcessing}
+			require.NoError(t, mediaRepo.Create(ctx, item))
+			store := storage.NewLocalStorage(t.TempDir())
+			if tt.uploaded {
+				require.NoError(t, store.Put(ctx, item.StorageKey(), strings.NewReader("file")))
+			}
+			service := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), UploadStorage: store, Metadata: tt.extractor})
+
+			// When
+			err := service.ProcessMedia(ctx, item.ID)
+
+			// Then
+			stored, getErr := mediaRepo.GetByID(ctx, item.ID)
+			require.NoError(t, getErr)
+			assert.Equal(t, tt.expectedStatus, stored.Status)
+			assert.Equal(t, tt.expectedFailure, stored.FailureCode)
+			if tt.expectedStatus != domain.StatusReady {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 62, stored.Duration)
+			assert.Equal(t, 1920, stored.Width)
+			assert.Equal(t, 1080, stored.Height)
+			assert.Equal(t, int64(2_500_000), stored.Bitrate)
+			assert.Equal(t, "h264", stored.VideoCodec)
+			assert.Equal(t, "aac", stored.AudioCodec)
+			assert.Equal(t, 2, stored.AudioChannels)
+		})
+	}
+}
+
+// stubDuplicateDetector links every media item to the same original
+type stubDuplicateDetector struct {
+	originalID string
+	err        error
+}
+
+func (d *stubDuplicateDetector) DetectDuplicate(ctx context.Context, media *domain.Media) error {
+	if d.err != nil {
+		return d.err
+	}
+	media.DuplicateOfID = d.originalID
+	return nil
+}
+
+func TestMediaService_ProcessMedia_DetectsDuplicates(t *testing.T) {
+	tests := []struct {
+		name              string
+		detector          *stubDuplicateDetector
+		expectedDuplicate string
+	}{
+		{
+			name:              "saves the link to the original",
+			detector:          &stubDuplicateDetector{originalID: "media-1"},
+			expectedDuplicate: "media-1",
+		},
+		{
+			name:     "processes audio that cannot be fingerprinted",
+			detector: &stubDuplicateDetector{err: errors.New("no audio stream")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123", Type: domain.TypePodcast, Status: domain.StatusProcessing}, nil)
+			mockRepo.On("IncrementProcessingAttempts", mock.Anything, "media-123").Return(nil)
+			mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(media *domain.Media) bool {
+				return media.DuplicateOfID == tt.expectedDuplicate
+			})).Return(nil)
+			mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusReady).Return(nil)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), Duplicates: tt.detector})
+
+			// When
+			err := service.ProcessMedia(context.Background(), "media-123")
+
+			// Then
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMediaService_ReprocessMedia(t *testing.T) {
+	tests := []struct {
+		name        string
+		mediaID     string
+		setupMock   func(*MockMediaRepository)
+		expectError bool
+		errorType   string
+	}{
+		{
+			name:    "successful reprocess of failed media",
+			mediaID: "media-123",
+			setupMock: func(mockRepo *MockMediaRepository) {
+				media := &domain.Media{
+					ID:                 "media-123",
+					Type:               domain.TypeVideo,
+					Status:             domain.StatusFailed,
+					ProcessingAttempts: 1,
+					FailureCode:        domain.FailureMetadataExtraction,
+					FailureMessage:     "corrupt container",
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusProcessing).Return(nil)
+				mockRepo.On("UpdateFailure", mock.Anything, "media-123", "", "").Return(nil)
+				mockRepo.On("IncrementProcessingAttempts", mock.Anything, "media-123").Return(nil)
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Media")).Return(nil)
+				mockRepo.On("UpdateStatus", mock.Anything, "media-123", domain.StatusReady).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:    "media not found",
+			mediaID: "non-existent",
+			setupMock: func(mockRepo *MockMediaRepository) {
+				mockRepo.On("GetByID", mock.Anything, "non-existent").
+					Return(nil, domain.ErrMediaNotFound)
+			},
+			expectError: true,
+		},
+		{
+			name:    "media is not failed",
+			mediaID: "media-123",
+			setupMock: func(mockRepo *MockMediaRepository) {
+				media := &domain.Media{
+					ID:     "media-123",
+					Status: domain.StatusReady,
+				}
+				mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+			},
+			expectError: true,
+			errorType:   "INVALID_STATUS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mockRepo := new(MockMediaRepository)
+			tt.setupMock(mockRepo)
+			service := NewMediaService(mockRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
+			ctx := context.Background()
+
+			// When
+			result, err := service.ReprocessMedia(ctx, tt.mediaID)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				if tt.errorType != "" {
+					var businessErr *domain.BusinessError
+					if errors.As(err, &businessErr) {
+						assert.Equal(t, tt.errorType, businessErr.Code)
+					}
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.StatusReady, result.Status)
+				assert.Equal(t, 2, result.ProcessingAttempts)
+				assert.Empty(t, result.FailureCode)
+				assert.Empty(t, result.FailureMessage)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
 }
+
+// Helper function to create media visibility pointer
+func visibilityPtr(v string) *domain.MediaVisibility {
+	visibility := domain.MediaVisibility(v)
+	return &visibility
+}
+
+func TestMediaService_ValidateUpload(t *testing.T) {
+	// Given a tenant with a 1000 byte quota, 600 bytes of which are used by a file with a known hash
+	ctx := context.Background()
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{
+		ID: "existing", Title: "Existing", Type: domain.TypePodcast, Status: domain.StatusReady, FileSize: 600, ContentHash: hash,
+	}))
+	limits, err := NewUploadLimitPolicy(UploadLimitOptions{
+		URLTTL: time.Hour, MaxVideoFileSize: 10000, MaxPodcastFileSize: 500, StorageQuota: 1000,
+	})
+	require.NoError(t, err)
+	service := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), UploadLimits: limits})
+
+	tests := []struct {
+		name   string
+		req    *domain.UploadRequest
+		fields []string
+	}{
+		{
+			name:   "valid upload",
+			req:    &domain.UploadRequest{Title: "Episode", Filename: "episode.mp3", FileSize: 100, Type: domain.TypePodcast},
+			fields: nil,
+		},
+		{
+			name:   "every violation at once",
+			req:    &domain.UploadRequest{Filename: "episode.mp4", FileSize: 501, Type: domain.TypePodcast},
+			fields: []string{"title", "filename", "file_size", "file_size"},
+		},
+		{
+			name:   "over the remaining quota",
+			req:    &domain.UploadRequest{Title: "Talk", Filename: "talk.mp4", FileSize: 401, Type: domain.TypeVideo},
+			fields: []string{"file_size"},
+		},
+		{
+			name:   "duplicate content",
+			req:    &domain.UploadRequest{Title: "Again", Filename: "again.mp3", FileSize: 100, Type: domain.TypePodcast, ContentHash: strings.ToUpper(hash)},
+			fields: []string{"content_hash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			violations, err := service.ValidateUpload(ctx, tt.req)
+
+			// Then
+			require.NoError(t, err)
+			var fields []string
+			for _, violation := range violations {
+				fields = append(fields, violation.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestMediaService_CreateUploadURL_EnforcesQuota(t *testing.T) {
+	// Given a tenant with 100 bytes left in its quota
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{
+		ID: "existing", Title: "Existing", Type: domain.TypeVideo, Status: domain.StatusReady, FileSize: 900,
+	}))
+	limits, err := NewUploadLimitPolicy(UploadLimitOptions{
+		URLTTL: time.Hour, MaxVideoFileSize: 10000, MaxPodcastFileSize: 10000, StorageQuota: 1000,
+	})
+	require.NoError(t, err)
+	service := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), UploadLimits: limits})
+
+	// When uploading a larger file
+	_, err = service.CreateUploadURL(ctx, &domain.UploadRequest{Title: "Talk", Filename: "talk.mp4", FileSize: 101, Type: domain.TypeVideo})
+
+	// Then
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "QUOTA_EXCEEDED", businessErr.Code)
+}
+
+// stubShowTemplateRepository serves a fixed set of show templates
+type stubShowTemplateRepository map[string]*domain.ShowTemplate
+
+func (r stubShowTemplateRepository) GetByShowID(ctx context.Context, showID string) (*domain.ShowTemplate, error) {
+	if template, ok := r[showID]; ok {
+		return template, nil
+	}
+	return nil, domain.ErrShowTemplateNotFound
+}
+
+func (r stubShowTemplateRepository) GetByShowIDs(ctx context.Context, showIDs []string) ([]*domain.ShowTemplate, error) {
+	var templates []*domain.ShowTemplate
+	for _, showID := range showIDs {
+		if template, ok := r[showID]; ok {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+func (r stubShowTemplateRepository) Save(ctx context.Context, template *domain.ShowTemplate) error {
+	r[template.ShowID] = template
+	return nil
+}
+
+func (r stubShowTemplateRepository) Delete(ctx context.Context, showID string) error {
+	delete(r, showID)
+	return nil
+}
+
+func TestMediaService_CreateUploadURL_AppliesShowTemplate(t *testing.T) {
+	// Given a show with a template
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	templates := stubShowTemplateRepository{
+		"show-1": {ShowID: "show-1", Labels: []string{"tech"}, Category: "Technology", Explicit: true},
+	}
+	service := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher(), ShowTemplates: templates})
+
+	// When uploading an episode of the show, overriding its category
+	uploadURL, err := service.CreateUploadURL(ctx, &domain.UploadRequest{
+		Title: "Episode", Filename: "episode.mp3", FileSize: 100, Type: domain.TypePodcast, ShowID: "show-1", Category: "News",
+	})
+	require.NoError(t, err)
+
+	// Then the episode has the template defaults and the override
+	media, err := mediaRepo.GetByID(ctx, uploadURL.MediaID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tech"}, media.Labels)
+	assert.Equal(t, "News", media.Category)
+	assert.True(t, media.ContentRating.Explicit)
+}
+
+func TestGetVisibleMedia(t *testing.T) {
+	// Given public, private and deleted media
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	for _, media := range []*domain.Media{
+		{ID: "public", Status: domain.StatusReady},
+		{ID: "unlisted", Status: domain.StatusReady, Visibility: domain.VisibilityUnlisted},
+		{ID: "private", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate, OwnerID: "user-1"},
+		{ID: "deleted", Status: domain.StatusDeleted},
+	} {
+		require.NoError(t, mediaRepo.Create(ctx, media))
+	}
+	service := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
+
+	tests := []struct {
+		name    string
+		mediaID string
+		viewer  domain.Viewer
+		visible bool
+	}{
+		{"public media", "public", domain.Viewer{}, true},
+		{"unlisted media", "unlisted", domain.Viewer{}, true},
+		{"private media", "private", domain.Viewer{}, false},
+		{"private media shared with the viewer", "private", domain.Viewer{SharedMediaID: "private"}, true},
+		{"private media seen by an admin", "private", domain.Viewer{IsAdmin: true, Role: domain.RoleAdmin}, true},
+		{"private media seen by its owner", "private", domain.Viewer{UserID: "user-1"}, true},
+		{"private media seen by another user", "private", domain.Viewer{UserID: "user-2"}, false},
+		{"deleted media", "deleted", domain.Viewer{IsAdmin: true, Role: domain.RoleAdmin}, false},
+		{"missing media", "missing", domain.Viewer{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			media, err := service.GetVisibleMedia(ctx, tt.mediaID, tt.viewer)
+
+			// Then hidden media is reported as missing
+			if !tt.visible {
+				assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.mediaID, media.ID)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/notification"
+
+	"github.com/google/uuid"
+)
+
+// NotificationService sends lifecycle notifications according to stored preferences.
+// It implements EventPublisher so it can be chained behind the media service events.
+type NotificationService interface {
+	EventPublisher
+
+	// Notify sends a message to the preferences of the subjects wanting its kind, msg.Event
+	Notify(ctx context.Context, subjects []domain.NotificationSubject, msg *notification.Message) error
+
+	// GetPreferences lists the notification preferences of a subject
+	GetPreferences(ctx context.Context, subject domain.NotificationSubject) ([]*domain.NotificationPreference, error)
+
+	// CreatePreference stores a new notification preference
+	CreatePreference(ctx context.Context, req *domain.NotificationPreferenceRequest) (*domain.NotificationPreference, error)
+
+	// DeletePreference removes a notification preference
+	DeletePreference(ctx context.Context, id string) error
+
+	// ListWebhooks lists the webhook subscriptions of all subjects with their delivery stats
+	ListWebhooks(ctx context.Context) ([]*domain.NotificationPreference, error)
+}
+
+// notificationService implements NotificationService interface
+type notificationService struct {
+	prefRepo  repository.NotificationPreferenceRepository
+	mediaRepo repository.MediaRepository
+	notifiers map[string]notification.Notifier
+	now       func() time.Time
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	prefRepo repository.NotificationPreferenceRepository,
+	mediaRepo repository.MediaRepository,
+	notifiers map[string]notification.Notifier,
+) NotificationService {
+	return &notificationService{
+		prefRepo:  prefRepo,
+		mediaRepo: mediaRepo,
+		notifiers: notifiers,
+		now:       time.Now,
+	}
+}
+
+// Publish turns relevant domain events into notifications
+func (s *notificationService) Publish(ctx context.Context, event *domain.Event) error {
+	kind := notificationKindFor(event)
+	if kind == "" {
+		return nil
+	}
+
+	mediaID, _ := event.Data["media_id"].(string)
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return fmt.Errorf("failed to load media for notification: %w", err)
+	}
+
+	return s.Notify(ctx, s.subjectsFor(media), buildNotificationMessage(kind, media, event))
+}
+
+// Notify sends a message to the preferences of the subjects wanting its kind
+func (s *notificationService) Notify(ctx context.Context, subjects []domain.NotificationSubject, msg *notification.Message) error {
+	prefs, err := s.prefRepo.GetBySubjects(ctx, subjects)
+	if err != nil {
+		return fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+
+	for _, pref := range prefs {
+		if !pref.Wants(msg.Event) {
+			continue
+		}
+
+		notifier, ok := s.notifiers[pref.Channel]
+		if !ok {
+			log.Printf("No notifier configured for channel %s, skipping preference %s", pref.Channel, pref.ID)
+			continue
+		}
+
+		// A failing channel must not prevent the others from being notified
+		sendErr := notifier.Send(ctx, pref.Target, msg)
+		if sendErr != nil {
+			log.Printf("Failed to send %s notification via %s: %v", msg.Event, pref.Channel, sendErr)
+		}
+		if err := s.prefRepo.RecordDelivery(ctx, pref.ID, s.now().UTC(), sendErr); err != nil {
+			log.Printf("Failed to record delivery of notification preference %s: %v", pref.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetPreferences lists the notification preferences of a subject
+func (s *notificationService) GetPreferences(ctx context.Context, subject domain.NotificationSubject) ([]*domain.NotificationPreference, error) {
+	return s.prefRepo.GetBySubjects(ctx, []domain.NotificationSubject{subject})
+}
+
+// CreatePreference stores a new notification preference
+func (s *notificationService) CreatePreference(ctx context.Context, req *domain.NotificationPreferenceRequest) (*domain.NotificationPreference, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Notification preference validation failed", errs.Error())
+	}
+
+	pref := req.ToPreference(uuid.New().String())
+	if err := s.prefRepo.Create(ctx, pref); err != nil {
+		return nil, fmt.Errorf("failed to create notification preference: %w", err)
+	}
+
+	return pref, nil
+}
+
+// DeletePreference removes a notification preference
+func (s *notificationService) DeletePreference(ctx context.Context, id string) error {
+	return s.prefRepo.Delete(ctx, id)
+}
+
+// ListWebhooks lists the webhook subscriptions of all subjects with their delivery stats
+func (s *notificationService) ListWebhooks(ctx context.Context) ([]*domain.NotificationPreference, error) {
+	webhooks, err := s.prefRepo.ListByChannel(ctx, notification.ChannelWebhook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if webhooks == nil {
+		webhooks = []*domain.NotificationPreference{}
+	}
+	return webhooks, nil
+}
+
+// subjectsFor returns the subjects interested in notifications about the
+// media: its tenant, and its owner when it has one
+func (s *notificationService) subjectsFor(media *domain.Media) []domain.NotificationSubject {
+	tenantID := media.TenantID
+	if tenantID == "" {
+		tenantID = domain.DefaultTenantID
+	}
+	subjects := []domain.NotificationSubject{
+		{Type: domain.SubjectTenant, ID: tenantID},
+	}
+	if media.OwnerID != "" {
+		subjects = append(subjects, domain.NotificationSubject{Type: domain.SubjectUser, ID: media.OwnerID})
+	}
+	return subjects
+}
+
+// notificationKindFor maps a domain event to the notification kind it triggers
+func notificationKindFor(event *domain.Event) string {
+	switch event.Type {
+	case domain.EventMediaStatusChanged:
+		switch event.Data["to"] {
+		case string(domain.StatusReady):
+			return domain.NotificationProcessingCompleted
+		case string(domain.StatusFailed):
+			return domain.NotificationProcessingFailed
+		}
+	case domain.EventModerationDecided:
+		return domain.NotificationModerationDecision
+	case domain.EventLicenseExpiring:
+		return domain.NotificationLicenseExpiring
+	case domain.EventMediaUnpublished:
+		if event.Data["reason"] == "license_expired" {
+			return domain.NotificationLicenseExpired
+		}
+	case domain.EventPremiereStarted:
+		return domain.NotificationPremiereStarted
+	}
+	return ""
+}
+
+// buildNotificationMessage renders the message sent for a notification kind
+func buildNotificationMessage(kind string, media *domain.Media, event *domain.Event) *notification.Message {
+	msg := &notification.Message{
+		Event: kind,
+		Data: map[string]interface{}{
+			"media_id": media.ID,
+			"title":    media.Title,
+			"status":   media.Status,
+		},
+	}
+
+	switch kind {
+	case domain.NotificationProcessingCompleted:
+		msg.Subject = fmt.Sprintf("\"%s\" is ready", media.Title)
+		msg.Body = fmt.Sprintf("Processing of \"%s\" (%s) completed successfully.", media.Title, media.ID)
+	case domain.NotificationProcessingFailed:
+		msg.Subject = fmt.Sprintf("\"%s\" failed to process", media.Title)
+		msg.Body = fmt.Sprintf("Processing of \"%s\" (%s) failed: %s", media.Title, media.ID, media.FailureMessage)
+	case domain.NotificationModerationDecision:
+		decision, _ := event.Data["decision"].(string)
+		msg.Subject = fmt.Sprintf("Moderation decision for \"%s\"", media.Title)
+		msg.Body = fmt.Sprintf("\"%s\" (%s) was reviewed: %s", media.Title, media.ID, decision)
+		msg.Data["decision"] = decision
+	case domain.NotificationLicenseExpiring:
+		endsAt, _ := event.Data["license_ends_at"].(string)
+		msg.Subject = fmt.Sprintf("License for \"%s\" is about to expire", media.Title)
+		msg.Body = fmt.Sprintf("The license of \"%s\" (%s) ends at %s, after which it will be unpublished.", media.Title, media.ID, endsAt)
+		msg.Data["license_ends_at"] = endsAt
+	case domain.NotificationLicenseExpired:
+		endsAt, _ := event.Data["license_ends_at"].(string)
+		msg.Subject = fmt.Sprintf("\"%s\" was unpublished", media.Title)
+		msg.Body = fmt.Sprintf("The license of \"%s\" (%s) ended at %s and it was unpublished.", media.Title, media.ID, endsAt)
+		msg.Data["license_ends_at"] = endsAt
+	case domain.NotificationPremiereStarted:
+		startsAt, _ := event.Data["starts_at"].(string)
+		msg.Subject = fmt.Sprintf("\"%s\" is premiering now", media.Title)
+		msg.Body = fmt.Sprintf("The premiere of \"%s\" (%s) started at %s.", media.Title, media.ID, startsAt)
+		msg.Data["starts_at"] = startsAt
+	}
+
+	return msg
+}
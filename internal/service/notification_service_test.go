@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationPreferenceRepository is a mock implementation of NotificationPreferenceRepository
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) Create(ctx context.Context, pref *domain.NotificationPreference) error {
+	args := m.Called(ctx, pref)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceRepository) GetBySubjects(ctx context.Context, subjects []domain.NotificationSubject) ([]*domain.NotificationPreference, error) {
+	args := m.Called(ctx, subjects)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceRepository) ListByChannel(ctx context.Context, channel string) ([]*domain.NotificationPreference, error) {
+	args := m.Called(ctx, channel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) RecordDelivery(ctx context.Context, id string, at time.Time, deliveryErr error) error {
+	args := m.Called(ctx, id, at, deliveryErr)
+	return args.Error(0)
+}
+
+// MockNotifier is a mock implementation of notification.Notifier
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Send(ctx context.Context, target string, msg *notification.Message) error {
+	args := m.Called(ctx, target, msg)
+	return args.Error(0)
+}
+
+func TestNotificationService_Publish(t *testing.T) {
+	media := &domain.Media{
+		ID:             "media-123",
+		TenantID:       "tenant-a",
+		OwnerID:        "user-1",
+		Title:          "Episode 1",
+		Status:         domain.StatusFailed,
+		FailureMessage: "corrupt file",
+	}
+
+	tests := []struct {
+		name        string
+		event       *domain.Event
+		setupMock   func(*MockNotificationPreferenceRepository, *MockMediaRepository, *MockNotifier, *MockNotifier)
+		expectError bool
+	}{
+		{
+			name: "processing failure notifies subscribed channels",
+			event: domain.NewEvent(domain.EventMediaStatusChanged, map[string]interface{}{
+				"media_id": "media-123", "from": "processing", "to": "failed",
+			}),
+			setupMock: func(prefRepo *MockNotificationPreferenceRepository, mediaRepo *MockMediaRepository, slack, email *MockNotifier) {
+				mediaRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				prefRepo.On("GetBySubjects", mock.Anything, []domain.NotificationSubject{
+					{Type: domain.SubjectTenant, ID: "tenant-a"},
+					{Type: domain.SubjectUser, ID: "user-1"},
+				}).Return([]*domain.NotificationPreference{
+					{ID: "p1", Channel: "slack", Target: "https://hooks.slack.test", Enabled: true},
+					{ID: "p2", Channel: "email", Target: "ops@example.com", Enabled: true, Events: domain.NotificationProcessingCompleted},
+				}, nil)
+				slack.On("Send", mock.Anything, "https://hooks.slack.test", mock.MatchedBy(func(msg *notification.Message) bool {
+					return msg.Event == domain.NotificationProcessingFailed
+				})).Return(nil)
+				prefRepo.On("RecordDelivery", mock.Anything, "p1", mock.Anything, nil).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "channel failure does not fail publishing",
+			event: domain.NewEvent(domain.EventMediaStatusChanged, map[string]interface{}{
+				"media_id": "media-123", "from": "processing", "to": "ready",
+			}),
+			setupMock: func(prefRepo *MockNotificationPreferenceRepository, mediaRepo *MockMediaRepository, slack, email *MockNotifier) {
+				mediaRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				prefRepo.On("GetBySubjects", mock.Anything, mock.Anything).Return([]*domain.NotificationPreference{
+					{ID: "p1", Channel: "email", Target: "ops@example.com", Enabled: true},
+					{ID: "p2", Channel: "sms", Target: "+966500000000", Enabled: true},
+				}, nil)
+				email.On("Send", mock.Anything, "ops@example.com", mock.Anything).Return(errors.New("smtp down"))
+				prefRepo.On("RecordDelivery", mock.Anything, "p1", mock.Anything, mock.MatchedBy(func(err error) bool {
+					return err != nil && err.Error() == "smtp down"
+				})).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "license expiry notice",
+			event: domain.NewEvent(domain.EventLicenseExpiring, map[string]interface{}{
+				"media_id": "media-123", "license_ends_at": "2026-01-01T00:00:00Z",
+			}),
+			setupMock: func(prefRepo *MockNotificationPreferenceRepository, mediaRepo *MockMediaRepository, slack, email *MockNotifier) {
+				mediaRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+				prefRepo.On("GetBySubjects", mock.Anything, mock.Anything).Return([]*domain.NotificationPreference{
+					{ID: "p1", Channel: "email", Target: "rights@example.com", Enabled: true, Events: domain.NotificationLicenseExpiring},
+				}, nil)
+				email.On("Send", mock.Anything, "rights@example.com", mock.MatchedBy(func(msg *notification.Message) bool {
+					return msg.Event == domain.NotificationLicenseExpiring && msg.Data["license_ends_at"] == "2026-01-01T00:00:00Z"
+				})).Return(nil)
+				prefRepo.On("RecordDelivery", mock.Anything, "p1", mock.Anything, nil).Return(errors.New("db down"))
+			},
+			expectError: false,
+		},
+		{
+			name: "irrelevant transition is ignored",
+			event: domain.NewEvent(domain.EventMediaStatusChanged, map[string]interface{}{
+				"media_id": "media-123", "from": "uploading", "to": "processing",
+			}),
+			setupMock: func(prefRepo *MockNotificationPreferenceRepository, mediaRepo *MockMediaRepository, slack, email *MockNotifier) {
+			},
+			expectError: false,
+		},
+		{
+			name: "media lookup error",
+			event: domain.NewEvent(domain.EventModerationDecided, map[string]interface{}{
+				"media_id": "media-123", "decision": "removed",
+			}),
+			setupMock: func(prefRepo *MockNotificationPreferenceRepository, mediaRepo *MockMediaRepository, slack, email *MockNotifier) {
+				mediaRepo.On("GetByID", mock.Anything, "media-123").Return(nil, domain.ErrMediaNotFound)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			prefRepo := new(MockNotificationPreferenceRepository)
+			mediaRepo := new(MockMediaRepository)
+			slack := new(MockNotifier)
+			email := new(MockNotifier)
+			tt.setupMock(prefRepo, mediaRepo, slack, email)
+			service := NewNotificationService(prefRepo, mediaRepo, map[string]notification.Notifier{
+				notification.ChannelSlack: slack,
+				notification.ChannelEmail: email,
+			})
+
+			// When
+			err := service.Publish(context.Background(), tt.event)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			prefRepo.AssertExpectations(t)
+			mediaRepo.AssertExpectations(t)
+			slack.AssertExpectations(t)
+			email.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_CreatePreference(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *domain.NotificationPreferenceRequest
+		setupMock   func(*MockNotificationPreferenceRepository)
+		expectError bool
+	}{
+		{
+			name: "successful creation",
+			request: &domain.NotificationPreferenceRequest{
+				SubjectType: domain.SubjectTenant,
+				SubjectID:   domain.DefaultTenantID,
+				Channel:     "webhook",
+				Target:      "https://example.com/hooks/media",
+			},
+			setupMock: func(prefRepo *MockNotificationPreferenceRepository) {
+				prefRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationPreference")).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "validation error",
+			request: &domain.NotificationPreferenceRequest{
+				SubjectType: domain.SubjectTenant,
+				SubjectID:   domain.DefaultTenantID,
+				Channel:     "pager",
+				Target:      "123",
+			},
+			setupMock:   func(prefRepo *MockNotificationPreferenceRepository) {},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			prefRepo := new(MockNotificationPreferenceRepository)
+			tt.setupMock(prefRepo)
+			service := NewNotificationService(prefRepo, new(MockMediaRepository), nil)
+
+			// When
+			pref, err := service.CreatePreference(context.Background(), tt.request)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, pref)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, pref.ID)
+				assert.True(t, pref.Enabled)
+			}
+
+			prefRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_ListWebhooks(t *testing.T) {
+	// Given no webhook subscriptions
+	prefRepo := new(MockNotificationPreferenceRepository)
+	prefRepo.On("ListByChannel", mock.Anything, notification.ChannelWebhook).Return(nil, nil)
+	service := NewNotificationService(prefRepo, new(MockMediaRepository), nil)
+
+	// When
+	webhooks, err := service.ListWebhooks(context.Background())
+
+	// Then an empty list is returned
+	assert.NoError(t, err)
+	assert.NotNil(t, webhooks)
+	assert.Empty(t, webhooks)
+	prefRepo.AssertExpectations(t)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/messagequeue"
+)
+
+// OutboxRelayOptions configures an outbox relay
+type OutboxRelayOptions struct {
+	Name         string        // identifies the relay in logs
+	BatchSize    int           // messages locked and published per batch
+	PollInterval time.Duration // wait between polls when the outbox is drained
+	Retention    time.Duration // how long published messages are kept, 0 keeps them forever
+}
+
+// OutboxRelayStats exposes relay metrics
+type OutboxRelayStats struct {
+	Published      uint64 `json:"published"`
+	Failed         uint64 `json:"failed"`
+	Batches        uint64 `json:"batches"`
+	Pruned         uint64 `json:"pruned"`
+	Lag            uint64 `json:"lag"` // messages waiting to be published
+	LastBatchMicro int64  `json:"last_batch_us"`
+}
+
+// OutboxRelay publishes outbox messages to the message queue with at-least-once
+// delivery. Several relays can run side by side, each publishes the messages
+// the others have not locked.
+type OutboxRelay interface {
+	// RelayBatch publishes the next batch of messages and returns how many were published
+	RelayBatch(ctx context.Context) (int, error)
+
+	// Run relays batches until ctx is cancelled
+	Run(ctx context.Context) error
+
+	// Stats returns a snapshot of the relay metrics
+	Stats() OutboxRelayStats
+}
+
+// outboxRelay implements OutboxRelay interface
+type outboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	queue      messagequeue.MessageQueue
+	options    OutboxRelayOptions
+
+	published      atomic.Uint64
+	failed         atomic.Uint64
+	batches        atomic.Uint64
+	pruned         atomic.Uint64
+	lag            atomic.Uint64
+	lastBatchMicro atomic.Int64
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, queue messagequeue.MessageQueue, options OutboxRelayOptions) OutboxRelay {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+
+	return &outboxRelay{
+		outboxRepo: outboxRepo,
+		queue:      queue,
+		options:    options,
+	}
+}
+
+// RelayBatch publishes the next batch of messages and returns how many were published.
+// Messages are only marked as published once the queue accepted them, so a
+// crash may publish a message twice but never skips one.
+func (r *outboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	start := time.Now()
+	defer func() {
+		r.lastBatchMicro.Store(time.Since(start).Microseconds())
+	}()
+
+	attempted := false
+	published, err := r.outboxRepo.RelayUnpublished(ctx, r.options.BatchSize, func(msg *domain.OutboxMessage) error {
+		attempted = true
+		if err := r.queue.Publish(ctx, msg.Topic, msg.Payload); err != nil {
+			r.failed.Add(1)
+			return fmt.Errorf("failed to publish outbox message %d: %w", msg.ID, err)
+		}
+		return nil
+	})
+	if attempted {
+		r.batches.Add(1)
+	}
+	r.published.Add(uint64(published))
+
+	r.updateLag(ctx)
+
+	if err != nil {
+		return published, fmt.Errorf("failed to relay outbox: %w", err)
+	}
+	return published, nil
+}
+
+// Run relays batches until ctx is cancelled
+func (r *outboxRelay) Run(ctx context.Context) error {
+	for {
+		published, err := r.RelayBatch(ctx)
+		if err != nil {
+			log.Printf("Outbox relay %s: %v", r.options.Name, err)
+		}
+
+		// Keep draining while batches come back full
+		if err == nil && published == r.options.BatchSize {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		if err == nil {
+			r.prune(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.options.PollInterval):
+		}
+	}
+}
+
+// Stats returns a snapshot of the relay metrics
+func (r *outboxRelay) Stats() OutboxRelayStats {
+	return OutboxRelayStats{
+		Published:      r.published.Load(),
+		Failed:         r.failed.Load(),
+		Batches:        r.batches.Load(),
+		Pruned:         r.pruned.Load(),
+		Lag:            r.lag.Load(),
+		LastBatchMicro: r.lastBatchMicro.Load(),
+	}
+}
+
+// updateLag records how many messages wait to be published
+func (r *outboxRelay) updateLag(ctx context.Context) {
+	count, err := r.outboxRepo.CountUnpublished(ctx)
+	if err != nil {
+		log.Printf("Outbox relay %s: failed to count unpublished messages: %v", r.options.Name, err)
+		return
+	}
+
+	r.lag.Store(uint64(count))
+}
+
+// prune removes published messages older than the retention period
+func (r *outboxRelay) prune(ctx context.Context) {
+	if r.options.Retention <= 0 {
+		return
+	}
+
+	deleted, err := r.outboxRepo.DeletePublished(ctx, time.Now().Add(-r.options.Retention))
+	if err != nil {
+		log.Printf("Outbox relay %s: failed to prune outbox: %v", r.options.Name, err)
+		return
+	}
+
+	r.pruned.Add(uint64(deleted))
+}
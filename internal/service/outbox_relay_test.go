@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/messagequeue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository is a mock implementation of OutboxRepository
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Append(ctx context.Context, msg *domain.OutboxMessage) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+// RelayUnpublished calls publish on the messages set up for the call, like the
+// repository does on the messages it locked
+func (m *MockOutboxRepository) RelayUnpublished(ctx context.Context, limit int, publish func(msg *domain.OutboxMessage) error) (int, error) {
+	args := m.Called(ctx, limit)
+	if err := args.Error(1); err != nil {
+		return 0, err
+	}
+	published := 0
+	for _, msg := range args.Get(0).([]*domain.OutboxMessage) {
+		if err := publish(msg); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+func (m *MockOutboxRepository) CountUnpublished(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockMessageQueue is a mock implementation of messagequeue.MessageQueue
+type MockMessageQueue struct {
+	mock.Mock
+}
+
+func (m *MockMessageQueue) Publish(ctx context.Context, topic string, message []byte) error {
+	args := m.Called(ctx, topic, message)
+	return args.Error(0)
+}
+
+func (m *MockMessageQueue) Subscribe(ctx context.Context, topic string, handler messagequeue.MessageHandler) error {
+	args := m.Called(ctx, topic, handler)
+	return args.Error(0)
+}
+
+func (m *MockMessageQueue) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestOutboxRelay_RelayBatch(t *testing.T) {
+	messages := []*domain.OutboxMessage{
+		{ID: 11, Topic: domain.EventMediaStatusChanged, Payload: []byte(`{"n":1}`)},
+		{ID: 12, Topic: domain.EventMediaStatusChanged, Payload: []byte(`{"n":2}`)},
+		{ID: 13, Topic: domain.EventModerationDecided, Payload: []byte(`{"n":3}`)},
+	}
+
+	tests := []struct {
+		name              string
+		messages          []*domain.OutboxMessage
+		readErr           error
+		failAtID          uint64
+		expectedPublished int
+		expectError       bool
+	}{
+		{
+			name:              "publishes batch",
+			messages:          messages,
+			expectedPublished: 3,
+		},
+		{
+			name:              "publish failure stops the batch",
+			messages:          messages,
+			failAtID:          12,
+			expectedPublished: 1,
+			expectError:       true,
+		},
+		{
+			name:              "failure on first message publishes none",
+			messages:          messages,
+			failAtID:          11,
+			expectedPublished: 0,
+			expectError:       true,
+		},
+		{
+			name:              "outbox unavailable",
+			readErr:           errors.New("connection refused"),
+			expectedPublished: 0,
+			expectError:       true,
+		},
+		{
+			name:              "empty outbox",
+			messages:          []*domain.OutboxMessage{},
+			expectedPublished: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mockRepo := new(MockOutboxRepository)
+			mockQueue := new(MockMessageQueue)
+			relay := NewOutboxRelay(mockRepo, mockQueue, OutboxRelayOptions{Name: "test-relay", BatchSize: 10})
+
+			mockRepo.On("RelayUnpublished", mock.Anything, 10).Return(tt.messages, tt.readErr)
+			mockRepo.On("CountUnpublished", mock.Anything).Return(int64(3-tt.expectedPublished), nil)
+			for _, msg := range tt.messages {
+				var err error
+				if msg.ID == tt.failAtID {
+					err = errors.New("broker unavailable")
+				}
+				mockQueue.On("Publish", mock.Anything, msg.Topic, msg.Payload).Return(err).Maybe()
+			}
+
+			// When
+			published, err := relay.RelayBatch(context.Background())
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedPublished, published)
+			assert.Equal(t, uint64(tt.expectedPublished), relay.Stats().Published)
+			assert.Equal(t, uint64(3-tt.expectedPublished), relay.Stats().Lag)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOutboxEventPublisher_Publish(t *testing.T) {
+	// Given
+	mockRepo := new(MockOutboxRepository)
+	publisher := NewOutboxEventPublisher(mockRepo)
+	event := domain.NewEvent(domain.EventMediaStatusChanged, map[string]interface{}{"media_id": "media-1"})
+
+	mockRepo.On("Append", mock.Anything, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+		return msg.Topic == domain.EventMediaStatusChanged && len(msg.Payload) > 0
+	})).Return(nil)
+
+	// When
+	err := publisher.Publish(context.Background(), event)
+
+	// Then
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// PeopleService manages the hosts and guests of media and shows
+type PeopleService interface {
+	// CreatePerson stores a new person
+	CreatePerson(ctx context.Context, req *domain.PersonRequest) (*domain.Person, error)
+
+	// GetPerson retrieves a person by ID
+	GetPerson(ctx context.Context, id string) (*domain.Person, error)
+
+	// ListPeople lists a page of people ordered by name, with the total
+	ListPeople(ctx context.Context, limit, offset int) ([]*domain.Person, int64, error)
+
+	// UpdatePerson replaces a person
+	UpdatePerson(ctx context.Context, id string, req *domain.PersonRequest) (*domain.Person, error)
+
+	// DeletePerson removes a person and their credits
+	DeletePerson(ctx context.Context, id string) error
+
+	// GetMediaPeople lists the people credited on a media item
+	GetMediaPeople(ctx context.Context, mediaID string, viewer domain.Viewer) ([]domain.Credit, error)
+
+	// SetMediaPeople replaces the people credited on a media item
+	SetMediaPeople(ctx context.Context, mediaID string, req *domain.CreditsRequest) ([]domain.Credit, error)
+
+	// GetShowPeople lists the people credited on a show
+	GetShowPeople(ctx context.Context, showID string) ([]domain.Credit, error)
+
+	// SetShowPeople replaces the people credited on a show
+	SetShowPeople(ctx context.Context, showID string, req *domain.CreditsRequest) ([]domain.Credit, error)
+
+	// GetAppearances lists the shows of a person and a page of the media they appear in
+	GetAppearances(ctx context.Context, personID string, viewer domain.Viewer, limit, offset int) (*domain.AppearancesResponse, error)
+}
+
+// peopleService implements PeopleService interface
+type peopleService struct {
+	personRepo repository.PersonRepository
+	mediaRepo  repository.MediaRepository
+	publisher  EventPublisher
+}
+
+// NewPeopleService creates a new people service. Changes to the people of
+// media are published as media updates so search indexes them.
+func NewPeopleService(personRepo repository.PersonRepository, mediaRepo repository.MediaRepository, publisher EventPublisher) PeopleService {
+	return &peopleService{
+		personRepo: personRepo,
+		mediaRepo:  mediaRepo,
+		publisher:  publisher,
+	}
+}
+
+// CreatePerson stores a new person
+func (s *peopleService) CreatePerson(ctx context.Context, req *domain.PersonRequest) (*domain.Person, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Person validation failed", errs.Error())
+	}
+
+	person := &domain.Person{ID: uuid.New().String()}
+	req.ApplyTo(person)
+
+	if err := s.personRepo.Create(ctx, person); err != nil {
+		return nil, fmt.Errorf("failed to create person: %w", err)
+	}
+
+	return person, nil
+}
+
+// GetPerson retrieves a person by ID
+func (s *peopleService) GetPerson(ctx context.Context, id string) (*domain.Person, error) {
+	return s.personRepo.GetByID(ctx, id)
+}
+
+// ListPeople lists a page of people ordered by name, with the total
+func (s *peopleService) ListPeople(ctx context.Context, limit, offset int) ([]*domain.Person, int64, error) {
+	people, total, err := s.personRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list people: %w", err)
+	}
+
+	return people, total, nil
+}
+
+// UpdatePerson replaces a person
+func (s *peopleService) UpdatePerson(ctx context.Context, id string, req *domain.PersonRequest) (*domain.Person, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Person validation failed", errs.Error())
+	}
+
+	person, err := s.personRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.ApplyTo(person)
+
+	if err := s.personRepo.Update(ctx, person); err != nil {
+		return nil, fmt.Errorf("failed to update person: %w", err)
+	}
+
+	return person, nil
+}
+
+// DeletePerson removes a person and their credits
+func (s *peopleService) DeletePerson(ctx context.Context, id string) error {
+	return s.personRepo.Delete(ctx, id)
+}
+
+// GetMediaPeople lists the people credited on a media item.
+// Media the viewer may not see is reported as missing.
+func (s *peopleService) GetMediaPeople(ctx context.Context, mediaID string, viewer domain.Viewer) ([]domain.Credit, error) {
+	if _, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer); err != nil {
+		return nil, err
+	}
+
+	return s.getCredits(ctx, domain.CreditTargetMedia, mediaID)
+}
+
+// SetMediaPeople replaces the people credited on a media item and
+// announces the change so search indexes the new names
+func (s *peopleService) SetMediaPeople(ctx context.Context, mediaID string, req *domain.CreditsRequest) ([]domain.Credit, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+
+	credits, err := s.setCredits(ctx, domain.CreditTargetMedia, mediaID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	event := domain.NewEvent(domain.EventMediaUpdated, map[string]interface{}{
+		"media_id": mediaID,
+	})
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish people change of media %s: %v", mediaID, err)
+	}
+
+	return credits, nil
+}
+
+// GetShowPeople lists the people credited on a show
+func (s *peopleService) GetShowPeople(ctx context.Context, showID string) ([]domain.Credit, error) {
+	return s.getCredits(ctx, domain.CreditTargetShow, showID)
+}
+
+// SetShowPeople replaces the people credited on a show. Episodes already in
+// search pick the new names up when they are next indexed.
+func (s *peopleService) SetShowPeople(ctx context.Context, showID string, req *domain.CreditsRequest) ([]domain.Credit, error) {
+	return s.setCredits(ctx, domain.CreditTargetShow, showID, req)
+}
+
+// GetAppearances lists the shows of a person and a page of the ready media they
+// appear in, directly or through a show, most recent first. Viewers other than
+// admins only see public media.
+func (s *peopleService) GetAppearances(ctx context.Context, personID string, viewer domain.Viewer, limit, offset int) (*domain.AppearancesResponse, error) {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	credits, err := s.personRepo.GetCreditsByPerson(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credits: %w", err)
+	}
+	shows := make([]domain.ShowAppearance, 0)
+	for _, credit := range credits {
+		if credit.TargetType == domain.CreditTargetShow {
+			shows = append(shows, domain.ShowAppearance{ShowID: credit.TargetID, Role: credit.Role})
+		}
+	}
+
+	appearances, total, err := s.personRepo.GetAppearances(ctx, personID, !viewer.IsAdmin, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appearances: %w", err)
+	}
+	mediaIDs := make([]string, len(appearances))
+	for i, appearance := range appearances {
+		mediaIDs[i] = appearance.TargetID
+	}
+	mediaByID := make(map[string]*domain.Media, len(mediaIDs))
+	if len(mediaIDs) > 0 {
+		mediaList, err := s.mediaRepo.GetByIDs(ctx, mediaIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up media: %w", err)
+		}
+		for _, media := range mediaList {
+			mediaByID[media.ID] = media
+		}
+	}
+
+	items := make([]domain.MediaAppearance, 0, len(appearances))
+	for _, appearance := range appearances {
+		// Media deleted since the page was read is skipped
+		if media, ok := mediaByID[appearance.TargetID]; ok {
+			items = append(items, domain.MediaAppearance{Media: media, Role: appearance.Role})
+		}
+	}
+
+	return &domain.AppearancesResponse{
+		Person: person,
+		Shows:  shows,
+		Items:  items,
+		Total:  total,
+	}, nil
+}
+
+// getCredits lists the people credited on a target in their order
+func (s *peopleService) getCredits(ctx context.Context, targetType domain.CreditTarget, targetID string) ([]domain.Credit, error) {
+	credits, err := s.personRepo.GetCredits(ctx, targetType, []string{targetID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credits: %w", err)
+	}
+
+	return s.resolveCredits(ctx, credits)
+}
+
+// setCredits validates a credits request and replaces the credits of a target with it
+func (s *peopleService) setCredits(ctx context.Context, targetType domain.CreditTarget, targetID string, req *domain.CreditsRequest) ([]domain.Credit, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Credits validation failed", errs.Error())
+	}
+
+	credits := req.ToCredits(targetType, targetID)
+	resolved, err := s.resolveCredits(ctx, credits)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolved) < len(credits) {
+		return nil, domain.NewBusinessErrorWithDetails("UNKNOWN_PERSON", "Credited people must exist",
+			strings.Join(missingPeople(credits, resolved), ", "))
+	}
+
+	if err := s.personRepo.SetCredits(ctx, targetType, targetID, credits); err != nil {
+		return nil, fmt.Errorf("failed to set credits: %w", err)
+	}
+
+	return resolved, nil
+}
+
+// resolveCredits looks up the people of credits, leaving out people that no longer exist
+func (s *peopleService) resolveCredits(ctx context.Context, credits []*domain.PersonCredit) ([]domain.Credit, error) {
+	people, err := s.personRepo.GetByIDs(ctx, creditedPersonIDs(credits))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up people: %w", err)
+	}
+	peopleByID := make(map[string]*domain.Person, len(people))
+	for _, person := range people {
+		peopleByID[person.ID] = person
+	}
+
+	resolved := make([]domain.Credit, 0, len(credits))
+	for _, credit := range credits {
+		if person, ok := peopleByID[credit.PersonID]; ok {
+			resolved = append(resolved, domain.Credit{Person: person, Role: credit.Role})
+		}
+	}
+
+	return resolved, nil
+}
+
+// missingPeople returns the people of credits that were not resolved
+func missingPeople(credits []*domain.PersonCredit, resolved []domain.Credit) []string {
+	found := make(map[string]bool, len(resolved))
+	for _, credit := range resolved {
+		found[credit.Person.ID] = true
+	}
+
+	var missing []string
+	for _, credit := range credits {
+		if !found[credit.PersonID] {
+			missing = append(missing, credit.PersonID)
+		}
+	}
+	return missing
+}
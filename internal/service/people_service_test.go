@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPersonRepository keeps people and credits in memory. Appearances are
+// served from the fixed appearances slice.
+type stubPersonRepository struct {
+	people      map[string]*domain.Person
+	credits     []*domain.PersonCredit
+	appearances []*domain.PersonCredit
+}
+
+func newStubPersonRepository(people ...*domain.Person) *stubPersonRepository {
+	repo := &stubPersonRepository{people: make(map[string]*domain.Person)}
+	for _, person := range people {
+		repo.people[person.ID] = person
+	}
+	return repo
+}
+
+func (r *stubPersonRepository) Create(ctx context.Context, person *domain.Person) error {
+	r.people[person.ID] = person
+	return nil
+}
+
+func (r *stubPersonRepository) GetByID(ctx context.Context, id string) (*domain.Person, error) {
+	if person, ok := r.people[id]; ok {
+		return person, nil
+	}
+	return nil, domain.ErrPersonNotFound
+}
+
+func (r *stubPersonRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Person, error) {
+	var people []*domain.Person
+	for _, id := range ids {
+		if person, ok := r.people[id]; ok {
+			people = append(people, person)
+		}
+	}
+	return people, nil
+}
+
+func (r *stubPersonRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Person, int64, error) {
+	var people []*domain.Person
+	for _, person := range r.people {
+		people = append(people, person)
+	}
+	sort.Slice(people, func(i, j int) bool { return people[i].Name < people[j].Name })
+	total := int64(len(people))
+	if offset >= len(people) {
+		return nil, total, nil
+	}
+	return people[offset:min(offset+limit, len(people))], total, nil
+}
+
+func (r *stubPersonRepository) Update(ctx context.Context, person *domain.Person) error {
+	r.people[person.ID] = person
+	return nil
+}
+
+func (r *stubPersonRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := r.people[id]; !ok {
+		return domain.ErrPersonNotFound
+	}
+	delete(r.people, id)
+	return nil
+}
+
+func (r *stubPersonRepository) SetCredits(ctx context.Context, targetType domain.CreditTarget, targetID string, credits []*domain.PersonCredit) error {
+	kept := credits
+	for _, credit := range r.credits {
+		if credit.TargetType != targetType || credit.TargetID != targetID {
+			kept = append(kept, credit)
+		}
+	}
+	r.credits = kept
+	return nil
+}
+
+func (r *stubPersonRepository) GetCredits(ctx context.Context, targetType domain.CreditTarget, targetIDs []string) ([]*domain.PersonCredit, error) {
+	var credits []*domain.PersonCredit
+	for _, credit := range r.credits {
+		for _, targetID := range targetIDs {
+			if credit.TargetType == targetType && credit.TargetID == targetID {
+				credits = append(credits, credit)
+			}
+		}
+	}
+	return credits, nil
+}
+
+func (r *stubPersonRepository) GetCreditsByPerson(ctx context.Context, personID string) ([]*domain.PersonCredit, error) {
+	var credits []*domain.PersonCredit
+	for _, credit := range r.credits {
+		if credit.PersonID == personID {
+			credits = append(credits, credit)
+		}
+	}
+	return credits, nil
+}
+
+func (r *stubPersonRepository) GetAppearances(ctx context.Context, personID string, publicOnly bool, limit, offset int) ([]*domain.PersonCredit, int64, error) {
+	return r.appearances, int64(len(r.appearances)), nil
+}
+
+func TestPeopleService_CreatePerson(t *testing.T) {
+	ctx := context.Background()
+	service := NewPeopleService(newStubPersonRepository(), repository.NewInMemoryMediaRepository(), newMockEventPublisher())
+
+	person, err := service.CreatePerson(ctx, &domain.PersonRequest{Name: "  Sara   Ali ", ImageURL: "https://cdn.example.com/sara.jpg"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, person.ID)
+	assert.Equal(t, "Sara Ali", person.Name)
+
+	_, err = service.CreatePerson(ctx, &domain.PersonRequest{ImageURL: "ftp://example.com/x.jpg"})
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_REQUEST", businessErr.Code)
+	assert.Contains(t, businessErr.Details, "name")
+	assert.Contains(t, businessErr.Details, "image_url")
+}
+
+func TestPeopleService_SetMediaPeople(t *testing.T) {
+	// Given a media item and two people
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", Title: "Episode", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate}))
+	people := newStubPersonRepository(&domain.Person{ID: "p1", Name: "Host"}, &domain.Person{ID: "p2", Name: "Guest"})
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaUpdated && event.Data["media_id"] == "m1"
+	})).Return(nil).Once()
+	service := NewPeopleService(people, mediaRepo, publisher)
+
+	// When crediting them in order
+	credits, err := service.SetMediaPeople(ctx, "m1", &domain.CreditsRequest{People: []domain.CreditRequest{
+		{PersonID: "p1", Role: domain.PersonRoleHost},
+		{PersonID: "p2", Role: domain.PersonRoleGuest},
+	}})
+
+	// Then the credits are stored and the media is announced for reindexing
+	require.NoError(t, err)
+	require.Len(t, credits, 2)
+	assert.Equal(t, "Host", credits[0].Person.Name)
+	assert.Equal(t, domain.PersonRoleGuest, credits[1].Role)
+	publisher.AssertExpectations(t)
+
+	// And only admins can list the people of private media
+	_, err = service.GetMediaPeople(ctx, "m1", domain.Viewer{})
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	listed, err := service.GetMediaPeople(ctx, "m1", domain.Viewer{IsAdmin: true})
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+
+	// And unknown people are rejected without changing the credits
+	_, err = service.SetMediaPeople(ctx, "m1", &domain.CreditsRequest{People: []domain.CreditRequest{
+		{PersonID: "missing", Role: domain.PersonRoleGuest},
+	}})
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "UNKNOWN_PERSON", businessErr.Code)
+	assert.Equal(t, "missing", businessErr.Details)
+	assert.Len(t, people.credits, 2)
+
+	_, err = service.SetMediaPeople(ctx, "missing", &domain.CreditsRequest{})
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+}
+
+func TestPeopleService_GetAppearances(t *testing.T) {
+	// Given a host of a show who appears in two episodes, one deleted since
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", Title: "Episode 1", Status: domain.StatusReady, ShowID: "show-1"}))
+	people := newStubPersonRepository(&domain.Person{ID: "p1", Name: "Host"})
+	people.credits = []*domain.PersonCredit{
+		{PersonID: "p1", TargetType: domain.CreditTargetShow, TargetID: "show-1", Role: domain.PersonRoleHost},
+	}
+	people.appearances = []*domain.PersonCredit{
+		{PersonID: "p1", TargetType: domain.CreditTargetMedia, TargetID: "m1", Role: domain.PersonRoleHost},
+		{PersonID: "p1", TargetType: domain.CreditTargetMedia, TargetID: "gone", Role: domain.PersonRoleGuest},
+	}
+	service := NewPeopleService(people, mediaRepo, newMockEventPublisher())
+
+	// When
+	appearances, err := service.GetAppearances(ctx, "p1", domain.Viewer{}, 10, 0)
+
+	// Then the show and the remaining episode are listed
+	require.NoError(t, err)
+	assert.Equal(t, "Host", appearances.Person.Name)
+	assert.Equal(t, []domain.ShowAppearance{{ShowID: "show-1", Role: domain.PersonRoleHost}}, appearances.Shows)
+	require.Len(t, appearances.Items, 1)
+	assert.Equal(t, "m1", appearances.Items[0].Media.ID)
+	assert.Equal(t, int64(2), appearances.Total)
+
+	_, err = service.GetAppearances(ctx, "missing", domain.Viewer{}, 10, 0)
+	assert.ErrorIs(t, err, domain.ErrPersonNotFound)
+}
+
+func TestSearchContextResolver_People(t *testing.T) {
+	// Given an episode with a guest, of a show with a host
+	ctx := context.Background()
+	people := newStubPersonRepository(&domain.Person{ID: "p1", Name: "Show Host"}, &domain.Person{ID: "p2", Name: "Visiting Guest"})
+	people.credits = []*domain.PersonCredit{
+		{PersonID: "p1", TargetType: domain.CreditTargetShow, TargetID: "show-1", Role: domain.PersonRoleHost},
+		{PersonID: "p2", TargetType: domain.CreditTargetMedia, TargetID: "m1", Role: domain.PersonRoleGuest},
+	}
+	resolver := NewSearchContextResolver(nil, people)
+	episode := &domain.Media{ID: "m1", ShowID: "show-1"}
+	other := &domain.Media{ID: "m2"}
+
+	// When
+	resolver.Resolve(ctx, []*domain.Media{episode, other})
+
+	// Then the episode is indexed with both, the other media with neither
+	assert.Equal(t, []string{"Show Host"}, episode.SearchContext.Hosts)
+	assert.Equal(t, []string{"Visiting Guest"}, episode.SearchContext.Guests)
+	assert.Empty(t, other.SearchContext.Hosts)
+	assert.Empty(t, other.SearchContext.Guests)
+
+	// And a nil resolver still sets the context of the media itself
+	(*SearchContextResolver)(nil).Resolve(ctx, []*domain.Media{other})
+	assert.NotNil(t, other.SearchContext)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// PlaybackService resolves what players need to start playback
+type PlaybackService interface {
+	// GetPlayback returns the stream URL of a media item and, for protected media,
+	// its license acquisition info
+	GetPlayback(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.PlaybackInfo, error)
+
+	// GetPlaybackBatch returns the playback info of up to MaxPlaybackBatchSize
+	// media items, in the order of mediaIDs without duplicates. Items that
+	// cannot be played carry the reason instead of failing the batch.
+	GetPlaybackBatch(ctx context.Context, mediaIDs []string, viewer domain.Viewer) ([]*domain.PlaybackResult, error)
+}
+
+// playbackService implements PlaybackService interface
+type playbackService struct {
+	mediaRepo  repository.MediaRepository
+	drmService DRMService
+	storage    storage.Storage
+	baseURL    string
+	urlTTL     time.Duration
+}
+
+// NewPlaybackService creates a new playback service. Stream URLs are signed by
+// store, which must issue download URLs, and stay valid urlTTL past the
+// duration of the media; the other endpoints are served from baseURL.
+func NewPlaybackService(mediaRepo repository.MediaRepository, drmService DRMService, store storage.Storage, baseURL string, urlTTL time.Duration) PlaybackService {
+	if urlTTL <= 0 {
+		urlTTL = domain.DefaultStreamURLTTL
+	}
+
+	return &playbackService{
+		mediaRepo:  mediaRepo,
+		drmService: drmService,
+		storage:    store,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		urlTTL:     urlTTL,
+	}
+}
+
+// GetPlayback returns the playback info of a media item
+func (s *playbackService) GetPlayback(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.PlaybackInfo, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.playbackInfo(ctx, media, viewer)
+}
+
+// GetPlaybackBatch returns the playback info of several media items, fetched in one query
+func (s *playbackService) GetPlaybackBatch(ctx context.Context, mediaIDs []string, viewer domain.Viewer) ([]*domain.PlaybackResult, error) {
+	ids := make([]string, 0, len(mediaIDs))
+	seen := make(map[string]bool, len(mediaIDs))
+	for _, id := range mediaIDs {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, domain.NewBusinessError("INVALID_REQUEST", "At least one media ID is required")
+	}
+	if len(ids) > domain.MaxPlaybackBatchSize {
+		return nil, domain.NewBusinessError("TOO_MANY_IDS", fmt.Sprintf("At most %d media can be played at once", domain.MaxPlaybackBatchSize))
+	}
+
+	mediaList, err := s.mediaRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	mediaByID := make(map[string]*domain.Media, len(mediaList))
+	for _, media := range mediaList {
+		mediaByID[media.ID] = media
+	}
+
+	results := make([]*domain.PlaybackResult, 0, len(ids))
+	for _, id := range ids {
+		result := &domain.PlaybackResult{MediaID: id}
+		results = append(results, result)
+
+		// Private media is reported as missing, like by GetVisibleMedia
+		media, ok := mediaByID[id]
+		if !ok || !media.IsVisibleTo(viewer) {
+			result.Error, result.Message = playbackFailure(domain.ErrMediaNotFound)
+			continue
+		}
+		info, err := s.playbackInfo(ctx, media, viewer)
+		if err != nil {
+			code, message := playbackFailure(err)
+			if code == "" {
+				return nil, err
+			}
+			result.Error, result.Message = code, message
+			continue
+		}
+		result.Playback = info
+	}
+
+	return results, nil
+}
+
+// playbackInfo checks that viewer may play media it can see and returns its playback info
+func (s *playbackService) playbackInfo(ctx context.Context, media *domain.Media, viewer domain.Viewer) (*domain.PlaybackInfo, error) {
+	if err := media.CheckPlayback(viewer); err != nil {
+		return nil, err
+	}
+
+	if !media.IsProcessed() {
+		return nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for playback")
+	}
+
+	drm, err := s.drmService.PlaybackDRM(ctx, media)
+	if err != nil {
+		return nil, err
+	}
+
+	// Protected media plays from its encrypted renditions, the upload is clear
+	var streamURL string
+	if drm != nil {
+		streamURL = fmt.Sprintf("%s/api/v1/media/%s/stream?format=%s", s.baseURL, media.ID, domain.StreamFormatDASH)
+	} else if streamURL, err = s.signStreamURL(ctx, media); err != nil {
+		return nil, err
+	}
+
+	info := &domain.PlaybackInfo{
+		MediaID:   media.ID,
+		Type:      media.Type,
+		StreamURL: streamURL,
+		DRM:       drm,
+		CuePoints: media.CuePoints,
+	}
+	if len(media.AudioTracks) > 0 {
+		info.AudioTracks = append(info.AudioTracks, domain.PlaybackAudioTrack{
+			Language: media.AudioLanguage,
+			URL:      info.StreamURL,
+			Default:  true,
+		})
+		for _, track := range media.AudioTracks {
+			info.AudioTracks = append(info.AudioTracks, domain.PlaybackAudioTrack{
+				Language: track.Language,
+				Label:    track.Label,
+				URL:      fmt.Sprintf("%s/api/v1/media/%s/audio-tracks/%s", s.baseURL, media.ID, track.Language),
+			})
+		}
+	}
+	return info, nil
+}
+
+// signStreamURL returns a signed URL of the uploaded file of media, valid
+// while it plays since the file is read all along playback
+func (s *playbackService) signStreamURL(ctx context.Context, media *domain.Media) (string, error) {
+	key := media.StorageKey()
+	if key == "" {
+		return "", domain.ErrFileNotFound
+	}
+	presigner, ok := s.storage.(storage.GetPresigner)
+	if !ok {
+		return "", errors.New("storage does not issue download URLs")
+	}
+
+	streamURL, err := presigner.PresignGet(ctx, key, s.urlTTL+time.Duration(media.Duration)*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign stream URL: %w", err)
+	}
+	return streamURL, nil
+}
+
+// playbackFailure returns the code and message of an error refusing the
+// playback of a media item, or an empty code for unexpected errors
+func playbackFailure(err error) (string, string) {
+	var geoErr *domain.GeoRestrictionError
+	var entitlementErr *domain.EntitlementError
+	var premiereErr *domain.PremiereNotStartedError
+	var businessErr *domain.BusinessError
+	switch {
+	case errors.Is(err, domain.ErrMediaNotFound):
+		return "MEDIA_NOT_FOUND", "Media not found"
+	case errors.Is(err, domain.ErrFileNotFound):
+		return "FILE_NOT_FOUND", "Media has no uploaded file"
+	case errors.As(err, &geoErr):
+		return "GEO_RESTRICTED", "Media is not available in your country"
+	case errors.As(err, &entitlementErr):
+		return "SUBSCRIPTION_REQUIRED", "A premium subscription is required for this media"
+	case errors.As(err, &premiereErr):
+		return "PREMIERE_NOT_STARTED", fmt.Sprintf("Media premieres at %s", premiereErr.StartsAt.Format(time.RFC3339))
+	case errors.As(err, &businessErr):
+		return businessErr.Code, businessErr.Message
+	}
+	return "", ""
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPlaybackStore(t *testing.T) *storage.PresigningLocalStorage {
+	t.Helper()
+	store, err := storage.NewPresigningLocalStorage(storage.NewLocalStorage(t.TempDir()), "https://cms.test", []byte("signing-key"))
+	require.NoError(t, err)
+	return store
+}
+
+// assertSignedStreamURL checks streamURL is a download URL of key signed by store
+func assertSignedStreamURL(t *testing.T, store *storage.PresigningLocalStorage, key, streamURL string) {
+	t.Helper()
+	u, err := url.Parse(streamURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/files/"+key, u.Path)
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	assert.NoError(t, store.VerifyGet(key, "", expires, u.Query().Get("signature")))
+}
+
+func TestPlaybackService_GetPlayback_GeoRestriction(t *testing.T) {
+	restriction := domain.GeoRestriction{
+		AllowedCountries: []string{"SA", "AE"},
+		BlockedCountries: []string{"AE"},
+	}
+
+	tests := []struct {
+		name          string
+		viewer        domain.Viewer
+		expectBlocked bool
+	}{
+		{name: "allowed country", viewer: domain.Viewer{Country: "SA"}},
+		{name: "country outside allow list", viewer: domain.Viewer{Country: "US"}, expectBlocked: true},
+		{name: "blocked country wins over allow list", viewer: domain.Viewer{Country: "AE"}, expectBlocked: true},
+		{name: "unknown country", viewer: domain.Viewer{}, expectBlocked: true},
+		{name: "admin outside allow list", viewer: domain.Viewer{IsAdmin: true, Country: "US"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			media := &domain.Media{
+				ID:             "media-123",
+				Type:           domain.TypeVideo,
+				Status:         domain.StatusReady,
+				FilePath:       "/uploads/media-123.mp4",
+				GeoRestriction: restriction,
+			}
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+			drmService, err := NewDRMService(new(MockContentKeyRepository), DRMOptions{})
+			require.NoError(t, err)
+			store := newTestPlaybackStore(t)
+			service := NewPlaybackService(mockRepo, drmService, store, "https://media.example.com/", 0)
+
+			// When
+			info, err := service.GetPlayback(context.Background(), "media-123", tt.viewer)
+
+			// Then
+			if tt.expectBlocked {
+				assert.ErrorIs(t, err, domain.ErrGeoRestricted)
+				var geoErr *domain.GeoRestrictionError
+				require.ErrorAs(t, err, &geoErr)
+				assert.Equal(t, "media-123", geoErr.MediaID)
+				assert.Equal(t, tt.viewer.Country, geoErr.Country)
+				assert.Nil(t, info)
+				return
+			}
+
+			require.NoError(t, err)
+			assertSignedStreamURL(t, store, "media-123.mp4", info.StreamURL)
+		})
+	}
+}
+
+func TestPlaybackService_GetPlayback_CuePoints(t *testing.T) {
+	// Given an episode with ad breaks
+	cuePoints := []domain.CuePoint{{Position: domain.CuePointPreroll}, {Position: domain.CuePointMidroll, OffsetSeconds: 600}}
+	media := &domain.Media{
+		ID:        "media-123",
+		Type:      domain.TypePodcast,
+		Status:    domain.StatusReady,
+		FilePath:  "/uploads/media-123.mp3",
+		CuePoints: cuePoints,
+	}
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	drmService, err := NewDRMService(new(MockContentKeyRepository), DRMOptions{})
+	require.NoError(t, err)
+	store := newTestPlaybackStore(t)
+	service := NewPlaybackService(mockRepo, drmService, store, "https://media.example.com", 0)
+
+	// When
+	info, err := service.GetPlayback(context.Background(), "media-123", domain.Viewer{})
+
+	// Then players get the breaks to request ads for
+	require.NoError(t, err)
+	assert.Equal(t, cuePoints, info.CuePoints)
+}
+
+func TestPlaybackService_GetPlayback_AudioTracks(t *testing.T) {
+	// Given a video dubbed in English
+	media := &domain.Media{
+		ID:            "media-123",
+		Type:          domain.TypeVideo,
+		Status:        domain.StatusReady,
+		FilePath:      "/uploads/media-123.mp4",
+		AudioLanguage: "ar",
+		AudioTracks:   []domain.AudioTrack{{Language: "en", Label: "English", Format: "mp3"}},
+	}
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	drmService, err := NewDRMService(new(MockContentKeyRepository), DRMOptions{})
+	require.NoError(t, err)
+	store := newTestPlaybackStore(t)
+	service := NewPlaybackService(mockRepo, drmService, store, "https://media.example.com", 0)
+
+	// When
+	info, err := service.GetPlayback(context.Background(), "media-123", domain.Viewer{})
+
+	// Then players can switch from the original audio to the dub
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PlaybackAudioTrack{
+		{Language: "ar", URL: info.StreamURL, Default: true},
+		{Language: "en", Label: "English", URL: "https://media.example.com/api/v1/media/media-123/audio-tracks/en"},
+	}, info.AudioTracks)
+}
+
+func TestPlaybackService_GetPlayback_Entitlement(t *testing.T) {
+	tests := []struct {
+		name          string
+		viewer        domain.Viewer
+		expectBlocked bool
+	}{
+		{name: "anonymous viewer", viewer: domain.Viewer{}, expectBlocked: true},
+		{name: "free subscriber", viewer: domain.Viewer{UserID: "u1", Tier: domain.SubscriptionFree}, expectBlocked: true},
+		{name: "premium subscriber", viewer: domain.Viewer{UserID: "u1", Tier: domain.SubscriptionPremium}},
+		{name: "admin", viewer: domain.Viewer{IsAdmin: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a premium episode
+			media := &domain.Media{
+				ID:         "media-123",
+				Type:       domain.TypePodcast,
+				Status:     domain.StatusReady,
+				FilePath:   "/uploads/media-123.mp3",
+				AccessTier: domain.AccessTierPremium,
+			}
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+			drmService, err := NewDRMService(new(MockContentKeyRepository), DRMOptions{})
+			require.NoError(t, err)
+			store := newTestPlaybackStore(t)
+			service := NewPlaybackService(mockRepo, drmService, store, "https://media.example.com", 0)
+
+			// When
+			info, err := service.GetPlayback(context.Background(), "media-123", tt.viewer)
+
+			// Then no stream URL is issued without a premium subscription
+			if tt.expectBlocked {
+				assert.ErrorIs(t, err, domain.ErrNotEntitled)
+				assert.Nil(t, info)
+				return
+			}
+			require.NoError(t, err)
+			assertSignedStreamURL(t, store, "media-123.mp3", info.StreamURL)
+		})
+	}
+}
+
+func TestPlaybackService_GetPlaybackBatch(t *testing.T) {
+	// Given a playlist with playable, premium, unprocessed, private and missing media
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByIDs", mock.Anything, []string{"ready", "premium", "processing", "private", "missing"}).Return([]*domain.Media{
+		{ID: "private", Type: domain.TypePodcast, Status: domain.StatusReady, FilePath: "/uploads/private.mp3", Visibility: domain.VisibilityPrivate},
+		{ID: "processing", Type: domain.TypePodcast, Status: domain.StatusProcessing},
+		{ID: "premium", Type: domain.TypePodcast, Status: domain.StatusReady, FilePath: "/uploads/premium.mp3", AccessTier: domain.AccessTierPremium},
+		{ID: "ready", Type: domain.TypePodcast, Status: domain.StatusReady, FilePath: "/uploads/ready.mp3"},
+	}, nil)
+	drmService, err := NewDRMService(new(MockContentKeyRepository), DRMOptions{})
+	require.NoError(t, err)
+	store := newTestPlaybackStore(t)
+	service := NewPlaybackService(mockRepo, drmService, store, "https://media.example.com", 0)
+
+	// When the playback of the playlist is requested, with a duplicate
+	results, err := service.GetPlaybackBatch(context.Background(), []string{"ready", "premium", "ready", "processing", "private", " missing "}, domain.Viewer{})
+
+	// Then every item is resolved in order from one query, with the reason unplayable items are refused
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.Equal(t, "ready", results[0].MediaID)
+	assertSignedStreamURL(t, store, "ready.mp3", results[0].Playback.StreamURL)
+	assert.Empty(t, results[0].Error)
+
+	var codes []string
+	for _, result := range results[1:] {
+		assert.Nil(t, result.Playback)
+		codes = append(codes, result.MediaID+":"+result.Error)
+	}
+	assert.Equal(t, []string{"premium:SUBSCRIPTION_REQUIRED", "processing:MEDIA_NOT_READY", "private:MEDIA_NOT_FOUND", "missing:MEDIA_NOT_FOUND"}, codes)
+	mockRepo.AssertNumberOfCalls(t, "GetByIDs", 1)
+}
+
+func TestPlaybackService_GetPlaybackBatch_Invalid(t *testing.T) {
+	service := NewPlaybackService(new(MockMediaRepository), nil, nil, "https://media.example.com", 0)
+	tooMany := make([]string, domain.MaxPlaybackBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("media-%d", i)
+	}
+
+	tests := []struct {
+		name     string
+		ids      []string
+		expected string
+	}{
+		{"no IDs", []string{" ", ""}, "INVALID_REQUEST"},
+		{"too many IDs", tooMany, "TOO_MANY_IDS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetPlaybackBatch(context.Background(), tt.ids, domain.Viewer{})
+
+			var businessErr *domain.BusinessError
+			require.ErrorAs(t, err, &businessErr)
+			assert.Equal(t, tt.expected, businessErr.Code)
+		})
+	}
+}
+
+func TestPlaybackService_GetPlayback_StreamURLExpiry(t *testing.T) {
+	// Given a one hour episode
+	media := &domain.Media{
+		ID:       "media-123",
+		Type:     domain.TypePodcast,
+		Status:   domain.StatusReady,
+		FilePath: "/uploads/media-123.mp3",
+		Duration: 3600,
+	}
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	drmService, err := NewDRMService(new(MockContentKeyRepository), DRMOptions{})
+	require.NoError(t, err)
+	store := newTestPlaybackStore(t)
+	service := NewPlaybackService(mockRepo, drmService, store, "https://media.example.com", 5*time.Minute)
+
+	// When
+	before := time.Now()
+	info, err := service.GetPlayback(context.Background(), "media-123", domain.Viewer{})
+
+	// Then the signed URL outlives the playback of the whole file
+	require.NoError(t, err)
+	assertSignedStreamURL(t, store, "media-123.mp3", info.StreamURL)
+	u, err := url.Parse(info.StreamURL)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, expires, before.Add(time.Hour+5*time.Minute).Unix())
+	assert.LessOrEqual(t, expires, time.Now().Add(time.Hour+5*time.Minute).Unix())
+}
+
+func TestPlaybackService_GetPlayback_ProtectedMedia(t *testing.T) {
+	// Given a premium video protected with DRM
+	media := &domain.Media{
+		ID:         "media-123",
+		Type:       domain.TypeVideo,
+		Status:     domain.StatusReady,
+		FilePath:   "/uploads/media-123.mp4",
+		AccessTier: domain.AccessTierPremium,
+	}
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	keyRepo := new(MockContentKeyRepository)
+	keyRepo.On("GetByMediaID", mock.Anything, "media-123").Return(&domain.ContentKey{MediaID: "media-123", KeyID: "00112233445566778899aabbccddeeff"}, nil)
+	drmService, err := NewDRMService(keyRepo, testDRMOptions())
+	require.NoError(t, err)
+	service := NewPlaybackService(mockRepo, drmService, newTestPlaybackStore(t), "https://media.example.com", 0)
+
+	// When
+	info, err := service.GetPlayback(context.Background(), "media-123", domain.Viewer{UserID: "u1", Tier: domain.SubscriptionPremium})
+
+	// Then it plays from the DASH manifest of its encrypted renditions, not from the clear upload
+	require.NoError(t, err)
+	assert.Equal(t, "https://media.example.com/api/v1/media/media-123/stream?format=dash", info.StreamURL)
+	require.NotNil(t, info.DRM)
+	assert.Equal(t, "00112233445566778899aabbccddeeff", info.DRM.KeyID)
+}
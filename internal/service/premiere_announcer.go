@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// PremiereAnnouncerOptions configures the announcement of premieres
+type PremiereAnnouncerOptions struct {
+	Interval  time.Duration // wait between sweeps
+	BatchSize int           // media handled per sweep
+}
+
+// PremiereSweepResult reports what a sweep did
+type PremiereSweepResult struct {
+	Announced int `json:"announced"`
+}
+
+// PremiereAnnouncer announces premieres as they start so subscribers can be notified
+type PremiereAnnouncer interface {
+	// Sweep announces the published media whose premiere started
+	Sweep(ctx context.Context) (*PremiereSweepResult, error)
+
+	// Run sweeps periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// premiereAnnouncer implements PremiereAnnouncer interface
+type premiereAnnouncer struct {
+	mediaRepo repository.MediaRepository
+	publisher EventPublisher
+	options   PremiereAnnouncerOptions
+	now       func() time.Time
+}
+
+// NewPremiereAnnouncer creates a new premiere announcer
+func NewPremiereAnnouncer(mediaRepo repository.MediaRepository, publisher EventPublisher, options PremiereAnnouncerOptions) PremiereAnnouncer {
+	if options.Interval <= 0 {
+		options.Interval = 15 * time.Second
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+
+	return &premiereAnnouncer{
+		mediaRepo: mediaRepo,
+		publisher: publisher,
+		options:   options,
+		now:       time.Now,
+	}
+}
+
+// Sweep announces the published media whose premiere started
+func (a *premiereAnnouncer) Sweep(ctx context.Context) (*PremiereSweepResult, error) {
+	now := a.now()
+	result := &PremiereSweepResult{}
+
+	due, err := a.mediaRepo.GetDuePremieres(ctx, now, a.options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load started premieres: %w", err)
+	}
+	for _, media := range due {
+		// One failing item must not hold back the announcement of the others
+		if err := a.announce(ctx, media, now); err != nil {
+			log.Printf("Failed to announce the premiere of media %s: %v", media.ID, err)
+			continue
+		}
+		result.Announced++
+	}
+
+	return result, nil
+}
+
+// Run sweeps periodically until ctx is cancelled
+func (a *premiereAnnouncer) Run(ctx context.Context) {
+	for {
+		result, err := a.Sweep(ctx)
+		if err != nil {
+			log.Printf("Premiere sweep failed: %v", err)
+		} else if result.Announced > 0 {
+			log.Printf("Premiere sweep announced %d premieres", result.Announced)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(a.options.Interval):
+		}
+	}
+}
+
+// announce publishes the start of a premiere and records it so it is announced once
+func (a *premiereAnnouncer) announce(ctx context.Context, media *domain.Media, now time.Time) error {
+	event := domain.NewEvent(domain.EventPremiereStarted, map[string]interface{}{
+		"media_id":  media.ID,
+		"starts_at": media.Premiere.StartsAt.UTC().Format(time.RFC3339),
+	})
+	if err := a.publisher.Publish(ctx, event); err != nil {
+		return err
+	}
+
+	media.Premiere.AnnouncedAt = &now
+	return a.mediaRepo.UpdatePremiere(ctx, media.ID, media.Premiere)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// PremiereService schedules the premieres of media and counts down to them
+type PremiereService interface {
+	// SchedulePremiere schedules or moves the premiere of a video or podcast.
+	// Playback is refused to viewers until it starts.
+	SchedulePremiere(ctx context.Context, mediaID string, req *domain.PremiereRequest) (*domain.Media, error)
+
+	// CancelPremiere removes the premiere of media, opening its playback
+	CancelPremiere(ctx context.Context, mediaID string) error
+
+	// GetCountdown returns how long until the premiere of media starts
+	GetCountdown(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.PremiereCountdown, error)
+}
+
+// premiereService implements PremiereService interface
+type premiereService struct {
+	mediaRepo repository.MediaRepository
+	now       func() time.Time
+}
+
+// NewPremiereService creates a new premiere service
+func NewPremiereService(mediaRepo repository.MediaRepository) PremiereService {
+	return &premiereService{
+		mediaRepo: mediaRepo,
+		now:       time.Now,
+	}
+}
+
+// SchedulePremiere schedules or moves the premiere of a video or podcast
+func (s *premiereService) SchedulePremiere(ctx context.Context, mediaID string, req *domain.PremiereRequest) (*domain.Media, error) {
+	now := s.now()
+	if errs := req.Validate(now); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_PREMIERE", "Invalid premiere", errs.Error())
+	}
+
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Type != domain.TypeVideo && media.Type != domain.TypePodcast {
+		return nil, domain.NewBusinessError("INVALID_MEDIA_TYPE", fmt.Sprintf("Only videos and podcasts can premiere, not %s media", media.Type))
+	}
+	// Viewers may already have played the media, it cannot be kept from them again
+	if media.Premiere.IsScheduled() && !media.Premiere.IsUpcoming(now) {
+		return nil, domain.NewBusinessError("PREMIERE_STARTED", "The premiere of the media has already started")
+	}
+
+	startsAt := req.StartsAt.UTC()
+	media.Premiere = domain.Premiere{StartsAt: &startsAt}
+	if err := s.mediaRepo.UpdatePremiere(ctx, media.ID, media.Premiere); err != nil {
+		return nil, fmt.Errorf("failed to schedule premiere: %w", err)
+	}
+
+	return media, nil
+}
+
+// CancelPremiere removes the premiere of media
+func (s *premiereService) CancelPremiere(ctx context.Context, mediaID string) error {
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	if !media.Premiere.IsScheduled() {
+		return domain.ErrPremiereNotFound
+	}
+
+	if err := s.mediaRepo.UpdatePremiere(ctx, media.ID, domain.Premiere{}); err != nil {
+		return fmt.Errorf("failed to cancel premiere: %w", err)
+	}
+	return nil
+}
+
+// GetCountdown returns how long until the premiere of media starts
+func (s *premiereService) GetCountdown(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.PremiereCountdown, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !media.Premiere.IsScheduled() {
+		return nil, domain.ErrPremiereNotFound
+	}
+
+	return domain.NewPremiereCountdown(media, s.now()), nil
+}
+
+// getMedia loads media, reporting deleted media as missing
+func (s *premiereService) getMedia(ctx context.Context, mediaID string) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+	return media, nil
+}
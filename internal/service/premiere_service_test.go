@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPremiereService_SchedulePremiere(t *testing.T) {
+	// Given a published video and a live stream
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "video", Title: "Trailer", Type: domain.TypeVideo, Status: domain.StatusReady}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "live", Title: "Stream", Type: domain.TypeLive, Status: domain.StatusUploading}))
+	service := NewPremiereService(mediaRepo).(*premiereService)
+	service.now = func() time.Time { return now }
+
+	// When the video premieres in an hour
+	startsAt := now.Add(time.Hour)
+	media, err := service.SchedulePremiere(ctx, "video", &domain.PremiereRequest{StartsAt: startsAt})
+
+	// Then viewers count down to it
+	require.NoError(t, err)
+	assert.Equal(t, startsAt, *media.Premiere.StartsAt)
+	countdown, err := service.GetCountdown(ctx, "video", domain.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600), countdown.SecondsRemaining)
+	assert.False(t, countdown.Started)
+
+	t.Run("rejects premieres in the past", func(t *testing.T) {
+		_, err := service.SchedulePremiere(ctx, "video", &domain.PremiereRequest{StartsAt: now})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "INVALID_PREMIERE", businessErr.Code)
+	})
+
+	t.Run("rejects live streams", func(t *testing.T) {
+		_, err := service.SchedulePremiere(ctx, "live", &domain.PremiereRequest{StartsAt: startsAt})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "INVALID_MEDIA_TYPE", businessErr.Code)
+	})
+
+	t.Run("rejects moving a premiere that started", func(t *testing.T) {
+		service.now = func() time.Time { return startsAt }
+		defer func() { service.now = func() time.Time { return now } }()
+
+		_, err := service.SchedulePremiere(ctx, "video", &domain.PremiereRequest{StartsAt: startsAt.Add(time.Hour)})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "PREMIERE_STARTED", businessErr.Code)
+	})
+
+	t.Run("cancels the premiere", func(t *testing.T) {
+		require.NoError(t, service.CancelPremiere(ctx, "video"))
+
+		_, err := service.GetCountdown(ctx, "video", domain.Viewer{})
+		assert.ErrorIs(t, err, domain.ErrPremiereNotFound)
+		assert.ErrorIs(t, service.CancelPremiere(ctx, "video"), domain.ErrPremiereNotFound)
+	})
+}
+
+func TestPremiereAnnouncer_Sweep(t *testing.T) {
+	// Given a published video whose premiere started and a private one
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	startedAt := now.Add(-time.Second)
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{
+		ID: "video", Title: "Trailer", Type: domain.TypeVideo, Status: domain.StatusReady, Visibility: domain.VisibilityPublic,
+		Premiere: domain.Premiere{StartsAt: &startedAt},
+	}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{
+		ID: "private", Title: "Draft", Type: domain.TypeVideo, Status: domain.StatusReady, Visibility: domain.VisibilityPrivate,
+		Premiere: domain.Premiere{StartsAt: &startedAt},
+	}))
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventPremiereStarted && event.Data["media_id"] == "video"
+	})).Return(nil).Once()
+	announcer := NewPremiereAnnouncer(mediaRepo, publisher, PremiereAnnouncerOptions{BatchSize: 10}).(*premiereAnnouncer)
+	announcer.now = func() time.Time { return now }
+
+	// When the announcer sweeps twice
+	result, err := announcer.Sweep(ctx)
+	require.NoError(t, err)
+	again, err := announcer.Sweep(ctx)
+	require.NoError(t, err)
+
+	// Then the premiere is announced once
+	assert.Equal(t, 1, result.Announced)
+	assert.Zero(t, again.Announced)
+	publisher.AssertExpectations(t)
+	media, err := mediaRepo.GetByID(ctx, "video")
+	require.NoError(t, err)
+	require.NotNil(t, media.Premiere.AnnouncedAt)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// ProcessingJob asks the worker to process a media file
+type ProcessingJob struct {
+	MediaID    string
+	Priority   domain.MediaPriority
+	EnqueuedAt time.Time
+	Trace      *domain.TraceContext // request that queued the job
+	Attempt    int                  // earlier attempts of the worker, 0 for a new job
+}
+
+// NewProcessingJob creates a processing job for the media
+func NewProcessingJob(media *domain.Media) *ProcessingJob {
+	priority := media.Priority
+	if !priority.IsValid() {
+		priority = domain.PriorityNormal
+	}
+
+	return &ProcessingJob{
+		MediaID:    media.ID,
+		Priority:   priority,
+		EnqueuedAt: time.Now(),
+	}
+}
+
+// ProcessingQueue buffers processing jobs for the processing worker
+type ProcessingQueue interface {
+	// Enqueue adds a job to the lane of its priority without blocking
+	Enqueue(ctx context.Context, job *ProcessingJob) error
+
+	// Dequeue blocks until a job is available or ctx is cancelled
+	Dequeue(ctx context.Context) (*ProcessingJob, error)
+}
+
+// priorityProcessingQueue implements ProcessingQueue with a high and a normal lane.
+// High priority jobs are served first, but after starvationLimit consecutive high
+// priority jobs a waiting normal job is served so the normal lane keeps moving.
+type priorityProcessingQueue struct {
+	high            chan *ProcessingJob
+	normal          chan *ProcessingJob
+	starvationLimit int
+
+	mu         sync.Mutex
+	highStreak int
+}
+
+// NewPriorityProcessingQueue creates an in-process processing queue with two lanes
+func NewPriorityProcessingQueue(capacity, starvationLimit int) ProcessingQueue {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	if starvationLimit <= 0 {
+		starvationLimit = 5
+	}
+
+	return &priorityProcessingQueue{
+		high:            make(chan *ProcessingJob, capacity),
+		normal:          make(chan *ProcessingJob, capacity),
+		starvationLimit: starvationLimit,
+	}
+}
+
+// Enqueue adds a job to the lane of its priority without blocking
+func (q *priorityProcessingQueue) Enqueue(ctx context.Context, job *ProcessingJob) error {
+	if job.Trace == nil {
+		job.Trace = domain.TraceFromContext(ctx)
+	}
+
+	lane := q.normal
+	if job.Priority == domain.PriorityHigh {
+		lane = q.high
+	}
+
+	select {
+	case lane <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return domain.ErrProcessingQueueFull
+	}
+}
+
+// Dequeue blocks until a job is available or ctx is cancelled
+func (q *priorityProcessingQueue) Dequeue(ctx context.Context) (*ProcessingJob, error) {
+	// Give the normal lane a turn once high priority jobs have had theirs
+	if q.starved() {
+		select {
+		case job := <-q.normal:
+			return q.served(job), nil
+		default:
+		}
+	}
+
+	select {
+	case job := <-q.high:
+		return q.served(job), nil
+	default:
+	}
+
+	select {
+	case job := <-q.normal:
+		return q.served(job), nil
+	default:
+	}
+
+	select {
+	case job := <-q.high:
+		return q.served(job), nil
+	case job := <-q.normal:
+		return q.served(job), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// starved reports whether the normal lane is due a turn
+func (q *priorityProcessingQueue) starved() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.highStreak >= q.starvationLimit
+}
+
+// served updates the high priority streak after a job was taken
+func (q *priorityProcessingQueue) served(job *ProcessingJob) *ProcessingJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Only count the streak while normal jobs are actually waiting
+	if job.Priority == domain.PriorityHigh && len(q.normal) > 0 {
+		q.highStreak++
+	} else {
+		q.highStreak = 0
+	}
+
+	return job
+}
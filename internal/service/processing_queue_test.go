@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityProcessingQueue_Dequeue(t *testing.T) {
+	tests := []struct {
+		name            string
+		starvationLimit int
+		enqueue         []domain.MediaPriority
+		expected        []domain.MediaPriority
+	}{
+		{
+			name:            "high priority jobs are served first",
+			starvationLimit: 5,
+			enqueue:         []domain.MediaPriority{domain.PriorityNormal, domain.PriorityHigh, domain.PriorityNormal, domain.PriorityHigh},
+			expected:        []domain.MediaPriority{domain.PriorityHigh, domain.PriorityHigh, domain.PriorityNormal, domain.PriorityNormal},
+		},
+		{
+			name:            "normal lane gets a turn after the starvation limit",
+			starvationLimit: 2,
+			enqueue: []domain.MediaPriority{
+				domain.PriorityNormal, domain.PriorityNormal,
+				domain.PriorityHigh, domain.PriorityHigh, domain.PriorityHigh, domain.PriorityHigh, domain.PriorityHigh,
+			},
+			expected: []domain.MediaPriority{
+				domain.PriorityHigh, domain.PriorityHigh, domain.PriorityNormal,
+				domain.PriorityHigh, domain.PriorityHigh, domain.PriorityNormal,
+				domain.PriorityHigh,
+			},
+		},
+		{
+			name:            "only normal jobs",
+			starvationLimit: 1,
+			enqueue:         []domain.MediaPriority{domain.PriorityNormal, domain.PriorityNormal},
+			expected:        []domain.MediaPriority{domain.PriorityNormal, domain.PriorityNormal},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			queue := NewPriorityProcessingQueue(10, tt.starvationLimit)
+			ctx := context.Background()
+			for i, priority := range tt.enqueue {
+				media := &domain.Media{ID: string(rune('a' + i)), Priority: priority}
+				assert.NoError(t, queue.Enqueue(ctx, NewProcessingJob(media)))
+			}
+
+			// When
+			var served []domain.MediaPriority
+			for range tt.expected {
+				job, err := queue.Dequeue(ctx)
+				assert.NoError(t, err)
+				served = append(served, job.Priority)
+			}
+
+			// Then
+			assert.Equal(t, tt.expected, served)
+		})
+	}
+}
+
+func TestPriorityProcessingQueue_EnqueueFull(t *testing.T) {
+	// Given
+	queue := NewPriorityProcessingQueue(1, 5)
+	ctx := context.Background()
+	assert.NoError(t, queue.Enqueue(ctx, NewProcessingJob(&domain.Media{ID: "media-1"})))
+
+	// When
+	err := queue.Enqueue(ctx, NewProcessingJob(&domain.Media{ID: "media-2"}))
+
+	// Then
+	assert.ErrorIs(t, err, domain.ErrProcessingQueueFull)
+}
+
+func TestPriorityProcessingQueue_DequeueCancelled(t *testing.T) {
+	// Given
+	queue := NewPriorityProcessingQueue(1, 5)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// When
+	job, err := queue.Dequeue(ctx)
+
+	// Then
+	assert.Nil(t, job)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPriorityProcessingQueue_EnqueueKeepsTrace(t *testing.T) {
+	// Given
+	queue := NewPriorityProcessingQueue(1, 5)
+	trace := &domain.TraceContext{RequestID: "req-1"}
+	ctx := domain.ContextWithTrace(context.Background(), trace)
+
+	// When
+	assert.NoError(t, queue.Enqueue(ctx, NewProcessingJob(&domain.Media{ID: "media-1"})))
+	job, err := queue.Dequeue(context.Background())
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, trace, job.Trace)
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/messagequeue"
+)
+
+// Processing retry defaults
+const (
+	DefaultProcessingAttempts      = 3
+	DefaultProcessingRetryDelay    = 10 * time.Second
+	DefaultProcessingMaxRetryDelay = 5 * time.Minute
+	processingResumePageSize       = 100
+	processingQueueWait            = 100 * time.Millisecond // between attempts to queue a job while the queue is full
+	processingMaintenanceWait      = time.Second            // between checks of the maintenance mode while it is read-only
+)
+
+// ProcessingWorkerOptions tunes the processing worker; zero values use the defaults
+type ProcessingWorkerOptions struct {
+	Concurrency   int           // jobs processed at once, 1 when 0
+	MaxAttempts   int           // attempts before media is marked as failed
+	RetryDelay    time.Duration // wait before the first retry, doubled for every next one
+	MaxRetryDelay time.Duration
+	// Events, when set, is the message queue the worker takes confirmed uploads
+	// from as media.uploaded events
+	Events messagequeue.MessageQueue
+	// Maintenance, when set, holds jobs back while it is read-only
+	Maintenance MaintenanceGate
+	// Thumbnails renders the default thumbnail of processed media ahead of the
+	// first request; nil skips it
+	Thumbnails ThumbnailService
+	// Transcoder produces the renditions of processed media; nil skips it
+	Transcoder MediaTranscoder
+}
+
+// MediaTranscoder transcodes processed media into its renditions
+type MediaTranscoder interface {
+	Transcode(ctx context.Context, mediaID string) error
+}
+
+// ProcessingWorker runs media processing for jobs taken from the processing queue.
+// Jobs failing for transient reasons are queued again with a growing delay until
+// the attempts run out and the media is marked as failed.
+type ProcessingWorker struct {
+	queue        ProcessingQueue
+	mediaService MediaService
+	options      ProcessingWorkerOptions
+	retries      sync.WaitGroup
+}
+
+// NewProcessingWorker creates a new processing worker pool
+func NewProcessingWorker(queue ProcessingQueue, mediaService MediaService, options ProcessingWorkerOptions) *ProcessingWorker {
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultProcessingAttempts
+	}
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = DefaultProcessingRetryDelay
+	}
+	if options.MaxRetryDelay <= 0 {
+		options.MaxRetryDelay = DefaultProcessingMaxRetryDelay
+	}
+
+	return &ProcessingWorker{
+		queue:        queue,
+		mediaService: mediaService,
+		options:      options,
+	}
+}
+
+// Run processes jobs until ctx is cancelled and waits for in-flight jobs to finish
+func (w *ProcessingWorker) Run(ctx context.Context) {
+	if w.options.Events != nil {
+		if err := w.options.Events.Subscribe(ctx, domain.EventMediaUploaded, w.handleUploaded); err != nil {
+			log.Printf("Processing worker failed to subscribe to %s: %v", domain.EventMediaUploaded, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.consume(ctx)
+		}()
+	}
+	wg.Wait()
+	w.retries.Wait()
+}
+
+// consume takes jobs off the queue one at a time until ctx is cancelled
+func (w *ProcessingWorker) consume(ctx context.Context) {
+	for {
+		if !w.waitWritable(ctx) {
+			return
+		}
+
+		job, err := w.queue.Dequeue(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return
+			}
+			log.Printf("Processing worker failed to dequeue job: %v", err)
+			continue
+		}
+
+		w.handle(ctx, job)
+	}
+}
+
+// waitWritable waits while the service is in read-only maintenance mode. It
+// returns false when ctx is cancelled meanwhile.
+func (w *ProcessingWorker) waitWritable(ctx context.Context) bool {
+	for w.options.Maintenance != nil && w.options.Maintenance.ReadOnly() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(processingMaintenanceWait):
+		}
+	}
+	return ctx.Err() == nil
+}
+
+// handleUploaded queues the processing of the media a media.uploaded event announces
+func (w *ProcessingWorker) handleUploaded(ctx context.Context, message []byte) error {
+	var event domain.Event
+	if err := json.Unmarshal(message, &event); err != nil {
+		return fmt.Errorf("invalid %s event: %w", domain.EventMediaUploaded, err)
+	}
+	mediaID, _ := event.Data["media_id"].(string)
+	if mediaID == "" {
+		return fmt.Errorf("%s event %s has no media", domain.EventMediaUploaded, event.ID)
+	}
+
+	media, err := w.mediaService.GetMedia(ctx, mediaID)
+	if errors.Is(err, domain.ErrMediaNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get uploaded media %s: %w", mediaID, err)
+	}
+	// Redelivered events find the media processed already
+	if media.Status != domain.StatusProcessing {
+		return nil
+	}
+
+	job := NewProcessingJob(media)
+	job.Trace = event.Trace
+	return w.enqueue(ctx, job)
+}
+
+// enqueue queues the job, waiting for room while the queue is full
+func (w *ProcessingWorker) enqueue(ctx context.Context, job *ProcessingJob) error {
+	for {
+		err := w.queue.Enqueue(ctx, job)
+		if !errors.Is(err, domain.ErrProcessingQueueFull) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(processingQueueWait):
+		}
+	}
+}
+
+// handle processes a single job in the trace of the request that queued it
+func (w *ProcessingWorker) handle(ctx context.Context, job *ProcessingJob) {
+	ctx = domain.ContextWithTrace(ctx, job.Trace)
+	log.Printf("Processing media %s (%s priority, attempt %d)", job.MediaID, job.Priority, job.Attempt+1)
+
+	err := w.mediaService.ProcessMedia(ctx, job.MediaID)
+	if err == nil {
+		w.warmThumbnail(ctx, job.MediaID)
+		w.transcode(ctx, job.MediaID)
+		return
+	}
+	log.Printf("Processing media %s failed: %v", job.MediaID, err)
+	if ctx.Err() != nil {
+		// Shutting down, the media is resumed on the next start
+		return
+	}
+
+	// Processing marks media as failed itself when the file is at fault; only
+	// media still in processing failed for a reason that may go away
+	media, getErr := w.mediaService.GetMedia(ctx, job.MediaID)
+	if getErr != nil || media.Status != domain.StatusProcessing {
+		return
+	}
+
+	if job.Attempt+1 >= w.options.MaxAttempts {
+		if err := w.mediaService.FailProcessing(ctx, job.MediaID, err); err != nil {
+			log.Printf("Failed to mark media %s as failed: %v", job.MediaID, err)
+		}
+		return
+	}
+	w.retry(ctx, job)
+}
+
+// retry queues the job again once its backoff delay has passed
+func (w *ProcessingWorker) retry(ctx context.Context, job *ProcessingJob) {
+	next := *job
+	next.Attempt++
+	delay := w.retryDelay(next.Attempt)
+	log.Printf("Retrying media %s in %s", job.MediaID, delay)
+
+	w.retries.Add(1)
+	go func() {
+		defer w.retries.Done()
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		next.EnqueuedAt = time.Now()
+		err := w.enqueue(ctx, &next)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		// Media nobody is going to process must not stay in processing
+		log.Printf("Failed to queue retry of media %s: %v", next.MediaID, err)
+		if err := w.mediaService.FailProcessing(ctx, next.MediaID, err); err != nil {
+			log.Printf("Failed to mark media %s as failed: %v", next.MediaID, err)
+		}
+	}()
+}
+
+// retryDelay returns the wait before the given retry, doubling from RetryDelay up to MaxRetryDelay
+func (w *ProcessingWorker) retryDelay(attempt int) time.Duration {
+	delay := w.options.RetryDelay
+	for i := 1; i < attempt && delay < w.options.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, w.options.MaxRetryDelay)
+}
+
+// Resume queues the media left in processing state, e.g. by a restart. It
+// waits for room in the queue, so it must run while the worker does, and on
+// a single replica: the scheduler runs it on the leader.
+func (w *ProcessingWorker) Resume(ctx context.Context) {
+	filter := &domain.MediaFilter{Status: domain.StatusProcessing}
+	var jobs []*ProcessingJob
+	for offset := 0; ; offset += processingResumePageSize {
+		media, total, err := w.mediaService.GetFilteredMedia(ctx, filter, processingResumePageSize, offset)
+		if err != nil {
+			log.Printf("Failed to list media in processing: %v", err)
+			break
+		}
+		for _, item := range media {
+			jobs = append(jobs, NewProcessingJob(item))
+		}
+		if len(media) == 0 || int64(offset+len(media)) >= total {
+			break
+		}
+	}
+
+	for _, job := range jobs {
+		if err := w.enqueue(ctx, job); err != nil {
+			log.Printf("Failed to resume processing of media %s: %v", job.MediaID, err)
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+	if len(jobs) > 0 {
+		log.Printf("Resumed processing of %d media items", len(jobs))
+	}
+}
+
+// warmThumbnail renders the default thumbnail of processed media so the first
+// viewer does not wait for it
+func (w *ProcessingWorker) warmThumbnail(ctx context.Context, mediaID string) {
+	if w.options.Thumbnails == nil {
+		return
+	}
+
+	spec := domain.ThumbnailSpec{Width: domain.DefaultThumbnailWidth, Format: domain.ThumbnailFormatJPEG}
+	asset, err := w.options.Thumbnails.GetThumbnail(ctx, mediaID, domain.Viewer{IsAdmin: true, Role: domain.RoleAdmin}, spec)
+	if err != nil {
+		if !errors.Is(err, domain.ErrArtworkNotFound) {
+			log.Printf("Failed to generate thumbnail of media %s: %v", mediaID, err)
+		}
+		return
+	}
+	asset.Body.Close()
+}
+
+// transcode produces the renditions of processed media. Failures are only
+// logged: the media plays from its upload and the renditions report their state.
+func (w *ProcessingWorker) transcode(ctx context.Context, mediaID string) {
+	if w.options.Transcoder == nil {
+		return
+	}
+
+	if err := w.options.Transcoder.Transcode(ctx, mediaID); err != nil {
+		log.Printf("Failed to transcode media %s: %v", mediaID, err)
+	}
+}
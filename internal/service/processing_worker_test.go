@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/messagequeue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProcessingMediaService processes media with scripted errors
+type fakeProcessingMediaService struct {
+	MediaService
+
+	mu       sync.Mutex
+	media    map[string]*domain.Media
+	errs     []error // returned by the next ProcessMedia calls, nil once used up
+	fatal    bool    // ProcessMedia marks media failed itself, as for unreadable files
+	attempts int
+	failed   []string
+}
+
+func (f *fakeProcessingMediaService) ProcessMedia(ctx context.Context, mediaID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		if f.fatal {
+			f.media[mediaID].Status = domain.StatusFailed
+		}
+		return err
+	}
+	f.media[mediaID].Status = domain.StatusReady
+	return nil
+}
+
+func (f *fakeProcessingMediaService) GetMedia(ctx context.Context, mediaID string) (*domain.Media, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	media, ok := f.media[mediaID]
+	if !ok {
+		return nil, domain.ErrMediaNotFound
+	}
+	stored := *media
+	return &stored, nil
+}
+
+func (f *fakeProcessingMediaService) GetFilteredMedia(ctx context.Context, filter *domain.MediaFilter, limit, offset int) ([]*domain.Media, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var matched []*domain.Media
+	for _, media := range f.media {
+		if media.Status == filter.Status {
+			stored := *media
+			matched = append(matched, &stored)
+		}
+	}
+	total := int64(len(matched))
+	if offset >= len(matched) {
+		return nil, total, nil
+	}
+	return matched[offset:min(offset+limit, len(matched))], total, nil
+}
+
+func (f *fakeProcessingMediaService) FailProcessing(ctx context.Context, mediaID string, cause error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.media[mediaID].Status = domain.StatusFailed
+	f.failed = append(f.failed, mediaID)
+	return nil
+}
+
+func (f *fakeProcessingMediaService) state() (attempts int, failed []string, status domain.MediaStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts, f.failed, f.media["episode"].Status
+}
+
+// runProcessingWorker runs the worker until done reports true
+func runProcessingWorker(t *testing.T, worker *ProcessingWorker, done func() bool) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(stopped)
+	}()
+
+	require.Eventually(t, done, time.Second, 5*time.Millisecond)
+	cancel()
+	<-stopped
+}
+
+func TestProcessingWorker_Retries(t *testing.T) {
+	storageDown := errors.New("storage unavailable")
+	tests := []struct {
+		name             string
+		errs             []error
+		fatal            bool
+		expectedAttempts int
+		expectedFailed   []string
+		expectedStatus   domain.MediaStatus
+	}{
+		{
+			name:             "transient failure is retried",
+			errs:             []error{storageDown},
+			expectedAttempts: 2,
+			expectedStatus:   domain.StatusReady,
+		},
+		{
+			name:             "media fails once the attempts run out",
+			errs:             []error{storageDown, storageDown, storageDown},
+			expectedAttempts: 3,
+			expectedFailed:   []string{"episode"},
+			expectedStatus:   domain.StatusFailed,
+		},
+		{
+			name:             "media failed by processing is not retried",
+			errs:             []error{errors.New("unreadable file")},
+			fatal:            true,
+			expectedAttempts: 1,
+			expectedStatus:   domain.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given an upload waiting for processing
+			media := &domain.Media{ID: "episode", Status: domain.StatusProcessing}
+			mediaService := &fakeProcessingMediaService{
+				media: map[string]*domain.Media{media.ID: media},
+				errs:  tt.errs,
+				fatal: tt.fatal,
+			}
+			queue := NewPriorityProcessingQueue(10, 0)
+			require.NoError(t, queue.Enqueue(context.Background(), NewProcessingJob(media)))
+			worker := NewProcessingWorker(queue, mediaService, ProcessingWorkerOptions{
+				MaxAttempts: 3,
+				RetryDelay:  time.Millisecond,
+			})
+
+			// When the worker runs until the media settles
+			runProcessingWorker(t, worker, func() bool {
+				_, _, status := mediaService.state()
+				return status != domain.StatusProcessing
+			})
+
+			// Then
+			attempts, failed, status := mediaService.state()
+			assert.Equal(t, tt.expectedAttempts, attempts)
+			assert.Equal(t, tt.expectedFailed, failed)
+			assert.Equal(t, tt.expectedStatus, status)
+		})
+	}
+}
+
+func TestProcessingWorker_ResumesMediaInProcessing(t *testing.T) {
+	// Given media left in processing by a restart, more than the queue holds
+	media := map[string]*domain.Media{
+		"ready": {ID: "ready", Status: domain.StatusReady},
+	}
+	for _, id := range []string{"episode", "episode-2", "episode-3", "episode-4"} {
+		media[id] = &domain.Media{ID: id, Status: domain.StatusProcessing}
+	}
+	mediaService := &fakeProcessingMediaService{media: media}
+	worker := NewProcessingWorker(NewPriorityProcessingQueue(1, 0), mediaService, ProcessingWorkerOptions{})
+
+	// When the leader resumes processing while the worker runs
+	go worker.Resume(context.Background())
+	runProcessingWorker(t, worker, func() bool {
+		attempts, _, _ := mediaService.state()
+		return attempts == 4
+	})
+
+	// Then only the media in processing is processed, all of it
+	for id, item := range media {
+		assert.Equal(t, domain.StatusReady, item.Status, id)
+	}
+}
+
+func TestProcessingWorker_ProcessesUploadEvents(t *testing.T) {
+	// Given an upload confirmed on the event queue
+	mediaService := &fakeProcessingMediaService{media: map[string]*domain.Media{
+		"episode": {ID: "episode", Status: domain.StatusProcessing},
+	}}
+	events := messagequeue.NewInMemoryQueue()
+	worker := NewProcessingWorker(NewPriorityProcessingQueue(10, 0), mediaService, ProcessingWorkerOptions{Events: events})
+	publisher := NewQueueEventPublisher(events)
+
+	// When the worker consumes the media.uploaded event
+	runProcessingWorker(t, worker, func() bool {
+		// The subscription starts with the worker
+		require.NoError(t, publisher.Publish(context.Background(), mediaEvent(domain.EventMediaUploaded, "episode")))
+		_, _, status := mediaService.state()
+		return status == domain.StatusReady
+	})
+
+	// Then the media is processed, and a redelivered event leaves it alone
+	require.NoError(t, worker.handleUploaded(context.Background(), []byte(`{"type":"media.uploaded","data":{"media_id":"episode"}}`)))
+	_, _, status := mediaService.state()
+	assert.Equal(t, domain.StatusReady, status)
+}
+
+// fullProcessingQueue refuses every job
+type fullProcessingQueue struct {
+	ProcessingQueue
+}
+
+func (fullProcessingQueue) Enqueue(ctx context.Context, job *ProcessingJob) error {
+	return errors.New("queue closed")
+}
+
+func TestProcessingWorker_FailsMediaWhenRetryCannotBeQueued(t *testing.T) {
+	// Given a transient failure and a queue refusing the retry
+	media := &domain.Media{ID: "episode", Status: domain.StatusProcessing}
+	mediaService := &fakeProcessingMediaService{
+		media: map[string]*domain.Media{media.ID: media},
+		errs:  []error{errors.New("storage unavailable")},
+	}
+	worker := NewProcessingWorker(fullProcessingQueue{}, mediaService, ProcessingWorkerOptions{RetryDelay: time.Millisecond})
+
+	// When the job is handled
+	worker.handle(context.Background(), NewProcessingJob(media))
+	worker.retries.Wait()
+
+	// Then the media does not stay in processing
+	_, failed, status := mediaService.state()
+	assert.Equal(t, []string{"episode"}, failed)
+	assert.Equal(t, domain.StatusFailed, status)
+}
+
+func TestProcessingWorker_RetryDelay(t *testing.T) {
+	worker := NewProcessingWorker(nil, nil, ProcessingWorkerOptions{
+		RetryDelay:    10 * time.Second,
+		MaxRetryDelay: time.Minute,
+	})
+
+	assert.Equal(t, 10*time.Second, worker.retryDelay(1))
+	assert.Equal(t, 20*time.Second, worker.retryDelay(2))
+	assert.Equal(t, 40*time.Second, worker.retryDelay(3))
+	assert.Equal(t, time.Minute, worker.retryDelay(4))
+	assert.Equal(t, time.Minute, worker.retryDelay(10))
+}
+
+func TestProcessingWorker_PausesDuringMaintenance(t *testing.T) {
+	// Given an upload queued while the service is read-only
+	media := &domain.Media{ID: "episode", Status: domain.StatusProcessing}
+	mediaService := &fakeProcessingMediaService{media: map[string]*domain.Media{media.ID: media}}
+	queue := NewPriorityProcessingQueue(10, 0)
+	require.NoError(t, queue.Enqueue(context.Background(), NewProcessingJob(media)))
+	gate := &fakeMaintenanceGate{readOnly: true}
+	worker := NewProcessingWorker(queue, mediaService, ProcessingWorkerOptions{Maintenance: gate})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	// Then it waits for the maintenance to end
+	time.Sleep(30 * time.Millisecond)
+	attempts, _, _ := mediaService.state()
+	assert.Zero(t, attempts)
+
+	gate.setReadOnly(false)
+	require.Eventually(t, func() bool {
+		_, _, status := mediaService.state()
+		return status == domain.StatusReady
+	}, 3*time.Second, 10*time.Millisecond)
+}
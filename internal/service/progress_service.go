@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// ProgressService records how far users got in media and lists what they can resume
+type ProgressService interface {
+	// RecordProgress stores the playback position of a user in a media item
+	RecordProgress(ctx context.Context, userID, mediaID string, req *domain.ProgressRequest, viewer domain.Viewer) (*domain.PlaybackProgress, error)
+
+	// GetContinueListening lists the unfinished media of a user, most recently played first
+	GetContinueListening(ctx context.Context, userID string, limit int, viewer domain.Viewer) ([]*domain.ContinueItem, error)
+}
+
+// progressService implements ProgressService interface
+type progressService struct {
+	progressRepo repository.ProgressRepository
+	mediaRepo    repository.MediaRepository
+	now          func() time.Time
+}
+
+// NewProgressService creates a new playback progress service
+func NewProgressService(progressRepo repository.ProgressRepository, mediaRepo repository.MediaRepository) ProgressService {
+	return &progressService{
+		progressRepo: progressRepo,
+		mediaRepo:    mediaRepo,
+		now:          time.Now,
+	}
+}
+
+// RecordProgress stores the playback position of a user in a media item
+func (s *progressService) RecordProgress(ctx context.Context, userID, mediaID string, req *domain.ProgressRequest, viewer domain.Viewer) (*domain.PlaybackProgress, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Progress validation failed", errs.Error())
+	}
+
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !media.IsProcessed() {
+		return nil, domain.ErrMediaNotFound
+	}
+
+	progress := req.ToProgress(userID, media, s.now())
+	if err := s.progressRepo.Save(ctx, progress); err != nil {
+		return nil, fmt.Errorf("failed to save progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// GetContinueListening lists the unfinished media of a user, most recently played first.
+// Media that was unpublished or deleted since it was played is skipped.
+func (s *progressService) GetContinueListening(ctx context.Context, userID string, limit int, viewer domain.Viewer) ([]*domain.ContinueItem, error) {
+	progress, err := s.progressRepo.GetInProgress(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+	if len(progress) == 0 {
+		return []*domain.ContinueItem{}, nil
+	}
+
+	ids := make([]string, 0, len(progress))
+	for _, p := range progress {
+		ids = append(ids, p.MediaID)
+	}
+	mediaList, err := s.mediaRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up media: %w", err)
+	}
+	mediaByID := make(map[string]*domain.Media, len(mediaList))
+	for _, media := range mediaList {
+		mediaByID[media.ID] = media
+	}
+
+	items := make([]*domain.ContinueItem, 0, len(progress))
+	for _, p := range progress {
+		media, ok := mediaByID[p.MediaID]
+		if !ok || !media.IsProcessed() || !media.IsVisibleTo(viewer) {
+			continue
+		}
+		items = append(items, &domain.ContinueItem{
+			Media:           media,
+			PositionSeconds: p.PositionSeconds,
+			LastPlayedAt:    p.LastPlayedAt,
+		})
+	}
+
+	return items, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryProgressRepository keeps playback progress in memory
+type memoryProgressRepository struct {
+	progress map[string]domain.PlaybackProgress
+}
+
+func newMemoryProgressRepository() *memoryProgressRepository {
+	return &memoryProgressRepository{progress: make(map[string]domain.PlaybackProgress)}
+}
+
+func (r *memoryProgressRepository) Save(ctx context.Context, progress *domain.PlaybackProgress) error {
+	r.progress[progress.UserID+"/"+progress.MediaID] = *progress
+	return nil
+}
+
+func (r *memoryProgressRepository) GetInProgress(ctx context.Context, userID string, limit int) ([]*domain.PlaybackProgress, error) {
+	var result []*domain.PlaybackProgress
+	for _, p := range r.progress {
+		if p.UserID == userID && !p.Completed {
+			result = append(result, &p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].LastPlayedAt.After(result[j].LastPlayedAt) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *memoryProgressRepository) DeleteByUser(ctx context.Context, userID string) error {
+	for key, p := range r.progress {
+		if p.UserID == userID {
+			delete(r.progress, key)
+		}
+	}
+	return nil
+}
+
+func newTestProgressService(t *testing.T) (*progressService, repository.MediaRepository) {
+	t.Helper()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	for _, media := range []*domain.Media{
+		{ID: "first", Duration: 600, Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+		{ID: "second", Duration: 600, Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+		{ID: "finished", Duration: 600, Status: domain.StatusReady, Visibility: domain.VisibilityPublic},
+		{ID: "private", Duration: 600, Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+		{ID: "processing", Status: domain.StatusProcessing, Visibility: domain.VisibilityPublic},
+	} {
+		require.NoError(t, mediaRepo.Create(context.Background(), media))
+	}
+	s := NewProgressService(newMemoryProgressRepository(), mediaRepo).(*progressService)
+	return s, mediaRepo
+}
+
+func TestProgressService_RecordProgress(t *testing.T) {
+	s, _ := newTestProgressService(t)
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	progress, err := s.RecordProgress(ctx, "u1", "first", &domain.ProgressRequest{PositionSeconds: 580}, domain.Viewer{})
+	require.NoError(t, err)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, now, progress.LastPlayedAt)
+
+	// Media the user cannot see is reported as missing
+	for _, id := range []string{"private", "processing", "missing"} {
+		_, err = s.RecordProgress(ctx, "u1", id, &domain.ProgressRequest{PositionSeconds: 10}, domain.Viewer{})
+		assert.ErrorIs(t, err, domain.ErrMediaNotFound, id)
+	}
+
+	_, err = s.RecordProgress(ctx, "u1", "first", &domain.ProgressRequest{PositionSeconds: -5}, domain.Viewer{})
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_REQUEST", businessErr.Code)
+}
+
+func TestProgressService_GetContinueListening(t *testing.T) {
+	// Given a user who finished one item and started two others
+	s, mediaRepo := newTestProgressService(t)
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, play := range []struct {
+		mediaID  string
+		position int
+	}{
+		{"first", 60},
+		{"finished", 600},
+		{"second", 120},
+	} {
+		now = now.Add(time.Minute)
+		s.now = func() time.Time { return now }
+		_, err := s.RecordProgress(ctx, "u1", play.mediaID, &domain.ProgressRequest{PositionSeconds: play.position}, domain.Viewer{})
+		require.NoError(t, err)
+	}
+
+	// When
+	items, err := s.GetContinueListening(ctx, "u1", 10, domain.Viewer{})
+	require.NoError(t, err)
+
+	// Then the unfinished items are listed with their media, most recently played first
+	require.Len(t, items, 2)
+	assert.Equal(t, "second", items[0].Media.ID)
+	assert.Equal(t, 120, items[0].PositionSeconds)
+	assert.Equal(t, "first", items[1].Media.ID)
+
+	// And media unpublished since it was played is left out
+	first, err := mediaRepo.GetByID(ctx, "first")
+	require.NoError(t, err)
+	first.Visibility = domain.VisibilityPrivate
+	require.NoError(t, mediaRepo.Update(ctx, first))
+	items, err = s.GetContinueListening(ctx, "u1", 10, domain.Viewer{})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "second", items[0].Media.ID)
+
+	// And other users have nothing to continue
+	items, err = s.GetContinueListening(ctx, "u2", 10, domain.Viewer{})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
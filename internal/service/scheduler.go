@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// LeaderLock is held by at most one replica at a time
+type LeaderLock interface {
+	// TryAcquire takes the lock without waiting, or confirms it is still held
+	TryAcquire(ctx context.Context) (bool, error)
+
+	// Release gives up the lock if it is held
+	Release(ctx context.Context) error
+}
+
+// MaintenanceGate reports whether writes are suspended for maintenance
+type MaintenanceGate interface {
+	ReadOnly() bool
+}
+
+// scheduledJob is a background job that must run on a single replica
+type scheduledJob struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// Scheduler runs singleton background jobs on the replica that holds the
+// leader lock. Other replicas stand by and take over when the leader goes away.
+type Scheduler struct {
+	lock          LeaderLock
+	checkInterval time.Duration
+	maintenance   MaintenanceGate
+	jobs          []scheduledJob
+}
+
+// NewScheduler creates a new scheduler. Without a lock every replica runs the
+// jobs, which suits single replica deployments.
+func NewScheduler(lock LeaderLock, checkInterval time.Duration) *Scheduler {
+	if checkInterval <= 0 {
+		checkInterval = 15 * time.Second
+	}
+
+	return &Scheduler{
+		lock:          lock,
+		checkInterval: checkInterval,
+	}
+}
+
+// PauseDuringMaintenance stops the jobs while gate is read-only and starts
+// them again once it is not, checked every check interval
+func (s *Scheduler) PauseDuringMaintenance(gate MaintenanceGate) {
+	s.maintenance = gate
+}
+
+// Add registers a job. Jobs run until the context they are given is cancelled,
+// which happens when leadership is lost or the scheduler stops.
+func (s *Scheduler) Add(name string, run func(ctx context.Context)) {
+	s.jobs = append(s.jobs, scheduledJob{name: name, run: run})
+}
+
+// Run campaigns for leadership and runs the jobs while leading, until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	if s.lock == nil && s.maintenance == nil {
+		s.runJobs(ctx).Wait()
+		return
+	}
+
+	var stopJobs func()
+	stepDown := func() {
+		if stopJobs != nil {
+			stopJobs()
+			stopJobs = nil
+		}
+	}
+
+	for {
+		leading := true
+		if s.lock != nil {
+			acquired, err := s.lock.TryAcquire(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Leader election failed: %v", err)
+			}
+			leading = acquired
+		}
+		paused := s.maintenance != nil && s.maintenance.ReadOnly()
+
+		switch {
+		case leading && !paused && stopJobs == nil:
+			log.Printf("Starting %d scheduled jobs", len(s.jobs))
+			stopJobs = s.startJobs(ctx)
+		case !leading && stopJobs != nil:
+			log.Printf("Lost scheduler leadership, stopping jobs")
+			stepDown()
+		case paused && stopJobs != nil:
+			log.Printf("Read-only maintenance mode, stopping jobs")
+			stepDown()
+		}
+
+		select {
+		case <-ctx.Done():
+			stepDown()
+			if s.lock == nil {
+				return
+			}
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := s.lock.Release(releaseCtx); err != nil {
+				log.Printf("Failed to release scheduler leadership: %v", err)
+			}
+			cancel()
+			return
+		case <-time.After(s.checkInterval):
+		}
+	}
+}
+
+// startJobs runs the jobs and returns a function stopping them and waiting for them to return
+func (s *Scheduler) startJobs(ctx context.Context) func() {
+	jobsCtx, cancel := context.WithCancel(ctx)
+	running := s.runJobs(jobsCtx)
+	return func() {
+		cancel()
+		running.Wait()
+	}
+}
+
+// runJobs starts every job and returns a wait group done once they all returned
+func (s *Scheduler) runJobs(ctx context.Context) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job scheduledJob) {
+			defer wg.Done()
+			job.run(ctx)
+		}(job)
+	}
+	return &wg
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLeaderLock grants leadership while held is set
+type fakeLeaderLock struct {
+	mu       sync.Mutex
+	held     bool
+	released bool
+}
+
+func (l *fakeLeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held, nil
+}
+
+func (l *fakeLeaderLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released = true
+	return nil
+}
+
+func (l *fakeLeaderLock) setHeld(held bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held = held
+}
+
+func TestScheduler_Run(t *testing.T) {
+	lock := &fakeLeaderLock{}
+	scheduler := NewScheduler(lock, 5*time.Millisecond)
+
+	started := make(chan struct{}, 2)
+	stopped := make(chan struct{}, 2)
+	scheduler.Add("job", func(ctx context.Context) {
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+
+	// Standby replicas do not run jobs
+	select {
+	case <-started:
+		t.Fatal("job started without leadership")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	lock.setHeld(true)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("job did not start after acquiring leadership")
+	}
+
+	lock.setHeld(false)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after losing leadership")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+	assert.True(t, lock.released)
+}
+
+func TestScheduler_Run_WithoutLock(t *testing.T) {
+	scheduler := NewScheduler(nil, time.Millisecond)
+
+	ran := make(chan struct{}, 1)
+	scheduler.Add("job", func(ctx context.Context) {
+		ran <- struct{}{}
+	})
+
+	scheduler.Run(context.Background())
+	require.Len(t, ran, 1)
+}
+
+// fakeMaintenanceGate is read-only while readOnly is set
+type fakeMaintenanceGate struct {
+	mu       sync.Mutex
+	readOnly bool
+}
+
+func (g *fakeMaintenanceGate) ReadOnly() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.readOnly
+}
+
+func (g *fakeMaintenanceGate) setReadOnly(readOnly bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.readOnly = readOnly
+}
+
+func TestScheduler_Run_PausesDuringMaintenance(t *testing.T) {
+	gate := &fakeMaintenanceGate{readOnly: true}
+	scheduler := NewScheduler(nil, 5*time.Millisecond)
+	scheduler.PauseDuringMaintenance(gate)
+
+	started := make(chan struct{}, 2)
+	stopped := make(chan struct{}, 2)
+	scheduler.Add("job", func(ctx context.Context) {
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Run(ctx)
+
+	// Jobs wait for the maintenance to end
+	select {
+	case <-started:
+		t.Fatal("job started during maintenance")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	gate.setReadOnly(false)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("job did not start after the maintenance")
+	}
+
+	gate.setReadOnly(true)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop for the maintenance")
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// maxDriftIDs caps the media IDs listed per kind of drift in a report
+const maxDriftIDs = 100
+
+// SearchConsistencyOptions configures the periodic consistency check
+type SearchConsistencyOptions struct {
+	Interval  time.Duration // wait between checks
+	AutoHeal  bool          // repair the drift found by periodic checks
+	BatchSize int           // media read from the database per query
+}
+
+// SearchConsistencyReport describes the drift between the database and the search index
+type SearchConsistencyReport struct {
+	CheckedAt  time.Time `json:"checked_at"`
+	Searchable int       `json:"searchable"` // searchable media in the database
+	Indexed    int       `json:"indexed"`    // documents in the search index
+	Missing    int       `json:"missing"`    // searchable media without a document
+	Stale      int       `json:"stale"`      // documents built from another version of their media
+	Orphaned   int       `json:"orphaned"`   // documents of media that is gone or no longer searchable
+
+	// Up to maxDriftIDs media IDs per kind of drift
+	MissingIDs  []string `json:"missing_ids,omitempty"`
+	StaleIDs    []string `json:"stale_ids,omitempty"`
+	OrphanedIDs []string `json:"orphaned_ids,omitempty"`
+
+	// Repairs made when healing
+	Healed    bool `json:"healed"`
+	Reindexed int  `json:"reindexed"`
+	Removed   int  `json:"removed"`
+	Failed    int  `json:"failed"`
+}
+
+// HasDrift returns true if the index does not match the database
+func (r *SearchConsistencyReport) HasDrift() bool {
+	return r.Missing > 0 || r.Stale > 0 || r.Orphaned > 0
+}
+
+// SearchConsistencyChecker compares the search index with the media in the database
+type SearchConsistencyChecker interface {
+	// Check reports the drift between the database and the search index. With
+	// heal, missing and stale documents are reindexed and orphans removed.
+	Check(ctx context.Context, heal bool) (*SearchConsistencyReport, error)
+
+	// Run checks periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// searchConsistencyChecker implements SearchConsistencyChecker interface
+type searchConsistencyChecker struct {
+	mediaRepo  repository.MediaRepository
+	searchRepo repository.SearchRepository
+	contexts   *SearchContextResolver
+	options    SearchConsistencyOptions
+	now        func() time.Time
+}
+
+// NewSearchConsistencyChecker creates a new consistency checker. Reindexed
+// media gets its show context from contexts, which may be nil.
+func NewSearchConsistencyChecker(mediaRepo repository.MediaRepository, searchRepo repository.SearchRepository, contexts *SearchContextResolver, options SearchConsistencyOptions) SearchConsistencyChecker {
+	if options.Interval <= 0 {
+		options.Interval = time.Hour
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 500
+	}
+
+	return &searchConsistencyChecker{
+		mediaRepo:  mediaRepo,
+		searchRepo: searchRepo,
+		contexts:   contexts,
+		options:    options,
+		now:        time.Now,
+	}
+}
+
+// Check reports the drift between the database and the search index
+func (c *searchConsistencyChecker) Check(ctx context.Context, heal bool) (*SearchConsistencyReport, error) {
+	report := &SearchConsistencyReport{CheckedAt: c.now()}
+
+	// The index is listed first: media indexed in between shows up as missing
+	// and is reindexed at worst, never removed
+	indexed, err := c.searchRepo.ListIndexed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mediaList, err := c.searchableMedia(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Searchable = len(mediaList)
+	report.Indexed = len(indexed)
+
+	searchable := make(map[string]*domain.Media, len(mediaList))
+	for _, media := range mediaList {
+		searchable[media.ID] = media
+	}
+
+	var outdated []*domain.Media
+	var orphaned []string
+	documents := make(map[string]*domain.IndexedMedia, len(indexed))
+	for _, document := range indexed {
+		documents[document.MediaID] = document
+		media, ok := searchable[document.MediaID]
+		switch {
+		case !ok:
+			report.Orphaned++
+			report.OrphanedIDs = appendDriftID(report.OrphanedIDs, document.MediaID)
+			orphaned = append(orphaned, document.MediaID)
+		case document.IsStaleFor(media):
+			report.Stale++
+			report.StaleIDs = appendDriftID(report.StaleIDs, media.ID)
+			outdated = append(outdated, media)
+		}
+	}
+	for _, media := range mediaList {
+		if _, ok := documents[media.ID]; !ok {
+			report.Missing++
+			report.MissingIDs = appendDriftID(report.MissingIDs, media.ID)
+			outdated = append(outdated, media)
+		}
+	}
+
+	if heal {
+		c.heal(ctx, report, outdated, orphaned)
+	}
+
+	return report, nil
+}
+
+// Run checks periodically until ctx is cancelled
+func (c *searchConsistencyChecker) Run(ctx context.Context) {
+	for {
+		report, err := c.Check(ctx, c.options.AutoHeal)
+		if err != nil {
+			log.Printf("Search consistency check failed: %v", err)
+		} else if report.HasDrift() {
+			log.Printf("Search index drift: %d missing, %d stale, %d orphaned; reindexed %d, removed %d, failed %d",
+				report.Missing, report.Stale, report.Orphaned, report.Reindexed, report.Removed, report.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.options.Interval):
+		}
+	}
+}
+
+// searchableMedia loads the media that belongs in the search index
+func (c *searchConsistencyChecker) searchableMedia(ctx context.Context) ([]*domain.Media, error) {
+	var searchable []*domain.Media
+	filter := &domain.MediaFilter{Status: domain.StatusReady}
+	for offset := 0; ; offset += c.options.BatchSize {
+		batch, err := c.mediaRepo.GetByFilter(ctx, filter, c.options.BatchSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load media: %w", err)
+		}
+		for _, media := range batch {
+			if media.CanBeSearched() {
+				searchable = append(searchable, media)
+			}
+		}
+		if len(batch) < c.options.BatchSize {
+			return searchable, nil
+		}
+	}
+}
+
+// heal reindexes outdated media and removes orphaned documents. One failing
+// item must not keep the others from being repaired.
+func (c *searchConsistencyChecker) heal(ctx context.Context, report *SearchConsistencyReport, outdated []*domain.Media, orphaned []string) {
+	report.Healed = true
+
+	c.contexts.Resolve(ctx, outdated)
+	for _, media := range outdated {
+		if err := c.searchRepo.IndexMedia(ctx, media); err != nil {
+			log.Printf("Failed to reindex media %s: %v", media.ID, err)
+			report.Failed++
+			continue
+		}
+		report.Reindexed++
+	}
+
+	for _, mediaID := range orphaned {
+		// Listings page by offset, media created during the check may have
+		// been skipped; it is looked up again before its document goes
+		media, err := c.mediaRepo.GetByID(ctx, mediaID)
+		if err == nil && media.CanBeSearched() {
+			continue
+		}
+		if err != nil && !errors.Is(err, domain.ErrMediaNotFound) {
+			log.Printf("Failed to look up orphaned media %s: %v", mediaID, err)
+			report.Failed++
+			continue
+		}
+		if err := c.searchRepo.RemoveFromIndex(ctx, mediaID); err != nil {
+			log.Printf("Failed to remove orphaned media %s from the index: %v", mediaID, err)
+			report.Failed++
+			continue
+		}
+		report.Removed++
+	}
+}
+
+// appendDriftID appends a media ID to a drift list, up to maxDriftIDs
+func appendDriftID(ids []string, mediaID string) []string {
+	if len(ids) >= maxDriftIDs {
+		return ids
+	}
+	return append(ids, mediaID)
+}
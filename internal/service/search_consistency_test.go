@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchConsistencyChecker_Check(t *testing.T) {
+	// Given an index missing one episode, holding an older version of another,
+	// and a document of media that has been made private since
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	searchRepo := repository.NewInMemorySearchRepository()
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, media := range []*domain.Media{
+		{ID: "m1", Title: "Indexed", Status: domain.StatusReady, Visibility: domain.VisibilityPublic, UpdatedAt: updatedAt},
+		{ID: "m2", Title: "Missing", Status: domain.StatusReady, Visibility: domain.VisibilityPublic, UpdatedAt: updatedAt},
+		{ID: "m3", Title: "Edited", Status: domain.StatusReady, Visibility: domain.VisibilityPublic, UpdatedAt: updatedAt.Add(time.Hour)},
+		{ID: "m4", Title: "Private", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate, UpdatedAt: updatedAt},
+	} {
+		require.NoError(t, mediaRepo.Create(ctx, media))
+	}
+	for _, media := range []*domain.Media{
+		{ID: "m1", Title: "Indexed", UpdatedAt: updatedAt},
+		{ID: "m3", Title: "Before edit", UpdatedAt: updatedAt},
+		{ID: "m4", Title: "Private", UpdatedAt: updatedAt},
+		{ID: "m5", Title: "Purged", UpdatedAt: updatedAt},
+	} {
+		require.NoError(t, searchRepo.IndexMedia(ctx, media))
+	}
+	checker := NewSearchConsistencyChecker(mediaRepo, searchRepo, nil, SearchConsistencyOptions{BatchSize: 2})
+
+	// When checking without healing
+	report, err := checker.Check(ctx, false)
+
+	// Then the drift is reported and the index is left alone
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Searchable)
+	assert.Equal(t, 4, report.Indexed)
+	assert.Equal(t, []string{"m2"}, report.MissingIDs)
+	assert.Equal(t, []string{"m3"}, report.StaleIDs)
+	assert.Equal(t, []string{"m4", "m5"}, report.OrphanedIDs)
+	assert.False(t, report.Healed)
+	indexed, err := searchRepo.ListIndexed(ctx)
+	require.NoError(t, err)
+	assert.Len(t, indexed, 4)
+
+	// When healing
+	report, err = checker.Check(ctx, true)
+
+	// Then missing and stale media is reindexed and orphans are removed
+	require.NoError(t, err)
+	assert.True(t, report.Healed)
+	assert.Equal(t, 2, report.Reindexed)
+	assert.Equal(t, 2, report.Removed)
+	assert.Zero(t, report.Failed)
+
+	report, err = checker.Check(ctx, false)
+	require.NoError(t, err)
+	assert.False(t, report.HasDrift())
+	results, _, err := searchRepo.Search(ctx, &domain.SearchRequest{Query: "edited"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "m3", results[0].Media.ID)
+}
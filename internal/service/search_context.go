@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// searchContextBatchSize bounds the media whose context is looked up in one query
+const searchContextBatchSize = 500
+
+// SearchContextResolver sets the search context of media about to be indexed
+// from the templates of their shows and the people credited on them
+type SearchContextResolver struct {
+	showTemplates repository.ShowTemplateRepository
+	people        repository.PersonRepository
+}
+
+// NewSearchContextResolver creates a search context resolver. Either repository
+// may be nil, media is then indexed without show templates or people.
+func NewSearchContextResolver(showTemplates repository.ShowTemplateRepository, people repository.PersonRepository) *SearchContextResolver {
+	return &SearchContextResolver{
+		showTemplates: showTemplates,
+		people:        people,
+	}
+}
+
+// Resolve sets the search context of media. A nil resolver, or templates and
+// people that cannot be read, leave media with its own category and artist only.
+func (r *SearchContextResolver) Resolve(ctx context.Context, mediaList []*domain.Media) {
+	for start := 0; start < len(mediaList); start += searchContextBatchSize {
+		end := min(start+searchContextBatchSize, len(mediaList))
+		r.resolveBatch(ctx, mediaList[start:end])
+	}
+}
+
+// resolveBatch sets the search context of a batch of media
+func (r *SearchContextResolver) resolveBatch(ctx context.Context, mediaList []*domain.Media) {
+	var shows map[string]*domain.ShowTemplate
+	var mediaCredits, showCredits map[string][]domain.Credit
+	if r != nil {
+		shows = r.resolveShows(ctx, mediaList)
+		mediaCredits, showCredits = r.resolveCredits(ctx, mediaList)
+	}
+
+	for _, media := range mediaList {
+		credits := append(append([]domain.Credit(nil), mediaCredits[media.ID]...), showCredits[media.ShowID]...)
+		media.SearchContext = domain.NewSearchContext(media, shows[media.ShowID], credits)
+	}
+}
+
+// resolveShows loads the templates of the shows of media
+func (r *SearchContextResolver) resolveShows(ctx context.Context, mediaList []*domain.Media) map[string]*domain.ShowTemplate {
+	shows := make(map[string]*domain.ShowTemplate)
+	showIDs := showIDsOf(mediaList)
+	if r.showTemplates == nil || len(showIDs) == 0 {
+		return shows
+	}
+
+	templates, err := r.showTemplates.GetByShowIDs(ctx, showIDs)
+	if err != nil {
+		log.Printf("Failed to load shows for search indexing, indexing without them: %v", err)
+	}
+	for _, template := range templates {
+		shows[template.ShowID] = template
+	}
+	return shows
+}
+
+// resolveCredits loads the people credited on media and on their shows, keyed by media and show ID
+func (r *SearchContextResolver) resolveCredits(ctx context.Context, mediaList []*domain.Media) (map[string][]domain.Credit, map[string][]domain.Credit) {
+	if r.people == nil {
+		return nil, nil
+	}
+
+	mediaIDs := make([]string, len(mediaList))
+	for i, media := range mediaList {
+		mediaIDs[i] = media.ID
+	}
+	credits, err := r.people.GetCredits(ctx, domain.CreditTargetMedia, mediaIDs)
+	if err == nil {
+		var showCredits []*domain.PersonCredit
+		if showCredits, err = r.people.GetCredits(ctx, domain.CreditTargetShow, showIDsOf(mediaList)); err == nil {
+			credits = append(credits, showCredits...)
+		}
+	}
+	var people []*domain.Person
+	if err == nil {
+		people, err = r.people.GetByIDs(ctx, creditedPersonIDs(credits))
+	}
+	if err != nil {
+		log.Printf("Failed to load people for search indexing, indexing without them: %v", err)
+		return nil, nil
+	}
+
+	peopleByID := make(map[string]*domain.Person, len(people))
+	for _, person := range people {
+		peopleByID[person.ID] = person
+	}
+	byMedia := make(map[string][]domain.Credit)
+	byShow := make(map[string][]domain.Credit)
+	for _, credit := range credits {
+		person, ok := peopleByID[credit.PersonID]
+		if !ok {
+			continue
+		}
+		resolved := domain.Credit{Person: person, Role: credit.Role}
+		if credit.TargetType == domain.CreditTargetShow {
+			byShow[credit.TargetID] = append(byShow[credit.TargetID], resolved)
+		} else {
+			byMedia[credit.TargetID] = append(byMedia[credit.TargetID], resolved)
+		}
+	}
+	return byMedia, byShow
+}
+
+// showIDsOf returns the distinct shows of media
+func showIDsOf(mediaList []*domain.Media) []string {
+	var showIDs []string
+	seen := make(map[string]bool)
+	for _, media := range mediaList {
+		if media.ShowID != "" && !seen[media.ShowID] {
+			seen[media.ShowID] = true
+			showIDs = append(showIDs, media.ShowID)
+		}
+	}
+	return showIDs
+}
+
+// creditedPersonIDs returns the distinct people of credits
+func creditedPersonIDs(credits []*domain.PersonCredit) []string {
+	var personIDs []string
+	seen := make(map[string]bool)
+	for _, credit := range credits {
+		if !seen[credit.PersonID] {
+			seen[credit.PersonID] = true
+			personIDs = append(personIDs, credit.PersonID)
+		}
+	}
+	return personIDs
+}
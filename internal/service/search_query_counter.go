@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// SearchQueryCounter counts the searches of tenants per day for their digests
+type SearchQueryCounter interface {
+	// RecordSearch counts a search of the query by the tenant today
+	RecordSearch(ctx context.Context, tenantID, query string) error
+}
+
+// searchQueryCounter implements SearchQueryCounter interface
+type searchQueryCounter struct {
+	searchQueryRepo repository.SearchQueryRepository
+	now             func() time.Time
+}
+
+// NewSearchQueryCounter creates a new search query counter
+func NewSearchQueryCounter(searchQueryRepo repository.SearchQueryRepository) SearchQueryCounter {
+	return &searchQueryCounter{
+		searchQueryRepo: searchQueryRepo,
+		now:             time.Now,
+	}
+}
+
+// RecordSearch counts a search of the query, folded into its normalized term, by the tenant today
+func (c *searchQueryCounter) RecordSearch(ctx context.Context, tenantID, query string) error {
+	term := domain.NormalizeSearchTerm(query)
+	if term == "" {
+		return nil
+	}
+	return c.searchQueryRepo.Increment(ctx, c.now().UTC().Format(domain.CalendarDateLayout), tenantID, term)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSearchQueryRepository records the searches it counts
+type recordingSearchQueryRepository struct {
+	stubSearchQueryRepository
+	counted []string
+}
+
+func (r *recordingSearchQueryRepository) Increment(ctx context.Context, day, tenantID, term string) error {
+	r.counted = append(r.counted, day+"/"+tenantID+"/"+term)
+	return nil
+}
+
+func TestSearchQueryCounter_RecordSearch(t *testing.T) {
+	// Given
+	repo := &recordingSearchQueryRepository{}
+	counter := NewSearchQueryCounter(repo).(*searchQueryCounter)
+	counter.now = func() time.Time { return time.Date(2025, 6, 2, 23, 30, 0, 0, time.FixedZone("AST", 3*3600)) }
+
+	// When
+	require.NoError(t, counter.RecordSearch(context.Background(), "acme", "  Jazz   Standards "))
+	require.NoError(t, counter.RecordSearch(context.Background(), "acme", "   "))
+
+	// Then searches are counted on their UTC day by normalized term, blank ones are not
+	assert.Equal(t, []string{"2025-06-02/acme/jazz standards"}, repo.counted)
+}
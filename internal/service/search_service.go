@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"time"
 
 	"thamaniyah/internal/domain"
 	"thamaniyah/internal/repository"
 	"thamaniyah/pkg/httpclient"
+
+	"github.com/google/uuid"
 )
 
 // SearchService defines search operations
@@ -18,21 +22,80 @@ type SearchService interface {
 	// Suggest provides search suggestions
 	Suggest(ctx context.Context, req *domain.SuggestRequest) (*domain.SuggestResponse, error)
 
-	// Reindex rebuilds the search index by fetching data from CMS service
-	Reindex(ctx context.Context) error
+	// Reindex rebuilds the search index by fetching data from CMS service.
+	// Each invocation by actor is audited, and a CooldownError is returned
+	// when the previous one started less than the cooldown window ago.
+	Reindex(ctx context.Context, actor domain.AuditActor) error
+}
+
+// SearchQueryParser turns the query string of searches into the structured
+// query the repositories search
+type SearchQueryParser interface {
+	Parse(query string) (*domain.SearchQuery, error)
 }
 
+// SearchQueryParserFunc adapts a function to SearchQueryParser
+type SearchQueryParserFunc func(query string) (*domain.SearchQuery, error)
+
+// Parse calls f(query)
+func (f SearchQueryParserFunc) Parse(query string) (*domain.SearchQuery, error) {
+	return f(query)
+}
+
+var (
+	// AdvancedSearchQueryParser supports "quoted phrases", tag: and type:
+	// filters, -exclusions and OR, see domain.ParseSearchQuery
+	AdvancedSearchQueryParser SearchQueryParser = SearchQueryParserFunc(domain.ParseSearchQuery)
+
+	// PlainSearchQueryParser matches media containing every word of the query, taken literally
+	PlainSearchQueryParser SearchQueryParser = SearchQueryParserFunc(func(query string) (*domain.SearchQuery, error) {
+		return domain.PlainSearchQuery(query), nil
+	})
+)
+
 // SearchServiceImpl implements SearchService
 type SearchServiceImpl struct {
-	searchRepo repository.SearchRepository
-	cmsClient  *httpclient.Client
+	searchRepo      repository.SearchRepository
+	parser          SearchQueryParser
+	cmsClient       *httpclient.Client
+	catalog         MediaCatalog
+	contexts        *SearchContextResolver
+	auditRepo       repository.AuditRepository
+	reindexCooldown time.Duration
+	now             func() time.Time
+}
+
+// SearchServiceDeps holds the optional collaborators of the search service
+type SearchServiceDeps struct {
+	// Catalog hydrates hits, so they carry the current metadata and deleted
+	// media is never returned; nil serves hits as the index holds them
+	Catalog MediaCatalog
+	// Contexts resolves the title, category and people of the show of episodes
+	// to index with them; nil indexes episodes alone
+	Contexts *SearchContextResolver
+	// AuditRepo records reindexes, at most one of which starts per
+	// ReindexCooldown; nil neither audits nor limits them
+	AuditRepo       repository.AuditRepository
+	ReindexCooldown time.Duration
+	// Parser parses queries, nil using AdvancedSearchQueryParser
+	Parser SearchQueryParser
 }
 
 // NewSearchService creates a new search service
-func NewSearchService(searchRepo repository.SearchRepository, cmsClient *httpclient.Client) SearchService {
+func NewSearchService(searchRepo repository.SearchRepository, cmsClient *httpclient.Client, deps SearchServiceDeps) SearchService {
+	parser := deps.Parser
+	if parser == nil {
+		parser = AdvancedSearchQueryParser
+	}
 	return &SearchServiceImpl{
-		searchRepo: searchRepo,
-		cmsClient:  cmsClient,
+		searchRepo:      searchRepo,
+		parser:          parser,
+		cmsClient:       cmsClient,
+		catalog:         deps.Catalog,
+		contexts:        deps.Contexts,
+		auditRepo:       deps.AuditRepo,
+		reindexCooldown: deps.ReindexCooldown,
+		now:             time.Now,
 	}
 }
 
@@ -46,19 +109,48 @@ func (s *SearchServiceImpl) Search(ctx context.Context, req *domain.SearchReques
 		}
 	}
 
+	if req.Collapse != "" && req.Collapse != domain.SearchCollapseShow {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_COLLAPSE", "Search results can only be collapsed by show",
+			"collapse must be show, got "+req.Collapse)
+	}
+
+	req.AudioLanguage = domain.NormalizeAudioLanguage(req.AudioLanguage)
+	if req.AudioLanguage != "" && !domain.IsValidAudioLanguage(req.AudioLanguage) {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_AUDIO_LANGUAGE", "Audio language must be a language tag",
+			"audio_language must be like ar or en-US, got "+req.AudioLanguage)
+	}
+
 	// Set defaults
 	if req.Limit <= 0 {
-		req.Limit = 20
+		req.Limit = domain.DefaultSearchLimit
 	}
 	if req.Offset < 0 {
 		req.Offset = 0
 	}
 
+	// Parse the query, its type filter narrowing the type of the request
+	parsed, err := s.parser.Parse(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Type != "" {
+		if req.Type != "" && req.Type != parsed.Type {
+			return nil, domain.NewBusinessErrorWithDetails("INVALID_SEARCH_QUERY", "Search query filters another type than the request",
+				fmt.Sprintf("the query searches %s but the request %s", parsed.Type, req.Type))
+		}
+		req.Type = parsed.Type
+	}
+	// Terms are matched in the normalized form the indexed content is stored in
+	req.Parsed = parsed.Normalized()
+
 	// Perform search
 	results, total, err := s.searchRepo.Search(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
+	if s.catalog != nil {
+		results, total = s.hydrate(ctx, results, total, req.Fresh)
+	}
 
 	// Build response
 	response := &domain.SearchResponse{
@@ -72,6 +164,80 @@ func (s *SearchServiceImpl) Search(ctx context.Context, req *domain.SearchReques
 	return response, nil
 }
 
+// hydrate replaces the media of the hits and their inner hits with the
+// authoritative records of the catalog, bypassing its cache when fresh is set.
+// Hits whose media no longer exists or stopped being searchable are left out
+// and removed from the index; the first remaining inner hit of a collapsed
+// show takes the place of its hit. When the catalog is unavailable the hits
+// are returned as indexed.
+func (s *SearchServiceImpl) hydrate(ctx context.Context, results []*domain.SearchResult, total int64, fresh bool) ([]*domain.SearchResult, int64) {
+	if len(results) == 0 {
+		return results, total
+	}
+
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.Media.ID)
+		for _, inner := range result.InnerHits {
+			ids = append(ids, inner.Media.ID)
+		}
+	}
+	var mediaList []*domain.Media
+	var err error
+	if freshCatalog, ok := s.catalog.(FreshMediaCatalog); ok && fresh {
+		mediaList, err = freshCatalog.GetFreshMediaByIDs(ctx, ids)
+	} else {
+		mediaList, err = s.catalog.GetMediaByIDs(ctx, ids)
+	}
+	if err != nil {
+		log.Printf("Failed to hydrate search hits, returning them as indexed: %v", err)
+		return results, total
+	}
+
+	current := make(map[string]*domain.Media, len(mediaList))
+	for _, media := range mediaList {
+		current[media.ID] = media
+	}
+
+	// resolve returns the hit with its current media, or nil once removed from the index
+	resolve := func(result *domain.SearchResult) *domain.SearchResult {
+		if media, ok := current[result.Media.ID]; ok && media.CanBeSearched() {
+			return &domain.SearchResult{Media: media, Score: result.Score}
+		}
+		if err := s.searchRepo.RemoveFromIndex(ctx, result.Media.ID); err != nil {
+			log.Printf("Failed to remove unresolved media %s from index: %v", result.Media.ID, err)
+		}
+		return nil
+	}
+
+	kept := results[:0]
+	for _, result := range results {
+		var innerHits []*domain.SearchResult
+		innerHitsTotal := result.InnerHitsTotal
+		for _, inner := range result.InnerHits {
+			if hydrated := resolve(inner); hydrated != nil {
+				innerHits = append(innerHits, hydrated)
+			} else {
+				innerHitsTotal--
+			}
+		}
+
+		hydrated := resolve(result)
+		if hydrated == nil && len(innerHits) > 0 {
+			hydrated, innerHits = innerHits[0], innerHits[1:]
+			innerHitsTotal--
+		}
+		if hydrated == nil {
+			total--
+			continue
+		}
+		hydrated.InnerHits, hydrated.InnerHitsTotal = innerHits, max(innerHitsTotal, int64(len(innerHits)))
+		kept = append(kept, hydrated)
+	}
+
+	return kept, max(total, int64(len(kept)))
+}
+
 // Suggest provides search suggestions
 func (s *SearchServiceImpl) Suggest(ctx context.Context, req *domain.SuggestRequest) (*domain.SuggestResponse, error) {
 	// Validate request
@@ -84,7 +250,7 @@ func (s *SearchServiceImpl) Suggest(ctx context.Context, req *domain.SuggestRequ
 
 	// Set defaults
 	if req.Limit <= 0 {
-		req.Limit = 10
+		req.Limit = domain.DefaultSuggestLimit
 	}
 
 	// Get suggestions
@@ -103,8 +269,77 @@ func (s *SearchServiceImpl) Suggest(ctx context.Context, req *domain.SuggestRequ
 }
 
 // Reindex rebuilds the search index by fetching data from CMS service
-func (s *SearchServiceImpl) Reindex(ctx context.Context) error {
-	return s.reindexWithPagination(ctx)
+func (s *SearchServiceImpl) Reindex(ctx context.Context, actor domain.AuditActor) error {
+	if s.auditRepo == nil {
+		return s.reindexWithPagination(ctx)
+	}
+
+	if err := s.startReindex(ctx, actor); err != nil {
+		return err
+	}
+
+	err := s.reindexWithPagination(ctx)
+	outcome, details := domain.AuditOutcomeSucceeded, ""
+	if err != nil {
+		outcome, details = domain.AuditOutcomeFailed, err.Error()
+	}
+	if auditErr := s.audit(ctx, outcome, actor, details); auditErr != nil {
+		log.Printf("Failed to audit reindex outcome: %v", auditErr)
+	}
+
+	return err
+}
+
+// startReindex records the start of a reindex, or its rejection when the
+// previous one started within the cooldown window. The repository checks and
+// records the start atomically, so the cooldown holds across replicas.
+func (s *SearchServiceImpl) startReindex(ctx context.Context, actor domain.AuditActor) error {
+	started := s.auditEntry(ctx, domain.AuditOutcomeStarted, actor, "")
+
+	// Unaudited reindexes are not run
+	if s.reindexCooldown <= 0 {
+		if err := s.auditRepo.Record(ctx, started); err != nil {
+			return fmt.Errorf("failed to audit reindex: %w", err)
+		}
+		return nil
+	}
+
+	last, err := s.auditRepo.RecordUnlessSince(ctx, started, started.CreatedAt.Add(-s.reindexCooldown))
+	if err != nil {
+		return fmt.Errorf("failed to audit reindex: %w", err)
+	}
+	if last != nil {
+		if err := s.audit(ctx, domain.AuditOutcomeRejected, actor, "cooldown"); err != nil {
+			log.Printf("Failed to audit rejected reindex: %v", err)
+		}
+		return &domain.CooldownError{
+			Action:     domain.AuditActionSearchReindex,
+			RetryAfter: s.reindexCooldown - started.CreatedAt.Sub(last.CreatedAt),
+		}
+	}
+	return nil
+}
+
+// audit records a reindex invocation in the audit trail
+func (s *SearchServiceImpl) audit(ctx context.Context, outcome string, actor domain.AuditActor, details string) error {
+	return s.auditRepo.Record(ctx, s.auditEntry(ctx, outcome, actor, details))
+}
+
+// auditEntry builds the audit trail entry of a reindex invocation
+func (s *SearchServiceImpl) auditEntry(ctx context.Context, outcome string, actor domain.AuditActor, details string) *domain.AuditEntry {
+	entry := &domain.AuditEntry{
+		ID:        uuid.New().String(),
+		Action:    domain.AuditActionSearchReindex,
+		Outcome:   outcome,
+		Actor:     actor.Name,
+		ClientIP:  actor.ClientIP,
+		Details:   details,
+		CreatedAt: s.now().UTC(),
+	}
+	if trace := domain.TraceFromContext(ctx); trace != nil {
+		entry.RequestID = trace.RequestID
+	}
+	return entry
 }
 
 // reindexWithPagination handles large datasets by paginating through CMS data
@@ -131,8 +366,12 @@ func (s *SearchServiceImpl) reindexWithPagination(ctx context.Context) error {
 			return fmt.Errorf("failed to parse CMS response: %w", err)
 		}
 
-		// Add to collection
-		allMedia = append(allMedia, cmsResponse.Items...)
+		// Add to collection, only searchable media is indexed
+		for _, media := range cmsResponse.Items {
+			if media.CanBeSearched() {
+				allMedia = append(allMedia, media)
+			}
+		}
 
 		// Check if we've fetched all data
 		if len(cmsResponse.Items) < batchSize {
@@ -147,6 +386,7 @@ func (s *SearchServiceImpl) reindexWithPagination(ctx context.Context) error {
 		}
 	}
 
-	// Reindex all media in batches
+	// Reindex all media in batches, with the context of their shows
+	s.contexts.Resolve(ctx, allMedia)
 	return s.searchRepo.ReindexAll(ctx, allMedia)
 }
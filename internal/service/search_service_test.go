@@ -3,13 +3,18 @@ package service
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
 	"thamaniyah/pkg/httpclient"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSearchRepository is a mock implementation of SearchRepository
@@ -48,6 +53,14 @@ func (m *MockSearchRepository) ReindexAll(ctx context.Context, media []*domain.M
 	return args.Error(0)
 }
 
+func (m *MockSearchRepository) ListIndexed(ctx context.Context) ([]*domain.IndexedMedia, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IndexedMedia), args.Error(1)
+}
+
 func TestSearchService_Search(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -118,6 +131,32 @@ func TestSearchService_Search(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "advanced query syntax",
+			request: &domain.SearchRequest{
+				Query: `"clean code" type:podcast -java OR golang`,
+			},
+			setupMock: func(mockRepo *MockSearchRepository) {
+				mockRepo.On("Search", mock.Anything, mock.MatchedBy(func(req *domain.SearchRequest) bool {
+					return req.Type == "podcast" && req.Parsed != nil && len(req.Parsed.Clauses) == 2 &&
+						req.Parsed.Clauses[0].Terms[0] == domain.SearchTerm{Text: "clean code", Phrase: true} &&
+						req.Parsed.Clauses[0].Excluded[0].Text == "java"
+				})).Return([]*domain.SearchResult{}, int64(0), nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "query type conflicting with request type",
+			request: &domain.SearchRequest{
+				Query: "golang type:podcast",
+				Type:  "video",
+			},
+			setupMock: func(mockRepo *MockSearchRepository) {
+				// No expectations as parsing should fail before repository call
+			},
+			expectError: true,
+			errorCode:   "INVALID_SEARCH_QUERY",
+		},
 	}
 
 	for _, tt := range tests {
@@ -125,7 +164,7 @@ func TestSearchService_Search(t *testing.T) {
 			// Given
 			mockRepo := new(MockSearchRepository)
 			tt.setupMock(mockRepo)
-			service := NewSearchService(mockRepo, &httpclient.Client{})
+			service := NewSearchService(mockRepo, &httpclient.Client{}, SearchServiceDeps{})
 			ctx := context.Background()
 
 			// When
@@ -226,7 +265,7 @@ func TestSearchService_Suggest(t *testing.T) {
 			// Given
 			mockRepo := new(MockSearchRepository)
 			tt.setupMock(mockRepo)
-			service := NewSearchService(mockRepo, &httpclient.Client{})
+			service := NewSearchService(mockRepo, &httpclient.Client{}, SearchServiceDeps{})
 			ctx := context.Background()
 
 			// When
@@ -261,19 +300,19 @@ func TestSearchService_Suggest(t *testing.T) {
 func TestSearchService_Reindex(t *testing.T) {
 	// Note: This is a simplified test since mocking HTTP client requires more setup
 	// In a real application, you would inject an HTTP client interface for better testability
-	
+
 	t.Run("reindex with mock repository", func(t *testing.T) {
 		// Given
 		mockRepo := new(MockSearchRepository)
 		// Setup mock to expect ReindexAll to be called (though HTTP call will fail)
 		mockRepo.On("ReindexAll", mock.Anything, mock.AnythingOfType("[]*domain.Media")).Return(nil)
-		
+
 		// Create service - note this will try to make HTTP calls
-		service := NewSearchService(mockRepo, httpclient.NewClient("http://localhost:8080"))
+		service := NewSearchService(mockRepo, httpclient.NewClient("http://localhost:8080"), SearchServiceDeps{})
 		ctx := context.Background()
 
 		// When - this will fail due to HTTP connection, which is expected in unit tests
-		err := service.Reindex(ctx)
+		err := service.Reindex(ctx, domain.AuditActor{})
 
 		// Then - we expect an error since HTTP client can't connect
 		assert.Error(t, err)
@@ -287,9 +326,198 @@ func TestNewSearchService(t *testing.T) {
 	mockClient := &httpclient.Client{}
 
 	// When
-	service := NewSearchService(mockRepo, mockClient)
+	service := NewSearchService(mockRepo, mockClient, SearchServiceDeps{})
 
 	// Then
 	assert.NotNil(t, service)
 	assert.IsType(t, &SearchServiceImpl{}, service)
 }
+
+func TestSearchService_Search_DropsUnresolvedHits(t *testing.T) {
+	// Given an index holding a live, a deleted and a failed media
+	ctx := context.Background()
+	searchRepo := repository.NewInMemorySearchRepository()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	for _, media := range []*domain.Media{
+		{ID: "live", Title: "Golang Live", Status: domain.StatusReady},
+		{ID: "deleted", Title: "Golang Deleted", Status: domain.StatusReady},
+		{ID: "failed", Title: "Golang Failed", Status: domain.StatusReady},
+	} {
+		require.NoError(t, searchRepo.IndexMedia(ctx, media))
+	}
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "live", Title: "Golang Live", Status: domain.StatusReady}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "failed", Title: "Golang Failed", Status: domain.StatusFailed}))
+	catalog := NewMediaService(mediaRepo, MediaServiceDeps{Publisher: newMockEventPublisher()})
+	service := NewSearchService(searchRepo, nil, SearchServiceDeps{Catalog: catalog})
+
+	// When
+	response, err := service.Search(ctx, &domain.SearchRequest{Query: "golang"})
+
+	// Then only the live media is returned and the others leave the index
+	require.NoError(t, err)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, "live", response.Results[0].Media.ID)
+	assert.Equal(t, int64(1), response.Total)
+	_, indexed, err := searchRepo.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), indexed)
+}
+
+func TestSearchService_Search_HydratesHits(t *testing.T) {
+	// Given a hit indexed before its title was corrected in CMS
+	ctx := context.Background()
+	searchRepo := repository.NewInMemorySearchRepository()
+	require.NoError(t, searchRepo.IndexMedia(ctx, &domain.Media{ID: "m1", Title: "Golang Weekyl", Status: domain.StatusReady}))
+	source := &countingMediaCatalog{media: map[string]*domain.Media{
+		"m1": {ID: "m1", Title: "Golang Weekly", Status: domain.StatusReady, Duration: 120},
+	}}
+	catalog := NewCachedMediaCatalog(source, time.Minute)
+	service := NewSearchService(searchRepo, nil, SearchServiceDeps{Catalog: catalog})
+
+	// When searching twice, the second time asking for fresh metadata
+	response, err := service.Search(ctx, &domain.SearchRequest{Query: "golang"})
+	require.NoError(t, err)
+	_, err = service.Search(ctx, &domain.SearchRequest{Query: "golang", Fresh: true})
+	require.NoError(t, err)
+
+	// Then the hit carries the CMS record, fetched again for the fresh search
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, "Golang Weekly", response.Results[0].Media.Title)
+	assert.Equal(t, 120, response.Results[0].Media.Duration)
+	assert.Len(t, source.requested, 2)
+}
+
+func TestSearchService_Search_CollapsesByShow(t *testing.T) {
+	// Given three episodes of a show, the best matching one since deleted
+	ctx := context.Background()
+	searchRepo := repository.NewInMemorySearchRepository()
+	source := &countingMediaCatalog{media: map[string]*domain.Media{}}
+	for _, id := range []string{"e1", "e2", "e3"} {
+		media := &domain.Media{ID: id, Title: "Golang Weekly", ShowID: "show-1", Status: domain.StatusReady}
+		require.NoError(t, searchRepo.IndexMedia(ctx, media))
+		if id != "e1" {
+			source.media[id] = media
+		}
+	}
+	service := NewSearchService(searchRepo, nil, SearchServiceDeps{Catalog: source})
+
+	// When searching collapsed by show
+	response, err := service.Search(ctx, &domain.SearchRequest{Query: "golang", Collapse: domain.SearchCollapseShow})
+
+	// Then the next episode takes the place of the deleted one
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), response.Total)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, "e2", response.Results[0].Media.ID)
+	require.Len(t, response.Results[0].InnerHits, 1)
+	assert.Equal(t, "e3", response.Results[0].InnerHits[0].Media.ID)
+	assert.Equal(t, int64(1), response.Results[0].InnerHitsTotal)
+
+	// And other collapse fields are rejected
+	_, err = service.Search(ctx, &domain.SearchRequest{Query: "golang", Collapse: "category"})
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_COLLAPSE", businessErr.Code)
+}
+
+// memoryAuditRepository keeps audit entries in insertion order
+type memoryAuditRepository struct {
+	entries []*domain.AuditEntry
+}
+
+func (r *memoryAuditRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *memoryAuditRepository) GetLatest(ctx context.Context, action, outcome string) (*domain.AuditEntry, error) {
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if r.entries[i].Action == action && r.entries[i].Outcome == outcome {
+			return r.entries[i], nil
+		}
+	}
+	return nil, domain.ErrAuditEntryNotFound
+}
+
+func (r *memoryAuditRepository) RecordUnlessSince(ctx context.Context, entry *domain.AuditEntry, since time.Time) (*domain.AuditEntry, error) {
+	if last, err := r.GetLatest(ctx, entry.Action, entry.Outcome); err == nil && last.CreatedAt.After(since) {
+		return last, nil
+	}
+	return nil, r.Record(ctx, entry)
+}
+
+func (r *memoryAuditRepository) outcomes() []string {
+	outcomes := make([]string, 0, len(r.entries))
+	for _, entry := range r.entries {
+		outcomes = append(outcomes, entry.Outcome)
+	}
+	return outcomes
+}
+
+func TestSearchService_Reindex_CooldownAndAudit(t *testing.T) {
+	// Given a CMS without media and a five minute cooldown
+	cms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[],"total":0}`))
+	}))
+	defer cms.Close()
+
+	searchRepo := new(MockSearchRepository)
+	searchRepo.On("ReindexAll", mock.Anything, mock.Anything).Return(nil)
+	auditRepo := &memoryAuditRepository{}
+	svc := NewSearchService(searchRepo, httpclient.NewClient(cms.URL), SearchServiceDeps{AuditRepo: auditRepo, ReindexCooldown: 5 * time.Minute}).(*SearchServiceImpl)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	ctx := domain.ContextWithTrace(context.Background(), &domain.TraceContext{RequestID: "req-1"})
+	actor := domain.AuditActor{Name: "admin", ClientIP: "10.0.0.1"}
+
+	// When reindexing
+	err := svc.Reindex(ctx, actor)
+
+	// Then it runs and its start and success are audited
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.AuditOutcomeStarted, domain.AuditOutcomeSucceeded}, auditRepo.outcomes())
+	assert.Equal(t, "admin", auditRepo.entries[0].Actor)
+	assert.Equal(t, "10.0.0.1", auditRepo.entries[0].ClientIP)
+	assert.Equal(t, "req-1", auditRepo.entries[0].RequestID)
+
+	// When reindexing again two minutes later
+	now = now.Add(2 * time.Minute)
+	err = svc.Reindex(ctx, actor)
+
+	// Then it is rejected with the remaining cooldown, and the rejection audited
+	var cooldownErr *domain.CooldownError
+	require.ErrorAs(t, err, &cooldownErr)
+	assert.ErrorIs(t, err, domain.ErrCooldown)
+	assert.Equal(t, 3*time.Minute, cooldownErr.RetryAfter)
+	assert.Equal(t, domain.AuditOutcomeRejected, auditRepo.entries[2].Outcome)
+	searchRepo.AssertNumberOfCalls(t, "ReindexAll", 1)
+
+	// When reindexing once the cooldown has passed
+	now = now.Add(3 * time.Minute)
+	err = svc.Reindex(ctx, actor)
+
+	// Then it runs again
+	require.NoError(t, err)
+	searchRepo.AssertNumberOfCalls(t, "ReindexAll", 2)
+}
+
+func TestSearchService_Reindex_AuditsFailure(t *testing.T) {
+	// Given an unreachable CMS
+	cms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer cms.Close()
+
+	auditRepo := &memoryAuditRepository{}
+	svc := NewSearchService(new(MockSearchRepository), httpclient.NewClient(cms.URL), SearchServiceDeps{AuditRepo: auditRepo, ReindexCooldown: time.Minute})
+
+	// When reindexing
+	err := svc.Reindex(context.Background(), domain.AuditActor{Name: "admin"})
+
+	// Then the failure is audited with its cause
+	require.Error(t, err)
+	assert.Equal(t, []string{domain.AuditOutcomeStarted, domain.AuditOutcomeFailed}, auditRepo.outcomes())
+	assert.Contains(t, auditRepo.entries[1].Details, "failed to fetch media from CMS service")
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// minWarmupPrefix is the shortest prefix of a warm-up query suggestions are preloaded for
+const minWarmupPrefix = 2
+
+// SearchWarmupOptions configures the warm-up of the search caches at startup
+type SearchWarmupOptions struct {
+	Queries []string      // representative queries, run as users would
+	Timeout time.Duration // how long the warm-up may delay serving, 0 for no limit
+}
+
+// SearchWarmupReport summarizes a warm-up
+type SearchWarmupReport struct {
+	Searches    int // queries searched
+	Suggestions int // prefixes suggestions were loaded for
+	Failed      int // searches and suggestions that failed
+	Duration    time.Duration
+}
+
+// SearchWarmer runs representative queries before a replica serves traffic,
+// so that the index caches, the hydration cache and the suggestion caches are
+// loaded by the warm-up rather than by the first users after a deploy
+type SearchWarmer interface {
+	// Warm searches every query and loads suggestions for their prefixes, as
+	// typed. Failures are logged and counted, never fatal. It stops early when
+	// ctx is cancelled or the timeout expires.
+	Warm(ctx context.Context) SearchWarmupReport
+}
+
+// searchWarmer implements SearchWarmer interface
+type searchWarmer struct {
+	searchService SearchService
+	options       SearchWarmupOptions
+}
+
+// NewSearchWarmer creates a new search warmer. Blank queries are skipped.
+func NewSearchWarmer(searchService SearchService, options SearchWarmupOptions) SearchWarmer {
+	queries := make([]string, 0, len(options.Queries))
+	for _, query := range options.Queries {
+		if query = strings.TrimSpace(query); query != "" {
+			queries = append(queries, query)
+		}
+	}
+	options.Queries = queries
+
+	return &searchWarmer{
+		searchService: searchService,
+		options:       options,
+	}
+}
+
+// Warm runs the warm-up queries
+func (w *searchWarmer) Warm(ctx context.Context) SearchWarmupReport {
+	started := time.Now()
+	if w.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.options.Timeout)
+		defer cancel()
+	}
+
+	var report SearchWarmupReport
+	for _, query := range w.options.Queries {
+		if ctx.Err() != nil {
+			log.Printf("Search warm-up stopped after %d of %d queries: %v", report.Searches, len(w.options.Queries), ctx.Err())
+			break
+		}
+
+		report.Searches++
+		if _, err := w.searchService.Search(ctx, &domain.SearchRequest{Query: query}); err != nil {
+			report.Failed++
+			log.Printf("Search warm-up query %q failed: %v", query, err)
+		}
+
+		for _, prefix := range warmupPrefixes(query) {
+			if ctx.Err() != nil {
+				break
+			}
+			report.Suggestions++
+			if _, err := w.searchService.Suggest(ctx, &domain.SuggestRequest{Query: prefix}); err != nil {
+				report.Failed++
+				log.Printf("Search warm-up suggestions for %q failed: %v", prefix, err)
+			}
+		}
+	}
+
+	report.Duration = time.Since(started)
+	return report
+}
+
+// warmupPrefixes returns the prefixes of query a user types on the way to it,
+// from minWarmupPrefix characters, without trailing spaces
+func warmupPrefixes(query string) []string {
+	runes := []rune(query)
+	var prefixes []string
+	for n := minWarmupPrefix; n <= len(runes); n++ {
+		if prefix := string(runes[:n]); strings.TrimSpace(prefix) == prefix {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
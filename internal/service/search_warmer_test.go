@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSearchService records the queries it is asked for
+type recordingSearchService struct {
+	SearchService
+	searches    []string
+	suggestions []string
+	failing     string // query whose search fails
+	cancel      context.CancelFunc
+}
+
+func (s *recordingSearchService) Search(ctx context.Context, req *domain.SearchRequest) (*domain.SearchResponse, error) {
+	s.searches = append(s.searches, req.Query)
+	if req.Query == s.failing {
+		return nil, errors.New("search failed")
+	}
+	return &domain.SearchResponse{}, nil
+}
+
+func (s *recordingSearchService) Suggest(ctx context.Context, req *domain.SuggestRequest) (*domain.SuggestResponse, error) {
+	s.suggestions = append(s.suggestions, req.Query)
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return &domain.SuggestResponse{}, nil
+}
+
+func TestSearchWarmer_Warm(t *testing.T) {
+	searchService := &recordingSearchService{failing: "قرآن"}
+	warmer := NewSearchWarmer(searchService, SearchWarmupOptions{Queries: []string{" go tip ", "", "قرآن"}, Timeout: time.Minute})
+
+	report := warmer.Warm(context.Background())
+
+	assert.Equal(t, []string{"go tip", "قرآن"}, searchService.searches)
+	assert.Equal(t, []string{"go", "go t", "go ti", "go tip", "قر", "قرآ", "قرآن"}, searchService.suggestions)
+	assert.Equal(t, 2, report.Searches)
+	assert.Equal(t, 7, report.Suggestions)
+	assert.Equal(t, 1, report.Failed)
+}
+
+func TestSearchWarmer_Warm_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	searchService := &recordingSearchService{cancel: cancel}
+	warmer := NewSearchWarmer(searchService, SearchWarmupOptions{Queries: []string{"golang", "rust"}})
+
+	report := warmer.Warm(ctx)
+
+	assert.Equal(t, []string{"golang"}, searchService.searches)
+	assert.Equal(t, []string{"go"}, searchService.suggestions)
+	assert.Equal(t, SearchWarmupReport{Searches: 1, Suggestions: 1, Duration: report.Duration}, report)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// SeriesService links the numbered parts of multi-part media into series
+type SeriesService interface {
+	// GetSeries places a media item within its series, listing the parts the viewer may see
+	GetSeries(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.SeriesResponse, error)
+
+	// SetSeries links a media item to a series part chosen by an editor, overriding detection
+	SetSeries(ctx context.Context, mediaID string, req *domain.SeriesRequest) (*domain.Media, error)
+
+	// UnlinkSeries removes a media item from its series and keeps detection from relinking it
+	UnlinkSeries(ctx context.Context, mediaID string) (*domain.Media, error)
+
+	// DetectSeries drops the editor's choice and links a media item to the series numbered in its title
+	DetectSeries(ctx context.Context, mediaID string) (*domain.Media, error)
+}
+
+// seriesService implements SeriesService interface
+type seriesService struct {
+	mediaRepo repository.MediaRepository
+}
+
+// NewSeriesService creates a new series service
+func NewSeriesService(mediaRepo repository.MediaRepository) SeriesService {
+	return &seriesService{
+		mediaRepo: mediaRepo,
+	}
+}
+
+// GetSeries places a media item within its series, listing the parts the viewer may see
+func (s *seriesService) GetSeries(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.SeriesResponse, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !media.IsProcessed() {
+		return nil, domain.ErrMediaNotFound
+	}
+	if media.SeriesID == "" {
+		return domain.NewSeriesResponse(media, nil), nil
+	}
+
+	parts, err := s.mediaRepo.GetBySeries(ctx, media.SeriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series parts: %w", err)
+	}
+
+	// Other parts are listed like search results, the requested one is always in place
+	listed := make([]*domain.Media, 0, len(parts))
+	for _, part := range parts {
+		if part.ID == media.ID || (part.IsProcessed() && (viewer.IsAdmin || part.IsPublic())) {
+			listed = append(listed, part)
+		}
+	}
+
+	return domain.NewSeriesResponse(media, listed), nil
+}
+
+// SetSeries links a media item to a series part chosen by an editor, overriding detection
+func (s *seriesService) SetSeries(ctx context.Context, mediaID string, req *domain.SeriesRequest) (*domain.Media, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_SERIES", "Series validation failed", errs.Error())
+	}
+
+	return s.updateSeries(ctx, mediaID, func(media *domain.Media) {
+		media.SeriesID = req.SeriesID
+		media.SeriesPart = req.Part
+		media.SeriesLocked = true
+	})
+}
+
+// UnlinkSeries removes a media item from its series and keeps detection from relinking it
+func (s *seriesService) UnlinkSeries(ctx context.Context, mediaID string) (*domain.Media, error) {
+	return s.updateSeries(ctx, mediaID, func(media *domain.Media) {
+		media.SeriesID = ""
+		media.SeriesPart = 0
+		media.SeriesLocked = true
+	})
+}
+
+// DetectSeries drops the editor's choice and links a media item to the series numbered in its title
+func (s *seriesService) DetectSeries(ctx context.Context, mediaID string) (*domain.Media, error) {
+	return s.updateSeries(ctx, mediaID, func(media *domain.Media) {
+		media.SeriesLocked = false
+		media.DetectSeries()
+	})
+}
+
+// updateSeries applies change to the series of a media item that is not deleted and stores it
+func (s *seriesService) updateSeries(ctx context.Context, mediaID string, change func(*domain.Media)) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+
+	change(media)
+	if err := s.mediaRepo.UpdateSeries(ctx, media.ID, media.SeriesID, media.SeriesPart, media.SeriesLocked); err != nil {
+		return nil, fmt.Errorf("failed to update series: %w", err)
+	}
+
+	return media, nil
+}
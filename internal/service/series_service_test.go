@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createSeriesPart stores a ready part of a show, linked by its title like uploads are
+func createSeriesPart(t *testing.T, mediaRepo repository.MediaRepository, id, title string, visibility domain.MediaVisibility) {
+	t.Helper()
+	media := &domain.Media{ID: id, Title: title, Status: domain.StatusReady, Visibility: visibility, ShowID: "show", CreatedAt: time.Now()}
+	media.DetectSeries()
+	require.NoError(t, mediaRepo.Create(context.Background(), media))
+}
+
+func TestSeriesService_GetSeries(t *testing.T) {
+	// Given three parts uploaded out of order, one of them private, and an unnumbered episode
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	createSeriesPart(t, mediaRepo, "p3", "The Founders - Episode 3", domain.VisibilityPublic)
+	createSeriesPart(t, mediaRepo, "p1", "The Founders - Episode 1", domain.VisibilityPublic)
+	createSeriesPart(t, mediaRepo, "p2", "The Founders - Episode 2", domain.VisibilityPrivate)
+	createSeriesPart(t, mediaRepo, "other", "Listener questions", domain.VisibilityPublic)
+	seriesService := NewSeriesService(mediaRepo)
+
+	// When
+	series, err := seriesService.GetSeries(ctx, "p3", domain.Viewer{})
+	require.NoError(t, err)
+
+	// Then the private part is left out
+	assert.Equal(t, 3, series.Part)
+	require.Len(t, series.Parts, 2)
+	assert.Equal(t, "p1", series.Parts[0].MediaID)
+	assert.Equal(t, "p1", series.Previous.MediaID)
+	assert.Nil(t, series.Next)
+
+	// And admins see every part
+	series, err = seriesService.GetSeries(ctx, "p1", domain.Viewer{IsAdmin: true})
+	require.NoError(t, err)
+	require.Len(t, series.Parts, 3)
+	assert.Equal(t, "p2", series.Next.MediaID)
+
+	// And media outside a series has no parts
+	series, err = seriesService.GetSeries(ctx, "other", domain.Viewer{})
+	require.NoError(t, err)
+	assert.Empty(t, series.SeriesID)
+	assert.Empty(t, series.Parts)
+
+	_, err = seriesService.GetSeries(ctx, "p2", domain.Viewer{})
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+}
+
+func TestSeriesService_Overrides(t *testing.T) {
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	createSeriesPart(t, mediaRepo, "p1", "The Founders - Episode 1", domain.VisibilityPublic)
+	createSeriesPart(t, mediaRepo, "bonus", "Founders bonus: the lost tapes", domain.VisibilityPublic)
+	seriesService := NewSeriesService(mediaRepo)
+	p1, err := mediaRepo.GetByID(ctx, "p1")
+	require.NoError(t, err)
+
+	// An editor links the bonus episode to the series
+	media, err := seriesService.SetSeries(ctx, "bonus", &domain.SeriesRequest{SeriesID: p1.SeriesID, Part: 2})
+	require.NoError(t, err)
+	assert.True(t, media.SeriesLocked)
+	series, err := seriesService.GetSeries(ctx, "p1", domain.Viewer{})
+	require.NoError(t, err)
+	assert.Equal(t, "bonus", series.Next.MediaID)
+
+	// Unlinking keeps the part out of the series
+	media, err = seriesService.UnlinkSeries(ctx, "p1")
+	require.NoError(t, err)
+	assert.Empty(t, media.SeriesID)
+	stored, err := mediaRepo.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.Empty(t, stored.SeriesID)
+	assert.True(t, stored.SeriesLocked)
+
+	// Detection links it again
+	media, err = seriesService.DetectSeries(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, p1.SeriesID, media.SeriesID)
+	assert.False(t, media.SeriesLocked)
+
+	var businessErr *domain.BusinessError
+	_, err = seriesService.SetSeries(ctx, "p1", &domain.SeriesRequest{SeriesID: "founders"})
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_SERIES", businessErr.Code)
+	_, err = seriesService.UnlinkSeries(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+}
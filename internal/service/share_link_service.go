@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// shareTokenBytes is the amount of randomness in a share token
+const shareTokenBytes = 32
+
+// ShareLinkService manages tokenized share links for media
+type ShareLinkService interface {
+	// CreateShareLink creates a share link for the media and returns its token
+	CreateShareLink(ctx context.Context, mediaID string, req *domain.ShareLinkRequest) (*domain.CreatedShareLink, error)
+
+	// ListShareLinks lists all share links of the media
+	ListShareLinks(ctx context.Context, mediaID string) ([]*domain.ShareLink, error)
+
+	// RevokeShareLink revokes a share link of the media
+	RevokeShareLink(ctx context.Context, mediaID, linkID string) error
+
+	// ResolveShareToken returns the active share link for a token
+	ResolveShareToken(ctx context.Context, token string) (*domain.ShareLink, error)
+}
+
+// shareLinkService implements ShareLinkService interface
+type shareLinkService struct {
+	shareLinkRepo repository.ShareLinkRepository
+	mediaRepo     repository.MediaRepository
+}
+
+// NewShareLinkService creates a new share link service
+func NewShareLinkService(shareLinkRepo repository.ShareLinkRepository, mediaRepo repository.MediaRepository) ShareLinkService {
+	return &shareLinkService{
+		shareLinkRepo: shareLinkRepo,
+		mediaRepo:     mediaRepo,
+	}
+}
+
+// CreateShareLink creates a share link for the media and returns its token
+func (s *shareLinkService) CreateShareLink(ctx context.Context, mediaID string, req *domain.ShareLinkRequest) (*domain.CreatedShareLink, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Share link validation failed", errs.Error())
+	}
+
+	// Make sure the media exists
+	if _, err := s.mediaRepo.GetByID(ctx, mediaID); err != nil {
+		return nil, err
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	link := &domain.ShareLink{
+		ID:        uuid.New().String(),
+		MediaID:   mediaID,
+		TokenHash: domain.HashShareToken(token),
+		ExpiresAt: time.Now().Add(req.TTL()),
+	}
+	if err := s.shareLinkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return &domain.CreatedShareLink{
+		ShareLink: link,
+		Token:     token,
+	}, nil
+}
+
+// ListShareLinks lists all share links of the media
+func (s *shareLinkService) ListShareLinks(ctx context.Context, mediaID string) ([]*domain.ShareLink, error) {
+	if _, err := s.mediaRepo.GetByID(ctx, mediaID); err != nil {
+		return nil, err
+	}
+
+	return s.shareLinkRepo.GetByMediaID(ctx, mediaID)
+}
+
+// RevokeShareLink revokes a share link of the media
+func (s *shareLinkService) RevokeShareLink(ctx context.Context, mediaID, linkID string) error {
+	return s.shareLinkRepo.Revoke(ctx, mediaID, linkID, time.Now())
+}
+
+// ResolveShareToken returns the active share link for a token
+func (s *shareLinkService) ResolveShareToken(ctx context.Context, token string) (*domain.ShareLink, error) {
+	if token == "" {
+		return nil, domain.ErrShareLinkNotFound
+	}
+
+	link, err := s.shareLinkRepo.GetByTokenHash(ctx, domain.HashShareToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	// Expired and revoked links behave as if they never existed
+	if !link.IsActive(time.Now()) {
+		return nil, domain.ErrShareLinkNotFound
+	}
+
+	return link, nil
+}
+
+// generateShareToken returns a random URL-safe token
+func generateShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockShareLinkRepository is a mock implementation of ShareLinkRepository
+type MockShareLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, link *domain.ShareLink) error {
+	args := m.Called(ctx, link)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.ShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) GetByMediaID(ctx context.Context, mediaID string) ([]*domain.ShareLink, error) {
+	args := m.Called(ctx, mediaID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) Revoke(ctx context.Context, mediaID, id string, revokedAt time.Time) error {
+	args := m.Called(ctx, mediaID, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) RevokeByMediaID(ctx context.Context, mediaID string, revokedAt time.Time) (int64, error) {
+	args := m.Called(ctx, mediaID, revokedAt)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestShareLinkService_CreateShareLink(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *domain.ShareLinkRequest
+		setupMocks  func(*MockShareLinkRepository, *MockMediaRepository)
+		expectError bool
+		errorCode   string
+	}{
+		{
+			name:    "creates link with hashed token",
+			request: &domain.ShareLinkRequest{ExpiresIn: 3600},
+			setupMocks: func(linkRepo *MockShareLinkRepository, mediaRepo *MockMediaRepository) {
+				mediaRepo.On("GetByID", mock.Anything, "media-123").Return(&domain.Media{ID: "media-123"}, nil)
+				linkRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ShareLink")).Return(nil)
+			},
+		},
+		{
+			name:    "media not found",
+			request: &domain.ShareLinkRequest{},
+			setupMocks: func(linkRepo *MockShareLinkRepository, mediaRepo *MockMediaRepository) {
+				mediaRepo.On("GetByID", mock.Anything, "media-123").Return(nil, domain.ErrMediaNotFound)
+			},
+			expectError: true,
+		},
+		{
+			name:        "invalid lifetime",
+			request:     &domain.ShareLinkRequest{ExpiresIn: -5},
+			setupMocks:  func(linkRepo *MockShareLinkRepository, mediaRepo *MockMediaRepository) {},
+			expectError: true,
+			errorCode:   "INVALID_REQUEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			linkRepo := new(MockShareLinkRepository)
+			mediaRepo := new(MockMediaRepository)
+			tt.setupMocks(linkRepo, mediaRepo)
+			service := NewShareLinkService(linkRepo, mediaRepo)
+
+			// When
+			link, err := service.CreateShareLink(context.Background(), "media-123", tt.request)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, link)
+				if tt.errorCode != "" {
+					var businessErr *domain.BusinessError
+					if errors.As(err, &businessErr) {
+						assert.Equal(t, tt.errorCode, businessErr.Code)
+					}
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, link.Token)
+				assert.Equal(t, domain.HashShareToken(link.Token), link.TokenHash)
+				assert.Equal(t, "media-123", link.MediaID)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), link.ExpiresAt, time.Minute)
+			}
+			linkRepo.AssertExpectations(t)
+			mediaRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShareLinkService_ResolveShareToken(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		token       string
+		stored      *domain.ShareLink
+		storedErr   error
+		expectError bool
+	}{
+		{
+			name:   "active link",
+			token:  "valid-token",
+			stored: &domain.ShareLink{ID: "link-1", MediaID: "media-123", ExpiresAt: time.Now().Add(time.Hour)},
+		},
+		{
+			name:        "expired link",
+			token:       "expired-token",
+			stored:      &domain.ShareLink{ID: "link-1", MediaID: "media-123", ExpiresAt: time.Now().Add(-time.Hour)},
+			expectError: true,
+		},
+		{
+			name:        "revoked link",
+			token:       "revoked-token",
+			stored:      &domain.ShareLink{ID: "link-1", MediaID: "media-123", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt},
+			expectError: true,
+		},
+		{
+			name:        "unknown token",
+			token:       "unknown-token",
+			storedErr:   domain.ErrShareLinkNotFound,
+			expectError: true,
+		},
+		{
+			name:        "empty token",
+			token:       "",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			linkRepo := new(MockShareLinkRepository)
+			if tt.token != "" {
+				var stored interface{}
+				if tt.stored != nil {
+					stored = tt.stored
+				}
+				linkRepo.On("GetByTokenHash", mock.Anything, domain.HashShareToken(tt.token)).Return(stored, tt.storedErr)
+			}
+			service := NewShareLinkService(linkRepo, new(MockMediaRepository))
+
+			// When
+			link, err := service.ResolveShareToken(context.Background(), tt.token)
+
+			// Then
+			if tt.expectError {
+				assert.ErrorIs(t, err, domain.ErrShareLinkNotFound)
+				assert.Nil(t, link)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "media-123", link.MediaID)
+			}
+			linkRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// ShowTemplateService manages the default metadata applied to the episodes of shows
+type ShowTemplateService interface {
+	// GetTemplate retrieves the template of a show
+	GetTemplate(ctx context.Context, showID string) (*domain.ShowTemplate, error)
+
+	// SetTemplate creates or replaces the template of a show
+	SetTemplate(ctx context.Context, showID string, req *domain.ShowTemplateRequest) (*domain.ShowTemplate, error)
+
+	// DeleteTemplate removes the template of a show; existing episodes keep their metadata
+	DeleteTemplate(ctx context.Context, showID string) error
+}
+
+// showTemplateService implements ShowTemplateService interface
+type showTemplateService struct {
+	templateRepo repository.ShowTemplateRepository
+}
+
+// NewShowTemplateService creates a new show template service
+func NewShowTemplateService(templateRepo repository.ShowTemplateRepository) ShowTemplateService {
+	return &showTemplateService{
+		templateRepo: templateRepo,
+	}
+}
+
+// GetTemplate retrieves the template of a show
+func (s *showTemplateService) GetTemplate(ctx context.Context, showID string) (*domain.ShowTemplate, error) {
+	return s.templateRepo.GetByShowID(ctx, showID)
+}
+
+// SetTemplate creates or replaces the template of a show
+func (s *showTemplateService) SetTemplate(ctx context.Context, showID string, req *domain.ShowTemplateRequest) (*domain.ShowTemplate, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Show template validation failed", errs.Error())
+	}
+
+	template := &domain.ShowTemplate{ShowID: showID}
+	req.ApplyTo(template)
+	if err := s.templateRepo.Save(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to save show template: %w", err)
+	}
+
+	// Read back for the creation time of replaced templates
+	return s.templateRepo.GetByShowID(ctx, showID)
+}
+
+// DeleteTemplate removes the template of a show
+func (s *showTemplateService) DeleteTemplate(ctx context.Context, showID string) error {
+	return s.templateRepo.Delete(ctx, showID)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// maxCollectedKeys caps the unreferenced keys listed in a collection result
+const maxCollectedKeys = 100
+
+// StorageCollectorOptions configures collection of unreferenced stored objects
+type StorageCollectorOptions struct {
+	GracePeriod time.Duration // objects modified more recently are never collected
+	Interval    time.Duration // wait between periodic collections
+	BatchSize   int           // objects matched against media records per query
+	DryRun      bool          // periodic collections only report unreferenced objects
+}
+
+// StorageCollectResult reports what a collection found and did
+type StorageCollectResult struct {
+	DryRun            bool     `json:"dry_run"`
+	Scanned           int      `json:"scanned"`
+	Unreferenced      int      `json:"unreferenced"`
+	UnreferencedBytes int64    `json:"unreferenced_bytes"`
+	Deleted           int      `json:"deleted"`
+	Failed            int      `json:"failed"`
+	Keys              []string `json:"keys,omitempty"` // up to maxCollectedKeys unreferenced keys
+}
+
+// StorageCollector deletes stored objects no media record references, like
+// leftovers of purged media and assets derived before a media item changed
+type StorageCollector interface {
+	// Collect finds the unreferenced objects and, unless dryRun, deletes them
+	Collect(ctx context.Context, dryRun bool) (*StorageCollectResult, error)
+
+	// Run collects periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// storageCollector implements StorageCollector interface
+type storageCollector struct {
+	mediaRepo repository.MediaRepository
+	storage   storage.Storage
+	options   StorageCollectorOptions
+	now       func() time.Time
+}
+
+// NewStorageCollector creates a new storage collector. Objects belong to the
+// media whose ID their key starts with; objects of media in the trash stay
+// until the trash is purged, and keys of any other layout are left alone.
+func NewStorageCollector(mediaRepo repository.MediaRepository, mediaStorage storage.Storage, options StorageCollectorOptions) StorageCollector {
+	if options.GracePeriod <= 0 {
+		options.GracePeriod = 24 * time.Hour
+	}
+	if options.Interval <= 0 {
+		options.Interval = 24 * time.Hour
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 500
+	}
+
+	return &storageCollector{
+		mediaRepo: mediaRepo,
+		storage:   mediaStorage,
+		options:   options,
+		now:       time.Now,
+	}
+}
+
+// Collect finds the unreferenced objects and, unless dryRun, deletes them
+func (c *storageCollector) Collect(ctx context.Context, dryRun bool) (*StorageCollectResult, error) {
+	result := &StorageCollectResult{DryRun: dryRun}
+	// Uploads may land before the record referencing them is committed
+	modifiedBefore := c.now().Add(-c.options.GracePeriod)
+
+	var batch []storage.ObjectInfo
+	err := c.storage.List(ctx, "", func(object storage.ObjectInfo) error {
+		result.Scanned++
+		if !object.ModifiedAt.Before(modifiedBefore) || domain.MediaIDOfStorageKey(object.Key) == "" {
+			return nil
+		}
+		batch = append(batch, object)
+		if len(batch) < c.options.BatchSize {
+			return nil
+		}
+		err := c.collectBatch(ctx, batch, result)
+		batch = nil
+		return err
+	})
+	if err == nil && len(batch) > 0 {
+		err = c.collectBatch(ctx, batch, result)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect stored objects: %w", err)
+	}
+
+	return result, nil
+}
+
+// Run collects periodically until ctx is cancelled
+func (c *storageCollector) Run(ctx context.Context) {
+	for {
+		result, err := c.Collect(ctx, c.options.DryRun)
+		if err != nil {
+			log.Printf("Storage collection failed: %v", err)
+		} else if result.Unreferenced > 0 {
+			log.Printf("Storage collection found %d unreferenced objects (%d bytes), deleted %d, failed %d, dry run: %t",
+				result.Unreferenced, result.UnreferencedBytes, result.Deleted, result.Failed, result.DryRun)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.options.Interval):
+		}
+	}
+}
+
+// collectBatch matches objects against the records of the media they belong to,
+// and deletes the unreferenced ones unless the collection is a dry run
+func (c *storageCollector) collectBatch(ctx context.Context, objects []storage.ObjectInfo, result *StorageCollectResult) error {
+	seen := make(map[string]bool, len(objects))
+	ids := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if mediaID := domain.MediaIDOfStorageKey(object.Key); !seen[mediaID] {
+			seen[mediaID] = true
+			ids = append(ids, mediaID)
+		}
+	}
+
+	mediaList, err := c.mediaRepo.GetByIDsWithTrashed(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load media records: %w", err)
+	}
+	referenced := make(map[string]bool)
+	var referencedPrefixes []string
+	for _, media := range mediaList {
+		for _, key := range media.StoredKeys() {
+			referenced[key] = true
+		}
+		referencedPrefixes = append(referencedPrefixes, media.StoredKeyPrefixes()...)
+	}
+
+	for _, object := range objects {
+		if referenced[object.Key] || hasAnyPrefix(object.Key, referencedPrefixes) {
+			continue
+		}
+		result.Unreferenced++
+		result.UnreferencedBytes += object.Size
+		if len(result.Keys) < maxCollectedKeys {
+			result.Keys = append(result.Keys, object.Key)
+		}
+		if result.DryRun {
+			continue
+		}
+
+		// One failing object must not keep the others stored
+		err := c.storage.Delete(ctx, object.Key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to delete unreferenced object %s: %v", object.Key, err)
+			result.Failed++
+			continue
+		}
+		result.Deleted++
+	}
+
+	return nil
+}
+
+// hasAnyPrefix returns true if key starts with one of prefixes
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
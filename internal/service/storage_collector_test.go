@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageCollector_Collect(t *testing.T) {
+	// Given a live episode, an episode in the trash, and objects no record references
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	store := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", Title: "Live", Status: domain.StatusReady, FilePath: domain.UploadPathPrefix + "m1.mp3",
+		Tags: domain.MediaTags{ArtworkKey: domain.DerivedArtworkKey("m1", "image/png")}}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m2", Title: "Trashed", Status: domain.StatusDeleted, FilePath: domain.UploadPathPrefix + "m2.mp3"}))
+	require.NoError(t, mediaRepo.Delete(ctx, "m2"))
+
+	kept := []string{"m1.mp3", domain.DerivedArtworkKey("m1", "image/png"), "m2.mp3", "exports/report.csv",
+		domain.RenditionKeyPrefix("m1") + "720p/segment_0000.ts"}
+	unreferenced := []string{domain.DerivedArtworkKey("m1", "image/jpeg"), "m3.mp4", domain.DerivedAudioKey("m9", domain.AudioFormatAAC),
+		domain.RenditionFileKey("m9", "720p", "mp4")}
+	for _, key := range append(append([]string{}, kept...), unreferenced...) {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("data")))
+	}
+	collector := NewStorageCollector(mediaRepo, store, StorageCollectorOptions{GracePeriod: time.Hour, BatchSize: 2}).(*storageCollector)
+
+	// When collecting within the grace period
+	result, err := collector.Collect(ctx, false)
+
+	// Then recently written objects are left alone
+	require.NoError(t, err)
+	assert.Equal(t, 9, result.Scanned)
+	assert.Zero(t, result.Unreferenced)
+
+	// When collecting in dry run mode once the grace period has passed
+	collector.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	result, err = collector.Collect(ctx, true)
+
+	// Then the unreferenced objects are reported but kept
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Unreferenced)
+	assert.Equal(t, int64(16), result.UnreferencedBytes)
+	assert.ElementsMatch(t, unreferenced, result.Keys)
+	assert.Zero(t, result.Deleted)
+	exists, err := store.Exists(ctx, "m3.mp4")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// When collecting for real
+	result, err = collector.Collect(ctx, false)
+
+	// Then only the unreferenced objects are deleted
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Deleted)
+	for _, key := range unreferenced {
+		exists, err := store.Exists(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, exists, key)
+	}
+	for _, key := range kept {
+		exists, err := store.Exists(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, exists, key)
+	}
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// StreamOptions configures streaming; zero values use the defaults
+type StreamOptions struct {
+	URLTTL         time.Duration // validity of manifest URLs, from the time each is needed
+	DownloadURLTTL time.Duration // validity of download URLs of uploaded files
+}
+
+// StreamService serves adaptive streaming manifests of transcoded media and
+// signed download URLs of uploaded files
+type StreamService interface {
+	// GetManifest returns the HLS or DASH manifest of a media item with signed
+	// URLs. Without a rendition, an HLS master playlist references the manifest
+	// of every rendition by URIs relative to the stream endpoint.
+	GetManifest(ctx context.Context, mediaID string, viewer domain.Viewer, req domain.StreamRequest) (*domain.StreamManifest, error)
+
+	// GetDownloadURL returns a signed URL of the uploaded file of a media item,
+	// saved as filename when not empty
+	GetDownloadURL(ctx context.Context, mediaID string, viewer domain.Viewer, filename string) (*domain.DownloadURL, error)
+
+	// OpenFile opens a stored file with a download URL signed by the storage of
+	// the service, for filename when it was issued to be saved as one; the
+	// caller must close its body
+	OpenFile(ctx context.Context, key, filename string, expires int64, signature string) (*domain.FileAsset, error)
+}
+
+// streamService implements StreamService interface
+type streamService struct {
+	mediaRepo     repository.MediaRepository
+	renditionRepo repository.RenditionRepository
+	storage       storage.Storage
+	drmService    DRMService
+	options       StreamOptions
+	now           func() time.Time
+}
+
+// NewStreamService creates a new stream service. Its storage must issue
+// download URLs to sign manifests.
+func NewStreamService(mediaRepo repository.MediaRepository, renditionRepo repository.RenditionRepository, store storage.Storage, drmService DRMService, options StreamOptions) StreamService {
+	if options.URLTTL <= 0 {
+		options.URLTTL = domain.DefaultStreamURLTTL
+	}
+	if options.DownloadURLTTL <= 0 {
+		options.DownloadURLTTL = domain.DefaultDownloadURLTTL
+	}
+
+	return &streamService{
+		mediaRepo:     mediaRepo,
+		renditionRepo: renditionRepo,
+		storage:       store,
+		drmService:    drmService,
+		options:       options,
+		now:           time.Now,
+	}
+}
+
+// GetManifest returns the signed manifest of a media item
+func (s *streamService) GetManifest(ctx context.Context, mediaID string, viewer domain.Viewer, req domain.StreamRequest) (*domain.StreamManifest, error) {
+	media, drm, err := s.getPlayableMedia(ctx, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Protected media is only encrypted for Widevine, which plays DASH
+	keyID := ""
+	if drm != nil {
+		if req.Format != domain.StreamFormatDASH {
+			return nil, domain.NewBusinessError("DRM_REQUIRES_DASH", "Protected media is streamed as DASH")
+		}
+		keyID = drm.KeyID
+	}
+
+	renditions, err := s.streamableRenditions(ctx, media.ID, keyID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &domain.StreamManifest{Format: req.Format, ExpiresAt: s.now().Add(s.options.URLTTL)}
+	switch {
+	case req.Format == domain.StreamFormatDASH:
+		// Progressive files are read all along playback
+		ttl := s.options.URLTTL + time.Duration(media.Duration)*time.Second
+		manifest.Body, err = domain.DASHManifest(media.Duration, media.CuePoints, renditions, func(rendition *domain.MediaRendition) (string, error) {
+			return s.presign(ctx, rendition.FileKey, ttl)
+		})
+	case req.Rendition == "":
+		manifest.Body = domain.HLSMasterPlaylist(renditions, func(rendition *domain.MediaRendition) string {
+			query := url.Values{}
+			query.Set("format", string(domain.StreamFormatHLS))
+			query.Set("rendition", rendition.Name)
+			return "stream?" + query.Encode()
+		})
+	default:
+		manifest.Body, err = s.signedMediaPlaylist(ctx, media, renditions[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s manifest: %w", req.Format, err)
+	}
+
+	return manifest, nil
+}
+
+// GetDownloadURL returns a signed URL of the uploaded file of a media item
+func (s *streamService) GetDownloadURL(ctx context.Context, mediaID string, viewer domain.Viewer, filename string) (*domain.DownloadURL, error) {
+	media, drm, err := s.getPlayableMedia(ctx, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	// The upload of protected media is not encrypted
+	if drm != nil {
+		return nil, domain.NewBusinessError("DRM_REQUIRED", "Protected media is played with the license of its playback info")
+	}
+	key := media.StorageKey()
+	if key == "" {
+		return nil, domain.ErrFileNotFound
+	}
+	filename, err = domain.DownloadFilename(filename, path.Ext(key))
+	if err != nil {
+		return nil, err
+	}
+
+	presigner, ok := s.storage.(storage.GetPresigner)
+	if !ok {
+		return nil, errors.New("storage does not issue download URLs")
+	}
+	download := &domain.DownloadURL{Filename: filename, ExpiresAt: s.now().Add(s.options.DownloadURLTTL)}
+	if filename == "" {
+		download.URL, err = presigner.PresignGet(ctx, key, s.options.DownloadURLTTL)
+	} else {
+		download.URL, err = presigner.PresignDownload(ctx, key, s.options.DownloadURLTTL, filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign download URL: %w", err)
+	}
+
+	return download, nil
+}
+
+// OpenFile opens a stored file with a signed download URL
+func (s *streamService) OpenFile(ctx context.Context, key, filename string, expires int64, signature string) (*domain.FileAsset, error) {
+	server, ok := s.storage.(storage.DownloadServer)
+	if !ok || server.VerifyGet(key, filename, expires, signature) != nil {
+		return nil, domain.ErrInvalidDownloadURL
+	}
+
+	object, err := s.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, domain.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+
+	if filename == "" {
+		filename = path.Base(key)
+	}
+	// Uploaded files are served as well as the files of streams
+	contentType := domain.StreamContentType(key)
+	if contentType == "application/octet-stream" {
+		contentType = contentTypeOf(key)
+	}
+
+	return &domain.FileAsset{
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        object.Size,
+		Body:        object.Body,
+	}, nil
+}
+
+// getPlayableMedia returns a ready media item the viewer may play, and its
+// license acquisition info when it is protected
+func (s *streamService) getPlayableMedia(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.Media, *domain.PlaybackDRM, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := media.CheckPlayback(viewer); err != nil {
+		return nil, nil, err
+	}
+	if !media.IsProcessed() {
+		return nil, nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for playback")
+	}
+
+	drm, err := s.drmService.PlaybackDRM(ctx, media)
+	if err != nil {
+		return nil, nil, err
+	}
+	return media, drm, nil
+}
+
+// streamableRenditions returns the ready renditions of media in the requested
+// format encrypted with keyID, clear ones when empty, only the selected one
+// when a rendition is requested
+func (s *streamService) streamableRenditions(ctx context.Context, mediaID, keyID string, req domain.StreamRequest) ([]*domain.MediaRendition, error) {
+	all, err := s.renditionRepo.ListByMediaID(ctx, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renditions: %w", err)
+	}
+
+	var renditions []*domain.MediaRendition
+	for _, rendition := range all {
+		if (req.Rendition != "" && rendition.Name != req.Rendition) || rendition.KeyID != keyID {
+			continue
+		}
+		streamable := rendition.PlaylistKey != ""
+		if req.Format == domain.StreamFormatDASH {
+			streamable = rendition.FileKey != ""
+		}
+		if rendition.Status == domain.RenditionReady && streamable {
+			renditions = append(renditions, rendition)
+		}
+	}
+
+	if len(renditions) > 0 {
+		return renditions, nil
+	}
+	if req.Rendition != "" {
+		return nil, domain.ErrRenditionNotFound
+	}
+	return nil, domain.ErrStreamNotFound
+}
+
+// signedMediaPlaylist returns the stored HLS media playlist of a rendition of
+// media with its ad breaks and a signed URL for every segment, valid until it is played
+func (s *streamService) signedMediaPlaylist(ctx context.Context, media *domain.Media, rendition *domain.MediaRendition) (string, error) {
+	object, err := s.storage.Get(ctx, rendition.PlaylistKey)
+	if err != nil {
+		return "", err
+	}
+	defer object.Body.Close()
+	stored, err := io.ReadAll(object.Body)
+	if err != nil {
+		return "", err
+	}
+	playlist, err := domain.InsertHLSCuePoints(string(stored), media.CuePoints)
+	if err != nil {
+		return "", err
+	}
+
+	dir := path.Dir(rendition.PlaylistKey)
+	return domain.SignHLSPlaylist(playlist, func(uri string, offset time.Duration) (string, error) {
+		if strings.Contains(uri, "://") {
+			return uri, nil
+		}
+		return s.presign(ctx, path.Join(dir, uri), s.options.URLTTL+offset)
+	})
+}
+
+// presign returns a download URL of the object stored under key
+func (s *streamService) presign(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	presigner, ok := s.storage.(storage.GetPresigner)
+	if !ok {
+		return "", errors.New("storage does not issue download URLs")
+	}
+	return presigner.PresignGet(ctx, key, ttl)
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRenditionRepository lists fixed renditions
+type fakeRenditionRepository struct {
+	renditions []*domain.MediaRendition
+}
+
+func (r *fakeRenditionRepository) Replace(ctx context.Context, mediaID string, renditions []*domain.MediaRendition) error {
+	r.renditions = renditions
+	return nil
+}
+
+func (r *fakeRenditionRepository) Update(ctx context.Context, rendition *domain.MediaRendition) error {
+	return nil
+}
+
+func (r *fakeRenditionRepository) ListByMediaID(ctx context.Context, mediaID string) ([]*domain.MediaRendition, error) {
+	var renditions []*domain.MediaRendition
+	for _, rendition := range r.renditions {
+		if rendition.MediaID == mediaID {
+			renditions = append(renditions, rendition)
+		}
+	}
+	return renditions, nil
+}
+
+func newTestStreamService(t *testing.T, media *domain.Media) StreamService {
+	t.Helper()
+	store, err := storage.NewPresigningLocalStorage(storage.NewLocalStorage(t.TempDir()), "https://cms.test", []byte("signing-key"))
+	require.NoError(t, err)
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(context.Background(), media))
+	drmService, err := NewDRMService(new(MockContentKeyRepository), DRMOptions{})
+	require.NoError(t, err)
+
+	// Renditions of media-123 only
+	ctx := context.Background()
+	playlist := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6.0,\nsegment_0000.ts\n#EXTINF:6.0,\nsegment_0001.ts\n#EXT-X-ENDLIST\n"
+	require.NoError(t, store.Put(ctx, domain.RenditionPlaylistKey("media-123", "720p"), strings.NewReader(playlist)))
+	require.NoError(t, store.Put(ctx, domain.RenditionKeyPrefix("media-123")+"720p/segment_0000.ts", strings.NewReader("segment")))
+
+	renditions := &fakeRenditionRepository{renditions: []*domain.MediaRendition{
+		{MediaID: "media-123", Name: "720p", Width: 1280, Height: 720, BitrateKbps: 2928, Status: domain.RenditionReady,
+			FileKey: domain.RenditionFileKey("media-123", "720p", "mp4"), PlaylistKey: domain.RenditionPlaylistKey("media-123", "720p")},
+		{MediaID: "media-123", Name: "480p", Width: 854, Height: 480, BitrateKbps: 1496, Status: domain.RenditionReady,
+			FileKey: domain.RenditionFileKey("media-123", "480p", "mp4"), PlaylistKey: domain.RenditionPlaylistKey("media-123", "480p")},
+		{MediaID: "media-123", Name: "1080p", Width: 1920, Height: 1080, Status: domain.RenditionSkipped},
+	}}
+	return NewStreamService(mediaRepo, renditions, store, drmService, StreamOptions{})
+}
+
+func newTestStreamMedia() *domain.Media {
+	return &domain.Media{ID: "media-123", Title: "Episode", Type: domain.TypeVideo, Status: domain.StatusReady, Duration: 12}
+}
+
+func TestStreamService_GetManifest_HLSMasterPlaylist(t *testing.T) {
+	service := newTestStreamService(t, newTestStreamMedia())
+
+	manifest, err := service.GetManifest(context.Background(), "media-123", domain.Viewer{}, domain.StreamRequest{Format: domain.StreamFormatHLS})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.StreamFormatHLS, manifest.Format)
+	assert.Contains(t, manifest.Body, "stream?format=hls&rendition=720p\n")
+	assert.Contains(t, manifest.Body, "stream?format=hls&rendition=480p\n")
+	assert.NotContains(t, manifest.Body, "1080p")
+	assert.Less(t, strings.Index(manifest.Body, "720p"), strings.Index(manifest.Body, "480p"))
+}
+
+func TestStreamService_GetManifest_SignsSegments(t *testing.T) {
+	// Given
+	service := newTestStreamService(t, newTestStreamMedia())
+
+	// When
+	manifest, err := service.GetManifest(context.Background(), "media-123", domain.Viewer{}, domain.StreamRequest{Format: domain.StreamFormatHLS, Rendition: "720p"})
+
+	// Then segments link to signed download URLs, later ones valid for longer
+	require.NoError(t, err)
+	var urls []*url.URL
+	for _, line := range strings.Split(manifest.Body, "\n") {
+		if strings.HasPrefix(line, "https://") {
+			u, err := url.Parse(line)
+			require.NoError(t, err)
+			urls = append(urls, u)
+		}
+	}
+	require.Len(t, urls, 2)
+	assert.Equal(t, "/files/derived/media-123/renditions/720p/segment_0000.ts", urls[0].Path)
+	first, _ := strconv.ParseInt(urls[0].Query().Get("expires"), 10, 64)
+	second, _ := strconv.ParseInt(urls[1].Query().Get("expires"), 10, 64)
+	assert.Equal(t, manifest.ExpiresAt.Unix(), first)
+	assert.Equal(t, first+6, second)
+
+	// And the service serves them
+	asset, err := service.OpenFile(context.Background(), strings.TrimPrefix(urls[0].Path, "/files/"), "", first, urls[0].Query().Get("signature"))
+	require.NoError(t, err)
+	defer asset.Body.Close()
+	body, _ := io.ReadAll(asset.Body)
+	assert.Equal(t, "segment", string(body))
+	assert.Equal(t, "video/mp2t", asset.ContentType)
+
+	_, err = service.OpenFile(context.Background(), strings.TrimPrefix(urls[1].Path, "/files/"), "", first, urls[1].Query().Get("signature"))
+	assert.ErrorIs(t, err, domain.ErrInvalidDownloadURL)
+}
+
+func TestStreamService_GetManifest_DASH(t *testing.T) {
+	service := newTestStreamService(t, newTestStreamMedia())
+
+	manifest, err := service.GetManifest(context.Background(), "media-123", domain.Viewer{}, domain.StreamRequest{Format: domain.StreamFormatDASH, Rendition: "480p"})
+
+	require.NoError(t, err)
+	assert.Contains(t, manifest.Body, `<Representation id="480p"`)
+	assert.Contains(t, manifest.Body, "https://cms.test/files/derived/media-123/renditions/480p.mp4?expires=")
+	assert.NotContains(t, manifest.Body, "720p")
+}
+
+func TestStreamService_GetManifest_CuePoints(t *testing.T) {
+	// Given a video with a pre-roll and a mid-roll after the first segment
+	media := newTestStreamMedia()
+	media.CuePoints = []domain.CuePoint{{Position: domain.CuePointPreroll}, {Position: domain.CuePointMidroll, OffsetSeconds: 6, MaxDurationSeconds: 30}}
+	service := newTestStreamService(t, media)
+
+	// When
+	hls, err := service.GetManifest(context.Background(), "media-123", domain.Viewer{}, domain.StreamRequest{Format: domain.StreamFormatHLS, Rendition: "720p"})
+	require.NoError(t, err)
+	dash, err := service.GetManifest(context.Background(), "media-123", domain.Viewer{}, domain.StreamRequest{Format: domain.StreamFormatDASH})
+	require.NoError(t, err)
+
+	// Then both manifests mark the breaks for ad insertion
+	preroll := strings.Index(hls.Body, "#EXT-X-CUE-OUT\n#EXT-X-CUE-IN\n")
+	midroll := strings.Index(hls.Body, "#EXT-X-CUE-OUT:DURATION=30\n#EXT-X-CUE-IN\n")
+	require.NotEqual(t, -1, preroll)
+	require.NotEqual(t, -1, midroll)
+	assert.Less(t, preroll, strings.Index(hls.Body, "segment_0000.ts"))
+	assert.Less(t, strings.Index(hls.Body, "segment_0000.ts"), midroll)
+	assert.Less(t, midroll, strings.Index(hls.Body, "segment_0001.ts"))
+
+	assert.Contains(t, dash.Body, `<EventStream schemeIdUri="`+domain.DASHCuePointScheme+`" timescale="1">`)
+	assert.Contains(t, dash.Body, `<Event id="1" presentationTime="0">preroll</Event>`)
+	assert.Contains(t, dash.Body, `<Event id="2" presentationTime="6" duration="30">midroll</Event>`)
+}
+
+func TestStreamService_GetManifest_Errors(t *testing.T) {
+	processing := newTestStreamMedia()
+	processing.Status = domain.StatusProcessing
+
+	tests := []struct {
+		name        string
+		media       *domain.Media
+		mediaID     string
+		req         domain.StreamRequest
+		expectedErr error
+		errorCode   string
+	}{
+		{name: "missing media", media: newTestStreamMedia(), mediaID: "missing", req: domain.StreamRequest{Format: domain.StreamFormatHLS}, expectedErr: domain.ErrMediaNotFound},
+		{name: "unknown rendition", media: newTestStreamMedia(), mediaID: "media-123", req: domain.StreamRequest{Format: domain.StreamFormatHLS, Rendition: "4k"}, expectedErr: domain.ErrRenditionNotFound},
+		{name: "skipped rendition", media: newTestStreamMedia(), mediaID: "media-123", req: domain.StreamRequest{Format: domain.StreamFormatDASH, Rendition: "1080p"}, expectedErr: domain.ErrRenditionNotFound},
+		{name: "no renditions", media: &domain.Media{ID: "other", Type: domain.TypeVideo, Status: domain.StatusReady}, mediaID: "other", req: domain.StreamRequest{Format: domain.StreamFormatHLS}, expectedErr: domain.ErrStreamNotFound},
+		{name: "not ready", media: processing, mediaID: "media-123", req: domain.StreamRequest{Format: domain.StreamFormatHLS}, errorCode: "MEDIA_NOT_READY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestStreamService(t, tt.media)
+
+			manifest, err := service.GetManifest(context.Background(), tt.mediaID, domain.Viewer{}, tt.req)
+
+			assert.Nil(t, manifest)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+			if tt.errorCode != "" {
+				var businessErr *domain.BusinessError
+				require.ErrorAs(t, err, &businessErr)
+				assert.Equal(t, tt.errorCode, businessErr.Code)
+			}
+		})
+	}
+}
+
+func TestStreamService_GetDownloadURL(t *testing.T) {
+	// Given an uploaded file
+	media := newTestStreamMedia()
+	media.FilePath = domain.UploadPathPrefix + "media-123.mp4"
+	service := newTestStreamService(t, media)
+	store := service.(*streamService).storage
+	require.NoError(t, store.Put(context.Background(), "media-123.mp4", strings.NewReader("video")))
+
+	// When
+	download, err := service.GetDownloadURL(context.Background(), "media-123", domain.Viewer{}, "Episode 1")
+
+	// Then the URL saves it under the filename until it expires
+	require.NoError(t, err)
+	assert.Equal(t, "Episode 1.mp4", download.Filename)
+	u, err := url.Parse(download.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/files/media-123.mp4", u.Path)
+	expires, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	assert.Equal(t, download.ExpiresAt.Unix(), expires)
+	assert.WithinDuration(t, time.Now().Add(domain.DefaultDownloadURLTTL), download.ExpiresAt, time.Minute)
+
+	asset, err := service.OpenFile(context.Background(), "media-123.mp4", u.Query().Get("filename"), expires, u.Query().Get("signature"))
+	require.NoError(t, err)
+	defer asset.Body.Close()
+	assert.Equal(t, "Episode 1.mp4", asset.Filename)
+	assert.Equal(t, "video/mp4", asset.ContentType)
+
+	// And the filename cannot be changed
+	_, err = service.OpenFile(context.Background(), "media-123.mp4", "other.mp4", expires, u.Query().Get("signature"))
+	assert.ErrorIs(t, err, domain.ErrInvalidDownloadURL)
+	_, err = service.OpenFile(context.Background(), "media-123.mp4", "", expires, u.Query().Get("signature"))
+	assert.ErrorIs(t, err, domain.ErrInvalidDownloadURL)
+}
+
+func TestStreamService_GetDownloadURL_Errors(t *testing.T) {
+	uploaded := newTestStreamMedia()
+	uploaded.FilePath = domain.UploadPathPrefix + "media-123.mp4"
+	private := newTestStreamMedia()
+	private.FilePath = uploaded.FilePath
+	private.OwnerID = "owner"
+	private.Visibility = domain.VisibilityPrivate
+
+	tests := []struct {
+		name        string
+		media       *domain.Media
+		filename    string
+		expectedErr error
+		errorCode   string
+	}{
+		{name: "private media", media: private, expectedErr: domain.ErrMediaNotFound},
+		{name: "no uploaded file", media: newTestStreamMedia(), expectedErr: domain.ErrFileNotFound},
+		{name: "invalid filename", media: uploaded, filename: "/../", errorCode: "INVALID_FILENAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestStreamService(t, tt.media)
+
+			download, err := service.GetDownloadURL(context.Background(), "media-123", domain.Viewer{UserID: "someone-else"}, tt.filename)
+
+			assert.Nil(t, download)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+			if tt.errorCode != "" {
+				var businessErr *domain.BusinessError
+				require.ErrorAs(t, err, &businessErr)
+				assert.Equal(t, tt.errorCode, businessErr.Code)
+			}
+		})
+	}
+}
+
+func TestStreamService_ProtectedMedia(t *testing.T) {
+	// Given a premium video with a rendition encrypted with its content key and
+	// one left from before it was protected
+	keyID := "00112233445566778899aabbccddeeff"
+	media := newTestStreamMedia()
+	media.AccessTier = domain.AccessTierPremium
+	media.FilePath = domain.UploadPathPrefix + "media-123.mp4"
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(context.Background(), media))
+	keyRepo := new(MockContentKeyRepository)
+	keyRepo.On("GetByMediaID", mock.Anything, "media-123").Return(&domain.ContentKey{MediaID: "media-123", KeyID: keyID}, nil)
+	drmService, err := NewDRMService(keyRepo, testDRMOptions())
+	require.NoError(t, err)
+	store, err := storage.NewPresigningLocalStorage(storage.NewLocalStorage(t.TempDir()), "https://cms.test", []byte("signing-key"))
+	require.NoError(t, err)
+	renditions := &fakeRenditionRepository{renditions: []*domain.MediaRendition{
+		{MediaID: "media-123", Name: "720p", Width: 1280, Height: 720, BitrateKbps: 2928, Status: domain.RenditionReady,
+			FileKey: domain.RenditionFileKey("media-123", "720p", "mp4"), KeyID: keyID},
+		{MediaID: "media-123", Name: "480p", Width: 854, Height: 480, BitrateKbps: 1496, Status: domain.RenditionReady,
+			FileKey: domain.RenditionFileKey("media-123", "480p", "mp4"), PlaylistKey: domain.RenditionPlaylistKey("media-123", "480p")},
+	}}
+	service := NewStreamService(mediaRepo, renditions, store, drmService, StreamOptions{})
+	viewer := domain.Viewer{UserID: "u1", Tier: domain.SubscriptionPremium}
+
+	// When
+	dash, err := service.GetManifest(context.Background(), "media-123", viewer, domain.StreamRequest{Format: domain.StreamFormatDASH})
+
+	// Then only the encrypted rendition is streamed, announcing its key to Widevine
+	require.NoError(t, err)
+	assert.Contains(t, dash.Body, `<Representation id="720p"`)
+	assert.NotContains(t, dash.Body, "480p")
+	assert.Contains(t, dash.Body, `xmlns:cenc="urn:mpeg:cenc:2013"`)
+	assert.Contains(t, dash.Body, `<ContentProtection schemeIdUri="urn:mpeg:dash:mp4protection:2011" value="cenc" cenc:default_KID="00112233-4455-6677-8899-aabbccddeeff">`)
+	assert.Contains(t, dash.Body, `<ContentProtection schemeIdUri="urn:uuid:`+domain.WidevineSystemID+`" value="Widevine">`)
+
+	// And neither clear HLS segments nor the clear upload are served
+	_, err = service.GetManifest(context.Background(), "media-123", viewer, domain.StreamRequest{Format: domain.StreamFormatHLS, Rendition: "480p"})
+	var businessErr *domain.BusinessError
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "DRM_REQUIRES_DASH", businessErr.Code)
+
+	_, err = service.GetDownloadURL(context.Background(), "media-123", viewer, "")
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "DRM_REQUIRED", businessErr.Code)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"thamaniyah/internal/repository"
+)
+
+// SuggestionRefresher periodically recomputes the precomputed search suggestions
+type SuggestionRefresher interface {
+	// Run refreshes the suggestions periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// suggestionRefresher implements SuggestionRefresher interface
+type suggestionRefresher struct {
+	suggestionRepo repository.SuggestionRepository
+	interval       time.Duration
+}
+
+// NewSuggestionRefresher creates a new suggestion refresher
+func NewSuggestionRefresher(suggestionRepo repository.SuggestionRepository, interval time.Duration) SuggestionRefresher {
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	return &suggestionRefresher{
+		suggestionRepo: suggestionRepo,
+		interval:       interval,
+	}
+}
+
+// Run refreshes the suggestions periodically until ctx is cancelled
+func (r *suggestionRefresher) Run(ctx context.Context) {
+	for {
+		if err := r.suggestionRepo.RefreshSuggestions(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Suggestion refresh failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval):
+		}
+	}
+}
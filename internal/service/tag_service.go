@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/id3"
+	"thamaniyah/pkg/storage"
+)
+
+// maxArtworkSize bounds the episode art written back into downloads
+const maxArtworkSize = 5 * 1024 * 1024
+
+// TagExtractor reads the tags embedded in uploaded files
+type TagExtractor interface {
+	// ExtractTags fills the empty tag fields of media from its uploaded file
+	ExtractTags(ctx context.Context, media *domain.Media) error
+}
+
+// TagService manages the tags embedded in media files
+type TagService interface {
+	TagExtractor
+
+	// GetArtwork returns the episode art extracted from a media item
+	GetArtwork(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.FileAsset, error)
+
+	// Download returns the uploaded file of a media item. Podcast MP3 files get their
+	// ID3 tags rewritten from the media record when write-back is enabled.
+	Download(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.FileAsset, error)
+}
+
+// tagService implements TagService interface
+type tagService struct {
+	mediaRepo repository.MediaRepository
+	storage   storage.Storage
+	writeBack bool
+}
+
+// NewTagService creates a new tag service
+func NewTagService(mediaRepo repository.MediaRepository, store storage.Storage, writeBack bool) TagService {
+	return &tagService{
+		mediaRepo: mediaRepo,
+		storage:   store,
+		writeBack: writeBack,
+	}
+}
+
+// ExtractTags fills the empty tag fields of media from the ID3 tag of its uploaded file
+func (s *tagService) ExtractTags(ctx context.Context, media *domain.Media) error {
+	// Only podcast files carry ID3 tags
+	if media.Type != domain.TypePodcast {
+		return nil
+	}
+
+	object, err := s.storage.Get(ctx, media.StorageKey())
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer object.Body.Close()
+
+	tag, err := id3.Read(object.Body)
+	if errors.Is(err, id3.ErrNoTag) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read ID3 tag: %w", err)
+	}
+
+	extracted := domain.MediaTags{
+		Artist:     tag.Artist,
+		Album:      tag.Album,
+		RecordedAt: domain.ParseRecordingDate(tag.RecordingDate),
+	}
+	if tag.Picture != nil && !media.Tags.HasArtwork() {
+		key := domain.DerivedArtworkKey(media.ID, tag.Picture.MIMEType)
+		if err := s.storage.Put(ctx, key, bytes.NewReader(tag.Picture.Data)); err != nil {
+			return fmt.Errorf("failed to store artwork: %w", err)
+		}
+		extracted.ArtworkKey = key
+	}
+
+	media.Tags.Merge(extracted)
+	return nil
+}
+
+// GetArtwork returns the episode art extracted from a media item
+func (s *tagService) GetArtwork(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.FileAsset, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !media.Tags.HasArtwork() {
+		return nil, domain.ErrArtworkNotFound
+	}
+
+	object, err := s.storage.Get(ctx, media.Tags.ArtworkKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, domain.ErrArtworkNotFound
+		}
+		return nil, fmt.Errorf("failed to open artwork: %w", err)
+	}
+
+	return &domain.FileAsset{
+		Filename:    filepath.Base(media.Tags.ArtworkKey),
+		ContentType: contentTypeOf(media.Tags.ArtworkKey),
+		Size:        object.Size,
+		Body:        object.Body,
+	}, nil
+}
+
+// Download returns the uploaded file of a media item
+func (s *tagService) Download(ctx context.Context, mediaID string, viewer domain.Viewer) (*domain.FileAsset, error) {
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !media.IsProcessed() {
+		return nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for download")
+	}
+	if err := media.CheckPlayback(viewer); err != nil {
+		return nil, err
+	}
+
+	object, err := s.storage.Get(ctx, media.StorageKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+
+	asset := &domain.FileAsset{
+		Filename:    media.ID + filepath.Ext(media.FilePath),
+		ContentType: contentTypeOf(media.FilePath),
+		Size:        object.Size,
+		Body:        object.Body,
+	}
+
+	if !s.writeBack || media.Type != domain.TypePodcast || !strings.EqualFold(filepath.Ext(media.FilePath), ".mp3") {
+		return asset, nil
+	}
+
+	// Replace the embedded tag with one built from the (possibly corrected) media record
+	encoded := id3.Encode(s.buildTag(ctx, media))
+	audio, skipped, err := id3.Strip(object.Body)
+	if err != nil {
+		object.Body.Close()
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	asset.Size = int64(len(encoded)) + object.Size - skipped
+	asset.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(encoded), audio),
+		Closer: object.Body,
+	}
+	return asset, nil
+}
+
+// buildTag builds the ID3 tag of a media item, attaching its episode art when available
+func (s *tagService) buildTag(ctx context.Context, media *domain.Media) *id3.Tag {
+	tag := &id3.Tag{
+		Title:         media.Title,
+		Artist:        media.Tags.Artist,
+		Album:         media.Tags.Album,
+		RecordingDate: domain.FormatRecordingDate(media.Tags.RecordedAt),
+	}
+	if !media.Tags.HasArtwork() {
+		return tag
+	}
+
+	object, err := s.storage.Get(ctx, media.Tags.ArtworkKey)
+	if err != nil {
+		return tag
+	}
+	defer object.Body.Close()
+
+	if object.Size > maxArtworkSize {
+		return tag
+	}
+	data, err := io.ReadAll(object.Body)
+	if err != nil {
+		return tag
+	}
+	tag.Picture = &id3.Picture{
+		MIMEType: contentTypeOf(media.Tags.ArtworkKey),
+		Type:     id3.PictureFrontCover,
+		Data:     data,
+	}
+	return tag
+}
+
+// mediaContentTypes covers the upload formats missing from the standard MIME table
+var mediaContentTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".mp4":  "video/mp4",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+}
+
+// contentTypeOf guesses the MIME type of a file from its extension
+func contentTypeOf(path string) string {
+	if contentType, ok := mediaContentTypes[strings.ToLower(filepath.Ext(path))]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// readCloser streams from Reader and closes Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/id3"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// taggedMP3 returns an upload made of an ID3 tag followed by audio frames
+func taggedMP3(tag *id3.Tag, audio string) []byte {
+	return append(id3.Encode(tag), audio...)
+}
+
+// id3v23Tag builds an ID3v2.3 tag with a UTF-16 artist frame and a year frame
+func id3v23Tag(artist, year string) []byte {
+	artistFrame := []byte{1, 0xFF, 0xFE}
+	for _, r := range artist {
+		artistFrame = append(artistFrame, byte(r), 0)
+	}
+	yearFrame := append([]byte{0}, year...)
+
+	var frames bytes.Buffer
+	for _, frame := range []struct {
+		id   string
+		data []byte
+	}{{"TPE1", artistFrame}, {"TYER", yearFrame}} {
+		frames.WriteString(frame.id)
+		frames.Write([]byte{0, 0, 0, byte(len(frame.data)), 0, 0})
+		frames.Write(frame.data)
+	}
+
+	tag := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, byte(frames.Len())}
+	return append(tag, frames.Bytes()...)
+}
+
+func TestTagService_ExtractTags(t *testing.T) {
+	recordedAt := time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC)
+	recordedYear := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		media           *domain.Media
+		upload          []byte
+		expectedTags    domain.MediaTags
+		expectedArtwork bool
+	}{
+		{
+			name:   "extracts ID3 tags and artwork",
+			media:  readyPodcast(),
+			upload: taggedMP3(&id3.Tag{Artist: "Host", Album: "Season 1", RecordingDate: "2025-08-27", Picture: &id3.Picture{MIMEType: "image/png", Type: id3.PictureFrontCover, Data: []byte("png")}}, "audio"),
+			expectedTags: domain.MediaTags{
+				Artist:     "Host",
+				Album:      "Season 1",
+				RecordedAt: &recordedAt,
+				ArtworkKey: "derived/media-123/artwork.png",
+			},
+			expectedArtwork: true,
+		},
+		{
+			name: "keeps editor corrections",
+			media: func() *domain.Media {
+				media := readyPodcast()
+				media.Tags.Artist = "Corrected Host"
+				return media
+			}(),
+			upload:       taggedMP3(&id3.Tag{Artist: "Host", Album: "Season 1"}, "audio"),
+			expectedTags: domain.MediaTags{Artist: "Corrected Host", Album: "Season 1"},
+		},
+		{
+			name:         "reads ID3v2.3 UTF-16 frames",
+			media:        readyPodcast(),
+			upload:       append(id3v23Tag("Host", "2025"), "audio"...),
+			expectedTags: domain.MediaTags{Artist: "Host", RecordedAt: &recordedYear},
+		},
+		{
+			name:   "file without tag",
+			media:  readyPodcast(),
+			upload: []byte("audio"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			store := storage.NewLocalStorage(t.TempDir())
+			require.NoError(t, store.Put(context.Background(), tt.media.StorageKey(), bytes.NewReader(tt.upload)))
+			service := NewTagService(new(MockMediaRepository), store, true)
+
+			// When
+			err := service.ExtractTags(context.Background(), tt.media)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTags, tt.media.Tags)
+			exists, _ := store.Exists(context.Background(), "derived/media-123/artwork.png")
+			assert.Equal(t, tt.expectedArtwork, exists)
+		})
+	}
+}
+
+func TestTagService_Download(t *testing.T) {
+	recordedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		writeBack    bool
+		expectedTag  *id3.Tag
+		expectedBody string
+	}{
+		{
+			name:        "rewrites tags from the media record",
+			writeBack:   true,
+			expectedTag: &id3.Tag{Title: "Episode 1", Artist: "Corrected Host", Album: "Season 1", RecordingDate: "2024-05-01"},
+		},
+		{
+			name:         "serves the uploaded file when write-back is disabled",
+			writeBack:    false,
+			expectedBody: string(taggedMP3(&id3.Tag{Artist: "Host"}, "audio")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			media := readyPodcast()
+			media.Title = "Episode 1"
+			media.Tags = domain.MediaTags{Artist: "Corrected Host", Album: "Season 1", RecordedAt: &recordedAt}
+			store := storage.NewLocalStorage(t.TempDir())
+			require.NoError(t, store.Put(context.Background(), media.StorageKey(), bytes.NewReader(taggedMP3(&id3.Tag{Artist: "Host"}, "audio"))))
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+			service := NewTagService(mockRepo, store, tt.writeBack)
+
+			// When
+			asset, err := service.Download(context.Background(), "media-123", domain.Viewer{})
+
+			// Then
+			require.NoError(t, err)
+			defer asset.Body.Close()
+			body, err := io.ReadAll(asset.Body)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(body)), asset.Size)
+			assert.Equal(t, "audio/mpeg", asset.ContentType)
+			assert.Equal(t, "media-123.mp3", asset.Filename)
+
+			if tt.expectedTag != nil {
+				tag, err := id3.Read(bytes.NewReader(body))
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedTag, tag)
+				assert.True(t, strings.HasSuffix(string(body), "audio"))
+			} else {
+				assert.Equal(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestTagService_Download_Premium(t *testing.T) {
+	// Given a premium episode
+	media := readyPodcast()
+	media.AccessTier = domain.AccessTierPremium
+	store := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, store.Put(context.Background(), media.StorageKey(), bytes.NewReader([]byte("audio"))))
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	service := NewTagService(mockRepo, store, false)
+
+	// When
+	_, err := service.Download(context.Background(), "media-123", domain.Viewer{UserID: "u1", Tier: domain.SubscriptionFree})
+
+	// Then only premium subscribers download it
+	assert.ErrorIs(t, err, domain.ErrNotEntitled)
+	asset, err := service.Download(context.Background(), "media-123", domain.Viewer{UserID: "u1", Tier: domain.SubscriptionPremium})
+	require.NoError(t, err)
+	asset.Body.Close()
+}
+
+func TestTagService_GetArtwork_NoArtwork(t *testing.T) {
+	// Given
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(readyPodcast(), nil)
+	service := NewTagService(mockRepo, storage.NewLocalStorage(t.TempDir()), true)
+
+	// When
+	asset, err := service.GetArtwork(context.Background(), "media-123", domain.Viewer{})
+
+	// Then
+	assert.ErrorIs(t, err, domain.ErrArtworkNotFound)
+	assert.Nil(t, asset)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+)
+
+// TaskType identifies a kind of processing task
+type TaskType string
+
+const (
+	TaskMetadata  TaskType = "metadata"
+	TaskThumbnail TaskType = "thumbnail"
+	TaskTranscode TaskType = "transcode"
+	TaskAnalysis  TaskType = "analysis"
+)
+
+// ffmpegTasks lists the task types that spawn FFmpeg processes
+var ffmpegTasks = map[TaskType]bool{
+	TaskThumbnail: true,
+	TaskTranscode: true,
+	TaskAnalysis:  true,
+}
+
+// TaskLimits configures how many tasks may run at once. Zero means unlimited.
+type TaskLimits struct {
+	Concurrency     map[TaskType]int
+	FFmpegProcesses int
+}
+
+// TaskLimiter bounds concurrent processing tasks per task type and the
+// number of FFmpeg processes across all task types.
+// A nil *TaskLimiter does not limit anything.
+type TaskLimiter struct {
+	tasks  map[TaskType]chan struct{}
+	ffmpeg chan struct{}
+}
+
+// NewTaskLimiter creates a task limiter from the given limits
+func NewTaskLimiter(limits TaskLimits) *TaskLimiter {
+	limiter := &TaskLimiter{
+		tasks: make(map[TaskType]chan struct{}),
+	}
+
+	for task, concurrency := range limits.Concurrency {
+		if concurrency > 0 {
+			limiter.tasks[task] = make(chan struct{}, concurrency)
+		}
+	}
+	if limits.FFmpegProcesses > 0 {
+		limiter.ffmpeg = make(chan struct{}, limits.FFmpegProcesses)
+	}
+
+	return limiter
+}
+
+// Acquire waits for a slot to run a task and returns the function releasing it
+func (l *TaskLimiter) Acquire(ctx context.Context, task TaskType) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	var held []chan struct{}
+	release := func() {
+		for _, slot := range held {
+			<-slot
+		}
+	}
+
+	slots := []chan struct{}{l.tasks[task]}
+	if ffmpegTasks[task] {
+		slots = append(slots, l.ffmpeg)
+	}
+
+	// Always take the task slot before the FFmpeg slot to avoid lock-order deadlocks
+	for _, slot := range slots {
+		if slot == nil {
+			continue
+		}
+		select {
+		case slot <- struct{}{}:
+			held = append(held, slot)
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
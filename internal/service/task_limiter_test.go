@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskLimiter_Acquire(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      TaskLimits
+		held        []TaskType
+		task        TaskType
+		expectBlock bool
+	}{
+		{
+			name:   "unlimited task type",
+			limits: TaskLimits{Concurrency: map[TaskType]int{TaskTranscode: 1}},
+			held:   []TaskType{TaskMetadata, TaskMetadata},
+			task:   TaskMetadata,
+		},
+		{
+			name:        "task concurrency exhausted",
+			limits:      TaskLimits{Concurrency: map[TaskType]int{TaskTranscode: 1}},
+			held:        []TaskType{TaskTranscode},
+			task:        TaskTranscode,
+			expectBlock: true,
+		},
+		{
+			name: "global ffmpeg limit shared between task types",
+			limits: TaskLimits{
+				Concurrency:     map[TaskType]int{TaskThumbnail: 8, TaskTranscode: 2},
+				FFmpegProcesses: 1,
+			},
+			held:        []TaskType{TaskTranscode},
+			task:        TaskThumbnail,
+			expectBlock: true,
+		},
+		{
+			name:   "ffmpeg limit does not apply to metadata",
+			limits: TaskLimits{FFmpegProcesses: 1},
+			held:   []TaskType{TaskTranscode},
+			task:   TaskMetadata,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			limiter := NewTaskLimiter(tt.limits)
+			for _, task := range tt.held {
+				_, err := limiter.Acquire(context.Background(), task)
+				assert.NoError(t, err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			// When
+			release, err := limiter.Acquire(ctx, tt.task)
+
+			// Then
+			if tt.expectBlock {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.Nil(t, release)
+			} else {
+				assert.NoError(t, err)
+				release()
+			}
+		})
+	}
+}
+
+func TestTaskLimiter_ReleaseFreesSlots(t *testing.T) {
+	// Given
+	limiter := NewTaskLimiter(TaskLimits{
+		Concurrency:     map[TaskType]int{TaskTranscode: 1},
+		FFmpegProcesses: 1,
+	})
+	release, err := limiter.Acquire(context.Background(), TaskTranscode)
+	assert.NoError(t, err)
+
+	// When
+	release()
+
+	// Then
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	release, err = limiter.Acquire(ctx, TaskThumbnail)
+	assert.NoError(t, err)
+	release()
+}
+
+func TestTaskLimiter_Nil(t *testing.T) {
+	var limiter *TaskLimiter
+
+	release, err := limiter.Acquire(context.Background(), TaskTranscode)
+
+	assert.NoError(t, err)
+	release()
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// ThumbnailRenderer renders a thumbnail of an image or a video
+type ThumbnailRenderer interface {
+	RenderThumbnail(ctx context.Context, src io.Reader, dst io.Writer, spec domain.ThumbnailSpec) error
+
+	// ExtractFrame writes the frame of a video shown at a position as a full size JPEG
+	ExtractFrame(ctx context.Context, src io.Reader, dst io.Writer, at time.Duration) error
+}
+
+// ThumbnailService serves media thumbnails resized on demand
+type ThumbnailService interface {
+	// GetThumbnail returns a thumbnail of a media item in the requested size and
+	// format, rendered from a frame of videos and the episode art of podcasts on
+	// the first request and served from the cache afterwards
+	GetThumbnail(ctx context.Context, mediaID string, viewer domain.Viewer, spec domain.ThumbnailSpec) (*domain.ThumbnailAsset, error)
+
+	// SetPoster picks the frame of a processed video at a position as its
+	// poster, which its thumbnails are rendered from from then on
+	SetPoster(ctx context.Context, mediaID string, at time.Duration) (*domain.Media, error)
+}
+
+// thumbnailService implements ThumbnailService interface
+type thumbnailService struct {
+	mediaRepo   repository.MediaRepository
+	storage     storage.Storage
+	renderer    ThumbnailRenderer
+	taskLimiter *TaskLimiter
+
+	mu       sync.Mutex
+	inflight map[string]chan struct{} // thumbnail key -> closed when its rendering ends
+}
+
+// NewThumbnailService creates a new thumbnail service
+func NewThumbnailService(mediaRepo repository.MediaRepository, store storage.Storage, renderer ThumbnailRenderer, taskLimiter *TaskLimiter) ThumbnailService {
+	return &thumbnailService{
+		mediaRepo:   mediaRepo,
+		storage:     store,
+		renderer:    renderer,
+		taskLimiter: taskLimiter,
+		inflight:    make(map[string]chan struct{}),
+	}
+}
+
+// GetThumbnail returns a thumbnail of a media item
+func (s *thumbnailService) GetThumbnail(ctx context.Context, mediaID string, viewer domain.Viewer, spec domain.ThumbnailSpec) (*domain.ThumbnailAsset, error) {
+	if !spec.Format.IsValid() {
+		return nil, domain.NewBusinessError("INVALID_FORMAT", "Thumbnail format must be one of: jpeg, png, webp")
+	}
+
+	media, err := GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceKey := media.Tags.ArtworkKey
+	if media.Type == domain.TypeVideo {
+		if !media.IsProcessed() {
+			return nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for playback")
+		}
+		sourceKey = media.StorageKey()
+		if media.Poster.IsSet() {
+			sourceKey = media.Poster.Key
+		}
+	} else if sourceKey == "" {
+		return nil, domain.ErrArtworkNotFound
+	}
+
+	key := domain.DerivedThumbnailKey(media.ID, spec)
+	cached := true
+
+	object, err := s.storage.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		cached = false
+		if err := s.render(ctx, sourceKey, spec, key); err != nil {
+			return nil, err
+		}
+		object, err = s.storage.Get(ctx, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open thumbnail: %w", err)
+	}
+
+	return &domain.ThumbnailAsset{
+		Format:      spec.Format,
+		ContentType: spec.Format.ContentType(),
+		Size:        object.Size,
+		Cached:      cached,
+		Body:        object.Body,
+	}, nil
+}
+
+// SetPoster picks the frame of a video at a position as its poster
+func (s *thumbnailService) SetPoster(ctx context.Context, mediaID string, at time.Duration) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+	if media.Type != domain.TypeVideo {
+		return nil, domain.NewBusinessError("INVALID_MEDIA_TYPE", "Only videos have poster frames")
+	}
+	if !media.IsProcessed() {
+		return nil, domain.NewBusinessError("MEDIA_NOT_READY", "Media is not ready for playback")
+	}
+	if at < 0 || (media.Duration > 0 && at >= time.Duration(media.Duration)*time.Second) {
+		return nil, domain.NewBusinessError("INVALID_TIMECODE",
+			fmt.Sprintf("Timecode must be within the %s of the video", domain.FormatTimecode(time.Duration(media.Duration)*time.Second)))
+	}
+
+	poster := domain.Poster{Key: domain.DerivedPosterKey(media.ID, at), Timecode: domain.FormatTimecode(at)}
+	if err := s.extractFrame(ctx, media.StorageKey(), at, poster.Key); err != nil {
+		return nil, err
+	}
+	if err := s.mediaRepo.UpdatePoster(ctx, media.ID, poster); err != nil {
+		return nil, fmt.Errorf("failed to update poster: %w", err)
+	}
+
+	// Thumbnails of the previous poster are rendered again on request
+	s.deleteThumbnails(ctx, media.ID)
+	if media.Poster.IsSet() && media.Poster.Key != poster.Key {
+		if err := s.storage.Delete(ctx, media.Poster.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to delete previous poster of media %s: %v", media.ID, err)
+		}
+	}
+
+	media.Poster = poster
+	return media, nil
+}
+
+// extractFrame stores the frame of the video stored under sourceKey at a position under key
+func (s *thumbnailService) extractFrame(ctx context.Context, sourceKey string, at time.Duration, key string) error {
+	release, err := s.taskLimiter.Acquire(ctx, TaskThumbnail)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	source, err := s.storage.Get(ctx, sourceKey)
+	if err != nil {
+		return fmt.Errorf("failed to open video: %w", err)
+	}
+	defer source.Body.Close()
+
+	// Extract to a temporary file so a failed run never leaves a partial poster in storage
+	tmp, err := os.CreateTemp("", "poster-*.jpg")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := s.renderer.ExtractFrame(ctx, source.Body, tmp, at); err != nil {
+		return fmt.Errorf("failed to extract frame: %w", err)
+	}
+	// Positions past the last frame produce no image
+	info, err := tmp.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read extracted frame: %w", err)
+	}
+	if info.Size() == 0 {
+		return domain.NewBusinessError("INVALID_TIMECODE", "The video has no frame at this timecode")
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read extracted frame: %w", err)
+	}
+
+	if err := s.storage.Put(ctx, key, tmp); err != nil {
+		return fmt.Errorf("failed to store poster: %w", err)
+	}
+	return nil
+}
+
+// deleteThumbnails removes the cached thumbnails of a media item. Failures are
+// only logged: stale thumbnails are collected with other unreferenced objects.
+func (s *thumbnailService) deleteThumbnails(ctx context.Context, mediaID string) {
+	var keys []string
+	err := s.storage.List(ctx, domain.DerivedThumbnailPrefix(mediaID), func(object storage.ObjectInfo) error {
+		keys = append(keys, object.Key)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to list thumbnails of media %s: %v", mediaID, err)
+		return
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to delete thumbnail %s: %v", key, err)
+		}
+	}
+}
+
+// render renders the thumbnail of the source stored under sourceKey into the
+// thumbnail stored under key. Concurrent requests for the same thumbnail wait
+// for a single rendering.
+func (s *thumbnailService) render(ctx context.Context, sourceKey string, spec domain.ThumbnailSpec, key string) error {
+	s.mu.Lock()
+	if done, ok := s.inflight[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	done := make(chan struct{})
+	s.inflight[key] = done
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	release, err := s.taskLimiter.Acquire(ctx, TaskThumbnail)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	source, err := s.storage.Get(ctx, sourceKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return domain.ErrArtworkNotFound
+		}
+		return fmt.Errorf("failed to open thumbnail source: %w", err)
+	}
+	defer source.Body.Close()
+
+	// Render to a temporary file so a failed run never leaves a partial thumbnail in storage
+	tmp, err := os.CreateTemp("", "thumbnail-*"+spec.Format.Extension())
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := s.renderer.RenderThumbnail(ctx, source.Body, tmp, spec); err != nil {
+		return fmt.Errorf("failed to render thumbnail: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read rendered thumbnail: %w", err)
+	}
+
+	if err := s.storage.Put(ctx, key, tmp); err != nil {
+		return fmt.Errorf("failed to cache thumbnail: %w", err)
+	}
+	return nil
+}
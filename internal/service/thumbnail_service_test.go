@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeThumbnailRenderer prefixes the source with the thumbnail size
+type fakeThumbnailRenderer struct {
+	calls int32
+	err   error
+}
+
+func (f *fakeThumbnailRenderer) RenderThumbnail(ctx context.Context, src io.Reader, dst io.Writer, spec domain.ThumbnailSpec) error {
+	atomic.AddInt32(&f.calls, 1)
+	if f.err != nil {
+		return f.err
+	}
+	if _, err := fmt.Fprintf(dst, "%dx%d:", spec.Width, spec.Height); err != nil {
+		return err
+	}
+	_, err := io.Copy(dst, src)
+	return err
+}
+
+func (f *fakeThumbnailRenderer) ExtractFrame(ctx context.Context, src io.Reader, dst io.Writer, at time.Duration) error {
+	atomic.AddInt32(&f.calls, 1)
+	if f.err != nil {
+		return f.err
+	}
+	_, err := fmt.Fprintf(dst, "frame at %s", at)
+	return err
+}
+
+func TestThumbnailService_GetThumbnail(t *testing.T) {
+	spec := domain.ThumbnailSpec{Width: 320, Format: domain.ThumbnailFormatWebP}
+	readyVideo := &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusReady, FilePath: domain.UploadPathPrefix + "media-123.mp4"}
+	podcastWithArt := readyPodcast()
+	podcastWithArt.Tags.ArtworkKey = "derived/media-123/artwork.jpg"
+
+	tests := []struct {
+		name          string
+		media         *domain.Media
+		renderErr     error
+		expectedErr   error
+		errorCode     string
+		expectedBody  string
+		expectedCalls int32
+	}{
+		{name: "frame of a video", media: readyVideo, expectedBody: "320x0:video", expectedCalls: 1},
+		{name: "art of a podcast", media: podcastWithArt, expectedBody: "320x0:artwork", expectedCalls: 1},
+		{name: "podcast without art", media: readyPodcast(), expectedErr: domain.ErrArtworkNotFound},
+		{
+			name:      "video not ready",
+			media:     &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusProcessing, FilePath: domain.UploadPathPrefix + "media-123.mp4"},
+			errorCode: "MEDIA_NOT_READY",
+		},
+		{
+			name:        "private media",
+			media:       &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusReady, Visibility: domain.VisibilityPrivate},
+			expectedErr: domain.ErrMediaNotFound,
+		},
+		{name: "render failure is not cached", media: readyVideo, renderErr: errors.New("ffmpeg failed"), expectedCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			store := storage.NewLocalStorage(t.TempDir())
+			require.NoError(t, store.Put(context.Background(), readyVideo.StorageKey(), strings.NewReader("video")))
+			require.NoError(t, store.Put(context.Background(), podcastWithArt.Tags.ArtworkKey, strings.NewReader("artwork")))
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(tt.media, nil)
+			renderer := &fakeThumbnailRenderer{err: tt.renderErr}
+			service := NewThumbnailService(mockRepo, store, renderer, nil)
+
+			// When
+			asset, err := service.GetThumbnail(context.Background(), "media-123", domain.Viewer{}, spec)
+
+			// Then
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&renderer.calls))
+			if tt.expectedBody == "" {
+				require.Error(t, err)
+				assert.Nil(t, asset)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+				if tt.errorCode != "" {
+					var businessErr *domain.BusinessError
+					require.ErrorAs(t, err, &businessErr)
+					assert.Equal(t, tt.errorCode, businessErr.Code)
+				}
+				exists, _ := store.Exists(context.Background(), domain.DerivedThumbnailKey("media-123", spec))
+				assert.False(t, exists)
+				return
+			}
+			require.NoError(t, err)
+			defer asset.Body.Close()
+			body, _ := io.ReadAll(asset.Body)
+			assert.Equal(t, tt.expectedBody, string(body))
+			assert.Equal(t, "image/webp", asset.ContentType)
+			assert.False(t, asset.Cached)
+		})
+	}
+}
+
+func TestThumbnailService_GetThumbnail_ServesCachedSizes(t *testing.T) {
+	// Given
+	store := storage.NewLocalStorage(t.TempDir())
+	media := &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusReady, FilePath: domain.UploadPathPrefix + "media-123.mp4"}
+	require.NoError(t, store.Put(context.Background(), media.StorageKey(), strings.NewReader("video")))
+	mockRepo := new(MockMediaRepository)
+	mockRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	renderer := &fakeThumbnailRenderer{}
+	service := NewThumbnailService(mockRepo, store, renderer, nil)
+	small := domain.ThumbnailSpec{Width: 160, Format: domain.ThumbnailFormatJPEG}
+
+	first, err := service.GetThumbnail(context.Background(), "media-123", domain.Viewer{}, small)
+	require.NoError(t, err)
+	first.Body.Close()
+
+	// When the same size is requested again, and another size
+	second, err := service.GetThumbnail(context.Background(), "media-123", domain.Viewer{}, small)
+	require.NoError(t, err)
+	defer second.Body.Close()
+	large, err := service.GetThumbnail(context.Background(), "media-123", domain.Viewer{}, domain.ThumbnailSpec{Width: 640, Height: 360, Format: domain.ThumbnailFormatJPEG})
+	require.NoError(t, err)
+	defer large.Body.Close()
+
+	// Then the cached size is served and only the new size is rendered
+	body, _ := io.ReadAll(second.Body)
+	assert.Equal(t, "160x0:video", string(body))
+	assert.True(t, second.Cached)
+	assert.False(t, large.Cached)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&renderer.calls))
+}
+
+func TestThumbnailService_SetPoster(t *testing.T) {
+	// Given a video with a cached thumbnail of the automatic frame
+	ctx := context.Background()
+	store := storage.NewLocalStorage(t.TempDir())
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	media := &domain.Media{ID: "media-123", Title: "Episode", Type: domain.TypeVideo, Status: domain.StatusReady, Duration: 600, FilePath: domain.UploadPathPrefix + "media-123.mp4"}
+	require.NoError(t, mediaRepo.Create(ctx, media))
+	require.NoError(t, store.Put(ctx, media.StorageKey(), strings.NewReader("video")))
+	service := NewThumbnailService(mediaRepo, store, &fakeThumbnailRenderer{}, nil)
+	spec := domain.ThumbnailSpec{Width: 320, Format: domain.ThumbnailFormatJPEG}
+	thumbnail, err := service.GetThumbnail(ctx, "media-123", domain.Viewer{}, spec)
+	require.NoError(t, err)
+	thumbnail.Body.Close()
+
+	// When
+	updated, err := service.SetPoster(ctx, "media-123", 83*time.Second)
+
+	// Then thumbnails are rendered from the frame
+	require.NoError(t, err)
+	assert.Equal(t, domain.Poster{Key: "derived/media-123/poster-83000.jpg", Timecode: "00:01:23"}, updated.Poster)
+	stored, err := mediaRepo.GetByID(ctx, "media-123")
+	require.NoError(t, err)
+	assert.Equal(t, updated.Poster, stored.Poster)
+
+	thumbnail, err = service.GetThumbnail(ctx, "media-123", domain.Viewer{}, spec)
+	require.NoError(t, err)
+	defer thumbnail.Body.Close()
+	body, _ := io.ReadAll(thumbnail.Body)
+	assert.Equal(t, "320x0:frame at 1m23s", string(body))
+	assert.False(t, thumbnail.Cached)
+
+	// And a new poster replaces the previous frame
+	_, err = service.SetPoster(ctx, "media-123", 90*time.Second)
+	require.NoError(t, err)
+	exists, err := store.Exists(ctx, "derived/media-123/poster-83000.jpg")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestThumbnailService_SetPoster_Errors(t *testing.T) {
+	readyVideo := &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusReady, Duration: 60, FilePath: domain.UploadPathPrefix + "media-123.mp4"}
+
+	tests := []struct {
+		name        string
+		media       *domain.Media
+		at          time.Duration
+		expectedErr error
+		errorCode   string
+	}{
+		{name: "podcast", media: readyPodcast(), errorCode: "INVALID_MEDIA_TYPE"},
+		{name: "video not ready", media: &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusProcessing}, errorCode: "MEDIA_NOT_READY"},
+		{name: "deleted video", media: &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusDeleted}, expectedErr: domain.ErrMediaNotFound},
+		{name: "past the end", media: readyVideo, at: time.Minute, errorCode: "INVALID_TIMECODE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMediaRepository)
+			mockRepo.On("GetByID", mock.Anything, "media-123").Return(tt.media, nil)
+			renderer := &fakeThumbnailRenderer{}
+			service := NewThumbnailService(mockRepo, storage.NewLocalStorage(t.TempDir()), renderer, nil)
+
+			media, err := service.SetPoster(context.Background(), "media-123", tt.at)
+
+			assert.Nil(t, media)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+			if tt.errorCode != "" {
+				var businessErr *domain.BusinessError
+				require.ErrorAs(t, err, &businessErr)
+				assert.Equal(t, tt.errorCode, businessErr.Code)
+			}
+			assert.Zero(t, atomic.LoadInt32(&renderer.calls))
+			mockRepo.AssertNotCalled(t, "UpdatePoster", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
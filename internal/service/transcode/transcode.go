@@ -0,0 +1,389 @@
+// Package transcode produces the renditions of uploaded videos: a file per
+// rendition of the transcode preset of the media, packaged as HLS segments
+// listed in a master playlist when players can stream them adaptively. The
+// renditions of DRM protected videos are MP4 files encrypted with their content
+// key instead, streamed as DASH.
+package transcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/internal/service"
+	"thamaniyah/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// Encoder transcodes and segments videos on local files
+type Encoder interface {
+	// TranscodeVideo transcodes the video at input into a rendition written to output,
+	// overlaying the watermark when it is not nil
+	TranscodeVideo(ctx context.Context, input, output string, rendition domain.Rendition, watermark *domain.Watermark) error
+
+	// PackageHLS splits the video at input into HLS segments next to the media playlist
+	PackageHLS(ctx context.Context, input, playlist string, segmentSeconds int) error
+
+	// EncryptCENC copies the MP4 video at input to output, encrypted with the
+	// cenc scheme under the hex encoded keyID
+	EncryptCENC(ctx context.Context, input, output, keyID string, key []byte) error
+}
+
+// KeyProvider supplies the content keys the renditions of protected media are encrypted with
+type KeyProvider interface {
+	// Protects reports whether media only plays with a DRM license
+	Protects(media *domain.Media) bool
+
+	// ContentKey returns the content key of a media item, generating it on first use
+	ContentKey(ctx context.Context, mediaID string) (keyID string, key []byte, err error)
+}
+
+// contentKey is the key the renditions of a protected media item are encrypted with
+type contentKey struct {
+	id  string // hex encoded
+	key []byte
+}
+
+// PresetResolver returns the transcode preset of media
+type PresetResolver interface {
+	ResolvePreset(ctx context.Context, media *domain.Media) (*domain.TranscodePreset, error)
+}
+
+// WatermarkPolicy returns the watermark overlaid on the renditions of media, or nil
+type WatermarkPolicy interface {
+	WatermarkFor(media *domain.Media) *domain.Watermark
+}
+
+// Options configures transcoding; zero values use the defaults
+type Options struct {
+	WorkDir        string // where uploads are downloaded and renditions written, the system temp dir when empty
+	SegmentSeconds int    // target duration of HLS segments
+}
+
+// Service transcodes videos into renditions and tracks their state
+type Service interface {
+	// Transcode produces the renditions of a processed video, replacing earlier
+	// ones. Other media is left alone. Renditions failing to transcode are
+	// recorded as failed without stopping the others.
+	Transcode(ctx context.Context, mediaID string) error
+
+	// GetRenditions returns the renditions of a media item, largest first
+	GetRenditions(ctx context.Context, mediaID string, viewer domain.Viewer) ([]*domain.MediaRendition, error)
+}
+
+// transcodeService implements Service interface
+type transcodeService struct {
+	mediaRepo     repository.MediaRepository
+	renditionRepo repository.RenditionRepository
+	presets       PresetResolver
+	watermarks    WatermarkPolicy
+	keys          KeyProvider
+	storage       storage.Storage
+	encoder       Encoder
+	taskLimiter   *service.TaskLimiter
+	options       Options
+}
+
+// NewService creates a new transcoding service. Videos whose media type has no
+// default preset are transcoded to domain.DefaultVideoRenditions, and every
+// encoding takes a transcode slot of the task limiter. A nil keys encrypts no
+// renditions.
+func NewService(mediaRepo repository.MediaRepository, renditionRepo repository.RenditionRepository, presets PresetResolver, watermarks WatermarkPolicy, keys KeyProvider, store storage.Storage, encoder Encoder, taskLimiter *service.TaskLimiter, options Options) Service {
+	if options.SegmentSeconds <= 0 {
+		options.SegmentSeconds = domain.DefaultHLSSegmentSeconds
+	}
+
+	return &transcodeService{
+		mediaRepo:     mediaRepo,
+		renditionRepo: renditionRepo,
+		presets:       presets,
+		watermarks:    watermarks,
+		keys:          keys,
+		storage:       store,
+		encoder:       encoder,
+		taskLimiter:   taskLimiter,
+		options:       options,
+	}
+}
+
+// Transcode produces the renditions of a processed video
+func (s *transcodeService) Transcode(ctx context.Context, mediaID string) error {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	if media.Type != domain.TypeVideo || media.Status != domain.StatusReady {
+		return nil
+	}
+
+	profiles := domain.DefaultVideoRenditions
+	preset, err := s.presets.ResolvePreset(ctx, media)
+	if err == nil {
+		profiles = preset.Renditions
+	} else if !errors.Is(err, domain.ErrTranscodePresetNotFound) {
+		return fmt.Errorf("failed to resolve transcode preset: %w", err)
+	}
+	watermark := s.watermarks.WatermarkFor(media)
+
+	var key *contentKey
+	if s.keys != nil && s.keys.Protects(media) {
+		key = &contentKey{}
+		key.id, key.key, err = s.keys.ContentKey(ctx, media.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get content key: %w", err)
+		}
+	}
+
+	renditions, profileOf := s.newRenditions(media, profiles, key != nil)
+	if len(renditions) == 0 {
+		return nil
+	}
+	if err := s.renditionRepo.Replace(ctx, media.ID, renditions); err != nil {
+		return fmt.Errorf("failed to record renditions: %w", err)
+	}
+
+	workDir, err := os.MkdirTemp(s.options.WorkDir, "transcode-"+media.ID+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	input := filepath.Join(workDir, "source."+media.Format)
+	if err := s.download(ctx, media.StorageKey(), input); err != nil {
+		s.failAll(ctx, renditions, err)
+		return err
+	}
+
+	failed := 0
+	for _, rendition := range renditions {
+		if rendition.Status != domain.RenditionPending {
+			continue
+		}
+		if err := s.transcodeRendition(ctx, media, rendition, profileOf[rendition.ID], watermark, key, input, workDir); err != nil {
+			log.Printf("Failed to transcode rendition %s of media %s: %v", rendition.Name, media.ID, err)
+			failed++
+		}
+	}
+
+	if err := s.writeMasterPlaylist(ctx, media.ID, renditions); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d renditions failed to transcode", failed, len(renditions))
+	}
+	return nil
+}
+
+// GetRenditions returns the renditions of a media item
+func (s *transcodeService) GetRenditions(ctx context.Context, mediaID string, viewer domain.Viewer) ([]*domain.MediaRendition, error) {
+	if _, err := service.GetVisibleMedia(ctx, s.mediaRepo, mediaID, viewer); err != nil {
+		return nil, err
+	}
+
+	return s.renditionRepo.ListByMediaID(ctx, mediaID)
+}
+
+// newRenditions returns the pending video renditions of the profiles, and the
+// profile of each by rendition ID. Renditions larger than the upload are
+// skipped, as are WebM renditions of protected media, which cannot be encrypted.
+func (s *transcodeService) newRenditions(media *domain.Media, profiles []domain.Rendition, protected bool) ([]*domain.MediaRendition, map[string]domain.Rendition) {
+	var renditions []*domain.MediaRendition
+	profileOf := make(map[string]domain.Rendition)
+	for _, profile := range profiles {
+		if profile.IsAudioOnly() {
+			continue
+		}
+		rendition := domain.NewMediaRendition(uuid.New().String(), media.ID, profile)
+		if media.Height > 0 && profile.Height > media.Height {
+			rendition.Status = domain.RenditionSkipped
+		}
+		if protected && profile.Container == "webm" {
+			rendition.Status = domain.RenditionSkipped
+			rendition.Error = "WebM renditions of protected media are not encrypted"
+		}
+		renditions = append(renditions, rendition)
+		profileOf[rendition.ID] = profile
+	}
+	return renditions, profileOf
+}
+
+// transcodeRendition encodes a rendition, packages it as HLS when players can
+// stream it adaptively, or encrypts it with key when not nil, and stores the outputs
+func (s *transcodeService) transcodeRendition(ctx context.Context, media *domain.Media, rendition *domain.MediaRendition, profile domain.Rendition, watermark *domain.Watermark, key *contentKey, input, workDir string) error {
+	rendition.Status = domain.RenditionProcessing
+	s.update(ctx, rendition)
+
+	err := s.encode(ctx, media, rendition, profile, watermark, key, input, workDir)
+	if err != nil {
+		rendition.Status = domain.RenditionFailed
+		rendition.Error = err.Error()
+	} else {
+		rendition.Status = domain.RenditionReady
+	}
+	s.update(ctx, rendition)
+	return err
+}
+
+// encode writes the file and HLS segments of a rendition to storage, or only
+// its file encrypted with key when not nil
+func (s *transcodeService) encode(ctx context.Context, media *domain.Media, rendition *domain.MediaRendition, profile domain.Rendition, watermark *domain.Watermark, key *contentKey, input, workDir string) error {
+	release, err := s.taskLimiter.Acquire(ctx, service.TaskTranscode)
+	if err != nil {
+		return fmt.Errorf("failed to acquire transcode slot: %w", err)
+	}
+	defer release()
+
+	// Renditions only streamed as HLS are encoded to MP4 first
+	container := profile.Container
+	if container == "hls" {
+		container = "mp4"
+	}
+	output := filepath.Join(workDir, rendition.Name+"."+container)
+	if err := s.encoder.TranscodeVideo(ctx, input, output, profile, watermark); err != nil {
+		return err
+	}
+
+	// Clear segments of protected media would play without a license
+	if key != nil {
+		encrypted := filepath.Join(workDir, rendition.Name+".cenc."+container)
+		if err := s.encoder.EncryptCENC(ctx, output, encrypted, key.id, key.key); err != nil {
+			return err
+		}
+		fileKey := domain.RenditionFileKey(media.ID, rendition.Name, container)
+		size, err := s.upload(ctx, encrypted, fileKey)
+		if err != nil {
+			return err
+		}
+		rendition.FileKey = fileKey
+		rendition.KeyID = key.id
+		rendition.Size += size
+		return nil
+	}
+
+	if profile.Container != "hls" {
+		key := domain.RenditionFileKey(media.ID, rendition.Name, container)
+		size, err := s.upload(ctx, output, key)
+		if err != nil {
+			return err
+		}
+		rendition.FileKey = key
+		rendition.Size += size
+	}
+
+	if profile.IsHLSCompatible() {
+		playlistKey := domain.RenditionPlaylistKey(media.ID, rendition.Name)
+		dir := filepath.Join(workDir, rendition.Name)
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create HLS directory: %w", err)
+		}
+		if err := s.encoder.PackageHLS(ctx, output, filepath.Join(dir, "index.m3u8"), s.options.SegmentSeconds); err != nil {
+			return err
+		}
+		size, err := s.uploadDir(ctx, dir, strings.TrimSuffix(playlistKey, "index.m3u8"))
+		if err != nil {
+			return err
+		}
+		rendition.PlaylistKey = playlistKey
+		rendition.Size += size
+	}
+
+	return nil
+}
+
+// writeMasterPlaylist stores the master playlist of the renditions packaged as HLS
+func (s *transcodeService) writeMasterPlaylist(ctx context.Context, mediaID string, renditions []*domain.MediaRendition) error {
+	playlist := domain.HLSMasterPlaylist(renditions, (*domain.MediaRendition).RelativePlaylistURI)
+	if playlist == "" {
+		return nil
+	}
+	if err := s.storage.Put(ctx, domain.HLSMasterPlaylistKey(mediaID), strings.NewReader(playlist)); err != nil {
+		return fmt.Errorf("failed to store HLS master playlist: %w", err)
+	}
+	return nil
+}
+
+// download copies a stored object to a local file
+func (s *transcodeService) download(ctx context.Context, key, path string) error {
+	object, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to open upload: %w", err)
+	}
+	defer object.Body.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create local copy of upload: %w", err)
+	}
+	if _, err := io.Copy(file, object.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to download upload: %w", err)
+	}
+	return file.Close()
+}
+
+// upload stores a local file under key and returns its size
+func (s *transcodeService) upload(ctx context.Context, path, key string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if err := s.storage.Put(ctx, key, file); err != nil {
+		return 0, fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return info.Size(), nil
+}
+
+// uploadDir stores the files of a local directory under prefix and returns their total size
+func (s *transcodeService) uploadDir(ctx context.Context, dir, prefix string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		size, err := s.upload(ctx, filepath.Join(dir, entry.Name()), prefix+entry.Name())
+		if err != nil {
+			return total, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// failAll records every pending rendition as failed for the same cause
+func (s *transcodeService) failAll(ctx context.Context, renditions []*domain.MediaRendition, cause error) {
+	for _, rendition := range renditions {
+		if rendition.Status != domain.RenditionPending {
+			continue
+		}
+		rendition.Status = domain.RenditionFailed
+		rendition.Error = cause.Error()
+		s.update(ctx, rendition)
+	}
+}
+
+// update records the state of a rendition. Failures are only logged: the
+// outputs are stored and transcoding again records them.
+func (s *transcodeService) update(ctx context.Context, rendition *domain.MediaRendition) {
+	if err := s.renditionRepo.Update(ctx, rendition); err != nil {
+		log.Printf("Failed to record rendition %s of media %s as %s: %v", rendition.Name, rendition.MediaID, rendition.Status, err)
+	}
+}
@@ -0,0 +1,353 @@
+package transcode
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/internal/service"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEncoder writes the name of the rendition instead of transcoding
+type fakeEncoder struct {
+	failing    map[string]bool // renditions failing to transcode
+	mu         sync.Mutex
+	watermarks []*domain.Watermark
+}
+
+func (e *fakeEncoder) TranscodeVideo(ctx context.Context, input, output string, rendition domain.Rendition, watermark *domain.Watermark) error {
+	e.mu.Lock()
+	e.watermarks = append(e.watermarks, watermark)
+	e.mu.Unlock()
+	if e.failing[rendition.Name] {
+		return errors.New("ffmpeg failed")
+	}
+	return os.WriteFile(output, []byte(rendition.Name), 0o644)
+}
+
+func (e *fakeEncoder) EncryptCENC(ctx context.Context, input, output, keyID string, key []byte) error {
+	clear, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, append([]byte("cenc:"+keyID+":"), clear...), 0o644)
+}
+
+func (e *fakeEncoder) PackageHLS(ctx context.Context, input, playlist string, segmentSeconds int) error {
+	if err := os.WriteFile(playlist, []byte("#EXTM3U\n"), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(filepath.Dir(playlist), "segment_0000.ts"), []byte("ts"), 0o644)
+}
+
+// fakePresets resolves every media to the same preset, or to none when nil
+type fakePresets struct {
+	preset *domain.TranscodePreset
+}
+
+func (p fakePresets) ResolvePreset(ctx context.Context, media *domain.Media) (*domain.TranscodePreset, error) {
+	if p.preset == nil {
+		return nil, domain.ErrTranscodePresetNotFound
+	}
+	return p.preset, nil
+}
+
+// fakeWatermarks watermarks every media with the same watermark
+type fakeWatermarks struct {
+	watermark *domain.Watermark
+}
+
+func (p fakeWatermarks) WatermarkFor(media *domain.Media) *domain.Watermark {
+	return p.watermark
+}
+
+// fakeKeys protects every media with the same content key, or none when empty
+type fakeKeys struct {
+	keyID string
+}
+
+func (k fakeKeys) Protects(media *domain.Media) bool {
+	return k.keyID != ""
+}
+
+func (k fakeKeys) ContentKey(ctx context.Context, mediaID string) (string, []byte, error) {
+	return k.keyID, []byte("0123456789abcdef"), nil
+}
+
+// memoryRenditionRepository keeps renditions in memory
+type memoryRenditionRepository struct {
+	mu         sync.Mutex
+	renditions map[string]domain.MediaRendition
+}
+
+func newMemoryRenditionRepository() *memoryRenditionRepository {
+	return &memoryRenditionRepository{renditions: make(map[string]domain.MediaRendition)}
+}
+
+func (r *memoryRenditionRepository) Replace(ctx context.Context, mediaID string, renditions []*domain.MediaRendition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, rendition := range r.renditions {
+		if rendition.MediaID == mediaID {
+			delete(r.renditions, id)
+		}
+	}
+	for _, rendition := range renditions {
+		r.renditions[rendition.ID] = *rendition
+	}
+	return nil
+}
+
+func (r *memoryRenditionRepository) Update(ctx context.Context, rendition *domain.MediaRendition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.renditions[rendition.ID]; !ok {
+		return domain.ErrRenditionNotFound
+	}
+	r.renditions[rendition.ID] = *rendition
+	return nil
+}
+
+func (r *memoryRenditionRepository) ListByMediaID(ctx context.Context, mediaID string) ([]*domain.MediaRendition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var renditions []*domain.MediaRendition
+	for _, rendition := range r.renditions {
+		if rendition.MediaID == mediaID {
+			rendition := rendition
+			renditions = append(renditions, &rendition)
+		}
+	}
+	sort.Slice(renditions, func(i, j int) bool { return renditions[i].Height > renditions[j].Height })
+	return renditions, nil
+}
+
+var _ repository.RenditionRepository = (*memoryRenditionRepository)(nil)
+
+type testEnv struct {
+	mediaRepo     repository.MediaRepository
+	renditionRepo *memoryRenditionRepository
+	store         *storage.LocalStorage
+	encoder       *fakeEncoder
+}
+
+func newTestEnv(t *testing.T, media *domain.Media) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		mediaRepo:     repository.NewInMemoryMediaRepository(),
+		renditionRepo: newMemoryRenditionRepository(),
+		store:         storage.NewLocalStorage(t.TempDir()),
+		encoder:       &fakeEncoder{},
+	}
+	require.NoError(t, env.mediaRepo.Create(context.Background(), media))
+	require.NoError(t, env.store.Put(context.Background(), media.StorageKey(), strings.NewReader("source")))
+	return env
+}
+
+func (env *testEnv) service(t *testing.T, presets PresetResolver, watermarks WatermarkPolicy) Service {
+	return env.protectedService(t, presets, watermarks, fakeKeys{})
+}
+
+func (env *testEnv) protectedService(t *testing.T, presets PresetResolver, watermarks WatermarkPolicy, keys KeyProvider) Service {
+	return NewService(env.mediaRepo, env.renditionRepo, presets, watermarks, keys, env.store, env.encoder, service.NewTaskLimiter(service.TaskLimits{}), Options{WorkDir: t.TempDir()})
+}
+
+func newTestVideo(height int) *domain.Media {
+	return &domain.Media{
+		ID:       "media-123",
+		Title:    "Episode",
+		Type:     domain.TypeVideo,
+		Status:   domain.StatusReady,
+		Format:   "mp4",
+		FilePath: domain.UploadPathPrefix + "media-123.mp4",
+		Height:   height,
+	}
+}
+
+func readObject(t *testing.T, store storage.Storage, key string) string {
+	t.Helper()
+	object, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer object.Body.Close()
+	body, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestService_Transcode_DefaultRenditions(t *testing.T) {
+	// Given a 720p upload of a media type without a default preset
+	env := newTestEnv(t, newTestVideo(720))
+	watermark := &domain.Watermark{LogoPath: "logo.png"}
+	svc := env.service(t, fakePresets{}, fakeWatermarks{watermark: watermark})
+
+	// When
+	require.NoError(t, svc.Transcode(context.Background(), "media-123"))
+
+	// Then 1080p is not upscaled and the others are stored with their HLS segments
+	renditions, err := svc.GetRenditions(context.Background(), "media-123", domain.Viewer{})
+	require.NoError(t, err)
+	require.Len(t, renditions, 3)
+
+	assert.Equal(t, "1080p", renditions[0].Name)
+	assert.Equal(t, domain.RenditionSkipped, renditions[0].Status)
+	assert.Empty(t, renditions[0].FileKey)
+
+	for _, rendition := range renditions[1:] {
+		assert.Equal(t, domain.RenditionReady, rendition.Status, rendition.Name)
+		assert.Equal(t, domain.RenditionFileKey("media-123", rendition.Name, "mp4"), rendition.FileKey)
+		assert.Equal(t, rendition.Name, readObject(t, env.store, rendition.FileKey))
+		assert.Equal(t, domain.RenditionPlaylistKey("media-123", rendition.Name), rendition.PlaylistKey)
+		exists, err := env.store.Exists(context.Background(), domain.RenditionKeyPrefix("media-123")+rendition.Name+"/segment_0000.ts")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, int64(len(rendition.Name)+len("#EXTM3U\n")+len("ts")), rendition.Size)
+	}
+
+	master := readObject(t, env.store, domain.HLSMasterPlaylistKey("media-123"))
+	assert.Contains(t, master, "720p/index.m3u8")
+	assert.Contains(t, master, "480p/index.m3u8")
+	assert.NotContains(t, master, "1080p")
+	assert.Equal(t, []*domain.Watermark{watermark, watermark}, env.encoder.watermarks)
+}
+
+func TestService_Transcode_PresetRenditions(t *testing.T) {
+	// Given a preset with an HLS only rendition, a WebM one and an audio only one
+	env := newTestEnv(t, newTestVideo(0))
+	preset := &domain.TranscodePreset{Renditions: []domain.Rendition{
+		{Name: "360p", Width: 640, Height: 360, VideoCodec: "h264", VideoBitrateKbps: 800, Container: "hls"},
+		{Name: "webm", Width: 640, Height: 360, VideoCodec: "vp9", VideoBitrateKbps: 700, Container: "webm"},
+		{Name: "audio", AudioCodec: "aac", AudioBitrateKbps: 128, Container: "mp4"},
+	}}
+	svc := env.service(t, fakePresets{preset: preset}, fakeWatermarks{})
+
+	// When
+	require.NoError(t, svc.Transcode(context.Background(), "media-123"))
+
+	// Then
+	renditions, err := svc.GetRenditions(context.Background(), "media-123", domain.Viewer{})
+	require.NoError(t, err)
+	require.Len(t, renditions, 2)
+	byName := map[string]*domain.MediaRendition{}
+	for _, rendition := range renditions {
+		assert.Equal(t, domain.RenditionReady, rendition.Status)
+		byName[rendition.Name] = rendition
+	}
+	assert.Empty(t, byName["360p"].FileKey, "only packaged as HLS")
+	assert.NotEmpty(t, byName["360p"].PlaylistKey)
+	assert.Equal(t, domain.RenditionFileKey("media-123", "webm", "webm"), byName["webm"].FileKey)
+	assert.Empty(t, byName["webm"].PlaylistKey)
+}
+
+func TestService_Transcode_EncryptsProtectedMedia(t *testing.T) {
+	// Given a protected video with an HLS only rendition and a WebM one
+	env := newTestEnv(t, newTestVideo(0))
+	preset := &domain.TranscodePreset{Renditions: []domain.Rendition{
+		{Name: "720p", Width: 1280, Height: 720, VideoCodec: "h264", VideoBitrateKbps: 2800, Container: "mp4"},
+		{Name: "360p", Width: 640, Height: 360, VideoCodec: "h264", VideoBitrateKbps: 800, Container: "hls"},
+		{Name: "webm", Width: 640, Height: 360, VideoCodec: "vp9", VideoBitrateKbps: 700, Container: "webm"},
+	}}
+	keyID := "00112233445566778899aabbccddeeff"
+	svc := env.protectedService(t, fakePresets{preset: preset}, fakeWatermarks{}, fakeKeys{keyID: keyID})
+
+	// When
+	require.NoError(t, svc.Transcode(context.Background(), "media-123"))
+
+	// Then only encrypted MP4 files are stored, without clear HLS segments
+	renditions, err := svc.GetRenditions(context.Background(), "media-123", domain.Viewer{})
+	require.NoError(t, err)
+	byName := map[string]*domain.MediaRendition{}
+	for _, rendition := range renditions {
+		byName[rendition.Name] = rendition
+	}
+	for _, name := range []string{"720p", "360p"} {
+		rendition := byName[name]
+		assert.Equal(t, domain.RenditionReady, rendition.Status, name)
+		assert.Equal(t, keyID, rendition.KeyID, name)
+		assert.Equal(t, domain.RenditionFileKey("media-123", name, "mp4"), rendition.FileKey, name)
+		assert.Equal(t, "cenc:"+keyID+":"+name, readObject(t, env.store, rendition.FileKey), name)
+		assert.Empty(t, rendition.PlaylistKey, name)
+	}
+	assert.Equal(t, domain.RenditionSkipped, byName["webm"].Status)
+	assert.Empty(t, byName["webm"].FileKey)
+
+	exists, err := env.store.Exists(context.Background(), domain.HLSMasterPlaylistKey("media-123"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestService_Transcode_RecordsFailedRenditions(t *testing.T) {
+	// Given
+	env := newTestEnv(t, newTestVideo(1080))
+	env.encoder.failing = map[string]bool{"720p": true}
+	svc := env.service(t, fakePresets{}, fakeWatermarks{})
+
+	// When
+	err := svc.Transcode(context.Background(), "media-123")
+
+	// Then the failure of one rendition does not stop the others
+	assert.Error(t, err)
+	renditions, err := svc.GetRenditions(context.Background(), "media-123", domain.Viewer{})
+	require.NoError(t, err)
+	statuses := map[string]domain.RenditionStatus{}
+	for _, rendition := range renditions {
+		statuses[rendition.Name] = rendition.Status
+	}
+	assert.Equal(t, map[string]domain.RenditionStatus{
+		"1080p": domain.RenditionReady,
+		"720p":  domain.RenditionFailed,
+		"480p":  domain.RenditionReady,
+	}, statuses)
+
+	master := readObject(t, env.store, domain.HLSMasterPlaylistKey("media-123"))
+	assert.NotContains(t, master, "720p")
+}
+
+func TestService_Transcode_SkipsOtherMedia(t *testing.T) {
+	tests := []struct {
+		name  string
+		media *domain.Media
+	}{
+		{name: "podcast", media: &domain.Media{ID: "media-123", Type: domain.TypePodcast, Status: domain.StatusReady, FilePath: domain.UploadPathPrefix + "media-123.mp3"}},
+		{name: "video in processing", media: &domain.Media{ID: "media-123", Type: domain.TypeVideo, Status: domain.StatusProcessing, FilePath: domain.UploadPathPrefix + "media-123.mp4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, tt.media)
+			svc := env.service(t, fakePresets{}, fakeWatermarks{})
+
+			require.NoError(t, svc.Transcode(context.Background(), "media-123"))
+
+			renditions, err := env.renditionRepo.ListByMediaID(context.Background(), "media-123")
+			require.NoError(t, err)
+			assert.Empty(t, renditions)
+			assert.Empty(t, env.encoder.watermarks)
+		})
+	}
+}
+
+func TestService_GetRenditions_HidesPrivateMedia(t *testing.T) {
+	media := newTestVideo(720)
+	media.OwnerID = "owner"
+	media.Visibility = domain.VisibilityPrivate
+	env := newTestEnv(t, media)
+	svc := env.service(t, fakePresets{}, fakeWatermarks{})
+
+	_, err := svc.GetRenditions(context.Background(), "media-123", domain.Viewer{UserID: "someone-else"})
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+
+	_, err = svc.GetRenditions(context.Background(), "missing", domain.Viewer{})
+	assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TranscodePresetService manages the rendition presets of the transcoding pipeline
+type TranscodePresetService interface {
+	// CreatePreset stores a new preset
+	CreatePreset(ctx context.Context, req *domain.TranscodePresetRequest) (*domain.TranscodePreset, error)
+
+	// GetPreset retrieves a preset by ID
+	GetPreset(ctx context.Context, id string) (*domain.TranscodePreset, error)
+
+	// ListPresets lists all presets
+	ListPresets(ctx context.Context) ([]*domain.TranscodePreset, error)
+
+	// UpdatePreset replaces a preset
+	UpdatePreset(ctx context.Context, id string, req *domain.TranscodePresetRequest) (*domain.TranscodePreset, error)
+
+	// DeletePreset removes a preset
+	DeletePreset(ctx context.Context, id string) error
+
+	// ResolvePreset returns the preset to transcode media with: the preset it references,
+	// or the default preset of its media type
+	ResolvePreset(ctx context.Context, media *domain.Media) (*domain.TranscodePreset, error)
+
+	// PlanTranscode returns the preset and watermark a media item is transcoded with
+	PlanTranscode(ctx context.Context, mediaID string) (*domain.TranscodePlan, error)
+}
+
+// transcodePresetService implements TranscodePresetService interface
+type transcodePresetService struct {
+	presetRepo      repository.TranscodePresetRepository
+	mediaRepo       repository.MediaRepository
+	watermarkPolicy *WatermarkPolicy
+}
+
+// NewTranscodePresetService creates a new transcode preset service.
+// When watermarkPolicy is nil, no video is watermarked.
+func NewTranscodePresetService(presetRepo repository.TranscodePresetRepository, mediaRepo repository.MediaRepository, watermarkPolicy *WatermarkPolicy) TranscodePresetService {
+	return &transcodePresetService{
+		presetRepo:      presetRepo,
+		mediaRepo:       mediaRepo,
+		watermarkPolicy: watermarkPolicy,
+	}
+}
+
+// CreatePreset stores a new preset
+func (s *transcodePresetService) CreatePreset(ctx context.Context, req *domain.TranscodePresetRequest) (*domain.TranscodePreset, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Transcode preset validation failed", errs.Error())
+	}
+
+	preset := &domain.TranscodePreset{ID: uuid.New().String()}
+	req.ApplyTo(preset)
+
+	if err := s.presetRepo.Create(ctx, preset); err != nil {
+		return nil, fmt.Errorf("failed to create transcode preset: %w", err)
+	}
+
+	return preset, nil
+}
+
+// GetPreset retrieves a preset by ID
+func (s *transcodePresetService) GetPreset(ctx context.Context, id string) (*domain.TranscodePreset, error) {
+	return s.presetRepo.GetByID(ctx, id)
+}
+
+// ListPresets lists all presets
+func (s *transcodePresetService) ListPresets(ctx context.Context) ([]*domain.TranscodePreset, error) {
+	return s.presetRepo.GetAll(ctx)
+}
+
+// UpdatePreset replaces a preset
+func (s *transcodePresetService) UpdatePreset(ctx context.Context, id string, req *domain.TranscodePresetRequest) (*domain.TranscodePreset, error) {
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_REQUEST", "Transcode preset validation failed", errs.Error())
+	}
+
+	preset, err := s.presetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(preset)
+	if err := s.presetRepo.Update(ctx, preset); err != nil {
+		return nil, fmt.Errorf("failed to update transcode preset: %w", err)
+	}
+
+	return preset, nil
+}
+
+// DeletePreset removes a preset
+func (s *transcodePresetService) DeletePreset(ctx context.Context, id string) error {
+	return s.presetRepo.Delete(ctx, id)
+}
+
+// ResolvePreset returns the preset to transcode media with
+func (s *transcodePresetService) ResolvePreset(ctx context.Context, media *domain.Media) (*domain.TranscodePreset, error) {
+	if media.TranscodePresetID != "" {
+		preset, err := s.presetRepo.GetByID(ctx, media.TranscodePresetID)
+		if err == nil {
+			return preset, nil
+		}
+		// A deleted preset falls back to the default of the media type
+		if !errors.Is(err, domain.ErrTranscodePresetNotFound) {
+			return nil, err
+		}
+	}
+
+	return s.presetRepo.GetDefault(ctx, media.Type)
+}
+
+// PlanTranscode returns the preset and watermark a media item is transcoded with
+func (s *transcodePresetService) PlanTranscode(ctx context.Context, mediaID string) (*domain.TranscodePlan, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	preset, err := s.ResolvePreset(ctx, media)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TranscodePlan{
+		MediaID:   media.ID,
+		Preset:    preset,
+		Watermark: s.watermarkPolicy.WatermarkFor(media),
+	}, nil
+}
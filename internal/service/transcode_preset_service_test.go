@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTranscodePresetRepository is a mock implementation of TranscodePresetRepository
+type MockTranscodePresetRepository struct {
+	mock.Mock
+}
+
+func (m *MockTranscodePresetRepository) Create(ctx context.Context, preset *domain.TranscodePreset) error {
+	args := m.Called(ctx, preset)
+	return args.Error(0)
+}
+
+func (m *MockTranscodePresetRepository) GetByID(ctx context.Context, id string) (*domain.TranscodePreset, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TranscodePreset), args.Error(1)
+}
+
+func (m *MockTranscodePresetRepository) GetAll(ctx context.Context) ([]*domain.TranscodePreset, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TranscodePreset), args.Error(1)
+}
+
+func (m *MockTranscodePresetRepository) GetDefault(ctx context.Context, mediaType domain.MediaType) (*domain.TranscodePreset, error) {
+	args := m.Called(ctx, mediaType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TranscodePreset), args.Error(1)
+}
+
+func (m *MockTranscodePresetRepository) Update(ctx context.Context, preset *domain.TranscodePreset) error {
+	args := m.Called(ctx, preset)
+	return args.Error(0)
+}
+
+func (m *MockTranscodePresetRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func validPresetRequest() *domain.TranscodePresetRequest {
+	return &domain.TranscodePresetRequest{
+		Name:      "podcast-standard",
+		MediaType: domain.TypePodcast,
+		Renditions: []domain.Rendition{
+			{Name: "aac-128", AudioCodec: "aac", AudioBitrateKbps: 128, Container: "mp4"},
+		},
+	}
+}
+
+func TestTranscodePresetService_CreatePreset(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *domain.TranscodePresetRequest
+		setupMocks  func(*MockTranscodePresetRepository)
+		expectError bool
+		errorCode   string
+	}{
+		{
+			name:    "creates preset",
+			request: validPresetRequest(),
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TranscodePreset")).Return(nil)
+			},
+		},
+		{
+			name:        "invalid preset",
+			request:     &domain.TranscodePresetRequest{Name: "empty", MediaType: domain.TypeVideo},
+			setupMocks:  func(repo *MockTranscodePresetRepository) {},
+			expectError: true,
+			errorCode:   "INVALID_REQUEST",
+		},
+		{
+			name:    "repository error",
+			request: validPresetRequest(),
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TranscodePreset")).Return(errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			repo := new(MockTranscodePresetRepository)
+			tt.setupMocks(repo)
+			service := NewTranscodePresetService(repo, new(MockMediaRepository), nil)
+
+			// When
+			preset, err := service.CreatePreset(context.Background(), tt.request)
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, preset)
+				if tt.errorCode != "" {
+					var businessErr *domain.BusinessError
+					if errors.As(err, &businessErr) {
+						assert.Equal(t, tt.errorCode, businessErr.Code)
+					}
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, preset.ID)
+				assert.Equal(t, "podcast-standard", preset.Name)
+				assert.Len(t, preset.Renditions, 1)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTranscodePresetService_UpdatePreset(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMocks  func(*MockTranscodePresetRepository)
+		expectedErr error
+	}{
+		{
+			name: "updates existing preset",
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("GetByID", mock.Anything, "preset-1").Return(&domain.TranscodePreset{ID: "preset-1", Name: "old"}, nil)
+				repo.On("Update", mock.Anything, mock.AnythingOfType("*domain.TranscodePreset")).Return(nil)
+			},
+		},
+		{
+			name: "preset not found",
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("GetByID", mock.Anything, "preset-1").Return(nil, domain.ErrTranscodePresetNotFound)
+			},
+			expectedErr: domain.ErrTranscodePresetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			repo := new(MockTranscodePresetRepository)
+			tt.setupMocks(repo)
+			service := NewTranscodePresetService(repo, new(MockMediaRepository), nil)
+
+			// When
+			preset, err := service.UpdatePreset(context.Background(), "preset-1", validPresetRequest())
+
+			// Then
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, preset)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "preset-1", preset.ID)
+				assert.Equal(t, "podcast-standard", preset.Name)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTranscodePresetService_ResolvePreset(t *testing.T) {
+	explicit := &domain.TranscodePreset{ID: "preset-1", MediaType: domain.TypeVideo}
+	fallback := &domain.TranscodePreset{ID: "default-video", MediaType: domain.TypeVideo, IsDefault: true}
+
+	tests := []struct {
+		name        string
+		media       *domain.Media
+		setupMocks  func(*MockTranscodePresetRepository)
+		expectedID  string
+		expectedErr error
+	}{
+		{
+			name:  "uses referenced preset",
+			media: &domain.Media{Type: domain.TypeVideo, TranscodePresetID: "preset-1"},
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("GetByID", mock.Anything, "preset-1").Return(explicit, nil)
+			},
+			expectedID: "preset-1",
+		},
+		{
+			name:  "falls back to default when referenced preset was deleted",
+			media: &domain.Media{Type: domain.TypeVideo, TranscodePresetID: "preset-1"},
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("GetByID", mock.Anything, "preset-1").Return(nil, domain.ErrTranscodePresetNotFound)
+				repo.On("GetDefault", mock.Anything, domain.TypeVideo).Return(fallback, nil)
+			},
+			expectedID: "default-video",
+		},
+		{
+			name:  "uses default without reference",
+			media: &domain.Media{Type: domain.TypeVideo},
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("GetDefault", mock.Anything, domain.TypeVideo).Return(fallback, nil)
+			},
+			expectedID: "default-video",
+		},
+		{
+			name:  "no default preset",
+			media: &domain.Media{Type: domain.TypePodcast},
+			setupMocks: func(repo *MockTranscodePresetRepository) {
+				repo.On("GetDefault", mock.Anything, domain.TypePodcast).Return(nil, domain.ErrTranscodePresetNotFound)
+			},
+			expectedErr: domain.ErrTranscodePresetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			repo := new(MockTranscodePresetRepository)
+			tt.setupMocks(repo)
+			service := NewTranscodePresetService(repo, new(MockMediaRepository), nil)
+
+			// When
+			preset, err := service.ResolvePreset(context.Background(), tt.media)
+
+			// Then
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedID, preset.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTranscodePresetService_PlanTranscode(t *testing.T) {
+	// Given
+	media := &domain.Media{ID: "media-123", Type: domain.TypeVideo, TenantID: "acme"}
+	preset := &domain.TranscodePreset{ID: "default-video", MediaType: domain.TypeVideo, IsDefault: true}
+	repo := new(MockTranscodePresetRepository)
+	repo.On("GetDefault", mock.Anything, domain.TypeVideo).Return(preset, nil)
+	mediaRepo := new(MockMediaRepository)
+	mediaRepo.On("GetByID", mock.Anything, "media-123").Return(media, nil)
+	policy, err := NewWatermarkPolicy(WatermarkOptions{
+		Tenants:   []string{"acme"},
+		Watermark: domain.Watermark{LogoPath: "/logos/{tenant}.png", Position: domain.WatermarkTopLeft, Opacity: 0.5},
+	})
+	assert.NoError(t, err)
+	service := NewTranscodePresetService(repo, mediaRepo, policy)
+
+	// When
+	plan, err := service.PlanTranscode(context.Background(), "media-123")
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "default-video", plan.Preset.ID)
+	if assert.NotNil(t, plan.Watermark) {
+		assert.Equal(t, "/logos/acme.png", plan.Watermark.LogoPath)
+	}
+	repo.AssertExpectations(t)
+	mediaRepo.AssertExpectations(t)
+}
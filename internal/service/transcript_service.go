@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TranscriptClassifier suggests labels and categories for media from its transcript
+type TranscriptClassifier interface {
+	Classify(ctx context.Context, transcript *domain.Transcript) ([]domain.MetadataSuggestion, error)
+}
+
+// keywordClassifier suggests the most mentioned keywords of a transcript as labels
+// and the categories whose keywords it mentions most
+type keywordClassifier struct {
+	categories map[string][]string
+}
+
+// NewKeywordClassifier creates a transcript classifier scoring categories by their
+// keywords, domain.DefaultCategoryKeywords when categories is empty
+func NewKeywordClassifier(categories map[string][]string) TranscriptClassifier {
+	if len(categories) == 0 {
+		categories = domain.DefaultCategoryKeywords
+	}
+
+	return &keywordClassifier{
+		categories: categories,
+	}
+}
+
+// Classify suggests labels and categories from the keywords of a transcript
+func (c *keywordClassifier) Classify(ctx context.Context, transcript *domain.Transcript) ([]domain.MetadataSuggestion, error) {
+	suggestions := domain.SuggestLabels(transcript.Text, domain.MaxSuggestedLabels)
+	return append(suggestions, domain.SuggestCategories(transcript.Text, c.categories, domain.MaxSuggestedCategories)...), nil
+}
+
+// TranscriptService manages the transcripts of media and the labels and categories suggested from them
+type TranscriptService interface {
+	// SetTranscript replaces the transcript of a media item and suggests labels and
+	// categories from it, replacing the earlier pending suggestions
+	SetTranscript(ctx context.Context, mediaID string, req *domain.TranscriptRequest) (*domain.Transcript, error)
+
+	// GetTranscript returns the transcript of a media item
+	GetTranscript(ctx context.Context, mediaID string) (*domain.Transcript, error)
+
+	// GetSuggestions returns the suggestions of a media item, the most confident first
+	GetSuggestions(ctx context.Context, mediaID string) ([]*domain.MetadataSuggestion, error)
+
+	// ListPendingSuggestions returns the suggestions waiting for review, the most confident first
+	ListPendingSuggestions(ctx context.Context, limit int) ([]*domain.MetadataSuggestion, error)
+
+	// AcceptSuggestion applies a pending suggestion to its media
+	AcceptSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error)
+
+	// DismissSuggestion closes a pending suggestion without applying it
+	DismissSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error)
+
+	// GetExplicitLanguage returns the explicit language found in the transcript of a media item
+	GetExplicitLanguage(ctx context.Context, mediaID string) (*domain.ExplicitLanguageReport, error)
+}
+
+// transcriptService implements TranscriptService interface
+type transcriptService struct {
+	transcriptRepo repository.TranscriptRepository
+	mediaRepo      repository.MediaRepository
+	publisher      EventPublisher
+	classifier     TranscriptClassifier
+	minConfidence  float64
+	explicitTerms  map[string]bool
+	now            func() time.Time
+}
+
+// NewTranscriptService creates a new transcript service. A nil classifier suggests
+// from keywords with the default categories; suggestions below minConfidence,
+// domain.DefaultSuggestionConfidence when 0, are not stored. Transcripts are
+// searched for explicitTerms, see domain.ParseExplicitTerms; when it is empty,
+// explicit language is not detected.
+func NewTranscriptService(transcriptRepo repository.TranscriptRepository, mediaRepo repository.MediaRepository, publisher EventPublisher, classifier TranscriptClassifier, minConfidence float64, explicitTerms map[string]bool) TranscriptService {
+	if publisher == nil {
+		publisher = NewLogEventPublisher()
+	}
+	if classifier == nil {
+		classifier = NewKeywordClassifier(nil)
+	}
+	if minConfidence <= 0 {
+		minConfidence = domain.DefaultSuggestionConfidence
+	}
+
+	return &transcriptService{
+		transcriptRepo: transcriptRepo,
+		mediaRepo:      mediaRepo,
+		publisher:      publisher,
+		classifier:     classifier,
+		minConfidence:  minConfidence,
+		explicitTerms:  explicitTerms,
+		now:            time.Now,
+	}
+}
+
+// SetTranscript replaces the transcript of a media item and suggests labels and categories from it
+func (s *transcriptService) SetTranscript(ctx context.Context, mediaID string, req *domain.TranscriptRequest) (*domain.Transcript, error) {
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cue timings are lost to normalizing, explicit language is located before
+	cues := domain.TranscriptCues(req.Text)
+	req.Normalize()
+	if errs := req.Validate(); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_TRANSCRIPT", "Transcript validation failed", errs.Error())
+	}
+
+	transcript := &domain.Transcript{
+		MediaID:   media.ID,
+		Language:  req.Language,
+		Text:      req.Text,
+		UpdatedAt: s.now().UTC(),
+	}
+	if err := s.transcriptRepo.Save(ctx, transcript); err != nil {
+		return nil, fmt.Errorf("failed to save transcript: %w", err)
+	}
+
+	// The transcript is kept when suggesting fails, suggestions only help editors
+	if err := s.suggest(ctx, media, transcript); err != nil {
+		log.Printf("Failed to suggest labels and categories for media %s: %v", media.ID, err)
+	}
+	if err := s.detectExplicitLanguage(ctx, media, cues); err != nil {
+		log.Printf("Failed to detect explicit language in media %s: %v", media.ID, err)
+	}
+
+	return transcript, nil
+}
+
+// GetTranscript returns the transcript of a media item
+func (s *transcriptService) GetTranscript(ctx context.Context, mediaID string) (*domain.Transcript, error) {
+	if _, err := s.getMedia(ctx, mediaID); err != nil {
+		return nil, err
+	}
+
+	return s.transcriptRepo.Get(ctx, mediaID)
+}
+
+// GetSuggestions returns the suggestions of a media item, the most confident first
+func (s *transcriptService) GetSuggestions(ctx context.Context, mediaID string) ([]*domain.MetadataSuggestion, error) {
+	if _, err := s.getMedia(ctx, mediaID); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.transcriptRepo.GetSuggestions(ctx, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+	if suggestions == nil {
+		suggestions = []*domain.MetadataSuggestion{}
+	}
+	return suggestions, nil
+}
+
+// ListPendingSuggestions returns the suggestions waiting for review, the most confident first
+func (s *transcriptService) ListPendingSuggestions(ctx context.Context, limit int) ([]*domain.MetadataSuggestion, error) {
+	if limit <= 0 {
+		limit = domain.DefaultSuggestionQueueLimit
+	}
+	limit = min(limit, domain.MaxSuggestionQueueLimit)
+
+	suggestions, err := s.transcriptRepo.ListPendingSuggestions(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending suggestions: %w", err)
+	}
+	if suggestions == nil {
+		suggestions = []*domain.MetadataSuggestion{}
+	}
+	return suggestions, nil
+}
+
+// AcceptSuggestion applies a pending suggestion to its media
+func (s *transcriptService) AcceptSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error) {
+	suggestion, err := s.getPendingSuggestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	media, err := s.getMedia(ctx, suggestion.MediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := suggestion.ApplyTo(media); errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_SUGGESTION", "Suggestion could not be accepted", errs.Error())
+	}
+	if err := s.mediaRepo.UpdateClassification(ctx, media.ID, media.Labels, media.Category); err != nil {
+		return nil, fmt.Errorf("failed to update classification: %w", err)
+	}
+	s.publishMediaUpdated(ctx, media.ID)
+
+	return s.resolve(ctx, suggestion, domain.SuggestionAccepted)
+}
+
+// DismissSuggestion closes a pending suggestion without applying it
+func (s *transcriptService) DismissSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error) {
+	suggestion, err := s.getPendingSuggestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolve(ctx, suggestion, domain.SuggestionDismissed)
+}
+
+// GetExplicitLanguage returns the explicit language found in the transcript of a media item
+func (s *transcriptService) GetExplicitLanguage(ctx context.Context, mediaID string) (*domain.ExplicitLanguageReport, error) {
+	media, err := s.getMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.transcriptRepo.GetExplicitMatches(ctx, media.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get explicit language matches: %w", err)
+	}
+	if matches == nil {
+		matches = []*domain.ExplicitLanguageMatch{}
+	}
+	return &domain.ExplicitLanguageReport{
+		MediaID:  media.ID,
+		Explicit: media.ContentRating.Explicit,
+		Matches:  matches,
+	}, nil
+}
+
+// detectExplicitLanguage stores the explicit terms found in the cues of a
+// transcript and flags the media as explicit when there are any. The flag is
+// never cleared here, editors lift it after reviewing the matches.
+func (s *transcriptService) detectExplicitLanguage(ctx context.Context, media *domain.Media, cues []domain.TranscriptCue) error {
+	if len(s.explicitTerms) == 0 {
+		return nil
+	}
+
+	now := s.now().UTC()
+	found := domain.FindExplicitLanguage(cues, s.explicitTerms)
+	matches := make([]*domain.ExplicitLanguageMatch, 0, len(found))
+	for _, match := range found {
+		match.ID = uuid.New().String()
+		match.MediaID = media.ID
+		match.CreatedAt = now
+		matches = append(matches, &match)
+	}
+	if err := s.transcriptRepo.ReplaceExplicitMatches(ctx, media.ID, matches); err != nil {
+		return fmt.Errorf("failed to save explicit language matches: %w", err)
+	}
+
+	if len(matches) == 0 || media.ContentRating.Explicit {
+		return nil
+	}
+	rating := media.ContentRating
+	rating.Explicit = true
+	if err := s.mediaRepo.UpdateContentRating(ctx, media.ID, rating); err != nil {
+		return fmt.Errorf("failed to flag media as explicit: %w", err)
+	}
+	media.ContentRating = rating
+	log.Printf("Flagged media %s as explicit, %d explicit words in its transcript", media.ID, len(matches))
+	s.publishMediaUpdated(ctx, media.ID)
+
+	return nil
+}
+
+// publishMediaUpdated announces a change of media so search indexes pick it up
+func (s *transcriptService) publishMediaUpdated(ctx context.Context, mediaID string) {
+	event := domain.NewEvent(domain.EventMediaUpdated, map[string]interface{}{
+		"media_id": mediaID,
+	})
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish %s of media %s: %v", domain.EventMediaUpdated, mediaID, err)
+	}
+}
+
+// suggest classifies the transcript of media and stores the confident suggestions
+// that are neither applied to the media already nor reviewed before
+func (s *transcriptService) suggest(ctx context.Context, media *domain.Media, transcript *domain.Transcript) error {
+	classified, err := s.classifier.Classify(ctx, transcript)
+	if err != nil {
+		return fmt.Errorf("failed to classify transcript: %w", err)
+	}
+	existing, err := s.transcriptRepo.GetSuggestions(ctx, media.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get suggestions: %w", err)
+	}
+	reviewed := make(map[string]bool, len(existing))
+	for _, suggestion := range existing {
+		if suggestion.Status != domain.SuggestionPending {
+			reviewed[string(suggestion.Kind)+":"+suggestion.Value] = true
+		}
+	}
+
+	now := s.now().UTC()
+	suggestions := make([]*domain.MetadataSuggestion, 0, len(classified))
+	for _, suggestion := range classified {
+		if suggestion.Confidence < s.minConfidence || suggestion.AppliedTo(media) ||
+			reviewed[string(suggestion.Kind)+":"+suggestion.Value] {
+			continue
+		}
+		suggestion.ID = uuid.New().String()
+		suggestion.MediaID = media.ID
+		suggestion.Status = domain.SuggestionPending
+		suggestion.CreatedAt = now
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	if err := s.transcriptRepo.ReplacePendingSuggestions(ctx, media.ID, suggestions); err != nil {
+		return fmt.Errorf("failed to save suggestions: %w", err)
+	}
+	return nil
+}
+
+// resolve closes a pending suggestion with a review decision
+func (s *transcriptService) resolve(ctx context.Context, suggestion *domain.MetadataSuggestion, status domain.MetadataSuggestionStatus) (*domain.MetadataSuggestion, error) {
+	now := s.now().UTC()
+	resolved, err := s.transcriptRepo.ResolveSuggestion(ctx, suggestion.ID, status, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve suggestion: %w", err)
+	}
+	if !resolved {
+		return nil, domain.ErrMetadataSuggestionResolved
+	}
+
+	suggestion.Status = status
+	suggestion.ResolvedAt = &now
+	return suggestion, nil
+}
+
+// getPendingSuggestion retrieves a suggestion waiting for review
+func (s *transcriptService) getPendingSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error) {
+	suggestion, err := s.transcriptRepo.GetSuggestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status != domain.SuggestionPending {
+		return nil, domain.ErrMetadataSuggestionResolved
+	}
+	return suggestion, nil
+}
+
+// getMedia retrieves media that is not deleted
+func (s *transcriptService) getMedia(ctx context.Context, mediaID string) (*domain.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == domain.StatusDeleted {
+		return nil, domain.ErrMediaNotFound
+	}
+	return media, nil
+}
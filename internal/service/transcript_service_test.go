@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTranscriptRepository keeps transcripts and suggestions in memory
+type memoryTranscriptRepository struct {
+	transcripts map[string]*domain.Transcript
+	suggestions []*domain.MetadataSuggestion
+	explicit    map[string][]*domain.ExplicitLanguageMatch
+}
+
+func newMemoryTranscriptRepository() *memoryTranscriptRepository {
+	return &memoryTranscriptRepository{
+		transcripts: make(map[string]*domain.Transcript),
+		explicit:    make(map[string][]*domain.ExplicitLanguageMatch),
+	}
+}
+
+func (r *memoryTranscriptRepository) Save(ctx context.Context, transcript *domain.Transcript) error {
+	stored := *transcript
+	r.transcripts[transcript.MediaID] = &stored
+	return nil
+}
+
+func (r *memoryTranscriptRepository) Get(ctx context.Context, mediaID string) (*domain.Transcript, error) {
+	transcript, ok := r.transcripts[mediaID]
+	if !ok {
+		return nil, domain.ErrTranscriptNotFound
+	}
+	return transcript, nil
+}
+
+func (r *memoryTranscriptRepository) ReplacePendingSuggestions(ctx context.Context, mediaID string, suggestions []*domain.MetadataSuggestion) error {
+	kept := r.suggestions[:0]
+	for _, suggestion := range r.suggestions {
+		if suggestion.MediaID != mediaID || suggestion.Status != domain.SuggestionPending {
+			kept = append(kept, suggestion)
+		}
+	}
+	r.suggestions = append(kept, suggestions...)
+	return nil
+}
+
+func (r *memoryTranscriptRepository) GetSuggestions(ctx context.Context, mediaID string) ([]*domain.MetadataSuggestion, error) {
+	var suggestions []*domain.MetadataSuggestion
+	for _, suggestion := range r.suggestions {
+		if suggestion.MediaID == mediaID {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+	return suggestions, nil
+}
+
+func (r *memoryTranscriptRepository) GetSuggestion(ctx context.Context, id string) (*domain.MetadataSuggestion, error) {
+	for _, suggestion := range r.suggestions {
+		if suggestion.ID == id {
+			stored := *suggestion
+			return &stored, nil
+		}
+	}
+	return nil, domain.ErrMetadataSuggestionNotFound
+}
+
+func (r *memoryTranscriptRepository) ListPendingSuggestions(ctx context.Context, limit int) ([]*domain.MetadataSuggestion, error) {
+	var suggestions []*domain.MetadataSuggestion
+	for _, suggestion := range r.suggestions {
+		if suggestion.Status == domain.SuggestionPending && len(suggestions) < limit {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions, nil
+}
+
+func (r *memoryTranscriptRepository) ResolveSuggestion(ctx context.Context, id string, status domain.MetadataSuggestionStatus, resolvedAt time.Time) (bool, error) {
+	for _, suggestion := range r.suggestions {
+		if suggestion.ID == id && suggestion.Status == domain.SuggestionPending {
+			suggestion.Status = status
+			suggestion.ResolvedAt = &resolvedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryTranscriptRepository) ReplaceExplicitMatches(ctx context.Context, mediaID string, matches []*domain.ExplicitLanguageMatch) error {
+	r.explicit[mediaID] = matches
+	return nil
+}
+
+func (r *memoryTranscriptRepository) GetExplicitMatches(ctx context.Context, mediaID string) ([]*domain.ExplicitLanguageMatch, error) {
+	return r.explicit[mediaID], nil
+}
+
+// fakeClassifier returns fixed suggestions for every transcript
+type fakeClassifier struct {
+	suggestions []domain.MetadataSuggestion
+	err         error
+}
+
+func (f *fakeClassifier) Classify(ctx context.Context, transcript *domain.Transcript) ([]domain.MetadataSuggestion, error) {
+	return f.suggestions, f.err
+}
+
+// findSuggestion returns the suggestion of a value
+func findSuggestion(t *testing.T, suggestions []*domain.MetadataSuggestion, value string) *domain.MetadataSuggestion {
+	t.Helper()
+	for _, suggestion := range suggestions {
+		if suggestion.Value == value {
+			return suggestion
+		}
+	}
+	require.Failf(t, "suggestion not found", "no suggestion of %q", value)
+	return nil
+}
+
+func TestTranscriptService_SuggestAndAccept(t *testing.T) {
+	// Given an episode already labelled as an interview
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Type: domain.TypePodcast, Status: domain.StatusReady, Labels: []string{"interview"}}))
+	transcriptRepo := newMemoryTranscriptRepository()
+	classifier := &fakeClassifier{suggestions: []domain.MetadataSuggestion{
+		{Kind: domain.SuggestionKindCategory, Value: "business", Confidence: 0.8},
+		{Kind: domain.SuggestionKindLabel, Value: "startups", Confidence: 0.6},
+		{Kind: domain.SuggestionKindLabel, Value: "interview", Confidence: 0.6},
+		{Kind: domain.SuggestionKindLabel, Value: "weather", Confidence: 0.2},
+	}}
+	transcriptService := NewTranscriptService(transcriptRepo, mediaRepo, nil, classifier, 0, nil)
+
+	// When its transcript is uploaded as captions
+	transcript, err := transcriptService.SetTranscript(ctx, "episode", &domain.TranscriptRequest{
+		Text:     "WEBVTT\n\n00:00:01.000 --> 00:00:04.000\nWelcome back to the show.",
+		Language: "en",
+	})
+
+	// Then the spoken text is stored and new confident suggestions are pending
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome back to the show.", transcript.Text)
+	suggestions, err := transcriptService.GetSuggestions(ctx, "episode")
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "business", suggestions[0].Value)
+	assert.Equal(t, "startups", suggestions[1].Value)
+
+	// When both are accepted
+	for _, suggestion := range suggestions {
+		accepted, err := transcriptService.AcceptSuggestion(ctx, suggestion.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.SuggestionAccepted, accepted.Status)
+	}
+
+	// Then the media is classified with them
+	media, err := mediaRepo.GetByID(ctx, "episode")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"interview", "startups"}, media.Labels)
+	assert.Equal(t, "business", media.Category)
+	pending, err := transcriptService.ListPendingSuggestions(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestTranscriptService_DismissedSuggestionsAreNotRepeated(t *testing.T) {
+	// Given a dismissed suggestion
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Type: domain.TypePodcast, Status: domain.StatusReady}))
+	transcriptRepo := newMemoryTranscriptRepository()
+	classifier := &fakeClassifier{suggestions: []domain.MetadataSuggestion{
+		{Kind: domain.SuggestionKindLabel, Value: "startups", Confidence: 0.6},
+		{Kind: domain.SuggestionKindLabel, Value: "investors", Confidence: 0.5},
+	}}
+	transcriptService := NewTranscriptService(transcriptRepo, mediaRepo, nil, classifier, 0, nil)
+	_, err := transcriptService.SetTranscript(ctx, "episode", &domain.TranscriptRequest{Text: "First draft"})
+	require.NoError(t, err)
+	suggestions, err := transcriptService.GetSuggestions(ctx, "episode")
+	require.NoError(t, err)
+	_, err = transcriptService.DismissSuggestion(ctx, findSuggestion(t, suggestions, "startups").ID)
+	require.NoError(t, err)
+
+	// When the transcript is corrected
+	_, err = transcriptService.SetTranscript(ctx, "episode", &domain.TranscriptRequest{Text: "Final draft"})
+	require.NoError(t, err)
+
+	// Then the dismissed value is not suggested again and the pending one is replaced
+	suggestions, err = transcriptService.GetSuggestions(ctx, "episode")
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, domain.SuggestionDismissed, findSuggestion(t, suggestions, "startups").Status)
+	assert.Equal(t, domain.SuggestionPending, findSuggestion(t, suggestions, "investors").Status)
+
+	_, err = transcriptService.DismissSuggestion(ctx, findSuggestion(t, suggestions, "startups").ID)
+	assert.ErrorIs(t, err, domain.ErrMetadataSuggestionResolved)
+}
+
+func TestTranscriptService_SetTranscript_Errors(t *testing.T) {
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Type: domain.TypePodcast, Status: domain.StatusReady}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "deleted", Type: domain.TypePodcast, Status: domain.StatusDeleted}))
+	transcriptRepo := newMemoryTranscriptRepository()
+	transcriptService := NewTranscriptService(transcriptRepo, mediaRepo, nil, &fakeClassifier{err: errors.New("model unavailable")}, 0, nil)
+
+	t.Run("keeps the transcript when classifying fails", func(t *testing.T) {
+		_, err := transcriptService.SetTranscript(ctx, "episode", &domain.TranscriptRequest{Text: "Welcome"})
+		require.NoError(t, err)
+
+		transcript, err := transcriptService.GetTranscript(ctx, "episode")
+		require.NoError(t, err)
+		assert.Equal(t, "Welcome", transcript.Text)
+	})
+
+	t.Run("rejects an invalid transcript", func(t *testing.T) {
+		_, err := transcriptService.SetTranscript(ctx, "episode", &domain.TranscriptRequest{Text: strings.Repeat("a", domain.MaxTranscriptBytes+1)})
+		var businessErr *domain.BusinessError
+		require.ErrorAs(t, err, &businessErr)
+		assert.Equal(t, "INVALID_TRANSCRIPT", businessErr.Code)
+	})
+
+	t.Run("deleted media is not found", func(t *testing.T) {
+		_, err := transcriptService.SetTranscript(ctx, "deleted", &domain.TranscriptRequest{Text: "Welcome"})
+		assert.ErrorIs(t, err, domain.ErrMediaNotFound)
+	})
+}
+
+func TestKeywordClassifier_Classify(t *testing.T) {
+	classifier := NewKeywordClassifier(map[string][]string{"gaming": {"gaming", "console", "games"}})
+
+	suggestions, err := classifier.Classify(context.Background(), &domain.Transcript{
+		Text: strings.Repeat("New console games this season. ", 3),
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, suggestions, domain.MetadataSuggestion{Kind: domain.SuggestionKindCategory, Value: "gaming", Confidence: 0.55})
+	assert.Contains(t, suggestions, domain.MetadataSuggestion{Kind: domain.SuggestionKindLabel, Value: "console", Confidence: 0.38})
+}
+
+func TestTranscriptService_DetectsExplicitLanguage(t *testing.T) {
+	// Given an episode rated for all ages and detection of the built-in terms
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "episode", Type: domain.TypePodcast, Status: domain.StatusReady}))
+	publisher := newMockEventPublisher()
+	terms, err := domain.ParseExplicitTerms(nil)
+	require.NoError(t, err)
+	transcriptService := NewTranscriptService(newMemoryTranscriptRepository(), mediaRepo, publisher, &fakeClassifier{}, 0, terms)
+
+	// When its captions swear
+	_, err = transcriptService.SetTranscript(ctx, "episode", &domain.TranscriptRequest{
+		Text: "WEBVTT\n\n00:00:01.000 --> 00:00:04.000\nWelcome back.\n\n00:01:30.500 --> 00:01:33.000\nWhat the FUCK was that?",
+	})
+	require.NoError(t, err)
+
+	// Then the media is flagged and the match is kept with its start time
+	report, err := transcriptService.GetExplicitLanguage(ctx, "episode")
+	require.NoError(t, err)
+	assert.True(t, report.Explicit)
+	require.Len(t, report.Matches, 1)
+	assert.Equal(t, "fuck", report.Matches[0].Term)
+	assert.Equal(t, "What the FUCK was that?", report.Matches[0].Excerpt)
+	require.NotNil(t, report.Matches[0].StartTime)
+	assert.Equal(t, 90.5, *report.Matches[0].StartTime)
+	publisher.AssertNumberOfCalls(t, "Publish", 1)
+
+	// When a clean transcript replaces it
+	_, err = transcriptService.SetTranscript(ctx, "episode", &domain.TranscriptRequest{Text: "Welcome back."})
+	require.NoError(t, err)
+
+	// Then the matches are cleared but the flag is left for editors to lift
+	report, err = transcriptService.GetExplicitLanguage(ctx, "episode")
+	require.NoError(t, err)
+	assert.True(t, report.Explicit)
+	assert.Empty(t, report.Matches)
+}
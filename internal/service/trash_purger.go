@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+)
+
+// TrashPurgerOptions configures purging of deleted media
+type TrashPurgerOptions struct {
+	Retention time.Duration // how long deleted media stays in the trash
+	Interval  time.Duration // wait between sweeps
+	BatchSize int           // media purged per sweep
+}
+
+// TrashSweepResult reports what a sweep did
+type TrashSweepResult struct {
+	Purged         int `json:"purged"`
+	ObjectsDeleted int `json:"objects_deleted"`
+}
+
+// TrashPurger permanently removes deleted media once its retention has passed
+type TrashPurger interface {
+	// Sweep purges the media deleted longer than the retention ago
+	Sweep(ctx context.Context) (*TrashSweepResult, error)
+
+	// Run sweeps periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// trashPurger implements TrashPurger interface
+type trashPurger struct {
+	mediaRepo repository.MediaRepository
+	storage   storage.Storage
+	publisher EventPublisher
+	options   TrashPurgerOptions
+	now       func() time.Time
+}
+
+// NewTrashPurger creates a new trash purger. Purged media is announced so
+// search drops any document left behind.
+func NewTrashPurger(mediaRepo repository.MediaRepository, mediaStorage storage.Storage, publisher EventPublisher, options TrashPurgerOptions) TrashPurger {
+	if options.Interval <= 0 {
+		options.Interval = time.Hour
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+
+	return &trashPurger{
+		mediaRepo: mediaRepo,
+		storage:   mediaStorage,
+		publisher: publisher,
+		options:   options,
+		now:       time.Now,
+	}
+}
+
+// Sweep purges the media deleted longer than the retention ago
+func (p *trashPurger) Sweep(ctx context.Context) (*TrashSweepResult, error) {
+	result := &TrashSweepResult{}
+
+	expired, err := p.mediaRepo.GetTrashed(ctx, p.now().Add(-p.options.Retention), p.options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load expired trash: %w", err)
+	}
+	for _, media := range expired {
+		// One failing item must not keep the others in the trash
+		deleted, err := p.purge(ctx, media)
+		result.ObjectsDeleted += deleted
+		if err != nil {
+			log.Printf("Failed to purge deleted media %s: %v", media.ID, err)
+			continue
+		}
+		result.Purged++
+	}
+
+	return result, nil
+}
+
+// Run sweeps periodically until ctx is cancelled
+func (p *trashPurger) Run(ctx context.Context) {
+	for {
+		result, err := p.Sweep(ctx)
+		if err != nil {
+			log.Printf("Trash sweep failed: %v", err)
+		} else if result.Purged > 0 {
+			log.Printf("Trash sweep purged %d media and deleted %d stored objects", result.Purged, result.ObjectsDeleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.options.Interval):
+		}
+	}
+}
+
+// purge deletes the stored files of media, then its record, and announces it.
+// Files go first: a record whose files could not be deleted stays for the next sweep.
+func (p *trashPurger) purge(ctx context.Context, media *domain.Media) (int, error) {
+	deleted := 0
+	for _, key := range media.StoredKeys() {
+		err := p.storage.Delete(ctx, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete stored object %s: %w", key, err)
+		}
+		deleted++
+	}
+
+	if err := p.mediaRepo.Purge(ctx, media.ID); err != nil {
+		return deleted, fmt.Errorf("failed to purge media record: %w", err)
+	}
+
+	event := domain.NewEvent(domain.EventMediaPurged, map[string]interface{}{
+		"media_id":   media.ID,
+		"tenant_id":  media.TenantID,
+		"deleted_at": media.DeletedAt.Format(time.RFC3339),
+	})
+	if err := p.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish purge of media %s: %v", media.ID, err)
+	}
+
+	return deleted, nil
+}
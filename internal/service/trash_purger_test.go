@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+	"thamaniyah/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashPurger_Sweep(t *testing.T) {
+	// Given a deleted episode with its uploaded file and artwork, and a live episode
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	store := storage.NewLocalStorage(t.TempDir())
+	deleted := &domain.Media{ID: "m1", Title: "Deleted", Status: domain.StatusDeleted, FilePath: domain.UploadPathPrefix + "m1.mp3",
+		Tags: domain.MediaTags{ArtworkKey: domain.DerivedArtworkKey("m1", "image/png")}}
+	require.NoError(t, mediaRepo.Create(ctx, deleted))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m2", Title: "Live", Status: domain.StatusReady}))
+	require.NoError(t, mediaRepo.Delete(ctx, "m1"))
+	for _, key := range []string{deleted.StorageKey(), deleted.Tags.ArtworkKey} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("data")))
+	}
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+		return event.Type == domain.EventMediaPurged && event.Data["media_id"] == "m1"
+	})).Return(nil).Once()
+	purger := NewTrashPurger(mediaRepo, store, publisher, TrashPurgerOptions{Retention: 30 * 24 * time.Hour}).(*trashPurger)
+
+	// When sweeping within the retention
+	result, err := purger.Sweep(ctx)
+
+	// Then the deleted media stays in the trash
+	require.NoError(t, err)
+	assert.Equal(t, &TrashSweepResult{}, result)
+
+	// When sweeping once the retention has passed
+	purger.now = func() time.Time { return time.Now().Add(31 * 24 * time.Hour) }
+	result, err = purger.Sweep(ctx)
+
+	// Then the record and its files are gone and the purge is announced
+	require.NoError(t, err)
+	assert.Equal(t, &TrashSweepResult{Purged: 1, ObjectsDeleted: 2}, result)
+	trashed, err := mediaRepo.GetTrashed(ctx, purger.now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+	exists, err := store.Exists(ctx, deleted.StorageKey())
+	require.NoError(t, err)
+	assert.False(t, exists)
+	publisher.AssertExpectations(t)
+
+	// And live media is untouched
+	_, err = mediaRepo.GetByID(ctx, "m2")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// UnpublishSchedulerOptions configures scheduled unpublishing
+type UnpublishSchedulerOptions struct {
+	Interval  time.Duration // wait between sweeps
+	BatchSize int           // media handled per sweep
+}
+
+// UnpublishSweepResult reports what a sweep did
+type UnpublishSweepResult struct {
+	Unpublished  int   `json:"unpublished"`
+	LinksRevoked int64 `json:"links_revoked"`
+}
+
+// UnpublishScheduler returns media to private once its publishing window closes
+type UnpublishScheduler interface {
+	// Sweep unpublishes the media whose unpublish time has passed
+	Sweep(ctx context.Context) (*UnpublishSweepResult, error)
+
+	// Run sweeps periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// unpublishScheduler implements UnpublishScheduler interface
+type unpublishScheduler struct {
+	mediaRepo     repository.MediaRepository
+	shareLinkRepo repository.ShareLinkRepository
+	publisher     EventPublisher
+	options       UnpublishSchedulerOptions
+	now           func() time.Time
+}
+
+// NewUnpublishScheduler creates a new unpublish scheduler
+func NewUnpublishScheduler(mediaRepo repository.MediaRepository, shareLinkRepo repository.ShareLinkRepository, publisher EventPublisher, options UnpublishSchedulerOptions) UnpublishScheduler {
+	if options.Interval <= 0 {
+		options.Interval = time.Minute
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+
+	return &unpublishScheduler{
+		mediaRepo:     mediaRepo,
+		shareLinkRepo: shareLinkRepo,
+		publisher:     publisher,
+		options:       options,
+		now:           time.Now,
+	}
+}
+
+// Sweep unpublishes the media whose unpublish time has passed
+func (s *unpublishScheduler) Sweep(ctx context.Context) (*UnpublishSweepResult, error) {
+	now := s.now()
+	result := &UnpublishSweepResult{}
+
+	due, err := s.mediaRepo.GetDueUnpublishes(ctx, now, s.options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load media due for unpublishing: %w", err)
+	}
+	for _, media := range due {
+		// One failing item must not keep the others published
+		revoked, err := s.unpublish(ctx, media, now)
+		result.LinksRevoked += revoked
+		if err != nil {
+			log.Printf("Failed to unpublish media %s at the end of its window: %v", media.ID, err)
+			continue
+		}
+		result.Unpublished++
+	}
+
+	return result, nil
+}
+
+// Run sweeps periodically until ctx is cancelled
+func (s *unpublishScheduler) Run(ctx context.Context) {
+	for {
+		result, err := s.Sweep(ctx)
+		if err != nil {
+			log.Printf("Unpublish sweep failed: %v", err)
+		} else if result.Unpublished > 0 {
+			log.Printf("Unpublish sweep unpublished %d media and revoked %d share links", result.Unpublished, result.LinksRevoked)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.options.Interval):
+		}
+	}
+}
+
+// unpublish revokes the share links of media, makes it private and announces
+// it so search drops it. Links go first: the media leaves the sweep once private.
+func (s *unpublishScheduler) unpublish(ctx context.Context, media *domain.Media, now time.Time) (int64, error) {
+	revoked, err := s.shareLinkRepo.RevokeByMediaID(ctx, media.ID, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke share links: %w", err)
+	}
+	if err := s.mediaRepo.UpdateVisibility(ctx, media.ID, domain.VisibilityPrivate); err != nil {
+		return revoked, err
+	}
+	media.Visibility = domain.VisibilityPrivate
+
+	// Republishing the media must not unpublish it again
+	unpublishAt := media.UnpublishAt
+	if err := s.mediaRepo.UpdateUnpublishAt(ctx, media.ID, nil); err != nil {
+		log.Printf("Failed to clear the unpublish time of media %s: %v", media.ID, err)
+	}
+	media.UnpublishAt = nil
+
+	event := domain.NewEvent(domain.EventMediaUnpublished, map[string]interface{}{
+		"media_id":      media.ID,
+		"reason":        "unpublish_scheduled",
+		"unpublish_at":  unpublishAt.Format(time.RFC3339),
+		"links_revoked": revoked,
+	})
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish unpublish event for media %s: %v", media.ID, err)
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpublishScheduler_Sweep(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		setupMock   func(*MockMediaRepository, *MockShareLinkRepository, *MockEventPublisher)
+		expected    *UnpublishSweepResult
+		expectError bool
+	}{
+		{
+			name: "unpublishes due media and revokes its share links",
+			setupMock: func(mediaRepo *MockMediaRepository, linkRepo *MockShareLinkRepository, publisher *MockEventPublisher) {
+				mediaRepo.On("GetDueUnpublishes", mock.Anything, now, 10).Return([]*domain.Media{
+					{ID: "due", Visibility: domain.VisibilityPublic, UnpublishAt: &dueAt},
+				}, nil)
+				linkRepo.On("RevokeByMediaID", mock.Anything, "due", now).Return(int64(2), nil)
+				mediaRepo.On("UpdateVisibility", mock.Anything, "due", domain.VisibilityPrivate).Return(nil)
+				mediaRepo.On("UpdateUnpublishAt", mock.Anything, "due", (*time.Time)(nil)).Return(nil)
+				publisher.On("Publish", mock.Anything, mock.MatchedBy(func(event *domain.Event) bool {
+					return event.Type == domain.EventMediaUnpublished && event.Data["media_id"] == "due" &&
+						event.Data["reason"] == "unpublish_scheduled"
+				})).Return(nil)
+			},
+			expected: &UnpublishSweepResult{Unpublished: 1, LinksRevoked: 2},
+		},
+		{
+			name: "failed revocation keeps the media published",
+			setupMock: func(mediaRepo *MockMediaRepository, linkRepo *MockShareLinkRepository, publisher *MockEventPublisher) {
+				mediaRepo.On("GetDueUnpublishes", mock.Anything, now, 10).Return([]*domain.Media{
+					{ID: "broken", UnpublishAt: &dueAt},
+					{ID: "due", UnpublishAt: &dueAt},
+				}, nil)
+				linkRepo.On("RevokeByMediaID", mock.Anything, "broken", now).Return(int64(0), errors.New("database error"))
+				linkRepo.On("RevokeByMediaID", mock.Anything, "due", now).Return(int64(0), nil)
+				mediaRepo.On("UpdateVisibility", mock.Anything, "due", domain.VisibilityPrivate).Return(nil)
+				mediaRepo.On("UpdateUnpublishAt", mock.Anything, "due", (*time.Time)(nil)).Return(nil)
+				publisher.On("Publish", mock.Anything, mock.Anything).Return(nil)
+			},
+			expected: &UnpublishSweepResult{Unpublished: 1},
+		},
+		{
+			name: "repository error",
+			setupMock: func(mediaRepo *MockMediaRepository, linkRepo *MockShareLinkRepository, publisher *MockEventPublisher) {
+				mediaRepo.On("GetDueUnpublishes", mock.Anything, now, 10).Return(nil, errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mediaRepo := new(MockMediaRepository)
+			linkRepo := new(MockShareLinkRepository)
+			publisher := new(MockEventPublisher)
+			tt.setupMock(mediaRepo, linkRepo, publisher)
+			scheduler := NewUnpublishScheduler(mediaRepo, linkRepo, publisher, UnpublishSchedulerOptions{BatchSize: 10}).(*unpublishScheduler)
+			scheduler.now = func() time.Time { return now }
+
+			// When
+			result, err := scheduler.Sweep(context.Background())
+
+			// Then
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+
+			mediaRepo.AssertExpectations(t)
+			linkRepo.AssertExpectations(t)
+			publisher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnpublishScheduler_Sweep_InMemory(t *testing.T) {
+	// Given media whose window closed, media still in its window and private media
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Minute)
+	laterAt := now.Add(time.Hour)
+
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "due", Status: domain.StatusReady, Visibility: domain.VisibilityPublic, UnpublishAt: &dueAt}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "later", Status: domain.StatusReady, Visibility: domain.VisibilityPublic, UnpublishAt: &laterAt}))
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "private", Status: domain.StatusReady, Visibility: domain.VisibilityPrivate, UnpublishAt: &dueAt}))
+	linkRepo := new(MockShareLinkRepository)
+	linkRepo.On("RevokeByMediaID", mock.Anything, "due", now).Return(int64(1), nil).Once()
+
+	scheduler := NewUnpublishScheduler(mediaRepo, linkRepo, newMockEventPublisher(), UnpublishSchedulerOptions{}).(*unpublishScheduler)
+	scheduler.now = func() time.Time { return now }
+
+	// When
+	first, err := scheduler.Sweep(ctx)
+	require.NoError(t, err)
+	second, err := scheduler.Sweep(ctx)
+	require.NoError(t, err)
+
+	// Then only the due media is unpublished, once
+	assert.Equal(t, &UnpublishSweepResult{Unpublished: 1, LinksRevoked: 1}, first)
+	assert.Equal(t, &UnpublishSweepResult{}, second, "unpublished media is not swept again")
+
+	due, err := mediaRepo.GetByID(ctx, "due")
+	require.NoError(t, err)
+	assert.Equal(t, domain.VisibilityPrivate, due.Visibility)
+	assert.Nil(t, due.UnpublishAt, "republishing must not unpublish the media again")
+	linkRepo.AssertExpectations(t)
+}
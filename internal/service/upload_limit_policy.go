@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// Keys of the per tenant upload limit overrides
+const (
+	uploadLimitVideoMaxMB    = "video_max_mb"
+	uploadLimitPodcastMaxMB  = "podcast_max_mb"
+	uploadLimitURLTTLSeconds = "url_ttl_seconds"
+	uploadLimitQuotaMB       = "quota_mb"
+)
+
+// UploadLimitOptions configures the upload limits of all tenants
+type UploadLimitOptions struct {
+	URLTTL             time.Duration
+	MaxVideoFileSize   int64 // in bytes
+	MaxPodcastFileSize int64 // in bytes
+	StorageQuota       int64 // total bytes of a tenant's media, 0 is unlimited
+	// Per tenant overrides as "tenant:key=value", e.g. "acme:video_max_mb=10240";
+	// keys are video_max_mb, podcast_max_mb, url_ttl_seconds and quota_mb
+	TenantOverrides []string
+}
+
+// UploadLimitPolicy resolves the upload limits in effect for a tenant
+type UploadLimitPolicy struct {
+	defaults domain.UploadLimits
+	tenants  map[string]domain.UploadLimits
+}
+
+// NewUploadLimitPolicy creates an upload limit policy, rejecting non-positive limits and malformed overrides
+func NewUploadLimitPolicy(options UploadLimitOptions) (*UploadLimitPolicy, error) {
+	defaults := domain.DefaultUploadLimits("")
+	defaults.UploadURLTTLSeconds = int(options.URLTTL / time.Second)
+	defaults.MaxFileSizes[domain.TypeVideo] = options.MaxVideoFileSize
+	defaults.MaxFileSizes[domain.TypePodcast] = options.MaxPodcastFileSize
+	defaults.StorageQuota = options.StorageQuota
+	if err := validateUploadLimits(defaults); err != nil {
+		return nil, err
+	}
+
+	policy := &UploadLimitPolicy{
+		defaults: *defaults,
+		tenants:  make(map[string]domain.UploadLimits),
+	}
+	for _, override := range options.TenantOverrides {
+		if err := policy.applyOverride(override); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+// LimitsFor returns the upload limits in effect for a tenant, the built-in
+// defaults when the policy is nil
+func (p *UploadLimitPolicy) LimitsFor(tenantID string) *domain.UploadLimits {
+	if tenantID == "" {
+		tenantID = domain.DefaultTenantID
+	}
+	if p == nil {
+		return domain.DefaultUploadLimits(tenantID)
+	}
+
+	limits, ok := p.tenants[tenantID]
+	if !ok {
+		limits = p.defaults
+	}
+	limits.TenantID = tenantID
+	limits.MaxFileSizes = copyFileSizes(limits.MaxFileSizes)
+	return &limits
+}
+
+// applyOverride applies a "tenant:key=value" override on top of the defaults
+func (p *UploadLimitPolicy) applyOverride(override string) error {
+	tenant, setting, ok := strings.Cut(override, ":")
+	key, value, hasValue := strings.Cut(setting, "=")
+	tenant, key = strings.TrimSpace(tenant), strings.TrimSpace(key)
+	if !ok || !hasValue || tenant == "" {
+		return fmt.Errorf("invalid upload limit override %q, expected tenant:key=value", override)
+	}
+	number, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid upload limit override %q: %w", override, err)
+	}
+
+	limits, exists := p.tenants[tenant]
+	if !exists {
+		limits = p.defaults
+		limits.MaxFileSizes = copyFileSizes(p.defaults.MaxFileSizes)
+	}
+	switch key {
+	case uploadLimitVideoMaxMB:
+		limits.MaxFileSizes[domain.TypeVideo] = number * 1024 * 1024
+	case uploadLimitPodcastMaxMB:
+		limits.MaxFileSizes[domain.TypePodcast] = number * 1024 * 1024
+	case uploadLimitURLTTLSeconds:
+		limits.UploadURLTTLSeconds = int(number)
+	case uploadLimitQuotaMB:
+		limits.StorageQuota = number * 1024 * 1024
+	default:
+		return fmt.Errorf("unknown upload limit %q in override %q", key, override)
+	}
+	if err := validateUploadLimits(&limits); err != nil {
+		return fmt.Errorf("invalid upload limits of tenant %s: %w", tenant, err)
+	}
+
+	p.tenants[tenant] = limits
+	return nil
+}
+
+// validateUploadLimits checks that every limit allows uploads
+func validateUploadLimits(limits *domain.UploadLimits) error {
+	if limits.UploadURLTTLSeconds <= 0 {
+		return fmt.Errorf("upload URL TTL must be positive")
+	}
+	if limits.StorageQuota < 0 {
+		return fmt.Errorf("storage quota must not be negative")
+	}
+	for mediaType, size := range limits.MaxFileSizes {
+		if size <= 0 {
+			return fmt.Errorf("maximum %s file size must be positive", mediaType)
+		}
+	}
+	return nil
+}
+
+// copyFileSizes copies file size limits so tenants never share them
+func copyFileSizes(sizes map[domain.MediaType]int64) map[domain.MediaType]int64 {
+	copied := make(map[domain.MediaType]int64, len(sizes))
+	for mediaType, size := range sizes {
+		copied[mediaType] = size
+	}
+	return copied
+}
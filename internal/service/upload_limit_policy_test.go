@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadLimitPolicy_LimitsFor(t *testing.T) {
+	// Given defaults with overrides for one tenant
+	policy, err := NewUploadLimitPolicy(UploadLimitOptions{
+		URLTTL:             time.Hour,
+		MaxVideoFileSize:   100 * 1024 * 1024,
+		MaxPodcastFileSize: 10 * 1024 * 1024,
+		TenantOverrides:    []string{"acme:video_max_mb=1000", "acme:url_ttl_seconds=7200"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		tenant      string
+		wantTenant  string
+		wantTTL     time.Duration
+		wantVideo   int64
+		wantPodcast int64
+	}{
+		{"default tenant", "", domain.DefaultTenantID, time.Hour, 100 * 1024 * 1024, 10 * 1024 * 1024},
+		{"tenant without overrides", "globex", "globex", time.Hour, 100 * 1024 * 1024, 10 * 1024 * 1024},
+		{"tenant with overrides", "acme", "acme", 2 * time.Hour, 1000 * 1024 * 1024, 10 * 1024 * 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			limits := policy.LimitsFor(tt.tenant)
+
+			// Then
+			assert.Equal(t, tt.wantTenant, limits.TenantID)
+			assert.Equal(t, tt.wantTTL, limits.UploadURLTTL())
+			assert.Equal(t, tt.wantVideo, limits.MaxFileSize(domain.TypeVideo))
+			assert.Equal(t, tt.wantPodcast, limits.MaxFileSize(domain.TypePodcast))
+		})
+	}
+}
+
+func TestUploadLimitPolicy_NilUsesDefaults(t *testing.T) {
+	// Given
+	var policy *UploadLimitPolicy
+
+	// When
+	limits := policy.LimitsFor("")
+
+	// Then
+	assert.Equal(t, domain.DefaultUploadLimits(domain.DefaultTenantID), limits)
+}
+
+func TestNewUploadLimitPolicy_Invalid(t *testing.T) {
+	valid := UploadLimitOptions{URLTTL: time.Hour, MaxVideoFileSize: 1, MaxPodcastFileSize: 1}
+
+	tests := []struct {
+		name      string
+		overrides []string
+		ttl       time.Duration
+	}{
+		{"zero TTL", nil, 0},
+		{"missing tenant", []string{"video_max_mb=10"}, time.Hour},
+		{"unknown key", []string{"acme:audio_max_mb=10"}, time.Hour},
+		{"not a number", []string{"acme:video_max_mb=big"}, time.Hour},
+		{"non-positive size", []string{"acme:podcast_max_mb=0"}, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := valid
+			options.URLTTL = tt.ttl
+			options.TenantOverrides = tt.overrides
+
+			_, err := NewUploadLimitPolicy(options)
+
+			assert.Error(t, err)
+		})
+	}
+}
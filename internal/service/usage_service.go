@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+)
+
+// UsageService meters API usage per tenant and API key for billing and abuse
+// detection. Requests are counted in live counters, rolled up daily into
+// usage records that reports are served from.
+type UsageService interface {
+	// RecordUsage counts a request in the live counters
+	RecordUsage(ctx context.Context, sample domain.UsageSample) error
+
+	// RollUp saves the live counters of a day, today when empty, with the
+	// storage used by each tenant, returning the number of records saved.
+	// Rolling up a day again replaces its records.
+	RollUp(ctx context.Context, day string) (int, error)
+
+	// GetUsage reports the daily usage within the from and to days, of a tenant
+	// and an API key when they are not empty
+	GetUsage(ctx context.Context, tenantID, apiKeyID, from, to string) (*domain.UsageReport, error)
+
+	// GetTopUsage lists the tenants and API keys with the most usage on a day,
+	// today when empty, for abuse detection
+	GetTopUsage(ctx context.Context, day string, sort domain.UsageSort, limit int) ([]domain.UsageRecord, error)
+}
+
+// usageService implements UsageService interface
+type usageService struct {
+	counterRepo repository.UsageCounterRepository
+	usageRepo   repository.UsageRepository
+	mediaRepo   repository.MediaRepository
+	now         func() time.Time
+}
+
+// NewUsageService creates a new usage metering service
+func NewUsageService(counterRepo repository.UsageCounterRepository, usageRepo repository.UsageRepository, mediaRepo repository.MediaRepository) UsageService {
+	return &usageService{
+		counterRepo: counterRepo,
+		usageRepo:   usageRepo,
+		mediaRepo:   mediaRepo,
+		now:         time.Now,
+	}
+}
+
+// RecordUsage counts a request in the live counters
+func (s *usageService) RecordUsage(ctx context.Context, sample domain.UsageSample) error {
+	if sample.TenantID == "" {
+		sample.TenantID = domain.DefaultTenantID
+	}
+	if sample.APIKeyID == "" {
+		sample.APIKeyID = domain.AnonymousAPIKeyID
+	}
+	if sample.At.IsZero() {
+		sample.At = s.now()
+	}
+	return s.counterRepo.Increment(ctx, sample)
+}
+
+// RollUp saves the live counters of a day into usage records
+func (s *usageService) RollUp(ctx context.Context, day string) (int, error) {
+	day, err := s.parseDay(day)
+	if err != nil {
+		return 0, err
+	}
+
+	counters, err := s.counterRepo.GetDay(ctx, day)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	storage := make(map[string]int64)
+	records := make([]domain.UsageRecord, 0, len(counters))
+	for _, tenantCounters := range counters {
+		used, ok := storage[tenantCounters.TenantID]
+		if !ok {
+			if used, err = s.mediaRepo.GetStorageUsage(ctx, tenantCounters.TenantID); err != nil {
+				return 0, fmt.Errorf("failed to measure storage of tenant %s: %w", tenantCounters.TenantID, err)
+			}
+			storage[tenantCounters.TenantID] = used
+		}
+		records = append(records, domain.NewUsageRecord(tenantCounters, used, now))
+	}
+
+	if err := s.usageRepo.Save(ctx, records); err != nil {
+		return 0, fmt.Errorf("failed to save usage records: %w", err)
+	}
+	return len(records), nil
+}
+
+// GetUsage reports the daily usage within the from and to days
+func (s *usageService) GetUsage(ctx context.Context, tenantID, apiKeyID, from, to string) (*domain.UsageReport, error) {
+	start, end, errs := domain.ParseStatsRange(from, to, s.now())
+	if errs.HasErrors() {
+		return nil, domain.NewBusinessErrorWithDetails("INVALID_RANGE", "Invalid usage range", errs.Error())
+	}
+
+	records, err := s.usageRepo.List(ctx, tenantID, apiKeyID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage records: %w", err)
+	}
+
+	report := domain.NewUsageReport(records, start, end)
+	report.TenantID = tenantID
+	report.APIKeyID = apiKeyID
+	return report, nil
+}
+
+// GetTopUsage lists the tenants and API keys with the most usage on a day
+func (s *usageService) GetTopUsage(ctx context.Context, day string, sort domain.UsageSort, limit int) ([]domain.UsageRecord, error) {
+	day, err := s.parseDay(day)
+	if err != nil {
+		return nil, err
+	}
+	if sort == "" {
+		sort = domain.UsageSortRequests
+	}
+	if !sort.IsValid() {
+		return nil, domain.NewBusinessError("INVALID_SORT", "Sort must be one of: requests, bytes_in, bytes_out")
+	}
+	if limit <= 0 {
+		limit = domain.DefaultTopUsageLimit
+	}
+	limit = min(limit, domain.MaxTopUsageLimit)
+
+	records, err := s.usageRepo.Top(ctx, day, sort, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top usage: %w", err)
+	}
+	if records == nil {
+		records = []domain.UsageRecord{}
+	}
+	return records, nil
+}
+
+// parseDay validates a day, today when empty
+func (s *usageService) parseDay(day string) (string, error) {
+	if day == "" {
+		return s.now().UTC().Format(domain.CalendarDateLayout), nil
+	}
+	if _, err := time.Parse(domain.CalendarDateLayout, day); err != nil {
+		return "", domain.NewBusinessError("INVALID_DAY", "Day must be a date like 2025-06-01")
+	}
+	return day, nil
+}
+
+// UsageRollup periodically rolls up the usage counters
+type UsageRollup interface {
+	// Run rolls up the counters of yesterday and today periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// usageRollup implements UsageRollup interface
+type usageRollup struct {
+	usageService UsageService
+	interval     time.Duration
+	now          func() time.Time
+}
+
+// NewUsageRollup creates a new usage rollup job
+func NewUsageRollup(usageService UsageService, interval time.Duration) UsageRollup {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	return &usageRollup{
+		usageService: usageService,
+		interval:     interval,
+		now:          time.Now,
+	}
+}
+
+// Run rolls up the counters of yesterday and today periodically until ctx is cancelled.
+// Yesterday is rolled up again so its last requests are saved after midnight.
+func (r *usageRollup) Run(ctx context.Context) {
+	for {
+		today := r.now().UTC()
+		for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+			if _, err := r.usageService.RollUp(ctx, day.Format(domain.CalendarDateLayout)); err != nil && ctx.Err() == nil {
+				log.Printf("Usage rollup of %s failed: %v", day.Format(domain.CalendarDateLayout), err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval):
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUsageRepository keeps usage records in memory
+type memoryUsageRepository struct {
+	records map[string]domain.UsageRecord
+}
+
+func (r *memoryUsageRepository) Save(ctx context.Context, records []domain.UsageRecord) error {
+	if r.records == nil {
+		r.records = make(map[string]domain.UsageRecord)
+	}
+	for _, record := range records {
+		r.records[record.Day+"/"+record.TenantID+"/"+record.APIKeyID] = record
+	}
+	return nil
+}
+
+func (r *memoryUsageRepository) List(ctx context.Context, tenantID, apiKeyID string, from, end time.Time) ([]domain.UsageRecord, error) {
+	var records []domain.UsageRecord
+	for _, record := range r.sorted() {
+		if (tenantID == "" || record.TenantID == tenantID) && (apiKeyID == "" || record.APIKeyID == apiKeyID) &&
+			record.Day >= from.Format(domain.CalendarDateLayout) && record.Day < end.Format(domain.CalendarDateLayout) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (r *memoryUsageRepository) Top(ctx context.Context, day string, by domain.UsageSort, limit int) ([]domain.UsageRecord, error) {
+	var records []domain.UsageRecord
+	for _, record := range r.sorted() {
+		if record.Day == day {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Requests > records[j].Requests })
+	return records[:min(limit, len(records))], nil
+}
+
+func (r *memoryUsageRepository) sorted() []domain.UsageRecord {
+	records := make([]domain.UsageRecord, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Day+records[i].TenantID+records[i].APIKeyID < records[j].Day+records[j].TenantID+records[j].APIKeyID
+	})
+	return records
+}
+
+func TestUsageService_RecordAndRollUp(t *testing.T) {
+	// Given media of a tenant and requests of two API keys
+	ctx := context.Background()
+	mediaRepo := repository.NewInMemoryMediaRepository()
+	require.NoError(t, mediaRepo.Create(ctx, &domain.Media{ID: "m1", TenantID: "acme", FileSize: 1000}))
+	usageRepo := &memoryUsageRepository{}
+	s := NewUsageService(repository.NewInMemoryUsageCounterRepository(), usageRepo, mediaRepo).(*usageService)
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.RecordUsage(ctx, domain.UsageSample{TenantID: "acme", APIKeyID: "k1", BytesIn: 10, BytesOut: 100}))
+	require.NoError(t, s.RecordUsage(ctx, domain.UsageSample{TenantID: "acme", APIKeyID: "k1", BytesOut: 50}))
+	require.NoError(t, s.RecordUsage(ctx, domain.UsageSample{BytesOut: 5}))
+
+	// When the day is rolled up
+	saved, err := s.RollUp(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved)
+
+	// Then its usage is reported with the storage of the tenant
+	report, err := s.GetUsage(ctx, "acme", "", "2025-06-01", "2025-06-01")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Requests)
+	assert.Equal(t, int64(10), report.BytesIn)
+	assert.Equal(t, int64(150), report.BytesOut)
+	assert.Equal(t, int64(1000), report.Storage)
+	require.Len(t, report.Days, 1)
+	assert.Equal(t, "k1", report.Days[0].APIKeyID)
+
+	// And requests without a tenant or key are counted for the default tenant, anonymously
+	report, err = s.GetUsage(ctx, domain.DefaultTenantID, domain.AnonymousAPIKeyID, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Requests)
+
+	// And rolling up again replaces the records with the current counters
+	require.NoError(t, s.RecordUsage(ctx, domain.UsageSample{TenantID: "acme", APIKeyID: "k1"}))
+	_, err = s.RollUp(ctx, "2025-06-01")
+	require.NoError(t, err)
+	top, err := s.GetTopUsage(ctx, "", "", 0)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, int64(3), top[0].Requests)
+}
+
+func TestUsageService_Validation(t *testing.T) {
+	ctx := context.Background()
+	s := NewUsageService(repository.NewInMemoryUsageCounterRepository(), &memoryUsageRepository{}, repository.NewInMemoryMediaRepository())
+
+	var businessErr *domain.BusinessError
+	_, err := s.RollUp(ctx, "yesterday")
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_DAY", businessErr.Code)
+	_, err = s.GetTopUsage(ctx, "", "storage", 10)
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_SORT", businessErr.Code)
+	_, err = s.GetUsage(ctx, "", "", "2025-06-02", "2025-06-01")
+	require.ErrorAs(t, err, &businessErr)
+	assert.Equal(t, "INVALID_RANGE", businessErr.Code)
+
+	top, err := s.GetTopUsage(ctx, "2025-06-01", domain.UsageSortBytesOut, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UsageRecord{}, top)
+}
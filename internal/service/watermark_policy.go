@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"thamaniyah/internal/domain"
+)
+
+// tenantPlaceholder is replaced with the tenant of the media in watermark logo paths
+const tenantPlaceholder = "{tenant}"
+
+// WatermarkOptions configures which videos are watermarked during transcoding
+type WatermarkOptions struct {
+	Enabled   bool     // watermark all videos
+	Tenants   []string // tenants whose videos are watermarked
+	Shows     []string // shows whose videos are watermarked
+	Watermark domain.Watermark
+}
+
+// WatermarkPolicy decides which watermark, if any, is overlaid on a video during transcoding
+type WatermarkPolicy struct {
+	options WatermarkOptions
+	tenants map[string]bool
+	shows   map[string]bool
+}
+
+// NewWatermarkPolicy creates a watermark policy, validating the watermark when any video is watermarked
+func NewWatermarkPolicy(options WatermarkOptions) (*WatermarkPolicy, error) {
+	policy := &WatermarkPolicy{
+		options: options,
+		tenants: make(map[string]bool),
+		shows:   make(map[string]bool),
+	}
+	for _, tenant := range options.Tenants {
+		policy.tenants[tenant] = true
+	}
+	for _, show := range options.Shows {
+		policy.shows[show] = true
+	}
+
+	if policy.active() {
+		if errs := options.Watermark.Validate(); errs.HasErrors() {
+			return nil, fmt.Errorf("invalid watermark configuration: %w", errs)
+		}
+	}
+
+	return policy, nil
+}
+
+// WatermarkFor returns the watermark to overlay on the renditions of media,
+// or nil when the media is not watermarked
+func (p *WatermarkPolicy) WatermarkFor(media *domain.Media) *domain.Watermark {
+	if p == nil || media.Type != domain.TypeVideo {
+		return nil
+	}
+
+	tenant := media.TenantID
+	if tenant == "" {
+		tenant = domain.DefaultTenantID
+	}
+
+	if !p.options.Enabled && !p.tenants[tenant] && (media.ShowID == "" || !p.shows[media.ShowID]) {
+		return nil
+	}
+
+	watermark := p.options.Watermark
+	watermark.LogoPath = strings.ReplaceAll(watermark.LogoPath, tenantPlaceholder, tenant)
+	return &watermark
+}
+
+// active reports whether any video can be watermarked
+func (p *WatermarkPolicy) active() bool {
+	return p.options.Enabled || len(p.tenants) > 0 || len(p.shows) > 0
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatermarkPolicy_WatermarkFor(t *testing.T) {
+	watermark := domain.Watermark{
+		LogoPath: "/logos/{tenant}.png",
+		Position: domain.WatermarkBottomRight,
+		Opacity:  0.6,
+		Margin:   24,
+	}
+
+	tests := []struct {
+		name         string
+		options      WatermarkOptions
+		media        *domain.Media
+		expectedLogo string
+	}{
+		{
+			name:         "enabled for all videos",
+			options:      WatermarkOptions{Enabled: true, Watermark: watermark},
+			media:        &domain.Media{Type: domain.TypeVideo, TenantID: "acme"},
+			expectedLogo: "/logos/acme.png",
+		},
+		{
+			name:         "enabled for tenant",
+			options:      WatermarkOptions{Tenants: []string{"acme"}, Watermark: watermark},
+			media:        &domain.Media{Type: domain.TypeVideo, TenantID: "acme"},
+			expectedLogo: "/logos/acme.png",
+		},
+		{
+			name:    "other tenant",
+			options: WatermarkOptions{Tenants: []string{"acme"}, Watermark: watermark},
+			media:   &domain.Media{Type: domain.TypeVideo, TenantID: "globex"},
+		},
+		{
+			name:         "enabled for show",
+			options:      WatermarkOptions{Shows: []string{"show-1"}, Watermark: watermark},
+			media:        &domain.Media{Type: domain.TypeVideo, ShowID: "show-1"},
+			expectedLogo: "/logos/default.png",
+		},
+		{
+			name:    "podcasts are never watermarked",
+			options: WatermarkOptions{Enabled: true, Watermark: watermark},
+			media:   &domain.Media{Type: domain.TypePodcast, TenantID: "acme"},
+		},
+		{
+			name:    "disabled",
+			options: WatermarkOptions{Watermark: watermark},
+			media:   &domain.Media{Type: domain.TypeVideo, TenantID: "acme", ShowID: "show-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			policy, err := NewWatermarkPolicy(tt.options)
+			require.NoError(t, err)
+
+			// When
+			result := policy.WatermarkFor(tt.media)
+
+			// Then
+			if tt.expectedLogo == "" {
+				assert.Nil(t, result)
+			} else {
+				assert.NotNil(t, result)
+				assert.Equal(t, tt.expectedLogo, result.LogoPath)
+				assert.Equal(t, domain.WatermarkBottomRight, result.Position)
+			}
+		})
+	}
+}
+
+func TestNewWatermarkPolicy_InvalidWatermark(t *testing.T) {
+	// When
+	policy, err := NewWatermarkPolicy(WatermarkOptions{
+		Enabled:   true,
+		Watermark: domain.Watermark{LogoPath: "/logos/logo.png", Position: "middle", Opacity: 0.5},
+	})
+
+	// Then
+	assert.Error(t, err)
+	assert.Nil(t, policy)
+}
@@ -0,0 +1,127 @@
+// Package testsupport starts real backends in Docker containers for
+// integration tests. Tests using it carry the integration build tag and are
+// skipped when Docker is unavailable:
+//
+//	go test -tags integration ./...
+package testsupport
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ContainerRequest describes a container to start
+type ContainerRequest struct {
+	Image        string
+	Env          map[string]string
+	ExposedPorts []string // container ports such as "5432/tcp", published on random host ports
+	Cmd          []string
+}
+
+// Container is a running container, removed when the test ends
+type Container struct {
+	ID    string
+	Host  string
+	ports map[string]string
+}
+
+// Port returns the host port a container port is published on
+func (c *Container) Port(containerPort string) string {
+	return c.ports[containerPort]
+}
+
+// Address returns host:port of a published container port
+func (c *Container) Address(containerPort string) string {
+	return c.Host + ":" + c.Port(containerPort)
+}
+
+// RequireDocker skips the test when no Docker daemon is reachable
+func RequireDocker(t testing.TB) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker is not installed")
+	}
+	if err := exec.Command("docker", "info").Run(); err != nil {
+		t.Skip("docker daemon is not reachable")
+	}
+}
+
+// StartContainer runs a container and removes it when the test ends
+func StartContainer(t testing.TB, req ContainerRequest) *Container {
+	t.Helper()
+	RequireDocker(t)
+
+	args := []string{"run", "--detach", "--rm"}
+	envKeys := make([]string, 0, len(req.Env))
+	for key := range req.Env {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+	for _, key := range envKeys {
+		args = append(args, "--env", key+"="+req.Env[key])
+	}
+	for _, port := range req.ExposedPorts {
+		args = append(args, "--publish", "127.0.0.1::"+port)
+	}
+	args = append(args, req.Image)
+	args = append(args, req.Cmd...)
+
+	out, err := docker(args...)
+	if err != nil {
+		t.Fatalf("failed to start %s: %v", req.Image, err)
+	}
+	container := &Container{ID: out, Host: "127.0.0.1", ports: make(map[string]string)}
+	t.Cleanup(func() {
+		if _, err := docker("rm", "--force", "--volumes", container.ID); err != nil {
+			t.Logf("failed to remove container %s: %v", container.ID, err)
+		}
+	})
+
+	for _, port := range req.ExposedPorts {
+		mapping, err := docker("port", container.ID, port)
+		if err != nil {
+			t.Fatalf("failed to get host port of %s: %v", port, err)
+		}
+		// e.g. "127.0.0.1:49153"
+		first := strings.Split(mapping, "\n")[0]
+		container.ports[port] = first[strings.LastIndex(first, ":")+1:]
+	}
+
+	return container
+}
+
+// WaitFor retries check until it succeeds or the timeout passes
+func WaitFor(t testing.TB, timeout time.Duration, check func(ctx context.Context) error) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := check(ctx)
+		cancel()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backend not ready after %s: %v", timeout, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// docker runs a docker command and returns its trimmed output
+func docker(args ...string) (string, error) {
+	out, err := exec.Command("docker", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
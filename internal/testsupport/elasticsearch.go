@@ -0,0 +1,52 @@
+package testsupport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/pkg/elasticsearch"
+)
+
+// ElasticsearchImage is the image of the Elasticsearch backend, matching docker-compose.yml
+const ElasticsearchImage = "elasticsearch:8.11.0"
+
+// SnapshotLocation is where the started Elasticsearch allows file system
+// snapshot repositories
+const SnapshotLocation = "/tmp/snapshots"
+
+// StartElasticsearch starts a single node Elasticsearch and returns a client
+// for the media index, created on connect
+func StartElasticsearch(t testing.TB) *elasticsearch.Client {
+	t.Helper()
+
+	container := StartContainer(t, ContainerRequest{
+		Image: ElasticsearchImage,
+		Env: map[string]string{
+			"discovery.type":         "single-node",
+			"xpack.security.enabled": "false",
+			"ES_JAVA_OPTS":           "-Xms512m -Xmx512m",
+			"path.repo":              SnapshotLocation,
+		},
+		ExposedPorts: []string{"9200/tcp"},
+	})
+
+	cfg := &config.Config{Elasticsearch: config.ElasticsearchConfig{
+		URL:   "http://" + container.Address("9200/tcp"),
+		Index: "media_test",
+	}}
+
+	var client *elasticsearch.Client
+	WaitFor(t, 2*time.Minute, func(ctx context.Context) error {
+		c, err := elasticsearch.NewClient(cfg)
+		if err != nil {
+			return err
+		}
+		client = c
+		return nil
+	})
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
@@ -0,0 +1,30 @@
+package testsupport
+
+import (
+	"testing"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/database"
+)
+
+// MediaFixtures returns a small catalog covering the media types, statuses and visibilities
+func MediaFixtures() []*domain.Media {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*domain.Media{
+		{ID: "fixture-video", Title: "Golang Concurrency Patterns", Description: "Channels, goroutines and select", Type: domain.TypeVideo, Status: domain.StatusReady, Format: "mp4", CreatedAt: created},
+		{ID: "fixture-podcast", Title: "Arabic Tech Podcast", Description: "Weekly talk about golang and the cloud", Type: domain.TypePodcast, Status: domain.StatusReady, Format: "mp3", CreatedAt: created.Add(time.Hour)},
+		{ID: "fixture-processing", Title: "Unfinished Upload", Type: domain.TypeVideo, Status: domain.StatusProcessing, Format: "mp4", CreatedAt: created.Add(2 * time.Hour)},
+		{ID: "fixture-private", Title: "Private Golang Draft", Type: domain.TypeVideo, Status: domain.StatusReady, Visibility: domain.VisibilityPrivate, Format: "mp4", CreatedAt: created.Add(3 * time.Hour)},
+	}
+}
+
+// SeedMedia inserts media into the database
+func SeedMedia(t testing.TB, conn *database.Connection, media ...*domain.Media) {
+	t.Helper()
+	for _, m := range media {
+		if err := conn.DB.Create(m).Error; err != nil {
+			t.Fatalf("failed to seed media %s: %v", m.ID, err)
+		}
+	}
+}
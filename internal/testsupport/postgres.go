@@ -0,0 +1,70 @@
+package testsupport
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/pkg/database"
+)
+
+// PostgresImage is the image of the Postgres backend, matching docker-compose.yml
+const PostgresImage = "postgres:15-alpine"
+
+// Postgres is a migrated database in a fresh Postgres container
+type Postgres struct {
+	Config *config.Config
+	Conn   *database.Connection
+}
+
+// StartPostgres starts Postgres, connects to it and applies the migrations
+func StartPostgres(t testing.TB) *Postgres {
+	t.Helper()
+
+	container := StartContainer(t, ContainerRequest{
+		Image: PostgresImage,
+		Env: map[string]string{
+			"POSTGRES_DB":       "thamaniyah_test",
+			"POSTGRES_USER":     "postgres",
+			"POSTGRES_PASSWORD": "postgres",
+		},
+		ExposedPorts: []string{"5432/tcp"},
+	})
+
+	port, _ := strconv.Atoi(container.Port("5432/tcp"))
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Driver:   config.DatabaseDriverPostgres,
+		Host:     container.Host,
+		Port:     port,
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "thamaniyah_test",
+		SSLMode:  "disable",
+	}}
+
+	var conn *database.Connection
+	WaitFor(t, time.Minute, func(ctx context.Context) error {
+		c, err := database.NewPostgresConnection(cfg)
+		if err != nil {
+			return err
+		}
+		if err := c.Ping(); err != nil {
+			c.Close()
+			return err
+		}
+		conn = c
+		return nil
+	})
+	t.Cleanup(func() { conn.Close() })
+
+	if err := database.SimpleAutoMigrate(conn.DB); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := database.CreateIndexes(conn.DB); err != nil {
+		t.Fatalf("failed to create indexes: %v", err)
+	}
+
+	return &Postgres{Config: cfg, Conn: conn}
+}
@@ -0,0 +1,43 @@
+package testsupport
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// RabbitMQImage is the image of the RabbitMQ backend, matching docker-compose.yml
+const RabbitMQImage = "rabbitmq:3-management-alpine"
+
+// StartRabbitMQ starts RabbitMQ and returns its AMQP URL once it accepts connections
+func StartRabbitMQ(t testing.TB) string {
+	t.Helper()
+
+	container := StartContainer(t, ContainerRequest{
+		Image: RabbitMQImage,
+		Env: map[string]string{
+			"RABBITMQ_DEFAULT_USER": "guest",
+			"RABBITMQ_DEFAULT_PASS": "guest",
+		},
+		ExposedPorts: []string{"5672/tcp"},
+	})
+
+	address := container.Address("5672/tcp")
+	WaitFor(t, time.Minute, func(ctx context.Context) error {
+		// The broker only answers the AMQP handshake once it is booted
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(2 * time.Second))
+		if _, err := conn.Write([]byte("AMQP\x00\x00\x09\x01")); err != nil {
+			return err
+		}
+		_, err = conn.Read(make([]byte, 1))
+		return err
+	})
+
+	return "amqp://guest:guest@" + address + "/"
+}
@@ -0,0 +1,114 @@
+// Package chromaprint computes acoustic fingerprints of audio with the fpcalc
+// tool of Chromaprint. Fingerprints of the same recording stay close after it
+// is re-encoded, trimmed or resampled, so they detect copies exact hashes miss.
+package chromaprint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"thamaniyah/pkg/media"
+)
+
+// maxStderrBytes bounds how much fpcalc output is kept for error messages
+const maxStderrBytes = 4096
+
+// maxShift bounds how many items fingerprints are shifted against each other when
+// compared, about 12 seconds of audio, to line up copies with trimmed intros
+const maxShift = 100
+
+// minOverlap is the least number of items compared for a similarity to count
+const minOverlap = 20
+
+// Fingerprint is the raw acoustic fingerprint of a recording
+type Fingerprint struct {
+	Duration float64  // seconds of audio fingerprinted
+	Items    []uint32 // one item per ~0.12s of audio
+}
+
+// Runner runs fpcalc as a subprocess, streaming media through stdin
+type Runner struct {
+	binary string
+	length int
+}
+
+// NewRunner creates a runner for the fpcalc binary at the given path that
+// fingerprints the first length seconds of audio, the fpcalc default when 0
+func NewRunner(binary string, length int) *Runner {
+	if binary == "" {
+		binary = "fpcalc"
+	}
+	return &Runner{binary: binary, length: length}
+}
+
+// Fingerprint reads media from src and returns the raw fingerprint of its audio
+func (r *Runner) Fingerprint(ctx context.Context, src io.Reader) (*Fingerprint, error) {
+	args := []string{"-raw", "-json"}
+	if r.length > 0 {
+		args = append(args, "-length", strconv.Itoa(r.length))
+	}
+	args = append(args, "-")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Stdin = src
+	cmd.Stdout = &stdout
+	cmd.Stderr = &media.LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("fpcalc failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseOutput(stdout.Bytes())
+}
+
+// parseOutput reads the JSON fpcalc prints for a raw fingerprint
+func parseOutput(output []byte) (*Fingerprint, error) {
+	var result struct {
+		Duration    float64  `json:"duration"`
+		Fingerprint []uint32 `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse fpcalc output: %w", err)
+	}
+	if len(result.Fingerprint) == 0 {
+		return nil, fmt.Errorf("fpcalc returned an empty fingerprint")
+	}
+
+	return &Fingerprint{Duration: result.Duration, Items: result.Fingerprint}, nil
+}
+
+// Similarity returns how alike two raw fingerprints are, from 0 for unrelated
+// audio to 1 for identical audio. The fingerprints are shifted against each
+// other to find the alignment whose items share the most bits.
+func Similarity(a, b []uint32) float64 {
+	best := 0.0
+	for shift := -maxShift; shift <= maxShift; shift++ {
+		if score, ok := alignedSimilarity(a, b, shift); ok && score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// alignedSimilarity compares a against b shifted by shift items, returning the
+// share of equal bits of the overlapping items
+func alignedSimilarity(a, b []uint32, shift int) (float64, bool) {
+	start := max(0, -shift)
+	end := min(len(a), len(b)-shift)
+	if end-start < minOverlap {
+		return 0, false
+	}
+
+	differing := 0
+	for i := start; i < end; i++ {
+		differing += bits.OnesCount32(a[i] ^ b[i+shift])
+	}
+	return 1 - float64(differing)/float64((end-start)*32), true
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// AdvisoryLock is a Postgres session-level advisory lock. The lock is held by a
+// dedicated connection, so it is released by Postgres when the holder dies.
+type AdvisoryLock struct {
+	db   *sql.DB
+	key  int64
+	name string
+
+	mu   sync.Mutex
+	conn *sql.Conn // set while the lock is held
+}
+
+// NewAdvisoryLock creates an advisory lock keyed by name
+func NewAdvisoryLock(conn *Connection, name string) (*AdvisoryLock, error) {
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+
+	return &AdvisoryLock{
+		db:   sqlDB,
+		key:  int64(hash.Sum64()),
+		name: name,
+	}, nil
+}
+
+// TryAcquire takes the lock without waiting. While the lock is held it checks
+// that the holding session is still alive, reporting false once it was lost.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if _, err := l.conn.ExecContext(ctx, "SELECT 1"); err == nil {
+			return true, nil
+		}
+		// The session ended and took the lock with it
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection for lock %s: %w", l.name, err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, fmt.Errorf("failed to acquire lock %s: %w", l.name, err)
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release gives up the lock if it is held
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	defer func() {
+		l.conn.Close()
+		l.conn = nil
+	}()
+
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", l.name, err)
+	}
+	return nil
+}
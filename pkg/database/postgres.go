@@ -1,7 +1,10 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
 	"time"
 
 	"thamaniyah/internal/config"
@@ -20,12 +23,19 @@ type Connection struct {
 func NewPostgresConnection(cfg *config.Config) (*Connection, error) {
 	dsn := cfg.DatabaseURL()
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	db, err := gorm.Open(postgres.Open(dsn), gormConfig(cfg, logger.Info))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	if err := applyQueryTimeout(db, cfg); err != nil {
+		return nil, err
+	}
+	if err := applyRetry(db, cfg); err != nil {
+		return nil, err
+	}
+	if err := applySlowQueryLog(db, cfg); err != nil {
+		return nil, err
+	}
 
 	// Get the underlying sql.DB to configure connection pool
 	sqlDB, err := db.DB()
@@ -41,6 +51,40 @@ func NewPostgresConnection(cfg *config.Config) (*Connection, error) {
 	return &Connection{DB: db}, nil
 }
 
+// gormConfig returns the GORM settings for the database config
+func gormConfig(cfg *config.Config, logLevel logger.LogLevel) *gorm.Config {
+	return &gorm.Config{
+		// Slow statements are reported by the slow query log instead
+		Logger: logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			LogLevel: logLevel,
+			Colorful: true,
+		}),
+		PrepareStmt:            cfg.Database.PrepareStmt,
+		SkipDefaultTransaction: cfg.Database.SkipDefaultTransaction,
+	}
+}
+
+// applyQueryTimeout sets the default query deadline of the database config
+func applyQueryTimeout(db *gorm.DB, cfg *config.Config) error {
+	if cfg.Database.QueryTimeoutMs <= 0 {
+		return nil
+	}
+	return registerQueryTimeout(db, time.Duration(cfg.Database.QueryTimeoutMs)*time.Millisecond)
+}
+
+// applyRetry sets the retries of transient failures of the database config
+func applyRetry(db *gorm.DB, cfg *config.Config) error {
+	return registerRetry(db, max(cfg.Database.RetryAttempts, 1), time.Duration(cfg.Database.RetryBackoffMs)*time.Millisecond)
+}
+
+// applySlowQueryLog sets the slow statement threshold of the database config
+func applySlowQueryLog(db *gorm.DB, cfg *config.Config) error {
+	if cfg.SlowLog.QueryMs <= 0 {
+		return nil
+	}
+	return registerSlowQueryLog(db, time.Duration(cfg.SlowLog.QueryMs)*time.Millisecond)
+}
+
 // Close closes the database connection
 func (c *Connection) Close() error {
 	sqlDB, err := c.DB.DB()
@@ -76,3 +120,28 @@ func (c *Connection) Health() map[string]interface{} {
 func (c *Connection) Transaction(fn func(*gorm.DB) error) error {
 	return c.DB.Transaction(fn)
 }
+
+// txContextKey is the context key of the transaction InTransaction runs in
+type txContextKey struct{}
+
+// InTransaction executes a function within a database transaction carried by
+// the context it is given, so the repositories it calls write through that
+// transaction when they get their session from DB. It joins the transaction
+// of ctx when there is one.
+func (c *Connection) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return c.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// DB returns a session of the transaction carried by ctx, or of db when there
+// is none, bound to ctx
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// queryTimeoutCancelKey stores the cancel function of the deadline added to a statement
+	queryTimeoutCancelKey = "database:query_timeout_cancel"
+	// queryTimeoutContextKey stores the context of the statement before the deadline was added
+	queryTimeoutContextKey = "database:query_timeout_context"
+)
+
+// registerQueryTimeout gives queries, creates, updates and deletes without a
+// deadline one of timeout, so a stuck query cannot hold a connection forever.
+// Row and Raw(...).Rows() are left alone, their rows are read after the callbacks ran.
+// The statement context is restored once the operation is done, so a chain
+// running several operations, such as Count then Find, gives each its own deadline.
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	before := func(tx *gorm.DB) {
+		if tx.Statement.Context == nil {
+			tx.Statement.Context = context.Background()
+		}
+		if _, ok := tx.Statement.Context.Deadline(); ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(tx.Statement.Context, timeout)
+		tx.InstanceSet(queryTimeoutContextKey, tx.Statement.Context)
+		tx.InstanceSet(queryTimeoutCancelKey, cancel)
+		tx.Statement.Context = ctx
+	}
+	after := func(tx *gorm.DB) {
+		cancel, ok := tx.InstanceGet(queryTimeoutCancelKey)
+		if !ok || cancel == nil {
+			return
+		}
+		cancel.(context.CancelFunc)()
+		if ctx, ok := tx.InstanceGet(queryTimeoutContextKey); ok {
+			tx.Statement.Context = ctx.(context.Context)
+		}
+		// The statement may run again, without a deadline of its own then
+		tx.InstanceSet(queryTimeoutCancelKey, nil)
+	}
+
+	callbacks := db.Callback()
+	err := errors.Join(
+		callbacks.Create().Before("*").Register("database:query_timeout", before),
+		callbacks.Create().After("*").Register("database:query_timeout_cancel", after),
+		callbacks.Query().Before("*").Register("database:query_timeout", before),
+		callbacks.Query().After("*").Register("database:query_timeout_cancel", after),
+		callbacks.Update().Before("*").Register("database:query_timeout", before),
+		callbacks.Update().After("*").Register("database:query_timeout_cancel", after),
+		callbacks.Delete().Before("*").Register("database:query_timeout", before),
+		callbacks.Delete().After("*").Register("database:query_timeout_cancel", after),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register query timeout: %w", err)
+	}
+	return nil
+}
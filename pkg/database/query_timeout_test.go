@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queryTimeoutRow struct {
+	ID   uint
+	Name string
+}
+
+func TestQueryTimeout_ChainedOperations(t *testing.T) {
+	// Given a connection with a default query deadline
+	conn, err := NewSQLiteConnection(&config.Config{Database: config.DatabaseConfig{
+		SQLitePath:     filepath.Join(t.TempDir(), "test.db"),
+		QueryTimeoutMs: 5000,
+	}})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, conn.DB.AutoMigrate(&queryTimeoutRow{}))
+	require.NoError(t, conn.DB.Create(&[]queryTimeoutRow{{Name: "a"}, {Name: "b"}, {Name: "c"}}).Error)
+
+	// When a chain counts, then finds
+	ctx := context.Background()
+	query := conn.DB.WithContext(ctx).Model(&queryTimeoutRow{}).Where("name <> ?", "c")
+	var total int64
+	require.NoError(t, query.Count(&total).Error)
+	var rows []queryTimeoutRow
+	err = query.Limit(1).Find(&rows).Error
+
+	// Then the find gets a deadline of its own instead of the cancelled one of the count
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, ctx, query.Statement.Context)
+}
+
+func TestQueryTimeout_KeepsCallerDeadline(t *testing.T) {
+	conn, err := NewSQLiteConnection(&config.Config{Database: config.DatabaseConfig{
+		SQLitePath:     filepath.Join(t.TempDir(), "test.db"),
+		QueryTimeoutMs: 5000,
+	}})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, conn.DB.AutoMigrate(&queryTimeoutRow{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	query := conn.DB.WithContext(ctx).Model(&queryTimeoutRow{})
+	var total int64
+	require.NoError(t, query.Count(&total).Error)
+	assert.Equal(t, ctx, query.Statement.Context)
+}
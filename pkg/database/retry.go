@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"thamaniyah/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+// transientSQLStates are the Postgres error codes of failures that may not
+// happen again: lost connections, server restarts, exhausted connection slots,
+// serialization failures and deadlocks
+var transientSQLStates = map[string]bool{
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+	"53300": true, // too_many_connections
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// IsTransient reports whether err is a database failure worth retrying, like a
+// lost connection or a server that is restarting. Query timeouts are not.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		// Class 08 holds the connection exceptions
+		return strings.HasPrefix(state, "08") || transientSQLStates[state]
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.As(err, &netErr) ||
+		strings.Contains(err.Error(), "database is locked") // SQLite busy beyond the busy timeout
+}
+
+// registerRetry retries queries failing with a transient error up to attempts
+// times in total, waiting backoff, then twice as long, between attempts. Only
+// queries outside transactions are retried: reads are safe to repeat, writes
+// may have been applied before the connection was lost. Transient errors left
+// after retrying, of every kind of statement, wrap domain.ErrServiceUnavailable.
+func registerRetry(db *gorm.DB, attempts int, backoff time.Duration) error {
+	markUnavailable := func(tx *gorm.DB) {
+		if IsTransient(tx.Error) && !errors.Is(tx.Error, domain.ErrServiceUnavailable) {
+			tx.Error = fmt.Errorf("%w: %w", domain.ErrServiceUnavailable, tx.Error)
+		}
+	}
+
+	callback := db.Callback()
+	err := errors.Join(
+		callback.Query().Replace("gorm:query", retryingQuery(attempts, backoff)),
+		callback.Create().After("*").Register("database:unavailable", markUnavailable),
+		callback.Query().After("*").Register("database:unavailable", markUnavailable),
+		callback.Update().After("*").Register("database:unavailable", markUnavailable),
+		callback.Delete().After("*").Register("database:unavailable", markUnavailable),
+		callback.Row().After("*").Register("database:unavailable", markUnavailable),
+		callback.Raw().After("*").Register("database:unavailable", markUnavailable),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register query retry: %w", err)
+	}
+	return nil
+}
+
+// retryingQuery is the query callback of GORM, sending the query again while
+// it fails with a transient error. Rows being read are never retried.
+func retryingQuery(attempts int, backoff time.Duration) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Error != nil {
+			return
+		}
+		callbacks.BuildQuerySQL(tx)
+		if tx.DryRun || tx.Error != nil {
+			return
+		}
+
+		_, inTransaction := tx.Statement.ConnPool.(gorm.TxCommitter)
+		wait := backoff
+		for attempt := 1; ; attempt++ {
+			rows, err := tx.Statement.ConnPool.QueryContext(tx.Statement.Context, tx.Statement.SQL.String(), tx.Statement.Vars...)
+			if err == nil {
+				defer func() {
+					tx.AddError(rows.Close())
+				}()
+				gorm.Scan(rows, tx, 0)
+
+				if tx.Statement.Result != nil {
+					tx.Statement.Result.RowsAffected = tx.RowsAffected
+				}
+				return
+			}
+			if inTransaction || attempt >= attempts || !IsTransient(err) {
+				tx.AddError(err)
+				return
+			}
+
+			select {
+			case <-tx.Statement.Context.Done():
+				tx.AddError(err)
+				return
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+	}
+}
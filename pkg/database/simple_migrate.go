@@ -9,17 +9,74 @@ import (
 	"gorm.io/gorm"
 )
 
+// dataMigrations fill the columns AutoMigrate added to existing rows. They run
+// on every start, so each must leave already migrated rows alone.
+var dataMigrations = []func(db *gorm.DB) error{
+	// Relays checkpointed by ID before messages were marked as published
+	func(db *gorm.DB) error {
+		return db.Exec(`UPDATE outbox_messages SET published_at = created_at
+			WHERE published_at IS NULL AND id <= (SELECT COALESCE(MIN(last_message_id), 0) FROM outbox_checkpoints)`).Error
+	},
+	// API keys were granted admin access by a flag before they had a role
+	func(db *gorm.DB) error {
+		if !db.Migrator().HasColumn(&domain.APIKey{}, "admin") {
+			return nil
+		}
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("UPDATE api_keys SET role = ? WHERE admin", domain.RoleAdmin).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&domain.APIKey{}, "admin")
+		})
+	},
+}
+
 // SimpleAutoMigrate performs simple auto-migration for domain models
 func SimpleAutoMigrate(db *gorm.DB) error {
 	// Auto-migrate all domain models
 	err := db.AutoMigrate(
 		&domain.Media{},
 		&domain.SearchIndex{},
+		&domain.SearchIndexRemoval{},
+		&domain.MaintenanceStatus{},
+		&domain.SearchSuggestion{},
+		&domain.NotificationPreference{},
+		&domain.OutboxMessage{},
+		&domain.OutboxCheckpoint{},
+		&domain.ShareLink{},
+		&domain.TranscodePreset{},
+		&domain.ContentKey{},
+		&domain.AuditEntry{},
+		&domain.ShowTemplate{},
+		&domain.BulkJob{},
+		&domain.FeaturedItem{},
+		&domain.PlaybackProgress{},
+		&domain.PlayEvent{},
+		&domain.Download{},
+		&domain.Person{},
+		&domain.PersonCredit{},
+		&domain.UsageRecord{},
+		&domain.AbuseReport{},
+		&domain.APIKey{},
+		&domain.SearchQueryCount{},
+		&domain.DigestDelivery{},
+		&domain.MediaFingerprint{},
+		&domain.Transcript{},
+		&domain.MetadataSuggestion{},
+		&domain.ExplicitLanguageMatch{},
+		&domain.LiveStream{},
+		&domain.MediaRendition{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to auto-migrate: %w", err)
 	}
 
+	for _, migrate := range dataMigrations {
+		if err := migrate(db); err != nil {
+			return fmt.Errorf("failed to migrate data: %w", err)
+		}
+	}
+
 	log.Println("✓ Database migration completed successfully")
 	return nil
 }
@@ -29,6 +86,8 @@ func CreateIndexes(db *gorm.DB) error {
 	// Drop existing indexes that might conflict
 	dropIndexes := []string{
 		"DROP INDEX IF EXISTS idx_media_tags",
+		"DROP INDEX IF EXISTS idx_search_media_id",   // superseded by the unique idx_search_media_id_unique
+		"DROP INDEX IF EXISTS idx_search_title_trgm", // suggestions are served from search_suggestions
 	}
 
 	for _, dropSQL := range dropIndexes {
@@ -39,8 +98,17 @@ func CreateIndexes(db *gorm.DB) error {
 
 	indexes := []string{
 		"CREATE INDEX IF NOT EXISTS idx_media_created_at ON media_files(created_at DESC)",
-		"CREATE INDEX IF NOT EXISTS idx_search_content ON search_index USING GIN(to_tsvector('english', content))",
-		"CREATE INDEX IF NOT EXISTS idx_search_media_id ON search_index(media_id)",
+		// Conflict target of the search index upsert
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_search_media_id_unique ON search_index(media_id)",
+	}
+	// SQLite searches through its own FTS5 table instead
+	if db.Dialector.Name() != "sqlite" {
+		indexes = append(indexes,
+			"CREATE INDEX IF NOT EXISTS idx_search_content ON search_index USING GIN(to_tsvector('english', content))",
+			// Trigram index serving the substring and similarity matches of suggestions
+			"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+			"CREATE INDEX IF NOT EXISTS idx_search_suggestions_term_trgm ON search_suggestions USING GIN(term gin_trgm_ops)",
+		)
 	}
 
 	for _, indexSQL := range indexes {
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyAPIKey is an API key as stored before keys had a role
+type legacyAPIKey struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"not null"`
+	TenantID  string `gorm:"type:varchar(100);not null;index"`
+	Admin     bool   `gorm:"not null;default:false"`
+	Prefix    string `gorm:"type:varchar(16);not null"`
+	KeyHash   string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (legacyAPIKey) TableName() string {
+	return "api_keys"
+}
+
+func TestSimpleAutoMigrate_APIKeyRoles(t *testing.T) {
+	// Given API keys stored with the admin flag
+	conn, err := NewSQLiteConnection(&config.Config{Database: config.DatabaseConfig{
+		SQLitePath: filepath.Join(t.TempDir(), "test.db"),
+	}})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, conn.DB.AutoMigrate(&legacyAPIKey{}))
+	require.NoError(t, conn.DB.Create(&[]legacyAPIKey{
+		{ID: "k1", Name: "ops", TenantID: domain.DefaultTenantID, Admin: true, KeyHash: "h1"},
+		{ID: "k2", Name: "app", TenantID: domain.DefaultTenantID, KeyHash: "h2"},
+	}).Error)
+
+	// When migrated, twice as on every start
+	require.NoError(t, SimpleAutoMigrate(conn.DB))
+	require.NoError(t, SimpleAutoMigrate(conn.DB))
+
+	// Then admin keys keep admin access and the others are viewers
+	var keys []domain.APIKey
+	require.NoError(t, conn.DB.Order("id").Find(&keys).Error)
+	require.Len(t, keys, 2)
+	assert.Equal(t, domain.RoleAdmin, keys[0].Role)
+	assert.Equal(t, domain.RoleViewer, keys[1].Role)
+	assert.False(t, conn.DB.Migrator().HasColumn("api_keys", "admin"))
+}
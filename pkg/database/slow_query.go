@@ -0,0 +1,72 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"thamaniyah/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+const slowQueryStartKey = "database:slow_query_start"
+
+var (
+	sqlStringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumber        = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	sqlPlaceholder   = regexp.MustCompile(`\$\d+`)
+	sqlValueList     = regexp.MustCompile(`\?(?:\s*,\s*\?)+`)
+	sqlSpaces        = regexp.MustCompile(`\s+`)
+)
+
+// registerSlowQueryLog logs and counts the statements taking longer than
+// threshold, by fingerprint
+func registerSlowQueryLog(db *gorm.DB, threshold time.Duration) error {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(slowQueryStartKey, time.Now())
+	}
+	after := func(tx *gorm.DB) {
+		start, ok := tx.InstanceGet(slowQueryStartKey)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start.(time.Time))
+		if elapsed > threshold {
+			metrics.ObserveSlow(tx.Statement.Context, metrics.KindSQL, SQLFingerprint(tx.Statement.SQL.String()), elapsed, threshold)
+		}
+	}
+
+	callback := db.Callback()
+	err := errors.Join(
+		callback.Create().Before("*").Register("database:slow_query", before),
+		callback.Create().After("*").Register("database:slow_query_log", after),
+		callback.Query().Before("*").Register("database:slow_query", before),
+		callback.Query().After("*").Register("database:slow_query_log", after),
+		callback.Update().Before("*").Register("database:slow_query", before),
+		callback.Update().After("*").Register("database:slow_query_log", after),
+		callback.Delete().Before("*").Register("database:slow_query", before),
+		callback.Delete().After("*").Register("database:slow_query_log", after),
+		callback.Row().Before("*").Register("database:slow_query", before),
+		callback.Row().After("*").Register("database:slow_query_log", after),
+		callback.Raw().Before("*").Register("database:slow_query", before),
+		callback.Raw().After("*").Register("database:slow_query_log", after),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register slow query log: %w", err)
+	}
+	return nil
+}
+
+// SQLFingerprint returns the statement with its literals and placeholders
+// replaced by ?, and value lists collapsed, so statements differing only by
+// their values share a fingerprint
+func SQLFingerprint(sql string) string {
+	fingerprint := sqlStringLiteral.ReplaceAllString(sql, "?")
+	fingerprint = sqlPlaceholder.ReplaceAllString(fingerprint, "?")
+	fingerprint = sqlNumber.ReplaceAllString(fingerprint, "?")
+	fingerprint = sqlValueList.ReplaceAllString(fingerprint, "?, ...")
+	return strings.TrimSpace(sqlSpaces.ReplaceAllString(fingerprint, " "))
+}
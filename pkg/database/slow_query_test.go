@@ -0,0 +1,76 @@
+package database
+
+import (
+	"expvar"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/pkg/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLFingerprint(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{
+			sql:      "SELECT * FROM media WHERE id = 'abc' AND views > 10",
+			expected: "SELECT * FROM media WHERE id = ? AND views > ?",
+		},
+		{
+			sql:      "SELECT * FROM media WHERE title = 'it''s'",
+			expected: "SELECT * FROM media WHERE title = ?",
+		},
+		{
+			sql:      "SELECT * FROM media WHERE id IN ($1, $2, $3) LIMIT $4",
+			expected: "SELECT * FROM media WHERE id IN (?, ...) LIMIT ?",
+		},
+		{
+			sql:      "SELECT *\n  FROM   media\n  WHERE id IN (?,?)",
+			expected: "SELECT * FROM media WHERE id IN (?, ...)",
+		},
+		{
+			sql:      "UPDATE media_v2 SET views = 3.5",
+			expected: "UPDATE media_v2 SET views = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SQLFingerprint(tt.sql))
+		})
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	// Given a connection counting every statement as slow
+	conn, err := NewSQLiteConnection(&config.Config{Database: config.DatabaseConfig{
+		SQLitePath: filepath.Join(t.TempDir(), "test.db"),
+	}})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, conn.DB.AutoMigrate(&queryTimeoutRow{}))
+	require.NoError(t, registerSlowQueryLog(conn.DB, time.Nanosecond))
+
+	slowOperations := expvar.Get("slow_operations").(*expvar.Map)
+	count := func() int64 {
+		if value, ok := slowOperations.Get(metrics.KindSQL).(*expvar.Int); ok {
+			return value.Value()
+		}
+		return 0
+	}
+	before := count()
+
+	// When statements run
+	require.NoError(t, conn.DB.Create(&queryTimeoutRow{Name: "a"}).Error)
+	var rows []queryTimeoutRow
+	require.NoError(t, conn.DB.Where("name = ?", "a").Find(&rows).Error)
+
+	// Then each is counted
+	assert.Equal(t, before+2, count())
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"thamaniyah/internal/config"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewSQLiteConnection creates a new SQLite connection to the database file of the
+// config, for local development without a database server
+func NewSQLiteConnection(cfg *config.Config) (*Connection, error) {
+	path := cfg.Database.SQLitePath
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	// WAL lets readers proceed while a write is in progress, and the busy
+	// timeout makes concurrent writers wait instead of failing
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=on", path)
+	db, err := gorm.Open(sqlite.Open(dsn), gormConfig(cfg, logger.Warn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := applyQueryTimeout(db, cfg); err != nil {
+		return nil, err
+	}
+	if err := applyRetry(db, cfg); err != nil {
+		return nil, err
+	}
+	if err := applySlowQueryLog(db, cfg); err != nil {
+		return nil, err
+	}
+
+	return &Connection{DB: db}, nil
+}
+
+// NewConnection connects to the database driver selected by the config
+func NewConnection(cfg *config.Config) (*Connection, error) {
+	switch cfg.Database.Driver {
+	case "", config.DatabaseDriverPostgres:
+		return NewPostgresConnection(cfg)
+	case config.DatabaseDriverSQLite:
+		return NewSQLiteConnection(cfg)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+	}
+}
+
+// IsSQLite reports whether the connection is to SQLite
+func (c *Connection) IsSQLite() bool {
+	return c.DB.Dialector.Name() == "sqlite"
+}
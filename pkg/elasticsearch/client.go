@@ -7,18 +7,27 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"strings"
+	"time"
 
 	"thamaniyah/internal/config"
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/metrics"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
 )
 
+// indexVersionSuffix names the index created behind the alias the client
+// uses, so the index can later be replaced without renaming the alias
+const indexVersionSuffix = "-v1"
+
 // Client wraps the Elasticsearch client with additional functionality
 type Client struct {
-	es    *elasticsearch.Client
-	index string
+	es            *elasticsearch.Client
+	index         string
+	slowThreshold time.Duration // requests taking longer are logged and counted, 0 disables
 }
 
 // NewClient creates a new Elasticsearch client
@@ -33,9 +42,15 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to create elasticsearch client: %w", err)
 	}
 
+	index, err := ResolveIndexName(cfg.Elasticsearch.Index, cfg.Elasticsearch.Environment, cfg.Elasticsearch.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
 	client := &Client{
-		es:    es,
-		index: cfg.Elasticsearch.Index,
+		es:            es,
+		index:         index,
+		slowThreshold: time.Duration(cfg.SlowLog.SearchMs) * time.Millisecond,
 	}
 
 	// Check connection
@@ -66,7 +81,38 @@ func (c *Client) ping(ctx context.Context) error {
 	return nil
 }
 
-// createIndexIfNotExists creates the media index if it doesn't exist
+// ResolveIndexName replaces the {env} and {tenant} placeholders of an index
+// name template, like media-{env}-{tenant}, and checks the result is a valid
+// index name. Names are lowercased as Elasticsearch requires.
+func ResolveIndexName(template, env, tenant string) (string, error) {
+	placeholders := map[string]string{"{env}": env, "{tenant}": tenant}
+	name := template
+	for placeholder, value := range placeholders {
+		if strings.Contains(name, placeholder) && strings.TrimSpace(value) == "" {
+			return "", fmt.Errorf("index name %q uses %s but no value is configured", template, placeholder)
+		}
+		name = strings.ReplaceAll(name, placeholder, strings.TrimSpace(value))
+	}
+	name = strings.ToLower(name)
+
+	switch {
+	case name == "" || name == "." || name == "..":
+		return "", fmt.Errorf("invalid index name %q", name)
+	case strings.ContainsAny(name, "{}"):
+		return "", fmt.Errorf("index name %q has an unknown placeholder, only {env} and {tenant} are supported", template)
+	case strings.ContainsAny(name, "\\/*?\"<>|, #:"):
+		return "", fmt.Errorf("index name %q contains a character Elasticsearch does not allow", name)
+	case strings.ContainsAny(name[:1], "-_+"):
+		return "", fmt.Errorf("index name %q must not start with -, _ or +", name)
+	case len(name) > 255-len(indexVersionSuffix):
+		return "", fmt.Errorf("index name %q is too long", name)
+	}
+	return name, nil
+}
+
+// createIndexIfNotExists creates the media index if it doesn't exist. The
+// index is created as <name>-v1 with <name> as its alias, which every request
+// goes through; an existing index or alias named <name> is used as it is.
 func (c *Client) createIndexIfNotExists(ctx context.Context) error {
 	// Check if index exists
 	res, err := c.es.Indices.Exists([]string{c.index})
@@ -75,10 +121,10 @@ func (c *Client) createIndexIfNotExists(ctx context.Context) error {
 	}
 	defer res.Body.Close()
 
-	// If index exists (200), return
+	// If index exists (200), add the fields introduced since it was created
 	if res.StatusCode == 200 {
 		log.Printf("Elasticsearch index '%s' already exists", c.index)
-		return nil
+		return c.addFieldMappings(ctx)
 	}
 
 	// Create index with mapping
@@ -123,6 +169,40 @@ func (c *Client) createIndexIfNotExists(ctx context.Context) error {
 				"format": {
 					"type": "keyword"
 				},
+				"age_rating": {
+					"type": "keyword"
+				},
+				"explicit": {
+					"type": "boolean"
+				},
+				"show_id": {
+					"type": "keyword"
+				},
+				"show_group": {
+					"type": "keyword"
+				},
+				"show_title": {
+					"type": "text",
+					"analyzer": "standard"
+				},
+				"categories": {
+					"type": "text",
+					"analyzer": "standard"
+				},
+				"hosts": {
+					"type": "text",
+					"analyzer": "standard"
+				},
+				"guests": {
+					"type": "text",
+					"analyzer": "standard"
+				},
+				"tags": {
+					"type": "keyword"
+				},
+				"audio_languages": {
+					"type": "keyword"
+				},
 				"created_at": {
 					"type": "date"
 				},
@@ -144,9 +224,23 @@ func (c *Client) createIndexIfNotExists(ctx context.Context) error {
 		}
 	}`
 
+	// Point the alias at the index as it is created, so it never exists without one
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(mapping), &body); err != nil {
+		return fmt.Errorf("failed to decode index mapping: %w", err)
+	}
+	body["aliases"] = map[string]interface{}{
+		c.index: map[string]interface{}{"is_write_index": true},
+	}
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index mapping: %w", err)
+	}
+
+	index := c.index + indexVersionSuffix
 	res, err = c.es.Indices.Create(
-		c.index,
-		c.es.Indices.Create.WithBody(strings.NewReader(mapping)),
+		index,
+		c.es.Indices.Create.WithBody(bytes.NewReader(bodyBytes)),
 		c.es.Indices.Create.WithContext(ctx),
 	)
 	if err != nil {
@@ -158,7 +252,60 @@ func (c *Client) createIndexIfNotExists(ctx context.Context) error {
 		return fmt.Errorf("failed to create index: %s", res.Status())
 	}
 
-	log.Printf("Elasticsearch index '%s' created successfully", c.index)
+	log.Printf("Elasticsearch index '%s' created successfully with alias '%s'", index, c.index)
+	return nil
+}
+
+// addFieldMappings maps fields added after the index was created, before
+// documents carrying them are indexed with a dynamic mapping
+func (c *Client) addFieldMappings(ctx context.Context) error {
+	mapping := `{
+		"properties": {
+			"show_id": {
+				"type": "keyword"
+			},
+			"show_group": {
+				"type": "keyword"
+			},
+			"show_title": {
+				"type": "text",
+				"analyzer": "standard"
+			},
+			"categories": {
+				"type": "text",
+				"analyzer": "standard"
+			},
+			"hosts": {
+				"type": "text",
+				"analyzer": "standard"
+			},
+			"guests": {
+				"type": "text",
+				"analyzer": "standard"
+			},
+			"tags": {
+				"type": "keyword"
+			},
+			"audio_languages": {
+				"type": "keyword"
+			}
+		}
+	}`
+
+	res, err := c.es.Indices.PutMapping(
+		[]string{c.index},
+		strings.NewReader(mapping),
+		c.es.Indices.PutMapping.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to update index mapping: %s", res.Status())
+	}
+
 	return nil
 }
 
@@ -176,14 +323,15 @@ func (c *Client) IndexDocument(ctx context.Context, docID string, doc interface{
 		Refresh:    "true",
 	}
 
+	defer c.observeSlow(ctx, time.Now(), "index")
 	res, err := req.Do(ctx, c.es)
 	if err != nil {
-		return fmt.Errorf("failed to index document: %w", err)
+		return fmt.Errorf("failed to index document: %w", unavailable(ctx, err))
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return fmt.Errorf("index request failed: %s", res.Status())
+		return responseError("index request failed", res)
 	}
 
 	return nil
@@ -197,14 +345,15 @@ func (c *Client) DeleteDocument(ctx context.Context, docID string) error {
 		Refresh:    "true",
 	}
 
+	defer c.observeSlow(ctx, time.Now(), "delete")
 	res, err := req.Do(ctx, c.es)
 	if err != nil {
-		return fmt.Errorf("failed to delete document: %w", err)
+		return fmt.Errorf("failed to delete document: %w", unavailable(ctx, err))
 	}
 	defer res.Body.Close()
 
 	if res.IsError() && res.StatusCode != 404 {
-		return fmt.Errorf("delete request failed: %s", res.Status())
+		return responseError("delete request failed", res)
 	}
 
 	return nil
@@ -222,14 +371,15 @@ func (c *Client) Search(ctx context.Context, query map[string]interface{}) (*Sea
 		Body:  bytes.NewReader(queryBytes),
 	}
 
+	defer c.observeSlow(ctx, time.Now(), "search "+QueryFingerprint(query))
 	res, err := req.Do(ctx, c.es)
 	if err != nil {
-		return nil, fmt.Errorf("search request failed: %w", err)
+		return nil, fmt.Errorf("search request failed: %w", unavailable(ctx, err))
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return nil, fmt.Errorf("search failed: %s", res.Status())
+		return nil, responseError("search failed", res)
 	}
 
 	body, err := io.ReadAll(res.Body)
@@ -286,14 +436,15 @@ func (c *Client) BulkIndex(ctx context.Context, documents []BulkDocument) error
 		Refresh: "true",
 	}
 
+	defer c.observeSlow(ctx, time.Now(), "bulk")
 	res, err := req.Do(ctx, c.es)
 	if err != nil {
-		return fmt.Errorf("bulk request failed: %w", err)
+		return fmt.Errorf("bulk request failed: %w", unavailable(ctx, err))
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return fmt.Errorf("bulk index failed: %s", res.Status())
+		return responseError("bulk index failed", res)
 	}
 
 	return nil
@@ -317,19 +468,84 @@ func (c *Client) ClearIndex(ctx context.Context) error {
 		Body:  bytes.NewReader(queryBytes),
 	}
 
+	defer c.observeSlow(ctx, time.Now(), "delete_by_query")
 	res, err := req.Do(ctx, c.es)
 	if err != nil {
-		return fmt.Errorf("delete by query failed: %w", err)
+		return fmt.Errorf("delete by query failed: %w", unavailable(ctx, err))
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return fmt.Errorf("clear index failed: %s", res.Status())
+		return responseError("clear index failed", res)
 	}
 
 	return nil
 }
 
+// unavailable wraps a failed request in domain.ErrServiceUnavailable, unless
+// the caller gave up on it
+func unavailable(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", domain.ErrServiceUnavailable, err)
+}
+
+// responseError describes an error response. Server errors and rejections of
+// an overloaded cluster wrap domain.ErrServiceUnavailable.
+func responseError(message string, res *esapi.Response) error {
+	if res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%s: %w: %s", message, domain.ErrServiceUnavailable, res.Status())
+	}
+	return fmt.Errorf("%s: %s", message, res.Status())
+}
+
+// observeSlow logs and counts the request started at start if it was slow
+func (c *Client) observeSlow(ctx context.Context, start time.Time, fingerprint string) {
+	metrics.ObserveSlow(ctx, metrics.KindElasticsearch, fingerprint, time.Since(start), c.slowThreshold)
+}
+
+// QueryFingerprint returns the structure of a query with its values replaced
+// by ?, so queries differing only by their values share a fingerprint
+func QueryFingerprint(query map[string]interface{}) string {
+	fingerprint, err := json.Marshal(queryShape(query))
+	if err != nil {
+		return "?"
+	}
+	return string(fingerprint)
+}
+
+// queryShape replaces the values of a decoded JSON query by ?, keeping its keys and clauses
+func queryShape(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		shape := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			shape[key] = queryShape(field)
+		}
+		return shape
+	case []map[string]interface{}:
+		shape := make([]interface{}, len(value))
+		for i, clause := range value {
+			shape[i] = queryShape(clause)
+		}
+		return shape
+	case []interface{}:
+		shape := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			itemShape := queryShape(item)
+			if itemShape == "?" {
+				// Lists of values, like terms, share a fingerprint whatever their length
+				return "?"
+			}
+			shape = append(shape, itemShape)
+		}
+		return shape
+	default:
+		return "?"
+	}
+}
+
 // Close closes the client connection
 func (c *Client) Close() error {
 	// The go-elasticsearch client doesn't require explicit closing
@@ -340,16 +556,33 @@ func (c *Client) Close() error {
 
 // SearchResponse represents Elasticsearch search response
 type SearchResponse struct {
-	Hits struct {
-		Total struct {
-			Value int64 `json:"value"`
-		} `json:"total"`
-		Hits []struct {
-			ID     string                 `json:"_id"`
-			Score  float64                `json:"_score"`
-			Source map[string]interface{} `json:"_source"`
-		} `json:"hits"`
-	} `json:"hits"`
+	Hits SearchHits `json:"hits"`
+
+	// Results of single value metric aggregations, like cardinality, by name
+	Aggregations map[string]struct {
+		Value float64 `json:"value"`
+	} `json:"aggregations,omitempty"`
+}
+
+// SearchHits are the hits of a search, or the inner hits of a collapsed hit
+type SearchHits struct {
+	Total struct {
+		Value int64 `json:"value"`
+	} `json:"total"`
+	Hits []SearchHit `json:"hits"`
+}
+
+// SearchHit is a document matching a search
+type SearchHit struct {
+	ID     string                 `json:"_id"`
+	Score  float64                `json:"_score"`
+	Source map[string]interface{} `json:"_source"`
+	Sort   []interface{}          `json:"sort,omitempty"` // sort values, for search_after paging
+
+	// Hits of the collapsed group of the hit, by inner_hits name
+	InnerHits map[string]struct {
+		Hits SearchHits `json:"hits"`
+	} `json:"inner_hits,omitempty"`
 }
 
 // BulkDocument represents a document for bulk indexing
@@ -0,0 +1,69 @@
+package elasticsearch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFingerprint(t *testing.T) {
+	query := func(text string, categories []interface{}, from int) map[string]interface{} {
+		return map[string]interface{}{
+			"from": from,
+			"query": map[string]interface{}{
+				"bool": map[string]interface{}{
+					"must": []map[string]interface{}{
+						{"multi_match": map[string]interface{}{"query": text, "fields": []interface{}{"title^2", "description"}}},
+					},
+					"filter": []interface{}{
+						map[string]interface{}{"terms": map[string]interface{}{"category": categories}},
+					},
+				},
+			},
+		}
+	}
+
+	fingerprint := QueryFingerprint(query("podcast", []interface{}{"tech"}, 0))
+
+	assert.Equal(t, `{"from":"?","query":{"bool":{"filter":[{"terms":{"category":"?"}}],"must":[{"multi_match":{"fields":"?","query":"?"}}]}}}`, fingerprint)
+	// Queries differing only by their values share the fingerprint
+	assert.Equal(t, fingerprint, QueryFingerprint(query("news", []interface{}{"tech", "science", "arts"}, 20)))
+	// A different structure does not
+	assert.NotEqual(t, fingerprint, QueryFingerprint(map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}}))
+}
+
+func TestResolveIndexName(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		env      string
+		tenant   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "plain name", template: "media", env: "production", tenant: "acme", expected: "media"},
+		{name: "env and tenant", template: "media-{env}-{tenant}", env: "staging", tenant: "acme", expected: "media-staging-acme"},
+		{name: "lowercased", template: "Media-{tenant}", env: "", tenant: " ACME ", expected: "media-acme"},
+		{name: "placeholder without value", template: "media-{env}", env: " ", tenant: "acme", wantErr: true},
+		{name: "unknown placeholder", template: "media-{region}", env: "production", tenant: "acme", wantErr: true},
+		{name: "forbidden character", template: "media-{tenant}", env: "production", tenant: "a/b", wantErr: true},
+		{name: "leading underscore", template: "{tenant}-media", env: "production", tenant: "_acme", wantErr: true},
+		{name: "empty", template: "", env: "production", tenant: "acme", wantErr: true},
+		{name: "dot", template: ".", env: "production", tenant: "acme", wantErr: true},
+		{name: "too long", template: strings.Repeat("m", 253), env: "production", tenant: "acme", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := ResolveIndexName(tt.template, tt.env, tt.tenant)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, name)
+		})
+	}
+}
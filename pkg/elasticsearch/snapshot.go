@@ -0,0 +1,299 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// snapshotSuccess is the state of a snapshot of every shard
+const snapshotSuccess = "SUCCESS"
+
+// Snapshot describes a snapshot of a snapshot repository
+type Snapshot struct {
+	Name      string    `json:"snapshot"`
+	State     string    `json:"state"`
+	Indices   []string  `json:"indices"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Shards    struct {
+		Total      int `json:"total"`
+		Failed     int `json:"failed"`
+		Successful int `json:"successful"`
+	} `json:"shards"`
+}
+
+// RestoreResult describes the index a snapshot was restored to
+type RestoreResult struct {
+	Index    string   // index restored from the snapshot, now behind the alias
+	Previous []string // indices the alias pointed to before, kept until deleted by hand
+}
+
+// Index returns the name of the index, or alias, requests go through
+func (c *Client) Index() string {
+	return c.index
+}
+
+// SnapshotName returns the default name of a snapshot of the index taken at
+// the given time
+func (c *Client) SnapshotName(at time.Time) string {
+	return c.index + "-" + at.UTC().Format("20060102-150405")
+}
+
+// RegisterSnapshotRepository registers, or updates, a shared file system
+// snapshot repository at location, which must be listed in the path.repo
+// setting of every node
+func (c *Client) RegisterSnapshotRepository(ctx context.Context, repository, location string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"type":     "fs",
+		"settings": map[string]interface{}{"location": location},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot repository: %w", err)
+	}
+
+	res, err := c.es.Snapshot.CreateRepository(
+		repository,
+		bytes.NewReader(body),
+		c.es.Snapshot.CreateRepository.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register snapshot repository: %w", unavailable(ctx, err))
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return responseError("register snapshot repository failed", res)
+	}
+
+	return nil
+}
+
+// CreateSnapshot snapshots the index behind the alias, without the cluster
+// state, and waits until the snapshot completed
+func (c *Client) CreateSnapshot(ctx context.Context, repository, name string) (*Snapshot, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"indices":              c.index,
+		"include_global_state": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot request: %w", err)
+	}
+
+	res, err := c.es.Snapshot.Create(
+		repository,
+		name,
+		c.es.Snapshot.Create.WithBody(bytes.NewReader(body)),
+		c.es.Snapshot.Create.WithWaitForCompletion(true),
+		c.es.Snapshot.Create.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot request failed: %w", unavailable(ctx, err))
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, responseError("snapshot failed", res)
+	}
+
+	var created struct {
+		Snapshot Snapshot `json:"snapshot"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot response: %w", err)
+	}
+	if created.Snapshot.State != snapshotSuccess {
+		return &created.Snapshot, fmt.Errorf("snapshot %s ended in state %s with %d failed shards", name, created.Snapshot.State, created.Snapshot.Shards.Failed)
+	}
+
+	return &created.Snapshot, nil
+}
+
+// ListSnapshots returns the snapshots of the repository holding the index,
+// oldest first
+func (c *Client) ListSnapshots(ctx context.Context, repository string) ([]Snapshot, error) {
+	all, err := c.getSnapshots(ctx, repository, "_all")
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, snapshot := range all {
+		if c.ownIndex(snapshot.Indices) != "" {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].StartTime.Before(snapshots[j].StartTime)
+	})
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot restores the index of a snapshot under a new name, then
+// moves the alias onto it in one atomic update, so searches switch from the
+// current index to the restored one without downtime. The previous indices
+// are kept so the restore can itself be rolled back.
+func (c *Client) RestoreSnapshot(ctx context.Context, repository, name string) (*RestoreResult, error) {
+	snapshots, err := c.getSnapshots(ctx, repository, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("snapshot %s not found in repository %s", name, repository)
+	}
+	source := c.ownIndex(snapshots[0].Indices)
+	if source == "" {
+		return nil, fmt.Errorf("snapshot %s does not hold index %s", name, c.index)
+	}
+
+	// The alias can only be moved if requests go through one
+	previous, err := c.aliasIndices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	restored := c.index + "-restored-" + name
+	body, err := json.Marshal(map[string]interface{}{
+		"indices":              source,
+		"include_global_state": false,
+		"include_aliases":      false,
+		"rename_pattern":       "^" + regexp.QuoteMeta(source) + "$",
+		"rename_replacement":   restored,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal restore request: %w", err)
+	}
+
+	res, err := c.es.Snapshot.Restore(
+		repository,
+		name,
+		c.es.Snapshot.Restore.WithBody(bytes.NewReader(body)),
+		c.es.Snapshot.Restore.WithWaitForCompletion(true),
+		c.es.Snapshot.Restore.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("restore request failed: %w", unavailable(ctx, err))
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, responseError("restore failed", res)
+	}
+
+	if err := c.moveAlias(ctx, previous, restored); err != nil {
+		return nil, err
+	}
+
+	return &RestoreResult{Index: restored, Previous: previous}, nil
+}
+
+// getSnapshots returns the named snapshots of a repository, or all with _all
+func (c *Client) getSnapshots(ctx context.Context, repository, name string) ([]Snapshot, error) {
+	res, err := c.es.Snapshot.Get(
+		repository,
+		[]string{name},
+		c.es.Snapshot.Get.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get snapshots request failed: %w", unavailable(ctx, err))
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound && name != "_all" {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, responseError("get snapshots failed", res)
+	}
+
+	var list struct {
+		Snapshots []Snapshot `json:"snapshots"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshots: %w", err)
+	}
+
+	return list.Snapshots, nil
+}
+
+// ownIndex returns the index of the client among the indices of a snapshot:
+// the index named like the alias, a version behind it or a restored one
+func (c *Client) ownIndex(indices []string) string {
+	own := regexp.MustCompile("^" + regexp.QuoteMeta(c.index) + `(-v[0-9]+|-restored-.+)?$`)
+	for _, index := range indices {
+		if own.MatchString(index) {
+			return index
+		}
+	}
+	return ""
+}
+
+// aliasIndices returns the indices the alias of the client points to
+func (c *Client) aliasIndices(ctx context.Context) ([]string, error) {
+	res, err := c.es.Indices.GetAlias(
+		c.es.Indices.GetAlias.WithName(c.index),
+		c.es.Indices.GetAlias.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get alias request failed: %w", unavailable(ctx, err))
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s is an index, not an alias; only indices created behind an alias can be restored", c.index)
+	}
+	if res.IsError() {
+		return nil, responseError("get alias failed", res)
+	}
+
+	var aliases map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&aliases); err != nil {
+		return nil, fmt.Errorf("failed to decode alias: %w", err)
+	}
+	indices := make([]string, 0, len(aliases))
+	for index := range aliases {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	return indices, nil
+}
+
+// moveAlias points the alias of the client at index alone, atomically
+func (c *Client) moveAlias(ctx context.Context, from []string, index string) error {
+	actions := make([]map[string]interface{}, 0, len(from)+1)
+	for _, previous := range from {
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]interface{}{"index": previous, "alias": c.index},
+		})
+	}
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]interface{}{"index": index, "alias": c.index, "is_write_index": true},
+	})
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alias update: %w", err)
+	}
+
+	res, err := c.es.Indices.UpdateAliases(
+		bytes.NewReader(body),
+		c.es.Indices.UpdateAliases.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("alias update request failed: %w", unavailable(ctx, err))
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return responseError(fmt.Sprintf("failed to move alias %s to %s", c.index, index), res)
+	}
+
+	return nil
+}
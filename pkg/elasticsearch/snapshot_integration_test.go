@@ -0,0 +1,59 @@
+//go:build integration
+
+package elasticsearch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/testsupport"
+	"thamaniyah/pkg/elasticsearch"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SnapshotAndRestore_Integration(t *testing.T) {
+	ctx := context.Background()
+	client := testsupport.StartElasticsearch(t)
+	require.NoError(t, client.RegisterSnapshotRepository(ctx, "backups", testsupport.SnapshotLocation+"/backups"))
+
+	countDocuments := func() int64 {
+		t.Helper()
+		res, err := client.Search(ctx, map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}})
+		require.NoError(t, err)
+		return res.Hits.Total.Value
+	}
+
+	// Given a snapshot of the index with two documents
+	require.NoError(t, client.BulkIndex(ctx, []elasticsearch.BulkDocument{
+		{ID: "media-1", Source: map[string]interface{}{"id": "media-1", "title": "First"}},
+		{ID: "media-2", Source: map[string]interface{}{"id": "media-2", "title": "Second"}},
+	}))
+	name := client.SnapshotName(time.Now())
+	snapshot, err := client.CreateSnapshot(ctx, "backups", name)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"media_test-v1"}, snapshot.Indices)
+
+	snapshots, err := client.ListSnapshots(ctx, "backups")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, name, snapshots[0].Name)
+
+	// And a bad reindex that emptied it
+	require.NoError(t, client.ClearIndex(ctx))
+	require.NoError(t, client.DeleteDocument(ctx, "media-1"))
+
+	// When
+	result, err := client.RestoreSnapshot(ctx, "backups", name)
+
+	// Then the alias serves the restored documents and the emptied index is kept
+	require.NoError(t, err)
+	assert.Equal(t, "media_test-restored-"+name, result.Index)
+	assert.Equal(t, []string{"media_test-v1"}, result.Previous)
+	assert.Equal(t, int64(2), countDocuments())
+
+	_, err = client.RestoreSnapshot(ctx, "backups", "missing")
+	assert.Error(t, err)
+}
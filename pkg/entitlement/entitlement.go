@@ -0,0 +1,127 @@
+package entitlement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// StaticProvider grants every user the same subscription tier
+type StaticProvider struct {
+	tier domain.SubscriptionTier
+}
+
+// NewStaticProvider creates a provider granting every user tier
+func NewStaticProvider(tier domain.SubscriptionTier) *StaticProvider {
+	return &StaticProvider{tier: tier}
+}
+
+// SubscriptionTier returns the tier of every user
+func (p *StaticProvider) SubscriptionTier(ctx context.Context, userID string) (domain.SubscriptionTier, error) {
+	return p.tier, nil
+}
+
+// cachedTier is a tier looked up from the subscription service
+type cachedTier struct {
+	tier      domain.SubscriptionTier
+	expiresAt time.Time
+}
+
+// HTTPProvider looks subscription tiers up from a subscription service answering
+// GET {baseURL}/users/{id}/entitlement with {"tier": "premium"}. Unknown users are free.
+// Tiers are cached for cacheTTL so playback does not wait on the service every time.
+type HTTPProvider struct {
+	baseURL    string
+	httpClient *http.Client
+	cacheTTL   time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedTier
+}
+
+// NewHTTPProvider creates a provider backed by the subscription service at baseURL
+func NewHTTPProvider(baseURL string, cacheTTL time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+		cache:    make(map[string]cachedTier),
+	}
+}
+
+// SubscriptionTier returns the tier of a user
+func (p *HTTPProvider) SubscriptionTier(ctx context.Context, userID string) (domain.SubscriptionTier, error) {
+	now := p.now()
+	p.mu.Lock()
+	cached, ok := p.cache[userID]
+	p.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.tier, nil
+	}
+
+	tier, err := p.fetch(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if p.cacheTTL > 0 {
+		p.mu.Lock()
+		// Drop expired entries so the cache only holds recently active users
+		for id, entry := range p.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(p.cache, id)
+			}
+		}
+		p.cache[userID] = cachedTier{tier: tier, expiresAt: now.Add(p.cacheTTL)}
+		p.mu.Unlock()
+	}
+
+	return tier, nil
+}
+
+// fetch asks the subscription service for the tier of a user
+func (p *HTTPProvider) fetch(ctx context.Context, userID string) (domain.SubscriptionTier, error) {
+	endpoint := fmt.Sprintf("%s/users/%s/entitlement", p.baseURL, url.PathEscape(userID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.SubscriptionFree, nil
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Tier domain.SubscriptionTier `json:"tier"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode entitlement: %w", err)
+	}
+	if !body.Tier.IsValid() {
+		return "", fmt.Errorf("unknown subscription tier %q", body.Tier)
+	}
+
+	return body.Tier, nil
+}
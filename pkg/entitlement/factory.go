@@ -0,0 +1,31 @@
+package entitlement
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"thamaniyah/internal/config"
+	"thamaniyah/internal/domain"
+)
+
+// Provider resolves the subscription tier of users
+type Provider interface {
+	SubscriptionTier(ctx context.Context, userID string) (domain.SubscriptionTier, error)
+}
+
+// NewFromConfig creates the provider selected by ENTITLEMENT_PROVIDER
+func NewFromConfig(cfg *config.Config) (Provider, error) {
+	switch cfg.Entitlement.Provider {
+	case "", "none":
+		// Without a subscription service nobody is entitled to premium media
+		return NewStaticProvider(domain.SubscriptionFree), nil
+	case "http":
+		if cfg.Entitlement.ServiceURL == "" {
+			return nil, fmt.Errorf("ENTITLEMENT_SERVICE_URL is required by the http entitlement provider")
+		}
+		return NewHTTPProvider(cfg.Entitlement.ServiceURL, time.Duration(cfg.Entitlement.CacheSeconds)*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unsupported entitlement provider: %s", cfg.Entitlement.Provider)
+	}
+}
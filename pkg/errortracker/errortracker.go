@@ -0,0 +1,162 @@
+package errortracker
+
+import (
+	"context"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a reported event
+type Level string
+
+const (
+	LevelError Level = "error"
+	LevelFatal Level = "fatal" // panics
+)
+
+// Frame is a single stack frame, innermost last
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Request describes the HTTP request an event happened in
+type Request struct {
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Route    string            `json:"route,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	ClientIP string            `json:"client_ip,omitempty"`
+}
+
+// Event is an error reported to an error tracker
+type Event struct {
+	Level     Level             `json:"level"`
+	Type      string            `json:"type"` // e.g. panic or http_5xx
+	Message   string            `json:"message"`
+	Frames    []Frame           `json:"frames,omitempty"`
+	Request   *Request          `json:"request,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Sink delivers events to an error tracking backend
+type Sink interface {
+	// Capture sends a single event
+	Capture(ctx context.Context, event *Event) error
+}
+
+// Options configures a tracker
+type Options struct {
+	Environment string // e.g. production or staging
+	ServerName  string // service reporting the events
+	BufferSize  int    // events waiting for delivery, newer events are dropped when full
+}
+
+// Tracker reports events to a sink in the background so requests never wait
+// on the error tracking backend
+type Tracker struct {
+	sink    Sink
+	options Options
+	events  chan *Event
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewTracker creates a tracker delivering events to sink
+func NewTracker(sink Sink, options Options) *Tracker {
+	if options.BufferSize <= 0 {
+		options.BufferSize = 100
+	}
+
+	t := &Tracker{
+		sink:    sink,
+		options: options,
+		events:  make(chan *Event, options.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Report queues an event for delivery, tagging it with the environment and server
+func (t *Tracker) Report(event *Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if event.Tags == nil {
+		event.Tags = make(map[string]string)
+	}
+	if t.options.Environment != "" {
+		event.Tags["environment"] = t.options.Environment
+	}
+	if t.options.ServerName != "" {
+		event.Tags["server_name"] = t.options.ServerName
+	}
+
+	select {
+	case t.events <- event:
+	default:
+		log.Printf("Error tracker buffer full, dropping event: %s", event.Message)
+	}
+}
+
+// Close stops accepting events and waits until the queued ones are delivered or ctx ends
+func (t *Tracker) Close(ctx context.Context) error {
+	t.once.Do(func() {
+		close(t.events)
+	})
+
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run delivers queued events until the tracker is closed
+func (t *Tracker) run() {
+	defer close(t.done)
+
+	for event := range t.events {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := t.sink.Capture(ctx, event); err != nil {
+			log.Printf("Failed to report error event: %v", err)
+		}
+		cancel()
+	}
+}
+
+// Stack returns the stack of the caller, skipping skip frames above it
+// and the runtime frames of an ongoing panic
+func Stack(skip int) []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var result []Frame
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			result = append(result, Frame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+
+	// Trackers expect the innermost frame last
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
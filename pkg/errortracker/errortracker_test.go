@@ -0,0 +1,75 @@
+package errortracker
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps the captured events, blocking until release is closed
+type recordingSink struct {
+	mu      sync.Mutex
+	events  []*Event
+	release chan struct{}
+}
+
+func (s *recordingSink) Capture(ctx context.Context, event *Event) error {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestTracker_Report(t *testing.T) {
+	sink := &recordingSink{}
+	tracker := NewTracker(sink, Options{Environment: "production", ServerName: "cms-service"})
+
+	tracker.Report(&Event{Level: LevelError, Message: "boom", Tags: map[string]string{"status": "500"}})
+	require.NoError(t, tracker.Close(context.Background()))
+
+	require.Len(t, sink.events, 1)
+	event := sink.events[0]
+	assert.False(t, event.Timestamp.IsZero())
+	assert.Equal(t, map[string]string{"status": "500", "environment": "production", "server_name": "cms-service"}, event.Tags)
+}
+
+func TestTracker_ReportDropsWhenFull(t *testing.T) {
+	// Given a sink stuck on the first event and room for one more
+	sink := &recordingSink{release: make(chan struct{})}
+	tracker := NewTracker(sink, Options{BufferSize: 1})
+
+	tracker.Report(&Event{Message: "first"})
+	require.Eventually(t, func() bool { return len(tracker.events) == 0 }, time.Second, time.Millisecond)
+	tracker.Report(&Event{Message: "second"})
+	tracker.Report(&Event{Message: "dropped"})
+
+	// When the sink recovers
+	close(sink.release)
+	require.NoError(t, tracker.Close(context.Background()))
+
+	// Then the reports beyond the buffer were dropped without blocking
+	messages := make([]string, 0, len(sink.events))
+	for _, event := range sink.events {
+		messages = append(messages, event.Message)
+	}
+	assert.Equal(t, []string{"first", "second"}, messages)
+}
+
+func TestStack(t *testing.T) {
+	frames := Stack(0)
+
+	require.NotEmpty(t, frames)
+	innermost := frames[len(frames)-1]
+	assert.True(t, strings.HasSuffix(innermost.Function, "TestStack"), innermost.Function)
+	for _, frame := range frames {
+		assert.False(t, strings.HasPrefix(frame.Function, "runtime."), frame.Function)
+	}
+}
@@ -0,0 +1,33 @@
+package errortracker
+
+import (
+	"fmt"
+
+	"thamaniyah/internal/config"
+)
+
+// NewFromConfig creates the tracker selected by ERROR_TRACKER for a service,
+// or nil when error tracking is disabled
+func NewFromConfig(cfg *config.Config, serverName string) (*Tracker, error) {
+	var sink Sink
+	switch cfg.ErrorTracking.Sink {
+	case "", "none":
+		return nil, nil
+	case "log":
+		sink = NewLogSink()
+	case "sentry":
+		sentry, err := NewSentrySink(cfg.ErrorTracking.SentryDSN)
+		if err != nil {
+			return nil, err
+		}
+		sink = sentry
+	default:
+		return nil, fmt.Errorf("unsupported error tracker: %s", cfg.ErrorTracking.Sink)
+	}
+
+	return NewTracker(sink, Options{
+		Environment: cfg.ErrorTracking.Environment,
+		ServerName:  serverName,
+		BufferSize:  cfg.ErrorTracking.BufferSize,
+	}), nil
+}
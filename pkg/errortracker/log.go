@@ -0,0 +1,25 @@
+package errortracker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// LogSink writes events to the standard logger, e.g. for development
+type LogSink struct{}
+
+// NewLogSink creates a new log sink
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+// Capture logs the event as JSON
+func (s *LogSink) Capture(ctx context.Context, event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("Error event: %s", payload)
+	return nil
+}
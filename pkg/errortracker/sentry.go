@@ -0,0 +1,160 @@
+package errortracker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// sentryClientName identifies this client in the Sentry auth header
+const sentryClientName = "thamaniyah-errortracker/1.0"
+
+// SentrySink sends events to Sentry through its envelope endpoint
+type SentrySink struct {
+	endpoint   string
+	publicKey  string
+	httpClient *http.Client
+}
+
+// NewSentrySink creates a sink for the project of a Sentry DSN
+// (https://<public key>@<host>/<project id>)
+func NewSentrySink(dsn string) (*SentrySink, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Sentry DSN: %w", err)
+	}
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing public key")
+	}
+
+	// The project ID is the last path segment, anything before it is a path prefix
+	path := strings.Trim(parsed.Path, "/")
+	projectID := path
+	prefix := ""
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		prefix = "/" + path[:i]
+		projectID = path[i+1:]
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing project ID")
+	}
+
+	return &SentrySink{
+		endpoint:  fmt.Sprintf("%s://%s%s/api/%s/envelope/", parsed.Scheme, parsed.Host, prefix, projectID),
+		publicKey: parsed.User.Username(),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Capture sends the event as a single item envelope
+func (s *SentrySink) Capture(ctx context.Context, event *Event) error {
+	eventID, err := newEventID()
+	if err != nil {
+		return err
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"event_id": eventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope header: %w", err)
+	}
+	payload, err := json.Marshal(toSentryEvent(eventID, event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	var body bytes.Buffer
+	body.Write(header)
+	body.WriteString("\n{\"type\":\"event\"}\n")
+	body.Write(payload)
+	body.WriteString("\n")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf(
+		"Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClientName, s.publicKey))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event to Sentry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("sentry rejected event with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// toSentryEvent converts an event to the Sentry event payload
+func toSentryEvent(eventID string, event *Event) map[string]interface{} {
+	frames := make([]map[string]interface{}, 0, len(event.Frames))
+	for _, frame := range event.Frames {
+		frames = append(frames, map[string]interface{}{
+			"function": frame.Function,
+			"abs_path": frame.File,
+			"lineno":   frame.Line,
+			"in_app":   strings.HasPrefix(frame.Function, "thamaniyah/"),
+		})
+	}
+
+	exception := map[string]interface{}{
+		"type":  event.Type,
+		"value": event.Message,
+	}
+	if len(frames) > 0 {
+		exception["stacktrace"] = map[string]interface{}{"frames": frames}
+	}
+
+	payload := map[string]interface{}{
+		"event_id":  eventID,
+		"timestamp": event.Timestamp.UTC().Format(time.RFC3339Nano),
+		"platform":  "go",
+		"level":     string(event.Level),
+		"exception": map[string]interface{}{"values": []interface{}{exception}},
+		"tags":      event.Tags,
+	}
+	if environment, ok := event.Tags["environment"]; ok {
+		payload["environment"] = environment
+	}
+	if serverName, ok := event.Tags["server_name"]; ok {
+		payload["server_name"] = serverName
+	}
+	if event.Request != nil {
+		payload["request"] = map[string]interface{}{
+			"method":  event.Request.Method,
+			"url":     event.Request.URL,
+			"headers": event.Request.Headers,
+			"env":     map[string]string{"REMOTE_ADDR": event.Request.ClientIP},
+		}
+		if event.Request.Route != "" {
+			payload["transaction"] = event.Request.Method + " " + event.Request.Route
+		}
+	}
+
+	return payload
+}
+
+// newEventID returns a random 32 character hex event ID
+func newEventID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,120 @@
+package errortracker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSentrySink(t *testing.T) {
+	tests := []struct {
+		name             string
+		dsn              string
+		expectedEndpoint string
+		expectedKey      string
+		expectError      bool
+	}{
+		{
+			name:             "project DSN",
+			dsn:              "https://abc123@o1.ingest.sentry.io/42",
+			expectedEndpoint: "https://o1.ingest.sentry.io/api/42/envelope/",
+			expectedKey:      "abc123",
+		},
+		{
+			name:             "self-hosted DSN with a path prefix",
+			dsn:              "http://abc123@sentry.internal:9000/errors/7",
+			expectedEndpoint: "http://sentry.internal:9000/errors/api/7/envelope/",
+			expectedKey:      "abc123",
+		},
+		{
+			name:        "missing public key",
+			dsn:         "https://o1.ingest.sentry.io/42",
+			expectError: true,
+		},
+		{
+			name:        "missing project ID",
+			dsn:         "https://abc123@o1.ingest.sentry.io/",
+			expectError: true,
+		},
+		{
+			name:        "malformed DSN",
+			dsn:         "https://abc123@o1.ingest.sentry.io:port/42",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := NewSentrySink(tt.dsn)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedEndpoint, sink.endpoint)
+			assert.Equal(t, tt.expectedKey, sink.publicKey)
+		})
+	}
+}
+
+func TestSentrySink_Capture(t *testing.T) {
+	var (
+		path, auth string
+		lines      []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("X-Sentry-Auth")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink, err := NewSentrySink(strings.Replace(server.URL, "://", "://key@", 1) + "/42")
+	require.NoError(t, err)
+
+	err = sink.Capture(context.Background(), &Event{
+		Level:     LevelFatal,
+		Type:      "panic",
+		Message:   "nil map",
+		Frames:    []Frame{{Function: "thamaniyah/internal/handler.(*MediaHandler).GetMedia", File: "media_handler.go", Line: 10}},
+		Request:   &Request{Method: http.MethodGet, URL: "/api/v1/media/1", Route: "/api/v1/media/:id"},
+		Tags:      map[string]string{"environment": "staging"},
+		Timestamp: time.Now(),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api/42/envelope/", path)
+	assert.Contains(t, auth, "sentry_key=key")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"type":"event"}`, lines[1])
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &payload))
+	assert.Equal(t, "fatal", payload["level"])
+	assert.Equal(t, "staging", payload["environment"])
+	assert.Equal(t, "GET /api/v1/media/:id", payload["transaction"])
+}
+
+func TestSentrySink_CaptureRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	sink, err := NewSentrySink(strings.Replace(server.URL, "://", "://key@", 1) + "/42")
+	require.NoError(t, err)
+
+	assert.Error(t, sink.Capture(context.Background(), &Event{Level: LevelError, Message: "boom"}))
+}
@@ -0,0 +1,317 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"thamaniyah/internal/domain"
+	"thamaniyah/pkg/media"
+)
+
+// maxStderrBytes bounds how much FFmpeg output is kept for error messages
+const maxStderrBytes = 4096
+
+// Runner runs FFmpeg as a subprocess, streaming media through stdin and stdout
+type Runner struct {
+	binary string
+}
+
+// NewRunner creates a runner for the FFmpeg binary at the given path
+func NewRunner(binary string) *Runner {
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+	return &Runner{binary: binary}
+}
+
+// TranscodeAudio reads media from src and writes its audio track to dst in the requested format
+func (r *Runner) TranscodeAudio(ctx context.Context, src io.Reader, dst io.Writer, format domain.AudioFormat) error {
+	args, err := audioArgs(format)
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Stdin = src
+	cmd.Stdout = dst
+	cmd.Stderr = &media.LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// TranscodeVideo transcodes the video at input into a rendition written to output,
+// overlaying the watermark when it is not nil
+func (r *Runner) TranscodeVideo(ctx context.Context, input, output string, rendition domain.Rendition, watermark *domain.Watermark) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, videoArgs(input, output, rendition, watermark)...)
+	cmd.Stderr = &media.LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// PackageHLS splits the H.264 video at input into HLS segments of about
+// segmentSeconds without re-encoding it, writing the media playlist to playlist
+// and the segments next to it
+func (r *Runner) PackageHLS(ctx context.Context, input, playlist string, segmentSeconds int) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, hlsArgs(input, playlist, segmentSeconds)...)
+	cmd.Stderr = &media.LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// EncryptCENC copies the MP4 video at input to output without re-encoding it,
+// encrypting its samples with AES-CTR (the cenc scheme of Widevine and
+// PlayReady) under the hex encoded keyID
+func (r *Runner) EncryptCENC(ctx context.Context, input, output, keyID string, key []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, cencArgs(input, output, keyID, key)...)
+	cmd.Stderr = &media.LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// RenderThumbnail reads an image or a video from src and writes a thumbnail of
+// it to dst, picking a representative frame of videos
+func (r *Runner) RenderThumbnail(ctx context.Context, src io.Reader, dst io.Writer, spec domain.ThumbnailSpec) error {
+	args, err := thumbnailArgs(spec)
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Stdin = src
+	cmd.Stdout = dst
+	cmd.Stderr = &media.LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ExtractFrame reads a video from src and writes the frame shown at the given
+// position to dst as a full size JPEG
+func (r *Runner) ExtractFrame(ctx context.Context, src io.Reader, dst io.Writer, at time.Duration) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, frameArgs(at)...)
+	cmd.Stdin = src
+	cmd.Stdout = dst
+	cmd.Stderr = &media.LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// DetectSilences reads media from src and returns the stretches of its audio
+// quieter than noiseDB (e.g. -35) for at least minSeconds. A silence running
+// until the end of the audio is left out.
+func (r *Runner) DetectSilences(ctx context.Context, src io.Reader, noiseDB, minSeconds float64) ([]domain.SilenceInterval, error) {
+	// silencedetect reports on the info log level, which is kept whole to be parsed
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, silenceArgs(noiseDB, minSeconds)...)
+	cmd.Stdin = src
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		output := strings.TrimSpace(stderr.String())
+		if len(output) > maxStderrBytes {
+			output = output[len(output)-maxStderrBytes:]
+		}
+		return nil, fmt.Errorf("ffmpeg failed: %w: %s", err, output)
+	}
+	return parseSilences(stderr.String()), nil
+}
+
+// silenceArgs builds the FFmpeg arguments detecting the silences of stdin
+func silenceArgs(noiseDB, minSeconds float64) []string {
+	return []string{"-hide_banner", "-nostats", "-loglevel", "info", "-i", "pipe:0", "-vn",
+		"-af", fmt.Sprintf("silencedetect=noise=%gdB:d=%g", noiseDB, minSeconds), "-f", "null", "-"}
+}
+
+// parseSilences reads the silence_start and silence_end lines silencedetect logs
+func parseSilences(output string) []domain.SilenceInterval {
+	var silences []domain.SilenceInterval
+	var start float64
+	started := false
+	for _, line := range strings.Split(output, "\n") {
+		if value, ok := logValue(line, "silence_start:"); ok {
+			start, started = value, true
+			continue
+		}
+		if value, ok := logValue(line, "silence_end:"); ok && started {
+			silences = append(silences, domain.SilenceInterval{Start: max(start, 0), End: value})
+			started = false
+		}
+	}
+	return silences
+}
+
+// logValue returns the number following key in a log line
+func logValue(line, key string) (float64, bool) {
+	i := strings.Index(line, key)
+	if i < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(line[i+len(key):])
+	if len(fields) == 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	return value, err == nil
+}
+
+// thumbnailArgs builds the FFmpeg arguments rendering a thumbnail of stdin on stdout
+func thumbnailArgs(spec domain.ThumbnailSpec) ([]string, error) {
+	// A missing dimension follows the aspect ratio, rounded to an even size for the encoders
+	scale := fmt.Sprintf("scale=%d:-2", spec.Width)
+	switch {
+	case spec.Width == 0:
+		scale = fmt.Sprintf("scale=-2:%d", spec.Height)
+	case spec.Height > 0:
+		scale = fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease", spec.Width, spec.Height)
+	}
+
+	// The thumbnail filter picks the most representative of the first frames, a still image is its own
+	args := []string{"-hide_banner", "-loglevel", "error", "-i", "pipe:0", "-vf", "thumbnail," + scale, "-frames:v", "1", "-an"}
+
+	switch spec.Format {
+	case domain.ThumbnailFormatJPEG:
+		args = append(args, "-c:v", "mjpeg", "-q:v", "3", "-f", "image2pipe")
+	case domain.ThumbnailFormatPNG:
+		args = append(args, "-c:v", "png", "-f", "image2pipe")
+	case domain.ThumbnailFormatWebP:
+		args = append(args, "-c:v", "libwebp", "-quality", "80", "-f", "webp")
+	default:
+		return nil, fmt.Errorf("unsupported thumbnail format: %s", spec.Format)
+	}
+
+	return append(args, "pipe:1"), nil
+}
+
+// videoArgs builds the FFmpeg arguments producing a video rendition
+func videoArgs(input, output string, rendition domain.Rendition, watermark *domain.Watermark) []string {
+	args := []string{"-hide_banner", "-loglevel", "error", "-y", "-i", input}
+
+	scale := fmt.Sprintf("scale=%d:%d", rendition.Width, rendition.Height)
+	if watermark != nil {
+		args = append(args, "-i", watermark.LogoPath,
+			"-filter_complex", fmt.Sprintf("[0:v]%s[base];%s", scale, overlayFilter("base", "1:v", watermark)))
+	} else {
+		args = append(args, "-vf", scale)
+	}
+
+	args = append(args,
+		"-c:v", videoEncoders[rendition.VideoCodec],
+		"-b:v", fmt.Sprintf("%dk", rendition.VideoBitrateKbps),
+		"-c:a", audioEncoders[rendition.AudioCodec],
+		"-b:a", fmt.Sprintf("%dk", rendition.AudioBitrateKbps),
+	)
+	return append(args, output)
+}
+
+// frameArgs builds the FFmpeg arguments writing the frame of stdin at a position to stdout
+func frameArgs(at time.Duration) []string {
+	// Seeking before the input decodes from the keyframe preceding the position
+	return []string{"-hide_banner", "-loglevel", "error",
+		"-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64), "-i", "pipe:0",
+		"-frames:v", "1", "-an", "-c:v", "mjpeg", "-q:v", "2", "-f", "image2pipe", "pipe:1"}
+}
+
+// hlsArgs builds the FFmpeg arguments segmenting a video into an HLS media playlist
+func hlsArgs(input, playlist string, segmentSeconds int) []string {
+	return []string{"-hide_banner", "-loglevel", "error", "-y", "-i", input,
+		"-c", "copy", "-f", "hls",
+		"-hls_time", strconv.Itoa(segmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(filepath.Dir(playlist), "segment_%04d.ts"),
+		playlist}
+}
+
+// cencArgs builds the FFmpeg arguments encrypting an MP4 video with a content key
+func cencArgs(input, output, keyID string, key []byte) []string {
+	return []string{"-hide_banner", "-loglevel", "error", "-y", "-i", input,
+		"-map", "0", "-c", "copy",
+		"-encryption_scheme", "cenc-aes-ctr",
+		"-encryption_key", hex.EncodeToString(key),
+		"-encryption_kid", keyID,
+		"-f", "mp4", output}
+}
+
+// videoEncoders maps rendition video codecs to FFmpeg encoders
+var videoEncoders = map[string]string{
+	"h264": "libx264",
+	"h265": "libx265",
+	"vp9":  "libvpx-vp9",
+	"av1":  "libaom-av1",
+}
+
+// audioEncoders maps rendition audio codecs to FFmpeg encoders
+var audioEncoders = map[string]string{
+	"aac":  "aac",
+	"opus": "libopus",
+	"mp3":  "libmp3lame",
+}
+
+// overlayFilter builds the filter graph overlaying the logo stream on the video stream
+// at the watermark position, with the logo's alpha scaled to the watermark opacity
+func overlayFilter(video, logo string, watermark *domain.Watermark) string {
+	margin := watermark.Margin
+
+	var x, y string
+	switch watermark.Position {
+	case domain.WatermarkTopLeft:
+		x, y = fmt.Sprint(margin), fmt.Sprint(margin)
+	case domain.WatermarkTopRight:
+		x, y = fmt.Sprintf("W-w-%d", margin), fmt.Sprint(margin)
+	case domain.WatermarkBottomLeft:
+		x, y = fmt.Sprint(margin), fmt.Sprintf("H-h-%d", margin)
+	case domain.WatermarkCenter:
+		x, y = "(W-w)/2", "(H-h)/2"
+	default:
+		x, y = fmt.Sprintf("W-w-%d", margin), fmt.Sprintf("H-h-%d", margin)
+	}
+
+	return fmt.Sprintf("[%s]format=rgba,colorchannelmixer=aa=%.2f[logo];[%s][logo]overlay=%s:%s",
+		logo, watermark.Opacity, video, x, y)
+}
+
+// audioArgs builds the FFmpeg arguments converting stdin to the requested audio format on stdout
+func audioArgs(format domain.AudioFormat) ([]string, error) {
+	args := []string{"-hide_banner", "-loglevel", "error", "-i", "pipe:0", "-vn"}
+
+	switch format {
+	case domain.AudioFormatAAC:
+		args = append(args, "-c:a", "aac", "-b:a", "128k", "-f", "adts")
+	case domain.AudioFormatOpus:
+		args = append(args, "-c:a", "libopus", "-b:a", "96k", "-f", "ogg")
+	default:
+		return nil, fmt.Errorf("unsupported audio format: %s", format)
+	}
+
+	return append(args, "pipe:1"), nil
+}
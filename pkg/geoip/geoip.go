@@ -0,0 +1,104 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ipRange maps an inclusive address range to a country
+type ipRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+// Database resolves IP addresses to countries from a range table such as the
+// DB-IP or IP2Location "country lite" CSV exports (start_ip,end_ip,country).
+type Database struct {
+	ranges []ipRange
+}
+
+// Open loads a range database from a CSV file
+func Open(path string) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+// Load reads a range database from CSV rows of start_ip,end_ip,country.
+// Rows that are not address ranges, like a header, are skipped.
+func Load(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var ranges []ipRange
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read geoip database: %w", err)
+		}
+		if len(record) < 3 {
+			continue
+		}
+
+		start, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+		if err != nil {
+			continue
+		}
+		end, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+		if err != nil {
+			continue
+		}
+		country := strings.ToUpper(strings.TrimSpace(record[2]))
+		if len(country) != 2 || start.Is4() != end.Is4() || end.Less(start) {
+			continue
+		}
+
+		ranges = append(ranges, ipRange{start: start, end: end, country: country})
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start.Less(ranges[j].start)
+	})
+
+	return &Database{ranges: ranges}, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country ip is located in,
+// or an empty string when it is unknown
+func (d *Database) Country(ip netip.Addr) string {
+	ip = ip.Unmap()
+
+	// Find the last range starting at or before ip
+	i := sort.Search(len(d.ranges), func(i int) bool {
+		return ip.Less(d.ranges[i].start)
+	}) - 1
+	if i < 0 {
+		return ""
+	}
+
+	r := d.ranges[i]
+	if r.start.Is4() != ip.Is4() || r.end.Less(ip) {
+		return ""
+	}
+	return r.country
+}
+
+// Len returns the number of ranges in the database
+func (d *Database) Len() int {
+	return len(d.ranges)
+}
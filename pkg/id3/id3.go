@@ -0,0 +1,327 @@
+// Package id3 reads and writes the ID3v2 tags embedded at the start of MP3 files.
+// It covers the frames the platform uses: title, artist, album, recording date and
+// attached pictures.
+package id3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// Errors returned while reading tags
+var (
+	ErrNoTag              = errors.New("no ID3v2 tag")
+	ErrUnsupportedVersion = errors.New("unsupported ID3v2 version")
+)
+
+const headerSize = 10
+
+// Header flags
+const (
+	flagUnsynchronisation = 0x80
+	flagExtendedHeader    = 0x40
+	flagFooter            = 0x10
+)
+
+// Picture types, see the APIC frame of the ID3v2 specification
+const (
+	PictureOther      byte = 0x00
+	PictureFrontCover byte = 0x03
+)
+
+// Picture is an image attached to the tag, such as episode art
+type Picture struct {
+	MIMEType string
+	Type     byte
+	Data     []byte
+}
+
+// Tag holds the frames read from or written to an ID3v2 tag
+type Tag struct {
+	Title         string
+	Artist        string
+	Album         string
+	RecordingDate string // ISO 8601, e.g. 2025-08-27 or 2025
+	Picture       *Picture
+}
+
+// Read reads the ID3v2 tag at the start of r. Only the tag is consumed.
+func Read(r io.Reader) (*Tag, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrNoTag
+		}
+		return nil, err
+	}
+	if string(header[:3]) != "ID3" {
+		return nil, ErrNoTag
+	}
+
+	version := header[3]
+	if version != 3 && version != 4 {
+		return nil, fmt.Errorf("%w: 2.%d", ErrUnsupportedVersion, version)
+	}
+	flags := header[5]
+
+	body := make([]byte, synchsafe(header[6:10]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("failed to read tag: %w", err)
+	}
+	if flags&flagUnsynchronisation != 0 {
+		body = removeUnsynchronisation(body)
+	}
+
+	if flags&flagExtendedHeader != 0 {
+		if len(body) < 4 {
+			return nil, fmt.Errorf("truncated extended header")
+		}
+		// The v2.4 size includes itself, the v2.3 size does not
+		size := int(binary.BigEndian.Uint32(body[:4])) + 4
+		if version == 4 {
+			size = synchsafe(body[:4])
+		}
+		if size > len(body) {
+			return nil, fmt.Errorf("truncated extended header")
+		}
+		body = body[size:]
+	}
+
+	return parseFrames(body, version), nil
+}
+
+// Strip consumes the ID3v2 tag at the start of r, if any, and returns a reader of the
+// remaining audio along with the number of bytes skipped
+func Strip(r io.Reader) (io.Reader, int64, error) {
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, 0, err
+	}
+	if n < headerSize || string(header[:3]) != "ID3" {
+		return io.MultiReader(bytes.NewReader(header[:n]), r), 0, nil
+	}
+
+	size := int64(synchsafe(header[6:10]))
+	if header[5]&flagFooter != 0 {
+		size += headerSize
+	}
+	if _, err := io.CopyN(io.Discard, r, size); err != nil {
+		return nil, 0, fmt.Errorf("failed to skip tag: %w", err)
+	}
+	return r, headerSize + size, nil
+}
+
+// Encode returns tag as an ID3v2.4 tag with UTF-8 text frames
+func Encode(tag *Tag) []byte {
+	var frames bytes.Buffer
+	writeTextFrame(&frames, "TIT2", tag.Title)
+	writeTextFrame(&frames, "TPE1", tag.Artist)
+	writeTextFrame(&frames, "TALB", tag.Album)
+	writeTextFrame(&frames, "TDRC", tag.RecordingDate)
+	if tag.Picture != nil && len(tag.Picture.Data) > 0 {
+		var data bytes.Buffer
+		data.WriteByte(encodingUTF8)
+		data.WriteString(tag.Picture.MIMEType)
+		data.WriteByte(0)
+		data.WriteByte(tag.Picture.Type)
+		data.WriteByte(0) // empty description
+		data.Write(tag.Picture.Data)
+		writeFrame(&frames, "APIC", data.Bytes())
+	}
+
+	var out bytes.Buffer
+	out.WriteString("ID3")
+	out.Write([]byte{4, 0, 0})
+	out.Write(toSynchsafe(frames.Len()))
+	out.Write(frames.Bytes())
+	return out.Bytes()
+}
+
+// Text encodings
+const (
+	encodingISO88591 = 0
+	encodingUTF16    = 1
+	encodingUTF16BE  = 2
+	encodingUTF8     = 3
+)
+
+// parseFrames reads the frames of a tag body until the padding
+func parseFrames(body []byte, version byte) *Tag {
+	tag := &Tag{}
+	var year string
+
+	for len(body) >= headerSize {
+		id := string(body[:4])
+		if body[0] == 0 {
+			break // padding
+		}
+
+		size := int(binary.BigEndian.Uint32(body[4:8]))
+		if version == 4 {
+			size = synchsafe(body[4:8])
+		}
+		if size < 0 || headerSize+size > len(body) {
+			break
+		}
+		data := body[headerSize : headerSize+size]
+		body = body[headerSize+size:]
+
+		switch id {
+		case "TIT2":
+			tag.Title = decodeText(data)
+		case "TPE1":
+			tag.Artist = decodeText(data)
+		case "TALB":
+			tag.Album = decodeText(data)
+		case "TDRC":
+			tag.RecordingDate = decodeText(data)
+		case "TYER":
+			year = decodeText(data)
+		case "APIC":
+			// Prefer the front cover over any other attached picture
+			if picture := parsePicture(data); picture != nil {
+				if tag.Picture == nil || (picture.Type == PictureFrontCover && tag.Picture.Type != PictureFrontCover) {
+					tag.Picture = picture
+				}
+			}
+		}
+	}
+
+	if tag.RecordingDate == "" {
+		tag.RecordingDate = year
+	}
+	return tag
+}
+
+// parsePicture reads an APIC frame
+func parsePicture(data []byte) *Picture {
+	if len(data) < 2 {
+		return nil
+	}
+	encoding := data[0]
+
+	end := bytes.IndexByte(data[1:], 0)
+	if end < 0 {
+		return nil
+	}
+	mimeType := string(data[1 : 1+end])
+	rest := data[2+end:]
+	if len(rest) < 1 {
+		return nil
+	}
+	pictureType := rest[0]
+
+	// Skip the description, terminated by one or two zero bytes depending on the encoding
+	_, rest = splitTerminated(rest[1:], encoding)
+	if len(rest) == 0 {
+		return nil
+	}
+
+	if mimeType == "" || !strings.Contains(mimeType, "/") {
+		mimeType = "image/" + strings.ToLower(mimeType)
+	}
+	return &Picture{MIMEType: mimeType, Type: pictureType, Data: rest}
+}
+
+// decodeText decodes a text frame, keeping the first value of multi-valued frames
+func decodeText(data []byte) string {
+	if len(data) < 1 {
+		return ""
+	}
+	value, _ := splitTerminated(data[1:], data[0])
+	return strings.TrimSpace(decodeString(value, data[0]))
+}
+
+// splitTerminated splits data at the first terminator of the given encoding
+func splitTerminated(data []byte, encoding byte) ([]byte, []byte) {
+	if encoding == encodingUTF16 || encoding == encodingUTF16BE {
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return data[:i], data[i+2:]
+			}
+		}
+		return data, nil
+	}
+
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return data[:i], data[i+1:]
+	}
+	return data, nil
+}
+
+// decodeString converts an encoded string to UTF-8
+func decodeString(data []byte, encoding byte) string {
+	switch encoding {
+	case encodingUTF16, encodingUTF16BE:
+		bigEndian := encoding == encodingUTF16BE
+		if len(data) >= 2 {
+			switch {
+			case data[0] == 0xFE && data[1] == 0xFF:
+				bigEndian, data = true, data[2:]
+			case data[0] == 0xFF && data[1] == 0xFE:
+				bigEndian, data = false, data[2:]
+			}
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			if bigEndian {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			} else {
+				units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+			}
+		}
+		return string(utf16.Decode(units))
+	case encodingUTF8:
+		return string(data)
+	default:
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+}
+
+// writeTextFrame writes a UTF-8 text frame, skipping empty values
+func writeTextFrame(buf *bytes.Buffer, id, value string) {
+	if value == "" {
+		return
+	}
+	writeFrame(buf, id, append([]byte{encodingUTF8}, value...))
+}
+
+// writeFrame writes an ID3v2.4 frame
+func writeFrame(buf *bytes.Buffer, id string, data []byte) {
+	buf.WriteString(id)
+	buf.Write(toSynchsafe(len(data)))
+	buf.Write([]byte{0, 0})
+	buf.Write(data)
+}
+
+// synchsafe decodes a 28-bit synchsafe integer
+func synchsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// toSynchsafe encodes a 28-bit synchsafe integer
+func toSynchsafe(n int) []byte {
+	return []byte{byte(n>>21) & 0x7F, byte(n>>14) & 0x7F, byte(n>>7) & 0x7F, byte(n) & 0x7F}
+}
+
+// removeUnsynchronisation reverts the 0xFF 0x00 byte stuffing of unsynchronised tags
+func removeUnsynchronisation(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		out = append(out, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0x00 {
+			i++
+		}
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+// Package media reads the technical metadata of media files, such as their
+// duration, resolution and codecs, with ffprobe of FFmpeg.
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// maxStderrBytes bounds how much ffprobe output is kept for error messages
+const maxStderrBytes = 4096
+
+// ErrInvalidMedia is returned for input that is not a readable audio or video file
+var ErrInvalidMedia = errors.New("not a readable media file")
+
+// Metadata describes the streams of a media file. Fields that do not apply, such
+// as the resolution of audio, or could not be read are left zero.
+type Metadata struct {
+	Duration      float64 // seconds
+	Container     string  // e.g. "mov,mp4,m4a,3gp,3g2,mj2" or "mp3"
+	Bitrate       int64   // overall bits per second
+	Width         int
+	Height        int
+	VideoCodec    string
+	AudioCodec    string
+	AudioChannels int
+	SampleRate    int // Hz
+}
+
+// HasVideo returns true if the file has a video stream other than cover art
+func (m *Metadata) HasVideo() bool {
+	return m.VideoCodec != ""
+}
+
+// HasAudio returns true if the file has an audio stream
+func (m *Metadata) HasAudio() bool {
+	return m.AudioCodec != ""
+}
+
+// MetadataExtractor reads the technical metadata of media files
+type MetadataExtractor interface {
+	// Extract reads a media file from src and returns its metadata, an error
+	// wrapping ErrInvalidMedia when src is not a readable media file
+	Extract(ctx context.Context, src io.Reader) (*Metadata, error)
+}
+
+// FFprobe extracts metadata by running ffprobe as a subprocess, streaming
+// media through stdin
+type FFprobe struct {
+	binary string
+}
+
+// NewFFprobe creates an extractor for the ffprobe binary at the given path
+func NewFFprobe(binary string) *FFprobe {
+	if binary == "" {
+		binary = "ffprobe"
+	}
+	return &FFprobe{binary: binary}
+}
+
+// Extract reads a media file from src and returns its metadata
+func (p *FFprobe) Extract(ctx context.Context, src io.Reader) (*Metadata, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.binary,
+		"-hide_banner", "-loglevel", "error", "-print_format", "json", "-show_format", "-show_streams", "pipe:0")
+	cmd.Stdin = src
+	cmd.Stdout = &stdout
+	cmd.Stderr = &LimitedWriter{Buf: &stderr, Limit: maxStderrBytes}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			return nil, fmt.Errorf("%w: ffprobe failed: %s", ErrInvalidMedia, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+	return parseOutput(stdout.Bytes())
+}
+
+// probeOutput is the part of the JSON ffprobe prints with -show_format -show_streams that is read
+type probeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType   string `json:"codec_type"`
+		CodecName   string `json:"codec_name"`
+		Width       int    `json:"width"`
+		Height      int    `json:"height"`
+		Channels    int    `json:"channels"`
+		SampleRate  string `json:"sample_rate"`
+		Duration    string `json:"duration"`
+		Disposition struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+	} `json:"streams"`
+}
+
+// parseOutput reads the metadata of the first video and audio streams from ffprobe output
+func parseOutput(output []byte) (*Metadata, error) {
+	var probe probeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	metadata := &Metadata{
+		Duration:  parseFloat(probe.Format.Duration),
+		Container: probe.Format.FormatName,
+		Bitrate:   int64(parseFloat(probe.Format.BitRate)),
+	}
+	for _, stream := range probe.Streams {
+		switch {
+		// Cover art of audio files is reported as a video stream of one picture
+		case stream.CodecType == "video" && stream.Disposition.AttachedPic == 0 && !metadata.HasVideo():
+			metadata.VideoCodec = stream.CodecName
+			metadata.Width = stream.Width
+			metadata.Height = stream.Height
+		case stream.CodecType == "audio" && !metadata.HasAudio():
+			metadata.AudioCodec = stream.CodecName
+			metadata.AudioChannels = stream.Channels
+			metadata.SampleRate = int(parseFloat(stream.SampleRate))
+		default:
+			continue
+		}
+		// Streams piped without an index may only know their own duration
+		if metadata.Duration == 0 {
+			metadata.Duration = parseFloat(stream.Duration)
+		}
+	}
+
+	if !metadata.HasVideo() && !metadata.HasAudio() {
+		return nil, fmt.Errorf("%w: no audio or video stream", ErrInvalidMedia)
+	}
+	return metadata, nil
+}
+
+// parseFloat reads a number ffprobe prints as a string, 0 when it is missing or "N/A"
+func parseFloat(value string) float64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0
+	}
+	return number
+}
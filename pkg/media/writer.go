@@ -0,0 +1,21 @@
+package media
+
+import "bytes"
+
+// LimitedWriter keeps the first Limit bytes written to Buf and discards the rest.
+// It bounds how much output of the media tools is kept for error messages.
+type LimitedWriter struct {
+	Buf   *bytes.Buffer
+	Limit int
+}
+
+func (w *LimitedWriter) Write(p []byte) (int, error) {
+	if remaining := w.Limit - w.Buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			w.Buf.Write(p[:remaining])
+		} else {
+			w.Buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,60 @@
+package messagequeue
+
+import (
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// Event types published on the queue
+const (
+	EventTypeMediaIndex = "media.index"
+)
+
+// Media index actions
+const (
+	MediaIndexCreated = "created"
+	MediaIndexUpdated = "updated"
+	MediaIndexDeleted = "deleted"
+)
+
+// builtinSchemas lists the schemas registered by NewDefaultRegistry
+var builtinSchemas = []func() Event{
+	func() Event { return &MediaIndexEventV1{} },
+}
+
+// MediaIndexEvent is the current media indexing event schema
+type MediaIndexEvent = MediaIndexEventV1
+
+// MediaIndexEventV1 asks search consumers to (re)index or remove a media item
+type MediaIndexEventV1 struct {
+	Action  string        `json:"action"` // "created", "updated", "deleted"
+	MediaID string        `json:"media_id"`
+	Media   *domain.Media `json:"media,omitempty"`
+}
+
+// EventType returns the event type
+func (e *MediaIndexEventV1) EventType() string {
+	return EventTypeMediaIndex
+}
+
+// SchemaVersion returns the payload schema version
+func (e *MediaIndexEventV1) SchemaVersion() int {
+	return 1
+}
+
+// DeadLetterTopic returns the topic messages of topic are dead-lettered to
+// once their consumer gives up on them
+func DeadLetterTopic(topic string) string {
+	return topic + ".dead_letter"
+}
+
+// DeadLetter wraps a message its consumer failed to handle, so it can be
+// inspected and replayed
+type DeadLetter struct {
+	Topic    string    `json:"topic"`
+	Message  []byte    `json:"message"` // as received, base64 in JSON
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
@@ -0,0 +1,25 @@
+package messagequeue
+
+import (
+	"fmt"
+
+	"thamaniyah/internal/config"
+)
+
+// NewFromConfig creates the message queue selected by QUEUE_DRIVER
+func NewFromConfig(cfg *config.Config) (MessageQueue, error) {
+	switch cfg.Queue.Driver {
+	case "", "memory":
+		return NewInMemoryQueue(), nil
+	case "rabbitmq":
+		return NewRabbitMQQueue(RabbitMQOptions{
+			URL:             cfg.QueueURL(),
+			Exchange:        cfg.Queue.Exchange,
+			ConsumerGroup:   cfg.Queue.ConsumerGroup,
+			ChannelPoolSize: cfg.Queue.ChannelPoolSize,
+			Prefetch:        cfg.Queue.Prefetch,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported queue driver: %s", cfg.Queue.Driver)
+	}
+}
@@ -18,10 +18,3 @@ type MessageQueue interface {
 
 // MessageHandler defines the function signature for message handlers
 type MessageHandler func(ctx context.Context, message []byte) error
-
-// MediaIndexEvent represents a media indexing event
-type MediaIndexEvent struct {
-	EventType string      `json:"event_type"` // "created", "updated", "deleted"
-	MediaID   string      `json:"media_id"`
-	Media     interface{} `json:"media,omitempty"`
-}
@@ -0,0 +1,87 @@
+package messagequeue
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+)
+
+// InMemoryQueue implements MessageQueue inside a single process.
+// Topics are matched like RabbitMQ topic exchanges: "#" matches everything
+// and "media.#" matches every topic starting with "media.".
+type InMemoryQueue struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]*subscription
+}
+
+// subscription is a registered handler for a topic pattern
+type subscription struct {
+	pattern string
+	handler MessageHandler
+}
+
+// NewInMemoryQueue creates a new in-memory message queue
+func NewInMemoryQueue() *InMemoryQueue {
+	return &InMemoryQueue{
+		subscribers: make(map[int]*subscription),
+	}
+}
+
+// Publish delivers the message synchronously to every matching subscriber
+func (q *InMemoryQueue) Publish(ctx context.Context, topic string, message []byte) error {
+	q.mu.RLock()
+	handlers := make([]MessageHandler, 0, len(q.subscribers))
+	for _, sub := range q.subscribers {
+		if TopicMatches(sub.pattern, topic) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	q.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, message); err != nil {
+			log.Printf("In-memory queue handler failed for topic %s: %v", topic, err)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers the handler until ctx is cancelled
+func (q *InMemoryQueue) Subscribe(ctx context.Context, topic string, handler MessageHandler) error {
+	q.mu.Lock()
+	id := q.nextID
+	q.nextID++
+	q.subscribers[id] = &subscription{pattern: topic, handler: handler}
+	q.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		q.mu.Lock()
+		delete(q.subscribers, id)
+		q.mu.Unlock()
+	}()
+
+	return nil
+}
+
+// Close removes all subscribers
+func (q *InMemoryQueue) Close() error {
+	q.mu.Lock()
+	q.subscribers = make(map[int]*subscription)
+	q.mu.Unlock()
+	return nil
+}
+
+// TopicMatches reports whether topic matches the subscription pattern
+func TopicMatches(pattern, topic string) bool {
+	if pattern == "#" || pattern == topic {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "#"); ok {
+		return strings.HasPrefix(topic, prefix)
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+//go:build integration
+
+package messagequeue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thamaniyah/internal/testsupport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRabbitMQQueue_Integration publishes and consumes events through a real broker
+func TestRabbitMQQueue_Integration(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	url := testsupport.StartRabbitMQ(t)
+
+	queue, err := NewRabbitMQQueue(RabbitMQOptions{URL: url, Exchange: "test.events", ConsumerGroup: "discovery"})
+	require.NoError(t, err)
+	defer queue.Close()
+
+	// Given a subscription to media events whose first attempt at a message fails
+	received := make(chan string, 10)
+	attempts := 0
+	require.NoError(t, queue.Subscribe(ctx, "media.#", func(ctx context.Context, message []byte) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("handler failed")
+		}
+		received <- string(message)
+		return nil
+	}))
+
+	// When events are published
+	require.NoError(t, queue.Publish(ctx, "media.created", []byte(`{"id":"1"}`)))
+	require.NoError(t, queue.Publish(ctx, "user.created", []byte(`{"id":"2"}`)))
+
+	// Then the matching event is redelivered after the failure and the other is not delivered
+	select {
+	case message := <-received:
+		assert.Equal(t, `{"id":"1"}`, message)
+	case <-ctx.Done():
+		t.Fatal("media.created was not delivered")
+	}
+	select {
+	case message := <-received:
+		t.Fatalf("unexpected message %s", message)
+	case <-time.After(time.Second):
+	}
+
+	require.NoError(t, queue.Close())
+	assert.ErrorIs(t, queue.Publish(ctx, "media.created", nil), ErrQueueClosed)
+}
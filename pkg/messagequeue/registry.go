@@ -0,0 +1,177 @@
+package messagequeue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUnknownSchema is returned when a message has no registered schema
+	ErrUnknownSchema = errors.New("unknown event schema")
+	// ErrSchemaAlreadyRegistered is returned when registering the same schema twice
+	ErrSchemaAlreadyRegistered = errors.New("event schema already registered")
+)
+
+// Event is a strongly typed, versioned message payload
+type Event interface {
+	// EventType returns the event type, also used as the queue topic
+	EventType() string
+	// SchemaVersion returns the payload schema version
+	SchemaVersion() int
+}
+
+// Upgradable is implemented by older schema versions that can be converted
+// to the next version of the same event type
+type Upgradable interface {
+	Upgrade() Event
+}
+
+// Envelope wraps an event payload with its type and schema version on the wire
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// schemaKey identifies a single event schema
+type schemaKey struct {
+	eventType string
+	version   int
+}
+
+// Registry maps event types and schema versions to their Go types so
+// producers and consumers can evolve independently
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[schemaKey]func() Event
+	latest    map[string]int
+}
+
+// NewRegistry creates an empty schema registry
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[schemaKey]func() Event),
+		latest:    make(map[string]int),
+	}
+}
+
+// NewDefaultRegistry creates a registry with all built-in event schemas
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, factory := range builtinSchemas {
+		if err := registry.Register(factory); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// Register adds a schema; factory must return a pointer to a zero value of the event
+func (r *Registry) Register(factory func() Event) error {
+	sample := factory()
+	key := schemaKey{eventType: sample.EventType(), version: sample.SchemaVersion()}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.factories[key]; exists {
+		return fmt.Errorf("%w: %s v%d", ErrSchemaAlreadyRegistered, key.eventType, key.version)
+	}
+
+	r.factories[key] = factory
+	if key.version > r.latest[key.eventType] {
+		r.latest[key.eventType] = key.version
+	}
+
+	return nil
+}
+
+// LatestVersion returns the newest registered schema version for an event type
+func (r *Registry) LatestVersion(eventType string) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	version, ok := r.latest[eventType]
+	return version, ok
+}
+
+// Marshal wraps a registered event in an envelope and encodes it
+func (r *Registry) Marshal(event Event) ([]byte, error) {
+	if !r.isRegistered(event.EventType(), event.SchemaVersion()) {
+		return nil, fmt.Errorf("%w: %s v%d", ErrUnknownSchema, event.EventType(), event.SchemaVersion())
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", event.EventType(), err)
+	}
+
+	return json.Marshal(&Envelope{
+		ID:            uuid.New().String(),
+		Type:          event.EventType(),
+		SchemaVersion: event.SchemaVersion(),
+		OccurredAt:    time.Now().UTC(),
+		Payload:       payload,
+	})
+}
+
+// Unmarshal decodes a message into its registered event type, exactly as it was produced
+func (r *Registry) Unmarshal(data []byte) (*Envelope, Event, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	r.mu.RLock()
+	factory, ok := r.factories[schemaKey{eventType: envelope.Type, version: envelope.SchemaVersion}]
+	r.mu.RUnlock()
+	if !ok {
+		return &envelope, nil, fmt.Errorf("%w: %s v%d", ErrUnknownSchema, envelope.Type, envelope.SchemaVersion)
+	}
+
+	event := factory()
+	if err := json.Unmarshal(envelope.Payload, event); err != nil {
+		return &envelope, nil, fmt.Errorf("failed to decode %s v%d payload: %w", envelope.Type, envelope.SchemaVersion, err)
+	}
+
+	return &envelope, event, nil
+}
+
+// UnmarshalLatest decodes a message and upgrades it to the newest registered schema version
+func (r *Registry) UnmarshalLatest(data []byte) (*Envelope, Event, error) {
+	envelope, event, err := r.Unmarshal(data)
+	if err != nil {
+		return envelope, nil, err
+	}
+
+	latest, _ := r.LatestVersion(event.EventType())
+	for event.SchemaVersion() < latest {
+		upgradable, ok := event.(Upgradable)
+		if !ok {
+			return envelope, nil, fmt.Errorf("%w: %s v%d cannot be upgraded", ErrUnknownSchema, event.EventType(), event.SchemaVersion())
+		}
+		upgraded := upgradable.Upgrade()
+		if upgraded == nil || upgraded.EventType() != event.EventType() || upgraded.SchemaVersion() <= event.SchemaVersion() {
+			return envelope, nil, fmt.Errorf("%w: %s v%d does not upgrade to a newer version", ErrUnknownSchema, event.EventType(), event.SchemaVersion())
+		}
+		event = upgraded
+	}
+
+	return envelope, event, nil
+}
+
+// isRegistered reports whether a schema is registered
+func (r *Registry) isRegistered(eventType string, version int) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.factories[schemaKey{eventType: eventType, version: version}]
+	return ok
+}
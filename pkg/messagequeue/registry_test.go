@@ -0,0 +1,189 @@
+package messagequeue
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"thamaniyah/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEventType = "test.greeting"
+
+type greetingV1 struct {
+	Name string `json:"name"`
+}
+
+func (e *greetingV1) EventType() string  { return testEventType }
+func (e *greetingV1) SchemaVersion() int { return 1 }
+func (e *greetingV1) Upgrade() Event {
+	return &greetingV2{FirstName: e.Name, Greeting: "hello"}
+}
+
+type greetingV2 struct {
+	FirstName string `json:"first_name"`
+	Greeting  string `json:"greeting"`
+}
+
+func (e *greetingV2) EventType() string  { return testEventType }
+func (e *greetingV2) SchemaVersion() int { return 2 }
+func (e *greetingV2) Upgrade() Event {
+	return &greetingV3{Text: e.Greeting + ", " + e.FirstName}
+}
+
+type greetingV3 struct {
+	Text string `json:"text"`
+}
+
+func (e *greetingV3) EventType() string  { return testEventType }
+func (e *greetingV3) SchemaVersion() int { return 3 }
+
+// greetingV4 is newer than greetingV3, which has no upgrade to it
+type greetingV4 struct{}
+
+func (e *greetingV4) EventType() string  { return testEventType }
+func (e *greetingV4) SchemaVersion() int { return 4 }
+
+// stuckV1 upgrades to itself, which must not loop forever
+type stuckV1 struct{}
+
+func (e *stuckV1) EventType() string  { return "test.stuck" }
+func (e *stuckV1) SchemaVersion() int { return 1 }
+func (e *stuckV1) Upgrade() Event     { return e }
+
+type stuckV2 struct{}
+
+func (e *stuckV2) EventType() string  { return "test.stuck" }
+func (e *stuckV2) SchemaVersion() int { return 2 }
+
+func newGreetingRegistry(t *testing.T) *Registry {
+	t.Helper()
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(func() Event { return &greetingV1{} }))
+	require.NoError(t, registry.Register(func() Event { return &greetingV3{} }))
+	require.NoError(t, registry.Register(func() Event { return &greetingV2{} }))
+	return registry
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := newGreetingRegistry(t)
+
+	latest, ok := registry.LatestVersion(testEventType)
+	assert.True(t, ok)
+	assert.Equal(t, 3, latest)
+
+	_, ok = registry.LatestVersion("test.unknown")
+	assert.False(t, ok)
+
+	err := registry.Register(func() Event { return &greetingV2{} })
+	assert.True(t, errors.Is(err, ErrSchemaAlreadyRegistered))
+}
+
+func TestRegistry_MarshalUnmarshal(t *testing.T) {
+	registry := newGreetingRegistry(t)
+
+	data, err := registry.Marshal(&greetingV2{FirstName: "Sara", Greeting: "hi"})
+	require.NoError(t, err)
+
+	var raw Envelope
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.NotEmpty(t, raw.ID)
+	assert.Equal(t, testEventType, raw.Type)
+	assert.Equal(t, 2, raw.SchemaVersion)
+	assert.False(t, raw.OccurredAt.IsZero())
+	assert.JSONEq(t, `{"first_name":"Sara","greeting":"hi"}`, string(raw.Payload))
+
+	envelope, event, err := registry.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, raw.ID, envelope.ID)
+	assert.Equal(t, &greetingV2{FirstName: "Sara", Greeting: "hi"}, event)
+}
+
+func TestRegistry_Marshal_UnknownSchema(t *testing.T) {
+	registry := NewRegistry()
+
+	_, err := registry.Marshal(&greetingV1{Name: "Sara"})
+	assert.True(t, errors.Is(err, ErrUnknownSchema))
+}
+
+func TestRegistry_Unmarshal_Errors(t *testing.T) {
+	registry := newGreetingRegistry(t)
+
+	_, _, err := registry.Unmarshal([]byte("not json"))
+	assert.Error(t, err)
+
+	_, _, err = registry.Unmarshal([]byte(`{"type":"test.greeting","schema_version":9,"payload":{}}`))
+	assert.True(t, errors.Is(err, ErrUnknownSchema))
+
+	_, _, err = registry.Unmarshal([]byte(`{"type":"test.greeting","schema_version":1,"payload":"oops"}`))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUnknownSchema))
+}
+
+func TestRegistry_UnmarshalLatest(t *testing.T) {
+	registry := newGreetingRegistry(t)
+
+	data, err := registry.Marshal(&greetingV1{Name: "Sara"})
+	require.NoError(t, err)
+
+	envelope, event, err := registry.UnmarshalLatest(data)
+	require.NoError(t, err)
+	assert.Equal(t, 1, envelope.SchemaVersion) // the envelope keeps the version it was produced with
+	assert.Equal(t, &greetingV3{Text: "hello, Sara"}, event)
+
+	data, err = registry.Marshal(&greetingV3{Text: "hey"})
+	require.NoError(t, err)
+
+	_, event, err = registry.UnmarshalLatest(data)
+	require.NoError(t, err)
+	assert.Equal(t, &greetingV3{Text: "hey"}, event)
+}
+
+func TestRegistry_UnmarshalLatest_NotUpgradable(t *testing.T) {
+	t.Run("upgrade does not raise the version", func(t *testing.T) {
+		registry := NewRegistry()
+		require.NoError(t, registry.Register(func() Event { return &stuckV1{} }))
+		require.NoError(t, registry.Register(func() Event { return &stuckV2{} }))
+
+		data, err := registry.Marshal(&stuckV1{})
+		require.NoError(t, err)
+
+		_, _, err = registry.UnmarshalLatest(data)
+		assert.True(t, errors.Is(err, ErrUnknownSchema))
+	})
+
+	t.Run("version has no upgrade", func(t *testing.T) {
+		registry := NewRegistry()
+		require.NoError(t, registry.Register(func() Event { return &greetingV3{} }))
+		require.NoError(t, registry.Register(func() Event { return &greetingV4{} }))
+
+		data, err := registry.Marshal(&greetingV3{Text: "hey"})
+		require.NoError(t, err)
+
+		_, _, err = registry.UnmarshalLatest(data)
+		assert.True(t, errors.Is(err, ErrUnknownSchema))
+	})
+}
+
+func TestDefaultRegistry_MediaIndexEvent(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	data, err := registry.Marshal(&MediaIndexEvent{
+		Action:  MediaIndexUpdated,
+		MediaID: "media-1",
+		Media:   &domain.Media{ID: "media-1", Title: "Episode 1"},
+	})
+	require.NoError(t, err)
+
+	_, event, err := registry.UnmarshalLatest(data)
+	require.NoError(t, err)
+
+	indexEvent, ok := event.(*MediaIndexEvent)
+	require.True(t, ok)
+	assert.Equal(t, MediaIndexUpdated, indexEvent.Action)
+	assert.Equal(t, "media-1", indexEvent.MediaID)
+	assert.Equal(t, "Episode 1", indexEvent.Media.Title)
+}
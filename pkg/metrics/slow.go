@@ -0,0 +1,66 @@
+// Package metrics counts the slow operations of the services and publishes the
+// counters with expvar
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"sync"
+	"time"
+
+	"thamaniyah/internal/domain"
+)
+
+// Kinds of slow operations
+const (
+	KindSQL           = "sql"
+	KindElasticsearch = "elasticsearch"
+	KindHTTP          = "http"
+)
+
+// maxFingerprints bounds the fingerprints counted separately, later ones are counted as "other"
+const maxFingerprints = 1000
+
+var (
+	slowOperations   = expvar.NewMap("slow_operations")   // by kind
+	slowFingerprints = expvar.NewMap("slow_fingerprints") // by kind and fingerprint
+
+	fingerprintsMu sync.Mutex
+	fingerprints   = make(map[string]bool)
+)
+
+// ObserveSlow logs and counts an operation of kind that took longer than
+// threshold, under a fingerprint identifying the query or route without its
+// values. It returns true if the operation was slow. A threshold of 0 disables
+// the detection.
+func ObserveSlow(ctx context.Context, kind, fingerprint string, elapsed, threshold time.Duration) bool {
+	if threshold <= 0 || elapsed <= threshold {
+		return false
+	}
+
+	slowOperations.Add(kind, 1)
+	slowFingerprints.Add(fingerprintKey(kind, fingerprint), 1)
+
+	requestID := ""
+	if trace := domain.TraceFromContext(ctx); trace != nil {
+		requestID = trace.RequestID
+	}
+	log.Printf("Slow %s took %s (threshold %s): %s request_id=%s", kind, elapsed.Round(time.Millisecond), threshold, fingerprint, requestID)
+	return true
+}
+
+// fingerprintKey returns the counter of a fingerprint, "other" once maxFingerprints are counted
+func fingerprintKey(kind, fingerprint string) string {
+	key := kind + " " + fingerprint
+
+	fingerprintsMu.Lock()
+	defer fingerprintsMu.Unlock()
+	if !fingerprints[key] {
+		if len(fingerprints) >= maxFingerprints {
+			return kind + " other"
+		}
+		fingerprints[key] = true
+	}
+	return key
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// counter returns the value of a counter of an expvar map, 0 when missing
+func counter(m *expvar.Map, key string) int64 {
+	if value, ok := m.Get(key).(*expvar.Int); ok {
+		return value.Value()
+	}
+	return 0
+}
+
+func TestObserveSlow(t *testing.T) {
+	const kind = "test-observe"
+	threshold := 100 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		elapsed   time.Duration
+		threshold time.Duration
+		expected  bool
+	}{
+		{"faster than the threshold", 50 * time.Millisecond, threshold, false},
+		{"at the threshold", threshold, threshold, false},
+		{"slower than the threshold", 150 * time.Millisecond, threshold, true},
+		{"disabled", time.Hour, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := counter(slowOperations, kind)
+			beforeFingerprint := counter(slowFingerprints, kind+" SELECT ?")
+
+			slow := ObserveSlow(context.Background(), kind, "SELECT ?", tt.elapsed, tt.threshold)
+
+			assert.Equal(t, tt.expected, slow)
+			increment := int64(0)
+			if tt.expected {
+				increment = 1
+			}
+			assert.Equal(t, before+increment, counter(slowOperations, kind))
+			assert.Equal(t, beforeFingerprint+increment, counter(slowFingerprints, kind+" SELECT ?"))
+		})
+	}
+}
+
+func TestFingerprintKey_Bounded(t *testing.T) {
+	fingerprintsMu.Lock()
+	saved := fingerprints
+	fingerprints = make(map[string]bool)
+	fingerprintsMu.Unlock()
+	t.Cleanup(func() {
+		fingerprintsMu.Lock()
+		fingerprints = saved
+		fingerprintsMu.Unlock()
+	})
+
+	for i := 0; i < maxFingerprints; i++ {
+		assert.Equal(t, fmt.Sprintf("sql q%d", i), fingerprintKey("sql", fmt.Sprintf("q%d", i)))
+	}
+
+	// Once full, new fingerprints share a counter and the known ones keep theirs
+	assert.Equal(t, "sql other", fingerprintKey("sql", "new"))
+	assert.Equal(t, "sql q0", fingerprintKey("sql", "q0"))
+}
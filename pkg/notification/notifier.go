@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"context"
+
+	"thamaniyah/internal/config"
+)
+
+// Channel names supported by the notification subsystem
+const (
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelWebhook = "webhook"
+)
+
+// Message represents a notification to deliver
+type Message struct {
+	Event   string                 `json:"event"`
+	Subject string                 `json:"subject"`
+	Body    string                 `json:"body"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// Notifier delivers messages over a single channel
+type Notifier interface {
+	// Send delivers the message to the channel-specific target (address, URL)
+	Send(ctx context.Context, target string, msg *Message) error
+}
+
+// NewNotifiers creates the notifiers available with the given configuration
+func NewNotifiers(cfg *config.Config) map[string]Notifier {
+	notifiers := map[string]Notifier{
+		ChannelSlack:   NewSlackNotifier(),
+		ChannelWebhook: NewWebhookNotifier(),
+	}
+
+	// Email needs an SMTP relay (SES exposes one as well)
+	if cfg.Notification.SMTPHost != "" {
+		notifiers[ChannelEmail] = NewSMTPNotifier(cfg)
+	}
+
+	return notifiers
+}
@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+
+	"thamaniyah/internal/config"
+)
+
+// SMTPNotifier sends notifications as plain-text emails
+type SMTPNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPNotifier creates a new SMTP notifier
+func NewSMTPNotifier(cfg *config.Config) *SMTPNotifier {
+	var auth smtp.Auth
+	if cfg.Notification.SMTPUser != "" {
+		auth = smtp.PlainAuth("", cfg.Notification.SMTPUser, cfg.Notification.SMTPPassword, cfg.Notification.SMTPHost)
+	}
+
+	return &SMTPNotifier{
+		addr: fmt.Sprintf("%s:%d", cfg.Notification.SMTPHost, cfg.Notification.SMTPPort),
+		auth: auth,
+		from: cfg.Notification.EmailFrom,
+	}
+}
+
+// Send emails the message to the target address
+func (n *SMTPNotifier) Send(ctx context.Context, target string, msg *Message) error {
+	body, err := buildEmail(n.from, target, msg)
+	if err != nil {
+		return err
+	}
+
+	// net/smtp has no context support, so honour cancellation before dialing
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{target}, body); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// buildEmail renders the message with its headers. Addresses containing line
+// breaks are rejected and the subject is encoded, so neither can inject headers.
+func buildEmail(from, target string, msg *Message) ([]byte, error) {
+	for _, addr := range []string{from, target} {
+		if strings.ContainsAny(addr, "\r\n") {
+			return nil, fmt.Errorf("invalid email address %q", addr)
+		}
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", from)
+	fmt.Fprintf(&body, "To: %s\r\n", target)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(msg.Body)
+
+	return []byte(body.String()), nil
+}
@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        string
+		target      string
+		subject     string
+		wantSubject string
+		wantErr     bool
+	}{
+		{
+			name:        "plain subject",
+			from:        "no-reply@example.com",
+			target:      "user@example.com",
+			subject:     "Media published",
+			wantSubject: "Subject: Media published\r\n",
+		},
+		{
+			name:        "line breaks in subject are encoded",
+			from:        "no-reply@example.com",
+			target:      "user@example.com",
+			subject:     "Hi\r\nBcc: victim@example.com",
+			wantSubject: "Subject: =?utf-8?q?Hi=0D=0ABcc:_victim@example.com?=\r\n",
+		},
+		{
+			name:    "line breaks in target",
+			from:    "no-reply@example.com",
+			target:  "user@example.com\r\nBcc: victim@example.com",
+			subject: "Media published",
+			wantErr: true,
+		},
+		{
+			name:    "line breaks in sender",
+			from:    "no-reply@example.com\n",
+			target:  "user@example.com",
+			subject: "Media published",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := buildEmail(tt.from, tt.target, &Message{Subject: tt.subject, Body: "body"})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, string(body), tt.wantSubject)
+			assert.NotContains(t, string(body), "\r\nBcc:")
+
+			headers, _, _ := strings.Cut(string(body), "\r\n\r\n")
+			assert.Len(t, strings.Split(headers, "\r\n"), 5)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier posts notifications as JSON to arbitrary URLs
+type WebhookNotifier struct {
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send posts the full message to the target URL
+func (n *WebhookNotifier) Send(ctx context.Context, target string, msg *Message) error {
+	return postJSON(ctx, n.httpClient, target, msg)
+}
+
+// SlackNotifier posts notifications to Slack incoming webhooks
+type SlackNotifier struct {
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a new Slack notifier
+func NewSlackNotifier() *SlackNotifier {
+	return &SlackNotifier{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send posts the message text to the Slack webhook URL
+func (n *SlackNotifier) Send(ctx context.Context, target string, msg *Message) error {
+	payload := map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", msg.Subject, msg.Body),
+	}
+	return postJSON(ctx, n.httpClient, target, payload)
+}
+
+// postJSON sends payload as a JSON POST request and checks the response status
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
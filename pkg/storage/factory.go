@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"thamaniyah/internal/config"
+)
+
+// NewFromConfig creates the storage selected by STORAGE_TYPE
+func NewFromConfig(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Type {
+	case "", "local":
+		signingKey := []byte(cfg.Storage.UploadSigningKey)
+		if len(signingKey) == 0 {
+			// Upload URLs are only accepted by the process that issued them
+			signingKey = make([]byte, 32)
+			if _, err := rand.Read(signingKey); err != nil {
+				return nil, fmt.Errorf("failed to generate upload signing key: %w", err)
+			}
+		}
+		return NewPresigningLocalStorage(NewLocalStorage(cfg.Storage.LocalPath), cfg.Embed.PublicBaseURL, signingKey)
+	case "s3":
+		return NewS3Storage(S3Options{
+			Bucket:          cfg.Storage.S3Bucket,
+			Region:          cfg.Storage.S3Region,
+			Endpoint:        cfg.Storage.S3Endpoint,
+			AccessKeyID:     cfg.Storage.S3AccessKeyID,
+			SecretAccessKey: cfg.Storage.S3SecretAccessKey,
+			SessionToken:    cfg.Storage.S3SessionToken,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", cfg.Storage.Type)
+	}
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidUploadSignature is returned for upload URLs that were not signed by
+// the storage or have expired
+var ErrInvalidUploadSignature = errors.New("upload URL is invalid or expired")
+
+// ErrInvalidDownloadSignature is returned for download URLs that were not
+// signed by the storage or have expired
+var ErrInvalidDownloadSignature = errors.New("download URL is invalid or expired")
+
+// UploadReceiver is a Presigner whose URLs point at the service itself, which
+// verifies them before storing the uploaded content
+type UploadReceiver interface {
+	Presigner
+
+	// VerifyPut checks that expires and signature were issued by PresignPut for
+	// key and are still valid
+	VerifyPut(key string, expires int64, signature string) error
+}
+
+// DownloadServer is a GetPresigner whose URLs point at the service itself,
+// which verifies them before serving the object
+type DownloadServer interface {
+	GetPresigner
+
+	// VerifyGet checks that expires and signature were issued by PresignGet, or
+	// by PresignDownload for filename when not empty, for key and are still valid
+	VerifyGet(key, filename string, expires int64, signature string) error
+}
+
+// AttachmentDisposition returns the Content-Disposition of a download saved
+// as filename, encoded for names that are not plain ASCII
+func AttachmentDisposition(filename string) string {
+	if disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename}); disposition != "" {
+		return disposition
+	}
+	return "attachment"
+}
+
+// PresigningLocalStorage is a local storage issuing upload URLs to the
+// PUT /upload/:key endpoint of the service and download URLs to its
+// GET /files/*key endpoint, signed with HMAC-SHA256
+type PresigningLocalStorage struct {
+	*LocalStorage
+	baseURL    string
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewPresigningLocalStorage creates a local storage whose upload URLs start with
+// baseURL, the public URL of the service, and are signed with signingKey
+func NewPresigningLocalStorage(local *LocalStorage, baseURL string, signingKey []byte) (*PresigningLocalStorage, error) {
+	if len(signingKey) == 0 {
+		return nil, errors.New("local upload URLs require a signing key")
+	}
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid upload base URL %q: %w", baseURL, err)
+	}
+
+	return &PresigningLocalStorage{
+		LocalStorage: local,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		signingKey:   signingKey,
+		now:          time.Now,
+	}, nil
+}
+
+// PresignPut returns a URL of the upload endpoint accepting a PUT of the object
+// stored under key until ttl elapses
+func (s *PresigningLocalStorage) PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := s.now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(http.MethodPut, key, expires))
+	return fmt.Sprintf("%s/upload/%s?%s", s.baseURL, url.PathEscape(key), query.Encode()), nil
+}
+
+// VerifyPut checks that expires and signature were issued for key and are still valid
+func (s *PresigningLocalStorage) VerifyPut(key string, expires int64, signature string) error {
+	if !s.verify(http.MethodPut, key, expires, signature) {
+		return ErrInvalidUploadSignature
+	}
+	return nil
+}
+
+// PresignGet returns a URL of the files endpoint serving the object stored
+// under key until ttl elapses
+func (s *PresigningLocalStorage) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presignGet(key, ttl, "")
+}
+
+// PresignDownload returns a URL of the files endpoint serving the object
+// stored under key as an attachment saved as filename until ttl elapses. The
+// filename is signed along with the key.
+func (s *PresigningLocalStorage) PresignDownload(ctx context.Context, key string, ttl time.Duration, filename string) (string, error) {
+	return s.presignGet(key, ttl, filename)
+}
+
+// presignGet returns a signed URL of the files endpoint, with the filename to
+// save the object as when not empty
+func (s *PresigningLocalStorage) presignGet(key string, ttl time.Duration, filename string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := s.now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	if filename != "" {
+		query.Set("filename", filename)
+	}
+	query.Set("signature", s.sign(http.MethodGet, signedKey(key, filename), expires))
+	// Keys keep their slashes so relative references between files resolve
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s/files/%s?%s", s.baseURL, strings.Join(segments, "/"), query.Encode()), nil
+}
+
+// VerifyGet checks that expires and signature were issued for key and
+// filename and are still valid
+func (s *PresigningLocalStorage) VerifyGet(key, filename string, expires int64, signature string) error {
+	if !s.verify(http.MethodGet, signedKey(key, filename), expires, signature) {
+		return ErrInvalidDownloadSignature
+	}
+	return nil
+}
+
+// verify reports whether signature was issued for a request of method for key
+// that has not expired
+func (s *PresigningLocalStorage) verify(method, key string, expires int64, signature string) bool {
+	expected, err := hex.DecodeString(s.sign(method, key, expires))
+	if err != nil {
+		return false
+	}
+	given, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, given) {
+		return false
+	}
+	return s.now().Unix() <= expires
+}
+
+// signedKey returns the key of a download as it is signed, followed by the
+// filename to save it as if any, so that it cannot be changed
+func signedKey(key, filename string) string {
+	if filename == "" {
+		return key
+	}
+	return key + "\n" + filename
+}
+
+// sign returns the hex HMAC-SHA256 of a request of method for key until expires
+func (s *PresigningLocalStorage) sign(method, key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s\n%s\n%d", method, key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,430 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 request signing (AWS Signature Version 4)
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3TimeFormat     = "20060102T150405Z"
+	s3DateFormat     = "20060102"
+	s3MaxPresignTTL  = 7 * 24 * time.Hour // longest validity S3 accepts for presigned URLs
+	s3MaxErrorLength = 1024               // bytes of an error response kept for the error message
+)
+
+// Presigner issues URLs clients upload objects to directly, without credentials
+type Presigner interface {
+	// PresignPut returns a URL accepting a PUT of the object stored under key until ttl elapses
+	PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// GetPresigner issues URLs clients download objects from directly, without credentials
+type GetPresigner interface {
+	// PresignGet returns a URL serving the object stored under key until ttl elapses
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+
+	// PresignDownload returns a URL serving the object stored under key as an
+	// attachment saved as filename until ttl elapses
+	PresignDownload(ctx context.Context, key string, ttl time.Duration, filename string) (string, error)
+}
+
+// S3Options configures an S3 storage
+type S3Options struct {
+	Bucket          string
+	Region          string
+	Endpoint        string // S3-compatible endpoint like MinIO, addressed path-style; AWS when empty
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // for temporary credentials
+}
+
+// S3Storage stores objects in an S3 bucket
+type S3Storage struct {
+	options S3Options
+	client  *http.Client
+	now     func() time.Time
+}
+
+// NewS3Storage creates a storage over the bucket of options
+func NewS3Storage(options S3Options) (*S3Storage, error) {
+	if options.Bucket == "" {
+		return nil, errors.New("s3 storage requires a bucket")
+	}
+	if options.AccessKeyID == "" || options.SecretAccessKey == "" {
+		return nil, errors.New("s3 storage requires an access key ID and a secret access key")
+	}
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+	options.Endpoint = strings.TrimSuffix(options.Endpoint, "/")
+
+	return &S3Storage{
+		options: options,
+		client:  &http.Client{Timeout: 5 * time.Minute},
+		now:     time.Now,
+	}, nil
+}
+
+// Get opens the object stored under key
+func (s *S3Storage) Get(ctx context.Context, key string) (*Object, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, -1)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, s3Error(resp, "get", key)
+	}
+
+	return &Object{Body: resp.Body, Size: resp.ContentLength}, nil
+}
+
+// Put stores the content of r under key. S3 needs the length of the content
+// up front, so readers other than files are spooled to a temporary file.
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader) error {
+	file, ok := r.(*os.File)
+	if !ok {
+		tmp, err := os.CreateTemp("", "s3-put-*")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary file for %s: %w", key, err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+
+		if _, err := io.Copy(tmp, r); err != nil {
+			return fmt.Errorf("failed to write %s: %w", key, err)
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to write %s: %w", key, err)
+		}
+		file = tmp
+	}
+
+	// The object is read from the current offset of the file
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat content of %s: %w", key, err)
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to read content of %s: %w", key, err)
+	}
+
+	var body io.ReadCloser = http.NoBody
+	if info.Size() > offset {
+		body = io.NopCloser(file)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, nil, body, info.Size()-offset)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp, "store", key)
+	}
+	return nil
+}
+
+// Exists reports whether an object is stored under key, with a HeadObject request
+func (s *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil, -1)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, s3Error(resp, "stat", key)
+	}
+}
+
+// Delete removes the object stored under key
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	// S3 deletes missing objects without complaint, report them like the other storages
+	exists, err := s.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return s3Error(resp, "delete", key)
+	}
+	return nil
+}
+
+// s3ListResult is the part of a ListObjectsV2 response the storage reads
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List calls fn with every object whose key starts with prefix, a page of up to 1000 at a time
+func (s *S3Storage) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, -1)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			return s3Error(resp, "list", prefix)
+		}
+
+		var page s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode listing of %s: %w", prefix, err)
+		}
+
+		for _, object := range page.Contents {
+			if err := fn(ObjectInfo{Key: object.Key, Size: object.Size, ModifiedAt: object.LastModified}); err != nil {
+				return err
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+// PresignPut returns a URL accepting a PUT of the object stored under key
+func (s *S3Storage) PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, ttl, nil)
+}
+
+// PresignGet returns a URL serving the object stored under key
+func (s *S3Storage) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, ttl, nil)
+}
+
+// PresignDownload returns a URL serving the object stored under key with a
+// Content-Disposition S3 overrides from the signed query string
+func (s *S3Storage) PresignDownload(ctx context.Context, key string, ttl time.Duration, filename string) (string, error) {
+	return s.presign(http.MethodGet, key, ttl, url.Values{"response-content-disposition": {AttachmentDisposition(filename)}})
+}
+
+// presign returns a URL accepting a request of method for the object stored
+// under key until ttl elapses, signed in the query string along with params
+func (s *S3Storage) presign(method, key string, ttl time.Duration, params url.Values) (string, error) {
+	if ttl <= 0 || ttl > s3MaxPresignTTL {
+		return "", fmt.Errorf("presigned URLs must expire within %s, got %s", s3MaxPresignTTL, ttl)
+	}
+
+	target := s.objectURL(key)
+	now := s.now().UTC()
+	query := url.Values{
+		"X-Amz-Algorithm":     {s3Algorithm},
+		"X-Amz-Credential":    {s.options.AccessKeyID + "/" + s.scope(now)},
+		"X-Amz-Date":          {now.Format(s3TimeFormat)},
+		"X-Amz-Expires":       {fmt.Sprint(int(ttl.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	for name, values := range params {
+		query[name] = values
+	}
+	if s.options.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.options.SessionToken)
+	}
+	target.RawQuery = s3CanonicalQuery(query)
+
+	headers := http.Header{"Host": {target.Host}}
+	signature := s.signature(now, method, target, headers, s3UnsignedBody)
+	target.RawQuery += "&X-Amz-Signature=" + signature
+	return target.String(), nil
+}
+
+// do sends a signed request for the object stored under key, or for the bucket when key is empty
+func (s *S3Storage) do(ctx context.Context, method, key string, query url.Values, body io.ReadCloser, length int64) (*http.Response, error) {
+	target := s.objectURL(key)
+	target.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	if length >= 0 {
+		req.ContentLength = length
+	}
+
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedBody)
+	if s.options.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.options.SessionToken)
+	}
+
+	headers := req.Header.Clone()
+	headers.Set("Host", target.Host)
+	signature := s.signature(now, method, target, headers, s3UnsignedBody)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.options.AccessKeyID, s.scope(now), s3SignedHeaders(headers), signature))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// objectURL returns the URL of the object stored under key, virtual-hosted on
+// AWS and path-style on custom endpoints
+func (s *S3Storage) objectURL(key string) *url.URL {
+	path := "/" + strings.TrimPrefix(key, "/")
+	if key == "" {
+		path = "/"
+	}
+
+	target := &url.URL{
+		Scheme: "https",
+		Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", s.options.Bucket, s.options.Region),
+		Path:   path,
+	}
+	if s.options.Endpoint != "" {
+		endpoint, err := url.Parse(s.options.Endpoint)
+		if err != nil || endpoint.Host == "" {
+			endpoint = &url.URL{Scheme: "https", Host: s.options.Endpoint}
+		}
+		target = &url.URL{
+			Scheme: endpoint.Scheme,
+			Host:   endpoint.Host,
+			Path:   strings.TrimSuffix(endpoint.Path, "/") + "/" + s.options.Bucket + strings.TrimSuffix(path, "/"),
+		}
+	}
+	// Requests carry the path escaped as it is signed
+	target.RawPath = s3EscapePath(target.Path)
+	return target
+}
+
+// scope returns the credential scope of requests signed at t
+func (s *S3Storage) scope(t time.Time) string {
+	return fmt.Sprintf("%s/%s/%s/aws4_request", t.Format(s3DateFormat), s.options.Region, s3Service)
+}
+
+// signature computes the Signature Version 4 of a request
+func (s *S3Storage) signature(t time.Time, method string, target *url.URL, headers http.Header, payloadHash string) string {
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(s3SignedHeaders(headers), ";") {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(strings.Join(headers.Values(name), ",")) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3EscapePath(target.Path),
+		target.RawQuery,
+		canonicalHeaders.String(),
+		s3SignedHeaders(headers),
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, t.Format(s3TimeFormat), s.scope(t), hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+s.options.SecretAccessKey), t.Format(s3DateFormat))
+	key = s3HMAC(key, s.options.Region)
+	key = s3HMAC(key, s3Service)
+	key = s3HMAC(key, "aws4_request")
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+// s3SignedHeaders returns the sorted, lowercased names of headers joined by semicolons
+func s3SignedHeaders(headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ";")
+}
+
+// s3CanonicalQuery encodes query sorted by key, with the escaping Signature Version 4 expects
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3EscapePath escapes every segment of a path
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape percent-encodes everything but unreserved characters, and slashes
+// unless encodeSlash is set
+func s3Escape(value string, encodeSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			escaped.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+// s3HMAC returns the HMAC-SHA256 of data with key
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Error describes a failed response, ErrNotFound for missing objects
+func s3Error(resp *http.Response, action, key string) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, s3MaxErrorLength))
+	return fmt.Errorf("failed to %s %s: s3 responded %s: %s", action, key, resp.Status, strings.TrimSpace(string(message)))
+}
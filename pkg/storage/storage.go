@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("object not found")
+
+// Object is a stored object opened for reading
+type Object struct {
+	Body io.ReadCloser
+	Size int64
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// Storage stores media files and derived assets by key
+type Storage interface {
+	// Get opens the object stored under key; the caller must close its body
+	Get(ctx context.Context, key string) (*Object, error)
+
+	// Put stores the content of r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Exists reports whether an object is stored under key
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// Delete removes the object stored under key
+	Delete(ctx context.Context, key string) error
+
+	// List calls fn with every object whose key starts with prefix, and stops
+	// at the first error fn returns
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}